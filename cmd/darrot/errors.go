@@ -20,7 +20,7 @@ func setupErrorHandling(rootCmd *cobra.Command) {
 
 	// Configure command suggestion settings
 	rootCmd.SuggestionsMinimumDistance = 1
-	rootCmd.SuggestFor = []string{"start", "version", "config", "help", "completion"}
+	rootCmd.SuggestFor = []string{"start", "validate", "version", "config", "help", "completion"}
 
 	// Set custom error handling function
 	rootCmd.SetFlagErrorFunc(handleFlagError)
@@ -285,6 +285,7 @@ func enhanceError(cmd *cobra.Command, err error) error {
 		fmt.Fprintf(os.Stderr, "• Use 'darrot config validate' to check your configuration\n")
 		fmt.Fprintf(os.Stderr, "• Use 'darrot config show' to see current configuration values\n")
 		fmt.Fprintf(os.Stderr, "• Use 'darrot config create' to generate a sample configuration file\n")
+		fmt.Fprintf(os.Stderr, "• Use 'darrot validate' to check credentials and permissions before deploying\n")
 		fmt.Fprintf(os.Stderr, "\nConfiguration precedence (highest to lowest):\n")
 		fmt.Fprintf(os.Stderr, "  1. CLI flags (--flag-name)\n")
 		fmt.Fprintf(os.Stderr, "  2. Environment variables (DRT_*)\n")
@@ -307,6 +308,7 @@ func enhanceError(cmd *cobra.Command, err error) error {
 		fmt.Fprintf(os.Stderr, "======================\n")
 		fmt.Fprintf(os.Stderr, "• Set Google Cloud credentials: DRT_TTS_GOOGLE_CLOUD_CREDENTIALS_PATH=/path/to/credentials.json\n")
 		fmt.Fprintf(os.Stderr, "• Or use CLI flag: --google-cloud-credentials-path /path/to/credentials.json\n")
+		fmt.Fprintf(os.Stderr, "• Or read them from Secret Manager: DRT_TTS_GOOGLE_CLOUD_CREDENTIALS_SECRET=projects/my-project/secrets/tts-key\n")
 		fmt.Fprintf(os.Stderr, "• Get credentials at: https://console.cloud.google.com/apis/credentials\n")
 		fmt.Fprintf(os.Stderr, "• Enable Text-to-Speech API in your Google Cloud project\n")
 	}
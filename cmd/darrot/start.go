@@ -57,8 +57,8 @@ The bot will run until interrupted with Ctrl+C or a termination signal.`,
 
 		logger.Println("Configuration loaded successfully")
 
-		// Initialize bot instance
-		botInstance, err := bot.New(cfg)
+		// Initialize the main bot and one bot per additional application
+		botInstance, err := bot.NewBotPool(cfg)
 		if err != nil {
 			logger.Fatalf("Failed to initialize bot: %v", err)
 			return err
@@ -66,7 +66,7 @@ The bot will run until interrupted with Ctrl+C or a termination signal.`,
 
 		logger.Println("Bot instance created successfully")
 
-		// Start the bot
+		// Start the bots
 		if err := botInstance.Start(); err != nil {
 			logger.Fatalf("Failed to start bot: %v", err)
 			return err
@@ -92,14 +92,40 @@ func init() {
 
 	// Discord configuration flags
 	startCmd.Flags().String("discord-token", "", "Discord bot token (required)")
+	startCmd.Flags().String("discord-test-guild-id", "", "Register slash commands in this guild only, for testing (empty = globally)")
+	startCmd.Flags().String("discord-applications", "", "Additional bots to run from this process, as comma-separated name=token pairs (e.g. red=TOKEN,blue=TOKEN)")
+	startCmd.Flags().String("discord-owner-ids", "", "Comma-separated IDs of the users who may run /darrot-owner across every server")
 
 	// TTS configuration flags
 	startCmd.Flags().String("google-cloud-credentials-path", "", "Path to Google Cloud credentials JSON file")
+	startCmd.Flags().String("google-cloud-credentials-secret", "", "Secret Manager secret holding the Google Cloud credentials JSON (projects/PROJECT/secrets/SECRET[/versions/VERSION])")
+	startCmd.Flags().String("google-cloud-endpoint", "", "Custom Google Cloud TTS endpoint (host:port, or http://host:port for a local mock)")
 	startCmd.Flags().String("tts-default-voice", "en-US-Standard-A", "Default TTS voice")
 	startCmd.Flags().Float32("tts-default-speed", 1.0, "Default TTS speed (0.25-4.0)")
 	startCmd.Flags().Float32("tts-default-volume", 1.0, "Default TTS volume (0.0-2.0)")
 	startCmd.Flags().Int("tts-max-queue-size", 10, "Maximum TTS queue size (1-100)")
+	startCmd.Flags().Int("tts-queue-spillover-mb", 0, "Megabytes of disk for messages full queues would drop (0-10240, 0 = drop them)")
+	startCmd.Flags().Int("tts-backlog-threshold", 0, "Queued messages past which the bot reads faster to catch up (0-100, 0 = off)")
+	startCmd.Flags().Float32("tts-backlog-max-speed", 1.5, "Fastest speed the bot reads at to catch up with a backlog (0.25-4.0)")
 	startCmd.Flags().Int("tts-max-message-length", 500, "Maximum message length for TTS (1-2000)")
+	startCmd.Flags().Int("tts-daily-character-budget", 0, "Characters synthesized per guild per day (0 = unlimited)")
+	startCmd.Flags().Int("tts-workers", 4, "Guild messages synthesized and played at the same time (1-64)")
+	startCmd.Flags().Int("tts-synthesis-timeout", 15, "Seconds a single TTS synthesis request may take (1-120)")
+	startCmd.Flags().Int("tts-drain-timeout", 10, "Seconds a shutdown waits for the bot to finish speaking (0-120, 0 = stop mid-sentence)")
+	startCmd.Flags().Bool("tts-shutdown-farewell", true, "Say goodbye in the voice channels before shutting down")
+	startCmd.Flags().Bool("tts-warmup", true, "Warm up the TTS engine on startup and on joining a voice channel")
+	startCmd.Flags().String("tts-api-address", "", "Listen address of the HTTP API for injecting messages (host:port, empty = off)")
+	startCmd.Flags().String("tts-metrics-address", "", "Listen address of the Prometheus metrics endpoint (host:port, empty = off)")
+	startCmd.Flags().String("tts-features", "", "Comma-separated experimental features to turn on for every guild, or off with a leading - (e.g. voice_auto_pause,-emoji_reading)")
+	startCmd.Flags().String("tts-storage-encryption-keys", "", "Comma-separated base64 AES-256 keys that encrypt stored data; the first encrypts, the others only decrypt")
+	startCmd.Flags().String("tts-storage-encryption-kms-key", "", "Cloud KMS key the storage encryption keys are wrapped with (projects/.../cryptoKeys/...)")
+	startCmd.Flags().String("tts-input-path", "", "Named pipe or file whose lines are spoken, or - for stdin (empty = off)")
+	startCmd.Flags().String("tts-input-guild-id", "", "Guild the input lines are spoken in")
+	startCmd.Flags().String("tts-input-channel-id", "", "Voice channel the input lines are limited to (empty = wherever the bot is in the guild)")
+	startCmd.Flags().String("tts-output-dir", "", "Directory speech is recorded to for guilds that choose the file output (empty = off)")
+	startCmd.Flags().String("tts-icecast-url", "", "Icecast mount URL with source credentials for guilds that choose the icecast output; {guild} is replaced with the guild ID (empty = off)")
+	startCmd.Flags().String("tts-pulse-sink", "", "PulseAudio sink, such as @DEFAULT_SINK@, for guilds that choose the pulse output (empty = off)")
+	startCmd.Flags().String("tts-command-permissions-token", "", "OAuth2 Bearer token of a server manager for syncing required roles to Discord's command permissions (empty = off)")
 
 	// Set up custom completion functions for start command
 	setupStartCompletions()
@@ -142,6 +168,12 @@ func bindStartFlags(cm *config.ConfigManager, cmd *cobra.Command) error {
 	if err := v.BindPFlag("tts.google_cloud_credentials_path", cmd.Flags().Lookup("google-cloud-credentials-path")); err != nil {
 		return err
 	}
+	if err := v.BindPFlag("tts.google_cloud_credentials_secret", cmd.Flags().Lookup("google-cloud-credentials-secret")); err != nil {
+		return err
+	}
+	if err := v.BindPFlag("tts.google_cloud_endpoint", cmd.Flags().Lookup("google-cloud-endpoint")); err != nil {
+		return err
+	}
 	if err := v.BindPFlag("tts.default_voice", cmd.Flags().Lookup("tts-default-voice")); err != nil {
 		return err
 	}
@@ -154,9 +186,81 @@ func bindStartFlags(cm *config.ConfigManager, cmd *cobra.Command) error {
 	if err := v.BindPFlag("tts.max_queue_size", cmd.Flags().Lookup("tts-max-queue-size")); err != nil {
 		return err
 	}
+	if err := v.BindPFlag("tts.queue_spillover_mb", cmd.Flags().Lookup("tts-queue-spillover-mb")); err != nil {
+		return err
+	}
+	if err := v.BindPFlag("tts.backlog_threshold", cmd.Flags().Lookup("tts-backlog-threshold")); err != nil {
+		return err
+	}
+	if err := v.BindPFlag("tts.backlog_max_speed", cmd.Flags().Lookup("tts-backlog-max-speed")); err != nil {
+		return err
+	}
 	if err := v.BindPFlag("tts.max_message_length", cmd.Flags().Lookup("tts-max-message-length")); err != nil {
 		return err
 	}
+	if err := v.BindPFlag("tts.daily_character_budget", cmd.Flags().Lookup("tts-daily-character-budget")); err != nil {
+		return err
+	}
+	if err := v.BindPFlag("tts.workers", cmd.Flags().Lookup("tts-workers")); err != nil {
+		return err
+	}
+	if err := v.BindPFlag("tts.synthesis_timeout", cmd.Flags().Lookup("tts-synthesis-timeout")); err != nil {
+		return err
+	}
+	if err := v.BindPFlag("tts.drain_timeout", cmd.Flags().Lookup("tts-drain-timeout")); err != nil {
+		return err
+	}
+	if err := v.BindPFlag("tts.shutdown_farewell", cmd.Flags().Lookup("tts-shutdown-farewell")); err != nil {
+		return err
+	}
+	if err := v.BindPFlag("tts.warmup", cmd.Flags().Lookup("tts-warmup")); err != nil {
+		return err
+	}
+	if err := v.BindPFlag("tts.api_address", cmd.Flags().Lookup("tts-api-address")); err != nil {
+		return err
+	}
+	if err := v.BindPFlag("tts.metrics_address", cmd.Flags().Lookup("tts-metrics-address")); err != nil {
+		return err
+	}
+	if err := v.BindPFlag("tts.features", cmd.Flags().Lookup("tts-features")); err != nil {
+		return err
+	}
+	if err := v.BindPFlag("tts.storage_encryption_keys", cmd.Flags().Lookup("tts-storage-encryption-keys")); err != nil {
+		return err
+	}
+	if err := v.BindPFlag("tts.storage_encryption_kms_key", cmd.Flags().Lookup("tts-storage-encryption-kms-key")); err != nil {
+		return err
+	}
+	if err := v.BindPFlag("tts.input_path", cmd.Flags().Lookup("tts-input-path")); err != nil {
+		return err
+	}
+	if err := v.BindPFlag("tts.input_guild_id", cmd.Flags().Lookup("tts-input-guild-id")); err != nil {
+		return err
+	}
+	if err := v.BindPFlag("tts.input_channel_id", cmd.Flags().Lookup("tts-input-channel-id")); err != nil {
+		return err
+	}
+	if err := v.BindPFlag("tts.output_dir", cmd.Flags().Lookup("tts-output-dir")); err != nil {
+		return err
+	}
+	if err := v.BindPFlag("tts.icecast_url", cmd.Flags().Lookup("tts-icecast-url")); err != nil {
+		return err
+	}
+	if err := v.BindPFlag("tts.pulse_sink", cmd.Flags().Lookup("tts-pulse-sink")); err != nil {
+		return err
+	}
+	if err := v.BindPFlag("tts.command_permissions_token", cmd.Flags().Lookup("tts-command-permissions-token")); err != nil {
+		return err
+	}
+	if err := v.BindPFlag("discord_test_guild_id", cmd.Flags().Lookup("discord-test-guild-id")); err != nil {
+		return err
+	}
+	if err := v.BindPFlag("discord_applications", cmd.Flags().Lookup("discord-applications")); err != nil {
+		return err
+	}
+	if err := v.BindPFlag("discord_owner_ids", cmd.Flags().Lookup("discord-owner-ids")); err != nil {
+		return err
+	}
 
 	return nil
 }
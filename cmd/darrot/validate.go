@@ -0,0 +1,289 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"darrot/internal/config"
+	"darrot/internal/tts"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/joho/godotenv"
+	"github.com/spf13/cobra"
+)
+
+// discordCheckTimeout bounds the Discord API call that checks the bot token
+const discordCheckTimeout = 10 * time.Second
+
+// checkStatus is the outcome of one deployment check
+type checkStatus int
+
+const (
+	checkPassed  checkStatus = iota
+	checkWarning             // The bot starts, but a feature will not work
+	checkFailed
+	checkSkipped
+)
+
+// checkResult reports a deployment check
+type checkResult struct {
+	name   string
+	status checkStatus
+	detail string
+}
+
+// validateCmd represents the validate command
+var validateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check configuration and credentials before deploying",
+	Long: `Check that the bot is ready to deploy without connecting to the Discord gateway.
+
+In addition to validating the configuration like 'darrot config validate',
+this command checks that:
+  • the Google Cloud credentials file is readable and accepted by the TTS API
+  • the Discord bot token is accepted by the Discord API
+  • the data directory exists and is writable
+
+The effective configuration is printed after the checks. Use --offline to skip
+the checks that contact Discord and Google Cloud.
+
+Exit codes:
+  0 - Ready to deploy (warnings may have been printed)
+  1 - At least one check failed`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Read the same .env file the start command would
+		_ = godotenv.Load()
+
+		offline, err := cmd.Flags().GetBool("offline")
+		if err != nil {
+			return fmt.Errorf("failed to get offline flag: %w", err)
+		}
+
+		// Create a new config manager
+		cm := config.NewConfigManager()
+
+		// Bind CLI flags to the config manager's Viper instance
+		if err := bindFlagsToConfigManager(cm, cmd); err != nil {
+			return fmt.Errorf("failed to bind flags: %w", err)
+		}
+
+		// If a config file was specified via global flag, set it on the config manager
+		if cfgFile != "" {
+			cm.SetConfigFile(cfgFile)
+		}
+
+		configWithSources, err := cm.LoadConfigWithSources()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "✗ Configuration: %v\n\n", err)
+			printValidationSuggestions(err)
+			return fmt.Errorf("configuration validation failed: %w", err)
+		}
+		cfg := configWithSources.Config
+
+		results := []checkResult{
+			{name: "Configuration", status: checkPassed, detail: "all values are valid"},
+			checkGoogleCredentials(cfg, offline),
+			checkDiscordToken(cfg.DiscordToken, offline),
+			checkDataDir(tts.DefaultDataDir),
+		}
+
+		failed := 0
+		fmt.Println("Deployment Checks:")
+		for _, result := range results {
+			fmt.Printf("  %s %s: %s\n", result.status.symbol(), result.name, result.detail)
+			if result.status == checkFailed {
+				failed++
+			}
+		}
+		fmt.Println()
+
+		if err := displayConfigHuman(configWithSources); err != nil {
+			return err
+		}
+		fmt.Println()
+
+		if failed > 0 {
+			return fmt.Errorf("%d deployment check(s) failed", failed)
+		}
+		fmt.Println("✓ Ready to deploy")
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(validateCmd)
+
+	addConfigFlags(validateCmd)
+	validateCmd.Flags().Bool("offline", false, "Skip the checks that contact Discord and Google Cloud")
+}
+
+// symbol returns the marker printed before a check result
+func (s checkStatus) symbol() string {
+	switch s {
+	case checkPassed:
+		return "✓"
+	case checkWarning:
+		return "!"
+	case checkSkipped:
+		return "-"
+	default:
+		return "✗"
+	}
+}
+
+// checkGoogleCredentials checks the credentials file or secret and, unless offline, that
+// the TTS API accepts the credentials and when they expire. Without working credentials
+// the bot starts in degraded mode.
+func checkGoogleCredentials(cfg *config.Config, offline bool) checkResult {
+	result := checkResult{name: "Google Cloud TTS"}
+	credentials := tts.CredentialSource{Path: cfg.TTS.GoogleCloudCredentialsPath, Secret: cfg.TTS.GoogleCloudCredentialsSecret}
+	endpoint := cfg.TTS.GoogleCloudEndpoint
+
+	switch {
+	case strings.HasPrefix(endpoint, "http://"):
+		// Local mocks don't check credentials
+		result.detail = fmt.Sprintf("using the unauthenticated endpoint %s", endpoint)
+	case credentials.Secret != "":
+		result.detail = fmt.Sprintf("reading credentials from Secret Manager secret %s", credentials.SecretVersion())
+	case credentials.Path != "":
+		if err := checkCredentialsFile(credentials.Path); err != nil {
+			result.status = checkFailed
+			result.detail = err.Error()
+			return result
+		}
+		result.detail = fmt.Sprintf("credentials file %s is valid", credentials.Path)
+	case os.Getenv("GOOGLE_APPLICATION_CREDENTIALS") != "":
+		result.detail = "using Application Default Credentials from GOOGLE_APPLICATION_CREDENTIALS"
+	default:
+		result.status = checkWarning
+		result.detail = "no credentials file configured, Application Default Credentials will be used if the host provides them"
+	}
+
+	if offline {
+		return result
+	}
+
+	if err := tts.CheckTTSCredentials(credentials, endpoint); err != nil {
+		result.status = checkFailed
+		result.detail = fmt.Sprintf("the TTS API rejected the request, the bot would start in degraded mode: %v", err)
+		return result
+	}
+	result.status = checkPassed
+	result.detail += ", and the TTS API accepted the request"
+
+	if credentials.IsSet() && !strings.HasPrefix(endpoint, "http://") {
+		checkCredentialExpiry(&result, credentials)
+	}
+	return result
+}
+
+// checkCredentialExpiry adds when the credentials expire to a passed check, warning when
+// that is within tts.CredentialExpiryWarning
+func checkCredentialExpiry(result *checkResult, credentials tts.CredentialSource) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	credentialsJSON, err := credentials.Load(ctx)
+	if err != nil {
+		return
+	}
+	expiry, err := tts.CredentialExpiry(ctx, credentialsJSON)
+	switch {
+	case err != nil:
+		result.status = checkWarning
+		result.detail += fmt.Sprintf(", but their expiry could not be looked up: %v", err)
+	case expiry.IsZero():
+	case time.Until(expiry) < tts.CredentialExpiryWarning:
+		result.status = checkWarning
+		result.detail += fmt.Sprintf(", but the key expires at %s", expiry.Format(time.RFC3339))
+	case expiry.Year() < 9999:
+		result.detail += fmt.Sprintf("; the key expires at %s", expiry.Format(time.RFC3339))
+	}
+}
+
+// checkCredentialsFile checks that a Google Cloud credentials file is a readable JSON
+// credentials file
+func checkCredentialsFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("cannot read credentials file: %w", err)
+	}
+
+	var credentials struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &credentials); err != nil {
+		return fmt.Errorf("credentials file %s is not valid JSON: %w", path, err)
+	}
+	if credentials.Type == "" {
+		return fmt.Errorf("credentials file %s has no credential type", path)
+	}
+	return nil
+}
+
+// checkDiscordToken checks that Discord accepts the bot token by fetching the bot's own
+// user over the REST API, without opening a gateway connection
+func checkDiscordToken(token string, offline bool) checkResult {
+	result := checkResult{name: "Discord token"}
+	if offline {
+		result.status = checkSkipped
+		result.detail = "skipped (offline)"
+		return result
+	}
+
+	session, err := discordgo.New("Bot " + token)
+	if err != nil {
+		result.status = checkFailed
+		result.detail = fmt.Sprintf("failed to create Discord session: %v", err)
+		return result
+	}
+	session.Client = &http.Client{Timeout: discordCheckTimeout}
+
+	user, err := session.User("@me")
+	if err != nil {
+		result.status = checkFailed
+		result.detail = fmt.Sprintf("the Discord API did not accept the token: %v", err)
+		return result
+	}
+
+	result.detail = fmt.Sprintf("authenticated as %s (%s)", user.Username, user.ID)
+	if !user.Bot {
+		result.status = checkWarning
+		result.detail += ", which is not a bot account"
+	}
+	return result
+}
+
+// checkDataDir checks that the directory where guild settings are stored exists, or can
+// be created, and is writable
+func checkDataDir(dir string) checkResult {
+	result := checkResult{name: "Data directory"}
+
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		result.status = checkFailed
+		result.detail = fmt.Sprintf("cannot create %s: %v", dir, err)
+		return result
+	}
+
+	file, err := os.CreateTemp(dir, ".darrot-validate-*")
+	if err != nil {
+		result.status = checkFailed
+		result.detail = fmt.Sprintf("%s is not writable: %v", dir, err)
+		return result
+	}
+	file.Close()
+	os.Remove(file.Name())
+
+	result.detail = fmt.Sprintf("%s is writable", dir)
+	return result
+}
@@ -0,0 +1,234 @@
+// Package app wires the bot's long-lived components together and runs their lifecycles
+// in a fixed order: components are initialized and started in the order they were
+// registered and stopped in reverse, so a component can rely on everything registered
+// before it while it runs.
+package app
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+)
+
+// Component is a named part of the application. Components take part in the lifecycle
+// phases they implement: Initializer, Starter and Stopper.
+type Component interface {
+	Name() string
+}
+
+// Initializer is a component with one-time setup that runs before any component starts
+type Initializer interface {
+	Init() error
+}
+
+// Starter is a component with work to start once every component is initialized
+type Starter interface {
+	Start() error
+}
+
+// Stopper is a component that releases resources on shutdown
+type Stopper interface {
+	Stop() error
+}
+
+// State is the lifecycle phase a container is in
+type State int
+
+const (
+	StateNew State = iota
+	StateInitialized
+	StateRunning
+	StateStopped
+)
+
+// String returns the name of the state
+func (s State) String() string {
+	switch s {
+	case StateNew:
+		return "new"
+	case StateInitialized:
+		return "initialized"
+	case StateRunning:
+		return "running"
+	case StateStopped:
+		return "stopped"
+	default:
+		return fmt.Sprintf("State(%d)", int(s))
+	}
+}
+
+// Container runs the lifecycles of registered components in order
+type Container struct {
+	logger *log.Logger
+
+	mu         sync.Mutex
+	components []Component
+	started    []Component // Started components, in start order
+	state      State
+}
+
+// NewContainer creates an empty container that logs lifecycle progress to logger
+func NewContainer(logger *log.Logger) *Container {
+	return &Container{logger: logger}
+}
+
+// Register adds components to the end of the lifecycle order. Components must be
+// registered before the container is initialized, and names must be unique.
+func (c *Container) Register(components ...Component) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.state != StateNew {
+		return fmt.Errorf("cannot register components in a container that is %s", c.state)
+	}
+
+	for _, component := range components {
+		if component == nil {
+			return fmt.Errorf("component cannot be nil")
+		}
+		if c.lookup(component.Name()) != nil {
+			return fmt.Errorf("component %q is already registered", component.Name())
+		}
+		c.components = append(c.components, component)
+	}
+	return nil
+}
+
+// Lookup returns the registered component with the given name, or nil
+func (c *Container) Lookup(name string) Component {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lookup(name)
+}
+
+// lookup finds a component by name; callers must hold mu
+func (c *Container) lookup(name string) Component {
+	for _, component := range c.components {
+		if component.Name() == name {
+			return component
+		}
+	}
+	return nil
+}
+
+// Components returns the names of the registered components in lifecycle order
+func (c *Container) Components() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	names := make([]string, len(c.components))
+	for i, component := range c.components {
+		names[i] = component.Name()
+	}
+	return names
+}
+
+// State returns the lifecycle phase the container is in
+func (c *Container) State() State {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.state
+}
+
+// Init initializes every component in registration order, stopping at the first error
+func (c *Container) Init() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.init()
+}
+
+// init runs the Init phase; callers must hold mu
+func (c *Container) init() error {
+	if c.state != StateNew {
+		return fmt.Errorf("container is already %s", c.state)
+	}
+
+	for _, component := range c.components {
+		initializer, ok := component.(Initializer)
+		if !ok {
+			continue
+		}
+		if err := initializer.Init(); err != nil {
+			return fmt.Errorf("failed to initialize %s: %w", component.Name(), err)
+		}
+	}
+
+	c.state = StateInitialized
+	return nil
+}
+
+// Start starts every component in registration order, initializing the container first
+// if needed. A stopped container can be started again without initializing twice. If a component fails to start, the components already started are stopped
+// in reverse order and the error is returned.
+func (c *Container) Start() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.state == StateNew {
+		if err := c.init(); err != nil {
+			return err
+		}
+	}
+	if c.state != StateInitialized && c.state != StateStopped {
+		return fmt.Errorf("cannot start a container that is %s", c.state)
+	}
+
+	for _, component := range c.components {
+		if starter, ok := component.(Starter); ok {
+			c.logf("Starting %s", component.Name())
+			if err := starter.Start(); err != nil {
+				err = fmt.Errorf("failed to start %s: %w", component.Name(), err)
+				if stopErr := c.stopStarted(); stopErr != nil {
+					err = errors.Join(err, stopErr)
+				}
+				c.state = StateStopped
+				return err
+			}
+		}
+		c.started = append(c.started, component)
+	}
+
+	c.state = StateRunning
+	return nil
+}
+
+// Stop stops every started component in reverse order. A component that fails to stop
+// does not keep the others running; all errors are returned together.
+func (c *Container) Stop() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.state != StateRunning {
+		return fmt.Errorf("cannot stop a container that is %s", c.state)
+	}
+
+	err := c.stopStarted()
+	c.state = StateStopped
+	return err
+}
+
+// stopStarted stops the started components in reverse order; callers must hold mu
+func (c *Container) stopStarted() error {
+	var errs []error
+	for i := len(c.started) - 1; i >= 0; i-- {
+		component := c.started[i]
+		stopper, ok := component.(Stopper)
+		if !ok {
+			continue
+		}
+		c.logf("Stopping %s", component.Name())
+		if err := stopper.Stop(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to stop %s: %w", component.Name(), err))
+		}
+	}
+	c.started = nil
+	return errors.Join(errs...)
+}
+
+// logf logs lifecycle progress when the container has a logger
+func (c *Container) logf(format string, args ...any) {
+	if c.logger != nil {
+		c.logger.Printf(format, args...)
+	}
+}
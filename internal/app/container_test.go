@@ -0,0 +1,126 @@
+package app
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recorder collects lifecycle calls from components in the order they happen
+type recorder struct {
+	calls []string
+}
+
+// component creates a component that records each phase and fails the phases in failOn
+func (r *recorder) component(name string, failOn ...string) *Hooks {
+	phase := func(p string) func() error {
+		return func() error {
+			r.calls = append(r.calls, p+" "+name)
+			for _, f := range failOn {
+				if f == p {
+					return errors.New(p + " failed")
+				}
+			}
+			return nil
+		}
+	}
+	return &Hooks{ComponentName: name, OnInit: phase("init"), OnStart: phase("start"), OnStop: phase("stop")}
+}
+
+func TestContainer_StartsInOrderAndStopsInReverse(t *testing.T) {
+	r := &recorder{}
+	c := NewContainer(nil)
+	require.NoError(t, c.Register(r.component("storage"), r.component("processor"), r.component("listener")))
+	assert.Equal(t, []string{"storage", "processor", "listener"}, c.Components())
+
+	require.NoError(t, c.Start())
+	assert.Equal(t, StateRunning, c.State())
+	require.NoError(t, c.Stop())
+	assert.Equal(t, StateStopped, c.State())
+
+	assert.Equal(t, []string{
+		"init storage", "init processor", "init listener",
+		"start storage", "start processor", "start listener",
+		"stop listener", "stop processor", "stop storage",
+	}, r.calls)
+}
+
+func TestContainer_StartFailureStopsStartedComponents(t *testing.T) {
+	r := &recorder{}
+	c := NewContainer(nil)
+	require.NoError(t, c.Register(r.component("storage"), r.component("processor", "start"), r.component("listener")))
+
+	err := c.Start()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to start processor")
+	assert.Equal(t, StateStopped, c.State())
+
+	// The failed component and the ones after it never started, so only storage is stopped
+	assert.Equal(t, []string{
+		"init storage", "init processor", "init listener",
+		"start storage", "start processor",
+		"stop storage",
+	}, r.calls)
+}
+
+func TestContainer_InitFailurePreventsStart(t *testing.T) {
+	r := &recorder{}
+	c := NewContainer(nil)
+	require.NoError(t, c.Register(r.component("storage", "init"), r.component("processor")))
+
+	err := c.Start()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to initialize storage")
+	assert.Equal(t, StateNew, c.State())
+	assert.Equal(t, []string{"init storage"}, r.calls)
+}
+
+func TestContainer_StopCollectsErrors(t *testing.T) {
+	r := &recorder{}
+	c := NewContainer(nil)
+	require.NoError(t, c.Register(r.component("storage", "stop"), r.component("processor", "stop")))
+	require.NoError(t, c.Start())
+
+	err := c.Stop()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to stop storage")
+	assert.Contains(t, err.Error(), "failed to stop processor")
+	assert.Equal(t, StateStopped, c.State())
+}
+
+func TestContainer_Restart(t *testing.T) {
+	r := &recorder{}
+	c := NewContainer(nil)
+	require.NoError(t, c.Register(r.component("storage")))
+
+	require.NoError(t, c.Start())
+	require.NoError(t, c.Stop())
+	require.NoError(t, c.Start())
+
+	assert.Equal(t, []string{"init storage", "start storage", "stop storage", "start storage"}, r.calls)
+}
+
+func TestContainer_StateErrors(t *testing.T) {
+	c := NewContainer(nil)
+	assert.Error(t, c.Stop(), "stopping a container that never started")
+
+	require.NoError(t, c.Init())
+	assert.Error(t, c.Init(), "initializing twice")
+	assert.Error(t, c.Register(&Hooks{ComponentName: "late"}), "registering after init")
+
+	require.NoError(t, c.Start())
+	assert.Error(t, c.Start(), "starting twice")
+}
+
+func TestContainer_RegisterValidation(t *testing.T) {
+	c := NewContainer(nil)
+	require.NoError(t, c.Register(&Hooks{ComponentName: "storage"}))
+
+	assert.Error(t, c.Register(&Hooks{ComponentName: "storage"}), "duplicate name")
+	assert.Error(t, c.Register(nil), "nil component")
+
+	assert.NotNil(t, c.Lookup("storage"))
+	assert.Nil(t, c.Lookup("missing"))
+}
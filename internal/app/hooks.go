@@ -0,0 +1,48 @@
+package app
+
+// Hooks adapts plain functions to a Component, for parts of the application whose
+// lifecycle is a method or two on a type that does not implement the phase interfaces.
+// Nil hooks are skipped.
+type Hooks struct {
+	ComponentName string
+	OnInit        func() error
+	OnStart       func() error
+	OnStop        func() error
+}
+
+// Name returns the component name
+func (h *Hooks) Name() string {
+	return h.ComponentName
+}
+
+// Init runs OnInit
+func (h *Hooks) Init() error {
+	if h.OnInit == nil {
+		return nil
+	}
+	return h.OnInit()
+}
+
+// Start runs OnStart
+func (h *Hooks) Start() error {
+	if h.OnStart == nil {
+		return nil
+	}
+	return h.OnStart()
+}
+
+// Stop runs OnStop
+func (h *Hooks) Stop() error {
+	if h.OnStop == nil {
+		return nil
+	}
+	return h.OnStop()
+}
+
+// StopFunc adapts a Stop method without a result, as on the event listeners
+func StopFunc(stop func()) func() error {
+	return func() error {
+		stop()
+		return nil
+	}
+}
@@ -7,7 +7,10 @@ import (
 	"os/signal"
 	"syscall"
 
+	"darrot/internal/app"
+	"darrot/internal/commands/textcmd"
 	"darrot/internal/config"
+	"darrot/internal/i18n"
 	"darrot/internal/tts"
 
 	"github.com/bwmarrin/discordgo"
@@ -15,16 +18,37 @@ import (
 
 // Bot represents the Discord bot instance with session management and command routing
 type Bot struct {
-	session       *discordgo.Session
-	config        *config.Config
-	logger        *log.Logger
-	commandRouter *CommandRouter
-	ttsSystem     *tts.TTSSystem
-	isRunning     bool
+	name            string // Name of the additional Discord application, empty for the main bot
+	session         *discordgo.Session
+	config          *config.Config
+	logger          *log.Logger
+	commandRouter   *CommandRouter
+	componentRouter *ComponentRouter
+	ttsSystem       *tts.TTSSystem
+	lifecycle       *app.Container
+	isRunning       bool
 }
 
 // New creates a new Bot instance with the provided configuration
 func New(cfg *config.Config) (*Bot, error) {
+	return newBot(cfg, nil)
+}
+
+// NewWithServices creates a bot whose TTS system is built around the given services.
+// Services left nil are the production implementations.
+func NewWithServices(cfg *config.Config, services *tts.Services) (*Bot, error) {
+	return newApplicationBot(cfg, "", services)
+}
+
+// newBot creates a bot whose TTS system synthesizes speech with ttsManager, or with
+// Google Cloud TTS when it is nil
+func newBot(cfg *config.Config, ttsManager tts.TTSManager) (*Bot, error) {
+	return newApplicationBot(cfg, "", &tts.Services{TTS: ttsManager})
+}
+
+// newApplicationBot creates a bot for a named additional Discord application, or the main
+// bot when name is empty, whose TTS system is built around the given services
+func newApplicationBot(cfg *config.Config, name string, services *tts.Services) (*Bot, error) {
 	if cfg == nil {
 		return nil, fmt.Errorf("configuration cannot be nil")
 	}
@@ -37,20 +61,27 @@ func New(cfg *config.Config) (*Bot, error) {
 
 	// Set required intents for message monitoring
 	// Note: IntentsMessageContent is a privileged intent that must be enabled in Discord Developer Portal
-	session.Identify.Intents = discordgo.IntentsGuildMessages | discordgo.IntentsGuildVoiceStates | discordgo.IntentsGuilds | discordgo.IntentsMessageContent
+	session.Identify.Intents = discordgo.IntentsGuildMessages | discordgo.IntentsGuildMessageReactions | discordgo.IntentsGuildVoiceStates | discordgo.IntentsGuilds | discordgo.IntentsMessageContent
 
 	// Create logger
-	logger := log.New(os.Stdout, "[BOT] ", log.LstdFlags|log.Lshortfile)
+	prefix := "[BOT] "
+	if name != "" {
+		prefix = "[BOT " + name + "] "
+	}
+	logger := log.New(os.Stdout, prefix, log.LstdFlags|log.Lshortfile)
 
 	// Create command router
 	commandRouter := NewCommandRouter(logger)
 
 	bot := &Bot{
-		session:       session,
-		config:        cfg,
-		logger:        logger,
-		commandRouter: commandRouter,
-		isRunning:     false,
+		name:            name,
+		session:         session,
+		config:          cfg,
+		logger:          logger,
+		commandRouter:   commandRouter,
+		componentRouter: NewComponentRouter(logger),
+		lifecycle:       app.NewContainer(logger),
+		isRunning:       false,
 	}
 
 	// Register the test command handler
@@ -60,7 +91,7 @@ func New(cfg *config.Config) (*Bot, error) {
 	}
 
 	// Initialize TTS system
-	ttsSystem, err := tts.NewTTSSystem(session, cfg, logger)
+	ttsSystem, err := tts.NewTTSSystemWithServices(session, cfg, logger, services)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize TTS system: %w", err)
 	}
@@ -72,12 +103,88 @@ func New(cfg *config.Config) (*Bot, error) {
 
 	bot.ttsSystem = ttsSystem
 
+	// Every command is logged, counted, answered when it fails and kept from panicking the
+	// bot; text commands are refused to members without their default permissions
+	commandRouter.Use(LogCommands(logger))
+	commandRouter.Use(RecordCommandMetrics(ttsSystem.GetMetrics()))
+	commandRouter.Use(AnswerErrors(ttsSystem.GetLocalizer(), logger))
+	commandRouter.Use(RecoverPanics(ttsSystem.GetMetrics()))
+	commandRouter.Use(CheckPermissions(commandRouter.Lookup, ttsSystem.GetServices().Permissions, ttsSystem.GetLocalizer()))
+
+	// Join and control commands are rate limited and can be limited to the voice channel
+	if controlGuard := ttsSystem.GetControlGuard(); controlGuard != nil {
+		commandRouter.Use(func(next CommandHandlerFunc) CommandHandlerFunc {
+			return controlGuard.Middleware(next)
+		})
+	}
+
+	// Register TTS component handlers
+	if err := bot.registerTTSComponentHandlers(ttsSystem); err != nil {
+		return nil, fmt.Errorf("failed to register TTS component handlers: %w", err)
+	}
+
 	// Set up event handlers
 	bot.setupEventHandlers()
 
+	if err := bot.registerLifecycle(); err != nil {
+		return nil, fmt.Errorf("failed to register bot components: %w", err)
+	}
+
 	return bot, nil
 }
 
+// registerLifecycle registers the parts of the bot in startup order. The Discord session
+// opens first and closes last, so the TTS system can use it while it starts and stops.
+func (b *Bot) registerLifecycle() error {
+	return b.lifecycle.Register(
+		&app.Hooks{
+			ComponentName: "Discord session",
+			OnStart: func() error {
+				if err := b.session.Open(); err != nil {
+					return fmt.Errorf("failed to open Discord connection: %w", err)
+				}
+				b.logger.Println("Discord connection established")
+				return nil
+			},
+			OnStop: func() error {
+				if err := b.session.Close(); err != nil {
+					b.logger.Printf("Error closing Discord connection: %v", err)
+					return fmt.Errorf("failed to close Discord connection: %w", err)
+				}
+				return nil
+			},
+		},
+		&app.Hooks{
+			ComponentName: "slash commands",
+			OnStart: func() error {
+				if err := b.registerCommands(); err != nil {
+					b.logger.Printf("Warning: Failed to register commands: %v", err)
+					// Continue running even if command registration fails
+				}
+				return nil
+			},
+		},
+		&app.Hooks{
+			ComponentName: "TTS system",
+			OnStart: func() error {
+				if err := b.ttsSystem.Start(); err != nil {
+					b.logger.Printf("Warning: Failed to start TTS system: %v", err)
+					// Continue running even if TTS system fails to start
+				}
+				return nil
+			},
+			OnStop: func() error {
+				if b.ttsSystem.IsRunning() {
+					if err := b.ttsSystem.Stop(); err != nil {
+						b.logger.Printf("Error stopping TTS system: %v", err)
+					}
+				}
+				return nil
+			},
+		},
+	)
+}
+
 // Start connects the bot to Discord and registers slash commands
 func (b *Bot) Start() error {
 	if b.isRunning {
@@ -86,23 +193,8 @@ func (b *Bot) Start() error {
 
 	b.logger.Println("Starting Discord bot...")
 
-	// Open Discord connection
-	if err := b.session.Open(); err != nil {
-		return fmt.Errorf("failed to open Discord connection: %w", err)
-	}
-
-	b.logger.Println("Discord connection established")
-
-	// Register slash commands
-	if err := b.registerCommands(); err != nil {
-		b.logger.Printf("Warning: Failed to register commands: %v", err)
-		// Continue running even if command registration fails
-	}
-
-	// Start TTS system
-	if err := b.ttsSystem.Start(); err != nil {
-		b.logger.Printf("Warning: Failed to start TTS system: %v", err)
-		// Continue running even if TTS system fails to start
+	if err := b.lifecycle.Start(); err != nil {
+		return err
 	}
 
 	b.isRunning = true
@@ -119,24 +211,21 @@ func (b *Bot) Stop() error {
 
 	b.logger.Println("Stopping Discord bot...")
 
-	// Stop TTS system
-	if err := b.ttsSystem.Stop(); err != nil {
-		b.logger.Printf("Error stopping TTS system: %v", err)
-	}
-
-	// Close Discord connection
-	if err := b.session.Close(); err != nil {
-		b.logger.Printf("Error closing Discord connection: %v", err)
-		return fmt.Errorf("failed to close Discord connection: %w", err)
+	// Every component is stopped even if one fails, so the bot is no longer running either way
+	err := b.lifecycle.Stop()
+	b.isRunning = false
+	if err != nil {
+		return err
 	}
 
-	b.isRunning = false
 	b.logger.Println("Bot stopped successfully")
 
 	return nil
 }
 
-// registerCommands registers all slash commands with Discord
+// registerCommands registers all slash commands with Discord, globally or in the test
+// guild when one is configured. Only commands that changed since the last start are
+// created, updated or deleted.
 func (b *Bot) registerCommands() error {
 	b.logger.Println("Registering slash commands...")
 
@@ -152,17 +241,19 @@ func (b *Bot) registerCommands() error {
 	}
 
 	for _, command := range commands {
-		b.logger.Printf("Registering command: %s", command.Name)
+		// Attach translated names and descriptions from the message catalogs
+		i18n.Default().LocalizeCommand(command)
+	}
 
-		_, err := b.session.ApplicationCommandCreate(b.session.State.User.ID, "", command)
-		if err != nil {
-			return fmt.Errorf("failed to register command '%s': %w", command.Name, err)
-		}
+	if b.config.DiscordTestGuildID != "" {
+		b.logger.Printf("Registering slash commands in test guild %s only", b.config.DiscordTestGuildID)
+	}
 
-		b.logger.Printf("Successfully registered command: %s", command.Name)
+	registry := NewCommandRegistry(b.session, b.logger)
+	if _, err := registry.Sync(b.session.State.User.ID, b.config.DiscordTestGuildID, commands); err != nil {
+		return err
 	}
 
-	b.logger.Printf("Successfully registered %d slash commands", len(commands))
 	return nil
 }
 
@@ -174,7 +265,7 @@ func (b *Bot) setupEventHandlers() {
 		b.logger.Printf("Bot has access to %d guilds", len(r.Guilds))
 	})
 
-	// Handle interaction events (slash commands)
+	// Handle interaction events (slash commands, components and modals)
 	b.session.AddHandler(func(s *discordgo.Session, i *discordgo.InteractionCreate) {
 		b.handleInteraction(s, i)
 	})
@@ -189,23 +280,46 @@ func (b *Bot) setupEventHandlers() {
 		b.logger.Println("Discord connection restored")
 	})
 
+	// Handle text commands for guilds that cannot use slash commands
+	b.session.AddHandler(func(s *discordgo.Session, m *discordgo.MessageCreate) {
+		b.handleTextCommand(s, m)
+	})
+
 	// Add debug handler for message events to verify they're being received
 	b.session.AddHandler(func(s *discordgo.Session, m *discordgo.MessageCreate) {
 		if !m.Author.Bot {
-			b.logger.Printf("[DEBUG] Received message from %s in guild %s: %s", m.Author.Username, m.GuildID, m.Content)
+			content := m.Content
+			if b.ttsSystem != nil {
+				content = b.ttsSystem.GetContentPolicy().Loggable(m.GuildID, content)
+			}
+			b.logger.Printf("[DEBUG] Received message from %s in guild %s: %s", m.Author.Username, m.GuildID, content)
 		}
 	})
 }
 
 // handleInteraction processes incoming Discord interactions
 func (b *Bot) handleInteraction(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	// Only handle application command interactions
-	if i.Type != discordgo.InteractionApplicationCommand {
+	var route func(*discordgo.Session, *discordgo.InteractionCreate) error
+	switch i.Type {
+	case discordgo.InteractionApplicationCommand:
+		// Route command to appropriate handler
+		route = b.commandRouter.RouteCommand
+	case discordgo.InteractionMessageComponent, discordgo.InteractionModalSubmit:
+		if i.Data == nil {
+			return // Nothing to route without a custom ID
+		}
+		// Route buttons, select menus and modals to the handler of their namespace
+		route = b.componentRouter.RouteComponent
+	default:
 		return
 	}
 
-	// Route command to appropriate handler
-	if err := b.commandRouter.RouteCommand(s, i); err != nil {
+	// A panicking handler is answered like a failing one instead of taking the bot down.
+	// Command middleware already answers the errors of commands.
+	err := tts.CatchPanic(b.metrics(), "interaction handler", i.GuildID, func() error {
+		return route(s, i)
+	})
+	if err != nil && !Answered(err) {
 		b.logger.Printf("Error handling interaction: %v", err)
 
 		// Send error response to user
@@ -213,6 +327,34 @@ func (b *Bot) handleInteraction(s *discordgo.Session, i *discordgo.InteractionCr
 	}
 }
 
+// handleTextCommand runs a prefix command typed in a guild text channel through the
+// handler of the matching slash command
+func (b *Bot) handleTextCommand(s *discordgo.Session, m *discordgo.MessageCreate) {
+	defer tts.Recover(b.metrics(), "text command", m.GuildID)
+
+	if m.Author == nil || m.Author.Bot || m.GuildID == "" || b.ttsSystem == nil {
+		return
+	}
+	if b.name != "" {
+		return // Only the main bot answers text commands, so each command runs once
+	}
+
+	interaction, err := textcmd.Parse(m.Message, b.ttsSystem.CommandPrefix(m.GuildID), b.commandRouter.Lookup)
+	if err != nil {
+		reply := "❌ " + b.ttsSystem.GetLocalizer().OptionError(m.GuildID, err)
+		if err := textcmd.Reply(s, m.Message, reply); err != nil {
+			b.logger.Printf("Failed to reply to text command: %v", err)
+		}
+		return
+	}
+	if interaction == nil {
+		return // Not a text command
+	}
+
+	b.logger.Printf("Running text command '%s' from %s in guild %s", interaction.ApplicationCommandData().Name, m.Author.Username, m.GuildID)
+	b.handleInteraction(s, interaction)
+}
+
 // sendErrorResponse sends a user-friendly error message
 func (b *Bot) sendErrorResponse(s *discordgo.Session, i *discordgo.InteractionCreate, message string) {
 	response := &discordgo.InteractionResponse{
@@ -223,16 +365,42 @@ func (b *Bot) sendErrorResponse(s *discordgo.Session, i *discordgo.InteractionCr
 		},
 	}
 
-	if err := s.InteractionRespond(i.Interaction, response); err != nil {
+	if err := textcmd.Respond(s, i.Interaction, response); err != nil {
 		b.logger.Printf("Failed to send error response: %v", err)
 	}
 }
 
+// metrics returns the registry recovered panics are counted in, or nil without a TTS system
+func (b *Bot) metrics() *tts.Metrics {
+	if b.ttsSystem == nil {
+		return nil
+	}
+	return b.ttsSystem.GetMetrics()
+}
+
 // IsRunning returns whether the bot is currently running
 func (b *Bot) IsRunning() bool {
 	return b.isRunning
 }
 
+// Name returns the name of the additional Discord application the bot runs for, or an
+// empty string for the main bot
+func (b *Bot) Name() string {
+	return b.name
+}
+
+// GetComponentRouter returns the router that button, select menu and modal handlers
+// register with
+func (b *Bot) GetComponentRouter() *ComponentRouter {
+	return b.componentRouter
+}
+
+// GetSession returns the bot's Discord session. Its HTTP client and websocket dialer can
+// be replaced before Start to connect to a mock Discord.
+func (b *Bot) GetSession() *discordgo.Session {
+	return b.session
+}
+
 // GetTTSSystem returns the TTS system for advanced usage
 func (b *Bot) GetTTSSystem() *tts.TTSSystem {
 	return b.ttsSystem
@@ -269,6 +437,22 @@ func (b *Bot) registerTTSCommandHandlers(ttsSystem *tts.TTSSystem, commandRouter
 		{"control", integration.GetControlHandler()},
 		{"opt-in", integration.GetOptInHandler()},
 		{"config", integration.GetConfigHandler()},
+		{"access", integration.GetAccessHandler()},
+		{"profile", integration.GetProfileHandler()},
+		{"clip", integration.GetClipHandler()},
+		{"play", integration.GetPlayHandler()},
+		{"moderation", integration.GetModerationHandler()},
+		{"mute", integration.GetMuteHandler()},
+		{"unmute", integration.GetUnmuteHandler()},
+		{"stats", integration.GetStatsHandler()},
+		{"preview", integration.GetPreviewHandler()},
+		{"debug", integration.GetDebugHandler()},
+		{"diagnose", integration.GetDiagnoseHandler()},
+		{"opt-in admin", integration.GetOptInAdminHandler()},
+		{"transcript", integration.GetTranscriptHandler()},
+		{"api", integration.GetAPIHandler()},
+		{"help", integration.GetHelpHandler()},
+		{"privacy", integration.GetPrivacyHandler()},
 	}
 
 	for _, h := range handlers {
@@ -280,10 +464,57 @@ func (b *Bot) registerTTSCommandHandlers(ttsSystem *tts.TTSSystem, commandRouter
 		b.logger.Printf("Registered TTS %s command handler", h.name)
 	}
 
+	// The owner command is only registered when the operator named owners
+	if ownerHandler := ttsSystem.GetOwnerHandler(); ownerHandler != nil {
+		if err := commandRouter.RegisterHandler(&ttsCommandWrapper{handler: ownerHandler}); err != nil {
+			return fmt.Errorf("failed to register TTS owner command handler: %w", err)
+		}
+		b.logger.Println("Registered TTS owner command handler")
+	}
+
 	b.logger.Println("Successfully registered all TTS command handlers")
 	return nil
 }
 
+// registerTTSComponentHandlers registers the handlers for buttons the TTS system sends
+func (b *Bot) registerTTSComponentHandlers(ttsSystem *tts.TTSSystem) error {
+	if privacyService := ttsSystem.GetPrivacyService(); privacyService != nil {
+		// The opt-out button in privacy notices carries the guild as its action
+		privacyService.SetComponentIDs(b.componentRouter)
+		if err := b.componentRouter.RegisterHandler(tts.PrivacyNoticeNamespace, ComponentHandlerFunc(
+			func(s *discordgo.Session, i *discordgo.InteractionCreate, id ComponentID) error {
+				return privacyService.HandleOptOut(s, i, id.Action)
+			})); err != nil {
+			return err
+		}
+	}
+
+	if queuePanel := ttsSystem.GetQueuePanel(); queuePanel != nil {
+		// Queue panel buttons carry what they do as their action and can be used by anyone
+		queuePanel.SetComponentIDs(b.componentRouter)
+		if err := b.componentRouter.RegisterHandler(tts.QueuePanelNamespace, ComponentHandlerFunc(
+			func(s *discordgo.Session, i *discordgo.InteractionCreate, id ComponentID) error {
+				return queuePanel.HandleButton(s, i, id.Action)
+			})); err != nil {
+			return err
+		}
+	}
+
+	if readMore := ttsSystem.GetReadMore(); readMore != nil {
+		// "Read the rest" buttons carry the long message as their action and expire with
+		// the offer
+		readMore.SetComponentIDs(b.componentRouter)
+		if err := b.componentRouter.RegisterHandler(tts.ReadMoreNamespace, ComponentHandlerFunc(
+			func(s *discordgo.Session, i *discordgo.InteractionCreate, id ComponentID) error {
+				return readMore.HandleButton(s, i, id.Action)
+			})); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // TTSCommandHandler interface that matches what TTS handlers implement
 type TTSCommandHandler interface {
 	Handle(s *discordgo.Session, i *discordgo.InteractionCreate) error
@@ -78,7 +78,7 @@ func TestNew(t *testing.T) {
 			}
 
 			// Verify all commands are registered (test + TTS commands)
-			expectedHandlers := 6 // 1 test + 5 TTS commands
+			expectedHandlers := 22 // 1 test + 21 TTS commands
 			if bot.commandRouter.GetHandlerCount() != expectedHandlers {
 				t.Errorf("New() expected %d registered handlers, got %d", expectedHandlers, bot.commandRouter.GetHandlerCount())
 			}
@@ -192,7 +192,7 @@ func TestBot_RegisterCommands(t *testing.T) {
 				// Session state will be nil (not connected to Discord)
 			},
 			expectError:    true, // Should fail because session state is not initialized
-			expectLogCount: 6,    // Should have all commands registered in router (test + TTS)
+			expectLogCount: 22,   // Should have all commands registered in router (test + TTS)
 		},
 		{
 			name: "no_commands_to_register",
@@ -279,7 +279,7 @@ func TestBot_RegisterCommands_Integration(t *testing.T) {
 
 	// Verify that the bot has the registerCommands method and it works with the command router
 	commands := bot.commandRouter.GetRegisteredCommands()
-	expectedCommands := 6 // test + 5 TTS commands
+	expectedCommands := 22 // test + 21 TTS commands
 	if len(commands) != expectedCommands {
 		t.Errorf("Expected %d registered commands, got %d", expectedCommands, len(commands))
 	}
@@ -0,0 +1,255 @@
+package bot
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"reflect"
+	"sort"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// commandAPI is the part of the Discord session that manages application commands
+type commandAPI interface {
+	ApplicationCommands(appID, guildID string, options ...discordgo.RequestOption) ([]*discordgo.ApplicationCommand, error)
+	ApplicationCommandCreate(appID, guildID string, cmd *discordgo.ApplicationCommand, options ...discordgo.RequestOption) (*discordgo.ApplicationCommand, error)
+	ApplicationCommandEdit(appID, guildID, cmdID string, cmd *discordgo.ApplicationCommand, options ...discordgo.RequestOption) (*discordgo.ApplicationCommand, error)
+	ApplicationCommandDelete(appID, guildID, cmdID string, options ...discordgo.RequestOption) error
+}
+
+// CommandDiff lists the changes that bring the registered application commands in line
+// with the local definitions
+type CommandDiff struct {
+	Create    []*discordgo.ApplicationCommand // Local commands Discord doesn't know yet
+	Update    []*discordgo.ApplicationCommand // Local commands that changed, with the ID of the registered command
+	Delete    []*discordgo.ApplicationCommand // Registered commands that no longer exist locally
+	Unchanged []string                        // Names of commands that are registered as defined
+}
+
+// Empty reports whether the registered commands already match
+func (d CommandDiff) Empty() bool {
+	return len(d.Create) == 0 && len(d.Update) == 0 && len(d.Delete) == 0
+}
+
+// CommandRegistry keeps the application commands registered with Discord in sync with
+// the local definitions, making only the create, edit and delete calls that are needed.
+// Editing an unchanged command would still count against Discord's daily command
+// creation limit and briefly hide it from clients.
+type CommandRegistry struct {
+	api    commandAPI
+	logger *log.Logger
+}
+
+// NewCommandRegistry creates a registry that manages commands through api
+func NewCommandRegistry(api commandAPI, logger *log.Logger) *CommandRegistry {
+	return &CommandRegistry{
+		api:    api,
+		logger: logger,
+	}
+}
+
+// Sync registers commands for an application, globally when guildID is empty and in
+// that guild otherwise, and removes the registered commands that are not among them
+func (r *CommandRegistry) Sync(appID, guildID string, commands []*discordgo.ApplicationCommand) (CommandDiff, error) {
+	scope := "globally"
+	if guildID != "" {
+		scope = "in guild " + guildID
+	}
+
+	existing, err := r.api.ApplicationCommands(appID, guildID)
+	if err != nil {
+		return CommandDiff{}, fmt.Errorf("failed to fetch registered commands: %w", err)
+	}
+
+	diff := DiffCommands(existing, commands)
+	if diff.Empty() {
+		r.logger.Printf("All %d slash commands are up to date %s", len(diff.Unchanged), scope)
+		return diff, nil
+	}
+
+	for _, command := range diff.Create {
+		if _, err := r.api.ApplicationCommandCreate(appID, guildID, command); err != nil {
+			return diff, fmt.Errorf("failed to create command '%s': %w", command.Name, err)
+		}
+		r.logger.Printf("Created slash command %s %s", command.Name, scope)
+	}
+
+	for _, command := range diff.Update {
+		if _, err := r.api.ApplicationCommandEdit(appID, guildID, command.ID, command); err != nil {
+			return diff, fmt.Errorf("failed to update command '%s': %w", command.Name, err)
+		}
+		r.logger.Printf("Updated slash command %s %s", command.Name, scope)
+	}
+
+	for _, command := range diff.Delete {
+		if err := r.api.ApplicationCommandDelete(appID, guildID, command.ID); err != nil {
+			return diff, fmt.Errorf("failed to delete command '%s': %w", command.Name, err)
+		}
+		r.logger.Printf("Deleted slash command %s %s", command.Name, scope)
+	}
+
+	r.logger.Printf("Synced slash commands %s: %d created, %d updated, %d deleted, %d unchanged",
+		scope, len(diff.Create), len(diff.Update), len(diff.Delete), len(diff.Unchanged))
+	return diff, nil
+}
+
+// DiffCommands compares the registered commands with the local definitions. Commands
+// are matched by type and name, and compared on the fields Discord stores, ignoring the
+// IDs and defaults Discord fills in.
+func DiffCommands(existing, local []*discordgo.ApplicationCommand) CommandDiff {
+	registered := make(map[string]*discordgo.ApplicationCommand, len(existing))
+	for _, command := range existing {
+		registered[commandKey(command)] = command
+	}
+
+	var diff CommandDiff
+	for _, command := range sortedCommands(local) {
+		key := commandKey(command)
+		current, found := registered[key]
+		delete(registered, key)
+
+		switch {
+		case !found:
+			diff.Create = append(diff.Create, command)
+		case sameCommand(current, command):
+			diff.Unchanged = append(diff.Unchanged, command.Name)
+		default:
+			update := *command
+			update.ID = current.ID
+			diff.Update = append(diff.Update, &update)
+		}
+	}
+
+	for _, command := range existing {
+		if _, stale := registered[commandKey(command)]; stale {
+			diff.Delete = append(diff.Delete, command)
+		}
+	}
+	diff.Delete = sortedCommands(diff.Delete)
+
+	return diff
+}
+
+// sortedCommands returns commands ordered by name, so changes are applied and logged in
+// a stable order
+func sortedCommands(commands []*discordgo.ApplicationCommand) []*discordgo.ApplicationCommand {
+	sorted := append([]*discordgo.ApplicationCommand(nil), commands...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+	return sorted
+}
+
+// commandKey identifies a command. User, message and slash commands may share a name.
+func commandKey(command *discordgo.ApplicationCommand) string {
+	commandType := command.Type
+	if commandType == 0 {
+		commandType = discordgo.ChatApplicationCommand
+	}
+	return fmt.Sprintf("%d:%s", commandType, command.Name)
+}
+
+// commandShape holds the fields of a command that are compared, in their JSON form so
+// that numbers and permissions decoded from Discord compare equal to local values
+type commandShape struct {
+	Type                     int               `json:"type"`
+	Name                     string            `json:"name"`
+	NameLocalizations        map[string]string `json:"name_localizations"`
+	Description              string            `json:"description"`
+	DescriptionLocalizations map[string]string `json:"description_localizations"`
+	DefaultMemberPermissions *string           `json:"default_member_permissions"`
+	DMPermission             *bool             `json:"dm_permission"`
+	NSFW                     bool              `json:"nsfw"`
+	Options                  []optionShape     `json:"options"`
+}
+
+// optionShape holds the compared fields of a command option
+type optionShape struct {
+	Type                     int               `json:"type"`
+	Name                     string            `json:"name"`
+	NameLocalizations        map[string]string `json:"name_localizations"`
+	Description              string            `json:"description"`
+	DescriptionLocalizations map[string]string `json:"description_localizations"`
+	Required                 bool              `json:"required"`
+	Autocomplete             bool              `json:"autocomplete"`
+	Choices                  []choiceShape     `json:"choices"`
+	ChannelTypes             []int             `json:"channel_types"`
+	MinValue                 *float64          `json:"min_value"`
+	MaxValue                 float64           `json:"max_value"`
+	MinLength                *int              `json:"min_length"`
+	MaxLength                int               `json:"max_length"`
+	Options                  []optionShape     `json:"options"`
+}
+
+// choiceShape holds the compared fields of an option choice
+type choiceShape struct {
+	Name              string            `json:"name"`
+	NameLocalizations map[string]string `json:"name_localizations"`
+	Value             interface{}       `json:"value"`
+}
+
+// sameCommand reports whether a registered command matches a local definition
+func sameCommand(registered, local *discordgo.ApplicationCommand) bool {
+	a, errA := shapeOf(registered)
+	b, errB := shapeOf(local)
+	if errA != nil || errB != nil {
+		// Commands that cannot be compared are updated to be safe
+		return false
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// shapeOf converts a command to its compared form, filling in Discord's defaults
+func shapeOf(command *discordgo.ApplicationCommand) (commandShape, error) {
+	var shape commandShape
+
+	data, err := json.Marshal(command)
+	if err != nil {
+		return shape, err
+	}
+	if err := json.Unmarshal(data, &shape); err != nil {
+		return shape, err
+	}
+
+	if shape.Type == 0 {
+		shape.Type = int(discordgo.ChatApplicationCommand)
+	}
+	if shape.DMPermission == nil {
+		allowed := true
+		shape.DMPermission = &allowed
+	}
+	shape.NameLocalizations = nilIfEmpty(shape.NameLocalizations)
+	shape.DescriptionLocalizations = nilIfEmpty(shape.DescriptionLocalizations)
+	shape.Options = normalizeOptions(shape.Options)
+
+	return shape, nil
+}
+
+// normalizeOptions replaces empty collections with nil, as Discord omits them
+func normalizeOptions(options []optionShape) []optionShape {
+	if len(options) == 0 {
+		return nil
+	}
+	for i := range options {
+		option := &options[i]
+		option.NameLocalizations = nilIfEmpty(option.NameLocalizations)
+		option.DescriptionLocalizations = nilIfEmpty(option.DescriptionLocalizations)
+		if len(option.Choices) == 0 {
+			option.Choices = nil
+		}
+		for j := range option.Choices {
+			option.Choices[j].NameLocalizations = nilIfEmpty(option.Choices[j].NameLocalizations)
+		}
+		if len(option.ChannelTypes) == 0 {
+			option.ChannelTypes = nil
+		}
+		option.Options = normalizeOptions(option.Options)
+	}
+	return options
+}
+
+func nilIfEmpty(localizations map[string]string) map[string]string {
+	if len(localizations) == 0 {
+		return nil
+	}
+	return localizations
+}
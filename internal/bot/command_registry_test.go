@@ -0,0 +1,212 @@
+package bot
+
+import (
+	"errors"
+	"io"
+	"log"
+	"strconv"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeCommandAPI stores commands in memory and records the calls that change them
+type fakeCommandAPI struct {
+	commands map[string][]*discordgo.ApplicationCommand // By guild ID, "" for global commands
+	calls    []string
+	nextID   int
+	failOn   string
+}
+
+func newFakeCommandAPI() *fakeCommandAPI {
+	return &fakeCommandAPI{commands: make(map[string][]*discordgo.ApplicationCommand)}
+}
+
+func (f *fakeCommandAPI) ApplicationCommands(appID, guildID string, options ...discordgo.RequestOption) ([]*discordgo.ApplicationCommand, error) {
+	return f.commands[guildID], nil
+}
+
+func (f *fakeCommandAPI) ApplicationCommandCreate(appID, guildID string, cmd *discordgo.ApplicationCommand, options ...discordgo.RequestOption) (*discordgo.ApplicationCommand, error) {
+	if f.failOn == "create "+cmd.Name {
+		return nil, errors.New("rate limited")
+	}
+	f.nextID++
+	created := *cmd
+	created.ID = strconv.Itoa(f.nextID)
+	created.ApplicationID = appID
+	f.commands[guildID] = append(f.commands[guildID], &created)
+	f.calls = append(f.calls, "create "+cmd.Name)
+	return &created, nil
+}
+
+func (f *fakeCommandAPI) ApplicationCommandEdit(appID, guildID, cmdID string, cmd *discordgo.ApplicationCommand, options ...discordgo.RequestOption) (*discordgo.ApplicationCommand, error) {
+	for i, existing := range f.commands[guildID] {
+		if existing.ID == cmdID {
+			edited := *cmd
+			edited.ApplicationID = appID
+			f.commands[guildID][i] = &edited
+			f.calls = append(f.calls, "edit "+cmd.Name)
+			return &edited, nil
+		}
+	}
+	return nil, errors.New("unknown command")
+}
+
+func (f *fakeCommandAPI) ApplicationCommandDelete(appID, guildID, cmdID string, options ...discordgo.RequestOption) error {
+	for i, existing := range f.commands[guildID] {
+		if existing.ID == cmdID {
+			f.commands[guildID] = append(f.commands[guildID][:i], f.commands[guildID][i+1:]...)
+			f.calls = append(f.calls, "delete "+existing.Name)
+			return nil
+		}
+	}
+	return errors.New("unknown command")
+}
+
+func testCommands() []*discordgo.ApplicationCommand {
+	return []*discordgo.ApplicationCommand{
+		{Name: "darrot-join", Description: "Join a voice channel", Options: []*discordgo.ApplicationCommandOption{
+			{Type: discordgo.ApplicationCommandOptionChannel, Name: "voice-channel", Description: "Channel", Required: true},
+		}},
+		{Name: "darrot-leave", Description: "Leave the voice channel"},
+		{Name: "darrot-config", Description: "Configure", Options: []*discordgo.ApplicationCommandOption{
+			{Type: discordgo.ApplicationCommandOptionInteger, Name: "speed", Description: "Speed", MinValue: &[]float64{1}[0], MaxValue: 4,
+				Choices: []*discordgo.ApplicationCommandOptionChoice{{Name: "fast", Value: 2}}},
+		}},
+	}
+}
+
+func newTestCommandRegistry(api commandAPI) *CommandRegistry {
+	return NewCommandRegistry(api, log.New(io.Discard, "", 0))
+}
+
+func TestCommandRegistry_CreatesOnlyMissingCommands(t *testing.T) {
+	api := newFakeCommandAPI()
+	registry := newTestCommandRegistry(api)
+
+	diff, err := registry.Sync("app", "", testCommands())
+	require.NoError(t, err)
+	assert.Len(t, diff.Create, 3)
+	assert.Equal(t, []string{"create darrot-config", "create darrot-join", "create darrot-leave"}, api.calls)
+
+	// A restart with the same definitions changes nothing
+	api.calls = nil
+	diff, err = registry.Sync("app", "", testCommands())
+	require.NoError(t, err)
+	assert.True(t, diff.Empty())
+	assert.Equal(t, []string{"darrot-config", "darrot-join", "darrot-leave"}, diff.Unchanged)
+	assert.Empty(t, api.calls)
+}
+
+func TestCommandRegistry_UpdatesAndDeletes(t *testing.T) {
+	api := newFakeCommandAPI()
+	registry := newTestCommandRegistry(api)
+
+	commands := testCommands()
+	commands = append(commands, &discordgo.ApplicationCommand{Name: "darrot-old", Description: "Removed later"})
+	_, err := registry.Sync("app", "", commands)
+	require.NoError(t, err)
+	api.calls = nil
+
+	commands = testCommands()
+	commands[1].Description = "Leave the voice channel now"
+	diff, err := registry.Sync("app", "", commands)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"edit darrot-leave", "delete darrot-old"}, api.calls)
+	require.Len(t, diff.Update, 1)
+	assert.NotEmpty(t, diff.Update[0].ID, "updates carry the ID of the registered command")
+	assert.Empty(t, commands[1].ID, "the local definition is not modified")
+	assert.Len(t, api.commands[""], 3)
+}
+
+func TestCommandRegistry_GuildScope(t *testing.T) {
+	api := newFakeCommandAPI()
+	api.commands[""] = []*discordgo.ApplicationCommand{{ID: "global", Name: "darrot-join", Description: "Join a voice channel"}}
+	registry := newTestCommandRegistry(api)
+
+	_, err := registry.Sync("app", "guild1", testCommands())
+	require.NoError(t, err)
+
+	assert.Len(t, api.commands["guild1"], 3)
+	assert.Len(t, api.commands[""], 1, "global commands are left alone when registering in a guild")
+}
+
+func TestCommandRegistry_StopsAtFirstError(t *testing.T) {
+	api := newFakeCommandAPI()
+	api.failOn = "create darrot-join"
+	registry := newTestCommandRegistry(api)
+
+	_, err := registry.Sync("app", "", testCommands())
+	assert.ErrorContains(t, err, "darrot-join")
+	assert.Equal(t, []string{"create darrot-config"}, api.calls)
+}
+
+func TestDiffCommands_IgnoresDiscordDefaults(t *testing.T) {
+	allowed := true
+	nsfw := false
+	empty := map[discordgo.Locale]string{}
+
+	// Registered commands come back with IDs, versions and defaults filled in
+	registered := []*discordgo.ApplicationCommand{
+		{
+			ID: "1", ApplicationID: "app", Version: "7", Type: discordgo.ChatApplicationCommand,
+			Name: "darrot-leave", Description: "Leave the voice channel",
+			DMPermission: &allowed, NSFW: &nsfw, NameLocalizations: &empty,
+		},
+	}
+	local := []*discordgo.ApplicationCommand{{Name: "darrot-leave", Description: "Leave the voice channel"}}
+
+	diff := DiffCommands(registered, local)
+	assert.True(t, diff.Empty())
+	assert.Equal(t, []string{"darrot-leave"}, diff.Unchanged)
+}
+
+func TestDiffCommands_DetectsChanges(t *testing.T) {
+	base := func() *discordgo.ApplicationCommand { return testCommands()[2] }
+
+	tests := []struct {
+		name   string
+		change func(*discordgo.ApplicationCommand)
+	}{
+		{name: "option added", change: func(c *discordgo.ApplicationCommand) {
+			c.Options = append(c.Options, &discordgo.ApplicationCommandOption{Type: discordgo.ApplicationCommandOptionBoolean, Name: "loud", Description: "Loud"})
+		}},
+		{name: "option required", change: func(c *discordgo.ApplicationCommand) { c.Options[0].Required = true }},
+		{name: "maximum changed", change: func(c *discordgo.ApplicationCommand) { c.Options[0].MaxValue = 3 }},
+		{name: "choice value changed", change: func(c *discordgo.ApplicationCommand) { c.Options[0].Choices[0].Value = 3 }},
+		{name: "localization added", change: func(c *discordgo.ApplicationCommand) {
+			c.DescriptionLocalizations = &map[discordgo.Locale]string{discordgo.German: "Einstellungen"}
+		}},
+		{name: "permissions set", change: func(c *discordgo.ApplicationCommand) {
+			permissions := int64(discordgo.PermissionManageGuild)
+			c.DefaultMemberPermissions = &permissions
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registered := base()
+			registered.ID = "1"
+			local := base()
+			tt.change(local)
+
+			diff := DiffCommands([]*discordgo.ApplicationCommand{registered}, []*discordgo.ApplicationCommand{local})
+			assert.Len(t, diff.Update, 1)
+			assert.Empty(t, diff.Unchanged)
+		})
+	}
+}
+
+func TestDiffCommands_MatchesByTypeAndName(t *testing.T) {
+	registered := []*discordgo.ApplicationCommand{
+		{ID: "1", Type: discordgo.UserApplicationCommand, Name: "darrot-leave"},
+	}
+	local := []*discordgo.ApplicationCommand{{Name: "darrot-leave", Description: "Leave the voice channel"}}
+
+	diff := DiffCommands(registered, local)
+	assert.Len(t, diff.Create, 1)
+	assert.Len(t, diff.Delete, 1)
+}
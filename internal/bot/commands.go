@@ -15,10 +15,18 @@ type CommandHandler interface {
 	Definition() *discordgo.ApplicationCommand
 }
 
+// CommandHandlerFunc handles a slash command interaction
+type CommandHandlerFunc func(s *discordgo.Session, i *discordgo.InteractionCreate) error
+
+// CommandMiddleware wraps the handling of every routed command. It can answer the
+// interaction itself instead of calling next, such as to refuse the command.
+type CommandMiddleware func(next CommandHandlerFunc) CommandHandlerFunc
+
 // CommandRouter manages command handler registration and routing
 type CommandRouter struct {
-	handlers map[string]CommandHandler
-	logger   *log.Logger
+	handlers   map[string]CommandHandler
+	middleware []CommandMiddleware
+	logger     *log.Logger
 }
 
 // NewCommandRouter creates a new CommandRouter instance
@@ -54,6 +62,12 @@ func (r *CommandRouter) RegisterHandler(handler CommandHandler) error {
 	return nil
 }
 
+// Use adds middleware that every routed command runs through. Middleware added first runs
+// first.
+func (r *CommandRouter) Use(middleware CommandMiddleware) {
+	r.middleware = append(r.middleware, middleware)
+}
+
 // RouteCommand routes an interaction to the appropriate command handler
 func (r *CommandRouter) RouteCommand(s *discordgo.Session, i *discordgo.InteractionCreate) error {
 	if i.ApplicationCommandData().Name == "" {
@@ -67,7 +81,21 @@ func (r *CommandRouter) RouteCommand(s *discordgo.Session, i *discordgo.Interact
 	}
 
 	r.logger.Printf("Routing command '%s' to handler", commandName)
-	return handler.Handle(s, i)
+	handle := CommandHandlerFunc(handler.Handle)
+	for n := len(r.middleware) - 1; n >= 0; n-- {
+		handle = r.middleware[n](handle)
+	}
+	return handle(s, i)
+}
+
+// Lookup returns the definition of the command registered under name, or nil if there
+// is none
+func (r *CommandRouter) Lookup(name string) *discordgo.ApplicationCommand {
+	handler, exists := r.handlers[name]
+	if !exists {
+		return nil
+	}
+	return handler.Definition()
 }
 
 // GetRegisteredCommands returns all registered command definitions
@@ -5,6 +5,8 @@ import (
 	"os"
 	"testing"
 
+	"darrot/internal/commands/textcmd"
+
 	"github.com/bwmarrin/discordgo"
 	"github.com/stretchr/testify/assert"
 )
@@ -13,6 +15,7 @@ import (
 type MockCommandHandler struct {
 	name        string
 	description string
+	permissions *int64
 	handleFunc  func(s *discordgo.Session, i *discordgo.InteractionCreate) error
 }
 
@@ -28,6 +31,8 @@ func (m *MockCommandHandler) Definition() *discordgo.ApplicationCommand {
 		Name:        m.name,
 		Description: m.description,
 		Type:        discordgo.ChatApplicationCommand,
+
+		DefaultMemberPermissions: m.permissions,
 	}
 }
 
@@ -173,6 +178,55 @@ func TestCommandRouter_RouteCommand(t *testing.T) {
 	}
 }
 
+func TestCommandRouter_Middleware(t *testing.T) {
+	router := NewCommandRouter(log.New(os.Stdout, "test: ", log.LstdFlags))
+
+	var calls []string
+	handler := &MockCommandHandler{
+		name: "test",
+		handleFunc: func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+			calls = append(calls, "handler")
+			return nil
+		},
+	}
+	if err := router.RegisterHandler(handler); err != nil {
+		t.Fatalf("failed to register handler: %v", err)
+	}
+
+	record := func(name string, callNext bool) CommandMiddleware {
+		return func(next CommandHandlerFunc) CommandHandlerFunc {
+			return func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+				calls = append(calls, name)
+				if !callNext {
+					return nil
+				}
+				return next(s, i)
+			}
+		}
+	}
+	router.Use(record("first", true))
+	router.Use(record("second", true))
+
+	interaction := &discordgo.InteractionCreate{
+		Interaction: &discordgo.Interaction{
+			Type: discordgo.InteractionApplicationCommand,
+			Data: discordgo.ApplicationCommandInteractionData{Name: "test"},
+		},
+	}
+	if err := router.RouteCommand(nil, interaction); err != nil {
+		t.Fatalf("unexpected error routing command: %v", err)
+	}
+	assert.Equal(t, []string{"first", "second", "handler"}, calls, "middleware runs in the order it was added")
+
+	// Middleware that answers the interaction itself stops the command
+	calls = nil
+	router.Use(record("refuse", false))
+	if err := router.RouteCommand(nil, interaction); err != nil {
+		t.Fatalf("unexpected error routing command: %v", err)
+	}
+	assert.Equal(t, []string{"first", "second", "refuse"}, calls)
+}
+
 func TestCommandRouter_RouteCommand_UnknownCommand(t *testing.T) {
 	logger := log.New(os.Stdout, "test: ", log.LstdFlags)
 	router := NewCommandRouter(logger)
@@ -227,6 +281,63 @@ func TestCommandRouter_RouteCommand_EmptyCommandName(t *testing.T) {
 	}
 }
 
+func TestCommandRouter_Lookup(t *testing.T) {
+	logger := log.New(os.Stdout, "test: ", log.LstdFlags)
+	router := NewCommandRouter(logger)
+
+	if err := router.RegisterHandler(&MockCommandHandler{name: "darrot-join", description: "Join"}); err != nil {
+		t.Fatalf("failed to register handler: %v", err)
+	}
+
+	if definition := router.Lookup("darrot-join"); definition == nil || definition.Name != "darrot-join" {
+		t.Errorf("expected darrot-join definition, got %v", definition)
+	}
+	if definition := router.Lookup("darrot-leave"); definition != nil {
+		t.Errorf("expected no definition for unregistered command, got %v", definition)
+	}
+}
+
+func TestCommandRouter_RouteTextCommand(t *testing.T) {
+	logger := log.New(os.Stdout, "test: ", log.LstdFlags)
+	router := NewCommandRouter(logger)
+
+	var routed *discordgo.InteractionCreate
+	handler := &MockCommandHandler{
+		name:        "darrot-join",
+		description: "Join",
+		handleFunc: func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+			routed = i
+			return nil
+		},
+	}
+	if err := router.RegisterHandler(handler); err != nil {
+		t.Fatalf("failed to register handler: %v", err)
+	}
+
+	// A text command reaches the same handler as the slash command
+	message := &discordgo.Message{
+		ID:        "msg-1",
+		GuildID:   "guild-1",
+		ChannelID: "chan-1",
+		Content:   "!darrot join",
+		Author:    &discordgo.User{ID: "user-1"},
+	}
+	interaction, err := textcmd.Parse(message, "!darrot", router.Lookup)
+	if err != nil {
+		t.Fatalf("failed to parse text command: %v", err)
+	}
+	if err := router.RouteCommand(nil, interaction); err != nil {
+		t.Fatalf("unexpected error routing text command: %v", err)
+	}
+
+	if routed == nil {
+		t.Fatal("handler was not called")
+	}
+	if !textcmd.IsTextCommand(routed.Interaction) || routed.Member.User.ID != "user-1" {
+		t.Errorf("unexpected interaction routed: %+v", routed.Interaction)
+	}
+}
+
 func TestCommandRouter_GetRegisteredCommands(t *testing.T) {
 	logger := log.New(os.Stdout, "test: ", log.LstdFlags)
 	router := NewCommandRouter(logger)
@@ -0,0 +1,219 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"darrot/internal/commands/textcmd"
+	"darrot/internal/i18n"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Component custom IDs have the form namespace:owner:expiry:action. The namespace picks
+// the handler, owner is the only user allowed to use the component (empty for anyone),
+// expiry is a base 36 Unix time after which the component is rejected (0 for never) and
+// action is free-form data for the handler. Everything the router checks is carried in
+// the custom ID itself, so components keep working across restarts without stored state.
+const (
+	customIDSeparator = ":"
+
+	// maxCustomIDLength is Discord's limit for component and modal custom IDs
+	maxCustomIDLength = 100
+)
+
+// ComponentID is a decoded component custom ID
+type ComponentID struct {
+	Namespace string
+	Action    string
+	OwnerID   string    // Only this user may use the component; empty allows anyone
+	ExpiresAt time.Time // Zero for components that never expire
+}
+
+// Encode returns the custom ID for the component
+func (c ComponentID) Encode() (string, error) {
+	if c.Namespace == "" {
+		return "", fmt.Errorf("component namespace cannot be empty")
+	}
+	if strings.Contains(c.Namespace, customIDSeparator) || strings.Contains(c.OwnerID, customIDSeparator) {
+		return "", fmt.Errorf("component namespace and owner cannot contain '%s'", customIDSeparator)
+	}
+
+	expiry := "0"
+	if !c.ExpiresAt.IsZero() {
+		expiry = strconv.FormatInt(c.ExpiresAt.Unix(), 36)
+	}
+
+	customID := strings.Join([]string{c.Namespace, c.OwnerID, expiry, c.Action}, customIDSeparator)
+	if len(customID) > maxCustomIDLength {
+		return "", fmt.Errorf("component custom ID is %d characters, Discord allows %d", len(customID), maxCustomIDLength)
+	}
+	return customID, nil
+}
+
+// ParseComponentID decodes a custom ID created by ComponentID.Encode
+func ParseComponentID(customID string) (ComponentID, error) {
+	parts := strings.SplitN(customID, customIDSeparator, 4)
+	if len(parts) != 4 || parts[0] == "" {
+		return ComponentID{}, fmt.Errorf("malformed component custom ID: %q", customID)
+	}
+
+	expiry, err := strconv.ParseInt(parts[2], 36, 64)
+	if err != nil {
+		return ComponentID{}, fmt.Errorf("malformed component expiry in custom ID %q: %w", customID, err)
+	}
+
+	id := ComponentID{Namespace: parts[0], OwnerID: parts[1], Action: parts[3]}
+	if expiry != 0 {
+		id.ExpiresAt = time.Unix(expiry, 0)
+	}
+	return id, nil
+}
+
+// ComponentHandler handles button, select menu and modal submit interactions for the
+// components of one namespace
+type ComponentHandler interface {
+	// HandleComponent processes the interaction. The router has already checked the
+	// component's expiry and owner.
+	HandleComponent(s *discordgo.Session, i *discordgo.InteractionCreate, id ComponentID) error
+}
+
+// ComponentHandlerFunc adapts a function to the ComponentHandler interface
+type ComponentHandlerFunc func(s *discordgo.Session, i *discordgo.InteractionCreate, id ComponentID) error
+
+// HandleComponent calls f(s, i, id)
+func (f ComponentHandlerFunc) HandleComponent(s *discordgo.Session, i *discordgo.InteractionCreate, id ComponentID) error {
+	return f(s, i, id)
+}
+
+// ComponentRouter dispatches MessageComponent and ModalSubmit interactions to the handler
+// registered for the namespace of their custom ID. Expired components and components
+// used by someone other than their owner are answered with an ephemeral message and
+// never reach the handler.
+type ComponentRouter struct {
+	handlers map[string]ComponentHandler
+	logger   *log.Logger
+	mu       sync.RWMutex
+
+	// Overridable for tests
+	now     func() time.Time
+	respond func(s *discordgo.Session, i *discordgo.InteractionCreate, content string) error
+}
+
+// NewComponentRouter creates a new ComponentRouter instance
+func NewComponentRouter(logger *log.Logger) *ComponentRouter {
+	return &ComponentRouter{
+		handlers: make(map[string]ComponentHandler),
+		logger:   logger,
+		now:      time.Now,
+		respond:  respondEphemeral,
+	}
+}
+
+// RegisterHandler registers the handler for the components of a namespace
+func (r *ComponentRouter) RegisterHandler(namespace string, handler ComponentHandler) error {
+	if handler == nil {
+		return fmt.Errorf("handler cannot be nil")
+	}
+	if namespace == "" {
+		return fmt.Errorf("component namespace cannot be empty")
+	}
+	if strings.Contains(namespace, customIDSeparator) {
+		return fmt.Errorf("component namespace cannot contain '%s'", customIDSeparator)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.handlers[namespace]; exists {
+		return fmt.Errorf("handler for component namespace '%s' is already registered", namespace)
+	}
+
+	r.handlers[namespace] = handler
+	r.logger.Printf("Registered component handler: %s", namespace)
+	return nil
+}
+
+// NewCustomID returns a custom ID for a component in namespace. Only ownerID may use
+// the component unless it is empty, and the component expires after ttl unless ttl is 0.
+func (r *ComponentRouter) NewCustomID(namespace, action, ownerID string, ttl time.Duration) (string, error) {
+	id := ComponentID{Namespace: namespace, Action: action, OwnerID: ownerID}
+	if ttl > 0 {
+		id.ExpiresAt = r.now().Add(ttl)
+	}
+	return id.Encode()
+}
+
+// RouteComponent routes a component or modal submit interaction to its handler
+func (r *ComponentRouter) RouteComponent(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	var customID string
+	switch i.Type {
+	case discordgo.InteractionMessageComponent:
+		customID = i.MessageComponentData().CustomID
+	case discordgo.InteractionModalSubmit:
+		customID = i.ModalSubmitData().CustomID
+	default:
+		return fmt.Errorf("interaction type %s is not a component interaction", i.Type)
+	}
+
+	id, err := ParseComponentID(customID)
+	if err != nil {
+		return err
+	}
+
+	r.mu.RLock()
+	handler, exists := r.handlers[id.Namespace]
+	r.mu.RUnlock()
+	if !exists {
+		return fmt.Errorf("no handler registered for component namespace: %s", id.Namespace)
+	}
+
+	catalog := i18n.Default()
+	if !id.ExpiresAt.IsZero() && r.now().After(id.ExpiresAt) {
+		r.logger.Printf("Rejected expired component '%s' (expired %s)", customID, id.ExpiresAt.Format(time.RFC3339))
+		return r.respond(s, i, catalog.T(string(i.Locale), "components.expired"))
+	}
+
+	if id.OwnerID != "" && interactionUserID(i) != id.OwnerID {
+		r.logger.Printf("Rejected component '%s' used by %s instead of its owner", customID, interactionUserID(i))
+		return r.respond(s, i, catalog.T(string(i.Locale), "components.not_owner", id.OwnerID))
+	}
+
+	r.logger.Printf("Routing component '%s' to handler", customID)
+	return handler.HandleComponent(s, i, id)
+}
+
+// GetHandlerCount returns the number of registered handlers
+func (r *ComponentRouter) GetHandlerCount() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return len(r.handlers)
+}
+
+// interactionUserID returns the user who triggered an interaction in a guild or a DM
+func interactionUserID(i *discordgo.InteractionCreate) string {
+	if i.Member != nil && i.Member.User != nil {
+		return i.Member.User.ID
+	}
+	if i.User != nil {
+		return i.User.ID
+	}
+	return ""
+}
+
+// respondEphemeral answers an interaction with a message only its user can see. Text
+// commands get it as a reply.
+func respondEphemeral(s *discordgo.Session, i *discordgo.InteractionCreate, content string) error {
+	return textcmd.Respond(s, i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: content,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
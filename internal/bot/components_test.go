@@ -0,0 +1,209 @@
+package bot
+
+import (
+	"errors"
+	"io"
+	"log"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestComponentRouter returns a router with a fixed clock that records rejections
+// instead of sending them to Discord
+func newTestComponentRouter(now time.Time) (*ComponentRouter, *[]string) {
+	router := NewComponentRouter(log.New(io.Discard, "", 0))
+	router.now = func() time.Time { return now }
+
+	var responses []string
+	router.respond = func(s *discordgo.Session, i *discordgo.InteractionCreate, content string) error {
+		responses = append(responses, content)
+		return nil
+	}
+	return router, &responses
+}
+
+// componentInteraction builds a button interaction used by userID in a guild
+func componentInteraction(customID, userID string) *discordgo.InteractionCreate {
+	return &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+		Type:    discordgo.InteractionMessageComponent,
+		GuildID: "guild-1",
+		Member:  &discordgo.Member{User: &discordgo.User{ID: userID}},
+		Data:    discordgo.MessageComponentInteractionData{CustomID: customID},
+	}}
+}
+
+func TestComponentID_EncodeParseRoundTrip(t *testing.T) {
+	expiresAt := time.Unix(1760000000, 0)
+
+	tests := []struct {
+		name string
+		id   ComponentID
+	}{
+		{"owner and expiry", ComponentID{Namespace: "queue", Action: "page:2", OwnerID: "123456789012345678", ExpiresAt: expiresAt}},
+		{"anyone, never expires", ComponentID{Namespace: "setup", Action: "next"}},
+		{"empty action", ComponentID{Namespace: "setup", OwnerID: "42"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			customID, err := tt.id.Encode()
+			require.NoError(t, err)
+
+			parsed, err := ParseComponentID(customID)
+			require.NoError(t, err)
+			assert.Equal(t, tt.id.Namespace, parsed.Namespace)
+			assert.Equal(t, tt.id.Action, parsed.Action)
+			assert.Equal(t, tt.id.OwnerID, parsed.OwnerID)
+			assert.True(t, tt.id.ExpiresAt.Equal(parsed.ExpiresAt))
+		})
+	}
+}
+
+func TestComponentID_EncodeErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		id       ComponentID
+		errorMsg string
+	}{
+		{"empty namespace", ComponentID{Action: "x"}, "namespace cannot be empty"},
+		{"separator in namespace", ComponentID{Namespace: "a:b"}, "cannot contain"},
+		{"separator in owner", ComponentID{Namespace: "a", OwnerID: "1:2"}, "cannot contain"},
+		{"too long", ComponentID{Namespace: "a", Action: strings.Repeat("x", 100)}, "Discord allows 100"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.id.Encode()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errorMsg)
+		})
+	}
+}
+
+func TestParseComponentID_Malformed(t *testing.T) {
+	for _, customID := range []string{"", "legacy-button", "ns:owner:0", ":owner:0:action", "ns:owner:not base 36:action"} {
+		_, err := ParseComponentID(customID)
+		assert.Error(t, err, "custom ID %q", customID)
+	}
+}
+
+func TestComponentRouter_RegisterHandler(t *testing.T) {
+	router, _ := newTestComponentRouter(time.Now())
+	noop := ComponentHandlerFunc(func(s *discordgo.Session, i *discordgo.InteractionCreate, id ComponentID) error { return nil })
+
+	require.NoError(t, router.RegisterHandler("queue", noop))
+	assert.Equal(t, 1, router.GetHandlerCount())
+
+	assert.ErrorContains(t, router.RegisterHandler("queue", noop), "already registered")
+	assert.ErrorContains(t, router.RegisterHandler("", noop), "cannot be empty")
+	assert.ErrorContains(t, router.RegisterHandler("a:b", noop), "cannot contain")
+	assert.ErrorContains(t, router.RegisterHandler("setup", nil), "handler cannot be nil")
+	assert.Equal(t, 1, router.GetHandlerCount())
+}
+
+func TestComponentRouter_RouteComponent(t *testing.T) {
+	now := time.Unix(1760000000, 0)
+	router, responses := newTestComponentRouter(now)
+
+	var handled []ComponentID
+	require.NoError(t, router.RegisterHandler("queue", ComponentHandlerFunc(
+		func(s *discordgo.Session, i *discordgo.InteractionCreate, id ComponentID) error {
+			handled = append(handled, id)
+			return nil
+		})))
+
+	customID, err := router.NewCustomID("queue", "page:2", "owner", time.Minute)
+	require.NoError(t, err)
+
+	// The owner reaches the handler with the decoded action
+	require.NoError(t, router.RouteComponent(nil, componentInteraction(customID, "owner")))
+	require.Len(t, handled, 1)
+	assert.Equal(t, "page:2", handled[0].Action)
+	assert.Empty(t, *responses)
+
+	// Another user is turned away
+	require.NoError(t, router.RouteComponent(nil, componentInteraction(customID, "someone-else")))
+	assert.Len(t, handled, 1)
+	require.Len(t, *responses, 1)
+	assert.Contains(t, (*responses)[0], "<@owner>")
+
+	// Once the TTL has passed the component is rejected even for its owner
+	router.now = func() time.Time { return now.Add(2 * time.Minute) }
+	require.NoError(t, router.RouteComponent(nil, componentInteraction(customID, "owner")))
+	assert.Len(t, handled, 1)
+	require.Len(t, *responses, 2)
+	assert.Contains(t, (*responses)[1], "expired")
+}
+
+func TestComponentRouter_RouteComponentAnyoneAndDMs(t *testing.T) {
+	router, responses := newTestComponentRouter(time.Now())
+
+	calls := 0
+	require.NoError(t, router.RegisterHandler("poll", ComponentHandlerFunc(
+		func(s *discordgo.Session, i *discordgo.InteractionCreate, id ComponentID) error {
+			calls++
+			return nil
+		})))
+
+	shared, err := router.NewCustomID("poll", "vote", "", 0)
+	require.NoError(t, err)
+	require.NoError(t, router.RouteComponent(nil, componentInteraction(shared, "anyone")))
+
+	// In DMs the user is set on the interaction instead of the member
+	owned, err := router.NewCustomID("poll", "vote", "dm-user", 0)
+	require.NoError(t, err)
+	dm := componentInteraction(owned, "")
+	dm.Member = nil
+	dm.User = &discordgo.User{ID: "dm-user"}
+	require.NoError(t, router.RouteComponent(nil, dm))
+
+	assert.Equal(t, 2, calls)
+	assert.Empty(t, *responses)
+}
+
+func TestComponentRouter_RouteModalSubmit(t *testing.T) {
+	router, _ := newTestComponentRouter(time.Now())
+
+	var action string
+	require.NoError(t, router.RegisterHandler("setup", ComponentHandlerFunc(
+		func(s *discordgo.Session, i *discordgo.InteractionCreate, id ComponentID) error {
+			action = id.Action
+			return nil
+		})))
+
+	customID, err := router.NewCustomID("setup", "voice-modal", "owner", time.Minute)
+	require.NoError(t, err)
+
+	interaction := &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+		Type:   discordgo.InteractionModalSubmit,
+		Member: &discordgo.Member{User: &discordgo.User{ID: "owner"}},
+		Data:   discordgo.ModalSubmitInteractionData{CustomID: customID},
+	}}
+	require.NoError(t, router.RouteComponent(nil, interaction))
+	assert.Equal(t, "voice-modal", action)
+}
+
+func TestComponentRouter_RouteComponentErrors(t *testing.T) {
+	router, _ := newTestComponentRouter(time.Now())
+	handlerErr := errors.New("handler failed")
+	require.NoError(t, router.RegisterHandler("queue", ComponentHandlerFunc(
+		func(s *discordgo.Session, i *discordgo.InteractionCreate, id ComponentID) error { return handlerErr })))
+
+	unknown, err := router.NewCustomID("missing", "x", "", 0)
+	require.NoError(t, err)
+	assert.ErrorContains(t, router.RouteComponent(nil, componentInteraction(unknown, "u")), "no handler registered")
+
+	assert.ErrorContains(t, router.RouteComponent(nil, componentInteraction("legacy", "u")), "malformed")
+
+	known, err := router.NewCustomID("queue", "x", "", 0)
+	require.NoError(t, err)
+	assert.ErrorIs(t, router.RouteComponent(nil, componentInteraction(known, "u")), handlerErr)
+
+	command := &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{Type: discordgo.InteractionApplicationCommand}}
+	assert.ErrorContains(t, router.RouteComponent(nil, command), "not a component interaction")
+}
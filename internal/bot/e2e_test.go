@@ -0,0 +1,161 @@
+package bot
+
+import (
+	"bytes"
+	"darrot/internal/config"
+	"darrot/internal/tts"
+	"encoding/binary"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"mock-discord/mockdiscord"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// End-to-end tests run the real bot against the in-process mock Discord from
+// tests/mock-discord: slash commands arrive over the gateway, the voice handshake runs
+// over websocket and UDP, and the audio the bot sends is decrypted and captured.
+
+// e2eFrames are the Opus frames the fake speech manager returns for every message
+var e2eFrames = [][]byte{
+	{0xF8, 0xFF, 0xFE, 0x01},
+	{0xF8, 0xFF, 0xFE, 0x02},
+	{0xF8, 0xFF, 0xFE, 0x03},
+}
+
+// fakeSpeechManager synthesizes every message as e2eFrames in DCA format
+type fakeSpeechManager struct{}
+
+func (m *fakeSpeechManager) ConvertToSpeech(text, voice string, config tts.TTSConfig) ([]byte, error) {
+	var dca bytes.Buffer
+	for _, frame := range e2eFrames {
+		binary.Write(&dca, binary.LittleEndian, int16(len(frame)))
+		dca.Write(frame)
+	}
+	return dca.Bytes(), nil
+}
+
+func (m *fakeSpeechManager) ProcessMessageQueue(guildID string) error { return nil }
+
+func (m *fakeSpeechManager) SetVoiceConfig(guildID string, config tts.TTSConfig) error { return nil }
+
+func (m *fakeSpeechManager) GetSupportedVoices() []tts.Voice { return nil }
+
+// startE2EBot starts a bot connected to a fresh mock Discord fixture. Bot data is kept in
+// a temporary working directory.
+func startE2EBot(t *testing.T) (*Bot, *mockdiscord.Fixture) {
+	t.Helper()
+
+	fixture, err := mockdiscord.NewFixture()
+	require.NoError(t, err)
+	t.Cleanup(fixture.Close)
+
+	workDir, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(t.TempDir()))
+	t.Cleanup(func() { os.Chdir(workDir) })
+
+	cfg := config.GetDefaultConfig()
+	cfg.DiscordToken = "test-bot-token"
+
+	bot, err := newBot(cfg, &fakeSpeechManager{})
+	require.NoError(t, err)
+
+	bot.session.Client = fixture.HTTPClient()
+	bot.session.Dialer = fixture.Dialer()
+
+	require.NoError(t, bot.Start())
+	t.Cleanup(func() { bot.Stop() })
+
+	return bot, fixture
+}
+
+// waitForResponse waits for the bot to answer an interaction
+func waitForResponse(t *testing.T, fixture *mockdiscord.Fixture, interactionID string) mockdiscord.InteractionResponse {
+	t.Helper()
+
+	var response mockdiscord.InteractionResponse
+	require.Eventually(t, func() bool {
+		var ok bool
+		response, ok = fixture.InteractionResponse(interactionID)
+		return ok
+	}, 20*time.Second, 20*time.Millisecond, "no response to interaction %s", interactionID)
+	return response
+}
+
+func TestEndToEnd_JoinSpeakLeave(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping end-to-end test in short mode")
+	}
+
+	bot, fixture := startE2EBot(t)
+	assert.Contains(t, fixture.API.GetCommands(), "darrot-join")
+
+	// Registering again after a restart leaves the unchanged commands alone
+	writes := fixture.API.CommandWrites()
+	require.NoError(t, bot.registerCommands())
+	assert.Equal(t, writes, fixture.API.CommandWrites())
+
+	// Join: the bot enters the voice channel and pairs the text channel
+	joinID := fixture.SendCommand(mockdiscord.TestGuildID, mockdiscord.TestTextChannelID, mockdiscord.TestUserID,
+		"darrot-join", mockdiscord.ChannelOption("voice-channel", mockdiscord.TestVoiceChannelID))
+
+	response := waitForResponse(t, fixture, joinID)
+	require.False(t, strings.HasPrefix(response.Content(), "❌"), "join failed: %s", response.Content())
+	assert.Contains(t, response.Content(), "General Voice")
+	assert.Equal(t, mockdiscord.TestVoiceChannelID, fixture.BotVoiceChannel(mockdiscord.TestGuildID))
+
+	connection, connected := fixture.Voice.Connection(mockdiscord.TestGuildID)
+	require.True(t, connected, "bot did not complete the voice handshake")
+	assert.Equal(t, mockdiscord.BotUserID, connection.UserID)
+
+	// Enqueue and speak: a message from the auto opted-in inviter is read aloud
+	fixture.SendMessage(mockdiscord.TestTextChannelID, mockdiscord.TestUserID, "hello from the end-to-end test")
+
+	require.Eventually(t, func() bool {
+		return len(fixture.CapturedFrames(mockdiscord.TestVoiceChannelID)) >= len(e2eFrames)
+	}, 20*time.Second, 20*time.Millisecond, "bot did not speak the message")
+	assert.Equal(t, e2eFrames, fixture.CapturedFrames(mockdiscord.TestVoiceChannelID)[:len(e2eFrames)])
+
+	connection, _ = fixture.Voice.Connection(mockdiscord.TestGuildID)
+	assert.True(t, connection.Speaking)
+
+	// Leave: the bot drops the connection and stops monitoring
+	leaveID := fixture.SendCommand(mockdiscord.TestGuildID, mockdiscord.TestTextChannelID, mockdiscord.TestUserID, "darrot-leave")
+
+	response = waitForResponse(t, fixture, leaveID)
+	assert.Contains(t, response.Content(), "Left voice channel")
+	assert.False(t, bot.GetTTSSystem().GetVoiceManager().IsConnected(mockdiscord.TestGuildID))
+}
+
+func TestEndToEnd_PanickingCommandIsAnswered(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping end-to-end test in short mode")
+	}
+
+	bot, fixture := startE2EBot(t)
+	require.NoError(t, bot.commandRouter.RegisterHandler(&MockCommandHandler{
+		name:        "darrot-panic",
+		description: "Panics",
+		handleFunc: func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+			var settings map[string]string
+			settings["voice"] = "en-US" // Writing to a nil map panics
+			return nil
+		},
+	}))
+
+	panicID := fixture.SendCommand(mockdiscord.TestGuildID, mockdiscord.TestTextChannelID, mockdiscord.TestUserID, "darrot-panic")
+
+	response := waitForResponse(t, fixture, panicID)
+	assert.Equal(t, "Sorry, something went wrong processing your command.", response.Content())
+	assert.Equal(t, 1.0, bot.GetTTSSystem().GetMetrics().Value(tts.MetricPanicsRecovered, tts.Labels{"component": "interaction handler"}))
+
+	// The bot keeps answering commands
+	leaveID := fixture.SendCommand(mockdiscord.TestGuildID, mockdiscord.TestTextChannelID, mockdiscord.TestUserID, "darrot-leave")
+	waitForResponse(t, fixture, leaveID)
+}
@@ -0,0 +1,186 @@
+package bot
+
+import (
+	"errors"
+	"log"
+	"slices"
+	"time"
+
+	"darrot/internal/commands/textcmd"
+	"darrot/internal/tts"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Command metric names
+const (
+	MetricCommands       = "darrot_commands_total"
+	MetricCommandSeconds = "darrot_command_seconds_total"
+)
+
+// The middleware every routed command runs through, outermost first:
+//
+//	LogCommands            logs each command with how long it took
+//	RecordCommandMetrics   counts commands by result and the time spent in them
+//	AnswerErrors           tells the user about a failed command in their guild's language
+//	RecoverPanics          turns a panicking handler into a failed command
+//	CheckPermissions       refuses text commands to members without their default permissions
+//
+// Middleware that refuses a command answers the interaction itself and returns nil, so
+// a refusal is not counted as a failed command.
+
+// answeredError is an error the user was already told about
+type answeredError struct {
+	error
+}
+
+func (e answeredError) Unwrap() error {
+	return e.error
+}
+
+// Answered reports whether the user was told about a command's error, so it must not be
+// answered again
+func Answered(err error) bool {
+	var answered answeredError
+	return errors.As(err, &answered)
+}
+
+// LogCommands logs each command, who ran it and how long it took
+func LogCommands(logger *log.Logger) CommandMiddleware {
+	return func(next CommandHandlerFunc) CommandHandlerFunc {
+		return func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+			started := time.Now()
+			err := next(s, i)
+			elapsed := time.Since(started).Round(time.Millisecond)
+
+			name := i.ApplicationCommandData().Name
+			if err != nil {
+				logger.Printf("Command /%s from user %s in guild %s failed after %s: %v", name, interactionUserID(i), i.GuildID, elapsed, err)
+			} else {
+				logger.Printf("Command /%s from user %s in guild %s finished in %s", name, interactionUserID(i), i.GuildID, elapsed)
+			}
+			return err
+		}
+	}
+}
+
+// RecordCommandMetrics counts commands by name and result, ok or the kind of error that
+// failed them, and adds up the seconds spent handling them. A nil metrics records nothing.
+func RecordCommandMetrics(metrics *tts.Metrics) CommandMiddleware {
+	if metrics != nil {
+		metrics.Describe(MetricCommands, tts.MetricTypeCounter, "Commands handled, by command and result")
+		metrics.Describe(MetricCommandSeconds, tts.MetricTypeCounter, "Seconds spent handling commands, by command")
+	}
+
+	return func(next CommandHandlerFunc) CommandHandlerFunc {
+		return func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+			started := time.Now()
+			err := next(s, i)
+			if metrics == nil {
+				return err
+			}
+
+			name := i.ApplicationCommandData().Name
+			result := "ok"
+			if err != nil {
+				result = string(tts.KindOf(err))
+			}
+			metrics.IncCounter(MetricCommands, tts.Labels{"command": name, "result": result})
+			metrics.AddCounter(MetricCommandSeconds, tts.Labels{"command": name}, time.Since(started).Seconds())
+			return err
+		}
+	}
+}
+
+// AnswerErrors tells the user about a command that failed with an ephemeral message
+// matching the kind of error, in the guild's language. The error is still returned,
+// marked as answered.
+func AnswerErrors(localizer *tts.Localizer, logger *log.Logger) CommandMiddleware {
+	return func(next CommandHandlerFunc) CommandHandlerFunc {
+		return func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+			err := next(s, i)
+			if err == nil || Answered(err) {
+				return err
+			}
+
+			key := "errors.command"
+			if kind := tts.KindOf(err); kind != tts.ErrorKindUnknown {
+				key = "errors." + string(kind)
+			}
+			if respondErr := respondEphemeral(s, i, localizer.T(i.GuildID, key)); respondErr != nil {
+				logger.Printf("Failed to send error response: %v", respondErr)
+			}
+			return answeredError{err}
+		}
+	}
+}
+
+// RecoverPanics turns a panicking handler into a failed command, so it is answered like
+// one instead of taking the bot down. A nil metrics does not count the panic.
+func RecoverPanics(metrics *tts.Metrics) CommandMiddleware {
+	return func(next CommandHandlerFunc) CommandHandlerFunc {
+		return func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+			return tts.CatchPanic(metrics, "interaction handler", i.GuildID, func() error {
+				return next(s, i)
+			})
+		}
+	}
+}
+
+// CheckPermissions refuses a text command in a guild when the member lacks the default
+// member permissions of its definition. Discord checks them for slash commands, along
+// with the guild's overrides, but never sees text commands. Members holding one of the
+// guild's required roles may run the role-gated commands, as syncing lets them in Discord.
+// Members whose permissions cannot be worked out are left to the handler's own checks.
+func CheckPermissions(lookup func(name string) *discordgo.ApplicationCommand, roles tts.PermissionService, localizer *tts.Localizer) CommandMiddleware {
+	return func(next CommandHandlerFunc) CommandHandlerFunc {
+		return func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+			if !textcmd.IsTextCommand(i.Interaction) || i.GuildID == "" || i.Member == nil || i.Member.User == nil {
+				return next(s, i)
+			}
+			name := i.ApplicationCommandData().Name
+			definition := lookup(name)
+			if definition == nil || definition.DefaultMemberPermissions == nil {
+				return next(s, i)
+			}
+
+			if s == nil || s.State == nil {
+				return next(s, i)
+			}
+			permissions, err := s.State.UserChannelPermissions(i.Member.User.ID, i.ChannelID)
+			if err != nil || hasPermissions(permissions, *definition.DefaultMemberPermissions) {
+				return next(s, i)
+			}
+			if tts.IsRoleGated(name) && hasRequiredRole(roles, i.GuildID, i.Member.Roles) {
+				return next(s, i)
+			}
+			return respondEphemeral(s, i, "❌ "+localizer.T(i.GuildID, "common.permission_denied", tts.ErrInvalidPermission))
+		}
+	}
+}
+
+// hasPermissions reports whether a member's permissions cover required. Administrators
+// may run every command, and a command requiring no permissions is for them alone.
+func hasPermissions(permissions, required int64) bool {
+	if permissions&discordgo.PermissionAdministrator != 0 {
+		return true
+	}
+	return required != 0 && permissions&required == required
+}
+
+// hasRequiredRole reports whether a member holds one of a guild's required roles
+func hasRequiredRole(roles tts.PermissionService, guildID string, memberRoles []string) bool {
+	if roles == nil {
+		return false
+	}
+	required, err := roles.GetRequiredRoles(guildID)
+	if err != nil {
+		return false
+	}
+	for _, roleID := range required {
+		if slices.Contains(memberRoles, roleID) {
+			return true
+		}
+	}
+	return false
+}
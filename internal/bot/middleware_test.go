@@ -0,0 +1,198 @@
+package bot
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"testing"
+
+	"darrot/internal/tts"
+
+	"mock-discord/mockdiscord"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newMiddlewareTestSession returns a session whose responses are posted to a mock Discord
+func newMiddlewareTestSession(t *testing.T) (*discordgo.Session, *mockdiscord.Fixture) {
+	t.Helper()
+
+	fixture, err := mockdiscord.NewFixture()
+	require.NoError(t, err)
+	t.Cleanup(fixture.Close)
+
+	session, err := discordgo.New("Bot test-bot-token")
+	require.NoError(t, err)
+	session.Client = fixture.HTTPClient()
+	return session, fixture
+}
+
+// newMiddlewareTestInteraction returns a slash command run by a member of the test guild
+func newMiddlewareTestInteraction(id, name string, permissions int64) *discordgo.InteractionCreate {
+	return &discordgo.InteractionCreate{
+		Interaction: &discordgo.Interaction{
+			ID:        id,
+			Token:     "token-" + id,
+			Type:      discordgo.InteractionApplicationCommand,
+			GuildID:   mockdiscord.TestGuildID,
+			ChannelID: mockdiscord.TestTextChannelID,
+			Member: &discordgo.Member{
+				User:        &discordgo.User{ID: "user1"},
+				Permissions: permissions,
+			},
+			Data: discordgo.ApplicationCommandInteractionData{Name: name},
+		},
+	}
+}
+
+func TestMiddleware_AnswersFailedCommands(t *testing.T) {
+	session, fixture := newMiddlewareTestSession(t)
+	metrics := tts.NewMetrics()
+	var logs bytes.Buffer
+	logger := log.New(&logs, "", 0)
+
+	router := NewCommandRouter(logger)
+	router.Use(LogCommands(logger))
+	router.Use(RecordCommandMetrics(metrics))
+	router.Use(AnswerErrors(nil, logger))
+	router.Use(RecoverPanics(metrics))
+	require.NoError(t, router.RegisterHandler(&MockCommandHandler{
+		name: "fail",
+		handleFunc: func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+			return errors.New("something odd")
+		},
+	}))
+	require.NoError(t, router.RegisterHandler(&MockCommandHandler{
+		name: "forbidden",
+		handleFunc: func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+			return tts.ErrInvalidPermission
+		},
+	}))
+	require.NoError(t, router.RegisterHandler(&MockCommandHandler{
+		name: "panic",
+		handleFunc: func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+			panic("handler bug")
+		},
+	}))
+	require.NoError(t, router.RegisterHandler(&MockCommandHandler{name: "ok"}))
+
+	err := router.RouteCommand(session, newMiddlewareTestInteraction("1", "fail", 0))
+	require.Error(t, err)
+	assert.True(t, Answered(err), "the handler's error was answered")
+	response, ok := fixture.InteractionResponse("1")
+	require.True(t, ok)
+	assert.Equal(t, "Sorry, something went wrong processing your command.", response.Content())
+	assert.EqualValues(t, discordgo.MessageFlagsEphemeral, response.Data["flags"])
+	assert.Contains(t, logs.String(), "Command /fail from user user1 in guild "+mockdiscord.TestGuildID+" failed after")
+
+	// Errors of a known kind get that kind's message
+	require.Error(t, router.RouteCommand(session, newMiddlewareTestInteraction("2", "forbidden", 0)))
+	response, ok = fixture.InteractionResponse("2")
+	require.True(t, ok)
+	assert.Contains(t, response.Content(), "I don't have the necessary permissions")
+
+	// A panicking handler fails the command instead of the bot
+	err = router.RouteCommand(session, newMiddlewareTestInteraction("3", "panic", 0))
+	require.Error(t, err)
+	assert.True(t, Answered(err))
+	_, ok = fixture.InteractionResponse("3")
+	assert.True(t, ok)
+	assert.Equal(t, 1.0, metrics.Value(tts.MetricPanicsRecovered, tts.Labels{"component": "interaction handler"}))
+
+	require.NoError(t, router.RouteCommand(session, newMiddlewareTestInteraction("4", "ok", 0)))
+	_, ok = fixture.InteractionResponse("4")
+	assert.False(t, ok, "commands that succeed answer for themselves")
+
+	assert.Equal(t, 1.0, metrics.Value(MetricCommands, tts.Labels{"command": "fail", "result": "unknown"}))
+	assert.Equal(t, 1.0, metrics.Value(MetricCommands, tts.Labels{"command": "forbidden", "result": "permission"}))
+	assert.Equal(t, 1.0, metrics.Value(MetricCommands, tts.Labels{"command": "panic", "result": "unknown"}))
+	assert.Equal(t, 1.0, metrics.Value(MetricCommands, tts.Labels{"command": "ok", "result": "ok"}))
+}
+
+// requiredRoles is a permission service that only knows the guild's required roles
+type requiredRoles struct {
+	tts.PermissionService
+	roleIDs []string
+}
+
+func (r requiredRoles) GetRequiredRoles(guildID string) ([]string, error) {
+	return r.roleIDs, nil
+}
+
+func TestMiddleware_CheckPermissions(t *testing.T) {
+	session, fixture := newMiddlewareTestSession(t)
+	logger := log.New(&bytes.Buffer{}, "", 0)
+
+	// The guild has a moderator role that may manage channels and a required DJ role
+	require.NoError(t, session.State.GuildAdd(&discordgo.Guild{
+		ID:      mockdiscord.TestGuildID,
+		OwnerID: "owner1",
+		Roles: []*discordgo.Role{
+			{ID: mockdiscord.TestGuildID, Permissions: discordgo.PermissionSendMessages},
+			{ID: "moderator", Permissions: discordgo.PermissionManageChannels},
+			{ID: "dj"},
+			{ID: "admin", Permissions: discordgo.PermissionAdministrator},
+		},
+		Channels: []*discordgo.Channel{{ID: mockdiscord.TestTextChannelID, GuildID: mockdiscord.TestGuildID, Type: discordgo.ChannelTypeGuildText}},
+	}))
+	for userID, roles := range map[string][]string{"member": nil, "moderator": {"moderator"}, "dj": {"dj"}, "admin": {"admin"}} {
+		require.NoError(t, session.State.MemberAdd(&discordgo.Member{GuildID: mockdiscord.TestGuildID, User: &discordgo.User{ID: userID}, Roles: roles}))
+	}
+
+	var ran []string
+	handler := func(name string, permissions *int64) *MockCommandHandler {
+		return &MockCommandHandler{
+			name:        name,
+			permissions: permissions,
+			handleFunc: func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+				ran = append(ran, name)
+				return nil
+			},
+		}
+	}
+	manageChannels := int64(discordgo.PermissionManageChannels)
+	adminOnly := int64(0)
+
+	router := NewCommandRouter(logger)
+	router.Use(CheckPermissions(router.Lookup, requiredRoles{roleIDs: []string{"dj"}}, nil))
+	require.NoError(t, router.RegisterHandler(handler("open", nil)))
+	require.NoError(t, router.RegisterHandler(handler("darrot-stats", &manageChannels)))
+	require.NoError(t, router.RegisterHandler(handler("manage", &manageChannels)))
+	require.NoError(t, router.RegisterHandler(handler("owner", &adminOnly)))
+
+	run := func(userID, name string) {
+		t.Helper()
+		interaction := newMiddlewareTestInteraction(userID+"-"+name, name, 0)
+		interaction.Token = "textcmd:" + interaction.ID
+		interaction.Member, _ = session.State.Member(mockdiscord.TestGuildID, userID)
+		require.NoError(t, router.RouteCommand(session, interaction))
+	}
+
+	run("member", "open")
+	run("member", "manage")
+	run("moderator", "manage")
+	run("moderator", "owner")
+	run("admin", "owner")
+	run("dj", "darrot-stats")
+	run("dj", "manage")
+	assert.Equal(t, []string{"open", "manage", "owner", "darrot-stats"}, ran,
+		"required roles only open the role-gated commands")
+
+	// Slash commands were already checked by Discord, with the guild's overrides
+	ran = nil
+	slash := newMiddlewareTestInteraction("slash", "manage", discordgo.PermissionSendMessages)
+	require.NoError(t, router.RouteCommand(session, slash))
+	assert.Equal(t, []string{"manage"}, ran)
+	_, ok := fixture.InteractionResponse("slash")
+	assert.False(t, ok)
+
+	// Members missing from the cache are left to the handler's own checks
+	ran = nil
+	unknown := newMiddlewareTestInteraction("unknown", "owner", 0)
+	unknown.Token = "textcmd:unknown"
+	unknown.Member.User.ID = "stranger"
+	require.NoError(t, router.RouteCommand(session, unknown))
+	assert.Equal(t, []string{"owner"}, ran)
+}
@@ -0,0 +1,154 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"darrot/internal/config"
+	"darrot/internal/tts"
+)
+
+// applicationsDir is where each additional application keeps its channel pairings and
+// voice handoff, in a directory named after it
+const applicationsDir = "applications"
+
+// BotPool runs the main bot and one bot per additional Discord application from a single
+// process. Communities that need more than one voice connection per guild invite several
+// applications, such as darrot-blue and darrot-red. Each bot has its own Discord session,
+// voice connections, channel pairings and queues, while the TTS backend, the audio cache
+// and guild settings are shared.
+type BotPool struct {
+	bots      []*Bot // The main bot comes first
+	logger    *log.Logger
+	isRunning bool
+}
+
+// NewBotPool creates the main bot and a bot for every application in discord_applications
+func NewBotPool(cfg *config.Config) (*BotPool, error) {
+	return newBotPool(cfg, nil, tts.DefaultDataDir)
+}
+
+// newBotPool creates a bot pool whose bots synthesize speech with ttsManager, or with
+// Google Cloud TTS when it is nil, keeping application data under dataDir
+func newBotPool(cfg *config.Config, ttsManager tts.TTSManager, dataDir string) (*BotPool, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("configuration cannot be nil")
+	}
+
+	main, err := newBot(cfg, ttsManager)
+	if err != nil {
+		return nil, err
+	}
+
+	pool := &BotPool{
+		bots:   []*Bot{main},
+		logger: log.New(os.Stdout, "[POOL] ", log.LstdFlags|log.Lshortfile),
+	}
+
+	shared := main.GetTTSSystem().GetServices()
+	for _, application := range cfg.Applications() {
+		storage, err := tts.NewStorageService(filepath.Join(dataDir, applicationsDir, application.Name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize storage for application %s: %w", application.Name, err)
+		}
+		if err := storage.EnableEncryption(shared.Storage.Cipher()); err != nil {
+			return nil, fmt.Errorf("failed to encrypt storage of application %s: %w", application.Name, err)
+		}
+
+		// Only the main bot serves the HTTP API and the metrics, which cover every bot, since
+		// the bots cannot share their addresses, and reads the input, so each line is spoken once
+		applicationConfig := *cfg
+		applicationConfig.DiscordToken = application.Token
+		applicationConfig.TTS.APIAddress = ""
+		applicationConfig.TTS.MetricsAddress = ""
+		applicationConfig.TTS.InputPath = ""
+
+		bot, err := newApplicationBot(&applicationConfig, application.Name, shared.ForApplication(storage))
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize application %s: %w", application.Name, err)
+		}
+		pool.bots = append(pool.bots, bot)
+	}
+
+	return pool, nil
+}
+
+// Start starts every bot in order. If one fails to start, the bots already started are
+// stopped again.
+func (p *BotPool) Start() error {
+	if p.isRunning {
+		return fmt.Errorf("bot pool is already running")
+	}
+
+	for i, bot := range p.bots {
+		if err := bot.Start(); err != nil {
+			if stopErr := stopBots(p.bots[:i]); stopErr != nil {
+				p.logger.Printf("Error stopping bots after failed start: %v", stopErr)
+			}
+			return fmt.Errorf("failed to start %s: %w", describeBot(bot), err)
+		}
+	}
+
+	p.isRunning = true
+	if len(p.bots) > 1 {
+		p.logger.Printf("Started %d bots", len(p.bots))
+	}
+	return nil
+}
+
+// Stop stops every bot at the same time, so their graceful shutdowns overlap
+func (p *BotPool) Stop() error {
+	if !p.isRunning {
+		return fmt.Errorf("bot pool is not running")
+	}
+
+	err := stopBots(p.bots)
+	p.isRunning = false
+	return err
+}
+
+// stopBots stops the given bots concurrently and joins their errors
+func stopBots(bots []*Bot) error {
+	errs := make([]error, len(bots))
+
+	var wg sync.WaitGroup
+	for i, bot := range bots {
+		wg.Add(1)
+		go func(i int, bot *Bot) {
+			defer wg.Done()
+			if err := bot.Stop(); err != nil {
+				errs[i] = fmt.Errorf("failed to stop %s: %w", describeBot(bot), err)
+			}
+		}(i, bot)
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// describeBot names a bot in errors
+func describeBot(bot *Bot) string {
+	if bot.Name() == "" {
+		return "main bot"
+	}
+	return "application " + bot.Name()
+}
+
+// Bots returns every bot in the pool, the main bot first
+func (p *BotPool) Bots() []*Bot {
+	return p.bots
+}
+
+// IsRunning returns whether the bots are running
+func (p *BotPool) IsRunning() bool {
+	return p.isRunning
+}
+
+// WaitForShutdown blocks until a shutdown signal is received
+func (p *BotPool) WaitForShutdown() {
+	p.bots[0].WaitForShutdown()
+}
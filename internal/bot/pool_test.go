@@ -0,0 +1,101 @@
+package bot
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"darrot/internal/config"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestBotPool creates a bot pool with the given additional applications. Bot data is
+// kept in a temporary working directory.
+func newTestBotPool(t *testing.T, applications string) (*BotPool, string) {
+	t.Helper()
+
+	workDir, err := os.Getwd()
+	require.NoError(t, err)
+	dataDir := t.TempDir()
+	require.NoError(t, os.Chdir(dataDir))
+	t.Cleanup(func() { os.Chdir(workDir) })
+
+	cfg := &config.Config{
+		DiscordToken:        "main-token",
+		DiscordApplications: applications,
+		LogLevel:            "INFO",
+		TTS:                 config.TTSConfig{APIAddress: "127.0.0.1:0", MetricsAddress: "127.0.0.1:0", InputPath: "-", InputGuildID: "123"},
+	}
+	pool, err := newBotPool(cfg, &fakeSpeechManager{}, dataDir)
+	require.NoError(t, err)
+	return pool, dataDir
+}
+
+func TestBotPool_MainBotOnly(t *testing.T) {
+	pool, _ := newTestBotPool(t, "")
+
+	require.Len(t, pool.Bots(), 1)
+	assert.Equal(t, "", pool.Bots()[0].Name())
+	assert.False(t, pool.IsRunning())
+}
+
+func TestBotPool_SharesBackendAndIsolatesSessions(t *testing.T) {
+	pool, dataDir := newTestBotPool(t, "blue=blue-token,red=red-token")
+
+	bots := pool.Bots()
+	require.Len(t, bots, 3)
+	assert.Equal(t, []string{"", "blue", "red"}, []string{bots[0].Name(), bots[1].Name(), bots[2].Name()})
+
+	main := bots[0].GetTTSSystem().GetServices()
+	for _, bot := range bots[1:] {
+		services := bot.GetTTSSystem().GetServices()
+
+		// Shared across applications
+		assert.Same(t, main.TTS, services.TTS)
+		assert.Same(t, main.AudioCache, services.AudioCache)
+		assert.Same(t, main.Storage, services.Storage)
+		assert.Same(t, main.Config, services.Config)
+
+		// Separate for each application
+		assert.NotSame(t, main.SessionStorage, services.SessionStorage)
+		assert.NotSame(t, main.Voice, services.Voice)
+		assert.NotSame(t, main.Channels, services.Channels)
+		assert.NotSame(t, main.Queue, services.Queue)
+		assert.NotSame(t, main.Processor, services.Processor)
+
+		assert.DirExists(t, filepath.Join(dataDir, applicationsDir, bot.Name()))
+		assert.Equal(t, "", bot.config.TTS.APIAddress, "only the main bot serves the HTTP API")
+		assert.Equal(t, "", bot.config.TTS.MetricsAddress, "only the main bot serves the metrics")
+		assert.Equal(t, "", bot.config.TTS.InputPath, "only the main bot reads the input")
+		assert.Equal(t, bot.Name()+"-token", bot.config.DiscordToken)
+	}
+	assert.Equal(t, "127.0.0.1:0", bots[0].config.TTS.APIAddress)
+	assert.Equal(t, "127.0.0.1:0", bots[0].config.TTS.MetricsAddress)
+	assert.Equal(t, "-", bots[0].config.TTS.InputPath)
+}
+
+func TestBotPool_OnlyMainBotAnswersTextCommands(t *testing.T) {
+	pool, _ := newTestBotPool(t, "red=red-token")
+	red := pool.Bots()[1]
+
+	// The red bot ignores the message before anything is sent, so a nil session is never used
+	red.handleTextCommand(nil, &discordgo.MessageCreate{Message: &discordgo.Message{
+		GuildID: "guild1",
+		Content: "!darrot join",
+		Author:  &discordgo.User{ID: "user1"},
+	}})
+}
+
+func TestBotPool_StopNotRunning(t *testing.T) {
+	pool, _ := newTestBotPool(t, "red=red-token")
+
+	assert.Error(t, pool.Stop())
+}
+
+func TestNewBotPool_NilConfig(t *testing.T) {
+	_, err := NewBotPool(nil)
+	assert.Error(t, err)
+}
@@ -4,6 +4,8 @@ import (
 	"log"
 	"os"
 
+	"darrot/internal/commands/textcmd"
+
 	"github.com/bwmarrin/discordgo"
 )
 
@@ -48,7 +50,7 @@ func (h *TestCommandHandler) Handle(s *discordgo.Session, i *discordgo.Interacti
 		},
 	}
 
-	err := s.InteractionRespond(i.Interaction, response)
+	err := textcmd.Respond(s, i.Interaction, response)
 	if err != nil {
 		h.logger.Printf("Error responding to /test command: %v", err)
 		return err
@@ -0,0 +1,190 @@
+// Package options reads and validates slash command options.
+//
+// Options are looked up by name rather than position, so optional options can be
+// omitted or reordered without breaking handlers. Validation failures are returned as
+// *Error values carrying an i18n catalog key, which handlers translate into the
+// guild's language and send as an ephemeral error response.
+package options
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"darrot/internal/i18n"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Catalog keys for validation errors
+const (
+	KeyMissing       = "options.missing"
+	KeyInvalidNumber = "options.invalid_number"
+	KeyOutOfRange    = "options.out_of_range"
+	KeyTooSmall      = "options.too_small"
+)
+
+// Error describes an option that is missing or holds an invalid value
+type Error struct {
+	Key  string
+	Args []any
+}
+
+// Error returns the message in the default language
+func (e *Error) Error() string {
+	return i18n.Default().T(i18n.DefaultLocale, e.Key, e.Args...)
+}
+
+// Missing returns the error for a required option that was not provided
+func Missing(name string) error {
+	return &Error{Key: KeyMissing, Args: []any{name}}
+}
+
+// Set holds the options of a command or subcommand keyed by name
+type Set map[string]*discordgo.ApplicationCommandInteractionDataOption
+
+// New creates a set from interaction options
+func New(options []*discordgo.ApplicationCommandInteractionDataOption) Set {
+	set := make(Set, len(options))
+	for _, option := range options {
+		set[option.Name] = option
+	}
+	return set
+}
+
+// FromInteraction returns the top-level options of a slash command interaction
+func FromInteraction(i *discordgo.InteractionCreate) Set {
+	return New(i.ApplicationCommandData().Options)
+}
+
+// Subcommand returns the name and options of the invoked subcommand. ok is false when
+// no subcommand was given.
+func (s Set) Subcommand() (name string, options Set, ok bool) {
+	for _, option := range s {
+		if option.Type == discordgo.ApplicationCommandOptionSubCommand || option.Type == discordgo.ApplicationCommandOptionSubCommandGroup {
+			return option.Name, New(option.Options), true
+		}
+	}
+	return "", nil, false
+}
+
+// Has reports whether an option was provided
+func (s Set) Has(name string) bool {
+	_, ok := s[name]
+	return ok
+}
+
+// String returns a string option
+func (s Set) String(name string) (string, bool) {
+	option, ok := s[name]
+	if !ok {
+		return "", false
+	}
+	value, ok := option.Value.(string)
+	return value, ok
+}
+
+// RequiredString returns a string option that must be provided
+func (s Set) RequiredString(name string) (string, error) {
+	value, ok := s.String(name)
+	if !ok {
+		return "", Missing(name)
+	}
+	return value, nil
+}
+
+// Int returns an integer option
+func (s Set) Int(name string) (int64, bool) {
+	option, ok := s[name]
+	if !ok {
+		return 0, false
+	}
+	// Discord sends every number as a JSON float
+	value, ok := option.Value.(float64)
+	return int64(value), ok
+}
+
+// Bool returns a boolean option
+func (s Set) Bool(name string) (bool, bool) {
+	option, ok := s[name]
+	if !ok {
+		return false, false
+	}
+	value, ok := option.Value.(bool)
+	return value, ok
+}
+
+// IntInRange returns an integer option if it was provided, checking that it is within
+// [minValue, maxValue]
+func (s Set) IntInRange(name string, minValue, maxValue int64) (int64, bool, error) {
+	value, ok := s.Int(name)
+	if !ok {
+		return 0, false, nil
+	}
+	if value < minValue || value > maxValue {
+		return 0, true, &Error{Key: KeyOutOfRange, Args: []any{name, minValue, maxValue}}
+	}
+	return value, true, nil
+}
+
+// IntAtLeast returns an integer option if it was provided, checking that it is at
+// least minValue
+func (s Set) IntAtLeast(name string, minValue int64) (int64, bool, error) {
+	value, ok := s.Int(name)
+	if !ok {
+		return 0, false, nil
+	}
+	if value < minValue {
+		return 0, true, &Error{Key: KeyTooSmall, Args: []any{name, minValue}}
+	}
+	return value, true, nil
+}
+
+// ChannelID returns the ID of a channel option
+func (s Set) ChannelID(name string) (string, bool) {
+	return s.id(name, discordgo.ApplicationCommandOptionChannel)
+}
+
+// RoleID returns the ID of a role option
+func (s Set) RoleID(name string) (string, bool) {
+	return s.id(name, discordgo.ApplicationCommandOptionRole)
+}
+
+// UserID returns the ID of a user option
+func (s Set) UserID(name string) (string, bool) {
+	return s.id(name, discordgo.ApplicationCommandOptionUser)
+}
+
+// id returns the snowflake ID held by a channel, role or user option
+func (s Set) id(name string, optionType discordgo.ApplicationCommandOptionType) (string, bool) {
+	option, ok := s[name]
+	if !ok || option.Type != optionType {
+		return "", false
+	}
+	value, ok := option.Value.(string)
+	return value, ok && value != ""
+}
+
+// ParseFloat32 parses a number typed into a string option, checking that it is within
+// [minValue, maxValue]. name identifies the value in error messages.
+func ParseFloat32(name, value string, minValue, maxValue float32) (float32, error) {
+	parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 32)
+	if err != nil || math.IsNaN(parsed) || math.IsInf(parsed, 0) {
+		return 0, &Error{Key: KeyInvalidNumber, Args: []any{name, value}}
+	}
+
+	result := float32(parsed)
+	if result < minValue || result > maxValue {
+		return 0, &Error{Key: KeyOutOfRange, Args: []any{name, formatFloat(minValue), formatFloat(maxValue)}}
+	}
+	return result, nil
+}
+
+// formatFloat formats a range bound without trailing zeros beyond one decimal
+func formatFloat(value float32) string {
+	formatted := strconv.FormatFloat(float64(value), 'f', -1, 32)
+	if !strings.Contains(formatted, ".") {
+		formatted += ".0"
+	}
+	return formatted
+}
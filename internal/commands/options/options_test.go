@@ -0,0 +1,152 @@
+package options
+
+import (
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testSet() Set {
+	return New([]*discordgo.ApplicationCommandInteractionDataOption{
+		{Name: "setting", Type: discordgo.ApplicationCommandOptionString, Value: "speed"},
+		{Name: "size", Type: discordgo.ApplicationCommandOptionInteger, Value: float64(25)},
+		{Name: "channel", Type: discordgo.ApplicationCommandOptionChannel, Value: "123"},
+		{Name: "role", Type: discordgo.ApplicationCommandOptionRole, Value: "456"},
+		{Name: "user", Type: discordgo.ApplicationCommandOptionUser, Value: "789"},
+		{Name: "whisper", Type: discordgo.ApplicationCommandOptionBoolean, Value: false},
+	})
+}
+
+func TestSet_Getters(t *testing.T) {
+	set := testSet()
+
+	assert.True(t, set.Has("setting"))
+	assert.False(t, set.Has("value"))
+
+	setting, ok := set.String("setting")
+	assert.True(t, ok)
+	assert.Equal(t, "speed", setting)
+
+	_, ok = set.String("size")
+	assert.False(t, ok, "a number is not a string")
+
+	size, ok := set.Int("size")
+	assert.True(t, ok)
+	assert.Equal(t, int64(25), size)
+
+	channelID, ok := set.ChannelID("channel")
+	assert.True(t, ok)
+	assert.Equal(t, "123", channelID)
+
+	_, ok = set.ChannelID("role")
+	assert.False(t, ok, "a role is not a channel")
+
+	roleID, ok := set.RoleID("role")
+	assert.True(t, ok)
+	assert.Equal(t, "456", roleID)
+
+	userID, ok := set.UserID("user")
+	assert.True(t, ok)
+	assert.Equal(t, "789", userID)
+
+	_, ok = set.UserID("role")
+	assert.False(t, ok, "a role is not a user")
+
+	whisper, ok := set.Bool("whisper")
+	assert.True(t, ok, "false is a provided value")
+	assert.False(t, whisper)
+
+	_, ok = set.Bool("setting")
+	assert.False(t, ok, "a string is not a boolean")
+}
+
+func TestSet_RequiredString(t *testing.T) {
+	set := testSet()
+
+	value, err := set.RequiredString("setting")
+	require.NoError(t, err)
+	assert.Equal(t, "speed", value)
+
+	_, err = set.RequiredString("action")
+	var optionErr *Error
+	require.ErrorAs(t, err, &optionErr)
+	assert.Equal(t, KeyMissing, optionErr.Key)
+	assert.Equal(t, "The `action` option is required.", err.Error())
+}
+
+func TestSet_IntValidation(t *testing.T) {
+	set := testSet()
+
+	size, ok, err := set.IntInRange("size", 1, 50)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, int64(25), size)
+
+	_, ok, err = set.IntInRange("size", 1, 10)
+	assert.True(t, ok)
+	assert.EqualError(t, err, "`size` must be between 1 and 10.")
+
+	_, ok, err = set.IntAtLeast("size", 30)
+	assert.True(t, ok)
+	assert.EqualError(t, err, "`size` must be at least 30.")
+
+	// Omitted optional options are not an error
+	_, ok, err = set.IntInRange("value", 1, 50)
+	assert.False(t, ok)
+	assert.NoError(t, err)
+}
+
+func TestSet_Subcommand(t *testing.T) {
+	set := New([]*discordgo.ApplicationCommandInteractionDataOption{
+		{
+			Name: "voice",
+			Type: discordgo.ApplicationCommandOptionSubCommand,
+			Options: []*discordgo.ApplicationCommandInteractionDataOption{
+				{Name: "setting", Type: discordgo.ApplicationCommandOptionString, Value: "volume"},
+			},
+		},
+	})
+
+	name, options, ok := set.Subcommand()
+	require.True(t, ok)
+	assert.Equal(t, "voice", name)
+	setting, _ := options.String("setting")
+	assert.Equal(t, "volume", setting)
+
+	_, _, ok = testSet().Subcommand()
+	assert.False(t, ok)
+}
+
+func TestParseFloat32(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected float32
+		errorMsg string
+	}{
+		{name: "valid float", input: "1.5", expected: 1.5},
+		{name: "valid integer", input: "2", expected: 2.0},
+		{name: "surrounding whitespace", input: " 0.75 ", expected: 0.75},
+		{name: "invalid string", input: "not_a_number", errorMsg: "`speed` must be a number, got `not_a_number`."},
+		{name: "trailing garbage", input: "1.5x", errorMsg: "`speed` must be a number, got `1.5x`."},
+		{name: "empty string", input: "", errorMsg: "`speed` must be a number, got ``."},
+		{name: "not a number", input: "NaN", errorMsg: "`speed` must be a number, got `NaN`."},
+		{name: "too small", input: "0.1", errorMsg: "`speed` must be between 0.25 and 4.0."},
+		{name: "too large", input: "4.5", errorMsg: "`speed` must be between 0.25 and 4.0."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseFloat32("speed", tt.input, 0.25, 4.0)
+
+			if tt.errorMsg != "" {
+				assert.EqualError(t, err, tt.errorMsg)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, result)
+			}
+		})
+	}
+}
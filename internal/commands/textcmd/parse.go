@@ -0,0 +1,358 @@
+// Package textcmd runs slash command handlers from prefix commands typed in chat.
+//
+// A message such as `!darrot config voice voice:en-US-Wavenet-D` is turned into the
+// interaction the matching slash command would have produced, so one handler serves
+// both. Subcommands are given by name, options as name:value pairs or in the order the
+// command defines them, and values with spaces in double quotes. Attachment options take
+// the message's attachments in order. Handlers answer through Respond and EditResponse,
+// which post the reply in the channel when the interaction came from a message.
+package textcmd
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"darrot/internal/commands/options"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Catalog keys for parse errors
+const (
+	KeyUnknownCommand    = "textcmd.unknown_command"
+	KeyUnknownSubcommand = "textcmd.unknown_subcommand"
+	KeyUnknownOption     = "textcmd.unknown_option"
+	KeyInvalidValue      = "textcmd.invalid_value"
+	KeyUnclosedQuote     = "textcmd.unclosed_quote"
+)
+
+// CommandNamePrefix is prepended to the command word when looking up a command, so
+// `!darrot join` runs /darrot-join
+const CommandNamePrefix = "darrot-"
+
+// tokenPrefix marks the token of interactions built from messages
+const tokenPrefix = "textcmd:"
+
+// Lookup returns the definition of a registered slash command, or nil if there is none
+type Lookup func(name string) *discordgo.ApplicationCommand
+
+var (
+	channelMention = regexp.MustCompile(`^<#(\d+)>$`)
+	roleMention    = regexp.MustCompile(`^<@&(\d+)>$`)
+	userMention    = regexp.MustCompile(`^<@!?(\d+)>$`)
+	snowflake      = regexp.MustCompile(`^\d+$`)
+)
+
+// Parse turns a message starting with prefix into a slash command interaction. It returns
+// nil and no error when the message is not a command. Errors are *options.Error values
+// that can be translated for the user.
+func Parse(m *discordgo.Message, prefix string, lookup Lookup) (*discordgo.InteractionCreate, error) {
+	if m == nil || m.Author == nil || prefix == "" {
+		return nil, nil
+	}
+
+	if !HasPrefix(m.Content, prefix) {
+		return nil, nil
+	}
+
+	tokens, err := tokenize(strings.TrimPrefix(strings.TrimSpace(m.Content), prefix))
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, &options.Error{Key: KeyUnknownCommand, Args: []any{""}}
+	}
+
+	word := strings.ToLower(tokens[0])
+	definition := lookup(CommandNamePrefix + word)
+	if definition == nil {
+		definition = lookup(word)
+	}
+	if definition == nil {
+		return nil, &options.Error{Key: KeyUnknownCommand, Args: []any{tokens[0]}}
+	}
+
+	parser := &parser{message: m, resolved: &discordgo.ApplicationCommandInteractionDataResolved{}}
+	data, err := parser.parseOptions(definition.Options, tokens[1:])
+	if err != nil {
+		return nil, err
+	}
+
+	member := &discordgo.Member{GuildID: m.GuildID}
+	if m.Member != nil {
+		copied := *m.Member
+		member = &copied
+	}
+	member.User = m.Author
+
+	return &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+		ID:        m.ID,
+		Type:      discordgo.InteractionApplicationCommand,
+		GuildID:   m.GuildID,
+		ChannelID: m.ChannelID,
+		Member:    member,
+		Token:     tokenPrefix + m.ID,
+		Data: discordgo.ApplicationCommandInteractionData{
+			ID:          definition.ID,
+			Name:        definition.Name,
+			CommandType: discordgo.ChatApplicationCommand,
+			Options:     data,
+			Resolved:    parser.resolved,
+		},
+	}}, nil
+}
+
+// HasPrefix reports whether content is a text command for prefix: the prefix on its own
+// or followed by whitespace
+func HasPrefix(content, prefix string) bool {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(content), prefix)
+	if !ok || prefix == "" {
+		return false
+	}
+	return rest == "" || unicode.IsSpace([]rune(rest)[0])
+}
+
+// IsTextCommand reports whether an interaction was built from a message by Parse
+func IsTextCommand(i *discordgo.Interaction) bool {
+	return i != nil && strings.HasPrefix(i.Token, tokenPrefix)
+}
+
+// messageID returns the ID of the message a text command interaction was built from
+func messageID(i *discordgo.Interaction) string {
+	return strings.TrimPrefix(i.Token, tokenPrefix)
+}
+
+// parser converts tokens into interaction options for one message
+type parser struct {
+	message     *discordgo.Message
+	resolved    *discordgo.ApplicationCommandInteractionDataResolved
+	attachments int // Message attachments already given to options
+}
+
+// parseOptions converts tokens into the options of a command or subcommand. When the
+// options are subcommands or groups, the first token names the one to run.
+func (p *parser) parseOptions(defined []*discordgo.ApplicationCommandOption, tokens []string) ([]*discordgo.ApplicationCommandInteractionDataOption, error) {
+	if hasSubcommands(defined) {
+		if len(tokens) == 0 {
+			return nil, &options.Error{Key: KeyUnknownSubcommand, Args: []any{"", subcommandNames(defined)}}
+		}
+		name := strings.ToLower(tokens[0])
+		for _, option := range defined {
+			if option.Name != name {
+				continue
+			}
+			nested, err := p.parseOptions(option.Options, tokens[1:])
+			if err != nil {
+				return nil, err
+			}
+			return []*discordgo.ApplicationCommandInteractionDataOption{{Name: option.Name, Type: option.Type, Options: nested}}, nil
+		}
+		return nil, &options.Error{Key: KeyUnknownSubcommand, Args: []any{tokens[0], subcommandNames(defined)}}
+	}
+
+	values := make(map[string]*discordgo.ApplicationCommandInteractionDataOption)
+	var positional []string
+	for _, token := range tokens {
+		name, value, named := strings.Cut(token, ":")
+		option := findOption(defined, strings.ToLower(name))
+		if !named || option == nil {
+			positional = append(positional, token)
+			continue
+		}
+		if err := p.setOption(values, option, value); err != nil {
+			return nil, err
+		}
+	}
+
+	// Unnamed values fill the remaining options in definition order
+	for _, option := range defined {
+		if _, set := values[option.Name]; set || option.Type == discordgo.ApplicationCommandOptionAttachment {
+			continue
+		}
+		if len(positional) == 0 {
+			break
+		}
+		if err := p.setOption(values, option, positional[0]); err != nil {
+			return nil, err
+		}
+		positional = positional[1:]
+	}
+	if len(positional) > 0 {
+		return nil, &options.Error{Key: KeyUnknownOption, Args: []any{positional[0]}}
+	}
+
+	// Attachment options take the message's attachments in order
+	for _, option := range defined {
+		if option.Type == discordgo.ApplicationCommandOptionAttachment && p.attachments < len(p.message.Attachments) {
+			attachment := p.message.Attachments[p.attachments]
+			p.attachments++
+			if p.resolved.Attachments == nil {
+				p.resolved.Attachments = make(map[string]*discordgo.MessageAttachment)
+			}
+			p.resolved.Attachments[attachment.ID] = attachment
+			values[option.Name] = &discordgo.ApplicationCommandInteractionDataOption{Name: option.Name, Type: option.Type, Value: attachment.ID}
+		}
+	}
+
+	result := make([]*discordgo.ApplicationCommandInteractionDataOption, 0, len(values))
+	for _, option := range defined {
+		if value, ok := values[option.Name]; ok {
+			result = append(result, value)
+		} else if option.Required {
+			return nil, options.Missing(option.Name)
+		}
+	}
+	return result, nil
+}
+
+// setOption converts a typed value for an option and stores it in values
+func (p *parser) setOption(values map[string]*discordgo.ApplicationCommandInteractionDataOption, option *discordgo.ApplicationCommandOption, raw string) error {
+	value, err := p.convert(option, raw)
+	if err != nil {
+		return err
+	}
+	values[option.Name] = &discordgo.ApplicationCommandInteractionDataOption{Name: option.Name, Type: option.Type, Value: value}
+	return nil
+}
+
+// convert parses a typed value into the form Discord sends for the option's type
+func (p *parser) convert(option *discordgo.ApplicationCommandOption, raw string) (any, error) {
+	invalid := &options.Error{Key: KeyInvalidValue, Args: []any{option.Name, raw}}
+
+	if len(option.Choices) > 0 {
+		for _, choice := range option.Choices {
+			if strings.EqualFold(fmt.Sprint(choice.Value), raw) || strings.EqualFold(choice.Name, raw) {
+				// Numbers arrive as JSON floats
+				switch number := choice.Value.(type) {
+				case int:
+					return float64(number), nil
+				case int64:
+					return float64(number), nil
+				}
+				return choice.Value, nil
+			}
+		}
+		return nil, invalid
+	}
+
+	switch option.Type {
+	case discordgo.ApplicationCommandOptionString:
+		return raw, nil
+	case discordgo.ApplicationCommandOptionInteger, discordgo.ApplicationCommandOptionNumber:
+		number, err := strconv.ParseFloat(raw, 64)
+		if err != nil || (option.Type == discordgo.ApplicationCommandOptionInteger && number != float64(int64(number))) {
+			return nil, invalid
+		}
+		if (option.MinValue != nil && number < *option.MinValue) || (option.MaxValue != 0 && number > option.MaxValue) {
+			return nil, invalid
+		}
+		return number, nil
+	case discordgo.ApplicationCommandOptionBoolean:
+		switch strings.ToLower(raw) {
+		case "true", "yes", "on", "1":
+			return true, nil
+		case "false", "no", "off", "0":
+			return false, nil
+		}
+		return nil, invalid
+	case discordgo.ApplicationCommandOptionChannel:
+		return mentionID(raw, channelMention, invalid)
+	case discordgo.ApplicationCommandOptionRole:
+		return mentionID(raw, roleMention, invalid)
+	case discordgo.ApplicationCommandOptionUser, discordgo.ApplicationCommandOptionMentionable:
+		id, err := mentionID(raw, userMention, invalid)
+		if err != nil && option.Type == discordgo.ApplicationCommandOptionMentionable {
+			return mentionID(raw, roleMention, invalid)
+		}
+		if err == nil {
+			p.resolveUser(id)
+		}
+		return id, err
+	}
+	return nil, invalid
+}
+
+// resolveUser records a mentioned user the way Discord resolves user options
+func (p *parser) resolveUser(id string) {
+	for _, user := range p.message.Mentions {
+		if user.ID == id {
+			if p.resolved.Users == nil {
+				p.resolved.Users = make(map[string]*discordgo.User)
+			}
+			p.resolved.Users[id] = user
+		}
+	}
+}
+
+// mentionID returns the ID in a mention or a bare snowflake
+func mentionID(raw string, mention *regexp.Regexp, invalid error) (string, error) {
+	if match := mention.FindStringSubmatch(raw); match != nil {
+		return match[1], nil
+	}
+	if snowflake.MatchString(raw) {
+		return raw, nil
+	}
+	return "", invalid
+}
+
+// hasSubcommands reports whether options are subcommands or subcommand groups
+func hasSubcommands(defined []*discordgo.ApplicationCommandOption) bool {
+	return slices.ContainsFunc(defined, func(option *discordgo.ApplicationCommandOption) bool {
+		return option.Type == discordgo.ApplicationCommandOptionSubCommand || option.Type == discordgo.ApplicationCommandOptionSubCommandGroup
+	})
+}
+
+// subcommandNames lists subcommand names for error messages
+func subcommandNames(defined []*discordgo.ApplicationCommandOption) string {
+	names := make([]string, 0, len(defined))
+	for _, option := range defined {
+		names = append(names, option.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+// findOption returns the option with the given name
+func findOption(defined []*discordgo.ApplicationCommandOption, name string) *discordgo.ApplicationCommandOption {
+	for _, option := range defined {
+		if option.Name == name {
+			return option
+		}
+	}
+	return nil
+}
+
+// tokenize splits command text on whitespace, keeping double-quoted text together
+func tokenize(text string) ([]string, error) {
+	var tokens []string
+	var current strings.Builder
+	inToken, quoted := false, false
+
+	for _, r := range text {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			inToken = true
+		case unicode.IsSpace(r) && !quoted:
+			if inToken {
+				tokens = append(tokens, current.String())
+				current.Reset()
+				inToken = false
+			}
+		default:
+			current.WriteRune(r)
+			inToken = true
+		}
+	}
+
+	if quoted {
+		return nil, &options.Error{Key: KeyUnclosedQuote}
+	}
+	if inToken {
+		tokens = append(tokens, current.String())
+	}
+	return tokens, nil
+}
@@ -0,0 +1,232 @@
+package textcmd
+
+import (
+	"testing"
+
+	"darrot/internal/commands/options"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testCommands are the definitions the parser looks commands up in
+var testCommands = map[string]*discordgo.ApplicationCommand{
+	"darrot-join": {
+		Name: "darrot-join",
+		Options: []*discordgo.ApplicationCommandOption{
+			{Type: discordgo.ApplicationCommandOptionChannel, Name: "voice-channel", Required: true},
+			{Type: discordgo.ApplicationCommandOptionChannel, Name: "text-channel"},
+			{Type: discordgo.ApplicationCommandOptionBoolean, Name: "whisper"},
+		},
+	},
+	"darrot-config": {
+		Name: "darrot-config",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type: discordgo.ApplicationCommandOptionSubCommand,
+				Name: "queue",
+				Options: []*discordgo.ApplicationCommandOption{
+					{Type: discordgo.ApplicationCommandOptionInteger, Name: "size", Required: true, MinValue: &[]float64{1}[0], MaxValue: 50},
+				},
+			},
+			{
+				Type: discordgo.ApplicationCommandOptionSubCommand,
+				Name: "roles",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type: discordgo.ApplicationCommandOptionString, Name: "action", Required: true,
+						Choices: []*discordgo.ApplicationCommandOptionChoice{{Name: "add", Value: "add"}, {Name: "list", Value: "list"}},
+					},
+					{Type: discordgo.ApplicationCommandOptionRole, Name: "role"},
+				},
+			},
+		},
+	},
+	"darrot-clip": {
+		Name: "darrot-clip",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type: discordgo.ApplicationCommandOptionSubCommand,
+				Name: "upload",
+				Options: []*discordgo.ApplicationCommandOption{
+					{Type: discordgo.ApplicationCommandOptionString, Name: "name", Required: true},
+					{Type: discordgo.ApplicationCommandOptionAttachment, Name: "file", Required: true},
+				},
+			},
+		},
+	},
+	"darrot-mute": {
+		Name: "darrot-mute",
+		Options: []*discordgo.ApplicationCommandOption{
+			{Type: discordgo.ApplicationCommandOptionUser, Name: "user", Required: true},
+		},
+	},
+	"test": {Name: "test"},
+}
+
+func lookupTestCommand(name string) *discordgo.ApplicationCommand {
+	return testCommands[name]
+}
+
+// testMessage returns a guild message from user-1 with the given content
+func testMessage(content string) *discordgo.Message {
+	return &discordgo.Message{
+		ID:        "msg-1",
+		ChannelID: "chan-1",
+		GuildID:   "guild-1",
+		Content:   content,
+		Author:    &discordgo.User{ID: "user-1", Username: "alice"},
+		Member:    &discordgo.Member{Nick: "Alice"},
+	}
+}
+
+func parseTest(t *testing.T, content string) *discordgo.InteractionCreate {
+	t.Helper()
+
+	interaction, err := Parse(testMessage(content), "!darrot", lookupTestCommand)
+	require.NoError(t, err)
+	require.NotNil(t, interaction)
+	return interaction
+}
+
+func TestParse_NotACommand(t *testing.T) {
+	for _, content := range []string{"hello", "!darrotjoin", "!other join", "darrot join"} {
+		interaction, err := Parse(testMessage(content), "!darrot", lookupTestCommand)
+		assert.NoError(t, err, content)
+		assert.Nil(t, interaction, content)
+	}
+
+	interaction, err := Parse(testMessage("!darrot join"), "", lookupTestCommand)
+	assert.NoError(t, err)
+	assert.Nil(t, interaction, "an empty prefix turns text commands off")
+}
+
+func TestHasPrefix(t *testing.T) {
+	assert.True(t, HasPrefix("!darrot join", "!darrot"))
+	assert.True(t, HasPrefix("  !darrot", "!darrot"))
+	assert.True(t, HasPrefix("!darrot\njoin", "!darrot"))
+	assert.False(t, HasPrefix("!darrots are loud", "!darrot"))
+	assert.False(t, HasPrefix("hello !darrot", "!darrot"))
+	assert.False(t, HasPrefix("!darrot join", ""))
+}
+
+func TestParse_BuildsInteraction(t *testing.T) {
+	i := parseTest(t, "!darrot join <#111> text-channel:<#222> whisper:yes")
+
+	assert.True(t, IsTextCommand(i.Interaction))
+	assert.Equal(t, discordgo.InteractionApplicationCommand, i.Type)
+	assert.Equal(t, "guild-1", i.GuildID)
+	assert.Equal(t, "chan-1", i.ChannelID)
+	assert.Equal(t, "msg-1", messageID(i.Interaction))
+	require.NotNil(t, i.Member)
+	assert.Equal(t, "user-1", i.Member.User.ID)
+	assert.Equal(t, "Alice", i.Member.Nick)
+
+	data := i.ApplicationCommandData()
+	assert.Equal(t, "darrot-join", data.Name)
+
+	opts := options.New(data.Options)
+	voiceChannel, ok := opts.ChannelID("voice-channel")
+	assert.True(t, ok)
+	assert.Equal(t, "111", voiceChannel)
+	textChannel, _ := opts.ChannelID("text-channel")
+	assert.Equal(t, "222", textChannel)
+	whisper, ok := opts.Bool("whisper")
+	assert.True(t, ok)
+	assert.True(t, whisper)
+}
+
+func TestParse_Subcommands(t *testing.T) {
+	i := parseTest(t, "!darrot CONFIG queue size:20")
+
+	name, opts, ok := options.FromInteraction(i).Subcommand()
+	require.True(t, ok)
+	assert.Equal(t, "queue", name)
+	size, ok := opts.Int("size")
+	assert.True(t, ok)
+	assert.Equal(t, int64(20), size)
+
+	i = parseTest(t, "!darrot config roles add <@&333>")
+	_, opts, _ = options.FromInteraction(i).Subcommand()
+	action, _ := opts.String("action")
+	assert.Equal(t, "add", action)
+	role, _ := opts.RoleID("role")
+	assert.Equal(t, "333", role)
+}
+
+func TestParse_QuotedValuesAndAttachments(t *testing.T) {
+	message := testMessage(`!darrot clip upload name:"air horn"`)
+	message.Attachments = []*discordgo.MessageAttachment{{ID: "att-1", URL: "https://cdn.example/horn.wav"}}
+
+	i, err := Parse(message, "!darrot", lookupTestCommand)
+	require.NoError(t, err)
+
+	data := i.ApplicationCommandData()
+	_, opts, _ := options.New(data.Options).Subcommand()
+	name, _ := opts.String("name")
+	assert.Equal(t, "air horn", name)
+	file, _ := opts.String("file")
+	assert.Equal(t, "att-1", file)
+	assert.Same(t, message.Attachments[0], data.Resolved.Attachments["att-1"])
+}
+
+func TestParse_ResolvesMentionedUsers(t *testing.T) {
+	message := testMessage("!darrot mute <@!444>")
+	message.Mentions = []*discordgo.User{{ID: "444", Username: "bob"}}
+
+	i, err := Parse(message, "!darrot", lookupTestCommand)
+	require.NoError(t, err)
+
+	data := i.ApplicationCommandData()
+	user, _ := options.New(data.Options).String("user")
+	assert.Equal(t, "444", user)
+	assert.Equal(t, "bob", data.Resolved.Users["444"].Username)
+}
+
+func TestParse_CommandsWithoutNamePrefix(t *testing.T) {
+	i := parseTest(t, "!darrot test")
+	assert.Equal(t, "test", i.ApplicationCommandData().Name)
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		content string
+		key     string
+	}{
+		{"!darrot", KeyUnknownCommand},
+		{"!darrot dance", KeyUnknownCommand},
+		{"!darrot config", KeyUnknownSubcommand},
+		{"!darrot config colors", KeyUnknownSubcommand},
+		{"!darrot config queue", options.KeyMissing},
+		{"!darrot config queue size:many", KeyInvalidValue},
+		{"!darrot config queue size:2.5", KeyInvalidValue},
+		{"!darrot config queue size:99", KeyInvalidValue},
+		{"!darrot config queue 5 6", KeyUnknownOption},
+		{"!darrot config roles remove", KeyInvalidValue},
+		{"!darrot join general", KeyInvalidValue},
+		{`!darrot clip upload name:"horn`, KeyUnclosedQuote},
+		{"!darrot clip upload name:horn", options.KeyMissing},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.content, func(t *testing.T) {
+			interaction, err := Parse(testMessage(tt.content), "!darrot", lookupTestCommand)
+			assert.Nil(t, interaction)
+
+			var optionErr *options.Error
+			require.ErrorAs(t, err, &optionErr)
+			assert.Equal(t, tt.key, optionErr.Key)
+		})
+	}
+}
+
+func TestTokenize(t *testing.T) {
+	tokens, err := tokenize(`  voice  text:"hello  world" "a b"  `)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"voice", "text:hello  world", "a b"}, tokens)
+
+	tokens, err = tokenize(`name:""`)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"name:"}, tokens, "empty quotes give an empty value")
+}
@@ -0,0 +1,90 @@
+package textcmd
+
+import (
+	"github.com/bwmarrin/discordgo"
+)
+
+// Respond answers an interaction. Text commands get the response as a reply to the
+// command message instead; ephemeral responses are visible to the whole channel there.
+func Respond(s *discordgo.Session, i *discordgo.Interaction, response *discordgo.InteractionResponse) error {
+	if !IsTextCommand(i) {
+		return s.InteractionRespond(i, response)
+	}
+
+	message := responseMessage(i, response)
+	if message == nil {
+		// Deferred responses are followed by an edit, so show that the bot is working
+		return s.ChannelTyping(i.ChannelID)
+	}
+
+	_, err := s.ChannelMessageSendComplex(i.ChannelID, message)
+	return err
+}
+
+// EditResponse edits the response to an interaction. Text commands get the edit as a
+// new reply to the command message.
+func EditResponse(s *discordgo.Session, i *discordgo.Interaction, edit *discordgo.WebhookEdit) (*discordgo.Message, error) {
+	if !IsTextCommand(i) {
+		return s.InteractionResponseEdit(i, edit)
+	}
+
+	return s.ChannelMessageSendComplex(i.ChannelID, editMessage(i, edit))
+}
+
+// Reply posts content as a reply to a command message, such as an error found while
+// parsing it
+func Reply(s *discordgo.Session, m *discordgo.Message, content string) error {
+	message := &discordgo.MessageSend{Content: content}
+	message.Reference = m.Reference()
+	message.AllowedMentions = &discordgo.MessageAllowedMentions{}
+	_, err := s.ChannelMessageSendComplex(m.ChannelID, message)
+	return err
+}
+
+// responseMessage converts an interaction response into a reply, or returns nil when
+// there is nothing to post yet
+func responseMessage(i *discordgo.Interaction, response *discordgo.InteractionResponse) *discordgo.MessageSend {
+	if response == nil || response.Data == nil {
+		return nil
+	}
+
+	switch response.Type {
+	case discordgo.InteractionResponseChannelMessageWithSource, discordgo.InteractionResponseUpdateMessage:
+	default:
+		return nil
+	}
+
+	data := response.Data
+	return reply(i, &discordgo.MessageSend{
+		Content:    data.Content,
+		Embeds:     data.Embeds,
+		Components: data.Components,
+		Files:      data.Files,
+	})
+}
+
+// editMessage converts a response edit into a reply
+func editMessage(i *discordgo.Interaction, edit *discordgo.WebhookEdit) *discordgo.MessageSend {
+	message := &discordgo.MessageSend{Files: edit.Files}
+	if edit.Content != nil {
+		message.Content = *edit.Content
+	}
+	if edit.Embeds != nil {
+		message.Embeds = *edit.Embeds
+	}
+	if edit.Components != nil {
+		message.Components = *edit.Components
+	}
+	return reply(i, message)
+}
+
+// reply addresses a message to the command message without pinging anyone it mentions
+func reply(i *discordgo.Interaction, message *discordgo.MessageSend) *discordgo.MessageSend {
+	message.Reference = &discordgo.MessageReference{
+		MessageID: messageID(i),
+		ChannelID: i.ChannelID,
+		GuildID:   i.GuildID,
+	}
+	message.AllowedMentions = &discordgo.MessageAllowedMentions{}
+	return message
+}
@@ -0,0 +1,56 @@
+package textcmd
+
+import (
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsTextCommand(t *testing.T) {
+	assert.False(t, IsTextCommand(nil))
+	assert.False(t, IsTextCommand(&discordgo.Interaction{Token: "aW50ZXJhY3Rpb24"}))
+	assert.True(t, IsTextCommand(&discordgo.Interaction{Token: tokenPrefix + "msg-1"}))
+}
+
+func TestResponseMessage(t *testing.T) {
+	i := parseTest(t, "!darrot test").Interaction
+	embed := &discordgo.MessageEmbed{Title: "Stats"}
+
+	message := responseMessage(i, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: "✅ Done",
+			Embeds:  []*discordgo.MessageEmbed{embed},
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+	require.NotNil(t, message)
+	assert.Equal(t, "✅ Done", message.Content)
+	assert.Equal(t, []*discordgo.MessageEmbed{embed}, message.Embeds)
+
+	// The reply points at the command and does not ping mentioned users or roles
+	require.NotNil(t, message.Reference)
+	assert.Equal(t, "msg-1", message.Reference.MessageID)
+	assert.Equal(t, "chan-1", message.Reference.ChannelID)
+	require.NotNil(t, message.AllowedMentions)
+	assert.Empty(t, message.AllowedMentions.Parse)
+
+	// Deferred responses have nothing to post until they are edited
+	assert.Nil(t, responseMessage(i, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral},
+	}))
+}
+
+func TestEditMessage(t *testing.T) {
+	i := parseTest(t, "!darrot test").Interaction
+	content := "✅ Uploaded"
+	file := &discordgo.File{Name: "preview.ogg"}
+
+	message := editMessage(i, &discordgo.WebhookEdit{Content: &content, Files: []*discordgo.File{file}})
+	assert.Equal(t, content, message.Content)
+	assert.Equal(t, []*discordgo.File{file}, message.Files)
+	assert.Equal(t, "msg-1", message.Reference.MessageID)
+}
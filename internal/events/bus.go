@@ -0,0 +1,115 @@
+// Package events is an in-process publish/subscribe bus for typed notifications between
+// components. Publishers such as the TTS processor and the voice manager announce what
+// happened without knowing who listens, and features such as transcripts and overlay
+// feeds subscribe to the event types they need.
+package events
+
+import (
+	"log"
+	"reflect"
+	"sync"
+)
+
+// Event is a notification published on a Bus. Every event belongs to a guild.
+type Event interface {
+	Guild() string
+}
+
+// Bus delivers each published event to the handlers subscribed to its type. Handlers run
+// synchronously on the publisher's goroutine in the order they subscribed, so they must
+// return quickly and hand slow work off to their own goroutines. A nil Bus is valid and
+// drops every event.
+type Bus struct {
+	mu       sync.RWMutex
+	handlers map[reflect.Type][]*subscription
+}
+
+// subscription is one handler of an event type
+type subscription struct {
+	handle func(Event)
+}
+
+// New creates an event bus without subscribers
+func New() *Bus {
+	return &Bus{
+		handlers: make(map[reflect.Type][]*subscription),
+	}
+}
+
+// Subscribe calls handler with every event of type T published on the bus until the
+// returned function is called
+func Subscribe[T Event](bus *Bus, handler func(T)) (unsubscribe func()) {
+	if bus == nil {
+		return func() {}
+	}
+
+	eventType := reflect.TypeFor[T]()
+	sub := &subscription{handle: func(event Event) { handler(event.(T)) }}
+
+	bus.mu.Lock()
+	bus.handlers[eventType] = append(bus.handlers[eventType], sub)
+	bus.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { bus.remove(eventType, sub) })
+	}
+}
+
+// Publish delivers an event to the handlers subscribed to its type. A handler that
+// panics is logged and does not stop delivery to the others.
+func (b *Bus) Publish(event Event) {
+	if b == nil || event == nil {
+		return
+	}
+
+	b.mu.RLock()
+	subs := b.handlers[reflect.TypeOf(event)]
+	b.mu.RUnlock()
+
+	for _, sub := range subs {
+		deliver(sub, event)
+	}
+}
+
+// Subscribers returns how many handlers are subscribed to events of type T
+func Subscribers[T Event](bus *Bus) int {
+	if bus == nil {
+		return 0
+	}
+
+	bus.mu.RLock()
+	defer bus.mu.RUnlock()
+	return len(bus.handlers[reflect.TypeFor[T]()])
+}
+
+// remove deletes a subscription. The slice is copied so deliveries in progress keep
+// the handlers they started with.
+func (b *Bus) remove(eventType reflect.Type, sub *subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	subs := b.handlers[eventType]
+	remaining := make([]*subscription, 0, len(subs))
+	for _, existing := range subs {
+		if existing != sub {
+			remaining = append(remaining, existing)
+		}
+	}
+
+	if len(remaining) == 0 {
+		delete(b.handlers, eventType)
+		return
+	}
+	b.handlers[eventType] = remaining
+}
+
+// deliver calls one handler, recovering from panics
+func deliver(sub *subscription, event Event) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Event handler for %T panicked: %v", event, r)
+		}
+	}()
+	sub.handle(event)
+}
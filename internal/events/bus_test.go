@@ -0,0 +1,77 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBus_DeliversByType(t *testing.T) {
+	bus := New()
+
+	var enqueued []MessageEnqueued
+	var lost []ConnectionLost
+	Subscribe(bus, func(e MessageEnqueued) { enqueued = append(enqueued, e) })
+	Subscribe(bus, func(e ConnectionLost) { lost = append(lost, e) })
+
+	bus.Publish(MessageEnqueued{GuildID: "guild1", MessageID: "m1"})
+	bus.Publish(ConfigChanged{GuildID: "guild1"}) // Nobody subscribed
+
+	assert.Equal(t, []MessageEnqueued{{GuildID: "guild1", MessageID: "m1"}}, enqueued)
+	assert.Empty(t, lost)
+}
+
+func TestBus_HandlersRunInSubscriptionOrder(t *testing.T) {
+	bus := New()
+
+	var calls []string
+	Subscribe(bus, func(e UtteranceStarted) { calls = append(calls, "first "+e.Text) })
+	Subscribe(bus, func(e UtteranceStarted) { calls = append(calls, "second "+e.Text) })
+
+	bus.Publish(UtteranceStarted{Utterance: Utterance{GuildID: "guild1", Text: "hi"}})
+
+	assert.Equal(t, []string{"first hi", "second hi"}, calls)
+}
+
+func TestBus_Unsubscribe(t *testing.T) {
+	bus := New()
+
+	calls := 0
+	unsubscribe := Subscribe(bus, func(e ConfigChanged) { calls++ })
+	assert.Equal(t, 1, Subscribers[ConfigChanged](bus))
+
+	bus.Publish(ConfigChanged{GuildID: "guild1"})
+	unsubscribe()
+	unsubscribe() // Unsubscribing twice is harmless
+	bus.Publish(ConfigChanged{GuildID: "guild1"})
+
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, 0, Subscribers[ConfigChanged](bus))
+}
+
+func TestBus_PanickingHandlerDoesNotStopDelivery(t *testing.T) {
+	bus := New()
+
+	delivered := false
+	Subscribe(bus, func(e ConfigChanged) { panic("broken handler") })
+	Subscribe(bus, func(e ConfigChanged) { delivered = true })
+
+	assert.NotPanics(t, func() { bus.Publish(ConfigChanged{GuildID: "guild1"}) })
+	assert.True(t, delivered)
+}
+
+func TestBus_Nil(t *testing.T) {
+	var bus *Bus
+
+	unsubscribe := Subscribe(bus, func(e ConfigChanged) {})
+	assert.NotPanics(t, func() {
+		bus.Publish(ConfigChanged{GuildID: "guild1"})
+		unsubscribe()
+	})
+	assert.Equal(t, 0, Subscribers[ConfigChanged](bus))
+}
+
+func TestUtteranceEventsReportTheirGuild(t *testing.T) {
+	var event Event = UtteranceFinished{Utterance: Utterance{GuildID: "guild1"}}
+	assert.Equal(t, "guild1", event.Guild())
+}
@@ -0,0 +1,107 @@
+package events
+
+import "time"
+
+// MessageEnqueued is published when a message is added to a guild's TTS queue
+type MessageEnqueued struct {
+	GuildID      string
+	MessageID    string
+	UserID       string
+	Tag          string // Names the HTTP API source of injected messages
+	Announcement bool   // Join/leave announcements and other low-priority messages
+	QueuedAt     time.Time
+}
+
+// Guild returns the guild the message was queued in
+func (e MessageEnqueued) Guild() string { return e.GuildID }
+
+// Utterance describes a message being read aloud
+type Utterance struct {
+	GuildID      string
+	MessageID    string
+	UserID       string
+	Username     string
+	Text         string // The text as spoken, after moderation
+	Tag          string // Names the HTTP API source of injected messages
+	Part         int    // Index of the part of a split message, 0 for the first
+	Announcement bool   // Join/leave announcements and other low-priority messages
+	Preview      bool   // Voice previews, read in a voice other than the guild's
+}
+
+// Guild returns the guild the utterance is read in
+func (u Utterance) Guild() string { return u.GuildID }
+
+// UtteranceStarted is published when a message starts playing
+type UtteranceStarted struct {
+	Utterance
+	Expected  time.Duration // Length of the audio, or 0 when it is streamed and not known yet
+	StartedAt time.Time
+}
+
+// UtteranceFinished is published when a message that started playing stops, either
+// because it played to the end or because it was skipped or failed
+type UtteranceFinished struct {
+	Utterance
+	Played     time.Duration
+	Completed  bool // Whether the whole message played
+	FinishedAt time.Time
+}
+
+// VoiceJoined is published when the bot joins a voice channel, or moves to another one
+type VoiceJoined struct {
+	GuildID   string
+	ChannelID string
+	JoinedAt  time.Time
+}
+
+// Guild returns the guild whose voice channel was joined
+func (e VoiceJoined) Guild() string { return e.GuildID }
+
+// ConnectionLost is published when a voice connection stops accepting audio and goes on
+// standby until it reconnects
+type ConnectionLost struct {
+	GuildID   string
+	ChannelID string
+	Reason    string
+	LostAt    time.Time
+}
+
+// Guild returns the guild whose voice connection was lost
+func (e ConnectionLost) Guild() string { return e.GuildID }
+
+// VoiceOutage is published when a guild's voice connection keeps failing, because its
+// voice server closes the connection or rejoining it fails, and the bot backs off before
+// trying again
+type VoiceOutage struct {
+	GuildID   string
+	ChannelID string
+	Reason    string // The latest failure
+	Failures  int
+	RetryAt   time.Time // When the bot next tries to rejoin
+}
+
+// Guild returns the guild whose voice server is unavailable
+func (e VoiceOutage) Guild() string { return e.GuildID }
+
+// TTSOutage is published when synthesis keeps failing for a guild's messages and its
+// circuit breaker opens, skipping messages until a probe synthesis succeeds
+type TTSOutage struct {
+	GuildID   string
+	ChannelID string // The voice channel the bot is in, if any
+	Reason    string // The latest failure
+	Failures  int
+	RetryAt   time.Time // When the bot next probes the TTS engine
+}
+
+// Guild returns the guild whose messages cannot be synthesized
+func (e TTSOutage) Guild() string { return e.GuildID }
+
+// ConfigChanged is published when a guild's TTS configuration is saved
+type ConfigChanged struct {
+	GuildID   string
+	Version   uint64 // The configuration service's version of the saved configuration; 0 for changes made elsewhere
+	ChangedAt time.Time
+}
+
+// Guild returns the guild whose configuration changed
+func (e ConfigChanged) Guild() string { return e.GuildID }
@@ -0,0 +1,190 @@
+// Package i18n provides message catalogs for translating slash command metadata and
+// bot responses.
+//
+// Catalogs are flat JSON objects stored in locales/<locale>.json, where <locale> is a
+// Discord locale code such as "en-US" or "de". Values are fmt format strings. The
+// en-US catalog is the source of truth: every other catalog may translate any subset
+// of its keys, and missing keys fall back to English.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// DefaultLocale is the locale used when a guild has not chosen a language
+const DefaultLocale = "en-US"
+
+// LanguageNameKey is the catalog key holding a locale's name in its own language
+const LanguageNameKey = "language.name"
+
+//go:embed locales/*.json
+var embeddedLocales embed.FS
+
+var (
+	defaultCatalog     *Catalog
+	defaultCatalogOnce sync.Once
+)
+
+// Catalog holds translated messages keyed by locale and message key
+type Catalog struct {
+	messages map[string]map[string]string
+}
+
+// Default returns the catalog built from the locale files shipped with the bot
+func Default() *Catalog {
+	defaultCatalogOnce.Do(func() {
+		localeFS, err := fs.Sub(embeddedLocales, "locales")
+		if err == nil {
+			defaultCatalog, err = Load(localeFS)
+		}
+		if err != nil {
+			panic(fmt.Sprintf("invalid embedded message catalog: %v", err))
+		}
+	})
+	return defaultCatalog
+}
+
+// Load reads every <locale>.json file at the root of fsys into a catalog
+func Load(fsys fs.FS) (*Catalog, error) {
+	files, err := fs.Glob(fsys, "*.json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list locale files: %w", err)
+	}
+
+	catalog := &Catalog{messages: make(map[string]map[string]string, len(files))}
+	for _, file := range files {
+		locale := strings.TrimSuffix(path.Base(file), ".json")
+		if _, known := discordgo.Locales[discordgo.Locale(locale)]; !known {
+			return nil, fmt.Errorf("locale file %s is not named after a Discord locale", file)
+		}
+
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read locale file %s: %w", file, err)
+		}
+
+		var messages map[string]string
+		if err := json.Unmarshal(data, &messages); err != nil {
+			return nil, fmt.Errorf("failed to parse locale file %s: %w", file, err)
+		}
+		catalog.messages[locale] = messages
+	}
+
+	if _, ok := catalog.messages[DefaultLocale]; !ok {
+		return nil, fmt.Errorf("missing %s.json catalog", DefaultLocale)
+	}
+
+	return catalog, nil
+}
+
+// T returns the message for key in the given locale, formatted with args. Missing
+// translations fall back to the default locale and then to the key itself.
+func (c *Catalog) T(locale, key string, args ...any) string {
+	message, ok := c.messages[locale][key]
+	if !ok {
+		message, ok = c.messages[DefaultLocale][key]
+	}
+	if !ok {
+		return key
+	}
+
+	if len(args) == 0 {
+		return message
+	}
+	return fmt.Sprintf(message, args...)
+}
+
+// Supports reports whether the catalog has messages for a locale
+func (c *Catalog) Supports(locale string) bool {
+	_, ok := c.messages[locale]
+	return ok
+}
+
+// Locales returns the available locales in sorted order
+func (c *Catalog) Locales() []string {
+	locales := make([]string, 0, len(c.messages))
+	for locale := range c.messages {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// Keys returns the message keys defined for a locale in sorted order
+func (c *Catalog) Keys(locale string) []string {
+	keys := make([]string, 0, len(c.messages[locale]))
+	for key := range c.messages[locale] {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Localizations returns the translations of key for every locale other than the
+// default, in the form Discord expects for command localization fields
+func (c *Catalog) Localizations(key string) map[discordgo.Locale]string {
+	var localizations map[discordgo.Locale]string
+	for locale, messages := range c.messages {
+		if locale == DefaultLocale {
+			continue
+		}
+		if message, ok := messages[key]; ok {
+			if localizations == nil {
+				localizations = make(map[discordgo.Locale]string)
+			}
+			localizations[discordgo.Locale(locale)] = message
+		}
+	}
+	return localizations
+}
+
+// LocalizeCommand fills in the name and description localizations of a command, its
+// options and their choices. Keys are derived from the command path, for example
+// "command.darrot-config.description", "command.darrot-config.voice.setting.name" and
+// "command.darrot-config.voice.setting.choice.speed", where choices are identified by
+// their value.
+func (c *Catalog) LocalizeCommand(command *discordgo.ApplicationCommand) {
+	prefix := "command." + command.Name
+
+	if localizations := c.Localizations(prefix + ".name"); localizations != nil {
+		command.NameLocalizations = &localizations
+	}
+	if localizations := c.Localizations(prefix + ".description"); localizations != nil {
+		command.DescriptionLocalizations = &localizations
+	}
+
+	c.localizeOptions(prefix, command.Options)
+}
+
+// localizeOptions localizes options, recursing into subcommands
+func (c *Catalog) localizeOptions(prefix string, options []*discordgo.ApplicationCommandOption) {
+	for _, option := range options {
+		optionPrefix := prefix + "." + option.Name
+
+		option.NameLocalizations = c.Localizations(optionPrefix + ".name")
+		option.DescriptionLocalizations = c.Localizations(optionPrefix + ".description")
+
+		for _, choice := range option.Choices {
+			choice.NameLocalizations = c.Localizations(optionPrefix + ".choice." + choiceKey(choice))
+		}
+
+		c.localizeOptions(optionPrefix, option.Options)
+	}
+}
+
+// choiceKey identifies a choice by its string value, which unlike its display name is stable
+func choiceKey(choice *discordgo.ApplicationCommandOptionChoice) string {
+	if value, ok := choice.Value.(string); ok {
+		return value
+	}
+	return choice.Name
+}
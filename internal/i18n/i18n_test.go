@@ -0,0 +1,116 @@
+package i18n
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testCatalog(t *testing.T) *Catalog {
+	catalog, err := Load(fstest.MapFS{
+		"en-US.json": {Data: []byte(`{
+			"greeting": "Hello, %s!",
+			"farewell": "Goodbye",
+			"command.ping.description": "Check the bot"
+		}`)},
+		"de.json": {Data: []byte(`{
+			"greeting": "Hallo, %s!",
+			"command.ping.description": "Den Bot prüfen",
+			"command.ping.target.name": "ziel",
+			"command.ping.target.choice.fast": "schnell"
+		}`)},
+	})
+	require.NoError(t, err)
+	return catalog
+}
+
+func TestCatalog_Translate(t *testing.T) {
+	catalog := testCatalog(t)
+
+	assert.Equal(t, "Hello, Ada!", catalog.T("en-US", "greeting", "Ada"))
+	assert.Equal(t, "Hallo, Ada!", catalog.T("de", "greeting", "Ada"))
+
+	// Missing translations fall back to English, then to the key
+	assert.Equal(t, "Goodbye", catalog.T("de", "farewell"))
+	assert.Equal(t, "Goodbye", catalog.T("fr", "farewell"))
+	assert.Equal(t, "missing.key", catalog.T("de", "missing.key"))
+
+	assert.True(t, catalog.Supports("de"))
+	assert.False(t, catalog.Supports("fr"))
+	assert.Equal(t, []string{"de", "en-US"}, catalog.Locales())
+}
+
+func TestCatalog_LocalizeCommand(t *testing.T) {
+	catalog := testCatalog(t)
+
+	command := &discordgo.ApplicationCommand{
+		Name:        "ping",
+		Description: "Check the bot",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "target",
+				Description: "Target",
+				Choices: []*discordgo.ApplicationCommandOptionChoice{
+					{Name: "fast response", Value: "fast"},
+					{Name: "slow response", Value: "slow"},
+				},
+			},
+		},
+	}
+	catalog.LocalizeCommand(command)
+
+	assert.Nil(t, command.NameLocalizations)
+	require.NotNil(t, command.DescriptionLocalizations)
+	assert.Equal(t, "Den Bot prüfen", (*command.DescriptionLocalizations)[discordgo.German])
+
+	option := command.Options[0]
+	assert.Equal(t, map[discordgo.Locale]string{discordgo.German: "ziel"}, option.NameLocalizations)
+	assert.Nil(t, option.DescriptionLocalizations)
+	assert.Equal(t, map[discordgo.Locale]string{discordgo.German: "schnell"}, option.Choices[0].NameLocalizations)
+	assert.Nil(t, option.Choices[1].NameLocalizations)
+}
+
+func TestLoad_Errors(t *testing.T) {
+	_, err := Load(fstest.MapFS{"de.json": {Data: []byte(`{}`)}})
+	assert.Error(t, err, "en-US catalog is required")
+
+	_, err = Load(fstest.MapFS{
+		"en-US.json":   {Data: []byte(`{}`)},
+		"klingon.json": {Data: []byte(`{}`)},
+	})
+	assert.Error(t, err, "locale files must be named after Discord locales")
+
+	_, err = Load(fstest.MapFS{"en-US.json": {Data: []byte(`not json`)}})
+	assert.Error(t, err)
+}
+
+var formatVerb = regexp.MustCompile(`%[-+# 0]*\d*(?:\.\d+)?[a-zA-Z]`)
+
+func TestDefault_TranslationsMatchEnglish(t *testing.T) {
+	catalog := Default()
+	require.True(t, catalog.Supports(DefaultLocale))
+	assert.Greater(t, len(catalog.Locales()), 1, "at least one translation should ship with the bot")
+
+	english := catalog.Keys(DefaultLocale)
+	for _, locale := range catalog.Locales() {
+		assert.NotEqual(t, LanguageNameKey, catalog.T(locale, LanguageNameKey), "%s has no language name", locale)
+
+		for _, key := range catalog.Keys(locale) {
+			if strings.HasPrefix(key, "command.") {
+				continue // Command metadata is checked against handler definitions in the tts package
+			}
+
+			assert.Contains(t, english, key, "%s defines a key that English does not", locale)
+			assert.Equal(t,
+				formatVerb.FindAllString(catalog.T(DefaultLocale, key), -1),
+				formatVerb.FindAllString(catalog.T(locale, key), -1),
+				"%s: format verbs for %q must match English", locale, key)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package tts
+
+import (
+	"fmt"
+
+	"darrot/internal/commands/options"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// AccessCommandHandler handles /darrot-access, which sets who can control the bot and
+// whose messages are read. It was split off /darrot-config when that command reached
+// Discord's limit of 25 subcommands, and runs on the configuration handler's services.
+type AccessCommandHandler struct {
+	config *ConfigCommandHandler
+}
+
+// NewAccessCommandHandler creates the /darrot-access command handler
+func NewAccessCommandHandler(config *ConfigCommandHandler) *AccessCommandHandler {
+	return &AccessCommandHandler{config: config}
+}
+
+// Definition returns the Discord slash command definition for the access command
+func (h *AccessCommandHandler) Definition() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:                     "darrot-access",
+		Description:              "Choose who can control the bot and whose messages are read (Administrator only)",
+		DefaultMemberPermissions: adminCommandPermissions(),
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "roles",
+				Description: "Configure required roles for bot invitations",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "action",
+						Description: "Action to perform",
+						Required:    true,
+						Choices: []*discordgo.ApplicationCommandOptionChoice{
+							{Name: "set", Value: "set"},
+							{Name: "add", Value: "add"},
+							{Name: "remove", Value: "remove"},
+							{Name: "clear", Value: "clear"},
+							{Name: "list", Value: "list"},
+							{Name: "sync to Discord", Value: "sync"},
+							{Name: "stop syncing", Value: "unsync"},
+						},
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionRole,
+						Name:        "role",
+						Description: "Role to add or remove",
+						Required:    false,
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "speaker-roles",
+				Description: "Only read messages from members with these roles",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "action",
+						Description: "Action to perform",
+						Required:    true,
+						Choices: []*discordgo.ApplicationCommandOptionChoice{
+							{Name: "add", Value: "add"},
+							{Name: "remove", Value: "remove"},
+							{Name: "clear", Value: "clear"},
+							{Name: "list", Value: "list"},
+						},
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionRole,
+						Name:        "role",
+						Description: "Role to add or remove",
+						Required:    false,
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "bots",
+				Description: "Read messages from other bots and webhooks",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "all-bots",
+						Description: "Read messages from every other bot",
+						Required:    false,
+						Choices: []*discordgo.ApplicationCommandOptionChoice{
+							{Name: "on", Value: "on"},
+							{Name: "off", Value: "off"},
+						},
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "webhooks",
+						Description: "Read messages sent through webhooks",
+						Required:    false,
+						Choices: []*discordgo.ApplicationCommandOptionChoice{
+							{Name: "on", Value: "on"},
+							{Name: "off", Value: "off"},
+						},
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "action",
+						Description: "Change the bots that are always read",
+						Required:    false,
+						Choices: []*discordgo.ApplicationCommandOptionChoice{
+							{Name: "add", Value: "add"},
+							{Name: "remove", Value: "remove"},
+							{Name: "clear", Value: "clear"},
+						},
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionUser,
+						Name:        "bot",
+						Description: "Bot to add or remove",
+						Required:    false,
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "control",
+				Description: "Limit how members can move and steer the bot with /darrot-join and /darrot-control",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionInteger,
+						Name:        "actions-per-minute",
+						Description: fmt.Sprintf("Join and control actions each member may take per minute (0 is unlimited, max %d)", MaxControlsPerMinute),
+						Required:    false,
+						MinValue:    &[]float64{0}[0],
+						MaxValue:    MaxControlsPerMinute,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionBoolean,
+						Name:        "voice-channel-only",
+						Description: "Only let members in the bot's voice channel steer it",
+						Required:    false,
+					},
+				},
+			},
+		},
+	}
+}
+
+// Handle processes the access command interaction
+func (h *AccessCommandHandler) Handle(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	return h.config.handleSubcommand(s, i, h.routeSubcommand)
+}
+
+// routeSubcommand runs a /darrot-access subcommand
+func (h *AccessCommandHandler) routeSubcommand(s *discordgo.Session, i *discordgo.InteractionCreate, guildID, subcommand string, opts options.Set) error {
+	switch subcommand {
+	case "roles":
+		return h.config.handleRolesConfig(s, i, guildID, opts)
+	case "speaker-roles":
+		return h.config.handleSpeakerRolesConfig(s, i, guildID, opts)
+	case "bots":
+		return h.config.handleBotsConfig(s, i, guildID, opts)
+	case "control":
+		return h.config.handleControlConfig(s, i, guildID, opts)
+	default:
+		return h.config.respondError(s, i, h.config.localizer.T(guildID, "common.invalid_subcommand"))
+	}
+}
+
+// ValidatePermissions validates that the user is a server administrator
+func (h *AccessCommandHandler) ValidatePermissions(userID, guildID string) error {
+	return h.config.ValidatePermissions(userID, guildID)
+}
+
+// ValidateChannelAccess is not needed for access commands but required by interface
+func (h *AccessCommandHandler) ValidateChannelAccess(userID, channelID string) error {
+	return nil // Not applicable for access commands
+}
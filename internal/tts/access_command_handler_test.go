@@ -0,0 +1,42 @@
+package tts
+
+import (
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccessCommandHandler_Definition(t *testing.T) {
+	config, _, _, _, _ := createTestConfigHandler()
+	handler := NewAccessCommandHandler(config)
+
+	definition := handler.Definition()
+
+	assert.Equal(t, "darrot-access", definition.Name)
+	assert.Equal(t, adminCommandPermissions(), definition.DefaultMemberPermissions)
+	require.Len(t, definition.Options, 4) // roles, speaker-roles, bots, control subcommands
+
+	subcommandNames := make(map[string]bool)
+	for _, option := range definition.Options {
+		assert.Equal(t, discordgo.ApplicationCommandOptionSubCommand, option.Type)
+		subcommandNames[option.Name] = true
+	}
+	assert.True(t, subcommandNames["roles"])
+	assert.True(t, subcommandNames["speaker-roles"])
+	assert.True(t, subcommandNames["bots"])
+	assert.True(t, subcommandNames["control"])
+}
+
+func TestAccessCommandHandler_ValidatePermissions(t *testing.T) {
+	config, _, mockPermissionService, _, _ := createTestConfigHandler()
+	handler := NewAccessCommandHandler(config)
+
+	mockPermissionService.On("CanControlBot", "admin", "guild123").Return(true, nil)
+	mockPermissionService.On("CanControlBot", "user", "guild123").Return(false, nil)
+
+	assert.NoError(t, handler.ValidatePermissions("admin", "guild123"))
+	assert.Error(t, handler.ValidatePermissions("user", "guild123"))
+	mockPermissionService.AssertExpectations(t)
+}
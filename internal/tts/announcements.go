@@ -0,0 +1,155 @@
+package tts
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// VoiceAnnouncer announces users joining or leaving the bot's voice channel.
+// Announcements are queued in the low-priority lane so they never delay chat messages.
+type VoiceAnnouncer struct {
+	voiceManager  VoiceManager
+	messageQueue  MessageQueue
+	configService ConfigService
+	logger        *log.Logger
+	removeHandler func()
+}
+
+// NewVoiceAnnouncer creates a voice announcer. Call Register to start listening for
+// voice state updates.
+func NewVoiceAnnouncer(
+	voiceManager VoiceManager,
+	messageQueue MessageQueue,
+	configService ConfigService,
+	logger *log.Logger,
+) *VoiceAnnouncer {
+	return &VoiceAnnouncer{
+		voiceManager:  voiceManager,
+		messageQueue:  messageQueue,
+		configService: configService,
+		logger:        logger,
+	}
+}
+
+// Register subscribes the announcer to voice state updates on the session
+func (a *VoiceAnnouncer) Register(session *discordgo.Session) {
+	a.removeHandler = session.AddHandler(a.handleVoiceStateUpdate)
+}
+
+// Stop unsubscribes the announcer from voice state updates
+func (a *VoiceAnnouncer) Stop() {
+	if a.removeHandler != nil {
+		a.removeHandler()
+		a.removeHandler = nil
+	}
+}
+
+// Enabled reports whether join/leave announcements are turned on for a guild
+func (a *VoiceAnnouncer) Enabled(guildID string) bool {
+	config, err := a.configService.GetGuildConfig(guildID)
+	if err != nil || config == nil {
+		return false
+	}
+	return config.AnnounceVoiceEvents
+}
+
+// SetEnabled turns join/leave announcements on or off for a guild
+func (a *VoiceAnnouncer) SetEnabled(guildID string, enabled bool) error {
+	config, err := a.configService.GetGuildConfig(guildID)
+	if err != nil {
+		return fmt.Errorf("failed to get guild config: %w", err)
+	}
+	if config == nil {
+		defaultConfig := DefaultGuildTTSConfig(guildID)
+		config = &defaultConfig
+	}
+
+	updated := *config
+	updated.AnnounceVoiceEvents = enabled
+
+	return a.configService.SetGuildConfig(guildID, &updated)
+}
+
+// handleVoiceStateUpdate is the discordgo event handler for voice state changes
+func (a *VoiceAnnouncer) handleVoiceStateUpdate(s *discordgo.Session, vsu *discordgo.VoiceStateUpdate) {
+	botUserID := ""
+	if s.State != nil && s.State.User != nil {
+		botUserID = s.State.User.ID
+	}
+	a.announce(vsu, botUserID)
+}
+
+// announce queues an announcement if the update moved a user into or out of the
+// channel the bot is connected to
+func (a *VoiceAnnouncer) announce(vsu *discordgo.VoiceStateUpdate, botUserID string) {
+	if vsu == nil || vsu.VoiceState == nil || vsu.UserID == botUserID {
+		return
+	}
+	if vsu.Member != nil && vsu.Member.User != nil && vsu.Member.User.Bot {
+		return
+	}
+
+	connection, exists := a.voiceManager.GetConnection(vsu.GuildID)
+	if !exists || connection == nil {
+		return
+	}
+
+	previousChannel := ""
+	if vsu.BeforeUpdate != nil {
+		previousChannel = vsu.BeforeUpdate.ChannelID
+	}
+
+	var action string
+	switch {
+	case vsu.ChannelID == connection.ChannelID && previousChannel != connection.ChannelID:
+		action = "joined"
+	case previousChannel == connection.ChannelID && vsu.ChannelID != connection.ChannelID:
+		action = "left"
+	default:
+		return // Mute, deafen or unrelated channel changes
+	}
+
+	if !a.Enabled(vsu.GuildID) {
+		return
+	}
+
+	name := voiceMemberName(vsu.Member)
+	if name == "" {
+		return
+	}
+
+	message := &QueuedMessage{
+		ID:        fmt.Sprintf("voice-%s-%s-%d", action, vsu.UserID, time.Now().UnixNano()),
+		GuildID:   vsu.GuildID,
+		ChannelID: connection.ChannelID,
+		UserID:    vsu.UserID,
+		Username:  name,
+		Content:   fmt.Sprintf("%s %s the channel", name, action),
+		Priority:  PriorityLow,
+		Timestamp: time.Now(),
+	}
+
+	if err := a.messageQueue.Enqueue(message); err != nil {
+		a.logger.Printf("Failed to queue voice announcement for guild %s: %v", vsu.GuildID, err)
+	}
+}
+
+// voiceMemberName returns the name a member is shown with in the guild
+func voiceMemberName(member *discordgo.Member) string {
+	if member == nil {
+		return ""
+	}
+	if member.Nick != "" {
+		return member.Nick
+	}
+	if member.User == nil {
+		return ""
+	}
+	if member.User.GlobalName != "" {
+		return member.User.GlobalName
+	}
+	return member.User.Username
+}
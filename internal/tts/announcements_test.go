@@ -0,0 +1,102 @@
+package tts
+
+import (
+	"log"
+	"os"
+	"testing"
+
+	"darrot/internal/config"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createTestVoiceAnnouncer(t *testing.T) (*VoiceAnnouncer, *mockVoiceManager, MessageQueue) {
+	storage, err := NewStorageService(t.TempDir())
+	require.NoError(t, err)
+
+	configService := NewConfigService(storage, config.TTSConfig{DefaultVoice: DefaultVoice, DefaultSpeed: 1.0, DefaultVolume: 1.0, MaxQueueSize: 10})
+	voiceManager := newMockVoiceManager()
+	queue := NewMessageQueue()
+	logger := log.New(os.Stdout, "[TEST] ", log.LstdFlags)
+
+	_, err = voiceManager.JoinChannel("guild1", "voice1")
+	require.NoError(t, err)
+
+	return NewVoiceAnnouncer(voiceManager, queue, configService, logger), voiceManager, queue
+}
+
+func voiceUpdate(userID, channelID, previousChannelID string) *discordgo.VoiceStateUpdate {
+	update := &discordgo.VoiceStateUpdate{
+		VoiceState: &discordgo.VoiceState{
+			GuildID:   "guild1",
+			UserID:    userID,
+			ChannelID: channelID,
+			Member:    &discordgo.Member{User: &discordgo.User{ID: userID, Username: "bob"}},
+		},
+	}
+	if previousChannelID != "" {
+		update.BeforeUpdate = &discordgo.VoiceState{GuildID: "guild1", UserID: userID, ChannelID: previousChannelID}
+	}
+	return update
+}
+
+func TestVoiceAnnouncer_DisabledByDefault(t *testing.T) {
+	announcer, _, queue := createTestVoiceAnnouncer(t)
+
+	assert.False(t, announcer.Enabled("guild1"))
+
+	announcer.announce(voiceUpdate("user1", "voice1", ""), "bot")
+	assert.Equal(t, 0, queue.Size("guild1"))
+}
+
+func TestVoiceAnnouncer_JoinAndLeave(t *testing.T) {
+	announcer, _, queue := createTestVoiceAnnouncer(t)
+	require.NoError(t, announcer.SetEnabled("guild1", true))
+	assert.True(t, announcer.Enabled("guild1"))
+
+	announcer.announce(voiceUpdate("user1", "voice1", ""), "bot")
+	announcer.announce(voiceUpdate("user1", "", "voice1"), "bot")
+
+	joined, err := queue.Dequeue("guild1")
+	require.NoError(t, err)
+	require.NotNil(t, joined)
+	assert.Equal(t, "bob joined the channel", joined.Content)
+	assert.Equal(t, PriorityLow, joined.Priority)
+
+	left, err := queue.Dequeue("guild1")
+	require.NoError(t, err)
+	require.NotNil(t, left)
+	assert.Equal(t, "bob left the channel", left.Content)
+}
+
+func TestVoiceAnnouncer_IgnoresUnrelatedUpdates(t *testing.T) {
+	announcer, _, queue := createTestVoiceAnnouncer(t)
+	require.NoError(t, announcer.SetEnabled("guild1", true))
+
+	// Mute/deafen toggles within the same channel
+	announcer.announce(voiceUpdate("user1", "voice1", "voice1"), "bot")
+	// Moves between other channels
+	announcer.announce(voiceUpdate("user1", "voice2", "voice3"), "bot")
+	// The bot itself
+	announcer.announce(voiceUpdate("bot", "voice1", ""), "bot")
+	// Other bots
+	botUpdate := voiceUpdate("user2", "voice1", "")
+	botUpdate.Member.User.Bot = true
+	announcer.announce(botUpdate, "bot")
+	// Guilds without a voice connection
+	otherGuild := voiceUpdate("user1", "voice1", "")
+	otherGuild.GuildID = "guild2"
+	announcer.announce(otherGuild, "bot")
+
+	assert.Equal(t, 0, queue.Size("guild1"))
+	assert.Equal(t, 0, queue.Size("guild2"))
+}
+
+func TestVoiceMemberName(t *testing.T) {
+	assert.Equal(t, "", voiceMemberName(nil))
+	assert.Equal(t, "Nick", voiceMemberName(&discordgo.Member{Nick: "Nick", User: &discordgo.User{Username: "user"}}))
+	assert.Equal(t, "Global", voiceMemberName(&discordgo.Member{User: &discordgo.User{Username: "user", GlobalName: "Global"}}))
+	assert.Equal(t, "user", voiceMemberName(&discordgo.Member{User: &discordgo.User{Username: "user"}}))
+}
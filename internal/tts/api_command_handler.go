@@ -0,0 +1,274 @@
+package tts
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"darrot/internal/commands/options"
+	"darrot/internal/commands/textcmd"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// APICommandHandler handles administrator management of the tokens external systems
+// use to queue messages through the HTTP API
+type APICommandHandler struct {
+	tokenService      APITokenService
+	ttsManager        TTSManager
+	permissionService PermissionService
+	apiEnabled        bool
+	auditLog          *AuditLog
+	localizer         *Localizer
+	logger            *log.Logger
+}
+
+// NewAPICommandHandler creates a new HTTP API token command handler
+func NewAPICommandHandler(
+	tokenService APITokenService,
+	ttsManager TTSManager,
+	permissionService PermissionService,
+	logger *log.Logger,
+) *APICommandHandler {
+	return &APICommandHandler{
+		tokenService:      tokenService,
+		ttsManager:        ttsManager,
+		permissionService: permissionService,
+		logger:            logger,
+	}
+}
+
+// SetLocalizer sets the localizer used to translate responses
+func (h *APICommandHandler) SetLocalizer(localizer *Localizer) {
+	h.localizer = localizer
+}
+
+// SetAuditLog sets the audit log that records tokens being created and revoked
+func (h *APICommandHandler) SetAuditLog(auditLog *AuditLog) {
+	h.auditLog = auditLog
+}
+
+// SetAPIEnabled records whether the HTTP API is listening, so administrators creating
+// tokens are told when the bot operator has not turned it on
+func (h *APICommandHandler) SetAPIEnabled(enabled bool) {
+	h.apiEnabled = enabled
+}
+
+// Definition returns the Discord slash command definition for the API token command
+func (h *APICommandHandler) Definition() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:                     "darrot-api",
+		Description:              "Manage tokens that let external systems queue messages (Administrator only)",
+		DefaultMemberPermissions: adminCommandPermissions(),
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "create",
+				Description: "Create a token for a stream overlay, game server or other system",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "name",
+						Description: "Token name, also the tag of its messages (a-z, 0-9, _ or -)",
+						Required:    true,
+						MaxLength:   32,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "voice",
+						Description: "Voice ID or name for the token's messages (defaults to the server's voice)",
+						Required:    false,
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "list",
+				Description: "Show the server's API tokens",
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "revoke",
+				Description: "Delete a token so it stops working",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "name",
+						Description: "Name of the token to revoke",
+						Required:    true,
+					},
+				},
+			},
+		},
+	}
+}
+
+// Handle processes the API token command interaction
+func (h *APICommandHandler) Handle(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	// Validate guild context
+	if i.GuildID == "" {
+		return h.respondError(s, i, h.localizer.T(i.GuildID, "common.guild_only"))
+	}
+
+	userID := i.Member.User.ID
+	guildID := i.GuildID
+
+	// Validate administrator permissions
+	if err := h.ValidatePermissions(userID, guildID); err != nil {
+		return h.respondError(s, i, h.localizer.T(guildID, "common.permission_denied", err))
+	}
+
+	subcommand, opts, ok := options.FromInteraction(i).Subcommand()
+	if !ok {
+		return h.respondError(s, i, h.localizer.T(guildID, "common.no_subcommand"))
+	}
+
+	switch subcommand {
+	case "create":
+		return h.handleCreate(s, i, guildID, opts)
+	case "list":
+		return h.handleList(s, i, guildID)
+	case "revoke":
+		return h.handleRevoke(s, i, guildID, opts)
+	default:
+		return h.respondError(s, i, h.localizer.T(guildID, "common.invalid_subcommand"))
+	}
+}
+
+// handleCreate creates a token and shows its secret once. Text command replies are seen
+// by the whole channel, so tokens can only be created with the slash command.
+func (h *APICommandHandler) handleCreate(s *discordgo.Session, i *discordgo.InteractionCreate, guildID string, opts options.Set) error {
+	if textcmd.IsTextCommand(i.Interaction) {
+		return h.respondError(s, i, h.localizer.T(guildID, "api.slash_only"))
+	}
+
+	name, err := opts.RequiredString("name")
+	if err != nil {
+		return h.respondError(s, i, h.localizer.OptionError(guildID, err))
+	}
+
+	voiceID := ""
+	if requested, _ := opts.String("voice"); requested != "" {
+		voice, ok := h.findVoice(requested)
+		if !ok {
+			return h.respondError(s, i, h.localizer.T(guildID, "config.voice.invalid_voice", requested))
+		}
+		voiceID = voice.ID
+	}
+
+	secret, token, err := h.tokenService.CreateToken(guildID, name, voiceID, i.Member.User.ID)
+	if err != nil {
+		h.logger.Printf("Error creating API token for guild %s: %v", guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "api.create_failed", err))
+	}
+
+	message := h.localizer.T(guildID, "api.created", token.Name, secret, guildID)
+	if !h.apiEnabled {
+		message += h.localizer.T(guildID, "api.disabled_note")
+	}
+
+	err = h.respondSuccess(s, i, message)
+	h.auditLog.RecordConfigChange(guildID, i.Member.User.ID, commandLine(i), h.localizer.T(guildID, "api.audit_created", token.Name))
+	return err
+}
+
+// handleList shows the guild's tokens without their secrets
+func (h *APICommandHandler) handleList(s *discordgo.Session, i *discordgo.InteractionCreate, guildID string) error {
+	tokens, err := h.tokenService.ListTokens(guildID)
+	if err != nil {
+		h.logger.Printf("Error listing API tokens for guild %s: %v", guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "api.list_failed"))
+	}
+
+	return h.respondSuccess(s, i, h.describeTokens(guildID, tokens))
+}
+
+// describeTokens returns a user-facing list of API tokens
+func (h *APICommandHandler) describeTokens(guildID string, tokens []APIToken) string {
+	if len(tokens) == 0 {
+		return h.localizer.T(guildID, "api.list_empty")
+	}
+
+	lines := make([]string, len(tokens))
+	for idx, token := range tokens {
+		voice := token.Voice
+		if voice == "" {
+			voice = h.localizer.T(guildID, "api.server_voice")
+		}
+		lines[idx] = h.localizer.T(guildID, "api.list_entry", token.Name, voice, token.CreatedBy, token.CreatedAt.Unix())
+	}
+	return h.localizer.T(guildID, "api.list", len(tokens), MaxAPITokensPerGuild, strings.Join(lines, "\n"))
+}
+
+// handleRevoke deletes a token
+func (h *APICommandHandler) handleRevoke(s *discordgo.Session, i *discordgo.InteractionCreate, guildID string, opts options.Set) error {
+	name, err := opts.RequiredString("name")
+	if err != nil {
+		return h.respondError(s, i, h.localizer.OptionError(guildID, err))
+	}
+
+	if err := h.tokenService.RevokeToken(guildID, name); err != nil {
+		if errors.Is(err, ErrAPITokenNotFound) {
+			return h.respondError(s, i, h.localizer.T(guildID, "api.not_found", name))
+		}
+		h.logger.Printf("Error revoking API token %q for guild %s: %v", name, guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "api.revoke_failed", err))
+	}
+
+	message := h.localizer.T(guildID, "api.revoked", strings.ToLower(strings.TrimSpace(name)))
+	err = h.respondSuccess(s, i, message)
+	h.auditLog.RecordConfigChange(guildID, i.Member.User.ID, commandLine(i), message)
+	return err
+}
+
+// findVoice looks up a supported voice by ID or display name, ignoring case
+func (h *APICommandHandler) findVoice(requested string) (Voice, bool) {
+	for _, voice := range h.ttsManager.GetSupportedVoices() {
+		if strings.EqualFold(voice.ID, requested) || strings.EqualFold(voice.Name, requested) {
+			return voice, true
+		}
+	}
+	return Voice{}, false
+}
+
+// ValidatePermissions validates that the user has administrator permissions
+func (h *APICommandHandler) ValidatePermissions(userID, guildID string) error {
+	canControl, err := h.permissionService.CanControlBot(userID, guildID)
+	if err != nil {
+		return fmt.Errorf("failed to check permissions: %w", err)
+	}
+
+	if !canControl {
+		return withKind(ErrPermission, fmt.Errorf("you must have administrator permissions to manage API tokens"))
+	}
+
+	return nil
+}
+
+// ValidateChannelAccess is not needed for API token commands but required by interface
+func (h *APICommandHandler) ValidateChannelAccess(userID, channelID string) error {
+	return nil // Not applicable for API token commands
+}
+
+// Helper methods for response handling
+
+func (h *APICommandHandler) respondSuccess(s *discordgo.Session, i *discordgo.InteractionCreate, message string) error {
+	return textcmd.Respond(s, i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: message,
+			Flags:   discordgo.MessageFlagsEphemeral, // Token secrets must only be shown to the administrator
+		},
+	})
+}
+
+func (h *APICommandHandler) respondError(s *discordgo.Session, i *discordgo.InteractionCreate, message string) error {
+	return textcmd.Respond(s, i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: "❌ " + message,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
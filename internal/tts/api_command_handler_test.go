@@ -0,0 +1,75 @@
+package tts
+
+import (
+	"errors"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createTestAPIHandler(t *testing.T) (*APICommandHandler, *MockPermissionService) {
+	mockPermissionService := &MockPermissionService{}
+	logger := log.New(os.Stdout, "[TEST] ", log.LstdFlags)
+
+	tokenService, _ := createTestAPITokenService(t)
+	return NewAPICommandHandler(tokenService, &MockTTSManagerTestify{}, mockPermissionService, logger), mockPermissionService
+}
+
+func TestAPICommandHandler_Definition(t *testing.T) {
+	handler, _ := createTestAPIHandler(t)
+
+	definition := handler.Definition()
+
+	assert.Equal(t, "darrot-api", definition.Name)
+	assert.Contains(t, definition.Description, "Administrator only")
+	require.Len(t, definition.Options, 3) // create, list, revoke subcommands
+
+	create := definition.Options[0]
+	assert.Equal(t, "create", create.Name)
+	require.Len(t, create.Options, 2)
+	assert.True(t, create.Options[0].Required)
+	assert.Equal(t, 32, create.Options[0].MaxLength)
+	assert.False(t, create.Options[1].Required, "the voice defaults to the server's voice")
+
+	assert.Equal(t, "list", definition.Options[1].Name)
+
+	revoke := definition.Options[2]
+	assert.Equal(t, "revoke", revoke.Name)
+	require.Len(t, revoke.Options, 1)
+	assert.Equal(t, discordgo.ApplicationCommandOptionString, revoke.Options[0].Type)
+}
+
+func TestAPICommandHandler_ValidatePermissions(t *testing.T) {
+	handler, mockPermissionService := createTestAPIHandler(t)
+
+	mockPermissionService.On("CanControlBot", "admin", "guild123").Return(true, nil)
+	mockPermissionService.On("CanControlBot", "user", "guild123").Return(false, nil)
+	mockPermissionService.On("CanControlBot", "broken", "guild123").Return(false, errors.New("permission check failed"))
+
+	assert.NoError(t, handler.ValidatePermissions("admin", "guild123"))
+	assert.ErrorContains(t, handler.ValidatePermissions("user", "guild123"), "administrator permissions")
+	assert.ErrorContains(t, handler.ValidatePermissions("broken", "guild123"), "failed to check permissions")
+
+	mockPermissionService.AssertExpectations(t)
+}
+
+func TestAPICommandHandler_DescribeTokens(t *testing.T) {
+	handler, _ := createTestAPIHandler(t)
+	createdAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	assert.Contains(t, handler.describeTokens("guild1", nil), "no API tokens")
+
+	description := handler.describeTokens("guild1", []APIToken{
+		{Name: "overlay", Voice: "en-US-Standard-A", CreatedBy: "admin1", CreatedAt: createdAt},
+		{Name: "game", CreatedBy: "admin2", CreatedAt: createdAt},
+	})
+	assert.Contains(t, description, "(2 of 10)")
+	assert.Contains(t, description, "**overlay**: voice en-US-Standard-A, created by <@admin1>")
+	assert.Contains(t, description, "**game**: voice of the server, created by <@admin2>")
+	assert.NotContains(t, description, "drt_", "secrets are never shown again")
+}
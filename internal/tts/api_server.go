@@ -0,0 +1,369 @@
+package tts
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"darrot/internal/events"
+)
+
+// HTTP API limits
+const (
+	APIRequestsPerMinute = 30                      // Messages each token may queue per minute
+	MaxAPITextLength     = DefaultMaxMessageLength // Characters of text per message
+	maxAPIRequestBytes   = 8 * 1024
+	apiShutdownTimeout   = 5 * time.Second
+	apiKeepaliveInterval = 30 * time.Second // Comments sent on idle event streams so proxies keep them open
+
+	MaxEventStreamsPerGuild = 10 // Overlays that can follow one guild at the same time
+	eventStreamBuffer       = 16 // Events kept for a stream that is slow to send
+)
+
+// SpeakRequest is the body of POST /api/guilds/{guildID}/speak
+type SpeakRequest struct {
+	Text   string `json:"text"`
+	Author string `json:"author,omitempty"` // Read before the text as "<author> says:"
+	Tag    string `json:"tag,omitempty"`    // Tags the queued message; defaults to the token name
+}
+
+// SpeakResponse is returned when a message was queued
+type SpeakResponse struct {
+	Status string `json:"status"`
+	Tag    string `json:"tag"`
+}
+
+// apiError is the body of every failed API request
+type apiError struct {
+	Error string `json:"error"`
+}
+
+// APIServer exposes an HTTP API that lets external systems, such as stream overlays
+// and game servers, queue text in a guild the bot is reading in and follow what it is
+// reading. Requests are authorized with per-guild tokens and rate limited per token.
+type APIServer struct {
+	tokens       APITokenService
+	messageQueue MessageQueue
+	voiceManager VoiceManager
+	eventBus     *events.Bus
+	cooldown     *UserCooldown
+	server       *http.Server
+	stopping     context.Context // Done once the server shuts down, ending event streams
+	logger       *log.Logger
+
+	streamsMu sync.Mutex
+	streams   map[string]int // Open event streams per guild
+}
+
+// NewAPIServer creates an HTTP API server that listens on address once started
+func NewAPIServer(address string, tokens APITokenService, messageQueue MessageQueue, voiceManager VoiceManager, logger *log.Logger) *APIServer {
+	a := &APIServer{
+		tokens:       tokens,
+		messageQueue: messageQueue,
+		voiceManager: voiceManager,
+		cooldown:     NewUserCooldown(),
+		logger:       logger,
+		streams:      make(map[string]int),
+	}
+	a.server = &http.Server{
+		Addr:              address,
+		Handler:           a.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	// Event streams never go idle, so they are ended when shutdown starts
+	stopping, stop := context.WithCancel(context.Background())
+	a.stopping = stopping
+	a.server.RegisterOnShutdown(stop)
+	return a
+}
+
+// SetEventBus enables the now-speaking event stream for overlays
+func (a *APIServer) SetEventBus(bus *events.Bus) {
+	a.eventBus = bus
+}
+
+// Handler returns the API's HTTP handler
+func (a *APIServer) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/guilds/{guildID}/speak", a.handleSpeak)
+	mux.HandleFunc("GET /api/guilds/{guildID}/events", a.handleEvents)
+	return mux
+}
+
+// Start listens on the configured address and serves requests in the background
+func (a *APIServer) Start() error {
+	listener, err := net.Listen("tcp", a.server.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", a.server.Addr, err)
+	}
+
+	go func() {
+		if err := a.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			a.logger.Printf("HTTP API stopped: %v", err)
+		}
+	}()
+
+	a.logger.Printf("HTTP API listening on %s", listener.Addr())
+	return nil
+}
+
+// Stop stops accepting requests and waits briefly for requests in progress
+func (a *APIServer) Stop() error {
+	ctx, cancel := context.WithTimeout(context.Background(), apiShutdownTimeout)
+	defer cancel()
+	return a.server.Shutdown(ctx)
+}
+
+// authenticate returns the guild's token a request is authorized with, writing an error
+// response when there is none. Browser sources such as OBS cannot set headers, so
+// requests that allow it may pass the token in the token query parameter instead.
+func (a *APIServer) authenticate(w http.ResponseWriter, r *http.Request, guildID string, allowQuery bool) (*APIToken, bool) {
+	secret, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok && allowQuery {
+		secret = r.URL.Query().Get("token")
+		ok = secret != ""
+	}
+	if !ok {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		writeAPIError(w, http.StatusUnauthorized, "missing bearer token")
+		return nil, false
+	}
+
+	token, err := a.tokens.Authenticate(guildID, strings.TrimSpace(secret))
+	if errors.Is(err, ErrAPITokenInvalid) {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		writeAPIError(w, http.StatusUnauthorized, "invalid token for this guild")
+		return nil, false
+	}
+	if err != nil {
+		a.logger.Printf("Failed to authenticate API request for guild %s: %v", guildID, err)
+		writeAPIError(w, http.StatusInternalServerError, "failed to check token")
+		return nil, false
+	}
+	return token, true
+}
+
+// handleSpeak queues text in a guild's TTS queue
+func (a *APIServer) handleSpeak(w http.ResponseWriter, r *http.Request) {
+	guildID := r.PathValue("guildID")
+
+	token, ok := a.authenticate(w, r, guildID, false)
+	if !ok {
+		return
+	}
+
+	var request SpeakRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxAPIRequestBytes)).Decode(&request); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "request body must be a JSON object with a text field")
+		return
+	}
+
+	message, err := a.newMessage(guildID, token, request)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if _, connected := a.voiceManager.GetConnection(guildID); !connected {
+		writeAPIError(w, http.StatusConflict, "the bot is not in a voice channel in this guild")
+		return
+	}
+
+	if !a.cooldown.Allow(guildID, "api:"+token.Name, APIRequestsPerMinute) {
+		w.Header().Set("Retry-After", fmt.Sprintf("%d", int(cooldownWindow.Seconds())))
+		writeAPIError(w, http.StatusTooManyRequests, fmt.Sprintf("tokens can queue at most %d messages per minute", APIRequestsPerMinute))
+		return
+	}
+
+	if err := a.messageQueue.Enqueue(message); err != nil {
+		a.logger.Printf("Failed to queue API message for guild %s: %v", guildID, err)
+		writeAPIError(w, http.StatusInternalServerError, "failed to queue message")
+		return
+	}
+
+	a.logger.Printf("Queued API message for guild %s with token %s", guildID, token.Name)
+	writeAPIResponse(w, http.StatusAccepted, SpeakResponse{Status: "queued", Tag: message.Tag})
+}
+
+// handleEvents streams the guild's speech events as server-sent events until the client
+// disconnects or the server stops
+func (a *APIServer) handleEvents(w http.ResponseWriter, r *http.Request) {
+	guildID := r.PathValue("guildID")
+
+	token, ok := a.authenticate(w, r, guildID, true)
+	if !ok {
+		return
+	}
+
+	flusher, canFlush := w.(http.Flusher)
+	if a.eventBus == nil || !canFlush {
+		writeAPIError(w, http.StatusNotImplemented, "event streams are not available")
+		return
+	}
+
+	if !a.openStream(guildID) {
+		writeAPIError(w, http.StatusTooManyRequests, fmt.Sprintf("a guild can have at most %d event streams", MaxEventStreamsPerGuild))
+		return
+	}
+	defer a.closeStream(guildID)
+
+	stream, unsubscribe := a.subscribeSpeech(guildID)
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no") // Stops nginx from buffering the stream
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	a.logger.Printf("Streaming speech events for guild %s to token %s", guildID, token.Name)
+
+	keepalive := time.NewTicker(apiKeepaliveInterval)
+	defer keepalive.Stop()
+
+	for {
+		var err error
+		select {
+		case event := <-stream:
+			err = writeServerSentEvent(w, event.Type, event)
+		case <-keepalive.C:
+			_, err = fmt.Fprint(w, ": keepalive\n\n")
+		case <-r.Context().Done():
+			return
+		case <-a.stopping.Done():
+			return
+		}
+		if err != nil {
+			return // The client went away
+		}
+		flusher.Flush()
+	}
+}
+
+// subscribeSpeech returns a channel receiving the guild's utterance events. Sending
+// never blocks playback: a stream that falls behind misses events.
+func (a *APIServer) subscribeSpeech(guildID string) (<-chan SpeechEvent, func()) {
+	stream := make(chan SpeechEvent, eventStreamBuffer)
+	send := func(event SpeechEvent) {
+		select {
+		case stream <- event:
+		default: // The client is not keeping up
+		}
+	}
+
+	unsubscribeStarted := events.Subscribe(a.eventBus, func(e events.UtteranceStarted) {
+		if e.GuildID == guildID {
+			send(speakingEvent(e))
+		}
+	})
+	unsubscribeFinished := events.Subscribe(a.eventBus, func(e events.UtteranceFinished) {
+		if e.GuildID == guildID {
+			send(finishedEvent(e))
+		}
+	})
+
+	return stream, func() {
+		unsubscribeStarted()
+		unsubscribeFinished()
+	}
+}
+
+// openStream counts a new event stream for a guild, refusing it once the guild has
+// MaxEventStreamsPerGuild
+func (a *APIServer) openStream(guildID string) bool {
+	a.streamsMu.Lock()
+	defer a.streamsMu.Unlock()
+
+	if a.streams[guildID] >= MaxEventStreamsPerGuild {
+		return false
+	}
+	a.streams[guildID]++
+	return true
+}
+
+// closeStream stops counting an event stream
+func (a *APIServer) closeStream(guildID string) {
+	a.streamsMu.Lock()
+	defer a.streamsMu.Unlock()
+
+	a.streams[guildID]--
+	if a.streams[guildID] <= 0 {
+		delete(a.streams, guildID)
+	}
+}
+
+// writeServerSentEvent writes one event of a text/event-stream response
+func writeServerSentEvent(w http.ResponseWriter, eventType string, body any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", eventType, data)
+	return err
+}
+
+// newMessage validates a speak request and builds the message to queue. Messages are
+// tagged with the request's tag or the token name and read with the token's voice.
+func (a *APIServer) newMessage(guildID string, token *APIToken, request SpeakRequest) (*QueuedMessage, error) {
+	return newSpeakMessage(guildID, "api-"+token.Name, token.Name, token.Voice, request)
+}
+
+// newSpeakMessage validates text queued from outside Discord and builds the message to
+// queue, tagged with the request's tag or defaultTag. Message IDs start with idPrefix.
+func newSpeakMessage(guildID, idPrefix, defaultTag, voice string, request SpeakRequest) (*QueuedMessage, error) {
+	text := strings.TrimSpace(request.Text)
+	if text == "" {
+		return nil, fmt.Errorf("text cannot be empty")
+	}
+	if utf8.RuneCountInString(text) > MaxAPITextLength {
+		return nil, fmt.Errorf("text can be at most %d characters", MaxAPITextLength)
+	}
+
+	tag := defaultTag
+	if request.Tag != "" {
+		normalized, err := NormalizeAPITokenName(request.Tag)
+		if err != nil {
+			return nil, fmt.Errorf("invalid tag: %w", err)
+		}
+		tag = normalized
+	}
+
+	// Messages without an author are listed under their tag, such as in transcripts
+	username := tag
+	if author := strings.Join(strings.Fields(request.Author), " "); author != "" {
+		text = fmt.Sprintf("%s says: %s", author, text)
+		username = author
+	}
+
+	now := time.Now()
+	return &QueuedMessage{
+		ID:        fmt.Sprintf("%s-%d", idPrefix, now.UnixNano()),
+		GuildID:   guildID,
+		Username:  username,
+		Content:   text,
+		Voice:     voice,
+		Tag:       tag,
+		Timestamp: now,
+	}, nil
+}
+
+// writeAPIResponse writes a JSON response body
+func writeAPIResponse(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// writeAPIError writes a JSON error response
+func writeAPIError(w http.ResponseWriter, status int, message string) {
+	writeAPIResponse(w, status, apiError{Error: message})
+}
@@ -0,0 +1,230 @@
+package tts
+
+import (
+	"bufio"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"darrot/internal/events"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createTestAPIServer(t *testing.T, connected bool) (*APIServer, MessageQueue, string) {
+	tokenService, _ := createTestAPITokenService(t)
+	secret, _, err := tokenService.CreateToken("guild1", "overlay", "en-US-Standard-A", "admin1")
+	require.NoError(t, err)
+
+	mockVoiceManager := &MockVoiceManager{}
+	mockVoiceManager.On("GetConnection", "guild1").Return(nil, connected).Maybe()
+
+	messageQueue := NewMessageQueue()
+	logger := log.New(os.Stdout, "[TEST] ", log.LstdFlags)
+	return NewAPIServer("127.0.0.1:0", tokenService, messageQueue, mockVoiceManager, logger), messageQueue, secret
+}
+
+func postSpeak(t *testing.T, server *APIServer, guildID, secret, body string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodPost, "/api/guilds/"+guildID+"/speak", strings.NewReader(body))
+	if secret != "" {
+		request.Header.Set("Authorization", "Bearer "+secret)
+	}
+	recorder := httptest.NewRecorder()
+	server.Handler().ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestAPIServer_QueuesTaggedMessage(t *testing.T) {
+	server, messageQueue, secret := createTestAPIServer(t, true)
+
+	recorder := postSpeak(t, server, "guild1", secret, `{"text": " Wave 5 incoming ", "author": "Game  Server"}`)
+	require.Equal(t, http.StatusAccepted, recorder.Code, recorder.Body.String())
+
+	var response SpeakResponse
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+	assert.Equal(t, SpeakResponse{Status: "queued", Tag: "overlay"}, response)
+
+	message, err := messageQueue.Dequeue("guild1")
+	require.NoError(t, err)
+	require.NotNil(t, message)
+	assert.Equal(t, "Game Server says: Wave 5 incoming", message.Content)
+	assert.Equal(t, "Game Server", message.Username)
+	assert.Equal(t, "overlay", message.Tag)
+	assert.Equal(t, "en-US-Standard-A", message.Voice, "messages are read with the token's voice")
+}
+
+func TestAPIServer_RequestTag(t *testing.T) {
+	server, messageQueue, secret := createTestAPIServer(t, true)
+
+	recorder := postSpeak(t, server, "guild1", secret, `{"text": "Follower alert", "tag": "Alerts"}`)
+	require.Equal(t, http.StatusAccepted, recorder.Code, recorder.Body.String())
+
+	message, err := messageQueue.Dequeue("guild1")
+	require.NoError(t, err)
+	require.NotNil(t, message)
+	assert.Equal(t, "alerts", message.Tag)
+	assert.Equal(t, "alerts", message.Username)
+	assert.Equal(t, "Follower alert", message.Content)
+
+	recorder = postSpeak(t, server, "guild1", secret, `{"text": "hi", "tag": "not a tag"}`)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestAPIServer_RejectsRequests(t *testing.T) {
+	server, messageQueue, secret := createTestAPIServer(t, true)
+
+	tests := []struct {
+		name     string
+		guildID  string
+		secret   string
+		body     string
+		expected int
+	}{
+		{name: "missing token", guildID: "guild1", body: `{"text": "hi"}`, expected: http.StatusUnauthorized},
+		{name: "invalid token", guildID: "guild1", secret: "drt_wrong", body: `{"text": "hi"}`, expected: http.StatusUnauthorized},
+		{name: "token of another guild", guildID: "guild2", secret: secret, body: `{"text": "hi"}`, expected: http.StatusUnauthorized},
+		{name: "invalid JSON", guildID: "guild1", secret: secret, body: `text=hi`, expected: http.StatusBadRequest},
+		{name: "empty text", guildID: "guild1", secret: secret, body: `{"text": "  "}`, expected: http.StatusBadRequest},
+		{name: "text too long", guildID: "guild1", secret: secret, body: `{"text": "` + strings.Repeat("a", MaxAPITextLength+1) + `"}`, expected: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := postSpeak(t, server, tt.guildID, tt.secret, tt.body)
+			assert.Equal(t, tt.expected, recorder.Code, recorder.Body.String())
+			assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+			if tt.expected == http.StatusUnauthorized {
+				assert.Equal(t, "Bearer", recorder.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+
+	assert.Equal(t, 0, messageQueue.Size("guild1"))
+}
+
+func TestAPIServer_RequiresVoiceConnection(t *testing.T) {
+	server, messageQueue, secret := createTestAPIServer(t, false)
+
+	recorder := postSpeak(t, server, "guild1", secret, `{"text": "hi"}`)
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+	assert.Equal(t, 0, messageQueue.Size("guild1"))
+}
+
+func TestAPIServer_RateLimitsPerToken(t *testing.T) {
+	server, _, secret := createTestAPIServer(t, true)
+
+	for i := 0; i < APIRequestsPerMinute; i++ {
+		recorder := postSpeak(t, server, "guild1", secret, `{"text": "hi"}`)
+		require.Equal(t, http.StatusAccepted, recorder.Code, recorder.Body.String())
+	}
+
+	recorder := postSpeak(t, server, "guild1", secret, `{"text": "hi"}`)
+	assert.Equal(t, http.StatusTooManyRequests, recorder.Code)
+	assert.NotEmpty(t, recorder.Header().Get("Retry-After"))
+}
+
+func TestAPIServer_StartStop(t *testing.T) {
+	server, _, _ := createTestAPIServer(t, true)
+
+	require.NoError(t, server.Start())
+	assert.NoError(t, server.Stop())
+}
+
+func TestAPIServer_StreamsSpeechEvents(t *testing.T) {
+	server, _, secret := createTestAPIServer(t, true)
+	bus := events.New()
+	server.SetEventBus(bus)
+
+	httpServer := httptest.NewServer(server.Handler())
+	defer httpServer.Close()
+
+	// Browser sources pass the token in the query string
+	response, err := http.Get(httpServer.URL + "/api/guilds/guild1/events?token=" + secret)
+	require.NoError(t, err)
+	defer response.Body.Close()
+	require.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, "text/event-stream", response.Header.Get("Content-Type"))
+
+	require.Eventually(t, func() bool { return events.Subscribers[events.UtteranceStarted](bus) == 1 }, time.Second, 10*time.Millisecond)
+	bus.Publish(events.UtteranceStarted{Utterance: events.Utterance{GuildID: "guild2", Text: "other guild"}})
+	bus.Publish(events.UtteranceStarted{
+		Utterance: events.Utterance{GuildID: "guild1", Username: "bob", Text: "bob says: hi"},
+		Expected:  1500 * time.Millisecond,
+	})
+
+	reader := bufio.NewReader(response.Body)
+	eventLine, err := reader.ReadString('\n')
+	require.NoError(t, err)
+	assert.Equal(t, "event: speaking\n", eventLine)
+	dataLine, err := reader.ReadString('\n')
+	require.NoError(t, err)
+
+	var event SpeechEvent
+	require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(dataLine, "data: ")), &event))
+	assert.Equal(t, "bob", event.Username)
+	assert.Equal(t, "bob says: hi", event.Text)
+	assert.Equal(t, int64(1500), event.DurationMs)
+
+	// Disconnecting unsubscribes
+	response.Body.Close()
+	assert.Eventually(t, func() bool { return events.Subscribers[events.UtteranceStarted](bus) == 0 }, time.Second, 10*time.Millisecond)
+}
+
+func TestAPIServer_EventsRequireToken(t *testing.T) {
+	server, _, secret := createTestAPIServer(t, true)
+	server.SetEventBus(events.New())
+
+	request := httptest.NewRequest(http.MethodGet, "/api/guilds/guild1/events?token=drt_wrong", nil)
+	recorder := httptest.NewRecorder()
+	server.Handler().ServeHTTP(recorder, request)
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+
+	// Tokens only follow the guild they were created in
+	request = httptest.NewRequest(http.MethodGet, "/api/guilds/guild2/events?token="+secret, nil)
+	recorder = httptest.NewRecorder()
+	server.Handler().ServeHTTP(recorder, request)
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+}
+
+func TestAPIServer_StopEndsEventStreams(t *testing.T) {
+	server, _, secret := createTestAPIServer(t, true)
+	bus := events.New()
+	server.SetEventBus(bus)
+	require.NoError(t, server.Start())
+
+	// Streams served by the handler end once shutdown starts
+	request := httptest.NewRequest(http.MethodGet, "/api/guilds/guild1/events", nil)
+	request.Header.Set("Authorization", "Bearer "+secret)
+	done := make(chan struct{})
+	go func() {
+		server.Handler().ServeHTTP(httptest.NewRecorder(), request)
+		close(done)
+	}()
+	require.Eventually(t, func() bool { return events.Subscribers[events.UtteranceFinished](bus) == 1 }, time.Second, 10*time.Millisecond)
+
+	require.NoError(t, server.Stop())
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Expected the event stream to end when the server stops")
+	}
+}
+
+func TestAPIServer_EventStreamLimit(t *testing.T) {
+	server, _, _ := createTestAPIServer(t, true)
+
+	for i := 0; i < MaxEventStreamsPerGuild; i++ {
+		require.True(t, server.openStream("guild1"))
+	}
+	assert.False(t, server.openStream("guild1"))
+	assert.True(t, server.openStream("guild2"), "the limit is per guild")
+
+	server.closeStream("guild1")
+	assert.True(t, server.openStream("guild1"))
+}
@@ -0,0 +1,171 @@
+package tts
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MaxAPITokensPerGuild bounds how many HTTP API tokens a guild can have
+const MaxAPITokensPerGuild = 10
+
+// apiTokenPrefix starts every HTTP API secret so leaked tokens are easy to recognize
+const apiTokenPrefix = "drt_"
+
+// apiTokenNamePattern restricts token names, which also tag queued messages, to safe,
+// easy-to-type identifiers
+var apiTokenNamePattern = regexp.MustCompile(`^[a-z0-9_-]{1,32}$`)
+
+// NormalizeAPITokenName lowercases and trims a token name, rejecting invalid names
+func NormalizeAPITokenName(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if !apiTokenNamePattern.MatchString(name) {
+		return "", fmt.Errorf("names must be 1-32 characters of a-z, 0-9, _ or -")
+	}
+	return name, nil
+}
+
+// APITokenServiceImpl implements APITokenService with token hashes stored through
+// StorageService. The secret is only known when the token is created.
+type APITokenServiceImpl struct {
+	storage *StorageService
+	now     func() time.Time
+	mu      sync.Mutex
+}
+
+// NewAPITokenService creates an HTTP API token service
+func NewAPITokenService(storage *StorageService) *APITokenServiceImpl {
+	return &APITokenServiceImpl{
+		storage: storage,
+		now:     time.Now,
+	}
+}
+
+// CreateToken creates a named token for the guild and returns its secret, which cannot
+// be recovered later
+func (s *APITokenServiceImpl) CreateToken(guildID, name, voice, createdBy string) (string, *APIToken, error) {
+	name, err := NormalizeAPITokenName(name)
+	if err != nil {
+		return "", nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tokens, err := s.storage.LoadAPITokens(guildID)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if findAPIToken(tokens.Tokens, name) >= 0 {
+		return "", nil, fmt.Errorf("%w: %s", ErrAPITokenExists, name)
+	}
+	if len(tokens.Tokens) >= MaxAPITokensPerGuild {
+		return "", nil, fmt.Errorf("%w: a server can have at most %d tokens", ErrAPITokenLimit, MaxAPITokensPerGuild)
+	}
+
+	secret, err := newAPISecret()
+	if err != nil {
+		return "", nil, err
+	}
+
+	token := APIToken{
+		Name:      name,
+		Hash:      hashAPISecret(secret),
+		Voice:     voice,
+		CreatedBy: createdBy,
+		CreatedAt: s.now(),
+	}
+	tokens.Tokens = append(tokens.Tokens, token)
+
+	if err := s.storage.SaveAPITokens(*tokens); err != nil {
+		return "", nil, err
+	}
+	return secret, &token, nil
+}
+
+// RevokeToken deletes a guild's token so its secret stops working
+func (s *APITokenServiceImpl) RevokeToken(guildID, name string) error {
+	name = strings.ToLower(strings.TrimSpace(name))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tokens, err := s.storage.LoadAPITokens(guildID)
+	if err != nil {
+		return err
+	}
+
+	index := findAPIToken(tokens.Tokens, name)
+	if index < 0 {
+		return fmt.Errorf("%w: %s", ErrAPITokenNotFound, name)
+	}
+	tokens.Tokens = slices.Delete(tokens.Tokens, index, index+1)
+
+	return s.storage.SaveAPITokens(*tokens)
+}
+
+// ListTokens returns a guild's tokens in the order they were created
+func (s *APITokenServiceImpl) ListTokens(guildID string) ([]APIToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tokens, err := s.storage.LoadAPITokens(guildID)
+	if err != nil {
+		return nil, err
+	}
+	return tokens.Tokens, nil
+}
+
+// Authenticate returns the guild's token with the given secret. Tokens only work for
+// the guild they were created in.
+func (s *APITokenServiceImpl) Authenticate(guildID, secret string) (*APIToken, error) {
+	if guildID == "" || !strings.HasPrefix(secret, apiTokenPrefix) {
+		return nil, ErrAPITokenInvalid
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tokens, err := s.storage.LoadAPITokens(guildID)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := []byte(hashAPISecret(secret))
+	for _, token := range tokens.Tokens {
+		if subtle.ConstantTimeCompare(hash, []byte(token.Hash)) == 1 {
+			return &token, nil
+		}
+	}
+	return nil, ErrAPITokenInvalid
+}
+
+// findAPIToken returns the index of the token with a name, or -1
+func findAPIToken(tokens []APIToken, name string) int {
+	return slices.IndexFunc(tokens, func(token APIToken) bool {
+		return token.Name == name
+	})
+}
+
+// newAPISecret generates a random token secret
+func newAPISecret() (string, error) {
+	random := make([]byte, 24)
+	if _, err := rand.Read(random); err != nil {
+		return "", fmt.Errorf("failed to generate API token: %w", err)
+	}
+	return apiTokenPrefix + hex.EncodeToString(random), nil
+}
+
+// hashAPISecret returns the hex-encoded SHA-256 of a token secret
+func hashAPISecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
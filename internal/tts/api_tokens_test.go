@@ -0,0 +1,102 @@
+package tts
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createTestAPITokenService(t *testing.T) (*APITokenServiceImpl, *StorageService) {
+	storage, err := NewStorageService(t.TempDir())
+	require.NoError(t, err)
+	return NewAPITokenService(storage), storage
+}
+
+func TestNormalizeAPITokenName(t *testing.T) {
+	name, err := NormalizeAPITokenName("  Stream-Overlay_1 ")
+	require.NoError(t, err)
+	assert.Equal(t, "stream-overlay_1", name)
+
+	for _, invalid := range []string{"", "has space", "émoji", strings.Repeat("a", 33)} {
+		_, err := NormalizeAPITokenName(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestAPITokenService_CreateAndAuthenticate(t *testing.T) {
+	tokenService, storage := createTestAPITokenService(t)
+	createdAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	tokenService.now = func() time.Time { return createdAt }
+
+	secret, token, err := tokenService.CreateToken("guild1", "Overlay", "en-US-Standard-A", "admin1")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(secret, apiTokenPrefix))
+	assert.Equal(t, "overlay", token.Name)
+	assert.Equal(t, "en-US-Standard-A", token.Voice)
+	assert.Equal(t, "admin1", token.CreatedBy)
+	assert.True(t, createdAt.Equal(token.CreatedAt))
+
+	// Only the hash of the secret is stored
+	stored, err := storage.LoadAPITokens("guild1")
+	require.NoError(t, err)
+	require.Len(t, stored.Tokens, 1)
+	assert.NotContains(t, stored.Tokens[0].Hash, secret)
+	assert.Equal(t, hashAPISecret(secret), stored.Tokens[0].Hash)
+
+	authenticated, err := tokenService.Authenticate("guild1", secret)
+	require.NoError(t, err)
+	assert.Equal(t, "overlay", authenticated.Name)
+
+	_, err = tokenService.Authenticate("guild1", secret+"0")
+	assert.ErrorIs(t, err, ErrAPITokenInvalid)
+	_, err = tokenService.Authenticate("guild1", "not-a-token")
+	assert.ErrorIs(t, err, ErrAPITokenInvalid)
+
+	// Tokens only work in the guild they were created in
+	_, err = tokenService.Authenticate("guild2", secret)
+	assert.ErrorIs(t, err, ErrAPITokenInvalid)
+}
+
+func TestAPITokenService_CreateRejectsDuplicatesAndLimit(t *testing.T) {
+	tokenService, _ := createTestAPITokenService(t)
+
+	_, _, err := tokenService.CreateToken("guild1", "overlay", "", "admin1")
+	require.NoError(t, err)
+	_, _, err = tokenService.CreateToken("guild1", "OVERLAY", "", "admin1")
+	assert.ErrorIs(t, err, ErrAPITokenExists)
+
+	for i := 1; i < MaxAPITokensPerGuild; i++ {
+		_, _, err := tokenService.CreateToken("guild1", fmt.Sprintf("token%d", i), "", "admin1")
+		require.NoError(t, err)
+	}
+	_, _, err = tokenService.CreateToken("guild1", "one-too-many", "", "admin1")
+	assert.ErrorIs(t, err, ErrAPITokenLimit)
+
+	// The limit is per guild
+	_, _, err = tokenService.CreateToken("guild2", "overlay", "", "admin1")
+	assert.NoError(t, err)
+}
+
+func TestAPITokenService_Revoke(t *testing.T) {
+	tokenService, _ := createTestAPITokenService(t)
+
+	secret, _, err := tokenService.CreateToken("guild1", "overlay", "", "admin1")
+	require.NoError(t, err)
+	_, _, err = tokenService.CreateToken("guild1", "game", "", "admin1")
+	require.NoError(t, err)
+
+	require.NoError(t, tokenService.RevokeToken("guild1", " Overlay "))
+	_, err = tokenService.Authenticate("guild1", secret)
+	assert.ErrorIs(t, err, ErrAPITokenInvalid)
+
+	tokens, err := tokenService.ListTokens("guild1")
+	require.NoError(t, err)
+	require.Len(t, tokens, 1)
+	assert.Equal(t, "game", tokens[0].Name)
+
+	assert.ErrorIs(t, tokenService.RevokeToken("guild1", "overlay"), ErrAPITokenNotFound)
+}
@@ -0,0 +1,120 @@
+package tts
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+)
+
+// DefaultAudioCacheBytes is the default memory budget for synthesized audio kept in the cache
+const DefaultAudioCacheBytes = 16 * 1024 * 1024
+
+// MetricAudioCacheHits counts messages served from the audio cache
+const MetricAudioCacheHits = "darrot_tts_audio_cache_hits_total"
+
+// AudioCache is a size-bounded LRU cache of synthesized audio keyed by text and voice settings.
+// Repeated phrases are served from memory instead of being synthesized (and billed) again.
+type AudioCache struct {
+	maxBytes  int
+	usedBytes int
+	entries   map[string]*list.Element
+	order     *list.List
+	mu        sync.Mutex
+}
+
+// audioCacheEntry is a single cached synthesis result
+type audioCacheEntry struct {
+	key   string
+	audio []byte
+}
+
+// NewAudioCache creates an audio cache holding at most maxBytes of audio
+func NewAudioCache(maxBytes int) *AudioCache {
+	if maxBytes <= 0 {
+		maxBytes = DefaultAudioCacheBytes
+	}
+
+	return &AudioCache{
+		maxBytes: maxBytes,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+// Get returns cached audio for text synthesized with config
+func (c *AudioCache) Get(text string, config TTSConfig) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, exists := c.entries[audioCacheKey(text, config)]
+	if !exists {
+		return nil, false
+	}
+
+	c.order.MoveToFront(element)
+	return element.Value.(*audioCacheEntry).audio, true
+}
+
+// Put stores audio for text synthesized with config, evicting the least recently used entries as needed
+func (c *AudioCache) Put(text string, config TTSConfig, audio []byte) {
+	if len(audio) == 0 || len(audio) > c.maxBytes {
+		return // Nothing to cache, or too large to ever fit
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := audioCacheKey(text, config)
+	if element, exists := c.entries[key]; exists {
+		entry := element.Value.(*audioCacheEntry)
+		c.usedBytes += len(audio) - len(entry.audio)
+		entry.audio = audio
+		c.order.MoveToFront(element)
+	} else {
+		c.entries[key] = c.order.PushFront(&audioCacheEntry{key: key, audio: audio})
+		c.usedBytes += len(audio)
+	}
+
+	for c.usedBytes > c.maxBytes {
+		oldest := c.order.Back()
+		if oldest == nil {
+			break
+		}
+		entry := oldest.Value.(*audioCacheEntry)
+		c.order.Remove(oldest)
+		delete(c.entries, entry.key)
+		c.usedBytes -= len(entry.audio)
+	}
+}
+
+// Len returns the number of cached entries
+func (c *AudioCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// Size returns the total bytes of cached audio
+func (c *AudioCache) Size() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.usedBytes
+}
+
+// Clear removes all cached audio
+func (c *AudioCache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = make(map[string]*list.Element)
+	c.order.Init()
+	c.usedBytes = 0
+}
+
+// audioCacheKey hashes the text together with every setting that affects the synthesized audio
+func audioCacheKey(text string, config TTSConfig) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%.2f|%.2f|%.1f|%s|%s|%.1f|%s|%s", config.Voice, config.Speed, config.Volume, config.Pitch, config.EffectsProfile, config.Style, config.Loudness, config.Format, text)))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,87 @@
+package tts
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAudioCache_GetPut(t *testing.T) {
+	cache := NewAudioCache(1024)
+	config := TTSConfig{Voice: DefaultVoice, Speed: 1.0, Volume: 1.0, Format: AudioFormatDCA}
+
+	_, ok := cache.Get("hello", config)
+	assert.False(t, ok)
+
+	cache.Put("hello", config, []byte("audio"))
+
+	audio, ok := cache.Get("hello", config)
+	assert.True(t, ok)
+	assert.Equal(t, []byte("audio"), audio)
+
+	// Any change to the voice settings is a different entry
+	other := config
+	other.Speed = 1.5
+	_, ok = cache.Get("hello", other)
+	assert.False(t, ok)
+}
+
+func TestAudioCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	cache := NewAudioCache(10)
+	config := TTSConfig{Voice: DefaultVoice}
+
+	cache.Put("a", config, []byte("aaaa"))
+	cache.Put("b", config, []byte("bbbb"))
+
+	// Touch "a" so "b" becomes the oldest entry
+	_, _ = cache.Get("a", config)
+	cache.Put("c", config, []byte("cccc"))
+
+	_, ok := cache.Get("b", config)
+	assert.False(t, ok, "least recently used entry should be evicted")
+	_, ok = cache.Get("a", config)
+	assert.True(t, ok)
+	_, ok = cache.Get("c", config)
+	assert.True(t, ok)
+
+	assert.Equal(t, 2, cache.Len())
+	assert.Equal(t, 8, cache.Size())
+}
+
+func TestAudioCache_RejectsOversizedAudio(t *testing.T) {
+	cache := NewAudioCache(4)
+	config := TTSConfig{Voice: DefaultVoice}
+
+	cache.Put("big", config, []byte("too large"))
+
+	assert.Equal(t, 0, cache.Len())
+	assert.Equal(t, 0, cache.Size())
+}
+
+func TestAudioCache_Clear(t *testing.T) {
+	cache := NewAudioCache(0)
+	config := TTSConfig{Voice: DefaultVoice}
+
+	cache.Put("hello", config, []byte("audio"))
+	cache.Clear()
+
+	assert.Equal(t, 0, cache.Len())
+	assert.Equal(t, 0, cache.Size())
+	_, ok := cache.Get("hello", config)
+	assert.False(t, ok)
+}
+
+func TestAudioCacheKey_VoiceOptions(t *testing.T) {
+	base := TTSConfig{Voice: "en-US-Neural2-F", Speed: 1.0, Volume: 1.0, Format: AudioFormatDCA}
+	pitched, profiled, styled, leveled := base, base, base, base
+	pitched.Pitch = 2
+	profiled.EffectsProfile = "headphone-class-device"
+	styled.Style = "calm"
+	leveled.Loudness = -20
+
+	keys := map[string]bool{}
+	for _, config := range []TTSConfig{base, pitched, profiled, styled, leveled} {
+		keys[audioCacheKey("hello", config)] = true
+	}
+	assert.Len(t, keys, 5, "pitch, effects, style and loudness must produce separate cache entries")
+}
@@ -0,0 +1,258 @@
+package tts
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+
+	"darrot/internal/events"
+)
+
+// audioStreamBuffer is how many messages an output can fall behind Discord before their
+// audio is dropped
+const audioStreamBuffer = 32
+
+// AudioOutputs copies the speech played in each guild to the extra output the guild chose,
+// such as a recording or an Icecast stream. Discord always plays the speech itself; the
+// other outputs only exist when the operator sets them up.
+type AudioOutputs struct {
+	sinks         map[AudioOutput]AudioSink
+	recorder      *SessionRecorder
+	configService ConfigService
+	contentPolicy *ContentPolicy
+	logger        *log.Logger
+}
+
+// NewAudioOutputs creates audio outputs with only Discord available
+func NewAudioOutputs(configService ConfigService, logger *log.Logger) *AudioOutputs {
+	return &AudioOutputs{
+		sinks:         make(map[AudioOutput]AudioSink),
+		configService: configService,
+		logger:        logger,
+	}
+}
+
+// SetContentPolicy keeps guilds that retain only metadata from being recorded to files
+func (o *AudioOutputs) SetContentPolicy(policy *ContentPolicy) {
+	o.contentPolicy = policy
+}
+
+// SetSessionRecorder also hands the audio to the session recorder, which records the
+// guilds that opted in
+func (o *AudioOutputs) SetSessionRecorder(recorder *SessionRecorder) {
+	o.recorder = recorder
+}
+
+// Register makes an output available to guilds
+func (o *AudioOutputs) Register(output AudioOutput, sink AudioSink) {
+	o.sinks[output] = sink
+}
+
+// Available reports whether guilds can choose an output
+func (o *AudioOutputs) Available(output AudioOutput) bool {
+	if output == AudioOutputDiscord {
+		return true
+	}
+	if o == nil {
+		return false
+	}
+	_, ok := o.sinks[output]
+	return ok
+}
+
+// Outputs returns the outputs guilds can choose, Discord first
+func (o *AudioOutputs) Outputs() []AudioOutput {
+	outputs := []AudioOutput{AudioOutputDiscord}
+	if o == nil {
+		return outputs
+	}
+	var others []AudioOutput
+	for output := range o.sinks {
+		others = append(others, output)
+	}
+	sort.Slice(others, func(i, j int) bool { return others[i] < others[j] })
+	return append(outputs, others...)
+}
+
+// Copy hands audio played in a guild to the guild's extra output, if it chose one. The
+// outputs buffer the audio, so Copy does not wait for them.
+func (o *AudioOutputs) Copy(guildID string, audioData []byte) {
+	if o == nil || o.configService == nil {
+		return
+	}
+
+	config, err := o.configService.GetGuildConfig(guildID)
+	if err != nil || config == nil {
+		return
+	}
+	o.recorder.Record(guildID, config, audioData)
+
+	sink, ok := o.sinks[config.AudioOutput]
+	if !ok {
+		return
+	}
+	if config.AudioOutput == AudioOutputFile && o.contentPolicy.ContentFree(guildID) {
+		return
+	}
+
+	if err := sink.PlayAudio(guildID, audioData); err != nil {
+		o.logger.Printf("Warning: failed to copy audio of guild %s to the %s output: %v", guildID, config.AudioOutput, err)
+	}
+}
+
+// Subscribe starts a new recording or stream session on every output whenever the bot
+// joins a voice channel
+func (o *AudioOutputs) Subscribe(bus *events.Bus) (unsubscribe func()) {
+	return events.Subscribe(bus, func(e events.VoiceJoined) {
+		for _, sink := range o.sinks {
+			if streaming, ok := sink.(*StreamingSink); ok {
+				streaming.EndStream(e.GuildID)
+			}
+		}
+	})
+}
+
+// Close finishes every recording and stream
+func (o *AudioOutputs) Close() error {
+	var errs []error
+	for output, sink := range o.sinks {
+		if streaming, ok := sink.(*StreamingSink); ok {
+			if err := streaming.Close(); err != nil {
+				errs = append(errs, fmt.Errorf("%s output: %w", output, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// audioStream is one guild's open recording or stream on an output
+type audioStream interface {
+	WritePackets(packets [][]byte) error
+	Close() error
+}
+
+// StreamingSink is an audio sink that keeps a stream open per guild, such as a recording
+// file or an Icecast connection, and writes to it in the background so a slow disk,
+// server or sound card never holds up Discord playback. A stream that fails is reopened
+// with the next message.
+type StreamingSink struct {
+	output AudioOutput
+	open   func(guildID string) (audioStream, error)
+	logger *log.Logger
+
+	mu      sync.Mutex
+	streams map[string]chan [][]byte // Audio waiting per guild; nil ends the guild's stream
+	closed  bool
+	wg      sync.WaitGroup
+}
+
+// newStreamingSink creates a sink opening guild streams with open
+func newStreamingSink(output AudioOutput, open func(guildID string) (audioStream, error), logger *log.Logger) *StreamingSink {
+	return &StreamingSink{
+		output:  output,
+		open:    open,
+		logger:  logger,
+		streams: make(map[string]chan [][]byte),
+	}
+}
+
+// PlayAudio queues DCA audio for the guild's stream, opening the stream first if needed
+func (s *StreamingSink) PlayAudio(guildID string, audioData []byte) error {
+	packets, err := parseDCAFrames(audioData)
+	if err != nil {
+		return err
+	}
+	if len(packets) == 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return fmt.Errorf("%s output is closed", s.output)
+	}
+	audio, ok := s.streams[guildID]
+	if !ok {
+		audio = make(chan [][]byte, audioStreamBuffer)
+		s.streams[guildID] = audio
+		s.wg.Add(1)
+		go s.run(guildID, audio)
+	}
+
+	select {
+	case audio <- packets:
+		return nil
+	default:
+		return fmt.Errorf("%s output is %d messages behind, dropping audio", s.output, audioStreamBuffer)
+	}
+}
+
+// EndStream finishes the guild's current recording or stream once its queued audio is
+// written; the next audio starts a new one
+func (s *StreamingSink) EndStream(guildID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if audio, ok := s.streams[guildID]; ok && !s.closed {
+		select {
+		case audio <- nil:
+		default: // The stream is far behind and is ended by its next failure or Close
+		}
+	}
+}
+
+// Close writes the queued audio and closes every stream
+func (s *StreamingSink) Close() error {
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		for _, audio := range s.streams {
+			close(audio)
+		}
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+	return nil
+}
+
+// run writes a guild's audio to its stream until the sink closes
+func (s *StreamingSink) run(guildID string, audio <-chan [][]byte) {
+	defer s.wg.Done()
+
+	var stream audioStream
+	closeStream := func() {
+		if stream == nil {
+			return
+		}
+		if err := stream.Close(); err != nil {
+			s.logger.Printf("Warning: failed to close %s output of guild %s: %v", s.output, guildID, err)
+		}
+		stream = nil
+	}
+	defer closeStream()
+
+	for packets := range audio {
+		if packets == nil {
+			closeStream()
+			continue
+		}
+
+		if stream == nil {
+			opened, err := s.open(guildID)
+			if err != nil {
+				s.logger.Printf("Warning: failed to open %s output of guild %s: %v", s.output, guildID, err)
+				continue
+			}
+			stream = opened
+		}
+
+		if err := stream.WritePackets(packets); err != nil {
+			s.logger.Printf("Warning: failed to write to %s output of guild %s: %v", s.output, guildID, err)
+			closeStream()
+		}
+	}
+}
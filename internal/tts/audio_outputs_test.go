@@ -0,0 +1,172 @@
+package tts
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"log"
+	"sync"
+	"testing"
+	"time"
+
+	"darrot/internal/events"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingSink is an audio sink that remembers the audio it is given
+type recordingSink struct {
+	mu     sync.Mutex
+	played map[string]int
+}
+
+func (r *recordingSink) PlayAudio(guildID string, audioData []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.played == nil {
+		r.played = make(map[string]int)
+	}
+	r.played[guildID]++
+	return nil
+}
+
+func (r *recordingSink) count(guildID string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.played[guildID]
+}
+
+// fakeAudioStream is an audio stream that collects the packets written to it
+type fakeAudioStream struct {
+	packets [][]byte
+	closed  bool
+}
+
+func (f *fakeAudioStream) WritePackets(packets [][]byte) error {
+	if f.closed {
+		return errors.New("stream is closed")
+	}
+	f.packets = append(f.packets, packets...)
+	return nil
+}
+
+func (f *fakeAudioStream) Close() error {
+	f.closed = true
+	return nil
+}
+
+// testDCA frames packets the way the TTS manager does
+func testDCA(t *testing.T, packets ...[]byte) []byte {
+	t.Helper()
+
+	var dca bytes.Buffer
+	for _, packet := range packets {
+		require.NoError(t, writeDCAFrame(&dca, packet))
+	}
+	return dca.Bytes()
+}
+
+func TestAudioOutputs_Copy(t *testing.T) {
+	configService := createTestExportConfigService(t)
+	for guildID, output := range map[string]AudioOutput{
+		"discord-guild":  "",
+		"icecast-guild":  AudioOutputIcecast,
+		"pulse-guild":    AudioOutputPulse,
+		"file-guild":     AudioOutputFile,
+		"metadata-guild": AudioOutputFile,
+	} {
+		config := DefaultGuildTTSConfig(guildID)
+		config.AudioOutput = output
+		if guildID == "metadata-guild" {
+			config.ContentRetention = ContentRetentionMetadata
+		}
+		require.NoError(t, configService.SetGuildConfig(guildID, &config))
+	}
+
+	icecast := &recordingSink{}
+	file := &recordingSink{}
+	outputs := NewAudioOutputs(configService, log.New(io.Discard, "", 0))
+	outputs.SetContentPolicy(NewContentPolicy(configService))
+	outputs.Register(AudioOutputIcecast, icecast)
+	outputs.Register(AudioOutputFile, file)
+
+	for _, guildID := range []string{"discord-guild", "icecast-guild", "pulse-guild", "file-guild", "metadata-guild", "unknown-guild"} {
+		outputs.Copy(guildID, []byte{1, 0, 0xF8})
+	}
+
+	assert.Equal(t, 1, icecast.count("icecast-guild"))
+	assert.Equal(t, 1, file.count("file-guild"))
+	assert.Zero(t, file.count("metadata-guild"), "guilds retaining only metadata are not recorded")
+	assert.Zero(t, icecast.count("discord-guild")+file.count("discord-guild"))
+	assert.Zero(t, icecast.count("pulse-guild")+file.count("pulse-guild"), "outputs the operator did not set up are ignored")
+
+	var nilOutputs *AudioOutputs
+	nilOutputs.Copy("icecast-guild", []byte{1, 0, 0xF8})
+}
+
+func TestAudioOutputs_Available(t *testing.T) {
+	var nilOutputs *AudioOutputs
+	assert.True(t, nilOutputs.Available(AudioOutputDiscord))
+	assert.False(t, nilOutputs.Available(AudioOutputFile))
+	assert.Equal(t, []AudioOutput{AudioOutputDiscord}, nilOutputs.Outputs())
+
+	outputs := NewAudioOutputs(nil, log.New(io.Discard, "", 0))
+	outputs.Register(AudioOutputPulse, &recordingSink{})
+	outputs.Register(AudioOutputFile, &recordingSink{})
+
+	assert.True(t, outputs.Available(AudioOutputFile))
+	assert.False(t, outputs.Available(AudioOutputIcecast))
+	assert.Equal(t, []AudioOutput{AudioOutputDiscord, AudioOutputFile, AudioOutputPulse}, outputs.Outputs())
+}
+
+func TestStreamingSink_Sessions(t *testing.T) {
+	var mu sync.Mutex
+	var streams []*fakeAudioStream
+	sink := newStreamingSink(AudioOutputFile, func(guildID string) (audioStream, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		stream := &fakeAudioStream{}
+		streams = append(streams, stream)
+		return stream, nil
+	}, log.New(io.Discard, "", 0))
+
+	outputs := NewAudioOutputs(nil, log.New(io.Discard, "", 0))
+	outputs.Register(AudioOutputFile, sink)
+	bus := events.New()
+	defer outputs.Subscribe(bus)()
+
+	require.NoError(t, sink.PlayAudio("guild123", testDCA(t, []byte{1}, []byte{2})))
+	require.NoError(t, sink.PlayAudio("guild123", testDCA(t, []byte{3})))
+	bus.Publish(events.VoiceJoined{GuildID: "guild123", JoinedAt: time.Now()})
+	require.NoError(t, sink.PlayAudio("guild123", testDCA(t, []byte{4})))
+	require.NoError(t, outputs.Close())
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, streams, 2, "joining a voice channel starts a new session")
+	assert.Equal(t, [][]byte{{1}, {2}, {3}}, streams[0].packets)
+	assert.Equal(t, [][]byte{{4}}, streams[1].packets)
+	assert.True(t, streams[0].closed)
+	assert.True(t, streams[1].closed)
+
+	assert.Error(t, sink.PlayAudio("guild123", testDCA(t, []byte{5})), "a closed sink refuses audio")
+	assert.Error(t, sink.PlayAudio("guild123", []byte{0xFF, 0xFF}), "invalid DCA is refused")
+}
+
+func TestStreamingSink_ReopensFailedStreams(t *testing.T) {
+	opens := make(chan struct{}, 3)
+	sink := newStreamingSink(AudioOutputIcecast, func(guildID string) (audioStream, error) {
+		opens <- struct{}{}
+		if len(opens) == 1 {
+			return nil, errors.New("connection refused")
+		}
+		return &fakeAudioStream{}, nil
+	}, log.New(io.Discard, "", 0))
+
+	require.NoError(t, sink.PlayAudio("guild123", testDCA(t, []byte{1})))
+	require.NoError(t, sink.PlayAudio("guild123", testDCA(t, []byte{2})))
+	require.NoError(t, sink.Close())
+
+	assert.Len(t, opens, 2, "the message after a failed open tries again")
+}
@@ -0,0 +1,216 @@
+package tts
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Embed colours of audit log entries
+const (
+	auditColorJoin     = 0x57F287 // Green
+	auditColorLeave    = 0x95A5A6 // Grey
+	auditColorChange   = 0x5865F2 // Blurple
+	auditColorRecovery = 0xFEE75C // Yellow
+	auditColorFailure  = 0xED4245 // Red
+	auditColorNotice   = 0xEB459E // Fuchsia
+	auditColorWarning  = 0xE67E22 // Orange
+)
+
+// auditFieldLimit is the longest value Discord accepts in an embed field
+const auditFieldLimit = 1024
+
+// AuditMessenger posts embeds to text channels
+type AuditMessenger interface {
+	ChannelMessageSendEmbed(channelID string, embed *discordgo.MessageEmbed, options ...discordgo.RequestOption) (*discordgo.Message, error)
+}
+
+// auditField is a field of an audit log entry, named by a catalog key
+type auditField struct {
+	nameKey string
+	value   string
+}
+
+// AuditLog posts who joined, left, reconfigured or cleared the bot, and how voice
+// connections recovered, to each guild's audit channel. Guilds without an audit channel
+// are skipped. A nil *AuditLog records nothing, so components can use it unconditionally.
+type AuditLog struct {
+	configService ConfigService
+	messenger     AuditMessenger
+	localizer     *Localizer
+	logger        *log.Logger
+}
+
+// NewAuditLog creates an audit log that posts through messenger
+func NewAuditLog(configService ConfigService, messenger AuditMessenger, logger *log.Logger) *AuditLog {
+	return &AuditLog{
+		configService: configService,
+		messenger:     messenger,
+		logger:        logger,
+	}
+}
+
+// SetLocalizer sets the localizer used to write entries in each guild's language
+func (a *AuditLog) SetLocalizer(localizer *Localizer) {
+	a.localizer = localizer
+}
+
+// Channel returns the audit channel of a guild, or "" when auditing is off
+func (a *AuditLog) Channel(guildID string) string {
+	if a == nil {
+		return ""
+	}
+
+	config, err := a.configService.GetGuildConfig(guildID)
+	if err != nil || config == nil {
+		return ""
+	}
+	return config.AuditChannelID
+}
+
+// SetChannel sets the audit channel of a guild. An empty channel ID turns auditing off.
+func (a *AuditLog) SetChannel(guildID, channelID string) error {
+	config, err := a.configService.GetGuildConfig(guildID)
+	if err != nil {
+		return fmt.Errorf("failed to get guild config: %w", err)
+	}
+	if config == nil {
+		defaultConfig := DefaultGuildTTSConfig(guildID)
+		config = &defaultConfig
+	}
+
+	updated := *config
+	updated.AuditChannelID = channelID
+	return a.configService.SetGuildConfig(guildID, &updated)
+}
+
+// RecordJoin records a user pairing the bot with a voice and a text channel
+func (a *AuditLog) RecordJoin(guildID, userID, voiceChannelID, textChannelID string) {
+	a.record(guildID, "audit.join.title", auditColorJoin, userID,
+		auditField{"audit.field.voice_channel", channelMention(voiceChannelID)},
+		auditField{"audit.field.text_channel", channelMention(textChannelID)},
+	)
+}
+
+// RecordLeave records a user disconnecting the bot from a voice channel
+func (a *AuditLog) RecordLeave(guildID, userID, voiceChannelID string) {
+	a.record(guildID, "audit.leave.title", auditColorLeave, userID,
+		auditField{"audit.field.voice_channel", channelMention(voiceChannelID)},
+	)
+}
+
+// RecordConfigChange records a configuration command and the response it got
+func (a *AuditLog) RecordConfigChange(guildID, userID, command, result string) {
+	a.record(guildID, "audit.config.title", auditColorChange, userID,
+		auditField{"audit.field.command", command},
+		auditField{"audit.field.result", result},
+	)
+}
+
+// RecordQueueClear records a user removing every queued message
+func (a *AuditLog) RecordQueueClear(guildID, userID string, cleared int) {
+	a.record(guildID, "audit.queue_clear.title", auditColorChange, userID,
+		auditField{"audit.field.messages", fmt.Sprint(cleared)},
+	)
+}
+
+// RecordRecovery records the outcome of reconnecting a lost voice connection. err is nil
+// when the connection was recovered.
+func (a *AuditLog) RecordRecovery(guildID string, attempts int, err error) {
+	attemptsField := auditField{"audit.field.attempts", fmt.Sprint(attempts)}
+	if err != nil {
+		a.record(guildID, "audit.recovery_failed.title", auditColorFailure, "", attemptsField, auditField{"audit.field.error", err.Error()})
+		return
+	}
+	a.record(guildID, "audit.recovery.title", auditColorRecovery, "", attemptsField)
+}
+
+// RecordPremiumBudgetExhausted records a guild's premium voices falling back to the
+// Standard tier for the rest of the day
+func (a *AuditLog) RecordPremiumBudgetExhausted(guildID string, budget int) {
+	a.record(guildID, "audit.premium_budget.title", auditColorWarning, "",
+		auditField{"audit.field.budget", fmt.Sprint(budget)},
+	)
+}
+
+// Broadcast posts a notice from one of the bot's owners to the audit channel of each
+// guild and returns how many guilds it was posted to
+func (a *AuditLog) Broadcast(guildIDs []string, userID, text string) int {
+	posted := 0
+	for _, guildID := range guildIDs {
+		if a.record(guildID, "audit.broadcast.title", auditColorNotice, userID, auditField{"audit.field.message", text}) {
+			posted++
+		}
+	}
+	return posted
+}
+
+// record posts an entry to the guild's audit channel and reports whether it was posted.
+// Entries without a user were started by the bot itself.
+func (a *AuditLog) record(guildID, titleKey string, color int, userID string, fields ...auditField) bool {
+	channelID := a.Channel(guildID)
+	if channelID == "" {
+		return false
+	}
+
+	actor := a.localizer.T(guildID, "audit.automatic")
+	if userID != "" {
+		actor = "<@" + userID + ">"
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:     a.localizer.T(guildID, titleKey),
+		Color:     color,
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+	}
+	for _, field := range append([]auditField{{"audit.field.user", actor}}, fields...) {
+		value := field.value
+		if len(value) > auditFieldLimit {
+			value = cutText(value, auditFieldLimit)
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: a.localizer.T(guildID, field.nameKey), Value: value, Inline: true})
+	}
+
+	if _, err := a.messenger.ChannelMessageSendEmbed(channelID, embed); err != nil {
+		a.logger.Printf("Failed to post audit entry to channel %s in guild %s: %v", channelID, guildID, err)
+		return false
+	}
+	return true
+}
+
+// channelMention formats a channel ID so Discord shows the channel's name
+func channelMention(channelID string) string {
+	return "<#" + channelID + ">"
+}
+
+// commandLine writes a slash command interaction the way it was typed, such as
+// "/darrot-config queue setting:max-size value:20"
+func commandLine(i *discordgo.InteractionCreate) string {
+	data := i.ApplicationCommandData()
+	parts := []string{"/" + data.Name}
+
+	var addOptions func(options []*discordgo.ApplicationCommandInteractionDataOption)
+	addOptions = func(options []*discordgo.ApplicationCommandInteractionDataOption) {
+		for _, option := range options {
+			switch option.Type {
+			case discordgo.ApplicationCommandOptionSubCommand, discordgo.ApplicationCommandOptionSubCommandGroup:
+				parts = append(parts, option.Name)
+				addOptions(option.Options)
+			case discordgo.ApplicationCommandOptionChannel:
+				parts = append(parts, option.Name+":"+channelMention(fmt.Sprint(option.Value)))
+			case discordgo.ApplicationCommandOptionRole:
+				parts = append(parts, fmt.Sprintf("%s:<@&%v>", option.Name, option.Value))
+			case discordgo.ApplicationCommandOptionUser:
+				parts = append(parts, fmt.Sprintf("%s:<@%v>", option.Name, option.Value))
+			default:
+				parts = append(parts, fmt.Sprintf("%s:%v", option.Name, option.Value))
+			}
+		}
+	}
+	addOptions(data.Options)
+
+	return strings.Join(parts, " ")
+}
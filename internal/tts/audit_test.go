@@ -0,0 +1,160 @@
+package tts
+
+import (
+	"errors"
+	"io"
+	"log"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingAuditMessenger keeps the embeds posted to each channel
+type recordingAuditMessenger struct {
+	channels []string
+	embeds   []*discordgo.MessageEmbed
+}
+
+func (m *recordingAuditMessenger) ChannelMessageSendEmbed(channelID string, embed *discordgo.MessageEmbed, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+	m.channels = append(m.channels, channelID)
+	m.embeds = append(m.embeds, embed)
+	return &discordgo.Message{}, nil
+}
+
+func newTestAuditLog(t *testing.T) (*AuditLog, *recordingAuditMessenger) {
+	t.Helper()
+
+	messenger := &recordingAuditMessenger{}
+	return NewAuditLog(createTestExportConfigService(t), messenger, log.New(io.Discard, "", 0)), messenger
+}
+
+// configInteraction builds a /darrot-config interaction for a subcommand and its options
+func configInteraction(subcommand string, options ...*discordgo.ApplicationCommandInteractionDataOption) *discordgo.InteractionCreate {
+	return &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+		Type:    discordgo.InteractionApplicationCommand,
+		GuildID: "guild1",
+		Member:  &discordgo.Member{User: &discordgo.User{ID: "user1"}},
+		Data: discordgo.ApplicationCommandInteractionData{
+			Name: "darrot-config",
+			Options: []*discordgo.ApplicationCommandInteractionDataOption{{
+				Type:    discordgo.ApplicationCommandOptionSubCommand,
+				Name:    subcommand,
+				Options: options,
+			}},
+		},
+	}}
+}
+
+func TestAuditLog_SkipsGuildsWithoutChannel(t *testing.T) {
+	auditLog, messenger := newTestAuditLog(t)
+
+	auditLog.RecordJoin("guild1", "user1", "voice1", "text1")
+	assert.Empty(t, messenger.embeds)
+
+	// A nil audit log records nothing
+	var disabled *AuditLog
+	disabled.RecordLeave("guild1", "user1", "voice1")
+	assert.Equal(t, "", disabled.Channel("guild1"))
+}
+
+func TestAuditLog_RecordJoin(t *testing.T) {
+	auditLog, messenger := newTestAuditLog(t)
+	require.NoError(t, auditLog.SetChannel("guild1", "audit1"))
+	assert.Equal(t, "audit1", auditLog.Channel("guild1"))
+
+	auditLog.RecordJoin("guild1", "user1", "voice1", "text1")
+
+	require.Len(t, messenger.embeds, 1)
+	assert.Equal(t, []string{"audit1"}, messenger.channels)
+
+	embed := messenger.embeds[0]
+	assert.Equal(t, "🔊 Joined voice channel", embed.Title)
+	assert.NotEmpty(t, embed.Timestamp)
+	require.Len(t, embed.Fields, 3)
+	assert.Equal(t, "<@user1>", embed.Fields[0].Value)
+	assert.Equal(t, "<#voice1>", embed.Fields[1].Value)
+	assert.Equal(t, "<#text1>", embed.Fields[2].Value)
+
+	// Turning auditing off stops further entries
+	require.NoError(t, auditLog.SetChannel("guild1", ""))
+	auditLog.RecordLeave("guild1", "user1", "voice1")
+	assert.Len(t, messenger.embeds, 1)
+}
+
+func TestAuditLog_RecordRecovery(t *testing.T) {
+	auditLog, messenger := newTestAuditLog(t)
+	require.NoError(t, auditLog.SetChannel("guild1", "audit1"))
+
+	auditLog.RecordRecovery("guild1", 2, nil)
+	auditLog.RecordRecovery("guild1", 3, errors.New("voice server unreachable"))
+
+	require.Len(t, messenger.embeds, 2)
+	assert.Equal(t, "🔁 Voice connection recovered", messenger.embeds[0].Title)
+	assert.Equal(t, "Automatic", messenger.embeds[0].Fields[0].Value)
+	assert.Equal(t, "2", messenger.embeds[0].Fields[1].Value)
+
+	failed := messenger.embeds[1]
+	assert.Equal(t, auditColorFailure, failed.Color)
+	require.Len(t, failed.Fields, 3)
+	assert.Equal(t, "voice server unreachable", failed.Fields[2].Value)
+}
+
+func TestAuditLog_Broadcast(t *testing.T) {
+	auditLog, messenger := newTestAuditLog(t)
+	require.NoError(t, auditLog.SetChannel("guild1", "audit1"))
+	require.NoError(t, auditLog.SetChannel("guild3", "audit3"))
+
+	posted := auditLog.Broadcast([]string{"guild1", "guild2", "guild3"}, "owner1", "Maintenance at 22:00 UTC")
+
+	assert.Equal(t, 2, posted, "guilds without an audit channel are skipped")
+	assert.Equal(t, []string{"audit1", "audit3"}, messenger.channels)
+	embed := messenger.embeds[0]
+	assert.Equal(t, "📣 Notice from the bot's operator", embed.Title)
+	require.Len(t, embed.Fields, 2)
+	assert.Equal(t, "<@owner1>", embed.Fields[0].Value)
+	assert.Equal(t, "Maintenance at 22:00 UTC", embed.Fields[1].Value)
+
+	var disabled *AuditLog
+	assert.Zero(t, disabled.Broadcast([]string{"guild1"}, "owner1", "hello"))
+}
+
+func TestCommandLine(t *testing.T) {
+	interaction := configInteraction("audit",
+		&discordgo.ApplicationCommandInteractionDataOption{Type: discordgo.ApplicationCommandOptionString, Name: "action", Value: "set"},
+		&discordgo.ApplicationCommandInteractionDataOption{Type: discordgo.ApplicationCommandOptionChannel, Name: "channel", Value: "audit1"},
+	)
+	assert.Equal(t, "/darrot-config audit action:set channel:<#audit1>", commandLine(interaction))
+
+	interaction = configInteraction("queue",
+		&discordgo.ApplicationCommandInteractionDataOption{Type: discordgo.ApplicationCommandOptionString, Name: "setting", Value: "max-size"},
+		&discordgo.ApplicationCommandInteractionDataOption{Type: discordgo.ApplicationCommandOptionInteger, Name: "value", Value: float64(20)},
+	)
+	assert.Equal(t, "/darrot-config queue setting:max-size value:20", commandLine(interaction))
+}
+
+func TestConfigCommandHandler_RecordsConfigChanges(t *testing.T) {
+	handler, _, _, _, _ := createTestConfigHandler()
+	auditLog, messenger := newTestAuditLog(t)
+	require.NoError(t, auditLog.SetChannel("guild1", "audit1"))
+	handler.SetAuditLog(auditLog)
+
+	setting := func(value string) *discordgo.ApplicationCommandInteractionDataOption {
+		return &discordgo.ApplicationCommandInteractionDataOption{Type: discordgo.ApplicationCommandOptionString, Name: "setting", Value: value}
+	}
+
+	// Showing settings changes nothing
+	handler.recordConfigChange(configInteraction("show"), "settings")
+	handler.recordConfigChange(configInteraction("queue", setting("show")), "queue settings")
+	assert.Empty(t, messenger.embeds)
+
+	handler.recordConfigChange(configInteraction("queue", setting("truncation")), "✅ Long messages are split")
+
+	require.Len(t, messenger.embeds, 1)
+	embed := messenger.embeds[0]
+	assert.Equal(t, "⚙️ Configuration changed", embed.Title)
+	assert.Equal(t, "<@user1>", embed.Fields[0].Value)
+	assert.Equal(t, "/darrot-config queue setting:truncation", embed.Fields[1].Value)
+	assert.Equal(t, "✅ Long messages are split", embed.Fields[2].Value)
+}
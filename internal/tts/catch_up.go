@@ -0,0 +1,158 @@
+package tts
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// CatchUpPolicy selects what happens to messages that reach the bot long after they were
+// sent, such as the backlog Discord delivers when the bot reconnects
+type CatchUpPolicy string
+
+// Catch-up policies
+const (
+	CatchUpRead    CatchUpPolicy = "read"    // Read every message, however old
+	CatchUpSkip    CatchUpPolicy = "skip"    // Drop messages older than the cutoff
+	CatchUpSummary CatchUpPolicy = "summary" // Drop them and say how many were missed
+)
+
+// Message age cutoff limits and timing
+const (
+	DefaultMessageAgeCutoff = 60   // Seconds after which a late message is caught up on
+	MinMessageAgeCutoff     = 10   // Shortest configurable cutoff in seconds
+	MaxMessageAgeCutoff     = 3600 // Longest configurable cutoff in seconds
+
+	// CatchUpQuietDelay is how long no late messages must arrive before the summary of
+	// those missed is spoken, unless a new message ends the backlog first
+	CatchUpQuietDelay = 3 * time.Second
+)
+
+// CatchUpPolicyFor returns the catch-up policy of a guild configuration, reading every
+// message when it is unset
+func CatchUpPolicyFor(config *GuildTTSConfig) CatchUpPolicy {
+	if config == nil || config.CatchUpPolicy == "" {
+		return CatchUpRead
+	}
+	return config.CatchUpPolicy
+}
+
+// MessageAgeCutoffFor returns how old a message may be when it reaches the bot before the
+// guild's catch-up policy applies, filling in the default when it is unset
+func MessageAgeCutoffFor(config *GuildTTSConfig) time.Duration {
+	if config == nil || config.MessageAgeCutoff <= 0 {
+		return DefaultMessageAgeCutoff * time.Second
+	}
+	return time.Duration(config.MessageAgeCutoff) * time.Second
+}
+
+// catchUp drops messages that are older than a guild's cutoff when they reach the bot and,
+// for guilds that want it, queues a spoken summary of how many were missed once the
+// backlog has been delivered
+type catchUp struct {
+	messageQueue MessageQueue
+	localizer    *Localizer
+	logger       *log.Logger
+	quietDelay   time.Duration
+	now          func() time.Time
+
+	mu      sync.Mutex
+	missed  map[string]*missedMessages // Late messages waiting to be summarized per guild
+	stopped bool
+}
+
+// missedMessages are the late messages dropped in a guild since the last summary
+type missedMessages struct {
+	channelID string
+	count     int
+	timer     *time.Timer
+}
+
+// newCatchUp creates the catch-up policy of a message monitor
+func newCatchUp(messageQueue MessageQueue, logger *log.Logger) *catchUp {
+	return &catchUp{
+		messageQueue: messageQueue,
+		logger:       logger,
+		quietDelay:   CatchUpQuietDelay,
+		now:          time.Now,
+		missed:       make(map[string]*missedMessages),
+	}
+}
+
+// admit reports whether a message should be read under the guild's policy, judging its
+// age by its timestamp. A message young enough to read ends the backlog, so the summary
+// of the messages missed before it is queued first.
+func (c *catchUp) admit(message *QueuedMessage, policy CatchUpPolicy, cutoff time.Duration) bool {
+	if policy == CatchUpRead || c.now().Sub(message.Timestamp) <= cutoff {
+		c.flush(message.GuildID)
+		return true
+	}
+
+	c.logger.Printf("Message %s in guild %s was sent %s ago, past the %s catch-up cutoff, dropping message",
+		message.ID, message.GuildID, c.now().Sub(message.Timestamp).Round(time.Second), cutoff)
+	if policy != CatchUpSummary {
+		return false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.stopped {
+		return false
+	}
+	missed, exists := c.missed[message.GuildID]
+	if !exists {
+		guildID := message.GuildID
+		missed = &missedMessages{}
+		missed.timer = time.AfterFunc(c.quietDelay, func() { c.flush(guildID) })
+		c.missed[guildID] = missed
+	} else {
+		missed.timer.Reset(c.quietDelay)
+	}
+	missed.channelID = message.ChannelID
+	missed.count++
+	return false
+}
+
+// flush queues the summary of the late messages dropped in a guild
+func (c *catchUp) flush(guildID string) {
+	c.mu.Lock()
+	missed, exists := c.missed[guildID]
+	if exists {
+		missed.timer.Stop()
+		delete(c.missed, guildID)
+	}
+	c.mu.Unlock()
+	if !exists || missed.count == 0 {
+		return
+	}
+
+	// The summary is spoken, where "1 message(s)" would sound odd
+	summary := c.localizer.T(guildID, "catch_up.summary", missed.count)
+	if missed.count == 1 {
+		summary = c.localizer.T(guildID, "catch_up.summary_one")
+	}
+
+	message := &QueuedMessage{
+		ID:        fmt.Sprintf("catch-up-%s-%d", guildID, c.now().UnixNano()),
+		GuildID:   guildID,
+		ChannelID: missed.channelID,
+		Username:  "catch-up",
+		Content:   summary,
+		Timestamp: c.now(),
+	}
+	if err := c.messageQueue.Enqueue(message); err != nil {
+		c.logger.Printf("Failed to queue catch-up summary for guild %s: %v", guildID, err)
+	}
+}
+
+// stop drops the late messages that were not summarized yet
+func (c *catchUp) stop() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stopped = true
+	for guildID, missed := range c.missed {
+		missed.timer.Stop()
+		delete(c.missed, guildID)
+	}
+}
@@ -0,0 +1,92 @@
+package tts
+
+import (
+	"io"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCatchUp(t *testing.T, now time.Time) (*catchUp, *mockMessageQueue) {
+	t.Helper()
+
+	queue := newMockMessageQueue()
+	c := newCatchUp(queue, log.New(io.Discard, "", 0))
+	c.quietDelay = time.Hour // Tests flush explicitly unless they test the delay
+	c.now = func() time.Time { return now }
+	t.Cleanup(c.stop)
+	return c, queue
+}
+
+func lateMessage(id string, sentAt time.Time) *QueuedMessage {
+	return &QueuedMessage{ID: id, GuildID: "guild1", ChannelID: "text1", Timestamp: sentAt}
+}
+
+func TestCatchUpPolicyFor(t *testing.T) {
+	assert.Equal(t, CatchUpRead, CatchUpPolicyFor(nil))
+	assert.Equal(t, CatchUpRead, CatchUpPolicyFor(&GuildTTSConfig{}))
+	assert.Equal(t, CatchUpSummary, CatchUpPolicyFor(&GuildTTSConfig{CatchUpPolicy: CatchUpSummary}))
+
+	assert.Equal(t, DefaultMessageAgeCutoff*time.Second, MessageAgeCutoffFor(nil))
+	assert.Equal(t, 5*time.Minute, MessageAgeCutoffFor(&GuildTTSConfig{MessageAgeCutoff: 300}))
+}
+
+func TestCatchUp_Read(t *testing.T) {
+	now := time.Now()
+	c, queue := newTestCatchUp(t, now)
+
+	assert.True(t, c.admit(lateMessage("msg1", now.Add(-time.Hour)), CatchUpRead, time.Minute))
+	assert.Empty(t, queue.getMessages())
+}
+
+func TestCatchUp_Skip(t *testing.T) {
+	now := time.Now()
+	c, queue := newTestCatchUp(t, now)
+
+	assert.False(t, c.admit(lateMessage("msg1", now.Add(-2*time.Minute)), CatchUpSkip, time.Minute))
+	assert.True(t, c.admit(lateMessage("msg2", now.Add(-30*time.Second)), CatchUpSkip, time.Minute))
+	assert.Empty(t, queue.getMessages(), "skipped messages are not summarized")
+}
+
+func TestCatchUp_Summary(t *testing.T) {
+	now := time.Now()
+	c, queue := newTestCatchUp(t, now)
+
+	for _, id := range []string{"msg1", "msg2", "msg3"} {
+		assert.False(t, c.admit(lateMessage(id, now.Add(-10*time.Minute)), CatchUpSummary, time.Minute))
+	}
+	assert.Empty(t, queue.getMessages(), "the summary waits for the end of the backlog")
+
+	assert.True(t, c.admit(lateMessage("msg4", now), CatchUpSummary, time.Minute))
+	messages := queue.getMessages()
+	require.Len(t, messages, 1, "a current message ends the backlog")
+	assert.Equal(t, "3 messages were sent while I was away.", messages[0].Content)
+	assert.Equal(t, "text1", messages[0].ChannelID)
+
+	assert.True(t, c.admit(lateMessage("msg5", now), CatchUpSummary, time.Minute))
+	assert.Len(t, queue.getMessages(), 1, "the summary is spoken once")
+}
+
+func TestCatchUp_SummaryAfterQuietDelay(t *testing.T) {
+	now := time.Now()
+	c, queue := newTestCatchUp(t, now)
+	c.quietDelay = 10 * time.Millisecond
+
+	assert.False(t, c.admit(lateMessage("msg1", now.Add(-10*time.Minute)), CatchUpSummary, time.Minute))
+	messages := queue.waitForMessages(t, 1)
+	assert.Equal(t, "A message was sent while I was away.", messages[0].Content)
+}
+
+func TestCatchUp_StopDropsPendingSummary(t *testing.T) {
+	now := time.Now()
+	c, queue := newTestCatchUp(t, now)
+
+	assert.False(t, c.admit(lateMessage("msg1", now.Add(-10*time.Minute)), CatchUpSummary, time.Minute))
+	c.stop()
+	assert.False(t, c.admit(lateMessage("msg2", now.Add(-10*time.Minute)), CatchUpSummary, time.Minute))
+	c.flush("guild1")
+	assert.Empty(t, queue.getMessages())
+}
@@ -2,16 +2,41 @@ package tts
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
 )
 
+// Channel types the bot can join and read messages from
+var (
+	voiceChannelTypes = []discordgo.ChannelType{discordgo.ChannelTypeGuildVoice, discordgo.ChannelTypeGuildStageVoice}
+	textChannelTypes  = []discordgo.ChannelType{discordgo.ChannelTypeGuildText, discordgo.ChannelTypeGuildNews}
+)
+
+// IsVoiceChannelType reports whether the bot can join a channel of this type
+func IsVoiceChannelType(channelType discordgo.ChannelType) bool {
+	return slices.Contains(voiceChannelTypes, channelType)
+}
+
+// IsTextChannelType reports whether the bot can read messages from a channel of this type
+func IsTextChannelType(channelType discordgo.ChannelType) bool {
+	return slices.Contains(textChannelTypes, channelType)
+}
+
+// IsVoiceChatPairing reports whether a pairing reads the built-in text chat of its voice
+// channel instead of a separate text channel
+func IsVoiceChatPairing(voiceChannelID, textChannelID string) bool {
+	return voiceChannelID == textChannelID
+}
+
 // ChannelServiceImpl implements the ChannelService interface
 type ChannelServiceImpl struct {
 	storage           *StorageService
 	session           DiscordSession
 	permissionService PermissionService
+	configService     ConfigService
 }
 
 // NewChannelService creates a new channel service instance
@@ -23,6 +48,12 @@ func NewChannelService(storage *StorageService, session DiscordSession, permissi
 	}
 }
 
+// SetConfigService sets the config service used to check whether a guild allows pairing
+// with age-restricted text channels. Without it such channels are always refused.
+func (c *ChannelServiceImpl) SetConfigService(configService ConfigService) {
+	c.configService = configService
+}
+
 // CreatePairing creates a new voice-text channel pairing
 func (c *ChannelServiceImpl) CreatePairing(guildID, voiceChannelID, textChannelID string) error {
 	return c.CreatePairingWithCreator(guildID, voiceChannelID, textChannelID, "")
@@ -57,16 +88,21 @@ func (c *ChannelServiceImpl) CreatePairingWithCreator(guildID, voiceChannelID, t
 	if err != nil {
 		return fmt.Errorf("failed to get voice channel: %w", err)
 	}
-	if voiceChannel.Type != discordgo.ChannelTypeGuildVoice {
+	if !IsVoiceChannelType(voiceChannel.Type) {
 		return fmt.Errorf("channel %s is not a voice channel", voiceChannelID)
 	}
 
-	textChannel, err := c.session.Channel(textChannelID)
-	if err != nil {
-		return fmt.Errorf("failed to get text channel: %w", err)
-	}
-	if textChannel.Type != discordgo.ChannelTypeGuildText {
-		return fmt.Errorf("channel %s is not a text channel", textChannelID)
+	// The built-in text chat of a voice channel shares the voice channel's ID, so a voice
+	// channel is only read as a text channel when it is paired with itself
+	textChannel := voiceChannel
+	if !IsVoiceChatPairing(voiceChannelID, textChannelID) {
+		textChannel, err = c.session.Channel(textChannelID)
+		if err != nil {
+			return fmt.Errorf("failed to get text channel: %w", err)
+		}
+		if !IsTextChannelType(textChannel.Type) {
+			return fmt.Errorf("channel %s is not a text channel", textChannelID)
+		}
 	}
 
 	// Verify channels are in the same guild
@@ -74,6 +110,11 @@ func (c *ChannelServiceImpl) CreatePairingWithCreator(guildID, voiceChannelID, t
 		return fmt.Errorf("channels must be in the specified guild")
 	}
 
+	// Age-restricted channels are only read when an administrator allowed it
+	if textChannel.NSFW && !c.allowsNSFW(guildID) {
+		return fmt.Errorf("%w: %s", ErrNSFWChannel, textChannelID)
+	}
+
 	// Create the pairing
 	pairing := ChannelPairingStorage{
 		GuildID:        guildID,
@@ -87,6 +128,15 @@ func (c *ChannelServiceImpl) CreatePairingWithCreator(guildID, voiceChannelID, t
 	return c.storage.SaveChannelPairing(pairing)
 }
 
+// allowsNSFW reports whether a guild allows pairing with age-restricted text channels
+func (c *ChannelServiceImpl) allowsNSFW(guildID string) bool {
+	if c.configService == nil {
+		return false
+	}
+	config, err := c.configService.GetGuildConfig(guildID)
+	return err == nil && config != nil && config.AllowNSFWChannels
+}
+
 // RemovePairing removes a voice-text channel pairing
 func (c *ChannelServiceImpl) RemovePairing(guildID, voiceChannelID string) error {
 	if guildID == "" {
@@ -132,11 +182,16 @@ func (c *ChannelServiceImpl) GetPairing(guildID, voiceChannelID string) (*Channe
 
 	// Convert storage format to interface format
 	pairing := &ChannelPairing{
-		GuildID:        storagePairing.GuildID,
-		VoiceChannelID: storagePairing.VoiceChannelID,
-		TextChannelID:  storagePairing.TextChannelID,
-		CreatedBy:      storagePairing.CreatedBy,
-		CreatedAt:      storagePairing.CreatedAt,
+		GuildID:         storagePairing.GuildID,
+		VoiceChannelID:  storagePairing.VoiceChannelID,
+		TextChannelID:   storagePairing.TextChannelID,
+		CreatedBy:       storagePairing.CreatedBy,
+		CreatedAt:       storagePairing.CreatedAt,
+		RequirePresence: storagePairing.RequirePresence,
+		Greeting:        storagePairing.Greeting,
+		ReadPinned:      storagePairing.ReadPinned,
+		MirrorChannelID: storagePairing.MirrorChannelID,
+		MirrorOnly:      storagePairing.MirrorOnly,
 	}
 
 	return pairing, nil
@@ -186,6 +241,86 @@ func (c *ChannelServiceImpl) IsChannelPaired(guildID, textChannelID string) bool
 	return false
 }
 
+// SetRequirePresence turns whisper mode on or off for a pairing. In whisper mode messages
+// from the text channel are only read while their author is in the paired voice channel.
+func (c *ChannelServiceImpl) SetRequirePresence(guildID, voiceChannelID string, required bool) error {
+	if guildID == "" {
+		return fmt.Errorf("guild ID is required")
+	}
+	if voiceChannelID == "" {
+		return fmt.Errorf("voice channel ID is required")
+	}
+
+	pairing, err := c.storage.LoadChannelPairing(guildID, voiceChannelID)
+	if err != nil {
+		return fmt.Errorf("channel pairing not found: %w", err)
+	}
+
+	pairing.RequirePresence = required
+	return c.storage.SaveChannelPairing(*pairing)
+}
+
+// SetGreeting sets what is spoken when the bot joins the voice channel of a pairing: a
+// welcome text, the most recent pinned message of the text channel, both or neither
+func (c *ChannelServiceImpl) SetGreeting(guildID, voiceChannelID, greeting string, readPinned bool) error {
+	if guildID == "" {
+		return fmt.Errorf("guild ID is required")
+	}
+	if voiceChannelID == "" {
+		return fmt.Errorf("voice channel ID is required")
+	}
+
+	pairing, err := c.storage.LoadChannelPairing(guildID, voiceChannelID)
+	if err != nil {
+		return fmt.Errorf("channel pairing not found: %w", err)
+	}
+
+	pairing.Greeting = strings.TrimSpace(greeting)
+	pairing.ReadPinned = readPinned
+	return c.storage.SaveChannelPairing(*pairing)
+}
+
+// SetMirror sets the text channel that shows what is read in the voice channel of a
+// pairing. With mirrorOnly messages are only posted there, not spoken. An empty channel
+// ID turns mirroring off.
+func (c *ChannelServiceImpl) SetMirror(guildID, voiceChannelID, mirrorChannelID string, mirrorOnly bool) error {
+	if guildID == "" {
+		return fmt.Errorf("guild ID is required")
+	}
+	if voiceChannelID == "" {
+		return fmt.Errorf("voice channel ID is required")
+	}
+
+	pairing, err := c.storage.LoadChannelPairing(guildID, voiceChannelID)
+	if err != nil {
+		return fmt.Errorf("channel pairing not found: %w", err)
+	}
+
+	pairing.MirrorChannelID = mirrorChannelID
+	pairing.MirrorOnly = mirrorOnly && mirrorChannelID != ""
+	return c.storage.SaveChannelPairing(*pairing)
+}
+
+// RequiresPresence checks if the active pairing of a text channel is in whisper mode
+func (c *ChannelServiceImpl) RequiresPresence(guildID, textChannelID string) bool {
+	if guildID == "" || textChannelID == "" {
+		return false
+	}
+
+	pairings, err := c.storage.ListGuildPairings(guildID)
+	if err != nil {
+		return false
+	}
+
+	for _, pairing := range pairings {
+		if pairing.IsActive && pairing.TextChannelID == textChannelID {
+			return pairing.RequirePresence
+		}
+	}
+
+	return false
+}
+
 // SetPairingCreator sets the creator of a channel pairing (used when creating pairings)
 func (c *ChannelServiceImpl) SetPairingCreator(guildID, voiceChannelID, creatorID string) error {
 	if guildID == "" {
@@ -226,11 +361,16 @@ func (c *ChannelServiceImpl) ListGuildPairings(guildID string) ([]*ChannelPairin
 	for _, sp := range storagePairings {
 		if sp.IsActive {
 			pairing := &ChannelPairing{
-				GuildID:        sp.GuildID,
-				VoiceChannelID: sp.VoiceChannelID,
-				TextChannelID:  sp.TextChannelID,
-				CreatedBy:      sp.CreatedBy,
-				CreatedAt:      sp.CreatedAt,
+				GuildID:         sp.GuildID,
+				VoiceChannelID:  sp.VoiceChannelID,
+				TextChannelID:   sp.TextChannelID,
+				CreatedBy:       sp.CreatedBy,
+				CreatedAt:       sp.CreatedAt,
+				RequirePresence: sp.RequirePresence,
+				Greeting:        sp.Greeting,
+				ReadPinned:      sp.ReadPinned,
+				MirrorChannelID: sp.MirrorChannelID,
+				MirrorOnly:      sp.MirrorOnly,
 			}
 			pairings = append(pairings, pairing)
 		}
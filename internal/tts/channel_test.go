@@ -26,6 +26,11 @@ func (m *MockChannelPermissionService) CanControlBot(userID, guildID string) (bo
 	return args.Bool(0), args.Error(1)
 }
 
+func (m *MockChannelPermissionService) CanBeRead(userID, guildID string) (bool, error) {
+	args := m.Called(userID, guildID)
+	return args.Bool(0), args.Error(1)
+}
+
 func (m *MockChannelPermissionService) HasChannelAccess(userID, channelID string) (bool, error) {
 	args := m.Called(userID, channelID)
 	return args.Bool(0), args.Error(1)
@@ -295,6 +300,73 @@ func TestCreatePairing_InvalidChannelTypes(t *testing.T) {
 	}
 }
 
+func TestCreatePairing_StageAndAnnouncementChannels(t *testing.T) {
+	channelService, _, mockSession, _, tempDir := setupChannelServiceTest(t)
+	defer cleanupChannelServiceTest(tempDir)
+
+	guildID := "guild123"
+	mockSession.AddChannel(&discordgo.Channel{ID: "stage456", GuildID: guildID, Type: discordgo.ChannelTypeGuildStageVoice})
+	mockSession.AddChannel(&discordgo.Channel{ID: "news789", GuildID: guildID, Type: discordgo.ChannelTypeGuildNews})
+
+	err := channelService.CreatePairing(guildID, "stage456", "news789")
+	assert.NoError(t, err)
+
+	pairing, err := channelService.GetPairing(guildID, "stage456")
+	assert.NoError(t, err)
+	assert.Equal(t, "news789", pairing.TextChannelID)
+}
+
+func TestCreatePairing_VoiceChannelChat(t *testing.T) {
+	channelService, _, mockSession, _, tempDir := setupChannelServiceTest(t)
+	defer cleanupChannelServiceTest(tempDir)
+
+	guildID := "guild123"
+	mockSession.AddChannel(&discordgo.Channel{ID: "voice456", GuildID: guildID, Type: discordgo.ChannelTypeGuildVoice})
+	mockSession.AddChannel(&discordgo.Channel{ID: "stage789", GuildID: guildID, Type: discordgo.ChannelTypeGuildStageVoice})
+
+	// A voice channel can be paired with its own built-in text chat
+	err := channelService.CreatePairing(guildID, "voice456", "voice456")
+	assert.NoError(t, err)
+
+	pairing, err := channelService.GetPairing(guildID, "voice456")
+	assert.NoError(t, err)
+	assert.Equal(t, "voice456", pairing.TextChannelID)
+	assert.True(t, channelService.IsChannelPaired(guildID, "voice456"))
+
+	// But not with the chat of another voice channel
+	err = channelService.CreatePairing(guildID, "stage789", "voice456")
+	assert.Error(t, err)
+}
+
+func TestCreatePairing_NSFWChannels(t *testing.T) {
+	channelService, _, mockSession, _, tempDir := setupChannelServiceTest(t)
+	defer cleanupChannelServiceTest(tempDir)
+
+	guildID := "guild123"
+	mockSession.AddChannel(&discordgo.Channel{ID: "voice456", GuildID: guildID, Type: discordgo.ChannelTypeGuildVoice})
+	mockSession.AddChannel(&discordgo.Channel{ID: "nsfw789", GuildID: guildID, Type: discordgo.ChannelTypeGuildText, NSFW: true})
+
+	// Refused without a config service
+	err := channelService.CreatePairing(guildID, "voice456", "nsfw789")
+	assert.ErrorIs(t, err, ErrNSFWChannel)
+
+	// Refused while the guild hasn't allowed it
+	configService := &MockConfigService{}
+	configService.On("GetGuildConfig", guildID).Return(&GuildTTSConfig{GuildID: guildID}, nil).Once()
+	channelService.SetConfigService(configService)
+	err = channelService.CreatePairing(guildID, "voice456", "nsfw789")
+	assert.ErrorIs(t, err, ErrNSFWChannel)
+
+	// Allowed once an administrator overrides it
+	configService.On("GetGuildConfig", guildID).Return(&GuildTTSConfig{GuildID: guildID, AllowNSFWChannels: true}, nil).Once()
+	require.NoError(t, channelService.CreatePairing(guildID, "voice456", "nsfw789"))
+
+	pairing, err := channelService.GetPairing(guildID, "voice456")
+	require.NoError(t, err)
+	assert.Equal(t, "nsfw789", pairing.TextChannelID)
+	configService.AssertExpectations(t)
+}
+
 func TestRemovePairing_Success(t *testing.T) {
 	channelService, _, mockSession, _, tempDir := setupChannelServiceTest(t)
 	defer cleanupChannelServiceTest(tempDir)
@@ -579,6 +651,121 @@ func TestIsChannelPaired_InvalidInput(t *testing.T) {
 	assert.False(t, isPaired)
 }
 
+func TestSetRequirePresence(t *testing.T) {
+	channelService, _, mockSession, _, tempDir := setupChannelServiceTest(t)
+	defer cleanupChannelServiceTest(tempDir)
+
+	guildID := "guild123"
+	voiceChannelID := "voice456"
+	textChannelID := "text789"
+
+	mockSession.AddChannel(&discordgo.Channel{ID: voiceChannelID, GuildID: guildID, Type: discordgo.ChannelTypeGuildVoice})
+	mockSession.AddChannel(&discordgo.Channel{ID: textChannelID, GuildID: guildID, Type: discordgo.ChannelTypeGuildText})
+
+	err := channelService.CreatePairing(guildID, voiceChannelID, textChannelID)
+	assert.NoError(t, err)
+
+	// New pairings read every opted-in author
+	assert.False(t, channelService.RequiresPresence(guildID, textChannelID))
+
+	err = channelService.SetRequirePresence(guildID, voiceChannelID, true)
+	assert.NoError(t, err)
+	assert.True(t, channelService.RequiresPresence(guildID, textChannelID))
+
+	pairing, err := channelService.GetPairing(guildID, voiceChannelID)
+	assert.NoError(t, err)
+	assert.True(t, pairing.RequirePresence)
+
+	err = channelService.SetRequirePresence(guildID, voiceChannelID, false)
+	assert.NoError(t, err)
+	assert.False(t, channelService.RequiresPresence(guildID, textChannelID))
+
+	// Unpaired channels and missing pairings
+	assert.False(t, channelService.RequiresPresence(guildID, "other"))
+	assert.Error(t, channelService.SetRequirePresence(guildID, "other", true))
+	assert.Error(t, channelService.SetRequirePresence("", voiceChannelID, true))
+}
+
+func TestSetGreeting(t *testing.T) {
+	channelService, _, mockSession, _, tempDir := setupChannelServiceTest(t)
+	defer cleanupChannelServiceTest(tempDir)
+
+	guildID := "guild123"
+	voiceChannelID := "voice456"
+	textChannelID := "text789"
+
+	mockSession.AddChannel(&discordgo.Channel{ID: voiceChannelID, GuildID: guildID, Type: discordgo.ChannelTypeGuildVoice})
+	mockSession.AddChannel(&discordgo.Channel{ID: textChannelID, GuildID: guildID, Type: discordgo.ChannelTypeGuildText})
+
+	err := channelService.CreatePairing(guildID, voiceChannelID, textChannelID)
+	assert.NoError(t, err)
+
+	err = channelService.SetGreeting(guildID, voiceChannelID, "  Recording night rules: keep it short  ", true)
+	assert.NoError(t, err)
+
+	pairing, err := channelService.GetPairing(guildID, voiceChannelID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Recording night rules: keep it short", pairing.Greeting)
+	assert.True(t, pairing.ReadPinned)
+
+	pairings, err := channelService.ListGuildPairings(guildID)
+	assert.NoError(t, err)
+	assert.Len(t, pairings, 1)
+	assert.Equal(t, "Recording night rules: keep it short", pairings[0].Greeting)
+
+	// Clearing the greeting
+	err = channelService.SetGreeting(guildID, voiceChannelID, "", false)
+	assert.NoError(t, err)
+	pairing, err = channelService.GetPairing(guildID, voiceChannelID)
+	assert.NoError(t, err)
+	assert.Empty(t, pairing.Greeting)
+	assert.False(t, pairing.ReadPinned)
+
+	// Missing pairings
+	assert.Error(t, channelService.SetGreeting(guildID, "other", "hello", false))
+	assert.Error(t, channelService.SetGreeting("", voiceChannelID, "hello", false))
+}
+
+func TestSetMirror(t *testing.T) {
+	channelService, _, mockSession, _, tempDir := setupChannelServiceTest(t)
+	defer cleanupChannelServiceTest(tempDir)
+
+	guildID := "guild123"
+	voiceChannelID := "voice456"
+	textChannelID := "text789"
+
+	mockSession.AddChannel(&discordgo.Channel{ID: voiceChannelID, GuildID: guildID, Type: discordgo.ChannelTypeGuildVoice})
+	mockSession.AddChannel(&discordgo.Channel{ID: textChannelID, GuildID: guildID, Type: discordgo.ChannelTypeGuildText})
+
+	err := channelService.CreatePairing(guildID, voiceChannelID, textChannelID)
+	assert.NoError(t, err)
+
+	err = channelService.SetMirror(guildID, voiceChannelID, "mirror1", true)
+	assert.NoError(t, err)
+
+	pairing, err := channelService.GetPairing(guildID, voiceChannelID)
+	assert.NoError(t, err)
+	assert.Equal(t, "mirror1", pairing.MirrorChannelID)
+	assert.True(t, pairing.MirrorOnly)
+
+	pairings, err := channelService.ListGuildPairings(guildID)
+	assert.NoError(t, err)
+	assert.Len(t, pairings, 1)
+	assert.Equal(t, "mirror1", pairings[0].MirrorChannelID)
+
+	// Turning mirroring off also turns mirror-only mode off
+	err = channelService.SetMirror(guildID, voiceChannelID, "", true)
+	assert.NoError(t, err)
+	pairing, err = channelService.GetPairing(guildID, voiceChannelID)
+	assert.NoError(t, err)
+	assert.Empty(t, pairing.MirrorChannelID)
+	assert.False(t, pairing.MirrorOnly)
+
+	// Missing pairings
+	assert.Error(t, channelService.SetMirror(guildID, "other", "mirror1", false))
+	assert.Error(t, channelService.SetMirror("", voiceChannelID, "mirror1", false))
+}
+
 func TestSetPairingCreator_Success(t *testing.T) {
 	channelService, _, mockSession, _, tempDir := setupChannelServiceTest(t)
 	defer cleanupChannelServiceTest(tempDir)
@@ -757,3 +944,16 @@ func TestChannelService_IntegrationWorkflow(t *testing.T) {
 
 	mockPermissionService.AssertExpectations(t)
 }
+
+func TestChannelTypes(t *testing.T) {
+	assert.True(t, IsVoiceChannelType(discordgo.ChannelTypeGuildVoice))
+	assert.True(t, IsVoiceChannelType(discordgo.ChannelTypeGuildStageVoice))
+	assert.False(t, IsVoiceChannelType(discordgo.ChannelTypeGuildText))
+
+	assert.True(t, IsTextChannelType(discordgo.ChannelTypeGuildText))
+	assert.True(t, IsTextChannelType(discordgo.ChannelTypeGuildNews))
+	assert.False(t, IsTextChannelType(discordgo.ChannelTypeGuildStageVoice))
+
+	assert.True(t, IsVoiceChatPairing("voice1", "voice1"))
+	assert.False(t, IsVoiceChatPairing("voice1", "text1"))
+}
@@ -0,0 +1,447 @@
+package tts
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"darrot/internal/commands/textcmd"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// attachmentDownloadTimeout bounds how long fetching an uploaded attachment may take
+const attachmentDownloadTimeout = 15 * time.Second
+
+// errAttachmentTooLarge is returned when a downloaded attachment exceeds its size limit
+var errAttachmentTooLarge = errors.New("attachment is too large")
+
+// ClipCommandHandler handles administrator audio clip management commands
+type ClipCommandHandler struct {
+	clipService       AudioClipService
+	permissionService PermissionService
+	httpClient        *http.Client
+	localizer         *Localizer
+	logger            *log.Logger
+}
+
+// NewClipCommandHandler creates a new clip management command handler
+func NewClipCommandHandler(
+	clipService AudioClipService,
+	permissionService PermissionService,
+	logger *log.Logger,
+) *ClipCommandHandler {
+	return &ClipCommandHandler{
+		clipService:       clipService,
+		permissionService: permissionService,
+		httpClient:        &http.Client{Timeout: attachmentDownloadTimeout},
+		logger:            logger,
+	}
+}
+
+// SetLocalizer sets the localizer used to translate responses
+func (h *ClipCommandHandler) SetLocalizer(localizer *Localizer) {
+	h.localizer = localizer
+}
+
+// Definition returns the Discord slash command definition for the clip command
+func (h *ClipCommandHandler) Definition() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:                     "darrot-clip",
+		Description:              "Manage audio clips for this server (Administrator only)",
+		DefaultMemberPermissions: adminCommandPermissions(),
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "upload",
+				Description: "Upload a WAV file as a named clip",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "name",
+						Description: "Clip name (letters, digits, '-' or '_')",
+						Required:    true,
+						MaxLength:   32,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionAttachment,
+						Name:        "file",
+						Description: "16-bit PCM WAV file",
+						Required:    true,
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "remove",
+				Description: "Remove a clip",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "name",
+						Description: "Clip name",
+						Required:    true,
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "list",
+				Description: "List clips stored for this server",
+			},
+		},
+	}
+}
+
+// Handle processes the clip command interaction
+func (h *ClipCommandHandler) Handle(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	// Validate guild context
+	if i.GuildID == "" {
+		return h.respondError(s, i, h.localizer.T(i.GuildID, "common.guild_only"))
+	}
+
+	userID := i.Member.User.ID
+	guildID := i.GuildID
+
+	// Validate administrator permissions
+	if err := h.ValidatePermissions(userID, guildID); err != nil {
+		return h.respondError(s, i, h.localizer.T(guildID, "common.permission_denied", err))
+	}
+
+	// Extract subcommand
+	options := i.ApplicationCommandData().Options
+	if len(options) == 0 {
+		return h.respondError(s, i, h.localizer.T(guildID, "common.no_subcommand"))
+	}
+
+	subcommand := options[0]
+	switch subcommand.Name {
+	case "upload":
+		return h.handleUpload(s, i, guildID, userID, subcommand.Options)
+	case "remove":
+		return h.handleRemove(s, i, guildID, subcommand.Options)
+	case "list":
+		return h.handleList(s, i, guildID)
+	default:
+		return h.respondError(s, i, h.localizer.T(guildID, "common.invalid_subcommand"))
+	}
+}
+
+// handleUpload downloads an attached WAV file and stores it as a clip
+func (h *ClipCommandHandler) handleUpload(s *discordgo.Session, i *discordgo.InteractionCreate, guildID, userID string, options []*discordgo.ApplicationCommandInteractionDataOption) error {
+	var name, attachmentID string
+	for _, option := range options {
+		switch option.Name {
+		case "name":
+			name = option.StringValue()
+		case "file":
+			attachmentID, _ = option.Value.(string)
+		}
+	}
+
+	name, err := NormalizeClipName(name)
+	if err != nil {
+		return h.respondError(s, i, h.localizer.T(guildID, "clip.invalid_name", err))
+	}
+
+	resolved := i.ApplicationCommandData().Resolved
+	if resolved == nil || resolved.Attachments[attachmentID] == nil {
+		return h.respondError(s, i, h.localizer.T(guildID, "clip.attach_file"))
+	}
+
+	attachment := resolved.Attachments[attachmentID]
+	if attachment.Size > MaxClipUploadBytes {
+		return h.respondError(s, i, h.localizer.T(guildID, "clip.file_too_large", MaxClipUploadBytes/(1024*1024)))
+	}
+
+	// Downloading and encoding can exceed Discord's response deadline
+	if err := textcmd.Respond(s, i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Flags: discordgo.MessageFlagsEphemeral,
+		},
+	}); err != nil {
+		return err
+	}
+
+	wavData, err := h.download(attachment.URL)
+	if err != nil {
+		h.logger.Printf("Failed to download clip %q for guild %s: %v", name, guildID, err)
+		return h.editResponse(s, i, "❌ "+h.localizer.T(guildID, "clip.download_failed"))
+	}
+
+	clip, err := h.clipService.SaveClip(guildID, name, userID, wavData)
+	if err != nil {
+		return h.editResponse(s, i, "❌ "+h.localizer.T(guildID, "clip.save_failed", err))
+	}
+
+	h.logger.Printf("Saved clip %q for guild %s (%d bytes, %s)", clip.Name, guildID, clip.Size, clip.Duration)
+	return h.editResponse(s, i, h.localizer.T(guildID, "clip.saved", clip.Name, clip.Duration.Seconds(), clip.Name))
+}
+
+// handleRemove deletes a clip
+func (h *ClipCommandHandler) handleRemove(s *discordgo.Session, i *discordgo.InteractionCreate, guildID string, options []*discordgo.ApplicationCommandInteractionDataOption) error {
+	if len(options) == 0 {
+		return h.respondError(s, i, h.localizer.T(guildID, "clip.name_required"))
+	}
+	name := options[0].StringValue()
+
+	err := h.clipService.RemoveClip(guildID, name)
+	if errors.Is(err, ErrClipNotFound) {
+		return h.respondError(s, i, h.localizer.T(guildID, "clip.not_found", name))
+	}
+	if err != nil {
+		return h.respondError(s, i, h.localizer.T(guildID, "clip.remove_failed", err))
+	}
+
+	return h.respondSuccess(s, i, h.localizer.T(guildID, "clip.removed", strings.ToLower(name)))
+}
+
+// handleList lists the clips stored for a guild
+func (h *ClipCommandHandler) handleList(s *discordgo.Session, i *discordgo.InteractionCreate, guildID string) error {
+	clips, err := h.clipService.ListClips(guildID)
+	if err != nil {
+		return h.respondError(s, i, h.localizer.T(guildID, "clip.list_failed", err))
+	}
+
+	if len(clips) == 0 {
+		return h.respondSuccess(s, i, h.localizer.T(guildID, "clip.list_empty"))
+	}
+
+	var builder strings.Builder
+	builder.WriteString(h.localizer.T(guildID, "clip.list"))
+	for _, clip := range clips {
+		builder.WriteString(fmt.Sprintf("• `%s` (%.1fs)\n", clip.Name, clip.Duration.Seconds()))
+	}
+
+	return h.respondSuccess(s, i, builder.String())
+}
+
+// download fetches an attachment, refusing bodies larger than MaxClipUploadBytes
+func (h *ClipCommandHandler) download(url string) ([]byte, error) {
+	data, err := downloadAttachment(h.httpClient, url, MaxClipUploadBytes)
+	if errors.Is(err, errAttachmentTooLarge) {
+		return nil, fmt.Errorf("%w: %w", ErrClipLimitExceeded, err)
+	}
+	return data, err
+}
+
+// downloadAttachment fetches an attachment, refusing bodies larger than limit bytes
+func downloadAttachment(client *http.Client, url string, limit int) ([]byte, error) {
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, int64(limit)+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > limit {
+		return nil, errAttachmentTooLarge
+	}
+
+	return data, nil
+}
+
+// ValidatePermissions validates that the user has administrator permissions
+func (h *ClipCommandHandler) ValidatePermissions(userID, guildID string) error {
+	canControl, err := h.permissionService.CanControlBot(userID, guildID)
+	if err != nil {
+		return fmt.Errorf("failed to check permissions: %w", err)
+	}
+
+	if !canControl {
+		return withKind(ErrPermission, fmt.Errorf("you must have administrator permissions to manage clips"))
+	}
+
+	return nil
+}
+
+// ValidateChannelAccess is not needed for clip commands but required by interface
+func (h *ClipCommandHandler) ValidateChannelAccess(userID, channelID string) error {
+	return nil // Not applicable for clip commands
+}
+
+// Helper methods for response handling
+
+func (h *ClipCommandHandler) respondSuccess(s *discordgo.Session, i *discordgo.InteractionCreate, message string) error {
+	return textcmd.Respond(s, i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: message,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
+
+func (h *ClipCommandHandler) respondError(s *discordgo.Session, i *discordgo.InteractionCreate, message string) error {
+	return textcmd.Respond(s, i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: "❌ " + message,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
+
+func (h *ClipCommandHandler) editResponse(s *discordgo.Session, i *discordgo.InteractionCreate, message string) error {
+	_, err := textcmd.EditResponse(s, i.Interaction, &discordgo.WebhookEdit{
+		Content: &message,
+	})
+	return err
+}
+
+// PlayCommandHandler queues a stored audio clip for playback in the voice channel
+type PlayCommandHandler struct {
+	clipService       AudioClipService
+	voiceManager      VoiceManager
+	messageQueue      MessageQueue
+	permissionService PermissionService
+	localizer         *Localizer
+	logger            *log.Logger
+}
+
+// NewPlayCommandHandler creates a new play command handler
+func NewPlayCommandHandler(
+	clipService AudioClipService,
+	voiceManager VoiceManager,
+	messageQueue MessageQueue,
+	permissionService PermissionService,
+	logger *log.Logger,
+) *PlayCommandHandler {
+	return &PlayCommandHandler{
+		clipService:       clipService,
+		voiceManager:      voiceManager,
+		messageQueue:      messageQueue,
+		permissionService: permissionService,
+		logger:            logger,
+	}
+}
+
+// SetLocalizer sets the localizer used to translate responses
+func (h *PlayCommandHandler) SetLocalizer(localizer *Localizer) {
+	h.localizer = localizer
+}
+
+// Definition returns the Discord slash command definition for the play command
+func (h *PlayCommandHandler) Definition() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:        "darrot-play",
+		Description: "Play a stored audio clip in the voice channel",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "clip",
+				Description: "Name of the clip to play",
+				Required:    true,
+			},
+		},
+	}
+}
+
+// Handle processes the play command interaction
+func (h *PlayCommandHandler) Handle(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	// Validate guild context
+	if i.GuildID == "" {
+		return h.respondError(s, i, h.localizer.T(i.GuildID, "common.guild_only"))
+	}
+
+	userID := i.Member.User.ID
+	guildID := i.GuildID
+
+	// Validate permissions
+	if err := h.ValidatePermissions(userID, guildID); err != nil {
+		return h.respondError(s, i, h.localizer.T(guildID, "common.permission_denied", err))
+	}
+
+	// Check if bot is connected to a voice channel
+	if _, exists := h.voiceManager.GetConnection(guildID); !exists {
+		return h.respondError(s, i, h.localizer.T(guildID, "common.not_in_voice"))
+	}
+
+	options := i.ApplicationCommandData().Options
+	if len(options) == 0 {
+		return h.respondError(s, i, h.localizer.T(guildID, "clip.name_required"))
+	}
+
+	name, err := NormalizeClipName(options[0].StringValue())
+	if err != nil {
+		return h.respondError(s, i, h.localizer.T(guildID, "clip.invalid_name", err))
+	}
+
+	// Make sure the clip exists before queueing it
+	if _, err := h.clipService.LoadClip(guildID, name); err != nil {
+		if errors.Is(err, ErrClipNotFound) {
+			return h.respondError(s, i, h.localizer.T(guildID, "play.not_found", name))
+		}
+		return h.respondError(s, i, h.localizer.T(guildID, "play.load_failed", err))
+	}
+
+	// Clips share the TTS queue so they play in order with spoken messages
+	message := &QueuedMessage{
+		ID:        i.ID,
+		GuildID:   guildID,
+		ChannelID: i.ChannelID,
+		UserID:    userID,
+		Username:  i.Member.User.Username,
+		Content:   fmt.Sprintf("clip %s", name),
+		ClipName:  name,
+		Timestamp: time.Now(),
+	}
+
+	if err := h.messageQueue.Enqueue(message); err != nil {
+		return h.respondError(s, i, h.localizer.T(guildID, "play.queue_failed", err))
+	}
+
+	return h.respondSuccess(s, i, h.localizer.T(guildID, "play.queued", name))
+}
+
+// ValidatePermissions validates that the user has permission to control the bot
+func (h *PlayCommandHandler) ValidatePermissions(userID, guildID string) error {
+	canControl, err := h.permissionService.CanControlBot(userID, guildID)
+	if err != nil {
+		return fmt.Errorf("failed to check permissions: %w", err)
+	}
+
+	if !canControl {
+		return withKind(ErrPermission, fmt.Errorf("you don't have permission to control the bot"))
+	}
+
+	return nil
+}
+
+// ValidateChannelAccess is not needed for play commands but required by interface
+func (h *PlayCommandHandler) ValidateChannelAccess(userID, channelID string) error {
+	return nil // Not applicable for play commands
+}
+
+// Helper methods for response handling
+
+func (h *PlayCommandHandler) respondSuccess(s *discordgo.Session, i *discordgo.InteractionCreate, message string) error {
+	return textcmd.Respond(s, i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: message,
+		},
+	})
+}
+
+func (h *PlayCommandHandler) respondError(s *discordgo.Session, i *discordgo.InteractionCreate, message string) error {
+	return textcmd.Respond(s, i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: "❌ " + message,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
@@ -0,0 +1,125 @@
+package tts
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createTestClipHandler(t *testing.T) (*ClipCommandHandler, *MockPermissionService) {
+	clipService, _ := createTestClipService(t, DefaultClipLimits())
+	mockPermissionService := &MockPermissionService{}
+	logger := log.New(os.Stdout, "[TEST] ", log.LstdFlags)
+
+	return NewClipCommandHandler(clipService, mockPermissionService, logger), mockPermissionService
+}
+
+func createTestPlayHandler(t *testing.T) (*PlayCommandHandler, *MockPermissionService) {
+	clipService, _ := createTestClipService(t, DefaultClipLimits())
+	mockPermissionService := &MockPermissionService{}
+	logger := log.New(os.Stdout, "[TEST] ", log.LstdFlags)
+
+	handler := NewPlayCommandHandler(
+		clipService,
+		&MockVoiceManager{},
+		&MockMessageQueue{},
+		mockPermissionService,
+		logger,
+	)
+
+	return handler, mockPermissionService
+}
+
+func TestClipCommandHandler_Definition(t *testing.T) {
+	handler, _ := createTestClipHandler(t)
+
+	definition := handler.Definition()
+
+	assert.Equal(t, "darrot-clip", definition.Name)
+	require.Len(t, definition.Options, 3)
+	assert.Equal(t, "upload", definition.Options[0].Name)
+	assert.Equal(t, "remove", definition.Options[1].Name)
+	assert.Equal(t, "list", definition.Options[2].Name)
+
+	uploadOptions := definition.Options[0].Options
+	require.Len(t, uploadOptions, 2)
+	assert.Equal(t, discordgo.ApplicationCommandOptionAttachment, uploadOptions[1].Type)
+	assert.True(t, uploadOptions[1].Required)
+}
+
+func TestClipCommandHandler_ValidatePermissions(t *testing.T) {
+	handler, mockPermissionService := createTestClipHandler(t)
+
+	mockPermissionService.On("CanControlBot", "admin", "guild123").Return(true, nil)
+	mockPermissionService.On("CanControlBot", "user", "guild123").Return(false, nil)
+	mockPermissionService.On("CanControlBot", "broken", "guild123").Return(false, errors.New("permission check failed"))
+
+	assert.NoError(t, handler.ValidatePermissions("admin", "guild123"))
+
+	err := handler.ValidatePermissions("user", "guild123")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "administrator permissions")
+
+	err = handler.ValidatePermissions("broken", "guild123")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to check permissions")
+
+	mockPermissionService.AssertExpectations(t)
+}
+
+func TestClipCommandHandler_Download(t *testing.T) {
+	handler, _ := createTestClipHandler(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/clip.wav":
+			w.Write([]byte("RIFF"))
+		case "/huge.wav":
+			w.Write([]byte(strings.Repeat("x", MaxClipUploadBytes+1)))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	data, err := handler.download(server.URL + "/clip.wav")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("RIFF"), data)
+
+	_, err = handler.download(server.URL + "/huge.wav")
+	assert.True(t, errors.Is(err, ErrClipLimitExceeded))
+
+	_, err = handler.download(server.URL + "/missing.wav")
+	assert.Error(t, err)
+}
+
+func TestPlayCommandHandler_Definition(t *testing.T) {
+	handler, _ := createTestPlayHandler(t)
+
+	definition := handler.Definition()
+
+	assert.Equal(t, "darrot-play", definition.Name)
+	require.Len(t, definition.Options, 1)
+	assert.Equal(t, "clip", definition.Options[0].Name)
+	assert.Equal(t, discordgo.ApplicationCommandOptionString, definition.Options[0].Type)
+	assert.True(t, definition.Options[0].Required)
+}
+
+func TestPlayCommandHandler_ValidatePermissions(t *testing.T) {
+	handler, mockPermissionService := createTestPlayHandler(t)
+
+	mockPermissionService.On("CanControlBot", "user123", "guild123").Return(false, nil)
+
+	err := handler.ValidatePermissions("user123", "guild123")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "don't have permission to control the bot")
+	mockPermissionService.AssertExpectations(t)
+}
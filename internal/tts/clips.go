@@ -0,0 +1,177 @@
+package tts
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Audio clip limits
+const (
+	MaxClipUploadBytes          = 8 * 1024 * 1024 // Largest WAV file accepted for upload
+	DefaultMaxClipsPerGuild     = 25
+	DefaultMaxClipBytesPerGuild = 10 * 1024 * 1024 // Encoded audio stored per guild
+	DefaultMaxClipDuration      = 15 * time.Second
+)
+
+// clipNamePattern restricts clip names to safe, easy-to-type identifiers
+var clipNamePattern = regexp.MustCompile(`^[a-z0-9_-]{1,32}$`)
+
+// AudioClipEncoder converts uploaded WAV audio into the DCA format played by VoiceManager
+type AudioClipEncoder interface {
+	EncodeWAV(wavData []byte) ([]byte, error)
+}
+
+// ClipLimits bounds how much clip audio a single guild may store
+type ClipLimits struct {
+	MaxClips    int
+	MaxBytes    int
+	MaxDuration time.Duration
+}
+
+// DefaultClipLimits returns the default per-guild clip storage limits
+func DefaultClipLimits() ClipLimits {
+	return ClipLimits{
+		MaxClips:    DefaultMaxClipsPerGuild,
+		MaxBytes:    DefaultMaxClipBytesPerGuild,
+		MaxDuration: DefaultMaxClipDuration,
+	}
+}
+
+// AudioClipServiceImpl implements AudioClipService with clips encoded once on upload
+// and stored on disk through StorageService
+type AudioClipServiceImpl struct {
+	storage *StorageService
+	encoder AudioClipEncoder
+	limits  ClipLimits
+	mu      sync.Mutex
+}
+
+// NewAudioClipService creates a new audio clip service
+func NewAudioClipService(storage *StorageService, encoder AudioClipEncoder, limits ClipLimits) *AudioClipServiceImpl {
+	return &AudioClipServiceImpl{
+		storage: storage,
+		encoder: encoder,
+		limits:  limits,
+	}
+}
+
+// SaveClip validates and encodes a WAV upload and stores it under name, replacing any
+// existing clip with the same name
+func (c *AudioClipServiceImpl) SaveClip(guildID, name, createdBy string, wavData []byte) (*AudioClip, error) {
+	name, err := NormalizeClipName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(wavData) > MaxClipUploadBytes {
+		return nil, fmt.Errorf("%w: uploads are limited to %d MB", ErrClipLimitExceeded, MaxClipUploadBytes/(1024*1024))
+	}
+
+	audio, err := parseWAV(wavData)
+	if err != nil {
+		return nil, fmt.Errorf("invalid clip audio: %w", err)
+	}
+
+	duration := audio.Duration()
+	if duration > c.limits.MaxDuration {
+		return nil, fmt.Errorf("%w: clips can be at most %s long", ErrClipLimitExceeded, c.limits.MaxDuration)
+	}
+
+	if c.encoder == nil {
+		return nil, fmt.Errorf("no audio encoder configured for clips")
+	}
+
+	encoded, err := c.encoder.EncodeWAV(wavData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode clip: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	existing, err := c.storage.ListAudioClips(guildID)
+	if err != nil {
+		return nil, err
+	}
+
+	count, totalBytes := 0, 0
+	for _, clip := range existing {
+		if clip.Name == name {
+			continue // Replaced by this upload
+		}
+		count++
+		totalBytes += clip.Size
+	}
+
+	if count+1 > c.limits.MaxClips {
+		return nil, fmt.Errorf("%w: a server can store at most %d clips", ErrClipLimitExceeded, c.limits.MaxClips)
+	}
+	if totalBytes+len(encoded) > c.limits.MaxBytes {
+		return nil, fmt.Errorf("%w: clip storage for this server is full", ErrClipLimitExceeded)
+	}
+
+	clip := AudioClip{
+		GuildID:   guildID,
+		Name:      name,
+		Size:      len(encoded),
+		Duration:  duration,
+		CreatedBy: createdBy,
+		CreatedAt: time.Now(),
+	}
+
+	if err := c.storage.SaveAudioClip(clip, encoded); err != nil {
+		return nil, err
+	}
+
+	return &clip, nil
+}
+
+// LoadClip returns the encoded audio for a clip
+func (c *AudioClipServiceImpl) LoadClip(guildID, name string) ([]byte, error) {
+	name, err := NormalizeClipName(name)
+	if err != nil {
+		return nil, err
+	}
+	return c.storage.LoadAudioClip(guildID, name)
+}
+
+// RemoveClip deletes a clip
+func (c *AudioClipServiceImpl) RemoveClip(guildID, name string) error {
+	name, err := NormalizeClipName(name)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.storage.RemoveAudioClip(guildID, name)
+}
+
+// ListClips returns all clips stored for a guild sorted by name
+func (c *AudioClipServiceImpl) ListClips(guildID string) ([]AudioClip, error) {
+	clips, err := c.storage.ListAudioClips(guildID)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(clips, func(i, j int) bool {
+		return clips[i].Name < clips[j].Name
+	})
+
+	return clips, nil
+}
+
+// NormalizeClipName lowercases a clip name and checks it only contains letters,
+// digits, dashes and underscores
+func NormalizeClipName(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if !clipNamePattern.MatchString(name) {
+		return "", fmt.Errorf("clip names must be 1-32 characters of letters, digits, '-' or '_'")
+	}
+	return name, nil
+}
@@ -0,0 +1,192 @@
+package tts
+
+import (
+	"encoding/binary"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClipEncoder returns the PCM payload as the "encoded" audio
+type fakeClipEncoder struct {
+	calls int
+	err   error
+}
+
+func (e *fakeClipEncoder) EncodeWAV(wavData []byte) ([]byte, error) {
+	e.calls++
+	if e.err != nil {
+		return nil, e.err
+	}
+	audio, err := parseWAV(wavData)
+	if err != nil {
+		return nil, err
+	}
+	return audio.pcm, nil
+}
+
+// buildTestWAV creates a mono 16-bit PCM WAV file of the given duration
+func buildTestWAV(sampleRate int, duration time.Duration) []byte {
+	pcm := make([]byte, int(duration.Seconds()*float64(sampleRate))*2)
+	return encodeWAV(pcm, sampleRate, 1)
+}
+
+func createTestClipService(t *testing.T, limits ClipLimits) (*AudioClipServiceImpl, *fakeClipEncoder) {
+	storage, err := NewStorageService(t.TempDir())
+	require.NoError(t, err)
+
+	encoder := &fakeClipEncoder{}
+	return NewAudioClipService(storage, encoder, limits), encoder
+}
+
+func TestParseWAV(t *testing.T) {
+	audio, err := parseWAV(buildTestWAV(8000, 2*time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, 8000, audio.sampleRate)
+	assert.Equal(t, 1, audio.channels)
+	assert.Equal(t, 2*time.Second, audio.Duration())
+
+	_, err = parseWAV([]byte("not a wav file at all"))
+	assert.Error(t, err)
+
+	// 8-bit audio is rejected
+	wav := buildTestWAV(8000, time.Second)
+	binary.LittleEndian.PutUint16(wav[34:36], 8)
+	_, err = parseWAV(wav)
+	assert.Error(t, err)
+
+	// So is audio that resampling would blow up to a huge buffer
+	_, err = parseWAV(encodeWAV([]byte{1, 2}, 1, 1))
+	assert.Error(t, err)
+
+	// A format chunk cut short
+	_, err = parseWAV([]byte("RIFF\x00\x00\x00\x00WAVEfmt \x10\x00\x00\x00\x01\x00"))
+	assert.Error(t, err)
+}
+
+func FuzzParseWAV(f *testing.F) {
+	f.Add(encodeWAV(make([]byte, 480), 24000, 1))
+	f.Add(encodeWAV(make([]byte, 960), 48000, 2))
+	f.Add([]byte("RIFF\x00\x00\x00\x00WAVEfmt \x10\x00\x00\x00"))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		audio, err := parseWAV(data)
+		if err != nil {
+			return
+		}
+		assert.Contains(t, []int{1, 2}, audio.channels)
+		assert.GreaterOrEqual(t, audio.sampleRate, minWAVSampleRate)
+		assert.LessOrEqual(t, audio.sampleRate, maxWAVSampleRate)
+		assert.LessOrEqual(t, len(audio.pcm), len(data))
+	})
+}
+
+func TestNormalizeClipName(t *testing.T) {
+	name, err := NormalizeClipName("  Air-Horn_2 ")
+	require.NoError(t, err)
+	assert.Equal(t, "air-horn_2", name)
+
+	for _, invalid := range []string{"", "../secret", "with space", "a/b", "this-name-is-much-too-long-to-be-accepted"} {
+		_, err := NormalizeClipName(invalid)
+		assert.Error(t, err, "expected %q to be rejected", invalid)
+	}
+}
+
+func TestAudioClipService_SaveLoadRemove(t *testing.T) {
+	service, encoder := createTestClipService(t, DefaultClipLimits())
+
+	clip, err := service.SaveClip("guild1", "Horn", "user1", buildTestWAV(8000, time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, "horn", clip.Name)
+	assert.Equal(t, "user1", clip.CreatedBy)
+	assert.Equal(t, time.Second, clip.Duration)
+	assert.Equal(t, 1, encoder.calls)
+
+	audio, err := service.LoadClip("guild1", "horn")
+	require.NoError(t, err)
+	assert.Len(t, audio, clip.Size)
+
+	clips, err := service.ListClips("guild1")
+	require.NoError(t, err)
+	require.Len(t, clips, 1)
+	assert.Equal(t, "horn", clips[0].Name)
+
+	// Clips are scoped per guild
+	_, err = service.LoadClip("guild2", "horn")
+	assert.True(t, errors.Is(err, ErrClipNotFound))
+
+	require.NoError(t, service.RemoveClip("guild1", "horn"))
+	_, err = service.LoadClip("guild1", "horn")
+	assert.True(t, errors.Is(err, ErrClipNotFound))
+	assert.True(t, errors.Is(service.RemoveClip("guild1", "horn"), ErrClipNotFound))
+}
+
+func TestAudioClipService_Limits(t *testing.T) {
+	limits := ClipLimits{MaxClips: 2, MaxBytes: 40000, MaxDuration: 2 * time.Second}
+	service, encoder := createTestClipService(t, limits)
+
+	// Too long
+	_, err := service.SaveClip("guild1", "long", "user1", buildTestWAV(8000, 3*time.Second))
+	assert.True(t, errors.Is(err, ErrClipLimitExceeded))
+	assert.Equal(t, 0, encoder.calls, "over-long clips should not be encoded")
+
+	// 16000 bytes each
+	_, err = service.SaveClip("guild1", "one", "user1", buildTestWAV(8000, time.Second))
+	require.NoError(t, err)
+	_, err = service.SaveClip("guild1", "two", "user1", buildTestWAV(8000, time.Second))
+	require.NoError(t, err)
+
+	// Too many clips
+	_, err = service.SaveClip("guild1", "three", "user1", buildTestWAV(8000, time.Second))
+	assert.True(t, errors.Is(err, ErrClipLimitExceeded))
+
+	// Replacing an existing clip does not count against the clip limit,
+	// but the replacement must still fit in the storage budget
+	_, err = service.SaveClip("guild1", "two", "user1", buildTestWAV(8000, 1500*time.Millisecond))
+	require.NoError(t, err)
+	_, err = service.SaveClip("guild1", "two", "user1", buildTestWAV(8000, 2*time.Second))
+	assert.True(t, errors.Is(err, ErrClipLimitExceeded))
+
+	// Other guilds have their own limits
+	_, err = service.SaveClip("guild2", "three", "user1", buildTestWAV(8000, time.Second))
+	assert.NoError(t, err)
+}
+
+func TestAudioClipService_EncoderError(t *testing.T) {
+	service, encoder := createTestClipService(t, DefaultClipLimits())
+	encoder.err = errors.New("encoder unavailable")
+
+	_, err := service.SaveClip("guild1", "horn", "user1", buildTestWAV(8000, time.Second))
+	assert.Error(t, err)
+
+	clips, err := service.ListClips("guild1")
+	require.NoError(t, err)
+	assert.Empty(t, clips)
+}
+
+func TestTTSProcessor_PlaysQueuedClip(t *testing.T) {
+	ttsManager := &mockTTSManager{}
+	voiceManager := newMockVoiceManager()
+	queue := NewMessageQueue()
+	processor := NewTTSProcessor(ttsManager, voiceManager, queue, newMockConfigService(), newMockUserService()).(*ttsProcessor)
+
+	clipService, _ := createTestClipService(t, DefaultClipLimits())
+	_, err := clipService.SaveClip("guild1", "horn", "user1", buildTestWAV(8000, time.Second))
+	require.NoError(t, err)
+	processor.SetClipService(clipService)
+
+	var played []byte
+	voiceManager.playAudioFunc = func(guildID string, audioData []byte) error {
+		played = audioData
+		return nil
+	}
+
+	require.NoError(t, queue.Enqueue(&QueuedMessage{ID: "m1", GuildID: "guild1", Content: "clip horn", ClipName: "horn", Timestamp: time.Now()}))
+	processor.processNextMessage("guild1", &guildProcessor{guildID: "guild1"})
+
+	assert.Empty(t, ttsManager.getCallLog(), "clips should not be synthesized")
+	assert.Len(t, played, 16000)
+}
@@ -1,8 +1,20 @@
 package tts
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"darrot/internal/commands/options"
+	"darrot/internal/commands/textcmd"
+	"darrot/internal/i18n"
 
 	"github.com/bwmarrin/discordgo"
 )
@@ -23,6 +35,11 @@ type JoinCommandHandler struct {
 	userService       UserService
 	ttsProcessor      TTSProcessor
 	errorRecovery     *ErrorRecoveryManager
+	privacyService    *PrivacyService
+	engineStatus      TTSEngineStatus
+	auditLog          *AuditLog
+	greeter           *JoinGreeter
+	localizer         *Localizer
 	logger            *log.Logger
 }
 
@@ -47,6 +64,31 @@ func NewJoinCommandHandler(
 	}
 }
 
+// SetLocalizer sets the localizer used to translate responses
+func (h *JoinCommandHandler) SetLocalizer(localizer *Localizer) {
+	h.localizer = localizer
+}
+
+// SetPrivacyService enables DM privacy notices for automatically opted-in users
+func (h *JoinCommandHandler) SetPrivacyService(privacyService *PrivacyService) {
+	h.privacyService = privacyService
+}
+
+// SetEngineStatus makes the command refuse to join while the TTS engine is unavailable
+func (h *JoinCommandHandler) SetEngineStatus(engineStatus TTSEngineStatus) {
+	h.engineStatus = engineStatus
+}
+
+// SetAuditLog records joins in the guild's audit channel
+func (h *JoinCommandHandler) SetAuditLog(auditLog *AuditLog) {
+	h.auditLog = auditLog
+}
+
+// SetGreeter speaks the greeting of a pairing when the bot joins its voice channel
+func (h *JoinCommandHandler) SetGreeter(greeter *JoinGreeter) {
+	h.greeter = greeter
+}
+
 // Definition returns the Discord slash command definition for the join command
 func (h *JoinCommandHandler) Definition() *discordgo.ApplicationCommand {
 	return &discordgo.ApplicationCommand{
@@ -54,22 +96,56 @@ func (h *JoinCommandHandler) Definition() *discordgo.ApplicationCommand {
 		Description: "Join a voice channel and start TTS for messages from a text channel",
 		Options: []*discordgo.ApplicationCommandOption{
 			{
-				Type:        discordgo.ApplicationCommandOptionChannel,
-				Name:        "voice-channel",
-				Description: "The voice channel to join",
-				Required:    true,
-				ChannelTypes: []discordgo.ChannelType{
-					discordgo.ChannelTypeGuildVoice,
-				},
+				Type:         discordgo.ApplicationCommandOptionChannel,
+				Name:         "voice-channel",
+				Description:  "The voice or stage channel to join",
+				Required:     true,
+				ChannelTypes: voiceChannelTypes,
+			},
+			{
+				Type:         discordgo.ApplicationCommandOptionChannel,
+				Name:         "text-channel",
+				Description:  "The text channel to monitor (defaults to the channel the command is run in)",
+				Required:     false,
+				ChannelTypes: textChannelTypes,
 			},
 			{
-				Type:        discordgo.ApplicationCommandOptionChannel,
-				Name:        "text-channel",
-				Description: "The text channel to monitor (defaults to voice channel's text chat)",
+				Type:        discordgo.ApplicationCommandOptionBoolean,
+				Name:        "whisper",
+				Description: "Only read messages from members who are in the voice channel",
+				Required:    false,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "greeting",
+				Description: "Welcome text to speak when the bot joins, like the rules for the session",
+				Required:    false,
+				MaxLength:   MaxGreetingLength,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionBoolean,
+				Name:        "read-pinned",
+				Description: "Speak the most recent pinned message of the text channel when the bot joins",
+				Required:    false,
+			},
+			{
+				Type:         discordgo.ApplicationCommandOptionChannel,
+				Name:         "mirror-channel",
+				Description:  "Text channel that shows the text of everything read aloud, in order",
+				Required:     false,
+				ChannelTypes: textChannelTypes,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionBoolean,
+				Name:        "mirror-only",
+				Description: "Only post messages to the mirror channel instead of reading them aloud",
+				Required:    false,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionBoolean,
+				Name:        "voice-chat",
+				Description: "Read the voice channel's built-in text chat instead of a text channel",
 				Required:    false,
-				ChannelTypes: []discordgo.ChannelType{
-					discordgo.ChannelTypeGuildText,
-				},
 			},
 		},
 	}
@@ -79,7 +155,7 @@ func (h *JoinCommandHandler) Definition() *discordgo.ApplicationCommand {
 func (h *JoinCommandHandler) Handle(s *discordgo.Session, i *discordgo.InteractionCreate) error {
 	// Validate guild context
 	if i.GuildID == "" {
-		return h.respondError(s, i, "This command can only be used in a server.")
+		return h.respondError(s, i, h.localizer.T(i.GuildID, "common.guild_only"))
 	}
 
 	userID := i.Member.User.ID
@@ -87,28 +163,63 @@ func (h *JoinCommandHandler) Handle(s *discordgo.Session, i *discordgo.Interacti
 
 	// Validate permissions
 	if err := h.ValidatePermissions(userID, guildID); err != nil {
-		return h.respondError(s, i, fmt.Sprintf("Permission denied: %v", err))
+		return h.respondError(s, i, h.localizer.T(guildID, "common.permission_denied", err))
+	}
+
+	// Joining is pointless while nothing can be spoken
+	if h.engineStatus != nil {
+		if err := h.engineStatus.EngineError(); err != nil {
+			h.logger.Printf("Refusing to join in guild %s, TTS engine unavailable: %v", guildID, err)
+			return h.respondError(s, i, h.localizer.T(guildID, "join.engine_unavailable"))
+		}
 	}
 
 	// Extract command options
-	options := i.ApplicationCommandData().Options
-	voiceChannelID := options[0].ChannelValue(s).ID
+	opts := options.FromInteraction(i)
+	voiceChannelID, ok := opts.ChannelID("voice-channel")
+	if !ok {
+		return h.respondError(s, i, h.localizer.OptionError(guildID, options.Missing("voice-channel")))
+	}
 
-	var textChannelID string
-	if len(options) > 1 && options[1].ChannelValue(s) != nil {
-		textChannelID = options[1].ChannelValue(s).ID
-	} else {
+	textChannelID, setTextChannel := opts.ChannelID("text-channel")
+	if voiceChat, _ := opts.Bool("voice-chat"); voiceChat {
+		// The voice channel's built-in text chat shares its ID
+		if setTextChannel && !IsVoiceChatPairing(voiceChannelID, textChannelID) {
+			return h.respondError(s, i, h.localizer.T(guildID, "join.voice_chat_conflict"))
+		}
+		textChannelID = voiceChannelID
+	} else if !setTextChannel {
 		// Default to the channel where the command was invoked
 		textChannelID = i.ChannelID
 	}
 
+	whisper, setWhisper := opts.Bool("whisper")
+	greeting, setGreeting := opts.String("greeting")
+	readPinned, setReadPinned := opts.Bool("read-pinned")
+	mirrorChannelID, setMirrorChannel := opts.ChannelID("mirror-channel")
+	mirrorOnly, setMirrorOnly := opts.Bool("mirror-only")
+
 	// Validate channel access
 	if err := h.ValidateChannelAccess(userID, voiceChannelID); err != nil {
-		return h.respondError(s, i, fmt.Sprintf("Cannot access voice channel: %v", err))
+		return h.respondError(s, i, h.localizer.T(guildID, "join.voice_channel_access", err))
 	}
 
 	if err := h.ValidateChannelAccess(userID, textChannelID); err != nil {
-		return h.respondError(s, i, fmt.Sprintf("Cannot access text channel: %v", err))
+		return h.respondError(s, i, h.localizer.T(guildID, "join.text_channel_access", err))
+	}
+
+	if setMirrorChannel {
+		if err := h.ValidateChannelAccess(userID, mirrorChannelID); err != nil {
+			return h.respondError(s, i, h.localizer.T(guildID, "join.mirror_channel_access", err))
+		}
+	}
+
+	// Mirror-only mode needs somewhere to post the messages it doesn't read
+	if mirrorOnly && mirrorChannelID == "" {
+		existingPairing, err := h.channelService.GetPairing(guildID, voiceChannelID)
+		if err != nil || existingPairing.MirrorChannelID == "" {
+			return h.respondError(s, i, h.localizer.T(guildID, "join.mirror_only_without_channel"))
+		}
 	}
 
 	// Check if bot is already connected to a different channel in this guild
@@ -133,6 +244,47 @@ func (h *JoinCommandHandler) Handle(s *discordgo.Session, i *discordgo.Interacti
 					h.logger.Printf("Warning: Failed to start TTS processing for guild %s: %v", guildID, err)
 				}
 
+				// Running the command again toggles whisper mode on the existing pairing
+				if setWhisper {
+					if err := h.channelService.SetRequirePresence(guildID, voiceChannelID, whisper); err != nil {
+						return h.respondError(s, i, h.localizer.T(guildID, "join.whisper_failed", err))
+					}
+				} else {
+					whisper = existingPairing.RequirePresence
+				}
+
+				// Running the command again with greeting options updates the greeting and
+				// speaks it right away; options left out are kept
+				greeted := existingPairing.Greeting != "" || existingPairing.ReadPinned
+				if setGreeting || setReadPinned {
+					if !setGreeting {
+						greeting = existingPairing.Greeting
+					}
+					if !setReadPinned {
+						readPinned = existingPairing.ReadPinned
+					}
+					if err := h.channelService.SetGreeting(guildID, voiceChannelID, greeting, readPinned); err != nil {
+						return h.respondError(s, i, h.localizer.T(guildID, "join.greeting_failed", err))
+					}
+					greeted = strings.TrimSpace(greeting) != "" || readPinned
+					if greeted {
+						h.greeter.Greet(guildID, voiceChannelID)
+					}
+				}
+
+				// Mirror options update the mirror channel the same way
+				if !setMirrorChannel {
+					mirrorChannelID = existingPairing.MirrorChannelID
+				}
+				if !setMirrorOnly {
+					mirrorOnly = existingPairing.MirrorOnly
+				}
+				if setMirrorChannel || setMirrorOnly {
+					if err := h.channelService.SetMirror(guildID, voiceChannelID, mirrorChannelID, mirrorOnly); err != nil {
+						return h.respondError(s, i, h.localizer.T(guildID, "join.mirror_failed", err))
+					}
+				}
+
 				voiceChannel, _ := s.Channel(voiceChannelID)
 				textChannel, _ := s.Channel(textChannelID)
 
@@ -145,8 +297,18 @@ func (h *JoinCommandHandler) Handle(s *discordgo.Session, i *discordgo.Interacti
 				if textChannelName == "" {
 					textChannelName = textChannelID
 				}
+				if IsVoiceChatPairing(voiceChannelID, textChannelID) {
+					textChannelName = h.localizer.T(guildID, "join.voice_chat_name", voiceChannelName)
+				}
 
-				responseMessage := fmt.Sprintf("✅ Already connected to voice channel **%s** and monitoring text channel **%s** for TTS messages.", voiceChannelName, textChannelName)
+				responseMessage := h.localizer.T(guildID, "join.already_connected", voiceChannelName, textChannelName)
+				if whisper {
+					responseMessage += h.localizer.T(guildID, "join.whisper")
+				}
+				if greeted {
+					responseMessage += h.localizer.T(guildID, "join.greeting")
+				}
+				responseMessage += h.mirrorNotice(guildID, mirrorChannelID, mirrorOnly)
 				return h.respondSuccess(s, i, responseMessage)
 			}
 		}
@@ -168,7 +330,7 @@ func (h *JoinCommandHandler) Handle(s *discordgo.Session, i *discordgo.Interacti
 	}
 
 	// Join the voice channel with error recovery
-	_, err := h.voiceManager.JoinChannel(guildID, voiceChannelID)
+	connection, err := h.voiceManager.JoinChannel(guildID, voiceChannelID)
 	if err != nil {
 		h.logger.Printf("Initial voice channel join failed for guild %s: %v", guildID, err)
 
@@ -178,19 +340,49 @@ func (h *JoinCommandHandler) Handle(s *discordgo.Session, i *discordgo.Interacti
 			return h.respondError(s, i, userMessage)
 		}
 
-		return h.respondError(s, i, fmt.Sprintf("Failed to join voice channel: %v", err))
+		return h.respondError(s, i, h.localizer.T(guildID, "join.failed", err))
 	}
 
 	// Create channel pairing (this will now work since we cleaned up any stale pairings)
 	if err := h.channelService.CreatePairingWithCreator(guildID, voiceChannelID, textChannelID, userID); err != nil {
 		// If pairing creation fails, leave the voice channel
 		_ = h.voiceManager.LeaveChannel(guildID)
-		return h.respondError(s, i, fmt.Sprintf("Failed to create channel pairing: %v", err))
+		if errors.Is(err, ErrNSFWChannel) {
+			return h.respondError(s, i, h.localizer.T(guildID, "join.nsfw_channel"))
+		}
+		return h.respondError(s, i, h.localizer.T(guildID, "join.pairing_failed", err))
+	}
+
+	if whisper {
+		if err := h.channelService.SetRequirePresence(guildID, voiceChannelID, true); err != nil {
+			h.logger.Printf("Warning: Failed to enable whisper mode for guild %s: %v", guildID, err)
+			whisper = false
+		}
+	}
+
+	greeted := false
+	if strings.TrimSpace(greeting) != "" || readPinned {
+		if err := h.channelService.SetGreeting(guildID, voiceChannelID, greeting, readPinned); err != nil {
+			h.logger.Printf("Warning: Failed to set greeting for guild %s: %v", guildID, err)
+		} else {
+			greeted = true
+		}
+	}
+
+	if mirrorChannelID != "" {
+		if err := h.channelService.SetMirror(guildID, voiceChannelID, mirrorChannelID, mirrorOnly); err != nil {
+			h.logger.Printf("Warning: Failed to set mirror channel for guild %s: %v", guildID, err)
+			mirrorChannelID, mirrorOnly = "", false
+		}
 	}
 
 	// Auto opt-in the user who invited the bot
+	alreadyOptedIn, _ := h.userService.IsOptedIn(userID, guildID)
+	autoOptedIn := false
 	if err := h.userService.AutoOptIn(userID, guildID); err != nil {
 		h.logger.Printf("Warning: Failed to auto opt-in user %s: %v", userID, err)
+	} else {
+		autoOptedIn = !alreadyOptedIn
 	}
 
 	// Start TTS processing for this guild
@@ -200,6 +392,10 @@ func (h *JoinCommandHandler) Handle(s *discordgo.Session, i *discordgo.Interacti
 		h.logger.Printf("Started TTS processing for guild %s", guildID)
 	}
 
+	if greeted {
+		h.greeter.Greet(guildID, voiceChannelID)
+	}
+
 	// Get channel names for response
 	voiceChannel, _ := s.Channel(voiceChannelID)
 	textChannel, _ := s.Channel(textChannelID)
@@ -213,11 +409,46 @@ func (h *JoinCommandHandler) Handle(s *discordgo.Session, i *discordgo.Interacti
 	if textChannelName == "" {
 		textChannelName = textChannelID
 	}
+	if IsVoiceChatPairing(voiceChannelID, textChannelID) {
+		textChannelName = h.localizer.T(guildID, "join.voice_chat_name", voiceChannelName)
+	}
+
+	responseMessage := h.localizer.T(guildID, "join.joined", voiceChannelName, textChannelName)
+	if whisper {
+		responseMessage += h.localizer.T(guildID, "join.whisper")
+	}
+	if greeted {
+		responseMessage += h.localizer.T(guildID, "join.greeting")
+	}
+	responseMessage += h.mirrorNotice(guildID, mirrorChannelID, mirrorOnly)
+	if connection != nil && connection.RequestedToSpeak {
+		responseMessage += h.localizer.T(guildID, "join.stage_requested")
+	}
 
-	responseMessage := fmt.Sprintf("✅ Joined voice channel **%s** and monitoring text channel **%s** for TTS messages.\n\nUsers must opt-in to have their messages read aloud. You have been automatically opted-in.",
-		voiceChannelName, textChannelName)
+	err = h.respondSuccess(s, i, responseMessage)
+	h.auditLog.RecordJoin(guildID, userID, voiceChannelID, textChannelID)
 
-	return h.respondSuccess(s, i, responseMessage)
+	// The privacy notice is sent after responding so the DM never delays the response
+	if autoOptedIn && h.privacyService != nil {
+		if noticeErr := h.privacyService.SendOptInNotice(userID, guildID); noticeErr != nil {
+			h.logger.Printf("Warning: Failed to send privacy notice to user %s: %v", userID, noticeErr)
+		}
+	}
+
+	return err
+}
+
+// mirrorNotice describes the mirror channel of a pairing for the join response, or
+// returns "" when the pairing has none
+func (h *JoinCommandHandler) mirrorNotice(guildID, mirrorChannelID string, mirrorOnly bool) string {
+	switch {
+	case mirrorChannelID == "":
+		return ""
+	case mirrorOnly:
+		return h.localizer.T(guildID, "join.mirror_only", channelMention(mirrorChannelID))
+	default:
+		return h.localizer.T(guildID, "join.mirror", channelMention(mirrorChannelID))
+	}
 }
 
 // ValidatePermissions validates that the user has permission to invite the bot
@@ -228,7 +459,7 @@ func (h *JoinCommandHandler) ValidatePermissions(userID, guildID string) error {
 	}
 
 	if !canInvite {
-		return fmt.Errorf("you don't have permission to invite the bot to voice channels")
+		return withKind(ErrPermission, fmt.Errorf("you don't have permission to invite the bot to voice channels"))
 	}
 
 	return nil
@@ -246,6 +477,9 @@ type LeaveCommandHandler struct {
 	permissionService PermissionService
 	ttsProcessor      TTSProcessor
 	errorRecovery     *ErrorRecoveryManager
+	auditLog          *AuditLog
+	sessionRecorder   *SessionRecorder
+	localizer         *Localizer
 	logger            *log.Logger
 }
 
@@ -268,6 +502,21 @@ func NewLeaveCommandHandler(
 	}
 }
 
+// SetLocalizer sets the localizer used to translate responses
+func (h *LeaveCommandHandler) SetLocalizer(localizer *Localizer) {
+	h.localizer = localizer
+}
+
+// SetAuditLog records leaves in the guild's audit channel
+func (h *LeaveCommandHandler) SetAuditLog(auditLog *AuditLog) {
+	h.auditLog = auditLog
+}
+
+// SetSessionRecorder posts the recording of the session in guilds that record them
+func (h *LeaveCommandHandler) SetSessionRecorder(recorder *SessionRecorder) {
+	h.sessionRecorder = recorder
+}
+
 // Definition returns the Discord slash command definition for the leave command
 func (h *LeaveCommandHandler) Definition() *discordgo.ApplicationCommand {
 	return &discordgo.ApplicationCommand{
@@ -280,7 +529,7 @@ func (h *LeaveCommandHandler) Definition() *discordgo.ApplicationCommand {
 func (h *LeaveCommandHandler) Handle(s *discordgo.Session, i *discordgo.InteractionCreate) error {
 	// Validate guild context
 	if i.GuildID == "" {
-		return h.respondError(s, i, "This command can only be used in a server.")
+		return h.respondError(s, i, h.localizer.T(i.GuildID, "common.guild_only"))
 	}
 
 	userID := i.Member.User.ID
@@ -288,13 +537,13 @@ func (h *LeaveCommandHandler) Handle(s *discordgo.Session, i *discordgo.Interact
 
 	// Validate permissions
 	if err := h.ValidatePermissions(userID, guildID); err != nil {
-		return h.respondError(s, i, fmt.Sprintf("Permission denied: %v", err))
+		return h.respondError(s, i, h.localizer.T(guildID, "common.permission_denied", err))
 	}
 
 	// Check if bot is connected to a voice channel
 	connection, exists := h.voiceManager.GetConnection(guildID)
 	if !exists {
-		return h.respondError(s, i, "I'm not currently in a voice channel in this server.")
+		return h.respondError(s, i, h.localizer.T(guildID, "common.not_in_voice"))
 	}
 
 	voiceChannelID := connection.ChannelID
@@ -309,7 +558,7 @@ func (h *LeaveCommandHandler) Handle(s *discordgo.Session, i *discordgo.Interact
 			return h.respondError(s, i, userMessage)
 		}
 
-		return h.respondError(s, i, fmt.Sprintf("Failed to leave voice channel: %v", err))
+		return h.respondError(s, i, h.localizer.T(guildID, "leave.failed", err))
 	}
 
 	// Stop TTS processing for this guild
@@ -331,8 +580,34 @@ func (h *LeaveCommandHandler) Handle(s *discordgo.Session, i *discordgo.Interact
 		channelName = voiceChannelID
 	}
 
-	responseMessage := fmt.Sprintf("✅ Left voice channel **%s** and stopped TTS monitoring.", channelName)
-	return h.respondSuccess(s, i, responseMessage)
+	responseMessage := h.localizer.T(guildID, "leave.left", channelName)
+	err := h.respondSuccess(s, i, responseMessage)
+	h.auditLog.RecordLeave(guildID, userID, voiceChannelID)
+	if recording, ok := h.sessionRecorder.Finish(guildID); ok {
+		h.postRecording(s, i.ChannelID, guildID, recording)
+	}
+	return err
+}
+
+// postRecording posts a session recording in the channel /darrot-leave was used in and
+// removes it. It is a separate message so a large upload cannot make the command
+// response miss its deadline.
+func (h *LeaveCommandHandler) postRecording(s *discordgo.Session, channelID, guildID string, recording *SessionRecording) {
+	defer recording.Close()
+
+	name := "darrot-" + recording.StartedAt.UTC().Format("20060102-150405")
+	files := []*discordgo.File{{Name: name + ".ogg", ContentType: "audio/ogg", Reader: recording.Audio}}
+	if recording.Timestamps != nil {
+		files = append(files, &discordgo.File{Name: name + ".vtt", ContentType: "text/vtt", Reader: bytes.NewReader(recording.Timestamps)})
+	}
+
+	content := h.localizer.T(guildID, "leave.recording", recording.Duration.Round(time.Second))
+	if recording.Truncated {
+		content += "\n" + h.localizer.T(guildID, "leave.recording_truncated")
+	}
+	if _, err := s.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{Content: content, Files: files}); err != nil {
+		h.logger.Printf("Warning: Failed to post session recording for guild %s: %v", guildID, err)
+	}
 }
 
 // ValidatePermissions validates that the user has permission to control the bot
@@ -343,7 +618,7 @@ func (h *LeaveCommandHandler) ValidatePermissions(userID, guildID string) error
 	}
 
 	if !canControl {
-		return fmt.Errorf("you don't have permission to control the bot")
+		return withKind(ErrPermission, fmt.Errorf("you don't have permission to control the bot"))
 	}
 
 	return nil
@@ -357,7 +632,7 @@ func (h *LeaveCommandHandler) ValidateChannelAccess(userID, channelID string) er
 // Helper methods for response handling
 
 func (h *JoinCommandHandler) respondSuccess(s *discordgo.Session, i *discordgo.InteractionCreate, message string) error {
-	return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+	return textcmd.Respond(s, i.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseChannelMessageWithSource,
 		Data: &discordgo.InteractionResponseData{
 			Content: message,
@@ -366,7 +641,7 @@ func (h *JoinCommandHandler) respondSuccess(s *discordgo.Session, i *discordgo.I
 }
 
 func (h *JoinCommandHandler) respondError(s *discordgo.Session, i *discordgo.InteractionCreate, message string) error {
-	return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+	return textcmd.Respond(s, i.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseChannelMessageWithSource,
 		Data: &discordgo.InteractionResponseData{
 			Content: "❌ " + message,
@@ -376,7 +651,7 @@ func (h *JoinCommandHandler) respondError(s *discordgo.Session, i *discordgo.Int
 }
 
 func (h *LeaveCommandHandler) respondSuccess(s *discordgo.Session, i *discordgo.InteractionCreate, message string) error {
-	return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+	return textcmd.Respond(s, i.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseChannelMessageWithSource,
 		Data: &discordgo.InteractionResponseData{
 			Content: message,
@@ -385,7 +660,7 @@ func (h *LeaveCommandHandler) respondSuccess(s *discordgo.Session, i *discordgo.
 }
 
 func (h *LeaveCommandHandler) respondError(s *discordgo.Session, i *discordgo.InteractionCreate, message string) error {
-	return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+	return textcmd.Respond(s, i.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseChannelMessageWithSource,
 		Data: &discordgo.InteractionResponseData{
 			Content: "❌ " + message,
@@ -399,6 +674,12 @@ type ControlCommandHandler struct {
 	voiceManager      VoiceManager
 	messageQueue      MessageQueue
 	permissionService PermissionService
+	statsService      StatsService
+	auditLog          *AuditLog
+	mutePauser        *MutePauser
+	pauseScheduler    *PauseScheduler
+	queuePanel        *QueuePanel
+	localizer         *Localizer
 	logger            *log.Logger
 }
 
@@ -417,11 +698,41 @@ func NewControlCommandHandler(
 	}
 }
 
+// SetLocalizer sets the localizer used to translate responses
+func (h *ControlCommandHandler) SetLocalizer(localizer *Localizer) {
+	h.localizer = localizer
+}
+
+// SetStatsService enables counting skips in the guild's statistics
+func (h *ControlCommandHandler) SetStatsService(statsService StatsService) {
+	h.statsService = statsService
+}
+
+// SetAuditLog records queue clears in the guild's audit channel
+func (h *ControlCommandHandler) SetAuditLog(auditLog *AuditLog) {
+	h.auditLog = auditLog
+}
+
+// SetMutePauser lets responses explain that playback is paused because the bot is muted
+func (h *ControlCommandHandler) SetMutePauser(mutePauser *MutePauser) {
+	h.mutePauser = mutePauser
+}
+
+// SetPauseScheduler enables the pause-for action and keeps resume from breaking quiet hours
+func (h *ControlCommandHandler) SetPauseScheduler(pauseScheduler *PauseScheduler) {
+	h.pauseScheduler = pauseScheduler
+}
+
+// SetQueuePanel enables posting the queue panel with the panel action
+func (h *ControlCommandHandler) SetQueuePanel(queuePanel *QueuePanel) {
+	h.queuePanel = queuePanel
+}
+
 // Definition returns the Discord slash command definition for TTS control commands
 func (h *ControlCommandHandler) Definition() *discordgo.ApplicationCommand {
 	return &discordgo.ApplicationCommand{
 		Name:        "darrot-control",
-		Description: "Control TTS playback (pause, resume, skip)",
+		Description: "Control TTS playback (pause, pause-for, resume, skip, clear, panel)",
 		Options: []*discordgo.ApplicationCommandOption{
 			{
 				Type:        discordgo.ApplicationCommandOptionString,
@@ -433,6 +744,10 @@ func (h *ControlCommandHandler) Definition() *discordgo.ApplicationCommand {
 						Name:  "pause",
 						Value: "pause",
 					},
+					{
+						Name:  "pause-for",
+						Value: "pause-for",
+					},
 					{
 						Name:  "resume",
 						Value: "resume",
@@ -441,8 +756,24 @@ func (h *ControlCommandHandler) Definition() *discordgo.ApplicationCommand {
 						Name:  "skip",
 						Value: "skip",
 					},
+					{
+						Name:  "clear",
+						Value: "clear",
+					},
+					{
+						Name:  "panel",
+						Value: "panel",
+					},
 				},
 			},
+			{
+				Type:        discordgo.ApplicationCommandOptionInteger,
+				Name:        "minutes",
+				Description: fmt.Sprintf("How long pause-for pauses playback (max %d)", MaxPauseMinutes),
+				Required:    false,
+				MinValue:    &[]float64{1}[0],
+				MaxValue:    MaxPauseMinutes,
+			},
 		},
 	}
 }
@@ -451,7 +782,7 @@ func (h *ControlCommandHandler) Definition() *discordgo.ApplicationCommand {
 func (h *ControlCommandHandler) Handle(s *discordgo.Session, i *discordgo.InteractionCreate) error {
 	// Validate guild context
 	if i.GuildID == "" {
-		return h.respondError(s, i, "This command can only be used in a server.")
+		return h.respondError(s, i, h.localizer.T(i.GuildID, "common.guild_only"))
 	}
 
 	userID := i.Member.User.ID
@@ -459,92 +790,174 @@ func (h *ControlCommandHandler) Handle(s *discordgo.Session, i *discordgo.Intera
 
 	// Validate permissions
 	if err := h.ValidatePermissions(userID, guildID); err != nil {
-		return h.respondError(s, i, fmt.Sprintf("Permission denied: %v", err))
+		return h.respondError(s, i, h.localizer.T(guildID, "common.permission_denied", err))
 	}
 
 	// Check if bot is connected to a voice channel
-	connection, exists := h.voiceManager.GetConnection(guildID)
-	if !exists {
-		return h.respondError(s, i, "I'm not currently in a voice channel in this server.")
+	if _, exists := h.voiceManager.GetConnection(guildID); !exists {
+		return h.respondError(s, i, h.localizer.T(guildID, "common.not_in_voice"))
 	}
 
 	// Extract command options
-	options := i.ApplicationCommandData().Options
-	action := options[0].StringValue()
+	opts := options.FromInteraction(i)
+	action, err := opts.RequiredString("action")
+	if err != nil {
+		return h.respondError(s, i, h.localizer.OptionError(guildID, err))
+	}
 
 	// Execute the requested action
 	switch action {
 	case "pause":
-		return h.handlePause(s, i, guildID, connection)
+		return h.handlePause(s, i, guildID)
+	case "pause-for":
+		return h.handlePauseFor(s, i, guildID, opts)
 	case "resume":
-		return h.handleResume(s, i, guildID, connection)
+		return h.handleResume(s, i, guildID)
 	case "skip":
-		return h.handleSkip(s, i, guildID, connection)
+		return h.handleSkip(s, i, guildID)
+	case "clear":
+		return h.handleClear(s, i, guildID, userID)
+	case "panel":
+		return h.handlePanel(s, i, guildID)
 	default:
-		return h.respondError(s, i, "Invalid action. Use pause, resume, or skip.")
+		return h.respondError(s, i, h.localizer.T(guildID, "control.invalid_action"))
 	}
 }
 
+// playback returns the playback control shared with voice commands and the queue panel
+func (h *ControlCommandHandler) playback() playbackControl {
+	return playbackControl{h.voiceManager, h.messageQueue, h.statsService, h.mutePauser, h.logger}
+}
+
 // handlePause pauses TTS playback
-func (h *ControlCommandHandler) handlePause(s *discordgo.Session, i *discordgo.InteractionCreate, guildID string, connection *VoiceConnection) error {
-	if connection.IsPaused {
-		return h.respondError(s, i, "TTS is already paused.")
+func (h *ControlCommandHandler) handlePause(s *discordgo.Session, i *discordgo.InteractionCreate, guildID string) error {
+	paused, err := h.playback().pause(guildID)
+	if err != nil {
+		return h.respondError(s, i, h.localizer.T(guildID, "control.pause_failed", err))
+	}
+	if !paused {
+		if h.mutePauser.KeepPaused(guildID) {
+			return h.respondSuccess(s, i, h.localizer.T(guildID, "control.paused_after_unmute"))
+		}
+		if h.pauseScheduler.Cancel(guildID) {
+			return h.respondSuccess(s, i, h.localizer.T(guildID, "control.paused_indefinitely"))
+		}
+		return h.respondError(s, i, h.localizer.T(guildID, "control.already_paused"))
+	}
+
+	return h.respondSuccess(s, i, h.localizer.T(guildID, "control.paused"))
+}
+
+// handlePauseFor pauses TTS playback and resumes it automatically after the given minutes
+func (h *ControlCommandHandler) handlePauseFor(s *discordgo.Session, i *discordgo.InteractionCreate, guildID string, opts options.Set) error {
+	if h.pauseScheduler == nil {
+		return h.respondError(s, i, h.localizer.T(guildID, "control.pause_for_unavailable"))
+	}
+
+	minutes, ok, err := opts.IntInRange("minutes", 1, MaxPauseMinutes)
+	if err != nil {
+		return h.respondError(s, i, h.localizer.OptionError(guildID, err))
+	}
+	if !ok {
+		return h.respondError(s, i, h.localizer.OptionError(guildID, options.Missing("minutes")))
 	}
 
-	if err := h.voiceManager.PausePlayback(guildID); err != nil {
-		return h.respondError(s, i, fmt.Sprintf("Failed to pause TTS: %v", err))
+	until, err := h.pauseScheduler.PauseFor(guildID, time.Duration(minutes)*time.Minute)
+	if err != nil {
+		return h.respondError(s, i, h.localizer.T(guildID, "control.pause_failed", err))
 	}
 
-	return h.respondSuccess(s, i, "⏸️ TTS playback paused. Use `/tts-control resume` to continue.")
+	return h.respondSuccess(s, i, h.localizer.T(guildID, "control.paused_for", minutes, until.Unix()))
 }
 
 // handleResume resumes TTS playback
-func (h *ControlCommandHandler) handleResume(s *discordgo.Session, i *discordgo.InteractionCreate, guildID string, connection *VoiceConnection) error {
-	if !connection.IsPaused {
-		return h.respondError(s, i, "TTS is not currently paused.")
+func (h *ControlCommandHandler) handleResume(s *discordgo.Session, i *discordgo.InteractionCreate, guildID string) error {
+	if !h.voiceManager.IsPaused(guildID) {
+		return h.respondError(s, i, h.localizer.T(guildID, "control.not_paused"))
+	}
+
+	// Quiet hours end on their own schedule
+	if end, quiet := h.pauseScheduler.QuietHoursEnd(guildID); quiet {
+		return h.respondError(s, i, h.localizer.T(guildID, "control.quiet_hours", end.Unix()))
 	}
+	h.pauseScheduler.Cancel(guildID)
 
-	if err := h.voiceManager.ResumePlayback(guildID); err != nil {
-		return h.respondError(s, i, fmt.Sprintf("Failed to resume TTS: %v", err))
+	_, deferred, err := h.playback().resume(guildID)
+	if err != nil {
+		return h.respondError(s, i, h.localizer.T(guildID, "control.resume_failed", err))
+	}
+	if deferred {
+		return h.respondSuccess(s, i, h.localizer.T(guildID, "control.resume_when_unmuted", h.messageQueue.Size(guildID)))
 	}
 
 	queueSize := h.messageQueue.Size(guildID)
 	var message string
 	if queueSize > 0 {
-		message = fmt.Sprintf("▶️ TTS playback resumed. %d message(s) in queue.", queueSize)
+		message = h.localizer.T(guildID, "control.resumed", queueSize)
 	} else {
-		message = "▶️ TTS playback resumed. No messages currently in queue."
+		message = h.localizer.T(guildID, "control.resumed_empty")
 	}
 
 	return h.respondSuccess(s, i, message)
 }
 
 // handleSkip skips the current message and proceeds to the next
-func (h *ControlCommandHandler) handleSkip(s *discordgo.Session, i *discordgo.InteractionCreate, guildID string, connection *VoiceConnection) error {
-	// Skip current playing message if any
-	if err := h.voiceManager.SkipCurrentMessage(guildID); err != nil {
-		h.logger.Printf("Warning: Failed to skip current message: %v", err)
-	}
-
-	// Skip next message in queue
-	skippedMessage, err := h.messageQueue.SkipNext(guildID)
+func (h *ControlCommandHandler) handleSkip(s *discordgo.Session, i *discordgo.InteractionCreate, guildID string) error {
+	skippedMessage, err := h.playback().skip(guildID)
 	if err != nil {
-		return h.respondError(s, i, fmt.Sprintf("Failed to skip message: %v", err))
+		return h.respondError(s, i, h.localizer.T(guildID, "control.skip_failed", err))
 	}
-
 	if skippedMessage == nil {
-		return h.respondError(s, i, "No messages in queue to skip.")
+		return h.respondError(s, i, h.localizer.T(guildID, "control.nothing_to_skip"))
 	}
 
 	queueSize := h.messageQueue.Size(guildID)
 	var message string
 	if queueSize > 0 {
-		message = fmt.Sprintf("⏭️ Skipped message from **%s**. %d message(s) remaining in queue.", skippedMessage.Username, queueSize)
+		message = h.localizer.T(guildID, "control.skipped", skippedMessage.Username, queueSize)
 	} else {
-		message = fmt.Sprintf("⏭️ Skipped message from **%s**. Queue is now empty.", skippedMessage.Username)
+		message = h.localizer.T(guildID, "control.skipped_empty", skippedMessage.Username)
 	}
 
-	return h.respondSuccess(s, i, message)
+	return h.respondSuccess(s, i, h.withMuteNotice(guildID, message))
+}
+
+// handleClear removes every queued message. The message being spoken finishes playing.
+func (h *ControlCommandHandler) handleClear(s *discordgo.Session, i *discordgo.InteractionCreate, guildID, userID string) error {
+	cleared := h.messageQueue.Size(guildID)
+	if cleared == 0 {
+		return h.respondError(s, i, h.localizer.T(guildID, "control.nothing_to_clear"))
+	}
+
+	if err := h.messageQueue.Clear(guildID); err != nil {
+		return h.respondError(s, i, h.localizer.T(guildID, "control.clear_failed", err))
+	}
+
+	err := h.respondSuccess(s, i, h.withMuteNotice(guildID, h.localizer.T(guildID, "control.cleared", cleared)))
+	h.auditLog.RecordQueueClear(guildID, userID, cleared)
+	return err
+}
+
+// handlePanel posts the live queue panel in the paired text channel
+func (h *ControlCommandHandler) handlePanel(s *discordgo.Session, i *discordgo.InteractionCreate, guildID string) error {
+	if h.queuePanel == nil {
+		return h.respondError(s, i, h.localizer.T(guildID, "control.panel_unavailable"))
+	}
+
+	channelID, err := h.queuePanel.Show(guildID)
+	if err != nil {
+		return h.respondError(s, i, h.localizer.T(guildID, "control.panel_failed", err))
+	}
+
+	return h.respondSuccess(s, i, h.localizer.T(guildID, "control.panel_posted", channelMention(channelID)))
+}
+
+// withMuteNotice adds a note to a response when playback is paused because the bot is muted
+func (h *ControlCommandHandler) withMuteNotice(guildID, message string) string {
+	if !h.mutePauser.IsMuted(guildID) {
+		return message
+	}
+	return message + "\n" + h.localizer.T(guildID, "control.muted_notice")
 }
 
 // ValidatePermissions validates that the user has permission to control the bot
@@ -555,7 +968,7 @@ func (h *ControlCommandHandler) ValidatePermissions(userID, guildID string) erro
 	}
 
 	if !canControl {
-		return fmt.Errorf("you don't have permission to control the bot")
+		return withKind(ErrPermission, fmt.Errorf("you don't have permission to control the bot"))
 	}
 
 	return nil
@@ -569,7 +982,7 @@ func (h *ControlCommandHandler) ValidateChannelAccess(userID, channelID string)
 // Helper methods for response handling
 
 func (h *ControlCommandHandler) respondSuccess(s *discordgo.Session, i *discordgo.InteractionCreate, message string) error {
-	return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+	return textcmd.Respond(s, i.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseChannelMessageWithSource,
 		Data: &discordgo.InteractionResponseData{
 			Content: message,
@@ -578,7 +991,7 @@ func (h *ControlCommandHandler) respondSuccess(s *discordgo.Session, i *discordg
 }
 
 func (h *ControlCommandHandler) respondError(s *discordgo.Session, i *discordgo.InteractionCreate, message string) error {
-	return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+	return textcmd.Respond(s, i.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseChannelMessageWithSource,
 		Data: &discordgo.InteractionResponseData{
 			Content: "❌ " + message,
@@ -587,10 +1000,13 @@ func (h *ControlCommandHandler) respondError(s *discordgo.Session, i *discordgo.
 	})
 }
 
-// OptInCommandHandler handles user opt-in and opt-out commands for TTS
+// OptInCommandHandler handles user opt-in and opt-out commands for TTS, and the channels
+// users' messages are read from
 type OptInCommandHandler struct {
-	userService UserService
-	logger      *log.Logger
+	userService    UserService
+	channelService ChannelService
+	localizer      *Localizer
+	logger         *log.Logger
 }
 
 // NewOptInCommandHandler creates a new opt-in command handler
@@ -604,6 +1020,16 @@ func NewOptInCommandHandler(
 	}
 }
 
+// SetLocalizer sets the localizer used to translate responses
+func (h *OptInCommandHandler) SetLocalizer(localizer *Localizer) {
+	h.localizer = localizer
+}
+
+// SetChannelService lets users only add paired text channels to their read channels
+func (h *OptInCommandHandler) SetChannelService(channelService ChannelService) {
+	h.channelService = channelService
+}
+
 // Definition returns the Discord slash command definition for the opt-in command
 func (h *OptInCommandHandler) Definition() *discordgo.ApplicationCommand {
 	return &discordgo.ApplicationCommand{
@@ -628,8 +1054,30 @@ func (h *OptInCommandHandler) Definition() *discordgo.ApplicationCommand {
 						Name:  "status",
 						Value: "status",
 					},
+					{
+						Name:  "channels",
+						Value: "channels",
+					},
 				},
 			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "operation",
+				Description: "With channels: add or remove a channel your messages are read from, or list them",
+				Required:    false,
+				Choices: []*discordgo.ApplicationCommandOptionChoice{
+					{Name: "list", Value: "list"},
+					{Name: "add", Value: "add"},
+					{Name: "remove", Value: "remove"},
+				},
+			},
+			{
+				Type:         discordgo.ApplicationCommandOptionChannel,
+				Name:         "channel",
+				Description:  "With channels: the paired text channel to add or remove",
+				Required:     false,
+				ChannelTypes: textChannelTypes,
+			},
 		},
 	}
 }
@@ -638,15 +1086,18 @@ func (h *OptInCommandHandler) Definition() *discordgo.ApplicationCommand {
 func (h *OptInCommandHandler) Handle(s *discordgo.Session, i *discordgo.InteractionCreate) error {
 	// Validate guild context
 	if i.GuildID == "" {
-		return h.respondError(s, i, "This command can only be used in a server.")
+		return h.respondError(s, i, h.localizer.T(i.GuildID, "common.guild_only"))
 	}
 
 	userID := i.Member.User.ID
 	guildID := i.GuildID
 
 	// Extract command options
-	options := i.ApplicationCommandData().Options
-	action := options[0].StringValue()
+	opts := options.FromInteraction(i)
+	action, err := opts.RequiredString("action")
+	if err != nil {
+		return h.respondError(s, i, h.localizer.OptionError(guildID, err))
+	}
 
 	// Execute the requested action
 	switch action {
@@ -656,9 +1107,77 @@ func (h *OptInCommandHandler) Handle(s *discordgo.Session, i *discordgo.Interact
 		return h.handleOptOut(s, i, userID, guildID)
 	case "status":
 		return h.handleStatus(s, i, userID, guildID)
+	case "channels":
+		return h.handleReadChannels(s, i, userID, guildID, opts)
+	default:
+		return h.respondError(s, i, h.localizer.T(guildID, "optin.invalid_action"))
+	}
+}
+
+// handleReadChannels lists, adds or removes the paired text channels the user's messages
+// are read from
+func (h *OptInCommandHandler) handleReadChannels(s *discordgo.Session, i *discordgo.InteractionCreate, userID, guildID string, opts options.Set) error {
+	allowlist, ok := h.userService.(ReadChannelAllowlist)
+	if !ok {
+		return h.respondError(s, i, h.localizer.T(guildID, "optin.channels.unavailable"))
+	}
+
+	operation, _ := opts.String("operation")
+	if operation == "" || operation == "list" {
+		return h.handleListReadChannels(s, i, allowlist, userID, guildID)
+	}
+
+	channelID, ok := opts.ChannelID("channel")
+	if !ok {
+		return h.respondError(s, i, h.localizer.OptionError(guildID, options.Missing("channel")))
+	}
+
+	switch operation {
+	case "add":
+		if h.channelService != nil && !h.channelService.IsChannelPaired(guildID, channelID) {
+			return h.respondError(s, i, h.localizer.T(guildID, "optin.channels.not_paired", channelID))
+		}
+		channels, err := allowlist.GetReadChannels(userID, guildID)
+		if err != nil {
+			h.logger.Printf("Error getting read channels for user %s in guild %s: %v", userID, guildID, err)
+			return h.respondError(s, i, h.localizer.T(guildID, "optin.channels.failed"))
+		}
+		if !slices.Contains(channels, channelID) && len(channels) >= MaxReadChannels {
+			return h.respondError(s, i, h.localizer.T(guildID, "optin.channels.too_many", MaxReadChannels))
+		}
+		if err := allowlist.AddReadChannel(userID, guildID, channelID); err != nil {
+			h.logger.Printf("Error adding read channel %s for user %s in guild %s: %v", channelID, userID, guildID, err)
+			return h.respondError(s, i, h.localizer.T(guildID, "optin.channels.failed"))
+		}
+		return h.respondSuccess(s, i, h.localizer.T(guildID, "optin.channels.added", channelID))
+	case "remove":
+		if err := allowlist.RemoveReadChannel(userID, guildID, channelID); err != nil {
+			h.logger.Printf("Error removing read channel %s for user %s in guild %s: %v", channelID, userID, guildID, err)
+			return h.respondError(s, i, h.localizer.T(guildID, "optin.channels.failed"))
+		}
+		return h.respondSuccess(s, i, h.localizer.T(guildID, "optin.channels.removed", channelID))
 	default:
-		return h.respondError(s, i, "Invalid action. Use opt-in, opt-out, or status.")
+		return h.respondError(s, i, h.localizer.T(guildID, "optin.invalid_action"))
+	}
+}
+
+// handleListReadChannels shows the channels the user's messages are read from
+func (h *OptInCommandHandler) handleListReadChannels(s *discordgo.Session, i *discordgo.InteractionCreate, allowlist ReadChannelAllowlist, userID, guildID string) error {
+	channels, err := allowlist.GetReadChannels(userID, guildID)
+	if err != nil {
+		h.logger.Printf("Error getting read channels for user %s in guild %s: %v", userID, guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "optin.channels.failed"))
 	}
+
+	if len(channels) == 0 {
+		return h.respondSuccess(s, i, h.localizer.T(guildID, "optin.channels.all"))
+	}
+
+	mentions := make([]string, len(channels))
+	for idx, channelID := range channels {
+		mentions[idx] = channelMention(channelID)
+	}
+	return h.respondSuccess(s, i, h.localizer.T(guildID, "optin.channels.list", strings.Join(mentions, ", ")))
 }
 
 // handleOptIn opts the user in for TTS message reading
@@ -667,20 +1186,20 @@ func (h *OptInCommandHandler) handleOptIn(s *discordgo.Session, i *discordgo.Int
 	isOptedIn, err := h.userService.IsOptedIn(userID, guildID)
 	if err != nil {
 		h.logger.Printf("Error checking opt-in status for user %s in guild %s: %v", userID, guildID, err)
-		return h.respondError(s, i, "Failed to check your current opt-in status.")
+		return h.respondError(s, i, h.localizer.T(guildID, "optin.status_failed"))
 	}
 
 	if isOptedIn {
-		return h.respondError(s, i, "You are already opted-in for TTS message reading in this server.")
+		return h.respondError(s, i, h.localizer.T(guildID, "optin.already_opted_in"))
 	}
 
 	// Opt the user in
 	if err := h.userService.SetOptInStatus(userID, guildID, true); err != nil {
 		h.logger.Printf("Error opting in user %s in guild %s: %v", userID, guildID, err)
-		return h.respondError(s, i, "Failed to opt you in for TTS. Please try again.")
+		return h.respondError(s, i, h.localizer.T(guildID, "optin.opt_in_failed"))
 	}
 
-	return h.respondSuccess(s, i, "✅ You have been opted-in for TTS message reading in this server. Your messages will now be read aloud when the bot is active in voice channels.")
+	return h.respondSuccess(s, i, h.localizer.T(guildID, "optin.opted_in"))
 }
 
 // handleOptOut opts the user out of TTS message reading
@@ -689,20 +1208,20 @@ func (h *OptInCommandHandler) handleOptOut(s *discordgo.Session, i *discordgo.In
 	isOptedIn, err := h.userService.IsOptedIn(userID, guildID)
 	if err != nil {
 		h.logger.Printf("Error checking opt-in status for user %s in guild %s: %v", userID, guildID, err)
-		return h.respondError(s, i, "Failed to check your current opt-in status.")
+		return h.respondError(s, i, h.localizer.T(guildID, "optin.status_failed"))
 	}
 
 	if !isOptedIn {
-		return h.respondError(s, i, "You are already opted-out of TTS message reading in this server.")
+		return h.respondError(s, i, h.localizer.T(guildID, "optin.already_opted_out"))
 	}
 
 	// Opt the user out
 	if err := h.userService.SetOptInStatus(userID, guildID, false); err != nil {
 		h.logger.Printf("Error opting out user %s in guild %s: %v", userID, guildID, err)
-		return h.respondError(s, i, "Failed to opt you out of TTS. Please try again.")
+		return h.respondError(s, i, h.localizer.T(guildID, "optin.opt_out_failed"))
 	}
 
-	return h.respondSuccess(s, i, "✅ You have been opted-out of TTS message reading in this server. Your messages will no longer be read aloud.")
+	return h.respondSuccess(s, i, h.localizer.T(guildID, "optin.opted_out"))
 }
 
 // handleStatus shows the user's current opt-in status
@@ -711,14 +1230,14 @@ func (h *OptInCommandHandler) handleStatus(s *discordgo.Session, i *discordgo.In
 	isOptedIn, err := h.userService.IsOptedIn(userID, guildID)
 	if err != nil {
 		h.logger.Printf("Error checking opt-in status for user %s in guild %s: %v", userID, guildID, err)
-		return h.respondError(s, i, "Failed to check your current opt-in status.")
+		return h.respondError(s, i, h.localizer.T(guildID, "optin.status_failed"))
 	}
 
 	var statusMessage string
 	if isOptedIn {
-		statusMessage = "✅ **Opted-in**: Your messages will be read aloud when the bot is active in voice channels.\n\nUse `/tts-optin opt-out` to opt out of TTS message reading."
+		statusMessage = h.localizer.T(guildID, "optin.status_opted_in")
 	} else {
-		statusMessage = "❌ **Opted-out**: Your messages will not be read aloud.\n\nUse `/tts-optin opt-in` to opt in for TTS message reading."
+		statusMessage = h.localizer.T(guildID, "optin.status_opted_out")
 	}
 
 	return h.respondSuccess(s, i, statusMessage)
@@ -739,7 +1258,7 @@ func (h *OptInCommandHandler) ValidateChannelAccess(userID, channelID string) er
 // Helper methods for response handling
 
 func (h *OptInCommandHandler) respondSuccess(s *discordgo.Session, i *discordgo.InteractionCreate, message string) error {
-	return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+	return textcmd.Respond(s, i.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseChannelMessageWithSource,
 		Data: &discordgo.InteractionResponseData{
 			Content: message,
@@ -749,7 +1268,7 @@ func (h *OptInCommandHandler) respondSuccess(s *discordgo.Session, i *discordgo.
 }
 
 func (h *OptInCommandHandler) respondError(s *discordgo.Session, i *discordgo.InteractionCreate, message string) error {
-	return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+	return textcmd.Respond(s, i.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseChannelMessageWithSource,
 		Data: &discordgo.InteractionResponseData{
 			Content: "❌ " + message,
@@ -760,11 +1279,25 @@ func (h *OptInCommandHandler) respondError(s *discordgo.Session, i *discordgo.In
 
 // ConfigCommandHandler handles administrator TTS configuration commands
 type ConfigCommandHandler struct {
-	configService     ConfigService
-	permissionService PermissionService
-	ttsManager        TTSManager
-	messageQueue      MessageQueue
-	logger            *log.Logger
+	configService      ConfigService
+	permissionService  PermissionService
+	ttsManager         TTSManager
+	messageQueue       MessageQueue
+	quotaService       TTSQuotaService
+	contentPolicy      *ContentPolicy
+	voiceAnnouncer     *VoiceAnnouncer
+	reactionSummarizer *ReactionSummarizer
+	skipNotes          *SkipNotes
+	voiceCommands      *VoiceCommandListener
+	privacyService     *PrivacyService
+	features           *FeatureFlagService
+	moderationService  ModerationService
+	auditLog           *AuditLog
+	audioOutputs       *AudioOutputs
+	commandPermissions *CommandPermissionSync
+	httpClient         *http.Client
+	localizer          *Localizer
+	logger             *log.Logger
 }
 
 // NewConfigCommandHandler creates a new configuration command handler
@@ -780,42 +1313,86 @@ func NewConfigCommandHandler(
 		permissionService: permissionService,
 		ttsManager:        ttsManager,
 		messageQueue:      messageQueue,
+		httpClient:        &http.Client{Timeout: attachmentDownloadTimeout},
 		logger:            logger,
 	}
 }
 
+// SetQuotaService enables the quota subcommand and usage reporting
+func (h *ConfigCommandHandler) SetQuotaService(quotaService TTSQuotaService) {
+	h.quotaService = quotaService
+}
+
+// SetContentPolicy enables the privacy subcommand
+func (h *ConfigCommandHandler) SetContentPolicy(policy *ContentPolicy) {
+	h.contentPolicy = policy
+}
+
+// SetAudioOutputs offers the outputs the operator set up in the output subcommand
+func (h *ConfigCommandHandler) SetAudioOutputs(outputs *AudioOutputs) {
+	h.audioOutputs = outputs
+}
+
+// SetCommandPermissionSync lets the roles subcommand mirror the required roles to
+// Discord's command permissions
+func (h *ConfigCommandHandler) SetCommandPermissionSync(sync *CommandPermissionSync) {
+	h.commandPermissions = sync
+}
+
+// SetVoiceAnnouncer enables the announcements subcommand
+func (h *ConfigCommandHandler) SetVoiceAnnouncer(announcer *VoiceAnnouncer) {
+	h.voiceAnnouncer = announcer
+}
+
+// SetReactionSummarizer enables reaction summaries in the announcements subcommand
+func (h *ConfigCommandHandler) SetReactionSummarizer(summarizer *ReactionSummarizer) {
+	h.reactionSummarizer = summarizer
+}
+
+// SetSkipNotes enables skip notes in the announcements subcommand
+func (h *ConfigCommandHandler) SetSkipNotes(skipNotes *SkipNotes) {
+	h.skipNotes = skipNotes
+}
+
+// SetVoiceCommandListener enables the voice-commands subcommand
+func (h *ConfigCommandHandler) SetVoiceCommandListener(listener *VoiceCommandListener) {
+	h.voiceCommands = listener
+}
+
+// SetPrivacyService enables the opt-in-notice subcommand
+func (h *ConfigCommandHandler) SetPrivacyService(privacyService *PrivacyService) {
+	h.privacyService = privacyService
+}
+
+// SetFeatureFlags enables the features subcommand
+func (h *ConfigCommandHandler) SetFeatureFlags(features *FeatureFlagService) {
+	h.features = features
+}
+
+// SetModerationService includes the moderation blocklist in configuration exports, imports
+// and profiles
+func (h *ConfigCommandHandler) SetModerationService(moderationService ModerationService) {
+	h.moderationService = moderationService
+}
+
+// SetAuditLog enables the audit subcommand and records configuration changes in the
+// guild's audit channel
+func (h *ConfigCommandHandler) SetAuditLog(auditLog *AuditLog) {
+	h.auditLog = auditLog
+}
+
+// SetLocalizer sets the localizer used to translate responses and enables the language subcommand
+func (h *ConfigCommandHandler) SetLocalizer(localizer *Localizer) {
+	h.localizer = localizer
+}
+
 // Definition returns the Discord slash command definition for the config command
 func (h *ConfigCommandHandler) Definition() *discordgo.ApplicationCommand {
 	return &discordgo.ApplicationCommand{
-		Name:        "darrot-config",
-		Description: "Configure TTS settings for this server (Administrator only)",
+		Name:                     "darrot-config",
+		Description:              "Configure TTS settings for this server (Administrator only)",
+		DefaultMemberPermissions: adminCommandPermissions(),
 		Options: []*discordgo.ApplicationCommandOption{
-			{
-				Type:        discordgo.ApplicationCommandOptionSubCommand,
-				Name:        "roles",
-				Description: "Configure required roles for bot invitations",
-				Options: []*discordgo.ApplicationCommandOption{
-					{
-						Type:        discordgo.ApplicationCommandOptionString,
-						Name:        "action",
-						Description: "Action to perform",
-						Required:    true,
-						Choices: []*discordgo.ApplicationCommandOptionChoice{
-							{Name: "set", Value: "set"},
-							{Name: "add", Value: "add"},
-							{Name: "remove", Value: "remove"},
-							{Name: "clear", Value: "clear"},
-							{Name: "list", Value: "list"},
-						},
-					},
-					{
-						Type:        discordgo.ApplicationCommandOptionRole,
-						Name:        "role",
-						Description: "Role to add or remove",
-						Required:    false,
-					},
-				},
-			},
 			{
 				Type:        discordgo.ApplicationCommandOptionSubCommand,
 				Name:        "voice",
@@ -830,13 +1407,17 @@ func (h *ConfigCommandHandler) Definition() *discordgo.ApplicationCommand {
 							{Name: "voice", Value: "voice"},
 							{Name: "speed", Value: "speed"},
 							{Name: "volume", Value: "volume"},
+							{Name: "pitch", Value: "pitch"},
+							{Name: "effects", Value: "effects"},
+							{Name: "style", Value: "style"},
+							{Name: "loudness", Value: "loudness"},
 							{Name: "list-voices", Value: "list-voices"},
 						},
 					},
 					{
 						Type:        discordgo.ApplicationCommandOptionString,
 						Name:        "value",
-						Description: "Value to set (voice name, speed 0.25-4.0, volume 0.0-1.0)",
+						Description: "Value to set (voice name, speed 0.25-4.0, volume 0.0-1.0, pitch -20 to 20, effects, style, loudness)",
 						Required:    false,
 					},
 				},
@@ -853,6 +1434,13 @@ func (h *ConfigCommandHandler) Definition() *discordgo.ApplicationCommand {
 						Required:    true,
 						Choices: []*discordgo.ApplicationCommandOptionChoice{
 							{Name: "max-size", Value: "max-size"},
+							{Name: "truncation", Value: "truncation"},
+							{Name: "max-length", Value: "max-length"},
+							{Name: "user-limit", Value: "user-limit"},
+							{Name: "order", Value: "order"},
+							{Name: "hold-back", Value: "hold-back"},
+							{Name: "catch-up", Value: "catch-up"},
+							{Name: "pacing", Value: "pacing"},
 							{Name: "show", Value: "show"},
 						},
 					},
@@ -864,355 +1452,2670 @@ func (h *ConfigCommandHandler) Definition() *discordgo.ApplicationCommand {
 						MinValue:    &[]float64{1}[0],
 						MaxValue:    50,
 					},
-				},
-			},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "mode",
+						Description: "How messages that are too long are read",
+						Required:    false,
+						Choices: []*discordgo.ApplicationCommandOptionChoice{
+							{Name: "hard", Value: string(TruncationModeHard)},
+							{Name: "sentence", Value: string(TruncationModeSentence)},
+							{Name: "split", Value: string(TruncationModeSplit)},
+							{Name: "ask", Value: string(TruncationModeAsk)},
+						},
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionInteger,
+						Name:        "length",
+						Description: fmt.Sprintf("Longest utterance in characters (%d-%d)", MinMessageLength, MaxMessageLength),
+						Required:    false,
+						MinValue:    &[]float64{MinMessageLength}[0],
+						MaxValue:    MaxMessageLength,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionInteger,
+						Name:        "per-minute",
+						Description: fmt.Sprintf("Messages read per user per minute (0 turns the limit off, max %d)", MaxUserMessagesPerMinute),
+						Required:    false,
+						MinValue:    &[]float64{0}[0],
+						MaxValue:    MaxUserMessagesPerMinute,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "order",
+						Description: "Order queued messages are read in",
+						Required:    false,
+						Choices: []*discordgo.ApplicationCommandOptionChoice{
+							{Name: "fifo", Value: string(QueueOrderFIFO)},
+							{Name: "round-robin", Value: string(QueueOrderRoundRobin)},
+						},
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionInteger,
+						Name:        "seconds",
+						Description: fmt.Sprintf("Seconds to wait for more messages from the same author (0 turns it off, max %d)", MaxHoldBackSeconds),
+						Required:    false,
+						MinValue:    &[]float64{0}[0],
+						MaxValue:    MaxHoldBackSeconds,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "catch-up",
+						Description: "What happens to messages that reach the bot late, such as after a reconnect",
+						Required:    false,
+						Choices: []*discordgo.ApplicationCommandOptionChoice{
+							{Name: "read", Value: string(CatchUpRead)},
+							{Name: "skip", Value: string(CatchUpSkip)},
+							{Name: "summary", Value: string(CatchUpSummary)},
+						},
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionInteger,
+						Name:        "max-age",
+						Description: fmt.Sprintf("Seconds after which a message counts as late (%d-%d)", MinMessageAgeCutoff, MaxMessageAgeCutoff),
+						Required:    false,
+						MinValue:    &[]float64{MinMessageAgeCutoff}[0],
+						MaxValue:    MaxMessageAgeCutoff,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionInteger,
+						Name:        "pause",
+						Description: fmt.Sprintf("Milliseconds of pause between messages (0 turns it off, max %d)", MaxPauseMillis),
+						Required:    false,
+						MinValue:    &[]float64{0}[0],
+						MaxValue:    MaxPauseMillis,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionInteger,
+						Name:        "author-pause",
+						Description: fmt.Sprintf("Milliseconds of pause when the author changes (0 turns it off, max %d)", MaxAuthorPauseMillis),
+						Required:    false,
+						MinValue:    &[]float64{0}[0],
+						MaxValue:    MaxAuthorPauseMillis,
+					},
+				},
+			},
 			{
 				Type:        discordgo.ApplicationCommandOptionSubCommand,
-				Name:        "show",
-				Description: "Show current TTS configuration",
+				Name:        "quota",
+				Description: "Configure the daily TTS character budget",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "setting",
+						Description: "Quota setting to configure",
+						Required:    true,
+						Choices: []*discordgo.ApplicationCommandOptionChoice{
+							{Name: "daily-budget", Value: "daily-budget"},
+							{Name: "premium-budget", Value: "premium-budget"},
+							{Name: "show", Value: "show"},
+						},
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionInteger,
+						Name:        "value",
+						Description: "Characters per day (0 = the bot default, or no separate cap for premium voices)",
+						Required:    false,
+						MinValue:    &[]float64{0}[0],
+					},
+				},
 			},
-		},
-	}
-}
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "privacy",
+				Description: "Control whether message content may appear in logs and caches",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "content-retention",
+						Description: "Content retention mode",
+						Required:    true,
+						Choices: []*discordgo.ApplicationCommandOptionChoice{
+							{Name: "full", Value: string(ContentRetentionFull)},
+							{Name: "metadata-only", Value: string(ContentRetentionMetadata)},
+							{Name: "show", Value: "show"},
+						},
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "announcements",
+				Description: "Announce users joining or leaving the voice channel, summarize reactions and note skipped messages",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "join-leave",
+						Description: "Join/leave announcements",
+						Required:    false,
+						Choices: []*discordgo.ApplicationCommandOptionChoice{
+							{Name: "on", Value: "on"},
+							{Name: "off", Value: "off"},
+							{Name: "show", Value: "show"},
+						},
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "reactions",
+						Description: "Spoken summaries of reactions on recent messages",
+						Required:    false,
+						Choices: []*discordgo.ApplicationCommandOptionChoice{
+							{Name: "on", Value: "on"},
+							{Name: "off", Value: "off"},
+							{Name: "show", Value: "show"},
+						},
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "skipped",
+						Description: "Spoken notes on messages skipped for their author or by moderation",
+						Required:    false,
+						Choices: []*discordgo.ApplicationCommandOptionChoice{
+							{Name: "on", Value: "on"},
+							{Name: "off", Value: "off"},
+							{Name: "show", Value: "show"},
+						},
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "voice-commands",
+				Description: "Listen for spoken skip, pause and resume commands in the voice channel",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "listen",
+						Description: "Voice commands",
+						Required:    true,
+						Choices: []*discordgo.ApplicationCommandOptionChoice{
+							{Name: "on", Value: "on"},
+							{Name: "off", Value: "off"},
+							{Name: "show", Value: "show"},
+						},
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "opt-in-notice",
+				Description: "DM users who are opted in automatically with a one-click opt-out",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "dm",
+						Description: "Privacy notice DM",
+						Required:    true,
+						Choices: []*discordgo.ApplicationCommandOptionChoice{
+							{Name: "on", Value: "on"},
+							{Name: "off", Value: "off"},
+							{Name: "show", Value: "show"},
+						},
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "features",
+				Description: "Turn experimental features on or off for this server",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "state",
+						Description: "Feature state, or default to follow the bot's setting",
+						Required:    true,
+						Choices: []*discordgo.ApplicationCommandOptionChoice{
+							{Name: "on", Value: "on"},
+							{Name: "off", Value: "off"},
+							{Name: "default", Value: "default"},
+							{Name: "show", Value: "show"},
+						},
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "feature",
+						Description: "Experimental feature",
+						Required:    false,
+						Choices: []*discordgo.ApplicationCommandOptionChoice{
+							{Name: "voice auto-pause", Value: string(FeatureVoiceAutoPause)},
+							{Name: "attachment narration", Value: string(FeatureAttachmentNarration)},
+							{Name: "emoji reading", Value: string(FeatureEmojiReading)},
+						},
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "language",
+				Description: "Choose the language the bot responds in",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "language",
+						Description: "Response language",
+						Required:    true,
+						Choices:     h.languageChoices(),
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "ignore-prefix",
+				Description: "Skip messages starting with a prefix, such as other bots' commands",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "action",
+						Description: "Action to perform",
+						Required:    true,
+						Choices: []*discordgo.ApplicationCommandOptionChoice{
+							{Name: "add", Value: "add"},
+							{Name: "remove", Value: "remove"},
+							{Name: "clear", Value: "clear"},
+							{Name: "list", Value: "list"},
+						},
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "prefix",
+						Description: "Prefix to add or remove, such as ! or ;;",
+						Required:    false,
+						MaxLength:   MaxIgnorePrefixLength,
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "content",
+				Description: "Choose how links, code blocks, spoilers and emoji are read aloud",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "links",
+						Description: "How links are read",
+						Required:    false,
+						Choices: []*discordgo.ApplicationCommandOptionChoice{
+							{Name: "domain", Value: string(LinkModeDomain)},
+							{Name: "full", Value: string(LinkModeFull)},
+							{Name: "skip", Value: string(LinkModeSkip)},
+						},
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "code-blocks",
+						Description: "How code blocks are read",
+						Required:    false,
+						Choices: []*discordgo.ApplicationCommandOptionChoice{
+							{Name: "summary", Value: string(CodeBlockModeSummary)},
+							{Name: "full", Value: string(CodeBlockModeFull)},
+							{Name: "skip", Value: string(CodeBlockModeSkip)},
+						},
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "spoilers",
+						Description: "How spoiler-tagged text is read",
+						Required:    false,
+						Choices: []*discordgo.ApplicationCommandOptionChoice{
+							{Name: "summary", Value: string(SpoilerModeSummary)},
+							{Name: "full", Value: string(SpoilerModeFull)},
+							{Name: "skip", Value: string(SpoilerModeSkip)},
+						},
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionInteger,
+						Name:        "max-emoji",
+						Description: fmt.Sprintf("Emoji read per message before the rest are summarized (1-%d)", MaxSpokenEmojiLimit),
+						Required:    false,
+						MinValue:    &[]float64{1}[0],
+						MaxValue:    MaxSpokenEmojiLimit,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionBoolean,
+						Name:        "allow-nsfw",
+						Description: "Allow pairing with age-restricted (NSFW) text channels",
+						Required:    false,
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "idle",
+				Description: "Choose when the bot says it is still listening, when it leaves and who it reads to",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionInteger,
+						Name:        "announce-after",
+						Description: fmt.Sprintf("Minutes of silence before saying it is still listening (0 turns it off, max %d)", MaxIdleMinutes),
+						Required:    false,
+						MinValue:    &[]float64{0}[0],
+						MaxValue:    MaxIdleMinutes,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionInteger,
+						Name:        "disconnect-after",
+						Description: fmt.Sprintf("Minutes of silence before leaving the voice channel (0 turns it off, max %d)", MaxIdleMinutes),
+						Required:    false,
+						MinValue:    &[]float64{0}[0],
+						MaxValue:    MaxIdleMinutes,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionInteger,
+						Name:        "min-listeners",
+						Description: fmt.Sprintf("People who must be listening before messages are read (0 turns it off, max %d)", MaxMinListeners),
+						Required:    false,
+						MinValue:    &[]float64{0}[0],
+						MaxValue:    MaxMinListeners,
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "quiet-hours",
+				Description: "Choose daily hours during which the bot does not speak",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "start",
+						Description: "When quiet hours start each day, such as 23:00",
+						Required:    false,
+						MaxLength:   len(clockLayout),
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "end",
+						Description: "When quiet hours end each day, such as 08:00",
+						Required:    false,
+						MaxLength:   len(clockLayout),
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "timezone",
+						Description: "Time zone of the quiet hours, such as Europe/Berlin (default UTC)",
+						Required:    false,
+						MaxLength:   100,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionBoolean,
+						Name:        "off",
+						Description: "Turn quiet hours off",
+						Required:    false,
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "output",
+				Description: "Choose where speech goes besides the voice channel, such as a recording or a stream",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "target",
+						Description: "Extra output for speech; the bot's operator decides which are available",
+						Required:    false,
+						Choices: []*discordgo.ApplicationCommandOptionChoice{
+							{Name: "discord only", Value: string(AudioOutputDiscord)},
+							{Name: "file recording", Value: string(AudioOutputFile)},
+							{Name: "icecast stream", Value: string(AudioOutputIcecast)},
+							{Name: "pulseaudio", Value: string(AudioOutputPulse)},
+						},
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "recording",
+				Description: "Record each voice session and post the recording when the bot leaves",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionBoolean,
+						Name:        "enabled",
+						Description: "Record the bot's speech and post it on /darrot-leave",
+						Required:    false,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionBoolean,
+						Name:        "timestamps",
+						Description: "Also post when each message was read, as WebVTT captions",
+						Required:    false,
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "command-prefix",
+				Description: "Choose how text commands start, for servers without slash commands",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "prefix",
+						Description: "Prefix such as " + DefaultCommandPrefix + ", or off to turn text commands off",
+						Required:    false,
+						MaxLength:   MaxCommandPrefixLength,
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "audit",
+				Description: "Post joins, leaves, configuration changes and recoveries to an audit channel",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "action",
+						Description: "Audit channel action",
+						Required:    true,
+						Choices: []*discordgo.ApplicationCommandOptionChoice{
+							{Name: "set", Value: "set"},
+							{Name: "off", Value: "off"},
+							{Name: "show", Value: "show"},
+						},
+					},
+					{
+						Type:         discordgo.ApplicationCommandOptionChannel,
+						Name:         "channel",
+						Description:  "Text channel that receives audit entries (required for set)",
+						Required:     false,
+						ChannelTypes: textChannelTypes,
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "show",
+				Description: "Show current TTS configuration",
+			},
+		},
+	}
+}
+
+// languageChoices lists every locale in the message catalog, named in its own language
+func (h *ConfigCommandHandler) languageChoices() []*discordgo.ApplicationCommandOptionChoice {
+	catalog := h.localizer.Catalog()
+
+	choices := make([]*discordgo.ApplicationCommandOptionChoice, 0, len(catalog.Locales())+1)
+	for _, locale := range catalog.Locales() {
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{
+			Name:  catalog.T(locale, i18n.LanguageNameKey),
+			Value: locale,
+		})
+	}
+	choices = append(choices, &discordgo.ApplicationCommandOptionChoice{Name: "show", Value: "show"})
+
+	return choices
+}
+
+// Handle processes the config command interaction
+func (h *ConfigCommandHandler) Handle(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	return h.handleSubcommand(s, i, h.routeSubcommand)
+}
+
+// handleSubcommand checks that a server administrator ran a configuration command and
+// passes its subcommand to route. /darrot-access and /darrot-profile share it.
+func (h *ConfigCommandHandler) handleSubcommand(s *discordgo.Session, i *discordgo.InteractionCreate, route func(s *discordgo.Session, i *discordgo.InteractionCreate, guildID, subcommand string, opts options.Set) error) error {
+	// Validate guild context
+	if i.GuildID == "" {
+		return h.respondError(s, i, h.localizer.T(i.GuildID, "common.guild_only"))
+	}
+
+	userID := i.Member.User.ID
+	guildID := i.GuildID
+
+	// Validate administrator permissions
+	if err := h.ValidatePermissions(userID, guildID); err != nil {
+		return h.respondError(s, i, h.localizer.T(guildID, "common.permission_denied", err))
+	}
+
+	// Extract subcommand
+	subcommand, opts, ok := options.FromInteraction(i).Subcommand()
+	if !ok {
+		return h.respondError(s, i, h.localizer.T(guildID, "common.no_subcommand"))
+	}
+
+	return route(s, i, guildID, subcommand, opts)
+}
+
+// routeSubcommand runs a /darrot-config subcommand
+func (h *ConfigCommandHandler) routeSubcommand(s *discordgo.Session, i *discordgo.InteractionCreate, guildID, subcommand string, opts options.Set) error {
+	switch subcommand {
+	case "voice":
+		return h.handleVoiceConfig(s, i, guildID, opts)
+	case "queue":
+		return h.handleQueueConfig(s, i, guildID, opts)
+	case "quota":
+		return h.handleQuotaConfig(s, i, guildID, opts)
+	case "privacy":
+		return h.handlePrivacyConfig(s, i, guildID, opts)
+	case "announcements":
+		return h.handleAnnouncementsConfig(s, i, guildID, opts)
+	case "voice-commands":
+		return h.handleVoiceCommandsConfig(s, i, guildID, opts)
+	case "opt-in-notice":
+		return h.handleOptInNoticeConfig(s, i, guildID, opts)
+	case "features":
+		return h.handleFeaturesConfig(s, i, guildID, opts)
+	case "language":
+		return h.handleLanguageConfig(s, i, guildID, opts)
+	case "ignore-prefix":
+		return h.handleIgnorePrefixConfig(s, i, guildID, opts)
+	case "content":
+		return h.handleContentConfig(s, i, guildID, opts)
+	case "idle":
+		return h.handleIdleConfig(s, i, guildID, opts)
+	case "quiet-hours":
+		return h.handleQuietHoursConfig(s, i, guildID, opts)
+	case "output":
+		return h.handleOutputConfig(s, i, guildID, opts)
+	case "recording":
+		return h.handleRecordingConfig(s, i, guildID, opts)
+	case "command-prefix":
+		return h.handleCommandPrefixConfig(s, i, guildID, opts)
+	case "audit":
+		return h.handleAuditConfig(s, i, guildID, opts)
+	case "show":
+		return h.handleShowConfig(s, i, guildID)
+	default:
+		return h.respondError(s, i, h.localizer.T(guildID, "common.invalid_subcommand"))
+	}
+}
+
+// handleRolesConfig handles role configuration commands
+func (h *ConfigCommandHandler) handleRolesConfig(s *discordgo.Session, i *discordgo.InteractionCreate, guildID string, opts options.Set) error {
+	action, err := opts.RequiredString("action")
+	if err != nil {
+		return h.respondError(s, i, h.localizer.OptionError(guildID, err))
+	}
+
+	switch action {
+	case "list":
+		return h.handleListRoles(s, i, guildID)
+	case "clear":
+		return h.handleClearRoles(s, i, guildID)
+	case "sync", "unsync":
+		return h.handleSyncRoles(s, i, guildID, action == "sync")
+	case "set", "add", "remove":
+		roleID, ok := opts.RoleID("role")
+		if !ok {
+			return h.respondError(s, i, h.localizer.OptionError(guildID, options.Missing("role")))
+		}
+		return h.handleRoleAction(s, i, guildID, action, roleID)
+	default:
+		return h.respondError(s, i, h.localizer.T(guildID, "config.roles.invalid_action"))
+	}
+}
+
+// handleRoleAction handles individual role actions (set, add, remove)
+func (h *ConfigCommandHandler) handleRoleAction(s *discordgo.Session, i *discordgo.InteractionCreate, guildID, action, roleID string) error {
+	currentRoles, err := h.configService.GetRequiredRoles(guildID)
+	if err != nil {
+		h.logger.Printf("Error getting required roles for guild %s: %v", guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "config.roles.get_failed"))
+	}
+
+	var newRoles []string
+	var actionMessage string
+
+	switch action {
+	case "set":
+		newRoles = []string{roleID}
+		actionMessage = h.localizer.T(guildID, "config.roles.set")
+	case "add":
+		// Check if role already exists
+		for _, existingRole := range currentRoles {
+			if existingRole == roleID {
+				return h.respondError(s, i, h.localizer.T(guildID, "config.roles.already_required"))
+			}
+		}
+		currentRoles = append(currentRoles, roleID)
+		newRoles = currentRoles
+		actionMessage = h.localizer.T(guildID, "config.roles.added")
+	case "remove":
+		// Remove role from list
+		for _, existingRole := range currentRoles {
+			if existingRole != roleID {
+				newRoles = append(newRoles, existingRole)
+			}
+		}
+		if len(newRoles) == len(currentRoles) {
+			return h.respondError(s, i, h.localizer.T(guildID, "config.roles.not_required"))
+		}
+		actionMessage = h.localizer.T(guildID, "config.roles.removed")
+	}
+
+	// Update the configuration
+	if err := h.configService.SetRequiredRoles(guildID, newRoles); err != nil {
+		h.logger.Printf("Error setting required roles for guild %s: %v", guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "config.roles.update_failed"))
+	}
+
+	// Get role name for response
+	role, err := s.State.Role(guildID, roleID)
+	if err != nil {
+		role = &discordgo.Role{ID: roleID, Name: roleID} // Fallback to ID
+	}
+
+	var responseMessage string
+	if len(newRoles) == 0 {
+		responseMessage = h.localizer.T(guildID, "config.roles.updated_none", actionMessage, role.Name)
+	} else {
+		responseMessage = h.localizer.T(guildID, "config.roles.updated", actionMessage, role.Name, len(newRoles))
+	}
+
+	return h.respondSuccess(s, i, responseMessage+h.syncRolePermissions(s, guildID, newRoles))
+}
+
+// handleListRoles lists current required roles
+func (h *ConfigCommandHandler) handleListRoles(s *discordgo.Session, i *discordgo.InteractionCreate, guildID string) error {
+	roles, err := h.configService.GetRequiredRoles(guildID)
+	if err != nil {
+		h.logger.Printf("Error getting required roles for guild %s: %v", guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "config.roles.list_failed"))
+	}
+
+	if len(roles) == 0 {
+		return h.respondSuccess(s, i, h.localizer.T(guildID, "config.roles.list_none"))
+	}
+
+	var roleNames []string
+	for _, roleID := range roles {
+		role, err := s.State.Role(guildID, roleID)
+		if err != nil {
+			roleNames = append(roleNames, h.localizer.T(guildID, "config.roles.unknown", roleID))
+		} else {
+			roleNames = append(roleNames, role.Name)
+		}
+	}
+
+	responseMessage := h.localizer.T(guildID, "config.roles.list", fmt.Sprintf("**%s**", roleNames[0]))
+
+	for _, roleName := range roleNames[1:] {
+		responseMessage += fmt.Sprintf("\n• **%s**", roleName)
+	}
+
+	return h.respondSuccess(s, i, responseMessage)
+}
+
+// handleClearRoles clears all required roles
+func (h *ConfigCommandHandler) handleClearRoles(s *discordgo.Session, i *discordgo.InteractionCreate, guildID string) error {
+	if err := h.configService.SetRequiredRoles(guildID, []string{}); err != nil {
+		h.logger.Printf("Error clearing required roles for guild %s: %v", guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "config.roles.clear_failed"))
+	}
+
+	return h.respondSuccess(s, i, h.localizer.T(guildID, "config.roles.cleared")+h.syncRolePermissions(s, guildID, nil))
+}
+
+// handleSyncRoles turns mirroring the required roles to Discord's command permissions on
+// or off. Turning it off removes the overrides it made.
+func (h *ConfigCommandHandler) handleSyncRoles(s *discordgo.Session, i *discordgo.InteractionCreate, guildID string, enabled bool) error {
+	if !h.commandPermissions.Available() {
+		return h.respondError(s, i, h.localizer.T(guildID, "config.roles.sync_unavailable"))
+	}
+
+	config, err := h.configService.GetGuildConfig(guildID)
+	if err != nil {
+		h.logger.Printf("Error getting guild config for guild %s: %v", guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "config.roles.get_failed"))
+	}
+	if config == nil {
+		defaultConfig := DefaultGuildTTSConfig(guildID)
+		config = &defaultConfig
+	}
+
+	var roles []string
+	if enabled {
+		roles = config.RequiredRoles
+	}
+	syncErr := h.commandPermissions.Sync(s.State.User.ID, guildID, roles)
+	if syncErr != nil {
+		h.logger.Printf("Error syncing command permissions for guild %s: %v", guildID, syncErr)
+		if enabled {
+			return h.respondError(s, i, h.localizer.T(guildID, "config.roles.sync_failed", syncErr))
+		}
+	}
+
+	updated := *config
+	updated.SyncRolePermissions = enabled
+	if err := h.configService.SetGuildConfig(guildID, &updated); err != nil {
+		h.logger.Printf("Error updating guild config for guild %s: %v", guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "config.roles.update_failed"))
+	}
+
+	if !enabled {
+		responseMessage := h.localizer.T(guildID, "config.roles.unsynced")
+		if syncErr != nil {
+			// The setting is off either way, so a token that stopped working can't keep it on
+			responseMessage += "\n\n" + h.localizer.T(guildID, "config.roles.sync_failed", syncErr)
+		}
+		return h.respondSuccess(s, i, responseMessage)
+	}
+	if len(roles) == 0 {
+		return h.respondSuccess(s, i, h.localizer.T(guildID, "config.roles.synced_none"))
+	}
+	return h.respondSuccess(s, i, h.localizer.T(guildID, "config.roles.synced", len(roles)))
+}
+
+// syncRolePermissions mirrors changed required roles to Discord's command permissions when
+// the guild syncs them, returning a note for the response when that failed
+func (h *ConfigCommandHandler) syncRolePermissions(s *discordgo.Session, guildID string, roles []string) string {
+	config, err := h.configService.GetGuildConfig(guildID)
+	if err != nil || config == nil || !config.SyncRolePermissions {
+		return ""
+	}
+
+	if err := h.commandPermissions.Sync(s.State.User.ID, guildID, roles); err != nil {
+		h.logger.Printf("Error syncing command permissions for guild %s: %v", guildID, err)
+		return "\n\n" + h.localizer.T(guildID, "config.roles.sync_failed", err)
+	}
+	return ""
+}
+
+// handleSpeakerRolesConfig handles the speaker role allowlist commands
+func (h *ConfigCommandHandler) handleSpeakerRolesConfig(s *discordgo.Session, i *discordgo.InteractionCreate, guildID string, opts options.Set) error {
+	action, err := opts.RequiredString("action")
+	if err != nil {
+		return h.respondError(s, i, h.localizer.OptionError(guildID, err))
+	}
+
+	config, err := h.configService.GetGuildConfig(guildID)
+	if err != nil {
+		h.logger.Printf("Error getting guild config for guild %s: %v", guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "config.speaker_roles.get_failed"))
+	}
+	if config == nil {
+		defaultConfig := DefaultGuildTTSConfig(guildID)
+		config = &defaultConfig
+	}
+
+	updated := *config
+	var responseMessage string
+
+	switch action {
+	case "list":
+		responseMessage = h.localizer.T(guildID, "config.speaker_roles.list", h.describeSpeakerRoles(guildID, config.SpeakerRoles))
+		return h.respondSuccess(s, i, responseMessage)
+	case "clear":
+		updated.SpeakerRoles = nil
+		responseMessage = h.localizer.T(guildID, "config.speaker_roles.cleared")
+	case "add", "remove":
+		roleID, ok := opts.RoleID("role")
+		if !ok {
+			return h.respondError(s, i, h.localizer.OptionError(guildID, options.Missing("role")))
+		}
+
+		index := -1
+		for j, existing := range config.SpeakerRoles {
+			if existing == roleID {
+				index = j
+				break
+			}
+		}
+
+		if action == "add" {
+			if index >= 0 {
+				return h.respondError(s, i, h.localizer.T(guildID, "config.speaker_roles.already_added", roleID))
+			}
+			if len(config.SpeakerRoles) >= MaxSpeakerRoles {
+				return h.respondError(s, i, h.localizer.T(guildID, "config.speaker_roles.too_many", MaxSpeakerRoles))
+			}
+			updated.SpeakerRoles = append(append([]string{}, config.SpeakerRoles...), roleID)
+			responseMessage = h.localizer.T(guildID, "config.speaker_roles.added", roleID, h.describeSpeakerRoles(guildID, updated.SpeakerRoles))
+		} else {
+			if index < 0 {
+				return h.respondError(s, i, h.localizer.T(guildID, "config.speaker_roles.not_found", roleID))
+			}
+			updated.SpeakerRoles = append(append([]string{}, config.SpeakerRoles[:index]...), config.SpeakerRoles[index+1:]...)
+			responseMessage = h.localizer.T(guildID, "config.speaker_roles.removed", roleID, h.describeSpeakerRoles(guildID, updated.SpeakerRoles))
+		}
+	default:
+		return h.respondError(s, i, h.localizer.T(guildID, "config.speaker_roles.invalid_action"))
+	}
+
+	if err := h.configService.SetGuildConfig(guildID, &updated); err != nil {
+		h.logger.Printf("Error setting speaker roles for guild %s: %v", guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "config.speaker_roles.update_failed", err))
+	}
+
+	return h.respondSuccess(s, i, responseMessage)
+}
+
+// describeSpeakerRoles returns a user-facing list of speaker roles as role mentions
+func (h *ConfigCommandHandler) describeSpeakerRoles(guildID string, roleIDs []string) string {
+	if len(roleIDs) == 0 {
+		return h.localizer.T(guildID, "config.speaker_roles.none")
+	}
+
+	mentions := make([]string, len(roleIDs))
+	for j, roleID := range roleIDs {
+		mentions[j] = fmt.Sprintf("<@&%s>", roleID)
+	}
+	return strings.Join(mentions, ", ")
+}
+
+// handleBotsConfig handles the settings for messages from bots and webhooks. Without
+// options it shows them.
+func (h *ConfigCommandHandler) handleBotsConfig(s *discordgo.Session, i *discordgo.InteractionCreate, guildID string, opts options.Set) error {
+	allBots, _ := opts.String("all-bots")
+	webhooks, _ := opts.String("webhooks")
+	for _, setting := range []string{allBots, webhooks} {
+		switch setting {
+		case "", "on", "off":
+		default:
+			return h.respondError(s, i, h.localizer.T(guildID, "config.bots.invalid_setting"))
+		}
+	}
+
+	config, err := h.configService.GetGuildConfig(guildID)
+	if err != nil {
+		h.logger.Printf("Error getting guild config for guild %s: %v", guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "config.bots.get_failed"))
+	}
+	if config == nil {
+		defaultConfig := DefaultGuildTTSConfig(guildID)
+		config = &defaultConfig
+	}
+
+	updated := *config
+	changed := allBots != "" || webhooks != ""
+	if allBots != "" {
+		updated.ReadBots = allBots == "on"
+	}
+	if webhooks != "" {
+		updated.ReadWebhooks = webhooks == "on"
+	}
+
+	action, hasAction := opts.String("action")
+	if hasAction {
+		changed = true
+		switch action {
+		case "clear":
+			updated.AllowedBots = nil
+		case "add", "remove":
+			botID, ok := opts.UserID("bot")
+			if !ok {
+				return h.respondError(s, i, h.localizer.OptionError(guildID, options.Missing("bot")))
+			}
+			index := slices.Index(config.AllowedBots, botID)
+
+			if action == "add" {
+				if user := resolvedUser(i, botID); user != nil && !user.Bot {
+					return h.respondError(s, i, h.localizer.T(guildID, "config.bots.not_a_bot", botID))
+				}
+				if index >= 0 {
+					return h.respondError(s, i, h.localizer.T(guildID, "config.bots.already_added", botID))
+				}
+				if len(config.AllowedBots) >= MaxAllowedBots {
+					return h.respondError(s, i, h.localizer.T(guildID, "config.bots.too_many", MaxAllowedBots))
+				}
+				updated.AllowedBots = append(slices.Clone(config.AllowedBots), botID)
+			} else {
+				if index < 0 {
+					return h.respondError(s, i, h.localizer.T(guildID, "config.bots.not_found", botID))
+				}
+				updated.AllowedBots = slices.Delete(slices.Clone(config.AllowedBots), index, index+1)
+			}
+		default:
+			return h.respondError(s, i, h.localizer.T(guildID, "config.bots.invalid_action"))
+		}
+	}
+
+	key := "config.bots.show"
+	if changed {
+		if err := h.configService.SetGuildConfig(guildID, &updated); err != nil {
+			h.logger.Printf("Error setting bot messages for guild %s: %v", guildID, err)
+			return h.respondError(s, i, h.localizer.T(guildID, "config.bots.update_failed", err))
+		}
+		key = "config.bots.updated"
+	}
+
+	responseMessage := h.localizer.T(guildID, key,
+		h.describeEnabled(guildID, updated.ReadBots),
+		h.describeEnabled(guildID, updated.ReadWebhooks),
+		h.describeAllowedBots(guildID, updated.AllowedBots))
+	return h.respondSuccess(s, i, responseMessage)
+}
+
+// describeAllowedBots returns a user-facing list of allowed bots as user mentions
+func (h *ConfigCommandHandler) describeAllowedBots(guildID string, botIDs []string) string {
+	if len(botIDs) == 0 {
+		return h.localizer.T(guildID, "config.bots.none")
+	}
+
+	mentions := make([]string, len(botIDs))
+	for j, botID := range botIDs {
+		mentions[j] = fmt.Sprintf("<@%s>", botID)
+	}
+	return strings.Join(mentions, ", ")
+}
+
+// resolvedUser returns the user Discord resolved for a user option, or nil when the
+// interaction carries none, as with text commands
+func resolvedUser(i *discordgo.InteractionCreate, userID string) *discordgo.User {
+	data := i.ApplicationCommandData()
+	if data.Resolved == nil {
+		return nil
+	}
+	return data.Resolved.Users[userID]
+}
+
+// handleVoiceConfig handles voice configuration commands
+func (h *ConfigCommandHandler) handleVoiceConfig(s *discordgo.Session, i *discordgo.InteractionCreate, guildID string, opts options.Set) error {
+	setting, err := opts.RequiredString("setting")
+	if err != nil {
+		return h.respondError(s, i, h.localizer.OptionError(guildID, err))
+	}
+
+	switch setting {
+	case "list-voices":
+		return h.handleListVoices(s, i, guildID)
+	case "voice", "speed", "volume", "pitch", "effects", "style", "loudness":
+		value, ok := opts.String("value")
+		if !ok {
+			return h.handleShowVoiceSetting(s, i, guildID, setting)
+		}
+		return h.handleSetVoiceSetting(s, i, guildID, setting, value)
+	default:
+		return h.respondError(s, i, h.localizer.T(guildID, "config.voice.invalid_setting"))
+	}
+}
+
+// handleListVoices lists available TTS voices
+func (h *ConfigCommandHandler) handleListVoices(s *discordgo.Session, i *discordgo.InteractionCreate, guildID string) error {
+	voices := h.ttsManager.GetSupportedVoices()
+	if len(voices) == 0 {
+		return h.respondError(s, i, h.localizer.T(guildID, "config.voice.no_voices"))
+	}
+
+	responseMessage := h.localizer.T(guildID, "config.voice.list")
+	for _, voice := range voices {
+		responseMessage += fmt.Sprintf("• **%s** (%s) - %s %s\n", voice.Name, voice.ID, voice.Language, voice.Gender)
+	}
+
+	return h.respondSuccess(s, i, responseMessage)
+}
+
+// handleShowVoiceSetting shows current voice setting value
+func (h *ConfigCommandHandler) handleShowVoiceSetting(s *discordgo.Session, i *discordgo.InteractionCreate, guildID, setting string) error {
+	config, err := h.configService.GetTTSSettings(guildID)
+	if err != nil {
+		h.logger.Printf("Error getting TTS settings for guild %s: %v", guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "config.voice.get_failed"))
+	}
+
+	var currentValue string
+	switch setting {
+	case "voice":
+		currentValue = config.Voice
+		if currentValue == "" {
+			currentValue = h.localizer.T(guildID, "config.voice.default")
+		}
+	case "speed":
+		currentValue = fmt.Sprintf("%.2f", config.Speed)
+	case "volume":
+		currentValue = fmt.Sprintf("%.2f", config.Volume)
+	case "pitch":
+		currentValue = fmt.Sprintf("%+.1f", config.Pitch)
+	case "effects":
+		currentValue = h.voiceOptionOrOff(guildID, config.EffectsProfile)
+	case "style":
+		currentValue = h.voiceOptionOrOff(guildID, config.Style)
+	case "loudness":
+		currentValue = h.describeLoudness(guildID, config.Loudness)
+	}
+
+	responseMessage := h.localizer.T(guildID, "config.voice.current", setting, currentValue)
+	return h.respondSuccess(s, i, responseMessage)
+}
+
+// handleSetVoiceSetting sets a voice configuration setting
+func (h *ConfigCommandHandler) handleSetVoiceSetting(s *discordgo.Session, i *discordgo.InteractionCreate, guildID, setting, value string) error {
+	// Get current settings
+	currentConfig, err := h.configService.GetTTSSettings(guildID)
+	if err != nil {
+		h.logger.Printf("Error getting TTS settings for guild %s: %v", guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "config.voice.get_failed"))
+	}
+
+	// Create new config with updated setting
+	newConfig := *currentConfig
+	var cleared []string
+
+	switch setting {
+	case "voice":
+		// Validate voice exists
+		voices := h.ttsManager.GetSupportedVoices()
+		validVoice := false
+		for _, voice := range voices {
+			if voice.ID == value || voice.Name == value {
+				newConfig.Voice = voice.ID
+				validVoice = true
+				break
+			}
+		}
+		if !validVoice {
+			return h.respondError(s, i, h.localizer.T(guildID, "config.voice.invalid_voice", value))
+		}
+
+		// Options the new voice cannot use are cleared rather than silently ignored
+		if newConfig.Pitch != 0 && !SupportsPitch(newConfig.Voice) {
+			newConfig.Pitch = 0
+			cleared = append(cleared, "pitch")
+		}
+		if newConfig.Style != "" && !SupportsStyle(newConfig.Voice) {
+			newConfig.Style = ""
+			cleared = append(cleared, "style")
+		}
+
+	case "speed":
+		speed, err := options.ParseFloat32(setting, value, MinTTSSpeed, MaxTTSSpeed)
+		if err != nil {
+			return h.respondError(s, i, h.localizer.OptionError(guildID, err))
+		}
+		newConfig.Speed = speed
+
+	case "volume":
+		volume, err := options.ParseFloat32(setting, value, MinTTSVolume, 1.0)
+		if err != nil {
+			return h.respondError(s, i, h.localizer.OptionError(guildID, err))
+		}
+		newConfig.Volume = volume
+
+	case "pitch":
+		pitch, err := options.ParseFloat32(setting, value, MinTTSPitch, MaxTTSPitch)
+		if err != nil {
+			return h.respondError(s, i, h.localizer.OptionError(guildID, err))
+		}
+		if pitch != 0 && !SupportsPitch(newConfig.Voice) {
+			return h.respondError(s, i, h.localizer.T(guildID, "config.voice.pitch_unsupported", newConfig.Voice))
+		}
+		newConfig.Pitch = pitch
+
+	case "effects":
+		switch {
+		case value == "off":
+			newConfig.EffectsProfile = ""
+		case slices.Contains(EffectsProfiles, value):
+			newConfig.EffectsProfile = value
+		default:
+			return h.respondError(s, i, h.localizer.T(guildID, "config.voice.invalid_effects", value, strings.Join(EffectsProfiles, ", ")))
+		}
+
+	case "style":
+		switch {
+		case value == "off":
+			newConfig.Style = ""
+		case !slices.Contains(VoiceStyles, value):
+			return h.respondError(s, i, h.localizer.T(guildID, "config.voice.invalid_style", value, strings.Join(VoiceStyles, ", ")))
+		case !SupportsStyle(newConfig.Voice):
+			return h.respondError(s, i, h.localizer.T(guildID, "config.voice.style_unsupported", newConfig.Voice, strings.Join(styledVoices, ", ")))
+		default:
+			newConfig.Style = value
+		}
+
+	case "loudness":
+		if value == "off" {
+			newConfig.Loudness = 0
+			break
+		}
+		loudness, err := options.ParseFloat32(setting, value, MinTTSLoudness, MaxTTSLoudness)
+		if err != nil {
+			return h.respondError(s, i, h.localizer.OptionError(guildID, err))
+		}
+		newConfig.Loudness = loudness
+	}
+
+	// Update the configuration
+	if err := h.configService.SetTTSSettings(guildID, newConfig); err != nil {
+		h.logger.Printf("Error setting TTS settings for guild %s: %v", guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "config.voice.update_failed"))
+	}
+
+	// Update TTS manager with new config
+	if err := h.ttsManager.SetVoiceConfig(guildID, newConfig); err != nil {
+		h.logger.Printf("Warning: Failed to update TTS manager config for guild %s: %v", guildID, err)
+	}
+
+	responseMessage := h.localizer.T(guildID, "config.voice.updated", setting, value)
+	if len(cleared) > 0 {
+		responseMessage += "\n" + h.localizer.T(guildID, "config.voice.options_cleared", strings.Join(cleared, ", "))
+	}
+	return h.respondSuccess(s, i, responseMessage)
+}
+
+// voiceOptionOrOff returns an effects profile or style, or "off" when it is not set
+func (h *ConfigCommandHandler) voiceOptionOrOff(guildID, option string) string {
+	if option == "" {
+		return h.localizer.T(guildID, "config.voice.off")
+	}
+	return option
+}
+
+// describeLoudness returns a loudness target in dBFS, or off when speech is not leveled
+func (h *ConfigCommandHandler) describeLoudness(guildID string, loudness float32) string {
+	if loudness == 0 {
+		return h.localizer.T(guildID, "config.voice.off")
+	}
+	return h.localizer.T(guildID, "config.voice.loudness", loudness)
+}
+
+// handleQueueConfig handles queue configuration commands
+func (h *ConfigCommandHandler) handleQueueConfig(s *discordgo.Session, i *discordgo.InteractionCreate, guildID string, opts options.Set) error {
+	setting, err := opts.RequiredString("setting")
+	if err != nil {
+		return h.respondError(s, i, h.localizer.OptionError(guildID, err))
+	}
+
+	switch setting {
+	case "show":
+		return h.handleShowQueueConfig(s, i, guildID)
+	case "max-size":
+		size, ok, err := opts.IntInRange("value", 1, 50)
+		if err != nil {
+			return h.respondError(s, i, h.localizer.OptionError(guildID, err))
+		}
+		if !ok {
+			return h.handleShowQueueConfig(s, i, guildID)
+		}
+		return h.handleSetMaxQueueSize(s, i, guildID, int(size))
+	case "truncation":
+		mode, ok := opts.String("mode")
+		if !ok {
+			return h.handleShowQueueConfig(s, i, guildID)
+		}
+		return h.handleSetLengthPolicy(s, i, guildID, TruncationMode(mode), 0)
+	case "max-length":
+		length, ok, err := opts.IntInRange("length", MinMessageLength, MaxMessageLength)
+		if err != nil {
+			return h.respondError(s, i, h.localizer.OptionError(guildID, err))
+		}
+		if !ok {
+			return h.handleShowQueueConfig(s, i, guildID)
+		}
+		return h.handleSetLengthPolicy(s, i, guildID, "", int(length))
+	case "user-limit":
+		limit, ok, err := opts.IntInRange("per-minute", 0, MaxUserMessagesPerMinute)
+		if err != nil {
+			return h.respondError(s, i, h.localizer.OptionError(guildID, err))
+		}
+		if !ok {
+			return h.handleShowQueueConfig(s, i, guildID)
+		}
+		return h.handleSetUserMessageLimit(s, i, guildID, int(limit))
+	case "order":
+		order, ok := opts.String("order")
+		if !ok {
+			return h.handleShowQueueConfig(s, i, guildID)
+		}
+		return h.handleSetQueueOrder(s, i, guildID, QueueOrder(order))
+	case "hold-back":
+		seconds, ok, err := opts.IntInRange("seconds", 0, MaxHoldBackSeconds)
+		if err != nil {
+			return h.respondError(s, i, h.localizer.OptionError(guildID, err))
+		}
+		if !ok {
+			return h.handleShowQueueConfig(s, i, guildID)
+		}
+		return h.handleSetHoldBack(s, i, guildID, int(seconds))
+	case "catch-up":
+		policy, hasPolicy := opts.String("catch-up")
+		cutoff, hasCutoff, err := opts.IntInRange("max-age", MinMessageAgeCutoff, MaxMessageAgeCutoff)
+		if err != nil {
+			return h.respondError(s, i, h.localizer.OptionError(guildID, err))
+		}
+		if !hasPolicy && !hasCutoff {
+			return h.handleShowQueueConfig(s, i, guildID)
+		}
+		return h.handleSetCatchUp(s, i, guildID, CatchUpPolicy(policy), int(cutoff))
+	case "pacing":
+		pause, hasPause, err := opts.IntInRange("pause", 0, MaxPauseMillis)
+		if err != nil {
+			return h.respondError(s, i, h.localizer.OptionError(guildID, err))
+		}
+		authorPause, hasAuthorPause, err := opts.IntInRange("author-pause", 0, MaxAuthorPauseMillis)
+		if err != nil {
+			return h.respondError(s, i, h.localizer.OptionError(guildID, err))
+		}
+		if !hasPause && !hasAuthorPause {
+			return h.handleShowQueueConfig(s, i, guildID)
+		}
+		if !hasPause {
+			pause = -1
+		}
+		if !hasAuthorPause {
+			authorPause = -1
+		}
+		return h.handleSetPacing(s, i, guildID, int(pause), int(authorPause))
+	default:
+		return h.respondError(s, i, h.localizer.T(guildID, "config.queue.invalid_setting"))
+	}
+}
+
+// handleShowQueueConfig shows current queue configuration
+func (h *ConfigCommandHandler) handleShowQueueConfig(s *discordgo.Session, i *discordgo.InteractionCreate, guildID string) error {
+	maxSize, err := h.configService.GetMaxQueueSize(guildID)
+	if err != nil {
+		h.logger.Printf("Error getting max queue size for guild %s: %v", guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "config.queue.get_failed"))
+	}
+
+	config, err := h.configService.GetGuildConfig(guildID)
+	if err != nil {
+		h.logger.Printf("Error getting guild config for guild %s: %v", guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "config.queue.get_failed"))
+	}
+
+	currentSize := h.messageQueue.Size(guildID)
+	responseMessage := h.localizer.T(guildID, "config.queue.show", maxSize, currentSize, h.describeLengthPolicy(guildID, LengthPolicyFor(config)),
+		h.describeUserMessageLimit(guildID, UserMessagesPerMinuteFor(config)), h.describeQueueOrder(guildID, QueueOrderFor(config)),
+		h.describeHoldBack(guildID, HoldBackFor(config)), h.describeCatchUp(guildID, CatchUpPolicyFor(config), MessageAgeCutoffFor(config)),
+		h.describePacing(guildID, config))
+
+	return h.respondSuccess(s, i, responseMessage)
+}
+
+// handleSetMaxQueueSize sets the maximum queue size
+func (h *ConfigCommandHandler) handleSetMaxQueueSize(s *discordgo.Session, i *discordgo.InteractionCreate, guildID string, size int) error {
+	// Update configuration
+	if err := h.configService.SetMaxQueueSize(guildID, size); err != nil {
+		h.logger.Printf("Error setting max queue size for guild %s: %v", guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "config.queue.update_failed"))
+	}
+
+	// Update message queue
+	if err := h.messageQueue.SetMaxSize(guildID, size); err != nil {
+		h.logger.Printf("Warning: Failed to update message queue max size for guild %s: %v", guildID, err)
+	}
+
+	responseMessage := h.localizer.T(guildID, "config.queue.updated", size)
+	return h.respondSuccess(s, i, responseMessage)
+}
+
+// handleSetLengthPolicy sets how long messages are read. An empty mode or zero length
+// keeps the current value.
+func (h *ConfigCommandHandler) handleSetLengthPolicy(s *discordgo.Session, i *discordgo.InteractionCreate, guildID string, mode TruncationMode, length int) error {
+	config, err := h.configService.GetGuildConfig(guildID)
+	if err != nil {
+		h.logger.Printf("Error getting guild config for guild %s: %v", guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "config.queue.get_failed"))
+	}
+	if config == nil {
+		defaultConfig := DefaultGuildTTSConfig(guildID)
+		config = &defaultConfig
+	}
+
+	updated := *config
+	if mode != "" {
+		updated.TruncationMode = mode
+	}
+	if length > 0 {
+		updated.MaxUtteranceLength = length
+	}
+
+	if err := h.configService.SetGuildConfig(guildID, &updated); err != nil {
+		h.logger.Printf("Error setting length policy for guild %s: %v", guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "config.queue.update_failed"))
+	}
+
+	responseMessage := h.localizer.T(guildID, "config.queue.length_updated", h.describeLengthPolicy(guildID, LengthPolicyFor(&updated)))
+	return h.respondSuccess(s, i, responseMessage)
+}
+
+// handleSetUserMessageLimit sets how many messages per minute each user can have read,
+// 0 turning the limit off
+func (h *ConfigCommandHandler) handleSetUserMessageLimit(s *discordgo.Session, i *discordgo.InteractionCreate, guildID string, limit int) error {
+	config, err := h.configService.GetGuildConfig(guildID)
+	if err != nil {
+		h.logger.Printf("Error getting guild config for guild %s: %v", guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "config.queue.get_failed"))
+	}
+	if config == nil {
+		defaultConfig := DefaultGuildTTSConfig(guildID)
+		config = &defaultConfig
+	}
+
+	updated := *config
+	updated.UserMessagesPerMinute = limit
+
+	if err := h.configService.SetGuildConfig(guildID, &updated); err != nil {
+		h.logger.Printf("Error setting per-user message limit for guild %s: %v", guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "config.queue.update_failed"))
+	}
+
+	responseMessage := h.localizer.T(guildID, "config.queue.user_limit_updated", h.describeUserMessageLimit(guildID, limit))
+	return h.respondSuccess(s, i, responseMessage)
+}
+
+// handleSetQueueOrder sets whether the queue is read in FIFO order or takes turns
+// between authors
+func (h *ConfigCommandHandler) handleSetQueueOrder(s *discordgo.Session, i *discordgo.InteractionCreate, guildID string, order QueueOrder) error {
+	config, err := h.configService.GetGuildConfig(guildID)
+	if err != nil {
+		h.logger.Printf("Error getting guild config for guild %s: %v", guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "config.queue.get_failed"))
+	}
+	if config == nil {
+		defaultConfig := DefaultGuildTTSConfig(guildID)
+		config = &defaultConfig
+	}
+
+	updated := *config
+	updated.QueueOrder = order
+
+	if err := h.configService.SetGuildConfig(guildID, &updated); err != nil {
+		h.logger.Printf("Error setting queue order for guild %s: %v", guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "config.queue.update_failed"))
+	}
+
+	responseMessage := h.localizer.T(guildID, "config.queue.order_updated", h.describeQueueOrder(guildID, QueueOrderFor(&updated)))
+	return h.respondSuccess(s, i, responseMessage)
+}
+
+// handleSetHoldBack sets how long the queue waits for more messages from the same author
+// before reading them as one
+func (h *ConfigCommandHandler) handleSetHoldBack(s *discordgo.Session, i *discordgo.InteractionCreate, guildID string, seconds int) error {
+	config, err := h.configService.GetGuildConfig(guildID)
+	if err != nil {
+		h.logger.Printf("Error getting guild config for guild %s: %v", guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "config.queue.get_failed"))
+	}
+	if config == nil {
+		defaultConfig := DefaultGuildTTSConfig(guildID)
+		config = &defaultConfig
+	}
+
+	updated := *config
+	updated.HoldBackSeconds = seconds
+
+	if err := h.configService.SetGuildConfig(guildID, &updated); err != nil {
+		h.logger.Printf("Error setting hold-back for guild %s: %v", guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "config.queue.update_failed"))
+	}
+
+	responseMessage := h.localizer.T(guildID, "config.queue.hold_back_updated", h.describeHoldBack(guildID, HoldBackFor(&updated)))
+	return h.respondSuccess(s, i, responseMessage)
+}
+
+// handleSetCatchUp sets what happens to late messages and when a message counts as late,
+// keeping whichever of the two is not given
+func (h *ConfigCommandHandler) handleSetCatchUp(s *discordgo.Session, i *discordgo.InteractionCreate, guildID string, policy CatchUpPolicy, cutoff int) error {
+	config, err := h.configService.GetGuildConfig(guildID)
+	if err != nil {
+		h.logger.Printf("Error getting guild config for guild %s: %v", guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "config.queue.get_failed"))
+	}
+	if config == nil {
+		defaultConfig := DefaultGuildTTSConfig(guildID)
+		config = &defaultConfig
+	}
+
+	updated := *config
+	if policy != "" {
+		updated.CatchUpPolicy = policy
+	}
+	if cutoff > 0 {
+		updated.MessageAgeCutoff = cutoff
+	}
+
+	if err := h.configService.SetGuildConfig(guildID, &updated); err != nil {
+		h.logger.Printf("Error setting catch-up policy for guild %s: %v", guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "config.queue.update_failed"))
+	}
+
+	responseMessage := h.localizer.T(guildID, "config.queue.catch_up_updated",
+		h.describeCatchUp(guildID, CatchUpPolicyFor(&updated), MessageAgeCutoffFor(&updated)))
+	return h.respondSuccess(s, i, responseMessage)
+}
+
+// handleSetPacing sets the pauses left between messages in milliseconds, keeping
+// whichever of the two is negative
+func (h *ConfigCommandHandler) handleSetPacing(s *discordgo.Session, i *discordgo.InteractionCreate, guildID string, pause, authorPause int) error {
+	config, err := h.configService.GetGuildConfig(guildID)
+	if err != nil {
+		h.logger.Printf("Error getting guild config for guild %s: %v", guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "config.queue.get_failed"))
+	}
+	if config == nil {
+		defaultConfig := DefaultGuildTTSConfig(guildID)
+		config = &defaultConfig
+	}
+
+	updated := *config
+	if pause >= 0 {
+		updated.PauseMillis = pause
+	}
+	if authorPause >= 0 {
+		updated.AuthorPauseMillis = authorPause
+	}
+
+	if err := h.configService.SetGuildConfig(guildID, &updated); err != nil {
+		h.logger.Printf("Error setting pacing for guild %s: %v", guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "config.queue.update_failed"))
+	}
+
+	responseMessage := h.localizer.T(guildID, "config.queue.pacing_updated", h.describePacing(guildID, &updated))
+	return h.respondSuccess(s, i, responseMessage)
+}
+
+// describePacing returns a user-facing label for the pauses between messages
+func (h *ConfigCommandHandler) describePacing(guildID string, config *GuildTTSConfig) string {
+	pause, authorPause := PacingFor(config)
+	if pause <= 0 && authorPause <= 0 {
+		return h.localizer.T(guildID, "common.off")
+	}
+	return h.localizer.T(guildID, "config.queue.pacing", pause.Milliseconds(), max(pause, authorPause).Milliseconds())
+}
+
+// describeCatchUp returns a user-facing label for the catch-up policy
+func (h *ConfigCommandHandler) describeCatchUp(guildID string, policy CatchUpPolicy, cutoff time.Duration) string {
+	if policy == CatchUpRead {
+		return h.localizer.T(guildID, "config.queue.catch_up.read")
+	}
+	return h.localizer.T(guildID, "config.queue.catch_up."+string(policy), int(cutoff/time.Second))
+}
+
+// describeHoldBack returns a user-facing label for the hold-back window
+func (h *ConfigCommandHandler) describeHoldBack(guildID string, holdBack time.Duration) string {
+	if holdBack <= 0 {
+		return h.localizer.T(guildID, "common.off")
+	}
+	return h.localizer.T(guildID, "config.queue.hold_back", int(holdBack/time.Second))
+}
+
+// describeQueueOrder returns a user-facing label for the queue order
+func (h *ConfigCommandHandler) describeQueueOrder(guildID string, order QueueOrder) string {
+	return h.localizer.T(guildID, "config.queue.order."+string(order))
+}
+
+// describeUserMessageLimit returns a user-facing label for the per-user message limit
+func (h *ConfigCommandHandler) describeUserMessageLimit(guildID string, limit int) string {
+	if limit <= 0 {
+		return h.localizer.T(guildID, "common.off")
+	}
+	return h.localizer.T(guildID, "config.queue.user_limit", limit)
+}
+
+// describeLengthPolicy returns a user-facing summary of how long messages are read
+func (h *ConfigCommandHandler) describeLengthPolicy(guildID string, policy LengthPolicy) string {
+	return h.localizer.T(guildID, "config.queue.truncation."+string(policy.Mode), policy.MaxLength)
+}
+
+// handleQuotaConfig handles daily character budget commands
+func (h *ConfigCommandHandler) handleQuotaConfig(s *discordgo.Session, i *discordgo.InteractionCreate, guildID string, opts options.Set) error {
+	if h.quotaService == nil {
+		return h.respondError(s, i, h.localizer.T(guildID, "config.quota.unavailable"))
+	}
+
+	setting, err := opts.RequiredString("setting")
+	if err != nil {
+		return h.respondError(s, i, h.localizer.OptionError(guildID, err))
+	}
+
+	switch setting {
+	case "show":
+		return h.handleShowQuotaConfig(s, i, guildID)
+	case "daily-budget":
+		budget, ok, err := opts.IntAtLeast("value", 0)
+		if err != nil {
+			return h.respondError(s, i, h.localizer.OptionError(guildID, err))
+		}
+		if !ok {
+			return h.handleShowQuotaConfig(s, i, guildID)
+		}
+		return h.handleSetDailyBudget(s, i, guildID, int(budget))
+	case "premium-budget":
+		budget, ok, err := opts.IntAtLeast("value", 0)
+		if err != nil {
+			return h.respondError(s, i, h.localizer.OptionError(guildID, err))
+		}
+		if !ok {
+			return h.handleShowQuotaConfig(s, i, guildID)
+		}
+		return h.handleSetPremiumBudget(s, i, guildID, int(budget))
+	default:
+		return h.respondError(s, i, h.localizer.T(guildID, "config.quota.invalid_setting"))
+	}
+}
+
+// handleShowQuotaConfig shows the daily budget and today's usage
+func (h *ConfigCommandHandler) handleShowQuotaConfig(s *discordgo.Session, i *discordgo.InteractionCreate, guildID string) error {
+	usageSummary, err := h.formatQuotaUsage(guildID)
+	if err != nil {
+		h.logger.Printf("Error getting TTS usage for guild %s: %v", guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "config.quota.get_failed"))
+	}
+
+	responseMessage := h.localizer.T(guildID, "config.quota.show") + usageSummary
+	return h.respondSuccess(s, i, responseMessage)
+}
+
+// handleSetDailyBudget sets the guild's daily character budget
+func (h *ConfigCommandHandler) handleSetDailyBudget(s *discordgo.Session, i *discordgo.InteractionCreate, guildID string, budget int) error {
+	if err := h.quotaService.SetDailyBudget(guildID, budget); err != nil {
+		h.logger.Printf("Error setting daily budget for guild %s: %v", guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "config.quota.update_failed"))
+	}
+
+	if budget == 0 {
+		return h.respondSuccess(s, i, h.localizer.T(guildID, "config.quota.reset"))
+	}
+
+	responseMessage := h.localizer.T(guildID, "config.quota.updated", budget)
+	return h.respondSuccess(s, i, responseMessage)
+}
+
+// handleSetPremiumBudget sets the daily character budget of the guild's premium voices
+func (h *ConfigCommandHandler) handleSetPremiumBudget(s *discordgo.Session, i *discordgo.InteractionCreate, guildID string, budget int) error {
+	if err := h.quotaService.SetPremiumBudget(guildID, budget); err != nil {
+		h.logger.Printf("Error setting premium voice budget for guild %s: %v", guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "config.quota.update_failed"))
+	}
+
+	if budget == 0 {
+		return h.respondSuccess(s, i, h.localizer.T(guildID, "config.quota.premium_reset"))
+	}
+
+	responseMessage := h.localizer.T(guildID, "config.quota.premium_updated", budget)
+	return h.respondSuccess(s, i, responseMessage)
+}
+
+// formatQuotaUsage renders today's usage against the guild's budgets, with the characters
+// of each voice tier
+func (h *ConfigCommandHandler) formatQuotaUsage(guildID string) (string, error) {
+	budget, err := h.quotaService.GetDailyBudget(guildID)
+	if err != nil {
+		return "", err
+	}
+
+	premiumBudget, err := h.quotaService.GetPremiumBudget(guildID)
+	if err != nil {
+		return "", err
+	}
+
+	usage, err := h.quotaService.GetUsage(guildID)
+	if err != nil {
+		return "", err
+	}
+
+	var summary string
+	if budget == 0 {
+		summary = h.localizer.T(guildID, "config.quota.usage_unlimited", usage.CharactersUsed)
+	} else {
+		percent := float64(usage.CharactersUsed) / float64(budget) * 100
+		summary = h.localizer.T(guildID, "config.quota.usage", budget, usage.CharactersUsed, percent)
+	}
+
+	if premiumBudget > 0 {
+		summary += h.localizer.T(guildID, "config.quota.premium_usage", premiumBudget, usage.PremiumCharacters())
+	}
+
+	tiers := slices.Sorted(maps.Keys(usage.TierCharacters))
+	for _, tier := range tiers {
+		summary += h.localizer.T(guildID, "config.quota.tier_usage", tier, usage.TierCharacters[tier])
+	}
+	return summary, nil
+}
+
+// handlePrivacyConfig handles content retention commands
+func (h *ConfigCommandHandler) handlePrivacyConfig(s *discordgo.Session, i *discordgo.InteractionCreate, guildID string, opts options.Set) error {
+	if h.contentPolicy == nil {
+		return h.respondError(s, i, h.localizer.T(guildID, "config.privacy.unavailable"))
+	}
+
+	setting, err := opts.RequiredString("content-retention")
+	if err != nil {
+		return h.respondError(s, i, h.localizer.OptionError(guildID, err))
+	}
+	if setting == "show" {
+		responseMessage := h.localizer.T(guildID, "config.privacy.show", h.describeContentRetention(guildID, h.contentPolicy.Mode(guildID)))
+		return h.respondSuccess(s, i, responseMessage)
+	}
+
+	mode := ContentRetention(setting)
+	if err := h.contentPolicy.SetMode(guildID, mode); err != nil {
+		h.logger.Printf("Error setting content retention for guild %s: %v", guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "config.privacy.update_failed"))
+	}
+
+	responseMessage := h.localizer.T(guildID, "config.privacy.updated", h.describeContentRetention(guildID, mode))
+	return h.respondSuccess(s, i, responseMessage)
+}
+
+// describeContentRetention returns a user-facing description of a content retention mode
+func (h *ConfigCommandHandler) describeContentRetention(guildID string, mode ContentRetention) string {
+	if mode == ContentRetentionMetadata {
+		return h.localizer.T(guildID, "config.privacy.mode_metadata")
+	}
+	return h.localizer.T(guildID, "config.privacy.mode_full")
+}
+
+// handleAnnouncementsConfig handles join/leave announcement, reaction summary and skip
+// note commands. Without a setting to change it shows all three.
+func (h *ConfigCommandHandler) handleAnnouncementsConfig(s *discordgo.Session, i *discordgo.InteractionCreate, guildID string, opts options.Set) error {
+	if h.voiceAnnouncer == nil {
+		return h.respondError(s, i, h.localizer.T(guildID, "config.announcements.unavailable"))
+	}
+
+	joinLeave, _ := opts.String("join-leave")
+	reactions, _ := opts.String("reactions")
+	skipped, _ := opts.String("skipped")
+	for _, setting := range []string{joinLeave, reactions, skipped} {
+		switch setting {
+		case "", "show", "on", "off":
+		default:
+			return h.respondError(s, i, h.localizer.T(guildID, "config.announcements.invalid_setting"))
+		}
+	}
+
+	changed := false
+	if joinLeave == "on" || joinLeave == "off" {
+		if err := h.voiceAnnouncer.SetEnabled(guildID, joinLeave == "on"); err != nil {
+			h.logger.Printf("Error setting voice announcements for guild %s: %v", guildID, err)
+			return h.respondError(s, i, h.localizer.T(guildID, "config.announcements.update_failed"))
+		}
+		changed = true
+	}
+	if reactions == "on" || reactions == "off" {
+		if h.reactionSummarizer == nil {
+			return h.respondError(s, i, h.localizer.T(guildID, "config.announcements.reactions_unavailable"))
+		}
+		if err := h.reactionSummarizer.SetEnabled(guildID, reactions == "on"); err != nil {
+			h.logger.Printf("Error setting reaction summaries for guild %s: %v", guildID, err)
+			return h.respondError(s, i, h.localizer.T(guildID, "config.announcements.update_failed"))
+		}
+		changed = true
+	}
+	if skipped == "on" || skipped == "off" {
+		if h.skipNotes == nil {
+			return h.respondError(s, i, h.localizer.T(guildID, "config.announcements.skipped_unavailable"))
+		}
+		if err := h.skipNotes.SetEnabled(guildID, skipped == "on"); err != nil {
+			h.logger.Printf("Error setting skip notes for guild %s: %v", guildID, err)
+			return h.respondError(s, i, h.localizer.T(guildID, "config.announcements.update_failed"))
+		}
+		changed = true
+	}
+
+	key := "config.announcements.show"
+	if changed {
+		key = "config.announcements.updated"
+	}
+	responseMessage := h.localizer.T(guildID, key,
+		h.describeEnabled(guildID, h.voiceAnnouncer.Enabled(guildID)),
+		h.describeEnabled(guildID, h.reactionSummarizer != nil && h.reactionSummarizer.Enabled(guildID)),
+		h.describeEnabled(guildID, h.skipNotes != nil && h.skipNotes.Enabled(guildID)))
+	return h.respondSuccess(s, i, responseMessage)
+}
+
+// handleVoiceCommandsConfig handles spoken command commands
+func (h *ConfigCommandHandler) handleVoiceCommandsConfig(s *discordgo.Session, i *discordgo.InteractionCreate, guildID string, opts options.Set) error {
+	if h.voiceCommands == nil || !h.voiceCommands.Available() {
+		return h.respondError(s, i, h.localizer.T(guildID, "config.voice_commands.unavailable"))
+	}
+
+	setting, err := opts.RequiredString("listen")
+	if err != nil {
+		return h.respondError(s, i, h.localizer.OptionError(guildID, err))
+	}
+	switch setting {
+	case "show":
+		responseMessage := h.localizer.T(guildID, "config.voice_commands.show",
+			h.describeEnabled(guildID, h.voiceCommands.Enabled(guildID)), h.describeVoiceCommandPhrases(guildID))
+		return h.respondSuccess(s, i, responseMessage)
+	case "on", "off":
+		enabled := setting == "on"
+		if err := h.voiceCommands.SetEnabled(guildID, enabled); err != nil {
+			h.logger.Printf("Error setting voice commands for guild %s: %v", guildID, err)
+			return h.respondError(s, i, h.localizer.T(guildID, "config.voice_commands.update_failed"))
+		}
+		responseMessage := h.localizer.T(guildID, "config.voice_commands.updated", h.describeEnabled(guildID, enabled))
+		if enabled {
+			responseMessage += "\n" + h.localizer.T(guildID, "config.voice_commands.phrases", h.describeVoiceCommandPhrases(guildID))
+		}
+		return h.respondSuccess(s, i, responseMessage)
+	default:
+		return h.respondError(s, i, h.localizer.T(guildID, "config.voice_commands.invalid_setting"))
+	}
+}
+
+// describeVoiceCommandPhrases lists the phrases that trigger voice commands in a guild
+func (h *ConfigCommandHandler) describeVoiceCommandPhrases(guildID string) string {
+	phrases := make([]string, len(VoiceCommands))
+	for i, command := range VoiceCommands {
+		phrases[i] = "\"" + h.voiceCommands.Phrase(guildID, command) + "\""
+	}
+	return strings.Join(phrases, ", ")
+}
+
+// handleFeaturesConfig handles experimental feature commands
+func (h *ConfigCommandHandler) handleFeaturesConfig(s *discordgo.Session, i *discordgo.InteractionCreate, guildID string, opts options.Set) error {
+	if h.features == nil {
+		return h.respondError(s, i, h.localizer.T(guildID, "config.features.unavailable"))
+	}
+
+	setting, err := opts.RequiredString("state")
+	if err != nil {
+		return h.respondError(s, i, h.localizer.OptionError(guildID, err))
+	}
+	if setting == "show" {
+		return h.respondSuccess(s, i, h.localizer.T(guildID, "config.features.show", h.describeFeatures(guildID)))
+	}
+
+	name, ok := opts.String("feature")
+	if !ok {
+		return h.respondError(s, i, h.localizer.T(guildID, "config.features.missing_feature"))
+	}
+	feature, ok := ParseFeature(name)
+	if !ok {
+		return h.respondError(s, i, h.localizer.T(guildID, "config.features.invalid_feature"))
+	}
+
+	var enabled *bool
+	switch setting {
+	case "on", "off":
+		value := setting == "on"
+		enabled = &value
+	case "default":
+	default:
+		return h.respondError(s, i, h.localizer.T(guildID, "config.features.invalid_setting"))
+	}
+
+	// Voice auto-pause needs to hear the voice channel
+	if feature == FeatureVoiceAutoPause && setting == "on" && (h.voiceCommands == nil || !h.voiceCommands.Available()) {
+		return h.respondError(s, i, h.localizer.T(guildID, "config.features.voice_unavailable"))
+	}
+
+	if err := h.features.Set(guildID, feature, enabled); err != nil {
+		h.logger.Printf("Error setting feature %s for guild %s: %v", feature, guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "config.features.update_failed"))
+	}
+
+	state := h.features.State(guildID, feature)
+	responseMessage := h.localizer.T(guildID, "config.features.updated", h.describeFeatureName(guildID, feature),
+		h.describeEnabled(guildID, state.Enabled), h.describeFeatureSource(guildID, state.Source))
+	if feature == FeatureVoiceAutoPause {
+		responseMessage += "\n" + h.localizer.T(guildID, "config.features.rejoin")
+	}
+	return h.respondSuccess(s, i, responseMessage)
+}
+
+// describeFeatures lists whether each experimental feature is on in a guild
+func (h *ConfigCommandHandler) describeFeatures(guildID string) string {
+	lines := make([]string, 0, len(Features))
+	for _, state := range h.features.States(guildID) {
+		lines = append(lines, h.localizer.T(guildID, "config.features.line", h.describeFeatureName(guildID, state.Feature),
+			h.describeEnabled(guildID, state.Enabled), h.describeFeatureSource(guildID, state.Source)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// describeFeatureName returns the user-facing name of an experimental feature
+func (h *ConfigCommandHandler) describeFeatureName(guildID string, feature Feature) string {
+	return h.localizer.T(guildID, "config.features.name."+string(feature))
+}
+
+// describeFeatureSource returns who decided a feature's state in a guild
+func (h *ConfigCommandHandler) describeFeatureSource(guildID, source string) string {
+	return h.localizer.T(guildID, "config.features.source."+source)
+}
+
+// handleOptInNoticeConfig handles privacy notice DM commands
+func (h *ConfigCommandHandler) handleOptInNoticeConfig(s *discordgo.Session, i *discordgo.InteractionCreate, guildID string, opts options.Set) error {
+	if h.privacyService == nil {
+		return h.respondError(s, i, h.localizer.T(guildID, "config.opt_in_notice.unavailable"))
+	}
+
+	setting, err := opts.RequiredString("dm")
+	if err != nil {
+		return h.respondError(s, i, h.localizer.OptionError(guildID, err))
+	}
+	switch setting {
+	case "show":
+		responseMessage := h.localizer.T(guildID, "config.opt_in_notice.show", h.describeEnabled(guildID, h.privacyService.NoticeEnabled(guildID)))
+		return h.respondSuccess(s, i, responseMessage)
+	case "on", "off":
+		enabled := setting == "on"
+		if err := h.privacyService.SetNoticeEnabled(guildID, enabled); err != nil {
+			h.logger.Printf("Error setting opt-in notice for guild %s: %v", guildID, err)
+			return h.respondError(s, i, h.localizer.T(guildID, "config.opt_in_notice.update_failed"))
+		}
+		responseMessage := h.localizer.T(guildID, "config.opt_in_notice.updated", h.describeEnabled(guildID, enabled))
+		return h.respondSuccess(s, i, responseMessage)
+	default:
+		return h.respondError(s, i, h.localizer.T(guildID, "config.opt_in_notice.invalid_setting"))
+	}
+}
+
+// handleLanguageConfig handles response language commands
+func (h *ConfigCommandHandler) handleLanguageConfig(s *discordgo.Session, i *discordgo.InteractionCreate, guildID string, opts options.Set) error {
+	if h.localizer == nil {
+		return h.respondError(s, i, h.localizer.T(guildID, "config.language.unavailable"))
+	}
+
+	setting, err := opts.RequiredString("language")
+	if err != nil {
+		return h.respondError(s, i, h.localizer.OptionError(guildID, err))
+	}
+	if setting == "show" {
+		responseMessage := h.localizer.T(guildID, "config.language.show", h.describeLanguage(guildID))
+		return h.respondSuccess(s, i, responseMessage)
+	}
+
+	if err := h.localizer.SetLanguage(guildID, setting); err != nil {
+		h.logger.Printf("Error setting response language for guild %s: %v", guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "config.language.update_failed"))
+	}
+
+	// Confirm in the newly selected language
+	responseMessage := h.localizer.T(guildID, "config.language.updated", h.describeLanguage(guildID))
+	return h.respondSuccess(s, i, responseMessage)
+}
+
+// handleIgnorePrefixConfig handles ignore prefix commands
+func (h *ConfigCommandHandler) handleIgnorePrefixConfig(s *discordgo.Session, i *discordgo.InteractionCreate, guildID string, opts options.Set) error {
+	action, err := opts.RequiredString("action")
+	if err != nil {
+		return h.respondError(s, i, h.localizer.OptionError(guildID, err))
+	}
+
+	prefixes, err := h.configService.GetIgnorePrefixes(guildID)
+	if err != nil {
+		h.logger.Printf("Error getting ignore prefixes for guild %s: %v", guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "config.ignore_prefix.get_failed"))
+	}
+
+	switch action {
+	case "list":
+		responseMessage := h.localizer.T(guildID, "config.ignore_prefix.list", h.describeIgnorePrefixes(guildID, prefixes))
+		return h.respondSuccess(s, i, responseMessage)
+	case "clear":
+		if err := h.configService.SetIgnorePrefixes(guildID, nil); err != nil {
+			h.logger.Printf("Error clearing ignore prefixes for guild %s: %v", guildID, err)
+			return h.respondError(s, i, h.localizer.T(guildID, "config.ignore_prefix.update_failed", err))
+		}
+		return h.respondSuccess(s, i, h.localizer.T(guildID, "config.ignore_prefix.cleared"))
+	case "add", "remove":
+		prefix, ok := opts.String("prefix")
+		if !ok {
+			return h.respondError(s, i, h.localizer.OptionError(guildID, options.Missing("prefix")))
+		}
+		prefix = strings.TrimSpace(prefix)
+
+		var updated []string
+		if action == "add" {
+			updated = append(append(updated, prefixes...), prefix)
+		} else {
+			for _, existing := range prefixes {
+				if existing != prefix {
+					updated = append(updated, existing)
+				}
+			}
+			if len(updated) == len(prefixes) {
+				return h.respondError(s, i, h.localizer.T(guildID, "config.ignore_prefix.not_found", prefix))
+			}
+		}
+
+		if err := h.configService.SetIgnorePrefixes(guildID, updated); err != nil {
+			return h.respondError(s, i, h.localizer.T(guildID, "config.ignore_prefix.update_failed", err))
+		}
+
+		if action == "remove" {
+			return h.respondSuccess(s, i, h.localizer.T(guildID, "config.ignore_prefix.removed", prefix))
+		}
+		return h.respondSuccess(s, i, h.localizer.T(guildID, "config.ignore_prefix.added", prefix))
+	default:
+		return h.respondError(s, i, h.localizer.T(guildID, "config.ignore_prefix.invalid_action"))
+	}
+}
+
+// describeIgnorePrefixes returns a user-facing list of ignore prefixes
+func (h *ConfigCommandHandler) describeIgnorePrefixes(guildID string, prefixes []string) string {
+	if len(prefixes) == 0 {
+		return h.localizer.T(guildID, "config.ignore_prefix.none")
+	}
+	return "`" + strings.Join(prefixes, "` `") + "`"
+}
+
+// handleContentConfig handles link, code block, spoiler and emoji mode commands and
+// whether age-restricted channels can be paired. With no options it shows the current modes.
+func (h *ConfigCommandHandler) handleContentConfig(s *discordgo.Session, i *discordgo.InteractionCreate, guildID string, opts options.Set) error {
+	config, err := h.configService.GetGuildConfig(guildID)
+	if err != nil {
+		h.logger.Printf("Error getting guild config for guild %s: %v", guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "config.content.get_failed"))
+	}
+	if config == nil {
+		defaultConfig := DefaultGuildTTSConfig(guildID)
+		config = &defaultConfig
+	}
+
+	links, setLinks := opts.String("links")
+	codeBlocks, setCodeBlocks := opts.String("code-blocks")
+	spoilers, setSpoilers := opts.String("spoilers")
+	allowNSFW, setAllowNSFW := opts.Bool("allow-nsfw")
+	maxEmoji, setMaxEmoji, err := opts.IntInRange("max-emoji", 1, MaxSpokenEmojiLimit)
+	if err != nil {
+		return h.respondError(s, i, h.localizer.OptionError(guildID, err))
+	}
+	if !setLinks && !setCodeBlocks && !setSpoilers && !setMaxEmoji && !setAllowNSFW {
+		responseMessage := h.localizer.T(guildID, "config.content.show", h.describeContentModes(guildID, config))
+		return h.respondSuccess(s, i, responseMessage)
+	}
+
+	updated := *config
+	if setLinks {
+		updated.LinkMode = LinkMode(links)
+	}
+	if setCodeBlocks {
+		updated.CodeBlockMode = CodeBlockMode(codeBlocks)
+	}
+	if setSpoilers {
+		updated.SpoilerMode = SpoilerMode(spoilers)
+	}
+	if setMaxEmoji {
+		updated.MaxSpokenEmoji = int(maxEmoji)
+	}
+	if setAllowNSFW {
+		updated.AllowNSFWChannels = allowNSFW
+	}
+
+	if err := h.configService.SetGuildConfig(guildID, &updated); err != nil {
+		h.logger.Printf("Error setting content modes for guild %s: %v", guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "config.content.update_failed", err))
+	}
+
+	responseMessage := h.localizer.T(guildID, "config.content.updated", h.describeContentModes(guildID, &updated))
+	return h.respondSuccess(s, i, responseMessage)
+}
+
+// describeContentModes returns a user-facing summary of a guild's link, code block,
+// spoiler and emoji modes and whether age-restricted channels can be paired
+func (h *ConfigCommandHandler) describeContentModes(guildID string, config *GuildTTSConfig) string {
+	modes := ContentModesFor(config)
+	nsfw := h.localizer.T(guildID, "config.content.nsfw.refused")
+	if config.AllowNSFWChannels {
+		nsfw = h.localizer.T(guildID, "config.content.nsfw.allowed")
+	}
+	return h.localizer.T(guildID, "config.content.modes",
+		h.localizer.T(guildID, "config.content.links."+string(modes.Links)),
+		h.localizer.T(guildID, "config.content.code_blocks."+string(modes.CodeBlocks)),
+		h.localizer.T(guildID, "config.content.spoilers."+string(modes.Spoilers)),
+		modes.MaxEmoji,
+		nsfw)
+}
+
+// handleIdleConfig handles the idle announcement and disconnect timeouts. With no options
+// it shows the current timeouts.
+func (h *ConfigCommandHandler) handleIdleConfig(s *discordgo.Session, i *discordgo.InteractionCreate, guildID string, opts options.Set) error {
+	config, err := h.configService.GetGuildConfig(guildID)
+	if err != nil {
+		h.logger.Printf("Error getting guild config for guild %s: %v", guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "config.idle.get_failed"))
+	}
+	if config == nil {
+		defaultConfig := DefaultGuildTTSConfig(guildID)
+		config = &defaultConfig
+	}
+
+	announceAfter, setAnnounce, err := opts.IntInRange("announce-after", 0, MaxIdleMinutes)
+	if err != nil {
+		return h.respondError(s, i, h.localizer.OptionError(guildID, err))
+	}
+	disconnectAfter, setDisconnect, err := opts.IntInRange("disconnect-after", 0, MaxIdleMinutes)
+	if err != nil {
+		return h.respondError(s, i, h.localizer.OptionError(guildID, err))
+	}
+	minListeners, setMinListeners, err := opts.IntInRange("min-listeners", 0, MaxMinListeners)
+	if err != nil {
+		return h.respondError(s, i, h.localizer.OptionError(guildID, err))
+	}
+	if !setAnnounce && !setDisconnect && !setMinListeners {
+		responseMessage := h.localizer.T(guildID, "config.idle.show", h.describeIdleSettings(guildID, config))
+		return h.respondSuccess(s, i, responseMessage)
+	}
+
+	updated := *config
+	if setAnnounce {
+		updated.IdleAnnounceMinutes = int(announceAfter)
+		if announceAfter == 0 {
+			updated.IdleAnnounceMinutes = -1 // 0 is stored as "use the default"
+		}
+	}
+	if setDisconnect {
+		updated.IdleDisconnectMinutes = int(disconnectAfter)
+	}
+	if setMinListeners {
+		updated.MinListeners = int(minListeners)
+	}
+
+	if err := h.configService.SetGuildConfig(guildID, &updated); err != nil {
+		h.logger.Printf("Error setting idle timeouts for guild %s: %v", guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "config.idle.update_failed", err))
+	}
+
+	responseMessage := h.localizer.T(guildID, "config.idle.updated", h.describeIdleSettings(guildID, &updated))
+	return h.respondSuccess(s, i, responseMessage)
+}
+
+// describeIdleSettings returns a user-facing summary of the idle timeouts and the
+// minimum number of listeners
+func (h *ConfigCommandHandler) describeIdleSettings(guildID string, config *GuildTTSConfig) string {
+	minListeners := h.localizer.T(guildID, "common.off")
+	if minimum := MinListenersFor(config); minimum > 0 {
+		minListeners = h.localizer.T(guildID, "config.idle.listeners", minimum)
+	}
+	return h.describeIdleTimeouts(guildID, IdleTimeoutsFor(config)) + h.localizer.T(guildID, "config.idle.min_listeners", minListeners)
+}
+
+// describeIdleTimeouts returns a user-facing summary of the idle timeouts
+func (h *ConfigCommandHandler) describeIdleTimeouts(guildID string, timeouts IdleTimeouts) string {
+	return h.localizer.T(guildID, "config.idle.timeouts",
+		h.describeIdleTimeout(guildID, timeouts.Announce),
+		h.describeIdleTimeout(guildID, timeouts.Disconnect))
+}
+
+// describeIdleTimeout returns a user-facing label for a single idle timeout
+func (h *ConfigCommandHandler) describeIdleTimeout(guildID string, timeout time.Duration) string {
+	if timeout <= 0 {
+		return h.localizer.T(guildID, "common.off")
+	}
+	return h.localizer.T(guildID, "config.idle.after_minutes", idleMinutes(timeout))
+}
+
+// handleOutputConfig handles the extra output speech is copied to. With no options it
+// shows the current output and the ones the operator set up.
+func (h *ConfigCommandHandler) handleOutputConfig(s *discordgo.Session, i *discordgo.InteractionCreate, guildID string, opts options.Set) error {
+	config, err := h.configService.GetGuildConfig(guildID)
+	if err != nil {
+		h.logger.Printf("Error getting guild config for guild %s: %v", guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "config.output.get_failed"))
+	}
+	if config == nil {
+		defaultConfig := DefaultGuildTTSConfig(guildID)
+		config = &defaultConfig
+	}
+
+	target, ok := opts.String("target")
+	if !ok {
+		responseMessage := h.localizer.T(guildID, "config.output.show",
+			h.describeAudioOutput(guildID, config.AudioOutput), h.describeAvailableOutputs())
+		return h.respondSuccess(s, i, responseMessage)
+	}
+
+	output := AudioOutput(target)
+	if !h.audioOutputs.Available(output) {
+		return h.respondError(s, i, h.localizer.T(guildID, "config.output.unavailable", output, h.describeAvailableOutputs()))
+	}
+	if output == AudioOutputFile && h.contentPolicy.ContentFree(guildID) {
+		return h.respondError(s, i, h.localizer.T(guildID, "config.output.content_free"))
+	}
+
+	updated := *config
+	updated.AudioOutput = output
+	if output == AudioOutputDiscord {
+		updated.AudioOutput = "" // Discord only is the default
+	}
+
+	if err := h.configService.SetGuildConfig(guildID, &updated); err != nil {
+		h.logger.Printf("Error setting audio output for guild %s: %v", guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "config.output.update_failed", err))
+	}
+
+	responseMessage := h.localizer.T(guildID, "config.output.updated", h.describeAudioOutput(guildID, updated.AudioOutput))
+	return h.respondSuccess(s, i, responseMessage)
+}
+
+// handleRecordingConfig handles whether voice sessions are recorded and timestamped. With
+// no options it shows the current settings.
+func (h *ConfigCommandHandler) handleRecordingConfig(s *discordgo.Session, i *discordgo.InteractionCreate, guildID string, opts options.Set) error {
+	config, err := h.configService.GetGuildConfig(guildID)
+	if err != nil {
+		h.logger.Printf("Error getting guild config for guild %s: %v", guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "config.recording.get_failed"))
+	}
+	if config == nil {
+		defaultConfig := DefaultGuildTTSConfig(guildID)
+		config = &defaultConfig
+	}
+
+	enabled, setEnabled := opts.Bool("enabled")
+	timestamps, setTimestamps := opts.Bool("timestamps")
+	if !setEnabled && !setTimestamps {
+		responseMessage := h.localizer.T(guildID, "config.recording.show", h.describeRecording(guildID, config))
+		return h.respondSuccess(s, i, responseMessage)
+	}
+
+	updated := *config
+	if setEnabled {
+		updated.RecordSessions = enabled
+	}
+	if setTimestamps {
+		updated.RecordTimestamps = timestamps
+	}
+	if updated.RecordSessions && h.contentPolicy.ContentFree(guildID) {
+		return h.respondError(s, i, h.localizer.T(guildID, "config.recording.content_free"))
+	}
+
+	if err := h.configService.SetGuildConfig(guildID, &updated); err != nil {
+		h.logger.Printf("Error setting session recording for guild %s: %v", guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "config.recording.update_failed", err))
+	}
+
+	responseMessage := h.localizer.T(guildID, "config.recording.updated", h.describeRecording(guildID, &updated))
+	return h.respondSuccess(s, i, responseMessage)
+}
+
+// handleControlConfig handles the limits on join and control commands. With no options it
+// shows the current limits.
+func (h *ConfigCommandHandler) handleControlConfig(s *discordgo.Session, i *discordgo.InteractionCreate, guildID string, opts options.Set) error {
+	config, err := h.configService.GetGuildConfig(guildID)
+	if err != nil {
+		h.logger.Printf("Error getting guild config for guild %s: %v", guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "config.control.get_failed"))
+	}
+	if config == nil {
+		defaultConfig := DefaultGuildTTSConfig(guildID)
+		config = &defaultConfig
+	}
+
+	perMinute, setPerMinute, err := opts.IntInRange("actions-per-minute", 0, MaxControlsPerMinute)
+	if err != nil {
+		return h.respondError(s, i, h.localizer.OptionError(guildID, err))
+	}
+	voiceOnly, setVoiceOnly := opts.Bool("voice-channel-only")
+	if !setPerMinute && !setVoiceOnly {
+		responseMessage := h.localizer.T(guildID, "config.control.show", h.describeControlLimits(guildID, config))
+		return h.respondSuccess(s, i, responseMessage)
+	}
+
+	updated := *config
+	if setPerMinute {
+		updated.ControlsPerMinute = int(perMinute)
+		if perMinute == 0 {
+			updated.ControlsPerMinute = -1 // 0 is stored as "use the default"
+		}
+	}
+	if setVoiceOnly {
+		updated.ControlInVoiceOnly = voiceOnly
+	}
+
+	if err := h.configService.SetGuildConfig(guildID, &updated); err != nil {
+		h.logger.Printf("Error setting control limits for guild %s: %v", guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "config.control.update_failed", err))
+	}
+
+	responseMessage := h.localizer.T(guildID, "config.control.updated", h.describeControlLimits(guildID, &updated))
+	return h.respondSuccess(s, i, responseMessage)
+}
+
+// describeControlLimits returns a user-facing summary of a guild's limits on join and
+// control commands
+func (h *ConfigCommandHandler) describeControlLimits(guildID string, config *GuildTTSConfig) string {
+	perMinute := h.localizer.T(guildID, "config.control.unlimited")
+	if limit := ControlsPerMinuteFor(config); limit > 0 {
+		perMinute = h.localizer.T(guildID, "config.control.per_minute", limit)
+	}
+	return h.localizer.T(guildID, "config.control.limits", perMinute, h.describeEnabled(guildID, config.ControlInVoiceOnly))
+}
+
+// describeRecording returns a user-facing summary of a guild's session recording
+func (h *ConfigCommandHandler) describeRecording(guildID string, config *GuildTTSConfig) string {
+	if !config.RecordSessions {
+		return h.localizer.T(guildID, "common.off")
+	}
+	if config.RecordTimestamps {
+		return h.localizer.T(guildID, "config.recording.with_timestamps")
+	}
+	return h.localizer.T(guildID, "common.on")
+}
+
+// describeAudioOutput returns a user-facing label for a guild's audio output
+func (h *ConfigCommandHandler) describeAudioOutput(guildID string, output AudioOutput) string {
+	if output == "" {
+		output = AudioOutputDiscord
+	}
+	return h.localizer.T(guildID, "config.output."+string(output))
+}
+
+// describeAvailableOutputs lists the outputs guilds can choose on this bot
+func (h *ConfigCommandHandler) describeAvailableOutputs() string {
+	var names []string
+	for _, output := range h.audioOutputs.Outputs() {
+		names = append(names, "`"+string(output)+"`")
+	}
+	return strings.Join(names, ", ")
+}
+
+// handleQuietHoursConfig shows or changes the daily hours during which the bot does not speak
+func (h *ConfigCommandHandler) handleQuietHoursConfig(s *discordgo.Session, i *discordgo.InteractionCreate, guildID string, opts options.Set) error {
+	config, err := h.configService.GetGuildConfig(guildID)
+	if err != nil {
+		h.logger.Printf("Error getting guild config for guild %s: %v", guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "config.quiet_hours.get_failed"))
+	}
+	if config == nil {
+		defaultConfig := DefaultGuildTTSConfig(guildID)
+		config = &defaultConfig
+	}
+
+	start, setStart := opts.String("start")
+	end, setEnd := opts.String("end")
+	timezone, setTimezone := opts.String("timezone")
+	off, _ := opts.Bool("off")
+	if !setStart && !setEnd && !setTimezone && !off {
+		responseMessage := h.localizer.T(guildID, "config.quiet_hours.show", h.describeQuietHours(guildID, config))
+		return h.respondSuccess(s, i, responseMessage)
+	}
+
+	updated := *config
+	if off {
+		updated.QuietHoursStart, updated.QuietHoursEnd = "", ""
+	}
+	if setStart {
+		updated.QuietHoursStart = strings.TrimSpace(start)
+	}
+	if setEnd {
+		updated.QuietHoursEnd = strings.TrimSpace(end)
+	}
+	if setTimezone {
+		updated.Timezone = strings.TrimSpace(timezone)
+	}
+
+	if updated.QuietHoursStart != "" || updated.QuietHoursEnd != "" {
+		if _, err := ParseQuietHours(updated.QuietHoursStart, updated.QuietHoursEnd, updated.Timezone); err != nil {
+			return h.respondError(s, i, h.localizer.T(guildID, "config.quiet_hours.invalid", err))
+		}
+	} else if _, err := LoadTimezone(updated.Timezone); err != nil {
+		return h.respondError(s, i, h.localizer.T(guildID, "config.quiet_hours.invalid", err))
+	}
+
+	if err := h.configService.SetGuildConfig(guildID, &updated); err != nil {
+		h.logger.Printf("Error setting quiet hours for guild %s: %v", guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "config.quiet_hours.update_failed", err))
+	}
+
+	responseMessage := h.localizer.T(guildID, "config.quiet_hours.updated", h.describeQuietHours(guildID, &updated))
+	return h.respondSuccess(s, i, responseMessage)
+}
+
+// describeQuietHours returns a user-facing summary of a guild's quiet hours
+func (h *ConfigCommandHandler) describeQuietHours(guildID string, config *GuildTTSConfig) string {
+	hours, ok := QuietHoursFor(config)
+	if !ok {
+		return h.localizer.T(guildID, "common.off")
+	}
+	return hours.String()
+}
+
+// handleCommandPrefixConfig shows or changes the prefix that starts text commands
+func (h *ConfigCommandHandler) handleCommandPrefixConfig(s *discordgo.Session, i *discordgo.InteractionCreate, guildID string, opts options.Set) error {
+	config, err := h.configService.GetGuildConfig(guildID)
+	if err != nil {
+		h.logger.Printf("Error getting guild config for guild %s: %v", guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "config.command_prefix.get_failed"))
+	}
+	if config == nil {
+		defaultConfig := DefaultGuildTTSConfig(guildID)
+		config = &defaultConfig
+	}
+
+	prefix, ok := opts.String("prefix")
+	if !ok {
+		responseMessage := h.localizer.T(guildID, "config.command_prefix.show", h.describeCommandPrefix(guildID, CommandPrefixFor(config)))
+		return h.respondSuccess(s, i, responseMessage)
+	}
 
-// Handle processes the config command interaction
-func (h *ConfigCommandHandler) Handle(s *discordgo.Session, i *discordgo.InteractionCreate) error {
-	// Validate guild context
-	if i.GuildID == "" {
-		return h.respondError(s, i, "This command can only be used in a server.")
+	prefix, err = NormalizeCommandPrefix(prefix)
+	if err != nil {
+		return h.respondError(s, i, h.localizer.T(guildID, "config.command_prefix.invalid", err))
 	}
 
-	userID := i.Member.User.ID
-	guildID := i.GuildID
+	updated := *config
+	updated.CommandPrefix = prefix
+	if prefix == DefaultCommandPrefix {
+		updated.CommandPrefix = ""
+	}
 
-	// Validate administrator permissions
-	if err := h.ValidatePermissions(userID, guildID); err != nil {
-		return h.respondError(s, i, fmt.Sprintf("Permission denied: %v", err))
+	if err := h.configService.SetGuildConfig(guildID, &updated); err != nil {
+		h.logger.Printf("Error setting command prefix for guild %s: %v", guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "config.command_prefix.update_failed", err))
 	}
 
-	// Extract subcommand
-	options := i.ApplicationCommandData().Options
-	if len(options) == 0 {
-		return h.respondError(s, i, "No subcommand specified.")
+	if prefix == CommandPrefixOff {
+		return h.respondSuccess(s, i, h.localizer.T(guildID, "config.command_prefix.disabled"))
 	}
+	return h.respondSuccess(s, i, h.localizer.T(guildID, "config.command_prefix.updated", prefix))
+}
 
-	subcommand := options[0]
-	switch subcommand.Name {
-	case "roles":
-		return h.handleRolesConfig(s, i, guildID, subcommand.Options)
-	case "voice":
-		return h.handleVoiceConfig(s, i, guildID, subcommand.Options)
-	case "queue":
-		return h.handleQueueConfig(s, i, guildID, subcommand.Options)
-	case "show":
-		return h.handleShowConfig(s, i, guildID)
-	default:
-		return h.respondError(s, i, "Invalid subcommand.")
+// describeCommandPrefix returns a user-facing label for the text command prefix
+func (h *ConfigCommandHandler) describeCommandPrefix(guildID, prefix string) string {
+	if prefix == "" {
+		return h.localizer.T(guildID, "common.off")
 	}
+	return "`" + prefix + "`"
 }
 
-// handleRolesConfig handles role configuration commands
-func (h *ConfigCommandHandler) handleRolesConfig(s *discordgo.Session, i *discordgo.InteractionCreate, guildID string, options []*discordgo.ApplicationCommandInteractionDataOption) error {
-	if len(options) == 0 {
-		return h.respondError(s, i, "No action specified for roles configuration.")
+// handleProfileConfig handles the /darrot-profile subcommands that save, switch, delete and
+// list profiles
+func (h *ConfigCommandHandler) handleProfileConfig(s *discordgo.Session, i *discordgo.InteractionCreate, guildID, subcommand string, opts options.Set) error {
+	if subcommand == "list" {
+		return h.handleListProfiles(s, i, guildID)
 	}
 
-	action := options[0].StringValue()
+	name, err := opts.RequiredString("name")
+	if err != nil {
+		return h.respondError(s, i, h.localizer.OptionError(guildID, err))
+	}
 
-	switch action {
-	case "list":
-		return h.handleListRoles(s, i, guildID)
-	case "clear":
-		return h.handleClearRoles(s, i, guildID)
-	case "set", "add", "remove":
-		if len(options) < 2 {
-			return h.respondError(s, i, fmt.Sprintf("Role parameter required for '%s' action.", action))
+	switch subcommand {
+	case "save":
+		return h.handleSaveProfile(s, i, guildID, name)
+	case "use":
+		return h.handleUseProfile(s, i, guildID, name)
+	case "delete":
+		if err := h.configService.DeleteProfile(guildID, name); err != nil {
+			if errors.Is(err, ErrProfileNotFound) {
+				return h.respondError(s, i, h.localizer.T(guildID, "config.profile.not_found", name))
+			}
+			h.logger.Printf("Error deleting profile for guild %s: %v", guildID, err)
+			return h.respondError(s, i, h.localizer.T(guildID, "config.profile.delete_failed", err))
 		}
-		roleID := options[1].RoleValue(s, guildID).ID
-		return h.handleRoleAction(s, i, guildID, action, roleID)
+		return h.respondSuccess(s, i, h.localizer.T(guildID, "config.profile.deleted", name))
 	default:
-		return h.respondError(s, i, "Invalid action for roles configuration.")
+		return h.respondError(s, i, h.localizer.T(guildID, "common.invalid_subcommand"))
 	}
 }
 
-// handleRoleAction handles individual role actions (set, add, remove)
-func (h *ConfigCommandHandler) handleRoleAction(s *discordgo.Session, i *discordgo.InteractionCreate, guildID, action, roleID string) error {
-	currentRoles, err := h.configService.GetRequiredRoles(guildID)
+// handleSaveProfile saves the guild's current voice, queue and moderation settings as a profile
+func (h *ConfigCommandHandler) handleSaveProfile(s *discordgo.Session, i *discordgo.InteractionCreate, guildID, name string) error {
+	name, err := NormalizeProfileName(name)
 	if err != nil {
-		h.logger.Printf("Error getting required roles for guild %s: %v", guildID, err)
-		return h.respondError(s, i, "Failed to get current role configuration.")
+		return h.respondError(s, i, h.localizer.T(guildID, "config.profile.save_failed", err))
 	}
 
-	var newRoles []string
-	var actionMessage string
-
-	switch action {
-	case "set":
-		newRoles = []string{roleID}
-		actionMessage = "Required role set to"
-	case "add":
-		// Check if role already exists
-		for _, existingRole := range currentRoles {
-			if existingRole == roleID {
-				return h.respondError(s, i, "Role is already in the required roles list.")
-			}
-		}
-		currentRoles = append(currentRoles, roleID)
-		newRoles = currentRoles
-		actionMessage = "Role added to required roles:"
-	case "remove":
-		// Remove role from list
-		for _, existingRole := range currentRoles {
-			if existingRole != roleID {
-				newRoles = append(newRoles, existingRole)
-			}
-		}
-		if len(newRoles) == len(currentRoles) {
-			return h.respondError(s, i, "Role was not found in the required roles list.")
-		}
-		actionMessage = "Role removed from required roles:"
+	config, err := h.configService.GetGuildConfig(guildID)
+	if err != nil {
+		h.logger.Printf("Error getting guild config for guild %s: %v", guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "config.profile.get_failed"))
 	}
 
-	// Update the configuration
-	if err := h.configService.SetRequiredRoles(guildID, newRoles); err != nil {
-		h.logger.Printf("Error setting required roles for guild %s: %v", guildID, err)
-		return h.respondError(s, i, "Failed to update role configuration.")
+	profile := GuildProfile{
+		Name:         name,
+		TTSSettings:  config.TTSSettings,
+		MaxQueueSize: config.MaxQueueSize,
+		CreatedBy:    i.Member.User.ID,
+	}
+	if h.moderationService != nil {
+		settings, err := h.moderationService.GetSettings(guildID)
+		if err != nil {
+			h.logger.Printf("Error getting moderation settings for guild %s: %v", guildID, err)
+			return h.respondError(s, i, h.localizer.T(guildID, "config.profile.get_failed"))
+		}
+		profile.Moderation = &ModerationSettings{GuildID: guildID, Mode: settings.Mode, Blocklist: slices.Clone(settings.Blocklist)}
 	}
 
-	// Get role name for response
-	role, err := s.State.Role(guildID, roleID)
-	if err != nil {
-		role = &discordgo.Role{ID: roleID, Name: roleID} // Fallback to ID
+	if err := h.configService.SaveProfile(guildID, profile); err != nil {
+		if errors.Is(err, ErrProfileLimit) {
+			return h.respondError(s, i, h.localizer.T(guildID, "config.profile.limit", MaxGuildProfiles))
+		}
+		return h.respondError(s, i, h.localizer.T(guildID, "config.profile.save_failed", err))
 	}
 
-	var responseMessage string
-	if len(newRoles) == 0 {
-		responseMessage = fmt.Sprintf("✅ %s **%s**\n\nNo roles are now required - any server member can invite the bot to voice channels.", actionMessage, role.Name)
-	} else {
-		responseMessage = fmt.Sprintf("✅ %s **%s**\n\nTotal required roles: %d", actionMessage, role.Name, len(newRoles))
+	// Saving the settings in use under a new name makes that profile the active one
+	if config.ActiveProfile != name {
+		if _, err := h.configService.UseProfile(guildID, name); err != nil {
+			h.logger.Printf("Warning: Failed to mark profile as active for guild %s: %v", guildID, err)
+		}
 	}
 
-	return h.respondSuccess(s, i, responseMessage)
+	return h.respondSuccess(s, i, h.localizer.T(guildID, "config.profile.saved", name))
 }
 
-// handleListRoles lists current required roles
-func (h *ConfigCommandHandler) handleListRoles(s *discordgo.Session, i *discordgo.InteractionCreate, guildID string) error {
-	roles, err := h.configService.GetRequiredRoles(guildID)
+// handleUseProfile switches the guild to a profile's settings, including the running
+// message queue and moderation
+func (h *ConfigCommandHandler) handleUseProfile(s *discordgo.Session, i *discordgo.InteractionCreate, guildID, name string) error {
+	profile, err := h.configService.UseProfile(guildID, name)
 	if err != nil {
-		h.logger.Printf("Error getting required roles for guild %s: %v", guildID, err)
-		return h.respondError(s, i, "Failed to get role configuration.")
+		if errors.Is(err, ErrProfileNotFound) {
+			return h.respondError(s, i, h.localizer.T(guildID, "config.profile.not_found", name))
+		}
+		h.logger.Printf("Error switching profile for guild %s: %v", guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "config.profile.use_failed", err))
 	}
 
-	if len(roles) == 0 {
-		return h.respondSuccess(s, i, "📋 **Required Roles Configuration**\n\nNo roles are currently required - any server member can invite the bot to voice channels.")
+	if err := h.messageQueue.SetMaxSize(guildID, profile.MaxQueueSize); err != nil {
+		h.logger.Printf("Warning: Failed to update queue size for guild %s: %v", guildID, err)
 	}
 
-	var roleNames []string
-	for _, roleID := range roles {
-		role, err := s.State.Role(guildID, roleID)
-		if err != nil {
-			roleNames = append(roleNames, fmt.Sprintf("Unknown Role (%s)", roleID))
-		} else {
-			roleNames = append(roleNames, role.Name)
+	if profile.Moderation != nil && h.moderationService != nil {
+		if err := h.moderationService.ReplaceSettings(guildID, *profile.Moderation); err != nil {
+			h.logger.Printf("Error applying profile moderation settings for guild %s: %v", guildID, err)
+			return h.respondError(s, i, h.localizer.T(guildID, "config.profile.moderation_failed", profile.Name))
 		}
 	}
 
-	responseMessage := fmt.Sprintf("📋 **Required Roles Configuration**\n\nUsers must have one of these roles to invite the bot:\n• %s",
-		fmt.Sprintf("**%s**", roleNames[0]))
+	return h.respondSuccess(s, i, h.localizer.T(guildID, "config.profile.used", profile.Name, profile.TTSSettings.Voice,
+		profile.TTSSettings.Speed, profile.TTSSettings.Volume, profile.MaxQueueSize))
+}
 
-	for _, roleName := range roleNames[1:] {
-		responseMessage += fmt.Sprintf("\n• **%s**", roleName)
+// handleListProfiles lists the guild's profiles, marking the active one
+func (h *ConfigCommandHandler) handleListProfiles(s *discordgo.Session, i *discordgo.InteractionCreate, guildID string) error {
+	profiles, err := h.configService.GetProfiles(guildID)
+	if err != nil {
+		h.logger.Printf("Error getting profiles for guild %s: %v", guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "config.profile.get_failed"))
+	}
+	if len(profiles) == 0 {
+		return h.respondSuccess(s, i, h.localizer.T(guildID, "config.profile.none"))
 	}
 
-	return h.respondSuccess(s, i, responseMessage)
-}
+	active := ""
+	if config, err := h.configService.GetGuildConfig(guildID); err == nil && config != nil {
+		active = config.ActiveProfile
+	}
 
-// handleClearRoles clears all required roles
-func (h *ConfigCommandHandler) handleClearRoles(s *discordgo.Session, i *discordgo.InteractionCreate, guildID string) error {
-	if err := h.configService.SetRequiredRoles(guildID, []string{}); err != nil {
-		h.logger.Printf("Error clearing required roles for guild %s: %v", guildID, err)
-		return h.respondError(s, i, "Failed to clear role configuration.")
+	var lines []string
+	for _, profile := range profiles {
+		line := h.localizer.T(guildID, "config.profile.entry", profile.Name, profile.TTSSettings.Voice,
+			profile.TTSSettings.Speed, profile.TTSSettings.Volume, profile.MaxQueueSize)
+		if profile.Name == active {
+			line += h.localizer.T(guildID, "config.profile.active")
+		}
+		lines = append(lines, line)
 	}
 
-	return h.respondSuccess(s, i, "✅ **All required roles cleared**\n\nAny server member can now invite the bot to voice channels.")
+	return h.respondSuccess(s, i, h.localizer.T(guildID, "config.profile.list", strings.Join(lines, "\n")))
 }
 
-// handleVoiceConfig handles voice configuration commands
-func (h *ConfigCommandHandler) handleVoiceConfig(s *discordgo.Session, i *discordgo.InteractionCreate, guildID string, options []*discordgo.ApplicationCommandInteractionDataOption) error {
-	if len(options) == 0 {
-		return h.respondError(s, i, "No setting specified for voice configuration.")
+// describeActiveProfile returns a user-facing name for the guild's active profile
+func (h *ConfigCommandHandler) describeActiveProfile(guildID, name string) string {
+	if name == "" {
+		return h.localizer.T(guildID, "config.profile.no_active")
 	}
+	return name
+}
 
-	setting := options[0].StringValue()
-
-	switch setting {
-	case "list-voices":
-		return h.handleListVoices(s, i)
-	case "voice", "speed", "volume":
-		if len(options) < 2 {
-			return h.handleShowVoiceSetting(s, i, guildID, setting)
-		}
-		value := options[1].StringValue()
-		return h.handleSetVoiceSetting(s, i, guildID, setting, value)
-	default:
-		return h.respondError(s, i, "Invalid setting for voice configuration.")
+// handleExportConfig replies with the guild's complete configuration as a JSON file
+func (h *ConfigCommandHandler) handleExportConfig(s *discordgo.Session, i *discordgo.InteractionCreate, guildID string) error {
+	export, err := h.configService.ExportGuildConfig(guildID)
+	if err != nil {
+		h.logger.Printf("Error exporting config for guild %s: %v", guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "config.export.failed"))
 	}
-}
 
-// handleListVoices lists available TTS voices
-func (h *ConfigCommandHandler) handleListVoices(s *discordgo.Session, i *discordgo.InteractionCreate) error {
-	voices := h.ttsManager.GetSupportedVoices()
-	if len(voices) == 0 {
-		return h.respondError(s, i, "No voices are currently available.")
+	if h.moderationService != nil {
+		settings, err := h.moderationService.GetSettings(guildID)
+		if err != nil {
+			h.logger.Printf("Error exporting moderation settings for guild %s: %v", guildID, err)
+			return h.respondError(s, i, h.localizer.T(guildID, "config.export.failed"))
+		}
+		export.Moderation = settings
 	}
 
-	responseMessage := "🎤 **Available TTS Voices**\n\n"
-	for _, voice := range voices {
-		responseMessage += fmt.Sprintf("• **%s** (%s) - %s %s\n", voice.Name, voice.ID, voice.Language, voice.Gender)
+	data, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		h.logger.Printf("Error encoding config export for guild %s: %v", guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "config.export.failed"))
 	}
 
-	return h.respondSuccess(s, i, responseMessage)
+	return textcmd.Respond(s, i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: h.localizer.T(guildID, "config.export.ready"),
+			Files: []*discordgo.File{{
+				Name:        fmt.Sprintf("darrot-config-%s.json", guildID),
+				ContentType: "application/json",
+				Reader:      bytes.NewReader(data),
+			}},
+			Flags: discordgo.MessageFlagsEphemeral,
+		},
+	})
 }
 
-// handleShowVoiceSetting shows current voice setting value
-func (h *ConfigCommandHandler) handleShowVoiceSetting(s *discordgo.Session, i *discordgo.InteractionCreate, guildID, setting string) error {
-	config, err := h.configService.GetTTSSettings(guildID)
-	if err != nil {
-		h.logger.Printf("Error getting TTS settings for guild %s: %v", guildID, err)
-		return h.respondError(s, i, "Failed to get current voice settings.")
+// handleImportConfig replaces the guild's configuration with an attached export. The whole
+// file is validated before anything is changed.
+func (h *ConfigCommandHandler) handleImportConfig(s *discordgo.Session, i *discordgo.InteractionCreate, guildID string, opts options.Set) error {
+	attachmentID, _ := opts.String("file")
+	resolved := i.ApplicationCommandData().Resolved
+	if resolved == nil || resolved.Attachments[attachmentID] == nil {
+		return h.respondError(s, i, h.localizer.T(guildID, "config.import.attach_file"))
 	}
 
-	var currentValue string
-	switch setting {
-	case "voice":
-		currentValue = config.Voice
-		if currentValue == "" {
-			currentValue = "default"
-		}
-	case "speed":
-		currentValue = fmt.Sprintf("%.2f", config.Speed)
-	case "volume":
-		currentValue = fmt.Sprintf("%.2f", config.Volume)
+	attachment := resolved.Attachments[attachmentID]
+	if attachment.Size > MaxConfigImportBytes {
+		return h.respondError(s, i, h.localizer.T(guildID, "config.import.file_too_large", MaxConfigImportBytes/1024))
 	}
 
-	responseMessage := fmt.Sprintf("🎤 **Current %s setting:** %s", setting, currentValue)
-	return h.respondSuccess(s, i, responseMessage)
-}
+	// Downloading can exceed Discord's response deadline
+	if err := textcmd.Respond(s, i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Flags: discordgo.MessageFlagsEphemeral,
+		},
+	}); err != nil {
+		return err
+	}
 
-// handleSetVoiceSetting sets a voice configuration setting
-func (h *ConfigCommandHandler) handleSetVoiceSetting(s *discordgo.Session, i *discordgo.InteractionCreate, guildID, setting, value string) error {
-	// Get current settings
-	currentConfig, err := h.configService.GetTTSSettings(guildID)
+	data, err := downloadAttachment(h.httpClient, attachment.URL, MaxConfigImportBytes)
 	if err != nil {
-		h.logger.Printf("Error getting TTS settings for guild %s: %v", guildID, err)
-		return h.respondError(s, i, "Failed to get current voice settings.")
+		h.logger.Printf("Failed to download config import for guild %s: %v", guildID, err)
+		return h.editResponse(s, i, "❌ "+h.localizer.T(guildID, "config.import.download_failed"))
 	}
 
-	// Create new config with updated setting
-	newConfig := *currentConfig
+	export, err := ParseConfigExport(data)
+	if err != nil {
+		return h.editResponse(s, i, "❌ "+h.localizer.T(guildID, "config.import.invalid", err))
+	}
 
-	switch setting {
-	case "voice":
-		// Validate voice exists
-		voices := h.ttsManager.GetSupportedVoices()
-		validVoice := false
-		for _, voice := range voices {
-			if voice.ID == value || voice.Name == value {
-				newConfig.Voice = voice.ID
-				validVoice = true
-				break
-			}
-		}
-		if !validVoice {
-			return h.respondError(s, i, fmt.Sprintf("Invalid voice '%s'. Use `/tts-config voice list-voices` to see available voices.", value))
-		}
+	if err := h.configService.ImportGuildConfig(guildID, export); err != nil {
+		h.logger.Printf("Error importing config for guild %s: %v", guildID, err)
+		return h.editResponse(s, i, "❌ "+h.localizer.T(guildID, "config.import.failed"))
+	}
 
-	case "speed":
-		speed, err := parseFloat32(value)
-		if err != nil || speed < 0.25 || speed > 4.0 {
-			return h.respondError(s, i, "Speed must be a number between 0.25 and 4.0")
-		}
-		newConfig.Speed = speed
+	if err := h.messageQueue.SetMaxSize(guildID, export.Config.MaxQueueSize); err != nil {
+		h.logger.Printf("Warning: Failed to update message queue max size for guild %s: %v", guildID, err)
+	}
 
-	case "volume":
-		volume, err := parseFloat32(value)
-		if err != nil || volume < 0.0 || volume > 1.0 {
-			return h.respondError(s, i, "Volume must be a number between 0.0 and 1.0")
+	if export.Moderation != nil && h.moderationService != nil {
+		if err := h.moderationService.ReplaceSettings(guildID, *export.Moderation); err != nil {
+			h.logger.Printf("Error importing moderation settings for guild %s: %v", guildID, err)
+			return h.editResponse(s, i, "❌ "+h.localizer.T(guildID, "config.import.moderation_failed"))
 		}
-		newConfig.Volume = volume
 	}
 
-	// Update the configuration
-	if err := h.configService.SetTTSSettings(guildID, newConfig); err != nil {
-		h.logger.Printf("Error setting TTS settings for guild %s: %v", guildID, err)
-		return h.respondError(s, i, "Failed to update voice settings.")
-	}
+	h.logger.Printf("Imported configuration from guild %s into guild %s", export.Config.GuildID, guildID)
 
-	// Update TTS manager with new config
-	if err := h.ttsManager.SetVoiceConfig(guildID, newConfig); err != nil {
-		h.logger.Printf("Warning: Failed to update TTS manager config for guild %s: %v", guildID, err)
+	responseMessage := h.localizer.T(guildID, "config.import.done")
+	if export.Config.GuildID != guildID {
+		responseMessage += "\n" + h.localizer.T(guildID, "config.import.roles_cleared")
 	}
-
-	responseMessage := fmt.Sprintf("✅ **%s updated to:** %s", setting, value)
-	return h.respondSuccess(s, i, responseMessage)
+	err = h.editResponse(s, i, responseMessage)
+	h.recordConfigChange(i, responseMessage)
+	return err
 }
 
-// handleQueueConfig handles queue configuration commands
-func (h *ConfigCommandHandler) handleQueueConfig(s *discordgo.Session, i *discordgo.InteractionCreate, guildID string, options []*discordgo.ApplicationCommandInteractionDataOption) error {
-	if len(options) == 0 {
-		return h.respondError(s, i, "No setting specified for queue configuration.")
+// handleAuditConfig sets or shows the channel that receives audit log entries
+func (h *ConfigCommandHandler) handleAuditConfig(s *discordgo.Session, i *discordgo.InteractionCreate, guildID string, opts options.Set) error {
+	if h.auditLog == nil {
+		return h.respondError(s, i, h.localizer.T(guildID, "config.audit.unavailable"))
 	}
 
-	setting := options[0].StringValue()
+	action, err := opts.RequiredString("action")
+	if err != nil {
+		return h.respondError(s, i, h.localizer.OptionError(guildID, err))
+	}
 
-	switch setting {
+	switch action {
 	case "show":
-		return h.handleShowQueueConfig(s, i, guildID)
-	case "max-size":
-		if len(options) < 2 {
-			return h.handleShowQueueConfig(s, i, guildID)
+		return h.respondSuccess(s, i, h.localizer.T(guildID, "config.audit.show", h.describeAuditChannel(guildID, h.auditLog.Channel(guildID))))
+	case "set":
+		channelID, ok := opts.ChannelID("channel")
+		if !ok {
+			return h.respondError(s, i, h.localizer.OptionError(guildID, options.Missing("channel")))
 		}
-		size := int(options[1].IntValue())
-		return h.handleSetMaxQueueSize(s, i, guildID, size)
+		if err := h.auditLog.SetChannel(guildID, channelID); err != nil {
+			h.logger.Printf("Error setting audit channel for guild %s: %v", guildID, err)
+			return h.respondError(s, i, h.localizer.T(guildID, "config.audit.update_failed"))
+		}
+		return h.respondSuccess(s, i, h.localizer.T(guildID, "config.audit.updated", h.describeAuditChannel(guildID, channelID)))
+	case "off":
+		if err := h.auditLog.SetChannel(guildID, ""); err != nil {
+			h.logger.Printf("Error turning off audit channel for guild %s: %v", guildID, err)
+			return h.respondError(s, i, h.localizer.T(guildID, "config.audit.update_failed"))
+		}
+		return h.respondSuccess(s, i, h.localizer.T(guildID, "config.audit.updated", h.describeAuditChannel(guildID, "")))
 	default:
-		return h.respondError(s, i, "Invalid setting for queue configuration.")
+		return h.respondError(s, i, h.localizer.T(guildID, "config.audit.invalid_action"))
 	}
 }
 
-// handleShowQueueConfig shows current queue configuration
-func (h *ConfigCommandHandler) handleShowQueueConfig(s *discordgo.Session, i *discordgo.InteractionCreate, guildID string) error {
-	maxSize, err := h.configService.GetMaxQueueSize(guildID)
-	if err != nil {
-		h.logger.Printf("Error getting max queue size for guild %s: %v", guildID, err)
-		return h.respondError(s, i, "Failed to get queue configuration.")
+// describeAuditChannel returns the audit channel as a mention, or that auditing is off
+func (h *ConfigCommandHandler) describeAuditChannel(guildID, channelID string) string {
+	if channelID == "" {
+		return h.localizer.T(guildID, "common.off")
 	}
-
-	currentSize := h.messageQueue.Size(guildID)
-	responseMessage := fmt.Sprintf("📋 **Message Queue Configuration**\n\nMax queue size: **%d**\nCurrent queue size: **%d**", maxSize, currentSize)
-
-	return h.respondSuccess(s, i, responseMessage)
+	return channelMention(channelID)
 }
 
-// handleSetMaxQueueSize sets the maximum queue size
-func (h *ConfigCommandHandler) handleSetMaxQueueSize(s *discordgo.Session, i *discordgo.InteractionCreate, guildID string, size int) error {
-	// Validate size range
-	if size < 1 || size > 50 {
-		return h.respondError(s, i, "Queue size must be between 1 and 50.")
+// recordConfigChange posts a configuration command and its response to the guild's audit
+// channel. Requests that only show or list settings change nothing and are not recorded.
+func (h *ConfigCommandHandler) recordConfigChange(i *discordgo.InteractionCreate, result string) {
+	if h.auditLog == nil {
+		return
 	}
 
-	// Update configuration
-	if err := h.configService.SetMaxQueueSize(guildID, size); err != nil {
-		h.logger.Printf("Error setting max queue size for guild %s: %v", guildID, err)
-		return h.respondError(s, i, "Failed to update queue configuration.")
+	subcommand, opts, ok := options.FromInteraction(i).Subcommand()
+	if !ok || subcommand == "show" || subcommand == "export" || subcommand == "list" {
+		return
+	}
+	for name := range opts {
+		if value, _ := opts.String(name); value == "show" || value == "list" {
+			return
+		}
 	}
 
-	// Update message queue
-	if err := h.messageQueue.SetMaxSize(guildID, size); err != nil {
-		h.logger.Printf("Warning: Failed to update message queue max size for guild %s: %v", guildID, err)
+	h.auditLog.RecordConfigChange(i.GuildID, i.Member.User.ID, commandLine(i), result)
+}
+
+// describeLanguage returns the name of the guild's response language in that language
+func (h *ConfigCommandHandler) describeLanguage(guildID string) string {
+	return h.localizer.T(guildID, i18n.LanguageNameKey)
+}
+
+// describeEnabled returns a user-facing label for a toggle
+func (h *ConfigCommandHandler) describeEnabled(guildID string, enabled bool) string {
+	if enabled {
+		return h.localizer.T(guildID, "common.on")
 	}
+	return h.localizer.T(guildID, "common.off")
+}
 
-	responseMessage := fmt.Sprintf("✅ **Maximum queue size updated to:** %d", size)
-	return h.respondSuccess(s, i, responseMessage)
+// describeEngineStatus returns whether the TTS engine is available. The engine error itself
+// is only logged, as it may contain details of the bot's host.
+func (h *ConfigCommandHandler) describeEngineStatus(guildID string, engineStatus TTSEngineStatus) string {
+	if engineStatus.EngineError() != nil {
+		return h.localizer.T(guildID, "config.engine.unavailable")
+	}
+	return h.localizer.T(guildID, "config.engine.available")
 }
 
 // handleShowConfig shows complete TTS configuration
@@ -1220,37 +4123,119 @@ func (h *ConfigCommandHandler) handleShowConfig(s *discordgo.Session, i *discord
 	config, err := h.configService.GetGuildConfig(guildID)
 	if err != nil {
 		h.logger.Printf("Error getting guild config for guild %s: %v", guildID, err)
-		return h.respondError(s, i, "Failed to get server configuration.")
+		return h.respondError(s, i, h.localizer.T(guildID, "config.show.get_failed"))
 	}
 
-	responseMessage := "⚙️ **TTS Configuration for this Server**\n\n"
+	responseMessage := h.localizer.T(guildID, "config.show.title")
+
+	// Speech engine, only shown when it can be unavailable
+	if engineStatus, ok := h.ttsManager.(TTSEngineStatus); ok {
+		responseMessage += h.localizer.T(guildID, "config.show.engine", h.describeEngineStatus(guildID, engineStatus))
+	}
 
 	// Required roles
 	if len(config.RequiredRoles) == 0 {
-		responseMessage += "**Required Roles:** None (any member can invite bot)\n"
+		responseMessage += h.localizer.T(guildID, "config.show.roles_none")
 	} else {
-		responseMessage += "**Required Roles:**\n"
+		responseMessage += h.localizer.T(guildID, "config.show.roles")
 		for _, roleID := range config.RequiredRoles {
 			role, err := s.State.Role(guildID, roleID)
 			if err != nil {
-				responseMessage += fmt.Sprintf("• Unknown Role (%s)\n", roleID)
+				responseMessage += "• " + h.localizer.T(guildID, "config.roles.unknown", roleID) + "\n"
 			} else {
 				responseMessage += fmt.Sprintf("• %s\n", role.Name)
 			}
 		}
 	}
+	if config.SyncRolePermissions {
+		responseMessage += h.localizer.T(guildID, "config.show.roles_synced")
+	}
+
+	// Speaker roles
+	responseMessage += h.localizer.T(guildID, "config.show.speaker_roles", h.describeSpeakerRoles(guildID, config.SpeakerRoles))
+
+	// Bots and webhooks
+	responseMessage += h.localizer.T(guildID, "config.show.bots", h.describeEnabled(guildID, config.ReadBots),
+		h.describeEnabled(guildID, config.ReadWebhooks), h.describeAllowedBots(guildID, config.AllowedBots))
 
 	// TTS settings
-	responseMessage += "\n**Voice Settings:**\n"
-	responseMessage += fmt.Sprintf("• Voice: %s\n", config.TTSSettings.Voice)
-	responseMessage += fmt.Sprintf("• Speed: %.2f\n", config.TTSSettings.Speed)
-	responseMessage += fmt.Sprintf("• Volume: %.2f\n", config.TTSSettings.Volume)
+	responseMessage += h.localizer.T(guildID, "config.show.voice", config.TTSSettings.Voice, config.TTSSettings.Speed, config.TTSSettings.Volume)
+	responseMessage += h.localizer.T(guildID, "config.show.voice_options", config.TTSSettings.Pitch,
+		h.voiceOptionOrOff(guildID, config.TTSSettings.EffectsProfile), h.voiceOptionOrOff(guildID, config.TTSSettings.Style),
+		h.describeLoudness(guildID, config.TTSSettings.Loudness))
 
 	// Queue settings
 	currentQueueSize := h.messageQueue.Size(guildID)
-	responseMessage += "\n**Queue Settings:**\n"
-	responseMessage += fmt.Sprintf("• Max Size: %d\n", config.MaxQueueSize)
-	responseMessage += fmt.Sprintf("• Current Size: %d\n", currentQueueSize)
+	responseMessage += h.localizer.T(guildID, "config.show.queue", config.MaxQueueSize, currentQueueSize, h.describeLengthPolicy(guildID, LengthPolicyFor(config)),
+		h.describeUserMessageLimit(guildID, UserMessagesPerMinuteFor(config)))
+
+	// Privacy settings
+	if h.contentPolicy != nil {
+		responseMessage += h.localizer.T(guildID, "config.show.privacy", h.describeContentRetention(guildID, h.contentPolicy.Mode(guildID)))
+	}
+
+	// Response language
+	if h.localizer != nil {
+		responseMessage += h.localizer.T(guildID, "config.show.language", h.describeLanguage(guildID))
+	}
+
+	// Ignore prefixes
+	responseMessage += h.localizer.T(guildID, "config.show.ignore_prefixes", h.describeIgnorePrefixes(guildID, config.IgnorePrefixes))
+
+	// Link, code block, spoiler and emoji modes
+	responseMessage += h.localizer.T(guildID, "config.show.content", h.describeContentModes(guildID, config))
+
+	// Idle announcement and disconnect timeouts
+	responseMessage += h.localizer.T(guildID, "config.show.idle", h.describeIdleSettings(guildID, config))
+	responseMessage += h.localizer.T(guildID, "config.show.quiet_hours", h.describeQuietHours(guildID, config))
+
+	// Extra audio output
+	responseMessage += h.localizer.T(guildID, "config.show.output", h.describeAudioOutput(guildID, config.AudioOutput))
+	responseMessage += h.localizer.T(guildID, "config.show.recording", h.describeRecording(guildID, config))
+	responseMessage += h.localizer.T(guildID, "config.show.control", h.describeControlLimits(guildID, config))
+
+	// Text command prefix
+	responseMessage += h.localizer.T(guildID, "config.show.command_prefix", h.describeCommandPrefix(guildID, CommandPrefixFor(config)))
+
+	// Configuration profile
+	responseMessage += h.localizer.T(guildID, "config.show.profile", h.describeActiveProfile(guildID, config.ActiveProfile))
+
+	// Announcement settings
+	if h.voiceAnnouncer != nil {
+		responseMessage += h.localizer.T(guildID, "config.show.announcements", h.describeEnabled(guildID, config.AnnounceVoiceEvents),
+			h.describeEnabled(guildID, h.reactionSummarizer != nil && config.ReadReactions),
+			h.describeEnabled(guildID, h.skipNotes != nil && config.AnnounceSkipped))
+	}
+
+	// Spoken commands
+	if h.voiceCommands != nil && h.voiceCommands.Available() {
+		responseMessage += h.localizer.T(guildID, "config.show.voice_commands", h.describeEnabled(guildID, config.VoiceCommands))
+	}
+
+	// Privacy notice for automatically opted-in users
+	if h.privacyService != nil {
+		responseMessage += h.localizer.T(guildID, "config.show.opt_in_notice", h.describeEnabled(guildID, config.OptInNoticeDM))
+	}
+
+	// Experimental features
+	if h.features != nil {
+		responseMessage += h.localizer.T(guildID, "config.show.features", h.describeFeatures(guildID))
+	}
+
+	// Audit channel
+	if h.auditLog != nil {
+		responseMessage += h.localizer.T(guildID, "config.show.audit", h.describeAuditChannel(guildID, config.AuditChannelID))
+	}
+
+	// Usage against the daily budget
+	if h.quotaService != nil {
+		usageSummary, err := h.formatQuotaUsage(guildID)
+		if err != nil {
+			h.logger.Printf("Error getting TTS usage for guild %s: %v", guildID, err)
+		} else {
+			responseMessage += h.localizer.T(guildID, "config.show.usage") + usageSummary
+		}
+	}
 
 	return h.respondSuccess(s, i, responseMessage)
 }
@@ -1268,7 +4253,7 @@ func (h *ConfigCommandHandler) ValidatePermissions(userID, guildID string) error
 	}
 
 	if !canControl {
-		return fmt.Errorf("you must have administrator permissions to configure TTS settings")
+		return withKind(ErrPermission, fmt.Errorf("you must have administrator permissions to configure TTS settings"))
 	}
 
 	return nil
@@ -1282,17 +4267,26 @@ func (h *ConfigCommandHandler) ValidateChannelAccess(userID, channelID string) e
 // Helper methods for response handling
 
 func (h *ConfigCommandHandler) respondSuccess(s *discordgo.Session, i *discordgo.InteractionCreate, message string) error {
-	return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+	err := textcmd.Respond(s, i.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseChannelMessageWithSource,
 		Data: &discordgo.InteractionResponseData{
 			Content: message,
 			Flags:   discordgo.MessageFlagsEphemeral, // Make config responses private to admin
 		},
 	})
+	h.recordConfigChange(i, message)
+	return err
+}
+
+func (h *ConfigCommandHandler) editResponse(s *discordgo.Session, i *discordgo.InteractionCreate, message string) error {
+	_, err := textcmd.EditResponse(s, i.Interaction, &discordgo.WebhookEdit{
+		Content: &message,
+	})
+	return err
 }
 
 func (h *ConfigCommandHandler) respondError(s *discordgo.Session, i *discordgo.InteractionCreate, message string) error {
-	return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+	return textcmd.Respond(s, i.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseChannelMessageWithSource,
 		Data: &discordgo.InteractionResponseData{
 			Content: "❌ " + message,
@@ -1300,14 +4294,3 @@ func (h *ConfigCommandHandler) respondError(s *discordgo.Session, i *discordgo.I
 		},
 	})
 }
-
-// parseFloat32 is a helper function to parse string to float32
-func parseFloat32(s string) (float32, error) {
-	f, err := fmt.Sscanf(s, "%f", new(float32))
-	if err != nil || f != 1 {
-		return 0, fmt.Errorf("invalid float value")
-	}
-	var result float32
-	_, _ = fmt.Sscanf(s, "%f", &result)
-	return result, nil
-}
@@ -198,6 +198,26 @@ func (m *MockChannelService) IsChannelPaired(guildID, textChannelID string) bool
 	return args.Bool(0)
 }
 
+func (m *MockChannelService) SetRequirePresence(guildID, voiceChannelID string, required bool) error {
+	args := m.Called(guildID, voiceChannelID, required)
+	return args.Error(0)
+}
+
+func (m *MockChannelService) RequiresPresence(guildID, textChannelID string) bool {
+	args := m.Called(guildID, textChannelID)
+	return args.Bool(0)
+}
+
+func (m *MockChannelService) SetGreeting(guildID, voiceChannelID, greeting string, readPinned bool) error {
+	args := m.Called(guildID, voiceChannelID, greeting, readPinned)
+	return args.Error(0)
+}
+
+func (m *MockChannelService) SetMirror(guildID, voiceChannelID, mirrorChannelID string, mirrorOnly bool) error {
+	args := m.Called(guildID, voiceChannelID, mirrorChannelID, mirrorOnly)
+	return args.Error(0)
+}
+
 type MockPermissionService struct {
 	mock.Mock
 }
@@ -212,6 +232,11 @@ func (m *MockPermissionService) CanControlBot(userID, guildID string) (bool, err
 	return args.Bool(0), args.Error(1)
 }
 
+func (m *MockPermissionService) CanBeRead(userID, guildID string) (bool, error) {
+	args := m.Called(userID, guildID)
+	return args.Bool(0), args.Error(1)
+}
+
 func (m *MockPermissionService) HasChannelAccess(userID, channelID string) (bool, error) {
 	args := m.Called(userID, channelID)
 	return args.Bool(0), args.Error(1)
@@ -251,6 +276,21 @@ func (m *MockUserService) AutoOptIn(userID, guildID string) error {
 	return args.Error(0)
 }
 
+func (m *MockUserService) MuteUser(listenerID, targetID, guildID string) error {
+	args := m.Called(listenerID, targetID, guildID)
+	return args.Error(0)
+}
+
+func (m *MockUserService) UnmuteUser(listenerID, targetID, guildID string) error {
+	args := m.Called(listenerID, targetID, guildID)
+	return args.Error(0)
+}
+
+func (m *MockUserService) GetMutedUsers(listenerID, guildID string) ([]string, error) {
+	args := m.Called(listenerID, guildID)
+	return args.Get(0).([]string), args.Error(1)
+}
+
 type MockMessageQueue struct {
 	mock.Mock
 }
@@ -370,7 +410,7 @@ func TestJoinCommandHandler_Definition(t *testing.T) {
 
 	assert.Equal(t, "darrot-join", definition.Name)
 	assert.Equal(t, "Join a voice channel and start TTS for messages from a text channel", definition.Description)
-	assert.Len(t, definition.Options, 2)
+	assert.Len(t, definition.Options, 8)
 
 	// Check voice channel option
 	voiceOption := definition.Options[0]
@@ -385,6 +425,42 @@ func TestJoinCommandHandler_Definition(t *testing.T) {
 	assert.Equal(t, discordgo.ApplicationCommandOptionChannel, textOption.Type)
 	assert.False(t, textOption.Required)
 	assert.Contains(t, textOption.ChannelTypes, discordgo.ChannelTypeGuildText)
+
+	// Check whisper option
+	whisperOption := definition.Options[2]
+	assert.Equal(t, "whisper", whisperOption.Name)
+	assert.Equal(t, discordgo.ApplicationCommandOptionBoolean, whisperOption.Type)
+	assert.False(t, whisperOption.Required)
+
+	// Check greeting options
+	greetingOption := definition.Options[3]
+	assert.Equal(t, "greeting", greetingOption.Name)
+	assert.Equal(t, discordgo.ApplicationCommandOptionString, greetingOption.Type)
+	assert.Equal(t, MaxGreetingLength, greetingOption.MaxLength)
+	assert.False(t, greetingOption.Required)
+
+	readPinnedOption := definition.Options[4]
+	assert.Equal(t, "read-pinned", readPinnedOption.Name)
+	assert.Equal(t, discordgo.ApplicationCommandOptionBoolean, readPinnedOption.Type)
+	assert.False(t, readPinnedOption.Required)
+
+	// Check mirror options
+	mirrorChannelOption := definition.Options[5]
+	assert.Equal(t, "mirror-channel", mirrorChannelOption.Name)
+	assert.Equal(t, discordgo.ApplicationCommandOptionChannel, mirrorChannelOption.Type)
+	assert.Equal(t, textChannelTypes, mirrorChannelOption.ChannelTypes)
+	assert.False(t, mirrorChannelOption.Required)
+
+	mirrorOnlyOption := definition.Options[6]
+	assert.Equal(t, "mirror-only", mirrorOnlyOption.Name)
+	assert.Equal(t, discordgo.ApplicationCommandOptionBoolean, mirrorOnlyOption.Type)
+	assert.False(t, mirrorOnlyOption.Required)
+
+	// Check voice chat option
+	voiceChatOption := definition.Options[7]
+	assert.Equal(t, "voice-chat", voiceChatOption.Name)
+	assert.Equal(t, discordgo.ApplicationCommandOptionBoolean, voiceChatOption.Type)
+	assert.False(t, voiceChatOption.Required)
 }
 
 func TestJoinCommandHandler_ValidatePermissions_Success(t *testing.T) {
@@ -638,15 +714,15 @@ func TestControlCommandHandler_Definition(t *testing.T) {
 	definition := handler.Definition()
 
 	assert.Equal(t, "darrot-control", definition.Name)
-	assert.Equal(t, "Control TTS playback (pause, resume, skip)", definition.Description)
-	assert.Len(t, definition.Options, 1)
+	assert.Equal(t, "Control TTS playback (pause, pause-for, resume, skip, clear, panel)", definition.Description)
+	assert.Len(t, definition.Options, 2)
 
 	// Check action option
 	actionOption := definition.Options[0]
 	assert.Equal(t, "action", actionOption.Name)
 	assert.Equal(t, discordgo.ApplicationCommandOptionString, actionOption.Type)
 	assert.True(t, actionOption.Required)
-	assert.Len(t, actionOption.Choices, 3)
+	assert.Len(t, actionOption.Choices, 6)
 
 	// Check choices
 	choices := make(map[string]string)
@@ -654,8 +730,17 @@ func TestControlCommandHandler_Definition(t *testing.T) {
 		choices[choice.Name] = choice.Value.(string)
 	}
 	assert.Equal(t, "pause", choices["pause"])
+	assert.Equal(t, "pause-for", choices["pause-for"])
 	assert.Equal(t, "resume", choices["resume"])
 	assert.Equal(t, "skip", choices["skip"])
+	assert.Equal(t, "clear", choices["clear"])
+	assert.Equal(t, "panel", choices["panel"])
+
+	// Check minutes option
+	minutesOption := definition.Options[1]
+	assert.Equal(t, "minutes", minutesOption.Name)
+	assert.Equal(t, discordgo.ApplicationCommandOptionInteger, minutesOption.Type)
+	assert.False(t, minutesOption.Required)
 }
 
 func TestControlCommandHandler_ValidatePermissions_Success(t *testing.T) {
@@ -1082,14 +1167,14 @@ func TestOptInCommandHandler_Definition(t *testing.T) {
 
 	assert.Equal(t, "darrot-optin", definition.Name)
 	assert.Equal(t, "Manage your TTS opt-in preferences", definition.Description)
-	assert.Len(t, definition.Options, 1)
+	assert.Len(t, definition.Options, 3)
 
 	// Check action option
 	actionOption := definition.Options[0]
 	assert.Equal(t, "action", actionOption.Name)
 	assert.Equal(t, discordgo.ApplicationCommandOptionString, actionOption.Type)
 	assert.True(t, actionOption.Required)
-	assert.Len(t, actionOption.Choices, 3)
+	assert.Len(t, actionOption.Choices, 4)
 
 	// Check choices
 	choices := make(map[string]string)
@@ -1099,6 +1184,13 @@ func TestOptInCommandHandler_Definition(t *testing.T) {
 	assert.Equal(t, "opt-in", choices["opt-in"])
 	assert.Equal(t, "opt-out", choices["opt-out"])
 	assert.Equal(t, "status", choices["status"])
+	assert.Equal(t, "channels", choices["channels"])
+
+	// The channel allowlist options are optional so the other actions stay one word
+	assert.Equal(t, "operation", definition.Options[1].Name)
+	assert.False(t, definition.Options[1].Required)
+	assert.Equal(t, "channel", definition.Options[2].Name)
+	assert.Equal(t, discordgo.ApplicationCommandOptionChannel, definition.Options[2].Type)
 }
 
 func TestOptInCommandHandler_ValidatePermissions_Success(t *testing.T) {
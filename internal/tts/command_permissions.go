@@ -0,0 +1,138 @@
+package tts
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// roleGatedCommands are the commands PermissionService limits to a guild's required roles.
+// Syncing gives those roles, and nobody else but administrators, these commands in Discord.
+var roleGatedCommands = []string{
+	"darrot-access",
+	"darrot-api",
+	"darrot-clip",
+	"darrot-config",
+	"darrot-control",
+	"darrot-debug",
+	"darrot-diagnose",
+	"darrot-join",
+	"darrot-leave",
+	"darrot-moderation",
+	"darrot-optin-admin",
+	"darrot-play",
+	"darrot-preview",
+	"darrot-profile",
+	"darrot-stats",
+	"darrot-transcript",
+}
+
+// IsRoleGated reports whether a command is limited to a guild's required roles, which
+// syncing gives the command in Discord
+func IsRoleGated(name string) bool {
+	return slices.Contains(roleGatedCommands, name)
+}
+
+// adminCommandPermissions returns the default member permissions of administrator
+// commands, which Discord only shows to members who can manage the server until the
+// server overrides them
+func adminCommandPermissions() *int64 {
+	permissions := int64(discordgo.PermissionManageGuild)
+	return &permissions
+}
+
+// commandPermissionsAPI is the part of the Discord session that edits command permissions
+type commandPermissionsAPI interface {
+	ApplicationCommands(appID, guildID string, options ...discordgo.RequestOption) ([]*discordgo.ApplicationCommand, error)
+	ApplicationCommandPermissionsEdit(appID, guildID, cmdID string, permissions *discordgo.ApplicationCommandPermissionsList, options ...discordgo.RequestOption) error
+}
+
+// CommandPermissionSync mirrors a guild's required roles to Discord's own command
+// permissions, so members without a required role don't see the role-gated commands.
+// Discord only lets a server manager's OAuth2 token with the
+// applications.commands.permissions.update scope edit them, never a bot token.
+type CommandPermissionSync struct {
+	api    commandPermissionsAPI
+	token  string
+	logger *log.Logger
+}
+
+// NewCommandPermissionSync creates a sync that edits permissions through api with the
+// Bearer token of a server manager
+func NewCommandPermissionSync(api commandPermissionsAPI, token string, logger *log.Logger) *CommandPermissionSync {
+	return &CommandPermissionSync{
+		api:    api,
+		token:  token,
+		logger: logger,
+	}
+}
+
+// Available reports whether the operator gave a token to sync with
+func (c *CommandPermissionSync) Available() bool {
+	return c != nil && c.token != ""
+}
+
+// Sync limits the role-gated commands of appID in a guild to roleIDs. No roles removes
+// the guild's overrides, so the commands' default member permissions apply again.
+func (c *CommandPermissionSync) Sync(appID, guildID string, roleIDs []string) error {
+	if !c.Available() {
+		return fmt.Errorf("no command permissions token is configured")
+	}
+
+	commandIDs, err := c.commandIDs(appID, guildID)
+	if err != nil {
+		return err
+	}
+
+	permissions := &discordgo.ApplicationCommandPermissionsList{Permissions: []*discordgo.ApplicationCommandPermissions{}}
+	if len(roleIDs) > 0 {
+		// The @everyone role shares the guild's ID
+		permissions.Permissions = append(permissions.Permissions, &discordgo.ApplicationCommandPermissions{
+			ID:         guildID,
+			Type:       discordgo.ApplicationCommandPermissionTypeRole,
+			Permission: false,
+		})
+		for _, roleID := range roleIDs {
+			permissions.Permissions = append(permissions.Permissions, &discordgo.ApplicationCommandPermissions{
+				ID:         roleID,
+				Type:       discordgo.ApplicationCommandPermissionTypeRole,
+				Permission: true,
+			})
+		}
+	}
+
+	var errs []error
+	for _, name := range roleGatedCommands {
+		commandID, ok := commandIDs[name]
+		if !ok {
+			continue // Not registered for this guild
+		}
+		if err := c.api.ApplicationCommandPermissionsEdit(appID, guildID, commandID, permissions, discordgo.WithHeader("Authorization", "Bearer "+c.token)); err != nil {
+			errs = append(errs, fmt.Errorf("failed to update permissions of /%s: %w", name, err))
+		}
+	}
+	if len(errs) == 0 {
+		c.logger.Printf("Synced command permissions of guild %s to %d required roles", guildID, len(roleIDs))
+	}
+	return errors.Join(errs...)
+}
+
+// commandIDs maps the names of the application's commands in a guild to their IDs.
+// Commands registered in the guild itself, as with a test guild, take precedence over
+// global ones.
+func (c *CommandPermissionSync) commandIDs(appID, guildID string) (map[string]string, error) {
+	ids := make(map[string]string)
+	for _, scope := range []string{"", guildID} {
+		commands, err := c.api.ApplicationCommands(appID, scope)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list commands: %w", err)
+		}
+		for _, command := range commands {
+			ids[command.Name] = command.ID
+		}
+	}
+	return ids, nil
+}
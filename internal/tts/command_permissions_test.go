@@ -0,0 +1,105 @@
+package tts
+
+import (
+	"errors"
+	"io"
+	"log"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeCommandPermissionsAPI lists fixed commands and records the permissions edited
+type fakeCommandPermissionsAPI struct {
+	commands      map[string][]*discordgo.ApplicationCommand // By guild ID, "" for global commands
+	edited        map[string][]*discordgo.ApplicationCommandPermissions
+	authorization string
+	editErr       error
+}
+
+func (f *fakeCommandPermissionsAPI) ApplicationCommands(appID, guildID string, options ...discordgo.RequestOption) ([]*discordgo.ApplicationCommand, error) {
+	return f.commands[guildID], nil
+}
+
+func (f *fakeCommandPermissionsAPI) ApplicationCommandPermissionsEdit(appID, guildID, cmdID string, permissions *discordgo.ApplicationCommandPermissionsList, options ...discordgo.RequestOption) error {
+	request := &discordgo.RequestConfig{Request: httptest.NewRequest("PUT", "/", nil)}
+	request.Request.Header.Set("Authorization", "Bot bot-token")
+	for _, option := range options {
+		option(request)
+	}
+	f.authorization = request.Request.Header.Get("Authorization")
+
+	if f.editErr != nil {
+		return f.editErr
+	}
+	if f.edited == nil {
+		f.edited = make(map[string][]*discordgo.ApplicationCommandPermissions)
+	}
+	f.edited[cmdID] = permissions.Permissions
+	return nil
+}
+
+func TestCommandPermissions_Definitions(t *testing.T) {
+	system, _ := newTestSystem(t)
+
+	names := make(map[string]bool)
+	for _, handler := range system.GetCommandIntegration().GetCommandHandlers() {
+		definition := handler.Definition()
+		names[definition.Name] = true
+
+		if strings.Contains(definition.Description, "Administrator only") {
+			require.NotNil(t, definition.DefaultMemberPermissions, definition.Name)
+			assert.Equal(t, int64(discordgo.PermissionManageGuild), *definition.DefaultMemberPermissions, definition.Name)
+		} else {
+			assert.Nil(t, definition.DefaultMemberPermissions, "/%s is open to every member by default", definition.Name)
+		}
+	}
+
+	for _, name := range roleGatedCommands {
+		assert.True(t, names[name], "/%s is a registered command", name)
+	}
+}
+
+func TestCommandPermissionSync_Sync(t *testing.T) {
+	api := &fakeCommandPermissionsAPI{commands: map[string][]*discordgo.ApplicationCommand{
+		"": {
+			{ID: "global-config", Name: "darrot-config"},
+			{ID: "global-join", Name: "darrot-join"},
+			{ID: "global-help", Name: "darrot-help"},
+		},
+		"guild123": {{ID: "guild-join", Name: "darrot-join"}},
+	}}
+	sync := NewCommandPermissionSync(api, "oauth-token", log.New(io.Discard, "", 0))
+	require.True(t, sync.Available())
+
+	require.NoError(t, sync.Sync("app123", "guild123", []string{"role1", "role2"}))
+	assert.Equal(t, "Bearer oauth-token", api.authorization, "Discord refuses bot tokens for command permissions")
+	assert.Len(t, api.edited, 2, "only role-gated commands are limited")
+	assert.Equal(t, []*discordgo.ApplicationCommandPermissions{
+		{ID: "guild123", Type: discordgo.ApplicationCommandPermissionTypeRole, Permission: false},
+		{ID: "role1", Type: discordgo.ApplicationCommandPermissionTypeRole, Permission: true},
+		{ID: "role2", Type: discordgo.ApplicationCommandPermissionTypeRole, Permission: true},
+	}, api.edited["global-config"])
+	assert.Equal(t, api.edited["global-config"], api.edited["guild-join"], "commands registered in the guild are used over global ones")
+
+	require.NoError(t, sync.Sync("app123", "guild123", nil))
+	assert.Empty(t, api.edited["global-config"], "no roles removes the overrides")
+
+	api.editErr = errors.New("401 Unauthorized")
+	err := sync.Sync("app123", "guild123", []string{"role1"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "/darrot-config")
+}
+
+func TestCommandPermissionSync_Unavailable(t *testing.T) {
+	var nilSync *CommandPermissionSync
+	assert.False(t, nilSync.Available())
+	assert.Error(t, nilSync.Sync("app123", "guild123", nil))
+
+	sync := NewCommandPermissionSync(&fakeCommandPermissionsAPI{}, "", log.New(io.Discard, "", 0))
+	assert.False(t, sync.Available())
+}
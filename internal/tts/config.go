@@ -2,9 +2,16 @@ package tts
 
 import (
 	"darrot/internal/config"
+	"darrot/internal/events"
 	"errors"
 	"fmt"
+	"maps"
+	"slices"
+	"strings"
 	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
 )
 
 // DefaultTTSConfig returns the default TTS configuration
@@ -37,7 +44,7 @@ func ValidateConfig(config TTSConfig) error {
 		return fmt.Errorf("invalid audio format: %s", config.Format)
 	}
 
-	return nil
+	return validateVoiceOptions(config)
 }
 
 // DefaultGuildTTSConfig returns the default guild TTS configuration
@@ -60,6 +67,106 @@ func ValidateGuildConfig(config GuildTTSConfig) error {
 		return errors.New("max queue size must be between 1 and 100")
 	}
 
+	if config.DailyCharacterBudget < 0 {
+		return errors.New("daily character budget cannot be negative")
+	}
+
+	if config.PremiumVoiceBudget < 0 {
+		return errors.New("premium voice budget cannot be negative")
+	}
+
+	switch config.ContentRetention {
+	case "", ContentRetentionFull, ContentRetentionMetadata:
+	default:
+		return errors.New("content retention must be full or metadata")
+	}
+
+	switch config.LinkMode {
+	case "", LinkModeDomain, LinkModeFull, LinkModeSkip:
+	default:
+		return errors.New("link mode must be domain, full or skip")
+	}
+
+	switch config.CodeBlockMode {
+	case "", CodeBlockModeSummary, CodeBlockModeFull, CodeBlockModeSkip:
+	default:
+		return errors.New("code block mode must be summary, full or skip")
+	}
+
+	switch config.SpoilerMode {
+	case "", SpoilerModeSummary, SpoilerModeFull, SpoilerModeSkip:
+	default:
+		return errors.New("spoiler mode must be summary, full or skip")
+	}
+
+	switch config.TruncationMode {
+	case "", TruncationModeHard, TruncationModeSentence, TruncationModeSplit, TruncationModeAsk:
+	default:
+		return errors.New("truncation mode must be hard, sentence, split or ask")
+	}
+
+	switch config.QueueOrder {
+	case "", QueueOrderFIFO, QueueOrderRoundRobin:
+	default:
+		return errors.New("queue order must be fifo or round-robin")
+	}
+
+	switch config.AudioOutput {
+	case "", AudioOutputDiscord, AudioOutputFile, AudioOutputIcecast, AudioOutputPulse:
+	default:
+		return errors.New("audio output must be discord, file, icecast or pulse")
+	}
+
+	if config.MaxUtteranceLength != 0 && (config.MaxUtteranceLength < MinMessageLength || config.MaxUtteranceLength > MaxMessageLength) {
+		return fmt.Errorf("max utterance length must be between %d and %d", MinMessageLength, MaxMessageLength)
+	}
+
+	if config.MaxSpokenEmoji < 0 || config.MaxSpokenEmoji > MaxSpokenEmojiLimit {
+		return fmt.Errorf("max spoken emoji must be between 1 and %d", MaxSpokenEmojiLimit)
+	}
+
+	if config.UserMessagesPerMinute < 0 || config.UserMessagesPerMinute > MaxUserMessagesPerMinute {
+		return fmt.Errorf("user messages per minute must be between 0 and %d", MaxUserMessagesPerMinute)
+	}
+
+	if config.HoldBackSeconds < 0 || config.HoldBackSeconds > MaxHoldBackSeconds {
+		return fmt.Errorf("hold-back must be between 0 and %d seconds", MaxHoldBackSeconds)
+	}
+
+	if config.MinListeners < 0 || config.MinListeners > MaxMinListeners {
+		return fmt.Errorf("minimum listeners must be between 0 and %d", MaxMinListeners)
+	}
+
+	switch config.CatchUpPolicy {
+	case "", CatchUpRead, CatchUpSkip, CatchUpSummary:
+	default:
+		return errors.New("catch-up policy must be read, skip or summary")
+	}
+
+	if config.MessageAgeCutoff != 0 && (config.MessageAgeCutoff < MinMessageAgeCutoff || config.MessageAgeCutoff > MaxMessageAgeCutoff) {
+		return fmt.Errorf("message age cutoff must be between %d and %d seconds", MinMessageAgeCutoff, MaxMessageAgeCutoff)
+	}
+
+	if config.PauseMillis < 0 || config.PauseMillis > MaxPauseMillis {
+		return fmt.Errorf("pause between messages must be between 0 and %d milliseconds", MaxPauseMillis)
+	}
+
+	if config.AuthorPauseMillis < 0 || config.AuthorPauseMillis > MaxAuthorPauseMillis {
+		return fmt.Errorf("pause between authors must be between 0 and %d milliseconds", MaxAuthorPauseMillis)
+	}
+
+	if config.QuietHoursStart != "" || config.QuietHoursEnd != "" {
+		if _, err := ParseQuietHours(config.QuietHoursStart, config.QuietHoursEnd, config.Timezone); err != nil {
+			return fmt.Errorf("invalid quiet hours: %w", err)
+		}
+	} else if _, err := LoadTimezone(config.Timezone); err != nil {
+		return err
+	}
+
+	if len(config.AllowedBots) > MaxAllowedBots {
+		return fmt.Errorf("at most %d allowed bots are allowed", MaxAllowedBots)
+	}
+
 	return ValidateConfig(config.TTSSettings)
 }
 
@@ -94,6 +201,14 @@ func ValidateUserPreferences(prefs UserTTSPreferences) error {
 		return errors.New("preferred voice is required")
 	}
 
+	if len(prefs.MutedUsers) > MaxMutedUsers {
+		return fmt.Errorf("cannot mute more than %d users", MaxMutedUsers)
+	}
+
+	if len(prefs.ReadChannels) > MaxReadChannels {
+		return fmt.Errorf("cannot limit reading to more than %d channels", MaxReadChannels)
+	}
+
 	return nil
 }
 
@@ -120,12 +235,20 @@ func ValidateChannelPairing(pairing ChannelPairingStorage) error {
 	return nil
 }
 
-// configService implements the ConfigService interface
+// configService implements the ConfigService interface. Guild configurations are cached
+// in memory, since they are read several times for every message. Every change made
+// through the service updates the cache and is published as events.ConfigChanged; changes
+// published by anything else, such as the permission service, drop the guild's cached
+// configuration so it is read from storage again.
 type configService struct {
 	storage      *StorageService
 	defaultTTS   config.TTSConfig
 	guildConfigs map[string]*GuildTTSConfig
+	versions     map[string]uint64 // Bumped whenever a guild's cached configuration changes
+	eventBus     *events.Bus
+	unsubscribe  func()
 	mu           sync.RWMutex
+	profileMu    sync.Mutex // Serializes changes to guild profiles
 }
 
 // NewConfigService creates a new config service
@@ -134,31 +257,78 @@ func NewConfigService(storage *StorageService, defaultTTS config.TTSConfig) Conf
 		storage:      storage,
 		defaultTTS:   defaultTTS,
 		guildConfigs: make(map[string]*GuildTTSConfig),
+		versions:     make(map[string]uint64),
 	}
 }
 
-// GetGuildConfig retrieves the TTS configuration for a guild
+// GetGuildConfig retrieves the TTS configuration for a guild. The configuration returned
+// is the caller's own copy; changes to it take effect once passed to SetGuildConfig.
 func (cs *configService) GetGuildConfig(guildID string) (*GuildTTSConfig, error) {
+	// Check cache first
 	cs.mu.RLock()
-	defer cs.mu.RUnlock()
+	cached, exists := cs.guildConfigs[guildID]
+	cs.mu.RUnlock()
+	if exists {
+		return cloneGuildConfig(cached), nil
+	}
 
-	// Check cache first
-	if config, exists := cs.guildConfigs[guildID]; exists {
-		return config, nil
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	// Another reader may have loaded it in the meantime
+	if cached, exists := cs.guildConfigs[guildID]; exists {
+		return cloneGuildConfig(cached), nil
 	}
 
 	// Try to load from storage
 	config, err := cs.storage.LoadGuildConfig(guildID)
 	if err != nil {
-		// If not found, return default config
+		// Unreadable configurations fall back to the defaults until the next read
 		defaultConfig := cs.createDefaultGuildConfig(guildID)
-		cs.guildConfigs[guildID] = &defaultConfig
 		return &defaultConfig, nil
 	}
 
 	// Cache the loaded config
 	cs.guildConfigs[guildID] = config
-	return config, nil
+	return cloneGuildConfig(config), nil
+}
+
+// ConfigVersion returns the version of a guild's cached configuration, which changes
+// whenever the configuration is saved or dropped from the cache
+func (cs *configService) ConfigVersion(guildID string) uint64 {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	return cs.versions[guildID]
+}
+
+// SetEventBus publishes every change to a guild's configuration and follows the changes
+// others publish
+func (cs *configService) SetEventBus(bus *events.Bus) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	// Discord applications sharing the service share the bus
+	if bus == cs.eventBus {
+		return
+	}
+	if cs.unsubscribe != nil {
+		cs.unsubscribe()
+	}
+	cs.eventBus = bus
+	cs.unsubscribe = events.Subscribe(bus, cs.handleConfigChanged)
+}
+
+// handleConfigChanged drops a guild's cached configuration when it was changed by anything
+// but this service's latest save
+func (cs *configService) handleConfigChanged(e events.ConfigChanged) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	if e.Version != 0 && e.Version == cs.versions[e.GuildID] {
+		return
+	}
+	delete(cs.guildConfigs, e.GuildID)
+	cs.versions[e.GuildID]++
 }
 
 // SetGuildConfig sets the TTS configuration for a guild
@@ -167,17 +337,41 @@ func (cs *configService) SetGuildConfig(guildID string, config *GuildTTSConfig)
 		return err
 	}
 
+	version, err := cs.save(guildID, config)
+	if err != nil {
+		return err
+	}
+
+	cs.eventBus.Publish(events.ConfigChanged{GuildID: guildID, Version: version, ChangedAt: time.Now()})
+	return nil
+}
+
+// save stores a guild's configuration, caches a copy of it and returns its new version
+func (cs *configService) save(guildID string, config *GuildTTSConfig) (uint64, error) {
 	cs.mu.Lock()
 	defer cs.mu.Unlock()
 
 	// Save to storage
 	if err := cs.storage.SaveGuildConfig(*config); err != nil {
-		return err
+		return 0, err
 	}
 
 	// Update cache
-	cs.guildConfigs[guildID] = config
-	return nil
+	cs.guildConfigs[guildID] = cloneGuildConfig(config)
+	cs.versions[guildID]++
+	return cs.versions[guildID], nil
+}
+
+// cloneGuildConfig copies a guild configuration, including its role, prefix and bot lists
+// and its features, so changes to the copy leave the original alone
+func cloneGuildConfig(config *GuildTTSConfig) *GuildTTSConfig {
+	clone := *config
+	clone.RequiredRoles = slices.Clone(config.RequiredRoles)
+	clone.IgnorePrefixes = slices.Clone(config.IgnorePrefixes)
+	clone.SpeakerRoles = slices.Clone(config.SpeakerRoles)
+	clone.AllowedBots = slices.Clone(config.AllowedBots)
+	clone.Features = maps.Clone(config.Features)
+	return &clone
 }
 
 // SetRequiredRoles sets the required roles for bot invitations
@@ -251,6 +445,157 @@ func (cs *configService) GetMaxQueueSize(guildID string) (int, error) {
 	return config.MaxQueueSize, nil
 }
 
+// SetIgnorePrefixes sets the message prefixes that are not read aloud in a guild
+func (cs *configService) SetIgnorePrefixes(guildID string, prefixes []string) error {
+	normalized, err := NormalizeIgnorePrefixes(prefixes)
+	if err != nil {
+		return err
+	}
+
+	config, err := cs.GetGuildConfig(guildID)
+	if err != nil {
+		return err
+	}
+
+	config.IgnorePrefixes = normalized
+	return cs.SetGuildConfig(guildID, config)
+}
+
+// GetIgnorePrefixes gets the message prefixes that are not read aloud in a guild
+func (cs *configService) GetIgnorePrefixes(guildID string) ([]string, error) {
+	config, err := cs.GetGuildConfig(guildID)
+	if err != nil {
+		return nil, err
+	}
+
+	return config.IgnorePrefixes, nil
+}
+
+// NormalizeIgnorePrefixes trims and deduplicates ignore prefixes, rejecting empty
+// prefixes, prefixes containing spaces and lists over the limits
+func NormalizeIgnorePrefixes(prefixes []string) ([]string, error) {
+	normalized := make([]string, 0, len(prefixes))
+	seen := make(map[string]bool, len(prefixes))
+	for _, prefix := range prefixes {
+		prefix = strings.TrimSpace(prefix)
+		if prefix == "" {
+			return nil, errors.New("ignore prefix cannot be empty")
+		}
+		if strings.ContainsFunc(prefix, unicode.IsSpace) {
+			return nil, fmt.Errorf("ignore prefix %q cannot contain spaces", prefix)
+		}
+		if utf8.RuneCountInString(prefix) > MaxIgnorePrefixLength {
+			return nil, fmt.Errorf("ignore prefix %q is longer than %d characters", prefix, MaxIgnorePrefixLength)
+		}
+		if seen[prefix] {
+			continue
+		}
+		seen[prefix] = true
+		normalized = append(normalized, prefix)
+	}
+
+	if len(normalized) > MaxIgnorePrefixes {
+		return nil, fmt.Errorf("at most %d ignore prefixes are allowed", MaxIgnorePrefixes)
+	}
+
+	return normalized, nil
+}
+
+// NormalizeCommandPrefix trims a text command prefix, rejecting empty prefixes, prefixes
+// containing spaces and prefixes over the length limit. CommandPrefixOff is accepted in
+// any case.
+func NormalizeCommandPrefix(prefix string) (string, error) {
+	prefix = strings.TrimSpace(prefix)
+	if strings.EqualFold(prefix, CommandPrefixOff) {
+		return CommandPrefixOff, nil
+	}
+	if prefix == "" {
+		return "", errors.New("command prefix cannot be empty")
+	}
+	if strings.ContainsFunc(prefix, unicode.IsSpace) {
+		return "", fmt.Errorf("command prefix %q cannot contain spaces", prefix)
+	}
+	if utf8.RuneCountInString(prefix) > MaxCommandPrefixLength {
+		return "", fmt.Errorf("command prefix %q is longer than %d characters", prefix, MaxCommandPrefixLength)
+	}
+	return prefix, nil
+}
+
+// CommandPrefixFor returns the text command prefix of a guild configuration, or an empty
+// string when text commands are turned off
+func CommandPrefixFor(config *GuildTTSConfig) string {
+	if config == nil || config.CommandPrefix == "" {
+		return DefaultCommandPrefix
+	}
+	if config.CommandPrefix == CommandPrefixOff {
+		return ""
+	}
+	return config.CommandPrefix
+}
+
+// ExportGuildConfig returns a guild's configuration in the configuration export schema
+func (cs *configService) ExportGuildConfig(guildID string) (*GuildConfigExport, error) {
+	config, err := cs.GetGuildConfig(guildID)
+	if err != nil {
+		return nil, err
+	}
+
+	profiles, err := cs.GetProfiles(guildID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GuildConfigExport{
+		Version:    ConfigExportVersion,
+		ExportedAt: time.Now().UTC(),
+		Config:     *config,
+		Profiles:   profiles,
+	}, nil
+}
+
+// ImportGuildConfig replaces a guild's configuration with the one in an export. Role and
+// channel IDs only exist in the guild they were created in, so required and speaker roles
+// and the audit channel are cleared when the export was taken from another guild. Profiles
+// are replaced when the export has any.
+func (cs *configService) ImportGuildConfig(guildID string, export *GuildConfigExport) error {
+	if err := ValidateConfigExport(export); err != nil {
+		return err
+	}
+
+	config := export.Config
+	if config.GuildID == guildID {
+		config.RequiredRoles = slices.Clone(config.RequiredRoles)
+		config.SpeakerRoles = slices.Clone(config.SpeakerRoles)
+	} else {
+		config.RequiredRoles = []string{}
+		config.SpeakerRoles = nil
+		config.AuditChannelID = ""
+	}
+	config.GuildID = guildID
+	config.IgnorePrefixes = slices.Clone(config.IgnorePrefixes)
+	config.AllowedBots = slices.Clone(config.AllowedBots)
+	config.Features = maps.Clone(config.Features)
+
+	if len(export.Profiles) > 0 {
+		if err := cs.replaceProfiles(guildID, export.Profiles); err != nil {
+			return err
+		}
+	}
+
+	// The active profile must name one of the guild's profiles
+	if config.ActiveProfile != "" {
+		profiles, err := cs.GetProfiles(guildID)
+		if err != nil {
+			return err
+		}
+		if findProfile(profiles, config.ActiveProfile) < 0 {
+			config.ActiveProfile = ""
+		}
+	}
+
+	return cs.SetGuildConfig(guildID, &config)
+}
+
 // ValidateConfig validates a guild TTS configuration
 func (cs *configService) ValidateConfig(config *GuildTTSConfig) error {
 	if config.GuildID == "" {
@@ -9,6 +9,7 @@ import (
 	"github.com/bwmarrin/discordgo"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // MockConfigService implements ConfigService for testing
@@ -90,6 +91,55 @@ func (m *MockConfigService) GetMaxQueueSize(guildID string) (int, error) {
 	return args.Int(0), args.Error(1)
 }
 
+func (m *MockConfigService) SetIgnorePrefixes(guildID string, prefixes []string) error {
+	args := m.Called(guildID, prefixes)
+	return args.Error(0)
+}
+
+func (m *MockConfigService) GetIgnorePrefixes(guildID string) ([]string, error) {
+	args := m.Called(guildID)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockConfigService) GetProfiles(guildID string) ([]GuildProfile, error) {
+	args := m.Called(guildID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]GuildProfile), args.Error(1)
+}
+
+func (m *MockConfigService) SaveProfile(guildID string, profile GuildProfile) error {
+	args := m.Called(guildID, profile)
+	return args.Error(0)
+}
+
+func (m *MockConfigService) DeleteProfile(guildID, name string) error {
+	args := m.Called(guildID, name)
+	return args.Error(0)
+}
+
+func (m *MockConfigService) UseProfile(guildID, name string) (*GuildProfile, error) {
+	args := m.Called(guildID, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*GuildProfile), args.Error(1)
+}
+
+func (m *MockConfigService) ExportGuildConfig(guildID string) (*GuildConfigExport, error) {
+	args := m.Called(guildID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*GuildConfigExport), args.Error(1)
+}
+
+func (m *MockConfigService) ImportGuildConfig(guildID string, export *GuildConfigExport) error {
+	args := m.Called(guildID, export)
+	return args.Error(0)
+}
+
 func (m *MockConfigService) ValidateConfig(config *GuildTTSConfig) error {
 	args := m.Called(config)
 	return args.Error(0)
@@ -141,19 +191,132 @@ func TestConfigCommandHandler_Definition(t *testing.T) {
 
 	assert.Equal(t, "darrot-config", definition.Name)
 	assert.Equal(t, "Configure TTS settings for this server (Administrator only)", definition.Description)
-	assert.Len(t, definition.Options, 4) // roles, voice, queue, show subcommands
+	assert.Len(t, definition.Options, 18) // voice, queue, quota, privacy, announcements, voice-commands, opt-in-notice, features, language, ignore-prefix, content, idle, quiet-hours, output, recording, command-prefix, audit, show subcommands
 
 	// Check subcommands exist
 	subcommandNames := make(map[string]bool)
 	for _, option := range definition.Options {
 		subcommandNames[option.Name] = true
 	}
-	assert.True(t, subcommandNames["roles"])
 	assert.True(t, subcommandNames["voice"])
 	assert.True(t, subcommandNames["queue"])
+	assert.True(t, subcommandNames["quota"])
+	assert.True(t, subcommandNames["privacy"])
+	assert.True(t, subcommandNames["announcements"])
+	assert.True(t, subcommandNames["voice-commands"])
+	assert.True(t, subcommandNames["opt-in-notice"])
+	assert.True(t, subcommandNames["features"])
+	assert.True(t, subcommandNames["idle"])
+	assert.True(t, subcommandNames["quiet-hours"])
+	assert.True(t, subcommandNames["output"])
+	assert.True(t, subcommandNames["recording"])
+	assert.True(t, subcommandNames["audit"])
 	assert.True(t, subcommandNames["show"])
 }
 
+func TestConfigCommandHandler_DescribeSpeakerRoles(t *testing.T) {
+	handler, _, _, _, _ := createTestConfigHandler()
+
+	assert.Equal(t, "None (every opted-in member is read aloud)", handler.describeSpeakerRoles("guild123", nil))
+	assert.Equal(t, "<@&role1>, <@&role2>", handler.describeSpeakerRoles("guild123", []string{"role1", "role2"}))
+}
+
+func TestConfigCommandHandler_DescribeAllowedBots(t *testing.T) {
+	handler, _, _, _, _ := createTestConfigHandler()
+
+	assert.Equal(t, "None", handler.describeAllowedBots("guild123", nil))
+	assert.Equal(t, "<@bot1>, <@bot2>", handler.describeAllowedBots("guild123", []string{"bot1", "bot2"}))
+}
+
+func TestConfigCommandHandler_DescribeIdleTimeouts(t *testing.T) {
+	handler, _, _, _, _ := createTestConfigHandler()
+
+	assert.Equal(t, "• Still-listening announcement: after 5 minute(s) of silence\n• Leave the voice channel: Off\n",
+		handler.describeIdleTimeouts("guild123", IdleTimeoutsFor(nil)))
+	assert.Equal(t, "• Still-listening announcement: Off\n• Leave the voice channel: after 60 minute(s) of silence\n",
+		handler.describeIdleTimeouts("guild123", IdleTimeoutsFor(&GuildTTSConfig{IdleAnnounceMinutes: -1, IdleDisconnectMinutes: 60})))
+}
+
+func TestConfigCommandHandler_DescribeQuietHours(t *testing.T) {
+	handler, _, _, _, _ := createTestConfigHandler()
+
+	assert.Equal(t, "Off", handler.describeQuietHours("guild123", nil))
+	assert.Equal(t, "23:00–08:00 (Europe/Berlin)",
+		handler.describeQuietHours("guild123", &GuildTTSConfig{QuietHoursStart: "23:00", QuietHoursEnd: "08:00", Timezone: "Europe/Berlin"}))
+}
+
+func TestConfigCommandHandler_DescribeAudioOutput(t *testing.T) {
+	handler, _, _, _, _ := createTestConfigHandler()
+
+	assert.Equal(t, "Voice channel only", handler.describeAudioOutput("guild123", ""))
+	assert.Equal(t, "Voice channel and an Icecast stream", handler.describeAudioOutput("guild123", AudioOutputIcecast))
+	assert.Equal(t, "`discord`", handler.describeAvailableOutputs())
+
+	outputs := NewAudioOutputs(nil, log.New(os.Stderr, "", 0))
+	outputs.Register(AudioOutputFile, &recordingSink{})
+	handler.SetAudioOutputs(outputs)
+	assert.Equal(t, "`discord`, `file`", handler.describeAvailableOutputs())
+}
+
+func TestConfigCommandHandler_DescribeRecording(t *testing.T) {
+	handler, _, _, _, _ := createTestConfigHandler()
+
+	assert.Equal(t, "Off", handler.describeRecording("guild123", &GuildTTSConfig{RecordTimestamps: true}))
+	assert.Equal(t, "On", handler.describeRecording("guild123", &GuildTTSConfig{RecordSessions: true}))
+	assert.Equal(t, "On, with timestamps", handler.describeRecording("guild123", &GuildTTSConfig{RecordSessions: true, RecordTimestamps: true}))
+}
+
+func TestConfigCommandHandler_DescribeControlLimits(t *testing.T) {
+	handler, _, _, _, _ := createTestConfigHandler()
+
+	assert.Equal(t, "• Actions per member: 5 per minute\n• Only from the bot's voice channel: Off\n", handler.describeControlLimits("guild123", &GuildTTSConfig{}))
+	assert.Equal(t, "• Actions per member: Unlimited\n• Only from the bot's voice channel: On\n", handler.describeControlLimits("guild123", &GuildTTSConfig{ControlsPerMinute: -1, ControlInVoiceOnly: true}))
+}
+
+func TestConfigCommandHandler_DescribeCommandPrefix(t *testing.T) {
+	handler, _, _, _, _ := createTestConfigHandler()
+
+	assert.Equal(t, "`!darrot`", handler.describeCommandPrefix("guild123", CommandPrefixFor(nil)))
+	assert.Equal(t, "Off", handler.describeCommandPrefix("guild123", CommandPrefixFor(&GuildTTSConfig{CommandPrefix: CommandPrefixOff})))
+}
+
+func TestConfigCommandHandler_DescribeActiveProfile(t *testing.T) {
+	handler, _, _, _, _ := createTestConfigHandler()
+
+	assert.Equal(t, "None", handler.describeActiveProfile("guild123", ""))
+	assert.Equal(t, "movie night", handler.describeActiveProfile("guild123", "movie night"))
+}
+
+func TestConfigCommandHandler_DescribeLengthPolicy(t *testing.T) {
+	handler, _, _, _, _ := createTestConfigHandler()
+
+	assert.Equal(t, "cut after the last sentence within 500 characters", handler.describeLengthPolicy("guild123", LengthPolicyFor(nil)))
+	assert.Equal(t, "split into parts of up to 200 characters", handler.describeLengthPolicy("guild123", LengthPolicyFor(&GuildTTSConfig{TruncationMode: TruncationModeSplit, MaxUtteranceLength: 200})))
+}
+
+func TestConfigCommandHandler_DescribeFeatures(t *testing.T) {
+	handler, _, _, _, _ := createTestConfigHandler()
+	features := NewFeatureFlagService(createTestExportConfigService(t), map[Feature]bool{FeatureAttachmentNarration: true})
+	handler.SetFeatureFlags(features)
+
+	off := false
+	require.NoError(t, features.Set("guild123", FeatureEmojiReading, &off))
+
+	assert.Equal(t, "• Voice auto-pause: **Off** (built-in default)\n"+
+		"• Attachment narration: **On** (bot default)\n"+
+		"• Emoji reading: **Off** (set for this server)", handler.describeFeatures("guild123"))
+}
+
+func TestConfigCommandHandler_DescribeEngineStatus(t *testing.T) {
+	handler, _, _, _, _ := createTestConfigHandler()
+
+	engine := &degradedTTSManagerForRecovery{engineErr: errors.New("no credentials")}
+	assert.Equal(t, "⚠️ Unavailable (degraded mode, retried automatically)", handler.describeEngineStatus("guild123", engine))
+
+	engine.engineErr = nil
+	assert.Equal(t, "✅ Available", handler.describeEngineStatus("guild123", engine))
+}
+
 func TestConfigCommandHandler_ValidatePermissions(t *testing.T) {
 	handler, _, mockPermissionService, _, _ := createTestConfigHandler()
 
@@ -371,46 +534,3 @@ func TestConfigCommandHandler_GuildConfig(t *testing.T) {
 		mockConfigService.AssertExpectations(t)
 	})
 }
-
-func TestParseFloat32(t *testing.T) {
-	tests := []struct {
-		name        string
-		input       string
-		expected    float32
-		expectError bool
-	}{
-		{
-			name:     "valid float",
-			input:    "1.5",
-			expected: 1.5,
-		},
-		{
-			name:     "valid integer",
-			input:    "2",
-			expected: 2.0,
-		},
-		{
-			name:        "invalid string",
-			input:       "not_a_number",
-			expectError: true,
-		},
-		{
-			name:        "empty string",
-			input:       "",
-			expectError: true,
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result, err := parseFloat32(tt.input)
-
-			if tt.expectError {
-				assert.Error(t, err)
-			} else {
-				assert.NoError(t, err)
-				assert.Equal(t, tt.expected, result)
-			}
-		})
-	}
-}
@@ -0,0 +1,69 @@
+package tts
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ConfigExportVersion is the schema version of configuration exports written by this
+// version of the bot. Exports with a newer version are refused.
+const ConfigExportVersion = 1
+
+// MaxConfigImportBytes bounds the size of an uploaded configuration export
+const MaxConfigImportBytes = 256 * 1024
+
+// GuildConfigExport is a guild's complete configuration as written by /darrot-profile export.
+// Config.GuildID names the guild the export was taken from.
+type GuildConfigExport struct {
+	Version    int                 `json:"version"`
+	ExportedAt time.Time           `json:"exported_at"`
+	Config     GuildTTSConfig      `json:"config"`
+	Moderation *ModerationSettings `json:"moderation,omitempty"`
+	Profiles   []GuildProfile      `json:"profiles,omitempty"`
+}
+
+// ParseConfigExport decodes and validates a configuration export. Unknown fields are
+// rejected so that a mistyped setting is reported instead of silently dropped.
+func ParseConfigExport(data []byte) (*GuildConfigExport, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	var export GuildConfigExport
+	if err := decoder.Decode(&export); err != nil {
+		return nil, fmt.Errorf("invalid configuration file: %w", err)
+	}
+
+	if err := ValidateConfigExport(&export); err != nil {
+		return nil, err
+	}
+	return &export, nil
+}
+
+// ValidateConfigExport checks the schema version and every setting of a configuration export
+func ValidateConfigExport(export *GuildConfigExport) error {
+	if export.Version < 1 {
+		return errors.New("configuration file has no schema version")
+	}
+	if export.Version > ConfigExportVersion {
+		return fmt.Errorf("configuration file version %d is newer than the supported version %d", export.Version, ConfigExportVersion)
+	}
+
+	if err := ValidateGuildConfig(export.Config); err != nil {
+		return fmt.Errorf("invalid guild configuration: %w", err)
+	}
+
+	if export.Moderation != nil {
+		if err := ValidateModerationSettings(*export.Moderation); err != nil {
+			return fmt.Errorf("invalid moderation settings: %w", err)
+		}
+	}
+
+	if err := ValidateGuildProfiles(export.Profiles); err != nil {
+		return fmt.Errorf("invalid profiles: %w", err)
+	}
+
+	return nil
+}
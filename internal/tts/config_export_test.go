@@ -0,0 +1,148 @@
+package tts
+
+import (
+	"encoding/json"
+	"testing"
+
+	"darrot/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createTestExportConfigService(t *testing.T) ConfigService {
+	t.Helper()
+
+	storage, err := NewStorageService(t.TempDir())
+	require.NoError(t, err)
+	return NewConfigService(storage, config.TTSConfig{DefaultVoice: DefaultVoice, DefaultSpeed: 1.0, DefaultVolume: 1.0, MaxQueueSize: 10})
+}
+
+// exportedGuild returns an export of guild1 with roles and non-default settings
+func exportedGuild(t *testing.T, configService ConfigService) *GuildConfigExport {
+	t.Helper()
+
+	guildConfig := DefaultGuildTTSConfig("guild1")
+	guildConfig.RequiredRoles = []string{"role1"}
+	guildConfig.SpeakerRoles = []string{"role2"}
+	guildConfig.AuditChannelID = "audit1"
+	guildConfig.MaxQueueSize = 25
+	guildConfig.LinkMode = LinkModeSkip
+	guildConfig.TruncationMode = TruncationModeSplit
+	require.NoError(t, configService.SetGuildConfig("guild1", &guildConfig))
+
+	export, err := configService.ExportGuildConfig("guild1")
+	require.NoError(t, err)
+	return export
+}
+
+func TestConfigService_ExportRoundTrip(t *testing.T) {
+	configService := createTestExportConfigService(t)
+	export := exportedGuild(t, configService)
+	export.Moderation = &ModerationSettings{GuildID: "guild1", Mode: ModerationModeBleep, Blocklist: []string{"darn"}}
+
+	assert.Equal(t, ConfigExportVersion, export.Version)
+	assert.False(t, export.ExportedAt.IsZero())
+
+	data, err := json.Marshal(export)
+	require.NoError(t, err)
+	parsed, err := ParseConfigExport(data)
+	require.NoError(t, err)
+	assert.Equal(t, export.Config.LinkMode, parsed.Config.LinkMode)
+	assert.Equal(t, export.Moderation, parsed.Moderation)
+
+	// Restoring into the same guild keeps its roles
+	reset := DefaultGuildTTSConfig("guild1")
+	require.NoError(t, configService.SetGuildConfig("guild1", &reset))
+	require.NoError(t, configService.ImportGuildConfig("guild1", parsed))
+
+	restored, err := configService.GetGuildConfig("guild1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"role1"}, restored.RequiredRoles)
+	assert.Equal(t, []string{"role2"}, restored.SpeakerRoles)
+	assert.Equal(t, "audit1", restored.AuditChannelID)
+	assert.Equal(t, 25, restored.MaxQueueSize)
+	assert.Equal(t, TruncationModeSplit, restored.TruncationMode)
+}
+
+func TestConfigService_ImportIntoAnotherGuild(t *testing.T) {
+	configService := createTestExportConfigService(t)
+	export := exportedGuild(t, configService)
+
+	require.NoError(t, configService.ImportGuildConfig("guild2", export))
+
+	imported, err := configService.GetGuildConfig("guild2")
+	require.NoError(t, err)
+	assert.Equal(t, "guild2", imported.GuildID)
+	assert.Equal(t, LinkModeSkip, imported.LinkMode)
+	assert.Equal(t, 25, imported.MaxQueueSize)
+
+	// Role and channel IDs belong to the guild the export was taken from
+	assert.Empty(t, imported.RequiredRoles)
+	assert.Empty(t, imported.SpeakerRoles)
+	assert.Empty(t, imported.AuditChannelID)
+
+	source, err := configService.GetGuildConfig("guild1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"role1"}, source.RequiredRoles)
+}
+
+func TestParseConfigExport_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"not JSON", `guild settings`},
+		{"missing version", `{"config": {"guild_id": "guild1", "max_queue_size": 10, "tts_settings": {"speed": 1, "volume": 1, "format": "opus"}}}`},
+		{"newer version", `{"version": 99, "config": {"guild_id": "guild1", "max_queue_size": 10, "tts_settings": {"speed": 1, "volume": 1, "format": "opus"}}}`},
+		{"unknown field", `{"version": 1, "colour": "red", "config": {"guild_id": "guild1", "max_queue_size": 10, "tts_settings": {"speed": 1, "volume": 1, "format": "opus"}}}`},
+		{"invalid setting", `{"version": 1, "config": {"guild_id": "guild1", "max_queue_size": 500, "tts_settings": {"speed": 1, "volume": 1, "format": "opus"}}}`},
+		{"invalid moderation", `{"version": 1, "config": {"guild_id": "guild1", "max_queue_size": 10, "tts_settings": {"speed": 1, "volume": 1, "format": "opus"}}, "moderation": {"mode": "shout"}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			export, err := ParseConfigExport([]byte(tt.data))
+			assert.Error(t, err)
+			assert.Nil(t, export)
+		})
+	}
+
+	export, err := ParseConfigExport([]byte(`{"version": 1, "config": {"guild_id": "guild1", "max_queue_size": 10, "tts_settings": {"speed": 1, "volume": 1, "format": "opus"}}}`))
+	require.NoError(t, err)
+	assert.Nil(t, export.Moderation)
+}
+
+func TestConfigService_ExportIncludesProfiles(t *testing.T) {
+	configService := createTestExportConfigService(t)
+	require.NoError(t, configService.SaveProfile("guild1", GuildProfile{Name: "movie night", TTSSettings: DefaultTTSConfig(), MaxQueueSize: 5}))
+	_, err := configService.UseProfile("guild1", "movie night")
+	require.NoError(t, err)
+
+	export, err := configService.ExportGuildConfig("guild1")
+	require.NoError(t, err)
+	require.Len(t, export.Profiles, 1)
+	assert.Equal(t, "movie night", export.Config.ActiveProfile)
+
+	data, err := json.Marshal(export)
+	require.NoError(t, err)
+	parsed, err := ParseConfigExport(data)
+	require.NoError(t, err)
+
+	require.NoError(t, configService.ImportGuildConfig("guild2", parsed))
+	profiles, err := configService.GetProfiles("guild2")
+	require.NoError(t, err)
+	require.Len(t, profiles, 1)
+	assert.Equal(t, "movie night", profiles[0].Name)
+
+	imported, err := configService.GetGuildConfig("guild2")
+	require.NoError(t, err)
+	assert.Equal(t, "movie night", imported.ActiveProfile)
+
+	// An active profile the guild doesn't have is dropped
+	parsed.Profiles = nil
+	require.NoError(t, configService.ImportGuildConfig("guild3", parsed))
+	imported, err = configService.GetGuildConfig("guild3")
+	require.NoError(t, err)
+	assert.Empty(t, imported.ActiveProfile)
+}
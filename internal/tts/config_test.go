@@ -1,8 +1,15 @@
 package tts
 
 import (
+	"io"
+	"log"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
+
+	"darrot/internal/config"
+	"darrot/internal/events"
 )
 
 func TestDefaultTTSConfig(t *testing.T) {
@@ -117,6 +124,43 @@ func TestValidateConfig(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "pitch too high",
+			config: TTSConfig{
+				Voice:  "en-US-Standard-A",
+				Speed:  1.0,
+				Volume: 1.0,
+				Format: AudioFormatOpus,
+				Pitch:  20.5,
+			},
+			wantErr: true,
+			errMsg:  "pitch must be between -20 and 20 semitones",
+		},
+		{
+			name: "unknown effects profile",
+			config: TTSConfig{
+				Voice:          "en-US-Standard-A",
+				Speed:          1.0,
+				Volume:         1.0,
+				Format:         AudioFormatOpus,
+				EffectsProfile: "stadium-class-device",
+			},
+			wantErr: true,
+			errMsg:  "invalid effects profile: stadium-class-device",
+		},
+		{
+			name: "pitch, effects and style",
+			config: TTSConfig{
+				Voice:          "en-US-Neural2-F",
+				Speed:          1.0,
+				Volume:         1.0,
+				Format:         AudioFormatOpus,
+				Pitch:          -20,
+				EffectsProfile: "headphone-class-device",
+				Style:          "calm",
+			},
+			wantErr: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -209,6 +253,39 @@ func TestValidateGuildConfig(t *testing.T) {
 			wantErr: true,
 			errMsg:  "max queue size must be between 1 and 100",
 		},
+		{
+			name: "spoken emoji limit too large",
+			config: GuildTTSConfig{
+				GuildID:        "123456789",
+				TTSSettings:    DefaultTTSConfig(),
+				MaxQueueSize:   10,
+				MaxSpokenEmoji: 51,
+			},
+			wantErr: true,
+			errMsg:  "max spoken emoji must be between 1 and 50",
+		},
+		{
+			name: "per-user message limit too large",
+			config: GuildTTSConfig{
+				GuildID:               "123456789",
+				TTSSettings:           DefaultTTSConfig(),
+				MaxQueueSize:          10,
+				UserMessagesPerMinute: 61,
+			},
+			wantErr: true,
+			errMsg:  "user messages per minute must be between 0 and 60",
+		},
+		{
+			name: "unknown spoiler mode",
+			config: GuildTTSConfig{
+				GuildID:      "123456789",
+				TTSSettings:  DefaultTTSConfig(),
+				MaxQueueSize: 10,
+				SpoilerMode:  "blur",
+			},
+			wantErr: true,
+			errMsg:  "spoiler mode must be summary, full or skip",
+		},
 		{
 			name: "invalid TTS settings",
 			config: GuildTTSConfig{
@@ -473,3 +550,227 @@ func TestValidateChannelPairing(t *testing.T) {
 		})
 	}
 }
+
+func TestNormalizeIgnorePrefixes(t *testing.T) {
+	tests := []struct {
+		name     string
+		prefixes []string
+		expected []string
+		wantErr  bool
+	}{
+		{name: "trims and deduplicates", prefixes: []string{" ! ", ";;", "!"}, expected: []string{"!", ";;"}},
+		{name: "empty list", prefixes: nil, expected: []string{}},
+		{name: "empty prefix", prefixes: []string{"  "}, wantErr: true},
+		{name: "prefix with space", prefixes: []string{"! !"}, wantErr: true},
+		{name: "prefix too long", prefixes: []string{strings.Repeat("!", MaxIgnorePrefixLength+1)}, wantErr: true},
+		{name: "too many prefixes", prefixes: strings.Split("a b c d e f g h i j k", " "), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := NormalizeIgnorePrefixes(tt.prefixes)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("NormalizeIgnorePrefixes(%q) expected error, got %q", tt.prefixes, result)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NormalizeIgnorePrefixes(%q) unexpected error: %v", tt.prefixes, err)
+			}
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("NormalizeIgnorePrefixes(%q) = %q, want %q", tt.prefixes, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNormalizeCommandPrefix(t *testing.T) {
+	tests := []struct {
+		prefix   string
+		expected string
+		wantErr  bool
+	}{
+		{prefix: " ?? ", expected: "??"},
+		{prefix: "!darrot", expected: "!darrot"},
+		{prefix: "OFF", expected: CommandPrefixOff},
+		{prefix: "  ", wantErr: true},
+		{prefix: "! darrot", wantErr: true},
+		{prefix: strings.Repeat("!", MaxCommandPrefixLength+1), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		result, err := NormalizeCommandPrefix(tt.prefix)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("NormalizeCommandPrefix(%q) expected error, got %q", tt.prefix, result)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("NormalizeCommandPrefix(%q) unexpected error: %v", tt.prefix, err)
+		}
+		if result != tt.expected {
+			t.Errorf("NormalizeCommandPrefix(%q) = %q, want %q", tt.prefix, result, tt.expected)
+		}
+	}
+}
+
+func TestCommandPrefixFor(t *testing.T) {
+	if prefix := CommandPrefixFor(nil); prefix != DefaultCommandPrefix {
+		t.Errorf("CommandPrefixFor(nil) = %q, want %q", prefix, DefaultCommandPrefix)
+	}
+	if prefix := CommandPrefixFor(&GuildTTSConfig{CommandPrefix: "??"}); prefix != "??" {
+		t.Errorf("CommandPrefixFor(??) = %q, want ??", prefix)
+	}
+	if prefix := CommandPrefixFor(&GuildTTSConfig{CommandPrefix: CommandPrefixOff}); prefix != "" {
+		t.Errorf("CommandPrefixFor(off) = %q, want text commands off", prefix)
+	}
+}
+
+func TestConfigService_IgnorePrefixes(t *testing.T) {
+	storage, err := NewStorageService(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	configService := NewConfigService(storage, config.TTSConfig{DefaultVoice: DefaultVoice, DefaultSpeed: 1.0, DefaultVolume: 1.0, MaxQueueSize: 10})
+
+	if err := configService.SetIgnorePrefixes("guild1", []string{"!", " ;; "}); err != nil {
+		t.Fatalf("SetIgnorePrefixes() error = %v", err)
+	}
+	if err := configService.SetIgnorePrefixes("guild1", []string{"bad prefix"}); err == nil {
+		t.Error("Expected invalid prefix to be rejected")
+	}
+
+	// Prefixes survive a restart
+	reloaded := NewConfigService(storage, config.TTSConfig{DefaultVoice: DefaultVoice, DefaultSpeed: 1.0, DefaultVolume: 1.0, MaxQueueSize: 10})
+	prefixes, err := reloaded.GetIgnorePrefixes("guild1")
+	if err != nil {
+		t.Fatalf("GetIgnorePrefixes() error = %v", err)
+	}
+	if !reflect.DeepEqual(prefixes, []string{"!", ";;"}) {
+		t.Errorf("Expected prefixes [! ;;], got %q", prefixes)
+	}
+
+	prefixes, err = reloaded.GetIgnorePrefixes("guild2")
+	if err != nil || len(prefixes) != 0 {
+		t.Errorf("Expected no prefixes for other guilds, got %q (%v)", prefixes, err)
+	}
+}
+
+func TestConfigService_PublishesChanges(t *testing.T) {
+	storage, err := NewStorageService(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	service := NewConfigService(storage, config.TTSConfig{DefaultVoice: DefaultVoice, DefaultSpeed: 1.0, DefaultVolume: 1.0, MaxQueueSize: 10})
+
+	bus := events.New()
+	service.(*configService).SetEventBus(bus)
+	var changed []string
+	events.Subscribe(bus, func(e events.ConfigChanged) { changed = append(changed, e.GuildID) })
+
+	if err := service.SetIgnorePrefixes("guild1", []string{"!"}); err != nil {
+		t.Fatalf("SetIgnorePrefixes() error = %v", err)
+	}
+	if err := service.SetIgnorePrefixes("guild1", []string{"bad prefix"}); err == nil {
+		t.Error("Expected invalid prefix to be rejected")
+	}
+
+	// Rejected changes are not published
+	if !reflect.DeepEqual(changed, []string{"guild1"}) {
+		t.Errorf("Expected one change for guild1, got %q", changed)
+	}
+}
+
+func TestConfigService_CacheIsolation(t *testing.T) {
+	storage, err := NewStorageService(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	service := NewConfigService(storage, config.TTSConfig{DefaultVoice: DefaultVoice, DefaultSpeed: 1.0, DefaultVolume: 1.0, MaxQueueSize: 10})
+
+	guildConfig, err := service.GetGuildConfig("guild1")
+	if err != nil {
+		t.Fatalf("GetGuildConfig() error = %v", err)
+	}
+	guildConfig.SpeakerRoles = append(guildConfig.SpeakerRoles, "role1")
+	guildConfig.MaxQueueSize = 0
+
+	// Changes to a copy are not seen until they are saved, so invalid ones never are
+	reread, _ := service.GetGuildConfig("guild1")
+	if len(reread.SpeakerRoles) != 0 || reread.MaxQueueSize == 0 {
+		t.Errorf("Expected the cached configuration to be unchanged, got %+v", reread)
+	}
+	if err := service.SetGuildConfig("guild1", guildConfig); err == nil {
+		t.Error("Expected the invalid queue size to be rejected")
+	}
+	reread, _ = service.GetGuildConfig("guild1")
+	if reread.MaxQueueSize == 0 {
+		t.Error("Expected the rejected configuration not to be cached")
+	}
+}
+
+func TestConfigService_Version(t *testing.T) {
+	storage, err := NewStorageService(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	service := NewConfigService(storage, config.TTSConfig{DefaultVoice: DefaultVoice, DefaultSpeed: 1.0, DefaultVolume: 1.0, MaxQueueSize: 10}).(*configService)
+	service.SetEventBus(events.New())
+
+	if version := service.ConfigVersion("guild1"); version != 0 {
+		t.Errorf("Expected version 0 before any change, got %d", version)
+	}
+	if err := service.SetMaxQueueSize("guild1", 20); err != nil {
+		t.Fatalf("SetMaxQueueSize() error = %v", err)
+	}
+	if version := service.ConfigVersion("guild1"); version != 1 {
+		t.Errorf("Expected version 1 after a change, got %d", version)
+	}
+
+	// The service's own changes keep the cached configuration
+	if _, exists := service.guildConfigs["guild1"]; !exists {
+		t.Error("Expected the saved configuration to stay cached")
+	}
+	if version := service.ConfigVersion("guild2"); version != 0 {
+		t.Errorf("Expected other guilds to keep version 0, got %d", version)
+	}
+}
+
+func TestConfigService_InvalidatedByOtherWriters(t *testing.T) {
+	storage, err := NewStorageService(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	service := NewConfigService(storage, config.TTSConfig{DefaultVoice: DefaultVoice, DefaultSpeed: 1.0, DefaultVolume: 1.0, MaxQueueSize: 10}).(*configService)
+	bus := events.New()
+	service.SetEventBus(bus)
+	service.SetEventBus(bus) // A second Discord application sharing the service
+
+	if _, err := service.GetGuildConfig("guild1"); err != nil {
+		t.Fatalf("GetGuildConfig() error = %v", err)
+	}
+	before := service.ConfigVersion("guild1")
+
+	// Roles saved by a permission service without the config service
+	permissions := NewPermissionService(NewMockDiscordSession(), storage, log.New(io.Discard, "", 0))
+	permissions.SetEventBus(bus)
+	if err := permissions.SetRequiredRoles("guild1", nil); err != nil {
+		t.Fatalf("SetRequiredRoles() error = %v", err)
+	}
+	stored, _ := storage.LoadGuildConfig("guild1")
+	stored.RequiredRoles = []string{"role1"}
+	if err := storage.SaveGuildConfig(*stored); err != nil {
+		t.Fatalf("SaveGuildConfig() error = %v", err)
+	}
+	bus.Publish(events.ConfigChanged{GuildID: "guild1", ChangedAt: time.Now()})
+
+	if after := service.ConfigVersion("guild1"); after != before+2 {
+		t.Errorf("Expected each outside change to bump the version from %d, got %d", before, after)
+	}
+	roles, _ := service.GetRequiredRoles("guild1")
+	if !reflect.DeepEqual(roles, []string{"role1"}) {
+		t.Errorf("Expected the stored roles after invalidation, got %q", roles)
+	}
+}
@@ -0,0 +1,156 @@
+package tts
+
+import (
+	"log"
+
+	"darrot/internal/commands/options"
+	"darrot/internal/commands/textcmd"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Control guard limits
+const (
+	// DefaultControlsPerMinute is how many /darrot-join and /darrot-control actions a
+	// member may take per minute when the guild did not choose a limit
+	DefaultControlsPerMinute = 5
+
+	// MaxControlsPerMinute is the highest per-member limit a guild can choose
+	MaxControlsPerMinute = 60
+)
+
+// guardedCommands are the commands that move or steer the bot in a voice channel
+var guardedCommands = map[string]bool{
+	"darrot-join":    true,
+	"darrot-control": true,
+}
+
+// ControlsPerMinuteFor returns the per-member limit on control actions of a guild
+// configuration, filling in the default when it is unset. 0 means no limit.
+func ControlsPerMinuteFor(config *GuildTTSConfig) int {
+	switch {
+	case config == nil || config.ControlsPerMinute == 0:
+		return DefaultControlsPerMinute
+	case config.ControlsPerMinute < 0:
+		return 0
+	default:
+		return config.ControlsPerMinute
+	}
+}
+
+// ControlGuard keeps members from trolling a voice channel they are not in: it limits how
+// often each member can run /darrot-join and /darrot-control, and, when the guild asks
+// for it, only lets members in the bot's voice channel steer the bot. Administrators are
+// not limited. It runs in the command dispatch path, before the command's handler.
+type ControlGuard struct {
+	configService ConfigService
+	voiceManager  VoiceManager
+	cooldown      *UserCooldown
+	localizer     *Localizer
+	logger        *log.Logger
+}
+
+// NewControlGuard creates a control guard for the guilds' configurations
+func NewControlGuard(configService ConfigService, voiceManager VoiceManager, logger *log.Logger) *ControlGuard {
+	return &ControlGuard{
+		configService: configService,
+		voiceManager:  voiceManager,
+		cooldown:      NewUserCooldown(),
+		logger:        logger,
+	}
+}
+
+// SetLocalizer sets the localizer refusals are translated with
+func (g *ControlGuard) SetLocalizer(localizer *Localizer) {
+	g.localizer = localizer
+}
+
+// Middleware wraps a command handler so guarded commands that are refused are answered
+// with the reason instead of running
+func (g *ControlGuard) Middleware(next func(*discordgo.Session, *discordgo.InteractionCreate) error) func(*discordgo.Session, *discordgo.InteractionCreate) error {
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+		if reason := g.Check(s, i); reason != "" {
+			return textcmd.Respond(s, i.Interaction, &discordgo.InteractionResponse{
+				Type: discordgo.InteractionResponseChannelMessageWithSource,
+				Data: &discordgo.InteractionResponseData{
+					Content: "❌ " + reason,
+					Flags:   discordgo.MessageFlagsEphemeral,
+				},
+			})
+		}
+		return next(s, i)
+	}
+}
+
+// Check returns why a command may not run, or an empty string when it may. Only allowed
+// actions count towards the member's limit.
+func (g *ControlGuard) Check(s *discordgo.Session, i *discordgo.InteractionCreate) string {
+	if i.Type != discordgo.InteractionApplicationCommand || !guardedCommands[i.ApplicationCommandData().Name] {
+		return ""
+	}
+	if i.GuildID == "" || i.Member == nil || i.Member.User == nil {
+		return "" // The handler refuses commands outside guilds
+	}
+	guildID, userID := i.GuildID, i.Member.User.ID
+	if memberPermissions(s, i)&discordgo.PermissionAdministrator != 0 {
+		return ""
+	}
+
+	config, err := g.configService.GetGuildConfig(guildID)
+	if err != nil {
+		g.logger.Printf("Error getting guild config for guild %s: %v", guildID, err)
+		config = nil // Guard with the defaults
+	}
+
+	if config != nil && config.ControlInVoiceOnly {
+		if channelID := g.targetChannel(i); channelID != "" && userVoiceChannel(s, guildID, userID) != channelID {
+			return g.localizer.T(guildID, "control_guard.not_in_channel", channelID)
+		}
+	}
+
+	limit := ControlsPerMinuteFor(config)
+	if !g.cooldown.Allow(guildID, userID, limit) {
+		g.logger.Printf("Refused /%s from user %s in guild %s: more than %d actions per minute", i.ApplicationCommandData().Name, userID, guildID, limit)
+		return g.localizer.T(guildID, "control_guard.rate_limited", limit)
+	}
+	return ""
+}
+
+// targetChannel returns the voice channel a command steers the bot in: the one /darrot-join
+// asks for, or the one the bot is in. It is empty when the bot is not in one.
+func (g *ControlGuard) targetChannel(i *discordgo.InteractionCreate) string {
+	if i.ApplicationCommandData().Name == "darrot-join" {
+		channelID, _ := options.FromInteraction(i).ChannelID("voice-channel")
+		return channelID
+	}
+
+	if connection, ok := g.voiceManager.GetConnection(i.GuildID); ok {
+		return connection.ChannelID
+	}
+	return ""
+}
+
+// memberPermissions returns the permissions of the member running a command. Text
+// commands carry none, so theirs are worked out from the cached guild.
+func memberPermissions(s *discordgo.Session, i *discordgo.InteractionCreate) int64 {
+	if i.Member.Permissions != 0 || s == nil || s.State == nil {
+		return i.Member.Permissions
+	}
+	permissions, err := s.State.UserChannelPermissions(i.Member.User.ID, i.ChannelID)
+	if err != nil {
+		return 0
+	}
+	return permissions
+}
+
+// userVoiceChannel returns the voice channel a member is in, or an empty string
+func userVoiceChannel(s *discordgo.Session, guildID, userID string) string {
+	if s == nil || s.State == nil {
+		return ""
+	}
+	voiceState, err := s.State.VoiceState(guildID, userID)
+	if err != nil || voiceState == nil {
+		return ""
+	}
+	return voiceState.ChannelID
+}
@@ -0,0 +1,86 @@
+package tts
+
+import (
+	"io"
+	"log"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// guardedInteraction is a slash command run by a member without special permissions
+func guardedInteraction(command, userID string, options ...*discordgo.ApplicationCommandInteractionDataOption) *discordgo.InteractionCreate {
+	return &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+		Type:    discordgo.InteractionApplicationCommand,
+		GuildID: "guild123",
+		Member:  &discordgo.Member{User: &discordgo.User{ID: userID}},
+		Data:    discordgo.ApplicationCommandInteractionData{Name: command, Options: options},
+	}}
+}
+
+func newTestControlGuard(t *testing.T, controlsPerMinute int, voiceOnly bool) (*ControlGuard, *mockVoiceManager) {
+	t.Helper()
+
+	configService := createTestExportConfigService(t)
+	config := DefaultGuildTTSConfig("guild123")
+	config.ControlsPerMinute = controlsPerMinute
+	config.ControlInVoiceOnly = voiceOnly
+	require.NoError(t, configService.SetGuildConfig("guild123", &config))
+	voiceManager := newMockVoiceManager()
+	return NewControlGuard(configService, voiceManager, log.New(io.Discard, "", 0)), voiceManager
+}
+
+func TestControlsPerMinuteFor(t *testing.T) {
+	assert.Equal(t, DefaultControlsPerMinute, ControlsPerMinuteFor(nil))
+	assert.Equal(t, DefaultControlsPerMinute, ControlsPerMinuteFor(&GuildTTSConfig{}))
+	assert.Equal(t, 10, ControlsPerMinuteFor(&GuildTTSConfig{ControlsPerMinute: 10}))
+	assert.Zero(t, ControlsPerMinuteFor(&GuildTTSConfig{ControlsPerMinute: -1}))
+}
+
+func TestControlGuard_RateLimit(t *testing.T) {
+	guard, _ := newTestControlGuard(t, 0, false)
+
+	for range DefaultControlsPerMinute {
+		assert.Empty(t, guard.Check(nil, guardedInteraction("darrot-control", "user1")))
+	}
+	assert.Contains(t, guard.Check(nil, guardedInteraction("darrot-join", "user1")), "5 times per minute", "join and control share the limit")
+
+	assert.Empty(t, guard.Check(nil, guardedInteraction("darrot-control", "user2")), "every member has their own limit")
+	assert.Empty(t, guard.Check(nil, guardedInteraction("darrot-optin", "user1")), "other commands are not limited")
+
+	admin := guardedInteraction("darrot-control", "user1")
+	admin.Member.Permissions = discordgo.PermissionAdministrator
+	assert.Empty(t, guard.Check(nil, admin), "administrators are not limited")
+}
+
+func TestControlGuard_Unlimited(t *testing.T) {
+	guard, _ := newTestControlGuard(t, -1, false)
+
+	for range 3 * DefaultControlsPerMinute {
+		assert.Empty(t, guard.Check(nil, guardedInteraction("darrot-control", "user1")))
+	}
+}
+
+func TestControlGuard_VoiceChannelOnly(t *testing.T) {
+	guard, voiceManager := newTestControlGuard(t, -1, true)
+
+	session := &discordgo.Session{State: discordgo.NewState()}
+	require.NoError(t, session.State.GuildAdd(&discordgo.Guild{ID: "guild123", VoiceStates: []*discordgo.VoiceState{
+		{GuildID: "guild123", UserID: "listener", ChannelID: "voice1"},
+		{GuildID: "guild123", UserID: "elsewhere", ChannelID: "voice2"},
+	}}))
+
+	joinVoice1 := &discordgo.ApplicationCommandInteractionDataOption{Name: "voice-channel", Type: discordgo.ApplicationCommandOptionChannel, Value: "voice1"}
+	assert.Empty(t, guard.Check(session, guardedInteraction("darrot-join", "listener", joinVoice1)))
+	assert.Contains(t, guard.Check(session, guardedInteraction("darrot-join", "elsewhere", joinVoice1)), "<#voice1>")
+	assert.Contains(t, guard.Check(session, guardedInteraction("darrot-join", "remote", joinVoice1)), "<#voice1>")
+
+	assert.Empty(t, guard.Check(session, guardedInteraction("darrot-control", "remote")), "there is nothing to steer while the bot is not in a voice channel")
+
+	_, err := voiceManager.JoinChannel("guild123", "voice1")
+	require.NoError(t, err)
+	assert.Empty(t, guard.Check(session, guardedInteraction("darrot-control", "listener")))
+	assert.Contains(t, guard.Check(session, guardedInteraction("darrot-control", "elsewhere")), "<#voice1>")
+}
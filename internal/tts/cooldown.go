@@ -0,0 +1,83 @@
+package tts
+
+import (
+	"sync"
+	"time"
+)
+
+// cooldownWindow is the period the per-user message limit counts over
+const cooldownWindow = time.Minute
+
+// UserCooldown limits how many messages each user can have read per minute in a guild,
+// so a single chatty user cannot fill the queue. It counts over a sliding window.
+type UserCooldown struct {
+	mu        sync.Mutex
+	sent      map[string][]time.Time // Times of accepted messages per guild and user
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// NewUserCooldown creates an empty per-user cooldown
+func NewUserCooldown() *UserCooldown {
+	return &UserCooldown{
+		sent: make(map[string][]time.Time),
+		now:  time.Now,
+	}
+}
+
+// UserMessagesPerMinuteFor returns the per-user message limit of a guild configuration, 0
+// meaning no limit
+func UserMessagesPerMinuteFor(config *GuildTTSConfig) int {
+	if config == nil || config.UserMessagesPerMinute < 0 {
+		return 0
+	}
+	return config.UserMessagesPerMinute
+}
+
+// Allow reports whether the user may have another message read in the guild, counting
+// it if so. A limit of 0 or less allows every message.
+func (c *UserCooldown) Allow(guildID, userID string, limit int) bool {
+	if limit <= 0 {
+		return true
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	cutoff := now.Add(-cooldownWindow)
+	c.sweep(now, cutoff)
+
+	key := guildID + ":" + userID
+	recent := pruneBefore(c.sent[key], cutoff)
+	if len(recent) >= limit {
+		c.sent[key] = recent
+		return false
+	}
+	c.sent[key] = append(recent, now)
+	return true
+}
+
+// sweep forgets users who sent nothing within the window, at most once per window
+func (c *UserCooldown) sweep(now, cutoff time.Time) {
+	if now.Sub(c.lastSweep) < cooldownWindow {
+		return
+	}
+	c.lastSweep = now
+
+	for key, times := range c.sent {
+		if recent := pruneBefore(times, cutoff); len(recent) == 0 {
+			delete(c.sent, key)
+		} else {
+			c.sent[key] = recent
+		}
+	}
+}
+
+// pruneBefore drops the times up to cutoff from a list in ascending order
+func pruneBefore(times []time.Time, cutoff time.Time) []time.Time {
+	for len(times) > 0 && !times[0].After(cutoff) {
+		times = times[1:]
+	}
+	return times
+}
@@ -0,0 +1,57 @@
+package tts
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserCooldown_Allow(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	cooldown := NewUserCooldown()
+	cooldown.now = func() time.Time { return now }
+
+	assert.True(t, cooldown.Allow("guild1", "user1", 2))
+	now = now.Add(10 * time.Second)
+	assert.True(t, cooldown.Allow("guild1", "user1", 2))
+	assert.False(t, cooldown.Allow("guild1", "user1", 2), "third message within a minute is over the limit")
+
+	// Other users and guilds have their own counts
+	assert.True(t, cooldown.Allow("guild1", "user2", 2))
+	assert.True(t, cooldown.Allow("guild2", "user1", 2))
+
+	// The window slides: the first message expires a minute after it was sent
+	now = now.Add(50 * time.Second)
+	assert.True(t, cooldown.Allow("guild1", "user1", 2))
+	assert.False(t, cooldown.Allow("guild1", "user1", 2))
+}
+
+func TestUserCooldown_Unlimited(t *testing.T) {
+	cooldown := NewUserCooldown()
+	for range 100 {
+		assert.True(t, cooldown.Allow("guild1", "user1", 0))
+	}
+	assert.Empty(t, cooldown.sent, "unlimited guilds are not tracked")
+}
+
+func TestUserCooldown_SweepsIdleUsers(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	cooldown := NewUserCooldown()
+	cooldown.now = func() time.Time { return now }
+
+	cooldown.Allow("guild1", "user1", 5)
+	cooldown.Allow("guild1", "user2", 5)
+	assert.Len(t, cooldown.sent, 2)
+
+	now = now.Add(2 * time.Minute)
+	cooldown.Allow("guild1", "user3", 5)
+	assert.Len(t, cooldown.sent, 1)
+	assert.Contains(t, cooldown.sent, "guild1:user3")
+}
+
+func TestUserMessagesPerMinuteFor(t *testing.T) {
+	assert.Equal(t, 0, UserMessagesPerMinuteFor(nil))
+	assert.Equal(t, 0, UserMessagesPerMinuteFor(&GuildTTSConfig{}))
+	assert.Equal(t, 5, UserMessagesPerMinuteFor(&GuildTTSConfig{UserMessagesPerMinute: 5}))
+}
@@ -0,0 +1,307 @@
+package tts
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"google.golang.org/api/option"
+	secretmanager "google.golang.org/api/secretmanager/v1"
+)
+
+// How often the credentials are checked for rotation. Files are cheap to read; Secret
+// Manager accesses are billed, so secrets are checked less often.
+const (
+	CredentialFileCheckInterval   = 30 * time.Second
+	CredentialSecretCheckInterval = 10 * time.Minute
+)
+
+// CredentialExpiryWarning is how long before the credentials expire the health check
+// starts warning about it
+const CredentialExpiryWarning = 7 * 24 * time.Hour
+
+// credentialLoadTimeout bounds reading the credentials and looking up their expiry
+const credentialLoadTimeout = 30 * time.Second
+
+// CredentialSource says where the Google Cloud credentials are read from: a Secret
+// Manager secret, a JSON file that may be replaced when the credentials rotate, such as
+// a mounted Kubernetes secret, or Application Default Credentials when both are empty
+type CredentialSource struct {
+	Path   string
+	Secret string // Secret Manager secret or secret version, such as projects/my-project/secrets/tts-key
+
+	// Options for the Secret Manager client, used by tests
+	secretOptions []option.ClientOption
+}
+
+// IsSet reports whether the credentials are read from a file or secret rather than from
+// Application Default Credentials
+func (c CredentialSource) IsSet() bool {
+	return c.Path != "" || c.Secret != ""
+}
+
+// String describes where the credentials come from
+func (c CredentialSource) String() string {
+	switch {
+	case c.Secret != "":
+		return "secret " + c.SecretVersion()
+	case c.Path != "":
+		return "file " + c.Path
+	default:
+		return "Application Default Credentials"
+	}
+}
+
+// SecretVersion returns the secret version the credentials are read from. A secret
+// without a version reads its latest version, so rotating the credentials only takes
+// adding a version.
+func (c CredentialSource) SecretVersion() string {
+	if c.Secret == "" || strings.Contains(c.Secret, "/versions/") {
+		return c.Secret
+	}
+	return c.Secret + "/versions/latest"
+}
+
+// checkInterval returns how often the credentials are checked for rotation, 0 when they
+// come from Application Default Credentials and are not watched
+func (c CredentialSource) checkInterval() time.Duration {
+	switch {
+	case c.Secret != "":
+		return CredentialSecretCheckInterval
+	case c.Path != "":
+		return CredentialFileCheckInterval
+	default:
+		return 0
+	}
+}
+
+// Load reads the credentials JSON, or returns nil when Application Default Credentials
+// are used
+func (c CredentialSource) Load(ctx context.Context) ([]byte, error) {
+	switch {
+	case c.Secret != "":
+		service, err := secretmanager.NewService(ctx, c.secretOptions...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Secret Manager client: %w", err)
+		}
+		response, err := service.Projects.Secrets.Versions.Access(c.SecretVersion()).Context(ctx).Do()
+		if err != nil {
+			return nil, fmt.Errorf("failed to access secret %s: %w", c.SecretVersion(), err)
+		}
+		if response.Payload == nil {
+			return nil, fmt.Errorf("secret %s has no payload", c.SecretVersion())
+		}
+		data, err := base64.StdEncoding.DecodeString(response.Payload.Data)
+		if err != nil {
+			return nil, fmt.Errorf("secret %s has an invalid payload: %w", c.SecretVersion(), err)
+		}
+		return data, nil
+	case c.Path != "":
+		data, err := os.ReadFile(c.Path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read credentials file: %w", err)
+		}
+		return data, nil
+	default:
+		return nil, nil
+	}
+}
+
+// credentialFingerprint identifies a version of the credentials, so that rotation can be
+// noticed without keeping the key itself around
+func credentialFingerprint(credentials []byte) [sha256.Size]byte {
+	return sha256.Sum256(bytes.TrimSpace(credentials))
+}
+
+// CredentialExpiry returns when a service account key stops working, looked up from the
+// public certificate Google publishes for it. Keys that never expire report the year
+// 9999. Other credential types have no expiry of their own and return the zero time.
+func CredentialExpiry(ctx context.Context, credentials []byte) (time.Time, error) {
+	var key struct {
+		Type         string `json:"type"`
+		PrivateKeyID string `json:"private_key_id"`
+		CertURL      string `json:"client_x509_cert_url"`
+	}
+	if err := json.Unmarshal(credentials, &key); err != nil {
+		return time.Time{}, fmt.Errorf("credentials are not valid JSON: %w", err)
+	}
+	if key.Type != "service_account" || key.PrivateKeyID == "" || key.CertURL == "" {
+		return time.Time{}, nil
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, key.CertURL, nil)
+	if err != nil {
+		return time.Time{}, err
+	}
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to fetch the key's certificate: %w", err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return time.Time{}, fmt.Errorf("failed to fetch the key's certificate: %s", response.Status)
+	}
+
+	var certificates map[string]string
+	if err := json.NewDecoder(io.LimitReader(response.Body, 1<<20)).Decode(&certificates); err != nil {
+		return time.Time{}, fmt.Errorf("invalid certificate list: %w", err)
+	}
+	certificatePEM, ok := certificates[key.PrivateKeyID]
+	if !ok {
+		// Deleted keys are no longer published
+		return time.Time{}, fmt.Errorf("key %s is not an active key of the service account", key.PrivateKeyID)
+	}
+	block, _ := pem.Decode([]byte(certificatePEM))
+	if block == nil {
+		return time.Time{}, errors.New("invalid certificate of the key")
+	}
+	certificate, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid certificate of the key: %w", err)
+	}
+	return certificate.NotAfter, nil
+}
+
+// loadedCredentials is a version of the credentials the manager's client was built with
+type loadedCredentials struct {
+	fingerprint [sha256.Size]byte
+	expiry      time.Time // Zero when unknown
+}
+
+// StartCredentialWatch checks the credentials for rotation in the background and
+// rebuilds the TTS client when they change. Credentials from Application Default
+// Credentials and local mock endpoints are not watched.
+func (g *GoogleTTSManager) StartCredentialWatch() error {
+	interval := g.credentials.checkInterval()
+	if interval == 0 || strings.HasPrefix(g.endpoint, "http://") {
+		return nil
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.stopWatch != nil {
+		return nil
+	}
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	g.stopWatch, g.watchDone = stop, done
+
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				err := CatchPanic(nil, "TTS credential watch", "", func() error {
+					_, err := g.ReloadCredentials()
+					return err
+				})
+				if err != nil {
+					log.Printf("Failed to reload TTS credentials from %s: %v", g.credentials, err)
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// StopCredentialWatch stops checking the credentials for rotation
+func (g *GoogleTTSManager) StopCredentialWatch() {
+	g.mu.Lock()
+	stop, done := g.stopWatch, g.watchDone
+	g.stopWatch, g.watchDone = nil, nil
+	g.mu.Unlock()
+
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+}
+
+// ReloadCredentials reads the credentials again and, when they changed, builds a new
+// TTS client with them and checks it before replacing the old one. It reports whether
+// the client was replaced. Requests already under way finish on the old client.
+func (g *GoogleTTSManager) ReloadCredentials() (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), credentialLoadTimeout)
+	defer cancel()
+
+	credentials, err := g.credentials.Load(ctx)
+	if err != nil {
+		return false, err
+	}
+	if credentials == nil {
+		return false, nil
+	}
+
+	fingerprint := credentialFingerprint(credentials)
+	g.clientMu.RLock()
+	unchanged := g.client != nil && g.loaded != nil && g.loaded.fingerprint == fingerprint
+	g.clientMu.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+
+	client, err := connectTTSClient(credentials, g.endpoint)
+	if err != nil {
+		return false, fmt.Errorf("the TTS API rejected the new credentials, keeping the old ones: %w", err)
+	}
+	loaded := g.inspectCredentials(ctx, credentials)
+
+	g.clientMu.Lock()
+	old := g.client
+	g.client = client
+	g.clientErr = nil
+	g.loaded = loaded
+	g.clientMu.Unlock()
+
+	if old != nil {
+		// Give requests on the old client time to finish before closing it
+		time.AfterFunc(g.closeDelay(), func() { old.Close() })
+		log.Printf("TTS credentials from %s changed, rebuilt the TTS client", g.credentials)
+	}
+	return true, nil
+}
+
+// CredentialExpiry returns when the credentials in use stop working, or the zero time
+// when that is unknown
+func (g *GoogleTTSManager) CredentialExpiry() time.Time {
+	g.clientMu.RLock()
+	defer g.clientMu.RUnlock()
+	if g.loaded == nil {
+		return time.Time{}
+	}
+	return g.loaded.expiry
+}
+
+// inspectCredentials fingerprints the credentials and looks up their expiry
+func (g *GoogleTTSManager) inspectCredentials(ctx context.Context, credentials []byte) *loadedCredentials {
+	loaded := &loadedCredentials{fingerprint: credentialFingerprint(credentials)}
+	expiry, err := CredentialExpiry(ctx, credentials)
+	if err != nil {
+		log.Printf("Failed to look up when the TTS credentials from %s expire: %v", g.credentials, err)
+	}
+	loaded.expiry = expiry
+	return loaded
+}
+
+// closeDelay returns how long a replaced client is kept open for the requests using it
+func (g *GoogleTTSManager) closeDelay() time.Duration {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return time.Duration(g.retries.maxAttempts)*(g.synthesisTimeout+g.retries.maxDelay) + time.Second
+}
@@ -0,0 +1,143 @@
+package tts
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"mock-tts/mocktts"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/option"
+)
+
+func TestCredentialSource_SecretVersion(t *testing.T) {
+	assert.Equal(t, "projects/p/secrets/tts/versions/latest", CredentialSource{Secret: "projects/p/secrets/tts"}.SecretVersion())
+	assert.Equal(t, "projects/p/secrets/tts/versions/3", CredentialSource{Secret: "projects/p/secrets/tts/versions/3"}.SecretVersion())
+	assert.False(t, CredentialSource{}.IsSet())
+}
+
+func TestCredentialSource_Load(t *testing.T) {
+	// Application Default Credentials are not read by the bot
+	credentials, err := CredentialSource{}.Load(context.Background())
+	require.NoError(t, err)
+	assert.Nil(t, credentials)
+
+	path := filepath.Join(t.TempDir(), "credentials.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"type":"authorized_user"}`), 0o600))
+	credentials, err = CredentialSource{Path: path}.Load(context.Background())
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type":"authorized_user"}`, string(credentials))
+
+	var accessed string
+	secretServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		accessed = r.URL.Path
+		json.NewEncoder(w).Encode(map[string]any{
+			"name":    "projects/p/secrets/tts/versions/7",
+			"payload": map[string]string{"data": base64.StdEncoding.EncodeToString([]byte(`{"type":"service_account"}`))},
+		})
+	}))
+	defer secretServer.Close()
+
+	source := CredentialSource{
+		Secret:        "projects/p/secrets/tts",
+		secretOptions: []option.ClientOption{option.WithEndpoint(secretServer.URL + "/"), option.WithoutAuthentication()},
+	}
+	credentials, err = source.Load(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "/v1/projects/p/secrets/tts/versions/latest:access", accessed)
+	assert.JSONEq(t, `{"type":"service_account"}`, string(credentials))
+}
+
+func TestCredentialExpiry(t *testing.T) {
+	notAfter := time.Now().Add(72 * time.Hour).Truncate(time.Second).UTC()
+	certServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{"key1": selfSignedCertificate(t, notAfter)})
+	}))
+	defer certServer.Close()
+
+	serviceAccount := func(keyID string) []byte {
+		return []byte(fmt.Sprintf(`{"type":"service_account","private_key_id":%q,"client_x509_cert_url":%q}`, keyID, certServer.URL))
+	}
+
+	expiry, err := CredentialExpiry(context.Background(), serviceAccount("key1"))
+	require.NoError(t, err)
+	assert.True(t, expiry.Equal(notAfter), "expected %s, got %s", notAfter, expiry)
+
+	// Deleted keys are no longer published
+	_, err = CredentialExpiry(context.Background(), serviceAccount("key2"))
+	assert.Error(t, err)
+
+	// Other credential types have no expiry of their own
+	expiry, err = CredentialExpiry(context.Background(), []byte(`{"type":"authorized_user"}`))
+	require.NoError(t, err)
+	assert.True(t, expiry.IsZero())
+}
+
+func TestGoogleTTSManager_ReloadCredentials(t *testing.T) {
+	httpServer := httptest.NewServer(mocktts.NewServer().Handler())
+	defer httpServer.Close()
+
+	path := filepath.Join(t.TempDir(), "credentials.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"type":"authorized_user","refresh_token":"one"}`), 0o600))
+
+	manager, err := NewGoogleTTSManagerWithCredentials(&MockMessageQueue{}, CredentialSource{Path: path}, httpServer.URL)
+	require.NoError(t, err)
+	t.Cleanup(func() { manager.Close() })
+	client := manager.ttsClient()
+
+	// Unchanged credentials keep the client
+	reloaded, err := manager.ReloadCredentials()
+	require.NoError(t, err)
+	assert.False(t, reloaded)
+	assert.Same(t, client, manager.ttsClient())
+
+	// Rotated credentials rebuild it
+	require.NoError(t, os.WriteFile(path, []byte(`{"type":"authorized_user","refresh_token":"two"}`), 0o600))
+	reloaded, err = manager.ReloadCredentials()
+	require.NoError(t, err)
+	assert.True(t, reloaded)
+	assert.NotSame(t, client, manager.ttsClient())
+
+	_, err = manager.ConvertToSpeech("hello", "", TTSConfig{Speed: 1.0, Volume: 1.0, Format: AudioFormatPCM})
+	assert.NoError(t, err)
+
+	// A missing file keeps the client that works
+	require.NoError(t, os.Remove(path))
+	_, err = manager.ReloadCredentials()
+	assert.Error(t, err)
+	assert.NoError(t, manager.EngineError())
+}
+
+// selfSignedCertificate returns a PEM certificate valid until notAfter, like the ones
+// Google publishes for service account keys
+func selfSignedCertificate(t *testing.T, notAfter time.Time) string {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "tts@example.iam.gserviceaccount.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
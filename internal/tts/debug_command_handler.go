@@ -0,0 +1,169 @@
+package tts
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"darrot/internal/commands/textcmd"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Accent colors of the /darrot-debug embed
+const (
+	debugEmbedColorHealthy  = 0x57F287
+	debugEmbedColorDegraded = 0xFEE75C
+)
+
+// DebugCommandHandler handles the administrator voice connection diagnostics command
+type DebugCommandHandler struct {
+	voiceManager      VoiceManager
+	permissionService PermissionService
+	localizer         *Localizer
+	logger            *log.Logger
+}
+
+// NewDebugCommandHandler creates a new debug command handler
+func NewDebugCommandHandler(
+	voiceManager VoiceManager,
+	permissionService PermissionService,
+	logger *log.Logger,
+) *DebugCommandHandler {
+	return &DebugCommandHandler{
+		voiceManager:      voiceManager,
+		permissionService: permissionService,
+		logger:            logger,
+	}
+}
+
+// SetLocalizer sets the localizer used to translate responses
+func (h *DebugCommandHandler) SetLocalizer(localizer *Localizer) {
+	h.localizer = localizer
+}
+
+// Definition returns the Discord slash command definition for the debug command
+func (h *DebugCommandHandler) Definition() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:                     "darrot-debug",
+		Description:              "Show voice connection diagnostics for this server (Administrator only)",
+		DefaultMemberPermissions: adminCommandPermissions(),
+	}
+}
+
+// Handle processes the debug command interaction
+func (h *DebugCommandHandler) Handle(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	// Validate guild context
+	if i.GuildID == "" {
+		return h.respondError(s, i, h.localizer.T(i.GuildID, "common.guild_only"))
+	}
+
+	userID := i.Member.User.ID
+	guildID := i.GuildID
+
+	// Validate administrator permissions
+	if err := h.ValidatePermissions(userID, guildID); err != nil {
+		return h.respondError(s, i, h.localizer.T(guildID, "common.permission_denied", err))
+	}
+
+	connection, exists := h.voiceManager.GetConnection(guildID)
+	if !exists || connection == nil {
+		return h.respondError(s, i, h.localizer.T(guildID, "common.not_in_voice"))
+	}
+
+	reporter, ok := h.voiceManager.(VoiceStatsReporter)
+	if !ok {
+		return h.respondError(s, i, h.localizer.T(guildID, "debug.unavailable"))
+	}
+	stats, ok := reporter.VoiceStats(guildID)
+	if !ok {
+		return h.respondError(s, i, h.localizer.T(guildID, "common.not_in_voice"))
+	}
+
+	return textcmd.Respond(s, i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{h.buildEmbed(guildID, connection, stats, time.Now())},
+			Flags:  discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
+
+// buildEmbed renders the diagnostics of the guild's voice connection as an embed
+func (h *DebugCommandHandler) buildEmbed(guildID string, connection *VoiceConnection, stats *VoiceConnectionStats, now time.Time) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Title:       h.localizer.T(guildID, "debug.title"),
+		Description: h.localizer.T(guildID, "debug.channel", connection.ChannelID),
+		Color:       debugEmbedColorHealthy,
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: h.localizer.T(guildID, "debug.status"), Value: h.describeStatus(guildID, connection, stats, now)},
+			{Name: h.localizer.T(guildID, "debug.frames_sent"), Value: fmt.Sprintf("%d", stats.FramesSent), Inline: true},
+			{Name: h.localizer.T(guildID, "debug.frames_dropped"), Value: h.localizer.T(guildID, "debug.loss", stats.FramesDropped, stats.Loss()*100), Inline: true},
+			{Name: h.localizer.T(guildID, "debug.frames_late"), Value: fmt.Sprintf("%d", stats.FramesLate), Inline: true},
+			{Name: h.localizer.T(guildID, "debug.jitter"), Value: h.localizer.T(guildID, "debug.milliseconds", durationMilliseconds(stats.Jitter)), Inline: true},
+			{Name: h.localizer.T(guildID, "debug.heartbeat"), Value: h.localizer.T(guildID, "debug.milliseconds", durationMilliseconds(stats.HeartbeatLatency)), Inline: true},
+		},
+	}
+
+	if stats.Degraded(now) != nil {
+		embed.Color = debugEmbedColorDegraded
+	}
+	if stats.LastAudio.IsZero() {
+		embed.Footer = &discordgo.MessageEmbedFooter{Text: h.localizer.T(guildID, "debug.no_audio")}
+	} else {
+		embed.Footer = &discordgo.MessageEmbedFooter{Text: h.localizer.T(guildID, "debug.last_audio", stats.LastAudio.UTC().Format("15:04:05"))}
+	}
+
+	return embed
+}
+
+// describeStatus summarizes the state of the connection and why it is degraded
+func (h *DebugCommandHandler) describeStatus(guildID string, connection *VoiceConnection, stats *VoiceConnectionStats, now time.Time) string {
+	switch {
+	case connection.Reconnecting:
+		return h.localizer.T(guildID, "debug.status_reconnecting")
+	case connection.IsPaused:
+		return h.localizer.T(guildID, "debug.status_paused")
+	}
+
+	if err := stats.Degraded(now); err != nil {
+		return h.localizer.T(guildID, "debug.status_degraded", err)
+	}
+	return h.localizer.T(guildID, "debug.status_healthy")
+}
+
+// durationMilliseconds returns a duration in fractional milliseconds
+func durationMilliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// ValidatePermissions validates that the user has administrator permissions
+func (h *DebugCommandHandler) ValidatePermissions(userID, guildID string) error {
+	canControl, err := h.permissionService.CanControlBot(userID, guildID)
+	if err != nil {
+		return fmt.Errorf("failed to check permissions: %w", err)
+	}
+
+	if !canControl {
+		return withKind(ErrPermission, fmt.Errorf("you must have administrator permissions to view voice diagnostics"))
+	}
+
+	return nil
+}
+
+// ValidateChannelAccess is not needed for debug commands but required by interface
+func (h *DebugCommandHandler) ValidateChannelAccess(userID, channelID string) error {
+	return nil // Not applicable for debug commands
+}
+
+// Helper methods for response handling
+
+func (h *DebugCommandHandler) respondError(s *discordgo.Session, i *discordgo.InteractionCreate, message string) error {
+	return textcmd.Respond(s, i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: "❌ " + message,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
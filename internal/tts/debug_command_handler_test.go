@@ -0,0 +1,88 @@
+package tts
+
+import (
+	"errors"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createTestDebugHandler(t *testing.T) (*DebugCommandHandler, *MockPermissionService) {
+	mockPermissionService := &MockPermissionService{}
+	logger := log.New(os.Stdout, "[TEST] ", log.LstdFlags)
+
+	return NewDebugCommandHandler(newMockVoiceManager(), mockPermissionService, logger), mockPermissionService
+}
+
+func TestDebugCommandHandler_Definition(t *testing.T) {
+	handler, _ := createTestDebugHandler(t)
+
+	definition := handler.Definition()
+
+	assert.Equal(t, "darrot-debug", definition.Name)
+	assert.Contains(t, definition.Description, "Administrator only")
+	assert.Empty(t, definition.Options)
+}
+
+func TestDebugCommandHandler_ValidatePermissions(t *testing.T) {
+	handler, mockPermissionService := createTestDebugHandler(t)
+
+	mockPermissionService.On("CanControlBot", "admin", "guild123").Return(true, nil)
+	mockPermissionService.On("CanControlBot", "user", "guild123").Return(false, nil)
+	mockPermissionService.On("CanControlBot", "broken", "guild123").Return(false, errors.New("permission check failed"))
+
+	assert.NoError(t, handler.ValidatePermissions("admin", "guild123"))
+	assert.ErrorContains(t, handler.ValidatePermissions("user", "guild123"), "administrator permissions")
+	assert.ErrorContains(t, handler.ValidatePermissions("broken", "guild123"), "failed to check permissions")
+
+	mockPermissionService.AssertExpectations(t)
+}
+
+func TestDebugCommandHandler_BuildEmbed(t *testing.T) {
+	handler, _ := createTestDebugHandler(t)
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	connection := &VoiceConnection{GuildID: "guild1", ChannelID: "voice1"}
+
+	stats := &VoiceConnectionStats{
+		FramesSent:       990,
+		FramesDropped:    10,
+		FramesLate:       3,
+		Jitter:           1500 * time.Microsecond,
+		HeartbeatLatency: 42 * time.Millisecond,
+		LastAudio:        now.Add(-time.Second),
+	}
+	embed := handler.buildEmbed("guild1", connection, stats, now)
+
+	assert.Equal(t, "🩺 Voice Connection Diagnostics", embed.Title)
+	assert.Equal(t, "Connected to <#voice1>", embed.Description)
+	assert.Equal(t, debugEmbedColorHealthy, embed.Color)
+	require.Len(t, embed.Fields, 6)
+	assert.Equal(t, "✅ Healthy", embed.Fields[0].Value)
+	assert.Equal(t, "990", embed.Fields[1].Value)
+	assert.Equal(t, "10 (1.0%)", embed.Fields[2].Value)
+	assert.Equal(t, "3", embed.Fields[3].Value)
+	assert.Equal(t, "1.5 ms", embed.Fields[4].Value)
+	assert.Equal(t, "42.0 ms", embed.Fields[5].Value)
+	require.NotNil(t, embed.Footer)
+	assert.Equal(t, "Last audio at 11:59:59 (UTC)", embed.Footer.Text)
+}
+
+func TestDebugCommandHandler_BuildEmbedDegraded(t *testing.T) {
+	handler, _ := createTestDebugHandler(t)
+	now := time.Now()
+
+	stats := &VoiceConnectionStats{Jitter: 25 * time.Millisecond, LastAudio: now}
+	embed := handler.buildEmbed("guild1", &VoiceConnection{ChannelID: "voice1"}, stats, now)
+
+	assert.Equal(t, debugEmbedColorDegraded, embed.Color)
+	assert.Contains(t, embed.Fields[0].Value, "⚠️ Degraded: frame send jitter")
+
+	// Connection state takes precedence over send quality
+	embed = handler.buildEmbed("guild1", &VoiceConnection{ChannelID: "voice1", Reconnecting: true}, &VoiceConnectionStats{}, now)
+	assert.Equal(t, "🔄 Reconnecting", embed.Fields[0].Value)
+	assert.Equal(t, "No audio sent on this connection yet", embed.Footer.Text)
+}
@@ -0,0 +1,441 @@
+package tts
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"darrot/internal/commands/options"
+	"darrot/internal/commands/textcmd"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Accent colors of the /darrot-diagnose embed
+const (
+	diagnoseEmbedColorPassed  = 0x57F287
+	diagnoseEmbedColorWarning = 0xFEE75C
+	diagnoseEmbedColorFailed  = 0xED4245
+)
+
+// Application flags that allow the privileged Message Content intent, for verified and
+// unverified applications. discordgo does not define them.
+const (
+	applicationFlagGatewayMessageContent        = 1 << 18
+	applicationFlagGatewayMessageContentLimited = 1 << 19
+)
+
+// diagnoseIntents are the gateway intents the TTS system cannot work without, with the
+// names the Discord Developer Portal uses
+var diagnoseIntents = []struct {
+	intent discordgo.Intent
+	name   string
+}{
+	{discordgo.IntentsGuilds, "Guilds"},
+	{discordgo.IntentsGuildMessages, "Guild Messages"},
+	{discordgo.IntentsGuildVoiceStates, "Guild Voice States"},
+	{discordgo.IntentsMessageContent, "Message Content"},
+}
+
+// channelPermission is a permission the bot needs in a channel and the localization key
+// of its name
+type channelPermission struct {
+	bit int64
+	key string
+}
+
+// Permissions the bot needs in the voice channel it reads in and in the text channel it
+// reads from
+var (
+	voicePermissions = []channelPermission{
+		{discordgo.PermissionViewChannel, "diagnose.permission.view_channel"},
+		{discordgo.PermissionVoiceConnect, "diagnose.permission.connect"},
+		{discordgo.PermissionVoiceSpeak, "diagnose.permission.speak"},
+	}
+	textPermissions = []channelPermission{
+		{discordgo.PermissionViewChannel, "diagnose.permission.view_channel"},
+		{discordgo.PermissionReadMessageHistory, "diagnose.permission.read_history"},
+		{discordgo.PermissionSendMessages, "diagnose.permission.send_messages"},
+	}
+)
+
+// diagnosticStatus is the outcome of one /darrot-diagnose check
+type diagnosticStatus int
+
+const (
+	diagnosticPassed  diagnosticStatus = iota
+	diagnosticWarning                  // Could not be checked fully
+	diagnosticFailed
+	diagnosticSkipped
+)
+
+// symbol returns the checklist mark of a status
+func (s diagnosticStatus) symbol() string {
+	switch s {
+	case diagnosticPassed:
+		return "✅"
+	case diagnosticWarning:
+		return "⚠️"
+	case diagnosticFailed:
+		return "❌"
+	default:
+		return "➖"
+	}
+}
+
+// diagnosticCheck reports one /darrot-diagnose check, with a hint on how to fix it
+// unless it passed
+type diagnosticCheck struct {
+	name   string
+	status diagnosticStatus
+	detail string
+	hint   string
+}
+
+// diagnosticsSession is the part of the Discord session the diagnostics read
+type diagnosticsSession interface {
+	UserChannelPermissions(userID, channelID string) (int64, error)
+	Application(appID string) (*discordgo.Application, error)
+}
+
+// DiagnoseCommandHandler handles the command that checks whether the bot has what it
+// needs to work in a server: channel permissions, gateway intents, the TTS engine and
+// the data directory
+type DiagnoseCommandHandler struct {
+	voiceManager      VoiceManager
+	ttsManager        TTSManager
+	storage           *StorageService
+	permissionService PermissionService
+	breakers          TTSBreakerStatus // Reports whether the guild's messages are being skipped
+	localizer         *Localizer
+	logger            *log.Logger
+}
+
+// NewDiagnoseCommandHandler creates a new diagnose command handler
+func NewDiagnoseCommandHandler(
+	voiceManager VoiceManager,
+	ttsManager TTSManager,
+	storage *StorageService,
+	permissionService PermissionService,
+	logger *log.Logger,
+) *DiagnoseCommandHandler {
+	return &DiagnoseCommandHandler{
+		voiceManager:      voiceManager,
+		ttsManager:        ttsManager,
+		storage:           storage,
+		permissionService: permissionService,
+		logger:            logger,
+	}
+}
+
+// SetBreakerStatus reports the state of each guild's TTS circuit breaker
+func (h *DiagnoseCommandHandler) SetBreakerStatus(breakers TTSBreakerStatus) {
+	h.breakers = breakers
+}
+
+// SetLocalizer sets the localizer used to translate responses
+func (h *DiagnoseCommandHandler) SetLocalizer(localizer *Localizer) {
+	h.localizer = localizer
+}
+
+// Definition returns the Discord slash command definition for the diagnose command
+func (h *DiagnoseCommandHandler) Definition() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:                     "darrot-diagnose",
+		Description:              "Check the bot's permissions, intents, TTS engine and storage (Administrator only)",
+		DefaultMemberPermissions: adminCommandPermissions(),
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:         discordgo.ApplicationCommandOptionChannel,
+				Name:         "voice-channel",
+				Description:  "Voice or stage channel to check (defaults to the bot's or your current one)",
+				Required:     false,
+				ChannelTypes: voiceChannelTypes,
+			},
+			{
+				Type:         discordgo.ApplicationCommandOptionChannel,
+				Name:         "text-channel",
+				Description:  "Text channel to check (defaults to this channel)",
+				Required:     false,
+				ChannelTypes: textChannelTypes,
+			},
+		},
+	}
+}
+
+// Handle processes the diagnose command interaction
+func (h *DiagnoseCommandHandler) Handle(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	// Validate guild context
+	if i.GuildID == "" {
+		return h.respondError(s, i, h.localizer.T(i.GuildID, "common.guild_only"))
+	}
+
+	userID := i.Member.User.ID
+	guildID := i.GuildID
+
+	// Validate administrator permissions
+	if err := h.ValidatePermissions(userID, guildID); err != nil {
+		return h.respondError(s, i, h.localizer.T(guildID, "common.permission_denied", err))
+	}
+
+	opts := options.FromInteraction(i)
+	voiceChannelID, ok := opts.ChannelID("voice-channel")
+	if !ok {
+		voiceChannelID = h.currentVoiceChannel(s, guildID, userID)
+	}
+	textChannelID, ok := opts.ChannelID("text-channel")
+	if !ok {
+		textChannelID = i.ChannelID
+	}
+
+	// The checks call Discord and Google Cloud, which can exceed the response deadline
+	if err := textcmd.Respond(s, i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Flags: discordgo.MessageFlagsEphemeral,
+		},
+	}); err != nil {
+		return err
+	}
+
+	checks := h.diagnose(NewDiscordSessionWrapper(s), s.State.User.ID, s.Identify.Intents, guildID, voiceChannelID, textChannelID)
+	embeds := []*discordgo.MessageEmbed{h.buildEmbed(guildID, checks)}
+	_, err := textcmd.EditResponse(s, i.Interaction, &discordgo.WebhookEdit{Embeds: &embeds})
+	return err
+}
+
+// currentVoiceChannel returns the voice channel the bot is in, or else the one the user
+// is in, or "" when neither is in one
+func (h *DiagnoseCommandHandler) currentVoiceChannel(s *discordgo.Session, guildID, userID string) string {
+	if connection, exists := h.voiceManager.GetConnection(guildID); exists && connection != nil {
+		return connection.ChannelID
+	}
+	if voiceState, err := s.State.VoiceState(guildID, userID); err == nil && voiceState != nil {
+		return voiceState.ChannelID
+	}
+	return ""
+}
+
+// diagnose runs every check for the bot with ID botID, connected with intents
+func (h *DiagnoseCommandHandler) diagnose(session diagnosticsSession, botID string, intents discordgo.Intent, guildID, voiceChannelID, textChannelID string) []diagnosticCheck {
+	checks := []diagnosticCheck{
+		h.checkChannel(session, botID, guildID, "diagnose.voice_channel", voiceChannelID, voicePermissions),
+		h.checkChannel(session, botID, guildID, "diagnose.text_channel", textChannelID, textPermissions),
+		h.checkIntents(session, guildID, intents),
+		h.checkTTS(guildID),
+		h.checkStorage(guildID),
+	}
+	if h.breakers != nil {
+		checks = append(checks, h.checkBreaker(guildID))
+	}
+	return checks
+}
+
+// checkChannel checks that the bot has the required permissions in a channel
+func (h *DiagnoseCommandHandler) checkChannel(session diagnosticsSession, botID, guildID, nameKey, channelID string, required []channelPermission) diagnosticCheck {
+	check := diagnosticCheck{name: h.localizer.T(guildID, nameKey)}
+	if channelID == "" {
+		check.status = diagnosticSkipped
+		check.detail = h.localizer.T(guildID, "diagnose.no_voice_channel")
+		check.hint = h.localizer.T(guildID, "diagnose.hint.no_voice_channel")
+		return check
+	}
+
+	permissions, err := session.UserChannelPermissions(botID, channelID)
+	if err != nil {
+		check.status = diagnosticFailed
+		check.detail = h.localizer.T(guildID, "diagnose.permissions_unreadable", channelID, err)
+		check.hint = h.localizer.T(guildID, "diagnose.hint.permissions_unreadable")
+		return check
+	}
+
+	var granted, missing []string
+	for _, permission := range required {
+		name := h.localizer.T(guildID, permission.key)
+		if permissions&discordgo.PermissionAdministrator != 0 || permissions&permission.bit != 0 {
+			granted = append(granted, name)
+		} else {
+			missing = append(missing, name)
+		}
+	}
+
+	if len(missing) > 0 {
+		check.status = diagnosticFailed
+		check.detail = h.localizer.T(guildID, "diagnose.permissions_missing", strings.Join(missing, ", "), channelID)
+		check.hint = h.localizer.T(guildID, "diagnose.hint.permissions")
+		return check
+	}
+	check.detail = h.localizer.T(guildID, "diagnose.permissions_ok", strings.Join(granted, ", "), channelID)
+	return check
+}
+
+// checkIntents checks that the bot asked for the intents it needs and that the
+// application is allowed the privileged Message Content intent
+func (h *DiagnoseCommandHandler) checkIntents(session diagnosticsSession, guildID string, intents discordgo.Intent) diagnosticCheck {
+	check := diagnosticCheck{name: h.localizer.T(guildID, "diagnose.intents")}
+
+	var requested, missing []string
+	for _, required := range diagnoseIntents {
+		if intents&required.intent == required.intent {
+			requested = append(requested, required.name)
+		} else {
+			missing = append(missing, required.name)
+		}
+	}
+	if len(missing) > 0 {
+		check.status = diagnosticFailed
+		check.detail = h.localizer.T(guildID, "diagnose.intents_missing", strings.Join(missing, ", "))
+		check.hint = h.localizer.T(guildID, "diagnose.hint.intents_missing")
+		return check
+	}
+
+	application, err := session.Application("@me")
+	if err != nil {
+		check.status = diagnosticWarning
+		check.detail = h.localizer.T(guildID, "diagnose.intents_unverified", strings.Join(requested, ", "), err)
+		return check
+	}
+	if application.Flags&(applicationFlagGatewayMessageContent|applicationFlagGatewayMessageContentLimited) == 0 {
+		check.status = diagnosticFailed
+		check.detail = h.localizer.T(guildID, "diagnose.message_content_off")
+		check.hint = h.localizer.T(guildID, "diagnose.hint.message_content")
+		return check
+	}
+
+	check.detail = h.localizer.T(guildID, "diagnose.intents_ok", strings.Join(requested, ", "))
+	return check
+}
+
+// checkTTS makes one request to the TTS engine
+func (h *DiagnoseCommandHandler) checkTTS(guildID string) diagnosticCheck {
+	check := diagnosticCheck{name: h.localizer.T(guildID, "diagnose.tts")}
+
+	var err error
+	started := time.Now()
+	switch manager := h.ttsManager.(type) {
+	case TTSEngineProbe:
+		ctx, cancel := context.WithTimeout(context.Background(), engineCheckTimeout)
+		defer cancel()
+		err = manager.ProbeEngine(ctx)
+	case TTSEngineStatus:
+		err = manager.EngineError()
+	default:
+		check.status = diagnosticSkipped
+		check.detail = h.localizer.T(guildID, "diagnose.tts_not_checked")
+		return check
+	}
+
+	if err != nil {
+		h.logger.Printf("TTS engine check failed for guild %s: %v", guildID, err)
+		check.status = diagnosticFailed
+		check.detail = h.localizer.T(guildID, "diagnose.tts_failed", err)
+		check.hint = h.localizer.T(guildID, "diagnose.hint.tts")
+		return check
+	}
+	check.detail = h.localizer.T(guildID, "diagnose.tts_ok", time.Since(started).Milliseconds())
+	return check
+}
+
+// checkBreaker reports whether the guild's messages are skipped because synthesis kept
+// failing
+func (h *DiagnoseCommandHandler) checkBreaker(guildID string) diagnosticCheck {
+	check := diagnosticCheck{name: h.localizer.T(guildID, "diagnose.tts_breaker")}
+	status := h.breakers.BreakerStatus(guildID)
+	switch status.State {
+	case BreakerOpen:
+		check.status = diagnosticFailed
+		check.detail = h.localizer.T(guildID, "diagnose.tts_breaker_open", status.Failures, status.OpenUntil.Unix())
+		check.hint = h.localizer.T(guildID, "diagnose.hint.tts_breaker")
+	case BreakerHalfOpen:
+		check.status = diagnosticWarning
+		check.detail = h.localizer.T(guildID, "diagnose.tts_breaker_half_open")
+	default:
+		check.detail = h.localizer.T(guildID, "diagnose.tts_breaker_closed")
+	}
+	return check
+}
+
+// checkStorage checks that guild settings can be saved
+func (h *DiagnoseCommandHandler) checkStorage(guildID string) diagnosticCheck {
+	check := diagnosticCheck{name: h.localizer.T(guildID, "diagnose.storage")}
+
+	if err := h.storage.CheckWritable(); err != nil {
+		h.logger.Printf("Storage check failed for guild %s: %v", guildID, err)
+		check.status = diagnosticFailed
+		check.detail = h.localizer.T(guildID, "diagnose.storage_failed", err)
+		check.hint = h.localizer.T(guildID, "diagnose.hint.storage")
+		return check
+	}
+	check.detail = h.localizer.T(guildID, "diagnose.storage_ok")
+	return check
+}
+
+// buildEmbed renders the checks as a checklist with a hint under every problem
+func (h *DiagnoseCommandHandler) buildEmbed(guildID string, checks []diagnosticCheck) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Title: h.localizer.T(guildID, "diagnose.title"),
+		Color: diagnoseEmbedColorPassed,
+	}
+
+	problems := 0
+	for _, check := range checks {
+		value := check.detail
+		if check.hint != "" {
+			value += "\n💡 " + check.hint
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  check.status.symbol() + " " + check.name,
+			Value: value,
+		})
+
+		switch check.status {
+		case diagnosticFailed:
+			problems++
+			embed.Color = diagnoseEmbedColorFailed
+		case diagnosticWarning:
+			if embed.Color != diagnoseEmbedColorFailed {
+				embed.Color = diagnoseEmbedColorWarning
+			}
+		}
+	}
+
+	if problems > 0 {
+		embed.Description = h.localizer.T(guildID, "diagnose.problems", problems)
+	} else {
+		embed.Description = h.localizer.T(guildID, "diagnose.all_passed")
+	}
+	return embed
+}
+
+// ValidatePermissions validates that the user has administrator permissions
+func (h *DiagnoseCommandHandler) ValidatePermissions(userID, guildID string) error {
+	canControl, err := h.permissionService.CanControlBot(userID, guildID)
+	if err != nil {
+		return fmt.Errorf("failed to check permissions: %w", err)
+	}
+
+	if !canControl {
+		return withKind(ErrPermission, fmt.Errorf("you must have administrator permissions to run diagnostics"))
+	}
+
+	return nil
+}
+
+// ValidateChannelAccess is not needed for diagnose commands but required by interface
+func (h *DiagnoseCommandHandler) ValidateChannelAccess(userID, channelID string) error {
+	return nil // Not applicable for diagnose commands
+}
+
+// Helper methods for response handling
+
+func (h *DiagnoseCommandHandler) respondError(s *discordgo.Session, i *discordgo.InteractionCreate, message string) error {
+	return textcmd.Respond(s, i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: "❌ " + message,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
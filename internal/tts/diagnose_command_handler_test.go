@@ -0,0 +1,179 @@
+package tts
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDiagnosticsSession answers the diagnostics with fixed permissions and application
+// flags
+type fakeDiagnosticsSession struct {
+	permissions    map[string]int64
+	flags          int
+	applicationErr error
+}
+
+func (f *fakeDiagnosticsSession) UserChannelPermissions(userID, channelID string) (int64, error) {
+	permissions, ok := f.permissions[channelID]
+	if !ok {
+		return 0, errors.New("unknown channel")
+	}
+	return permissions, nil
+}
+
+func (f *fakeDiagnosticsSession) Application(appID string) (*discordgo.Application, error) {
+	if f.applicationErr != nil {
+		return nil, f.applicationErr
+	}
+	return &discordgo.Application{ID: "bot1", Flags: f.flags}, nil
+}
+
+// probedTTSManager is a TTS manager whose engine answers with err
+type probedTTSManager struct {
+	mockTTSManager
+	err error
+}
+
+func (m *probedTTSManager) ProbeEngine(ctx context.Context) error {
+	return m.err
+}
+
+const diagnoseTestIntents = discordgo.IntentsGuilds | discordgo.IntentsGuildMessages | discordgo.IntentsGuildVoiceStates | discordgo.IntentsMessageContent
+
+func createTestDiagnoseHandler(t *testing.T, ttsManager TTSManager) (*DiagnoseCommandHandler, *MockPermissionService) {
+	storage, err := NewStorageService(t.TempDir())
+	require.NoError(t, err)
+	mockPermissionService := &MockPermissionService{}
+	logger := log.New(os.Stdout, "[TEST] ", log.LstdFlags)
+
+	return NewDiagnoseCommandHandler(newMockVoiceManager(), ttsManager, storage, mockPermissionService, logger), mockPermissionService
+}
+
+func TestDiagnoseCommandHandler_Definition(t *testing.T) {
+	handler, _ := createTestDiagnoseHandler(t, &probedTTSManager{})
+
+	definition := handler.Definition()
+
+	assert.Equal(t, "darrot-diagnose", definition.Name)
+	assert.Contains(t, definition.Description, "Administrator only")
+	require.Len(t, definition.Options, 2)
+	assert.Equal(t, "voice-channel", definition.Options[0].Name)
+	assert.Equal(t, "text-channel", definition.Options[1].Name)
+	assert.False(t, definition.Options[0].Required)
+	assert.False(t, definition.Options[1].Required)
+}
+
+func TestDiagnoseCommandHandler_ValidatePermissions(t *testing.T) {
+	handler, mockPermissionService := createTestDiagnoseHandler(t, &probedTTSManager{})
+
+	mockPermissionService.On("CanControlBot", "admin", "guild123").Return(true, nil)
+	mockPermissionService.On("CanControlBot", "user", "guild123").Return(false, nil)
+
+	assert.NoError(t, handler.ValidatePermissions("admin", "guild123"))
+	assert.ErrorContains(t, handler.ValidatePermissions("user", "guild123"), "administrator permissions")
+
+	mockPermissionService.AssertExpectations(t)
+}
+
+func TestDiagnoseCommandHandler_AllPassed(t *testing.T) {
+	handler, _ := createTestDiagnoseHandler(t, &probedTTSManager{})
+	session := &fakeDiagnosticsSession{
+		permissions: map[string]int64{
+			"voice1": discordgo.PermissionViewChannel | discordgo.PermissionVoiceConnect | discordgo.PermissionVoiceSpeak,
+			"text1":  discordgo.PermissionAdministrator,
+		},
+		flags: applicationFlagGatewayMessageContentLimited,
+	}
+
+	checks := handler.diagnose(session, "bot1", diagnoseTestIntents, "guild1", "voice1", "text1")
+	for _, check := range checks {
+		assert.Equal(t, diagnosticPassed, check.status, "%s: %s", check.name, check.detail)
+		assert.Empty(t, check.hint)
+	}
+
+	embed := handler.buildEmbed("guild1", checks)
+	assert.Equal(t, "🩺 Bot Diagnostics", embed.Title)
+	assert.Equal(t, "Everything the bot needs is in place.", embed.Description)
+	assert.Equal(t, diagnoseEmbedColorPassed, embed.Color)
+	require.Len(t, embed.Fields, 5)
+	assert.Equal(t, "✅ Voice channel permissions", embed.Fields[0].Name)
+	assert.Equal(t, "The bot has View Channel, Connect, Speak in <#voice1>.", embed.Fields[0].Value)
+	assert.Equal(t, "Requested and allowed: Guilds, Guild Messages, Guild Voice States, Message Content.", embed.Fields[2].Value)
+}
+
+func TestDiagnoseCommandHandler_ReportsProblemsWithHints(t *testing.T) {
+	handler, _ := createTestDiagnoseHandler(t, &probedTTSManager{err: errors.New("permission denied")})
+	session := &fakeDiagnosticsSession{
+		permissions: map[string]int64{
+			"voice1": discordgo.PermissionViewChannel,
+			"text1":  discordgo.PermissionViewChannel | discordgo.PermissionSendMessages,
+		},
+	}
+
+	// The data directory is gone
+	require.NoError(t, os.RemoveAll(handler.storage.dataDir))
+
+	checks := handler.diagnose(session, "bot1", diagnoseTestIntents, "guild1", "voice1", "text1")
+	require.Len(t, checks, 5)
+	for _, check := range checks {
+		assert.Equal(t, diagnosticFailed, check.status, "%s: %s", check.name, check.detail)
+		assert.NotEmpty(t, check.hint, check.name)
+	}
+	assert.Equal(t, "The bot is missing Connect, Speak in <#voice1>.", checks[0].detail)
+	assert.Equal(t, "The bot is missing Read Message History in <#text1>.", checks[1].detail)
+	assert.Contains(t, checks[2].hint, "Privileged Gateway Intents")
+	assert.Equal(t, "Not reachable: permission denied", checks[3].detail)
+
+	embed := handler.buildEmbed("guild1", checks)
+	assert.Equal(t, diagnoseEmbedColorFailed, embed.Color)
+	assert.Equal(t, "Found 5 problem(s). Follow the hints below to fix them.", embed.Description)
+	assert.Contains(t, embed.Fields[0].Value, "\n💡 ")
+}
+
+func TestDiagnoseCommandHandler_UncheckedAndUnverified(t *testing.T) {
+	handler, _ := createTestDiagnoseHandler(t, &mockTTSManager{})
+	session := &fakeDiagnosticsSession{
+		permissions:    map[string]int64{"text1": discordgo.PermissionAdministrator},
+		applicationErr: errors.New("unauthorized"),
+	}
+
+	checks := handler.diagnose(session, "bot1", diagnoseTestIntents&^discordgo.IntentsGuildVoiceStates, "guild1", "", "text1")
+
+	// Neither the user nor the bot is in a voice channel
+	assert.Equal(t, diagnosticSkipped, checks[0].status)
+	assert.Equal(t, diagnosticPassed, checks[1].status)
+
+	// The intents the bot does not request are reported before the application is read
+	assert.Equal(t, diagnosticFailed, checks[2].status)
+	assert.Equal(t, "The bot does not request Guild Voice States.", checks[2].detail)
+
+	// TTS managers that cannot be probed are not checked
+	assert.Equal(t, diagnosticSkipped, checks[3].status)
+
+	intents := handler.checkIntents(session, "guild1", diagnoseTestIntents)
+	assert.Equal(t, diagnosticWarning, intents.status)
+
+	embed := handler.buildEmbed("guild1", append(checks[:2], intents))
+	assert.Equal(t, diagnoseEmbedColorWarning, embed.Color)
+}
+
+func TestStorageService_CheckWritable(t *testing.T) {
+	service, tempDir := newTestStorage(t)
+
+	require.NoError(t, service.CheckWritable())
+	entries, _ := os.ReadDir(tempDir)
+	assert.Empty(t, entries)
+
+	missing, err := NewStorageService(filepath.Join(t.TempDir(), "data"))
+	require.NoError(t, err)
+	require.NoError(t, os.RemoveAll(missing.dataDir))
+	assert.Error(t, missing.CheckWritable())
+}
@@ -0,0 +1,430 @@
+package tts
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Code points that extend an emoji into a longer sequence
+const (
+	variationSelector = '\uFE0F'
+	zeroWidthJoiner   = '\u200D'
+	keycapMark        = '\u20E3'
+)
+
+// customEmoteRegex matches a Discord custom emote such as <:name:id> or <a:name:id>
+// at the start of a string
+var customEmoteRegex = regexp.MustCompile(`^<a?:(\w+):\d+>`)
+
+// speakEmoji replaces custom emotes and Unicode emoji with spoken names such as
+// "fire emoji". Once limit emoji have been read, the rest are collapsed into
+// "and N more emoji"; a limit of 0 reads them all. Emoji without a known name are
+// passed through for the speech engine to handle.
+func speakEmoji(content string, limit int) string {
+	var b strings.Builder
+	spoken, dropped := 0, 0
+	collapsedAt := -1
+	afterEmoji := false
+
+	for i := 0; i < len(content); {
+		name, width := scanEmoji(content[i:])
+		if width == 0 {
+			r, size := utf8.DecodeRuneInString(content[i:])
+			if afterEmoji && isWordRune(r) {
+				b.WriteByte(' ')
+			}
+			b.WriteString(content[i : i+size])
+			afterEmoji = false
+			i += size
+			continue
+		}
+		i += width
+
+		if limit > 0 && spoken >= limit {
+			if dropped == 0 {
+				collapsedAt = b.Len()
+			}
+			dropped++
+			continue
+		}
+		spoken++
+
+		if last, _ := utf8.DecodeLastRuneInString(b.String()); afterEmoji || isWordRune(last) {
+			b.WriteByte(' ')
+		}
+		b.WriteString(name)
+		afterEmoji = true
+	}
+
+	result := b.String()
+	if dropped > 0 {
+		more := fmt.Sprintf("and %d more emoji", dropped)
+		if !strings.HasSuffix(result[:collapsedAt], " ") {
+			more = " " + more
+		}
+		result = result[:collapsedAt] + more + result[collapsedAt:]
+	}
+	return result
+}
+
+// dropEmoji replaces custom emotes and Unicode emoji with spaces, so words on either side
+// stay apart. Callers collapse the extra spaces.
+func dropEmoji(content string) string {
+	var b strings.Builder
+	for i := 0; i < len(content); {
+		if _, width := scanEmoji(content[i:]); width > 0 {
+			b.WriteByte(' ')
+			i += width
+			continue
+		}
+		_, size := utf8.DecodeRuneInString(content[i:])
+		b.WriteString(content[i : i+size])
+		i += size
+	}
+	return b.String()
+}
+
+// scanEmoji returns the spoken name and byte length of the emoji at the start of s,
+// or a zero length if s does not start with one
+func scanEmoji(s string) (string, int) {
+	if match := customEmoteRegex.FindStringSubmatch(s); match != nil {
+		return strings.ReplaceAll(match[1], "_", " ") + " emoji", len(match[0])
+	}
+
+	r, width := utf8.DecodeRuneInString(s)
+	if !isEmojiBase(r) {
+		return "", 0
+	}
+
+	// Two regional indicator letters form a flag
+	if isRegionalIndicator(r) {
+		next, size := utf8.DecodeRuneInString(s[width:])
+		if !isRegionalIndicator(next) {
+			return "", 0
+		}
+		return "flag emoji", width + size
+	}
+
+	// Consume variation selectors, skin tones and joined emoji
+	for width < len(s) {
+		next, size := utf8.DecodeRuneInString(s[width:])
+		switch {
+		case next == variationSelector || next == keycapMark || isSkinTone(next):
+			width += size
+		case next == zeroWidthJoiner:
+			joined, joinedSize := utf8.DecodeRuneInString(s[width+size:])
+			if !isEmojiBase(joined) {
+				return emojiName(s[:width]), width
+			}
+			width += size + joinedSize
+		default:
+			return emojiName(s[:width]), width
+		}
+	}
+	return emojiName(s[:width]), width
+}
+
+// emojiName returns the spoken name of an emoji sequence. Joined sequences without a
+// name of their own fall back to their first emoji.
+func emojiName(sequence string) string {
+	key := strings.Map(func(r rune) rune {
+		if r == variationSelector || isSkinTone(r) {
+			return -1
+		}
+		return r
+	}, sequence)
+
+	if name, ok := emojiNames[key]; ok {
+		return name + " emoji"
+	}
+	if first, _, found := strings.Cut(key, string(zeroWidthJoiner)); found {
+		if name, ok := emojiNames[first]; ok {
+			return name + " emoji"
+		}
+	}
+	return sequence
+}
+
+// isEmojiBase reports whether r starts an emoji sequence
+func isEmojiBase(r rune) bool {
+	switch {
+	case r >= 0x1F000 && r <= 0x1FAFF:
+		return !isSkinTone(r)
+	case r >= 0x2600 && r <= 0x27BF, // Miscellaneous symbols and dingbats
+		r >= 0x231A && r <= 0x23FF, // Watch, hourglass, media controls
+		r == 0x2B50, r == 0x2B55, r == 0x2B1B, r == 0x2B1C,
+		r == 0x203C, r == 0x2049, r == 0x2122, r == 0x2139:
+		return true
+	}
+	return false
+}
+
+func isSkinTone(r rune) bool {
+	return r >= 0x1F3FB && r <= 0x1F3FF
+}
+
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1F1E6 && r <= 0x1F1FF
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// emojiNames holds short spoken names for commonly used emoji, keyed without
+// variation selectors or skin tones
+var emojiNames = map[string]string{
+	// Faces
+	"😀": "grinning face",
+	"😃": "grinning face with big eyes",
+	"😄": "grinning face with smiling eyes",
+	"😁": "beaming face",
+	"😆": "grinning squinting face",
+	"😅": "grinning face with sweat",
+	"🤣": "rolling on the floor laughing",
+	"😂": "tears of joy",
+	"🙂": "slightly smiling face",
+	"🙃": "upside-down face",
+	"😉": "winking face",
+	"😊": "smiling face",
+	"😇": "halo",
+	"🥰": "smiling face with hearts",
+	"😍": "heart eyes",
+	"🤩": "star-struck",
+	"😘": "blowing a kiss",
+	"😋": "yum",
+	"😛": "tongue out",
+	"😜": "winking face with tongue",
+	"🤪": "zany face",
+	"🤑": "money-mouth face",
+	"🤗": "hugging face",
+	"🤭": "hand over mouth",
+	"🤫": "shushing face",
+	"🤔": "thinking face",
+	"🤐": "zipper-mouth face",
+	"🤨": "raised eyebrow",
+	"😐": "neutral face",
+	"😑": "expressionless face",
+	"😶": "face without mouth",
+	"😏": "smirk",
+	"😒": "unamused face",
+	"🙄": "eye roll",
+	"😬": "grimace",
+	"😌": "relieved face",
+	"😔": "pensive face",
+	"😪": "sleepy face",
+	"😴": "sleeping face",
+	"😷": "face with mask",
+	"🤒": "face with thermometer",
+	"🤢": "nauseated face",
+	"🤮": "vomiting face",
+	"🥵": "hot face",
+	"🥶": "cold face",
+	"🥴": "woozy face",
+	"😵": "dizzy face",
+	"🤯": "mind blown",
+	"🥳": "party face",
+	"😎": "sunglasses",
+	"🤓": "nerd face",
+	"🧐": "monocle",
+	"😕": "confused face",
+	"😟": "worried face",
+	"🙁": "slightly frowning face",
+	"☹": "frowning face",
+	"😮": "open mouth",
+	"😲": "astonished face",
+	"😳": "flushed face",
+	"🥺": "pleading face",
+	"😦": "frowning face with open mouth",
+	"😨": "fearful face",
+	"😰": "anxious face",
+	"😢": "crying face",
+	"😭": "loudly crying face",
+	"😱": "screaming face",
+	"😖": "confounded face",
+	"😣": "persevering face",
+	"😞": "disappointed face",
+	"😓": "downcast face",
+	"😩": "weary face",
+	"😫": "tired face",
+	"🥱": "yawning face",
+	"😤": "huffing face",
+	"😡": "pouting face",
+	"😠": "angry face",
+	"🤬": "cursing face",
+	"😈": "smiling devil",
+	"💀": "skull",
+	"☠": "skull and crossbones",
+	"💩": "pile of poo",
+	"🤡": "clown",
+	"👻": "ghost",
+	"👽": "alien",
+	"🤖": "robot",
+	"🫠": "melting face",
+	"🫡": "saluting face",
+
+	// Gestures and people
+	"👋": "waving hand",
+	"👌": "OK hand",
+	"🤌": "pinched fingers",
+	"✌": "victory hand",
+	"🤞": "crossed fingers",
+	"🤟": "love-you gesture",
+	"🤘": "sign of the horns",
+	"🤙": "call me hand",
+	"👈": "pointing left",
+	"👉": "pointing right",
+	"👆": "pointing up",
+	"👇": "pointing down",
+	"☝": "index pointing up",
+	"👍": "thumbs up",
+	"👎": "thumbs down",
+	"✊": "raised fist",
+	"👊": "fist bump",
+	"👏": "clapping hands",
+	"🙌": "raising hands",
+	"👐": "open hands",
+	"🤝": "handshake",
+	"🙏": "folded hands",
+	"💪": "flexed biceps",
+	"🫶": "heart hands",
+	"👀": "eyes",
+	"👁": "eye",
+	"🧠": "brain",
+	"🤷": "shrug",
+	"🤦": "facepalm",
+	"🙋": "raising hand",
+	"🙇": "bowing",
+
+	// Hearts and symbols
+	"❤": "red heart",
+	"🧡": "orange heart",
+	"💛": "yellow heart",
+	"💚": "green heart",
+	"💙": "blue heart",
+	"💜": "purple heart",
+	"🖤": "black heart",
+	"🤍": "white heart",
+	"🤎": "brown heart",
+	"💔": "broken heart",
+	"💕": "two hearts",
+	"💖": "sparkling heart",
+	"💯": "hundred points",
+	"💢": "anger symbol",
+	"💥": "collision",
+	"💫": "dizzy",
+	"💦": "sweat droplets",
+	"💨": "dashing away",
+	"💤": "zzz",
+	"💬": "speech balloon",
+	"✅": "check mark",
+	"✔": "check mark",
+	"☑": "check box",
+	"❌": "cross mark",
+	"❎": "cross mark button",
+	"❗": "exclamation mark",
+	"❓": "question mark",
+	"‼": "double exclamation mark",
+	"⁉": "exclamation question mark",
+	"⚠": "warning",
+	"🚫": "prohibited",
+	"⛔": "no entry",
+	"🆗": "OK button",
+	"🆕": "new button",
+	"🔞": "no one under eighteen",
+	"™": "trade mark",
+	"ℹ": "information",
+
+	// Nature, food and objects
+	"🔥": "fire",
+	"✨": "sparkles",
+	"⭐": "star",
+	"🌟": "glowing star",
+	"⚡": "lightning",
+	"☀": "sun",
+	"🌙": "crescent moon",
+	"🌈": "rainbow",
+	"☁": "cloud",
+	"❄": "snowflake",
+	"🌊": "water wave",
+	"🌹": "rose",
+	"🌸": "cherry blossom",
+	"🍀": "four leaf clover",
+	"🐶": "dog face",
+	"🐱": "cat face",
+	"🐸": "frog",
+	"🐍": "snake",
+	"🦀": "crab",
+	"🦄": "unicorn",
+	"🐐": "goat",
+	"🍕": "pizza",
+	"🍔": "hamburger",
+	"🍟": "french fries",
+	"🌮": "taco",
+	"🍿": "popcorn",
+	"🍰": "cake",
+	"🎂": "birthday cake",
+	"🍪": "cookie",
+	"☕": "coffee",
+	"🍺": "beer",
+	"🍻": "clinking beer mugs",
+	"🍷": "wine glass",
+	"🥂": "clinking glasses",
+	"🎉": "party popper",
+	"🎊": "confetti ball",
+	"🎁": "gift",
+	"🎈": "balloon",
+	"🏆": "trophy",
+	"🥇": "gold medal",
+	"🎮": "video game",
+	"🎲": "game die",
+	"🎵": "musical note",
+	"🎶": "musical notes",
+	"🎤": "microphone",
+	"🎧": "headphones",
+	"📢": "loudspeaker",
+	"🔔": "bell",
+	"🔕": "bell with slash",
+	"🔇": "muted speaker",
+	"🔊": "speaker high volume",
+	"📌": "pushpin",
+	"📎": "paperclip",
+	"📝": "memo",
+	"📅": "calendar",
+	"📷": "camera",
+	"💻": "laptop",
+	"📱": "mobile phone",
+	"💡": "light bulb",
+	"💰": "money bag",
+	"💸": "money with wings",
+	"🔒": "locked",
+	"🔑": "key",
+	"🔨": "hammer",
+	"🛠": "hammer and wrench",
+	"⚙": "gear",
+	"🐛": "bug",
+	"🚀": "rocket",
+	"🚗": "car",
+	"✈": "airplane",
+	"⏰": "alarm clock",
+	"⌛": "hourglass",
+	"⏳": "hourglass flowing sand",
+	"🧢": "billed cap",
+	"👑": "crown",
+	"💎": "gem stone",
+	"🗿": "moai",
+	"🎯": "direct hit",
+	"🏳": "white flag",
+	"🏴": "black flag",
+	"🚩": "triangular flag",
+
+	// Joined sequences
+	"🏳\u200d🌈": "rainbow flag",
+	"🏴\u200d☠": "pirate flag",
+	"❤\u200d🔥": "heart on fire",
+	"😮\u200d💨": "face exhaling",
+	"😶\u200d🌫": "face in clouds",
+}
@@ -0,0 +1,51 @@
+package tts
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpeakEmoji(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		limit    int
+		expected string
+	}{
+		{"unicode emoji", "that is 🔥", 0, "that is fire emoji"},
+		{"emoji between words", "nice🔥work", 0, "nice fire emoji work"},
+		{"consecutive emoji", "🎉🎉", 0, "party popper emoji party popper emoji"},
+		{"variation selector", "I ❤️ it", 0, "I red heart emoji it"},
+		{"skin tone", "👍🏽 sure", 0, "thumbs up emoji sure"},
+		{"joined sequence", "🏳️‍🌈", 0, "rainbow flag emoji"},
+		{"joined sequence falls back to first emoji", "🤷‍♀️", 0, "shrug emoji"},
+		{"flag", "go 🇫🇮!", 0, "go flag emoji!"},
+		{"custom emote", "hi <:party_parrot:123>!", 0, "hi party parrot emoji!"},
+		{"animated emote", "<a:dance:456>", 0, "dance emoji"},
+		{"unknown emoji passes through", "🦩", 0, "🦩"},
+		{"no emoji", "plain text: (ok)", 0, "plain text: (ok)"},
+		{"within limit", "🔥🔥", 2, "fire emoji fire emoji"},
+		{"collapsed", "wow 🔥🔥🔥🔥🔥", 2, "wow fire emoji fire emoji and 3 more emoji"},
+		{"collapsed mid-message", "🔥 a 😂 b", 1, "fire emoji a and 1 more emoji b"},
+		{"collapsed before punctuation", "🔥🔥🔥!", 1, "fire emoji and 2 more emoji!"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, speakEmoji(tt.content, tt.limit))
+		})
+	}
+}
+
+func TestApplyContentModes_EmojiLimit(t *testing.T) {
+	modes := ContentModesFor(&GuildTTSConfig{MaxSpokenEmoji: 1})
+	assert.Equal(t, "gg party popper emoji and 2 more emoji", applyContentModes("gg 🎉 😂 🔥", modes))
+}
+
+func TestApplyContentModes_SkipEmoji(t *testing.T) {
+	modes := ContentModesFor(nil)
+	modes.SkipEmoji = true
+	assert.Equal(t, "gg well played", applyContentModes("gg 🎉 well<:pog:123>played 👍🏽", modes))
+	assert.Equal(t, "", applyContentModes("🎉🎉", modes))
+}
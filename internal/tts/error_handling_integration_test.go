@@ -265,6 +265,18 @@ func (m *mockUserServiceForIntegration) AutoOptIn(userID, guildID string) error
 	return nil
 }
 
+func (m *mockUserServiceForIntegration) MuteUser(listenerID, targetID, guildID string) error {
+	return nil
+}
+
+func (m *mockUserServiceForIntegration) UnmuteUser(listenerID, targetID, guildID string) error {
+	return nil
+}
+
+func (m *mockUserServiceForIntegration) GetMutedUsers(listenerID, guildID string) ([]string, error) {
+	return nil, nil
+}
+
 type mockChannelServiceForIntegration struct{}
 
 func (m *mockChannelServiceForIntegration) CreatePairing(guildID, voiceChannelID, textChannelID string) error {
@@ -291,6 +303,22 @@ func (m *mockChannelServiceForIntegration) IsChannelPaired(guildID, textChannelI
 	return false
 }
 
+func (m *mockChannelServiceForIntegration) SetRequirePresence(guildID, voiceChannelID string, required bool) error {
+	return nil
+}
+
+func (m *mockChannelServiceForIntegration) RequiresPresence(guildID, textChannelID string) bool {
+	return false
+}
+
+func (m *mockChannelServiceForIntegration) SetGreeting(guildID, voiceChannelID, greeting string, readPinned bool) error {
+	return nil
+}
+
+func (m *mockChannelServiceForIntegration) SetMirror(guildID, voiceChannelID, mirrorChannelID string, mirrorOnly bool) error {
+	return nil
+}
+
 type mockPermissionServiceForIntegration struct{}
 
 func (m *mockPermissionServiceForIntegration) CanInviteBot(userID, guildID string) (bool, error) {
@@ -301,6 +329,10 @@ func (m *mockPermissionServiceForIntegration) CanControlBot(userID, guildID stri
 	return true, nil
 }
 
+func (m *mockPermissionServiceForIntegration) CanBeRead(userID, guildID string) (bool, error) {
+	return true, nil
+}
+
 func (m *mockPermissionServiceForIntegration) HasChannelAccess(userID, channelID string) (bool, error) {
 	return true, nil
 }
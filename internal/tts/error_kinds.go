@@ -0,0 +1,157 @@
+package tts
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Error kinds. Services wrap the errors they return in one of these, so callers branch
+// with errors.Is instead of matching messages.
+var (
+	ErrPermission   = errors.New("missing permission")
+	ErrRateLimited  = errors.New("rate limited")
+	ErrVoiceGateway = errors.New("voice gateway unavailable")
+	ErrTTSQuota     = errors.New("text-to-speech quota exhausted")
+)
+
+// ErrorKind names the kind of an error in user messages and metrics labels
+type ErrorKind string
+
+// Error kinds, from the most to the least specific
+const (
+	ErrorKindPermission   ErrorKind = "permission"
+	ErrorKindRateLimited  ErrorKind = "rate_limited"
+	ErrorKindVoiceGateway ErrorKind = "voice_gateway"
+	ErrorKindTTSQuota     ErrorKind = "tts_quota"
+	ErrorKindTTS          ErrorKind = "tts"
+	ErrorKindNetwork      ErrorKind = "network"
+	ErrorKindConfig       ErrorKind = "config"
+	ErrorKindUnknown      ErrorKind = "unknown"
+)
+
+// kindError marks an error with its kind without changing its message
+type kindError struct {
+	kind error
+	err  error
+}
+
+func (e *kindError) Error() string {
+	return e.err.Error()
+}
+
+func (e *kindError) Unwrap() []error {
+	return []error{e.err, e.kind}
+}
+
+// withKind marks err with kind, one of ErrPermission, ErrRateLimited, ErrVoiceGateway
+// or ErrTTSQuota. A nil err stays nil.
+func withKind(kind, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &kindError{kind: kind, err: err}
+}
+
+// KindOf classifies an error. Errors marked with a kind are classified by it, then
+// Google Cloud and Discord errors by their status codes, TTS errors by their type and
+// package errors by their sentinel. Anything else is classified by its message.
+func KindOf(err error) ErrorKind {
+	if err == nil {
+		return ErrorKindUnknown
+	}
+
+	switch {
+	case errors.Is(err, ErrPermission):
+		return ErrorKindPermission
+	case errors.Is(err, ErrRateLimited):
+		return ErrorKindRateLimited
+	case errors.Is(err, ErrVoiceGateway):
+		return ErrorKindVoiceGateway
+	case errors.Is(err, ErrTTSQuota):
+		return ErrorKindTTSQuota
+	}
+
+	var grpcErr interface{ GRPCStatus() *status.Status }
+	if errors.As(err, &grpcErr) {
+		switch grpcErr.GRPCStatus().Code() {
+		case codes.ResourceExhausted:
+			return ErrorKindTTSQuota
+		case codes.PermissionDenied, codes.Unauthenticated, codes.InvalidArgument:
+			return ErrorKindConfig
+		case codes.Unavailable, codes.DeadlineExceeded:
+			return ErrorKindNetwork
+		default:
+			return ErrorKindTTS
+		}
+	}
+
+	var rateLimitErr *discordgo.RateLimitError
+	if errors.As(err, &rateLimitErr) {
+		return ErrorKindRateLimited
+	}
+	var restErr *discordgo.RESTError
+	if errors.As(err, &restErr) && restErr.Response != nil {
+		switch restErr.Response.StatusCode {
+		case http.StatusForbidden:
+			return ErrorKindPermission
+		case http.StatusTooManyRequests:
+			return ErrorKindRateLimited
+		}
+	}
+
+	var ttsErr *TTSError
+	if errors.As(err, &ttsErr) {
+		switch ttsErr.Type {
+		case "voice_connection", "voice_recovery":
+			return ErrorKindVoiceGateway
+		case "permission":
+			return ErrorKindPermission
+		case "conversion":
+			return ErrorKindTTS
+		}
+	}
+
+	switch {
+	case errors.Is(err, ErrInvalidConfig), errors.Is(err, ErrInvalidVoiceConfig):
+		return ErrorKindConfig
+	case errors.Is(err, ErrTTSEngineUnavailable), errors.Is(err, ErrAudioConversionFailed), errors.Is(err, ErrOpusUnavailable):
+		return ErrorKindTTS
+	}
+
+	return kindFromMessage(err.Error())
+}
+
+// kindFromMessage classifies an error that carries no kind by its message, for errors
+// from libraries that only describe what went wrong
+func kindFromMessage(message string) ErrorKind {
+	matches := func(patterns ...string) bool {
+		for _, pattern := range patterns {
+			if strings.Contains(message, pattern) {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch {
+	case matches("voice connection", "voice channel"):
+		return ErrorKindVoiceGateway
+	case matches("permission", "access denied"):
+		return ErrorKindPermission
+	case matches("TTS", "text-to-speech"):
+		return ErrorKindTTS
+	case matches("rate limit", "quota"):
+		return ErrorKindRateLimited
+	case matches("timeout", "connection refused"):
+		return ErrorKindNetwork
+	case matches("configuration", "invalid"):
+		return ErrorKindConfig
+	default:
+		return ErrorKindUnknown
+	}
+}
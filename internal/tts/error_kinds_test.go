@@ -0,0 +1,90 @@
+package tts
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestWithKind(t *testing.T) {
+	cause := errors.New("socket closed")
+	err := withKind(ErrVoiceGateway, fmt.Errorf("failed to rejoin voice channel: %w", cause))
+
+	assert.Equal(t, "failed to rejoin voice channel: socket closed", err.Error(), "the message is unchanged")
+	assert.ErrorIs(t, err, ErrVoiceGateway)
+	assert.ErrorIs(t, err, cause)
+	assert.NoError(t, withKind(ErrVoiceGateway, nil))
+
+	wrapped := fmt.Errorf("playback failed: %w", ErrQuotaExceeded)
+	assert.ErrorIs(t, wrapped, ErrTTSQuota)
+	assert.ErrorIs(t, wrapped, ErrQuotaExceeded)
+}
+
+func TestKindOf(t *testing.T) {
+	restError := func(code int) error {
+		return &discordgo.RESTError{Response: &http.Response{StatusCode: code}}
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		want ErrorKind
+	}{
+		{"nil", nil, ErrorKindUnknown},
+		{"marked permission", fmt.Errorf("join: %w", ErrInvalidPermission), ErrorKindPermission},
+		{"marked rate limit", withKind(ErrRateLimited, errors.New("slow down")), ErrorKindRateLimited},
+		{"marked voice gateway", withKind(ErrVoiceGateway, errors.New("socket closed")), ErrorKindVoiceGateway},
+		{"daily budget", ErrQuotaExceeded, ErrorKindTTSQuota},
+		{"marks win over messages", withKind(ErrTTSQuota, errors.New("permission denied")), ErrorKindTTSQuota},
+		{"Google quota", status.Error(codes.ResourceExhausted, "quota exceeded"), ErrorKindTTSQuota},
+		{"Google credentials", fmt.Errorf("synthesis: %w", status.Error(codes.PermissionDenied, "denied")), ErrorKindConfig},
+		{"Google unavailable", status.Error(codes.Unavailable, "try again"), ErrorKindNetwork},
+		{"Google internal", status.Error(codes.Internal, "oops"), ErrorKindTTS},
+		{"Discord forbidden", restError(http.StatusForbidden), ErrorKindPermission},
+		{"Discord too many requests", restError(http.StatusTooManyRequests), ErrorKindRateLimited},
+		{"Discord rate limit", &discordgo.RateLimitError{RateLimit: &discordgo.RateLimit{TooManyRequests: &discordgo.TooManyRequests{}}}, ErrorKindRateLimited},
+		{"voice recovery", NewTTSError("voice_recovery", "automatic reconnection failed", "guild1", "", nil), ErrorKindVoiceGateway},
+		{"conversion", NewTTSError("conversion", "all fallback mechanisms failed", "guild1", "", nil), ErrorKindTTS},
+		{"conversion out of quota", NewTTSError("conversion", "all fallback mechanisms failed", "guild1", "", status.Error(codes.ResourceExhausted, "quota")), ErrorKindTTSQuota},
+		{"invalid voice", fmt.Errorf("voice %q: %w", "xx", ErrInvalidVoiceConfig), ErrorKindConfig},
+		{"no Opus", ErrOpusUnavailable, ErrorKindTTS},
+		{"message only", errors.New("dial tcp: connection refused"), ErrorKindNetwork},
+		{"unknown", errors.New("something odd"), ErrorKindUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, KindOf(tt.err))
+		})
+	}
+}
+
+func TestErrorKinds_RetryAndFatal(t *testing.T) {
+	assert.True(t, IsRetryableError(withKind(ErrRateLimited, errors.New("slow down"))))
+	assert.True(t, IsFatalError(fmt.Errorf("clip: %w", ErrInvalidPermission)))
+	assert.True(t, IsFatalError(ErrQuotaExceeded))
+	assert.False(t, IsFatalError(withKind(ErrVoiceGateway, errors.New("socket closed"))))
+}
+
+func TestErrorRecoveryManager_LocalizedErrorMessage(t *testing.T) {
+	erm := newTestErrorRecoveryManager(newMockVoiceManagerForRecovery(), newMockTTSManagerForRecovery(), &mockMessageQueueForRecovery{}, &mockConfigServiceForRecovery{})
+
+	assert.Equal(t, "The text-to-speech quota is used up for now. Messages will be read again once it resets.",
+		erm.CreateUserFriendlyErrorMessage(ErrQuotaExceeded, "guild1"))
+
+	localizer := createTestLocalizer(t)
+	require.NoError(t, localizer.SetLanguage("guild1", "de"))
+	erm.SetLocalizer(localizer)
+
+	assert.Equal(t, "Mir fehlen die nötigen Berechtigungen für diese Aktion. Prüfe bitte meine Berechtigungen für Sprach- und Textkanäle.",
+		erm.CreateUserFriendlyErrorMessage(fmt.Errorf("join: %w", ErrInvalidPermission), "guild1"))
+	assert.Equal(t, "I'm having trouble connecting to the voice channel. Please try inviting me again, or check that I have the necessary permissions.",
+		erm.CreateUserFriendlyErrorMessage(withKind(ErrVoiceGateway, errors.New("socket closed")), "guild2"), "other guilds keep the default language")
+}
@@ -6,6 +6,8 @@ import (
 	"log"
 	"sync"
 	"time"
+
+	"darrot/internal/events"
 )
 
 // ErrorRecoveryManager handles comprehensive error recovery for TTS operations
@@ -21,10 +23,16 @@ type ErrorRecoveryManager struct {
 	connectionTimeout   time.Duration
 	healthCheckInterval time.Duration
 	fallbackVoice       string
+	breakerThreshold    int
+	breakerCooldown     time.Duration
 
 	// Connection monitoring
 	connectionMonitor *ConnectionMonitor
 	healthChecker     *HealthChecker
+	auditLog          *AuditLog
+	localizer         *Localizer // Translates user-friendly error messages
+	metrics           *Metrics
+	eventBus          *events.Bus // Tells text channels when a guild's breaker opens
 
 	// Error tracking
 	errorStats map[string]*ErrorStats
@@ -46,6 +54,9 @@ type ErrorStats struct {
 	ConsecutiveFailures    int
 	RecoveryAttempts       int
 	LastSuccessfulActivity time.Time
+	ConsecutiveTTSFailures int          // Messages that failed synthesis after every fallback, in a row
+	Breaker                BreakerState // The guild's TTS circuit breaker, empty while closed
+	BreakerOpenUntil       time.Time
 }
 
 // ConnectionMonitor monitors voice connection health
@@ -89,6 +100,8 @@ type ErrorRecoveryConfig struct {
 	ConnectionTimeout   time.Duration
 	HealthCheckInterval time.Duration
 	MonitorInterval     time.Duration
+	BreakerThreshold    int
+	BreakerCooldown     time.Duration
 }
 
 // NewErrorRecoveryManagerWithConfig creates a new error recovery manager with custom configuration
@@ -111,6 +124,12 @@ func NewErrorRecoveryManagerWithConfig(voiceManager VoiceManager, ttsManager TTS
 	if config.MonitorInterval == 0 {
 		config.MonitorInterval = time.Second * 30
 	}
+	if config.BreakerThreshold == 0 {
+		config.BreakerThreshold = DefaultTTSBreakerThreshold
+	}
+	if config.BreakerCooldown == 0 {
+		config.BreakerCooldown = DefaultTTSBreakerCooldown
+	}
 
 	erm := &ErrorRecoveryManager{
 		voiceManager:        voiceManager,
@@ -122,6 +141,8 @@ func NewErrorRecoveryManagerWithConfig(voiceManager VoiceManager, ttsManager TTS
 		connectionTimeout:   config.ConnectionTimeout,
 		healthCheckInterval: config.HealthCheckInterval,
 		fallbackVoice:       DefaultVoice,
+		breakerThreshold:    config.BreakerThreshold,
+		breakerCooldown:     config.BreakerCooldown,
 		errorStats:          make(map[string]*ErrorStats),
 		ctx:                 ctx,
 		cancel:              cancel,
@@ -176,6 +197,31 @@ func (erm *ErrorRecoveryManager) Stop() error {
 	return nil
 }
 
+// SetAuditLog records voice connection recoveries in each guild's audit channel
+func (erm *ErrorRecoveryManager) SetAuditLog(auditLog *AuditLog) {
+	erm.auditLog = auditLog
+}
+
+// SetLocalizer sets the localizer used to translate user-friendly error messages
+func (erm *ErrorRecoveryManager) SetLocalizer(localizer *Localizer) {
+	erm.localizer = localizer
+}
+
+// SetMetrics records the state of each guild's TTS circuit breaker in metrics
+func (erm *ErrorRecoveryManager) SetMetrics(metrics *Metrics) {
+	erm.metrics = metrics
+	if metrics != nil {
+		metrics.Describe(MetricTTSBreakerState, MetricTypeGauge, "State of the guild's TTS circuit breaker: 0 closed, 1 open, 2 half-open")
+		metrics.Describe(MetricTTSBreakerOpened, MetricTypeCounter, "Times the guild's TTS circuit breaker opened")
+		metrics.Describe(MetricTTSBreakerSkipped, MetricTypeCounter, "Messages skipped while the guild's TTS circuit breaker was open")
+	}
+}
+
+// SetEventBus publishes a TTSOutage on bus when a guild's circuit breaker opens
+func (erm *ErrorRecoveryManager) SetEventBus(bus *events.Bus) {
+	erm.eventBus = bus
+}
+
 // HandleVoiceDisconnection handles voice connection failures with automatic recovery
 // Implements requirement 9.1: automatic reconnection logic for voice connections
 func (erm *ErrorRecoveryManager) HandleVoiceDisconnection(guildID string) error {
@@ -243,6 +289,7 @@ func (erm *ErrorRecoveryManager) HandleVoiceDisconnection(guildID string) error
 
 			// Reset error stats on successful recovery
 			erm.resetErrorStats(guildID)
+			erm.auditLog.RecordRecovery(guildID, attempt, nil)
 			return nil
 		}
 
@@ -258,7 +305,7 @@ func (erm *ErrorRecoveryManager) HandleVoiceDisconnection(guildID string) error
 	}
 
 	// All recovery attempts failed
-	err := fmt.Errorf("failed to recover voice connection after %d attempts", erm.maxRetries)
+	err := withKind(ErrVoiceGateway, fmt.Errorf("failed to recover voice connection after %d attempts", erm.maxRetries))
 	log.Printf("Voice connection recovery failed for guild %s: %v", guildID, err)
 
 	// Mark connection as unhealthy
@@ -269,12 +316,29 @@ func (erm *ErrorRecoveryManager) HandleVoiceDisconnection(guildID string) error
 	}
 	erm.connectionMonitor.mu.Unlock()
 
+	erm.auditLog.RecordRecovery(guildID, erm.maxRetries, err)
 	return NewTTSError("voice_recovery", "automatic reconnection failed", guildID, "", err)
 }
 
 // HandleTTSFailure implements comprehensive fallback mechanisms for TTS failures
 // Implements requirement 9.2: graceful handling of TTS engine failures
 func (erm *ErrorRecoveryManager) HandleTTSFailure(text, voice string, config TTSConfig, guildID string) ([]byte, error) {
+	audioData, err := erm.handleTTSFailure(text, voice, config, guildID)
+	if guildID == "" {
+		return audioData, err
+	}
+
+	// Messages that keep failing open the guild's circuit breaker
+	if err != nil {
+		erm.recordTTSFailure(guildID, err)
+	} else {
+		erm.recordTTSSuccess(guildID)
+	}
+	return audioData, err
+}
+
+// handleTTSFailure tries each fallback in turn until one synthesizes the message
+func (erm *ErrorRecoveryManager) handleTTSFailure(text, voice string, config TTSConfig, guildID string) ([]byte, error) {
 	if guildID == "" {
 		return nil, fmt.Errorf("guild ID cannot be empty")
 	}
@@ -286,11 +350,12 @@ func (erm *ErrorRecoveryManager) HandleTTSFailure(text, voice string, config TTS
 
 	var lastErr error
 
-	// Strategy 1: Retry with original configuration
+	// Strategy 1: Retry with original configuration, backing off between attempts
+	backoff := retryPolicy{maxAttempts: erm.maxRetries, baseDelay: erm.retryDelay, maxDelay: 4 * erm.retryDelay}
 	for attempt := 1; attempt <= erm.maxRetries; attempt++ {
 		if attempt > 1 {
 			log.Printf("TTS retry attempt %d/%d for guild %s", attempt, erm.maxRetries, guildID)
-			time.Sleep(erm.retryDelay)
+			time.Sleep(backoff.delay(attempt - 1))
 		}
 
 		audioData, err := erm.ttsManager.ConvertToSpeech(text, voice, config)
@@ -408,43 +473,9 @@ func (erm *ErrorRecoveryManager) HandleAudioPlaybackFailure(guildID string, audi
 // Implements requirement 9.3: user-friendly error messages for common failure scenarios
 func (erm *ErrorRecoveryManager) CreateUserFriendlyErrorMessage(err error, guildID string) string {
 	if err == nil {
-		return "An unknown error occurred."
-	}
-
-	errorStr := err.Error()
-
-	// Voice connection errors
-	if contains(errorStr, "voice connection") || contains(errorStr, "voice channel") {
-		return "I'm having trouble connecting to the voice channel. Please try inviting me again, or check that I have the necessary permissions."
-	}
-
-	// Permission errors
-	if contains(errorStr, "permission") || contains(errorStr, "access denied") {
-		return "I don't have the necessary permissions to perform this action. Please check that I have voice channel and text channel permissions."
-	}
-
-	// TTS engine errors
-	if contains(errorStr, "TTS") || contains(errorStr, "text-to-speech") {
-		return "I'm having trouble converting text to speech right now. I'll keep trying, but some messages might be skipped."
-	}
-
-	// Rate limiting errors
-	if contains(errorStr, "rate limit") || contains(errorStr, "quota") {
-		return "I'm being rate limited by the text-to-speech service. Please wait a moment and try again."
+		return erm.localizer.T(guildID, "errors.none")
 	}
-
-	// Network errors
-	if contains(errorStr, "timeout") || contains(errorStr, "connection refused") {
-		return "I'm having network connectivity issues. I'll keep trying to reconnect automatically."
-	}
-
-	// Configuration errors
-	if contains(errorStr, "configuration") || contains(errorStr, "invalid") {
-		return "There's an issue with the TTS configuration. Please check your settings or contact an administrator."
-	}
-
-	// Generic fallback
-	return "I encountered an error, but I'll keep trying to work normally. If problems persist, please try restarting the TTS session."
+	return erm.localizer.T(guildID, "errors."+string(KindOf(err)))
 }
 
 // GetErrorStats returns error statistics for a guild
@@ -463,6 +494,9 @@ func (erm *ErrorRecoveryManager) GetErrorStats(guildID string) *ErrorStats {
 			ConsecutiveFailures:    stats.ConsecutiveFailures,
 			RecoveryAttempts:       stats.RecoveryAttempts,
 			LastSuccessfulActivity: stats.LastSuccessfulActivity,
+			ConsecutiveTTSFailures: stats.ConsecutiveTTSFailures,
+			Breaker:                stats.Breaker,
+			BreakerOpenUntil:       stats.BreakerOpenUntil,
 		}
 	}
 
@@ -635,12 +669,7 @@ func (hc *HealthChecker) start(ctx context.Context, wg *sync.WaitGroup) {
 // performHealthCheck performs comprehensive health checks
 func (hc *HealthChecker) performHealthCheck() {
 	// Test TTS engine
-	_, err := hc.ttsManager.ConvertToSpeech(hc.testText, "", hc.testConfig)
-	if err != nil {
-		log.Printf("TTS health check failed: %v", err)
-	} else {
-		log.Printf("TTS health check passed")
-	}
+	hc.checkTTSEngine()
 
 	// Test voice manager health
 	activeGuilds := hc.voiceManager.GetActiveConnections()
@@ -656,4 +685,43 @@ func (hc *HealthChecker) performHealthCheck() {
 	log.Printf("Voice connections health: %d/%d healthy", healthyConnections, len(activeGuilds))
 }
 
+// checkTTSEngine synthesizes a test phrase, or tries to reconnect when the engine is
+// unavailable so that the bot leaves degraded mode once its credentials work
+func (hc *HealthChecker) checkTTSEngine() {
+	if engineStatus, ok := hc.ttsManager.(TTSEngineStatus); ok && engineStatus.EngineError() != nil {
+		if err := engineStatus.Reconnect(); err != nil {
+			log.Printf("TTS engine still unavailable: %v", err)
+			return
+		}
+		log.Println("TTS engine is available again, leaving degraded mode")
+	}
+
+	_, err := hc.ttsManager.ConvertToSpeech(hc.testText, "", hc.testConfig)
+	if err != nil {
+		log.Printf("TTS health check failed: %v", err)
+	} else {
+		log.Printf("TTS health check passed")
+	}
+
+	hc.checkCredentialExpiry()
+}
+
+// checkCredentialExpiry warns when the TTS credentials have expired or expire within
+// CredentialExpiryWarning, so that they can be rotated before speech stops
+func (hc *HealthChecker) checkCredentialExpiry() {
+	credentialStatus, ok := hc.ttsManager.(TTSCredentialStatus)
+	if !ok {
+		return
+	}
+
+	expiry := credentialStatus.CredentialExpiry()
+	switch {
+	case expiry.IsZero():
+	case !time.Now().Before(expiry):
+		log.Printf("TTS credentials expired at %s, rotate them to restore speech", expiry.Format(time.RFC3339))
+	case time.Until(expiry) < CredentialExpiryWarning:
+		log.Printf("Warning: TTS credentials expire at %s, rotate them before then", expiry.Format(time.RFC3339))
+	}
+}
+
 // Helper functions are defined in tts_errors.go
@@ -236,6 +236,38 @@ func (m *mockConfigServiceForRecovery) GetMaxQueueSize(guildID string) (int, err
 	return 10, nil
 }
 
+func (m *mockConfigServiceForRecovery) SetIgnorePrefixes(guildID string, prefixes []string) error {
+	return nil
+}
+
+func (m *mockConfigServiceForRecovery) GetIgnorePrefixes(guildID string) ([]string, error) {
+	return nil, nil
+}
+
+func (m *mockConfigServiceForRecovery) GetProfiles(guildID string) ([]GuildProfile, error) {
+	return nil, nil
+}
+
+func (m *mockConfigServiceForRecovery) SaveProfile(guildID string, profile GuildProfile) error {
+	return nil
+}
+
+func (m *mockConfigServiceForRecovery) DeleteProfile(guildID, name string) error {
+	return nil
+}
+
+func (m *mockConfigServiceForRecovery) UseProfile(guildID, name string) (*GuildProfile, error) {
+	return nil, nil
+}
+
+func (m *mockConfigServiceForRecovery) ExportGuildConfig(guildID string) (*GuildConfigExport, error) {
+	return nil, nil
+}
+
+func (m *mockConfigServiceForRecovery) ImportGuildConfig(guildID string, export *GuildConfigExport) error {
+	return nil
+}
+
 func (m *mockConfigServiceForRecovery) ValidateConfig(config *GuildTTSConfig) error {
 	return nil
 }
@@ -742,4 +774,59 @@ func TestHealthChecker_TTSHealthCheck(t *testing.T) {
 	}
 }
 
+// degradedTTSManagerForRecovery is a TTS manager that starts without an engine
+type degradedTTSManagerForRecovery struct {
+	*mockTTSManagerForRecovery
+	engineErr      error
+	reconnectErr   error
+	reconnectCalls int
+}
+
+func (m *degradedTTSManagerForRecovery) EngineError() error {
+	return m.engineErr
+}
+
+func (m *degradedTTSManagerForRecovery) Reconnect() error {
+	m.reconnectCalls++
+	if m.reconnectErr != nil {
+		return m.reconnectErr
+	}
+	m.engineErr = nil
+	return nil
+}
+
+func TestHealthChecker_ReconnectsDegradedEngine(t *testing.T) {
+	mockTTS := &degradedTTSManagerForRecovery{
+		mockTTSManagerForRecovery: newMockTTSManagerForRecovery(),
+		engineErr:                 ErrTTSEngineUnavailable,
+		reconnectErr:              errors.New("no credentials"),
+	}
+	erm := newTestErrorRecoveryManager(newMockVoiceManagerForRecovery(), mockTTS, &mockMessageQueueForRecovery{}, &mockConfigServiceForRecovery{})
+
+	// Without credentials the engine stays degraded and nothing is synthesized
+	erm.healthChecker.performHealthCheck()
+	if mockTTS.reconnectCalls != 1 {
+		t.Errorf("Expected 1 reconnect attempt, got %d", mockTTS.reconnectCalls)
+	}
+	if len(mockTTS.conversionCalls) != 0 {
+		t.Errorf("Expected no conversion while the engine is unavailable, got %d", len(mockTTS.conversionCalls))
+	}
+
+	// Once credentials work the engine is checked as usual
+	mockTTS.reconnectErr = nil
+	erm.healthChecker.performHealthCheck()
+	if mockTTS.EngineError() != nil {
+		t.Errorf("Expected the engine to be available after reconnecting")
+	}
+	if len(mockTTS.conversionCalls) != 1 {
+		t.Errorf("Expected the health check to convert after reconnecting, got %d conversions", len(mockTTS.conversionCalls))
+	}
+
+	// An available engine is not reconnected
+	erm.healthChecker.performHealthCheck()
+	if mockTTS.reconnectCalls != 2 {
+		t.Errorf("Expected no further reconnect attempts, got %d", mockTTS.reconnectCalls)
+	}
+}
+
 // Note: Error classification functions (IsRetryableError, IsFatalError) are tested in tts_errors_test.go
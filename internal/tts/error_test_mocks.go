@@ -375,6 +375,59 @@ func (m *mockChannelServiceError) IsChannelPaired(guildID, textChannelID string)
 	return false
 }
 
+func (m *mockChannelServiceError) SetRequirePresence(guildID, voiceChannelID string, required bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := fmt.Sprintf("%s:%s", guildID, voiceChannelID)
+	pairing, exists := m.pairings[key]
+	if !exists {
+		return fmt.Errorf("no pairing found for guild %s, voice channel %s", guildID, voiceChannelID)
+	}
+	pairing.RequirePresence = required
+	return nil
+}
+
+func (m *mockChannelServiceError) RequiresPresence(guildID, textChannelID string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, pairing := range m.pairings {
+		if pairing.GuildID == guildID && pairing.TextChannelID == textChannelID {
+			return pairing.RequirePresence
+		}
+	}
+	return false
+}
+
+func (m *mockChannelServiceError) SetGreeting(guildID, voiceChannelID, greeting string, readPinned bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := fmt.Sprintf("%s:%s", guildID, voiceChannelID)
+	pairing, exists := m.pairings[key]
+	if !exists {
+		return fmt.Errorf("no pairing found for guild %s, voice channel %s", guildID, voiceChannelID)
+	}
+	pairing.Greeting = greeting
+	pairing.ReadPinned = readPinned
+	return nil
+}
+
+func (m *mockChannelServiceError) SetMirror(guildID, voiceChannelID, mirrorChannelID string, mirrorOnly bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := fmt.Sprintf("%s:%s", guildID, voiceChannelID)
+	pairing, exists := m.pairings[key]
+	if !exists {
+		return fmt.Errorf("no pairing found for guild %s, voice channel %s", guildID, voiceChannelID)
+	}
+	pairing.MirrorChannelID = mirrorChannelID
+	pairing.MirrorOnly = mirrorOnly
+	return nil
+}
+
 // Error simulation methods
 func (m *mockChannelServiceError) setChannelAccessError(userID, channelID string, err error) {
 	m.mu.Lock()
@@ -415,6 +468,10 @@ func (m *mockPermissionServiceError) CanControlBot(userID, guildID string) (bool
 	return m.canControlBot[key], nil
 }
 
+func (m *mockPermissionServiceError) CanBeRead(userID, guildID string) (bool, error) {
+	return true, nil
+}
+
 func (m *mockPermissionServiceError) HasChannelAccess(userID, channelID string) (bool, error) {
 	// Always allow channel access in error tests unless specifically configured
 	return true, nil
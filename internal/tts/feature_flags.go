@@ -0,0 +1,158 @@
+package tts
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Feature is an experimental feature that can be turned on or off per guild
+type Feature string
+
+// Experimental features
+const (
+	FeatureVoiceAutoPause      Feature = "voice_auto_pause"     // Hold the next message while someone in the voice channel is talking
+	FeatureAttachmentNarration Feature = "attachment_narration" // Say which files a message has attached
+	FeatureEmojiReading        Feature = "emoji_reading"        // Read emoji by name instead of leaving them out
+)
+
+// Features lists every experimental feature in display order
+var Features = []Feature{FeatureVoiceAutoPause, FeatureAttachmentNarration, FeatureEmojiReading}
+
+// builtInFeatureDefaults holds the features that are on unless the operator or a guild
+// turns them off. Emoji were always read before they became a feature.
+var builtInFeatureDefaults = map[Feature]bool{
+	FeatureEmojiReading: true,
+}
+
+// Where a guild's feature state comes from
+const (
+	FeatureSourceGuild    = "guild"    // Set by the guild's administrators
+	FeatureSourceOperator = "operator" // Set by the operator with tts.features
+	FeatureSourceDefault  = "default"  // Built-in default
+)
+
+// FeatureState is whether a feature is on in a guild, and who decided
+type FeatureState struct {
+	Feature Feature
+	Enabled bool
+	Source  string
+}
+
+// ParseFeature returns the feature with the given name
+func ParseFeature(name string) (Feature, bool) {
+	for _, feature := range Features {
+		if string(feature) == name {
+			return feature, true
+		}
+	}
+	return "", false
+}
+
+// ParseFeatureDefaults reads the operator's tts.features entries, where a name turns a
+// feature on and a name with a leading "-" turns it off. Unknown names are reported in
+// the error; the known entries are returned either way.
+func ParseFeatureDefaults(entries []string) (map[Feature]bool, error) {
+	defaults := make(map[Feature]bool)
+	var unknown []string
+
+	for _, entry := range entries {
+		name := strings.TrimPrefix(entry, "-")
+		feature, ok := ParseFeature(name)
+		if !ok {
+			unknown = append(unknown, name)
+			continue
+		}
+		defaults[feature] = !strings.HasPrefix(entry, "-")
+	}
+
+	if len(unknown) > 0 {
+		return defaults, fmt.Errorf("unknown features %s (known features: %s)", strings.Join(unknown, ", "), featureNames())
+	}
+	return defaults, nil
+}
+
+// featureNames returns the names of every feature, comma separated
+func featureNames() string {
+	names := make([]string, len(Features))
+	for i, feature := range Features {
+		names[i] = string(feature)
+	}
+	return strings.Join(names, ", ")
+}
+
+// FeatureFlagService decides which experimental features are on in each guild. A guild's
+// administrators override the operator's defaults from tts.features, which override the
+// built-in defaults. A nil service uses the built-in defaults.
+type FeatureFlagService struct {
+	configService ConfigService
+	defaults      map[Feature]bool
+}
+
+// NewFeatureFlagService creates a feature flag service with the operator's defaults
+func NewFeatureFlagService(configService ConfigService, defaults map[Feature]bool) *FeatureFlagService {
+	return &FeatureFlagService{
+		configService: configService,
+		defaults:      defaults,
+	}
+}
+
+// Enabled reports whether a feature is on in a guild
+func (f *FeatureFlagService) Enabled(guildID string, feature Feature) bool {
+	return f.State(guildID, feature).Enabled
+}
+
+// State returns whether a feature is on in a guild and where that comes from
+func (f *FeatureFlagService) State(guildID string, feature Feature) FeatureState {
+	if f == nil {
+		return FeatureState{Feature: feature, Enabled: builtInFeatureDefaults[feature], Source: FeatureSourceDefault}
+	}
+
+	if config, err := f.configService.GetGuildConfig(guildID); err == nil && config != nil {
+		if enabled, ok := config.Features[string(feature)]; ok {
+			return FeatureState{Feature: feature, Enabled: enabled, Source: FeatureSourceGuild}
+		}
+	}
+	if enabled, ok := f.defaults[feature]; ok {
+		return FeatureState{Feature: feature, Enabled: enabled, Source: FeatureSourceOperator}
+	}
+	return FeatureState{Feature: feature, Enabled: builtInFeatureDefaults[feature], Source: FeatureSourceDefault}
+}
+
+// States returns the state of every feature in a guild
+func (f *FeatureFlagService) States(guildID string) []FeatureState {
+	states := make([]FeatureState, len(Features))
+	for i, feature := range Features {
+		states[i] = f.State(guildID, feature)
+	}
+	return states
+}
+
+// Set turns a feature on or off for a guild. A nil value removes the guild's override,
+// so the operator's default applies again.
+func (f *FeatureFlagService) Set(guildID string, feature Feature, enabled *bool) error {
+	config, err := f.configService.GetGuildConfig(guildID)
+	if err != nil {
+		return fmt.Errorf("failed to get guild config: %w", err)
+	}
+	if config == nil {
+		defaultConfig := DefaultGuildTTSConfig(guildID)
+		config = &defaultConfig
+	}
+
+	updated := *config
+	features := make(map[string]bool, len(config.Features)+1)
+	for name, value := range config.Features {
+		features[name] = value
+	}
+	if enabled == nil {
+		delete(features, string(feature))
+	} else {
+		features[string(feature)] = *enabled
+	}
+	if len(features) == 0 {
+		features = nil
+	}
+	updated.Features = features
+
+	return f.configService.SetGuildConfig(guildID, &updated)
+}
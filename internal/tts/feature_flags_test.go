@@ -0,0 +1,84 @@
+package tts
+
+import (
+	"testing"
+
+	"darrot/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createTestFeatureFlags(t *testing.T, defaults map[Feature]bool) (*FeatureFlagService, ConfigService) {
+	storage, err := NewStorageService(t.TempDir())
+	require.NoError(t, err)
+
+	configService := NewConfigService(storage, config.TTSConfig{DefaultVoice: DefaultVoice, DefaultSpeed: 1.0, DefaultVolume: 1.0, MaxQueueSize: 10})
+	return NewFeatureFlagService(configService, defaults), configService
+}
+
+func TestParseFeatureDefaults(t *testing.T) {
+	defaults, err := ParseFeatureDefaults([]string{"voice_auto_pause", "-emoji_reading"})
+	require.NoError(t, err)
+	assert.Equal(t, map[Feature]bool{FeatureVoiceAutoPause: true, FeatureEmojiReading: false}, defaults)
+
+	defaults, err = ParseFeatureDefaults([]string{"attachment_narration", "-text_to_everything"})
+	assert.ErrorContains(t, err, "text_to_everything")
+	assert.Equal(t, map[Feature]bool{FeatureAttachmentNarration: true}, defaults, "known entries are kept")
+}
+
+func TestFeatureFlagService_Precedence(t *testing.T) {
+	features, _ := createTestFeatureFlags(t, map[Feature]bool{FeatureVoiceAutoPause: true})
+
+	// Built-in defaults apply when nobody chose
+	assert.Equal(t, FeatureState{Feature: FeatureEmojiReading, Enabled: true, Source: FeatureSourceDefault}, features.State("guild1", FeatureEmojiReading))
+	assert.False(t, features.Enabled("guild1", FeatureAttachmentNarration))
+
+	// The operator's defaults override them
+	assert.Equal(t, FeatureState{Feature: FeatureVoiceAutoPause, Enabled: true, Source: FeatureSourceOperator}, features.State("guild1", FeatureVoiceAutoPause))
+
+	// And a guild overrides the operator
+	off := false
+	require.NoError(t, features.Set("guild1", FeatureVoiceAutoPause, &off))
+	assert.Equal(t, FeatureState{Feature: FeatureVoiceAutoPause, Enabled: false, Source: FeatureSourceGuild}, features.State("guild1", FeatureVoiceAutoPause))
+	assert.True(t, features.Enabled("guild2", FeatureVoiceAutoPause), "other guilds keep the operator's default")
+
+	// Removing the override follows the operator again
+	require.NoError(t, features.Set("guild1", FeatureVoiceAutoPause, nil))
+	assert.Equal(t, FeatureSourceOperator, features.State("guild1", FeatureVoiceAutoPause).Source)
+}
+
+func TestFeatureFlagService_SetKeepsOtherSettings(t *testing.T) {
+	features, configService := createTestFeatureFlags(t, nil)
+	require.NoError(t, configService.SetIgnorePrefixes("guild1", []string{"!"}))
+
+	on := true
+	require.NoError(t, features.Set("guild1", FeatureAttachmentNarration, &on))
+
+	guildConfig, err := configService.GetGuildConfig("guild1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"!"}, guildConfig.IgnorePrefixes)
+	assert.Equal(t, map[string]bool{"attachment_narration": true}, guildConfig.Features)
+
+	// The last override removed leaves no feature map behind
+	require.NoError(t, features.Set("guild1", FeatureAttachmentNarration, nil))
+	guildConfig, err = configService.GetGuildConfig("guild1")
+	require.NoError(t, err)
+	assert.Nil(t, guildConfig.Features)
+}
+
+func TestFeatureFlagService_States(t *testing.T) {
+	features, _ := createTestFeatureFlags(t, nil)
+
+	states := features.States("guild1")
+	require.Len(t, states, len(Features))
+	for i, feature := range Features {
+		assert.Equal(t, feature, states[i].Feature)
+	}
+}
+
+func TestFeatureFlagService_Nil(t *testing.T) {
+	var features *FeatureFlagService
+	assert.True(t, features.Enabled("guild1", FeatureEmojiReading))
+	assert.False(t, features.Enabled("guild1", FeatureVoiceAutoPause))
+}
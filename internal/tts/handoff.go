@@ -0,0 +1,237 @@
+package tts
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// MaxHandoffAge is how long saved voice sessions stay eligible for resuming. Sessions
+// saved longer ago are dropped instead of rejoining channels that have likely moved on.
+const MaxHandoffAge = 30 * time.Minute
+
+// HandoffMessenger posts messages to text channels
+type HandoffMessenger interface {
+	ChannelMessageSend(channelID, content string, options ...discordgo.RequestOption) (*discordgo.Message, error)
+}
+
+// HandoffManager saves active voice sessions when the bot stops and resumes them when it
+// starts again, so a restart does not require users to run /darrot-join again
+type HandoffManager struct {
+	storage        *StorageService
+	voiceManager   VoiceManager
+	channelService ChannelService
+	ttsProcessor   TTSProcessor
+	messageQueue   MessageQueue   // Nil unless queued messages are carried over
+	contentPolicy  *ContentPolicy // Content-free guilds never have messages written to disk
+	messenger      HandoffMessenger
+	localizer      *Localizer
+	logger         *log.Logger
+}
+
+// NewHandoffManager creates a handoff manager. The messenger may be nil, in which case
+// sessions are resumed silently.
+func NewHandoffManager(
+	storage *StorageService,
+	voiceManager VoiceManager,
+	channelService ChannelService,
+	ttsProcessor TTSProcessor,
+	messenger HandoffMessenger,
+	logger *log.Logger,
+) *HandoffManager {
+	return &HandoffManager{
+		storage:        storage,
+		voiceManager:   voiceManager,
+		channelService: channelService,
+		ttsProcessor:   ttsProcessor,
+		messenger:      messenger,
+		logger:         logger,
+	}
+}
+
+// SetLocalizer sets the localizer used to translate the resume message
+func (h *HandoffManager) SetLocalizer(localizer *Localizer) {
+	h.localizer = localizer
+}
+
+// SetMessageQueue carries the messages still waiting in each guild's queue over to the
+// next start. The queue must be able to list its messages.
+func (h *HandoffManager) SetMessageQueue(messageQueue MessageQueue) {
+	h.messageQueue = messageQueue
+}
+
+// SetContentPolicy keeps the queues of content-free guilds from being saved
+func (h *HandoffManager) SetContentPolicy(policy *ContentPolicy) {
+	h.contentPolicy = policy
+}
+
+// Save records every voice connection that has a channel pairing. It must be called
+// before the voice connections are closed.
+func (h *HandoffManager) Save() error {
+	var sessions []HandoffSession
+	for _, guildID := range h.voiceManager.GetActiveConnections() {
+		connection, exists := h.voiceManager.GetConnection(guildID)
+		if !exists {
+			continue
+		}
+
+		pairing, err := h.channelService.GetPairing(guildID, connection.ChannelID)
+		if err != nil || pairing == nil {
+			continue // Nothing is being read in this channel
+		}
+
+		sessions = append(sessions, HandoffSession{
+			GuildID:         guildID,
+			VoiceChannelID:  pairing.VoiceChannelID,
+			TextChannelID:   pairing.TextChannelID,
+			CreatedBy:       pairing.CreatedBy,
+			RequirePresence: pairing.RequirePresence,
+			Greeting:        pairing.Greeting,
+			ReadPinned:      pairing.ReadPinned,
+			MirrorChannelID: pairing.MirrorChannelID,
+			MirrorOnly:      pairing.MirrorOnly,
+			Queue:           h.queuedMessages(guildID),
+		})
+	}
+
+	if len(sessions) == 0 {
+		return h.storage.ClearVoiceHandoff()
+	}
+
+	h.logger.Printf("Saving %d voice session(s) for handoff", len(sessions))
+	return h.storage.SaveVoiceHandoff(VoiceHandoff{Sessions: sessions})
+}
+
+// queuedMessages returns the messages waiting in a guild's queue, or nil when they are not
+// carried over
+func (h *HandoffManager) queuedMessages(guildID string) []*QueuedMessage {
+	lister, ok := h.messageQueue.(QueueLister)
+	if !ok || h.contentPolicy.ContentFree(guildID) {
+		return nil
+	}
+	return lister.List(guildID)
+}
+
+// Resume rejoins the voice sessions saved by the last shutdown, restarts TTS processing
+// and announces the return in each paired text channel. Saved sessions are consumed even
+// if resuming fails, so a failing channel is not retried on every start. It returns the
+// number of sessions resumed.
+func (h *HandoffManager) Resume() (int, error) {
+	handoff, err := h.storage.LoadVoiceHandoff()
+	if err != nil {
+		return 0, err
+	}
+	if len(handoff.Sessions) == 0 {
+		return 0, nil
+	}
+
+	if err := h.storage.ClearVoiceHandoff(); err != nil {
+		return 0, err
+	}
+
+	if time.Since(handoff.SavedAt) > MaxHandoffAge {
+		h.logger.Printf("Discarding %d voice session(s) saved at %s: older than %s", len(handoff.Sessions), handoff.SavedAt.Format(time.RFC3339), MaxHandoffAge)
+		for _, session := range handoff.Sessions {
+			h.removePairing(session)
+		}
+		return 0, nil
+	}
+
+	resumed := 0
+	for _, session := range handoff.Sessions {
+		if err := h.resumeSession(session); err != nil {
+			h.logger.Printf("Failed to resume voice session in guild %s: %v", session.GuildID, err)
+			h.removePairing(session)
+			continue
+		}
+		resumed++
+	}
+
+	h.logger.Printf("Resumed %d of %d voice session(s)", resumed, len(handoff.Sessions))
+	return resumed, nil
+}
+
+// resumeSession rejoins a single voice session
+func (h *HandoffManager) resumeSession(session HandoffSession) error {
+	if h.voiceManager.IsConnected(session.GuildID) {
+		return nil // Someone already brought the bot back with /darrot-join
+	}
+
+	if _, err := h.voiceManager.JoinChannel(session.GuildID, session.VoiceChannelID); err != nil {
+		return withKind(ErrVoiceGateway, fmt.Errorf("failed to rejoin voice channel: %w", err))
+	}
+
+	// The pairing normally survives the restart; recreate it if it was lost or changed
+	pairing, err := h.channelService.GetPairing(session.GuildID, session.VoiceChannelID)
+	if err != nil || pairing == nil || pairing.TextChannelID != session.TextChannelID {
+		if err == nil && pairing != nil {
+			_ = h.channelService.RemovePairing(session.GuildID, session.VoiceChannelID)
+		}
+		if err := h.channelService.CreatePairingWithCreator(session.GuildID, session.VoiceChannelID, session.TextChannelID, session.CreatedBy); err != nil {
+			_ = h.voiceManager.LeaveChannel(session.GuildID)
+			return fmt.Errorf("failed to restore channel pairing: %w", err)
+		}
+		if session.RequirePresence {
+			if err := h.channelService.SetRequirePresence(session.GuildID, session.VoiceChannelID, true); err != nil {
+				h.logger.Printf("Warning: Failed to restore whisper mode for guild %s: %v", session.GuildID, err)
+			}
+		}
+		// Resumed sessions are not greeted again, but keep the greeting for the next join
+		if session.Greeting != "" || session.ReadPinned {
+			if err := h.channelService.SetGreeting(session.GuildID, session.VoiceChannelID, session.Greeting, session.ReadPinned); err != nil {
+				h.logger.Printf("Warning: Failed to restore greeting for guild %s: %v", session.GuildID, err)
+			}
+		}
+		if session.MirrorChannelID != "" {
+			if err := h.channelService.SetMirror(session.GuildID, session.VoiceChannelID, session.MirrorChannelID, session.MirrorOnly); err != nil {
+				h.logger.Printf("Warning: Failed to restore mirror channel for guild %s: %v", session.GuildID, err)
+			}
+		}
+	}
+
+	if err := h.ttsProcessor.StartGuildProcessing(session.GuildID); err != nil {
+		h.logger.Printf("Warning: Failed to start TTS processing for guild %s: %v", session.GuildID, err)
+	}
+
+	restored := h.restoreQueue(session)
+
+	if h.messenger != nil {
+		message := h.localizer.T(session.GuildID, "handoff.resumed", session.VoiceChannelID)
+		if restored > 0 {
+			message = h.localizer.T(session.GuildID, "handoff.resumed_queue", session.VoiceChannelID, restored)
+		}
+		if _, err := h.messenger.ChannelMessageSend(session.TextChannelID, message); err != nil {
+			h.logger.Printf("Warning: Failed to post resume message in channel %s: %v", session.TextChannelID, err)
+		}
+	}
+
+	return nil
+}
+
+// restoreQueue puts the messages saved with a session back in the guild's queue, returning
+// how many were queued
+func (h *HandoffManager) restoreQueue(session HandoffSession) int {
+	if h.messageQueue == nil {
+		return 0
+	}
+
+	restored := 0
+	for _, message := range session.Queue {
+		if err := h.messageQueue.Enqueue(message); err != nil {
+			h.logger.Printf("Warning: Failed to restore queued message for guild %s: %v", session.GuildID, err)
+			continue
+		}
+		restored++
+	}
+	return restored
+}
+
+// removePairing drops the pairing of a session that will not be resumed, so its text
+// channel is no longer monitored
+func (h *HandoffManager) removePairing(session HandoffSession) {
+	if err := h.channelService.RemovePairing(session.GuildID, session.VoiceChannelID); err != nil {
+		h.logger.Printf("Warning: Failed to remove pairing for guild %s: %v", session.GuildID, err)
+	}
+}
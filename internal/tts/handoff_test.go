@@ -0,0 +1,201 @@
+package tts
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"darrot/internal/config"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeHandoffMessenger records messages posted to text channels
+type fakeHandoffMessenger struct {
+	messages map[string]string
+}
+
+func (m *fakeHandoffMessenger) ChannelMessageSend(channelID, content string, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+	m.messages[channelID] = content
+	return &discordgo.Message{ChannelID: channelID, Content: content}, nil
+}
+
+type handoffTestEnv struct {
+	storage        *StorageService
+	voiceManager   *mockVoiceManager
+	channelService *ChannelServiceImpl
+	messenger      *fakeHandoffMessenger
+}
+
+func (e *handoffTestEnv) newManager() *HandoffManager {
+	return NewHandoffManager(e.storage, e.voiceManager, e.channelService, &mockTTSProcessorForRecovery{}, e.messenger, log.New(os.Stdout, "", 0))
+}
+
+func setupHandoffTest(t *testing.T) *handoffTestEnv {
+	storage, err := NewStorageService(t.TempDir())
+	require.NoError(t, err)
+
+	session := NewMockDiscordSession()
+	session.AddChannel(&discordgo.Channel{ID: "voice1", GuildID: "guild1", Type: discordgo.ChannelTypeGuildVoice})
+	session.AddChannel(&discordgo.Channel{ID: "text1", GuildID: "guild1", Type: discordgo.ChannelTypeGuildText})
+
+	return &handoffTestEnv{
+		storage:        storage,
+		voiceManager:   newMockVoiceManager(),
+		channelService: NewChannelService(storage, session, &MockChannelPermissionService{}),
+		messenger:      &fakeHandoffMessenger{messages: make(map[string]string)},
+	}
+}
+
+func TestHandoffManager_SaveAndResume(t *testing.T) {
+	env := setupHandoffTest(t)
+
+	// Active session before shutdown
+	_, err := env.voiceManager.JoinChannel("guild1", "voice1")
+	require.NoError(t, err)
+	require.NoError(t, env.channelService.CreatePairingWithCreator("guild1", "voice1", "text1", "user1"))
+
+	// A connection without a pairing is not saved
+	_, err = env.voiceManager.JoinChannel("guild2", "voice2")
+	require.NoError(t, err)
+
+	require.NoError(t, env.newManager().Save())
+
+	handoff, err := env.storage.LoadVoiceHandoff()
+	require.NoError(t, err)
+	assert.Equal(t, []HandoffSession{{GuildID: "guild1", VoiceChannelID: "voice1", TextChannelID: "text1", CreatedBy: "user1"}}, handoff.Sessions)
+
+	// Restart with fresh voice connections
+	env.voiceManager = newMockVoiceManager()
+	resumed, err := env.newManager().Resume()
+	require.NoError(t, err)
+	assert.Equal(t, 1, resumed)
+
+	connection, connected := env.voiceManager.GetConnection("guild1")
+	require.True(t, connected)
+	assert.Equal(t, "voice1", connection.ChannelID)
+	assert.True(t, env.channelService.IsChannelPaired("guild1", "text1"))
+	assert.Equal(t, "👋 I'm back! Reading messages from this channel in <#voice1> again.", env.messenger.messages["text1"])
+
+	// Saved sessions are only resumed once
+	resumed, err = env.newManager().Resume()
+	require.NoError(t, err)
+	assert.Equal(t, 0, resumed)
+}
+
+func TestHandoffManager_RecreatesLostPairing(t *testing.T) {
+	env := setupHandoffTest(t)
+	require.NoError(t, env.storage.SaveVoiceHandoff(VoiceHandoff{
+		Sessions: []HandoffSession{{GuildID: "guild1", VoiceChannelID: "voice1", TextChannelID: "text1", CreatedBy: "user1", RequirePresence: true, Greeting: "Welcome back", ReadPinned: true, MirrorChannelID: "mirror1", MirrorOnly: true}},
+	}))
+
+	resumed, err := env.newManager().Resume()
+	require.NoError(t, err)
+	assert.Equal(t, 1, resumed)
+
+	pairing, err := env.channelService.GetPairing("guild1", "voice1")
+	require.NoError(t, err)
+	assert.Equal(t, "text1", pairing.TextChannelID)
+	assert.Equal(t, "user1", pairing.CreatedBy)
+	assert.True(t, pairing.RequirePresence, "whisper mode is restored with the pairing")
+	assert.Equal(t, "Welcome back", pairing.Greeting, "the greeting is restored with the pairing")
+	assert.True(t, pairing.ReadPinned)
+	assert.Equal(t, "mirror1", pairing.MirrorChannelID, "the mirror channel is restored with the pairing")
+	assert.True(t, pairing.MirrorOnly)
+}
+
+func TestHandoffManager_FailedResumeRemovesPairing(t *testing.T) {
+	env := setupHandoffTest(t)
+	require.NoError(t, env.storage.SaveVoiceHandoff(VoiceHandoff{
+		Sessions: []HandoffSession{{GuildID: "guild1", VoiceChannelID: "voice1", TextChannelID: "deleted", CreatedBy: "user1"}},
+	}))
+
+	resumed, err := env.newManager().Resume()
+	require.NoError(t, err)
+	assert.Equal(t, 0, resumed)
+	assert.False(t, env.voiceManager.IsConnected("guild1"), "voice channel should be left when the pairing cannot be restored")
+	assert.Empty(t, env.messenger.messages)
+}
+
+func TestHandoffManager_DiscardsExpiredSessions(t *testing.T) {
+	env := setupHandoffTest(t)
+	require.NoError(t, env.channelService.CreatePairingWithCreator("guild1", "voice1", "text1", "user1"))
+
+	data, err := json.Marshal(VoiceHandoff{
+		Sessions: []HandoffSession{{GuildID: "guild1", VoiceChannelID: "voice1", TextChannelID: "text1"}},
+		SavedAt:  time.Now().Add(-MaxHandoffAge - time.Minute),
+	})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(env.storage.dataDir, "handoff.json"), data, 0600))
+
+	resumed, err := env.newManager().Resume()
+	require.NoError(t, err)
+	assert.Equal(t, 0, resumed)
+	assert.False(t, env.voiceManager.IsConnected("guild1"))
+	assert.False(t, env.channelService.IsChannelPaired("guild1", "text1"))
+}
+
+func TestHandoffManager_CarriesQueueOver(t *testing.T) {
+	env := setupHandoffTest(t)
+	configService := NewConfigService(env.storage, config.TTSConfig{DefaultVoice: DefaultVoice, DefaultSpeed: 1.0, DefaultVolume: 1.0, MaxQueueSize: 10})
+	policy := NewContentPolicy(configService)
+
+	_, err := env.voiceManager.JoinChannel("guild1", "voice1")
+	require.NoError(t, err)
+	require.NoError(t, env.channelService.CreatePairingWithCreator("guild1", "voice1", "text1", "user1"))
+
+	queue := NewMessageQueue()
+	require.NoError(t, queue.Enqueue(&QueuedMessage{ID: "m1", GuildID: "guild1", Username: "Alice", Content: "first"}))
+	require.NoError(t, queue.Enqueue(&QueuedMessage{ID: "m2", GuildID: "guild1", Username: "Bob", Content: "second"}))
+
+	manager := env.newManager()
+	manager.SetMessageQueue(queue)
+	manager.SetContentPolicy(policy)
+	require.NoError(t, manager.Save())
+
+	// Restart with an empty queue
+	env.voiceManager = newMockVoiceManager()
+	restartedQueue := NewMessageQueue()
+	manager = env.newManager()
+	manager.SetMessageQueue(restartedQueue)
+	manager.SetContentPolicy(policy)
+
+	resumed, err := manager.Resume()
+	require.NoError(t, err)
+	assert.Equal(t, 1, resumed)
+	require.Equal(t, 2, restartedQueue.Size("guild1"))
+
+	message, err := restartedQueue.Dequeue("guild1")
+	require.NoError(t, err)
+	assert.Equal(t, "first", message.Content, "messages keep their order")
+	assert.Equal(t, "👋 I'm back! Reading messages from this channel in <#voice1> again, starting with the 2 message(s) that were still queued.", env.messenger.messages["text1"])
+}
+
+func TestHandoffManager_ContentFreeQueueNotSaved(t *testing.T) {
+	env := setupHandoffTest(t)
+	configService := NewConfigService(env.storage, config.TTSConfig{DefaultVoice: DefaultVoice, DefaultSpeed: 1.0, DefaultVolume: 1.0, MaxQueueSize: 10})
+	policy := NewContentPolicy(configService)
+	require.NoError(t, policy.SetMode("guild1", ContentRetentionMetadata))
+
+	_, err := env.voiceManager.JoinChannel("guild1", "voice1")
+	require.NoError(t, err)
+	require.NoError(t, env.channelService.CreatePairingWithCreator("guild1", "voice1", "text1", "user1"))
+
+	queue := NewMessageQueue()
+	require.NoError(t, queue.Enqueue(&QueuedMessage{ID: "m1", GuildID: "guild1", Content: "secret"}))
+
+	manager := env.newManager()
+	manager.SetMessageQueue(queue)
+	manager.SetContentPolicy(policy)
+	require.NoError(t, manager.Save())
+
+	handoff, err := env.storage.LoadVoiceHandoff()
+	require.NoError(t, err)
+	require.Len(t, handoff.Sessions, 1)
+	assert.Empty(t, handoff.Sessions[0].Queue, "message content must not be written to disk for content-free guilds")
+}
@@ -0,0 +1,286 @@
+package tts
+
+import (
+	"log"
+	"strings"
+
+	"darrot/internal/commands/textcmd"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// helpEmbedColor is the accent color of the /darrot-help embed
+const helpEmbedColor = 0x5865F2
+
+// helpAccess is who may run a command listed by /darrot-help
+type helpAccess int
+
+const (
+	helpAccessControl  helpAccess = iota // Members who can control the bot
+	helpAccessInvite                     // Members who can invite the bot
+	helpAccessEveryone                   // Every member
+)
+
+// helpCommandAccess names the commands that need less than control of the bot. Commands
+// not listed are only shown to members who can control it.
+var helpCommandAccess = map[string]helpAccess{
+	"darrot-join":    helpAccessInvite,
+	"darrot-optin":   helpAccessEveryone,
+	"darrot-mute":    helpAccessEveryone,
+	"darrot-unmute":  helpAccessEveryone,
+	"darrot-help":    helpAccessEveryone,
+	"darrot-privacy": helpAccessEveryone,
+}
+
+// HelpCommandHandler handles the command that explains the bot to the member running it:
+// the commands they may use, what the bot is doing in the server, who can control it and
+// what to do next
+type HelpCommandHandler struct {
+	voiceManager      VoiceManager
+	channelService    ChannelService
+	permissionService PermissionService
+	userService       UserService
+	messageQueue      MessageQueue
+	commands          []*discordgo.ApplicationCommand
+	localizer         *Localizer
+	logger            *log.Logger
+}
+
+// NewHelpCommandHandler creates a new help command handler
+func NewHelpCommandHandler(
+	voiceManager VoiceManager,
+	channelService ChannelService,
+	permissionService PermissionService,
+	userService UserService,
+	messageQueue MessageQueue,
+	logger *log.Logger,
+) *HelpCommandHandler {
+	return &HelpCommandHandler{
+		voiceManager:      voiceManager,
+		channelService:    channelService,
+		permissionService: permissionService,
+		userService:       userService,
+		messageQueue:      messageQueue,
+		logger:            logger,
+	}
+}
+
+// SetLocalizer sets the localizer used to translate responses
+func (h *HelpCommandHandler) SetLocalizer(localizer *Localizer) {
+	h.localizer = localizer
+}
+
+// SetCommands sets the commands listed by the help
+func (h *HelpCommandHandler) SetCommands(commands []*discordgo.ApplicationCommand) {
+	h.commands = commands
+}
+
+// Definition returns the Discord slash command definition for the help command
+func (h *HelpCommandHandler) Definition() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:        "darrot-help",
+		Description: "Show the commands you can use, what the bot is doing here and what to do next",
+	}
+}
+
+// Handle processes the help command interaction
+func (h *HelpCommandHandler) Handle(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	// Validate guild context
+	if i.GuildID == "" {
+		return h.respondError(s, i, h.localizer.T(i.GuildID, "common.guild_only"))
+	}
+
+	return textcmd.Respond(s, i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{h.buildEmbed(i.GuildID, i.ChannelID, i.Member.User.ID)},
+			Flags:  discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
+
+// helpContext is what the help knows about the server and the member asking
+type helpContext struct {
+	canInvite  bool
+	canControl bool
+	optedIn    bool
+	canBeRead  bool
+	connection *VoiceConnection
+	pairing    *ChannelPairing
+}
+
+// buildEmbed renders the help for a member running the command in a channel
+func (h *HelpCommandHandler) buildEmbed(guildID, channelID, userID string) *discordgo.MessageEmbed {
+	help := h.gather(guildID, userID)
+
+	return &discordgo.MessageEmbed{
+		Title:       h.localizer.T(guildID, "help.title"),
+		Description: h.localizer.T(guildID, "help.intro") + "\n\n" + h.describeCommands(guildID, help),
+		Color:       helpEmbedColor,
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: h.localizer.T(guildID, "help.status"), Value: h.describeStatus(guildID, help)},
+			{Name: h.localizer.T(guildID, "help.controllers"), Value: h.describeControllers(guildID)},
+			{Name: h.localizer.T(guildID, "help.you"), Value: h.describeMember(guildID, help)},
+			{Name: h.localizer.T(guildID, "help.next"), Value: h.describeNextSteps(guildID, channelID, help)},
+		},
+	}
+}
+
+// gather looks up the member's permissions and the bot's connection. Lookups that fail
+// are logged and treated as not allowed.
+func (h *HelpCommandHandler) gather(guildID, userID string) helpContext {
+	var help helpContext
+	var err error
+
+	if help.canInvite, err = h.permissionService.CanInviteBot(userID, guildID); err != nil {
+		h.logger.Printf("Failed to check invite permission of user %s in guild %s: %v", userID, guildID, err)
+	}
+	if help.canControl, err = h.permissionService.CanControlBot(userID, guildID); err != nil {
+		h.logger.Printf("Failed to check control permission of user %s in guild %s: %v", userID, guildID, err)
+	}
+	if help.optedIn, err = h.userService.IsOptedIn(userID, guildID); err != nil {
+		h.logger.Printf("Failed to check opt-in status of user %s in guild %s: %v", userID, guildID, err)
+	}
+	if help.canBeRead, err = h.permissionService.CanBeRead(userID, guildID); err != nil {
+		h.logger.Printf("Failed to check speaker roles of user %s in guild %s: %v", userID, guildID, err)
+	}
+
+	if connection, exists := h.voiceManager.GetConnection(guildID); exists && connection != nil {
+		help.connection = connection
+		if pairing, err := h.channelService.GetPairing(guildID, connection.ChannelID); err == nil {
+			help.pairing = pairing
+		}
+	}
+
+	return help
+}
+
+// describeCommands lists the commands the member may run with their descriptions in the
+// guild's language
+func (h *HelpCommandHandler) describeCommands(guildID string, help helpContext) string {
+	var lines []string
+	for _, command := range h.commands {
+		access, listed := helpCommandAccess[command.Name]
+		if !listed {
+			access = helpAccessControl
+		}
+		if (access == helpAccessControl && !help.canControl) || (access == helpAccessInvite && !help.canInvite) {
+			continue
+		}
+
+		key := "command." + command.Name + ".description"
+		description := h.localizer.T(guildID, key)
+		if description == key {
+			description = command.Description
+		}
+		lines = append(lines, h.localizer.T(guildID, "help.command", command.Name, description))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// describeStatus tells whether the bot is in a voice channel and what it reads there
+func (h *HelpCommandHandler) describeStatus(guildID string, help helpContext) string {
+	switch {
+	case help.connection == nil:
+		return h.localizer.T(guildID, "help.status.disconnected")
+	case help.pairing == nil:
+		return h.localizer.T(guildID, "help.status.unpaired", help.connection.ChannelID)
+	}
+
+	queued := h.messageQueue.Size(guildID)
+	if h.voiceManager.IsPaused(guildID) {
+		return h.localizer.T(guildID, "help.status.paused", help.connection.ChannelID, help.pairing.TextChannelID, queued)
+	}
+	return h.localizer.T(guildID, "help.status.connected", help.connection.ChannelID, help.pairing.TextChannelID, queued)
+}
+
+// describeControllers tells who may invite and control the bot
+func (h *HelpCommandHandler) describeControllers(guildID string) string {
+	roles, err := h.permissionService.GetRequiredRoles(guildID)
+	if err != nil {
+		h.logger.Printf("Failed to get required roles for guild %s: %v", guildID, err)
+		return h.localizer.T(guildID, "help.controllers.unknown")
+	}
+	if len(roles) == 0 {
+		return h.localizer.T(guildID, "help.controllers.everyone")
+	}
+
+	mentions := make([]string, len(roles))
+	for i, roleID := range roles {
+		mentions[i] = "<@&" + roleID + ">"
+	}
+	return h.localizer.T(guildID, "help.controllers.roles", strings.Join(mentions, ", "))
+}
+
+// describeMember tells the member whether they are read and whether they control the bot
+func (h *HelpCommandHandler) describeMember(guildID string, help helpContext) string {
+	var lines []string
+	switch {
+	case !help.optedIn:
+		lines = append(lines, h.localizer.T(guildID, "help.you.not_opted_in"))
+	case !help.canBeRead:
+		lines = append(lines, h.localizer.T(guildID, "help.you.no_speaker_role"))
+	default:
+		lines = append(lines, h.localizer.T(guildID, "help.you.read"))
+	}
+
+	if help.canControl {
+		lines = append(lines, h.localizer.T(guildID, "help.you.can_control"))
+	} else {
+		lines = append(lines, h.localizer.T(guildID, "help.you.cannot_control"))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// describeNextSteps recommends what the member can do next, most pressing first
+func (h *HelpCommandHandler) describeNextSteps(guildID, channelID string, help helpContext) string {
+	var steps []string
+	if !help.optedIn {
+		steps = append(steps, h.localizer.T(guildID, "help.next.optin"))
+	} else if !help.canBeRead {
+		steps = append(steps, h.localizer.T(guildID, "help.next.speaker_role"))
+	}
+
+	switch {
+	case help.connection == nil && help.canInvite:
+		steps = append(steps, h.localizer.T(guildID, "help.next.join"))
+	case help.connection == nil:
+		steps = append(steps, h.localizer.T(guildID, "help.next.ask_join"))
+	case help.pairing != nil && help.pairing.TextChannelID != channelID:
+		steps = append(steps, h.localizer.T(guildID, "help.next.other_channel", help.pairing.TextChannelID))
+	}
+
+	if help.canControl {
+		steps = append(steps, h.localizer.T(guildID, "help.next.settings"), h.localizer.T(guildID, "help.next.diagnose"))
+	}
+
+	if len(steps) == 0 {
+		return h.localizer.T(guildID, "help.next.ready")
+	}
+	for i, step := range steps {
+		steps[i] = "• " + step
+	}
+	return strings.Join(steps, "\n")
+}
+
+// ValidatePermissions allows every member to get help
+func (h *HelpCommandHandler) ValidatePermissions(userID, guildID string) error {
+	return nil
+}
+
+// ValidateChannelAccess is not needed for help commands but required by interface
+func (h *HelpCommandHandler) ValidateChannelAccess(userID, channelID string) error {
+	return nil // Not applicable for help commands
+}
+
+// Helper methods for response handling
+
+func (h *HelpCommandHandler) respondError(s *discordgo.Session, i *discordgo.InteractionCreate, message string) error {
+	return textcmd.Respond(s, i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: "❌ " + message,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
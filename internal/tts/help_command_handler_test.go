@@ -0,0 +1,126 @@
+package tts
+
+import (
+	"log"
+	"os"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// helpTestCommands are the commands the help lists in the tests
+var helpTestCommands = []*discordgo.ApplicationCommand{
+	{Name: "darrot-join", Description: "Join a voice channel"},
+	{Name: "darrot-optin", Description: "Opt in to TTS"},
+	{Name: "darrot-config", Description: "Configure the bot (Administrator only)"},
+	{Name: "darrot-help", Description: "Show help"},
+}
+
+func createTestHelpHandler() (*HelpCommandHandler, *mockVoiceManager, *MockChannelService, *MockPermissionService, *mockUserService) {
+	voiceManager := newMockVoiceManager()
+	channelService := &MockChannelService{}
+	permissionService := &MockPermissionService{}
+	userService := newMockUserService()
+	logger := log.New(os.Stdout, "[TEST] ", log.LstdFlags)
+
+	handler := NewHelpCommandHandler(voiceManager, channelService, permissionService, userService, NewMessageQueue(), logger)
+	handler.SetCommands(helpTestCommands)
+	return handler, voiceManager, channelService, permissionService, userService
+}
+
+// allowHelp sets what the member may do
+func allowHelp(permissionService *MockPermissionService, canInvite, canControl, canBeRead bool, roles []string) {
+	permissionService.On("CanInviteBot", "user1", "guild1").Return(canInvite, nil)
+	permissionService.On("CanControlBot", "user1", "guild1").Return(canControl, nil)
+	permissionService.On("CanBeRead", "user1", "guild1").Return(canBeRead, nil)
+	permissionService.On("GetRequiredRoles", "guild1").Return(roles, nil)
+}
+
+func TestHelpCommandHandler_Definition(t *testing.T) {
+	handler, _, _, _, _ := createTestHelpHandler()
+
+	definition := handler.Definition()
+
+	assert.Equal(t, "darrot-help", definition.Name)
+	assert.NotEmpty(t, definition.Description)
+	assert.Empty(t, definition.Options)
+	assert.NoError(t, handler.ValidatePermissions("user1", "guild1"))
+}
+
+func TestHelpCommandHandler_NewMember(t *testing.T) {
+	handler, _, _, permissionService, _ := createTestHelpHandler()
+	allowHelp(permissionService, false, false, true, []string{"role1", "role2"})
+
+	embed := handler.buildEmbed("guild1", "text1", "user1")
+
+	// Members who cannot control the bot only see the commands they may run
+	assert.Equal(t, "📖 darrot Help", embed.Title)
+	assert.Contains(t, embed.Description, "`/darrot-optin` — Opt in to TTS")
+	assert.Contains(t, embed.Description, "`/darrot-help`")
+	assert.NotContains(t, embed.Description, "darrot-join")
+	assert.NotContains(t, embed.Description, "darrot-config")
+
+	require.Len(t, embed.Fields, 4)
+	assert.Equal(t, "🔇 Not in a voice channel.", embed.Fields[0].Value)
+	assert.Equal(t, "Administrators and members with <@&role1>, <@&role2>.", embed.Fields[1].Value)
+	assert.Equal(t, "❌ Your messages are not read until you opt in.\nYou cannot control the bot.", embed.Fields[2].Value)
+	assert.Equal(t, "• Run `/darrot-optin` to have your messages read aloud.\n• Ask someone who can control the bot to run `/darrot-join`.", embed.Fields[3].Value)
+}
+
+func TestHelpCommandHandler_Controller(t *testing.T) {
+	handler, voiceManager, channelService, permissionService, userService := createTestHelpHandler()
+	allowHelp(permissionService, true, true, true, []string{})
+	userService.optedInUsers["user1:guild1"] = true
+	voiceManager.connections["guild1"] = &VoiceConnection{GuildID: "guild1", ChannelID: "voice1"}
+	voiceManager.pausedGuilds["guild1"] = true
+	channelService.On("GetPairing", "guild1", "voice1").Return(&ChannelPairing{GuildID: "guild1", VoiceChannelID: "voice1", TextChannelID: "text1"}, nil)
+
+	embed := handler.buildEmbed("guild1", "other", "user1")
+
+	assert.Contains(t, embed.Description, "`/darrot-join`")
+	assert.Contains(t, embed.Description, "`/darrot-config` — Configure the bot (Administrator only)")
+	assert.Equal(t, "⏸️ Paused in <#voice1>, reading <#text1>. 0 message(s) queued.", embed.Fields[0].Value)
+	assert.Equal(t, "Every member can invite and control the bot.", embed.Fields[1].Value)
+	assert.Equal(t, "✅ Your messages are read aloud.\nYou can invite and control the bot.", embed.Fields[2].Value)
+	assert.Contains(t, embed.Fields[3].Value, "write in <#text1> to be heard")
+	assert.Contains(t, embed.Fields[3].Value, "`/darrot-diagnose`")
+}
+
+func TestHelpCommandHandler_ReadyAndSpeakerRoles(t *testing.T) {
+	handler, voiceManager, channelService, permissionService, userService := createTestHelpHandler()
+	allowHelp(permissionService, false, false, true, []string{"role1"})
+	userService.optedInUsers["user1:guild1"] = true
+	voiceManager.connections["guild1"] = &VoiceConnection{GuildID: "guild1", ChannelID: "voice1"}
+	channelService.On("GetPairing", "guild1", "voice1").Return(&ChannelPairing{GuildID: "guild1", VoiceChannelID: "voice1", TextChannelID: "text1"}, nil)
+
+	embed := handler.buildEmbed("guild1", "text1", "user1")
+	assert.Equal(t, "🔊 In <#voice1>, reading <#text1>. 0 message(s) queued.", embed.Fields[0].Value)
+	assert.Equal(t, "You're all set: what you write in the paired text channel is read aloud.", embed.Fields[3].Value)
+
+	// Opted-in members without a speaker role are told how to be read
+	handler, _, _, permissionService, userService = createTestHelpHandler()
+	allowHelp(permissionService, false, false, false, []string{"role1"})
+	userService.optedInUsers["user1:guild1"] = true
+
+	embed = handler.buildEmbed("guild1", "text1", "user1")
+	assert.Contains(t, embed.Fields[2].Value, "only members with a speaker role are read")
+	assert.Contains(t, embed.Fields[3].Value, "Ask an administrator for a speaker role")
+}
+
+func TestHelpCommandHandler_German(t *testing.T) {
+	handler, _, _, permissionService, _ := createTestHelpHandler()
+	allowHelp(permissionService, true, true, true, []string{})
+	localizer := createTestLocalizer(t)
+	require.NoError(t, localizer.SetLanguage("guild1", "de"))
+	handler.SetLocalizer(localizer)
+	handler.SetCommands(append(helpTestCommands, &discordgo.ApplicationCommand{Name: "darrot-custom", Description: "Not translated"}))
+
+	embed := handler.buildEmbed("guild1", "text1", "user1")
+
+	// Command descriptions follow the guild's language where a translation exists
+	assert.Equal(t, "📖 darrot-Hilfe", embed.Title)
+	assert.Contains(t, embed.Description, "`/darrot-help` — Deine Befehle, den Status des Bots hier und die nächsten Schritte anzeigen")
+	assert.Contains(t, embed.Description, "`/darrot-custom` — Not translated")
+}
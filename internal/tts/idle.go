@@ -0,0 +1,35 @@
+package tts
+
+import "time"
+
+// IdleTimeouts holds how long a guild's voice channel may stay silent before the bot
+// says it is still listening and before it leaves. A zero timeout is turned off.
+type IdleTimeouts struct {
+	Announce   time.Duration
+	Disconnect time.Duration
+}
+
+// IdleTimeoutsFor returns the idle timeouts of a guild configuration, filling in the
+// default announcement timeout when it is unset
+func IdleTimeoutsFor(config *GuildTTSConfig) IdleTimeouts {
+	timeouts := IdleTimeouts{Announce: DefaultIdleAnnounceMinutes * time.Minute}
+	if config == nil {
+		return timeouts
+	}
+
+	switch {
+	case config.IdleAnnounceMinutes < 0:
+		timeouts.Announce = 0
+	case config.IdleAnnounceMinutes > 0:
+		timeouts.Announce = time.Duration(config.IdleAnnounceMinutes) * time.Minute
+	}
+	if config.IdleDisconnectMinutes > 0 {
+		timeouts.Disconnect = time.Duration(config.IdleDisconnectMinutes) * time.Minute
+	}
+	return timeouts
+}
+
+// idleMinutes converts a timeout to the whole minutes shown to users, 0 meaning off
+func idleMinutes(timeout time.Duration) int {
+	return int(timeout / time.Minute)
+}
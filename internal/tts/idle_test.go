@@ -0,0 +1,27 @@
+package tts
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdleTimeoutsFor(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   *GuildTTSConfig
+		expected IdleTimeouts
+	}{
+		{"nil config", nil, IdleTimeouts{Announce: 5 * time.Minute}},
+		{"unset", &GuildTTSConfig{}, IdleTimeouts{Announce: 5 * time.Minute}},
+		{"configured", &GuildTTSConfig{IdleAnnounceMinutes: 10, IdleDisconnectMinutes: 60}, IdleTimeouts{Announce: 10 * time.Minute, Disconnect: time.Hour}},
+		{"announcement off", &GuildTTSConfig{IdleAnnounceMinutes: -1, IdleDisconnectMinutes: 15}, IdleTimeouts{Disconnect: 15 * time.Minute}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, IdleTimeoutsFor(tt.config))
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package tts
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"sync"
+)
+
+// InputTag tags messages read from tts.input_path that do not choose their own tag
+const InputTag = "input"
+
+// StdinInputPath is the tts.input_path that reads lines from standard input
+const StdinInputPath = "-"
+
+// InputReader speaks the lines written to a named pipe or standard input in one guild,
+// so scripts and monitoring can pipe alerts into a voice channel without the HTTP API.
+// Each line is plain text or a JSON object in the format of the HTTP API's speak
+// requests. Lines share the API's length limit and rate limit, and are dropped while the
+// bot is not in the guild's voice channel.
+type InputReader struct {
+	path         string
+	guildID      string
+	channelID    string // Empty speaks wherever the bot is in the guild
+	messageQueue MessageQueue
+	voiceManager VoiceManager
+	cooldown     *UserCooldown
+	stdin        io.Reader // Overridable for tests
+	logger       *log.Logger
+
+	mu      sync.Mutex
+	file    *os.File // The named pipe being read
+	stopped bool
+}
+
+// NewInputReader creates a reader speaking the lines of path, a named pipe or
+// StdinInputPath, in a guild. A channelID limits them to that voice channel.
+func NewInputReader(path, guildID, channelID string, messageQueue MessageQueue, voiceManager VoiceManager, logger *log.Logger) *InputReader {
+	return &InputReader{
+		path:         path,
+		guildID:      guildID,
+		channelID:    channelID,
+		messageQueue: messageQueue,
+		voiceManager: voiceManager,
+		cooldown:     NewUserCooldown(),
+		stdin:        os.Stdin,
+		logger:       logger,
+	}
+}
+
+// Start opens the input and reads it in the background. Paths other than named pipes and
+// StdinInputPath are refused, since a regular file would be read once and never again.
+func (r *InputReader) Start() error {
+	input := r.stdin
+	if r.path != StdinInputPath {
+		info, err := os.Stat(r.path)
+		if err != nil {
+			return fmt.Errorf("failed to open input: %w", err)
+		}
+		if info.Mode()&os.ModeNamedPipe == 0 {
+			return fmt.Errorf("input %s is not a named pipe; create one with mkfifo", r.path)
+		}
+
+		// Opened for writing too, so opening does not wait for a writer and the pipe
+		// stays open between writers instead of ending when the first one closes it
+		file, err := os.OpenFile(r.path, os.O_RDWR, 0)
+		if err != nil {
+			return fmt.Errorf("failed to open input: %w", err)
+		}
+		r.mu.Lock()
+		r.file = file
+		r.mu.Unlock()
+		input = file
+	}
+
+	go r.readLines(input)
+	r.logger.Printf("Speaking lines from %s in guild %s", r.describePath(), r.guildID)
+	return nil
+}
+
+// Stop stops reading the input. Standard input is left open, so a line being read from it
+// is dropped.
+func (r *InputReader) Stop() {
+	r.mu.Lock()
+	file := r.file
+	r.file = nil
+	r.stopped = true
+	r.mu.Unlock()
+
+	if file != nil {
+		file.Close()
+	}
+}
+
+// readLines speaks each line of input until it ends or the reader stops
+func (r *InputReader) readLines(input io.Reader) {
+	reader := bufio.NewReaderSize(input, maxAPIRequestBytes)
+	for {
+		line, err := reader.ReadSlice('\n')
+		if errors.Is(err, bufio.ErrBufferFull) {
+			r.logger.Printf("Dropped a line from %s longer than %d bytes", r.describePath(), maxAPIRequestBytes)
+			for errors.Is(err, bufio.ErrBufferFull) {
+				_, err = reader.ReadSlice('\n')
+			}
+		} else if !r.isStopped() {
+			if speakErr := r.Speak(string(line)); speakErr != nil {
+				r.logger.Printf("Dropped a line from %s: %v", r.describePath(), speakErr)
+			}
+		}
+
+		if err != nil {
+			switch {
+			case r.isStopped():
+			case errors.Is(err, io.EOF):
+				r.logger.Printf("Reached the end of %s", r.describePath())
+			default:
+				r.logger.Printf("Stopped reading %s: %v", r.describePath(), err)
+			}
+			return
+		}
+	}
+}
+
+// Speak queues one line of input. Empty lines are skipped.
+func (r *InputReader) Speak(line string) error {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return nil
+	}
+
+	request := SpeakRequest{Text: line}
+	if strings.HasPrefix(line, "{") {
+		request = SpeakRequest{}
+		if err := json.Unmarshal([]byte(line), &request); err != nil {
+			return fmt.Errorf("invalid JSON line: %w", err)
+		}
+	}
+
+	message, err := newSpeakMessage(r.guildID, InputTag, InputTag, "", request)
+	if err != nil {
+		return err
+	}
+
+	connection, connected := r.voiceManager.GetConnection(r.guildID)
+	if !connected || connection == nil {
+		return fmt.Errorf("the bot is not in a voice channel in guild %s", r.guildID)
+	}
+	if r.channelID != "" && connection.ChannelID != r.channelID {
+		return fmt.Errorf("the bot is not in voice channel %s", r.channelID)
+	}
+
+	if !r.cooldown.Allow(r.guildID, "input", APIRequestsPerMinute) {
+		return fmt.Errorf("at most %d lines are read per minute", APIRequestsPerMinute)
+	}
+
+	if err := r.messageQueue.Enqueue(message); err != nil {
+		return fmt.Errorf("failed to queue line: %w", err)
+	}
+	return nil
+}
+
+// isStopped reports whether Stop was called
+func (r *InputReader) isStopped() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.stopped
+}
+
+// describePath names the input in log messages
+func (r *InputReader) describePath() string {
+	if r.path == StdinInputPath {
+		return "standard input"
+	}
+	return r.path
+}
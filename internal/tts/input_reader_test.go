@@ -0,0 +1,109 @@
+package tts
+
+import (
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupInputReaderTest(t *testing.T, channelID string) (*InputReader, MessageQueue) {
+	t.Helper()
+
+	voiceManager := newMockVoiceManager()
+	_, err := voiceManager.JoinChannel("guild1", "voice1")
+	require.NoError(t, err)
+
+	queue := NewMessageQueue()
+	require.NoError(t, queue.SetMaxSize("guild1", 100))
+	reader := NewInputReader(StdinInputPath, "guild1", channelID, queue, voiceManager, log.New(io.Discard, "", 0))
+	t.Cleanup(reader.Stop)
+	return reader, queue
+}
+
+func TestInputReader_SpeaksLines(t *testing.T) {
+	reader, queue := setupInputReaderTest(t, "")
+
+	reader.readLines(strings.NewReader("Backup finished\n\n" + `{"text": "Disk almost full", "author": "Monitoring", "tag": "alerts"}` + "\nNo newline at the end"))
+
+	require.Equal(t, 3, queue.Size("guild1"))
+	first, err := queue.Dequeue("guild1")
+	require.NoError(t, err)
+	assert.Equal(t, "Backup finished", first.Content)
+	assert.Equal(t, InputTag, first.Tag)
+	assert.Equal(t, InputTag, first.Username)
+
+	second, err := queue.Dequeue("guild1")
+	require.NoError(t, err)
+	assert.Equal(t, "Monitoring says: Disk almost full", second.Content)
+	assert.Equal(t, "alerts", second.Tag)
+
+	third, err := queue.Dequeue("guild1")
+	require.NoError(t, err)
+	assert.Equal(t, "No newline at the end", third.Content)
+}
+
+func TestInputReader_DropsLongLines(t *testing.T) {
+	reader, queue := setupInputReaderTest(t, "")
+
+	reader.readLines(strings.NewReader(strings.Repeat("a", maxAPIRequestBytes*2) + "\nShort line\n"))
+
+	require.Equal(t, 1, queue.Size("guild1"))
+	message, err := queue.Dequeue("guild1")
+	require.NoError(t, err)
+	assert.Equal(t, "Short line", message.Content)
+}
+
+func TestInputReader_RejectedLines(t *testing.T) {
+	reader, queue := setupInputReaderTest(t, "")
+
+	assert.ErrorContains(t, reader.Speak(`{"text": `), "invalid JSON line")
+	assert.ErrorContains(t, reader.Speak(`{"author": "Monitoring"}`), "text cannot be empty")
+	assert.ErrorContains(t, reader.Speak(strings.Repeat("a", MaxAPITextLength+1)), "at most")
+	assert.NoError(t, reader.Speak("   "))
+	assert.Equal(t, 0, queue.Size("guild1"))
+}
+
+func TestInputReader_OnlyInVoiceChannel(t *testing.T) {
+	reader, queue := setupInputReaderTest(t, "voice2")
+	assert.ErrorContains(t, reader.Speak("Server restarted"), "not in voice channel voice2")
+
+	reader.guildID = "guild2"
+	assert.ErrorContains(t, reader.Speak("Server restarted"), "not in a voice channel")
+	assert.Equal(t, 0, queue.Size("guild1"))
+}
+
+func TestInputReader_RateLimit(t *testing.T) {
+	reader, queue := setupInputReaderTest(t, "voice1")
+
+	for i := 0; i < APIRequestsPerMinute; i++ {
+		require.NoError(t, reader.Speak("Alert"))
+	}
+	assert.ErrorContains(t, reader.Speak("One too many"), "per minute")
+	assert.Equal(t, APIRequestsPerMinute, queue.Size("guild1"))
+}
+
+func TestInputReader_Start(t *testing.T) {
+	reader, queue := setupInputReaderTest(t, "")
+	reader.stdin = strings.NewReader("From stdin\n")
+
+	require.NoError(t, reader.Start())
+	require.Eventually(t, func() bool { return queue.Size("guild1") == 1 }, time.Second, 5*time.Millisecond)
+}
+
+func TestInputReader_StartRequiresNamedPipe(t *testing.T) {
+	// Regular files would only be read once
+	path := filepath.Join(t.TempDir(), "alerts.txt")
+	require.NoError(t, os.WriteFile(path, []byte("Alert\n"), 0600))
+	reader := NewInputReader(path, "guild1", "", NewMessageQueue(), newMockVoiceManager(), log.New(io.Discard, "", 0))
+	assert.ErrorContains(t, reader.Start(), "not a named pipe")
+
+	reader = NewInputReader(filepath.Join(t.TempDir(), "missing"), "guild1", "", NewMessageQueue(), newMockVoiceManager(), log.New(io.Discard, "", 0))
+	assert.Error(t, reader.Start())
+}
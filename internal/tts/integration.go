@@ -18,80 +18,52 @@ type CommandHandler interface {
 	Definition() *discordgo.ApplicationCommand
 }
 
-// mockConfigServiceForIntegration provides a simple config service for integration
-type mockConfigServiceForIntegration struct{}
-
-func (m *mockConfigServiceForIntegration) GetGuildConfig(guildID string) (*GuildTTSConfig, error) {
-	return nil, nil
-}
-
-func (m *mockConfigServiceForIntegration) SetGuildConfig(guildID string, config *GuildTTSConfig) error {
-	return nil
-}
-
-func (m *mockConfigServiceForIntegration) SetRequiredRoles(guildID string, roleIDs []string) error {
-	return nil
-}
-
-func (m *mockConfigServiceForIntegration) GetRequiredRoles(guildID string) ([]string, error) {
-	return nil, nil
-}
-
-func (m *mockConfigServiceForIntegration) SetTTSSettings(guildID string, settings TTSConfig) error {
-	return nil
-}
-
-func (m *mockConfigServiceForIntegration) GetTTSSettings(guildID string) (*TTSConfig, error) {
-	return nil, nil
-}
-
-func (m *mockConfigServiceForIntegration) SetMaxQueueSize(guildID string, size int) error {
-	return nil
-}
-
-func (m *mockConfigServiceForIntegration) GetMaxQueueSize(guildID string) (int, error) {
-	return 10, nil
-}
-
-func (m *mockConfigServiceForIntegration) ValidateConfig(config *GuildTTSConfig) error {
-	return nil
-}
-
 // TTSCommandIntegration provides methods to integrate TTS command handlers with the bot
 type TTSCommandIntegration struct {
-	joinHandler    *JoinCommandHandler
-	leaveHandler   *LeaveCommandHandler
-	controlHandler *ControlCommandHandler
-	optInHandler   *OptInCommandHandler
-	configHandler  *ConfigCommandHandler
-	logger         *log.Logger
-}
-
-// NewTTSCommandIntegration creates a new TTS command integration instance
-func NewTTSCommandIntegration(
-	session *discordgo.Session,
-	storage *StorageService,
-	voiceManager VoiceManager,
-	ttsProcessor TTSProcessor,
-	logger *log.Logger,
-) (*TTSCommandIntegration, error) {
-	// Create TTS services
-	sessionWrapper := NewDiscordSessionWrapper(session)
-	permissionService := NewPermissionService(sessionWrapper, storage, logger)
-	channelService := NewChannelService(storage, sessionWrapper, permissionService)
-	userService := NewUserService(storage)
-
-	logger.Printf("Using shared voice manager instance: %p", voiceManager)
-
-	// Create message queue and config service (needed for error recovery)
-	messageQueue := NewMessageQueue()
-	configService := &mockConfigServiceForIntegration{}
-
-	// Create TTS manager (needed for error recovery) - using Google Cloud TTS
-	ttsManager, err := NewGoogleTTSManager(messageQueue, "")
-	if err != nil {
-		return nil, err
+	joinHandler       *JoinCommandHandler
+	leaveHandler      *LeaveCommandHandler
+	controlHandler    *ControlCommandHandler
+	optInHandler      *OptInCommandHandler
+	configHandler     *ConfigCommandHandler
+	accessHandler     *AccessCommandHandler
+	profileHandler    *ProfileCommandHandler
+	clipHandler       *ClipCommandHandler
+	playHandler       *PlayCommandHandler
+	moderationHandler *ModerationCommandHandler
+	muteHandler       *MuteCommandHandler
+	unmuteHandler     *MuteCommandHandler
+	statsHandler      *StatsCommandHandler
+	previewHandler    *PreviewCommandHandler
+	debugHandler      *DebugCommandHandler
+	diagnoseHandler   *DiagnoseCommandHandler
+	optInAdminHandler *OptInAdminCommandHandler
+	transcriptHandler *TranscriptCommandHandler
+	apiHandler        *APICommandHandler
+	helpHandler       *HelpCommandHandler
+	privacyHandler    *PrivacyCommandHandler
+	errorRecovery     *ErrorRecoveryManager
+	logger            *log.Logger
+}
+
+// NewTTSCommandIntegration creates a new TTS command integration instance. The command
+// handlers share the services of the TTS system.
+func NewTTSCommandIntegration(services *Services, logger *log.Logger) (*TTSCommandIntegration, error) {
+	if services == nil {
+		return nil, fmt.Errorf("services cannot be nil")
 	}
+	voiceManager := services.Voice
+	channelService := services.Channels
+	permissionService := services.Permissions
+	userService := services.Users
+	configService := services.Config
+	messageQueue := services.Queue
+	ttsManager := services.TTS
+	ttsProcessor := services.Processor
+	clipService := services.Clips
+	moderationService := services.Moderation
+	statsService := services.Stats
+	transcriptService := services.Transcripts
+	apiTokenService := services.APITokens
 
 	// Create error recovery manager
 	errorRecovery := NewErrorRecoveryManager(voiceManager, ttsManager, messageQueue, configService)
@@ -122,11 +94,13 @@ func NewTTSCommandIntegration(
 		permissionService,
 		logger,
 	)
+	controlHandler.SetStatsService(statsService)
 
 	optInHandler := NewOptInCommandHandler(
 		userService,
 		logger,
 	)
+	optInHandler.SetChannelService(channelService)
 
 	configHandler := NewConfigCommandHandler(
 		configService,
@@ -135,15 +109,128 @@ func NewTTSCommandIntegration(
 		messageQueue,
 		logger,
 	)
+	configHandler.SetModerationService(moderationService)
+	accessHandler := NewAccessCommandHandler(configHandler)
+	profileHandler := NewProfileCommandHandler(configHandler)
+
+	clipHandler := NewClipCommandHandler(
+		clipService,
+		permissionService,
+		logger,
+	)
+
+	playHandler := NewPlayCommandHandler(
+		clipService,
+		voiceManager,
+		messageQueue,
+		permissionService,
+		logger,
+	)
+
+	moderationHandler := NewModerationCommandHandler(
+		moderationService,
+		permissionService,
+		logger,
+	)
+
+	muteHandler := NewMuteCommandHandler(userService, logger)
+	unmuteHandler := NewUnmuteCommandHandler(userService, logger)
+
+	statsHandler := NewStatsCommandHandler(
+		statsService,
+		permissionService,
+		logger,
+	)
+
+	previewHandler := NewPreviewCommandHandler(
+		ttsManager,
+		voiceManager,
+		messageQueue,
+		configService,
+		permissionService,
+		logger,
+	)
+
+	debugHandler := NewDebugCommandHandler(
+		voiceManager,
+		permissionService,
+		logger,
+	)
+
+	diagnoseHandler := NewDiagnoseCommandHandler(
+		voiceManager,
+		ttsManager,
+		services.Storage,
+		permissionService,
+		logger,
+	)
+
+	optInAdminHandler := NewOptInAdminCommandHandler(
+		userService,
+		configService,
+		permissionService,
+		logger,
+	)
+
+	transcriptHandler := NewTranscriptCommandHandler(
+		transcriptService,
+		configService,
+		permissionService,
+		logger,
+	)
+
+	apiHandler := NewAPICommandHandler(
+		apiTokenService,
+		ttsManager,
+		permissionService,
+		logger,
+	)
+
+	helpHandler := NewHelpCommandHandler(
+		voiceManager,
+		channelService,
+		permissionService,
+		userService,
+		messageQueue,
+		logger,
+	)
 
-	return &TTSCommandIntegration{
-		joinHandler:    joinHandler,
-		leaveHandler:   leaveHandler,
-		controlHandler: controlHandler,
-		optInHandler:   optInHandler,
-		configHandler:  configHandler,
-		logger:         logger,
-	}, nil
+	privacyHandler := NewPrivacyCommandHandler(services.UserData, logger)
+
+	integration := &TTSCommandIntegration{
+		joinHandler:       joinHandler,
+		leaveHandler:      leaveHandler,
+		controlHandler:    controlHandler,
+		optInHandler:      optInHandler,
+		configHandler:     configHandler,
+		accessHandler:     accessHandler,
+		profileHandler:    profileHandler,
+		clipHandler:       clipHandler,
+		playHandler:       playHandler,
+		moderationHandler: moderationHandler,
+		muteHandler:       muteHandler,
+		unmuteHandler:     unmuteHandler,
+		statsHandler:      statsHandler,
+		previewHandler:    previewHandler,
+		debugHandler:      debugHandler,
+		diagnoseHandler:   diagnoseHandler,
+		optInAdminHandler: optInAdminHandler,
+		transcriptHandler: transcriptHandler,
+		apiHandler:        apiHandler,
+		helpHandler:       helpHandler,
+		privacyHandler:    privacyHandler,
+		errorRecovery:     errorRecovery,
+		logger:            logger,
+	}
+
+	// The help lists every command, itself included
+	var commands []*discordgo.ApplicationCommand
+	for _, handler := range integration.GetCommandHandlers() {
+		commands = append(commands, handler.Definition())
+	}
+	helpHandler.SetCommands(commands)
+
+	return integration, nil
 }
 
 // GetJoinHandler returns the join command handler
@@ -171,6 +258,122 @@ func (t *TTSCommandIntegration) GetConfigHandler() *ConfigCommandHandler {
 	return t.configHandler
 }
 
+// GetAccessHandler returns the access command handler
+func (t *TTSCommandIntegration) GetAccessHandler() *AccessCommandHandler {
+	return t.accessHandler
+}
+
+// GetProfileHandler returns the profile command handler
+func (t *TTSCommandIntegration) GetProfileHandler() *ProfileCommandHandler {
+	return t.profileHandler
+}
+
+// GetClipHandler returns the clip management command handler
+func (t *TTSCommandIntegration) GetClipHandler() *ClipCommandHandler {
+	return t.clipHandler
+}
+
+// GetPlayHandler returns the clip playback command handler
+func (t *TTSCommandIntegration) GetPlayHandler() *PlayCommandHandler {
+	return t.playHandler
+}
+
+// GetModerationHandler returns the moderation command handler
+func (t *TTSCommandIntegration) GetModerationHandler() *ModerationCommandHandler {
+	return t.moderationHandler
+}
+
+// GetMuteHandler returns the mute command handler
+func (t *TTSCommandIntegration) GetMuteHandler() *MuteCommandHandler {
+	return t.muteHandler
+}
+
+// GetUnmuteHandler returns the unmute command handler
+func (t *TTSCommandIntegration) GetUnmuteHandler() *MuteCommandHandler {
+	return t.unmuteHandler
+}
+
+// GetStatsHandler returns the usage statistics command handler
+func (t *TTSCommandIntegration) GetStatsHandler() *StatsCommandHandler {
+	return t.statsHandler
+}
+
+// GetPreviewHandler returns the voice preview command handler
+func (t *TTSCommandIntegration) GetPreviewHandler() *PreviewCommandHandler {
+	return t.previewHandler
+}
+
+// GetDebugHandler returns the debug command handler
+func (t *TTSCommandIntegration) GetDebugHandler() *DebugCommandHandler {
+	return t.debugHandler
+}
+
+// GetDiagnoseHandler returns the diagnose command handler
+func (t *TTSCommandIntegration) GetDiagnoseHandler() *DiagnoseCommandHandler {
+	return t.diagnoseHandler
+}
+
+// GetOptInAdminHandler returns the opt-in administration command handler
+func (t *TTSCommandIntegration) GetOptInAdminHandler() *OptInAdminCommandHandler {
+	return t.optInAdminHandler
+}
+
+// GetTranscriptHandler returns the session transcript command handler
+func (t *TTSCommandIntegration) GetTranscriptHandler() *TranscriptCommandHandler {
+	return t.transcriptHandler
+}
+
+// GetAPIHandler returns the HTTP API token command handler
+func (t *TTSCommandIntegration) GetAPIHandler() *APICommandHandler {
+	return t.apiHandler
+}
+
+// GetHelpHandler returns the help command handler
+func (t *TTSCommandIntegration) GetHelpHandler() *HelpCommandHandler {
+	return t.helpHandler
+}
+
+// GetPrivacyHandler returns the privacy command handler
+func (t *TTSCommandIntegration) GetPrivacyHandler() *PrivacyCommandHandler {
+	return t.privacyHandler
+}
+
+// SetLocalizer sets the localizer used by every command handler to translate responses
+func (t *TTSCommandIntegration) SetLocalizer(localizer *Localizer) {
+	t.joinHandler.SetLocalizer(localizer)
+	t.leaveHandler.SetLocalizer(localizer)
+	t.controlHandler.SetLocalizer(localizer)
+	t.optInHandler.SetLocalizer(localizer)
+	t.configHandler.SetLocalizer(localizer)
+	t.clipHandler.SetLocalizer(localizer)
+	t.playHandler.SetLocalizer(localizer)
+	t.moderationHandler.SetLocalizer(localizer)
+	t.muteHandler.SetLocalizer(localizer)
+	t.unmuteHandler.SetLocalizer(localizer)
+	t.statsHandler.SetLocalizer(localizer)
+	t.previewHandler.SetLocalizer(localizer)
+	t.debugHandler.SetLocalizer(localizer)
+	t.diagnoseHandler.SetLocalizer(localizer)
+	t.optInAdminHandler.SetLocalizer(localizer)
+	t.transcriptHandler.SetLocalizer(localizer)
+	t.apiHandler.SetLocalizer(localizer)
+	t.helpHandler.SetLocalizer(localizer)
+	t.privacyHandler.SetLocalizer(localizer)
+	t.errorRecovery.SetLocalizer(localizer)
+}
+
+// SetAuditLog records joins, leaves, queue clears, configuration changes, opt-outs,
+// transcript settings changes and API tokens by administrators in each guild's audit channel
+func (t *TTSCommandIntegration) SetAuditLog(auditLog *AuditLog) {
+	t.joinHandler.SetAuditLog(auditLog)
+	t.leaveHandler.SetAuditLog(auditLog)
+	t.controlHandler.SetAuditLog(auditLog)
+	t.configHandler.SetAuditLog(auditLog)
+	t.optInAdminHandler.SetAuditLog(auditLog)
+	t.transcriptHandler.SetAuditLog(auditLog)
+	t.apiHandler.SetAuditLog(auditLog)
+}
+
 // GetCommandHandlers returns all TTS command handlers for registration
 func (t *TTSCommandIntegration) GetCommandHandlers() []interface {
 	Handle(s *discordgo.Session, i *discordgo.InteractionCreate) error
@@ -185,6 +388,22 @@ func (t *TTSCommandIntegration) GetCommandHandlers() []interface {
 		t.controlHandler,
 		t.optInHandler,
 		t.configHandler,
+		t.accessHandler,
+		t.profileHandler,
+		t.clipHandler,
+		t.playHandler,
+		t.moderationHandler,
+		t.muteHandler,
+		t.unmuteHandler,
+		t.statsHandler,
+		t.previewHandler,
+		t.debugHandler,
+		t.diagnoseHandler,
+		t.optInAdminHandler,
+		t.transcriptHandler,
+		t.apiHandler,
+		t.helpHandler,
+		t.privacyHandler,
 	}
 }
 
@@ -201,6 +420,22 @@ func (t *TTSCommandIntegration) RegisterWithBot(commandRouter CommandRouter) err
 		{"control", t.controlHandler},
 		{"opt-in", t.optInHandler},
 		{"config", t.configHandler},
+		{"access", t.accessHandler},
+		{"profile", t.profileHandler},
+		{"clip", t.clipHandler},
+		{"play", t.playHandler},
+		{"moderation", t.moderationHandler},
+		{"mute", t.muteHandler},
+		{"unmute", t.unmuteHandler},
+		{"stats", t.statsHandler},
+		{"preview", t.previewHandler},
+		{"debug", t.debugHandler},
+		{"diagnose", t.diagnoseHandler},
+		{"opt-in admin", t.optInAdminHandler},
+		{"transcript", t.transcriptHandler},
+		{"api", t.apiHandler},
+		{"help", t.helpHandler},
+		{"privacy", t.privacyHandler},
 	}
 
 	for _, h := range handlers {
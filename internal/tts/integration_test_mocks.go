@@ -396,6 +396,59 @@ func (m *mockChannelServiceIntegration) IsChannelPaired(guildID, textChannelID s
 	return false
 }
 
+func (m *mockChannelServiceIntegration) SetRequirePresence(guildID, voiceChannelID string, required bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := fmt.Sprintf("%s:%s", guildID, voiceChannelID)
+	pairing, exists := m.pairings[key]
+	if !exists {
+		return fmt.Errorf("no pairing found for guild %s, voice channel %s", guildID, voiceChannelID)
+	}
+	pairing.RequirePresence = required
+	return nil
+}
+
+func (m *mockChannelServiceIntegration) RequiresPresence(guildID, textChannelID string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, pairing := range m.pairings {
+		if pairing.GuildID == guildID && pairing.TextChannelID == textChannelID {
+			return pairing.RequirePresence
+		}
+	}
+	return false
+}
+
+func (m *mockChannelServiceIntegration) SetGreeting(guildID, voiceChannelID, greeting string, readPinned bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := fmt.Sprintf("%s:%s", guildID, voiceChannelID)
+	pairing, exists := m.pairings[key]
+	if !exists {
+		return fmt.Errorf("no pairing found for guild %s, voice channel %s", guildID, voiceChannelID)
+	}
+	pairing.Greeting = greeting
+	pairing.ReadPinned = readPinned
+	return nil
+}
+
+func (m *mockChannelServiceIntegration) SetMirror(guildID, voiceChannelID, mirrorChannelID string, mirrorOnly bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := fmt.Sprintf("%s:%s", guildID, voiceChannelID)
+	pairing, exists := m.pairings[key]
+	if !exists {
+		return fmt.Errorf("no pairing found for guild %s, voice channel %s", guildID, voiceChannelID)
+	}
+	pairing.MirrorChannelID = mirrorChannelID
+	pairing.MirrorOnly = mirrorOnly
+	return nil
+}
+
 // mockPermissionServiceIntegration provides a comprehensive mock for permission management
 type mockPermissionServiceIntegration struct {
 	canInviteBot  map[string]bool     // "userID:guildID" -> canInvite
@@ -428,6 +481,10 @@ func (m *mockPermissionServiceIntegration) CanControlBot(userID, guildID string)
 	return m.canControlBot[key], nil
 }
 
+func (m *mockPermissionServiceIntegration) CanBeRead(userID, guildID string) (bool, error) {
+	return true, nil
+}
+
 func (m *mockPermissionServiceIntegration) HasChannelAccess(userID, channelID string) (bool, error) {
 	// Always allow channel access in integration tests
 	return true, nil
@@ -516,6 +573,18 @@ func (m *mockUserServiceIntegration) AutoOptIn(userID, guildID string) error {
 	return m.SetOptInStatus(userID, guildID, true)
 }
 
+func (m *mockUserServiceIntegration) MuteUser(listenerID, targetID, guildID string) error {
+	return nil
+}
+
+func (m *mockUserServiceIntegration) UnmuteUser(listenerID, targetID, guildID string) error {
+	return nil
+}
+
+func (m *mockUserServiceIntegration) GetMutedUsers(listenerID, guildID string) ([]string, error) {
+	return nil, nil
+}
+
 // mockConfigServiceIntegration provides a comprehensive mock for configuration management
 type mockConfigServiceIntegration struct {
 	configs map[string]*GuildTTSConfig
@@ -615,6 +684,53 @@ func (m *mockConfigServiceIntegration) GetMaxQueueSize(guildID string) (int, err
 	return config.MaxQueueSize, nil
 }
 
+func (m *mockConfigServiceIntegration) SetIgnorePrefixes(guildID string, prefixes []string) error {
+	config, err := m.GetGuildConfig(guildID)
+	if err != nil {
+		return err
+	}
+	config.IgnorePrefixes = prefixes
+	return m.SaveGuildConfig(config)
+}
+
+func (m *mockConfigServiceIntegration) GetIgnorePrefixes(guildID string) ([]string, error) {
+	config, err := m.GetGuildConfig(guildID)
+	if err != nil {
+		return nil, err
+	}
+	return config.IgnorePrefixes, nil
+}
+
+func (m *mockConfigServiceIntegration) GetProfiles(guildID string) ([]GuildProfile, error) {
+	return nil, nil
+}
+
+func (m *mockConfigServiceIntegration) SaveProfile(guildID string, profile GuildProfile) error {
+	return fmt.Errorf("profiles are not supported")
+}
+
+func (m *mockConfigServiceIntegration) DeleteProfile(guildID, name string) error {
+	return ErrProfileNotFound
+}
+
+func (m *mockConfigServiceIntegration) UseProfile(guildID, name string) (*GuildProfile, error) {
+	return nil, ErrProfileNotFound
+}
+
+func (m *mockConfigServiceIntegration) ExportGuildConfig(guildID string) (*GuildConfigExport, error) {
+	config, err := m.GetGuildConfig(guildID)
+	if err != nil {
+		return nil, err
+	}
+	return &GuildConfigExport{Version: ConfigExportVersion, Config: *config}, nil
+}
+
+func (m *mockConfigServiceIntegration) ImportGuildConfig(guildID string, export *GuildConfigExport) error {
+	config := export.Config
+	config.GuildID = guildID
+	return m.SaveGuildConfig(&config)
+}
+
 func (m *mockConfigServiceIntegration) ValidateConfig(config *GuildTTSConfig) error {
 	if config.GuildID == "" {
 		return fmt.Errorf("guild ID cannot be empty")
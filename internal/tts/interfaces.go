@@ -1,5 +1,10 @@
 package tts
 
+import (
+	"context"
+	"time"
+)
+
 // TTSManager handles text-to-speech conversion and audio processing
 type TTSManager interface {
 	ConvertToSpeech(text, voice string, config TTSConfig) ([]byte, error)
@@ -8,12 +13,58 @@ type TTSManager interface {
 	GetSupportedVoices() []Voice
 }
 
+// TTSEngineStatus is implemented by TTS managers that can start without a working engine,
+// such as when no credentials are configured, and connect to it later
+type TTSEngineStatus interface {
+	// EngineError returns why the engine is unavailable, or nil when it is available
+	EngineError() error
+	// Reconnect tries to connect to the engine again when it is unavailable
+	Reconnect() error
+}
+
+// TextNormalizer spells out numbers, times, dates, currencies and abbreviations in the
+// language of a voice, such as "en-US", before synthesis
+type TextNormalizer interface {
+	Normalize(text, languageCode string) string
+}
+
+// NormalizingEngine is implemented by TTS managers whose engine spells out numbers,
+// dates and abbreviations itself, so text is sent to it as written
+type NormalizingEngine interface {
+	NormalizesText() bool
+}
+
+// TTSEngineProbe is implemented by TTS managers that can check whether the engine
+// answers right now
+type TTSEngineProbe interface {
+	// ProbeEngine makes one request to the engine and returns why it failed
+	ProbeEngine(ctx context.Context) error
+}
+
+// TTSCredentialStatus is implemented by TTS managers whose credentials can rotate and
+// expire
+type TTSCredentialStatus interface {
+	// StartCredentialWatch rebuilds the TTS client whenever the credentials rotate
+	StartCredentialWatch() error
+	// StopCredentialWatch stops checking the credentials for rotation
+	StopCredentialWatch()
+	// CredentialExpiry returns when the credentials in use stop working, or the zero time
+	// when that is unknown
+	CredentialExpiry() time.Time
+}
+
+// AudioSink plays a guild's speech, given as DCA audio. The voice manager is the sink
+// every guild plays to; AudioOutputs copies speech to the extra sink a guild chose.
+type AudioSink interface {
+	PlayAudio(guildID string, audioData []byte) error
+}
+
 // VoiceManager manages Discord voice connections and audio streaming
 type VoiceManager interface {
+	AudioSink
 	JoinChannel(guildID, channelID string) (*VoiceConnection, error)
 	LeaveChannel(guildID string) error
 	GetConnection(guildID string) (*VoiceConnection, bool)
-	PlayAudio(guildID string, audioData []byte) error
 	IsConnected(guildID string) bool
 	PausePlayback(guildID string) error
 	ResumePlayback(guildID string) error
@@ -26,6 +77,45 @@ type VoiceManager interface {
 	GetActiveConnections() []string
 }
 
+// ContextSpeechConverter is implemented by TTS managers whose synthesis can be cancelled,
+// such as when the message is skipped before it is spoken
+type ContextSpeechConverter interface {
+	ConvertToSpeechContext(ctx context.Context, text, voice string, config TTSConfig) ([]byte, error)
+}
+
+// SpeechStreamer is implemented by TTS managers that can deliver Opus frames while a
+// message is still being synthesized
+type SpeechStreamer interface {
+	StreamSpeech(ctx context.Context, text, voice string, config TTSConfig, emit func(frame []byte) error) error
+}
+
+// AudioStreamer is implemented by voice managers that can play Opus frames as they are produced
+type AudioStreamer interface {
+	StreamAudio(guildID string, frames <-chan []byte) error
+}
+
+// ContextAudioPlayer is implemented by voice managers that stop sending frames as soon as
+// a context is cancelled, so a skipped message goes silent right away. Playback that is
+// cut short returns ErrPlaybackSkipped.
+type ContextAudioPlayer interface {
+	PlayAudioContext(ctx context.Context, guildID string, audioData []byte) error
+	StreamAudioContext(ctx context.Context, guildID string, frames <-chan []byte) error
+}
+
+// ClipMixer is implemented by voice managers that can keep a clip playing underneath
+// speech, ducking the clip instead of making the speech wait
+type ClipMixer interface {
+	MixClip(guildID string, audioData []byte) error
+}
+
+// VoiceReceiver is implemented by voice managers that can hear the users in the voice
+// channels they join. The bot joins undeafened where listen reports true and passes each
+// user's speech to handle as 16kHz mono PCM frames; a nil frame means the user stopped
+// transmitting.
+type VoiceReceiver interface {
+	SetVoiceReceiver(listen func(guildID string) bool, handle func(guildID, userID string, pcm []int16))
+}
+
 // ChannelService manages voice-text channel pairings and monitoring
 type ChannelService interface {
 	CreatePairing(guildID, voiceChannelID, textChannelID string) error
@@ -34,12 +124,17 @@ type ChannelService interface {
 	GetPairing(guildID, voiceChannelID string) (*ChannelPairing, error)
 	ValidateChannelAccess(userID, channelID string) error
 	IsChannelPaired(guildID, textChannelID string) bool
+	SetRequirePresence(guildID, voiceChannelID string, required bool) error
+	RequiresPresence(guildID, textChannelID string) bool
+	SetGreeting(guildID, voiceChannelID, greeting string, readPinned bool) error
+	SetMirror(guildID, voiceChannelID, mirrorChannelID string, mirrorOnly bool) error
 }
 
 // PermissionService handles role-based access control and user permissions
 type PermissionService interface {
 	CanInviteBot(userID, guildID string) (bool, error)
 	CanControlBot(userID, guildID string) (bool, error)
+	CanBeRead(userID, guildID string) (bool, error)
 	HasChannelAccess(userID, channelID string) (bool, error)
 	SetRequiredRoles(guildID string, roleIDs []string) error
 	GetRequiredRoles(guildID string) ([]string, error)
@@ -51,6 +146,30 @@ type UserService interface {
 	IsOptedIn(userID, guildID string) (bool, error)
 	GetOptedInUsers(guildID string) ([]string, error)
 	AutoOptIn(userID, guildID string) error // For bot inviters
+	MuteUser(listenerID, targetID, guildID string) error
+	UnmuteUser(listenerID, targetID, guildID string) error
+	GetMutedUsers(listenerID, guildID string) ([]string, error)
+}
+
+// VoiceMemberOptIn is implemented by user services that can opt in members of the bot's
+// voice channel who never chose whether their messages are read
+type VoiceMemberOptIn interface {
+	OptInVoiceMember(userID, guildID string) (bool, error)
+}
+
+// ConsentAuditor is implemented by user services that keep an audit trail of who changed
+// a user's opt-in status, when and how
+type ConsentAuditor interface {
+	SetOptInStatusBy(userID, guildID string, optedIn bool, method ConsentMethod, actorID string) error
+	GetConsentHistory(userID, guildID string) ([]ConsentRecord, error)
+}
+
+// ReadChannelAllowlist is implemented by user services that let users limit which paired
+// text channels their messages are read from. An empty allowlist reads every channel.
+type ReadChannelAllowlist interface {
+	AddReadChannel(userID, guildID, channelID string) error
+	RemoveReadChannel(userID, guildID, channelID string) error
+	GetReadChannels(userID, guildID string) ([]string, error)
 }
 
 // MessageQueue handles queuing and processing of text messages for TTS conversion
@@ -63,6 +182,31 @@ type MessageQueue interface {
 	SkipNext(guildID string) (*QueuedMessage, error)
 }
 
+// QueueLister is implemented by message queues that can show the messages waiting in a
+// guild's queue without removing them
+type QueueLister interface {
+	List(guildID string) []*QueuedMessage
+}
+
+// NowPlayingReporter is implemented by TTS processors that can tell which message is
+// being spoken in a guild
+type NowPlayingReporter interface {
+	NowPlaying(guildID string) *QueuedMessage
+}
+
+// ProcessorDrainer is implemented by TTS processors that can stop between messages for a
+// graceful shutdown
+type ProcessorDrainer interface {
+	Drain(ctx context.Context) bool
+	Announce(ctx context.Context, guildID, text string) error
+}
+
+// VoiceStatsReporter is implemented by voice managers that track how smoothly audio is
+// sent on their connections
+type VoiceStatsReporter interface {
+	VoiceStats(guildID string) (*VoiceConnectionStats, bool)
+}
+
 // ConfigService manages guild TTS configuration settings
 type ConfigService interface {
 	GetGuildConfig(guildID string) (*GuildTTSConfig, error)
@@ -73,6 +217,14 @@ type ConfigService interface {
 	GetTTSSettings(guildID string) (*TTSConfig, error)
 	SetMaxQueueSize(guildID string, size int) error
 	GetMaxQueueSize(guildID string) (int, error)
+	SetIgnorePrefixes(guildID string, prefixes []string) error
+	GetIgnorePrefixes(guildID string) ([]string, error)
+	GetProfiles(guildID string) ([]GuildProfile, error)
+	SaveProfile(guildID string, profile GuildProfile) error
+	DeleteProfile(guildID, name string) error
+	UseProfile(guildID, name string) (*GuildProfile, error)
+	ExportGuildConfig(guildID string) (*GuildConfigExport, error)
+	ImportGuildConfig(guildID string, export *GuildConfigExport) error
 	ValidateConfig(config *GuildTTSConfig) error
 }
 
@@ -90,3 +242,62 @@ type TTSProcessor interface {
 	ClearQueue(guildID string) error
 	GetQueueSize(guildID string) int
 }
+
+// TTSQuotaService tracks characters synthesized per guild, day and voice tier and enforces
+// daily budgets
+type TTSQuotaService interface {
+	Reserve(guildID, text string, config TTSConfig) (TTSConfig, error)
+	Release(guildID string, characters int, voice string)
+	RecordUsage(guildID string, characters int, voice string) error
+	GetUsage(guildID string) (*QuotaUsage, error)
+	GetDailyBudget(guildID string) (int, error)
+	SetDailyBudget(guildID string, budget int) error
+	GetPremiumBudget(guildID string) (int, error)
+	SetPremiumBudget(guildID string, budget int) error
+}
+
+// StatsService records per-guild usage analytics for /darrot-stats
+type StatsService interface {
+	RecordMessage(guildID, userID, username string) error
+	RecordCharacters(guildID string, characters int) error
+	RecordAudio(guildID string, played time.Duration) error
+	RecordSkip(guildID string) error
+	GetStats(guildID string) (*GuildStats, error)
+}
+
+// TranscriptService records what is spoken in each voice session for /darrot-transcript
+type TranscriptService interface {
+	StartSession(guildID string) error
+	EndSession(guildID string) error
+	RecordUtterance(guildID string, entry TranscriptEntry) error
+	LatestSession(guildID string) (*TranscriptSession, error)
+	ClearTranscripts(guildID string) error
+}
+
+// APITokenService manages the per-guild tokens that authorize the HTTP API
+type APITokenService interface {
+	CreateToken(guildID, name, voice, createdBy string) (string, *APIToken, error)
+	RevokeToken(guildID, name string) error
+	ListTokens(guildID string) ([]APIToken, error)
+	Authenticate(guildID, secret string) (*APIToken, error)
+}
+
+// AudioClipService manages short named audio clips that can be played through the voice pipeline
+type AudioClipService interface {
+	SaveClip(guildID, name, createdBy string, wavData []byte) (*AudioClip, error)
+	LoadClip(guildID, name string) ([]byte, error)
+	RemoveClip(guildID, name string) error
+	ListClips(guildID string) ([]AudioClip, error)
+}
+
+// ModerationService filters blocked words from messages before TTS conversion
+type ModerationService interface {
+	Moderate(guildID, text string) (*ModerationResult, error)
+	GetSettings(guildID string) (*ModerationSettings, error)
+	SetMode(guildID string, mode ModerationMode) error
+	AddWords(guildID string, words []string) error
+	RemoveWords(guildID string, words []string) error
+	ClearWords(guildID string) error
+	ReplaceSettings(guildID string, settings ModerationSettings) error
+	BleepAudio() ([]byte, error)
+}
@@ -0,0 +1,143 @@
+package tts
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// JoinGreeter speaks the greeting of a pairing when the bot joins its voice channel: the
+// welcome text set with /darrot-join ("Recording night rules: ..."), the most recent
+// pinned message of the text channel, or both. Greetings are read once per join in the
+// low-priority lane like other announcements; resumed voice sessions are not greeted again.
+type JoinGreeter struct {
+	channelService ChannelService
+	messageQueue   MessageQueue
+	configService  ConfigService
+	localizer      *Localizer
+	logger         *log.Logger
+
+	// pinnedMessages returns the pinned messages of a channel, most recently pinned first
+	pinnedMessages func(channelID string) ([]*discordgo.Message, error)
+}
+
+// NewJoinGreeter creates a join greeter that reads pinned messages through the session
+func NewJoinGreeter(
+	channelService ChannelService,
+	messageQueue MessageQueue,
+	configService ConfigService,
+	session *discordgo.Session,
+	logger *log.Logger,
+) *JoinGreeter {
+	greeter := &JoinGreeter{
+		channelService: channelService,
+		messageQueue:   messageQueue,
+		configService:  configService,
+		logger:         logger,
+	}
+	if session != nil {
+		greeter.pinnedMessages = func(channelID string) ([]*discordgo.Message, error) {
+			return session.ChannelMessagesPinned(channelID)
+		}
+	}
+	return greeter
+}
+
+// SetLocalizer sets the localizer used to introduce pinned messages
+func (g *JoinGreeter) SetLocalizer(localizer *Localizer) {
+	g.localizer = localizer
+}
+
+// Greet queues the greeting of the pairing of a voice channel. Pairings without a
+// greeting are left silent.
+func (g *JoinGreeter) Greet(guildID, voiceChannelID string) {
+	if g == nil {
+		return
+	}
+
+	pairing, err := g.channelService.GetPairing(guildID, voiceChannelID)
+	if err != nil || pairing == nil {
+		return
+	}
+
+	var greetings []string
+	if pairing.Greeting != "" {
+		greetings = append(greetings, pairing.Greeting)
+	}
+	if pairing.ReadPinned {
+		if pinned := g.latestPinned(guildID, pairing.TextChannelID); pinned != "" {
+			greetings = append(greetings, pinned)
+		}
+	}
+
+	policy := g.lengthPolicy(guildID)
+	now := time.Now()
+	for index, greeting := range greetings {
+		content := speakEmoji(applyContentModes(greeting, g.contentModes(guildID)), 0)
+		if strings.TrimSpace(content) == "" {
+			continue
+		}
+
+		message := &QueuedMessage{
+			ID:        fmt.Sprintf("greeting-%s-%d-%d", guildID, index, now.UnixNano()),
+			GuildID:   guildID,
+			ChannelID: voiceChannelID,
+			Username:  "greeting",
+			Content:   policy.Apply(content)[0], // Greetings are read in one utterance
+			Priority:  PriorityLow,
+			Timestamp: now,
+		}
+		if err := g.messageQueue.Enqueue(message); err != nil {
+			g.logger.Printf("Failed to queue join greeting for guild %s: %v", guildID, err)
+			return
+		}
+	}
+}
+
+// latestPinned returns the most recent pinned message of a text channel introduced with
+// its author, or "" if the channel has no pinned text
+func (g *JoinGreeter) latestPinned(guildID, textChannelID string) string {
+	if g.pinnedMessages == nil {
+		return ""
+	}
+
+	pinned, err := g.pinnedMessages(textChannelID)
+	if err != nil {
+		g.logger.Printf("Failed to get pinned messages of channel %s in guild %s: %v", textChannelID, guildID, err)
+		return ""
+	}
+
+	for _, message := range pinned {
+		content := strings.TrimSpace(message.ContentWithMentionsReplaced())
+		if content == "" || message.Author == nil {
+			continue // Pinned attachments have nothing to read
+		}
+		return g.localizer.T(guildID, "greeting.pinned", message.Author.Username, content)
+	}
+	return ""
+}
+
+// contentModes returns how links and code blocks are read in a guild
+func (g *JoinGreeter) contentModes(guildID string) ContentModes {
+	config, err := g.configService.GetGuildConfig(guildID)
+	if err != nil {
+		return ContentModesFor(nil)
+	}
+	return ContentModesFor(config)
+}
+
+// lengthPolicy returns how long greetings are cut in a guild
+func (g *JoinGreeter) lengthPolicy(guildID string) LengthPolicy {
+	config, err := g.configService.GetGuildConfig(guildID)
+	if err != nil {
+		return LengthPolicyFor(nil)
+	}
+	policy := LengthPolicyFor(config)
+	if policy.Mode == TruncationModeSplit || policy.Mode == TruncationModeAsk {
+		policy.Mode = TruncationModeSentence
+	}
+	return policy
+}
@@ -0,0 +1,99 @@
+package tts
+
+import (
+	"errors"
+	"io"
+	"log"
+	"testing"
+
+	"darrot/internal/config"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createTestJoinGreeter(t *testing.T) (*JoinGreeter, ChannelService, MessageQueue) {
+	storage, err := NewStorageService(t.TempDir())
+	require.NoError(t, err)
+
+	configService := NewConfigService(storage, config.TTSConfig{DefaultVoice: DefaultVoice, DefaultSpeed: 1.0, DefaultVolume: 1.0, MaxQueueSize: 10})
+	channelService := newMockChannelServiceIntegration()
+	require.NoError(t, channelService.CreatePairing("guild1", "voice1", "text1"))
+	queue := NewMessageQueue()
+
+	greeter := NewJoinGreeter(channelService, queue, configService, nil, log.New(io.Discard, "", 0))
+	greeter.pinnedMessages = func(channelID string) ([]*discordgo.Message, error) {
+		return []*discordgo.Message{
+			{ID: "m3", Author: &discordgo.User{ID: "user3", Username: "carol"}}, // Pinned image without text
+			{ID: "m2", Content: "Be kind <@user2>", Author: &discordgo.User{ID: "user1", Username: "alice"}, Mentions: []*discordgo.User{{ID: "user2", Username: "bob"}}},
+			{ID: "m1", Content: "Older pin", Author: &discordgo.User{ID: "user1", Username: "alice"}},
+		}, nil
+	}
+	return greeter, channelService, queue
+}
+
+func dequeueAll(t *testing.T, queue MessageQueue, guildID string) []*QueuedMessage {
+	t.Helper()
+	var messages []*QueuedMessage
+	for {
+		message, err := queue.Dequeue(guildID)
+		require.NoError(t, err)
+		if message == nil {
+			return messages
+		}
+		messages = append(messages, message)
+	}
+}
+
+func TestJoinGreeter_NoGreeting(t *testing.T) {
+	greeter, _, queue := createTestJoinGreeter(t)
+
+	greeter.Greet("guild1", "voice1")
+	greeter.Greet("guild1", "unpaired")
+	assert.Equal(t, 0, queue.Size("guild1"))
+
+	// Handlers without a greeter stay silent
+	var missing *JoinGreeter
+	missing.Greet("guild1", "voice1")
+}
+
+func TestJoinGreeter_WelcomeTextAndPinnedMessage(t *testing.T) {
+	greeter, channelService, queue := createTestJoinGreeter(t)
+	require.NoError(t, channelService.SetGreeting("guild1", "voice1", "Recording night rules: mute when not talking", true))
+
+	greeter.Greet("guild1", "voice1")
+
+	messages := dequeueAll(t, queue, "guild1")
+	require.Len(t, messages, 2)
+	assert.Equal(t, "Recording night rules: mute when not talking", messages[0].Content)
+	assert.Equal(t, PriorityLow, messages[0].Priority)
+	assert.Equal(t, "Pinned message from alice: Be kind @bob", messages[1].Content, "the latest pinned text is read")
+}
+
+func TestJoinGreeter_PinnedMessageOnly(t *testing.T) {
+	greeter, channelService, queue := createTestJoinGreeter(t)
+	require.NoError(t, channelService.SetGreeting("guild1", "voice1", "", true))
+
+	greeter.pinnedMessages = func(channelID string) ([]*discordgo.Message, error) {
+		return nil, errors.New("missing access")
+	}
+	greeter.Greet("guild1", "voice1")
+	assert.Equal(t, 0, queue.Size("guild1"), "nothing is read when pins cannot be fetched")
+}
+
+func TestJoinGreeter_CutsLongGreetings(t *testing.T) {
+	greeter, channelService, queue := createTestJoinGreeter(t)
+
+	long := "This is a sentence. "
+	for len(long) < 2*DefaultMaxMessageLength {
+		long += long
+	}
+	require.NoError(t, channelService.SetGreeting("guild1", "voice1", long, false))
+
+	greeter.Greet("guild1", "voice1")
+
+	messages := dequeueAll(t, queue, "guild1")
+	require.Len(t, messages, 1)
+	assert.LessOrEqual(t, len(messages[0].Content), DefaultMaxMessageLength)
+}
@@ -0,0 +1,394 @@
+package tts
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+	"sync"
+)
+
+// VoiceCommand is an action users can trigger by speaking in the voice channel
+type VoiceCommand string
+
+const (
+	VoiceCommandSkip   VoiceCommand = "skip"
+	VoiceCommandPause  VoiceCommand = "pause"
+	VoiceCommandResume VoiceCommand = "resume"
+)
+
+// VoiceCommands are the commands the keyword spotter recognizes
+var VoiceCommands = []VoiceCommand{VoiceCommandSkip, VoiceCommandPause, VoiceCommandResume}
+
+// Keyword spotting runs on 16kHz mono audio, split into 25ms frames every 10ms
+const (
+	keywordSampleRate   = 16000
+	keywordFrameSamples = 400
+	keywordHopSamples   = 160
+	keywordFFTSize      = 512
+	keywordMelBands     = 26
+	keywordCepstra      = 12 // Coefficients 1-12; 0 is overall loudness and is dropped
+	keywordPreEmphasis  = 0.97
+)
+
+// Keyword matching thresholds. Distances are the average distance between aligned frames of
+// normalized features: unrelated speech scores 3 to 4.5, the same phrase 1.5 to 2.5 when
+// the voices are of similar pitch.
+const (
+	DefaultKeywordThreshold = 2.5
+	keywordMargin           = 0.9 // The best command must beat every other command by this ratio
+	keywordMaxStretch       = 2.0 // Utterances more than twice as long or short as a template never match
+)
+
+// KeywordSpotter recognizes a small set of spoken commands locally by comparing the
+// features of an utterance with recorded templates of each command using dynamic time
+// warping. No audio leaves the process.
+type KeywordSpotter struct {
+	threshold float64
+	window    []float64
+	melBank   [][]float64
+
+	mu        sync.RWMutex
+	templates []keywordTemplate
+}
+
+// keywordTemplate is the features of one recording of a command
+type keywordTemplate struct {
+	command  VoiceCommand
+	features [][]float64
+}
+
+// NewKeywordSpotter creates a keyword spotter without templates. Utterances match a
+// command when their distance to one of its templates is at most threshold.
+func NewKeywordSpotter(threshold float64) *KeywordSpotter {
+	window := make([]float64, keywordFrameSamples)
+	for i := range window {
+		window[i] = 0.54 - 0.46*math.Cos(2*math.Pi*float64(i)/float64(keywordFrameSamples-1)) // Hamming
+	}
+
+	return &KeywordSpotter{
+		threshold: threshold,
+		window:    window,
+		melBank:   newMelFilterBank(keywordMelBands, keywordFFTSize, keywordSampleRate),
+	}
+}
+
+// AddTemplate adds a recording of a command as 16kHz mono PCM
+func (k *KeywordSpotter) AddTemplate(command VoiceCommand, pcm []int16) error {
+	features := k.features(pcm)
+	if len(features) == 0 {
+		return fmt.Errorf("template for %s is too short", command)
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.templates = append(k.templates, keywordTemplate{command: command, features: features})
+	return nil
+}
+
+// Templates returns the number of templates added
+func (k *KeywordSpotter) Templates() int {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return len(k.templates)
+}
+
+// Match recognizes the command spoken in an utterance of 16kHz mono PCM. It reports false
+// when the utterance is not close enough to any command, or about as close to two.
+func (k *KeywordSpotter) Match(pcm []int16) (VoiceCommand, bool) {
+	features := k.features(pcm)
+	if len(features) == 0 {
+		return "", false
+	}
+
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	// Closest template of each command
+	distances := make(map[VoiceCommand]float64)
+	for _, template := range k.templates {
+		distance := dtwDistance(features, template.features)
+		if best, ok := distances[template.command]; !ok || distance < best {
+			distances[template.command] = distance
+		}
+	}
+
+	var best VoiceCommand
+	bestDistance, runnerUp := math.Inf(1), math.Inf(1)
+	for command, distance := range distances {
+		switch {
+		case distance < bestDistance:
+			best, bestDistance, runnerUp = command, distance, bestDistance
+		case distance < runnerUp:
+			runnerUp = distance
+		}
+	}
+
+	if bestDistance > k.threshold || bestDistance > runnerUp*keywordMargin {
+		return "", false
+	}
+	return best, true
+}
+
+// features computes the mel-frequency cepstral coefficients of each frame, normalized to
+// zero mean and unit variance over the utterance so loudness and microphone do not matter
+func (k *KeywordSpotter) features(pcm []int16) [][]float64 {
+	if len(pcm) < keywordFrameSamples {
+		return nil
+	}
+
+	// Pre-emphasis lifts the high frequencies that carry consonants
+	samples := make([]float64, len(pcm))
+	previous := 0.0
+	for i, sample := range pcm {
+		current := float64(sample) / 32768
+		samples[i] = current - keywordPreEmphasis*previous
+		previous = current
+	}
+
+	frameCount := 1 + (len(samples)-keywordFrameSamples)/keywordHopSamples
+	features := make([][]float64, frameCount)
+	spectrum := make([]complex128, keywordFFTSize)
+	melEnergies := make([]float64, keywordMelBands)
+
+	for f := range features {
+		offset := f * keywordHopSamples
+		for i := range spectrum {
+			spectrum[i] = 0
+			if i < keywordFrameSamples {
+				spectrum[i] = complex(samples[offset+i]*k.window[i], 0)
+			}
+		}
+		fft(spectrum)
+
+		for band, filter := range k.melBank {
+			energy := 0.0
+			for bin, weight := range filter {
+				if weight > 0 {
+					power := cmplx.Abs(spectrum[bin])
+					energy += weight * power * power
+				}
+			}
+			melEnergies[band] = math.Log(energy + 1e-10)
+		}
+
+		// DCT-II of the log mel energies
+		cepstra := make([]float64, keywordCepstra)
+		for c := range cepstra {
+			n := float64(c + 1)
+			for band, energy := range melEnergies {
+				cepstra[c] += energy * math.Cos(math.Pi*n*(float64(band)+0.5)/keywordMelBands)
+			}
+		}
+		features[f] = cepstra
+	}
+
+	normalizeFeatures(features)
+	return features
+}
+
+// normalizeFeatures scales each coefficient to zero mean and unit variance
+func normalizeFeatures(features [][]float64) {
+	for c := 0; c < keywordCepstra; c++ {
+		mean := 0.0
+		for _, frame := range features {
+			mean += frame[c]
+		}
+		mean /= float64(len(features))
+
+		variance := 0.0
+		for _, frame := range features {
+			variance += (frame[c] - mean) * (frame[c] - mean)
+		}
+		deviation := math.Sqrt(variance / float64(len(features)))
+		if deviation < 1e-6 {
+			deviation = 1
+		}
+
+		for _, frame := range features {
+			frame[c] = (frame[c] - mean) / deviation
+		}
+	}
+}
+
+// dtwDistance aligns two feature sequences with dynamic time warping and returns the
+// average distance between aligned frames. Diagonal steps count twice so every path has
+// the same total weight. Sequences whose lengths differ too much are infinitely far apart.
+func dtwDistance(a, b [][]float64) float64 {
+	n, m := len(a), len(b)
+	if n == 0 || m == 0 || float64(n) > keywordMaxStretch*float64(m) || float64(m) > keywordMaxStretch*float64(n) {
+		return math.Inf(1)
+	}
+
+	previous := make([]float64, m+1)
+	current := make([]float64, m+1)
+	for j := range previous {
+		previous[j] = math.Inf(1)
+	}
+	previous[0] = 0
+
+	for i := 1; i <= n; i++ {
+		current[0] = math.Inf(1)
+		for j := 1; j <= m; j++ {
+			d := frameDistance(a[i-1], b[j-1])
+			current[j] = math.Min(previous[j-1]+2*d, math.Min(previous[j]+d, current[j-1]+d))
+		}
+		previous, current = current, previous
+	}
+
+	return previous[m] / float64(n+m)
+}
+
+// frameDistance is the Euclidean distance between two feature frames
+func frameDistance(a, b []float64) float64 {
+	sum := 0.0
+	for i := range a {
+		diff := a[i] - b[i]
+		sum += diff * diff
+	}
+	return math.Sqrt(sum)
+}
+
+// newMelFilterBank creates triangular filters spaced evenly on the mel scale up to the
+// Nyquist frequency, as weights over the bins of an FFT of size fftSize
+func newMelFilterBank(bands, fftSize, sampleRate int) [][]float64 {
+	toMel := func(hz float64) float64 { return 2595 * math.Log10(1+hz/700) }
+	toHz := func(mel float64) float64 { return 700 * (math.Pow(10, mel/2595) - 1) }
+
+	low, high := toMel(64), toMel(float64(sampleRate)/2)
+	bins := make([]float64, bands+2)
+	for i := range bins {
+		hz := toHz(low + (high-low)*float64(i)/float64(bands+1))
+		bins[i] = hz * float64(fftSize) / float64(sampleRate)
+	}
+
+	filters := make([][]float64, bands)
+	for band := range filters {
+		left, center, right := bins[band], bins[band+1], bins[band+2]
+		filter := make([]float64, fftSize/2+1)
+		for bin := range filter {
+			x := float64(bin)
+			switch {
+			case x > left && x <= center:
+				filter[bin] = (x - left) / (center - left)
+			case x > center && x < right:
+				filter[bin] = (right - x) / (right - center)
+			}
+		}
+		filters[band] = filter
+	}
+	return filters
+}
+
+// fft computes the discrete Fourier transform in place. The length must be a power of two.
+func fft(x []complex128) {
+	n := len(x)
+
+	// Bit-reversal permutation
+	for i, j := 1, 0; i < n; i++ {
+		bit := n >> 1
+		for ; j&bit != 0; bit >>= 1 {
+			j ^= bit
+		}
+		j |= bit
+		if i < j {
+			x[i], x[j] = x[j], x[i]
+		}
+	}
+
+	for size := 2; size <= n; size <<= 1 {
+		step := cmplx.Exp(complex(0, -2*math.Pi/float64(size)))
+		for start := 0; start < n; start += size {
+			w := complex(1, 0)
+			for k := 0; k < size/2; k++ {
+				even, odd := x[start+k], w*x[start+k+size/2]
+				x[start+k], x[start+k+size/2] = even+odd, even-odd
+				w *= step
+			}
+		}
+	}
+}
+
+// Utterances are split on silence in 20ms frames
+const (
+	speechRMSThreshold  = 500                        // About -36 dBFS
+	utteranceMinSamples = keywordSampleRate * 3 / 10 // Shorter sounds are not commands
+	utteranceMaxSamples = keywordSampleRate * 5 / 2  // Longer speech is conversation, not a command
+	utteranceHangover   = 15                         // Silent frames that end an utterance
+)
+
+// utteranceSegmenter collects one user's speech into utterances separated by silence
+type utteranceSegmenter struct {
+	samples      []int16
+	speaking     bool
+	overlong     bool // Speech passed utteranceMaxSamples and is dropped until the next silence
+	silentFrames int
+	silentTail   int // Samples of silence at the end of samples
+}
+
+// Add adds a frame of 16kHz mono PCM and returns an utterance when the frame ends one
+func (s *utteranceSegmenter) Add(frame []int16) []int16 {
+	if frameRMS(frame) >= speechRMSThreshold {
+		s.speaking = true
+		s.silentFrames = 0
+		s.silentTail = 0
+		if !s.overlong {
+			s.samples = append(s.samples, frame...)
+			if len(s.samples) > utteranceMaxSamples {
+				s.overlong = true
+				s.samples = s.samples[:0]
+			}
+		}
+		return nil
+	}
+
+	if !s.speaking {
+		return nil
+	}
+	if !s.overlong {
+		s.samples = append(s.samples, frame...)
+		s.silentTail += len(frame)
+	}
+	s.silentFrames++
+	if s.silentFrames < utteranceHangover {
+		return nil
+	}
+	return s.Flush()
+}
+
+// Flush ends the current utterance, such as when the user stops transmitting, and returns
+// it unless it is too short or too long to be a command
+func (s *utteranceSegmenter) Flush() []int16 {
+	utterance := s.samples[:len(s.samples)-s.silentTail]
+	overlong := s.overlong
+	*s = utteranceSegmenter{}
+
+	if overlong || len(utterance) < utteranceMinSamples {
+		return nil
+	}
+	return utterance
+}
+
+// trimSilence removes the silent frames at both ends of 16kHz mono PCM
+func trimSilence(pcm []int16) []int16 {
+	const frame = keywordSampleRate / 50 // 20ms
+	start, end := 0, len(pcm)
+	for start+frame <= end && frameRMS(pcm[start:start+frame]) < speechRMSThreshold {
+		start += frame
+	}
+	for end-frame >= start && frameRMS(pcm[end-frame:end]) < speechRMSThreshold {
+		end -= frame
+	}
+	return pcm[start:end]
+}
+
+// frameRMS returns the root mean square amplitude of a frame
+func frameRMS(frame []int16) float64 {
+	if len(frame) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, sample := range frame {
+		sum += float64(sample) * float64(sample)
+	}
+	return math.Sqrt(sum / float64(len(frame)))
+}
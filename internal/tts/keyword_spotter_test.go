@@ -0,0 +1,213 @@
+package tts
+
+import (
+	"math"
+	"math/cmplx"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// syllable is a voiced sound made of two formants
+type syllable struct {
+	f1, f2   float64 // Formant frequencies in Hz
+	duration float64 // Seconds
+}
+
+// Synthetic phrases standing in for the spoken commands
+var (
+	phraseSkip   = []syllable{{700, 1200, 0.18}, {300, 2300, 0.16}, {500, 1500, 0.12}}
+	phrasePause  = []syllable{{700, 1100, 0.2}, {400, 800, 0.25}}
+	phraseResume = []syllable{{350, 2100, 0.15}, {500, 1700, 0.12}, {320, 900, 0.22}}
+	phraseOther  = []syllable{{600, 1000, 0.3}, {450, 2500, 0.3}, {650, 1400, 0.25}}
+)
+
+// speak renders a phrase as 16kHz PCM in the voice of a speaker, who talks tempo times
+// slower and pitch times higher than the reference speaker, with background noise
+func speak(phrase []syllable, tempo, pitch, noise float64, seed int64) []int16 {
+	rng := rand.New(rand.NewSource(seed))
+	fundamental := 120 * pitch
+
+	var pcm []int16
+	for _, s := range phrase {
+		n := int(s.duration * tempo * keywordSampleRate)
+		for i := 0; i < n; i++ {
+			t := float64(i) / keywordSampleRate
+			envelope := math.Sin(math.Pi * float64(i) / float64(n))
+			sample := 0.0
+			for harmonic := 1; harmonic*int(fundamental) < 4000; harmonic++ {
+				f := float64(harmonic) * fundamental
+				// Harmonics near the formants are loudest
+				gain := math.Exp(-math.Pow((f-s.f1*pitch)/150, 2)) + 0.7*math.Exp(-math.Pow((f-s.f2*pitch)/200, 2)) + 0.02
+				sample += gain * math.Sin(2*math.Pi*f*t)
+			}
+			sample = 6000*envelope*sample + noise*rng.NormFloat64()
+			pcm = append(pcm, int16(math.Max(-32768, math.Min(32767, sample))))
+		}
+	}
+	return pcm
+}
+
+// newTestSpotter creates a spotter with one template per command from the reference speaker
+func newTestSpotter(t *testing.T) *KeywordSpotter {
+	t.Helper()
+	spotter := NewKeywordSpotter(DefaultKeywordThreshold)
+	require.NoError(t, spotter.AddTemplate(VoiceCommandSkip, speak(phraseSkip, 1, 1, 0, 1)))
+	require.NoError(t, spotter.AddTemplate(VoiceCommandPause, speak(phrasePause, 1, 1, 0, 1)))
+	require.NoError(t, spotter.AddTemplate(VoiceCommandResume, speak(phraseResume, 1, 1, 0, 1)))
+	return spotter
+}
+
+func TestKeywordSpotter_MatchesCommandsFromOtherSpeakers(t *testing.T) {
+	spotter := newTestSpotter(t)
+	assert.Equal(t, 3, spotter.Templates())
+
+	speakers := []struct {
+		name         string
+		tempo, pitch float64
+	}{
+		{"reference", 1, 1},
+		{"slower", 1.3, 1},
+		{"faster", 0.8, 1},
+		{"higher voice", 1.1, 1.08},
+	}
+	phrases := map[VoiceCommand][]syllable{
+		VoiceCommandSkip:   phraseSkip,
+		VoiceCommandPause:  phrasePause,
+		VoiceCommandResume: phraseResume,
+	}
+
+	for _, speaker := range speakers {
+		for command, phrase := range phrases {
+			got, ok := spotter.Match(speak(phrase, speaker.tempo, speaker.pitch, 200, 7))
+			assert.True(t, ok, "%s should be recognized from the %s speaker", command, speaker.name)
+			assert.Equal(t, command, got, "%s speaker", speaker.name)
+		}
+	}
+}
+
+func TestKeywordSpotter_RejectsOtherSpeech(t *testing.T) {
+	spotter := newTestSpotter(t)
+
+	_, ok := spotter.Match(speak(phraseOther, 1, 1, 200, 3))
+	assert.False(t, ok, "unrelated speech should not match")
+
+	rng := rand.New(rand.NewSource(5))
+	noise := make([]int16, keywordSampleRate/2)
+	for i := range noise {
+		noise[i] = int16(3000 * rng.NormFloat64())
+	}
+	_, ok = spotter.Match(noise)
+	assert.False(t, ok, "noise should not match")
+
+	_, ok = spotter.Match(make([]int16, 100))
+	assert.False(t, ok, "audio shorter than a frame should not match")
+}
+
+func TestKeywordSpotter_WithoutTemplates(t *testing.T) {
+	spotter := NewKeywordSpotter(DefaultKeywordThreshold)
+
+	_, ok := spotter.Match(speak(phraseSkip, 1, 1, 0, 1))
+	assert.False(t, ok)
+	assert.Error(t, spotter.AddTemplate(VoiceCommandSkip, make([]int16, 10)), "too short for a template")
+}
+
+func TestDTWDistance(t *testing.T) {
+	a := [][]float64{{0, 0}, {1, 1}, {2, 2}}
+	assert.InDelta(t, 0, dtwDistance(a, a), 1e-9)
+
+	// Repeating frames is absorbed by the alignment
+	stretched := [][]float64{{0, 0}, {0, 0}, {1, 1}, {1, 1}, {2, 2}}
+	assert.InDelta(t, 0, dtwDistance(a, stretched), 1e-9)
+
+	assert.True(t, math.IsInf(dtwDistance(a, a[:1]), 1), "more than twice as long")
+	assert.True(t, math.IsInf(dtwDistance(a, nil), 1))
+}
+
+func TestFFT(t *testing.T) {
+	x := make([]complex128, 8)
+	for i := range x {
+		x[i] = complex(math.Cos(2*math.Pi*float64(i)/8), 0)
+	}
+	fft(x)
+
+	for k, value := range x {
+		want := 0.0
+		if k == 1 || k == 7 {
+			want = 4
+		}
+		assert.InDelta(t, want, cmplx.Abs(value), 1e-9, "bin %d", k)
+	}
+}
+
+func TestUtteranceSegmenter(t *testing.T) {
+	const frame = keywordSampleRate / 50
+	speech := func(frames int) [][]int16 {
+		out := make([][]int16, frames)
+		for i := range out {
+			out[i] = make([]int16, frame)
+			for j := range out[i] {
+				out[i][j] = int16(4000 * math.Sin(float64(j)))
+			}
+		}
+		return out
+	}
+	silence := func(frames int) [][]int16 {
+		out := make([][]int16, frames)
+		for i := range out {
+			out[i] = make([]int16, frame)
+		}
+		return out
+	}
+	feed := func(s *utteranceSegmenter, frames ...[][]int16) [][]int16 {
+		var utterances [][]int16
+		for _, group := range frames {
+			for _, f := range group {
+				if u := s.Add(f); u != nil {
+					utterances = append(utterances, u)
+				}
+			}
+		}
+		return utterances
+	}
+
+	t.Run("ends after silence", func(t *testing.T) {
+		s := &utteranceSegmenter{}
+		utterances := feed(s, silence(5), speech(20), silence(5), speech(10), silence(utteranceHangover))
+		require.Len(t, utterances, 1)
+		assert.Len(t, utterances[0], 35*frame, "short pauses stay in the utterance, trailing silence is dropped")
+	})
+
+	t.Run("flush ends the utterance", func(t *testing.T) {
+		s := &utteranceSegmenter{}
+		assert.Empty(t, feed(s, speech(20), silence(3)))
+		assert.Len(t, s.Flush(), 20*frame)
+		assert.Nil(t, s.Flush(), "nothing left after a flush")
+	})
+
+	t.Run("drops short sounds", func(t *testing.T) {
+		s := &utteranceSegmenter{}
+		assert.Empty(t, feed(s, speech(5), silence(utteranceHangover)))
+	})
+
+	t.Run("drops long speech", func(t *testing.T) {
+		s := &utteranceSegmenter{}
+		assert.Empty(t, feed(s, speech(200), silence(utteranceHangover)))
+
+		// The next command is recognized again
+		assert.Len(t, feed(s, speech(20), silence(utteranceHangover)), 1)
+	})
+}
+
+func TestTrimSilence(t *testing.T) {
+	const frame = keywordSampleRate / 50
+	pcm := make([]int16, 10*frame)
+	for i := 3 * frame; i < 7*frame; i++ {
+		pcm[i] = int16(4000 * math.Sin(float64(i)))
+	}
+
+	assert.Len(t, trimSilence(pcm), 4*frame)
+	assert.Empty(t, trimSilence(make([]int16, 5*frame)))
+}
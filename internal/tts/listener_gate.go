@@ -0,0 +1,209 @@
+package tts
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Listener gate limits and timing
+const (
+	// MaxMinListeners is the most human listeners a guild can require before messages are read
+	MaxMinListeners = 25
+
+	// ListenerGateGrace is how long a guild keeps being read to after its listeners dropped
+	// below the minimum, so someone rejoining or switching channels does not make the bot
+	// stop and start
+	ListenerGateGrace = 30 * time.Second
+)
+
+// MinListenersFor returns how many human listeners a guild's voice channel needs before
+// messages are read, 0 meaning any number
+func MinListenersFor(config *GuildTTSConfig) int {
+	if config == nil || config.MinListeners < 0 {
+		return 0
+	}
+	return config.MinListeners
+}
+
+// ListenerGate holds a guild's messages in the queue while fewer human listeners than the
+// guild asks for are in the bot's voice channel, so no speech is synthesized for an empty
+// room. The gate opens as soon as enough people are listening and closes only once they
+// have been too few for ListenerGateGrace. It is re-evaluated on voice state updates and
+// whenever the processor asks. A nil *ListenerGate is always open.
+type ListenerGate struct {
+	configService ConfigService
+	voiceManager  VoiceManager
+	logger        *log.Logger
+	grace         time.Duration
+	now           func() time.Time
+	listeners     func(guildID string) int // Counts the human listeners of a guild
+	session       *discordgo.Session
+	removeHandler func()
+
+	mu         sync.Mutex
+	open       map[string]bool      // Guilds that have enough listeners
+	belowSince map[string]time.Time // When open guilds dropped below their minimum
+}
+
+// NewListenerGate creates a listener gate. Call Register to count listeners from the
+// session's state and to follow voice state updates.
+func NewListenerGate(configService ConfigService, voiceManager VoiceManager, logger *log.Logger) *ListenerGate {
+	g := &ListenerGate{
+		configService: configService,
+		voiceManager:  voiceManager,
+		logger:        logger,
+		grace:         ListenerGateGrace,
+		now:           time.Now,
+		open:          make(map[string]bool),
+		belowSince:    make(map[string]time.Time),
+	}
+	g.listeners = g.countListeners
+	return g
+}
+
+// Register subscribes the gate to voice state updates on the session
+func (g *ListenerGate) Register(session *discordgo.Session) {
+	g.session = session
+	g.removeHandler = session.AddHandler(g.handleVoiceStateUpdate)
+}
+
+// Stop unsubscribes the gate from voice state updates
+func (g *ListenerGate) Stop() {
+	if g.removeHandler != nil {
+		g.removeHandler()
+		g.removeHandler = nil
+	}
+}
+
+// Open reports whether a guild's messages may be read
+func (g *ListenerGate) Open(guildID string) bool {
+	if g == nil {
+		return true
+	}
+	return g.evaluate(guildID)
+}
+
+// handleVoiceStateUpdate is the discordgo event handler for voice state changes
+func (g *ListenerGate) handleVoiceStateUpdate(s *discordgo.Session, vsu *discordgo.VoiceStateUpdate) {
+	if vsu == nil || vsu.VoiceState == nil {
+		return
+	}
+
+	// The bot leaving starts the next voice session with a closed gate
+	if s.State != nil && s.State.User != nil && vsu.UserID == s.State.User.ID && vsu.ChannelID == "" {
+		g.forget(vsu.GuildID)
+		return
+	}
+	g.evaluate(vsu.GuildID)
+}
+
+// evaluate opens or closes a guild's gate for the number of people listening now
+func (g *ListenerGate) evaluate(guildID string) bool {
+	minimum := g.minListeners(guildID)
+	if minimum <= 0 {
+		g.forget(guildID)
+		return true
+	}
+	listeners := g.listeners(guildID)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if listeners >= minimum {
+		delete(g.belowSince, guildID)
+		if !g.open[guildID] {
+			g.open[guildID] = true
+			g.logger.Printf("Reading messages for guild %s with %d listener(s) in the voice channel", guildID, listeners)
+		}
+		return true
+	}
+
+	if !g.open[guildID] {
+		return false
+	}
+	since, below := g.belowSince[guildID]
+	if !below {
+		g.belowSince[guildID] = g.now()
+		return true
+	}
+	if g.now().Sub(since) < g.grace {
+		return true
+	}
+
+	delete(g.open, guildID)
+	delete(g.belowSince, guildID)
+	g.logger.Printf("Holding messages for guild %s with %d of %d listener(s) in the voice channel", guildID, listeners, minimum)
+	return false
+}
+
+// forget drops what the gate knows about a guild
+func (g *ListenerGate) forget(guildID string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.open, guildID)
+	delete(g.belowSince, guildID)
+}
+
+// minListeners returns the minimum number of listeners of a guild
+func (g *ListenerGate) minListeners(guildID string) int {
+	config, err := g.configService.GetGuildConfig(guildID)
+	if err != nil {
+		g.logger.Printf("Error getting guild config for guild %s: %v", guildID, err)
+		return 0 // Read to everyone rather than hold messages on a config error
+	}
+	return MinListenersFor(config)
+}
+
+// countListeners counts the people in the bot's voice channel who can hear it: everyone
+// but bots and deafened members
+func (g *ListenerGate) countListeners(guildID string) int {
+	if g.session == nil || g.session.State == nil {
+		return 0
+	}
+	connection, ok := g.voiceManager.GetConnection(guildID)
+	if !ok || connection == nil {
+		return 0
+	}
+	guild, err := g.session.State.Guild(guildID)
+	if err != nil {
+		return 0
+	}
+
+	botUserID := ""
+	if g.session.State.User != nil {
+		botUserID = g.session.State.User.ID
+	}
+
+	g.session.State.RLock()
+	voiceStates := make([]*discordgo.VoiceState, len(guild.VoiceStates))
+	copy(voiceStates, guild.VoiceStates)
+	g.session.State.RUnlock()
+
+	listeners := 0
+	for _, state := range voiceStates {
+		if state.ChannelID != connection.ChannelID || state.UserID == botUserID || state.Deaf || state.SelfDeaf {
+			continue
+		}
+		if g.isBot(guildID, state) {
+			continue
+		}
+		listeners++
+	}
+	return listeners
+}
+
+// isBot reports whether a voice state belongs to a bot, looking the member up when the
+// voice state does not carry it
+func (g *ListenerGate) isBot(guildID string, state *discordgo.VoiceState) bool {
+	member := state.Member
+	if member == nil || member.User == nil {
+		var err error
+		if member, err = g.session.State.Member(guildID, state.UserID); err != nil || member.User == nil {
+			return false
+		}
+	}
+	return member.User.Bot
+}
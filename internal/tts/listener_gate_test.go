@@ -0,0 +1,104 @@
+package tts
+
+import (
+	"io"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createTestListenerGate(t *testing.T, minListeners int) (*ListenerGate, *int, *time.Time) {
+	t.Helper()
+
+	configService := createTestExportConfigService(t)
+	config := DefaultGuildTTSConfig("guild1")
+	config.MinListeners = minListeners
+	require.NoError(t, configService.SetGuildConfig("guild1", &config))
+
+	gate := NewListenerGate(configService, newMockVoiceManager(), log.New(io.Discard, "", 0))
+	listeners := 0
+	now := time.Now()
+	gate.listeners = func(string) int { return listeners }
+	gate.now = func() time.Time { return now }
+	return gate, &listeners, &now
+}
+
+func TestMinListenersFor(t *testing.T) {
+	assert.Zero(t, MinListenersFor(nil))
+	assert.Zero(t, MinListenersFor(&GuildTTSConfig{MinListeners: -1}))
+	assert.Equal(t, 2, MinListenersFor(&GuildTTSConfig{MinListeners: 2}))
+}
+
+func TestListenerGate_Off(t *testing.T) {
+	gate, _, _ := createTestListenerGate(t, 0)
+	assert.True(t, gate.Open("guild1"), "guilds without a minimum are read to an empty room")
+
+	var nilGate *ListenerGate
+	assert.True(t, nilGate.Open("guild1"))
+}
+
+func TestListenerGate_Hysteresis(t *testing.T) {
+	gate, listeners, now := createTestListenerGate(t, 2)
+
+	*listeners = 1
+	assert.False(t, gate.Open("guild1"), "the inviter alone is not enough")
+
+	*listeners = 2
+	assert.True(t, gate.Open("guild1"), "the gate opens as soon as enough people listen")
+
+	*listeners = 1
+	assert.True(t, gate.Open("guild1"), "someone leaving does not stop the bot right away")
+	*now = now.Add(ListenerGateGrace / 2)
+	assert.True(t, gate.Open("guild1"))
+
+	*listeners = 2
+	assert.True(t, gate.Open("guild1"), "someone coming back keeps the gate open")
+	*listeners = 1
+	*now = now.Add(ListenerGateGrace / 2)
+	assert.True(t, gate.Open("guild1"), "the grace period starts again")
+	*now = now.Add(ListenerGateGrace)
+	assert.False(t, gate.Open("guild1"))
+
+	*listeners = 3
+	assert.True(t, gate.Open("guild1"))
+}
+
+func TestListenerGate_BotLeavingClosesGate(t *testing.T) {
+	gate, listeners, _ := createTestListenerGate(t, 1)
+
+	*listeners = 1
+	require.True(t, gate.Open("guild1"))
+
+	session := &discordgo.Session{State: discordgo.NewState()}
+	session.State.User = &discordgo.User{ID: "bot"}
+	gate.handleVoiceStateUpdate(session, &discordgo.VoiceStateUpdate{VoiceState: &discordgo.VoiceState{GuildID: "guild1", UserID: "bot"}})
+
+	*listeners = 0
+	assert.False(t, gate.Open("guild1"), "a new voice session starts closed, without a grace period")
+}
+
+func TestListenerGate_CountListeners(t *testing.T) {
+	configService := createTestExportConfigService(t)
+	voiceManager := newMockVoiceManager()
+	_, err := voiceManager.JoinChannel("guild1", "voice1")
+	require.NoError(t, err)
+
+	session := &discordgo.Session{State: discordgo.NewState()}
+	session.State.User = &discordgo.User{ID: "bot"}
+	require.NoError(t, session.State.GuildAdd(&discordgo.Guild{ID: "guild1", VoiceStates: []*discordgo.VoiceState{
+		{GuildID: "guild1", UserID: "bot", ChannelID: "voice1"},
+		{GuildID: "guild1", UserID: "listener", ChannelID: "voice1"},
+		{GuildID: "guild1", UserID: "deafened", ChannelID: "voice1", SelfDeaf: true},
+		{GuildID: "guild1", UserID: "music", ChannelID: "voice1", Member: &discordgo.Member{User: &discordgo.User{ID: "music", Bot: true}}},
+		{GuildID: "guild1", UserID: "elsewhere", ChannelID: "voice2"},
+	}}))
+
+	gate := NewListenerGate(configService, voiceManager, log.New(io.Discard, "", 0))
+	gate.session = session
+	assert.Equal(t, 1, gate.countListeners("guild1"))
+	assert.Zero(t, gate.countListeners("guild2"), "the bot is not in a voice channel there")
+}
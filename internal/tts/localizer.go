@@ -0,0 +1,90 @@
+package tts
+
+import (
+	"errors"
+	"fmt"
+
+	"darrot/internal/commands/options"
+	"darrot/internal/i18n"
+)
+
+// Localizer translates command responses into each guild's configured language.
+// A nil *Localizer responds in the default language, so handlers can use it
+// unconditionally.
+type Localizer struct {
+	catalog       *i18n.Catalog
+	configService ConfigService
+}
+
+// NewLocalizer creates a localizer backed by a message catalog and per-guild configuration
+func NewLocalizer(catalog *i18n.Catalog, configService ConfigService) *Localizer {
+	return &Localizer{
+		catalog:       catalog,
+		configService: configService,
+	}
+}
+
+// Catalog returns the message catalog used for translations
+func (l *Localizer) Catalog() *i18n.Catalog {
+	if l == nil || l.catalog == nil {
+		return i18n.Default()
+	}
+	return l.catalog
+}
+
+// Locale returns the response language for a guild
+func (l *Localizer) Locale(guildID string) string {
+	if l == nil || l.configService == nil || guildID == "" {
+		return i18n.DefaultLocale
+	}
+
+	config, err := l.configService.GetGuildConfig(guildID)
+	if err != nil || config == nil || !l.Catalog().Supports(config.Language) {
+		return i18n.DefaultLocale
+	}
+
+	return config.Language
+}
+
+// T returns the message for key in the guild's language, formatted with args
+func (l *Localizer) T(guildID, key string, args ...any) string {
+	return l.Catalog().T(l.Locale(guildID), key, args...)
+}
+
+// SetLanguage changes the response language for a guild
+func (l *Localizer) SetLanguage(guildID, locale string) error {
+	if l == nil || l.configService == nil {
+		return fmt.Errorf("localization is not configured")
+	}
+
+	if !l.Catalog().Supports(locale) {
+		return fmt.Errorf("unsupported language: %s", locale)
+	}
+
+	config, err := l.configService.GetGuildConfig(guildID)
+	if err != nil {
+		return fmt.Errorf("failed to get guild config: %w", err)
+	}
+	if config == nil {
+		defaultConfig := DefaultGuildTTSConfig(guildID)
+		config = &defaultConfig
+	}
+
+	updated := *config
+	updated.Language = locale
+	if locale == i18n.DefaultLocale {
+		updated.Language = ""
+	}
+
+	return l.configService.SetGuildConfig(guildID, &updated)
+}
+
+// OptionError translates a command option validation error into the guild's language.
+// Other errors are returned unchanged.
+func (l *Localizer) OptionError(guildID string, err error) string {
+	var optionErr *options.Error
+	if errors.As(err, &optionErr) {
+		return l.T(guildID, optionErr.Key, optionErr.Args...)
+	}
+	return err.Error()
+}
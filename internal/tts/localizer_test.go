@@ -0,0 +1,192 @@
+package tts
+
+import (
+	"errors"
+	"log"
+	"os"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"darrot/internal/commands/options"
+	"darrot/internal/config"
+	"darrot/internal/i18n"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createTestLocalizer(t *testing.T) *Localizer {
+	storage, err := NewStorageService(t.TempDir())
+	require.NoError(t, err)
+
+	configService := NewConfigService(storage, config.TTSConfig{DefaultVoice: DefaultVoice, DefaultSpeed: 1.0, DefaultVolume: 1.0, MaxQueueSize: 10})
+	return NewLocalizer(i18n.Default(), configService)
+}
+
+func TestLocalizer_NilUsesDefaultLanguage(t *testing.T) {
+	var localizer *Localizer
+
+	assert.Equal(t, i18n.DefaultLocale, localizer.Locale("guild1"))
+	assert.Equal(t, "This command can only be used in a server.", localizer.T("guild1", "common.guild_only"))
+	assert.Error(t, localizer.SetLanguage("guild1", "de"))
+}
+
+func TestLocalizer_SetLanguage(t *testing.T) {
+	localizer := createTestLocalizer(t)
+
+	assert.Equal(t, i18n.DefaultLocale, localizer.Locale("guild1"))
+
+	require.NoError(t, localizer.SetLanguage("guild1", "de"))
+	assert.Equal(t, "de", localizer.Locale("guild1"))
+	assert.Equal(t, "✅ Clip `horn` entfernt.", localizer.T("guild1", "clip.removed", "horn"))
+
+	// Other guilds are unaffected
+	assert.Equal(t, "✅ Removed clip `horn`.", localizer.T("guild2", "clip.removed", "horn"))
+
+	// Switching back to English clears the setting
+	require.NoError(t, localizer.SetLanguage("guild1", i18n.DefaultLocale))
+	config, err := localizer.configService.GetGuildConfig("guild1")
+	require.NoError(t, err)
+	assert.Empty(t, config.Language)
+
+	assert.Error(t, localizer.SetLanguage("guild1", "tlh"))
+}
+
+// testCommandDefinitions returns the definition of every TTS command
+func testCommandDefinitions() []*discordgo.ApplicationCommand {
+	logger := log.New(os.Stdout, "", 0)
+	configHandler := NewConfigCommandHandler(nil, nil, nil, nil, logger)
+	handlers := []interface {
+		Definition() *discordgo.ApplicationCommand
+	}{
+		NewJoinCommandHandler(nil, nil, nil, nil, nil, nil, logger),
+		NewLeaveCommandHandler(nil, nil, nil, nil, nil, logger),
+		NewControlCommandHandler(nil, nil, nil, logger),
+		NewOptInCommandHandler(nil, logger),
+		configHandler,
+		NewAccessCommandHandler(configHandler),
+		NewProfileCommandHandler(configHandler),
+		NewClipCommandHandler(nil, nil, logger),
+		NewPlayCommandHandler(nil, nil, nil, nil, logger),
+		NewModerationCommandHandler(nil, nil, logger),
+		NewMuteCommandHandler(nil, logger),
+		NewUnmuteCommandHandler(nil, logger),
+		NewStatsCommandHandler(nil, nil, logger),
+		NewPreviewCommandHandler(nil, nil, nil, nil, nil, logger),
+		NewDebugCommandHandler(nil, nil, logger),
+		NewDiagnoseCommandHandler(nil, nil, nil, nil, logger),
+		NewOptInAdminCommandHandler(nil, nil, nil, logger),
+		NewTranscriptCommandHandler(nil, nil, nil, logger),
+		NewAPICommandHandler(nil, nil, nil, logger),
+		NewHelpCommandHandler(nil, nil, nil, nil, nil, logger),
+		NewPrivacyCommandHandler(nil, logger),
+		NewOwnerCommandHandler(nil, nil, nil, nil, nil, nil, nil, logger),
+	}
+
+	definitions := make([]*discordgo.ApplicationCommand, 0, len(handlers))
+	for _, handler := range handlers {
+		definitions = append(definitions, handler.Definition())
+	}
+	return definitions
+}
+
+func TestLocalizer_CommandKeysMatchDefinitions(t *testing.T) {
+	// Collect every key LocalizeCommand can look up
+	valid := make(map[string]bool)
+	var collect func(prefix string, options []*discordgo.ApplicationCommandOption)
+	collect = func(prefix string, options []*discordgo.ApplicationCommandOption) {
+		for _, option := range options {
+			optionPrefix := prefix + "." + option.Name
+			valid[optionPrefix+".name"] = true
+			valid[optionPrefix+".description"] = true
+			for _, choice := range option.Choices {
+				valid[optionPrefix+".choice."+choice.Value.(string)] = true
+			}
+			collect(optionPrefix, option.Options)
+		}
+	}
+	for _, definition := range testCommandDefinitions() {
+		prefix := "command." + definition.Name
+		valid[prefix+".name"] = true
+		valid[prefix+".description"] = true
+		collect(prefix, definition.Options)
+	}
+
+	catalog := i18n.Default()
+	for _, locale := range catalog.Locales() {
+		for _, key := range catalog.Keys(locale) {
+			if strings.HasPrefix(key, "command.") {
+				assert.True(t, valid[key], "%s: %q does not match any command definition", locale, key)
+				if strings.HasSuffix(key, ".description") {
+					// Discord rejects the whole command when a localized description is too long
+					assert.LessOrEqual(t, utf8.RuneCountInString(catalog.T(locale, key)), 100, "%s: %q is too long", locale, key)
+				}
+			}
+		}
+	}
+}
+
+// longestLocalization returns the length of the longest of text and its localizations
+func longestLocalization(text string, localizations map[discordgo.Locale]string) int {
+	longest := utf8.RuneCountInString(text)
+	for _, localized := range localizations {
+		longest = max(longest, utf8.RuneCountInString(localized))
+	}
+	return longest
+}
+
+func TestLocalizer_CommandDefinitionsFitDiscordLimits(t *testing.T) {
+	// Discord rejects a command whose names, descriptions and choices add up to more than
+	// 8000 characters, counting the longest localization of each
+	const maxCommandSize = 8000
+
+	var optionsSize func(options []*discordgo.ApplicationCommandOption) int
+	optionsSize = func(options []*discordgo.ApplicationCommandOption) int {
+		size := 0
+		for _, option := range options {
+			size += longestLocalization(option.Name, option.NameLocalizations)
+			size += longestLocalization(option.Description, option.DescriptionLocalizations)
+			for _, choice := range option.Choices {
+				size += longestLocalization(choice.Name, choice.NameLocalizations)
+				if value, ok := choice.Value.(string); ok {
+					size += utf8.RuneCountInString(value)
+				}
+			}
+			size += optionsSize(option.Options)
+		}
+		return size
+	}
+
+	for _, definition := range testCommandDefinitions() {
+		i18n.Default().LocalizeCommand(definition)
+
+		size := optionsSize(definition.Options)
+		if definition.NameLocalizations != nil {
+			size += longestLocalization(definition.Name, *definition.NameLocalizations)
+		} else {
+			size += utf8.RuneCountInString(definition.Name)
+		}
+		if definition.DescriptionLocalizations != nil {
+			size += longestLocalization(definition.Description, *definition.DescriptionLocalizations)
+		} else {
+			size += utf8.RuneCountInString(definition.Description)
+		}
+
+		assert.LessOrEqual(t, size, maxCommandSize, "/%s is too large for Discord", definition.Name)
+		assert.LessOrEqual(t, len(definition.Options), 25, "/%s has too many options for Discord", definition.Name)
+	}
+}
+
+func TestLocalizer_OptionError(t *testing.T) {
+	localizer := createTestLocalizer(t)
+	require.NoError(t, localizer.SetLanguage("guild1", "de"))
+
+	_, err := options.ParseFloat32("speed", "fast", MinTTSSpeed, MaxTTSSpeed)
+	assert.Equal(t, "`speed` muss eine Zahl sein, erhalten: `fast`.", localizer.OptionError("guild1", err))
+	assert.Equal(t, "`speed` must be a number, got `fast`.", localizer.OptionError("guild2", err))
+
+	// Other errors pass through untranslated
+	assert.Equal(t, "boom", localizer.OptionError("guild1", errors.New("boom")))
+}
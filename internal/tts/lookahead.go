@@ -0,0 +1,251 @@
+package tts
+
+import (
+	"context"
+	"log"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Synthesis lookahead
+const (
+	LookaheadMessages = 2       // Queued messages synthesized while the current one plays
+	lookaheadMaxBytes = 4 << 20 // Audio synthesized ahead per guild before the lookahead stops
+)
+
+// Lookahead metric names
+const (
+	MetricLookaheadHits      = "darrot_tts_lookahead_hits_total"
+	MetricLookaheadDiscarded = "darrot_tts_lookahead_discarded_total"
+)
+
+// lookaheadEntry is the audio synthesized ahead for a queued message. The text and config
+// it was synthesized from tell whether the message changed before its turn.
+type lookaheadEntry struct {
+	key    string
+	config TTSConfig
+	audio  []byte
+	err    error
+	done   chan struct{} // Closed when synthesis finished
+	cancel context.CancelFunc
+}
+
+// lookaheadMessage is a queued message the lookahead synthesizes
+type lookaheadMessage struct {
+	ctx       context.Context
+	message   *QueuedMessage
+	moderated *ModerationResult
+	config    TTSConfig
+	entry     *lookaheadEntry
+}
+
+// startLookahead synthesizes the guild's next queued messages in the background while
+// the current message plays, so the next one starts without a gap. Messages that left the
+// window, because they were skipped, removed or merged, are discarded first.
+func (tp *ttsProcessor) startLookahead(guildID string, processor *guildProcessor) {
+	lister, ok := tp.messageQueue.(QueueLister)
+	if !ok || tp.lookaheadCount < 1 || tp.draining.Load() {
+		return
+	}
+	if tp.errorRecovery.BreakerStatus(guildID).State != BreakerClosed {
+		return // Messages are skipped, not synthesized, while the breaker is open
+	}
+
+	// Nothing is synthesized ahead for guilds that stopped processing
+	tp.mu.RLock()
+	active := tp.guildProcessors[guildID] == processor
+	tp.mu.RUnlock()
+	if !active {
+		return
+	}
+
+	upcoming := lister.List(guildID)
+	if len(upcoming) > tp.lookaheadCount {
+		upcoming = upcoming[:tp.lookaheadCount]
+	}
+	tp.pruneLookahead(processor, upcoming)
+
+	var pending []lookaheadMessage
+	for _, message := range upcoming {
+		// A message still waiting for more from its author may yet be merged and replaced
+		if tp.mayMerge(guildID, message) {
+			break
+		}
+		if message.ClipName != "" || tp.textMirror.MirrorOnly(guildID) {
+			continue
+		}
+
+		processor.mu.RLock()
+		_, exists := processor.ahead[message]
+		processor.mu.RUnlock()
+		if exists {
+			continue
+		}
+
+		config, moderated, ok := tp.prepareSpeech(guildID, message)
+		if !ok {
+			continue
+		}
+		ctx, cancel := context.WithCancel(tp.ctx)
+		pending = append(pending, lookaheadMessage{
+			ctx:       ctx,
+			message:   message,
+			moderated: moderated,
+			config:    config,
+			entry:     &lookaheadEntry{key: speechKey(moderated), config: config, done: make(chan struct{}), cancel: cancel},
+		})
+	}
+	if len(pending) == 0 {
+		return
+	}
+
+	processor.mu.Lock()
+	if processor.ahead == nil {
+		processor.ahead = make(map[*QueuedMessage]*lookaheadEntry)
+	}
+	for _, next := range pending {
+		processor.ahead[next.message] = next.entry
+	}
+	processor.mu.Unlock()
+
+	tp.wg.Add(1)
+	go func() {
+		defer tp.wg.Done()
+		defer Recover(tp.metrics, "tts lookahead", guildID)
+		tp.runLookahead(guildID, processor, pending)
+	}()
+}
+
+// runLookahead synthesizes pending messages in reading order until the guild's lookahead
+// holds lookaheadMaxBytes of audio
+func (tp *ttsProcessor) runLookahead(guildID string, processor *guildProcessor, pending []lookaheadMessage) {
+	for i, next := range pending {
+		if tp.lookaheadBytes(processor) >= lookaheadMaxBytes {
+			for _, skipped := range pending[i:] {
+				tp.dropLookahead(processor, skipped.message, skipped.entry)
+				skipped.entry.cancel()
+				close(skipped.entry.done)
+			}
+			return
+		}
+
+		audioData, err := tp.synthesizeSpeech(next.ctx, guildID, next.moderated, next.config)
+		next.entry.cancel()
+
+		processor.mu.Lock()
+		next.entry.audio, next.entry.err = audioData, err
+		processor.mu.Unlock()
+		close(next.entry.done)
+
+		if err != nil && next.ctx.Err() == nil {
+			log.Printf("Lookahead synthesis failed for guild %s: %v", guildID, err)
+		}
+	}
+}
+
+// takeLookahead returns the audio synthesized ahead for message, waiting for synthesis
+// still in progress. It reports false when nothing usable was synthesized, including when
+// the message's text or the guild's voice settings changed since.
+func (tp *ttsProcessor) takeLookahead(ctx context.Context, processor *guildProcessor, message *QueuedMessage, moderated *ModerationResult, config TTSConfig) ([]byte, bool) {
+	processor.mu.Lock()
+	entry, exists := processor.ahead[message]
+	delete(processor.ahead, message)
+	processor.mu.Unlock()
+	if !exists {
+		return nil, false
+	}
+
+	if entry.key != speechKey(moderated) || entry.config != config {
+		entry.cancel()
+		tp.recordLookahead(processor.guildID, MetricLookaheadDiscarded)
+		return nil, false
+	}
+
+	select {
+	case <-entry.done:
+	case <-ctx.Done():
+		entry.cancel()
+		return nil, false
+	}
+
+	processor.mu.RLock()
+	audioData, err := entry.audio, entry.err
+	processor.mu.RUnlock()
+	if err != nil || audioData == nil {
+		return nil, false
+	}
+
+	tp.recordLookahead(processor.guildID, MetricLookaheadHits)
+	return audioData, true
+}
+
+// pruneLookahead discards audio synthesized for messages no longer among the next ones
+func (tp *ttsProcessor) pruneLookahead(processor *guildProcessor, upcoming []*QueuedMessage) {
+	processor.mu.Lock()
+	defer processor.mu.Unlock()
+
+	for message, entry := range processor.ahead {
+		if !slices.Contains(upcoming, message) {
+			entry.cancel()
+			delete(processor.ahead, message)
+			tp.recordLookahead(processor.guildID, MetricLookaheadDiscarded)
+		}
+	}
+}
+
+// discardLookahead discards all audio synthesized ahead for a guild
+func (tp *ttsProcessor) discardLookahead(processor *guildProcessor) {
+	tp.pruneLookahead(processor, nil)
+}
+
+// dropLookahead removes entry for message unless it was replaced in the meantime
+func (tp *ttsProcessor) dropLookahead(processor *guildProcessor, message *QueuedMessage, entry *lookaheadEntry) {
+	processor.mu.Lock()
+	defer processor.mu.Unlock()
+
+	if processor.ahead[message] == entry {
+		delete(processor.ahead, message)
+	}
+}
+
+// lookaheadBytes returns how much audio is held ahead for a guild
+func (tp *ttsProcessor) lookaheadBytes(processor *guildProcessor) int {
+	processor.mu.RLock()
+	defer processor.mu.RUnlock()
+
+	total := 0
+	for _, entry := range processor.ahead {
+		total += len(entry.audio)
+	}
+	return total
+}
+
+// mayMerge reports whether a queued message can still take more messages from its author
+// during the guild's hold-back window
+func (tp *ttsProcessor) mayMerge(guildID string, message *QueuedMessage) bool {
+	if message.Lead == "" || tp.configService == nil {
+		return false
+	}
+
+	config, err := tp.configService.GetGuildConfig(guildID)
+	if err != nil {
+		return false
+	}
+	return time.Since(message.Timestamp) < HoldBackFor(config)
+}
+
+// recordLookahead counts a lookahead hit or discard for a guild
+func (tp *ttsProcessor) recordLookahead(guildID, metric string) {
+	if tp.metrics != nil {
+		tp.metrics.IncCounter(metric, Labels{"guild": guildID})
+	}
+}
+
+// speechKey identifies the moderated text of a message, with bleeped words as breaks
+func speechKey(moderated *ModerationResult) string {
+	if len(moderated.Segments) > 0 {
+		return strings.Join(moderated.Segments, "\x00")
+	}
+	return moderated.Text
+}
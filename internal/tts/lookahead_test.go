@@ -0,0 +1,190 @@
+package tts
+
+import (
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingTTSManager records the texts it synthesizes
+type recordingTTSManager struct {
+	mockTTSManager
+	audio  []byte
+	mu     sync.Mutex
+	spoken []string
+}
+
+func (m *recordingTTSManager) ConvertToSpeech(text, voice string, config TTSConfig) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.spoken = append(m.spoken, text)
+	if m.audio != nil {
+		return m.audio, nil
+	}
+	return []byte("audio for " + text), nil
+}
+
+func (m *recordingTTSManager) synthesized() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return slices.Clone(m.spoken)
+}
+
+// createLookaheadTestProcessor returns a processor with guild1 processing and a queue
+// holding messages with the given IDs
+func createLookaheadTestProcessor(t *testing.T, ttsManager TTSManager, ids ...string) (*ttsProcessor, *guildProcessor, MessageQueue, *mockConfigService) {
+	queue := NewMessageQueue()
+	configService := newMockConfigService()
+	configService.configs["guild1"] = &TTSConfig{Voice: DefaultVoice, Speed: 1.0, Volume: 1.0, Format: AudioFormatPCM}
+	processor := NewTTSProcessor(ttsManager, newMockVoiceManager(), queue, configService, newMockUserService()).(*ttsProcessor)
+	t.Cleanup(func() { processor.Stop() })
+
+	if err := processor.StartGuildProcessing("guild1"); err != nil {
+		t.Fatalf("Failed to start guild processing: %v", err)
+	}
+	for _, id := range ids {
+		message := &QueuedMessage{ID: id, GuildID: "guild1", UserID: "user1", Content: "bob says: " + id, Timestamp: time.Now()}
+		if err := queue.Enqueue(message); err != nil {
+			t.Fatalf("Failed to enqueue message: %v", err)
+		}
+	}
+
+	return processor, processor.guildProcessors["guild1"], queue, configService
+}
+
+// waitForLookahead waits until the audio synthesized ahead for a guild is ready and
+// returns the IDs of the messages it was synthesized for
+func waitForLookahead(t *testing.T, processor *guildProcessor) []string {
+	t.Helper()
+
+	processor.mu.RLock()
+	entries := make(map[*QueuedMessage]*lookaheadEntry, len(processor.ahead))
+	for message, entry := range processor.ahead {
+		entries[message] = entry
+	}
+	processor.mu.RUnlock()
+
+	for message, entry := range entries {
+		select {
+		case <-entry.done:
+		case <-time.After(time.Second):
+			t.Fatalf("Lookahead for message %s did not finish", message.ID)
+		}
+	}
+
+	// Messages over the memory bound are dropped before they are synthesized
+	processor.mu.RLock()
+	defer processor.mu.RUnlock()
+	var ids []string
+	for message := range processor.ahead {
+		ids = append(ids, message.ID)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+func TestTTSProcessor_LookaheadSynthesizesNextMessages(t *testing.T) {
+	ttsManager := &recordingTTSManager{}
+	tp, processor, _, _ := createLookaheadTestProcessor(t, ttsManager, "m1", "m2", "m3", "m4")
+	metrics := NewMetrics()
+	tp.SetMetrics(metrics)
+
+	// The next two messages are synthesized while the first one plays
+	tp.processNextMessage("guild1", processor)
+	if ids := waitForLookahead(t, processor); !slices.Equal(ids, []string{"m2", "m3"}) {
+		t.Fatalf("Expected m2 and m3 to be synthesized ahead, got %v", ids)
+	}
+
+	tp.processNextMessage("guild1", processor)
+	waitForLookahead(t, processor)
+	tp.processNextMessage("guild1", processor)
+	tp.processNextMessage("guild1", processor)
+
+	// Every message was synthesized once, in reading order
+	expected := []string{"bob says: m1", "bob says: m2", "bob says: m3", "bob says: m4"}
+	if spoken := ttsManager.synthesized(); !slices.Equal(spoken, expected) {
+		t.Errorf("Expected %q to be synthesized, got %q", expected, spoken)
+	}
+	if hits := metrics.Value(MetricLookaheadHits, Labels{"guild": "guild1"}); hits != 3 {
+		t.Errorf("Expected 3 lookahead hits, got %v", hits)
+	}
+	if len(processor.ahead) != 0 {
+		t.Errorf("Expected no audio to be left over, got %d entries", len(processor.ahead))
+	}
+}
+
+func TestTTSProcessor_LookaheadDiscardsSkippedAndClearedMessages(t *testing.T) {
+	ttsManager := &recordingTTSManager{}
+	tp, processor, queue, _ := createLookaheadTestProcessor(t, ttsManager, "m1", "m2", "m3", "m4")
+	metrics := NewMetrics()
+	tp.SetMetrics(metrics)
+
+	tp.processNextMessage("guild1", processor)
+	waitForLookahead(t, processor)
+
+	// m2 is skipped before its turn, so its audio is dropped when m3 plays
+	if _, err := queue.SkipNext("guild1"); err != nil {
+		t.Fatalf("Failed to skip message: %v", err)
+	}
+	tp.processNextMessage("guild1", processor)
+	if ids := waitForLookahead(t, processor); !slices.Equal(ids, []string{"m4"}) {
+		t.Errorf("Expected only m4 to be held ahead, got %v", ids)
+	}
+	if discarded := metrics.Value(MetricLookaheadDiscarded, Labels{"guild": "guild1"}); discarded != 1 {
+		t.Errorf("Expected the skipped message to be discarded, got %v", discarded)
+	}
+
+	// Clearing the queue drops everything synthesized ahead
+	if err := tp.ClearQueue("guild1"); err != nil {
+		t.Fatalf("Failed to clear queue: %v", err)
+	}
+	if len(processor.ahead) != 0 {
+		t.Errorf("Expected no audio to be left after clearing the queue, got %d entries", len(processor.ahead))
+	}
+}
+
+func TestTTSProcessor_LookaheadResynthesizesChangedSettings(t *testing.T) {
+	ttsManager := &recordingTTSManager{}
+	tp, processor, _, configService := createLookaheadTestProcessor(t, ttsManager, "m1", "m2")
+
+	tp.processNextMessage("guild1", processor)
+	waitForLookahead(t, processor)
+
+	// The guild's voice changed after m2 was synthesized
+	configService.configs["guild1"] = &TTSConfig{Voice: "de-DE-Wavenet-B", Speed: 1.0, Volume: 1.0, Format: AudioFormatPCM}
+	tp.processNextMessage("guild1", processor)
+
+	expected := []string{"bob says: m1", "bob says: m2", "bob says: m2"}
+	if spoken := ttsManager.synthesized(); !slices.Equal(spoken, expected) {
+		t.Errorf("Expected m2 to be synthesized again, got %q", spoken)
+	}
+}
+
+func TestTTSProcessor_LookaheadMemoryBound(t *testing.T) {
+	ttsManager := &recordingTTSManager{audio: make([]byte, lookaheadMaxBytes)}
+	tp, processor, _, _ := createLookaheadTestProcessor(t, ttsManager, "m1", "m2", "m3")
+
+	// m2 fills the lookahead, so m3 waits for its turn
+	tp.processNextMessage("guild1", processor)
+	if ids := waitForLookahead(t, processor); !slices.Equal(ids, []string{"m2"}) {
+		t.Errorf("Expected only m2 to be held ahead, got %v", ids)
+	}
+	if spoken := ttsManager.synthesized(); len(spoken) != 2 {
+		t.Errorf("Expected m1 and m2 to be synthesized, got %q", spoken)
+	}
+}
+
+func TestTTSProcessor_LookaheadInactiveGuild(t *testing.T) {
+	ttsManager := &recordingTTSManager{}
+	tp, processor, _, _ := createLookaheadTestProcessor(t, ttsManager, "m1", "m2")
+
+	if err := tp.StopGuildProcessing("guild1"); err != nil {
+		t.Fatalf("Failed to stop guild processing: %v", err)
+	}
+	tp.processNextMessage("guild1", processor)
+
+	if len(processor.ahead) != 0 {
+		t.Errorf("Expected nothing to be synthesized ahead for a stopped guild, got %d entries", len(processor.ahead))
+	}
+}
@@ -0,0 +1,85 @@
+package tts
+
+import (
+	"math"
+)
+
+// Loudness targets in dBFS accepted by the loudness setting. A target of 0 turns
+// normalization off.
+const (
+	MinTTSLoudness = -30.0
+	MaxTTSLoudness = -10.0
+)
+
+const (
+	// loudnessBlockFrames is the length of the blocks loudness is measured in, 20ms at 48kHz
+	loudnessBlockFrames = 960
+
+	// loudnessGate is the level below which a block counts as a pause between words
+	// and is left out of the measurement, in dBFS
+	loudnessGate = -50.0
+
+	// maxLoudnessGain limits how far quiet speech is raised, so noise in near-silent
+	// audio is not blown up, in dB
+	maxLoudnessGain = 20.0
+)
+
+// normalizeLoudness scales 48kHz stereo samples in place so their speech level is
+// targetDBFS scaled by volume, leaving the relative volume a guild chose intact. The
+// level is the RMS of the 20ms blocks above loudnessGate, which roughly follows
+// perceived loudness without a full LUFS meter. The gain is capped so peaks never clip.
+func normalizeLoudness(samples []int16, targetDBFS, volume float32) {
+	if targetDBFS == 0 || len(samples) == 0 {
+		return
+	}
+
+	level, peak := speechLevel(samples)
+	if level <= loudnessGate || peak == 0 {
+		return // Silence, nothing to level
+	}
+
+	target := float64(targetDBFS)
+	if volume > 0 && volume < 1 {
+		target += 20 * math.Log10(float64(volume))
+	}
+
+	gainDB := math.Min(target-level, maxLoudnessGain)
+	gain := math.Pow(10, gainDB/20)
+	gain = math.Min(gain, math.MaxInt16/peak)
+
+	for i, sample := range samples {
+		samples[i] = int16(math.Round(float64(sample) * gain))
+	}
+}
+
+// speechLevel returns the gated RMS level of stereo samples in dBFS and their peak
+// absolute sample value
+func speechLevel(samples []int16) (float64, float64) {
+	gateMeanSquare := math.Pow(10, loudnessGate/10)
+	blockSamples := loudnessBlockFrames * 2
+
+	var sum float64
+	var blocks int
+	var peak float64
+	for start := 0; start < len(samples); start += blockSamples {
+		end := min(start+blockSamples, len(samples))
+
+		var blockSum float64
+		for _, sample := range samples[start:end] {
+			value := float64(sample) / 32768
+			blockSum += value * value
+			peak = math.Max(peak, math.Abs(float64(sample)))
+		}
+
+		meanSquare := blockSum / float64(end-start)
+		if meanSquare > gateMeanSquare {
+			sum += meanSquare
+			blocks++
+		}
+	}
+
+	if blocks == 0 {
+		return math.Inf(-1), peak
+	}
+	return 10 * math.Log10(sum/float64(blocks)), peak
+}
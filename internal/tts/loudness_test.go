@@ -0,0 +1,94 @@
+package tts
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// sineSamples returns a second of a 48kHz stereo 440Hz tone at amplitude, preceded by
+// a pause of silence
+func sineSamples(amplitude float64) []int16 {
+	samples := make([]int16, 0, 4*48000)
+	samples = append(samples, make([]int16, 2*24000)...)
+	for i := 0; i < 48000; i++ {
+		value := int16(amplitude * math.Sin(2*math.Pi*440*float64(i)/48000))
+		samples = append(samples, value, value)
+	}
+	return samples
+}
+
+func TestNormalizeLoudness_LevelsQuietAndLoudSpeech(t *testing.T) {
+	quiet := sineSamples(1000)
+	loud := sineSamples(20000)
+
+	normalizeLoudness(quiet, -20, 1.0)
+	normalizeLoudness(loud, -20, 1.0)
+
+	quietLevel, _ := speechLevel(quiet)
+	loudLevel, _ := speechLevel(loud)
+	assert.InDelta(t, -20, quietLevel, 0.1, "pauses between words do not count towards the level")
+	assert.InDelta(t, -20, loudLevel, 0.1)
+}
+
+func TestNormalizeLoudness_KeepsRelativeVolume(t *testing.T) {
+	samples := sineSamples(5000)
+
+	normalizeLoudness(samples, -20, 0.5)
+
+	level, _ := speechLevel(samples)
+	assert.InDelta(t, -26, level, 0.1, "half volume is 6dB below the target")
+}
+
+func TestNormalizeLoudness_Limits(t *testing.T) {
+	// Peaks are never clipped
+	samples := sineSamples(3000)
+	samples[len(samples)/2] = 30000
+	normalizeLoudness(samples, -10, 1.0)
+	level, peak := speechLevel(samples)
+	assert.Equal(t, float64(math.MaxInt16), peak)
+	assert.Less(t, level, -10.0)
+
+	// Near-silent audio is not blown up
+	faint := sineSamples(150)
+	before, _ := speechLevel(faint)
+	normalizeLoudness(faint, -20, 1.0)
+	level, _ = speechLevel(faint)
+	assert.InDelta(t, before+maxLoudnessGain, level, 0.1)
+
+	// Silence and disabled normalization are left alone
+	silence := make([]int16, 960)
+	normalizeLoudness(silence, -20, 1.0)
+	assert.Equal(t, make([]int16, 960), silence)
+
+	untouched := sineSamples(1000)
+	normalizeLoudness(untouched, 0, 1.0)
+	assert.Equal(t, sineSamples(1000), untouched)
+}
+
+func TestProcessAudioForDiscord_Loudness(t *testing.T) {
+	manager := &GoogleTTSManager{}
+
+	// A quiet 48kHz mono tone, as LINEAR16 bytes
+	mono := sineSamples(1000)
+	pcm := make([]byte, len(mono))
+	for i := 0; i < len(mono)/2; i++ {
+		pcm[i*2] = byte(mono[i*2])
+		pcm[i*2+1] = byte(mono[i*2] >> 8)
+	}
+
+	toSamples := func(data []byte) []int16 {
+		samples := make([]int16, len(data)/2)
+		for i := range samples {
+			samples[i] = int16(data[i*2]) | int16(data[i*2+1])<<8
+		}
+		return samples
+	}
+
+	plain, _ := speechLevel(toSamples(manager.processAudioForDiscord(pcm, 48000, 1, TTSConfig{Volume: 1.0})))
+	leveled, _ := speechLevel(toSamples(manager.processAudioForDiscord(pcm, 48000, 1, TTSConfig{Volume: 1.0, Loudness: -20})))
+
+	assert.InDelta(t, -33.3, plain, 0.1)
+	assert.InDelta(t, -20, leveled, 0.1)
+}
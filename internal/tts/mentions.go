@@ -0,0 +1,197 @@
+package tts
+
+import (
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// mentionCacheTTL is how long a resolved name is reused before it is looked up again
+	mentionCacheTTL = 5 * time.Minute
+
+	// mentionCacheSize is how many names are cached before expired ones are dropped
+	mentionCacheSize = 1000
+)
+
+// mentionRegex matches user (<@123>, <@!123>), role (<@&456>) and channel (<#789>) mentions
+var mentionRegex = regexp.MustCompile(`<(@!?|@&|#)(\d+)>`)
+
+// mentionName is a cached name; an empty name means the lookup failed
+type mentionName struct {
+	name    string
+	expires time.Time
+}
+
+// MentionResolver replaces user, role and channel mentions in message content with
+// readable names, so "<@123>" is read as "at Alice" instead of a string of digits.
+// Names are cached per guild, including lookups that failed, so a busy channel does
+// not hit Discord for every message. A nil *MentionResolver leaves content unchanged.
+type MentionResolver struct {
+	session DiscordSession
+	cache   map[string]mentionName
+	mu      sync.Mutex
+	now     func() time.Time
+}
+
+// NewMentionResolver creates a mention resolver that looks names up through session
+func NewMentionResolver(session DiscordSession) *MentionResolver {
+	return &MentionResolver{
+		session: session,
+		cache:   make(map[string]mentionName),
+		now:     time.Now,
+	}
+}
+
+// Expand replaces the mentions in content with the names they refer to in a guild.
+// Users mentioned in the message are used when a member cannot be looked up.
+func (r *MentionResolver) Expand(guildID, content string, mentioned []*discordgo.User) string {
+	if r == nil {
+		return content
+	}
+
+	return mentionRegex.ReplaceAllStringFunc(content, func(mention string) string {
+		match := mentionRegex.FindStringSubmatch(mention)
+		kind, id := match[1], match[2]
+
+		switch kind {
+		case "@&":
+			if name := r.roleName(guildID, id); name != "" {
+				return "at role " + name
+			}
+			return "at a role"
+		case "#":
+			if name := r.channelName(guildID, id); name != "" {
+				return "in channel " + name
+			}
+			return "in a channel"
+		default:
+			if name := r.userName(guildID, id, mentioned); name != "" {
+				return "at " + name
+			}
+			return "at someone"
+		}
+	})
+}
+
+// userName returns the display name of a guild member
+func (r *MentionResolver) userName(guildID, userID string, mentioned []*discordgo.User) string {
+	name := r.cached("user:"+guildID+":"+userID, func() string {
+		member, err := r.session.GuildMember(guildID, userID)
+		if err != nil || member == nil || member.User == nil {
+			return ""
+		}
+		return member.DisplayName()
+	})
+	if name != "" {
+		return name
+	}
+
+	// Users who left the guild are still named in the message
+	for _, user := range mentioned {
+		if user != nil && user.ID == userID {
+			return user.DisplayName()
+		}
+	}
+	return ""
+}
+
+// roleName returns the name of a guild role
+func (r *MentionResolver) roleName(guildID, roleID string) string {
+	return r.cached("role:"+guildID+":"+roleID, func() string {
+		guild, err := r.session.Guild(guildID)
+		if err != nil || guild == nil {
+			return ""
+		}
+		for _, role := range guild.Roles {
+			if role.ID == roleID {
+				return role.Name
+			}
+		}
+		return ""
+	})
+}
+
+// channelName returns the name of a channel in the guild. Channels of other guilds
+// are not named.
+func (r *MentionResolver) channelName(guildID, channelID string) string {
+	return r.cached("channel:"+guildID+":"+channelID, func() string {
+		channel, err := r.session.Channel(channelID)
+		if err != nil || channel == nil || channel.GuildID != guildID {
+			return ""
+		}
+		return channel.Name
+	})
+}
+
+// cached returns the cached name for key, looking it up when it is missing or expired
+func (r *MentionResolver) cached(key string, lookup func() string) string {
+	now := r.now()
+
+	r.mu.Lock()
+	entry, ok := r.cache[key]
+	r.mu.Unlock()
+	if ok && now.Before(entry.expires) {
+		return entry.name
+	}
+
+	name := lookup()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.cache) >= mentionCacheSize {
+		for cachedKey, cachedEntry := range r.cache {
+			if !now.Before(cachedEntry.expires) {
+				delete(r.cache, cachedKey)
+			}
+		}
+		if len(r.cache) >= mentionCacheSize {
+			r.cache = make(map[string]mentionName)
+		}
+	}
+	r.cache[key] = mentionName{name: name, expires: now.Add(mentionCacheTTL)}
+	return name
+}
+
+// stateSession looks guilds, members and channels up in the session state first and
+// only asks Discord when they are not there
+type stateSession struct {
+	*DiscordSessionWrapper
+}
+
+// newStateSession wraps a Discord session so lookups prefer its state
+func newStateSession(session *discordgo.Session) stateSession {
+	return stateSession{NewDiscordSessionWrapper(session)}
+}
+
+// Guild retrieves guild information, including its roles
+func (s stateSession) Guild(guildID string) (*discordgo.Guild, error) {
+	if state := s.session.State; state != nil {
+		if guild, err := state.Guild(guildID); err == nil {
+			return guild, nil
+		}
+	}
+	return s.DiscordSessionWrapper.Guild(guildID)
+}
+
+// GuildMember retrieves guild member information
+func (s stateSession) GuildMember(guildID, userID string) (*discordgo.Member, error) {
+	if state := s.session.State; state != nil {
+		if member, err := state.Member(guildID, userID); err == nil {
+			return member, nil
+		}
+	}
+	return s.DiscordSessionWrapper.GuildMember(guildID, userID)
+}
+
+// Channel retrieves channel information
+func (s stateSession) Channel(channelID string) (*discordgo.Channel, error) {
+	if state := s.session.State; state != nil {
+		if channel, err := state.Channel(channelID); err == nil {
+			return channel, nil
+		}
+	}
+	return s.DiscordSessionWrapper.Channel(channelID)
+}
@@ -0,0 +1,98 @@
+package tts
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingDiscordSession counts the lookups that reach the mock session
+type countingDiscordSession struct {
+	*MockDiscordSession
+	lookups int
+}
+
+func (c *countingDiscordSession) GuildMember(guildID, userID string) (*discordgo.Member, error) {
+	c.lookups++
+	return c.MockDiscordSession.GuildMember(guildID, userID)
+}
+
+func (c *countingDiscordSession) Guild(guildID string) (*discordgo.Guild, error) {
+	c.lookups++
+	return c.MockDiscordSession.Guild(guildID)
+}
+
+func (c *countingDiscordSession) Channel(channelID string) (*discordgo.Channel, error) {
+	c.lookups++
+	return c.MockDiscordSession.Channel(channelID)
+}
+
+func createTestMentionResolver() (*MentionResolver, *countingDiscordSession) {
+	session := NewMockDiscordSession()
+	session.AddGuild(&discordgo.Guild{ID: "guild1", Roles: []*discordgo.Role{{ID: "456", Name: "Moderators"}}})
+	session.AddMember("guild1", &discordgo.Member{User: &discordgo.User{ID: "123", Username: "alice", GlobalName: "Alice"}})
+	session.AddMember("guild1", &discordgo.Member{Nick: "Bobby", User: &discordgo.User{ID: "124", Username: "bob"}})
+	session.AddChannel(&discordgo.Channel{ID: "789", GuildID: "guild1", Name: "general"})
+	session.AddChannel(&discordgo.Channel{ID: "790", GuildID: "guild2", Name: "secret"})
+
+	counting := &countingDiscordSession{MockDiscordSession: session}
+	return NewMentionResolver(counting), counting
+}
+
+func TestMentionResolver_Expand(t *testing.T) {
+	resolver, _ := createTestMentionResolver()
+
+	assert.Equal(t, "at Alice and at Bobby, ask at role Moderators in channel general",
+		resolver.Expand("guild1", "<@123> and <@!124>, ask <@&456> <#789>", nil))
+	assert.Equal(t, "no mentions here", resolver.Expand("guild1", "no mentions here", nil))
+}
+
+func TestMentionResolver_Unresolved(t *testing.T) {
+	resolver, _ := createTestMentionResolver()
+
+	// Members who left are named from the message, everything else is read generically
+	mentioned := []*discordgo.User{{ID: "125", Username: "carol"}}
+	assert.Equal(t, "at carol, at someone, at a role, in a channel",
+		resolver.Expand("guild1", "<@125>, <@999>, <@&999>, <#999>", mentioned))
+
+	// Channels of other guilds are not named
+	assert.Equal(t, "in a channel", resolver.Expand("guild1", "<#790>", nil))
+
+	// Messages are read unchanged without a resolver
+	var missing *MentionResolver
+	assert.Equal(t, "<@123>", missing.Expand("guild1", "<@123>", nil))
+}
+
+func TestMentionResolver_Cache(t *testing.T) {
+	resolver, session := createTestMentionResolver()
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	resolver.now = func() time.Time { return now }
+
+	resolver.Expand("guild1", "<@123> <@&456> <#789> <@999>", nil)
+	require.Equal(t, 4, session.lookups)
+
+	// Names and failed lookups are reused until they expire
+	session.AddMember("guild1", &discordgo.Member{Nick: "Al", User: &discordgo.User{ID: "123", Username: "alice"}})
+	assert.Equal(t, "at Alice at role Moderators in channel general at someone", resolver.Expand("guild1", "<@123> <@&456> <#789> <@999>", nil))
+	assert.Equal(t, 4, session.lookups)
+
+	now = now.Add(mentionCacheTTL)
+	assert.Equal(t, "at Al", resolver.Expand("guild1", "<@123>", nil))
+	assert.Equal(t, 5, session.lookups)
+}
+
+func TestStateSession_PrefersState(t *testing.T) {
+	session := &discordgo.Session{State: discordgo.NewState()}
+	require.NoError(t, session.State.GuildAdd(&discordgo.Guild{
+		ID:       "guild1",
+		Roles:    []*discordgo.Role{{ID: "456", Name: "Moderators"}},
+		Channels: []*discordgo.Channel{{ID: "789", GuildID: "guild1", Name: "general"}},
+		Members:  []*discordgo.Member{{GuildID: "guild1", User: &discordgo.User{ID: "123", Username: "alice"}}},
+	}))
+
+	resolver := NewMentionResolver(newStateSession(session))
+	assert.Equal(t, "at alice, at role Moderators, in channel general", resolver.Expand("guild1", "<@123>, <@&456>, <#789>", nil))
+}
@@ -4,20 +4,39 @@ import (
 	"fmt"
 	"log"
 	"regexp"
+	"slices"
 	"strings"
 	"time"
 
+	"darrot/internal/commands/textcmd"
+
 	"github.com/bwmarrin/discordgo"
 )
 
 // MessageMonitor handles monitoring Discord text channels for TTS processing
 type MessageMonitor struct {
-	session        *discordgo.Session
-	channelService ChannelService
-	userService    UserService
-	messageQueue   MessageQueue
-	logger         *log.Logger
-	emojiRegex     *regexp.Regexp
+	session           *discordgo.Session
+	channelService    ChannelService
+	userService       UserService
+	messageQueue      MessageQueue
+	logger            *log.Logger
+	emojiRegex        *regexp.Regexp
+	contentPolicy     *ContentPolicy
+	configService     ConfigService
+	permissionService PermissionService
+	cooldown          *UserCooldown
+	mentions          *MentionResolver
+	privacyService    *PrivacyService
+	features          *FeatureFlagService
+	readMore          *ReadMore
+	catchUp           *catchUp
+	skipNotes         *SkipNotes
+
+	// voiceListeners returns the users currently in the bot's voice channel
+	voiceListeners func(guildID string) []string
+
+	// addReaction reacts to a Discord message with an emoji
+	addReaction func(channelID, messageID, emoji string) error
 }
 
 // NewMessageMonitor creates a new MessageMonitor instance
@@ -38,6 +57,14 @@ func NewMessageMonitor(
 		messageQueue:   messageQueue,
 		logger:         logger,
 		emojiRegex:     emojiRegex,
+		cooldown:       NewUserCooldown(),
+		mentions:       NewMentionResolver(newStateSession(session)),
+		catchUp:        newCatchUp(messageQueue, logger),
+	}
+
+	monitor.voiceListeners = monitor.voiceChannelListeners
+	monitor.addReaction = func(channelID, messageID, emoji string) error {
+		return session.MessageReactionAdd(channelID, messageID, emoji)
 	}
 
 	// Register message event handler
@@ -48,17 +75,20 @@ func NewMessageMonitor(
 
 // handleMessageCreate processes new Discord messages for TTS
 func (m *MessageMonitor) handleMessageCreate(s *discordgo.Session, mc *discordgo.MessageCreate) {
-	// Skip messages from bots (including ourselves)
-	if mc.Author.Bot {
+	// Messages from bots and webhooks are only read in guilds that allow them, and the
+	// bot's own messages never are
+	automated := mc.Author.Bot || mc.WebhookID != ""
+	if automated && !m.readsAutomatedMessage(s, mc) {
 		return
 	}
 
-	// Skip empty messages
-	if strings.TrimSpace(mc.Content) == "" {
+	// Skip empty messages, unless the guild narrates their attachments
+	attachments := m.attachmentNarration(mc.GuildID, mc.Attachments)
+	if strings.TrimSpace(mc.Content) == "" && attachments == "" {
 		return
 	}
 
-	m.logger.Printf("Received message from %s in guild %s, channel %s: %s", mc.Author.Username, mc.GuildID, mc.ChannelID, mc.Content)
+	m.logger.Printf("Received message from %s in guild %s, channel %s: %s", mc.Author.Username, mc.GuildID, mc.ChannelID, m.contentPolicy.Loggable(mc.GuildID, mc.Content))
 
 	// Check if this channel is paired with a voice channel
 	// Note: Using the existing interface which returns bool only
@@ -70,22 +100,94 @@ func (m *MessageMonitor) handleMessageCreate(s *discordgo.Session, mc *discordgo
 
 	m.logger.Printf("Channel %s in guild %s is paired, processing message", mc.ChannelID, mc.GuildID)
 
-	// Check if user is opted-in for TTS
-	isOptedIn, err := m.userService.IsOptedIn(mc.Author.ID, mc.GuildID)
-	if err != nil {
-		m.logger.Printf("Error checking opt-in status for user %s in guild %s: %v", mc.Author.ID, mc.GuildID, err)
+	// Text commands are run by the bot, not read aloud
+	if textcmd.HasPrefix(mc.Content, m.commandPrefix(mc.GuildID)) {
+		m.logger.Printf("Message from %s in guild %s is a text command, ignoring message", mc.Author.Username, mc.GuildID)
 		return
 	}
 
-	if !isOptedIn {
-		m.logger.Printf("User %s in guild %s is not opted-in, ignoring message", mc.Author.Username, mc.GuildID)
-		return // User is not opted-in, ignore message
+	// Bot commands and deliberately muted messages are not read aloud
+	if prefix, ignored := m.ignorePrefix(mc.GuildID, mc.Content); ignored {
+		m.logger.Printf("Message from %s in guild %s starts with ignore prefix %q, ignoring message", mc.Author.Username, mc.GuildID, prefix)
+		return
 	}
 
-	m.logger.Printf("User %s in guild %s is opted-in, processing message", mc.Author.Username, mc.GuildID)
+	// Bots and webhooks cannot opt in or hold speaker roles: the guild allowing them
+	// stands in for both
+	if !automated {
+		// Check if user is opted-in for TTS
+		isOptedIn, err := m.userService.IsOptedIn(mc.Author.ID, mc.GuildID)
+		if err != nil {
+			m.logger.Printf("Error checking opt-in status for user %s in guild %s: %v", mc.Author.ID, mc.GuildID, err)
+			return
+		}
+
+		// Guilds can opt in members of the voice channel who never chose
+		if !isOptedIn {
+			isOptedIn = m.optInVoiceMember(mc.GuildID, mc.Author.ID)
+		}
+
+		if !isOptedIn {
+			m.logger.Printf("User %s in guild %s is not opted-in, ignoring message", mc.Author.Username, mc.GuildID)
+			m.skipNotes.Skipped(mc.GuildID, mc.ChannelID, SkipNotOptedIn)
+			return // User is not opted-in, ignore message
+		}
+
+		m.logger.Printf("User %s in guild %s is opted-in, processing message", mc.Author.Username, mc.GuildID)
+
+		// Users can limit which paired channels their messages are read from
+		if !m.readsChannel(mc.Author.ID, mc.GuildID, mc.ChannelID) {
+			m.logger.Printf("User %s in guild %s does not have messages read from channel %s, ignoring message", mc.Author.Username, mc.GuildID, mc.ChannelID)
+			return
+		}
+
+		// Guilds with speaker roles only read members holding one of them
+		if m.permissionService != nil {
+			canBeRead, err := m.permissionService.CanBeRead(mc.Author.ID, mc.GuildID)
+			if err != nil {
+				m.logger.Printf("Error checking speaker roles for user %s in guild %s: %v", mc.Author.ID, mc.GuildID, err)
+				return
+			}
+			if !canBeRead {
+				m.logger.Printf("User %s in guild %s has no speaker role, ignoring message", mc.Author.Username, mc.GuildID)
+				return
+			}
+		}
+	}
+
+	// Respect listeners in the voice channel who muted the author
+	if m.isMutedByListener(mc.GuildID, mc.Author.ID) {
+		m.logger.Printf("User %s in guild %s is muted by a listener in the voice channel, ignoring message", mc.Author.Username, mc.GuildID)
+		m.skipNotes.Skipped(mc.GuildID, mc.ChannelID, SkipMuted)
+		return
+	}
+
+	// Whisper-mode pairings only read authors who are in the voice channel
+	if m.channelService.RequiresPresence(mc.GuildID, mc.ChannelID) && !m.isListening(mc.GuildID, mc.Author.ID) {
+		m.logger.Printf("User %s in guild %s is not in the voice channel of a whisper-mode pairing, ignoring message", mc.Author.Username, mc.GuildID)
+		return
+	}
+
+	// Mentions are read as the names they refer to, then links and code blocks the way
+	// the guild prefers
+	modes := m.contentModes(mc.GuildID)
+	content := m.mentions.Expand(mc.GuildID, mc.Content, mc.Mentions)
+	content = applyContentModes(content, modes)
+	if content == "" && attachments == "" {
+		m.logger.Printf("Message from %s only contained skipped links or code blocks, skipping", mc.Author.Username)
+		return
+	}
+
+	// Guilds that turn emoji reading off don't hear emoji in names either
+	username := mc.Author.Username
+	if modes.SkipEmoji {
+		if name := strings.Join(strings.Fields(dropEmoji(username)), " "); name != "" {
+			username = name
+		}
+	}
 
 	// Preprocess the message
-	processedContent := m.preprocessMessage(mc.Content, mc.Author.Username)
+	processedContent := m.narrateAttachments(m.preprocessMessage(content, username), content, username, attachments)
 
 	// Skip if message becomes empty after preprocessing
 	if strings.TrimSpace(processedContent) == "" {
@@ -93,24 +195,360 @@ func (m *MessageMonitor) handleMessageCreate(s *discordgo.Session, mc *discordgo
 		return
 	}
 
-	// Create queued message
-	queuedMessage := &QueuedMessage{
-		ID:        mc.ID,
-		GuildID:   mc.GuildID,
-		ChannelID: mc.ChannelID,
-		UserID:    mc.Author.ID,
-		Username:  mc.Author.Username,
-		Content:   processedContent,
-		Timestamp: time.Now(),
+	// Long messages are cut or split the way the guild prefers
+	policy := m.lengthPolicy(mc.GuildID)
+	parts := policy.Apply(processedContent)
+	var rest []string
+	if policy.Mode == TruncationModeAsk && len(parts) > 1 {
+		// Guilds that ask first read the first part and offer the rest
+		parts, rest = parts[:1], parts[1:]
+		m.logger.Printf("Reading the first of %d parts of long message from %s", len(rest)+1, mc.Author.Username)
+	} else if len(parts) > 1 {
+		m.logger.Printf("Split long message from %s into %d parts", mc.Author.Username, len(parts))
+	} else if parts[0] != processedContent {
+		m.logger.Printf("Truncated long message from %s", mc.Author.Username)
 	}
 
-	// Add to message queue
-	if err := m.messageQueue.Enqueue(queuedMessage); err != nil {
-		m.logger.Printf("Error enqueueing message from %s: %v", mc.Author.Username, err)
+	// The queue orders and merges messages by when they were sent, which for a backlog
+	// delivered after a reconnect is long before they reach the bot
+	sentAt := mc.Timestamp
+	if sentAt.IsZero() {
+		sentAt = time.Now()
+	}
+
+	// Messages sent long ago are skipped or summarized in guilds that don't read them
+	catchUpPolicy, cutoff := m.catchUpPolicy(mc.GuildID)
+	if !m.catchUp.admit(&QueuedMessage{ID: mc.ID, GuildID: mc.GuildID, ChannelID: mc.ChannelID, Timestamp: sentAt}, catchUpPolicy, cutoff) {
+		return
+	}
+
+	// Listeners hear what was skipped before the message that ends the run
+	m.skipNotes.Read(mc.GuildID)
+
+	// Users over the guild's per-user limit are dropped so they cannot fill the queue
+	if !m.cooldown.Allow(mc.GuildID, mc.Author.ID, m.userMessageLimit(mc.GuildID)) {
+		m.logger.Printf("User %s in guild %s is over the per-user message limit, dropping message", mc.Author.Username, mc.GuildID)
+		if err := m.addReaction(mc.ChannelID, mc.ID, CooldownReaction); err != nil {
+			m.logger.Printf("Failed to react to dropped message %s in guild %s: %v", mc.ID, mc.GuildID, err)
+		}
 		return
 	}
 
-	m.logger.Printf("Queued message from %s in guild %s: %s", mc.Author.Username, mc.GuildID, processedContent)
+	// The queue leaves the lead out when it merges a burst of messages from the author
+	lead := m.handleEmojis(fmt.Sprintf("%s says:", username))
+
+	var first *QueuedMessage
+	for index, part := range parts {
+		// Create queued message
+		queuedMessage := &QueuedMessage{
+			ID:        mc.ID,
+			GuildID:   mc.GuildID,
+			ChannelID: mc.ChannelID,
+			UserID:    mc.Author.ID,
+			Username:  mc.Author.Username,
+			Content:   part,
+			Timestamp: sentAt,
+		}
+		if index > 0 {
+			queuedMessage.Part = index + 1
+		} else if strings.HasPrefix(part, lead+" ") {
+			queuedMessage.Lead = lead
+		}
+		if index == 0 {
+			first = queuedMessage
+		}
+
+		// Add to message queue
+		if err := m.messageQueue.Enqueue(queuedMessage); err != nil {
+			m.logger.Printf("Error enqueueing message from %s: %v", mc.Author.Username, err)
+			return
+		}
+	}
+
+	if len(rest) > 0 && m.readMore != nil {
+		if err := m.readMore.Offer(first, rest); err != nil {
+			m.logger.Printf("Failed to offer the rest of message %s in guild %s: %v", mc.ID, mc.GuildID, err)
+		}
+	}
+
+	m.logger.Printf("Queued message from %s in guild %s: %s", mc.Author.Username, mc.GuildID, m.contentPolicy.Loggable(mc.GuildID, processedContent))
+}
+
+// isMutedByListener reports whether anyone currently listening in the voice channel has muted the user
+func (m *MessageMonitor) isMutedByListener(guildID, userID string) bool {
+	for _, listenerID := range m.voiceListeners(guildID) {
+		mutedUsers, err := m.userService.GetMutedUsers(listenerID, guildID)
+		if err != nil {
+			m.logger.Printf("Error checking mute list of user %s in guild %s: %v", listenerID, guildID, err)
+			continue
+		}
+		for _, mutedID := range mutedUsers {
+			if mutedID == userID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isListening reports whether the user is in the bot's voice channel
+func (m *MessageMonitor) isListening(guildID, userID string) bool {
+	return slices.Contains(m.voiceListeners(guildID), userID)
+}
+
+// voiceChannelListeners returns the users sharing the bot's voice channel, based on the session state cache
+func (m *MessageMonitor) voiceChannelListeners(guildID string) []string {
+	if m.session == nil || m.session.State == nil || m.session.State.User == nil {
+		return nil
+	}
+
+	botState, err := m.session.State.VoiceState(guildID, m.session.State.User.ID)
+	if err != nil || botState.ChannelID == "" {
+		return nil
+	}
+
+	guild, err := m.session.State.Guild(guildID)
+	if err != nil {
+		return nil
+	}
+
+	m.session.State.RLock()
+	defer m.session.State.RUnlock()
+
+	listeners := make([]string, 0, len(guild.VoiceStates))
+	for _, state := range guild.VoiceStates {
+		if state.ChannelID == botState.ChannelID && state.UserID != botState.UserID {
+			listeners = append(listeners, state.UserID)
+		}
+	}
+
+	return listeners
+}
+
+// SetContentPolicy sets the policy deciding whether message content may be logged
+func (m *MessageMonitor) SetContentPolicy(policy *ContentPolicy) {
+	m.contentPolicy = policy
+}
+
+// SetConfigService sets the configuration source for per-guild ignore prefixes, text
+// command prefixes, link and code block modes, per-user message limits and catch-up
+// policies
+func (m *MessageMonitor) SetConfigService(configService ConfigService) {
+	m.configService = configService
+}
+
+// SetLocalizer sets the localizer used to translate the summary of missed messages
+func (m *MessageMonitor) SetLocalizer(localizer *Localizer) {
+	m.catchUp.localizer = localizer
+}
+
+// SetPrivacyService enables DM privacy notices for voice channel members who are opted
+// in automatically
+func (m *MessageMonitor) SetPrivacyService(privacyService *PrivacyService) {
+	m.privacyService = privacyService
+}
+
+// SetFeatureFlags sets the service deciding whether guilds read emoji and narrate
+// attachments
+func (m *MessageMonitor) SetFeatureFlags(features *FeatureFlagService) {
+	m.features = features
+}
+
+// SetReadMore sets the service offering the rest of long messages in guilds using the ask
+// truncation mode. Without it only their first part is read.
+func (m *MessageMonitor) SetReadMore(readMore *ReadMore) {
+	m.readMore = readMore
+}
+
+// SetSkipNotes counts messages skipped for their author, for guilds that hear a note about
+// them
+func (m *MessageMonitor) SetSkipNotes(skipNotes *SkipNotes) {
+	m.skipNotes = skipNotes
+}
+
+// VoiceListeners returns the users currently in the bot's voice channel
+func (m *MessageMonitor) VoiceListeners(guildID string) []string {
+	return m.voiceListeners(guildID)
+}
+
+// SetPermissionService enables the speaker role allowlist
+func (m *MessageMonitor) SetPermissionService(permissionService PermissionService) {
+	m.permissionService = permissionService
+}
+
+// contentModes returns how links, code blocks and emoji are read in a guild
+func (m *MessageMonitor) contentModes(guildID string) ContentModes {
+	var config *GuildTTSConfig
+	if m.configService != nil {
+		var err error
+		if config, err = m.configService.GetGuildConfig(guildID); err != nil {
+			m.logger.Printf("Error getting guild config for guild %s: %v", guildID, err)
+			config = nil
+		}
+	}
+
+	modes := ContentModesFor(config)
+	modes.SkipEmoji = !m.features.Enabled(guildID, FeatureEmojiReading)
+	return modes
+}
+
+// lengthPolicy returns how long messages are read in a guild
+func (m *MessageMonitor) lengthPolicy(guildID string) LengthPolicy {
+	if m.configService == nil {
+		return LengthPolicyFor(nil)
+	}
+
+	config, err := m.configService.GetGuildConfig(guildID)
+	if err != nil {
+		m.logger.Printf("Error getting guild config for guild %s: %v", guildID, err)
+		return LengthPolicyFor(nil)
+	}
+	return LengthPolicyFor(config)
+}
+
+// userMessageLimit returns how many messages per minute a user can have read in a guild,
+// 0 meaning no limit
+func (m *MessageMonitor) userMessageLimit(guildID string) int {
+	if m.configService == nil {
+		return 0
+	}
+
+	config, err := m.configService.GetGuildConfig(guildID)
+	if err != nil {
+		m.logger.Printf("Error getting guild config for guild %s: %v", guildID, err)
+		return 0
+	}
+	return UserMessagesPerMinuteFor(config)
+}
+
+// readsChannel reports whether a user's messages are read from a paired text channel,
+// which they are unless the user limited reading to other channels
+func (m *MessageMonitor) readsChannel(userID, guildID, channelID string) bool {
+	allowlist, ok := m.userService.(ReadChannelAllowlist)
+	if !ok {
+		return true
+	}
+
+	channels, err := allowlist.GetReadChannels(userID, guildID)
+	if err != nil {
+		m.logger.Printf("Error getting read channels for user %s in guild %s: %v", userID, guildID, err)
+		return true
+	}
+	return len(channels) == 0 || slices.Contains(channels, channelID)
+}
+
+// catchUpPolicy returns what happens to late messages in a guild and how old a message
+// may be before it counts as late
+func (m *MessageMonitor) catchUpPolicy(guildID string) (CatchUpPolicy, time.Duration) {
+	if m.configService == nil {
+		return CatchUpPolicyFor(nil), MessageAgeCutoffFor(nil)
+	}
+
+	config, err := m.configService.GetGuildConfig(guildID)
+	if err != nil {
+		m.logger.Printf("Error getting guild config for guild %s: %v", guildID, err)
+		return CatchUpPolicyFor(nil), MessageAgeCutoffFor(nil)
+	}
+	return CatchUpPolicyFor(config), MessageAgeCutoffFor(config)
+}
+
+// commandPrefix returns the prefix of text commands in a guild, or an empty string when
+// they are turned off
+func (m *MessageMonitor) commandPrefix(guildID string) string {
+	if m.configService == nil {
+		return CommandPrefixFor(nil)
+	}
+
+	config, err := m.configService.GetGuildConfig(guildID)
+	if err != nil {
+		m.logger.Printf("Error getting guild config for guild %s: %v", guildID, err)
+		return CommandPrefixFor(nil)
+	}
+	return CommandPrefixFor(config)
+}
+
+// optInVoiceMember opts in a message author who is in the bot's voice channel when the
+// guild opts voice channel members in automatically, and reports whether they are now
+// opted in
+func (m *MessageMonitor) optInVoiceMember(guildID, userID string) bool {
+	optIn, ok := m.userService.(VoiceMemberOptIn)
+	if !ok || m.configService == nil {
+		return false
+	}
+
+	config, err := m.configService.GetGuildConfig(guildID)
+	if err != nil || config == nil || !config.AutoOptInVoiceMembers {
+		return false
+	}
+	if !m.isListening(guildID, userID) {
+		return false
+	}
+
+	optedIn, err := optIn.OptInVoiceMember(userID, guildID)
+	if err != nil {
+		m.logger.Printf("Error opting in voice channel member %s in guild %s: %v", userID, guildID, err)
+		return false
+	}
+	if !optedIn {
+		return false
+	}
+
+	m.logger.Printf("Opted in voice channel member %s in guild %s automatically", userID, guildID)
+	if m.privacyService != nil {
+		// The notice is sent in the background so the DM never delays reading the message
+		go func() {
+			if err := m.privacyService.SendOptInNotice(userID, guildID); err != nil {
+				m.logger.Printf("Warning: Failed to send privacy notice to user %s: %v", userID, err)
+			}
+		}()
+	}
+	return true
+}
+
+// ignorePrefix returns the guild ignore prefix the message starts with, if any
+func (m *MessageMonitor) ignorePrefix(guildID, content string) (string, bool) {
+	if m.configService == nil {
+		return "", false
+	}
+
+	prefixes, err := m.configService.GetIgnorePrefixes(guildID)
+	if err != nil {
+		m.logger.Printf("Error getting ignore prefixes for guild %s: %v", guildID, err)
+		return "", false
+	}
+
+	content = strings.TrimSpace(content)
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(content, prefix) {
+			return prefix, true
+		}
+	}
+	return "", false
+}
+
+// readsAutomatedMessage reports whether a message from a bot or webhook is read in its
+// guild: webhook messages when the guild reads webhooks, and bot messages when it reads
+// every bot or allows this one
+func (m *MessageMonitor) readsAutomatedMessage(s *discordgo.Session, mc *discordgo.MessageCreate) bool {
+	if s != nil && s.State != nil && s.State.User != nil && s.State.User.ID == mc.Author.ID {
+		return false
+	}
+	if m.configService == nil {
+		return false
+	}
+
+	config, err := m.configService.GetGuildConfig(mc.GuildID)
+	if err != nil {
+		m.logger.Printf("Error getting guild config for guild %s: %v", mc.GuildID, err)
+		return false
+	}
+	if config == nil {
+		return false
+	}
+	if mc.WebhookID != "" {
+		return config.ReadWebhooks
+	}
+	return config.ReadBots || slices.Contains(config.AllowedBots, mc.Author.ID)
 }
 
 // preprocessMessage handles message preprocessing including author name and emoji handling
@@ -125,30 +563,38 @@ func (m *MessageMonitor) preprocessMessage(content, username string) string {
 	processedContent = m.handleEmojis(processedContent)
 
 	// Clean up extra whitespace again
-	processedContent = strings.TrimSpace(processedContent)
+	return strings.TrimSpace(processedContent)
+}
 
-	// Limit message length (max ~30 seconds of speech at average rate)
-	const maxLength = 500
-	if len(processedContent) > maxLength {
-		processedContent = processedContent[:maxLength-3] + "..."
-		m.logger.Printf("Truncated long message from %s", username)
+// attachmentNarration describes a message's attachments when the guild narrates them, and
+// returns an empty string otherwise
+func (m *MessageMonitor) attachmentNarration(guildID string, attachments []*discordgo.MessageAttachment) string {
+	if len(attachments) == 0 || !m.features.Enabled(guildID, FeatureAttachmentNarration) {
+		return ""
 	}
+	return describeAttachments(attachments)
+}
 
-	return processedContent
+// narrateAttachments adds the attachment narration to a preprocessed message. Messages
+// with nothing but attachments are read as "alice sent an image".
+func (m *MessageMonitor) narrateAttachments(processed, content, username, attachments string) string {
+	switch {
+	case attachments == "":
+		return processed
+	case content == "":
+		return strings.TrimSpace(m.handleEmojis(fmt.Sprintf("%s sent %s", username, attachments)))
+	case strings.HasSuffix(processed, ".") || strings.HasSuffix(processed, "!") || strings.HasSuffix(processed, "?"):
+		return fmt.Sprintf("%s Attached %s.", processed, attachments)
+	default:
+		return fmt.Sprintf("%s. Attached %s.", processed, attachments)
+	}
 }
 
-// handleEmojis processes emojis in the message content
+// handleEmojis replaces custom Discord emotes and Unicode emoji with spoken names.
+// Message content has already had the guild's emoji limit applied, so this only
+// catches emoji in other text such as usernames.
 func (m *MessageMonitor) handleEmojis(content string) string {
-	// Replace custom Discord emojis with their names
-	content = regexp.MustCompile(`<a?:(\w+):\d+>`).ReplaceAllString(content, "$1 emoji")
-
-	// For Unicode emojis, we'll keep them as-is since most TTS engines can handle them
-	// or convert them to descriptive text if needed
-
-	// Remove excessive emoji sequences (more than 3 in a row)
-	content = regexp.MustCompile(`([\x{1F600}-\x{1F64F}]|[\x{1F300}-\x{1F5FF}]|[\x{1F680}-\x{1F6FF}]|[\x{1F1E0}-\x{1F1FF}]|[\x{2600}-\x{26FF}]|[\x{2700}-\x{27BF}]){4,}`).ReplaceAllString(content, "multiple emojis")
-
-	return content
+	return speakEmoji(content, 0)
 }
 
 // IsMonitoring returns whether the monitor is actively listening for messages
@@ -164,4 +610,5 @@ func (m *MessageMonitor) Stop() {
 		// or implement a more sophisticated handler management system
 		m.logger.Println("Message monitor stopped")
 	}
+	m.catchUp.stop()
 }
@@ -2,22 +2,57 @@ package tts
 
 import (
 	"errors"
+	"strings"
 	"sync"
 	"time"
+
+	"darrot/internal/events"
 )
 
 // DefaultInactivityTimeout is the default timeout for inactivity announcement
 const DefaultInactivityTimeout = 5 * time.Minute
 
+// Low-priority lane limits. Announcements are only useful while they are current,
+// so the lane is kept short and stale entries are dropped instead of being spoken.
+const (
+	MaxLowPriorityQueueSize = 5
+	LowPriorityMaxAge       = 30 * time.Second
+)
+
+// QueueOrderFor returns the order a guild configuration reads its queue in, FIFO unless
+// round-robin was chosen
+func QueueOrderFor(config *GuildTTSConfig) QueueOrder {
+	if config == nil || config.QueueOrder != QueueOrderRoundRobin {
+		return QueueOrderFIFO
+	}
+	return QueueOrderRoundRobin
+}
+
+// HoldBackFor returns how long a guild configuration holds back a message for more from
+// the same author, 0 when messages are read as soon as they are queued
+func HoldBackFor(config *GuildTTSConfig) time.Duration {
+	if config == nil || config.HoldBackSeconds <= 0 {
+		return 0
+	}
+	return time.Duration(config.HoldBackSeconds) * time.Second
+}
+
 // MessageQueueImpl implements the MessageQueue interface
 type MessageQueueImpl struct {
-	mu     sync.RWMutex
-	queues map[string]*guildQueue
+	mu            sync.RWMutex
+	queues        map[string]*guildQueue
+	eventBus      *events.Bus
+	configService ConfigService   // Nil reads every queue in FIFO order without holding messages back
+	spillover     *queueSpillover // Nil drops messages from full queues
 }
 
-// guildQueue represents a message queue for a specific guild
+// guildQueue represents a message queue for a specific guild. In round-robin order the
+// normal lane is read one message per author in turn, each author's messages in the
+// order they were posted.
 type guildQueue struct {
-	messages       []*QueuedMessage
+	messages       []*QueuedMessage // In the order they were posted
+	lowPriority    []*QueuedMessage // Only dequeued when messages is empty
+	turns          []string         // Authors in the order they take turns in round-robin order
 	maxSize        int
 	lastActivity   time.Time
 	inactivityFunc func(guildID string) // Callback for inactivity handling
@@ -30,6 +65,46 @@ func NewMessageQueue() MessageQueue {
 	}
 }
 
+// SetEventBus publishes every message added to a queue
+func (mq *MessageQueueImpl) SetEventBus(bus *events.Bus) {
+	mq.eventBus = bus
+}
+
+// SetConfigService reads each guild's queue order and hold-back window from its
+// configuration
+func (mq *MessageQueueImpl) SetConfigService(configService ConfigService) {
+	mq.configService = configService
+}
+
+// SetSpillover keeps the messages full queues would drop in dir, up to maxBytes across
+// every guild, encrypted with cipher unless it is nil. Guilds that retain only metadata
+// never have message text written to disk.
+func (mq *MessageQueueImpl) SetSpillover(dir string, maxBytes int64, cipher *StorageCipher, metrics *Metrics) error {
+	spillover, err := newQueueSpillover(dir, maxBytes, cipher, metrics)
+	if err != nil {
+		return err
+	}
+
+	mq.mu.Lock()
+	defer mq.mu.Unlock()
+	mq.spillover = spillover
+	return nil
+}
+
+// guildConfig returns the guild's configuration, or nil when it has none or it cannot
+// be read
+func (mq *MessageQueueImpl) guildConfig(guildID string) *GuildTTSConfig {
+	if mq.configService == nil {
+		return nil
+	}
+
+	config, err := mq.configService.GetGuildConfig(guildID)
+	if err != nil {
+		return nil
+	}
+	return config
+}
+
 // Enqueue adds a message to the queue for the specified guild
 func (mq *MessageQueueImpl) Enqueue(message *QueuedMessage) error {
 	if message == nil {
@@ -44,6 +119,21 @@ func (mq *MessageQueueImpl) Enqueue(message *QueuedMessage) error {
 		return errors.New("message content cannot be empty")
 	}
 
+	mq.add(message, mq.guildConfig(message.GuildID))
+	mq.eventBus.Publish(events.MessageEnqueued{
+		GuildID:      message.GuildID,
+		MessageID:    message.ID,
+		UserID:       message.UserID,
+		Tag:          message.Tag,
+		Announcement: message.Priority != PriorityNormal,
+		QueuedAt:     message.Timestamp,
+	})
+	return nil
+}
+
+// add appends a validated message to its guild's queue, or merges it into the author's
+// previous message while that is held back
+func (mq *MessageQueueImpl) add(message *QueuedMessage, config *GuildTTSConfig) {
 	mq.mu.Lock()
 	defer mq.mu.Unlock()
 
@@ -61,10 +151,32 @@ func (mq *MessageQueueImpl) Enqueue(message *QueuedMessage) error {
 	// Update last activity time
 	queue.lastActivity = time.Now()
 
+	if message.Priority == PriorityLow {
+		if len(queue.lowPriority) >= MaxLowPriorityQueueSize {
+			queue.lowPriority = queue.lowPriority[1:]
+		}
+		queue.lowPriority = append(queue.lowPriority, message)
+		return
+	}
+
+	// Messages on disk are newer than the ones in memory, so while there are any the
+	// message goes after them
+	spilled := mq.spillover.count(message.GuildID)
+	if spilled == 0 && queue.merge(message, HoldBackFor(config), LengthPolicyFor(config).MaxLength) {
+		return
+	}
+
 	// Check if queue is at max capacity (Requirement 4.3)
-	if len(queue.messages) >= queue.maxSize {
+	if spilled > 0 || len(queue.messages) >= queue.maxSize {
+		if mq.spillover != nil && !contentFree(config) && mq.spillover.spill(message) {
+			return
+		}
+		if spilled > 0 {
+			return // Dropping an older message would put this one ahead of those on disk
+		}
+
 		// Remove oldest message and indicate skip
-		queue.messages = queue.messages[1:]
+		queue.remove(queue.overflowIndex(QueueOrderFor(config)))
 
 		// Log or handle the skip indication
 		// In a real implementation, this would notify users about the skip
@@ -73,8 +185,6 @@ func (mq *MessageQueueImpl) Enqueue(message *QueuedMessage) error {
 
 	// Add new message to queue
 	queue.messages = append(queue.messages, message)
-
-	return nil
 }
 
 // Dequeue removes and returns the next message from the queue for the specified guild
@@ -83,19 +193,21 @@ func (mq *MessageQueueImpl) Dequeue(guildID string) (*QueuedMessage, error) {
 		return nil, errors.New("guild ID cannot be empty")
 	}
 
+	config := mq.guildConfig(guildID)
+
 	mq.mu.Lock()
 	defer mq.mu.Unlock()
 
 	queue, exists := mq.queues[guildID]
-	if !exists || len(queue.messages) == 0 {
+	if !exists {
 		return nil, nil // No messages in queue
 	}
 
-	// Get first message
-	message := queue.messages[0]
-
-	// Remove from queue
-	queue.messages = queue.messages[1:]
+	message := queue.next(QueueOrderFor(config), HoldBackFor(config))
+	if message == nil {
+		return nil, nil // No messages in queue
+	}
+	mq.refill(guildID, queue)
 
 	// Update last activity time
 	queue.lastActivity = time.Now()
@@ -103,6 +215,198 @@ func (mq *MessageQueueImpl) Dequeue(guildID string) (*QueuedMessage, error) {
 	return message, nil
 }
 
+// next removes and returns the next message, preferring the normal lane and
+// discarding stale low-priority messages. It returns nil while the next message is
+// held back for more from its author.
+func (q *guildQueue) next(order QueueOrder, holdBack time.Duration) *QueuedMessage {
+	if len(q.messages) > 0 {
+		index, turns := 0, q.turns
+		if order == QueueOrderRoundRobin {
+			index, turns = q.turn()
+		}
+		if q.heldBack(index, holdBack) {
+			return nil
+		}
+		message := q.messages[index]
+		q.turns = turns
+		q.remove(index)
+		return message
+	}
+
+	for len(q.lowPriority) > 0 {
+		message := q.lowPriority[0]
+		q.lowPriority = q.lowPriority[1:]
+		if time.Since(message.Timestamp) <= LowPriorityMaxAge {
+			return message
+		}
+	}
+
+	return nil
+}
+
+// refill moves a guild's spilled messages back into memory while its queue has room
+// (caller must hold the lock)
+func (mq *MessageQueueImpl) refill(guildID string, queue *guildQueue) {
+	for len(queue.messages) < queue.maxSize && mq.spillover.count(guildID) > 0 {
+		if message := mq.spillover.pop(guildID); message != nil {
+			queue.messages = append(queue.messages, message)
+		}
+	}
+}
+
+// contentFree reports whether a guild configuration retains only metadata
+func contentFree(config *GuildTTSConfig) bool {
+	return config != nil && config.ContentRetention == ContentRetentionMetadata
+}
+
+// remove drops the normal-lane message at index
+func (q *guildQueue) remove(index int) {
+	if index == 0 {
+		q.messages = q.messages[1:]
+		return
+	}
+	q.messages = append(q.messages[:index], q.messages[index+1:]...)
+}
+
+// rotation returns the authors with queued messages in the order they take turns.
+// Authors already taking turns keep their place and newcomers join at the back in the
+// order they posted.
+func (q *guildQueue) rotation() []string {
+	queued := make(map[string]bool)
+	var newcomers []string
+	for _, message := range q.messages {
+		if !queued[message.UserID] {
+			queued[message.UserID] = true
+			newcomers = append(newcomers, message.UserID)
+		}
+	}
+
+	rotation := make([]string, 0, len(newcomers))
+	taking := make(map[string]bool)
+	for _, author := range q.turns {
+		if queued[author] && !taking[author] {
+			taking[author] = true
+			rotation = append(rotation, author)
+		}
+	}
+	for _, author := range newcomers {
+		if !taking[author] {
+			rotation = append(rotation, author)
+		}
+	}
+	return rotation
+}
+
+// turn returns the index of the oldest message of the author whose turn it is, and the
+// turns after the author has had theirs
+func (q *guildQueue) turn() (int, []string) {
+	rotation := q.rotation()
+	author := rotation[0]
+	turns := append(rotation[1:], author)
+
+	for index, message := range q.messages {
+		if message.UserID == author {
+			return index, turns
+		}
+	}
+	return 0, turns
+}
+
+// heldBack reports whether the normal-lane message at index is still waiting for more
+// from its author. Only the newest message can be merged into, so anyone else posting
+// releases it right away.
+func (q *guildQueue) heldBack(index int, holdBack time.Duration) bool {
+	if holdBack <= 0 || index != len(q.messages)-1 {
+		return false
+	}
+	message := q.messages[index]
+	return message.Lead != "" && time.Since(message.Timestamp) < holdBack
+}
+
+// merge appends a message to the newest queued message when both are from the same
+// author in the same channel and the newer one arrived within the hold-back window,
+// reading them as one utterance under a single "Alice says:". It reports whether the
+// message was merged.
+func (q *guildQueue) merge(message *QueuedMessage, holdBack time.Duration, maxLength int) bool {
+	if holdBack <= 0 || message.Lead == "" || message.Part != 0 || len(q.messages) == 0 {
+		return false
+	}
+
+	last := q.messages[len(q.messages)-1]
+	if last.Lead != message.Lead || last.UserID != message.UserID || last.ChannelID != message.ChannelID || last.Part != 0 {
+		return false
+	}
+	if message.Timestamp.Sub(last.Timestamp) >= holdBack {
+		return false
+	}
+
+	text := strings.TrimSpace(strings.TrimPrefix(message.Content, message.Lead))
+	separator := ". "
+	if strings.HasSuffix(last.Content, ".") || strings.HasSuffix(last.Content, "!") || strings.HasSuffix(last.Content, "?") {
+		separator = " "
+	}
+	content := last.Content + separator + text
+	if len(content) > maxLength {
+		return false
+	}
+
+	// The queued message may be shown elsewhere, so it is replaced rather than changed
+	merged := *last
+	merged.Content = content
+	merged.Timestamp = message.Timestamp
+	q.messages[len(q.messages)-1] = &merged
+	return true
+}
+
+// ordered returns the normal-lane messages in the order they will be read
+func (q *guildQueue) ordered(order QueueOrder) []*QueuedMessage {
+	if order != QueueOrderRoundRobin {
+		return q.messages
+	}
+
+	lanes := make(map[string][]*QueuedMessage)
+	for _, message := range q.messages {
+		lanes[message.UserID] = append(lanes[message.UserID], message)
+	}
+
+	rotation := q.rotation()
+	messages := make([]*QueuedMessage, 0, len(q.messages))
+	for len(messages) < len(q.messages) {
+		for _, author := range rotation {
+			if lane := lanes[author]; len(lane) > 0 {
+				messages = append(messages, lane[0])
+				lanes[author] = lane[1:]
+			}
+		}
+	}
+	return messages
+}
+
+// overflowIndex returns the index of the message dropped when a full queue takes another
+// one: the oldest, or in round-robin order the oldest of the author with the most queued
+// messages, so one author cannot push everyone else's messages out
+func (q *guildQueue) overflowIndex(order QueueOrder) int {
+	if order != QueueOrderRoundRobin {
+		return 0
+	}
+
+	counts := make(map[string]int)
+	busiest := ""
+	for _, message := range q.messages {
+		counts[message.UserID]++
+		if counts[message.UserID] > counts[busiest] {
+			busiest = message.UserID
+		}
+	}
+
+	for index, message := range q.messages {
+		if message.UserID == busiest {
+			return index
+		}
+	}
+	return 0
+}
+
 // Clear removes all messages from the queue for the specified guild
 func (mq *MessageQueueImpl) Clear(guildID string) error {
 	if guildID == "" {
@@ -118,7 +422,10 @@ func (mq *MessageQueueImpl) Clear(guildID string) error {
 	}
 
 	// Clear all messages
+	mq.spillover.discard(guildID)
 	queue.messages = queue.messages[:0]
+	queue.lowPriority = queue.lowPriority[:0]
+	queue.turns = nil
 	queue.lastActivity = time.Now()
 
 	return nil
@@ -138,7 +445,31 @@ func (mq *MessageQueueImpl) Size(guildID string) int {
 		return 0
 	}
 
-	return len(queue.messages)
+	return len(queue.messages) + len(queue.lowPriority) + mq.spillover.count(guildID)
+}
+
+// List returns the messages waiting in a guild's queue in the order they will be read,
+// without removing them. Low-priority messages too old to be spoken are left out, and
+// so are messages spilled to disk, which are read after all of the listed ones.
+func (mq *MessageQueueImpl) List(guildID string) []*QueuedMessage {
+	order := QueueOrderFor(mq.guildConfig(guildID))
+
+	mq.mu.RLock()
+	defer mq.mu.RUnlock()
+
+	queue, exists := mq.queues[guildID]
+	if !exists {
+		return nil
+	}
+
+	messages := make([]*QueuedMessage, 0, len(queue.messages)+len(queue.lowPriority))
+	messages = append(messages, queue.ordered(order)...)
+	for _, message := range queue.lowPriority {
+		if time.Since(message.Timestamp) <= LowPriorityMaxAge {
+			messages = append(messages, message)
+		}
+	}
+	return messages
 }
 
 // SetMaxSize sets the maximum queue size for the specified guild
@@ -178,6 +509,7 @@ func (mq *MessageQueueImpl) SetMaxSize(guildID string, size int) error {
 		// Log or handle the skip indication
 		// Queue size reduction logging removed per user request
 	}
+	mq.refill(guildID, queue)
 
 	return nil
 }
@@ -242,6 +574,7 @@ func (mq *MessageQueueImpl) RemoveGuild(guildID string) error {
 	mq.mu.Lock()
 	defer mq.mu.Unlock()
 
+	mq.spillover.discard(guildID)
 	delete(mq.queues, guildID)
 	return nil
 }
@@ -252,19 +585,22 @@ func (mq *MessageQueueImpl) SkipNext(guildID string) (*QueuedMessage, error) {
 		return nil, errors.New("guild ID cannot be empty")
 	}
 
+	order := QueueOrderFor(mq.guildConfig(guildID))
+
 	mq.mu.Lock()
 	defer mq.mu.Unlock()
 
 	queue, exists := mq.queues[guildID]
-	if !exists || len(queue.messages) == 0 {
+	if !exists {
 		return nil, nil // No messages in queue to skip
 	}
 
-	// Get first message (the one being skipped)
-	skippedMessage := queue.messages[0]
-
-	// Remove from queue
-	queue.messages = queue.messages[1:]
+	// Get next message (the one being skipped), even while it is held back
+	skippedMessage := queue.next(order, 0)
+	if skippedMessage == nil {
+		return nil, nil // No messages in queue to skip
+	}
+	mq.refill(guildID, queue)
 
 	// Update last activity time
 	queue.lastActivity = time.Now()
@@ -1,9 +1,16 @@
 package tts
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
+
+	"darrot/internal/config"
+	"darrot/internal/events"
 )
 
 func TestNewMessageQueue(t *testing.T) {
@@ -953,3 +960,394 @@ func TestMessageQueue_SkipNext_ConcurrentAccess(t *testing.T) {
 		t.Errorf("Expected final queue size 0, got %d", finalSize)
 	}
 }
+
+func TestMessageQueue_LowPriorityLane(t *testing.T) {
+	mq := NewMessageQueue()
+	guildID := "guild123"
+
+	announcement := &QueuedMessage{ID: "a1", GuildID: guildID, Content: "Bob joined the channel", Priority: PriorityLow, Timestamp: time.Now()}
+	if err := mq.Enqueue(announcement); err != nil {
+		t.Fatalf("Failed to enqueue announcement: %v", err)
+	}
+	chat := &QueuedMessage{ID: "m1", GuildID: guildID, Content: "hello", Timestamp: time.Now()}
+	if err := mq.Enqueue(chat); err != nil {
+		t.Fatalf("Failed to enqueue message: %v", err)
+	}
+
+	if size := mq.Size(guildID); size != 2 {
+		t.Errorf("Expected size 2, got %d", size)
+	}
+
+	// Chat messages are always played before announcements
+	first, _ := mq.Dequeue(guildID)
+	if first == nil || first.ID != "m1" {
+		t.Fatalf("Expected chat message first, got %+v", first)
+	}
+	second, _ := mq.Dequeue(guildID)
+	if second == nil || second.ID != "a1" {
+		t.Fatalf("Expected announcement second, got %+v", second)
+	}
+}
+
+func TestMessageQueue_LowPriorityLaneLimits(t *testing.T) {
+	mq := NewMessageQueue()
+	guildID := "guild123"
+
+	// Stale announcements are dropped
+	stale := &QueuedMessage{ID: "stale", GuildID: guildID, Content: "old news", Priority: PriorityLow, Timestamp: time.Now().Add(-2 * LowPriorityMaxAge)}
+	if err := mq.Enqueue(stale); err != nil {
+		t.Fatalf("Failed to enqueue announcement: %v", err)
+	}
+	if msg, _ := mq.Dequeue(guildID); msg != nil {
+		t.Errorf("Expected stale announcement to be dropped, got %+v", msg)
+	}
+
+	// The lane is bounded and keeps the newest announcements
+	for i := 0; i < MaxLowPriorityQueueSize+3; i++ {
+		mq.Enqueue(&QueuedMessage{ID: fmt.Sprintf("a%d", i), GuildID: guildID, Content: "announcement", Priority: PriorityLow, Timestamp: time.Now()})
+	}
+	if size := mq.Size(guildID); size != MaxLowPriorityQueueSize {
+		t.Errorf("Expected size %d, got %d", MaxLowPriorityQueueSize, size)
+	}
+	if msg, _ := mq.Dequeue(guildID); msg == nil || msg.ID != "a3" {
+		t.Errorf("Expected oldest announcements to be discarded, got %+v", msg)
+	}
+
+	// Announcements do not count against the chat queue size
+	mq.SetMaxSize(guildID, 1)
+	mq.Enqueue(&QueuedMessage{ID: "m1", GuildID: guildID, Content: "hello", Timestamp: time.Now()})
+	if msg, _ := mq.Dequeue(guildID); msg == nil || msg.ID != "m1" {
+		t.Errorf("Expected chat message to survive, got %+v", msg)
+	}
+
+	mq.Clear(guildID)
+	if size := mq.Size(guildID); size != 0 {
+		t.Errorf("Expected cleared queue, got size %d", size)
+	}
+}
+
+func TestMessageQueue_PublishesEnqueuedMessages(t *testing.T) {
+	mq := NewMessageQueue().(*MessageQueueImpl)
+	bus := events.New()
+	mq.SetEventBus(bus)
+
+	var enqueued []events.MessageEnqueued
+	events.Subscribe(bus, func(e events.MessageEnqueued) { enqueued = append(enqueued, e) })
+
+	messages := []*QueuedMessage{
+		{ID: "m1", GuildID: "guild1", UserID: "user1", Content: "hello", Tag: "overlay"},
+		{ID: "a1", GuildID: "guild1", Content: "ann joined", Priority: PriorityLow},
+		{ID: "bad", GuildID: "guild1"}, // Rejected without content
+	}
+	for _, message := range messages {
+		_ = mq.Enqueue(message)
+	}
+
+	if len(enqueued) != 2 {
+		t.Fatalf("Expected 2 enqueued events, got %+v", enqueued)
+	}
+	if enqueued[0].MessageID != "m1" || enqueued[0].UserID != "user1" || enqueued[0].Tag != "overlay" || enqueued[0].Announcement {
+		t.Errorf("Unexpected event for the chat message: %+v", enqueued[0])
+	}
+	if !enqueued[1].Announcement {
+		t.Errorf("Expected the low-priority message to be an announcement: %+v", enqueued[1])
+	}
+}
+
+func TestMessageQueue_RoundRobinOrder(t *testing.T) {
+	storage, err := NewStorageService(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	configService := NewConfigService(storage, config.TTSConfig{DefaultVoice: DefaultVoice, DefaultSpeed: 1.0, DefaultVolume: 1.0, MaxQueueSize: 10})
+	guildID := "guild123"
+	guildConfig := DefaultGuildTTSConfig(guildID)
+	guildConfig.QueueOrder = QueueOrderRoundRobin
+	if err := configService.SetGuildConfig(guildID, &guildConfig); err != nil {
+		t.Fatalf("Failed to set guild config: %v", err)
+	}
+
+	mq := NewMessageQueue().(*MessageQueueImpl)
+	mq.SetConfigService(configService)
+	mq.SetMaxSize(guildID, 5)
+
+	// Alice posts in a burst before Bob and Carol get a word in
+	for _, message := range []struct{ id, user string }{
+		{"a1", "alice"}, {"a2", "alice"}, {"a3", "alice"}, {"b1", "bob"}, {"c1", "carol"}, {"a4", "alice"},
+	} {
+		if err := mq.Enqueue(&QueuedMessage{ID: message.id, GuildID: guildID, UserID: message.user, Content: "hello", Timestamp: time.Now()}); err != nil {
+			t.Fatalf("Failed to enqueue %s: %v", message.id, err)
+		}
+	}
+
+	// The overflow came out of Alice's messages, not Bob's or Carol's
+	var listed []string
+	for _, message := range mq.List(guildID) {
+		listed = append(listed, message.ID)
+	}
+	if got := fmt.Sprint(listed); got != "[a2 b1 c1 a3 a4]" {
+		t.Errorf("Unexpected listed order: %s", got)
+	}
+
+	var read []string
+	for {
+		message, err := mq.Dequeue(guildID)
+		if err != nil {
+			t.Fatalf("Failed to dequeue: %v", err)
+		}
+		if message == nil {
+			break
+		}
+		read = append(read, message.ID)
+	}
+	if got := fmt.Sprint(read); got != fmt.Sprint(listed) {
+		t.Errorf("Expected messages to be read in the listed order %v, got %s", listed, got)
+	}
+
+	// Without a round-robin configuration the queue stays FIFO
+	if order := QueueOrderFor(nil); order != QueueOrderFIFO {
+		t.Errorf("Expected FIFO by default, got %s", order)
+	}
+}
+
+func TestMessageQueue_HoldBackMergesBursts(t *testing.T) {
+	storage, err := NewStorageService(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	configService := NewConfigService(storage, config.TTSConfig{DefaultVoice: DefaultVoice, DefaultSpeed: 1.0, DefaultVolume: 1.0, MaxQueueSize: 10})
+	guildID := "guild123"
+	guildConfig := DefaultGuildTTSConfig(guildID)
+	guildConfig.HoldBackSeconds = 2
+	if err := configService.SetGuildConfig(guildID, &guildConfig); err != nil {
+		t.Fatalf("Failed to set guild config: %v", err)
+	}
+
+	mq := NewMessageQueue().(*MessageQueueImpl)
+	mq.SetConfigService(configService)
+
+	chat := func(id, user, text string, at time.Time) *QueuedMessage {
+		lead := user + " says:"
+		return &QueuedMessage{ID: id, GuildID: guildID, ChannelID: "channel1", UserID: user, Username: user, Content: lead + " " + text, Lead: lead, Timestamp: at}
+	}
+
+	now := time.Now()
+	mq.Enqueue(chat("m1", "alice", "hi", now.Add(-3*time.Second)))
+	mq.Enqueue(chat("m2", "alice", "how are you?", now.Add(-2500*time.Millisecond)))
+	mq.Enqueue(chat("m3", "alice", "anyone here", now.Add(-2*time.Second)))
+	if size := mq.Size(guildID); size != 1 {
+		t.Fatalf("Expected the burst to be merged into one message, got size %d", size)
+	}
+
+	// Someone else posting releases Alice's burst right away
+	mq.Enqueue(chat("m4", "bob", "yes", now))
+	first, _ := mq.Dequeue(guildID)
+	if first == nil || first.ID != "m1" || first.Content != "alice says: hi. how are you? anyone here" {
+		t.Fatalf("Unexpected merged message: %+v", first)
+	}
+
+	// The newest message waits out the hold-back window for more from its author
+	if message, _ := mq.Dequeue(guildID); message != nil {
+		t.Fatalf("Expected Bob's message to be held back, got %+v", message)
+	}
+	if skipped, _ := mq.SkipNext(guildID); skipped == nil || skipped.ID != "m4" {
+		t.Errorf("Expected skipping to take the held message, got %+v", skipped)
+	}
+
+	// Messages outside the window are read separately
+	mq.Enqueue(chat("m5", "alice", "one", now.Add(-5*time.Second)))
+	mq.Enqueue(chat("m6", "alice", "two", now.Add(-2*time.Second)))
+	if size := mq.Size(guildID); size != 2 {
+		t.Errorf("Expected messages outside the window to stay apart, got size %d", size)
+	}
+}
+
+func TestMessageQueue_SpilloverKeepsBursts(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "spillover")
+	metrics := NewMetrics()
+	mq := NewMessageQueue().(*MessageQueueImpl)
+	if err := mq.SetSpillover(dir, 1<<20, nil, metrics); err != nil {
+		t.Fatalf("Failed to set up spillover: %v", err)
+	}
+	guildID := "guild123"
+	mq.SetMaxSize(guildID, 3)
+
+	for i := 1; i <= 8; i++ {
+		mq.Enqueue(&QueuedMessage{ID: fmt.Sprintf("m%d", i), GuildID: guildID, UserID: "alice", Content: "hello", Timestamp: time.Now()})
+	}
+	if size := mq.Size(guildID); size != 8 {
+		t.Errorf("Expected all 8 messages to be kept, got size %d", size)
+	}
+	if listed := len(mq.List(guildID)); listed != 3 {
+		t.Errorf("Expected only the messages in memory to be listed, got %d", listed)
+	}
+	if spilled := metrics.Value(MetricQueueSpilledTotal, Labels{"guild": guildID}); spilled != 5 {
+		t.Errorf("Expected 5 spilled messages, got %v", spilled)
+	}
+	if used := metrics.Value(MetricQueueSpilloverBytes, nil); used <= 0 {
+		t.Errorf("Expected spillover disk usage to be reported, got %v", used)
+	}
+
+	// Spilled messages come back in the order they were posted
+	var read []string
+	for {
+		message, _ := mq.Dequeue(guildID)
+		if message == nil {
+			break
+		}
+		read = append(read, message.ID)
+	}
+	if got := fmt.Sprint(read); got != "[m1 m2 m3 m4 m5 m6 m7 m8]" {
+		t.Errorf("Unexpected read order: %s", got)
+	}
+
+	// Drained segments are removed
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("Expected no segments left, found %d", len(entries))
+	}
+	if used := metrics.Value(MetricQueueSpilloverBytes, nil); used != 0 {
+		t.Errorf("Expected no spillover disk usage, got %v", used)
+	}
+}
+
+func TestMessageQueue_SpilloverSizeLimit(t *testing.T) {
+	metrics := NewMetrics()
+	mq := NewMessageQueue().(*MessageQueueImpl)
+	// Every message is written with the same length; time.Now() would drop trailing zeros
+	// from the nanoseconds
+	queuedAt := time.Date(2026, 1, 10, 12, 0, 0, 123456789, time.UTC)
+	message := func(id string) *QueuedMessage {
+		return &QueuedMessage{ID: id, GuildID: "guild123", UserID: "alice", Content: "hello", Timestamp: queuedAt}
+	}
+
+	// Room for exactly two spilled messages
+	line, _ := json.Marshal(message("m0"))
+	if err := mq.SetSpillover(t.TempDir(), int64(2*(len(line)+1)), nil, metrics); err != nil {
+		t.Fatalf("Failed to set up spillover: %v", err)
+	}
+	mq.SetMaxSize("guild123", 1)
+
+	for i := 1; i <= 5; i++ {
+		mq.Enqueue(message(fmt.Sprintf("m%d", i)))
+	}
+	if size := mq.Size("guild123"); size != 3 {
+		t.Errorf("Expected one message in memory and two on disk, got size %d", size)
+	}
+	if rejected := metrics.Value(MetricQueueSpillRejected, Labels{"guild": "guild123"}); rejected != 2 {
+		t.Errorf("Expected 2 rejected messages, got %v", rejected)
+	}
+
+	// Reading a message frees its space for the next one
+	mq.Dequeue("guild123")
+	mq.Enqueue(message("m6"))
+
+	var read []string
+	for {
+		next, _ := mq.Dequeue("guild123")
+		if next == nil {
+			break
+		}
+		read = append(read, next.ID)
+	}
+	if got := fmt.Sprint(read); got != "[m2 m3 m6]" {
+		t.Errorf("Unexpected read order: %s", got)
+	}
+}
+
+func TestMessageQueue_SpilloverEncrypted(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "spillover")
+	mq := NewMessageQueue().(*MessageQueueImpl)
+	if err := mq.SetSpillover(dir, 1<<20, newTestCipher(t, testStorageKey(1)), nil); err != nil {
+		t.Fatalf("Failed to set up spillover: %v", err)
+	}
+	mq.SetMaxSize("guild123", 1)
+	mq.SetMaxSize("guild456", 1)
+
+	for _, guildID := range []string{"guild123", "guild456"} {
+		for i := 1; i <= 3; i++ {
+			mq.Enqueue(&QueuedMessage{ID: fmt.Sprintf("m%d", i), GuildID: guildID, UserID: "alice", Content: "secret words", Timestamp: time.Now()})
+		}
+	}
+
+	// Neither the text nor the author is written to disk in the clear
+	data, err := os.ReadFile(filepath.Join(dir, "queue_guild123.jsonl"))
+	if err != nil {
+		t.Fatalf("Failed to read segment: %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 spilled messages, got %d lines", len(lines))
+	}
+	for _, line := range lines {
+		if _, ok := parseEncryptedFile([]byte(line)); !ok || strings.Contains(line, "secret") || strings.Contains(line, "alice") {
+			t.Errorf("Expected an encrypted line, got %s", line)
+		}
+	}
+
+	var read []string
+	for {
+		message, _ := mq.Dequeue("guild123")
+		if message == nil {
+			break
+		}
+		read = append(read, message.ID+":"+message.Content)
+	}
+	if got := fmt.Sprint(read); got != "[m1:secret words m2:secret words m3:secret words]" {
+		t.Errorf("Unexpected messages read back: %s", got)
+	}
+
+	// A message moved to another guild's segment cannot be read back
+	other := filepath.Join(dir, "queue_guild456.jsonl")
+	if err := os.WriteFile(other, []byte(lines[0]+"\n"+lines[1]+"\n"), 0600); err != nil {
+		t.Fatalf("Failed to overwrite segment: %v", err)
+	}
+	mq.Dequeue("guild456")
+	if message, _ := mq.Dequeue("guild456"); message != nil {
+		t.Errorf("Expected the moved message to be rejected, got %s", message.ID)
+	}
+}
+
+func TestMessageQueue_SpilloverClearAndRetention(t *testing.T) {
+	storage, err := NewStorageService(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	configService := NewConfigService(storage, config.TTSConfig{DefaultVoice: DefaultVoice, DefaultSpeed: 1.0, DefaultVolume: 1.0, MaxQueueSize: 10})
+	private := DefaultGuildTTSConfig("private")
+	private.ContentRetention = ContentRetentionMetadata
+	if err := configService.SetGuildConfig("private", &private); err != nil {
+		t.Fatalf("Failed to set guild config: %v", err)
+	}
+
+	dir := storage.QueueSpilloverDir()
+	mq := NewMessageQueue().(*MessageQueueImpl)
+	mq.SetConfigService(configService)
+	if err := mq.SetSpillover(dir, 1<<20, nil, nil); err != nil {
+		t.Fatalf("Failed to set up spillover: %v", err)
+	}
+
+	for _, guildID := range []string{"public", "private"} {
+		mq.SetMaxSize(guildID, 1)
+		for i := 1; i <= 3; i++ {
+			mq.Enqueue(&QueuedMessage{ID: fmt.Sprintf("m%d", i), GuildID: guildID, UserID: "alice", Content: "hello", Timestamp: time.Now()})
+		}
+	}
+
+	// Guilds that retain only metadata drop messages instead of writing them to disk
+	if size := mq.Size("private"); size != 1 {
+		t.Errorf("Expected the metadata-only guild to keep one message, got size %d", size)
+	}
+	if size := mq.Size("public"); size != 3 {
+		t.Errorf("Expected the public guild to keep all 3 messages, got size %d", size)
+	}
+
+	if err := mq.Clear("public"); err != nil {
+		t.Fatalf("Failed to clear queue: %v", err)
+	}
+	if size := mq.Size("public"); size != 0 {
+		t.Errorf("Expected an empty queue after clearing, got size %d", size)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("Expected clearing to remove the segment, found %d files", len(entries))
+	}
+}
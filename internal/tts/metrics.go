@@ -0,0 +1,159 @@
+package tts
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Metric types used in the Prometheus exposition format
+const (
+	MetricTypeCounter = "counter"
+	MetricTypeGauge   = "gauge"
+)
+
+// Labels identifies a single series within a metric
+type Labels map[string]string
+
+// Metrics is a minimal in-process registry of counters and gauges that can be
+// rendered in the Prometheus text exposition format
+type Metrics struct {
+	families map[string]*metricFamily
+	mu       sync.RWMutex
+}
+
+// metricFamily holds every series recorded under one metric name
+type metricFamily struct {
+	name       string
+	help       string
+	metricType string
+	series     map[string]float64
+}
+
+// NewMetrics creates an empty metrics registry
+func NewMetrics() *Metrics {
+	return &Metrics{
+		families: make(map[string]*metricFamily),
+	}
+}
+
+// Describe registers help text for a metric so it is included in the exposition output
+func (m *Metrics) Describe(name, metricType, help string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	family := m.family(name, metricType)
+	family.help = help
+}
+
+// AddCounter increases a counter series by delta
+func (m *Metrics) AddCounter(name string, labels Labels, delta float64) {
+	if delta < 0 {
+		return // Counters only go up
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.family(name, MetricTypeCounter).series[labels.String()] += delta
+}
+
+// IncCounter increases a counter series by one
+func (m *Metrics) IncCounter(name string, labels Labels) {
+	m.AddCounter(name, labels, 1)
+}
+
+// SetGauge sets a gauge series to value
+func (m *Metrics) SetGauge(name string, labels Labels, value float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.family(name, MetricTypeGauge).series[labels.String()] = value
+}
+
+// Value returns the current value of a series, or 0 if it has not been recorded
+func (m *Metrics) Value(name string, labels Labels) float64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	family, exists := m.families[name]
+	if !exists {
+		return 0
+	}
+	return family.series[labels.String()]
+}
+
+// WritePrometheus writes all metrics in the Prometheus text exposition format
+func (m *Metrics) WritePrometheus(w io.Writer) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	names := make([]string, 0, len(m.families))
+	for name := range m.families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		family := m.families[name]
+		if family.help != "" {
+			if _, err := fmt.Fprintf(w, "# HELP %s %s\n", name, family.help); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(w, "# TYPE %s %s\n", name, family.metricType); err != nil {
+			return err
+		}
+
+		keys := make([]string, 0, len(family.series))
+		for key := range family.series {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			if _, err := fmt.Fprintf(w, "%s%s %v\n", name, key, family.series[key]); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// family returns the metric family for name, creating it if needed (caller must hold the lock)
+func (m *Metrics) family(name, metricType string) *metricFamily {
+	family, exists := m.families[name]
+	if !exists {
+		family = &metricFamily{
+			name:       name,
+			metricType: metricType,
+			series:     make(map[string]float64),
+		}
+		m.families[name] = family
+	}
+	return family
+}
+
+// String renders labels in Prometheus form, e.g. {guild="123",mode="cache"}
+func (l Labels) String() string {
+	if len(l) == 0 {
+		return ""
+	}
+
+	keys := make([]string, 0, len(l))
+	for key := range l {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		value := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(l[key])
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, key, value))
+	}
+
+	return "{" + strings.Join(pairs, ",") + "}"
+}
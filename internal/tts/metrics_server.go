@@ -0,0 +1,85 @@
+package tts
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"time"
+)
+
+// prometheusContentType is the content type of the Prometheus text exposition format
+const prometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// MetricsServer serves the metrics registry at GET /metrics for Prometheus to scrape.
+// It has no authentication, since the series name guilds but never message text, so it
+// should listen on an address only the scraper can reach.
+type MetricsServer struct {
+	metrics *Metrics
+	server  *http.Server
+	logger  *log.Logger
+}
+
+// NewMetricsServer creates a metrics server that listens on address once started
+func NewMetricsServer(address string, metrics *Metrics, logger *log.Logger) *MetricsServer {
+	m := &MetricsServer{
+		metrics: metrics,
+		logger:  logger,
+	}
+	m.server = &http.Server{
+		Addr:              address,
+		Handler:           m.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	return m
+}
+
+// Handler returns the metrics server's HTTP handler
+func (m *MetricsServer) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /metrics", m.handleMetrics)
+	return mux
+}
+
+// Start listens on the configured address and serves scrapes in the background
+func (m *MetricsServer) Start() error {
+	listener, err := net.Listen("tcp", m.server.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", m.server.Addr, err)
+	}
+
+	go func() {
+		if err := m.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			m.logger.Printf("Metrics endpoint stopped: %v", err)
+		}
+	}()
+
+	m.logger.Printf("Metrics endpoint listening on %s/metrics", listener.Addr())
+	return nil
+}
+
+// Stop stops accepting scrapes and waits briefly for those in progress
+func (m *MetricsServer) Stop() error {
+	ctx, cancel := context.WithTimeout(context.Background(), apiShutdownTimeout)
+	defer cancel()
+	return m.server.Shutdown(ctx)
+}
+
+// handleMetrics handles GET /metrics. The exposition is rendered before anything is
+// written, so a failure becomes an error status instead of a truncated scrape.
+func (m *MetricsServer) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	var body bytes.Buffer
+	if err := m.metrics.WritePrometheus(&body); err != nil {
+		m.logger.Printf("Failed to render metrics: %v", err)
+		http.Error(w, "failed to render metrics", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", prometheusContentType)
+	if _, err := body.WriteTo(w); err != nil {
+		m.logger.Printf("Failed to write metrics: %v", err)
+	}
+}
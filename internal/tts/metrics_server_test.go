@@ -0,0 +1,72 @@
+package tts
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"darrot/internal/config"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scrapeMetrics scrapes the metrics the way Prometheus does and returns the exposition
+func scrapeMetrics(t *testing.T, metrics *Metrics) string {
+	t.Helper()
+
+	server := httptest.NewServer(NewMetricsServer("127.0.0.1:0", metrics, log.New(io.Discard, "", 0)).Handler())
+	t.Cleanup(server.Close)
+
+	response, err := http.Get(server.URL + "/metrics")
+	require.NoError(t, err)
+	defer response.Body.Close()
+	body, err := io.ReadAll(response.Body)
+	require.NoError(t, err)
+
+	require.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, prometheusContentType, response.Header.Get("Content-Type"))
+	return string(body)
+}
+
+func TestMetricsServer_ServesPrometheusMetrics(t *testing.T) {
+	metrics := NewMetrics()
+	metrics.Describe(MetricTTSCharactersTotal, MetricTypeCounter, "Characters synthesized")
+	metrics.AddCounter(MetricTTSCharactersTotal, Labels{"guild": "guild1"}, 120)
+	metrics.SetGauge(MetricTTSQuotaUsed, Labels{"guild": "guild1"}, 120)
+
+	output := scrapeMetrics(t, metrics)
+	assert.Contains(t, output, "# HELP darrot_tts_characters_total Characters synthesized\n# TYPE darrot_tts_characters_total counter\n")
+	assert.Contains(t, output, `darrot_tts_characters_total{guild="guild1"} 120`+"\n")
+	assert.Contains(t, output, `darrot_tts_quota_used_characters{guild="guild1"} 120`+"\n")
+
+	server := httptest.NewServer(NewMetricsServer("127.0.0.1:0", metrics, log.New(io.Discard, "", 0)).Handler())
+	t.Cleanup(server.Close)
+	response, err := http.Post(server.URL+"/metrics", "text/plain", nil)
+	require.NoError(t, err)
+	response.Body.Close()
+	assert.Equal(t, http.StatusMethodNotAllowed, response.StatusCode)
+}
+
+func TestMetricsServer_StartStop(t *testing.T) {
+	server := NewMetricsServer("127.0.0.1:0", NewMetrics(), log.New(io.Discard, "", 0))
+
+	require.NoError(t, server.Start())
+	assert.NoError(t, server.Stop())
+}
+
+func TestTTSSystem_ServesMetricsWhenConfigured(t *testing.T) {
+	storage, err := NewStorageService(t.TempDir())
+	require.NoError(t, err)
+	services := &Services{Storage: storage, TTS: &mockTTSManager{}, Voice: newMockVoiceManager()}
+	cfg := &config.Config{TTS: config.TTSConfig{DefaultVoice: "en-US-Standard-A", DefaultSpeed: 1.0, DefaultVolume: 1.0, MaxQueueSize: 10, MetricsAddress: "127.0.0.1:0"}}
+
+	system, err := NewTTSSystemWithServices(&discordgo.Session{State: discordgo.NewState()}, cfg, log.New(io.Discard, "", 0), services)
+	require.NoError(t, err)
+	require.NotNil(t, system.metricsServer)
+	assert.Same(t, services.Metrics, system.metricsServer.metrics)
+	assert.Contains(t, system.lifecycle.Components(), "metrics endpoint")
+}
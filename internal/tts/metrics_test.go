@@ -0,0 +1,47 @@
+package tts
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetrics_CountersAndGauges(t *testing.T) {
+	metrics := NewMetrics()
+
+	metrics.IncCounter("requests_total", Labels{"guild": "1"})
+	metrics.AddCounter("requests_total", Labels{"guild": "1"}, 2)
+	metrics.AddCounter("requests_total", Labels{"guild": "1"}, -5) // Ignored
+	metrics.SetGauge("queue_size", Labels{"guild": "1"}, 7)
+	metrics.SetGauge("queue_size", Labels{"guild": "1"}, 3)
+
+	assert.Equal(t, float64(3), metrics.Value("requests_total", Labels{"guild": "1"}))
+	assert.Equal(t, float64(3), metrics.Value("queue_size", Labels{"guild": "1"}))
+	assert.Equal(t, float64(0), metrics.Value("requests_total", Labels{"guild": "2"}))
+	assert.Equal(t, float64(0), metrics.Value("missing", nil))
+}
+
+func TestMetrics_WritePrometheus(t *testing.T) {
+	metrics := NewMetrics()
+	metrics.Describe("darrot_test_total", MetricTypeCounter, "Test counter")
+	metrics.IncCounter("darrot_test_total", Labels{"mode": "cache", "guild": "1"})
+	metrics.SetGauge("darrot_test_gauge", nil, 1.5)
+
+	var out strings.Builder
+	require.NoError(t, metrics.WritePrometheus(&out))
+
+	expected := "# TYPE darrot_test_gauge gauge\n" +
+		"darrot_test_gauge 1.5\n" +
+		"# HELP darrot_test_total Test counter\n" +
+		"# TYPE darrot_test_total counter\n" +
+		"darrot_test_total{guild=\"1\",mode=\"cache\"} 1\n"
+	assert.Equal(t, expected, out.String())
+}
+
+func TestLabels_String(t *testing.T) {
+	assert.Equal(t, "", Labels{}.String())
+	assert.Equal(t, `{a="1",b="2"}`, Labels{"b": "2", "a": "1"}.String())
+	assert.Equal(t, `{name="say \"hi\""}`, Labels{"name": `say "hi"`}.String())
+}
@@ -0,0 +1,100 @@
+package tts
+
+import (
+	"log"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// mirrorBleep stands in for blocked words in mirrored text, where the bleep tone is heard
+const mirrorBleep = "███"
+
+// mirrorMessageLimit is the longest message Discord accepts
+const mirrorMessageLimit = 2000
+
+// MirrorMessenger posts messages to text channels
+type MirrorMessenger interface {
+	ChannelMessageSendComplex(channelID string, data *discordgo.MessageSend, options ...discordgo.RequestOption) (*discordgo.Message, error)
+}
+
+// TextMirror posts the text of each message read aloud to the mirror channel of the
+// pairing, in the order it is read, so deaf and hard-of-hearing members can follow the
+// voice channel. Pairings in mirror-only mode are posted to instead of spoken. A nil
+// *TextMirror posts nothing, so the processor can use it unconditionally.
+type TextMirror struct {
+	channelService ChannelService
+	voiceManager   VoiceManager
+	messenger      MirrorMessenger
+	logger         *log.Logger
+}
+
+// NewTextMirror creates a text mirror that posts through messenger
+func NewTextMirror(channelService ChannelService, voiceManager VoiceManager, messenger MirrorMessenger, logger *log.Logger) *TextMirror {
+	return &TextMirror{
+		channelService: channelService,
+		voiceManager:   voiceManager,
+		messenger:      messenger,
+		logger:         logger,
+	}
+}
+
+// Post posts text about to be read in a guild to the mirror channel of the pairing of
+// the bot's voice channel. It reports whether the pairing is in mirror-only mode, in
+// which case the text must not be spoken.
+func (m *TextMirror) Post(guildID string, result *ModerationResult) bool {
+	if m == nil {
+		return false
+	}
+
+	pairing := m.pairing(guildID)
+	if pairing == nil || pairing.MirrorChannelID == "" {
+		return false
+	}
+
+	text := result.Text
+	if len(result.Segments) > 0 {
+		text = strings.Join(result.Segments, " "+mirrorBleep+" ")
+	}
+	text = strings.TrimSpace(text)
+	if len(text) > mirrorMessageLimit {
+		text = cutText(text, mirrorMessageLimit)
+	}
+	if text == "" {
+		return pairing.MirrorOnly
+	}
+
+	message := &discordgo.MessageSend{
+		Content:         text,
+		AllowedMentions: &discordgo.MessageAllowedMentions{}, // Mirrored text never pings anyone
+	}
+	if _, err := m.messenger.ChannelMessageSendComplex(pairing.MirrorChannelID, message); err != nil {
+		m.logger.Printf("Failed to mirror message to channel %s in guild %s: %v", pairing.MirrorChannelID, guildID, err)
+	}
+	return pairing.MirrorOnly
+}
+
+// MirrorOnly reports whether the pairing of the bot's voice channel in a guild is in
+// mirror-only mode, where messages are posted instead of spoken
+func (m *TextMirror) MirrorOnly(guildID string) bool {
+	if m == nil {
+		return false
+	}
+
+	pairing := m.pairing(guildID)
+	return pairing != nil && pairing.MirrorChannelID != "" && pairing.MirrorOnly
+}
+
+// pairing returns the pairing of the voice channel the bot is connected to in a guild
+func (m *TextMirror) pairing(guildID string) *ChannelPairing {
+	connection, exists := m.voiceManager.GetConnection(guildID)
+	if !exists || connection == nil {
+		return nil
+	}
+
+	pairing, err := m.channelService.GetPairing(guildID, connection.ChannelID)
+	if err != nil {
+		return nil
+	}
+	return pairing
+}
@@ -0,0 +1,94 @@
+package tts
+
+import (
+	"io"
+	"log"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createTestTextMirror(t *testing.T) (*TextMirror, *mockChannelServiceIntegration, *mockPrivacyMessenger) {
+	channelService := newMockChannelServiceIntegration()
+	require.NoError(t, channelService.CreatePairing("guild1", "voice1", "text1"))
+
+	voiceManager := newMockVoiceManager()
+	_, err := voiceManager.JoinChannel("guild1", "voice1")
+	require.NoError(t, err)
+
+	messenger := newMockPrivacyMessenger()
+	return NewTextMirror(channelService, voiceManager, messenger, log.New(io.Discard, "", 0)), channelService, messenger
+}
+
+func TestTextMirror_PostsReadText(t *testing.T) {
+	mirror, channelService, messenger := createTestTextMirror(t)
+	require.NoError(t, channelService.SetMirror("guild1", "voice1", "mirror1", false))
+
+	assert.False(t, mirror.Post("guild1", &ModerationResult{Text: "alice says: hi @everyone"}))
+	assert.False(t, mirror.Post("guild1", &ModerationResult{Text: "bob says:   it", Segments: []string{"bob says: ", " it"}}))
+
+	sent := messenger.sent["mirror1"]
+	require.Len(t, sent, 2)
+	assert.Equal(t, "alice says: hi @everyone", sent[0].Content)
+	assert.NotNil(t, sent[0].AllowedMentions, "mirrored text never pings anyone")
+	assert.Empty(t, sent[0].AllowedMentions.Parse)
+	assert.Equal(t, "bob says:  "+mirrorBleep+"  it", sent[1].Content, "blocked words are blacked out")
+}
+
+func TestTextMirror_MirrorOnly(t *testing.T) {
+	mirror, channelService, messenger := createTestTextMirror(t)
+	require.NoError(t, channelService.SetMirror("guild1", "voice1", "mirror1", true))
+
+	assert.True(t, mirror.Post("guild1", &ModerationResult{Text: strings.Repeat("a", 2*mirrorMessageLimit)}))
+
+	sent := messenger.sent["mirror1"]
+	require.Len(t, sent, 1)
+	assert.LessOrEqual(t, len(sent[0].Content), mirrorMessageLimit)
+}
+
+func TestTextMirror_NoMirrorChannel(t *testing.T) {
+	mirror, _, messenger := createTestTextMirror(t)
+
+	assert.False(t, mirror.Post("guild1", &ModerationResult{Text: "alice says: hi"}))
+	assert.False(t, mirror.Post("other", &ModerationResult{Text: "alice says: hi"}), "guilds without a connection are not mirrored")
+	assert.Empty(t, messenger.sent)
+
+	// Processors without a mirror read everything aloud
+	var missing *TextMirror
+	assert.False(t, missing.Post("guild1", &ModerationResult{Text: "alice says: hi"}))
+}
+
+func TestTTSProcessor_MirrorOnlySkipsSpeech(t *testing.T) {
+	ttsManager := &mockTTSManager{}
+	voiceManager := newMockVoiceManager()
+	queue := NewMessageQueue()
+	processor := NewTTSProcessor(ttsManager, voiceManager, queue, newMockConfigService(), newMockUserService()).(*ttsProcessor)
+
+	channelService := newMockChannelServiceIntegration()
+	require.NoError(t, channelService.CreatePairing("guild1", "voice1", "text1"))
+	require.NoError(t, channelService.SetMirror("guild1", "voice1", "mirror1", true))
+	_, err := voiceManager.JoinChannel("guild1", "voice1")
+	require.NoError(t, err)
+
+	messenger := newMockPrivacyMessenger()
+	processor.SetTextMirror(NewTextMirror(channelService, voiceManager, messenger, log.New(io.Discard, "", 0)))
+
+	require.NoError(t, queue.Enqueue(&QueuedMessage{ID: "m1", GuildID: "guild1", Content: "alice says: hi", Timestamp: time.Now()}))
+	processor.processNextMessage("guild1", &guildProcessor{guildID: "guild1"})
+
+	require.Len(t, messenger.sent["mirror1"], 1)
+	assert.Equal(t, "alice says: hi", messenger.sent["mirror1"][0].Content)
+	assert.Empty(t, ttsManager.getCallLog(), "mirror-only messages are never synthesized")
+	assert.NotContains(t, voiceManager.getCallLog(), "PlayAudio")
+
+	// With mirror-only mode off the message is posted and read
+	require.NoError(t, channelService.SetMirror("guild1", "voice1", "mirror1", false))
+	require.NoError(t, queue.Enqueue(&QueuedMessage{ID: "m2", GuildID: "guild1", Content: "bob says: hello", Timestamp: time.Now()}))
+	processor.processNextMessage("guild1", &guildProcessor{guildID: "guild1"})
+
+	assert.Len(t, messenger.sent["mirror1"], 2)
+	assert.Contains(t, voiceManager.getCallLog(), "PlayAudio")
+}
@@ -0,0 +1,236 @@
+package tts
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sync"
+)
+
+// clipDuckingGain is the volume a clip keeps while speech plays over it
+const clipDuckingGain = 0.3
+
+// errMixerFinished is returned when speech is offered to a clip mixer that already ended
+var errMixerFinished = errors.New("clip mixer finished")
+
+// mixFrame writes speech plus clip into dst, one 20ms frame of 48kHz stereo PCM. The clip
+// is scaled by a gain that ramps from fromGain to toGain across the frame so ducking does
+// not click, and the sum is clamped to 16 bits. speech or clip may be shorter than dst
+// or nil, in which case the missing samples are silence.
+func mixFrame(dst, speech, clip []int16, fromGain, toGain float64) {
+	frames := len(dst) / discordChannels
+	for i := range dst {
+		gain := toGain
+		if frames > 1 {
+			gain = fromGain + (toGain-fromGain)*float64(i/discordChannels)/float64(frames-1)
+		}
+
+		var sample float64
+		if i < len(speech) {
+			sample += float64(speech[i])
+		}
+		if i < len(clip) {
+			sample += float64(clip[i]) * gain
+		}
+
+		dst[i] = int16(math.Max(math.MinInt16, math.Min(math.MaxInt16, math.Round(sample))))
+	}
+}
+
+// clipMixer plays a clip and mixes speech offered while it plays over it, ducking the clip
+// for as long as speech is attached. Speech that outlasts the clip is passed through
+// unchanged.
+type clipMixer struct {
+	clip        [][]byte
+	next        int
+	encoder     opusEncoder
+	clipDecoder opusDecoder
+	speechDec   opusDecoder
+	gain        float64 // Clip gain used for the previous frame
+
+	mu         sync.Mutex
+	speech     <-chan []byte
+	speechDone chan error
+	finished   bool
+	done       chan struct{}
+}
+
+// newClipMixer creates a mixer for the clip's Opus frames that encodes with encoder
+func newClipMixer(clip [][]byte, encoder opusEncoder) (*clipMixer, error) {
+	clipDecoder, err := newOpusDecoder(discordSampleRate, discordChannels)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Opus decoder: %w", err)
+	}
+	speechDec, err := newOpusDecoder(discordSampleRate, discordChannels)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Opus decoder: %w", err)
+	}
+
+	return &clipMixer{
+		clip:        clip,
+		encoder:     encoder,
+		clipDecoder: clipDecoder,
+		speechDec:   speechDec,
+		gain:        1,
+		done:        make(chan struct{}),
+	}, nil
+}
+
+// attach offers speech frames to the mixer. It returns a channel that receives the
+// playback result once every frame was consumed, or errMixerFinished if the clip already
+// ended or other speech is still attached.
+func (m *clipMixer) attach(frames <-chan []byte) (<-chan error, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.finished || m.speech != nil {
+		return nil, errMixerFinished
+	}
+
+	m.speech = frames
+	m.speechDone = make(chan error, 1)
+	return m.speechDone, nil
+}
+
+// detach releases the attached speech with its playback result
+func (m *clipMixer) detach(err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.speech != nil {
+		m.speechDone <- err
+		m.speech = nil
+		m.speechDone = nil
+	}
+}
+
+// attached returns the speech currently mixed over the clip, if any
+func (m *clipMixer) attached() <-chan []byte {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.speech
+}
+
+// finish marks the mixer as ended unless speech was attached in the meantime
+func (m *clipMixer) finish() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.speech != nil {
+		return false
+	}
+	m.finished = true
+	return true
+}
+
+// stop ends the mixer early, failing any attached speech with err
+func (m *clipMixer) stop(err error) {
+	m.mu.Lock()
+	m.finished = true
+	m.mu.Unlock()
+
+	m.detach(err)
+}
+
+// run produces mixed Opus frames on out until the clip and all attached speech have been
+// played or stop is closed. out is closed when run returns.
+func (m *clipMixer) run(out chan<- []byte, stop <-chan struct{}) {
+	defer close(out)
+
+	clipPCM := make([]int16, opusSamplesPerFrame)
+	speechPCM := make([]int16, opusSamplesPerFrame)
+	mixed := make([]int16, opusSamplesPerFrame)
+
+	for {
+		speech := m.attached()
+		hasClip := m.next < len(m.clip)
+		if !hasClip && speech == nil {
+			if m.finish() {
+				return
+			}
+			continue
+		}
+
+		// Keep the clip moving while streamed speech is still being synthesized, but wait
+		// for speech once the clip is over
+		var speechFrame []byte
+		if speech != nil {
+			var ok bool
+			if hasClip {
+				select {
+				case speechFrame, ok = <-speech:
+				default:
+					ok = true
+				}
+			} else {
+				select {
+				case speechFrame, ok = <-speech:
+				case <-stop:
+					return
+				}
+			}
+			if !ok {
+				m.detach(nil)
+				continue
+			}
+		}
+
+		frame := speechFrame
+		if hasClip {
+			var err error
+			frame, err = m.mixNext(speechFrame, speech != nil, clipPCM, speechPCM, mixed)
+			if err != nil {
+				log.Printf("[DEBUG] Dropping mixed frame %d: %v", m.next, err)
+				continue
+			}
+		}
+		if frame == nil {
+			continue
+		}
+
+		select {
+		case out <- frame:
+		case <-stop:
+			return
+		}
+	}
+}
+
+// mixNext decodes the next clip frame and the speech frame, if any, and encodes them
+// mixed into one Opus frame. The clip is ducked while speaking is true.
+func (m *clipMixer) mixNext(speechFrame []byte, speaking bool, clipPCM, speechPCM, mixed []int16) ([]byte, error) {
+	clipFrame := m.clip[m.next]
+	m.next++
+
+	clipSamples := clipPCM[:0]
+	if n, err := m.clipDecoder.Decode(clipFrame, clipPCM); err == nil {
+		clipSamples = clipPCM[:n*discordChannels]
+	} else {
+		log.Printf("[DEBUG] Treating clip frame %d as silence: %v", m.next-1, err)
+	}
+
+	var speechSamples []int16
+	if speechFrame != nil {
+		n, err := m.speechDec.Decode(speechFrame, speechPCM)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode speech frame: %w", err)
+		}
+		speechSamples = speechPCM[:n*discordChannels]
+	}
+
+	gain := 1.0
+	if speaking {
+		gain = clipDuckingGain
+	}
+	mixFrame(mixed, speechSamples, clipSamples, m.gain, gain)
+	m.gain = gain
+
+	frame := make([]byte, maxOpusFrameBytes)
+	n, err := m.encoder.Encode(mixed, frame)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode mixed frame: %w", err)
+	}
+	return frame[:n], nil
+}
@@ -0,0 +1,217 @@
+package tts
+
+import (
+	"bytes"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// encodeSilence returns count valid Opus frames of silence
+func encodeSilence(t *testing.T, count int) [][]byte {
+	t.Helper()
+	requireNativeOpus(t)
+
+	encoder, err := NewOpusEncoderPool(dcaBitrate, 1).Get()
+	require.NoError(t, err)
+
+	frames := make([][]byte, count)
+	pcm := make([]int16, opusSamplesPerFrame)
+	for i := range frames {
+		frame := make([]byte, maxOpusFrameBytes)
+		n, err := encoder.Encode(pcm, frame)
+		require.NoError(t, err)
+		frames[i] = frame[:n]
+	}
+	return frames
+}
+
+// newTestClipMixer returns a mixer for a clip of clipFrames silent frames
+func newTestClipMixer(t *testing.T, clipFrames int) *clipMixer {
+	t.Helper()
+	requireNativeOpus(t)
+
+	encoder, err := NewOpusEncoderPool(dcaBitrate, 1).Get()
+	require.NoError(t, err)
+	mixer, err := newClipMixer(encodeSilence(t, clipFrames), encoder)
+	require.NoError(t, err)
+	return mixer
+}
+
+// collectMixed runs mixer until it finishes and returns the frames it produced
+func collectMixed(t *testing.T, mixer *clipMixer) [][]byte {
+	t.Helper()
+
+	out := make(chan []byte)
+	go mixer.run(out, make(chan struct{}))
+
+	var frames [][]byte
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case frame, ok := <-out:
+			if !ok {
+				return frames
+			}
+			frames = append(frames, frame)
+		case <-timeout:
+			t.Fatal("mixer did not finish")
+		}
+	}
+}
+
+func TestMixFrame(t *testing.T) {
+	dst := make([]int16, 4)
+
+	// Speech and clip add up, the clip scaled by the gain
+	mixFrame(dst, []int16{100, -100, 100, -100}, []int16{1000, 1000, 1000, 1000}, 0.5, 0.5)
+	assert.Equal(t, []int16{600, 400, 600, 400}, dst)
+
+	// Sums past 16 bits are clamped instead of wrapping around
+	mixFrame(dst, []int16{30000, -30000, 0, 0}, []int16{10000, -10000, 0, 0}, 1, 1)
+	assert.Equal(t, []int16{math.MaxInt16, math.MinInt16, 0, 0}, dst)
+
+	// Missing samples are silence
+	mixFrame(dst, nil, []int16{1000, 1000}, 1, 1)
+	assert.Equal(t, []int16{1000, 1000, 0, 0}, dst)
+}
+
+func TestMixFrame_RampsGain(t *testing.T) {
+	dst := make([]int16, opusSamplesPerFrame)
+	clip := make([]int16, opusSamplesPerFrame)
+	for i := range clip {
+		clip[i] = 1000
+	}
+
+	mixFrame(dst, nil, clip, 1, clipDuckingGain)
+
+	// Both channels of a sample share the gain, which moves from full volume to ducked
+	assert.Equal(t, int16(1000), dst[0])
+	assert.Equal(t, dst[0], dst[1])
+	assert.Equal(t, int16(1000*clipDuckingGain), dst[len(dst)-1])
+	assert.Greater(t, dst[len(dst)/2], dst[len(dst)-1])
+}
+
+func TestClipMixer_PlaysClipAlone(t *testing.T) {
+	mixer := newTestClipMixer(t, 3)
+
+	frames := collectMixed(t, mixer)
+	assert.Len(t, frames, 3)
+	assert.Equal(t, 1.0, mixer.gain, "the clip is not ducked without speech")
+
+	// Speech that arrives after the clip ended is played by the caller
+	_, err := mixer.attach(make(chan []byte))
+	assert.ErrorIs(t, err, errMixerFinished)
+}
+
+func TestClipMixer_MixesSpeechOverClip(t *testing.T) {
+	mixer := newTestClipMixer(t, 5)
+
+	speech := make(chan []byte, 2)
+	for _, frame := range encodeSilence(t, 2) {
+		speech <- frame
+	}
+	close(speech)
+
+	done, err := mixer.attach(speech)
+	require.NoError(t, err)
+
+	// Short speech is mixed into the clip, which keeps its length
+	frames := collectMixed(t, mixer)
+	assert.Len(t, frames, 5)
+	assert.NoError(t, <-done)
+	assert.Nil(t, mixer.attached())
+}
+
+func TestClipMixer_PassesLongSpeechThrough(t *testing.T) {
+	mixer := newTestClipMixer(t, 2)
+
+	speechFrames := encodeSilence(t, 5)
+	speech := make(chan []byte, len(speechFrames))
+	for _, frame := range speechFrames {
+		speech <- frame
+	}
+	close(speech)
+
+	done, err := mixer.attach(speech)
+	require.NoError(t, err)
+
+	// Only one speech source is mixed at a time
+	_, err = mixer.attach(make(chan []byte))
+	assert.ErrorIs(t, err, errMixerFinished)
+
+	frames := collectMixed(t, mixer)
+	require.Len(t, frames, 5)
+	for i := 2; i < len(frames); i++ {
+		assert.Same(t, &speechFrames[i][0], &frames[i][0], "speech after the clip is sent unchanged")
+	}
+	assert.Equal(t, clipDuckingGain, mixer.gain)
+	assert.NoError(t, <-done)
+}
+
+func TestClipMixer_StopFailsSpeech(t *testing.T) {
+	mixer := newTestClipMixer(t, 2)
+
+	done, err := mixer.attach(make(chan []byte))
+	require.NoError(t, err)
+
+	mixer.stop(assert.AnError)
+	assert.ErrorIs(t, <-done, assert.AnError)
+
+	_, err = mixer.attach(make(chan []byte))
+	assert.ErrorIs(t, err, errMixerFinished)
+}
+
+func TestVoiceManager_MixClip(t *testing.T) {
+	voiceConn := createMockVoiceConnection("guild1", "channel1")
+	voiceConn.OpusSend = make(chan []byte)
+	vm, _ := newStandbyTestManager(t, voiceConn)
+	vm.sendTimeout = time.Minute
+
+	var clip bytes.Buffer
+	for _, frame := range encodeSilence(t, 10) {
+		require.NoError(t, writeDCAFrame(&clip, frame))
+	}
+	var speech bytes.Buffer
+	for _, frame := range encodeSilence(t, 3) {
+		require.NoError(t, writeDCAFrame(&speech, frame))
+	}
+
+	// The clip starts playing without blocking the queue
+	require.NoError(t, vm.MixClip("guild1", clip.Bytes()))
+	vm.mutex.RLock()
+	mixer := vm.mixers["guild1"]
+	vm.mutex.RUnlock()
+	require.NotNil(t, mixer)
+
+	spoken := make(chan error, 1)
+	go func() {
+		spoken <- vm.PlayAudio("guild1", speech.Bytes())
+	}()
+	require.Eventually(t, func() bool { return mixer.attached() != nil }, time.Second, time.Millisecond)
+
+	// The speech plays over the clip instead of after it
+	sent := 0
+	for {
+		select {
+		case <-voiceConn.OpusSend:
+			sent++
+			continue
+		case <-mixer.done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("clip did not finish")
+		}
+		break
+	}
+	sent += len(drainFrames(voiceConn.OpusSend))
+
+	assert.Equal(t, 10, sent)
+	assert.NoError(t, <-spoken)
+
+	vm.mutex.RLock()
+	assert.Empty(t, vm.mixers)
+	vm.mutex.RUnlock()
+}
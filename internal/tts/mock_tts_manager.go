@@ -58,6 +58,12 @@ func (m *MockTTSManager) generateSimpleBeep(textLength int) []byte {
 	return audioData
 }
 
+// NormalizesText reports that text needs no normalization, as the beep does not
+// depend on it
+func (m *MockTTSManager) NormalizesText() bool {
+	return true
+}
+
 // GetSupportedVoices returns mock voice options (required by TTSManager interface)
 func (m *MockTTSManager) GetSupportedVoices() []Voice {
 	return []Voice{
@@ -0,0 +1,335 @@
+package tts
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Moderation limits and defaults
+const (
+	DefaultModerationMode = ModerationModeReplace
+	MaxBlocklistWords     = 200
+	MaxBlockedWordLength  = 50
+	moderationReplacement = "asterisk"
+	bleepFrequency        = 1000 // Hz
+	bleepDuration         = 400 * time.Millisecond
+	bleepAmplitude        = 0.3
+)
+
+// ModerationServiceImpl implements ModerationService with per-guild blocklists stored on disk
+type ModerationServiceImpl struct {
+	storage *StorageService
+	encoder AudioClipEncoder
+
+	mu       sync.RWMutex
+	patterns map[string]*regexp.Regexp // Compiled blocklists by guild, nil when empty
+
+	bleepMu    sync.Mutex
+	bleepAudio []byte
+}
+
+// NewModerationService creates a new moderation service. The encoder renders the bleep
+// tone in the same format as synthesized speech and may be nil if bleep mode is unused.
+func NewModerationService(storage *StorageService, encoder AudioClipEncoder) *ModerationServiceImpl {
+	return &ModerationServiceImpl{
+		storage:  storage,
+		encoder:  encoder,
+		patterns: make(map[string]*regexp.Regexp),
+	}
+}
+
+// Moderate applies the guild's blocklist to text
+func (m *ModerationServiceImpl) Moderate(guildID, text string) (*ModerationResult, error) {
+	settings, err := m.GetSettings(guildID)
+	if err != nil {
+		return nil, err
+	}
+
+	pattern, err := m.pattern(guildID, settings.Blocklist)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ModerationResult{Text: text}
+	if pattern == nil {
+		return result, nil
+	}
+
+	matches := blockedWords(pattern, text)
+	result.Matches = len(matches)
+	if result.Matches == 0 {
+		return result, nil
+	}
+
+	// The text between blocked words
+	segments := make([]string, 0, len(matches)+1)
+	start := 0
+	for _, match := range matches {
+		segments = append(segments, text[start:match[0]])
+		start = match[1]
+	}
+	segments = append(segments, text[start:])
+
+	switch settings.Mode {
+	case ModerationModeSkip:
+		result.Skip = true
+		result.Text = ""
+	case ModerationModeBleep:
+		result.Segments = segments
+		result.Text = strings.Join(result.Segments, " ")
+	default:
+		result.Text = strings.Join(segments, moderationReplacement)
+	}
+
+	return result, nil
+}
+
+// blockedWords returns the start and end of each blocked word in text. The pattern
+// matches the characters around a word, so each search starts at the character after
+// the previous word, which can also come before the next one.
+func blockedWords(pattern *regexp.Regexp, text string) [][]int {
+	var matches [][]int
+	for offset := 0; offset < len(text); {
+		match := pattern.FindStringSubmatchIndex(text[offset:])
+		if match == nil {
+			break
+		}
+		matches = append(matches, []int{offset + match[2], offset + match[3]})
+		offset += match[3]
+	}
+	return matches
+}
+
+// pattern returns the compiled blocklist for a guild, or nil if the blocklist is empty
+func (m *ModerationServiceImpl) pattern(guildID string, blocklist []string) (*regexp.Regexp, error) {
+	m.mu.RLock()
+	pattern, cached := m.patterns[guildID]
+	m.mu.RUnlock()
+	if cached {
+		return pattern, nil
+	}
+
+	// Blocked words match whole words in any script: \b only knows ASCII letters, so a
+	// blocked "dchen" would match inside "Mädchen"
+	if len(blocklist) > 0 {
+		quoted := make([]string, len(blocklist))
+		for i, word := range blocklist {
+			quoted[i] = regexp.QuoteMeta(word)
+		}
+
+		var err error
+		pattern, err = regexp.Compile(`(?i)(?:^|[^\p{L}\p{N}])(` + strings.Join(quoted, "|") + `)(?:$|[^\p{L}\p{N}])`)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compile blocklist: %w", err)
+		}
+	}
+
+	m.mu.Lock()
+	m.patterns[guildID] = pattern
+	m.mu.Unlock()
+
+	return pattern, nil
+}
+
+// GetSettings returns the moderation settings for a guild
+func (m *ModerationServiceImpl) GetSettings(guildID string) (*ModerationSettings, error) {
+	settings, err := m.storage.LoadModerationSettings(guildID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load moderation settings: %w", err)
+	}
+	if settings.Mode == "" {
+		settings.Mode = DefaultModerationMode
+	}
+	return settings, nil
+}
+
+// SetMode changes how blocked words are handled for a guild
+func (m *ModerationServiceImpl) SetMode(guildID string, mode ModerationMode) error {
+	switch mode {
+	case ModerationModeSkip, ModerationModeBleep, ModerationModeReplace:
+	default:
+		return fmt.Errorf("invalid moderation mode: %s", mode)
+	}
+
+	return m.update(guildID, func(settings *ModerationSettings) error {
+		settings.Mode = mode
+		return nil
+	})
+}
+
+// AddWords adds words to a guild's blocklist
+func (m *ModerationServiceImpl) AddWords(guildID string, words []string) error {
+	normalized, err := normalizeBlockedWords(words)
+	if err != nil {
+		return err
+	}
+
+	return m.update(guildID, func(settings *ModerationSettings) error {
+		existing := make(map[string]bool, len(settings.Blocklist))
+		for _, word := range settings.Blocklist {
+			existing[word] = true
+		}
+		for _, word := range normalized {
+			if !existing[word] {
+				settings.Blocklist = append(settings.Blocklist, word)
+				existing[word] = true
+			}
+		}
+
+		if len(settings.Blocklist) > MaxBlocklistWords {
+			return fmt.Errorf("blocklist can contain at most %d words", MaxBlocklistWords)
+		}
+
+		sort.Strings(settings.Blocklist)
+		return nil
+	})
+}
+
+// RemoveWords removes words from a guild's blocklist
+func (m *ModerationServiceImpl) RemoveWords(guildID string, words []string) error {
+	normalized, err := normalizeBlockedWords(words)
+	if err != nil {
+		return err
+	}
+
+	return m.update(guildID, func(settings *ModerationSettings) error {
+		remove := make(map[string]bool, len(normalized))
+		for _, word := range normalized {
+			remove[word] = true
+		}
+
+		kept := settings.Blocklist[:0]
+		for _, word := range settings.Blocklist {
+			if !remove[word] {
+				kept = append(kept, word)
+			}
+		}
+		settings.Blocklist = kept
+		return nil
+	})
+}
+
+// ClearWords removes every word from a guild's blocklist
+func (m *ModerationServiceImpl) ClearWords(guildID string) error {
+	return m.update(guildID, func(settings *ModerationSettings) error {
+		settings.Blocklist = nil
+		return nil
+	})
+}
+
+// ReplaceSettings replaces a guild's moderation mode and blocklist, as when restoring a
+// configuration export
+func (m *ModerationServiceImpl) ReplaceSettings(guildID string, settings ModerationSettings) error {
+	if err := ValidateModerationSettings(settings); err != nil {
+		return err
+	}
+
+	var blocklist []string
+	if len(settings.Blocklist) > 0 {
+		normalized, err := normalizeBlockedWords(settings.Blocklist)
+		if err != nil {
+			return err
+		}
+		sort.Strings(normalized)
+		blocklist = slices.Compact(normalized)
+	}
+
+	return m.update(guildID, func(current *ModerationSettings) error {
+		current.Mode = settings.Mode
+		if current.Mode == "" {
+			current.Mode = DefaultModerationMode
+		}
+		current.Blocklist = blocklist
+		return nil
+	})
+}
+
+// update applies a change to a guild's settings, persists them and drops the compiled pattern
+func (m *ModerationServiceImpl) update(guildID string, change func(settings *ModerationSettings) error) error {
+	settings, err := m.GetSettings(guildID)
+	if err != nil {
+		return err
+	}
+
+	if err := change(settings); err != nil {
+		return err
+	}
+
+	if err := m.storage.SaveModerationSettings(*settings); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	delete(m.patterns, guildID)
+	m.mu.Unlock()
+
+	return nil
+}
+
+// BleepAudio returns the encoded tone spoken in place of blocked words
+func (m *ModerationServiceImpl) BleepAudio() ([]byte, error) {
+	m.bleepMu.Lock()
+	defer m.bleepMu.Unlock()
+
+	if m.bleepAudio != nil {
+		return m.bleepAudio, nil
+	}
+
+	if m.encoder == nil {
+		return nil, fmt.Errorf("no audio encoder configured for bleep tone")
+	}
+
+	audio, err := m.encoder.EncodeWAV(toneWAV(bleepFrequency, bleepDuration, bleepAmplitude))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode bleep tone: %w", err)
+	}
+
+	m.bleepAudio = audio
+	return audio, nil
+}
+
+// ValidateModerationSettings validates a guild's moderation mode and blocklist
+func ValidateModerationSettings(settings ModerationSettings) error {
+	switch settings.Mode {
+	case "", ModerationModeSkip, ModerationModeBleep, ModerationModeReplace:
+	default:
+		return fmt.Errorf("invalid moderation mode: %s", settings.Mode)
+	}
+
+	if len(settings.Blocklist) > MaxBlocklistWords {
+		return fmt.Errorf("blocklist can contain at most %d words", MaxBlocklistWords)
+	}
+	for _, word := range settings.Blocklist {
+		if len(strings.TrimSpace(word)) > MaxBlockedWordLength {
+			return fmt.Errorf("blocked words can be at most %d characters", MaxBlockedWordLength)
+		}
+	}
+
+	return nil
+}
+
+// normalizeBlockedWords lowercases and validates words for a blocklist
+func normalizeBlockedWords(words []string) ([]string, error) {
+	normalized := make([]string, 0, len(words))
+	for _, word := range words {
+		word = strings.ToLower(strings.TrimSpace(word))
+		if word == "" {
+			continue
+		}
+		if len(word) > MaxBlockedWordLength {
+			return nil, fmt.Errorf("blocked words can be at most %d characters", MaxBlockedWordLength)
+		}
+		normalized = append(normalized, word)
+	}
+
+	if len(normalized) == 0 {
+		return nil, fmt.Errorf("no words specified")
+	}
+
+	return normalized, nil
+}
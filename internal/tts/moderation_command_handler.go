@@ -0,0 +1,271 @@
+package tts
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"darrot/internal/commands/textcmd"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// ModerationCommandHandler handles administrator blocklist management commands
+type ModerationCommandHandler struct {
+	moderationService ModerationService
+	permissionService PermissionService
+	localizer         *Localizer
+	logger            *log.Logger
+}
+
+// NewModerationCommandHandler creates a new moderation command handler
+func NewModerationCommandHandler(
+	moderationService ModerationService,
+	permissionService PermissionService,
+	logger *log.Logger,
+) *ModerationCommandHandler {
+	return &ModerationCommandHandler{
+		moderationService: moderationService,
+		permissionService: permissionService,
+		logger:            logger,
+	}
+}
+
+// SetLocalizer sets the localizer used to translate responses
+func (h *ModerationCommandHandler) SetLocalizer(localizer *Localizer) {
+	h.localizer = localizer
+}
+
+// Definition returns the Discord slash command definition for the moderation command
+func (h *ModerationCommandHandler) Definition() *discordgo.ApplicationCommand {
+	wordsOption := []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "words",
+			Description: "Words separated by commas or spaces",
+			Required:    true,
+		},
+	}
+
+	return &discordgo.ApplicationCommand{
+		Name:                     "darrot-moderation",
+		Description:              "Manage the word blocklist for TTS (Administrator only)",
+		DefaultMemberPermissions: adminCommandPermissions(),
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "mode",
+				Description: "Choose how messages with blocked words are read",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "mode",
+						Description: "Moderation mode",
+						Required:    true,
+						Choices: []*discordgo.ApplicationCommandOptionChoice{
+							{Name: "skip message", Value: string(ModerationModeSkip)},
+							{Name: "bleep words", Value: string(ModerationModeBleep)},
+							{Name: "replace words", Value: string(ModerationModeReplace)},
+						},
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "add",
+				Description: "Add words to the blocklist",
+				Options:     wordsOption,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "remove",
+				Description: "Remove words from the blocklist",
+				Options:     wordsOption,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "clear",
+				Description: "Remove all words from the blocklist",
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "list",
+				Description: "Show the blocklist and moderation mode",
+			},
+		},
+	}
+}
+
+// Handle processes the moderation command interaction
+func (h *ModerationCommandHandler) Handle(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	// Validate guild context
+	if i.GuildID == "" {
+		return h.respondError(s, i, h.localizer.T(i.GuildID, "common.guild_only"))
+	}
+
+	userID := i.Member.User.ID
+	guildID := i.GuildID
+
+	// Validate administrator permissions
+	if err := h.ValidatePermissions(userID, guildID); err != nil {
+		return h.respondError(s, i, h.localizer.T(guildID, "common.permission_denied", err))
+	}
+
+	// Extract subcommand
+	options := i.ApplicationCommandData().Options
+	if len(options) == 0 {
+		return h.respondError(s, i, h.localizer.T(guildID, "common.no_subcommand"))
+	}
+
+	subcommand := options[0]
+	switch subcommand.Name {
+	case "mode":
+		return h.handleMode(s, i, guildID, subcommand.Options)
+	case "add":
+		return h.handleAdd(s, i, guildID, subcommand.Options)
+	case "remove":
+		return h.handleRemove(s, i, guildID, subcommand.Options)
+	case "clear":
+		return h.handleClear(s, i, guildID)
+	case "list":
+		return h.handleList(s, i, guildID)
+	default:
+		return h.respondError(s, i, h.localizer.T(guildID, "common.invalid_subcommand"))
+	}
+}
+
+// handleMode changes the moderation mode
+func (h *ModerationCommandHandler) handleMode(s *discordgo.Session, i *discordgo.InteractionCreate, guildID string, options []*discordgo.ApplicationCommandInteractionDataOption) error {
+	if len(options) == 0 {
+		return h.respondError(s, i, h.localizer.T(guildID, "moderation.mode_required"))
+	}
+
+	mode := ModerationMode(options[0].StringValue())
+	if err := h.moderationService.SetMode(guildID, mode); err != nil {
+		h.logger.Printf("Error setting moderation mode for guild %s: %v", guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "moderation.mode_failed"))
+	}
+
+	return h.respondSuccess(s, i, h.localizer.T(guildID, "moderation.mode_updated", h.describeModerationMode(guildID, mode)))
+}
+
+// handleAdd adds words to the blocklist
+func (h *ModerationCommandHandler) handleAdd(s *discordgo.Session, i *discordgo.InteractionCreate, guildID string, options []*discordgo.ApplicationCommandInteractionDataOption) error {
+	words := parseWordList(options)
+	if len(words) == 0 {
+		return h.respondError(s, i, h.localizer.T(guildID, "moderation.words_required"))
+	}
+
+	if err := h.moderationService.AddWords(guildID, words); err != nil {
+		return h.respondError(s, i, h.localizer.T(guildID, "moderation.update_failed", err))
+	}
+
+	return h.respondSuccess(s, i, h.localizer.T(guildID, "moderation.added", len(words)))
+}
+
+// handleRemove removes words from the blocklist
+func (h *ModerationCommandHandler) handleRemove(s *discordgo.Session, i *discordgo.InteractionCreate, guildID string, options []*discordgo.ApplicationCommandInteractionDataOption) error {
+	words := parseWordList(options)
+	if len(words) == 0 {
+		return h.respondError(s, i, h.localizer.T(guildID, "moderation.words_required"))
+	}
+
+	if err := h.moderationService.RemoveWords(guildID, words); err != nil {
+		return h.respondError(s, i, h.localizer.T(guildID, "moderation.update_failed", err))
+	}
+
+	return h.respondSuccess(s, i, h.localizer.T(guildID, "moderation.removed", len(words)))
+}
+
+// handleClear empties the blocklist
+func (h *ModerationCommandHandler) handleClear(s *discordgo.Session, i *discordgo.InteractionCreate, guildID string) error {
+	if err := h.moderationService.ClearWords(guildID); err != nil {
+		h.logger.Printf("Error clearing blocklist for guild %s: %v", guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "moderation.clear_failed"))
+	}
+
+	return h.respondSuccess(s, i, h.localizer.T(guildID, "moderation.cleared"))
+}
+
+// handleList shows the blocklist and mode
+func (h *ModerationCommandHandler) handleList(s *discordgo.Session, i *discordgo.InteractionCreate, guildID string) error {
+	settings, err := h.moderationService.GetSettings(guildID)
+	if err != nil {
+		h.logger.Printf("Error getting moderation settings for guild %s: %v", guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "moderation.get_failed"))
+	}
+
+	responseMessage := h.localizer.T(guildID, "moderation.list", h.describeModerationMode(guildID, settings.Mode))
+
+	if len(settings.Blocklist) == 0 {
+		responseMessage += h.localizer.T(guildID, "moderation.blocklist_empty")
+	} else {
+		responseMessage += h.localizer.T(guildID, "moderation.blocklist", len(settings.Blocklist), strings.Join(settings.Blocklist, ", "))
+	}
+
+	return h.respondSuccess(s, i, responseMessage)
+}
+
+// parseWordList extracts words from the "words" option
+func parseWordList(options []*discordgo.ApplicationCommandInteractionDataOption) []string {
+	if len(options) == 0 {
+		return nil
+	}
+
+	return strings.FieldsFunc(options[0].StringValue(), func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\n' || r == '\t'
+	})
+}
+
+// describeModerationMode returns a user-facing description of a moderation mode
+func (h *ModerationCommandHandler) describeModerationMode(guildID string, mode ModerationMode) string {
+	switch mode {
+	case ModerationModeSkip:
+		return h.localizer.T(guildID, "moderation.mode_skip")
+	case ModerationModeBleep:
+		return h.localizer.T(guildID, "moderation.mode_bleep")
+	default:
+		return h.localizer.T(guildID, "moderation.mode_replace")
+	}
+}
+
+// ValidatePermissions validates that the user has administrator permissions
+func (h *ModerationCommandHandler) ValidatePermissions(userID, guildID string) error {
+	canControl, err := h.permissionService.CanControlBot(userID, guildID)
+	if err != nil {
+		return fmt.Errorf("failed to check permissions: %w", err)
+	}
+
+	if !canControl {
+		return withKind(ErrPermission, fmt.Errorf("you must have administrator permissions to manage moderation settings"))
+	}
+
+	return nil
+}
+
+// ValidateChannelAccess is not needed for moderation commands but required by interface
+func (h *ModerationCommandHandler) ValidateChannelAccess(userID, channelID string) error {
+	return nil // Not applicable for moderation commands
+}
+
+// Helper methods for response handling
+
+func (h *ModerationCommandHandler) respondSuccess(s *discordgo.Session, i *discordgo.InteractionCreate, message string) error {
+	return textcmd.Respond(s, i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: message,
+			Flags:   discordgo.MessageFlagsEphemeral, // Keep the blocklist private to admins
+		},
+	})
+}
+
+func (h *ModerationCommandHandler) respondError(s *discordgo.Session, i *discordgo.InteractionCreate, message string) error {
+	return textcmd.Respond(s, i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: "❌ " + message,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
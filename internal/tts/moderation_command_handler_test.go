@@ -0,0 +1,75 @@
+package tts
+
+import (
+	"errors"
+	"log"
+	"os"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createTestModerationHandler(t *testing.T) (*ModerationCommandHandler, *MockPermissionService) {
+	moderationService, _ := createTestModerationService(t)
+	mockPermissionService := &MockPermissionService{}
+	logger := log.New(os.Stdout, "[TEST] ", log.LstdFlags)
+
+	return NewModerationCommandHandler(moderationService, mockPermissionService, logger), mockPermissionService
+}
+
+func TestModerationCommandHandler_Definition(t *testing.T) {
+	handler, _ := createTestModerationHandler(t)
+
+	definition := handler.Definition()
+
+	assert.Equal(t, "darrot-moderation", definition.Name)
+	require.Len(t, definition.Options, 5) // mode, add, remove, clear, list subcommands
+
+	subcommandNames := make(map[string]bool)
+	for _, option := range definition.Options {
+		assert.Equal(t, discordgo.ApplicationCommandOptionSubCommand, option.Type)
+		subcommandNames[option.Name] = true
+	}
+	assert.True(t, subcommandNames["mode"])
+	assert.True(t, subcommandNames["add"])
+	assert.True(t, subcommandNames["remove"])
+	assert.True(t, subcommandNames["clear"])
+	assert.True(t, subcommandNames["list"])
+
+	modeChoices := definition.Options[0].Options[0].Choices
+	require.Len(t, modeChoices, 3)
+	assert.Equal(t, string(ModerationModeSkip), modeChoices[0].Value)
+	assert.Equal(t, string(ModerationModeBleep), modeChoices[1].Value)
+	assert.Equal(t, string(ModerationModeReplace), modeChoices[2].Value)
+}
+
+func TestModerationCommandHandler_ValidatePermissions(t *testing.T) {
+	handler, mockPermissionService := createTestModerationHandler(t)
+
+	mockPermissionService.On("CanControlBot", "admin", "guild123").Return(true, nil)
+	mockPermissionService.On("CanControlBot", "user", "guild123").Return(false, nil)
+	mockPermissionService.On("CanControlBot", "broken", "guild123").Return(false, errors.New("permission check failed"))
+
+	assert.NoError(t, handler.ValidatePermissions("admin", "guild123"))
+
+	err := handler.ValidatePermissions("user", "guild123")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "administrator permissions")
+
+	err = handler.ValidatePermissions("broken", "guild123")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to check permissions")
+
+	mockPermissionService.AssertExpectations(t)
+}
+
+func TestParseWordList(t *testing.T) {
+	options := []*discordgo.ApplicationCommandInteractionDataOption{
+		{Name: "words", Type: discordgo.ApplicationCommandOptionString, Value: "alpha, beta,gamma  delta"},
+	}
+
+	assert.Equal(t, []string{"alpha", "beta", "gamma", "delta"}, parseWordList(options))
+	assert.Nil(t, parseWordList(nil))
+}
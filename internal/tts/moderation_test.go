@@ -0,0 +1,245 @@
+package tts
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBleepEncoder marks encoded audio so tests can find the bleep in joined output
+type fakeBleepEncoder struct {
+	calls int
+}
+
+func (e *fakeBleepEncoder) EncodeWAV(wavData []byte) ([]byte, error) {
+	e.calls++
+	return []byte("<bleep>"), nil
+}
+
+func createTestModerationService(t *testing.T) (*ModerationServiceImpl, *fakeBleepEncoder) {
+	storage, err := NewStorageService(t.TempDir())
+	require.NoError(t, err)
+
+	encoder := &fakeBleepEncoder{}
+	return NewModerationService(storage, encoder), encoder
+}
+
+func TestModerationService_EmptyBlocklist(t *testing.T) {
+	service, _ := createTestModerationService(t)
+
+	result, err := service.Moderate("guild1", "alice says: hello there")
+	require.NoError(t, err)
+	assert.Equal(t, "alice says: hello there", result.Text)
+	assert.False(t, result.Skip)
+	assert.Equal(t, 0, result.Matches)
+
+	settings, err := service.GetSettings("guild1")
+	require.NoError(t, err)
+	assert.Equal(t, DefaultModerationMode, settings.Mode)
+	assert.Empty(t, settings.Blocklist)
+}
+
+func TestModerationService_Modes(t *testing.T) {
+	service, _ := createTestModerationService(t)
+	require.NoError(t, service.AddWords("guild1", []string{"Darn", "heck"}))
+
+	// Replace is the default mode and matches whole words case-insensitively
+	result, err := service.Moderate("guild1", "alice says: DARN it, what the heck, darned")
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Matches)
+	assert.Equal(t, "alice says: asterisk it, what the asterisk, darned", result.Text)
+
+	require.NoError(t, service.SetMode("guild1", ModerationModeBleep))
+	result, err = service.Moderate("guild1", "alice says: darn it")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alice says: ", " it"}, result.Segments)
+	assert.NotContains(t, result.Text, "darn")
+
+	require.NoError(t, service.SetMode("guild1", ModerationModeSkip))
+	result, err = service.Moderate("guild1", "alice says: darn it")
+	require.NoError(t, err)
+	assert.True(t, result.Skip)
+
+	// Other guilds are unaffected
+	result, err = service.Moderate("guild2", "alice says: darn it")
+	require.NoError(t, err)
+	assert.False(t, result.Skip)
+
+	assert.Error(t, service.SetMode("guild1", ModerationMode("shout")))
+}
+
+func TestModerationService_ManageWords(t *testing.T) {
+	service, _ := createTestModerationService(t)
+
+	require.NoError(t, service.AddWords("guild1", []string{"beta", " Alpha ", "beta"}))
+	settings, err := service.GetSettings("guild1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alpha", "beta"}, settings.Blocklist)
+
+	// Updates take effect immediately
+	result, err := service.Moderate("guild1", "alpha gamma")
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Matches)
+
+	require.NoError(t, service.RemoveWords("guild1", []string{"ALPHA"}))
+	result, err = service.Moderate("guild1", "alpha gamma")
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.Matches)
+
+	require.NoError(t, service.ClearWords("guild1"))
+	settings, err = service.GetSettings("guild1")
+	require.NoError(t, err)
+	assert.Empty(t, settings.Blocklist)
+
+	assert.Error(t, service.AddWords("guild1", []string{" ", ""}))
+	assert.Error(t, service.AddWords("guild1", []string{strings.Repeat("x", MaxBlockedWordLength+1)}))
+
+	tooMany := make([]string, MaxBlocklistWords+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("word%d", i)
+	}
+	assert.Error(t, service.AddWords("guild1", tooMany))
+}
+
+func TestModerationService_ReplaceSettings(t *testing.T) {
+	service, _ := createTestModerationService(t)
+	require.NoError(t, service.AddWords("guild1", []string{"alpha"}))
+
+	// Compiled blocklists are replaced along with the stored settings
+	result, err := service.Moderate("guild1", "alpha beta")
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Matches)
+
+	require.NoError(t, service.ReplaceSettings("guild1", ModerationSettings{Mode: ModerationModeSkip, Blocklist: []string{"Beta", "gamma", "beta"}}))
+	settings, err := service.GetSettings("guild1")
+	require.NoError(t, err)
+	assert.Equal(t, ModerationModeSkip, settings.Mode)
+	assert.Equal(t, []string{"beta", "gamma"}, settings.Blocklist)
+
+	result, err = service.Moderate("guild1", "alpha beta")
+	require.NoError(t, err)
+	assert.True(t, result.Skip)
+
+	// An empty blocklist and mode restore the defaults
+	require.NoError(t, service.ReplaceSettings("guild1", ModerationSettings{}))
+	settings, err = service.GetSettings("guild1")
+	require.NoError(t, err)
+	assert.Equal(t, DefaultModerationMode, settings.Mode)
+	assert.Empty(t, settings.Blocklist)
+
+	assert.Error(t, service.ReplaceSettings("guild1", ModerationSettings{Mode: ModerationMode("shout")}))
+}
+
+func TestModerationService_SpecialCharactersAreLiteral(t *testing.T) {
+	service, _ := createTestModerationService(t)
+	require.NoError(t, service.AddWords("guild1", []string{"a.c"}))
+
+	result, err := service.Moderate("guild1", "abc a.c")
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Matches)
+	assert.Equal(t, "abc asterisk", result.Text)
+}
+
+func TestModerationService_WholeWordsInAnyScript(t *testing.T) {
+	service, _ := createTestModerationService(t)
+	require.NoError(t, service.AddWords("guild1", []string{"dchen", "Äpfel", "darn"}))
+
+	// Letters outside ASCII are part of the word, and case is folded for them too
+	result, err := service.Moderate("guild1", "äpfel für das Mädchen")
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Matches)
+	assert.Equal(t, "asterisk für das Mädchen", result.Text)
+
+	// Words next to each other share the space between them
+	result, err = service.Moderate("guild1", "darn darn, Äpfel!")
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Matches)
+	assert.Equal(t, "asterisk asterisk, asterisk!", result.Text)
+
+	require.NoError(t, service.SetMode("guild1", ModerationModeBleep))
+	result, err = service.Moderate("guild1", "darn darn")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"", " ", ""}, result.Segments)
+}
+
+func TestModerationService_BleepAudioIsCached(t *testing.T) {
+	service, encoder := createTestModerationService(t)
+
+	first, err := service.BleepAudio()
+	require.NoError(t, err)
+	second, err := service.BleepAudio()
+	require.NoError(t, err)
+
+	assert.Equal(t, first, second)
+	assert.Equal(t, 1, encoder.calls)
+}
+
+func TestToneWAV(t *testing.T) {
+	audio, err := parseWAV(toneWAV(1000, 400*time.Millisecond, 0.3))
+	require.NoError(t, err)
+	assert.Equal(t, 48000, audio.sampleRate)
+	assert.Equal(t, 400*time.Millisecond, audio.Duration())
+}
+
+func TestTTSProcessor_ModerationBleepsAndSkips(t *testing.T) {
+	ttsManager := &mockTTSManager{
+		convertFunc: func(text, voice string, config TTSConfig) ([]byte, error) {
+			return []byte("[" + text + "]"), nil
+		},
+	}
+	voiceManager := newMockVoiceManager()
+	queue := NewMessageQueue()
+	processor := NewTTSProcessor(ttsManager, voiceManager, queue, newMockConfigService(), newMockUserService()).(*ttsProcessor)
+
+	moderation, _ := createTestModerationService(t)
+	require.NoError(t, moderation.AddWords("guild1", []string{"darn"}))
+	require.NoError(t, moderation.SetMode("guild1", ModerationModeBleep))
+	processor.SetModerationService(moderation)
+
+	var played []byte
+	voiceManager.playAudioFunc = func(guildID string, audioData []byte) error {
+		played = audioData
+		return nil
+	}
+
+	require.NoError(t, queue.Enqueue(&QueuedMessage{ID: "m1", GuildID: "guild1", Content: "bob says: darn it", Timestamp: time.Now()}))
+	processor.processNextMessage("guild1", &guildProcessor{guildID: "guild1"})
+	assert.Equal(t, "[bob says: ]<bleep>[ it]", string(played))
+
+	// Skipped messages are never synthesized or played, but can be noted
+	notes, notesQueue := newTestSkipNotes(t)
+	processor.SetSkipNotes(notes)
+	require.NoError(t, moderation.SetMode("guild1", ModerationModeSkip))
+	played = nil
+	calls := len(ttsManager.getCallLog())
+	require.NoError(t, queue.Enqueue(&QueuedMessage{ID: "m2", GuildID: "guild1", ChannelID: "text1", Content: "bob says: darn it", Timestamp: time.Now()}))
+	processor.processNextMessage("guild1", &guildProcessor{guildID: "guild1"})
+	assert.Nil(t, played)
+	assert.Equal(t, calls, len(ttsManager.getCallLog()))
+
+	notes.Read("guild1")
+	require.Len(t, notesQueue.getMessages(), 1)
+	assert.Equal(t, "A message was skipped by moderation.", notesQueue.getMessages()[0].Content)
+}
+
+func TestTTSProcessor_ModerationFailureSkipsMessage(t *testing.T) {
+	processor := NewTTSProcessor(&mockTTSManager{}, newMockVoiceManager(), NewMessageQueue(), newMockConfigService(), newMockUserService()).(*ttsProcessor)
+	processor.SetModerationService(&failingModerationService{})
+
+	result := processor.moderate("guild1", "hello")
+	assert.True(t, result.Skip)
+}
+
+// failingModerationService simulates an unreadable blocklist
+type failingModerationService struct {
+	ModerationService
+}
+
+func (f *failingModerationService) Moderate(guildID, text string) (*ModerationResult, error) {
+	return nil, errors.New("storage unavailable")
+}
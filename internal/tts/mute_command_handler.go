@@ -0,0 +1,205 @@
+package tts
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"darrot/internal/commands/textcmd"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// MuteCommandHandler handles /darrot-mute and /darrot-unmute, which let listeners stop
+// specific users' messages from being read while they are in the voice channel
+type MuteCommandHandler struct {
+	userService UserService
+	unmute      bool
+	localizer   *Localizer
+	logger      *log.Logger
+}
+
+// NewMuteCommandHandler creates the /darrot-mute command handler
+func NewMuteCommandHandler(userService UserService, logger *log.Logger) *MuteCommandHandler {
+	return &MuteCommandHandler{
+		userService: userService,
+		logger:      logger,
+	}
+}
+
+// NewUnmuteCommandHandler creates the /darrot-unmute command handler
+func NewUnmuteCommandHandler(userService UserService, logger *log.Logger) *MuteCommandHandler {
+	return &MuteCommandHandler{
+		userService: userService,
+		unmute:      true,
+		logger:      logger,
+	}
+}
+
+// SetLocalizer sets the localizer used to translate responses
+func (h *MuteCommandHandler) SetLocalizer(localizer *Localizer) {
+	h.localizer = localizer
+}
+
+// Definition returns the Discord slash command definition for the mute or unmute command
+func (h *MuteCommandHandler) Definition() *discordgo.ApplicationCommand {
+	if h.unmute {
+		return &discordgo.ApplicationCommand{
+			Name:        "darrot-unmute",
+			Description: "Have a muted user's messages read to you again",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionUser,
+					Name:        "user",
+					Description: "The user to unmute (omit to list muted users)",
+					Required:    false,
+				},
+			},
+		}
+	}
+
+	return &discordgo.ApplicationCommand{
+		Name:        "darrot-mute",
+		Description: "Don't read a user's messages while you are in the voice channel",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionUser,
+				Name:        "user",
+				Description: "The user to mute",
+				Required:    true,
+			},
+		},
+	}
+}
+
+// Handle processes the mute or unmute command interaction
+func (h *MuteCommandHandler) Handle(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	// Validate guild context
+	if i.GuildID == "" {
+		return h.respondError(s, i, h.localizer.T(i.GuildID, "common.guild_only"))
+	}
+
+	userID := i.Member.User.ID
+	guildID := i.GuildID
+
+	// Only opted-in listeners can manage a mute list
+	if err := h.ValidatePermissions(userID, guildID); err != nil {
+		return h.respondError(s, i, h.localizer.T(guildID, "common.permission_denied", err))
+	}
+
+	target := optionUser(i)
+
+	if h.unmute {
+		if target == nil {
+			return h.handleList(s, i, userID, guildID)
+		}
+		return h.handleUnmute(s, i, userID, guildID, target)
+	}
+
+	if target == nil {
+		return h.respondError(s, i, h.localizer.T(guildID, "mute.user_required"))
+	}
+	return h.handleMute(s, i, userID, guildID, target)
+}
+
+// handleMute adds a user to the caller's mute list
+func (h *MuteCommandHandler) handleMute(s *discordgo.Session, i *discordgo.InteractionCreate, userID, guildID string, target *discordgo.User) error {
+	if target.ID == userID {
+		return h.respondError(s, i, h.localizer.T(guildID, "mute.self"))
+	}
+
+	if err := h.userService.MuteUser(userID, target.ID, guildID); err != nil {
+		h.logger.Printf("Error muting user %s for %s in guild %s: %v", target.ID, userID, guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "mute.failed", err))
+	}
+
+	return h.respondSuccess(s, i, h.localizer.T(guildID, "mute.muted", target.ID))
+}
+
+// handleUnmute removes a user from the caller's mute list
+func (h *MuteCommandHandler) handleUnmute(s *discordgo.Session, i *discordgo.InteractionCreate, userID, guildID string, target *discordgo.User) error {
+	if err := h.userService.UnmuteUser(userID, target.ID, guildID); err != nil {
+		h.logger.Printf("Error unmuting user %s for %s in guild %s: %v", target.ID, userID, guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "mute.unmute_failed", err))
+	}
+
+	return h.respondSuccess(s, i, h.localizer.T(guildID, "mute.unmuted", target.ID))
+}
+
+// handleList shows the caller's mute list
+func (h *MuteCommandHandler) handleList(s *discordgo.Session, i *discordgo.InteractionCreate, userID, guildID string) error {
+	mutedUsers, err := h.userService.GetMutedUsers(userID, guildID)
+	if err != nil {
+		h.logger.Printf("Error getting mute list for user %s in guild %s: %v", userID, guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "mute.list_failed"))
+	}
+
+	if len(mutedUsers) == 0 {
+		return h.respondSuccess(s, i, h.localizer.T(guildID, "mute.list_empty"))
+	}
+
+	mentions := make([]string, len(mutedUsers))
+	for idx, mutedID := range mutedUsers {
+		mentions[idx] = fmt.Sprintf("<@%s>", mutedID)
+	}
+
+	return h.respondSuccess(s, i, h.localizer.T(guildID, "mute.list", strings.Join(mentions, ", ")))
+}
+
+// optionUser returns the user selected in the command's "user" option, preferring the
+// resolved data Discord sends with the interaction over an API lookup
+func optionUser(i *discordgo.InteractionCreate) *discordgo.User {
+	data := i.ApplicationCommandData()
+	for _, option := range data.Options {
+		if option.Name != "user" {
+			continue
+		}
+		userID, _ := option.Value.(string)
+		if data.Resolved != nil && data.Resolved.Users[userID] != nil {
+			return data.Resolved.Users[userID]
+		}
+		return &discordgo.User{ID: userID}
+	}
+	return nil
+}
+
+// ValidatePermissions validates that the user is an opted-in listener
+func (h *MuteCommandHandler) ValidatePermissions(userID, guildID string) error {
+	optedIn, err := h.userService.IsOptedIn(userID, guildID)
+	if err != nil {
+		return fmt.Errorf("failed to check your opt-in status")
+	}
+
+	if !optedIn {
+		return fmt.Errorf("only opted-in users can mute others. Use `/darrot-optin opt-in` first")
+	}
+
+	return nil
+}
+
+// ValidateChannelAccess is not needed for mute commands but required by interface
+func (h *MuteCommandHandler) ValidateChannelAccess(userID, channelID string) error {
+	return nil // Not applicable for mute commands
+}
+
+// Helper methods for response handling
+
+func (h *MuteCommandHandler) respondSuccess(s *discordgo.Session, i *discordgo.InteractionCreate, message string) error {
+	return textcmd.Respond(s, i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: message,
+			Flags:   discordgo.MessageFlagsEphemeral, // Mute lists are private to the listener
+		},
+	})
+}
+
+func (h *MuteCommandHandler) respondError(s *discordgo.Session, i *discordgo.InteractionCreate, message string) error {
+	return textcmd.Respond(s, i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: "❌ " + message,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
@@ -0,0 +1,81 @@
+package tts
+
+import (
+	"errors"
+	"log"
+	"os"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMuteCommandHandler_Definition(t *testing.T) {
+	logger := log.New(os.Stdout, "[TEST] ", log.LstdFlags)
+
+	mute := NewMuteCommandHandler(&MockUserService{}, logger).Definition()
+	assert.Equal(t, "darrot-mute", mute.Name)
+	require.Len(t, mute.Options, 1)
+	assert.Equal(t, discordgo.ApplicationCommandOptionUser, mute.Options[0].Type)
+	assert.True(t, mute.Options[0].Required)
+
+	unmute := NewUnmuteCommandHandler(&MockUserService{}, logger).Definition()
+	assert.Equal(t, "darrot-unmute", unmute.Name)
+	require.Len(t, unmute.Options, 1)
+	assert.Equal(t, discordgo.ApplicationCommandOptionUser, unmute.Options[0].Type)
+	assert.False(t, unmute.Options[0].Required, "omitting the user lists muted users")
+}
+
+func TestMuteCommandHandler_ValidatePermissions(t *testing.T) {
+	mockUserService := &MockUserService{}
+	handler := NewMuteCommandHandler(mockUserService, log.New(os.Stdout, "[TEST] ", log.LstdFlags))
+
+	mockUserService.On("IsOptedIn", "listener", "guild123").Return(true, nil)
+	mockUserService.On("IsOptedIn", "lurker", "guild123").Return(false, nil)
+	mockUserService.On("IsOptedIn", "broken", "guild123").Return(false, errors.New("storage error"))
+
+	assert.NoError(t, handler.ValidatePermissions("listener", "guild123"))
+
+	err := handler.ValidatePermissions("lurker", "guild123")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "opted-in")
+
+	assert.Error(t, handler.ValidatePermissions("broken", "guild123"))
+
+	mockUserService.AssertExpectations(t)
+}
+
+func TestOptionUser(t *testing.T) {
+	interaction := func(data discordgo.ApplicationCommandInteractionData) *discordgo.InteractionCreate {
+		return &discordgo.InteractionCreate{
+			Interaction: &discordgo.Interaction{
+				Type: discordgo.InteractionApplicationCommand,
+				Data: data,
+			},
+		}
+	}
+
+	assert.Nil(t, optionUser(interaction(discordgo.ApplicationCommandInteractionData{Name: "darrot-unmute"})))
+
+	option := &discordgo.ApplicationCommandInteractionDataOption{
+		Name:  "user",
+		Type:  discordgo.ApplicationCommandOptionUser,
+		Value: "user123",
+	}
+
+	user := optionUser(interaction(discordgo.ApplicationCommandInteractionData{
+		Options: []*discordgo.ApplicationCommandInteractionDataOption{option},
+	}))
+	require.NotNil(t, user)
+	assert.Equal(t, "user123", user.ID)
+
+	user = optionUser(interaction(discordgo.ApplicationCommandInteractionData{
+		Options: []*discordgo.ApplicationCommandInteractionDataOption{option},
+		Resolved: &discordgo.ApplicationCommandInteractionDataResolved{
+			Users: map[string]*discordgo.User{"user123": {ID: "user123", Bot: true}},
+		},
+	}))
+	require.NotNil(t, user)
+	assert.True(t, user.Bot, "resolved user data should be used")
+}
@@ -0,0 +1,144 @@
+package tts
+
+import (
+	"log"
+	"sync"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// MutePauser pauses a guild's playback while the bot is server muted, or suppressed in a
+// stage channel, and resumes it once the bot can be heard again. Messages keep queueing
+// while playback is paused. A nil *MutePauser reports every guild as unmuted.
+type MutePauser struct {
+	voiceManager  VoiceManager
+	logger        *log.Logger
+	removeHandler func()
+
+	mu     sync.Mutex
+	muted  map[string]bool // Guilds where the bot cannot be heard
+	paused map[string]bool // Guilds whose playback was paused because of it
+}
+
+// NewMutePauser creates a mute pauser. Call Register to start listening for voice state
+// updates.
+func NewMutePauser(voiceManager VoiceManager, logger *log.Logger) *MutePauser {
+	return &MutePauser{
+		voiceManager: voiceManager,
+		logger:       logger,
+		muted:        make(map[string]bool),
+		paused:       make(map[string]bool),
+	}
+}
+
+// Register subscribes the pauser to voice state updates on the session
+func (m *MutePauser) Register(session *discordgo.Session) {
+	m.removeHandler = session.AddHandler(m.handleVoiceStateUpdate)
+}
+
+// Stop unsubscribes the pauser from voice state updates
+func (m *MutePauser) Stop() {
+	if m.removeHandler != nil {
+		m.removeHandler()
+		m.removeHandler = nil
+	}
+}
+
+// IsMuted reports whether the bot is server muted or suppressed in a guild's voice channel
+func (m *MutePauser) IsMuted(guildID string) bool {
+	if m == nil {
+		return false
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.muted[guildID]
+}
+
+// KeepPaused stops playback from resuming automatically once the bot is unmuted in a
+// guild. It reports whether playback was paused because of the mute.
+func (m *MutePauser) KeepPaused(guildID string) bool {
+	if m == nil {
+		return false
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	paused := m.paused[guildID]
+	delete(m.paused, guildID)
+	return paused
+}
+
+// ResumeWhenUnmuted makes playback resume automatically once the bot is unmuted in a
+// guild, even if it was paused by hand. It reports false when the bot is not muted.
+func (m *MutePauser) ResumeWhenUnmuted(guildID string) bool {
+	if m == nil {
+		return false
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.muted[guildID] {
+		return false
+	}
+	m.paused[guildID] = true
+	return true
+}
+
+// handleVoiceStateUpdate is the discordgo event handler for voice state changes
+func (m *MutePauser) handleVoiceStateUpdate(s *discordgo.Session, vsu *discordgo.VoiceStateUpdate) {
+	botUserID := ""
+	if s.State != nil && s.State.User != nil {
+		botUserID = s.State.User.ID
+	}
+	m.update(vsu, botUserID)
+}
+
+// update pauses or resumes playback when the bot's own voice state changes whether it
+// can be heard in the channel it is connected to
+func (m *MutePauser) update(vsu *discordgo.VoiceStateUpdate, botUserID string) {
+	if vsu == nil || vsu.VoiceState == nil || botUserID == "" || vsu.UserID != botUserID {
+		return
+	}
+	guildID := vsu.GuildID
+
+	connection, connected := m.voiceManager.GetConnection(guildID)
+	muted := connected && connection != nil && vsu.ChannelID == connection.ChannelID && (vsu.Mute || vsu.Suppress)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	switch {
+	case muted && !m.muted[guildID]:
+		m.muted[guildID] = true
+
+		// Playback someone paused on purpose stays theirs to resume
+		if m.voiceManager.IsPaused(guildID) {
+			return
+		}
+		if err := m.voiceManager.PausePlayback(guildID); err != nil {
+			m.logger.Printf("Failed to pause playback for guild %s after the bot was muted: %v", guildID, err)
+			return
+		}
+		m.paused[guildID] = true
+		m.logger.Printf("Paused playback for guild %s while the bot is server muted", guildID)
+
+	case !muted && m.muted[guildID]:
+		delete(m.muted, guildID)
+		if !m.paused[guildID] {
+			return
+		}
+		delete(m.paused, guildID)
+
+		if !connected || !m.voiceManager.IsPaused(guildID) {
+			return
+		}
+		if err := m.voiceManager.ResumePlayback(guildID); err != nil {
+			m.logger.Printf("Failed to resume playback for guild %s after the bot was unmuted: %v", guildID, err)
+			return
+		}
+		m.logger.Printf("Resumed playback for guild %s now that the bot is unmuted", guildID)
+	}
+}
@@ -0,0 +1,134 @@
+package tts
+
+import (
+	"io"
+	"log"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createTestMutePauser(t *testing.T) (*MutePauser, *mockVoiceManager) {
+	t.Helper()
+
+	voiceManager := newMockVoiceManager()
+	_, err := voiceManager.JoinChannel("guild1", "voice1")
+	require.NoError(t, err)
+
+	return NewMutePauser(voiceManager, log.New(io.Discard, "", 0)), voiceManager
+}
+
+// botVoiceUpdate is a voice state update for the bot in voice1
+func botVoiceUpdate(mute, suppress bool) *discordgo.VoiceStateUpdate {
+	return &discordgo.VoiceStateUpdate{VoiceState: &discordgo.VoiceState{
+		GuildID:   "guild1",
+		UserID:    "bot",
+		ChannelID: "voice1",
+		Mute:      mute,
+		Suppress:  suppress,
+	}}
+}
+
+func TestMutePauser_PausesWhileMuted(t *testing.T) {
+	pauser, voiceManager := createTestMutePauser(t)
+
+	pauser.update(botVoiceUpdate(true, false), "bot")
+	assert.True(t, pauser.IsMuted("guild1"))
+	assert.True(t, voiceManager.IsPaused("guild1"))
+
+	// Repeated updates while muted change nothing
+	pauser.update(botVoiceUpdate(true, false), "bot")
+	assert.True(t, voiceManager.IsPaused("guild1"))
+
+	pauser.update(botVoiceUpdate(false, false), "bot")
+	assert.False(t, pauser.IsMuted("guild1"))
+	assert.False(t, voiceManager.IsPaused("guild1"))
+}
+
+func TestMutePauser_PausesWhileSuppressed(t *testing.T) {
+	pauser, voiceManager := createTestMutePauser(t)
+
+	// Stage audience members are suppressed until a moderator invites them to speak
+	pauser.update(botVoiceUpdate(false, true), "bot")
+	assert.True(t, voiceManager.IsPaused("guild1"))
+
+	pauser.update(botVoiceUpdate(false, false), "bot")
+	assert.False(t, voiceManager.IsPaused("guild1"))
+}
+
+func TestMutePauser_IgnoresOtherUpdates(t *testing.T) {
+	pauser, voiceManager := createTestMutePauser(t)
+
+	// Other members being muted
+	other := botVoiceUpdate(true, false)
+	other.UserID = "user1"
+	pauser.update(other, "bot")
+
+	// The bot muted in a channel it is not connected to
+	elsewhere := botVoiceUpdate(true, false)
+	elsewhere.ChannelID = "voice2"
+	pauser.update(elsewhere, "bot")
+
+	pauser.update(nil, "bot")
+	pauser.update(botVoiceUpdate(true, false), "")
+
+	assert.False(t, pauser.IsMuted("guild1"))
+	assert.False(t, voiceManager.IsPaused("guild1"))
+}
+
+func TestMutePauser_KeepsManualPause(t *testing.T) {
+	pauser, voiceManager := createTestMutePauser(t)
+
+	// Playback paused by hand before the mute stays paused after it
+	require.NoError(t, voiceManager.PausePlayback("guild1"))
+	pauser.update(botVoiceUpdate(true, false), "bot")
+	pauser.update(botVoiceUpdate(false, false), "bot")
+	assert.True(t, voiceManager.IsPaused("guild1"))
+
+	// Unless someone asked to resume while the bot was muted
+	pauser.update(botVoiceUpdate(true, false), "bot")
+	assert.True(t, pauser.ResumeWhenUnmuted("guild1"))
+	pauser.update(botVoiceUpdate(false, false), "bot")
+	assert.False(t, voiceManager.IsPaused("guild1"))
+	assert.False(t, pauser.ResumeWhenUnmuted("guild1"))
+
+	// Pausing by hand while muted keeps playback paused after the mute
+	pauser.update(botVoiceUpdate(true, false), "bot")
+	assert.True(t, pauser.KeepPaused("guild1"))
+	pauser.update(botVoiceUpdate(false, false), "bot")
+	assert.True(t, voiceManager.IsPaused("guild1"))
+}
+
+func TestMutePauser_ClearsStateWhenDisconnected(t *testing.T) {
+	pauser, voiceManager := createTestMutePauser(t)
+
+	pauser.update(botVoiceUpdate(true, false), "bot")
+	require.NoError(t, voiceManager.LeaveChannel("guild1"))
+
+	left := botVoiceUpdate(true, false)
+	left.ChannelID = ""
+	pauser.update(left, "bot")
+	assert.False(t, pauser.IsMuted("guild1"))
+
+	// A nil pauser reports nothing
+	var disabled *MutePauser
+	assert.False(t, disabled.IsMuted("guild1"))
+	assert.False(t, disabled.KeepPaused("guild1"))
+	assert.False(t, disabled.ResumeWhenUnmuted("guild1"))
+}
+
+func TestControlCommandHandler_MuteNotice(t *testing.T) {
+	handler, _, _, _ := createTestControlHandler()
+	assert.Equal(t, "Cleared", handler.withMuteNotice("guild1", "Cleared"))
+
+	pauser, _ := createTestMutePauser(t)
+	pauser.update(botVoiceUpdate(true, false), "bot")
+	handler.SetMutePauser(pauser)
+
+	message := handler.withMuteNotice("guild1", "Cleared")
+	assert.Contains(t, message, "Cleared\n")
+	assert.Contains(t, message, "server muted")
+	assert.Equal(t, "Cleared", handler.withMuteNotice("guild2", "Cleared"))
+}
@@ -0,0 +1,382 @@
+package tts
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Text normalization spells out numbers, times, dates, currencies, units and common
+// abbreviations before synthesis, so "1,024" is read as "one thousand twenty four"
+// rather than digit by digit, and "z.B." as "zum Beispiel". The rules follow the
+// language of the voice: English and German voices read numbers with their own
+// separators and words, and text for voices in other languages is left as written.
+// Tokens that are part of a word, a link or a longer number, such as "mp3", version
+// numbers or phone numbers, are left alone.
+
+// NewTextNormalizer creates the normalizer for English and German voices
+func NewTextNormalizer() TextNormalizer {
+	return &ruleNormalizer{
+		languages: map[string]*normalizationLanguage{
+			"en": newNormalizationLanguage(englishNormalization),
+			"de": newNormalizationLanguage(germanNormalization),
+		},
+	}
+}
+
+// ruleNormalizer normalizes text with the rules of the voice's language
+type ruleNormalizer struct {
+	languages map[string]*normalizationLanguage
+}
+
+// Normalize rewrites text for speech in languageCode, such as "en-US"
+func (n *ruleNormalizer) Normalize(text, languageCode string) string {
+	language, region, _ := strings.Cut(languageCode, "-")
+	rules, ok := n.languages[strings.ToLower(language)]
+	if !ok {
+		return text
+	}
+	return rules.normalize(text, strings.ToUpper(region))
+}
+
+// languageRules holds the words and formats of a language
+type languageRules struct {
+	cardinal       func(n int64) string // Spells out a whole number
+	ordinal        func(n int64) string // Spells out the ordinal of a whole number
+	time           func(hour, minute int, suffix string) string
+	date           func(day, month, year int, months []string) string
+	dayFirst       func(region string) bool // Whether numeric dates put the day before the month
+	one            string                   // "One" before a noun, as in "one euro"
+	digits         []string                 // Words for digits read one by one
+	point          string                   // Word for the decimal separator
+	minus          string
+	decimal        string // Decimal separator
+	group          string // Thousands separator
+	dateSeparators string
+	months         []string
+	currencies     map[string]unitWords // Keyed by symbol
+	units          map[string]unitWords // Keyed by abbreviation
+	abbreviations  []abbreviation
+	ordinals       bool // Whether ordinals are written with suffixes, as in "21st"
+	clock12        bool // Whether times can carry AM or PM
+}
+
+// unitWords are the words for a currency or unit; minor units are used for the cents
+// of a currency
+type unitWords struct {
+	one, many           string
+	minorOne, minorMany string
+}
+
+// abbreviation is a written abbreviation and what is said for it
+type abbreviation struct {
+	written, spoken string
+}
+
+// normalizationLanguage is a language's rules with the patterns built from them
+type normalizationLanguage struct {
+	languageRules
+	isoDate              *regexp.Regexp
+	numericDate          *regexp.Regexp
+	clock                *regexp.Regexp
+	currencyLead         *regexp.Regexp // Symbol before the amount, as in "$5"
+	currencyTail         *regexp.Regexp // Symbol after the amount, as in "5 €"
+	unit                 *regexp.Regexp
+	ordinalNumber        *regexp.Regexp // Only for languages that write ordinals with suffixes
+	number               *regexp.Regexp
+	abbreviationPatterns []*regexp.Regexp
+}
+
+// newNormalizationLanguage builds the patterns of a language's rules
+func newNormalizationLanguage(rules languageRules) *normalizationLanguage {
+	// A number with an optional sign, thousands separators and decimals
+	number := `([-−]?)(\d{1,3}(?:` + regexp.QuoteMeta(rules.group) + `\d{3})+|\d+)(?:` + regexp.QuoteMeta(rules.decimal) + `(\d+))?`
+	// An amount of money, with at most two decimals
+	amount := `(\d{1,3}(?:` + regexp.QuoteMeta(rules.group) + `\d{3})+|\d+)(?:` + regexp.QuoteMeta(rules.decimal) + `(\d{1,2}))?`
+
+	symbols := make([]string, 0, len(rules.currencies))
+	for symbol := range rules.currencies {
+		symbols = append(symbols, regexp.QuoteMeta(symbol))
+	}
+	units := make([]string, 0, len(rules.units))
+	for unit := range rules.units {
+		units = append(units, regexp.QuoteMeta(unit))
+	}
+	// Longer units first, so "km/h" is not read as "km"
+	slices.SortFunc(units, func(a, b string) int { return len(b) - len(a) })
+
+	clock := `(\d{1,2}):(\d{2})`
+	if rules.clock12 {
+		clock += `(?:\s?([AaPp])\.?[Mm]\b\.?)?`
+	}
+
+	language := &normalizationLanguage{
+		languageRules: rules,
+		isoDate:       regexp.MustCompile(`(\d{4})-(\d{2})-(\d{2})`),
+		numericDate:   regexp.MustCompile(`(\d{1,2})([` + regexp.QuoteMeta(rules.dateSeparators) + `])(\d{1,2})([` + regexp.QuoteMeta(rules.dateSeparators) + `])(\d{4})`),
+		clock:         regexp.MustCompile(clock),
+		currencyLead:  regexp.MustCompile(`(` + strings.Join(symbols, "|") + `)\s?` + amount),
+		currencyTail:  regexp.MustCompile(amount + `\s?(` + strings.Join(symbols, "|") + `)`),
+		unit:          regexp.MustCompile(number + `\s?(` + strings.Join(units, "|") + `)`),
+		number:        regexp.MustCompile(number),
+	}
+	if rules.ordinals {
+		language.ordinalNumber = regexp.MustCompile(`(\d+)(st|nd|rd|th)`)
+	}
+	for _, abbreviation := range rules.abbreviations {
+		language.abbreviationPatterns = append(language.abbreviationPatterns, abbreviationPattern(abbreviation.written))
+	}
+	return language
+}
+
+// abbreviationPattern matches a written abbreviation, allowing a space after its inner
+// dots ("z. B.") and a capital first letter at the start of a sentence
+func abbreviationPattern(written string) *regexp.Regexp {
+	var pattern strings.Builder
+	for i, r := range written {
+		switch {
+		case i == 0 && unicode.IsLower(r):
+			pattern.WriteString("[" + string(r) + string(unicode.ToUpper(r)) + "]")
+		case r == '.' && i < len(written)-1:
+			pattern.WriteString(`\.\s?`)
+		default:
+			pattern.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	return regexp.MustCompile(pattern.String())
+}
+
+// normalize rewrites text for speech. Dates and times go first, as their parts would
+// otherwise be read as separate numbers, then amounts with a currency or unit, then the
+// numbers left, and abbreviations last.
+func (l *normalizationLanguage) normalize(text, region string) string {
+	text = replaceStandalone(text, l.isoDate, func(groups []string) (string, bool) {
+		return l.spellDate(groups[3], groups[2], groups[1])
+	})
+	text = replaceStandalone(text, l.numericDate, func(groups []string) (string, bool) {
+		if groups[2] != groups[4] {
+			return "", false // Mixed separators are not a date
+		}
+		if l.dayFirst(region) {
+			return l.spellDate(groups[1], groups[3], groups[5])
+		}
+		return l.spellDate(groups[3], groups[1], groups[5])
+	})
+	text = replaceStandalone(text, l.clock, l.spellTime)
+	text = replaceStandalone(text, l.currencyLead, func(groups []string) (string, bool) {
+		return l.spellMoney(groups[2], groups[3], l.currencies[groups[1]])
+	})
+	text = replaceStandalone(text, l.currencyTail, func(groups []string) (string, bool) {
+		return l.spellMoney(groups[1], groups[2], l.currencies[groups[3]])
+	})
+	text = replaceStandalone(text, l.unit, func(groups []string) (string, bool) {
+		words, ok := l.spellNumber(groups[1], groups[2], groups[3])
+		if !ok {
+			return "", false
+		}
+		unit := l.units[groups[4]]
+		if groups[3] == "" && strings.ReplaceAll(groups[2], l.group, "") == "1" {
+			if groups[1] == "" {
+				words = l.one
+			}
+			return words + " " + unit.one, true
+		}
+		return words + " " + unit.many, true
+	})
+	if l.ordinalNumber != nil {
+		text = replaceStandalone(text, l.ordinalNumber, func(groups []string) (string, bool) {
+			value, ok := parseWhole(groups[1], "")
+			if !ok {
+				return "", false
+			}
+			return l.ordinal(value), true
+		})
+	}
+	text = replaceStandalone(text, l.number, func(groups []string) (string, bool) {
+		return l.spellNumber(groups[1], groups[2], groups[3])
+	})
+	for i, pattern := range l.abbreviationPatterns {
+		spoken := l.abbreviations[i].spoken
+		text = replaceStandalone(text, pattern, func(groups []string) (string, bool) {
+			if first, _ := utf8.DecodeRuneInString(groups[0]); unicode.IsUpper(first) {
+				return capitalize(spoken), true
+			}
+			return spoken, true
+		})
+	}
+	return text
+}
+
+// spellNumber spells out a number with an optional sign and decimals. Whole numbers with
+// a leading zero, such as "007", are read digit by digit unless they are grouped.
+func (l *normalizationLanguage) spellNumber(sign, whole, fraction string) (string, bool) {
+	var words string
+	if len(whole) > 1 && whole[0] == '0' && fraction == "" && !strings.Contains(whole, l.group) {
+		words = l.spellDigits(whole)
+	} else {
+		value, ok := parseWhole(whole, l.group)
+		if !ok {
+			return "", false
+		}
+		words = l.cardinal(value)
+	}
+	if fraction != "" {
+		words += " " + l.point + " " + l.spellDigits(fraction)
+	}
+	if sign != "" {
+		words = l.minus + " " + words
+	}
+	return words, true
+}
+
+// spellDigits reads digits one by one
+func (l *normalizationLanguage) spellDigits(digits string) string {
+	words := make([]string, len(digits))
+	for i := range digits {
+		words[i] = l.digits[digits[i]-'0']
+	}
+	return strings.Join(words, " ")
+}
+
+// spellMoney spells out an amount of money with its optional cents
+func (l *normalizationLanguage) spellMoney(whole, cents string, currency unitWords) (string, bool) {
+	value, ok := parseWhole(whole, l.group)
+	if !ok {
+		return "", false
+	}
+	minor := 0
+	if cents != "" {
+		minor, _ = strconv.Atoi(cents)
+		if len(cents) == 1 {
+			minor *= 10 // "$3.5" is three dollars fifty
+		}
+	}
+
+	var words []string
+	if value > 0 || minor == 0 || currency.minorMany == "" {
+		words = append(words, l.quantity(value, currency.one, currency.many))
+	}
+	if minor > 0 {
+		if currency.minorMany == "" {
+			return "", false // The currency has no cents
+		}
+		if value > 0 && currency.minorOne == "" {
+			words = append(words, l.cardinal(int64(minor))) // "drei Euro fünfzig"
+		} else {
+			words = append(words, l.quantity(int64(minor), currency.minorOne, currency.minorMany))
+		}
+	}
+	return strings.Join(words, " "), true
+}
+
+// quantity spells out a count of something, such as "one dollar" or "five dollars"
+func (l *normalizationLanguage) quantity(value int64, one, many string) string {
+	if value == 1 {
+		if one == "" {
+			one = many
+		}
+		return l.one + " " + one
+	}
+	return l.cardinal(value) + " " + many
+}
+
+// spellDate spells out a valid date
+func (l *normalizationLanguage) spellDate(day, month, year string) (string, bool) {
+	d, _ := strconv.Atoi(day)
+	m, _ := strconv.Atoi(month)
+	y, _ := strconv.Atoi(year)
+	if d < 1 || d > 31 || m < 1 || m > 12 {
+		return "", false
+	}
+	return l.date(d, m, y, l.months), true
+}
+
+// spellTime spells out a valid time of day
+func (l *normalizationLanguage) spellTime(groups []string) (string, bool) {
+	hour, _ := strconv.Atoi(groups[1])
+	minute, _ := strconv.Atoi(groups[2])
+	suffix := ""
+	if len(groups) > 3 && groups[3] != "" {
+		suffix = strings.ToUpper(groups[3]) + "M"
+	}
+	if minute > 59 || hour > 23 || (suffix != "" && (hour < 1 || hour > 12)) {
+		return "", false
+	}
+	return l.time(hour, minute, suffix), true
+}
+
+// replaceStandalone replaces the matches of pattern that stand on their own with what
+// spell returns for their submatches, leaving matches it cannot spell out as they are
+func replaceStandalone(text string, pattern *regexp.Regexp, spell func(groups []string) (string, bool)) string {
+	matches := pattern.FindAllStringSubmatchIndex(text, -1)
+	if matches == nil {
+		return text
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, match := range matches {
+		if !standalone(text, match[0], match[1]) {
+			continue
+		}
+		groups := make([]string, len(match)/2)
+		for g := range groups {
+			if match[2*g] >= 0 {
+				groups[g] = text[match[2*g]:match[2*g+1]]
+			}
+		}
+		words, ok := spell(groups)
+		if !ok {
+			continue
+		}
+		b.WriteString(text[last:match[0]])
+		b.WriteString(words)
+		last = match[1]
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+// standalone reports whether text[start:end] is a token of its own rather than part of
+// a word, a link or a longer number
+func standalone(text string, start, end int) bool {
+	if start > 0 {
+		before, _ := utf8.DecodeLastRuneInString(text[:start])
+		if unicode.IsLetter(before) || unicode.IsDigit(before) || strings.ContainsRune("/.,:-_@#$€£¥", before) {
+			return false
+		}
+	}
+	if end < len(text) {
+		after, size := utf8.DecodeRuneInString(text[end:])
+		if unicode.IsLetter(after) || unicode.IsDigit(after) || strings.ContainsRune("/_@%€$£¥", after) {
+			return false
+		}
+		// "1.2.3" and "555-1234" continue with more digits
+		if next := text[end+size:]; strings.ContainsRune(".,:-", after) && next != "" && next[0] >= '0' && next[0] <= '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// parseWhole parses a whole number written with thousands separators. Numbers of more
+// than 12 digits are more likely IDs than amounts and are not spelled out.
+func parseWhole(digits, group string) (int64, bool) {
+	if group != "" {
+		digits = strings.ReplaceAll(digits, group, "")
+	}
+	if len(digits) > 12 {
+		return 0, false
+	}
+	value, err := strconv.ParseInt(digits, 10, 64)
+	return value, err == nil
+}
+
+// capitalize upper-cases the first letter of s
+func capitalize(s string) string {
+	first, size := utf8.DecodeRuneInString(s)
+	return string(unicode.ToUpper(first)) + s[size:]
+}
@@ -0,0 +1,317 @@
+package tts
+
+import "strings"
+
+// englishNormalization reads numbers the way American and British speakers say them,
+// without "and": "1,024" is "one thousand twenty four"
+var englishNormalization = languageRules{
+	cardinal: englishCardinal,
+	ordinal:  englishOrdinal,
+	time:     englishTime,
+	date:     englishDate,
+	dayFirst: func(region string) bool {
+		return region != "US" && region != "" // 03/04/2024 is March 4 in the US and 3 April elsewhere
+	},
+	one:            "one",
+	digits:         englishOnes[:10],
+	point:          "point",
+	minus:          "minus",
+	decimal:        ".",
+	group:          ",",
+	dateSeparators: "/",
+	months:         []string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+	currencies: map[string]unitWords{
+		"$": {one: "dollar", many: "dollars", minorOne: "cent", minorMany: "cents"},
+		"€": {one: "euro", many: "euros", minorOne: "cent", minorMany: "cents"},
+		"£": {one: "pound", many: "pounds", minorOne: "penny", minorMany: "pence"},
+		"¥": {one: "yen", many: "yen"},
+	},
+	units: map[string]unitWords{
+		"%":    {one: "percent", many: "percent"},
+		"km":   {one: "kilometer", many: "kilometers"},
+		"km/h": {one: "kilometer per hour", many: "kilometers per hour"},
+		"mph":  {one: "mile per hour", many: "miles per hour"},
+		"kg":   {one: "kilogram", many: "kilograms"},
+		"cm":   {one: "centimeter", many: "centimeters"},
+		"mm":   {one: "millimeter", many: "millimeters"},
+		"°C":   {one: "degree Celsius", many: "degrees Celsius"},
+		"°F":   {one: "degree Fahrenheit", many: "degrees Fahrenheit"},
+	},
+	abbreviations: []abbreviation{
+		{"e.g.", "for example"},
+		{"i.e.", "that is"},
+		{"etc.", "et cetera"},
+		{"vs.", "versus"},
+		{"approx.", "approximately"},
+		{"Dr.", "Doctor"},
+		{"Mr.", "Mister"},
+		{"Mrs.", "Missus"},
+		{"w/o", "without"},
+		{"w/", "with"},
+	},
+	ordinals: true,
+	clock12:  true,
+}
+
+// germanNormalization reads numbers as single words up to a million, the way they are
+// written out in German: "1.024" is "eintausendvierundzwanzig"
+var germanNormalization = languageRules{
+	cardinal:       germanCardinal,
+	ordinal:        germanOrdinal,
+	time:           germanTime,
+	date:           germanDate,
+	dayFirst:       func(region string) bool { return true },
+	one:            "ein",
+	digits:         germanOnes[:10],
+	point:          "Komma",
+	minus:          "minus",
+	decimal:        ",",
+	group:          ".",
+	dateSeparators: "./",
+	months:         []string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+	currencies: map[string]unitWords{
+		// Without a word for one cent, "3,50 €" is read "drei Euro fünfzig"
+		"€": {one: "Euro", many: "Euro", minorMany: "Cent"},
+		"$": {one: "Dollar", many: "Dollar", minorMany: "Cent"},
+		"£": {one: "Pfund", many: "Pfund", minorMany: "Pence"},
+		"¥": {one: "Yen", many: "Yen"},
+	},
+	units: map[string]unitWords{
+		"%":    {one: "Prozent", many: "Prozent"},
+		"km":   {one: "Kilometer", many: "Kilometer"},
+		"km/h": {one: "Kilometer pro Stunde", many: "Kilometer pro Stunde"},
+		"kg":   {one: "Kilogramm", many: "Kilogramm"},
+		"cm":   {one: "Zentimeter", many: "Zentimeter"},
+		"mm":   {one: "Millimeter", many: "Millimeter"},
+		"°C":   {one: "Grad Celsius", many: "Grad Celsius"},
+		"°F":   {one: "Grad Fahrenheit", many: "Grad Fahrenheit"},
+	},
+	abbreviations: []abbreviation{
+		{"z.B.", "zum Beispiel"},
+		{"d.h.", "das heißt"},
+		{"usw.", "und so weiter"},
+		{"bzw.", "beziehungsweise"},
+		{"ca.", "circa"},
+		{"evtl.", "eventuell"},
+		{"ggf.", "gegebenenfalls"},
+		{"inkl.", "inklusive"},
+		{"vs.", "versus"},
+		{"Nr.", "Nummer"},
+		{"Dr.", "Doktor"},
+	},
+}
+
+var (
+	englishOnes = []string{"zero", "one", "two", "three", "four", "five", "six", "seven", "eight", "nine", "ten",
+		"eleven", "twelve", "thirteen", "fourteen", "fifteen", "sixteen", "seventeen", "eighteen", "nineteen"}
+	englishTens = []string{"", "", "twenty", "thirty", "forty", "fifty", "sixty", "seventy", "eighty", "ninety"}
+
+	// englishIrregularOrdinals are the ordinals not formed by adding "th"
+	englishIrregularOrdinals = map[string]string{
+		"one": "first", "two": "second", "three": "third", "five": "fifth", "eight": "eighth", "nine": "ninth", "twelve": "twelfth",
+	}
+
+	germanOnes = []string{"null", "eins", "zwei", "drei", "vier", "fünf", "sechs", "sieben", "acht", "neun", "zehn",
+		"elf", "zwölf", "dreizehn", "vierzehn", "fünfzehn", "sechzehn", "siebzehn", "achtzehn", "neunzehn"}
+	germanTens = []string{"", "", "zwanzig", "dreißig", "vierzig", "fünfzig", "sechzig", "siebzig", "achtzig", "neunzig"}
+
+	// germanIrregularOrdinals are the ordinals below 20 not formed by adding "te"
+	germanIrregularOrdinals = map[int64]string{1: "erste", 3: "dritte", 7: "siebte", 8: "achte"}
+)
+
+// numberScale is a power of a thousand with its English and German names
+type numberScale struct {
+	value                 int64
+	english               string
+	germanOne, germanMany string
+}
+
+// numberScales are the scales above a thousand, largest first. Numbers are spelled out
+// up to 12 digits, so billions are the largest needed.
+var numberScales = []numberScale{
+	{1_000_000_000, "billion", "eine Milliarde", "Milliarden"},
+	{1_000_000, "million", "eine Million", "Millionen"},
+}
+
+// englishCardinal spells out a whole number in English
+func englishCardinal(n int64) string {
+	switch {
+	case n < 20:
+		return englishOnes[n]
+	case n < 100:
+		if n%10 == 0 {
+			return englishTens[n/10]
+		}
+		return englishTens[n/10] + " " + englishOnes[n%10]
+	case n < 1000:
+		return withRest(englishOnes[n/100]+" hundred", n%100, " ", englishCardinal)
+	case n < 1_000_000:
+		return withRest(englishCardinal(n/1000)+" thousand", n%1000, " ", englishCardinal)
+	}
+
+	for _, scale := range numberScales {
+		if n >= scale.value {
+			return withRest(englishCardinal(n/scale.value)+" "+scale.english, n%scale.value, " ", englishCardinal)
+		}
+	}
+	return ""
+}
+
+// englishOrdinal spells out an ordinal in English: 21 is "twenty first"
+func englishOrdinal(n int64) string {
+	words := englishCardinal(n)
+	head, last := "", words
+	if i := strings.LastIndex(words, " "); i >= 0 {
+		head, last = words[:i+1], words[i+1:]
+	}
+
+	switch {
+	case englishIrregularOrdinals[last] != "":
+		last = englishIrregularOrdinals[last]
+	case strings.HasSuffix(last, "y"):
+		last = strings.TrimSuffix(last, "y") + "ieth"
+	default:
+		last += "th"
+	}
+	return head + last
+}
+
+// englishYear spells out a year the way it is said: 1999 is "nineteen ninety nine",
+// 2005 "two thousand five" and 2024 "twenty twenty four"
+func englishYear(year int) string {
+	y := int64(year)
+	switch {
+	case y < 1000 || y >= 2100 || (y >= 2000 && y < 2010):
+		return englishCardinal(y)
+	case y%100 == 0:
+		return englishCardinal(y/100) + " hundred"
+	case y%100 < 10:
+		return englishCardinal(y/100) + " oh " + englishOnes[y%100]
+	default:
+		return englishCardinal(y/100) + " " + englishCardinal(y%100)
+	}
+}
+
+// englishTime spells out a time of day: 9:05 is "nine oh five", 14:00 "fourteen
+// hundred" and 3:00 PM "three PM"
+func englishTime(hour, minute int, suffix string) string {
+	words := englishCardinal(int64(hour))
+	switch {
+	case minute == 0 && suffix != "":
+	case minute == 0 && hour >= 1 && hour <= 12:
+		words += " o'clock"
+	case minute == 0:
+		words += " hundred"
+	case minute < 10:
+		words += " oh " + englishOnes[minute]
+	default:
+		words += " " + englishCardinal(int64(minute))
+	}
+	if suffix != "" {
+		words += " " + suffix
+	}
+	return words
+}
+
+// englishDate spells out a date: "March fifteenth, twenty twenty four"
+func englishDate(day, month, year int, months []string) string {
+	return months[month-1] + " " + englishOrdinal(int64(day)) + ", " + englishYear(year)
+}
+
+// germanCardinal spells out a whole number in German
+func germanCardinal(n int64) string {
+	if n == 0 {
+		return germanOnes[0]
+	}
+
+	for _, scale := range numberScales {
+		if n >= scale.value {
+			count := n / scale.value
+			words := scale.germanOne
+			if count > 1 {
+				words = germanCardinal(count) + " " + scale.germanMany
+			}
+			return withRest(words, n%scale.value, " ", germanCardinal)
+		}
+	}
+	if n >= 1000 {
+		return withRest(germanBelowThousand(n/1000, false)+"tausend", n%1000, "", func(rest int64) string {
+			return germanBelowThousand(rest, true)
+		})
+	}
+	return germanBelowThousand(n, true)
+}
+
+// germanBelowThousand spells out a number below a thousand. A final one is "eins" on its
+// own and "ein" before another word, as in "eintausend".
+func germanBelowThousand(n int64, final bool) string {
+	if n < 100 {
+		return germanBelowHundred(n, final)
+	}
+	return withRest(germanBelowHundred(n/100, false)+"hundert", n%100, "", func(rest int64) string {
+		return germanBelowHundred(rest, final)
+	})
+}
+
+// germanBelowHundred spells out a number below a hundred, putting the ones before the
+// tens: 21 is "einundzwanzig"
+func germanBelowHundred(n int64, final bool) string {
+	switch {
+	case n == 1 && !final:
+		return "ein"
+	case n < 20:
+		return germanOnes[n]
+	case n%10 == 0:
+		return germanTens[n/10]
+	case n%10 == 1:
+		return "einund" + germanTens[n/10]
+	default:
+		return germanOnes[n%10] + "und" + germanTens[n/10]
+	}
+}
+
+// germanOrdinal spells out the ordinal of a number below a hundred, which covers the
+// days of a month: 15 is "fünfzehnte"
+func germanOrdinal(n int64) string {
+	if words, ok := germanIrregularOrdinals[n]; ok {
+		return words
+	}
+	if n < 20 {
+		return germanCardinal(n) + "te"
+	}
+	return germanCardinal(n) + "ste"
+}
+
+// germanYear spells out a year the way it is said: 1999 is "neunzehnhundertneunundneunzig"
+func germanYear(year int) string {
+	y := int64(year)
+	if y >= 1100 && y < 2000 {
+		return withRest(germanBelowHundred(y/100, true)+"hundert", y%100, "", func(rest int64) string {
+			return germanBelowHundred(rest, true)
+		})
+	}
+	return germanCardinal(y)
+}
+
+// germanTime spells out a time of day: 14:30 is "vierzehn Uhr dreißig"
+func germanTime(hour, minute int, suffix string) string {
+	words := germanBelowHundred(int64(hour), false) + " Uhr"
+	if minute > 0 {
+		words += " " + germanCardinal(int64(minute))
+	}
+	return words
+}
+
+// germanDate spells out a date: "fünfzehnter März zweitausendvierundzwanzig"
+func germanDate(day, month, year int, months []string) string {
+	return germanOrdinal(int64(day)) + "r " + months[month-1] + " " + germanYear(year)
+}
+
+// withRest appends the spelled-out remainder of a number to the words for its larger
+// part, unless the remainder is zero
+func withRest(words string, rest int64, separator string, spell func(int64) string) string {
+	if rest == 0 {
+		return words
+	}
+	return words + separator + spell(rest)
+}
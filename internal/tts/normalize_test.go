@@ -0,0 +1,159 @@
+package tts
+
+import (
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTextNormalizer_English(t *testing.T) {
+	normalizer := NewTextNormalizer()
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"grouped number", "I have 1,024 files", "I have one thousand twenty four files"},
+		{"plain number", "alice says: 42", "alice says: forty two"},
+		{"zero and teens", "0 or 13", "zero or thirteen"},
+		{"hundreds", "101 and 900", "one hundred one and nine hundred"},
+		{"millions", "3,000,005 people", "three million five people"},
+		{"billions", "2,000,000,000", "two billion"},
+		{"decimal", "pi is 3.14", "pi is three point one four"},
+		{"negative", "it is -5 outside", "it is minus five outside"},
+		{"sentence end", "I ate 3.", "I ate three."},
+		{"leading zero", "agent 007", "agent zero zero seven"},
+		{"grouped leading zero", "0,000 points", "zero points"},
+		{"ordinals", "1st, 2nd, 3rd, 12th and 21st", "first, second, third, twelfth and twenty first"},
+		{"tens ordinal", "the 40th time", "the fortieth time"},
+		{"24-hour time", "meet at 14:30", "meet at fourteen thirty"},
+		{"time with minutes below ten", "at 9:05", "at nine oh five"},
+		{"full hour", "at 9:00", "at nine o'clock"},
+		{"full hour after noon", "at 18:00", "at eighteen hundred"},
+		{"time with suffix", "at 3:05pm", "at three oh five PM"},
+		{"time with dotted suffix", "at 11:00 a.m. sharp", "at eleven AM sharp"},
+		{"scores are not times", "score 3:75", "score 3:75"},
+		{"dollars", "costs $5", "costs five dollars"},
+		{"one dollar", "just $1", "just one dollar"},
+		{"dollars and cents", "$3.50 each", "three dollars fifty cents each"},
+		{"single decimal cents", "$3.5", "three dollars fifty cents"},
+		{"cents only", "$0.99", "ninety nine cents"},
+		{"large amount", "$1,000,000", "one million dollars"},
+		{"euros after amount", "20 €", "twenty euros"},
+		{"pounds", "£1.01", "one pound one penny"},
+		{"yen", "¥500", "five hundred yen"},
+		{"percent", "50% off", "fifty percent off"},
+		{"one unit", "1 km away", "one kilometer away"},
+		{"units", "5km at 30 km/h", "five kilometers at thirty kilometers per hour"},
+		{"negative temperature", "-3°C tonight", "minus three degrees Celsius tonight"},
+		{"iso date", "on 2024-03-15", "on March fifteenth, twenty twenty four"},
+		{"us date", "on 03/04/2024", "on March fourth, twenty twenty four"},
+		{"year two thousand", "2005-01-01", "January first, two thousand five"},
+		{"nineties", "12/31/1999", "December thirty first, nineteen ninety nine"},
+		{"invalid date", "13/13/2024", "13/13/2024"},
+		{"abbreviations", "fruit, e.g. apples, etc.", "fruit, for example apples, et cetera"},
+		{"capitalized abbreviation", "I.e. no", "That is no"},
+		{"titles", "Dr. Who vs. Mr. Smith", "Doctor Who versus Mister Smith"},
+		{"with and without", "tea w/ milk, w/o sugar", "tea with milk, without sugar"},
+		{"words with digits", "mp3 and 4K and user123", "mp3 and 4K and user123"},
+		{"version number", "v1.2.3 and 1.2.3", "v1.2.3 and 1.2.3"},
+		{"phone number", "call 555-1234", "call 555-1234"},
+		{"ids are not spelled out", "id 123456789012345", "id 123456789012345"},
+		{"links", "see example.com/page/42", "see example.com/page/42"},
+		{"abbreviation inside a word", "etcetera and pi.e.", "etcetera and pi.e."},
+		{"ranges", "5-10 people", "5-10 people"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := normalizer.Normalize(tt.input, "en-US"); got != tt.want {
+				t.Errorf("Normalize(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTextNormalizer_EnglishRegions(t *testing.T) {
+	normalizer := NewTextNormalizer()
+
+	// Numeric dates put the day first outside the US
+	if got := normalizer.Normalize("03/04/2024", "en-GB"); got != "April third, twenty twenty four" {
+		t.Errorf("Expected a day-first date for en-GB, got %q", got)
+	}
+	if got := normalizer.Normalize("03/04/2024", "en-US"); got != "March fourth, twenty twenty four" {
+		t.Errorf("Expected a month-first date for en-US, got %q", got)
+	}
+}
+
+func TestTextNormalizer_German(t *testing.T) {
+	normalizer := NewTextNormalizer()
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"grouped number", "1.024 Dateien", "eintausendvierundzwanzig Dateien"},
+		{"one on its own", "Platz 1", "Platz eins"},
+		{"ones before tens", "21 und 99", "einundzwanzig und neunundneunzig"},
+		{"hundreds", "101 und 300", "einhunderteins und dreihundert"},
+		{"thousands", "21.000 und 101.001", "einundzwanzigtausend und einhunderteintausendeins"},
+		{"millions", "1.000.000 und 2.300.000", "eine Million und zwei Millionen dreihunderttausend"},
+		{"billions", "3.000.000.000", "drei Milliarden"},
+		{"decimal", "3,14", "drei Komma eins vier"},
+		{"negative", "-7 Grad", "minus sieben Grad"},
+		{"time", "um 14:30", "um vierzehn Uhr dreißig"},
+		{"full hour", "um 1:00", "um ein Uhr"},
+		{"time with one minute", "um 14:01", "um vierzehn Uhr eins"},
+		{"euros", "3,50 €", "drei Euro fünfzig"},
+		{"one euro", "1 €", "ein Euro"},
+		{"cents only", "0,99 €", "neunundneunzig Cent"},
+		{"euro sign first", "€20", "zwanzig Euro"},
+		{"percent", "50 %", "fünfzig Prozent"},
+		{"one unit", "1 km", "ein Kilometer"},
+		{"temperature", "-3 °C", "minus drei Grad Celsius"},
+		{"dotted date", "am 15.03.2024", "am fünfzehnter März zweitausendvierundzwanzig"},
+		{"irregular ordinals", "1.1.1999 und 3.7.2000", "erster Januar neunzehnhundertneunundneunzig und dritter Juli zweitausend"},
+		{"iso date", "2024-08-21", "einundzwanzigster August zweitausendvierundzwanzig"},
+		{"abbreviations", "Obst, z.B. Äpfel, usw.", "Obst, zum Beispiel Äpfel, und so weiter"},
+		{"spaced abbreviation", "d. h. nein", "das heißt nein"},
+		{"capitalized abbreviation", "Z.B. so", "Zum Beispiel so"},
+		{"english separators are not grouping", "1,024", "eins Komma null zwei vier"},
+		{"version number", "1.2.3", "1.2.3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := normalizer.Normalize(tt.input, "de-DE"); got != tt.want {
+				t.Errorf("Normalize(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTextNormalizer_OtherLanguages(t *testing.T) {
+	normalizer := NewTextNormalizer()
+
+	// Languages without rules are left as written for the engine
+	for _, languageCode := range []string{"fr-FR", "ja-JP", ""} {
+		if got := normalizer.Normalize("1,024 e.g. 14:30", languageCode); got != "1,024 e.g. 14:30" {
+			t.Errorf("Expected text for %q to be unchanged, got %q", languageCode, got)
+		}
+	}
+}
+
+func FuzzNormalize(f *testing.F) {
+	f.Add("I have 1,024 files, 3.14 and -5 at 14:30 on 24.12.2025")
+	f.Add("$12.50 or 99999999999999999999 people, the 21st")
+	f.Add("z.B. 1.000,5 € um 9:05 Uhr")
+
+	normalizer := NewTextNormalizer()
+	f.Fuzz(func(t *testing.T, text string) {
+		for _, language := range []string{"en-US", "de-DE", "fr-FR"} {
+			result := normalizer.Normalize(text, language)
+			if utf8.ValidString(text) && !utf8.ValidString(result) {
+				t.Errorf("Normalize(%q, %q) = %q is not valid UTF-8", text, language, result)
+			}
+		}
+	})
+}
@@ -0,0 +1,237 @@
+package tts
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// Ogg page layout constants (RFC 3533)
+const (
+	oggCapturePattern   = "OggS"
+	oggPageHeaderLength = 27
+	oggContinuedPacket  = 0x01
+	oggFirstPage        = 0x02
+	oggLastPage         = 0x04
+	oggMaxSegments      = 255
+)
+
+// oggCRCTable holds the CRC-32 Ogg checksums pages with: polynomial 0x04c11db7, not
+// reflected, starting from zero
+var oggCRCTable = func() (table [256]uint32) {
+	for i := range table {
+		crc := uint32(i) << 24
+		for bit := 0; bit < 8; bit++ {
+			if crc&0x80000000 != 0 {
+				crc = crc<<1 ^ 0x04c11db7
+			} else {
+				crc <<= 1
+			}
+		}
+		table[i] = crc
+	}
+	return table
+}()
+
+// oggCRC returns the Ogg checksum of data
+func oggCRC(data []byte) uint32 {
+	var crc uint32
+	for _, b := range data {
+		crc = crc<<8 ^ oggCRCTable[byte(crc>>24)^b]
+	}
+	return crc
+}
+
+// demuxOggOpus extracts the Opus audio packets from an Ogg Opus stream (RFC 7845). The
+// OpusHead and OpusTags header packets are validated and dropped, so every returned
+// packet can be sent to Discord as-is.
+func demuxOggOpus(data []byte) ([][]byte, error) {
+	var packets [][]byte
+	var partial []byte
+	offset := 0
+
+	for offset < len(data) {
+		if len(data)-offset < oggPageHeaderLength {
+			return nil, fmt.Errorf("truncated Ogg page header at offset %d", offset)
+		}
+		header := data[offset : offset+oggPageHeaderLength]
+		if string(header[0:4]) != oggCapturePattern {
+			return nil, fmt.Errorf("missing Ogg capture pattern at offset %d", offset)
+		}
+		if header[4] != 0 {
+			return nil, fmt.Errorf("unsupported Ogg version %d", header[4])
+		}
+		if header[5]&oggContinuedPacket == 0 && partial != nil {
+			return nil, fmt.Errorf("Ogg page at offset %d does not continue the previous packet", offset)
+		}
+
+		segmentCount := int(header[26])
+		segmentTable := offset + oggPageHeaderLength
+		body := segmentTable + segmentCount
+		if body > len(data) {
+			return nil, fmt.Errorf("truncated Ogg segment table at offset %d", offset)
+		}
+
+		// Segments shorter than 255 bytes end a packet; 255 means the packet continues
+		for _, size := range data[segmentTable:body] {
+			end := body + int(size)
+			if end > len(data) {
+				return nil, fmt.Errorf("truncated Ogg page body at offset %d", offset)
+			}
+			partial = append(partial, data[body:end]...)
+			body = end
+
+			if size < 255 {
+				packets = append(packets, partial)
+				partial = nil
+			}
+		}
+		offset = body
+	}
+
+	if partial != nil {
+		return nil, fmt.Errorf("Ogg stream ends inside a packet")
+	}
+	if len(packets) < 2 || !bytes.HasPrefix(packets[0], []byte("OpusHead")) || !bytes.HasPrefix(packets[1], []byte("OpusTags")) {
+		return nil, fmt.Errorf("stream is not Ogg Opus")
+	}
+
+	return packets[2:], nil
+}
+
+// opusPacketSamples returns the duration of an Opus packet in samples per channel at
+// 48kHz, read from its TOC byte (RFC 6716 section 3.1). It returns 0 for malformed packets.
+func opusPacketSamples(packet []byte) int {
+	if len(packet) == 0 {
+		return 0
+	}
+
+	toc := packet[0]
+	config := int(toc >> 3)
+
+	var frameSamples int
+	switch {
+	case config < 12: // SILK-only: 10, 20, 40 or 60ms
+		frameSamples = []int{480, 960, 1920, 2880}[config%4]
+	case config < 16: // Hybrid: 10 or 20ms
+		frameSamples = []int{480, 960}[config%2]
+	default: // CELT-only: 2.5, 5, 10 or 20ms
+		frameSamples = []int{120, 240, 480, 960}[config%4]
+	}
+
+	switch toc & 0x03 {
+	case 0:
+		return frameSamples
+	case 1, 2:
+		return 2 * frameSamples
+	default:
+		if len(packet) < 2 {
+			return 0
+		}
+		return int(packet[1]&0x3F) * frameSamples
+	}
+}
+
+// oggOpusWriter muxes Opus packets into an Ogg Opus stream (RFC 7845), the format
+// recordings are saved and Icecast streams are sent in. Packets are never split across
+// pages, so every page ends on a whole packet and a listener can start at any page.
+type oggOpusWriter struct {
+	w        io.Writer
+	serial   uint32
+	sequence uint32
+	granule  uint64 // Samples per channel written so far
+	started  bool
+}
+
+// newOggOpusWriter creates a writer for a 48kHz stereo stream. serial tells streams apart
+// when they are chained, as in an Icecast mount that a new session takes over.
+func newOggOpusWriter(w io.Writer, serial uint32) *oggOpusWriter {
+	return &oggOpusWriter{w: w, serial: serial}
+}
+
+// WritePackets writes packets in as few pages as they fit in, preceded by the OpusHead
+// and OpusTags headers on the first call
+func (o *oggOpusWriter) WritePackets(packets [][]byte) error {
+	if !o.started {
+		if err := o.writeHeaders(); err != nil {
+			return err
+		}
+		o.started = true
+	}
+
+	var lacing, body []byte
+	for _, packet := range packets {
+		// A packet takes a 255 lacing value per full 255 bytes and ends with a shorter one
+		segments := len(packet)/255 + 1
+		if segments > oggMaxSegments {
+			return fmt.Errorf("Opus packet of %d bytes does not fit in an Ogg page", len(packet))
+		}
+		if len(lacing)+segments > oggMaxSegments {
+			if err := o.writePage(0, lacing, body); err != nil {
+				return err
+			}
+			lacing, body = nil, nil
+		}
+
+		for i := 0; i < segments-1; i++ {
+			lacing = append(lacing, 255)
+		}
+		lacing = append(lacing, byte(len(packet)%255))
+		body = append(body, packet...)
+		o.granule += uint64(opusPacketSamples(packet))
+	}
+	if len(lacing) == 0 {
+		return nil
+	}
+	return o.writePage(0, lacing, body)
+}
+
+// Close ends the stream with an empty last page. It does not close the underlying writer.
+func (o *oggOpusWriter) Close() error {
+	if !o.started {
+		return nil
+	}
+	return o.writePage(oggLastPage, nil, nil)
+}
+
+// writeHeaders writes the OpusHead and OpusTags packets, each on a page of its own
+func (o *oggOpusWriter) writeHeaders() error {
+	head := []byte("OpusHead")
+	head = append(head, 1, discordChannels) // Version and channel count
+	head = binary.LittleEndian.AppendUint16(head, 0)
+	head = binary.LittleEndian.AppendUint32(head, discordSampleRate)
+	head = binary.LittleEndian.AppendUint16(head, 0) // Output gain
+	head = append(head, 0)                           // Channel mapping family: mono or stereo
+	if err := o.writePage(oggFirstPage, []byte{byte(len(head))}, head); err != nil {
+		return err
+	}
+
+	vendor := "darrot"
+	tags := []byte("OpusTags")
+	tags = binary.LittleEndian.AppendUint32(tags, uint32(len(vendor)))
+	tags = append(tags, vendor...)
+	tags = binary.LittleEndian.AppendUint32(tags, 0) // No user comments
+	return o.writePage(0, []byte{byte(len(tags))}, tags)
+}
+
+// writePage writes one Ogg page holding the given lacing values and body
+func (o *oggOpusWriter) writePage(headerType byte, lacing, body []byte) error {
+	page := make([]byte, 0, oggPageHeaderLength+len(lacing)+len(body))
+	page = append(page, oggCapturePattern...)
+	page = append(page, 0, headerType)
+	page = binary.LittleEndian.AppendUint64(page, o.granule)
+	page = binary.LittleEndian.AppendUint32(page, o.serial)
+	page = binary.LittleEndian.AppendUint32(page, o.sequence)
+	page = binary.LittleEndian.AppendUint32(page, 0) // Checksum, filled in below
+	page = append(page, byte(len(lacing)))
+	page = append(page, lacing...)
+	page = append(page, body...)
+	binary.LittleEndian.PutUint32(page[22:26], oggCRC(page))
+
+	o.sequence++
+	if _, err := o.w.Write(page); err != nil {
+		return fmt.Errorf("failed to write Ogg page: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package tts
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// oggPage builds an Ogg page holding the given segments. CRCs are not checked by the
+// demuxer, so they are left zero.
+func oggPage(headerType byte, segments [][]byte) []byte {
+	page := []byte(oggCapturePattern)
+	page = append(page, 0, headerType)
+	page = append(page, make([]byte, 20)...) // Granule position, serial, sequence and CRC
+	page = append(page, byte(len(segments)))
+	for _, segment := range segments {
+		page = append(page, byte(len(segment)))
+	}
+	for _, segment := range segments {
+		page = append(page, segment...)
+	}
+	return page
+}
+
+func oggOpusHeaders() []byte {
+	stream := oggPage(0x02, [][]byte{[]byte("OpusHead\x01\x01\x38\x01\x80\xbb\x00\x00\x00\x00\x00")})
+	return append(stream, oggPage(0, [][]byte{[]byte("OpusTagsvendor")})...)
+}
+
+// oggOpusStream muxes Opus packets into an Ogg Opus stream, one page per packet, as
+// Google TTS returns them for OGG_OPUS requests
+func oggOpusStream(packets [][]byte) []byte {
+	stream := oggOpusHeaders()
+	for _, packet := range packets {
+		// Packets are laced into 255-byte segments ended by a shorter one
+		var segments [][]byte
+		for len(packet) >= 255 {
+			segments = append(segments, packet[:255])
+			packet = packet[255:]
+		}
+		stream = append(stream, oggPage(0, append(segments, packet))...)
+	}
+	return stream
+}
+
+func TestDemuxOggOpus(t *testing.T) {
+	// A 300-byte packet is laced as 255 + 45 and split across two pages
+	long := append([]byte{0xFC}, bytes.Repeat([]byte{0xAA}, 299)...)
+
+	stream := oggOpusHeaders()
+	stream = append(stream, oggPage(0, [][]byte{{0xFC, 1, 2}, long[:255]})...)
+	stream = append(stream, oggPage(oggContinuedPacket|0x04, [][]byte{long[255:], {0xFC, 3}})...)
+
+	packets, err := demuxOggOpus(stream)
+	require.NoError(t, err)
+	assert.Equal(t, [][]byte{{0xFC, 1, 2}, long, {0xFC, 3}}, packets)
+}
+
+func TestDemuxOggOpus_Errors(t *testing.T) {
+	audio := oggPage(0, [][]byte{{0xFC, 1}})
+
+	tests := []struct {
+		name   string
+		stream []byte
+	}{
+		{"not ogg", []byte("RIFF0000WAVEfmt ")},
+		{"truncated header", append(oggOpusHeaders(), audio[:10]...)},
+		{"truncated body", append(oggOpusHeaders(), audio[:len(audio)-1]...)},
+		{"missing opus headers", audio},
+		{"unfinished packet", append(oggOpusHeaders(), oggPage(0, [][]byte{bytes.Repeat([]byte{1}, 255)})...)},
+		{"unexpected new packet", append(append(oggOpusHeaders(), oggPage(0, [][]byte{bytes.Repeat([]byte{1}, 255)})...), audio...)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := demuxOggOpus(tt.stream)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestOpusPacketSamples(t *testing.T) {
+	tests := []struct {
+		name    string
+		packet  []byte
+		samples int
+	}{
+		{"empty", nil, 0},
+		{"CELT 20ms", []byte{31 << 3}, 960},
+		{"CELT 10ms", []byte{30 << 3}, 480},
+		{"SILK 20ms", []byte{1 << 3}, 960},
+		{"SILK 60ms", []byte{3 << 3}, 2880},
+		{"hybrid 20ms", []byte{13 << 3}, 960},
+		{"two CELT 10ms frames", []byte{30<<3 | 1}, 960},
+		{"three CELT 20ms frames", []byte{31<<3 | 3, 3}, 2880},
+		{"missing frame count", []byte{31<<3 | 3}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.samples, opusPacketSamples(tt.packet))
+		})
+	}
+}
+
+func FuzzDemuxOggOpus(f *testing.F) {
+	f.Add(append(oggOpusHeaders(), oggPage(0x04, [][]byte{{0xFC, 1, 2}})...))
+	f.Add(oggPage(oggContinuedPacket, [][]byte{bytes.Repeat([]byte{0xAA}, 255)}))
+	f.Add([]byte(oggCapturePattern))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		packets, err := demuxOggOpus(data)
+		if err != nil {
+			return
+		}
+		for _, packet := range packets {
+			assert.NotEmpty(t, packet)
+		}
+	})
+}
+
+func TestOggCRC(t *testing.T) {
+	assert.Equal(t, uint32(0x89A1897F), oggCRC([]byte("123456789")))
+}
+
+func TestOggOpusWriter_RoundTrip(t *testing.T) {
+	// Enough packets to fill more than one page, and one that spans several segments
+	packets := [][]byte{bytes.Repeat([]byte{0xF8}, 700)}
+	for i := 0; i < 300; i++ {
+		packets = append(packets, []byte{0xF8, byte(i)})
+	}
+
+	var stream bytes.Buffer
+	writer := newOggOpusWriter(&stream, 1234)
+	require.NoError(t, writer.WritePackets(packets[:100]))
+	require.NoError(t, writer.WritePackets(packets[100:]))
+	require.NoError(t, writer.Close())
+
+	demuxed, err := demuxOggOpus(stream.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, packets, demuxed)
+
+	// Every page carries a valid checksum, and only the last one ends the stream
+	data := stream.Bytes()
+	var headerTypes []byte
+	for len(data) > 0 {
+		require.True(t, bytes.HasPrefix(data, []byte(oggCapturePattern)))
+		segments := int(data[26])
+		size := 27 + segments
+		for _, lacing := range data[27 : 27+segments] {
+			size += int(lacing)
+		}
+		page := append([]byte(nil), data[:size]...)
+		crc := binary.LittleEndian.Uint32(page[22:26])
+		copy(page[22:26], []byte{0, 0, 0, 0})
+		assert.Equal(t, oggCRC(page), crc)
+
+		headerTypes = append(headerTypes, data[5])
+		data = data[size:]
+	}
+	assert.Equal(t, byte(oggFirstPage), headerTypes[0])
+	assert.Equal(t, byte(oggLastPage), headerTypes[len(headerTypes)-1])
+}
@@ -0,0 +1,352 @@
+package tts
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"darrot/internal/commands/options"
+	"darrot/internal/commands/textcmd"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// maxListedOptIns is how many opted-in users /darrot-optin-admin list shows before the
+// rest are counted, keeping the response within Discord's message length
+const maxListedOptIns = 50
+
+// maxListedConsent is how many opt-in changes /darrot-optin-admin history shows, newest
+// first, before the earlier ones are counted
+const maxListedConsent = 20
+
+// OptInAdminCommandHandler handles administrator management of who is opted in: listing
+// opted-in users, opting a user out, showing a user's opt-in history and opting in voice
+// channel members automatically
+type OptInAdminCommandHandler struct {
+	userService       UserService
+	configService     ConfigService
+	permissionService PermissionService
+	auditLog          *AuditLog
+	localizer         *Localizer
+	logger            *log.Logger
+}
+
+// NewOptInAdminCommandHandler creates a new opt-in administration command handler
+func NewOptInAdminCommandHandler(
+	userService UserService,
+	configService ConfigService,
+	permissionService PermissionService,
+	logger *log.Logger,
+) *OptInAdminCommandHandler {
+	return &OptInAdminCommandHandler{
+		userService:       userService,
+		configService:     configService,
+		permissionService: permissionService,
+		logger:            logger,
+	}
+}
+
+// SetLocalizer sets the localizer used to translate responses
+func (h *OptInAdminCommandHandler) SetLocalizer(localizer *Localizer) {
+	h.localizer = localizer
+}
+
+// SetAuditLog sets the audit log that records opt-outs and mode changes
+func (h *OptInAdminCommandHandler) SetAuditLog(auditLog *AuditLog) {
+	h.auditLog = auditLog
+}
+
+// Definition returns the Discord slash command definition for the opt-in administration command
+func (h *OptInAdminCommandHandler) Definition() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:                     "darrot-optin-admin",
+		Description:              "Manage which users have their messages read aloud (Administrator only)",
+		DefaultMemberPermissions: adminCommandPermissions(),
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "list",
+				Description: "Show the users whose messages are read aloud",
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "opt-out",
+				Description: "Stop reading a user's messages until they opt in again",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionUser,
+						Name:        "user",
+						Description: "The user to opt out",
+						Required:    true,
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "auto-voice",
+				Description: "Opt in members of the bot's voice channel who never chose",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionBoolean,
+						Name:        "enabled",
+						Description: "Turn automatic opt-in of voice channel members on or off (omit to show)",
+						Required:    false,
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "history",
+				Description: "Show when a user's opt-in status changed, who changed it and how",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionUser,
+						Name:        "user",
+						Description: "The user whose opt-in history to show",
+						Required:    true,
+					},
+				},
+			},
+		},
+	}
+}
+
+// Handle processes the opt-in administration command interaction
+func (h *OptInAdminCommandHandler) Handle(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	// Validate guild context
+	if i.GuildID == "" {
+		return h.respondError(s, i, h.localizer.T(i.GuildID, "common.guild_only"))
+	}
+
+	userID := i.Member.User.ID
+	guildID := i.GuildID
+
+	// Validate administrator permissions
+	if err := h.ValidatePermissions(userID, guildID); err != nil {
+		return h.respondError(s, i, h.localizer.T(guildID, "common.permission_denied", err))
+	}
+
+	subcommand, opts, ok := options.FromInteraction(i).Subcommand()
+	if !ok {
+		return h.respondError(s, i, h.localizer.T(guildID, "common.no_subcommand"))
+	}
+
+	switch subcommand {
+	case "list":
+		return h.handleList(s, i, guildID)
+	case "opt-out":
+		return h.handleOptOut(s, i, guildID, opts)
+	case "auto-voice":
+		return h.handleAutoVoice(s, i, guildID, opts)
+	case "history":
+		return h.handleHistory(s, i, guildID, opts)
+	default:
+		return h.respondError(s, i, h.localizer.T(guildID, "common.invalid_subcommand"))
+	}
+}
+
+// handleList shows the users who are opted in
+func (h *OptInAdminCommandHandler) handleList(s *discordgo.Session, i *discordgo.InteractionCreate, guildID string) error {
+	optedIn, err := h.userService.GetOptedInUsers(guildID)
+	if err != nil {
+		h.logger.Printf("Error listing opted-in users for guild %s: %v", guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "optin_admin.list_failed"))
+	}
+
+	return h.respondSuccess(s, i, h.describeOptIns(guildID, optedIn))
+}
+
+// describeOptIns returns a user-facing list of opted-in users, showing at most
+// maxListedOptIns of them
+func (h *OptInAdminCommandHandler) describeOptIns(guildID string, userIDs []string) string {
+	if len(userIDs) == 0 {
+		return h.localizer.T(guildID, "optin_admin.list_empty")
+	}
+
+	userIDs = slices.Clone(userIDs)
+	slices.Sort(userIDs)
+
+	shown := userIDs[:min(len(userIDs), maxListedOptIns)]
+	mentions := make([]string, len(shown))
+	for idx, userID := range shown {
+		mentions[idx] = fmt.Sprintf("<@%s>", userID)
+	}
+
+	message := h.localizer.T(guildID, "optin_admin.list", len(userIDs), strings.Join(mentions, ", "))
+	if hidden := len(userIDs) - len(shown); hidden > 0 {
+		message += h.localizer.T(guildID, "optin_admin.list_more", hidden)
+	}
+	return message
+}
+
+// handleOptOut opts a user out on an administrator's behalf. The user stays opted out,
+// including from automatic opt-in, until they opt in again themselves.
+func (h *OptInAdminCommandHandler) handleOptOut(s *discordgo.Session, i *discordgo.InteractionCreate, guildID string, opts options.Set) error {
+	targetID, ok := opts.UserID("user")
+	if !ok {
+		return h.respondError(s, i, h.localizer.OptionError(guildID, options.Missing("user")))
+	}
+
+	optedIn, err := h.userService.IsOptedIn(targetID, guildID)
+	if err != nil {
+		h.logger.Printf("Error checking opt-in status for user %s in guild %s: %v", targetID, guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "optin.status_failed"))
+	}
+
+	if err := setOptInStatusBy(h.userService, targetID, guildID, false, ConsentMethodAdmin, i.Member.User.ID); err != nil {
+		h.logger.Printf("Error opting out user %s in guild %s: %v", targetID, guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "optin_admin.opt_out_failed", err))
+	}
+
+	// Users who were not opted in are still recorded as opted out, so automatic opt-in
+	// never picks them up
+	message := h.localizer.T(guildID, "optin_admin.opted_out", targetID)
+	if !optedIn {
+		message = h.localizer.T(guildID, "optin_admin.not_opted_in", targetID)
+	}
+
+	err = h.respondSuccess(s, i, message)
+	h.auditLog.RecordConfigChange(guildID, i.Member.User.ID, commandLine(i), message)
+	return err
+}
+
+// handleAutoVoice turns automatic opt-in of voice channel members on or off. With no
+// option it shows whether it is on.
+func (h *OptInAdminCommandHandler) handleAutoVoice(s *discordgo.Session, i *discordgo.InteractionCreate, guildID string, opts options.Set) error {
+	config, err := h.configService.GetGuildConfig(guildID)
+	if err != nil {
+		h.logger.Printf("Error getting guild config for guild %s: %v", guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "optin_admin.auto_voice_get_failed"))
+	}
+	if config == nil {
+		defaultConfig := DefaultGuildTTSConfig(guildID)
+		config = &defaultConfig
+	}
+
+	enabled, set := opts.Bool("enabled")
+	if !set {
+		return h.respondSuccess(s, i, h.describeAutoVoice(guildID, config.AutoOptInVoiceMembers))
+	}
+
+	updated := *config
+	updated.AutoOptInVoiceMembers = enabled
+	if err := h.configService.SetGuildConfig(guildID, &updated); err != nil {
+		h.logger.Printf("Error setting automatic voice opt-in for guild %s: %v", guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "optin_admin.auto_voice_update_failed", err))
+	}
+
+	message := h.localizer.T(guildID, "optin_admin.auto_voice_updated", h.describeAutoVoice(guildID, enabled))
+	err = h.respondSuccess(s, i, message)
+	h.auditLog.RecordConfigChange(guildID, i.Member.User.ID, commandLine(i), message)
+	return err
+}
+
+// handleHistory shows the recorded opt-in changes of a user, for answering data-protection
+// inquiries
+func (h *OptInAdminCommandHandler) handleHistory(s *discordgo.Session, i *discordgo.InteractionCreate, guildID string, opts options.Set) error {
+	targetID, ok := opts.UserID("user")
+	if !ok {
+		return h.respondError(s, i, h.localizer.OptionError(guildID, options.Missing("user")))
+	}
+
+	auditor, ok := h.userService.(ConsentAuditor)
+	if !ok {
+		return h.respondError(s, i, h.localizer.T(guildID, "optin_admin.history_unavailable"))
+	}
+
+	records, err := auditor.GetConsentHistory(targetID, guildID)
+	if err != nil {
+		h.logger.Printf("Error getting opt-in history for user %s in guild %s: %v", targetID, guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "optin_admin.history_failed"))
+	}
+
+	return h.respondSuccess(s, i, h.describeHistory(guildID, targetID, records))
+}
+
+// describeHistory returns a user-facing list of a user's opt-in changes, newest first,
+// showing at most maxListedConsent of them
+func (h *OptInAdminCommandHandler) describeHistory(guildID, userID string, records []ConsentRecord) string {
+	if len(records) == 0 {
+		return h.localizer.T(guildID, "optin_admin.history_empty", userID)
+	}
+
+	lines := []string{h.localizer.T(guildID, "optin_admin.history", userID)}
+	for idx := len(records) - 1; idx >= max(0, len(records)-maxListedConsent); idx-- {
+		record := records[idx]
+
+		status := h.localizer.T(guildID, "optin_admin.history_opted_out")
+		if record.OptedIn {
+			status = h.localizer.T(guildID, "optin_admin.history_opted_in")
+		}
+
+		var method string
+		switch record.Method {
+		case ConsentMethodAuto:
+			method = h.localizer.T(guildID, "optin_admin.history_auto")
+		case ConsentMethodAdmin:
+			method = h.localizer.T(guildID, "optin_admin.history_admin", record.ActorID)
+		case ConsentMethodNotice:
+			method = h.localizer.T(guildID, "optin_admin.history_notice")
+		default:
+			method = h.localizer.T(guildID, "optin_admin.history_command")
+		}
+
+		lines = append(lines, h.localizer.T(guildID, "optin_admin.history_entry", record.Timestamp.Unix(), status, method))
+	}
+
+	message := strings.Join(lines, "\n")
+	if hidden := len(records) - maxListedConsent; hidden > 0 {
+		message += h.localizer.T(guildID, "optin_admin.history_more", hidden)
+	}
+	return message
+}
+
+// describeAutoVoice returns a user-facing description of automatic voice opt-in
+func (h *OptInAdminCommandHandler) describeAutoVoice(guildID string, enabled bool) string {
+	if enabled {
+		return h.localizer.T(guildID, "optin_admin.auto_voice_on")
+	}
+	return h.localizer.T(guildID, "optin_admin.auto_voice_off")
+}
+
+// ValidatePermissions validates that the user has administrator permissions
+func (h *OptInAdminCommandHandler) ValidatePermissions(userID, guildID string) error {
+	canControl, err := h.permissionService.CanControlBot(userID, guildID)
+	if err != nil {
+		return fmt.Errorf("failed to check permissions: %w", err)
+	}
+
+	if !canControl {
+		return withKind(ErrPermission, fmt.Errorf("you must have administrator permissions to manage opt-ins"))
+	}
+
+	return nil
+}
+
+// ValidateChannelAccess is not needed for opt-in administration commands but required by interface
+func (h *OptInAdminCommandHandler) ValidateChannelAccess(userID, channelID string) error {
+	return nil // Not applicable for opt-in administration commands
+}
+
+// Helper methods for response handling
+
+func (h *OptInAdminCommandHandler) respondSuccess(s *discordgo.Session, i *discordgo.InteractionCreate, message string) error {
+	return textcmd.Respond(s, i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: message,
+			Flags:   discordgo.MessageFlagsEphemeral, // Opt-in lists are only shown to administrators
+		},
+	})
+}
+
+func (h *OptInAdminCommandHandler) respondError(s *discordgo.Session, i *discordgo.InteractionCreate, message string) error {
+	return textcmd.Respond(s, i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: "❌ " + message,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
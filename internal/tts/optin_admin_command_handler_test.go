@@ -0,0 +1,112 @@
+package tts
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createTestOptInAdminHandler(t *testing.T) (*OptInAdminCommandHandler, *MockPermissionService) {
+	mockPermissionService := &MockPermissionService{}
+	logger := log.New(os.Stdout, "[TEST] ", log.LstdFlags)
+
+	return NewOptInAdminCommandHandler(&MockUserService{}, &MockConfigService{}, mockPermissionService, logger), mockPermissionService
+}
+
+func TestOptInAdminCommandHandler_Definition(t *testing.T) {
+	handler, _ := createTestOptInAdminHandler(t)
+
+	definition := handler.Definition()
+
+	assert.Equal(t, "darrot-optin-admin", definition.Name)
+	assert.Contains(t, definition.Description, "Administrator only")
+	require.Len(t, definition.Options, 4) // list, opt-out, auto-voice, history subcommands
+
+	optOut := definition.Options[1]
+	assert.Equal(t, "opt-out", optOut.Name)
+	require.Len(t, optOut.Options, 1)
+	assert.Equal(t, discordgo.ApplicationCommandOptionUser, optOut.Options[0].Type)
+	assert.True(t, optOut.Options[0].Required)
+
+	autoVoice := definition.Options[2]
+	assert.Equal(t, "auto-voice", autoVoice.Name)
+	require.Len(t, autoVoice.Options, 1)
+	assert.False(t, autoVoice.Options[0].Required, "omitting enabled shows the current mode")
+
+	history := definition.Options[3]
+	assert.Equal(t, "history", history.Name)
+	require.Len(t, history.Options, 1)
+	assert.Equal(t, discordgo.ApplicationCommandOptionUser, history.Options[0].Type)
+	assert.True(t, history.Options[0].Required)
+}
+
+func TestOptInAdminCommandHandler_ValidatePermissions(t *testing.T) {
+	handler, mockPermissionService := createTestOptInAdminHandler(t)
+
+	mockPermissionService.On("CanControlBot", "admin", "guild123").Return(true, nil)
+	mockPermissionService.On("CanControlBot", "user", "guild123").Return(false, nil)
+	mockPermissionService.On("CanControlBot", "broken", "guild123").Return(false, errors.New("permission check failed"))
+
+	assert.NoError(t, handler.ValidatePermissions("admin", "guild123"))
+	assert.ErrorContains(t, handler.ValidatePermissions("user", "guild123"), "administrator permissions")
+	assert.ErrorContains(t, handler.ValidatePermissions("broken", "guild123"), "failed to check permissions")
+
+	mockPermissionService.AssertExpectations(t)
+}
+
+func TestOptInAdminCommandHandler_DescribeOptIns(t *testing.T) {
+	handler, _ := createTestOptInAdminHandler(t)
+
+	assert.Equal(t, "📋 No users are opted in.", handler.describeOptIns("guild1", nil))
+
+	assert.Equal(t, "📋 **Opted-in users (2):**\n<@111>, <@222>", handler.describeOptIns("guild1", []string{"222", "111"}))
+
+	// Long lists are cut off and the rest counted
+	userIDs := make([]string, maxListedOptIns+5)
+	for idx := range userIDs {
+		userIDs[idx] = fmt.Sprintf("%03d", idx)
+	}
+	description := handler.describeOptIns("guild1", userIDs)
+	assert.Contains(t, description, fmt.Sprintf("(%d)", maxListedOptIns+5))
+	assert.Contains(t, description, fmt.Sprintf("<@%03d>", maxListedOptIns-1))
+	assert.NotContains(t, description, fmt.Sprintf("<@%03d>", maxListedOptIns))
+	assert.Contains(t, description, "…and 5 more")
+}
+
+func TestOptInAdminCommandHandler_DescribeHistory(t *testing.T) {
+	handler, _ := createTestOptInAdminHandler(t)
+
+	assert.Equal(t, "📜 No opt-in changes are recorded for <@111>.", handler.describeHistory("guild1", "111", nil))
+
+	start := time.Unix(1700000000, 0)
+	records := []ConsentRecord{
+		{OptedIn: true, Method: ConsentMethodAuto, ActorID: "111", Timestamp: start},
+		{OptedIn: false, Method: ConsentMethodAdmin, ActorID: "999", Timestamp: start.Add(time.Hour)},
+		{OptedIn: true, Method: ConsentMethodCommand, ActorID: "111", Timestamp: start.Add(2 * time.Hour)},
+		{OptedIn: false, Method: ConsentMethodNotice, ActorID: "111", Timestamp: start.Add(3 * time.Hour)},
+	}
+
+	// Newest changes come first
+	assert.Equal(t, "📜 **Opt-in history of <@111>** (newest first):\n"+
+		"<t:1700010800:f> ❌ opted out from the privacy notice\n"+
+		"<t:1700007200:f> ✅ opted in with an opt-in command\n"+
+		"<t:1700003600:f> ❌ opted out by administrator <@999>\n"+
+		"<t:1700000000:f> ✅ opted in automatically", handler.describeHistory("guild1", "111", records))
+
+	// Long histories are cut off and the earlier changes counted
+	records = make([]ConsentRecord, maxListedConsent+3)
+	for idx := range records {
+		records[idx] = ConsentRecord{OptedIn: idx%2 == 0, Method: ConsentMethodCommand, Timestamp: start.Add(time.Duration(idx) * time.Second)}
+	}
+	description := handler.describeHistory("guild1", "111", records)
+	assert.Contains(t, description, fmt.Sprintf("<t:%d:f>", start.Unix()+int64(maxListedConsent+2)))
+	assert.NotContains(t, description, fmt.Sprintf("<t:%d:f>", start.Unix()+2))
+	assert.Contains(t, description, "…and 3 earlier changes")
+}
@@ -0,0 +1,110 @@
+package tts
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// opusBackend names how PCM audio is encoded to Opus
+type opusBackend string
+
+// Opus backends, in the order they are preferred
+const (
+	opusBackendNative opusBackend = "native" // libopus through cgo
+	opusBackendFFmpeg opusBackend = "ffmpeg" // An ffmpeg process per message
+	opusBackendNone   opusBackend = "none"   // Only voices synthesized straight to Ogg Opus can be played
+)
+
+// detectOpusBackend returns the Opus encoder this build and host offer: libopus when the
+// build has it, otherwise ffmpeg when it is on the PATH
+func detectOpusBackend() (opusBackend, string) {
+	if nativeOpus {
+		return opusBackendNative, ""
+	}
+	if path, err := exec.LookPath("ffmpeg"); err == nil {
+		return opusBackendFFmpeg, path
+	}
+	return opusBackendNone, ""
+}
+
+// OpusEncodingAvailable reports whether this build and host can encode Opus, through
+// libopus or ffmpeg. Without an encoder only voices synthesized straight to Ogg Opus play.
+func OpusEncodingAvailable() bool {
+	backend, _ := detectOpusBackend()
+	return backend != opusBackendNone
+}
+
+// encodeOpusWithFFmpeg encodes 48kHz stereo 16-bit PCM into 20ms Opus packets with the
+// ffmpeg binary at command, reading them back from the Ogg Opus stream it writes
+func encodeOpusWithFFmpeg(command string, pcmData []byte, bitrate int) ([][]byte, error) {
+	cmd := exec.Command(command, "-hide_banner", "-loglevel", "error",
+		"-f", "s16le", "-ar", strconv.Itoa(discordSampleRate), "-ac", strconv.Itoa(discordChannels), "-i", "pipe:0",
+		"-c:a", "libopus", "-b:a", strconv.Itoa(bitrate), "-frame_duration", "20", "-application", "audio",
+		"-f", "ogg", "pipe:1")
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdin = bytes.NewReader(pcmData)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return nil, fmt.Errorf("ffmpeg failed to encode Opus: %w: %s", err, message)
+		}
+		return nil, fmt.Errorf("ffmpeg failed to encode Opus: %w", err)
+	}
+
+	packets, err := demuxOggOpus(stdout.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ffmpeg's Opus output: %w", err)
+	}
+	return packets, nil
+}
+
+// opusEncoding returns the Opus backend the manager encodes PCM with, detecting it on
+// first use
+func (g *GoogleTTSManager) opusEncoding() opusBackend {
+	g.backendOnce.Do(func() {
+		g.opusBackend, g.ffmpegPath = detectOpusBackend()
+		switch g.opusBackend {
+		case opusBackendFFmpeg:
+			log.Printf("Built without native Opus, encoding audio with %s", g.ffmpegPath)
+		case opusBackendNone:
+			log.Printf("Warning: Built without native Opus and ffmpeg was not found; only voices synthesized straight to Opus can be played")
+		}
+	})
+	return g.opusBackend
+}
+
+// encodeOpus encodes 48kHz stereo 16-bit PCM into 20ms Opus packets at the bitrate of
+// pool with the manager's Opus backend, taking a native encoder from pool
+func (g *GoogleTTSManager) encodeOpus(pcmData []byte, pool *OpusEncoderPool) ([][]byte, error) {
+	switch g.opusEncoding() {
+	case opusBackendNative:
+		encoder, err := pool.Get()
+		if err != nil {
+			return nil, err
+		}
+		defer pool.Put(encoder)
+
+		var frames [][]byte
+		writer := newOpusFrameWriter(encoder, func(frame []byte) error {
+			frames = append(frames, frame)
+			return nil
+		})
+		if err := writer.Write(pcmData); err != nil {
+			return nil, err
+		}
+		if err := writer.Flush(); err != nil {
+			return nil, err
+		}
+		return frames, nil
+	case opusBackendFFmpeg:
+		return encodeOpusWithFFmpeg(g.ffmpegPath, pcmData, pool.bitrate)
+	default:
+		return nil, ErrOpusUnavailable
+	}
+}
@@ -0,0 +1,117 @@
+package tts
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// managerWithOpusBackend returns a manager that encodes with the given backend
+func managerWithOpusBackend(backend opusBackend, ffmpegPath string) *GoogleTTSManager {
+	manager := &GoogleTTSManager{}
+	manager.backendOnce.Do(func() {
+		manager.opusBackend = backend
+		manager.ffmpegPath = ffmpegPath
+	})
+	return manager
+}
+
+// requireNativeOpus skips a test that encodes or decodes with libopus in builds without it
+func requireNativeOpus(t *testing.T) {
+	t.Helper()
+	if !nativeOpus {
+		t.Skip("built without libopus")
+	}
+}
+
+// requireOpusEncoding skips a test that encodes Opus when neither libopus nor ffmpeg is available
+func requireOpusEncoding(t *testing.T) {
+	t.Helper()
+	if !OpusEncodingAvailable() {
+		t.Skip("built without libopus and ffmpeg is not installed")
+	}
+}
+
+// fakeFFmpeg writes a script that drains its input and prints an Ogg Opus stream holding
+// packets, standing in for ffmpeg
+func fakeFFmpeg(t *testing.T, packets [][]byte) string {
+	t.Helper()
+
+	var stream bytes.Buffer
+	ogg := newOggOpusWriter(&stream, 1)
+	require.NoError(t, ogg.WritePackets(packets))
+	require.NoError(t, ogg.Close())
+
+	dir := t.TempDir()
+	output := filepath.Join(dir, "output.ogg")
+	require.NoError(t, os.WriteFile(output, stream.Bytes(), 0o600))
+	script := filepath.Join(dir, "ffmpeg")
+	require.NoError(t, os.WriteFile(script, []byte("#!/bin/sh\ncat > /dev/null\ncat "+output+"\n"), 0o700))
+	return script
+}
+
+func TestDetectOpusBackend(t *testing.T) {
+	backend, _ := detectOpusBackend()
+	if nativeOpus {
+		assert.Equal(t, opusBackendNative, backend)
+	} else {
+		assert.NotEqual(t, opusBackendNative, backend)
+	}
+}
+
+func TestGoogleTTSManager_ConvertWithoutOpus(t *testing.T) {
+	manager := managerWithOpusBackend(opusBackendNone, "")
+	pcm := make([]byte, opusSamplesPerFrame*2)
+
+	_, err := manager.convertToDiscordFormat(pcm, AudioFormatDCA)
+	assert.ErrorIs(t, err, ErrOpusUnavailable)
+	_, err = manager.convertToDiscordFormat(pcm, AudioFormatOpus)
+	assert.ErrorIs(t, err, ErrOpusUnavailable)
+
+	converted, err := manager.convertToDiscordFormat(pcm, AudioFormatPCM)
+	require.NoError(t, err)
+	assert.Equal(t, pcm, converted, "PCM needs no encoder")
+}
+
+func TestGoogleTTSManager_ConvertWithFFmpeg(t *testing.T) {
+	packets := [][]byte{{0xF8, 0x01}, {0xF8, 0x02}, {0xF8, 0x03}}
+	manager := managerWithOpusBackend(opusBackendFFmpeg, fakeFFmpeg(t, packets))
+
+	dcaData, err := manager.convertToDiscordFormat(make([]byte, opusSamplesPerFrame*5), AudioFormatDCA)
+	require.NoError(t, err)
+	frames, err := parseDCAFrames(dcaData)
+	require.NoError(t, err)
+	assert.Equal(t, packets, frames)
+
+	opusData, err := manager.convertToDiscordFormat(make([]byte, opusSamplesPerFrame*5), AudioFormatOpus)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0xF8, 0x01, 0xF8, 0x02, 0xF8, 0x03}, opusData)
+}
+
+func TestEncodeOpusWithFFmpeg_Failure(t *testing.T) {
+	script := filepath.Join(t.TempDir(), "ffmpeg")
+	require.NoError(t, os.WriteFile(script, []byte("#!/bin/sh\necho 'Unknown encoder libopus' >&2\nexit 1\n"), 0o700))
+
+	_, err := encodeOpusWithFFmpeg(script, make([]byte, opusSamplesPerFrame*2), dcaBitrate)
+	assert.ErrorContains(t, err, "Unknown encoder libopus")
+}
+
+func TestEncodeOpusWithFFmpeg_Real(t *testing.T) {
+	path, err := exec.LookPath("ffmpeg")
+	if err != nil {
+		t.Skip("ffmpeg is not installed")
+	}
+
+	packets, err := encodeOpusWithFFmpeg(path, goldenTone(time.Second), dcaBitrate)
+	require.NoError(t, err)
+	require.NotEmpty(t, packets)
+	for i, packet := range packets {
+		assert.Equal(t, opusFrameSamples, opusPacketSamples(packet), "packet %d", i)
+	}
+}
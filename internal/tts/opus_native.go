@@ -0,0 +1,26 @@
+//go:build cgo && !purego
+
+package tts
+
+import "gopkg.in/hraban/opus.v2"
+
+// nativeOpus reports whether this build encodes and decodes Opus with libopus
+const nativeOpus = true
+
+// newOpusEncoder creates a libopus encoder for general audio
+func newOpusEncoder(sampleRate, channels int) (opusEncoder, error) {
+	encoder, err := opus.NewEncoder(sampleRate, channels, opus.AppAudio)
+	if err != nil {
+		return nil, err
+	}
+	return encoder, nil
+}
+
+// newOpusDecoder creates a libopus decoder
+func newOpusDecoder(sampleRate, channels int) (opusDecoder, error) {
+	decoder, err := opus.NewDecoder(sampleRate, channels)
+	if err != nil {
+		return nil, err
+	}
+	return decoder, nil
+}
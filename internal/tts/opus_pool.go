@@ -0,0 +1,151 @@
+package tts
+
+import "fmt"
+
+// Discord voice audio format and Opus encoder settings
+const (
+	discordSampleRate   = 48000 // 48kHz
+	discordChannels     = 2     // Stereo
+	opusFrameSamples    = 960   // 20ms per channel at 48kHz
+	opusSamplesPerFrame = opusFrameSamples * discordChannels
+	maxOpusFrameBytes   = 4000
+	dcaBitrate          = 64000  // 64kbps
+	rawOpusBitrate      = 128000 // 128kbps for higher quality raw Opus
+)
+
+// DefaultOpusPoolSize is the number of idle encoders kept per bitrate
+const DefaultOpusPoolSize = 4
+
+// opusEncoder encodes PCM into Opus packets
+type opusEncoder interface {
+	Encode(pcm []int16, data []byte) (int, error)
+	SetBitrate(bitrate int) error
+	Reset() error
+}
+
+// opusDecoder decodes Opus packets into PCM
+type opusDecoder interface {
+	Decode(data []byte, pcm []int16) (int, error)
+}
+
+// OpusEncoderPool keeps idle Opus encoders for reuse so each message does not allocate a
+// new encoder. Encoders are reset before they are returned to the pool.
+type OpusEncoderPool struct {
+	bitrate int
+	idle    chan opusEncoder
+}
+
+// NewOpusEncoderPool creates a pool of Discord-format encoders with the given bitrate,
+// keeping up to size idle encoders
+func NewOpusEncoderPool(bitrate, size int) *OpusEncoderPool {
+	return &OpusEncoderPool{
+		bitrate: bitrate,
+		idle:    make(chan opusEncoder, size),
+	}
+}
+
+// Get returns an idle encoder or creates a new one
+func (p *OpusEncoderPool) Get() (opusEncoder, error) {
+	select {
+	case encoder := <-p.idle:
+		return encoder, nil
+	default:
+	}
+
+	encoder, err := newOpusEncoder(discordSampleRate, discordChannels)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Opus encoder: %w", err)
+	}
+
+	if err := encoder.SetBitrate(p.bitrate); err != nil {
+		return nil, fmt.Errorf("failed to set bitrate: %w", err)
+	}
+
+	return encoder, nil
+}
+
+// Put resets an encoder and returns it to the pool. Encoders that cannot be reset or do
+// not fit in the pool are dropped.
+func (p *OpusEncoderPool) Put(encoder opusEncoder) {
+	if encoder == nil {
+		return
+	}
+
+	// Encoders carry prediction state from the previous stream
+	if err := encoder.Reset(); err != nil {
+		return
+	}
+
+	select {
+	case p.idle <- encoder:
+	default:
+	}
+}
+
+// opusFrameWriter encodes 48kHz stereo 16-bit PCM into 20ms Opus frames as it arrives and
+// passes each frame to emit. PCM that does not fill a frame is kept until the next Write.
+type opusFrameWriter struct {
+	encoder opusEncoder
+	emit    func(frame []byte) error
+	pending []int16
+	frames  int
+}
+
+// newOpusFrameWriter creates a frame writer around an encoder
+func newOpusFrameWriter(encoder opusEncoder, emit func(frame []byte) error) *opusFrameWriter {
+	return &opusFrameWriter{
+		encoder: encoder,
+		emit:    emit,
+		pending: make([]int16, 0, opusSamplesPerFrame),
+	}
+}
+
+// Write encodes every complete frame of PCM data
+func (w *opusFrameWriter) Write(pcmData []byte) error {
+	if len(pcmData)%2 != 0 {
+		return fmt.Errorf("PCM data length must be even (16-bit samples)")
+	}
+
+	for i := 0; i < len(pcmData); i += 2 {
+		// Convert little-endian bytes to int16
+		w.pending = append(w.pending, int16(pcmData[i])|int16(pcmData[i+1])<<8)
+		if len(w.pending) == opusSamplesPerFrame {
+			if err := w.encodePending(); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Flush pads the last partial frame with silence and encodes it
+func (w *opusFrameWriter) Flush() error {
+	if len(w.pending) == 0 {
+		return nil
+	}
+
+	// Pad the rest of the frame with silence
+	for len(w.pending) < opusSamplesPerFrame {
+		w.pending = append(w.pending, 0)
+	}
+	return w.encodePending()
+}
+
+// Frames returns the number of frames encoded so far
+func (w *opusFrameWriter) Frames() int {
+	return w.frames
+}
+
+// encodePending encodes one full frame of pending samples
+func (w *opusFrameWriter) encodePending() error {
+	frame := make([]byte, maxOpusFrameBytes)
+	n, err := w.encoder.Encode(w.pending, frame)
+	if err != nil {
+		return fmt.Errorf("failed to encode Opus frame %d: %w", w.frames, err)
+	}
+
+	w.pending = w.pending[:0]
+	w.frames++
+	return w.emit(frame[:n])
+}
@@ -0,0 +1,72 @@
+package tts
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpusEncoderPool_ReusesEncoders(t *testing.T) {
+	requireNativeOpus(t)
+	pool := NewOpusEncoderPool(dcaBitrate, 1)
+
+	first, err := pool.Get()
+	require.NoError(t, err)
+	pool.Put(first)
+
+	second, err := pool.Get()
+	require.NoError(t, err)
+	assert.Same(t, first, second)
+
+	// The pool only keeps up to its size idle encoders
+	assert.Empty(t, pool.idle)
+	third, err := pool.Get()
+	require.NoError(t, err)
+	pool.Put(second)
+	pool.Put(third)
+	assert.Len(t, pool.idle, 1)
+}
+
+func TestOpusFrameWriter(t *testing.T) {
+	requireNativeOpus(t)
+	pool := NewOpusEncoderPool(dcaBitrate, 1)
+	encoder, err := pool.Get()
+	require.NoError(t, err)
+
+	var frames [][]byte
+	writer := newOpusFrameWriter(encoder, func(frame []byte) error {
+		frames = append(frames, frame)
+		return nil
+	})
+
+	// One and a half frames, written in pieces that do not line up with frame boundaries
+	pcm := make([]byte, opusSamplesPerFrame*3)
+	require.NoError(t, writer.Write(pcm[:1000]))
+	assert.Empty(t, frames, "no frame is emitted before a full frame of PCM arrives")
+	require.NoError(t, writer.Write(pcm[1000:]))
+	assert.Len(t, frames, 1)
+
+	// The partial frame is padded with silence on flush
+	require.NoError(t, writer.Flush())
+	assert.Len(t, frames, 2)
+	assert.Equal(t, 2, writer.Frames())
+
+	assert.Error(t, writer.Write([]byte{1, 2, 3}), "odd-length PCM is rejected")
+}
+
+func TestOpusFrameWriter_EmitError(t *testing.T) {
+	requireNativeOpus(t)
+	pool := NewOpusEncoderPool(dcaBitrate, 1)
+	encoder, err := pool.Get()
+	require.NoError(t, err)
+
+	stopped := errors.New("playback stopped")
+	writer := newOpusFrameWriter(encoder, func(frame []byte) error {
+		return stopped
+	})
+
+	err = writer.Write(make([]byte, opusSamplesPerFrame*2))
+	assert.ErrorIs(t, err, stopped)
+}
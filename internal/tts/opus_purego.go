@@ -0,0 +1,18 @@
+//go:build !cgo || purego
+
+package tts
+
+// nativeOpus reports whether this build encodes and decodes Opus with libopus. Builds
+// without cgo, or with the purego tag, encode through ffmpeg when it is installed and
+// cannot decode Opus at all.
+const nativeOpus = false
+
+// newOpusEncoder always fails: this build has no libopus
+func newOpusEncoder(sampleRate, channels int) (opusEncoder, error) {
+	return nil, ErrOpusUnavailable
+}
+
+// newOpusDecoder always fails: this build has no libopus
+func newOpusDecoder(sampleRate, channels int) (opusDecoder, error) {
+	return nil, ErrOpusUnavailable
+}
@@ -0,0 +1,373 @@
+package tts
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"darrot/internal/commands/options"
+	"darrot/internal/commands/textcmd"
+	"darrot/internal/events"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Owner command limits
+const (
+	// MaxBroadcastLength is the longest text /darrot-owner broadcast posts, which fits in
+	// one field of an audit entry
+	MaxBroadcastLength = 1000
+
+	// ownerGuildListLimit keeps the guild list within Discord's message length
+	ownerGuildListLimit = 1900
+)
+
+// OwnerGate decides who may run /darrot-owner. Unlike PermissionService, which checks a
+// member's roles in one guild, it checks the user against the owner IDs of the bot's
+// configuration, the same in every guild and in direct messages. A nil *OwnerGate lets
+// nobody through.
+type OwnerGate struct {
+	owners map[string]bool
+}
+
+// NewOwnerGate creates a gate that lets the users with ownerIDs through
+func NewOwnerGate(ownerIDs []string) *OwnerGate {
+	owners := make(map[string]bool, len(ownerIDs))
+	for _, id := range ownerIDs {
+		owners[id] = true
+	}
+	return &OwnerGate{owners: owners}
+}
+
+// Configured reports whether any owner is set, so the owner command is worth registering
+func (g *OwnerGate) Configured() bool {
+	return g != nil && len(g.owners) > 0
+}
+
+// IsOwner reports whether a user is one of the bot's owners
+func (g *OwnerGate) IsOwner(userID string) bool {
+	return g != nil && userID != "" && g.owners[userID]
+}
+
+// interactionUserID returns who ran a command, in a guild or in a direct message
+func interactionUserID(i *discordgo.InteractionCreate) string {
+	switch {
+	case i.Member != nil && i.Member.User != nil:
+		return i.Member.User.ID
+	case i.User != nil:
+		return i.User.ID
+	default:
+		return ""
+	}
+}
+
+// ownerGuildAPI is the part of the Discord session that removes the bot from a guild
+type ownerGuildAPI interface {
+	GuildLeave(guildID string, options ...discordgo.RequestOption) error
+}
+
+// credentialReloader is implemented by TTS managers that can read their credentials again
+type credentialReloader interface {
+	ReloadCredentials() (bool, error)
+}
+
+// OwnerCommandHandler handles /darrot-owner, which lets the bot's owners look after every
+// guild the bot is in: list the guilds, leave one, post a notice to every audit channel and
+// reload the configuration. It has its own gate instead of the guild permission checks.
+type OwnerCommandHandler struct {
+	gate           *OwnerGate
+	guildAPI       ownerGuildAPI
+	voiceManager   VoiceManager
+	channelService ChannelService
+	ttsProcessor   TTSProcessor
+	ttsManager     TTSManager
+	eventBus       *events.Bus
+	auditLog       *AuditLog
+	localizer      *Localizer
+	logger         *log.Logger
+}
+
+// NewOwnerCommandHandler creates a new owner command handler
+func NewOwnerCommandHandler(
+	gate *OwnerGate,
+	guildAPI ownerGuildAPI,
+	voiceManager VoiceManager,
+	channelService ChannelService,
+	ttsProcessor TTSProcessor,
+	ttsManager TTSManager,
+	eventBus *events.Bus,
+	logger *log.Logger,
+) *OwnerCommandHandler {
+	return &OwnerCommandHandler{
+		gate:           gate,
+		guildAPI:       guildAPI,
+		voiceManager:   voiceManager,
+		channelService: channelService,
+		ttsProcessor:   ttsProcessor,
+		ttsManager:     ttsManager,
+		eventBus:       eventBus,
+		logger:         logger,
+	}
+}
+
+// SetLocalizer sets the localizer used to translate responses
+func (h *OwnerCommandHandler) SetLocalizer(localizer *Localizer) {
+	h.localizer = localizer
+}
+
+// SetAuditLog sets the audit log broadcasts are posted through
+func (h *OwnerCommandHandler) SetAuditLog(auditLog *AuditLog) {
+	h.auditLog = auditLog
+}
+
+// Definition returns the Discord slash command definition for the owner command. Only
+// administrators see it in guilds by default; owners can use it in a direct message.
+func (h *OwnerCommandHandler) Definition() *discordgo.ApplicationCommand {
+	hidden := int64(0)
+	allowDM := true
+	return &discordgo.ApplicationCommand{
+		Name:                     "darrot-owner",
+		Description:              "Look after every server the bot is in (Bot owner only)",
+		DefaultMemberPermissions: &hidden,
+		DMPermission:             &allowDM,
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "guilds",
+				Description: "List the servers the bot is in",
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "leave-guild",
+				Description: "Remove the bot from a server",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "guild-id",
+						Description: "ID of the server to leave",
+						Required:    true,
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "broadcast",
+				Description: "Post a notice to the audit channel of every server",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "text",
+						Description: "Notice to post",
+						Required:    true,
+						MaxLength:   MaxBroadcastLength,
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "reload",
+				Description: "Read server configurations and TTS credentials again",
+			},
+		},
+	}
+}
+
+// Handle processes the owner command interaction
+func (h *OwnerCommandHandler) Handle(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	userID := interactionUserID(i)
+	if err := h.ValidatePermissions(userID, i.GuildID); err != nil {
+		h.logger.Printf("Refused /darrot-owner from user %s in guild %s", userID, i.GuildID)
+		return h.respondError(s, i, h.localizer.T(i.GuildID, "common.permission_denied", err))
+	}
+
+	// Text command replies are seen by the whole channel, and the owner's answers are
+	// about every guild
+	if textcmd.IsTextCommand(i.Interaction) {
+		return h.respondError(s, i, h.localizer.T(i.GuildID, "owner.slash_only"))
+	}
+
+	subcommand, opts, ok := options.FromInteraction(i).Subcommand()
+	if !ok {
+		return h.respondError(s, i, h.localizer.T(i.GuildID, "common.no_subcommand"))
+	}
+
+	switch subcommand {
+	case "guilds":
+		return h.respondSuccess(s, i, h.describeGuilds(i.GuildID, stateGuilds(s)))
+	case "leave-guild":
+		return h.handleLeaveGuild(s, i, opts)
+	case "broadcast":
+		return h.handleBroadcast(s, i, userID, opts)
+	case "reload":
+		return h.respondSuccess(s, i, h.reload(i.GuildID, guildIDs(stateGuilds(s))))
+	default:
+		return h.respondError(s, i, h.localizer.T(i.GuildID, "common.invalid_subcommand"))
+	}
+}
+
+// handleLeaveGuild disconnects the bot from a guild's voice channel and leaves the guild
+func (h *OwnerCommandHandler) handleLeaveGuild(s *discordgo.Session, i *discordgo.InteractionCreate, opts options.Set) error {
+	targetID, err := opts.RequiredString("guild-id")
+	if err != nil {
+		return h.respondError(s, i, h.localizer.OptionError(i.GuildID, err))
+	}
+	targetID = strings.TrimSpace(targetID)
+
+	guild, err := s.State.Guild(targetID)
+	if err != nil {
+		return h.respondError(s, i, h.localizer.T(i.GuildID, "owner.unknown_guild", targetID))
+	}
+	name := guild.Name
+	if name == "" {
+		name = targetID
+	}
+
+	if err := h.leaveGuild(targetID); err != nil {
+		return h.respondError(s, i, h.localizer.T(i.GuildID, "owner.leave_failed", name, err))
+	}
+	h.logger.Printf("Left guild %s at the request of owner %s", targetID, interactionUserID(i))
+	return h.respondSuccess(s, i, h.localizer.T(i.GuildID, "owner.left", name, targetID))
+}
+
+// leaveGuild stops reading in a guild and removes the bot from it
+func (h *OwnerCommandHandler) leaveGuild(guildID string) error {
+	if connection, ok := h.voiceManager.GetConnection(guildID); ok {
+		if err := h.ttsProcessor.StopGuildProcessing(guildID); err != nil {
+			h.logger.Printf("Failed to stop TTS processing for guild %s: %v", guildID, err)
+		}
+		if err := h.voiceManager.LeaveChannel(guildID); err != nil {
+			h.logger.Printf("Failed to leave voice channel in guild %s: %v", guildID, err)
+		}
+		if err := h.channelService.RemovePairing(guildID, connection.ChannelID); err != nil {
+			h.logger.Printf("Failed to remove channel pairing in guild %s: %v", guildID, err)
+		}
+	}
+
+	if err := h.guildAPI.GuildLeave(guildID); err != nil {
+		return fmt.Errorf("failed to leave guild: %w", err)
+	}
+	return nil
+}
+
+// handleBroadcast posts a notice to the audit channel of every guild that has one
+func (h *OwnerCommandHandler) handleBroadcast(s *discordgo.Session, i *discordgo.InteractionCreate, userID string, opts options.Set) error {
+	text, err := opts.RequiredString("text")
+	if err != nil {
+		return h.respondError(s, i, h.localizer.OptionError(i.GuildID, err))
+	}
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return h.respondError(s, i, h.localizer.T(i.GuildID, "owner.broadcast_empty"))
+	}
+
+	guilds := guildIDs(stateGuilds(s))
+	posted := h.auditLog.Broadcast(guilds, userID, text)
+	h.logger.Printf("Owner %s broadcast a notice to %d of %d guilds", userID, posted, len(guilds))
+	return h.respondSuccess(s, i, h.localizer.T(i.GuildID, "owner.broadcast_sent", posted, len(guilds)))
+}
+
+// describeGuilds returns a user-facing list of guilds, largest first
+func (h *OwnerCommandHandler) describeGuilds(guildID string, guilds []*discordgo.Guild) string {
+	if len(guilds) == 0 {
+		return h.localizer.T(guildID, "owner.guilds_empty")
+	}
+
+	sorted := append([]*discordgo.Guild(nil), guilds...)
+	sort.SliceStable(sorted, func(a, b int) bool {
+		return sorted[a].MemberCount > sorted[b].MemberCount
+	})
+
+	var list strings.Builder
+	for idx, guild := range sorted {
+		line := h.localizer.T(guildID, "owner.guilds_entry", guild.Name, guild.ID, guild.MemberCount)
+		if h.voiceManager.IsConnected(guild.ID) {
+			line += h.localizer.T(guildID, "owner.guilds_in_voice")
+		}
+		if list.Len()+len(line)+1 > ownerGuildListLimit {
+			list.WriteString(h.localizer.T(guildID, "owner.guilds_more", len(sorted)-idx))
+			break
+		}
+		list.WriteString(line + "\n")
+	}
+	return h.localizer.T(guildID, "owner.guilds", len(guilds), strings.TrimRight(list.String(), "\n"))
+}
+
+// reload drops the cached configuration of every guild, so configurations edited on disk
+// take effect, and reads the TTS credentials again
+func (h *OwnerCommandHandler) reload(guildID string, guildIDs []string) string {
+	for _, id := range guildIDs {
+		h.eventBus.Publish(events.ConfigChanged{GuildID: id, ChangedAt: time.Now()})
+	}
+	lines := []string{h.localizer.T(guildID, "owner.reloaded_configs", len(guildIDs))}
+
+	reloader, ok := h.ttsManager.(credentialReloader)
+	if !ok {
+		return strings.Join(lines, "\n")
+	}
+	switch changed, err := reloader.ReloadCredentials(); {
+	case err != nil:
+		h.logger.Printf("Failed to reload TTS credentials: %v", err)
+		lines = append(lines, h.localizer.T(guildID, "owner.credentials_failed", err))
+	case changed:
+		lines = append(lines, h.localizer.T(guildID, "owner.credentials_reloaded"))
+	default:
+		lines = append(lines, h.localizer.T(guildID, "owner.credentials_unchanged"))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// stateGuilds returns the guilds the session's bot is in
+func stateGuilds(s *discordgo.Session) []*discordgo.Guild {
+	if s == nil || s.State == nil {
+		return nil
+	}
+	s.State.RLock()
+	defer s.State.RUnlock()
+	return append([]*discordgo.Guild(nil), s.State.Guilds...)
+}
+
+// guildIDs returns the IDs of guilds
+func guildIDs(guilds []*discordgo.Guild) []string {
+	ids := make([]string, len(guilds))
+	for idx, guild := range guilds {
+		ids[idx] = guild.ID
+	}
+	return ids
+}
+
+// ValidatePermissions validates that the user is one of the bot's owners
+func (h *OwnerCommandHandler) ValidatePermissions(userID, guildID string) error {
+	if !h.gate.IsOwner(userID) {
+		return fmt.Errorf("only the bot's owners can use this command")
+	}
+	return nil
+}
+
+// ValidateChannelAccess is not needed for owner commands but required by interface
+func (h *OwnerCommandHandler) ValidateChannelAccess(userID, channelID string) error {
+	return nil // Not applicable for owner commands
+}
+
+// Helper methods for response handling
+
+func (h *OwnerCommandHandler) respondSuccess(s *discordgo.Session, i *discordgo.InteractionCreate, message string) error {
+	return textcmd.Respond(s, i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: message,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
+
+func (h *OwnerCommandHandler) respondError(s *discordgo.Session, i *discordgo.InteractionCreate, message string) error {
+	return textcmd.Respond(s, i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: "❌ " + message,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
@@ -0,0 +1,154 @@
+package tts
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"testing"
+
+	"darrot/internal/events"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeOwnerGuildAPI records the guilds the bot left
+type fakeOwnerGuildAPI struct {
+	left []string
+	err  error
+}
+
+func (f *fakeOwnerGuildAPI) GuildLeave(guildID string, options ...discordgo.RequestOption) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.left = append(f.left, guildID)
+	return nil
+}
+
+// reloadingTTSManager is a TTS manager whose credentials can be reloaded
+type reloadingTTSManager struct {
+	*mockTTSManagerForRecovery
+	changed bool
+	err     error
+}
+
+func (m *reloadingTTSManager) ReloadCredentials() (bool, error) {
+	return m.changed, m.err
+}
+
+func newTestOwnerHandler(t *testing.T, ttsManager TTSManager) (*OwnerCommandHandler, *fakeOwnerGuildAPI, *mockVoiceManager, *events.Bus) {
+	t.Helper()
+
+	guildAPI := &fakeOwnerGuildAPI{}
+	voiceManager := newMockVoiceManager()
+	bus := events.New()
+	handler := NewOwnerCommandHandler(NewOwnerGate([]string{"owner1"}), guildAPI, voiceManager, newMockChannelService(),
+		&mockTTSProcessorForRecovery{}, ttsManager, bus, log.New(io.Discard, "", 0))
+	return handler, guildAPI, voiceManager, bus
+}
+
+func TestOwnerGate(t *testing.T) {
+	gate := NewOwnerGate([]string{"owner1", "owner2"})
+	assert.True(t, gate.Configured())
+	assert.True(t, gate.IsOwner("owner2"))
+	assert.False(t, gate.IsOwner("user1"))
+	assert.False(t, gate.IsOwner(""))
+
+	assert.False(t, NewOwnerGate(nil).Configured())
+	var nilGate *OwnerGate
+	assert.False(t, nilGate.Configured())
+	assert.False(t, nilGate.IsOwner("owner1"))
+}
+
+func TestOwnerCommandHandler_ValidatePermissions(t *testing.T) {
+	handler, _, _, _ := newTestOwnerHandler(t, newMockTTSManagerForRecovery())
+
+	assert.NoError(t, handler.ValidatePermissions("owner1", ""), "owners can use the command in direct messages")
+	assert.NoError(t, handler.ValidatePermissions("owner1", "guild123"))
+	assert.ErrorContains(t, handler.ValidatePermissions("admin", "guild123"), "owners", "guild administrators are not owners")
+
+	dm := &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{User: &discordgo.User{ID: "owner1"}}}
+	assert.Equal(t, "owner1", interactionUserID(dm))
+}
+
+func TestOwnerCommandHandler_Definition(t *testing.T) {
+	handler, _, _, _ := newTestOwnerHandler(t, newMockTTSManagerForRecovery())
+
+	definition := handler.Definition()
+	assert.Equal(t, "darrot-owner", definition.Name)
+	require.NotNil(t, definition.DefaultMemberPermissions)
+	assert.Zero(t, *definition.DefaultMemberPermissions, "guild members don't see the command by default")
+	require.NotNil(t, definition.DMPermission)
+	assert.True(t, *definition.DMPermission)
+
+	var subcommands []string
+	for _, option := range definition.Options {
+		subcommands = append(subcommands, option.Name)
+	}
+	assert.Equal(t, []string{"guilds", "leave-guild", "broadcast", "reload"}, subcommands)
+}
+
+func TestOwnerCommandHandler_DescribeGuilds(t *testing.T) {
+	handler, _, voiceManager, _ := newTestOwnerHandler(t, newMockTTSManagerForRecovery())
+	_, err := voiceManager.JoinChannel("guild2", "voice1")
+	require.NoError(t, err)
+
+	assert.Equal(t, "The bot is not in any server.", handler.describeGuilds("", nil))
+
+	description := handler.describeGuilds("", []*discordgo.Guild{
+		{ID: "guild1", Name: "Small", MemberCount: 3},
+		{ID: "guild2", Name: "Large", MemberCount: 120},
+	})
+	assert.Equal(t, "🌐 The bot is in 2 server(s):\n"+
+		"• **Large** (`guild2`) · 120 member(s) · 🔊 in a voice channel\n"+
+		"• **Small** (`guild1`) · 3 member(s)", description)
+
+	many := make([]*discordgo.Guild, 100)
+	for idx := range many {
+		many[idx] = &discordgo.Guild{ID: fmt.Sprintf("guild%03d", idx), Name: strings.Repeat("x", 30)}
+	}
+	description = handler.describeGuilds("", many)
+	assert.LessOrEqual(t, len(description), 2000)
+	assert.Contains(t, description, "more")
+}
+
+func TestOwnerCommandHandler_LeaveGuild(t *testing.T) {
+	handler, guildAPI, voiceManager, _ := newTestOwnerHandler(t, newMockTTSManagerForRecovery())
+	_, err := voiceManager.JoinChannel("guild1", "voice1")
+	require.NoError(t, err)
+
+	require.NoError(t, handler.leaveGuild("guild1"))
+	assert.Equal(t, []string{"guild1"}, guildAPI.left)
+	assert.False(t, voiceManager.IsConnected("guild1"), "the bot leaves the voice channel first")
+
+	guildAPI.err = errors.New("404 Not Found")
+	assert.ErrorContains(t, handler.leaveGuild("guild2"), "404")
+}
+
+func TestOwnerCommandHandler_Reload(t *testing.T) {
+	manager := &reloadingTTSManager{mockTTSManagerForRecovery: newMockTTSManagerForRecovery(), changed: true}
+	handler, _, _, bus := newTestOwnerHandler(t, manager)
+
+	var reloaded []string
+	defer events.Subscribe(bus, func(e events.ConfigChanged) {
+		assert.Zero(t, e.Version, "the cached configurations are dropped")
+		reloaded = append(reloaded, e.GuildID)
+	})()
+
+	assert.Equal(t, "🔄 Reloaded the configuration of 2 server(s).\n🔑 Reloaded the TTS credentials, which had changed.",
+		handler.reload("", []string{"guild1", "guild2"}))
+	assert.Equal(t, []string{"guild1", "guild2"}, reloaded)
+
+	manager.changed = false
+	assert.Contains(t, handler.reload("", nil), "have not changed")
+	manager.err = errors.New("secret not found")
+	assert.Contains(t, handler.reload("", nil), "secret not found")
+
+	// Managers without credentials only have configurations to reload
+	handler, _, _, _ = newTestOwnerHandler(t, newMockTTSManagerForRecovery())
+	assert.Equal(t, "🔄 Reloaded the configuration of 1 server(s).", handler.reload("", []string{"guild1"}))
+}
@@ -0,0 +1,131 @@
+package tts
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Pacing limits
+const (
+	MaxPauseMillis       = 2000 // Longest configurable pause between messages
+	MaxAuthorPauseMillis = 5000 // Longest configurable pause when the author changes
+)
+
+// PacingFor returns the pause a guild leaves between messages and the pause it leaves
+// when the next message is from someone else, 0 meaning none
+func PacingFor(config *GuildTTSConfig) (time.Duration, time.Duration) {
+	if config == nil {
+		return 0, 0
+	}
+	return time.Duration(max(config.PauseMillis, 0)) * time.Millisecond,
+		time.Duration(max(config.AuthorPauseMillis, 0)) * time.Millisecond
+}
+
+// PacingPolicy leaves short pauses between messages so playback does not sound
+// breathless, and a longer one when the author changes. Pauses are measured from the end
+// of the previous message, so synthesis time counts towards them, and are only left when
+// nothing else is waiting in the queue or the message's channel has slow mode on; a
+// backlog is read without them. A nil *PacingPolicy never pauses.
+type PacingPolicy struct {
+	session       *discordgo.Session
+	configService ConfigService
+	logger        *log.Logger
+	now           func() time.Time
+
+	mu   sync.Mutex
+	last map[string]spokenMessage // The last message spoken in each guild
+}
+
+// spokenMessage is who spoke last in a guild and when they finished
+type spokenMessage struct {
+	userID  string
+	endedAt time.Time
+}
+
+// NewPacingPolicy creates a pacing policy reading slow mode from the session's state
+func NewPacingPolicy(session *discordgo.Session, configService ConfigService, logger *log.Logger) *PacingPolicy {
+	return &PacingPolicy{
+		session:       session,
+		configService: configService,
+		logger:        logger,
+		now:           time.Now,
+		last:          make(map[string]spokenMessage),
+	}
+}
+
+// Wait pauses before a message is played while backlog other messages wait in its
+// guild's queue. It returns early when ctx is done.
+func (p *PacingPolicy) Wait(ctx context.Context, guildID string, message *QueuedMessage, backlog int) {
+	if p == nil {
+		return
+	}
+
+	pause := p.pause(guildID, message, backlog)
+	if pause <= 0 {
+		return
+	}
+
+	timer := time.NewTimer(pause)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+}
+
+// Finished records that a message finished playing in its guild
+func (p *PacingPolicy) Finished(guildID string, message *QueuedMessage) {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.last[guildID] = spokenMessage{userID: message.UserID, endedAt: p.now()}
+}
+
+// Forget drops what the policy knows about a guild, so its next message plays right away
+func (p *PacingPolicy) Forget(guildID string) {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.last, guildID)
+}
+
+// pause returns how much longer a message waits before it is played
+func (p *PacingPolicy) pause(guildID string, message *QueuedMessage, backlog int) time.Duration {
+	p.mu.Lock()
+	last, spoken := p.last[guildID]
+	p.mu.Unlock()
+	if !spoken || (backlog > 0 && !p.slowMode(message.ChannelID)) {
+		return 0
+	}
+
+	config, err := p.configService.GetGuildConfig(guildID)
+	if err != nil {
+		p.logger.Printf("Error getting guild config for guild %s: %v", guildID, err)
+		return 0
+	}
+
+	pause, authorPause := PacingFor(config)
+	if message.UserID != last.userID {
+		pause = max(pause, authorPause)
+	}
+	return pause - p.now().Sub(last.endedAt)
+}
+
+// slowMode reports whether a text channel has slow mode on
+func (p *PacingPolicy) slowMode(channelID string) bool {
+	if p.session == nil || p.session.State == nil || channelID == "" {
+		return false
+	}
+	channel, err := p.session.State.Channel(channelID)
+	return err == nil && channel.RateLimitPerUser > 0
+}
@@ -0,0 +1,96 @@
+package tts
+
+import (
+	"context"
+	"io"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createTestPacingPolicy(t *testing.T, pause, authorPause int) (*PacingPolicy, *time.Time) {
+	t.Helper()
+
+	configService := createTestExportConfigService(t)
+	config := DefaultGuildTTSConfig("guild1")
+	config.PauseMillis = pause
+	config.AuthorPauseMillis = authorPause
+	require.NoError(t, configService.SetGuildConfig("guild1", &config))
+
+	session := &discordgo.Session{State: discordgo.NewState()}
+	require.NoError(t, session.State.GuildAdd(&discordgo.Guild{ID: "guild1", Channels: []*discordgo.Channel{
+		{ID: "text1", GuildID: "guild1"},
+		{ID: "slow1", GuildID: "guild1", RateLimitPerUser: 30},
+	}}))
+
+	policy := NewPacingPolicy(session, configService, log.New(io.Discard, "", 0))
+	now := time.Now()
+	policy.now = func() time.Time { return now }
+	return policy, &now
+}
+
+func pacedMessage(userID, channelID string) *QueuedMessage {
+	return &QueuedMessage{ID: "msg-" + userID, GuildID: "guild1", ChannelID: channelID, UserID: userID}
+}
+
+func TestPacingFor(t *testing.T) {
+	pause, authorPause := PacingFor(nil)
+	assert.Zero(t, pause)
+	assert.Zero(t, authorPause)
+
+	pause, authorPause = PacingFor(&GuildTTSConfig{PauseMillis: 300, AuthorPauseMillis: 800})
+	assert.Equal(t, 300*time.Millisecond, pause)
+	assert.Equal(t, 800*time.Millisecond, authorPause)
+}
+
+func TestPacingPolicy_Pauses(t *testing.T) {
+	policy, now := createTestPacingPolicy(t, 300, 800)
+
+	assert.Zero(t, policy.pause("guild1", pacedMessage("alice", "text1"), 0), "the first message plays right away")
+
+	policy.Finished("guild1", pacedMessage("alice", "text1"))
+	assert.Equal(t, 300*time.Millisecond, policy.pause("guild1", pacedMessage("alice", "text1"), 0))
+	assert.Equal(t, 800*time.Millisecond, policy.pause("guild1", pacedMessage("bob", "text1"), 0), "the author changed")
+
+	*now = now.Add(200 * time.Millisecond)
+	assert.Equal(t, 100*time.Millisecond, policy.pause("guild1", pacedMessage("alice", "text1"), 0), "time since the last message counts")
+	*now = now.Add(time.Second)
+	assert.LessOrEqual(t, policy.pause("guild1", pacedMessage("bob", "text1"), 0), time.Duration(0))
+
+	policy.Forget("guild1")
+	assert.Zero(t, policy.pause("guild1", pacedMessage("alice", "text1"), 0))
+}
+
+func TestPacingPolicy_Backlog(t *testing.T) {
+	policy, _ := createTestPacingPolicy(t, 300, 800)
+	policy.Finished("guild1", pacedMessage("alice", "text1"))
+
+	assert.Zero(t, policy.pause("guild1", pacedMessage("bob", "text1"), 2), "a backlog is read without pauses")
+	assert.Equal(t, 800*time.Millisecond, policy.pause("guild1", pacedMessage("bob", "slow1"), 2), "slow mode channels keep their pauses")
+}
+
+func TestPacingPolicy_Off(t *testing.T) {
+	policy, _ := createTestPacingPolicy(t, 0, 0)
+	policy.Finished("guild1", pacedMessage("alice", "text1"))
+	assert.Zero(t, policy.pause("guild1", pacedMessage("bob", "text1"), 0))
+
+	var nilPolicy *PacingPolicy
+	nilPolicy.Finished("guild1", pacedMessage("alice", "text1"))
+	nilPolicy.Wait(context.Background(), "guild1", pacedMessage("alice", "text1"), 0)
+}
+
+func TestPacingPolicy_WaitStopsWithContext(t *testing.T) {
+	policy, _ := createTestPacingPolicy(t, MaxPauseMillis, MaxAuthorPauseMillis)
+	policy.Finished("guild1", pacedMessage("alice", "text1"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	started := time.Now()
+	policy.Wait(ctx, "guild1", pacedMessage("bob", "text1"), 0)
+	assert.Less(t, time.Since(started), time.Second, "skipping the message ends its pause")
+}
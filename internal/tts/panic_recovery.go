@@ -0,0 +1,55 @@
+package tts
+
+import (
+	"fmt"
+	"log"
+	"runtime/debug"
+)
+
+// MetricPanicsRecovered counts panics caught in handlers and background goroutines
+const MetricPanicsRecovered = "darrot_panics_recovered_total"
+
+// describePanicMetrics registers help text for the panic counter
+func describePanicMetrics(metrics *Metrics) {
+	if metrics != nil {
+		metrics.Describe(MetricPanicsRecovered, MetricTypeCounter, "Panics recovered in interaction handlers and background goroutines, by component")
+	}
+}
+
+// Recover stops a panic in the calling goroutine from crashing the process. It logs the
+// stack trace with the guild the goroutine was working for and counts the panic under
+// component. It only works when deferred directly:
+//
+//	defer Recover(metrics, "voice receiver", guildID)
+//
+// The metrics registry and the guild ID may be empty.
+func Recover(metrics *Metrics, component, guildID string) {
+	if r := recover(); r != nil {
+		reportPanic(metrics, component, guildID, r)
+	}
+}
+
+// CatchPanic calls fn and turns a panic in it into an error, after reporting it like
+// Recover does. Callers waiting on fn's result therefore always get one.
+func CatchPanic(metrics *Metrics, component, guildID string, fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			reportPanic(metrics, component, guildID, r)
+			err = fmt.Errorf("%s panicked: %v", component, r)
+		}
+	}()
+	return fn()
+}
+
+// reportPanic logs a recovered panic with the stack trace of the panicking goroutine
+func reportPanic(metrics *Metrics, component, guildID string, r any) {
+	where := component
+	if guildID != "" {
+		where = fmt.Sprintf("%s for guild %s", component, guildID)
+	}
+	log.Printf("Recovered from panic in %s: %v\n%s", where, r, debug.Stack())
+
+	if metrics != nil {
+		metrics.IncCounter(MetricPanicsRecovered, Labels{"component": component})
+	}
+}
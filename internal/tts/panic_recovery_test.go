@@ -0,0 +1,55 @@
+package tts
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecover(t *testing.T) {
+	metrics := NewMetrics()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer Recover(metrics, "voice receiver", "guild1")
+		panic("decoder exploded")
+	}()
+	<-done
+
+	assert.Equal(t, 1.0, metrics.Value(MetricPanicsRecovered, Labels{"component": "voice receiver"}))
+}
+
+func TestRecover_NoPanic(t *testing.T) {
+	metrics := NewMetrics()
+
+	func() {
+		defer Recover(metrics, "voice receiver", "guild1")
+	}()
+
+	assert.Zero(t, metrics.Value(MetricPanicsRecovered, Labels{"component": "voice receiver"}))
+}
+
+func TestRecover_WithoutMetrics(t *testing.T) {
+	assert.NotPanics(t, func() {
+		defer Recover(nil, "tts dispatcher", "")
+		panic("no registry")
+	})
+}
+
+func TestCatchPanic(t *testing.T) {
+	metrics := NewMetrics()
+
+	err := CatchPanic(metrics, "voice playback", "guild1", func() error {
+		panic("encoder exploded")
+	})
+	assert.EqualError(t, err, "voice playback panicked: encoder exploded")
+	assert.Equal(t, 1.0, metrics.Value(MetricPanicsRecovered, Labels{"component": "voice playback"}))
+
+	// Errors and successes pass through unchanged
+	failure := errors.New("connection closed")
+	assert.Same(t, failure, CatchPanic(metrics, "voice playback", "guild1", func() error { return failure }))
+	assert.NoError(t, CatchPanic(metrics, "voice playback", "guild1", func() error { return nil }))
+	assert.Equal(t, 1.0, metrics.Value(MetricPanicsRecovered, Labels{"component": "voice playback"}))
+}
@@ -7,6 +7,8 @@ import (
 	"testing"
 	"time"
 
+	"darrot/internal/config"
+
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -92,6 +94,41 @@ func BenchmarkTTSConversion(b *testing.B) {
 	}
 }
 
+// BenchmarkPerMessageConfigReads benchmarks the guild configuration reads every message
+// makes, from storage and from the config service's cache
+func BenchmarkPerMessageConfigReads(b *testing.B) {
+	storage, err := NewStorageService(b.TempDir())
+	if err != nil {
+		b.Fatalf("Failed to create storage: %v", err)
+	}
+	guildConfig := DefaultGuildTTSConfig("benchmark-guild")
+	guildConfig.IgnorePrefixes = []string{"!", "?"}
+	if err := storage.SaveGuildConfig(guildConfig); err != nil {
+		b.Fatalf("Failed to save guild config: %v", err)
+	}
+
+	b.Run("Storage", func(b *testing.B) {
+		permissions := NewPermissionService(NewMockDiscordSession(), storage, nil)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if _, err := permissions.CanBeRead("bench-user", "benchmark-guild"); err != nil {
+				b.Fatalf("CanBeRead failed: %v", err)
+			}
+		}
+	})
+
+	b.Run("ConfigCache", func(b *testing.B) {
+		permissions := NewPermissionService(NewMockDiscordSession(), storage, nil)
+		permissions.SetConfigService(NewConfigService(storage, config.TTSConfig{DefaultVoice: DefaultVoice, DefaultSpeed: 1.0, DefaultVolume: 1.0, MaxQueueSize: 10}))
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if _, err := permissions.CanBeRead("bench-user", "benchmark-guild"); err != nil {
+				b.Fatalf("CanBeRead failed: %v", err)
+			}
+		}
+	})
+}
+
 // BenchmarkConcurrentOperations benchmarks concurrent TTS operations
 func BenchmarkConcurrentOperations(b *testing.B) {
 	_ = runtime.NumCPU() // Available for future use
@@ -3,6 +3,9 @@ package tts
 import (
 	"fmt"
 	"log"
+	"time"
+
+	"darrot/internal/events"
 
 	"github.com/bwmarrin/discordgo"
 )
@@ -17,9 +20,11 @@ type DiscordSession interface {
 
 // PermissionServiceImpl implements the PermissionService interface
 type PermissionServiceImpl struct {
-	session DiscordSession
-	storage *StorageService
-	logger  *log.Logger
+	session       DiscordSession
+	storage       *StorageService
+	configService ConfigService // Cached guild configurations; storage is read when unset
+	eventBus      *events.Bus
+	logger        *log.Logger
 }
 
 // NewPermissionService creates a new permission service instance
@@ -31,6 +36,25 @@ func NewPermissionService(session DiscordSession, storage *StorageService, logge
 	}
 }
 
+// SetConfigService reads guild configurations through the config service's cache instead
+// of from storage on every check
+func (p *PermissionServiceImpl) SetConfigService(configService ConfigService) {
+	p.configService = configService
+}
+
+// SetEventBus publishes the required roles saved without a config service
+func (p *PermissionServiceImpl) SetEventBus(bus *events.Bus) {
+	p.eventBus = bus
+}
+
+// guildConfig returns a guild's configuration
+func (p *PermissionServiceImpl) guildConfig(guildID string) (*GuildTTSConfig, error) {
+	if p.configService != nil {
+		return p.configService.GetGuildConfig(guildID)
+	}
+	return p.storage.LoadGuildConfig(guildID)
+}
+
 // CanInviteBot checks if a user has permission to invite the bot to voice channels
 // Requirements: 7.1, 7.2, 7.3, 7.4, 7.5
 func (p *PermissionServiceImpl) CanInviteBot(userID, guildID string) (bool, error) {
@@ -48,7 +72,7 @@ func (p *PermissionServiceImpl) CanInviteBot(userID, guildID string) (bool, erro
 	}
 
 	// Get guild configuration to check required roles
-	guildConfig, err := p.storage.LoadGuildConfig(guildID)
+	guildConfig, err := p.guildConfig(guildID)
 	if err != nil {
 		return false, fmt.Errorf("failed to load guild config: %w", err)
 	}
@@ -88,6 +112,31 @@ func (p *PermissionServiceImpl) CanControlBot(userID, guildID string) (bool, err
 	return p.CanInviteBot(userID, guildID)
 }
 
+// CanBeRead checks if a user's messages may be read aloud. When the guild has speaker
+// roles configured only members holding one of them are read, on top of opting in.
+func (p *PermissionServiceImpl) CanBeRead(userID, guildID string) (bool, error) {
+	if userID == "" || guildID == "" {
+		return false, fmt.Errorf("userID and guildID cannot be empty")
+	}
+
+	guildConfig, err := p.guildConfig(guildID)
+	if err != nil {
+		return false, fmt.Errorf("failed to load guild config: %w", err)
+	}
+
+	// If no speaker roles are configured, every opted-in user is read
+	if len(guildConfig.SpeakerRoles) == 0 {
+		return true, nil
+	}
+
+	hasSpeakerRole, err := p.hasAnyRole(userID, guildID, guildConfig.SpeakerRoles)
+	if err != nil {
+		return false, fmt.Errorf("failed to check user roles: %w", err)
+	}
+
+	return hasSpeakerRole, nil
+}
+
 // HasChannelAccess validates if a user has access to a specific channel
 // Requirements: 8.1, 8.2, 8.3, 8.4, 8.5
 func (p *PermissionServiceImpl) HasChannelAccess(userID, channelID string) (bool, error) {
@@ -103,9 +152,9 @@ func (p *PermissionServiceImpl) HasChannelAccess(userID, channelID string) (bool
 
 	// Check if user has access to the channel based on channel type
 	switch channel.Type {
-	case discordgo.ChannelTypeGuildVoice:
+	case discordgo.ChannelTypeGuildVoice, discordgo.ChannelTypeGuildStageVoice:
 		return p.hasVoiceChannelAccess(userID, channel)
-	case discordgo.ChannelTypeGuildText:
+	case discordgo.ChannelTypeGuildText, discordgo.ChannelTypeGuildNews:
 		return p.hasTextChannelAccess(userID, channel)
 	default:
 		return false, fmt.Errorf("unsupported channel type: %v", channel.Type)
@@ -126,18 +175,25 @@ func (p *PermissionServiceImpl) SetRequiredRoles(guildID string, roleIDs []strin
 		}
 	}
 
-	// Load current guild configuration
-	guildConfig, err := p.storage.LoadGuildConfig(guildID)
-	if err != nil {
-		return fmt.Errorf("failed to load guild config: %w", err)
-	}
+	if p.configService != nil {
+		if err := p.configService.SetRequiredRoles(guildID, roleIDs); err != nil {
+			return fmt.Errorf("failed to save guild config: %w", err)
+		}
+	} else {
+		// Load current guild configuration
+		guildConfig, err := p.guildConfig(guildID)
+		if err != nil {
+			return fmt.Errorf("failed to load guild config: %w", err)
+		}
 
-	// Update required roles
-	guildConfig.RequiredRoles = roleIDs
+		// Update required roles
+		guildConfig.RequiredRoles = roleIDs
 
-	// Save updated configuration
-	if err := p.storage.SaveGuildConfig(*guildConfig); err != nil {
-		return fmt.Errorf("failed to save guild config: %w", err)
+		// Save updated configuration; a config service sharing the bus drops its copy
+		if err := p.storage.SaveGuildConfig(*guildConfig); err != nil {
+			return fmt.Errorf("failed to save guild config: %w", err)
+		}
+		p.eventBus.Publish(events.ConfigChanged{GuildID: guildID, ChangedAt: time.Now()})
 	}
 
 	p.logger.Printf("Updated required roles for guild %s: %v", guildID, roleIDs)
@@ -151,7 +207,7 @@ func (p *PermissionServiceImpl) GetRequiredRoles(guildID string) ([]string, erro
 		return nil, fmt.Errorf("guildID cannot be empty")
 	}
 
-	guildConfig, err := p.storage.LoadGuildConfig(guildID)
+	guildConfig, err := p.guildConfig(guildID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load guild config: %w", err)
 	}
@@ -5,6 +5,8 @@ import (
 	"os"
 	"testing"
 
+	"darrot/internal/config"
+
 	"github.com/bwmarrin/discordgo"
 )
 
@@ -515,6 +517,75 @@ func TestCanControlBot(t *testing.T) {
 	})
 }
 
+// Test CanBeRead functionality
+func TestCanBeRead(t *testing.T) {
+	permService, mockSession, storage := setupPermissionTest(t)
+
+	guildID := "test-guild-123"
+	speakerID := "speaker-user-456"
+	userID := "test-user-789"
+	speakerRoleID := "speaker-role-111"
+
+	mockSession.AddGuild(&discordgo.Guild{
+		ID:    guildID,
+		Roles: []*discordgo.Role{{ID: speakerRoleID, Name: "Speaker"}},
+	})
+	mockSession.AddMember(guildID, &discordgo.Member{
+		User:  &discordgo.User{ID: speakerID},
+		Roles: []string{speakerRoleID},
+	})
+	mockSession.AddMember(guildID, &discordgo.Member{
+		User:  &discordgo.User{ID: userID},
+		Roles: []string{},
+	})
+
+	t.Run("No speaker roles configured - everyone is read", func(t *testing.T) {
+		canBeRead, err := permService.CanBeRead(userID, guildID)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !canBeRead {
+			t.Error("Expected user to be read when no speaker roles are configured")
+		}
+	})
+
+	t.Run("Speaker roles configured - only members with a role are read", func(t *testing.T) {
+		guildConfig, err := storage.LoadGuildConfig(guildID)
+		if err != nil {
+			t.Fatalf("Failed to load guild config: %v", err)
+		}
+		guildConfig.SpeakerRoles = []string{speakerRoleID}
+		if err := storage.SaveGuildConfig(*guildConfig); err != nil {
+			t.Fatalf("Failed to save guild config: %v", err)
+		}
+
+		canBeRead, err := permService.CanBeRead(speakerID, guildID)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !canBeRead {
+			t.Error("Expected member with speaker role to be read")
+		}
+
+		canBeRead, err = permService.CanBeRead(userID, guildID)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if canBeRead {
+			t.Error("Expected member without speaker role not to be read")
+		}
+	})
+
+	t.Run("Empty parameters should return error", func(t *testing.T) {
+		if _, err := permService.CanBeRead("", guildID); err == nil {
+			t.Error("Expected error for empty userID")
+		}
+		if _, err := permService.CanBeRead(userID, ""); err == nil {
+			t.Error("Expected error for empty guildID")
+		}
+	})
+}
+
 // Test error handling
 func TestPermissionServiceErrorHandling(t *testing.T) {
 	permService, mockSession, _ := setupPermissionTest(t)
@@ -540,3 +611,42 @@ func TestPermissionServiceErrorHandling(t *testing.T) {
 		mockSession.SetError(false, "")
 	})
 }
+
+// Test reading guild configurations through the config service's cache
+func TestPermissionServiceWithConfigService(t *testing.T) {
+	permService, mockSession, storage := setupPermissionTest(t)
+	configService := NewConfigService(storage, config.TTSConfig{DefaultVoice: DefaultVoice, DefaultSpeed: 1.0, DefaultVolume: 1.0, MaxQueueSize: 10})
+	permService.SetConfigService(configService)
+
+	guildID := "test-guild-123"
+	roleID := "role-111"
+	mockSession.AddGuild(&discordgo.Guild{ID: guildID, Roles: []*discordgo.Role{{ID: roleID, Name: "DJ"}}})
+
+	// Roles set through the permission service are saved by the config service
+	if err := permService.SetRequiredRoles(guildID, []string{roleID}); err != nil {
+		t.Fatalf("SetRequiredRoles() error = %v", err)
+	}
+	cached, _ := configService.GetRequiredRoles(guildID)
+	if len(cached) != 1 || cached[0] != roleID {
+		t.Errorf("Expected the config service to have the new roles, got %v", cached)
+	}
+	stored, err := storage.LoadGuildConfig(guildID)
+	if err != nil || len(stored.RequiredRoles) != 1 {
+		t.Errorf("Expected the new roles in storage, got %v (%v)", stored, err)
+	}
+
+	// Speaker roles set through the config service apply to the next check
+	guildConfig, _ := configService.GetGuildConfig(guildID)
+	guildConfig.SpeakerRoles = []string{roleID}
+	if err := configService.SetGuildConfig(guildID, guildConfig); err != nil {
+		t.Fatalf("SetGuildConfig() error = %v", err)
+	}
+	mockSession.AddMember(guildID, &discordgo.Member{User: &discordgo.User{ID: "user1"}})
+	canBeRead, err := permService.CanBeRead("user1", guildID)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if canBeRead {
+		t.Error("Expected member without the speaker role not to be read")
+	}
+}
@@ -0,0 +1,53 @@
+package tts
+
+import "log"
+
+// playbackControl pauses, resumes and skips a guild's playback. /darrot-control, voice
+// commands and the queue panel buttons all go through it, so they behave the same.
+type playbackControl struct {
+	voiceManager VoiceManager
+	messageQueue MessageQueue
+	statsService StatsService // Skips are not recorded when nil
+	mutePauser   *MutePauser
+	logger       *log.Logger
+}
+
+// pause pauses a guild's playback. It reports false when playback already was paused.
+func (c playbackControl) pause(guildID string) (bool, error) {
+	if c.voiceManager.IsPaused(guildID) {
+		return false, nil
+	}
+	return true, c.voiceManager.PausePlayback(guildID)
+}
+
+// resume resumes a guild's paused playback and reports whether it was paused. While the
+// bot is server-muted nobody would hear it, so playback resumes once the bot is unmuted
+// instead, reported as deferred.
+func (c playbackControl) resume(guildID string) (resumed, deferred bool, err error) {
+	if !c.voiceManager.IsPaused(guildID) {
+		return false, false, nil
+	}
+	if c.mutePauser.ResumeWhenUnmuted(guildID) {
+		return true, true, nil
+	}
+	return true, false, c.voiceManager.ResumePlayback(guildID)
+}
+
+// skip stops the message being spoken and drops the next queued one, recording the skip.
+// It returns the dropped message, nil when the queue was empty.
+func (c playbackControl) skip(guildID string) (*QueuedMessage, error) {
+	if err := c.voiceManager.SkipCurrentMessage(guildID); err != nil {
+		c.logger.Printf("Warning: Failed to skip current message: %v", err)
+	}
+
+	skipped, err := c.messageQueue.SkipNext(guildID)
+	if err != nil || skipped == nil {
+		return nil, err
+	}
+	if c.statsService != nil {
+		if err := c.statsService.RecordSkip(guildID); err != nil {
+			c.logger.Printf("Warning: Failed to record skip for guild %s: %v", guildID, err)
+		}
+	}
+	return skipped, nil
+}
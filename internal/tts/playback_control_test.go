@@ -0,0 +1,67 @@
+package tts
+
+import (
+	"io"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlaybackControl_PauseAndResume(t *testing.T) {
+	pauser, voiceManager := createTestMutePauser(t)
+	control := playbackControl{voiceManager, NewMessageQueue(), nil, pauser, log.New(io.Discard, "", 0)}
+
+	resumed, _, err := control.resume("guild1")
+	require.NoError(t, err)
+	assert.False(t, resumed, "playback was not paused")
+
+	paused, err := control.pause("guild1")
+	require.NoError(t, err)
+	assert.True(t, paused)
+	paused, err = control.pause("guild1")
+	require.NoError(t, err)
+	assert.False(t, paused, "playback already was paused")
+
+	resumed, deferred, err := control.resume("guild1")
+	require.NoError(t, err)
+	assert.True(t, resumed)
+	assert.False(t, deferred)
+	assert.False(t, voiceManager.IsPaused("guild1"))
+}
+
+func TestPlaybackControl_ResumeWhileMuted(t *testing.T) {
+	pauser, voiceManager := createTestMutePauser(t)
+	control := playbackControl{voiceManager, NewMessageQueue(), nil, pauser, log.New(io.Discard, "", 0)}
+
+	pauser.update(botVoiceUpdate(true, false), "bot")
+	_, deferred, err := control.resume("guild1")
+	require.NoError(t, err)
+	assert.True(t, deferred)
+	assert.True(t, voiceManager.IsPaused("guild1"), "nobody would hear it while muted")
+
+	pauser.update(botVoiceUpdate(false, false), "bot")
+	assert.False(t, voiceManager.IsPaused("guild1"))
+}
+
+func TestPlaybackControl_Skip(t *testing.T) {
+	voiceManager := newMockVoiceManager()
+	messageQueue := NewMessageQueue()
+	control := playbackControl{voiceManager, messageQueue, nil, nil, log.New(io.Discard, "", 0)}
+
+	skipped, err := control.skip("guild1")
+	require.NoError(t, err)
+	assert.Nil(t, skipped)
+
+	for _, content := range []string{"first", "second"} {
+		require.NoError(t, messageQueue.Enqueue(&QueuedMessage{GuildID: "guild1", Content: content, Timestamp: time.Now()}))
+	}
+	skipped, err = control.skip("guild1")
+	require.NoError(t, err)
+	require.NotNil(t, skipped)
+	assert.Equal(t, "first", skipped.Content)
+	assert.Equal(t, 1, messageQueue.Size("guild1"))
+	assert.Contains(t, voiceManager.getCallLog(), "SkipCurrentMessage")
+}
@@ -0,0 +1,188 @@
+package tts
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+var (
+	// codeBlockRegex matches fenced code blocks, capturing the optional language tag and the code
+	codeBlockRegex = regexp.MustCompile("(?s)```([\\w+#.-]*)\n?(.*?)```")
+
+	// linkRegex matches http(s) URLs, including Discord's <url> form that suppresses embeds
+	linkRegex = regexp.MustCompile(`<?https?://[^\s<>]+>?`)
+
+	// spoilerRegex matches spoiler-tagged text, capturing the hidden text
+	spoilerRegex = regexp.MustCompile(`(?s)\|\|(.+?)\|\|`)
+
+	spaceRegex = regexp.MustCompile(`[ \t]{2,}`)
+)
+
+// ContentModes holds how a guild wants links, code blocks, spoilers and emoji read
+type ContentModes struct {
+	Links      LinkMode
+	CodeBlocks CodeBlockMode
+	Spoilers   SpoilerMode
+	MaxEmoji   int  // Emoji read per message before the rest are collapsed
+	SkipEmoji  bool // Leave emoji out instead of reading them, for guilds that turn emoji reading off
+}
+
+// ContentModesFor returns the content modes of a guild configuration, filling in
+// defaults for unset modes
+func ContentModesFor(config *GuildTTSConfig) ContentModes {
+	modes := ContentModes{Links: LinkModeDomain, CodeBlocks: CodeBlockModeSummary, Spoilers: SpoilerModeSummary, MaxEmoji: DefaultMaxSpokenEmoji}
+	if config == nil {
+		return modes
+	}
+	if config.LinkMode != "" {
+		modes.Links = config.LinkMode
+	}
+	if config.CodeBlockMode != "" {
+		modes.CodeBlocks = config.CodeBlockMode
+	}
+	if config.SpoilerMode != "" {
+		modes.Spoilers = config.SpoilerMode
+	}
+	if config.MaxSpokenEmoji > 0 {
+		modes.MaxEmoji = config.MaxSpokenEmoji
+	}
+	return modes
+}
+
+// applyContentModes rewrites spoilers, code blocks, links and emoji in message content
+// for speech. Spoilers are handled first so links and code hidden in them are never
+// read unless the guild reads spoilers, then code blocks so URLs inside code follow
+// the code block mode.
+func applyContentModes(content string, modes ContentModes) string {
+	content = rewriteSpoilers(content, modes.Spoilers)
+	content = rewriteCodeBlocks(content, modes.CodeBlocks)
+	content = rewriteLinks(content, modes.Links)
+	if modes.SkipEmoji {
+		content = dropEmoji(content)
+	} else {
+		content = speakEmoji(content, modes.MaxEmoji)
+	}
+
+	// Removed links and code can leave doubled spaces behind
+	return strings.TrimSpace(spaceRegex.ReplaceAllString(content, " "))
+}
+
+// rewriteSpoilers replaces spoiler-tagged text according to mode. Code often contains
+// "||", so spoilers are only looked for outside code blocks.
+func rewriteSpoilers(content string, mode SpoilerMode) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range codeBlockRegex.FindAllStringIndex(content, -1) {
+		b.WriteString(replaceSpoilers(content[last:loc[0]], mode))
+		b.WriteString(content[loc[0]:loc[1]])
+		last = loc[1]
+	}
+	b.WriteString(replaceSpoilers(content[last:], mode))
+	return b.String()
+}
+
+// replaceSpoilers replaces the spoilers in text that contains no code blocks
+func replaceSpoilers(text string, mode SpoilerMode) string {
+	return spoilerRegex.ReplaceAllStringFunc(text, func(spoiler string) string {
+		switch mode {
+		case SpoilerModeSkip:
+			return ""
+		case SpoilerModeFull:
+			return spoilerRegex.FindStringSubmatch(spoiler)[1]
+		default:
+			return "spoiler omitted"
+		}
+	})
+}
+
+// rewriteCodeBlocks replaces fenced code blocks according to mode
+func rewriteCodeBlocks(content string, mode CodeBlockMode) string {
+	return codeBlockRegex.ReplaceAllStringFunc(content, func(block string) string {
+		code := strings.Trim(codeBlockRegex.FindStringSubmatch(block)[2], "\n")
+
+		switch mode {
+		case CodeBlockModeSkip:
+			return ""
+		case CodeBlockModeFull:
+			return code
+		default:
+			lines := strings.Count(code, "\n") + 1
+			if lines == 1 {
+				return "a code snippet, 1 line"
+			}
+			return fmt.Sprintf("a code snippet, %d lines", lines)
+		}
+	})
+}
+
+// rewriteLinks replaces URLs according to mode
+func rewriteLinks(content string, mode LinkMode) string {
+	return linkRegex.ReplaceAllStringFunc(content, func(match string) string {
+		link := strings.TrimSuffix(strings.TrimPrefix(match, "<"), ">")
+
+		// Sentence punctuation right after a link is not part of it
+		trimmed := strings.TrimRight(link, ".,!?;:)'\"")
+		trailing := link[len(trimmed):]
+		link = trimmed
+
+		switch mode {
+		case LinkModeSkip:
+			return trailing
+		case LinkModeFull:
+			return link + trailing
+		default:
+			parsed, err := url.Parse(link)
+			if err != nil || parsed.Hostname() == "" {
+				return "a link" + trailing
+			}
+			return "a link to " + strings.TrimPrefix(parsed.Hostname(), "www.") + trailing
+		}
+	})
+}
+
+// attachmentKinds names each kind of attachment in the singular and plural, in the order
+// they are described
+var attachmentKinds = []struct {
+	prefix   string // Content type prefix; empty matches everything
+	singular string
+	plural   string
+}{
+	{"image/", "an image", "images"},
+	{"video/", "a video", "videos"},
+	{"audio/", "an audio clip", "audio clips"},
+	{"", "a file", "files"},
+}
+
+// describeAttachments describes a message's attachments for speech, such as "an image"
+// or "2 images and a file"
+func describeAttachments(attachments []*discordgo.MessageAttachment) string {
+	counts := make([]int, len(attachmentKinds))
+	for _, attachment := range attachments {
+		for i, kind := range attachmentKinds {
+			if strings.HasPrefix(attachment.ContentType, kind.prefix) {
+				counts[i]++
+				break
+			}
+		}
+	}
+
+	var parts []string
+	for i, kind := range attachmentKinds {
+		switch counts[i] {
+		case 0:
+		case 1:
+			parts = append(parts, kind.singular)
+		default:
+			parts = append(parts, fmt.Sprintf("%d %s", counts[i], kind.plural))
+		}
+	}
+
+	if len(parts) <= 1 {
+		return strings.Join(parts, "")
+	}
+	return strings.Join(parts[:len(parts)-1], ", ") + " and " + parts[len(parts)-1]
+}
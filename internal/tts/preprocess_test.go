@@ -0,0 +1,161 @@
+package tts
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContentModesFor(t *testing.T) {
+	defaults := ContentModes{Links: LinkModeDomain, CodeBlocks: CodeBlockModeSummary, Spoilers: SpoilerModeSummary, MaxEmoji: DefaultMaxSpokenEmoji}
+	assert.Equal(t, defaults, ContentModesFor(nil))
+	assert.Equal(t, defaults, ContentModesFor(&GuildTTSConfig{}))
+
+	config := &GuildTTSConfig{LinkMode: LinkModeSkip, CodeBlockMode: CodeBlockModeFull, SpoilerMode: SpoilerModeSkip, MaxSpokenEmoji: 2}
+	assert.Equal(t, ContentModes{Links: LinkModeSkip, CodeBlocks: CodeBlockModeFull, Spoilers: SpoilerModeSkip, MaxEmoji: 2}, ContentModesFor(config))
+}
+
+func TestApplyContentModes_Links(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		mode     LinkMode
+		expected string
+	}{
+		{"domain", "see https://github.com/mmannerm/darrot for details", LinkModeDomain, "see a link to github.com for details"},
+		{"domain strips www", "https://www.example.com/page?q=1", LinkModeDomain, "a link to example.com"},
+		{"domain keeps subdomain", "http://docs.example.org", LinkModeDomain, "a link to docs.example.org"},
+		{"embed suppressed form", "look <https://example.com/x> here", LinkModeDomain, "look a link to example.com here"},
+		{"trailing punctuation", "Have you seen https://example.com/a?", LinkModeDomain, "Have you seen a link to example.com?"},
+		{"multiple links", "https://a.com and https://b.com.", LinkModeDomain, "a link to a.com and a link to b.com."},
+		{"full", "read https://example.com/a/b, please", LinkModeFull, "read https://example.com/a/b, please"},
+		{"full embed suppressed form", "<https://example.com>", LinkModeFull, "https://example.com"},
+		{"skip", "look at https://example.com/x now", LinkModeSkip, "look at now"},
+		{"skip keeps punctuation", "wow https://example.com!", LinkModeSkip, "wow !"},
+		{"skip only link", "https://example.com", LinkModeSkip, ""},
+		{"no link", "nothing to see here", LinkModeDomain, "nothing to see here"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			modes := ContentModes{Links: tt.mode, CodeBlocks: CodeBlockModeSummary}
+			assert.Equal(t, tt.expected, applyContentModes(tt.content, modes))
+		})
+	}
+}
+
+func TestApplyContentModes_CodeBlocks(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		mode     CodeBlockMode
+		expected string
+	}{
+		{"summary single line", "try ```x := 1```", CodeBlockModeSummary, "try a code snippet, 1 line"},
+		{"summary with language", "fix:\n```go\nfunc main() {\n}\n```\nthanks", CodeBlockModeSummary, "fix:\na code snippet, 2 lines\nthanks"},
+		{"summary multiple blocks", "```a``` vs ```\nb\nc```", CodeBlockModeSummary, "a code snippet, 1 line vs a code snippet, 2 lines"},
+		{"full", "run ```go\nfmt.Println(1)\n```", CodeBlockModeFull, "run fmt.Println(1)"},
+		{"skip", "before ```\ncode\n``` after", CodeBlockModeSkip, "before after"},
+		{"skip only code", "```\ncode\n```", CodeBlockModeSkip, ""},
+		{"unterminated fence", "```not closed", CodeBlockModeSkip, "```not closed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			modes := ContentModes{Links: LinkModeDomain, CodeBlocks: tt.mode}
+			assert.Equal(t, tt.expected, applyContentModes(tt.content, modes))
+		})
+	}
+}
+
+func TestApplyContentModes_LinksInsideCode(t *testing.T) {
+	content := "```\ncurl https://example.com/api\n```"
+
+	// Summarized code hides its links entirely
+	assert.Equal(t, "a code snippet, 1 line", applyContentModes(content, ContentModes{Links: LinkModeDomain, CodeBlocks: CodeBlockModeSummary}))
+
+	// Code read in full still follows the link mode
+	assert.Equal(t, "curl a link to example.com", applyContentModes(content, ContentModes{Links: LinkModeDomain, CodeBlocks: CodeBlockModeFull}))
+}
+
+func TestApplyContentModes_Spoilers(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		mode     SpoilerMode
+		expected string
+	}{
+		{"summary", "the killer is ||the butler||!", SpoilerModeSummary, "the killer is spoiler omitted!"},
+		{"summary by default", "||ending||", "", "spoiler omitted"},
+		{"full", "the killer is ||the butler||", SpoilerModeFull, "the killer is the butler"},
+		{"skip", "before ||hidden|| after", SpoilerModeSkip, "before after"},
+		{"skip only spoiler", "||hidden||", SpoilerModeSkip, ""},
+		{"multiple", "||a|| and ||b||", SpoilerModeSummary, "spoiler omitted and spoiler omitted"},
+		{"unterminated", "a || b", SpoilerModeSkip, "a || b"},
+		{"inside code", "```\nif a || b || c {}\n```", SpoilerModeSkip, "a code snippet, 1 line"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			modes := ContentModes{Links: LinkModeDomain, CodeBlocks: CodeBlockModeSummary, Spoilers: tt.mode}
+			assert.Equal(t, tt.expected, applyContentModes(tt.content, modes))
+		})
+	}
+
+	// Links hidden in a spoiler are not read unless spoilers are
+	content := "watch ||https://example.com/ending||"
+	assert.Equal(t, "watch spoiler omitted", applyContentModes(content, ContentModes{Links: LinkModeDomain, Spoilers: SpoilerModeSummary}))
+	assert.Equal(t, "watch a link to example.com", applyContentModes(content, ContentModes{Links: LinkModeDomain, Spoilers: SpoilerModeFull}))
+
+	// Code read in full keeps its "||"
+	assert.Equal(t, "if a || b || c {}", applyContentModes("```\nif a || b || c {}\n```", ContentModes{CodeBlocks: CodeBlockModeFull, Spoilers: SpoilerModeSkip}))
+}
+
+func TestDescribeAttachments(t *testing.T) {
+	attachment := func(contentType string) *discordgo.MessageAttachment {
+		return &discordgo.MessageAttachment{ContentType: contentType}
+	}
+
+	tests := []struct {
+		name        string
+		attachments []*discordgo.MessageAttachment
+		expected    string
+	}{
+		{"image", []*discordgo.MessageAttachment{attachment("image/png")}, "an image"},
+		{"unknown type", []*discordgo.MessageAttachment{attachment("")}, "a file"},
+		{"two kinds", []*discordgo.MessageAttachment{attachment("image/png"), attachment("image/gif"), attachment("application/zip")}, "2 images and a file"},
+		{"every kind", []*discordgo.MessageAttachment{attachment("audio/ogg"), attachment("video/mp4"), attachment("text/plain"), attachment("image/jpeg")}, "an image, a video, an audio clip and a file"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, describeAttachments(tt.attachments))
+		})
+	}
+}
+
+func FuzzApplyContentModes(f *testing.F) {
+	f.Add("check ||https://example.com/secret|| and ```go\nfmt.Println(\"||x||\")\n```")
+	f.Add("<https://github.com/mmannerm/darrot> 😀😀😀 <:party:123456789>")
+	f.Add("```unterminated ||spoiler")
+
+	f.Fuzz(func(t *testing.T, content string) {
+		for _, links := range []LinkMode{LinkModeDomain, LinkModeFull, LinkModeSkip} {
+			for _, code := range []CodeBlockMode{CodeBlockModeSummary, CodeBlockModeFull, CodeBlockModeSkip} {
+				for _, spoilers := range []SpoilerMode{SpoilerModeSummary, SpoilerModeFull, SpoilerModeSkip} {
+					for _, skipEmoji := range []bool{false, true} {
+						modes := ContentModes{Links: links, CodeBlocks: code, Spoilers: spoilers, MaxEmoji: DefaultMaxSpokenEmoji, SkipEmoji: skipEmoji}
+						result := applyContentModes(content, modes)
+						assert.Equal(t, strings.TrimSpace(result), result)
+						if utf8.ValidString(content) {
+							assert.True(t, utf8.ValidString(result), "modes %+v broke UTF-8", modes)
+						}
+					}
+				}
+			}
+		}
+	})
+}
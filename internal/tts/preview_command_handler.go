@@ -0,0 +1,273 @@
+package tts
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"darrot/internal/commands/options"
+	"darrot/internal/commands/textcmd"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// MaxPreviewLength is the longest sample text /darrot-preview accepts
+const MaxPreviewLength = 200
+
+// PreviewCommandHandler plays a short sample of a voice without changing the guild's voice
+type PreviewCommandHandler struct {
+	ttsManager        TTSManager
+	voiceManager      VoiceManager
+	messageQueue      MessageQueue
+	configService     ConfigService
+	permissionService PermissionService
+	quotaService      TTSQuotaService
+	localizer         *Localizer
+	logger            *log.Logger
+}
+
+// NewPreviewCommandHandler creates a new voice preview command handler
+func NewPreviewCommandHandler(
+	ttsManager TTSManager,
+	voiceManager VoiceManager,
+	messageQueue MessageQueue,
+	configService ConfigService,
+	permissionService PermissionService,
+	logger *log.Logger,
+) *PreviewCommandHandler {
+	return &PreviewCommandHandler{
+		ttsManager:        ttsManager,
+		voiceManager:      voiceManager,
+		messageQueue:      messageQueue,
+		configService:     configService,
+		permissionService: permissionService,
+		logger:            logger,
+	}
+}
+
+// SetLocalizer sets the localizer used to translate responses
+func (h *PreviewCommandHandler) SetLocalizer(localizer *Localizer) {
+	h.localizer = localizer
+}
+
+// SetQuotaService charges previews sent as attachments against the daily budget. Previews
+// played in the voice channel go through the queue and are charged there.
+func (h *PreviewCommandHandler) SetQuotaService(quotaService TTSQuotaService) {
+	h.quotaService = quotaService
+}
+
+// Definition returns the Discord slash command definition for the preview command
+func (h *PreviewCommandHandler) Definition() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:        "darrot-preview",
+		Description: "Hear a TTS voice without changing the server's voice",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "voice",
+				Description: "Voice ID or name, see /darrot-config voice setting:list-voices",
+				Required:    true,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "text",
+				Description: "Text to read (defaults to a short sample sentence)",
+				MaxLength:   MaxPreviewLength,
+			},
+		},
+	}
+}
+
+// Handle processes the preview command interaction. When the bot is in a voice channel
+// the sample is queued there; otherwise it is returned as a WAV attachment only the
+// user can see.
+func (h *PreviewCommandHandler) Handle(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	// Validate guild context
+	if i.GuildID == "" {
+		return h.respondError(s, i, h.localizer.T(i.GuildID, "common.guild_only"))
+	}
+
+	userID := i.Member.User.ID
+	guildID := i.GuildID
+
+	// Validate permissions
+	if err := h.ValidatePermissions(userID, guildID); err != nil {
+		return h.respondError(s, i, h.localizer.T(guildID, "common.permission_denied", err))
+	}
+
+	opts := options.FromInteraction(i)
+	requested, err := opts.RequiredString("voice")
+	if err != nil {
+		return h.respondError(s, i, h.localizer.OptionError(guildID, err))
+	}
+
+	voice, ok := h.findVoice(requested)
+	if !ok {
+		return h.respondError(s, i, h.localizer.T(guildID, "config.voice.invalid_voice", requested))
+	}
+
+	text, _ := opts.String("text")
+	text = strings.TrimSpace(text)
+	if text == "" {
+		text = h.localizer.T(guildID, "preview.sample_text", voice.Name)
+	}
+	if utf8.RuneCountInString(text) > MaxPreviewLength {
+		return h.respondError(s, i, h.localizer.T(guildID, "preview.text_too_long", MaxPreviewLength))
+	}
+
+	if _, connected := h.voiceManager.GetConnection(guildID); connected {
+		return h.queuePreview(s, i, guildID, voice, text)
+	}
+	return h.sendPreviewFile(s, i, guildID, voice, text)
+}
+
+// queuePreview queues the sample so it plays in order with chat messages
+func (h *PreviewCommandHandler) queuePreview(s *discordgo.Session, i *discordgo.InteractionCreate, guildID string, voice Voice, text string) error {
+	message := &QueuedMessage{
+		ID:        i.ID,
+		GuildID:   guildID,
+		ChannelID: i.ChannelID,
+		UserID:    i.Member.User.ID,
+		Username:  i.Member.User.Username,
+		Content:   text,
+		Voice:     voice.ID,
+		Timestamp: time.Now(),
+	}
+
+	if err := h.messageQueue.Enqueue(message); err != nil {
+		return h.respondError(s, i, h.localizer.T(guildID, "preview.queue_failed", err))
+	}
+
+	return h.respondSuccess(s, i, h.localizer.T(guildID, "preview.queued", voice.Name))
+}
+
+// sendPreviewFile synthesizes the sample and sends it as a WAV attachment
+func (h *PreviewCommandHandler) sendPreviewFile(s *discordgo.Session, i *discordgo.InteractionCreate, guildID string, voice Voice, text string) error {
+	// Synthesis can exceed Discord's response deadline
+	if err := textcmd.Respond(s, i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Flags: discordgo.MessageFlagsEphemeral,
+		},
+	}); err != nil {
+		return err
+	}
+
+	wavData, err := h.synthesizeWAV(guildID, voice, text)
+	if errors.Is(err, ErrQuotaExceeded) {
+		return h.editResponse(s, i, "❌ "+h.localizer.T(guildID, "preview.quota_exceeded"), nil)
+	}
+	if err != nil {
+		h.logger.Printf("Failed to synthesize preview of voice %s for guild %s: %v", voice.ID, guildID, err)
+		return h.editResponse(s, i, "❌ "+h.localizer.T(guildID, "preview.synthesis_failed"), nil)
+	}
+
+	file := &discordgo.File{
+		Name:        fmt.Sprintf("preview-%s.wav", voice.ID),
+		ContentType: "audio/wav",
+		Reader:      bytes.NewReader(wavData),
+	}
+	return h.editResponse(s, i, h.localizer.T(guildID, "preview.attached", voice.Name), file)
+}
+
+// synthesizeWAV synthesizes text with the voice and the guild's speed, volume, pitch,
+// effects profile, style and loudness. newSynthesisRequest drops what the voice cannot use.
+func (h *PreviewCommandHandler) synthesizeWAV(guildID string, voice Voice, text string) ([]byte, error) {
+	config := TTSConfig{Voice: voice.ID, Speed: DefaultTTSSpeed, Volume: DefaultTTSVolume}
+	if settings, err := h.configService.GetTTSSettings(guildID); err == nil && settings != nil {
+		config.Speed = settings.Speed
+		config.Volume = settings.Volume
+		config.Pitch = settings.Pitch
+		config.EffectsProfile = settings.EffectsProfile
+		config.Style = settings.Style
+		config.Loudness = settings.Loudness
+	}
+	config.Format = AudioFormatPCM
+
+	// Only the budget is checked: a cheaper voice would defeat the purpose of a preview
+	if h.quotaService != nil {
+		if _, err := h.quotaService.Reserve(guildID, text, config); err != nil {
+			return nil, err
+		}
+	}
+
+	pcm, err := h.ttsManager.ConvertToSpeech(text, voice.ID, config)
+	if err != nil {
+		if h.quotaService != nil {
+			h.quotaService.Release(guildID, utf8.RuneCountInString(text), voice.ID)
+		}
+		return nil, err
+	}
+
+	if h.quotaService != nil {
+		if err := h.quotaService.RecordUsage(guildID, utf8.RuneCountInString(text), voice.ID); err != nil {
+			h.logger.Printf("Failed to record TTS usage for guild %s: %v", guildID, err)
+		}
+	}
+
+	return encodeWAV(pcm, discordSampleRate, discordChannels), nil
+}
+
+// findVoice looks up a supported voice by ID or name
+func (h *PreviewCommandHandler) findVoice(requested string) (Voice, bool) {
+	for _, voice := range h.ttsManager.GetSupportedVoices() {
+		if strings.EqualFold(voice.ID, requested) || strings.EqualFold(voice.Name, requested) {
+			return voice, true
+		}
+	}
+	return Voice{}, false
+}
+
+// ValidatePermissions validates that the user has permission to control the bot
+func (h *PreviewCommandHandler) ValidatePermissions(userID, guildID string) error {
+	canControl, err := h.permissionService.CanControlBot(userID, guildID)
+	if err != nil {
+		return fmt.Errorf("failed to check permissions: %w", err)
+	}
+
+	if !canControl {
+		return withKind(ErrPermission, fmt.Errorf("you don't have permission to control the bot"))
+	}
+
+	return nil
+}
+
+// ValidateChannelAccess is not needed for preview commands but required by interface
+func (h *PreviewCommandHandler) ValidateChannelAccess(userID, channelID string) error {
+	return nil // Not applicable for preview commands
+}
+
+// Helper methods for response handling
+
+func (h *PreviewCommandHandler) respondSuccess(s *discordgo.Session, i *discordgo.InteractionCreate, message string) error {
+	return textcmd.Respond(s, i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: message,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
+
+func (h *PreviewCommandHandler) respondError(s *discordgo.Session, i *discordgo.InteractionCreate, message string) error {
+	return textcmd.Respond(s, i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: "❌ " + message,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
+
+func (h *PreviewCommandHandler) editResponse(s *discordgo.Session, i *discordgo.InteractionCreate, message string, file *discordgo.File) error {
+	edit := &discordgo.WebhookEdit{Content: &message}
+	if file != nil {
+		edit.Files = []*discordgo.File{file}
+	}
+	_, err := textcmd.EditResponse(s, i.Interaction, edit)
+	return err
+}
@@ -0,0 +1,124 @@
+package tts
+
+import (
+	"errors"
+	"log"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createTestPreviewHandler(t *testing.T) (*PreviewCommandHandler, *mockTTSManager, *MockPermissionService) {
+	ttsManager := &mockTTSManager{
+		getSupportedFunc: func() []Voice {
+			return []Voice{
+				{ID: "en-US-Standard-A", Name: "Standard A", Language: "en-US", Gender: "male"},
+				{ID: "de-DE-Wavenet-B", Name: "Wavenet B", Language: "de-DE", Gender: "male"},
+			}
+		},
+	}
+	mockPermissionService := &MockPermissionService{}
+	logger := log.New(os.Stdout, "[TEST] ", log.LstdFlags)
+
+	handler := NewPreviewCommandHandler(ttsManager, newMockVoiceManager(), NewMessageQueue(), newMockConfigService(), mockPermissionService, logger)
+	return handler, ttsManager, mockPermissionService
+}
+
+func TestPreviewCommandHandler_Definition(t *testing.T) {
+	handler, _, _ := createTestPreviewHandler(t)
+
+	definition := handler.Definition()
+
+	assert.Equal(t, "darrot-preview", definition.Name)
+	require.Len(t, definition.Options, 2)
+	assert.Equal(t, "voice", definition.Options[0].Name)
+	assert.True(t, definition.Options[0].Required)
+	assert.Equal(t, "text", definition.Options[1].Name)
+	assert.False(t, definition.Options[1].Required)
+	assert.Equal(t, MaxPreviewLength, definition.Options[1].MaxLength)
+}
+
+func TestPreviewCommandHandler_ValidatePermissions(t *testing.T) {
+	handler, _, mockPermissionService := createTestPreviewHandler(t)
+
+	mockPermissionService.On("CanControlBot", "admin", "guild123").Return(true, nil)
+	mockPermissionService.On("CanControlBot", "user", "guild123").Return(false, nil)
+	mockPermissionService.On("CanControlBot", "broken", "guild123").Return(false, errors.New("permission check failed"))
+
+	assert.NoError(t, handler.ValidatePermissions("admin", "guild123"))
+	assert.ErrorContains(t, handler.ValidatePermissions("user", "guild123"), "permission to control the bot")
+	assert.ErrorContains(t, handler.ValidatePermissions("broken", "guild123"), "failed to check permissions")
+
+	mockPermissionService.AssertExpectations(t)
+}
+
+func TestPreviewCommandHandler_FindVoice(t *testing.T) {
+	handler, _, _ := createTestPreviewHandler(t)
+
+	voice, ok := handler.findVoice("de-DE-Wavenet-B")
+	require.True(t, ok)
+	assert.Equal(t, "de-DE-Wavenet-B", voice.ID)
+
+	voice, ok = handler.findVoice("standard a")
+	require.True(t, ok)
+	assert.Equal(t, "en-US-Standard-A", voice.ID)
+
+	_, ok = handler.findVoice("en-US-Neural2-Z")
+	assert.False(t, ok)
+}
+
+func TestPreviewCommandHandler_SynthesizeWAV(t *testing.T) {
+	handler, ttsManager, _ := createTestPreviewHandler(t)
+
+	var requested TTSConfig
+	ttsManager.convertFunc = func(text, voice string, config TTSConfig) ([]byte, error) {
+		requested = config
+		return make([]byte, 3840), nil // 20ms of 48kHz stereo PCM
+	}
+
+	voice, _ := handler.findVoice("de-DE-Wavenet-B")
+	wavData, err := handler.synthesizeWAV("guild1", voice, "hallo")
+	require.NoError(t, err)
+
+	// The requested voice is used as PCM without touching the guild's settings
+	assert.Equal(t, "de-DE-Wavenet-B", requested.Voice)
+	assert.Equal(t, AudioFormatPCM, requested.Format)
+
+	audio, err := parseWAV(wavData)
+	require.NoError(t, err)
+	assert.Equal(t, discordSampleRate, audio.sampleRate)
+	assert.Equal(t, discordChannels, audio.channels)
+	assert.Len(t, audio.pcm, 3840)
+}
+
+func TestPreviewCommandHandler_SynthesizeWAVQuota(t *testing.T) {
+	handler, ttsManager, _ := createTestPreviewHandler(t)
+	quotaService, _, _ := createTestQuotaService(t, 10)
+	handler.SetQuotaService(quotaService)
+
+	voice, _ := handler.findVoice("de-DE-Wavenet-B")
+
+	// Previews are charged against the daily budget
+	_, err := handler.synthesizeWAV("guild1", voice, "hallo")
+	require.NoError(t, err)
+	usage, err := quotaService.GetUsage("guild1")
+	require.NoError(t, err)
+	assert.Equal(t, 5, usage.CharactersUsed)
+
+	// Past the downgrade threshold the premium voice is still previewed
+	var voices []string
+	ttsManager.convertFunc = func(text, voice string, config TTSConfig) ([]byte, error) {
+		voices = append(voices, config.Voice)
+		return []byte{0, 0}, nil
+	}
+	_, err = handler.synthesizeWAV("guild1", voice, "welt")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"de-DE-Wavenet-B"}, voices)
+
+	// Once the budget is spent nothing is synthesized
+	_, err = handler.synthesizeWAV("guild1", voice, "zu lang")
+	assert.ErrorIs(t, err, ErrQuotaExceeded)
+	assert.Len(t, voices, 1)
+}
@@ -0,0 +1,173 @@
+package tts
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// PrivacyNoticeNamespace is the component namespace of the opt-out button in privacy
+// notices. The bot routes clicks on it to PrivacyService.HandleOptOut.
+const PrivacyNoticeNamespace = "privacy"
+
+// PrivacyMessenger sends direct messages
+type PrivacyMessenger interface {
+	UserChannelCreate(recipientID string, options ...discordgo.RequestOption) (*discordgo.Channel, error)
+	ChannelMessageSendComplex(channelID string, data *discordgo.MessageSend, options ...discordgo.RequestOption) (*discordgo.Message, error)
+	Guild(guildID string, options ...discordgo.RequestOption) (*discordgo.Guild, error)
+}
+
+// ComponentIDFactory creates custom IDs for message components. The bot's
+// ComponentRouter implements it.
+type ComponentIDFactory interface {
+	NewCustomID(namespace, action, ownerID string, ttl time.Duration) (string, error)
+}
+
+// PrivacyService tells users who were opted in automatically that their messages will
+// be read aloud, and lets them opt out with one click
+type PrivacyService struct {
+	userService   UserService
+	configService ConfigService
+	messenger     PrivacyMessenger
+	componentIDs  ComponentIDFactory
+	localizer     *Localizer
+	logger        *log.Logger
+}
+
+// NewPrivacyService creates a privacy service. Notices are sent without an opt-out
+// button until SetComponentIDs is called.
+func NewPrivacyService(
+	userService UserService,
+	configService ConfigService,
+	messenger PrivacyMessenger,
+	logger *log.Logger,
+) *PrivacyService {
+	return &PrivacyService{
+		userService:   userService,
+		configService: configService,
+		messenger:     messenger,
+		logger:        logger,
+	}
+}
+
+// SetComponentIDs sets the factory for the custom ID of the opt-out button
+func (p *PrivacyService) SetComponentIDs(componentIDs ComponentIDFactory) {
+	p.componentIDs = componentIDs
+}
+
+// SetLocalizer sets the localizer used to translate notices
+func (p *PrivacyService) SetLocalizer(localizer *Localizer) {
+	p.localizer = localizer
+}
+
+// NoticeEnabled reports whether automatically opted-in users of a guild get a DM notice
+func (p *PrivacyService) NoticeEnabled(guildID string) bool {
+	config, err := p.configService.GetGuildConfig(guildID)
+	if err != nil || config == nil {
+		return false
+	}
+	return config.OptInNoticeDM
+}
+
+// SetNoticeEnabled turns the DM notice on or off for a guild
+func (p *PrivacyService) SetNoticeEnabled(guildID string, enabled bool) error {
+	config, err := p.configService.GetGuildConfig(guildID)
+	if err != nil {
+		return fmt.Errorf("failed to get guild config: %w", err)
+	}
+	if config == nil {
+		defaultConfig := DefaultGuildTTSConfig(guildID)
+		config = &defaultConfig
+	}
+
+	updated := *config
+	updated.OptInNoticeDM = enabled
+
+	return p.configService.SetGuildConfig(guildID, &updated)
+}
+
+// SendOptInNotice sends a user who was just opted in automatically a DM explaining that
+// their messages in the guild will be read aloud. It does nothing unless the guild has
+// turned the notice on.
+func (p *PrivacyService) SendOptInNotice(userID, guildID string) error {
+	if !p.NoticeEnabled(guildID) {
+		return nil
+	}
+
+	guildName := guildID
+	if guild, err := p.messenger.Guild(guildID); err == nil && guild.Name != "" {
+		guildName = guild.Name
+	}
+
+	message := &discordgo.MessageSend{
+		Content: p.localizer.T(guildID, "privacy.notice", guildName),
+	}
+
+	if p.componentIDs != nil {
+		customID, err := p.componentIDs.NewCustomID(PrivacyNoticeNamespace, guildID, userID, 0)
+		if err != nil {
+			return fmt.Errorf("failed to create opt-out button: %w", err)
+		}
+		message.Components = []discordgo.MessageComponent{
+			discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Label:    p.localizer.T(guildID, "privacy.opt_out_button"),
+					Style:    discordgo.DangerButton,
+					CustomID: customID,
+				},
+			}},
+		}
+	}
+
+	channel, err := p.messenger.UserChannelCreate(userID)
+	if err != nil {
+		return fmt.Errorf("failed to open DM channel: %w", err)
+	}
+	if _, err := p.messenger.ChannelMessageSendComplex(channel.ID, message); err != nil {
+		return fmt.Errorf("failed to send privacy notice: %w", err)
+	}
+
+	p.logger.Printf("Sent opt-in privacy notice to user %s for guild %s", userID, guildID)
+	return nil
+}
+
+// OptOut opts a user out of TTS in a guild and returns the confirmation to show them
+func (p *PrivacyService) OptOut(userID, guildID string) (string, error) {
+	if err := setOptInStatusBy(p.userService, userID, guildID, false, ConsentMethodNotice, userID); err != nil {
+		return p.localizer.T(guildID, "privacy.opt_out_failed"), err
+	}
+
+	p.logger.Printf("User %s opted out of TTS in guild %s from a privacy notice", userID, guildID)
+	return p.localizer.T(guildID, "privacy.opted_out"), nil
+}
+
+// HandleOptOut handles a click on the opt-out button of a privacy notice. The button's
+// action is the guild the notice is about. On success the notice is replaced with the
+// confirmation so the button cannot be clicked again.
+func (p *PrivacyService) HandleOptOut(s *discordgo.Session, i *discordgo.InteractionCreate, guildID string) error {
+	userID := ""
+	if i.User != nil {
+		userID = i.User.ID
+	} else if i.Member != nil && i.Member.User != nil {
+		userID = i.Member.User.ID
+	}
+
+	confirmation, err := p.OptOut(userID, guildID)
+	if err != nil {
+		p.logger.Printf("Failed to opt out user %s in guild %s: %v", userID, guildID, err)
+		return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{Content: confirmation},
+		})
+	}
+
+	return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Content:    confirmation,
+			Components: []discordgo.MessageComponent{},
+		},
+	})
+}
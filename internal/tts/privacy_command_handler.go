@@ -0,0 +1,169 @@
+package tts
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"darrot/internal/commands/options"
+	"darrot/internal/commands/textcmd"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// PrivacyCommandHandler handles a member's data-protection requests: receiving
+// everything the bot stores about them in the server, or having it deleted
+type PrivacyCommandHandler struct {
+	userDataService *UserDataService
+	localizer       *Localizer
+	logger          *log.Logger
+}
+
+// NewPrivacyCommandHandler creates a new privacy command handler
+func NewPrivacyCommandHandler(userDataService *UserDataService, logger *log.Logger) *PrivacyCommandHandler {
+	return &PrivacyCommandHandler{
+		userDataService: userDataService,
+		logger:          logger,
+	}
+}
+
+// SetLocalizer sets the localizer used to translate responses
+func (h *PrivacyCommandHandler) SetLocalizer(localizer *Localizer) {
+	h.localizer = localizer
+}
+
+// Definition returns the Discord slash command definition for the privacy command
+func (h *PrivacyCommandHandler) Definition() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:        "darrot-privacy",
+		Description: "Get or delete the data the bot stores about you in this server",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "export",
+				Description: "Receive everything the bot stores about you as a JSON file",
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "delete",
+				Description: "Delete everything the bot stores about you and opt out",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionBoolean,
+						Name:        "confirm",
+						Description: "Set to true to confirm that your data should be deleted",
+						Required:    true,
+					},
+				},
+			},
+		},
+	}
+}
+
+// Handle processes the privacy command interaction
+func (h *PrivacyCommandHandler) Handle(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	// Validate guild context
+	if i.GuildID == "" {
+		return h.respondError(s, i, h.localizer.T(i.GuildID, "common.guild_only"))
+	}
+
+	userID := i.Member.User.ID
+	guildID := i.GuildID
+
+	subcommand, opts, ok := options.FromInteraction(i).Subcommand()
+	if !ok {
+		return h.respondError(s, i, h.localizer.T(guildID, "common.no_subcommand"))
+	}
+
+	switch subcommand {
+	case "export":
+		return h.handleExport(s, i, userID, guildID)
+	case "delete":
+		return h.handleDelete(s, i, userID, guildID, opts)
+	default:
+		return h.respondError(s, i, h.localizer.T(guildID, "common.invalid_subcommand"))
+	}
+}
+
+// handleExport sends the member everything stored about them as a JSON attachment
+func (h *PrivacyCommandHandler) handleExport(s *discordgo.Session, i *discordgo.InteractionCreate, userID, guildID string) error {
+	export, err := h.userDataService.Export(userID, guildID)
+	if err != nil {
+		h.logger.Printf("Error exporting data of user %s in guild %s: %v", userID, guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "privacy.export_failed"))
+	}
+
+	file, err := userDataFile(export)
+	if err != nil {
+		h.logger.Printf("Error encoding data of user %s in guild %s: %v", userID, guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "privacy.export_failed"))
+	}
+
+	return textcmd.Respond(s, i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: h.localizer.T(guildID, "privacy.export_ready"),
+			Files:   []*discordgo.File{file},
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
+
+// userDataFile encodes an export as a JSON attachment named after the user and guild
+func userDataFile(export *UserDataExport) (*discordgo.File, error) {
+	data, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	name := fmt.Sprintf("darrot-data-%s-%s.json", export.UserID, export.GuildID)
+	return &discordgo.File{Name: name, ContentType: "application/json", Reader: bytes.NewReader(data)}, nil
+}
+
+// handleDelete deletes everything stored about the member once they confirmed it
+func (h *PrivacyCommandHandler) handleDelete(s *discordgo.Session, i *discordgo.InteractionCreate, userID, guildID string, opts options.Set) error {
+	if confirm, _ := opts.Bool("confirm"); !confirm {
+		return h.respondError(s, i, h.localizer.T(guildID, "privacy.delete_unconfirmed"))
+	}
+
+	if err := h.userDataService.Delete(userID, guildID); err != nil {
+		h.logger.Printf("Error deleting data of user %s in guild %s: %v", userID, guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "privacy.delete_failed"))
+	}
+
+	h.logger.Printf("Deleted the data of user %s in guild %s at their request", userID, guildID)
+	return h.respondSuccess(s, i, h.localizer.T(guildID, "privacy.deleted"))
+}
+
+// ValidatePermissions allows every member to manage their own data
+func (h *PrivacyCommandHandler) ValidatePermissions(userID, guildID string) error {
+	return nil
+}
+
+// ValidateChannelAccess is not needed for privacy commands but required by interface
+func (h *PrivacyCommandHandler) ValidateChannelAccess(userID, channelID string) error {
+	return nil // Not applicable for privacy commands
+}
+
+// Helper methods for response handling
+
+func (h *PrivacyCommandHandler) respondSuccess(s *discordgo.Session, i *discordgo.InteractionCreate, message string) error {
+	return textcmd.Respond(s, i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: message,
+			Flags:   discordgo.MessageFlagsEphemeral, // A member's data is only shown to them
+		},
+	})
+}
+
+func (h *PrivacyCommandHandler) respondError(s *discordgo.Session, i *discordgo.InteractionCreate, message string) error {
+	return textcmd.Respond(s, i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: "❌ " + message,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
@@ -0,0 +1,49 @@
+package tts
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"os"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrivacyCommandHandler_Definition(t *testing.T) {
+	handler := NewPrivacyCommandHandler(nil, log.New(os.Stdout, "[TEST] ", log.LstdFlags))
+
+	definition := handler.Definition()
+
+	assert.Equal(t, "darrot-privacy", definition.Name)
+	require.Len(t, definition.Options, 2) // export, delete subcommands
+	assert.Equal(t, "export", definition.Options[0].Name)
+
+	deleteOption := definition.Options[1]
+	assert.Equal(t, "delete", deleteOption.Name)
+	require.Len(t, deleteOption.Options, 1)
+	assert.Equal(t, discordgo.ApplicationCommandOptionBoolean, deleteOption.Options[0].Type)
+	assert.True(t, deleteOption.Options[0].Required, "deleting needs confirmation")
+
+	assert.NoError(t, handler.ValidatePermissions("user1", "guild1"))
+}
+
+func TestPrivacyCommandHandler_UserDataFile(t *testing.T) {
+	service, _, _, _, _ := createTestUserDataService(t)
+	export, err := service.Export("user1", "guild1")
+	require.NoError(t, err)
+
+	file, err := userDataFile(export)
+	require.NoError(t, err)
+	assert.Equal(t, "darrot-data-user1-guild1.json", file.Name)
+	assert.Equal(t, "application/json", file.ContentType)
+
+	data, err := io.ReadAll(file.Reader)
+	require.NoError(t, err)
+	var decoded UserDataExport
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, "user1", decoded.UserID)
+	assert.Len(t, decoded.Transcripts, 1)
+}
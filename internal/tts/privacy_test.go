@@ -0,0 +1,145 @@
+package tts
+
+import (
+	"errors"
+	"io"
+	"log"
+	"testing"
+	"time"
+
+	"darrot/internal/config"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockPrivacyMessenger records the DMs a PrivacyService sends
+type mockPrivacyMessenger struct {
+	sent       map[string][]*discordgo.MessageSend // keyed by recipient
+	channelErr error
+}
+
+func newMockPrivacyMessenger() *mockPrivacyMessenger {
+	return &mockPrivacyMessenger{sent: make(map[string][]*discordgo.MessageSend)}
+}
+
+func (m *mockPrivacyMessenger) UserChannelCreate(recipientID string, options ...discordgo.RequestOption) (*discordgo.Channel, error) {
+	if m.channelErr != nil {
+		return nil, m.channelErr
+	}
+	return &discordgo.Channel{ID: recipientID}, nil
+}
+
+func (m *mockPrivacyMessenger) ChannelMessageSendComplex(channelID string, data *discordgo.MessageSend, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+	m.sent[channelID] = append(m.sent[channelID], data)
+	return &discordgo.Message{ChannelID: channelID}, nil
+}
+
+func (m *mockPrivacyMessenger) Guild(guildID string, options ...discordgo.RequestOption) (*discordgo.Guild, error) {
+	return &discordgo.Guild{ID: guildID, Name: "Test Server"}, nil
+}
+
+// mockComponentIDs encodes custom IDs as namespace/owner/action
+type mockComponentIDs struct{}
+
+func (mockComponentIDs) NewCustomID(namespace, action, ownerID string, ttl time.Duration) (string, error) {
+	return namespace + "/" + ownerID + "/" + action, nil
+}
+
+func createTestPrivacyService(t *testing.T) (*PrivacyService, *mockPrivacyMessenger, UserService) {
+	storage, err := NewStorageService(t.TempDir())
+	require.NoError(t, err)
+
+	configService := NewConfigService(storage, config.TTSConfig{DefaultVoice: DefaultVoice, DefaultSpeed: 1.0, DefaultVolume: 1.0, MaxQueueSize: 10})
+	userService := NewUserService(storage)
+	messenger := newMockPrivacyMessenger()
+
+	return NewPrivacyService(userService, configService, messenger, log.New(io.Discard, "", 0)), messenger, userService
+}
+
+func TestPrivacyService_NoticeDisabledByDefault(t *testing.T) {
+	privacy, messenger, _ := createTestPrivacyService(t)
+
+	assert.False(t, privacy.NoticeEnabled("guild1"))
+	require.NoError(t, privacy.SendOptInNotice("user1", "guild1"))
+	assert.Empty(t, messenger.sent)
+}
+
+func TestPrivacyService_SendOptInNotice(t *testing.T) {
+	privacy, messenger, _ := createTestPrivacyService(t)
+	privacy.SetComponentIDs(mockComponentIDs{})
+	require.NoError(t, privacy.SetNoticeEnabled("guild1", true))
+	assert.True(t, privacy.NoticeEnabled("guild1"))
+
+	require.NoError(t, privacy.SendOptInNotice("user1", "guild1"))
+
+	require.Len(t, messenger.sent["user1"], 1)
+	notice := messenger.sent["user1"][0]
+	assert.Contains(t, notice.Content, "Test Server")
+
+	require.Len(t, notice.Components, 1)
+	row, ok := notice.Components[0].(discordgo.ActionsRow)
+	require.True(t, ok)
+	require.Len(t, row.Components, 1)
+	button, ok := row.Components[0].(discordgo.Button)
+	require.True(t, ok)
+	assert.Equal(t, discordgo.DangerButton, button.Style)
+	assert.Equal(t, "Opt out", button.Label)
+	assert.Equal(t, PrivacyNoticeNamespace+"/user1/guild1", button.CustomID)
+}
+
+func TestPrivacyService_SendOptInNoticeWithoutButton(t *testing.T) {
+	privacy, messenger, _ := createTestPrivacyService(t)
+	require.NoError(t, privacy.SetNoticeEnabled("guild1", true))
+
+	require.NoError(t, privacy.SendOptInNotice("user1", "guild1"))
+
+	require.Len(t, messenger.sent["user1"], 1)
+	assert.Empty(t, messenger.sent["user1"][0].Components)
+}
+
+func TestPrivacyService_SendOptInNoticeDMClosed(t *testing.T) {
+	privacy, messenger, _ := createTestPrivacyService(t)
+	require.NoError(t, privacy.SetNoticeEnabled("guild1", true))
+	messenger.channelErr = errors.New("cannot send messages to this user")
+
+	assert.ErrorContains(t, privacy.SendOptInNotice("user1", "guild1"), "failed to open DM channel")
+}
+
+func TestPrivacyService_OptOut(t *testing.T) {
+	privacy, _, userService := createTestPrivacyService(t)
+	require.NoError(t, userService.SetOptInStatus("user1", "guild1", true))
+
+	confirmation, err := privacy.OptOut("user1", "guild1")
+	require.NoError(t, err)
+	assert.Contains(t, confirmation, "opted out")
+
+	optedIn, err := userService.IsOptedIn("user1", "guild1")
+	require.NoError(t, err)
+	assert.False(t, optedIn)
+
+	// The consent history records that the user opted out from the notice
+	history, err := userService.(ConsentAuditor).GetConsentHistory("user1", "guild1")
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, ConsentMethodNotice, history[1].Method)
+	assert.Equal(t, "user1", history[1].ActorID)
+}
+
+func TestPrivacyService_SetNoticeEnabledKeepsOtherSettings(t *testing.T) {
+	privacy, _, _ := createTestPrivacyService(t)
+	existing := DefaultGuildTTSConfig("guild1")
+	existing.TTSSettings.Speed = 1.5
+	require.NoError(t, privacy.configService.SetGuildConfig("guild1", &existing))
+
+	require.NoError(t, privacy.SetNoticeEnabled("guild1", true))
+
+	guildConfig, err := privacy.configService.GetGuildConfig("guild1")
+	require.NoError(t, err)
+	assert.True(t, guildConfig.OptInNoticeDM)
+	assert.Equal(t, float32(1.5), guildConfig.TTSSettings.Speed)
+
+	require.NoError(t, privacy.SetNoticeEnabled("guild1", false))
+	assert.False(t, privacy.NoticeEnabled("guild1"))
+}
@@ -0,0 +1,110 @@
+package tts
+
+import (
+	"darrot/internal/commands/options"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// ProfileCommandHandler handles /darrot-profile, which saves and switches between named
+// presets of a server's settings and exports and imports its whole configuration. It was
+// split off /darrot-config when that command reached Discord's limit of 25 subcommands,
+// and runs on the configuration handler's services.
+type ProfileCommandHandler struct {
+	config *ConfigCommandHandler
+}
+
+// NewProfileCommandHandler creates the /darrot-profile command handler
+func NewProfileCommandHandler(config *ConfigCommandHandler) *ProfileCommandHandler {
+	return &ProfileCommandHandler{config: config}
+}
+
+// Definition returns the Discord slash command definition for the profile command
+func (h *ProfileCommandHandler) Definition() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:                     "darrot-profile",
+		Description:              "Save, switch, export and import this server's settings (Administrator only)",
+		DefaultMemberPermissions: adminCommandPermissions(),
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "save",
+				Description: "Save the current voice, queue size and moderation settings as a profile",
+				Options:     []*discordgo.ApplicationCommandOption{profileNameOption()},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "use",
+				Description: "Switch this server to the settings of a profile",
+				Options:     []*discordgo.ApplicationCommandOption{profileNameOption()},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "delete",
+				Description: "Delete a profile",
+				Options:     []*discordgo.ApplicationCommandOption{profileNameOption()},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "list",
+				Description: "List the saved profiles",
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "export",
+				Description: "Download this server's configuration as a JSON file",
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "import",
+				Description: "Restore this server's configuration from an exported JSON file",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionAttachment,
+						Name:        "file",
+						Description: "JSON file written by /darrot-profile export",
+						Required:    true,
+					},
+				},
+			},
+		},
+	}
+}
+
+// profileNameOption is the name option of the profile subcommands
+func profileNameOption() *discordgo.ApplicationCommandOption {
+	return &discordgo.ApplicationCommandOption{
+		Type:        discordgo.ApplicationCommandOptionString,
+		Name:        "name",
+		Description: "Profile name, such as movie night",
+		Required:    true,
+		MaxLength:   MaxProfileNameLength,
+	}
+}
+
+// Handle processes the profile command interaction
+func (h *ProfileCommandHandler) Handle(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	return h.config.handleSubcommand(s, i, h.routeSubcommand)
+}
+
+// routeSubcommand runs a /darrot-profile subcommand
+func (h *ProfileCommandHandler) routeSubcommand(s *discordgo.Session, i *discordgo.InteractionCreate, guildID, subcommand string, opts options.Set) error {
+	switch subcommand {
+	case "export":
+		return h.config.handleExportConfig(s, i, guildID)
+	case "import":
+		return h.config.handleImportConfig(s, i, guildID, opts)
+	default:
+		return h.config.handleProfileConfig(s, i, guildID, subcommand, opts)
+	}
+}
+
+// ValidatePermissions validates that the user is a server administrator
+func (h *ProfileCommandHandler) ValidatePermissions(userID, guildID string) error {
+	return h.config.ValidatePermissions(userID, guildID)
+}
+
+// ValidateChannelAccess is not needed for profile commands but required by interface
+func (h *ProfileCommandHandler) ValidateChannelAccess(userID, channelID string) error {
+	return nil // Not applicable for profile commands
+}
@@ -0,0 +1,44 @@
+package tts
+
+import (
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfileCommandHandler_Definition(t *testing.T) {
+	config, _, _, _, _ := createTestConfigHandler()
+	handler := NewProfileCommandHandler(config)
+
+	definition := handler.Definition()
+
+	assert.Equal(t, "darrot-profile", definition.Name)
+	assert.Equal(t, adminCommandPermissions(), definition.DefaultMemberPermissions)
+	require.Len(t, definition.Options, 6) // save, use, delete, list, export, import subcommands
+
+	subcommandNames := make(map[string]bool)
+	for _, option := range definition.Options {
+		assert.Equal(t, discordgo.ApplicationCommandOptionSubCommand, option.Type)
+		subcommandNames[option.Name] = true
+	}
+	assert.True(t, subcommandNames["save"])
+	assert.True(t, subcommandNames["use"])
+	assert.True(t, subcommandNames["delete"])
+	assert.True(t, subcommandNames["list"])
+	assert.True(t, subcommandNames["export"])
+	assert.True(t, subcommandNames["import"])
+}
+
+func TestProfileCommandHandler_ValidatePermissions(t *testing.T) {
+	config, _, mockPermissionService, _, _ := createTestConfigHandler()
+	handler := NewProfileCommandHandler(config)
+
+	mockPermissionService.On("CanControlBot", "admin", "guild123").Return(true, nil)
+	mockPermissionService.On("CanControlBot", "user", "guild123").Return(false, nil)
+
+	assert.NoError(t, handler.ValidatePermissions("admin", "guild123"))
+	assert.Error(t, handler.ValidatePermissions("user", "guild123"))
+	mockPermissionService.AssertExpectations(t)
+}
@@ -0,0 +1,198 @@
+package tts
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// NormalizeProfileName trims a configuration profile name, rejecting empty names, names
+// with control characters and names over the length limit
+func NormalizeProfileName(name string) (string, error) {
+	name = strings.Join(strings.Fields(name), " ")
+	if name == "" {
+		return "", errors.New("profile name cannot be empty")
+	}
+	if strings.ContainsFunc(name, unicode.IsControl) {
+		return "", fmt.Errorf("profile name %q cannot contain control characters", name)
+	}
+	if utf8.RuneCountInString(name) > MaxProfileNameLength {
+		return "", fmt.Errorf("profile name %q is longer than %d characters", name, MaxProfileNameLength)
+	}
+	return name, nil
+}
+
+// ValidateGuildProfile validates the name and settings of a configuration profile
+func ValidateGuildProfile(profile GuildProfile) error {
+	if _, err := NormalizeProfileName(profile.Name); err != nil {
+		return err
+	}
+
+	if err := ValidateConfig(profile.TTSSettings); err != nil {
+		return fmt.Errorf("invalid TTS settings in profile %q: %w", profile.Name, err)
+	}
+
+	if profile.MaxQueueSize < 1 || profile.MaxQueueSize > MaxQueueSize {
+		return fmt.Errorf("max queue size of profile %q must be between 1 and %d", profile.Name, MaxQueueSize)
+	}
+
+	if profile.Moderation != nil {
+		if err := ValidateModerationSettings(*profile.Moderation); err != nil {
+			return fmt.Errorf("invalid moderation settings in profile %q: %w", profile.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// ValidateGuildProfiles validates a guild's profiles and checks that names are unique
+func ValidateGuildProfiles(profiles []GuildProfile) error {
+	if len(profiles) > MaxGuildProfiles {
+		return fmt.Errorf("at most %d profiles are allowed", MaxGuildProfiles)
+	}
+
+	seen := make(map[string]bool, len(profiles))
+	for _, profile := range profiles {
+		if err := ValidateGuildProfile(profile); err != nil {
+			return err
+		}
+		key := strings.ToLower(profile.Name)
+		if seen[key] {
+			return fmt.Errorf("profile name %q is used more than once", profile.Name)
+		}
+		seen[key] = true
+	}
+
+	return nil
+}
+
+// findProfile returns the index of the profile with a name, ignoring case, or -1
+func findProfile(profiles []GuildProfile, name string) int {
+	return slices.IndexFunc(profiles, func(profile GuildProfile) bool {
+		return strings.EqualFold(profile.Name, name)
+	})
+}
+
+// GetProfiles returns a guild's configuration profiles in the order they were created
+func (cs *configService) GetProfiles(guildID string) ([]GuildProfile, error) {
+	profiles, err := cs.storage.LoadGuildProfiles(guildID)
+	if err != nil {
+		return nil, err
+	}
+	return profiles.Profiles, nil
+}
+
+// SaveProfile creates a configuration profile or replaces the profile with the same name
+func (cs *configService) SaveProfile(guildID string, profile GuildProfile) error {
+	name, err := NormalizeProfileName(profile.Name)
+	if err != nil {
+		return err
+	}
+	profile.Name = name
+	if err := ValidateGuildProfile(profile); err != nil {
+		return err
+	}
+	profile.UpdatedAt = time.Now()
+
+	cs.profileMu.Lock()
+	defer cs.profileMu.Unlock()
+
+	profiles, err := cs.storage.LoadGuildProfiles(guildID)
+	if err != nil {
+		return err
+	}
+
+	if index := findProfile(profiles.Profiles, name); index >= 0 {
+		profiles.Profiles[index] = profile
+	} else {
+		if len(profiles.Profiles) >= MaxGuildProfiles {
+			return ErrProfileLimit
+		}
+		profiles.Profiles = append(profiles.Profiles, profile)
+	}
+
+	return cs.storage.SaveGuildProfiles(*profiles)
+}
+
+// DeleteProfile removes a configuration profile. Deleting the active profile leaves the
+// guild's settings as they are but clears the active profile.
+func (cs *configService) DeleteProfile(guildID, name string) error {
+	cs.profileMu.Lock()
+	defer cs.profileMu.Unlock()
+
+	profiles, err := cs.storage.LoadGuildProfiles(guildID)
+	if err != nil {
+		return err
+	}
+
+	index := findProfile(profiles.Profiles, strings.TrimSpace(name))
+	if index < 0 {
+		return ErrProfileNotFound
+	}
+	deleted := profiles.Profiles[index].Name
+	profiles.Profiles = slices.Delete(profiles.Profiles, index, index+1)
+
+	if err := cs.storage.SaveGuildProfiles(*profiles); err != nil {
+		return err
+	}
+
+	config, err := cs.GetGuildConfig(guildID)
+	if err != nil {
+		return err
+	}
+	if config.ActiveProfile != deleted {
+		return nil
+	}
+	updated := *config
+	updated.ActiveProfile = ""
+	return cs.SetGuildConfig(guildID, &updated)
+}
+
+// UseProfile applies a profile's voice settings and queue size to a guild and makes it the
+// active profile. The profile is returned so callers can apply the settings kept outside
+// the guild configuration, such as moderation and the running message queue.
+func (cs *configService) UseProfile(guildID, name string) (*GuildProfile, error) {
+	cs.profileMu.Lock()
+	defer cs.profileMu.Unlock()
+
+	profiles, err := cs.storage.LoadGuildProfiles(guildID)
+	if err != nil {
+		return nil, err
+	}
+
+	index := findProfile(profiles.Profiles, strings.TrimSpace(name))
+	if index < 0 {
+		return nil, ErrProfileNotFound
+	}
+	profile := profiles.Profiles[index]
+
+	config, err := cs.GetGuildConfig(guildID)
+	if err != nil {
+		return nil, err
+	}
+	updated := *config
+	updated.TTSSettings = profile.TTSSettings
+	updated.MaxQueueSize = profile.MaxQueueSize
+	updated.ActiveProfile = profile.Name
+
+	if err := cs.SetGuildConfig(guildID, &updated); err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// replaceProfiles replaces all of a guild's profiles, as when restoring a configuration export
+func (cs *configService) replaceProfiles(guildID string, profiles []GuildProfile) error {
+	if err := ValidateGuildProfiles(profiles); err != nil {
+		return err
+	}
+
+	cs.profileMu.Lock()
+	defer cs.profileMu.Unlock()
+
+	return cs.storage.SaveGuildProfiles(GuildProfiles{GuildID: guildID, Profiles: slices.Clone(profiles)})
+}
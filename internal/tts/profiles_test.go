@@ -0,0 +1,109 @@
+package tts
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testProfile(name, voice string, queueSize int) GuildProfile {
+	settings := DefaultTTSConfig()
+	settings.Voice = voice
+	return GuildProfile{Name: name, TTSSettings: settings, MaxQueueSize: queueSize}
+}
+
+func TestNormalizeProfileName(t *testing.T) {
+	name, err := NormalizeProfileName("  movie   night ")
+	require.NoError(t, err)
+	assert.Equal(t, "movie night", name)
+
+	_, err = NormalizeProfileName("   ")
+	assert.Error(t, err)
+	_, err = NormalizeProfileName(strings.Repeat("a", MaxProfileNameLength+1))
+	assert.Error(t, err)
+}
+
+func TestValidateGuildProfiles(t *testing.T) {
+	assert.NoError(t, ValidateGuildProfiles(nil))
+	assert.NoError(t, ValidateGuildProfiles([]GuildProfile{testProfile("a", DefaultVoice, 10), testProfile("b", DefaultVoice, 10)}))
+
+	assert.Error(t, ValidateGuildProfiles([]GuildProfile{testProfile("raid", DefaultVoice, 10), testProfile("Raid", DefaultVoice, 10)}), "names are unique ignoring case")
+	assert.Error(t, ValidateGuildProfiles([]GuildProfile{testProfile("a", DefaultVoice, 0)}))
+
+	tooFast := testProfile("a", DefaultVoice, 10)
+	tooFast.TTSSettings.Speed = MaxTTSSpeed + 1
+	assert.Error(t, ValidateGuildProfiles([]GuildProfile{tooFast}))
+
+	withModeration := testProfile("a", DefaultVoice, 10)
+	withModeration.Moderation = &ModerationSettings{Mode: "shout"}
+	assert.Error(t, ValidateGuildProfiles([]GuildProfile{withModeration}))
+}
+
+func TestConfigService_SaveAndUseProfile(t *testing.T) {
+	configService := createTestExportConfigService(t)
+
+	require.NoError(t, configService.SaveProfile("guild1", testProfile(" movie night ", "en-GB-Standard-A", 5)))
+	require.NoError(t, configService.SaveProfile("guild1", testProfile("raid calls", "en-US-Standard-B", 50)))
+
+	profiles, err := configService.GetProfiles("guild1")
+	require.NoError(t, err)
+	require.Len(t, profiles, 2)
+	assert.Equal(t, "movie night", profiles[0].Name)
+	assert.Equal(t, "raid calls", profiles[1].Name)
+
+	profile, err := configService.UseProfile("guild1", "Movie Night")
+	require.NoError(t, err)
+	assert.Equal(t, "movie night", profile.Name)
+
+	guildConfig, err := configService.GetGuildConfig("guild1")
+	require.NoError(t, err)
+	assert.Equal(t, "en-GB-Standard-A", guildConfig.TTSSettings.Voice)
+	assert.Equal(t, 5, guildConfig.MaxQueueSize)
+	assert.Equal(t, "movie night", guildConfig.ActiveProfile)
+
+	// Saving under an existing name replaces the profile
+	require.NoError(t, configService.SaveProfile("guild1", testProfile("MOVIE NIGHT", "en-AU-Standard-A", 8)))
+	profiles, err = configService.GetProfiles("guild1")
+	require.NoError(t, err)
+	require.Len(t, profiles, 2)
+	assert.Equal(t, "en-AU-Standard-A", profiles[0].TTSSettings.Voice)
+
+	_, err = configService.UseProfile("guild1", "karaoke")
+	assert.ErrorIs(t, err, ErrProfileNotFound)
+}
+
+func TestConfigService_ProfileLimit(t *testing.T) {
+	configService := createTestExportConfigService(t)
+
+	for n := 0; n < MaxGuildProfiles; n++ {
+		require.NoError(t, configService.SaveProfile("guild1", testProfile(strings.Repeat("p", n+1), DefaultVoice, 10)))
+	}
+	assert.ErrorIs(t, configService.SaveProfile("guild1", testProfile("one more", DefaultVoice, 10)), ErrProfileLimit)
+
+	// Replacing an existing profile is still allowed
+	assert.NoError(t, configService.SaveProfile("guild1", testProfile("p", DefaultVoice, 20)))
+}
+
+func TestConfigService_DeleteProfile(t *testing.T) {
+	configService := createTestExportConfigService(t)
+	require.NoError(t, configService.SaveProfile("guild1", testProfile("movie night", "en-GB-Standard-A", 5)))
+	require.NoError(t, configService.SaveProfile("guild1", testProfile("raid calls", DefaultVoice, 50)))
+	_, err := configService.UseProfile("guild1", "movie night")
+	require.NoError(t, err)
+
+	require.NoError(t, configService.DeleteProfile("guild1", "raid calls"))
+	guildConfig, err := configService.GetGuildConfig("guild1")
+	require.NoError(t, err)
+	assert.Equal(t, "movie night", guildConfig.ActiveProfile, "deleting another profile keeps the active one")
+
+	// Deleting the active profile keeps its settings but clears the pointer
+	require.NoError(t, configService.DeleteProfile("guild1", "Movie Night"))
+	guildConfig, err = configService.GetGuildConfig("guild1")
+	require.NoError(t, err)
+	assert.Empty(t, guildConfig.ActiveProfile)
+	assert.Equal(t, "en-GB-Standard-A", guildConfig.TTSSettings.Voice)
+
+	assert.ErrorIs(t, configService.DeleteProfile("guild1", "movie night"), ErrProfileNotFound)
+}
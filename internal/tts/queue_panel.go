@@ -0,0 +1,508 @@
+package tts
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// QueuePanelNamespace is the component namespace of the buttons on queue panels. The bot
+// routes clicks on them to QueuePanel.HandleButton.
+const QueuePanelNamespace = "queue-panel"
+
+// Queue panel layout and refresh rate
+const (
+	QueuePanelPageSize = 10 // Queued messages shown per page
+
+	// QueuePanelRefreshInterval is how often panels are checked for changes. Discord
+	// allows about five message edits per channel every five seconds, so editing a
+	// panel at most every three seconds leaves room for the bot's other messages.
+	QueuePanelRefreshInterval = 3 * time.Second
+
+	queuePanelEntryLength = 100 // Longer queue entries are cut
+)
+
+// Queue panel button actions
+const (
+	queuePanelPrevious = "prev"
+	queuePanelNext     = "next"
+	queuePanelPause    = "pause"
+	queuePanelResume   = "resume"
+	queuePanelSkip     = "skip"
+)
+
+// Embed colours of queue panels
+const (
+	queuePanelColorPlaying = 0x57F287 // Green
+	queuePanelColorPaused  = 0xFEE75C // Yellow
+	queuePanelColorStopped = 0x95A5A6 // Grey
+)
+
+// QueuePanelMessenger posts, edits and deletes panel messages
+type QueuePanelMessenger interface {
+	ChannelMessageSendComplex(channelID string, data *discordgo.MessageSend, options ...discordgo.RequestOption) (*discordgo.Message, error)
+	ChannelMessageEditComplex(m *discordgo.MessageEdit, options ...discordgo.RequestOption) (*discordgo.Message, error)
+	ChannelMessageDelete(channelID, messageID string, options ...discordgo.RequestOption) error
+}
+
+// QueuePanel keeps an embed in a guild's paired text channel showing the message being
+// read, the queued messages and whether playback is paused, with buttons to page through
+// the queue, pause, resume and skip. Panels are edited in place by a refresher that only
+// edits panels whose content changed. When the bot leaves the voice channel the panel
+// is marked as stopped and its buttons are removed.
+type QueuePanel struct {
+	voiceManager      VoiceManager
+	messageQueue      MessageQueue
+	channelService    ChannelService
+	permissionService PermissionService
+	statsService      StatsService
+	mutePauser        *MutePauser
+	nowPlaying        NowPlayingReporter
+	messenger         QueuePanelMessenger
+	componentIDs      ComponentIDFactory
+	localizer         *Localizer
+	logger            *log.Logger
+
+	// respond answers button clicks; overridable for tests
+	respond func(s *discordgo.Session, i *discordgo.InteractionCreate, response *discordgo.InteractionResponse) error
+
+	mu     sync.Mutex
+	panels map[string]*guildPanel
+	stop   chan struct{}
+	done   chan struct{}
+}
+
+// guildPanel is the panel message of a guild
+type guildPanel struct {
+	channelID string
+	messageID string
+	page      int
+	shown     string // Fingerprint of the content last shown, so unchanged panels are not edited
+}
+
+// queuePanelView is the content of a panel message
+type queuePanelView struct {
+	Embed      *discordgo.MessageEmbed      `json:"embed"`
+	Components []discordgo.MessageComponent `json:"components"`
+	page       int
+}
+
+// NewQueuePanel creates a queue panel. Panels are shown without buttons until
+// SetComponentIDs is called.
+func NewQueuePanel(
+	voiceManager VoiceManager,
+	messageQueue MessageQueue,
+	channelService ChannelService,
+	permissionService PermissionService,
+	messenger QueuePanelMessenger,
+	logger *log.Logger,
+) *QueuePanel {
+	return &QueuePanel{
+		voiceManager:      voiceManager,
+		messageQueue:      messageQueue,
+		channelService:    channelService,
+		permissionService: permissionService,
+		messenger:         messenger,
+		logger:            logger,
+		respond: func(s *discordgo.Session, i *discordgo.InteractionCreate, response *discordgo.InteractionResponse) error {
+			return s.InteractionRespond(i.Interaction, response)
+		},
+		panels: make(map[string]*guildPanel),
+	}
+}
+
+// SetComponentIDs sets the factory for the custom IDs of the panel buttons
+func (p *QueuePanel) SetComponentIDs(componentIDs ComponentIDFactory) {
+	p.componentIDs = componentIDs
+}
+
+// SetLocalizer sets the localizer used to write panels in each guild's language
+func (p *QueuePanel) SetLocalizer(localizer *Localizer) {
+	p.localizer = localizer
+}
+
+// SetStatsService sets the stats service used to count skipped messages
+func (p *QueuePanel) SetStatsService(statsService StatsService) {
+	p.statsService = statsService
+}
+
+// SetMutePauser sets the mute pauser, so resuming while the bot is muted waits for unmute
+func (p *QueuePanel) SetMutePauser(mutePauser *MutePauser) {
+	p.mutePauser = mutePauser
+}
+
+// SetNowPlaying sets where panels find the message being read
+func (p *QueuePanel) SetNowPlaying(nowPlaying NowPlayingReporter) {
+	p.nowPlaying = nowPlaying
+}
+
+// Start starts refreshing panels every QueuePanelRefreshInterval
+func (p *QueuePanel) Start() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.stop != nil {
+		return fmt.Errorf("queue panel refresher is already running")
+	}
+	p.stop = make(chan struct{})
+	p.done = make(chan struct{})
+	go p.refreshLoop(p.stop, p.done)
+	return nil
+}
+
+// Stop stops refreshing panels. Panels keep their last content.
+func (p *QueuePanel) Stop() {
+	p.mu.Lock()
+	stop, done := p.stop, p.done
+	p.stop, p.done = nil, nil
+	p.mu.Unlock()
+
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+}
+
+// refreshLoop refreshes every panel until stop is closed
+func (p *QueuePanel) refreshLoop(stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(QueuePanelRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			p.RefreshAll()
+		}
+	}
+}
+
+// Show posts a panel in the text channel paired with the bot's voice channel in a guild
+// and returns the channel. A guild has one panel; showing it again moves it to the end
+// of the channel.
+func (p *QueuePanel) Show(guildID string) (string, error) {
+	connection, exists := p.voiceManager.GetConnection(guildID)
+	if !exists || connection == nil {
+		return "", fmt.Errorf("not connected to a voice channel")
+	}
+
+	pairing, err := p.channelService.GetPairing(guildID, connection.ChannelID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get channel pairing: %w", err)
+	}
+
+	view, shown := p.render(guildID, 0)
+	message, err := p.messenger.ChannelMessageSendComplex(pairing.TextChannelID, &discordgo.MessageSend{
+		Embeds:     []*discordgo.MessageEmbed{view.Embed},
+		Components: view.Components,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to post queue panel: %w", err)
+	}
+
+	p.mu.Lock()
+	previous := p.panels[guildID]
+	p.panels[guildID] = &guildPanel{channelID: pairing.TextChannelID, messageID: message.ID, shown: shown}
+	p.mu.Unlock()
+
+	if previous != nil {
+		if err := p.messenger.ChannelMessageDelete(previous.channelID, previous.messageID); err != nil {
+			p.logger.Printf("Failed to delete previous queue panel in guild %s: %v", guildID, err)
+		}
+	}
+	return pairing.TextChannelID, nil
+}
+
+// RefreshAll edits the panels whose content changed since they were last shown
+func (p *QueuePanel) RefreshAll() {
+	p.mu.Lock()
+	guildIDs := make([]string, 0, len(p.panels))
+	for guildID := range p.panels {
+		guildIDs = append(guildIDs, guildID)
+	}
+	p.mu.Unlock()
+
+	for _, guildID := range guildIDs {
+		p.refresh(guildID)
+	}
+}
+
+// refresh edits the panel of a guild if its content changed. Panels of guilds the bot
+// left are marked as stopped and forgotten.
+func (p *QueuePanel) refresh(guildID string) {
+	p.mu.Lock()
+	panel, exists := p.panels[guildID]
+	if !exists {
+		p.mu.Unlock()
+		return
+	}
+	page, lastShown := panel.page, panel.shown
+	p.mu.Unlock()
+
+	_, connected := p.voiceManager.GetConnection(guildID)
+	view, shown := p.render(guildID, page)
+	if connected && shown == lastShown {
+		return
+	}
+
+	edit := discordgo.NewMessageEdit(panel.channelID, panel.messageID).SetEmbeds([]*discordgo.MessageEmbed{view.Embed})
+	edit.Components = &view.Components
+	_, err := p.messenger.ChannelMessageEditComplex(edit)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.panels[guildID] != panel {
+		return // Replaced while it was being edited
+	}
+
+	var restErr *discordgo.RESTError
+	switch {
+	case !connected:
+		delete(p.panels, guildID)
+	case errors.As(err, &restErr) && restErr.Response != nil && restErr.Response.StatusCode == http.StatusNotFound:
+		p.logger.Printf("Queue panel in guild %s was deleted", guildID)
+		delete(p.panels, guildID)
+	case err != nil:
+		p.logger.Printf("Failed to refresh queue panel in guild %s: %v", guildID, err)
+	default:
+		panel.shown = shown
+		panel.page = view.page
+	}
+}
+
+// HandleButton handles a click on a panel button. Anyone can page through the queue;
+// pausing, resuming and skipping need permission to control the bot. The panel is
+// updated in the response, so the click shows its effect right away.
+func (p *QueuePanel) HandleButton(s *discordgo.Session, i *discordgo.InteractionCreate, action string) error {
+	guildID := i.GuildID
+	if guildID == "" {
+		return fmt.Errorf("queue panel button used outside a guild")
+	}
+
+	p.mu.Lock()
+	panel, exists := p.panels[guildID]
+	if !exists && i.Message != nil {
+		// Panels posted before a restart keep working
+		panel = &guildPanel{channelID: i.ChannelID, messageID: i.Message.ID}
+		p.panels[guildID] = panel
+	}
+	page := 0
+	if panel != nil {
+		page = panel.page
+	}
+	p.mu.Unlock()
+
+	switch action {
+	case queuePanelPrevious:
+		page--
+	case queuePanelNext:
+		page++
+	case queuePanelPause, queuePanelResume, queuePanelSkip:
+		if canControl, err := p.permissionService.CanControlBot(interactionUser(i), guildID); err != nil || !canControl {
+			return p.respondError(s, i, p.localizer.T(guildID, "queue_panel.not_allowed"))
+		}
+		if err := p.run(guildID, action); err != nil {
+			p.logger.Printf("Queue panel %s failed in guild %s: %v", action, guildID, err)
+			return p.respondError(s, i, p.localizer.T(guildID, "queue_panel.action_failed", err))
+		}
+	default:
+		return fmt.Errorf("unknown queue panel action %q", action)
+	}
+
+	view, shown := p.render(guildID, page)
+	p.mu.Lock()
+	if panel != nil && p.panels[guildID] == panel {
+		panel.page = view.page
+		panel.shown = shown
+	}
+	p.mu.Unlock()
+
+	return p.respond(s, i, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Embeds:     []*discordgo.MessageEmbed{view.Embed},
+			Components: view.Components,
+		},
+	})
+}
+
+// run pauses, resumes or skips playback in a guild like /darrot-control
+func (p *QueuePanel) run(guildID, action string) error {
+	if _, exists := p.voiceManager.GetConnection(guildID); !exists {
+		return fmt.Errorf("not connected to a voice channel")
+	}
+
+	control := playbackControl{p.voiceManager, p.messageQueue, p.statsService, p.mutePauser, p.logger}
+	switch action {
+	case queuePanelPause:
+		_, err := control.pause(guildID)
+		return err
+
+	case queuePanelResume:
+		_, _, err := control.resume(guildID)
+		return err
+
+	default:
+		_, err := control.skip(guildID)
+		return err
+	}
+}
+
+// render builds the panel of a guild showing a page of the queue, clamped to the pages
+// there are, and returns it with its fingerprint
+func (p *QueuePanel) render(guildID string, page int) (*queuePanelView, string) {
+	connection, connected := p.voiceManager.GetConnection(guildID)
+	if !connected || connection == nil {
+		view := &queuePanelView{
+			Embed: &discordgo.MessageEmbed{
+				Title:       p.localizer.T(guildID, "queue_panel.title"),
+				Description: p.localizer.T(guildID, "queue_panel.stopped"),
+				Color:       queuePanelColorStopped,
+			},
+			Components: []discordgo.MessageComponent{},
+		}
+		return view, ""
+	}
+
+	var queued []*QueuedMessage
+	if lister, ok := p.messageQueue.(QueueLister); ok {
+		queued = lister.List(guildID)
+	}
+	total := p.messageQueue.Size(guildID)
+	pages := max(1, (len(queued)+QueuePanelPageSize-1)/QueuePanelPageSize)
+	page = min(max(page, 0), pages-1)
+
+	var current *QueuedMessage
+	if p.nowPlaying != nil {
+		current = p.nowPlaying.NowPlaying(guildID)
+	}
+	paused := p.voiceManager.IsPaused(guildID)
+
+	var description strings.Builder
+	if paused {
+		description.WriteString(p.localizer.T(guildID, "queue_panel.paused") + "\n")
+	}
+	if current != nil {
+		description.WriteString(p.localizer.T(guildID, "queue_panel.now_playing", p.entry(guildID, current)))
+	} else {
+		description.WriteString(p.localizer.T(guildID, "queue_panel.idle"))
+	}
+	description.WriteString("\n\n")
+
+	start := page * QueuePanelPageSize
+	end := min(start+QueuePanelPageSize, len(queued))
+	if start >= end {
+		description.WriteString(p.localizer.T(guildID, "queue_panel.empty"))
+	}
+	for index := start; index < end; index++ {
+		fmt.Fprintf(&description, "`%d.` %s\n", index+1, p.entry(guildID, queued[index]))
+	}
+
+	color := queuePanelColorPlaying
+	if paused {
+		color = queuePanelColorPaused
+	}
+
+	view := &queuePanelView{
+		Embed: &discordgo.MessageEmbed{
+			Title:       p.localizer.T(guildID, "queue_panel.title"),
+			Description: strings.TrimSpace(description.String()),
+			Color:       color,
+			Footer: &discordgo.MessageEmbedFooter{
+				Text: p.localizer.T(guildID, "queue_panel.footer", page+1, pages, total),
+			},
+		},
+		Components: p.buttons(guildID, page, pages, paused, current != nil || total > 0),
+		page:       page,
+	}
+
+	fingerprint, err := json.Marshal(view)
+	if err != nil {
+		return view, ""
+	}
+	return view, string(fingerprint)
+}
+
+// buttons returns the button row of a panel, or no buttons without a component ID factory
+func (p *QueuePanel) buttons(guildID string, page, pages int, paused, skippable bool) []discordgo.MessageComponent {
+	if p.componentIDs == nil {
+		return []discordgo.MessageComponent{}
+	}
+
+	button := func(action, labelKey string, style discordgo.ButtonStyle, disabled bool) discordgo.MessageComponent {
+		customID, err := p.componentIDs.NewCustomID(QueuePanelNamespace, action, "", 0)
+		if err != nil {
+			p.logger.Printf("Failed to create queue panel button %s: %v", action, err)
+		}
+		return discordgo.Button{
+			Label:    p.localizer.T(guildID, labelKey),
+			Style:    style,
+			CustomID: customID,
+			Disabled: disabled,
+		}
+	}
+
+	pauseButton := button(queuePanelPause, "queue_panel.pause_button", discordgo.SecondaryButton, false)
+	if paused {
+		pauseButton = button(queuePanelResume, "queue_panel.resume_button", discordgo.SuccessButton, false)
+	}
+
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+			button(queuePanelPrevious, "queue_panel.previous_button", discordgo.SecondaryButton, page == 0),
+			pauseButton,
+			button(queuePanelSkip, "queue_panel.skip_button", discordgo.PrimaryButton, !skippable),
+			button(queuePanelNext, "queue_panel.next_button", discordgo.SecondaryButton, page >= pages-1),
+		}},
+	}
+}
+
+// entry describes a queued message on one line
+func (p *QueuePanel) entry(guildID string, message *QueuedMessage) string {
+	if message.ClipName != "" {
+		return p.localizer.T(guildID, "queue_panel.clip", message.ClipName)
+	}
+
+	text := strings.Join(strings.Fields(message.Content), " ")
+	if len(text) > queuePanelEntryLength {
+		text = cutText(text, queuePanelEntryLength)
+	}
+
+	// Messages queued through the HTTP API show where they came from
+	if message.Tag != "" {
+		return p.localizer.T(guildID, "queue_panel.tagged", message.Tag, text)
+	}
+	return text
+}
+
+// respondError answers a button click with a message only its user can see
+func (p *QueuePanel) respondError(s *discordgo.Session, i *discordgo.InteractionCreate, message string) error {
+	return p.respond(s, i, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: "❌ " + message,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
+
+// interactionUser returns the user who triggered an interaction
+func interactionUser(i *discordgo.InteractionCreate) string {
+	if i.Member != nil && i.Member.User != nil {
+		return i.Member.User.ID
+	}
+	if i.User != nil {
+		return i.User.ID
+	}
+	return ""
+}
@@ -0,0 +1,296 @@
+package tts
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockQueuePanelMessenger records the panel messages a QueuePanel posts, edits and deletes
+type mockQueuePanelMessenger struct {
+	mu      sync.Mutex
+	sent    []*discordgo.MessageSend
+	edits   []*discordgo.MessageEdit
+	deleted []string
+	editErr error
+}
+
+func (m *mockQueuePanelMessenger) ChannelMessageSendComplex(channelID string, data *discordgo.MessageSend, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sent = append(m.sent, data)
+	return &discordgo.Message{ID: fmt.Sprintf("panel%d", len(m.sent)), ChannelID: channelID}, nil
+}
+
+func (m *mockQueuePanelMessenger) ChannelMessageEditComplex(edit *discordgo.MessageEdit, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.editErr != nil {
+		return nil, m.editErr
+	}
+	m.edits = append(m.edits, edit)
+	return &discordgo.Message{ID: edit.ID, ChannelID: edit.Channel}, nil
+}
+
+func (m *mockQueuePanelMessenger) ChannelMessageDelete(channelID, messageID string, options ...discordgo.RequestOption) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.deleted = append(m.deleted, messageID)
+	return nil
+}
+
+// mockNowPlaying reports a fixed message as being read
+type mockNowPlaying struct {
+	message *QueuedMessage
+}
+
+func (m *mockNowPlaying) NowPlaying(guildID string) *QueuedMessage {
+	return m.message
+}
+
+type queuePanelTestEnv struct {
+	panel        *QueuePanel
+	voiceManager *mockVoiceManager
+	queue        MessageQueue
+	permissions  *mockPermissionServiceIntegration
+	messenger    *mockQueuePanelMessenger
+	nowPlaying   *mockNowPlaying
+	responses    []*discordgo.InteractionResponse
+}
+
+func setupQueuePanelTest(t *testing.T) *queuePanelTestEnv {
+	env := &queuePanelTestEnv{
+		voiceManager: newMockVoiceManager(),
+		queue:        NewMessageQueue(),
+		permissions:  newMockPermissionServiceIntegration(),
+		messenger:    &mockQueuePanelMessenger{},
+		nowPlaying:   &mockNowPlaying{},
+	}
+
+	channelService := newMockChannelServiceIntegration()
+	require.NoError(t, channelService.CreatePairing("guild1", "voice1", "text1"))
+	_, err := env.voiceManager.JoinChannel("guild1", "voice1")
+	require.NoError(t, err)
+
+	env.panel = NewQueuePanel(env.voiceManager, env.queue, channelService, env.permissions, env.messenger, log.New(io.Discard, "", 0))
+	env.panel.SetComponentIDs(mockComponentIDs{})
+	env.panel.SetNowPlaying(env.nowPlaying)
+	env.panel.respond = func(s *discordgo.Session, i *discordgo.InteractionCreate, response *discordgo.InteractionResponse) error {
+		env.responses = append(env.responses, response)
+		return nil
+	}
+	return env
+}
+
+func (env *queuePanelTestEnv) enqueue(t *testing.T, count int) {
+	t.Helper()
+	for n := 1; n <= count; n++ {
+		require.NoError(t, env.queue.SetMaxSize("guild1", 50))
+		require.NoError(t, env.queue.Enqueue(&QueuedMessage{ID: fmt.Sprintf("m%d", n), GuildID: "guild1", Content: fmt.Sprintf("alice says: message %d", n), Timestamp: time.Now()}))
+	}
+}
+
+func (env *queuePanelTestEnv) click(action, userID string) *discordgo.InteractionCreate {
+	return &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+		Type:      discordgo.InteractionMessageComponent,
+		GuildID:   "guild1",
+		ChannelID: "text1",
+		Member:    &discordgo.Member{User: &discordgo.User{ID: userID}},
+		Message:   &discordgo.Message{ID: "panel1"},
+		Data:      discordgo.MessageComponentInteractionData{CustomID: QueuePanelNamespace + "//" + action},
+	}}
+}
+
+// panelButtons returns the buttons of a panel by custom ID
+func panelButtons(components []discordgo.MessageComponent) map[string]discordgo.Button {
+	buttons := make(map[string]discordgo.Button)
+	for _, component := range components {
+		row, ok := component.(discordgo.ActionsRow)
+		if !ok {
+			continue
+		}
+		for _, rowComponent := range row.Components {
+			if button, ok := rowComponent.(discordgo.Button); ok {
+				buttons[button.CustomID] = button
+			}
+		}
+	}
+	return buttons
+}
+
+func TestQueuePanel_ShowPostsPanelInPairedChannel(t *testing.T) {
+	env := setupQueuePanelTest(t)
+	env.nowPlaying.message = &QueuedMessage{Content: "bob says: hello"}
+	env.enqueue(t, 12)
+
+	channelID, err := env.panel.Show("guild1")
+	require.NoError(t, err)
+	assert.Equal(t, "text1", channelID)
+
+	require.Len(t, env.messenger.sent, 1)
+	embed := env.messenger.sent[0].Embeds[0]
+	assert.Contains(t, embed.Description, "bob says: hello")
+	assert.Contains(t, embed.Description, "`1.` alice says: message 1")
+	assert.Contains(t, embed.Description, "`10.` alice says: message 10")
+	assert.NotContains(t, embed.Description, "message 11", "later messages are on the next page")
+	assert.Equal(t, "Page 1 of 2 · 12 message(s) queued", embed.Footer.Text)
+
+	buttons := panelButtons(env.messenger.sent[0].Components)
+	assert.True(t, buttons[QueuePanelNamespace+"//prev"].Disabled)
+	assert.False(t, buttons[QueuePanelNamespace+"//next"].Disabled)
+	assert.Contains(t, buttons, QueuePanelNamespace+"//pause")
+
+	// Showing the panel again replaces it
+	_, err = env.panel.Show("guild1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"panel1"}, env.messenger.deleted)
+}
+
+func TestQueuePanel_ShowsTagOfAPIMessages(t *testing.T) {
+	env := setupQueuePanelTest(t)
+	env.nowPlaying.message = &QueuedMessage{Content: "Wave 5 incoming", Tag: "overlay"}
+
+	_, err := env.panel.Show("guild1")
+	require.NoError(t, err)
+
+	require.Len(t, env.messenger.sent, 1)
+	assert.Contains(t, env.messenger.sent[0].Embeds[0].Description, "🔌 `overlay` Wave 5 incoming")
+}
+
+func TestQueuePanel_ShowRequiresVoiceConnection(t *testing.T) {
+	env := setupQueuePanelTest(t)
+
+	_, err := env.panel.Show("other")
+	assert.Error(t, err)
+	assert.Empty(t, env.messenger.sent)
+}
+
+func TestQueuePanel_RefreshOnlyEditsChangedPanels(t *testing.T) {
+	env := setupQueuePanelTest(t)
+	_, err := env.panel.Show("guild1")
+	require.NoError(t, err)
+
+	env.panel.RefreshAll()
+	assert.Empty(t, env.messenger.edits, "unchanged panels are not edited")
+
+	env.enqueue(t, 1)
+	env.panel.RefreshAll()
+	require.Len(t, env.messenger.edits, 1)
+	assert.Equal(t, "panel1", env.messenger.edits[0].ID)
+	assert.Contains(t, (*env.messenger.edits[0].Embeds)[0].Description, "alice says: message 1")
+
+	env.panel.RefreshAll()
+	assert.Len(t, env.messenger.edits, 1)
+}
+
+func TestQueuePanel_StopsWhenBotLeaves(t *testing.T) {
+	env := setupQueuePanelTest(t)
+	_, err := env.panel.Show("guild1")
+	require.NoError(t, err)
+
+	require.NoError(t, env.voiceManager.LeaveChannel("guild1"))
+	env.panel.RefreshAll()
+
+	require.Len(t, env.messenger.edits, 1)
+	assert.Empty(t, *env.messenger.edits[0].Components, "buttons are removed")
+	assert.Equal(t, queuePanelColorStopped, (*env.messenger.edits[0].Embeds)[0].Color)
+
+	env.panel.RefreshAll()
+	assert.Len(t, env.messenger.edits, 1, "stopped panels are forgotten")
+}
+
+func TestQueuePanel_DeletedPanelIsForgotten(t *testing.T) {
+	env := setupQueuePanelTest(t)
+	_, err := env.panel.Show("guild1")
+	require.NoError(t, err)
+
+	env.messenger.editErr = &discordgo.RESTError{Response: &http.Response{StatusCode: http.StatusNotFound}}
+	env.enqueue(t, 1)
+	env.panel.RefreshAll()
+
+	env.panel.mu.Lock()
+	defer env.panel.mu.Unlock()
+	assert.Empty(t, env.panel.panels)
+}
+
+func TestQueuePanel_PagingButtons(t *testing.T) {
+	env := setupQueuePanelTest(t)
+	env.enqueue(t, 12)
+	_, err := env.panel.Show("guild1")
+	require.NoError(t, err)
+
+	// Anyone can page through the queue
+	require.NoError(t, env.panel.HandleButton(nil, env.click(queuePanelNext, "user1"), queuePanelNext))
+	require.Len(t, env.responses, 1)
+	assert.Equal(t, discordgo.InteractionResponseUpdateMessage, env.responses[0].Type)
+	embed := env.responses[0].Data.Embeds[0]
+	assert.Contains(t, embed.Description, "`11.` alice says: message 11")
+	assert.Equal(t, "Page 2 of 2 · 12 message(s) queued", embed.Footer.Text)
+
+	// Paging past the end stays on the last page
+	require.NoError(t, env.panel.HandleButton(nil, env.click(queuePanelNext, "user1"), queuePanelNext))
+	assert.Equal(t, "Page 2 of 2 · 12 message(s) queued", env.responses[1].Data.Embeds[0].Footer.Text)
+
+	require.NoError(t, env.panel.HandleButton(nil, env.click(queuePanelPrevious, "user1"), queuePanelPrevious))
+	assert.Equal(t, "Page 1 of 2 · 12 message(s) queued", env.responses[2].Data.Embeds[0].Footer.Text)
+}
+
+func TestQueuePanel_PlaybackButtons(t *testing.T) {
+	env := setupQueuePanelTest(t)
+	env.enqueue(t, 2)
+	_, err := env.panel.Show("guild1")
+	require.NoError(t, err)
+
+	// Members who cannot control the bot are turned away
+	require.NoError(t, env.panel.HandleButton(nil, env.click(queuePanelPause, "user1"), queuePanelPause))
+	require.Len(t, env.responses, 1)
+	assert.Equal(t, discordgo.MessageFlagsEphemeral, env.responses[0].Data.Flags)
+	assert.False(t, env.voiceManager.IsPaused("guild1"))
+
+	env.permissions.setCanControlBot("user1", "guild1", true)
+	require.NoError(t, env.panel.HandleButton(nil, env.click(queuePanelPause, "user1"), queuePanelPause))
+	assert.True(t, env.voiceManager.IsPaused("guild1"))
+	assert.Contains(t, panelButtons(env.responses[1].Data.Components), QueuePanelNamespace+"//resume", "paused panels offer resume")
+	assert.Equal(t, queuePanelColorPaused, env.responses[1].Data.Embeds[0].Color)
+
+	require.NoError(t, env.panel.HandleButton(nil, env.click(queuePanelResume, "user1"), queuePanelResume))
+	assert.False(t, env.voiceManager.IsPaused("guild1"))
+
+	require.NoError(t, env.panel.HandleButton(nil, env.click(queuePanelSkip, "user1"), queuePanelSkip))
+	assert.Equal(t, 1, env.queue.Size("guild1"))
+	assert.Contains(t, env.voiceManager.getCallLog(), "SkipCurrentMessage")
+}
+
+func TestQueuePanel_AdoptsPanelsFromBeforeRestart(t *testing.T) {
+	env := setupQueuePanelTest(t)
+
+	require.NoError(t, env.panel.HandleButton(nil, env.click(queuePanelNext, "user1"), queuePanelNext))
+
+	env.enqueue(t, 1)
+	env.panel.RefreshAll()
+	require.Len(t, env.messenger.edits, 1)
+	assert.Equal(t, "panel1", env.messenger.edits[0].ID)
+	assert.Equal(t, "text1", env.messenger.edits[0].Channel)
+}
+
+func TestMessageQueue_List(t *testing.T) {
+	queue := NewMessageQueue()
+	require.NoError(t, queue.Enqueue(&QueuedMessage{ID: "low", GuildID: "guild1", Content: "announcement", Priority: PriorityLow, Timestamp: time.Now()}))
+	require.NoError(t, queue.Enqueue(&QueuedMessage{ID: "stale", GuildID: "guild1", Content: "old", Priority: PriorityLow, Timestamp: time.Now().Add(-2 * LowPriorityMaxAge)}))
+	require.NoError(t, queue.Enqueue(&QueuedMessage{ID: "m1", GuildID: "guild1", Content: "hello", Timestamp: time.Now()}))
+
+	listed := queue.(QueueLister).List("guild1")
+	require.Len(t, listed, 2)
+	assert.Equal(t, "m1", listed[0].ID, "messages are listed in the order they are read")
+	assert.Equal(t, "low", listed[1].ID)
+	assert.Equal(t, 3, queue.Size("guild1"), "listing does not remove messages")
+	assert.Empty(t, queue.(QueueLister).List("other"))
+}
@@ -0,0 +1,117 @@
+package tts
+
+import (
+	"log"
+	"strings"
+	"sync"
+)
+
+// Queue pressure metrics
+const (
+	MetricQueuePressureActivations = "darrot_tts_queue_pressure_activations_total"
+	MetricQueuePressureActive      = "darrot_tts_queue_pressure_active"
+)
+
+// QueuePressurePolicy speeds up reading while a guild's queue is backed up. Once more than
+// threshold messages are waiting, messages are read halfway between their normal speed and
+// maxSpeed, and at maxSpeed past twice the threshold, with "Alice says:" shortened to
+// "Alice:". The guild returns to normal once its backlog falls to half the threshold, so
+// a queue hovering around the threshold does not switch back and forth. The speed changes
+// in steps rather than with every message, which keeps audio synthesized ahead usable. A
+// nil *QueuePressurePolicy leaves messages unchanged.
+type QueuePressurePolicy struct {
+	threshold int
+	maxSpeed  float32
+	metrics   *Metrics
+	logger    *log.Logger
+
+	mu     sync.Mutex
+	active map[string]bool // Guilds reading faster to catch up
+}
+
+// NewQueuePressurePolicy creates a policy speeding up guilds with more than threshold
+// queued messages, up to maxSpeed. A threshold of 0 turns it off. metrics may be nil.
+func NewQueuePressurePolicy(threshold int, maxSpeed float32, metrics *Metrics, logger *log.Logger) *QueuePressurePolicy {
+	if threshold <= 0 {
+		return nil
+	}
+
+	if metrics != nil {
+		metrics.Describe(MetricQueuePressureActivations, MetricTypeCounter, "Times the guild's backlog made the bot read faster to catch up")
+		metrics.Describe(MetricQueuePressureActive, MetricTypeGauge, "Whether the guild is reading faster to catch up with its backlog (1) or not (0)")
+	}
+
+	return &QueuePressurePolicy{
+		threshold: threshold,
+		maxSpeed:  maxSpeed,
+		metrics:   metrics,
+		logger:    logger,
+		active:    make(map[string]bool),
+	}
+}
+
+// Apply returns the voice settings and text to read a message with while backlog messages
+// wait in its guild's queue
+func (p *QueuePressurePolicy) Apply(guildID string, backlog int, config TTSConfig, message *QueuedMessage) (TTSConfig, string) {
+	text := message.Content
+	if p == nil || !p.update(guildID, backlog) {
+		return config, text
+	}
+
+	if config.Speed < p.maxSpeed {
+		if backlog > 2*p.threshold {
+			config.Speed = p.maxSpeed
+		} else {
+			config.Speed += (p.maxSpeed - config.Speed) / 2
+		}
+	}
+
+	if message.Lead != "" && strings.HasPrefix(text, message.Lead+" ") {
+		text = shortLead(message.Lead) + strings.TrimPrefix(text, message.Lead)
+	}
+	return config, text
+}
+
+// Active reports whether a guild is reading faster to catch up with its backlog
+func (p *QueuePressurePolicy) Active(guildID string) bool {
+	if p == nil {
+		return false
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.active[guildID]
+}
+
+// update switches a guild to catching up or back for its backlog and reports whether it
+// is catching up
+func (p *QueuePressurePolicy) update(guildID string, backlog int) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	active := p.active[guildID]
+	switch {
+	case !active && backlog > p.threshold:
+		p.active[guildID] = true
+		p.logger.Printf("Reading faster in guild %s to catch up with %d queued messages", guildID, backlog)
+		if p.metrics != nil {
+			p.metrics.IncCounter(MetricQueuePressureActivations, Labels{"guild": guildID})
+			p.metrics.SetGauge(MetricQueuePressureActive, Labels{"guild": guildID}, 1)
+		}
+		return true
+
+	case active && backlog <= p.threshold/2:
+		delete(p.active, guildID)
+		p.logger.Printf("Reading at normal speed again in guild %s", guildID)
+		if p.metrics != nil {
+			p.metrics.SetGauge(MetricQueuePressureActive, Labels{"guild": guildID}, 0)
+		}
+		return false
+	}
+	return active
+}
+
+// shortLead shortens a lead such as "Alice says:" to "Alice:"
+func shortLead(lead string) string {
+	return strings.TrimSuffix(lead, " says:") + ":"
+}
@@ -0,0 +1,88 @@
+package tts
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestQueuePressurePolicy(metrics *Metrics) *QueuePressurePolicy {
+	return NewQueuePressurePolicy(4, 2.0, metrics, log.New(io.Discard, "", 0))
+}
+
+func TestQueuePressurePolicy_CatchesUp(t *testing.T) {
+	metrics := NewMetrics()
+	policy := newTestQueuePressurePolicy(metrics)
+	message := &QueuedMessage{Content: "Alice says: hello there", Lead: "Alice says:"}
+	config := TTSConfig{Voice: DefaultVoice, Speed: 1.0, Volume: 1.0}
+
+	// At the threshold messages are read as usual
+	adjusted, text := policy.Apply("guild1", 4, config, message)
+	assert.Equal(t, config, adjusted)
+	assert.Equal(t, "Alice says: hello there", text)
+	assert.False(t, policy.Active("guild1"))
+
+	adjusted, text = policy.Apply("guild1", 5, config, message)
+	assert.Equal(t, float32(1.5), adjusted.Speed)
+	assert.Equal(t, "Alice: hello there", text)
+	assert.True(t, policy.Active("guild1"))
+
+	adjusted, _ = policy.Apply("guild1", 9, config, message)
+	assert.Equal(t, float32(2.0), adjusted.Speed)
+
+	// The guild keeps catching up until half the threshold is left
+	adjusted, _ = policy.Apply("guild1", 3, config, message)
+	assert.Equal(t, float32(1.5), adjusted.Speed)
+	adjusted, text = policy.Apply("guild1", 2, config, message)
+	assert.Equal(t, config, adjusted)
+	assert.Equal(t, "Alice says: hello there", text)
+	assert.False(t, policy.Active("guild1"))
+
+	policy.Apply("guild1", 5, config, message)
+	labels := Labels{"guild": "guild1"}
+	assert.Equal(t, 2.0, metrics.Value(MetricQueuePressureActivations, labels))
+	assert.Equal(t, 1.0, metrics.Value(MetricQueuePressureActive, labels))
+}
+
+func TestQueuePressurePolicy_KeepsFasterSpeeds(t *testing.T) {
+	policy := newTestQueuePressurePolicy(nil)
+	message := &QueuedMessage{Content: "Deploy finished"}
+
+	// Messages without a lead, such as from the HTTP API, keep their text
+	adjusted, text := policy.Apply("guild1", 10, TTSConfig{Speed: 3.0}, message)
+	assert.Equal(t, float32(3.0), adjusted.Speed)
+	assert.Equal(t, "Deploy finished", text)
+}
+
+func TestQueuePressurePolicy_Off(t *testing.T) {
+	policy := NewQueuePressurePolicy(0, 2.0, nil, log.New(io.Discard, "", 0))
+	assert.Nil(t, policy)
+
+	message := &QueuedMessage{Content: "Alice says: hi", Lead: "Alice says:"}
+	adjusted, text := policy.Apply("guild1", 50, TTSConfig{Speed: 1.0}, message)
+	assert.Equal(t, float32(1.0), adjusted.Speed)
+	assert.Equal(t, "Alice says: hi", text)
+	assert.False(t, policy.Active("guild1"))
+}
+
+func TestTTSProcessor_QueuePressure(t *testing.T) {
+	queue := NewMessageQueue()
+	require.NoError(t, queue.SetMaxSize("guild1", 20))
+	processor := NewTTSProcessor(&mockTTSManager{}, newMockVoiceManager(), queue, newMockConfigService(), newMockUserService()).(*ttsProcessor)
+	processor.SetQueuePressurePolicy(newTestQueuePressurePolicy(nil))
+
+	for i := 0; i < 10; i++ {
+		require.NoError(t, queue.Enqueue(&QueuedMessage{ID: fmt.Sprintf("m%d", i), GuildID: "guild1", Content: fmt.Sprintf("Message %d", i), Timestamp: time.Now()}))
+	}
+
+	message := &QueuedMessage{GuildID: "guild1", Content: "Bob says: catch up", Lead: "Bob says:"}
+	config, moderated, ok := processor.prepareSpeech("guild1", message)
+	require.True(t, ok)
+	assert.Equal(t, float32(2.0), config.Speed)
+	assert.Equal(t, "Bob: catch up", moderated.Text)
+}
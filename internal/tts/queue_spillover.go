@@ -0,0 +1,272 @@
+package tts
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+// Queue spillover metrics
+const (
+	MetricQueueSpilledTotal      = "darrot_queue_spilled_total"
+	MetricQueueSpillRejected     = "darrot_queue_spill_rejected_total"
+	MetricQueueSpilloverMessages = "darrot_queue_spillover_messages"
+	MetricQueueSpilloverBytes    = "darrot_queue_spillover_bytes"
+)
+
+// queueSpillover keeps the messages a full queue would drop in a segment file per guild,
+// so a long burst is read out in full instead of losing its oldest messages. Spilled
+// messages are always newer than the ones in memory and are moved back in order as
+// the queue makes room. The segment files together never take more than maxBytes; once
+// they would, the queue drops messages as it does without spillover. Callers hold the
+// queue's lock.
+type queueSpillover struct {
+	dir      string
+	maxBytes int64
+	used     int64 // Size of all segment files, including messages already read back
+	segments map[string]*spillSegment
+	cipher   *StorageCipher // Nil writes messages unencrypted
+	metrics  *Metrics
+}
+
+// spillSegment is a guild's segment file, one JSON message per line. With a cipher, each
+// line is the message encrypted the way stored files are, bound to the segment's name so
+// a message cannot be moved to another guild's segment. It is read from the front and
+// removed once empty.
+type spillSegment struct {
+	file    *os.File
+	read    int64 // Offset of the oldest message still waiting
+	written int64 // Offset the next message is written at
+	lengths []int // Line length of each waiting message, oldest first
+}
+
+// newQueueSpillover creates a spillover in dir, removing the segments of a previous
+// run: their messages were never going to be read in time. Messages are encrypted with
+// cipher unless it is nil.
+func newQueueSpillover(dir string, maxBytes int64, cipher *StorageCipher, metrics *Metrics) (*queueSpillover, error) {
+	if err := os.RemoveAll(dir); err != nil {
+		return nil, fmt.Errorf("failed to remove old queue spillover: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create queue spillover directory: %w", err)
+	}
+
+	if metrics != nil {
+		metrics.Describe(MetricQueueSpilledTotal, MetricTypeCounter, "Messages written to disk because the guild's queue was full")
+		metrics.Describe(MetricQueueSpillRejected, MetricTypeCounter, "Messages dropped because the queue spillover was full")
+		metrics.Describe(MetricQueueSpilloverMessages, MetricTypeGauge, "Messages waiting on disk")
+		metrics.Describe(MetricQueueSpilloverBytes, MetricTypeGauge, "Disk space used by queue spillover segments")
+		metrics.SetGauge(MetricQueueSpilloverBytes, nil, 0)
+	}
+
+	return &queueSpillover{
+		dir:      dir,
+		maxBytes: maxBytes,
+		segments: make(map[string]*spillSegment),
+		cipher:   cipher,
+		metrics:  metrics,
+	}, nil
+}
+
+// count returns the number of a guild's messages waiting on disk
+func (s *queueSpillover) count(guildID string) int {
+	if s == nil {
+		return 0
+	}
+	if segment, ok := s.segments[guildID]; ok {
+		return len(segment.lengths)
+	}
+	return 0
+}
+
+// spill writes a message to the end of its guild's segment. It reports false when the
+// message does not fit within maxBytes or cannot be written.
+func (s *queueSpillover) spill(message *QueuedMessage) bool {
+	data, err := s.encode(message)
+	if err != nil {
+		log.Printf("Warning: Failed to spill queued message for guild %s: %v", message.GuildID, err)
+		return false
+	}
+	line := append(data, '\n')
+
+	if s.used+int64(len(line)) > s.maxBytes {
+		s.compact()
+	}
+	if s.used+int64(len(line)) > s.maxBytes {
+		s.record(MetricQueueSpillRejected, message.GuildID)
+		return false
+	}
+
+	segment, err := s.segment(message.GuildID)
+	if err != nil {
+		log.Printf("Warning: Failed to spill queued message for guild %s: %v", message.GuildID, err)
+		return false
+	}
+	if _, err := segment.file.WriteAt(line, segment.written); err != nil {
+		log.Printf("Warning: Failed to spill queued message for guild %s: %v", message.GuildID, err)
+		return false
+	}
+
+	segment.written += int64(len(line))
+	segment.lengths = append(segment.lengths, len(line))
+	s.used += int64(len(line))
+	s.record(MetricQueueSpilledTotal, message.GuildID)
+	s.updateGauges(message.GuildID)
+	return true
+}
+
+// pop removes and returns a guild's oldest message on disk, or nil when it has none.
+// Messages that cannot be read back are skipped.
+func (s *queueSpillover) pop(guildID string) *QueuedMessage {
+	segment, ok := s.segments[guildID]
+	if !ok {
+		return nil
+	}
+	defer s.updateGauges(guildID)
+
+	for len(segment.lengths) > 0 {
+		line := make([]byte, segment.lengths[0])
+		_, err := segment.file.ReadAt(line, segment.read)
+		segment.lengths = segment.lengths[1:]
+		segment.read += int64(len(line))
+		if len(segment.lengths) == 0 {
+			s.discard(guildID)
+		}
+		if err != nil {
+			log.Printf("Warning: Failed to read spilled message for guild %s: %v", guildID, err)
+			continue
+		}
+
+		message, err := s.decode(guildID, line)
+		if err != nil {
+			log.Printf("Warning: Failed to read spilled message for guild %s: %v", guildID, err)
+			continue
+		}
+		return message
+	}
+	return nil
+}
+
+// encode returns the line written for a message, without its newline
+func (s *queueSpillover) encode(message *QueuedMessage) ([]byte, error) {
+	data, err := json.Marshal(message)
+	if err != nil || s.cipher == nil {
+		return data, err
+	}
+
+	sealed, err := s.cipher.encrypt(s.segmentPath(message.GuildID), data)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(sealed)
+}
+
+// decode returns the message held by a line of a guild's segment
+func (s *queueSpillover) decode(guildID string, line []byte) (*QueuedMessage, error) {
+	data := line
+	if s.cipher != nil {
+		sealed, ok := parseEncryptedFile(line)
+		if !ok {
+			return nil, errors.New("message is not encrypted")
+		}
+		var err error
+		if data, err = s.cipher.open(s.segmentPath(guildID), sealed); err != nil {
+			return nil, err
+		}
+	}
+
+	var message QueuedMessage
+	if err := json.Unmarshal(data, &message); err != nil {
+		return nil, err
+	}
+	return &message, nil
+}
+
+// discard removes a guild's segment and the messages in it
+func (s *queueSpillover) discard(guildID string) {
+	if s == nil {
+		return
+	}
+	segment, ok := s.segments[guildID]
+	if !ok {
+		return
+	}
+
+	delete(s.segments, guildID)
+	s.used -= segment.written
+	segment.file.Close()
+	if err := os.Remove(segment.file.Name()); err != nil && !os.IsNotExist(err) {
+		log.Printf("Warning: Failed to remove queue spillover for guild %s: %v", guildID, err)
+	}
+	s.updateGauges(guildID)
+}
+
+// segment returns a guild's segment, creating its file on first use
+func (s *queueSpillover) segment(guildID string) (*spillSegment, error) {
+	if segment, ok := s.segments[guildID]; ok {
+		return segment, nil
+	}
+
+	file, err := os.OpenFile(s.segmentPath(guildID), os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return nil, err
+	}
+	segment := &spillSegment{file: file}
+	s.segments[guildID] = segment
+	return segment, nil
+}
+
+// segmentPath returns the path of a guild's segment file
+func (s *queueSpillover) segmentPath(guildID string) string {
+	return filepath.Join(s.dir, fmt.Sprintf("queue_%s.jsonl", guildID))
+}
+
+// compact moves the waiting messages of every segment to the start of its file,
+// giving back the space of the messages already read
+func (s *queueSpillover) compact() {
+	for guildID, segment := range s.segments {
+		if segment.read == 0 {
+			continue
+		}
+
+		waiting := make([]byte, segment.written-segment.read)
+		if _, err := segment.file.ReadAt(waiting, segment.read); err != nil {
+			log.Printf("Warning: Failed to compact queue spillover for guild %s: %v", guildID, err)
+			continue
+		}
+		if _, err := segment.file.WriteAt(waiting, 0); err != nil {
+			log.Printf("Warning: Failed to compact queue spillover for guild %s: %v", guildID, err)
+			continue
+		}
+		if err := segment.file.Truncate(int64(len(waiting))); err != nil {
+			log.Printf("Warning: Failed to compact queue spillover for guild %s: %v", guildID, err)
+			continue
+		}
+
+		s.used -= segment.read
+		segment.written -= segment.read
+		segment.read = 0
+	}
+	if s.metrics != nil {
+		s.metrics.SetGauge(MetricQueueSpilloverBytes, nil, float64(s.used))
+	}
+}
+
+// record counts a spilled or rejected message
+func (s *queueSpillover) record(name, guildID string) {
+	if s.metrics != nil {
+		s.metrics.IncCounter(name, Labels{"guild": guildID})
+	}
+}
+
+// updateGauges records the messages a guild has waiting on disk and the space used by
+// every segment
+func (s *queueSpillover) updateGauges(guildID string) {
+	if s.metrics != nil {
+		s.metrics.SetGauge(MetricQueueSpilloverMessages, Labels{"guild": guildID}, float64(s.count(guildID)))
+		s.metrics.SetGauge(MetricQueueSpilloverBytes, nil, float64(s.used))
+	}
+}
@@ -0,0 +1,355 @@
+package tts
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	// Time zones are embedded, since the container image has no zoneinfo database
+	_ "time/tzdata"
+)
+
+// QuietHoursCheckInterval is how often the pause scheduler looks for guilds whose quiet
+// hours began or ended
+const QuietHoursCheckInterval = 30 * time.Second
+
+// MaxPauseMinutes is the longest /darrot-control pause-for can pause playback
+const MaxPauseMinutes = 240
+
+// clockLayout is how quiet hours are written, as 24-hour times of day
+const clockLayout = "15:04"
+
+// QuietHours is the daily window in which a guild's bot does not speak. A window whose
+// end is before its start runs past midnight, such as 23:00 to 08:00.
+type QuietHours struct {
+	Start    time.Duration // Since midnight
+	End      time.Duration // Since midnight
+	Location *time.Location
+}
+
+// ParseQuietHours parses quiet hours written as HH:MM in an IANA time zone. An empty
+// time zone is UTC.
+func ParseQuietHours(start, end, timezone string) (QuietHours, error) {
+	startTime, err := parseClock(start)
+	if err != nil {
+		return QuietHours{}, fmt.Errorf("start %w", err)
+	}
+	endTime, err := parseClock(end)
+	if err != nil {
+		return QuietHours{}, fmt.Errorf("end %w", err)
+	}
+	if startTime == endTime {
+		return QuietHours{}, errors.New("start and end must differ")
+	}
+
+	location, err := LoadTimezone(timezone)
+	if err != nil {
+		return QuietHours{}, err
+	}
+	return QuietHours{Start: startTime, End: endTime, Location: location}, nil
+}
+
+// LoadTimezone returns the location of an IANA time zone name, UTC when it is empty
+func LoadTimezone(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil || name == "Local" {
+		return nil, fmt.Errorf("unknown time zone %q", name)
+	}
+	return location, nil
+}
+
+// QuietHoursFor returns the quiet hours of a guild configuration. It reports false when
+// the guild has none.
+func QuietHoursFor(config *GuildTTSConfig) (QuietHours, bool) {
+	if config == nil || config.QuietHoursStart == "" || config.QuietHoursEnd == "" {
+		return QuietHours{}, false
+	}
+	hours, err := ParseQuietHours(config.QuietHoursStart, config.QuietHoursEnd, config.Timezone)
+	if err != nil {
+		return QuietHours{}, false
+	}
+	return hours, true
+}
+
+// Contains reports whether t falls within the quiet hours
+func (q QuietHours) Contains(t time.Time) bool {
+	local := t.In(q.Location)
+	clock := time.Duration(local.Hour())*time.Hour + time.Duration(local.Minute())*time.Minute + time.Duration(local.Second())*time.Second
+	if q.Start < q.End {
+		return clock >= q.Start && clock < q.End
+	}
+	return clock >= q.Start || clock < q.End
+}
+
+// EndAfter returns when the quiet hours next end after t
+func (q QuietHours) EndAfter(t time.Time) time.Time {
+	local := t.In(q.Location)
+	hour, minute := int(q.End/time.Hour), int(q.End%time.Hour/time.Minute)
+
+	end := time.Date(local.Year(), local.Month(), local.Day(), hour, minute, 0, 0, q.Location)
+	if !end.After(t) {
+		end = time.Date(local.Year(), local.Month(), local.Day()+1, hour, minute, 0, 0, q.Location)
+	}
+	return end
+}
+
+// String returns the quiet hours as shown to users, such as "23:00–08:00 (Europe/Berlin)"
+func (q QuietHours) String() string {
+	return fmt.Sprintf("%s–%s (%s)", formatClock(q.Start), formatClock(q.End), q.Location)
+}
+
+// parseClock parses a time of day written as HH:MM
+func parseClock(value string) (time.Duration, error) {
+	parsed, err := time.Parse(clockLayout, value)
+	if err != nil {
+		return 0, fmt.Errorf("must be a time such as 23:00, got %q", value)
+	}
+	return time.Duration(parsed.Hour())*time.Hour + time.Duration(parsed.Minute())*time.Minute, nil
+}
+
+// formatClock writes a time of day as HH:MM
+func formatClock(clock time.Duration) string {
+	return fmt.Sprintf("%02d:%02d", int(clock/time.Hour), int(clock%time.Hour/time.Minute))
+}
+
+// PauseScheduler pauses playback on a schedule: for a while with /darrot-control
+// pause-for, and every day during a guild's quiet hours. Messages keep queueing while
+// playback is paused and play once it resumes. A nil *PauseScheduler has no quiet hours.
+type PauseScheduler struct {
+	voiceManager  VoiceManager
+	configService ConfigService
+	mutePauser    *MutePauser
+	logger        *log.Logger
+	now           func() time.Time // Overridable for tests
+
+	mu     sync.Mutex
+	timers map[string]*pauseTimer // Pending automatic resumes, by guild
+	quiet  map[string]bool        // Guilds whose playback was paused for their quiet hours
+	stop   chan struct{}
+	done   chan struct{}
+}
+
+// pauseTimer resumes a guild's playback once a pause-for ends
+type pauseTimer struct {
+	timer *time.Timer
+	until time.Time
+}
+
+// NewPauseScheduler creates a pause scheduler. Call Start to begin enforcing quiet hours.
+func NewPauseScheduler(voiceManager VoiceManager, configService ConfigService, logger *log.Logger) *PauseScheduler {
+	return &PauseScheduler{
+		voiceManager:  voiceManager,
+		configService: configService,
+		logger:        logger,
+		now:           time.Now,
+		timers:        make(map[string]*pauseTimer),
+		quiet:         make(map[string]bool),
+	}
+}
+
+// SetMutePauser sets the mute pauser, so playback resuming while the bot is server muted
+// waits until it is unmuted
+func (p *PauseScheduler) SetMutePauser(mutePauser *MutePauser) {
+	p.mutePauser = mutePauser
+}
+
+// Start starts checking quiet hours every QuietHoursCheckInterval
+func (p *PauseScheduler) Start() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.stop != nil {
+		return fmt.Errorf("pause scheduler is already running")
+	}
+	p.stop = make(chan struct{})
+	p.done = make(chan struct{})
+	go p.checkLoop(p.stop, p.done)
+	return nil
+}
+
+// Stop stops checking quiet hours and drops pending automatic resumes
+func (p *PauseScheduler) Stop() {
+	p.mu.Lock()
+	stop, done := p.stop, p.done
+	p.stop, p.done = nil, nil
+	for guildID, pending := range p.timers {
+		pending.timer.Stop()
+		delete(p.timers, guildID)
+	}
+	p.mu.Unlock()
+
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+}
+
+// checkLoop checks quiet hours until stop is closed
+func (p *PauseScheduler) checkLoop(stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(QuietHoursCheckInterval)
+	defer ticker.Stop()
+
+	p.Check()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			p.Check()
+		}
+	}
+}
+
+// QuietHoursEnd reports whether a guild is in its quiet hours and returns when they end
+func (p *PauseScheduler) QuietHoursEnd(guildID string) (time.Time, bool) {
+	if p == nil {
+		return time.Time{}, false
+	}
+
+	now := p.now()
+	hours, ok := p.quietHoursFor(guildID)
+	if !ok || !hours.Contains(now) {
+		return time.Time{}, false
+	}
+	return hours.EndAfter(now), true
+}
+
+// InQuietHours reports whether a guild is in its quiet hours
+func (p *PauseScheduler) InQuietHours(guildID string) bool {
+	_, quiet := p.QuietHoursEnd(guildID)
+	return quiet
+}
+
+// PauseFor pauses a guild's playback and resumes it after d, returning when. Pausing
+// again replaces the earlier resume time.
+func (p *PauseScheduler) PauseFor(guildID string, d time.Duration) (time.Time, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.voiceManager.IsPaused(guildID) {
+		if err := p.voiceManager.PausePlayback(guildID); err != nil {
+			return time.Time{}, err
+		}
+	}
+	p.cancelLocked(guildID)
+
+	pending := &pauseTimer{until: p.now().Add(d)}
+	pending.timer = time.AfterFunc(d, func() { p.resumeAfterPause(guildID, pending) })
+	p.timers[guildID] = pending
+	p.logger.Printf("Paused playback for guild %s until %s", guildID, pending.until.Format(time.RFC3339))
+	return pending.until, nil
+}
+
+// Cancel drops a guild's automatic resume, so playback paused or resumed by hand stays
+// that way. It reports whether one was pending.
+func (p *PauseScheduler) Cancel(guildID string) bool {
+	if p == nil {
+		return false
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.cancelLocked(guildID)
+}
+
+// cancelLocked drops a guild's automatic resume; p.mu must be held
+func (p *PauseScheduler) cancelLocked(guildID string) bool {
+	pending, exists := p.timers[guildID]
+	if !exists {
+		return false
+	}
+	pending.timer.Stop()
+	delete(p.timers, guildID)
+	return true
+}
+
+// resumeAfterPause resumes a guild's playback once its pause-for ends. During quiet hours
+// playback stays paused until they end.
+func (p *PauseScheduler) resumeAfterPause(guildID string, pending *pauseTimer) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.timers[guildID] != pending {
+		return
+	}
+	delete(p.timers, guildID)
+
+	if hours, ok := p.quietHoursFor(guildID); ok && hours.Contains(p.now()) {
+		p.quiet[guildID] = true
+		return
+	}
+	p.resumeLocked(guildID, "the pause ended")
+}
+
+// Check pauses playback in connected guilds whose quiet hours began and resumes it in
+// guilds whose quiet hours ended. Playback someone paused by hand before quiet hours
+// began stays paused after they end.
+func (p *PauseScheduler) Check() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	connected := make(map[string]bool)
+	for _, guildID := range p.voiceManager.GetActiveConnections() {
+		connected[guildID] = true
+
+		hours, ok := p.quietHoursFor(guildID)
+		quiet := ok && hours.Contains(now)
+
+		switch {
+		case quiet && !p.voiceManager.IsPaused(guildID):
+			if err := p.voiceManager.PausePlayback(guildID); err != nil {
+				p.logger.Printf("Failed to pause playback for guild %s during quiet hours: %v", guildID, err)
+				continue
+			}
+			p.quiet[guildID] = true
+			p.logger.Printf("Paused playback for guild %s during its quiet hours %s", guildID, hours)
+
+		case !quiet && p.quiet[guildID]:
+			delete(p.quiet, guildID)
+			p.resumeLocked(guildID, "its quiet hours ended")
+		}
+	}
+
+	// Guilds the bot left start over when it joins again
+	for guildID := range p.quiet {
+		if !connected[guildID] {
+			delete(p.quiet, guildID)
+		}
+	}
+}
+
+// resumeLocked resumes a guild's paused playback, or leaves it to the mute pauser while
+// the bot is server muted; p.mu must be held
+func (p *PauseScheduler) resumeLocked(guildID, reason string) {
+	if !p.voiceManager.IsConnected(guildID) || !p.voiceManager.IsPaused(guildID) {
+		return
+	}
+	if p.mutePauser.ResumeWhenUnmuted(guildID) {
+		return
+	}
+	if err := p.voiceManager.ResumePlayback(guildID); err != nil {
+		p.logger.Printf("Failed to resume playback for guild %s after %s: %v", guildID, reason, err)
+		return
+	}
+	p.logger.Printf("Resumed playback for guild %s now that %s", guildID, reason)
+}
+
+// quietHoursFor returns the quiet hours configured for a guild
+func (p *PauseScheduler) quietHoursFor(guildID string) (QuietHours, bool) {
+	if p.configService == nil {
+		return QuietHours{}, false
+	}
+	config, err := p.configService.GetGuildConfig(guildID)
+	if err != nil {
+		return QuietHours{}, false
+	}
+	return QuietHoursFor(config)
+}
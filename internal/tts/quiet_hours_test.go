@@ -0,0 +1,195 @@
+package tts
+
+import (
+	"io"
+	"log"
+	"testing"
+	"time"
+
+	"darrot/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuietHours_Contains(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	// Overnight quiet hours run past midnight
+	overnight, err := ParseQuietHours("23:00", "08:00", "Europe/Berlin")
+	require.NoError(t, err)
+	assert.True(t, overnight.Contains(time.Date(2026, 1, 10, 23, 0, 0, 0, berlin)))
+	assert.True(t, overnight.Contains(time.Date(2026, 1, 11, 3, 30, 0, 0, berlin)))
+	assert.False(t, overnight.Contains(time.Date(2026, 1, 11, 8, 0, 0, 0, berlin)))
+	assert.False(t, overnight.Contains(time.Date(2026, 1, 11, 22, 59, 0, 0, berlin)))
+
+	// Times are compared in the configured time zone: 22:30 UTC is 23:30 in Berlin
+	assert.True(t, overnight.Contains(time.Date(2026, 1, 10, 22, 30, 0, 0, time.UTC)))
+
+	daytime, err := ParseQuietHours("13:00", "14:30", "")
+	require.NoError(t, err)
+	assert.True(t, daytime.Contains(time.Date(2026, 1, 10, 14, 29, 0, 0, time.UTC)))
+	assert.False(t, daytime.Contains(time.Date(2026, 1, 10, 14, 30, 0, 0, time.UTC)))
+	assert.False(t, daytime.Contains(time.Date(2026, 1, 10, 12, 59, 0, 0, time.UTC)))
+	assert.Equal(t, "13:00–14:30 (UTC)", daytime.String())
+}
+
+func TestQuietHours_EndAfter(t *testing.T) {
+	hours, err := ParseQuietHours("23:00", "08:00", "Europe/Berlin")
+	require.NoError(t, err)
+	berlin := hours.Location
+
+	assert.Equal(t, time.Date(2026, 1, 11, 8, 0, 0, 0, berlin), hours.EndAfter(time.Date(2026, 1, 10, 23, 30, 0, 0, berlin)))
+	assert.Equal(t, time.Date(2026, 1, 11, 8, 0, 0, 0, berlin), hours.EndAfter(time.Date(2026, 1, 11, 2, 0, 0, 0, berlin)))
+
+	// The night the clocks go forward is an hour shorter
+	assert.Equal(t, time.Date(2026, 3, 29, 6, 0, 0, 0, time.UTC), hours.EndAfter(time.Date(2026, 3, 28, 23, 0, 0, 0, berlin)).UTC())
+}
+
+func TestParseQuietHours_Invalid(t *testing.T) {
+	for _, tc := range [][3]string{
+		{"23:00", "", ""},
+		{"25:00", "08:00", ""},
+		{"11pm", "08:00", ""},
+		{"08:00", "08:00", ""},
+		{"23:00", "08:00", "Mars/Olympus"},
+		{"23:00", "08:00", "Local"},
+	} {
+		_, err := ParseQuietHours(tc[0], tc[1], tc[2])
+		assert.Error(t, err, "%v", tc)
+	}
+}
+
+type pauseSchedulerTestEnv struct {
+	scheduler     *PauseScheduler
+	voiceManager  *mockVoiceManager
+	configService ConfigService
+	now           time.Time
+}
+
+func setupPauseSchedulerTest(t *testing.T) *pauseSchedulerTestEnv {
+	t.Helper()
+
+	storage, err := NewStorageService(t.TempDir())
+	require.NoError(t, err)
+	env := &pauseSchedulerTestEnv{
+		voiceManager:  newMockVoiceManager(),
+		configService: NewConfigService(storage, config.TTSConfig{DefaultVoice: DefaultVoice, DefaultSpeed: 1.0, DefaultVolume: 1.0, MaxQueueSize: 10}),
+		now:           time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC),
+	}
+	_, err = env.voiceManager.JoinChannel("guild1", "voice1")
+	require.NoError(t, err)
+
+	env.scheduler = NewPauseScheduler(env.voiceManager, env.configService, log.New(io.Discard, "", 0))
+	env.scheduler.now = func() time.Time { return env.now }
+	t.Cleanup(env.scheduler.Stop)
+	return env
+}
+
+// setQuietHours gives guild1 quiet hours in UTC
+func (env *pauseSchedulerTestEnv) setQuietHours(t *testing.T, start, end string) {
+	t.Helper()
+
+	guildConfig := DefaultGuildTTSConfig("guild1")
+	guildConfig.QuietHoursStart = start
+	guildConfig.QuietHoursEnd = end
+	require.NoError(t, env.configService.SetGuildConfig("guild1", &guildConfig))
+}
+
+func TestPauseScheduler_PauseForResumes(t *testing.T) {
+	env := setupPauseSchedulerTest(t)
+
+	until, err := env.scheduler.PauseFor("guild1", 20*time.Millisecond)
+	require.NoError(t, err)
+	assert.Equal(t, env.now.Add(20*time.Millisecond), until)
+	assert.True(t, env.voiceManager.IsPaused("guild1"))
+
+	require.Eventually(t, func() bool { return !env.voiceManager.IsPaused("guild1") }, time.Second, 5*time.Millisecond)
+}
+
+func TestPauseScheduler_CancelKeepsPaused(t *testing.T) {
+	env := setupPauseSchedulerTest(t)
+
+	_, err := env.scheduler.PauseFor("guild1", 20*time.Millisecond)
+	require.NoError(t, err)
+	assert.True(t, env.scheduler.Cancel("guild1"))
+	assert.False(t, env.scheduler.Cancel("guild1"))
+
+	time.Sleep(50 * time.Millisecond)
+	assert.True(t, env.voiceManager.IsPaused("guild1"))
+}
+
+func TestPauseScheduler_QuietHours(t *testing.T) {
+	env := setupPauseSchedulerTest(t)
+	env.setQuietHours(t, "23:00", "08:00")
+
+	env.scheduler.Check()
+	assert.False(t, env.voiceManager.IsPaused("guild1"))
+	assert.False(t, env.scheduler.InQuietHours("guild1"))
+
+	env.now = time.Date(2026, 1, 10, 23, 0, 0, 0, time.UTC)
+	env.scheduler.Check()
+	assert.True(t, env.voiceManager.IsPaused("guild1"))
+	end, quiet := env.scheduler.QuietHoursEnd("guild1")
+	assert.True(t, quiet)
+	assert.Equal(t, time.Date(2026, 1, 11, 8, 0, 0, 0, time.UTC), end)
+
+	env.now = time.Date(2026, 1, 11, 8, 0, 0, 0, time.UTC)
+	env.scheduler.Check()
+	assert.False(t, env.voiceManager.IsPaused("guild1"))
+}
+
+func TestPauseScheduler_QuietHoursKeepManualPause(t *testing.T) {
+	env := setupPauseSchedulerTest(t)
+	env.setQuietHours(t, "23:00", "08:00")
+
+	// Playback paused by hand before quiet hours stays paused after them
+	require.NoError(t, env.voiceManager.PausePlayback("guild1"))
+	env.now = time.Date(2026, 1, 10, 23, 30, 0, 0, time.UTC)
+	env.scheduler.Check()
+	env.now = time.Date(2026, 1, 11, 9, 0, 0, 0, time.UTC)
+	env.scheduler.Check()
+	assert.True(t, env.voiceManager.IsPaused("guild1"))
+}
+
+func TestPauseScheduler_PauseForEndingInQuietHours(t *testing.T) {
+	env := setupPauseSchedulerTest(t)
+	env.setQuietHours(t, "23:00", "08:00")
+	env.now = time.Date(2026, 1, 10, 22, 59, 0, 0, time.UTC)
+
+	_, err := env.scheduler.PauseFor("guild1", 10*time.Millisecond)
+	require.NoError(t, err)
+	env.scheduler.mu.Lock()
+	env.now = time.Date(2026, 1, 10, 23, 1, 0, 0, time.UTC)
+	env.scheduler.mu.Unlock()
+
+	// The pause ends during quiet hours, so playback resumes when they end instead
+	require.Eventually(t, func() bool {
+		env.scheduler.mu.Lock()
+		defer env.scheduler.mu.Unlock()
+		return env.scheduler.quiet["guild1"]
+	}, time.Second, 5*time.Millisecond)
+	assert.True(t, env.voiceManager.IsPaused("guild1"))
+
+	env.now = time.Date(2026, 1, 11, 8, 0, 0, 0, time.UTC)
+	env.scheduler.Check()
+	assert.False(t, env.voiceManager.IsPaused("guild1"))
+}
+
+func TestValidateGuildConfig_QuietHours(t *testing.T) {
+	guildConfig := DefaultGuildTTSConfig("guild1")
+	guildConfig.QuietHoursStart = "23:00"
+	guildConfig.QuietHoursEnd = "08:00"
+	guildConfig.Timezone = "America/New_York"
+	assert.NoError(t, ValidateGuildConfig(guildConfig))
+
+	guildConfig.QuietHoursEnd = ""
+	assert.Error(t, ValidateGuildConfig(guildConfig))
+
+	// A time zone alone is kept for when quiet hours are set
+	guildConfig.QuietHoursStart = ""
+	assert.NoError(t, ValidateGuildConfig(guildConfig))
+	guildConfig.Timezone = "Nowhere/Special"
+	assert.Error(t, ValidateGuildConfig(guildConfig))
+}
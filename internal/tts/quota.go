@@ -0,0 +1,389 @@
+package tts
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// QuotaDowngradeThreshold is the fraction of the daily budget after which premium
+// voices are swapped for the cheaper Standard tier of the same language
+const QuotaDowngradeThreshold = 0.8
+
+// quotaDateFormat is the layout used for the UTC day a usage record belongs to
+const quotaDateFormat = "2006-01-02"
+
+// standardTier is the cheapest pricing tier of Google voices, which the premium voice
+// budget does not cover
+const standardTier = "Standard"
+
+// Quota metric names
+const (
+	MetricTTSCharactersTotal   = "darrot_tts_characters_total"
+	MetricTTSTierCharacters    = "darrot_tts_tier_characters_total"
+	MetricTTSQuotaUsed         = "darrot_tts_quota_used_characters"
+	MetricTTSQuotaBudget       = "darrot_tts_quota_budget_characters"
+	MetricTTSQuotaDegradations = "darrot_tts_quota_degradations_total"
+)
+
+// TTSQuotaServiceImpl implements TTSQuotaService with usage persisted through StorageService.
+// Budgets degrade in two steps: past QuotaDowngradeThreshold premium voices fall back to the
+// Standard tier, and once the budget is spent only audio already in the cache can be played.
+// Guilds can also cap premium voices on their own, after which they fall back to the
+// Standard tier for the rest of the day.
+type TTSQuotaServiceImpl struct {
+	storage       *StorageService
+	configService ConfigService
+	defaultBudget int
+	metrics       *Metrics
+	auditLog      *AuditLog
+	usage         map[string]*QuotaUsage
+	reserved      map[string]quotaReservation // Characters being synthesized per guild
+	now           func() time.Time
+	mu            sync.Mutex
+}
+
+// quotaReservation holds characters that passed the budget check but are not recorded as
+// used yet, so messages synthesized at the same time cannot spend the same budget
+type quotaReservation struct {
+	characters int
+	premium    int
+}
+
+// NewTTSQuotaService creates a quota service. defaultBudget applies to guilds without
+// their own budget; 0 means unlimited. metrics may be nil.
+func NewTTSQuotaService(storage *StorageService, configService ConfigService, defaultBudget int, metrics *Metrics) *TTSQuotaServiceImpl {
+	if metrics != nil {
+		metrics.Describe(MetricTTSCharactersTotal, MetricTypeCounter, "Characters sent to the TTS engine")
+		metrics.Describe(MetricTTSTierCharacters, MetricTypeCounter, "Characters sent to the TTS engine per voice tier")
+		metrics.Describe(MetricTTSQuotaUsed, MetricTypeGauge, "Characters synthesized today")
+		metrics.Describe(MetricTTSQuotaBudget, MetricTypeGauge, "Daily character budget (0 = unlimited)")
+		metrics.Describe(MetricTTSQuotaDegradations, MetricTypeCounter, "Messages degraded or denied because of the daily budget")
+	}
+
+	return &TTSQuotaServiceImpl{
+		storage:       storage,
+		configService: configService,
+		defaultBudget: defaultBudget,
+		metrics:       metrics,
+		usage:         make(map[string]*QuotaUsage),
+		reserved:      make(map[string]quotaReservation),
+		now:           time.Now,
+	}
+}
+
+// SetAuditLog records the premium voice budget running out in each guild's audit channel
+func (q *TTSQuotaServiceImpl) SetAuditLog(auditLog *AuditLog) {
+	q.auditLog = auditLog
+}
+
+// Reserve checks whether text may be synthesized for a guild and returns the configuration
+// to synthesize it with. ErrQuotaExceeded is returned once the daily budget is spent.
+// Otherwise the text's characters are held against the budget until RecordUsage records
+// them or Release hands them back.
+func (q *TTSQuotaServiceImpl) Reserve(guildID, text string, config TTSConfig) (TTSConfig, error) {
+	budget, err := q.GetDailyBudget(guildID)
+	if err != nil {
+		return config, err
+	}
+	q.setGauge(MetricTTSQuotaBudget, guildID, float64(budget))
+
+	premiumBudget := 0
+	if isPremiumVoice(config.Voice) {
+		if premiumBudget, err = q.GetPremiumBudget(guildID); err != nil {
+			return config, err
+		}
+	}
+	characters := utf8.RuneCountInString(text)
+
+	// The check and the reservation happen under one lock, so workers synthesizing at the
+	// same time cannot all pass the check and overshoot the budget together
+	q.mu.Lock()
+	usage, err := q.currentUsage(guildID)
+	if err != nil {
+		q.mu.Unlock()
+		return config, err
+	}
+	reservation := q.reserved[guildID]
+	used := usage.CharactersUsed + reservation.characters
+	premiumUsed := usage.PremiumCharacters() + reservation.premium
+
+	action := ""
+	switch projected := used + characters; {
+	case budget > 0 && projected > budget:
+		q.mu.Unlock()
+		q.recordDegradation(guildID, "denied")
+		return config, ErrQuotaExceeded
+	case budget > 0 && float64(projected) > float64(budget)*QuotaDowngradeThreshold && isPremiumVoice(config.Voice):
+		action = "voice_downgrade"
+		config = downgradeVoice(config)
+	case premiumBudget > 0 && premiumUsed+characters > premiumBudget:
+		action = "premium_exhausted"
+		config = downgradeVoice(config)
+	}
+
+	reservation.characters += characters
+	if isPremiumVoice(config.Voice) {
+		reservation.premium += characters
+	}
+	q.reserved[guildID] = reservation
+	q.mu.Unlock()
+
+	if action != "" {
+		q.recordDegradation(guildID, action)
+	}
+	if action == "premium_exhausted" {
+		q.auditPremiumCap(guildID, premiumBudget)
+	}
+	return config, nil
+}
+
+// Release hands back characters reserved for a voice that were not synthesized, such as
+// when synthesis failed or the audio turned out to be cached
+func (q *TTSQuotaServiceImpl) Release(guildID string, characters int, voice string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.release(guildID, characters, voice)
+}
+
+// release removes characters synthesized with a voice from the guild's reservation.
+// Usage that was never reserved releases nothing. (caller must hold the lock)
+func (q *TTSQuotaServiceImpl) release(guildID string, characters int, voice string) {
+	reservation, exists := q.reserved[guildID]
+	if !exists {
+		return
+	}
+
+	reservation.characters -= min(characters, reservation.characters)
+	if isPremiumVoice(voice) {
+		reservation.premium -= min(characters, reservation.premium)
+	}
+	if reservation.characters == 0 {
+		delete(q.reserved, guildID)
+		return
+	}
+	q.reserved[guildID] = reservation
+}
+
+// auditPremiumCap records the first time each day that a guild's premium voice budget ran
+// out in its audit channel
+func (q *TTSQuotaServiceImpl) auditPremiumCap(guildID string, premiumBudget int) {
+	q.mu.Lock()
+	usage, err := q.currentUsage(guildID)
+	if err != nil || usage.PremiumCapReached {
+		q.mu.Unlock()
+		return
+	}
+	usage.PremiumCapReached = true
+	if err := q.storage.SaveQuotaUsage(*usage); err != nil {
+		q.mu.Unlock()
+		return
+	}
+	q.mu.Unlock()
+
+	q.auditLog.RecordPremiumBudgetExhausted(guildID, premiumBudget)
+}
+
+// RecordUsage adds characters synthesized with a voice to the guild's usage for today,
+// turning their reservation into usage
+func (q *TTSQuotaServiceImpl) RecordUsage(guildID string, characters int, voice string) error {
+	if characters <= 0 {
+		return nil
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	usage, err := q.currentUsage(guildID)
+	if err != nil {
+		return err
+	}
+
+	tier := voiceTier(voice)
+	if tier == "" {
+		tier = standardTier // The engine's default voice
+	}
+
+	q.release(guildID, characters, voice)
+	usage.CharactersUsed += characters
+	if usage.TierCharacters == nil {
+		usage.TierCharacters = make(map[string]int)
+	}
+	usage.TierCharacters[tier] += characters
+	if err := q.storage.SaveQuotaUsage(*usage); err != nil {
+		return fmt.Errorf("failed to save quota usage: %w", err)
+	}
+
+	if q.metrics != nil {
+		q.metrics.AddCounter(MetricTTSCharactersTotal, Labels{"guild": guildID}, float64(characters))
+		q.metrics.AddCounter(MetricTTSTierCharacters, Labels{"guild": guildID, "tier": tier}, float64(characters))
+	}
+	q.setGauge(MetricTTSQuotaUsed, guildID, float64(usage.CharactersUsed))
+
+	return nil
+}
+
+// GetUsage returns the guild's usage for today
+func (q *TTSQuotaServiceImpl) GetUsage(guildID string) (*QuotaUsage, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	usage, err := q.currentUsage(guildID)
+	if err != nil {
+		return nil, err
+	}
+
+	usageCopy := *usage
+	usageCopy.TierCharacters = maps.Clone(usage.TierCharacters)
+	return &usageCopy, nil
+}
+
+// GetDailyBudget returns the effective daily character budget for a guild (0 = unlimited)
+func (q *TTSQuotaServiceImpl) GetDailyBudget(guildID string) (int, error) {
+	config, err := q.configService.GetGuildConfig(guildID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get guild config: %w", err)
+	}
+
+	if config != nil && config.DailyCharacterBudget > 0 {
+		return config.DailyCharacterBudget, nil
+	}
+
+	return q.defaultBudget, nil
+}
+
+// SetDailyBudget sets a guild-specific daily budget; 0 reverts to the global default
+func (q *TTSQuotaServiceImpl) SetDailyBudget(guildID string, budget int) error {
+	if budget < 0 {
+		return fmt.Errorf("daily character budget cannot be negative")
+	}
+
+	config, err := q.configService.GetGuildConfig(guildID)
+	if err != nil {
+		return fmt.Errorf("failed to get guild config: %w", err)
+	}
+	if config == nil {
+		defaultConfig := DefaultGuildTTSConfig(guildID)
+		config = &defaultConfig
+	}
+
+	updated := *config
+	updated.DailyCharacterBudget = budget
+
+	return q.configService.SetGuildConfig(guildID, &updated)
+}
+
+// GetPremiumBudget returns the daily character budget of a guild's premium voices
+// (0 = no separate cap)
+func (q *TTSQuotaServiceImpl) GetPremiumBudget(guildID string) (int, error) {
+	config, err := q.configService.GetGuildConfig(guildID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get guild config: %w", err)
+	}
+
+	if config == nil {
+		return 0, nil
+	}
+	return config.PremiumVoiceBudget, nil
+}
+
+// SetPremiumBudget sets the daily character budget of a guild's premium voices; 0 removes
+// the separate cap
+func (q *TTSQuotaServiceImpl) SetPremiumBudget(guildID string, budget int) error {
+	if budget < 0 {
+		return fmt.Errorf("premium voice budget cannot be negative")
+	}
+
+	config, err := q.configService.GetGuildConfig(guildID)
+	if err != nil {
+		return fmt.Errorf("failed to get guild config: %w", err)
+	}
+	if config == nil {
+		defaultConfig := DefaultGuildTTSConfig(guildID)
+		config = &defaultConfig
+	}
+
+	updated := *config
+	updated.PremiumVoiceBudget = budget
+
+	return q.configService.SetGuildConfig(guildID, &updated)
+}
+
+// currentUsage returns today's usage record for a guild, loading it from storage and
+// starting a fresh record when the UTC day has rolled over (caller must hold the lock)
+func (q *TTSQuotaServiceImpl) currentUsage(guildID string) (*QuotaUsage, error) {
+	today := q.now().UTC().Format(quotaDateFormat)
+
+	usage, exists := q.usage[guildID]
+	if !exists {
+		loaded, err := q.storage.LoadQuotaUsage(guildID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load quota usage: %w", err)
+		}
+		usage = loaded
+		q.usage[guildID] = usage
+	}
+
+	if usage.Date != today {
+		usage.Date = today
+		usage.CharactersUsed = 0
+		usage.TierCharacters = nil
+		usage.PremiumCapReached = false
+	}
+
+	return usage, nil
+}
+
+// recordDegradation counts a message that was degraded or denied by the budget
+func (q *TTSQuotaServiceImpl) recordDegradation(guildID, action string) {
+	if q.metrics != nil {
+		q.metrics.IncCounter(MetricTTSQuotaDegradations, Labels{"guild": guildID, "action": action})
+	}
+}
+
+// setGauge updates a per-guild gauge when metrics are enabled
+func (q *TTSQuotaServiceImpl) setGauge(name, guildID string, value float64) {
+	if q.metrics != nil {
+		q.metrics.SetGauge(name, Labels{"guild": guildID}, value)
+	}
+}
+
+// voiceTier returns the pricing tier of a Google voice name, e.g. "Wavenet" for "en-US-Wavenet-D"
+func voiceTier(voice string) string {
+	parts := strings.Split(voice, "-")
+	if len(parts) < 4 {
+		return ""
+	}
+	return parts[2]
+}
+
+// isPremiumVoice reports whether a voice is billed above the Standard tier
+func isPremiumVoice(voice string) bool {
+	tier := voiceTier(voice)
+	return tier != "" && tier != standardTier
+}
+
+// downgradeVoice returns config with its voice swapped for the Standard tier voice of the
+// same language
+func downgradeVoice(config TTSConfig) TTSConfig {
+	config.Voice = standardVoiceFor(config.Voice)
+	return config
+}
+
+// standardVoiceFor returns the Standard tier voice for the same language as voice
+func standardVoiceFor(voice string) string {
+	parts := strings.Split(voice, "-")
+	if len(parts) < 4 {
+		return DefaultVoice
+	}
+
+	variant := parts[3]
+	if len(parts) > 4 || len(variant) != 1 {
+		variant = "A" // Named voices have no Standard equivalent; use the first variant
+	}
+
+	return fmt.Sprintf("%s-%s-%s-%s", parts[0], parts[1], standardTier, variant)
+}
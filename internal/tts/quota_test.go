@@ -0,0 +1,270 @@
+package tts
+
+import (
+	"io"
+	"log"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"darrot/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createTestQuotaService(t *testing.T, defaultBudget int) (*TTSQuotaServiceImpl, *StorageService, *Metrics) {
+	storage, err := NewStorageService(t.TempDir())
+	require.NoError(t, err)
+
+	configService := NewConfigService(storage, config.TTSConfig{DefaultVoice: DefaultVoice, DefaultSpeed: 1.0, DefaultVolume: 1.0, MaxQueueSize: 10})
+	metrics := NewMetrics()
+
+	return NewTTSQuotaService(storage, configService, defaultBudget, metrics), storage, metrics
+}
+
+func TestTTSQuotaService_UnlimitedBudget(t *testing.T) {
+	service, _, _ := createTestQuotaService(t, 0)
+
+	config := TTSConfig{Voice: "en-US-Wavenet-D", Speed: 1.0, Volume: 1.0}
+	reserved, err := service.Reserve("guild1", "hello world", config)
+
+	require.NoError(t, err)
+	assert.Equal(t, config, reserved)
+}
+
+func TestTTSQuotaService_RecordUsage(t *testing.T) {
+	service, storage, metrics := createTestQuotaService(t, 100)
+
+	require.NoError(t, service.RecordUsage("guild1", 30, DefaultVoice))
+	require.NoError(t, service.RecordUsage("guild1", 12, DefaultVoice))
+
+	usage, err := service.GetUsage("guild1")
+	require.NoError(t, err)
+	assert.Equal(t, 42, usage.CharactersUsed)
+	assert.Equal(t, time.Now().UTC().Format(quotaDateFormat), usage.Date)
+
+	// Usage is persisted
+	stored, err := storage.LoadQuotaUsage("guild1")
+	require.NoError(t, err)
+	assert.Equal(t, 42, stored.CharactersUsed)
+
+	assert.Equal(t, float64(42), metrics.Value(MetricTTSCharactersTotal, Labels{"guild": "guild1"}))
+	assert.Equal(t, float64(42), metrics.Value(MetricTTSQuotaUsed, Labels{"guild": "guild1"}))
+}
+
+func TestTTSQuotaService_Degradation(t *testing.T) {
+	service, _, metrics := createTestQuotaService(t, 100)
+	premium := TTSConfig{Voice: "en-GB-Neural2-B", Speed: 1.0, Volume: 1.0}
+
+	// Below the downgrade threshold the configured voice is kept
+	reserved, err := service.Reserve("guild1", "short", premium)
+	require.NoError(t, err)
+	assert.Equal(t, "en-GB-Neural2-B", reserved.Voice)
+
+	// Past the threshold premium voices fall back to the Standard tier
+	require.NoError(t, service.RecordUsage("guild1", 80, DefaultVoice))
+	reserved, err = service.Reserve("guild1", "short", premium)
+	require.NoError(t, err)
+	assert.Equal(t, "en-GB-Standard-B", reserved.Voice)
+	assert.Equal(t, float64(1), metrics.Value(MetricTTSQuotaDegradations, Labels{"guild": "guild1", "action": "voice_downgrade"}))
+
+	// Standard voices are unaffected by the threshold
+	standard := TTSConfig{Voice: "en-US-Standard-C", Speed: 1.0, Volume: 1.0}
+	reserved, err = service.Reserve("guild1", "short", standard)
+	require.NoError(t, err)
+	assert.Equal(t, standard, reserved)
+
+	// Once the budget would be exceeded synthesis is denied
+	_, err = service.Reserve("guild1", "this message is far too long for the remaining budget", standard)
+	assert.ErrorIs(t, err, ErrQuotaExceeded)
+	assert.Equal(t, float64(1), metrics.Value(MetricTTSQuotaDegradations, Labels{"guild": "guild1", "action": "denied"}))
+}
+
+func TestTTSQuotaService_ConcurrentReserve(t *testing.T) {
+	service, _, _ := createTestQuotaService(t, 100)
+	standard := TTSConfig{Voice: "en-US-Standard-C"}
+
+	// Workers synthesizing at the same time share the budget instead of each seeing all of it
+	var granted atomic.Int32
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := service.Reserve("guild1", "0123456789", standard); err == nil {
+				granted.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(10), granted.Load())
+
+	// Recording the usage turns reservations into usage without counting them twice
+	for range 10 {
+		require.NoError(t, service.RecordUsage("guild1", 10, standard.Voice))
+	}
+	usage, err := service.GetUsage("guild1")
+	require.NoError(t, err)
+	assert.Equal(t, 100, usage.CharactersUsed)
+	_, err = service.Reserve("guild1", "x", standard)
+	assert.ErrorIs(t, err, ErrQuotaExceeded)
+}
+
+func TestTTSQuotaService_Release(t *testing.T) {
+	service, _, _ := createTestQuotaService(t, 20)
+	standard := TTSConfig{Voice: "en-US-Standard-C"}
+
+	_, err := service.Reserve("guild1", "0123456789", standard)
+	require.NoError(t, err)
+	_, err = service.Reserve("guild1", "0123456789", standard)
+	require.NoError(t, err)
+	_, err = service.Reserve("guild1", "x", standard)
+	assert.ErrorIs(t, err, ErrQuotaExceeded, "reserved characters count against the budget")
+
+	// Characters that were not synthesized are handed back
+	service.Release("guild1", 10, standard.Voice)
+	_, err = service.Reserve("guild1", "x", standard)
+	assert.NoError(t, err)
+
+	usage, err := service.GetUsage("guild1")
+	require.NoError(t, err)
+	assert.Equal(t, 0, usage.CharactersUsed, "reservations are not usage")
+}
+
+func TestTTSQuotaService_DailyReset(t *testing.T) {
+	service, _, _ := createTestQuotaService(t, 100)
+
+	day := time.Date(2024, 3, 1, 23, 59, 0, 0, time.UTC)
+	service.now = func() time.Time { return day }
+
+	require.NoError(t, service.RecordUsage("guild1", 100, DefaultVoice))
+	_, err := service.Reserve("guild1", "hi", TTSConfig{Voice: DefaultVoice})
+	assert.ErrorIs(t, err, ErrQuotaExceeded)
+
+	// The next UTC day starts with a fresh budget
+	day = day.Add(2 * time.Minute)
+	usage, err := service.GetUsage("guild1")
+	require.NoError(t, err)
+	assert.Equal(t, 0, usage.CharactersUsed)
+	assert.Equal(t, "2024-03-02", usage.Date)
+
+	_, err = service.Reserve("guild1", "hi", TTSConfig{Voice: DefaultVoice})
+	assert.NoError(t, err)
+}
+
+func TestTTSQuotaService_GuildBudgetOverride(t *testing.T) {
+	service, _, _ := createTestQuotaService(t, 1000)
+
+	budget, err := service.GetDailyBudget("guild1")
+	require.NoError(t, err)
+	assert.Equal(t, 1000, budget)
+
+	require.NoError(t, service.SetDailyBudget("guild1", 50))
+	budget, err = service.GetDailyBudget("guild1")
+	require.NoError(t, err)
+	assert.Equal(t, 50, budget)
+
+	// Other guilds keep the default
+	budget, err = service.GetDailyBudget("guild2")
+	require.NoError(t, err)
+	assert.Equal(t, 1000, budget)
+
+	// Zero reverts to the default
+	require.NoError(t, service.SetDailyBudget("guild1", 0))
+	budget, err = service.GetDailyBudget("guild1")
+	require.NoError(t, err)
+	assert.Equal(t, 1000, budget)
+
+	assert.Error(t, service.SetDailyBudget("guild1", -1))
+}
+
+func TestTTSQuotaService_TierUsage(t *testing.T) {
+	service, _, metrics := createTestQuotaService(t, 0)
+
+	require.NoError(t, service.RecordUsage("guild1", 10, "en-US-Standard-C"))
+	require.NoError(t, service.RecordUsage("guild1", 20, "en-US-Wavenet-D"))
+	require.NoError(t, service.RecordUsage("guild1", 5, "en-GB-Neural2-B"))
+	require.NoError(t, service.RecordUsage("guild1", 1, ""))
+
+	usage, err := service.GetUsage("guild1")
+	require.NoError(t, err)
+	assert.Equal(t, 36, usage.CharactersUsed)
+	assert.Equal(t, map[string]int{"Standard": 11, "Wavenet": 20, "Neural2": 5}, usage.TierCharacters)
+	assert.Equal(t, 25, usage.PremiumCharacters())
+	assert.Equal(t, float64(20), metrics.Value(MetricTTSTierCharacters, Labels{"guild": "guild1", "tier": "Wavenet"}))
+
+	// Usage handed out is a copy
+	usage.TierCharacters["Standard"] = 1000
+	usage, err = service.GetUsage("guild1")
+	require.NoError(t, err)
+	assert.Equal(t, 11, usage.TierCharacters["Standard"])
+}
+
+func TestTTSQuotaService_PremiumBudget(t *testing.T) {
+	service, storage, metrics := createTestQuotaService(t, 0)
+	messenger := &recordingAuditMessenger{}
+	auditLog := NewAuditLog(service.configService, messenger, log.New(io.Discard, "", 0))
+	require.NoError(t, auditLog.SetChannel("guild1", "audit1"))
+	service.SetAuditLog(auditLog)
+
+	require.NoError(t, service.SetPremiumBudget("guild1", 30))
+	budget, err := service.GetPremiumBudget("guild1")
+	require.NoError(t, err)
+	assert.Equal(t, 30, budget)
+	assert.Error(t, service.SetPremiumBudget("guild1", -1))
+
+	premium := TTSConfig{Voice: "en-US-Wavenet-D"}
+	reserved, err := service.Reserve("guild1", "hello", premium)
+	require.NoError(t, err)
+	assert.Equal(t, premium, reserved)
+
+	// Standard voices do not count towards the premium budget
+	require.NoError(t, service.RecordUsage("guild1", 100, "en-US-Standard-C"))
+	require.NoError(t, service.RecordUsage("guild1", 28, "en-US-Wavenet-D"))
+	reserved, err = service.Reserve("guild1", "hello", premium)
+	require.NoError(t, err)
+	assert.Equal(t, "en-US-Standard-D", reserved.Voice, "premium voices fall back once their budget is used up")
+	assert.Equal(t, float64(1), metrics.Value(MetricTTSQuotaDegradations, Labels{"guild": "guild1", "action": "premium_exhausted"}))
+
+	_, err = service.Reserve("guild1", "hello again", premium)
+	require.NoError(t, err)
+	require.Len(t, messenger.embeds, 1, "the budget running out is audited once a day")
+	assert.Contains(t, messenger.embeds[0].Title, "Premium voice budget")
+	stored, err := storage.LoadQuotaUsage("guild1")
+	require.NoError(t, err)
+	assert.True(t, stored.PremiumCapReached, "a restart does not audit it again")
+
+	standard := TTSConfig{Voice: "en-US-Standard-C"}
+	reserved, err = service.Reserve("guild1", "hello", standard)
+	require.NoError(t, err)
+	assert.Equal(t, standard, reserved)
+
+	// The next UTC day starts with a fresh premium budget
+	service.now = func() time.Time { return time.Now().Add(24 * time.Hour) }
+	reserved, err = service.Reserve("guild1", "hello", premium)
+	require.NoError(t, err)
+	assert.Equal(t, premium, reserved)
+}
+
+func TestStandardVoiceFor(t *testing.T) {
+	tests := []struct {
+		voice    string
+		expected string
+		premium  bool
+	}{
+		{"en-US-Wavenet-D", "en-US-Standard-D", true},
+		{"de-DE-Neural2-A", "de-DE-Standard-A", true},
+		{"en-US-Standard-B", "en-US-Standard-B", false},
+		{"en-US-Chirp3-HD-Achernar", "en-US-Standard-A", true},
+		{"", DefaultVoice, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.voice, func(t *testing.T) {
+			assert.Equal(t, tt.expected, standardVoiceFor(tt.voice))
+			assert.Equal(t, tt.premium, isPremiumVoice(tt.voice))
+		})
+	}
+}
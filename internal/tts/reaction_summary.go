@@ -0,0 +1,392 @@
+package tts
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Reaction summary timing and size
+const (
+	ReactionSummaryQuietDelay = 30 * time.Second // Reaction-free time before a summary is spoken
+	ReactionSummaryMaxDelay   = 2 * time.Minute  // Longest a summary waits while reactions keep coming
+	ReactionMaxMessageAge     = 10 * time.Minute // Reactions on older messages are not summarized
+	MaxSummarizedMessages     = 3                // Most-reacted messages named in one summary
+)
+
+// ReactionSummarizer periodically speaks a summary of reactions on recent messages in a
+// guild's paired text channels ("Bob's message got 5 tears of joy reactions"). Reactions
+// are collected until none arrive for ReactionSummaryQuietDelay, so individual reaction
+// events never reach the queue. Summaries use the low-priority lane like join/leave
+// announcements.
+type ReactionSummarizer struct {
+	voiceManager   VoiceManager
+	channelService ChannelService
+	userService    UserService
+	messageQueue   MessageQueue
+	configService  ConfigService
+	localizer      *Localizer
+	logger         *log.Logger
+	removeHandlers []func()
+
+	quietDelay time.Duration
+	maxDelay   time.Duration
+	now        func() time.Time
+
+	// messageAuthor returns the author of a message
+	messageAuthor func(channelID, messageID string) (*discordgo.User, error)
+
+	mu      sync.Mutex
+	pending map[string]*pendingReactions // Reactions waiting to be summarized per guild
+}
+
+// pendingReactions are the reactions collected in a guild since the last summary
+type pendingReactions struct {
+	first    time.Time
+	timer    *time.Timer
+	messages map[string]*reactionTally
+}
+
+// reactionTally counts the reactions on a single message
+type reactionTally struct {
+	channelID string
+	messageID string
+	counts    map[string]int // Spoken reaction name to count
+	order     []string       // Reaction names in the order they were first added
+}
+
+// total returns the number of reactions on the message
+func (t *reactionTally) total() int {
+	total := 0
+	for _, count := range t.counts {
+		total += count
+	}
+	return total
+}
+
+// NewReactionSummarizer creates a reaction summarizer. Call Register to start listening
+// for reaction events.
+func NewReactionSummarizer(
+	voiceManager VoiceManager,
+	channelService ChannelService,
+	userService UserService,
+	messageQueue MessageQueue,
+	configService ConfigService,
+	logger *log.Logger,
+) *ReactionSummarizer {
+	return &ReactionSummarizer{
+		voiceManager:   voiceManager,
+		channelService: channelService,
+		userService:    userService,
+		messageQueue:   messageQueue,
+		configService:  configService,
+		logger:         logger,
+		quietDelay:     ReactionSummaryQuietDelay,
+		maxDelay:       ReactionSummaryMaxDelay,
+		now:            time.Now,
+		pending:        make(map[string]*pendingReactions),
+	}
+}
+
+// Register subscribes the summarizer to reaction events on the session
+func (r *ReactionSummarizer) Register(session *discordgo.Session) {
+	r.messageAuthor = func(channelID, messageID string) (*discordgo.User, error) {
+		if message, err := session.State.Message(channelID, messageID); err == nil && message.Author != nil {
+			return message.Author, nil
+		}
+		message, err := session.ChannelMessage(channelID, messageID)
+		if err != nil {
+			return nil, err
+		}
+		return message.Author, nil
+	}
+	r.removeHandlers = append(r.removeHandlers,
+		session.AddHandler(r.handleReactionAdd),
+		session.AddHandler(r.handleReactionRemove),
+	)
+}
+
+// Stop unsubscribes the summarizer from reaction events and drops reactions that were
+// not summarized yet
+func (r *ReactionSummarizer) Stop() {
+	for _, remove := range r.removeHandlers {
+		remove()
+	}
+	r.removeHandlers = nil
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for guildID, pending := range r.pending {
+		pending.timer.Stop()
+		delete(r.pending, guildID)
+	}
+}
+
+// SetLocalizer sets the localizer used to translate summaries
+func (r *ReactionSummarizer) SetLocalizer(localizer *Localizer) {
+	r.localizer = localizer
+}
+
+// Enabled reports whether reaction summaries are turned on for a guild
+func (r *ReactionSummarizer) Enabled(guildID string) bool {
+	config, err := r.configService.GetGuildConfig(guildID)
+	if err != nil || config == nil {
+		return false
+	}
+	return config.ReadReactions
+}
+
+// SetEnabled turns reaction summaries on or off for a guild
+func (r *ReactionSummarizer) SetEnabled(guildID string, enabled bool) error {
+	config, err := r.configService.GetGuildConfig(guildID)
+	if err != nil {
+		return fmt.Errorf("failed to get guild config: %w", err)
+	}
+	if config == nil {
+		defaultConfig := DefaultGuildTTSConfig(guildID)
+		config = &defaultConfig
+	}
+
+	updated := *config
+	updated.ReadReactions = enabled
+
+	return r.configService.SetGuildConfig(guildID, &updated)
+}
+
+// handleReactionAdd is the discordgo event handler for added reactions
+func (r *ReactionSummarizer) handleReactionAdd(s *discordgo.Session, mr *discordgo.MessageReactionAdd) {
+	if mr == nil || mr.MessageReaction == nil {
+		return
+	}
+	if mr.Member != nil && mr.Member.User != nil && mr.Member.User.Bot {
+		return
+	}
+	if s.State != nil && s.State.User != nil && mr.UserID == s.State.User.ID {
+		return
+	}
+	r.add(mr.MessageReaction)
+}
+
+// handleReactionRemove is the discordgo event handler for removed reactions
+func (r *ReactionSummarizer) handleReactionRemove(s *discordgo.Session, mr *discordgo.MessageReactionRemove) {
+	if mr == nil || mr.MessageReaction == nil {
+		return
+	}
+	if s.State != nil && s.State.User != nil && mr.UserID == s.State.User.ID {
+		return
+	}
+	r.remove(mr.MessageReaction)
+}
+
+// add counts a reaction on a recent message in a paired channel and schedules a summary
+func (r *ReactionSummarizer) add(reaction *discordgo.MessageReaction) {
+	if !r.shouldCount(reaction) {
+		return
+	}
+	name := reactionName(reaction.Emoji)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	pending, exists := r.pending[reaction.GuildID]
+	if !exists {
+		guildID := reaction.GuildID
+		pending = &pendingReactions{
+			first:    r.now(),
+			messages: make(map[string]*reactionTally),
+		}
+		pending.timer = time.AfterFunc(r.quietDelay, func() { r.flush(guildID) })
+		r.pending[guildID] = pending
+	} else {
+		// Wait for reactions to settle, but never past the maximum delay
+		delay := r.quietDelay
+		if remaining := r.maxDelay - r.now().Sub(pending.first); remaining < delay {
+			delay = max(remaining, 0)
+		}
+		pending.timer.Reset(delay)
+	}
+
+	tally, exists := pending.messages[reaction.MessageID]
+	if !exists {
+		tally = &reactionTally{
+			channelID: reaction.ChannelID,
+			messageID: reaction.MessageID,
+			counts:    make(map[string]int),
+		}
+		pending.messages[reaction.MessageID] = tally
+	}
+	if tally.counts[name] == 0 {
+		tally.order = append(tally.order, name)
+	}
+	tally.counts[name]++
+}
+
+// remove takes back a reaction that has not been summarized yet
+func (r *ReactionSummarizer) remove(reaction *discordgo.MessageReaction) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	pending, exists := r.pending[reaction.GuildID]
+	if !exists {
+		return
+	}
+	tally, exists := pending.messages[reaction.MessageID]
+	if !exists {
+		return
+	}
+
+	name := reactionName(reaction.Emoji)
+	if tally.counts[name] == 0 {
+		return
+	}
+	tally.counts[name]--
+	if tally.counts[name] == 0 {
+		delete(tally.counts, name)
+		tally.order = slices.DeleteFunc(tally.order, func(n string) bool { return n == name })
+	}
+	if len(tally.counts) == 0 {
+		delete(pending.messages, reaction.MessageID)
+	}
+}
+
+// shouldCount reports whether a reaction belongs in a summary: summaries must be on for
+// the guild, the bot must be in a voice channel and the message must be recent and in a
+// paired channel
+func (r *ReactionSummarizer) shouldCount(reaction *discordgo.MessageReaction) bool {
+	if reaction.GuildID == "" || reaction.Emoji.Name == "" {
+		return false
+	}
+	if sent, err := discordgo.SnowflakeTimestamp(reaction.MessageID); err != nil || r.now().Sub(sent) > ReactionMaxMessageAge {
+		return false
+	}
+	if connection, connected := r.voiceManager.GetConnection(reaction.GuildID); !connected || connection == nil {
+		return false
+	}
+	if !r.channelService.IsChannelPaired(reaction.GuildID, reaction.ChannelID) {
+		return false
+	}
+	return r.Enabled(reaction.GuildID)
+}
+
+// flush speaks the summary of the reactions collected in a guild
+func (r *ReactionSummarizer) flush(guildID string) {
+	r.mu.Lock()
+	pending, exists := r.pending[guildID]
+	if exists {
+		pending.timer.Stop()
+		delete(r.pending, guildID)
+	}
+	r.mu.Unlock()
+	if !exists || len(pending.messages) == 0 {
+		return
+	}
+
+	connection, connected := r.voiceManager.GetConnection(guildID)
+	if !connected || connection == nil {
+		return
+	}
+
+	summary := r.summarize(guildID, pending.messages)
+	if summary == "" {
+		return
+	}
+
+	message := &QueuedMessage{
+		ID:        fmt.Sprintf("reactions-%s-%d", guildID, r.now().UnixNano()),
+		GuildID:   guildID,
+		ChannelID: connection.ChannelID,
+		Username:  "reactions",
+		Content:   summary,
+		Priority:  PriorityLow,
+		Timestamp: r.now(),
+	}
+	if err := r.messageQueue.Enqueue(message); err != nil {
+		r.logger.Printf("Failed to queue reaction summary for guild %s: %v", guildID, err)
+	}
+}
+
+// summarize describes the reactions on the most-reacted messages, skipping messages
+// whose author cannot be looked up, is a bot or is not opted in
+func (r *ReactionSummarizer) summarize(guildID string, messages map[string]*reactionTally) string {
+	tallies := make([]*reactionTally, 0, len(messages))
+	for _, tally := range messages {
+		tallies = append(tallies, tally)
+	}
+	sort.Slice(tallies, func(i, j int) bool {
+		if tallies[i].total() != tallies[j].total() {
+			return tallies[i].total() > tallies[j].total()
+		}
+		return tallies[i].messageID < tallies[j].messageID // Older messages first
+	})
+
+	var sentences []string
+	for _, tally := range tallies {
+		if len(sentences) == MaxSummarizedMessages {
+			break
+		}
+
+		author, err := r.author(tally)
+		if err != nil {
+			r.logger.Printf("Failed to look up author of message %s in guild %s: %v", tally.messageID, guildID, err)
+			continue
+		}
+		if author == nil || author.Bot {
+			continue
+		}
+		if optedIn, err := r.userService.IsOptedIn(author.ID, guildID); err != nil || !optedIn {
+			continue
+		}
+
+		sentences = append(sentences, r.localizer.T(guildID, "reactions.summary", userDisplayName(author), r.describeReactions(guildID, tally)))
+	}
+	return strings.Join(sentences, " ")
+}
+
+// describeReactions lists the reactions on a message from most to least common
+func (r *ReactionSummarizer) describeReactions(guildID string, tally *reactionTally) string {
+	names := append([]string(nil), tally.order...)
+	sort.SliceStable(names, func(i, j int) bool {
+		return tally.counts[names[i]] > tally.counts[names[j]]
+	})
+
+	parts := make([]string, len(names))
+	for i, name := range names {
+		if count := tally.counts[name]; count == 1 {
+			parts[i] = r.localizer.T(guildID, "reactions.one", name)
+		} else {
+			parts[i] = r.localizer.T(guildID, "reactions.many", count, name)
+		}
+	}
+	return strings.Join(parts, r.localizer.T(guildID, "reactions.and"))
+}
+
+// author returns the author of a tallied message
+func (r *ReactionSummarizer) author(tally *reactionTally) (*discordgo.User, error) {
+	if r.messageAuthor == nil {
+		return nil, fmt.Errorf("message lookup is not available")
+	}
+	return r.messageAuthor(tally.channelID, tally.messageID)
+}
+
+// reactionName returns the spoken name of a reaction emoji: the name of a custom emoji,
+// or the short name of a Unicode emoji without the trailing "emoji"
+func reactionName(emoji discordgo.Emoji) string {
+	if emoji.ID != "" {
+		return emoji.Name
+	}
+	return strings.TrimSuffix(emojiName(emoji.Name), " emoji")
+}
+
+// userDisplayName returns the name a user is shown with outside a guild
+func userDisplayName(user *discordgo.User) string {
+	if user.GlobalName != "" {
+		return user.GlobalName
+	}
+	return user.Username
+}
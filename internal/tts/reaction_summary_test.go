@@ -0,0 +1,217 @@
+package tts
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"darrot/internal/config"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// discordEpoch is the start of Discord snowflake timestamps in Unix milliseconds
+const discordEpoch = 1420070400000
+
+// snowflakeAt returns a message ID created at the given time
+func snowflakeAt(at time.Time) string {
+	return strconv.FormatInt((at.UnixMilli()-discordEpoch)<<22, 10)
+}
+
+func createTestReactionSummarizer(t *testing.T) (*ReactionSummarizer, *mockChannelService, *mockUserService, MessageQueue) {
+	storage, err := NewStorageService(t.TempDir())
+	require.NoError(t, err)
+
+	configService := NewConfigService(storage, config.TTSConfig{DefaultVoice: DefaultVoice, DefaultSpeed: 1.0, DefaultVolume: 1.0, MaxQueueSize: 10})
+	voiceManager := newMockVoiceManager()
+	channelService := newMockChannelService()
+	userService := newMockUserService()
+	queue := NewMessageQueue()
+	logger := log.New(os.Stdout, "[TEST] ", log.LstdFlags)
+
+	_, err = voiceManager.JoinChannel("guild1", "voice1")
+	require.NoError(t, err)
+	channelService.setPaired("text1", true)
+
+	summarizer := NewReactionSummarizer(voiceManager, channelService, userService, queue, configService, logger)
+	summarizer.quietDelay = time.Hour // Tests flush explicitly unless they test the delay
+	summarizer.maxDelay = time.Hour
+	t.Cleanup(summarizer.Stop)
+
+	return summarizer, channelService, userService, queue
+}
+
+func reaction(messageID, emoji string) *discordgo.MessageReaction {
+	return &discordgo.MessageReaction{
+		UserID:    "reactor",
+		MessageID: messageID,
+		ChannelID: "text1",
+		GuildID:   "guild1",
+		Emoji:     discordgo.Emoji{Name: emoji},
+	}
+}
+
+func authors(users map[string]*discordgo.User) func(channelID, messageID string) (*discordgo.User, error) {
+	return func(channelID, messageID string) (*discordgo.User, error) {
+		if user, ok := users[messageID]; ok {
+			return user, nil
+		}
+		return nil, fmt.Errorf("unknown message %s", messageID)
+	}
+}
+
+func TestReactionSummarizer_DisabledByDefault(t *testing.T) {
+	summarizer, _, _, queue := createTestReactionSummarizer(t)
+	assert.False(t, summarizer.Enabled("guild1"))
+
+	summarizer.add(reaction(snowflakeAt(time.Now()), "😂"))
+	summarizer.flush("guild1")
+	assert.Equal(t, 0, queue.Size("guild1"))
+}
+
+func TestReactionSummarizer_Summary(t *testing.T) {
+	summarizer, _, userService, queue := createTestReactionSummarizer(t)
+	require.NoError(t, summarizer.SetEnabled("guild1", true))
+
+	first := snowflakeAt(time.Now().Add(-2 * time.Minute))
+	second := snowflakeAt(time.Now().Add(-time.Minute))
+	summarizer.messageAuthor = authors(map[string]*discordgo.User{
+		first:  {ID: "bob", Username: "bob"},
+		second: {ID: "alice", Username: "alice", GlobalName: "Alice"},
+	})
+	userService.setOptedIn("bob", "guild1", true)
+	userService.setOptedIn("alice", "guild1", true)
+
+	summarizer.add(reaction(second, "👍"))
+	for range 3 {
+		summarizer.add(reaction(first, "😂"))
+	}
+	summarizer.add(reaction(first, "👍"))
+	custom := reaction(second, "pepe")
+	custom.Emoji.ID = "123"
+	summarizer.add(custom)
+	summarizer.add(custom)
+	summarizer.remove(reaction(second, "👍"))
+
+	summarizer.flush("guild1")
+
+	message, err := queue.Dequeue("guild1")
+	require.NoError(t, err)
+	require.NotNil(t, message)
+	assert.Equal(t, "bob's message got 3 tears of joy reactions and one thumbs up reaction. Alice's message got 2 pepe reactions.", message.Content)
+	assert.Equal(t, PriorityLow, message.Priority)
+	assert.Equal(t, "voice1", message.ChannelID)
+
+	// Reactions were consumed by the summary
+	summarizer.flush("guild1")
+	assert.Equal(t, 0, queue.Size("guild1"))
+}
+
+func TestReactionSummarizer_LimitsSummarizedMessages(t *testing.T) {
+	summarizer, _, userService, queue := createTestReactionSummarizer(t)
+	require.NoError(t, summarizer.SetEnabled("guild1", true))
+
+	users := map[string]*discordgo.User{}
+	now := time.Now()
+	for i := range MaxSummarizedMessages + 2 {
+		messageID := snowflakeAt(now.Add(-time.Duration(i+1) * time.Second))
+		userID := fmt.Sprintf("user%d", i)
+		users[messageID] = &discordgo.User{ID: userID, Username: userID}
+		userService.setOptedIn(userID, "guild1", true)
+		for range i + 1 {
+			summarizer.add(reaction(messageID, "🔥"))
+		}
+	}
+	summarizer.messageAuthor = authors(users)
+
+	summarizer.flush("guild1")
+
+	message, err := queue.Dequeue("guild1")
+	require.NoError(t, err)
+	require.NotNil(t, message)
+	assert.Equal(t, "user4's message got 5 fire reactions. user3's message got 4 fire reactions. user2's message got 3 fire reactions.", message.Content)
+}
+
+func TestReactionSummarizer_IgnoresReactions(t *testing.T) {
+	summarizer, _, userService, queue := createTestReactionSummarizer(t)
+	require.NoError(t, summarizer.SetEnabled("guild1", true))
+
+	recent := snowflakeAt(time.Now())
+	summarizer.messageAuthor = authors(map[string]*discordgo.User{
+		recent: {ID: "bot", Username: "helper", Bot: true},
+	})
+	userService.setOptedIn("bob", "guild1", true)
+
+	// Old messages, unpaired channels and guilds the bot is not connected in
+	summarizer.add(reaction(snowflakeAt(time.Now().Add(-ReactionMaxMessageAge-time.Minute)), "😂"))
+	unpaired := reaction(recent, "😂")
+	unpaired.ChannelID = "text2"
+	summarizer.add(unpaired)
+	otherGuild := reaction(recent, "😂")
+	otherGuild.GuildID = "guild2"
+	summarizer.add(otherGuild)
+	assert.Empty(t, summarizer.pending)
+
+	// Messages by bots are counted but not summarized
+	summarizer.add(reaction(recent, "😂"))
+	summarizer.flush("guild1")
+	assert.Equal(t, 0, queue.Size("guild1"))
+
+	// Neither are messages by users who are not opted in
+	summarizer.messageAuthor = authors(map[string]*discordgo.User{
+		recent: {ID: "carol", Username: "carol"},
+	})
+	summarizer.add(reaction(recent, "😂"))
+	summarizer.flush("guild1")
+	assert.Equal(t, 0, queue.Size("guild1"))
+}
+
+func TestReactionSummarizer_Debounce(t *testing.T) {
+	summarizer, _, userService, queue := createTestReactionSummarizer(t)
+	require.NoError(t, summarizer.SetEnabled("guild1", true))
+	summarizer.quietDelay = 50 * time.Millisecond
+
+	messageID := snowflakeAt(time.Now())
+	summarizer.messageAuthor = authors(map[string]*discordgo.User{messageID: {ID: "bob", Username: "bob"}})
+	userService.setOptedIn("bob", "guild1", true)
+
+	for range 5 {
+		summarizer.add(reaction(messageID, "❤️"))
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, 0, queue.Size("guild1"), "summary waits until reactions settle")
+
+	assert.Eventually(t, func() bool { return queue.Size("guild1") == 1 }, time.Second, 10*time.Millisecond)
+	message, err := queue.Dequeue("guild1")
+	require.NoError(t, err)
+	assert.Equal(t, "bob's message got 5 red heart reactions.", message.Content)
+}
+
+func TestReactionSummarizer_MaxDelay(t *testing.T) {
+	summarizer, _, userService, queue := createTestReactionSummarizer(t)
+	require.NoError(t, summarizer.SetEnabled("guild1", true))
+	summarizer.quietDelay = 50 * time.Millisecond
+	summarizer.maxDelay = 100 * time.Millisecond
+
+	messageID := snowflakeAt(time.Now())
+	summarizer.messageAuthor = authors(map[string]*discordgo.User{messageID: {ID: "bob", Username: "bob"}})
+	userService.setOptedIn("bob", "guild1", true)
+
+	// Reactions that never settle are still summarized once the maximum delay passes
+	deadline := time.Now().Add(400 * time.Millisecond)
+	for time.Now().Before(deadline) && queue.Size("guild1") == 0 {
+		summarizer.add(reaction(messageID, "👍"))
+		time.Sleep(20 * time.Millisecond)
+	}
+	assert.Equal(t, 1, queue.Size("guild1"))
+}
+
+func TestReactionName(t *testing.T) {
+	assert.Equal(t, "tears of joy", reactionName(discordgo.Emoji{Name: "😂"}))
+	assert.Equal(t, "pepe", reactionName(discordgo.Emoji{ID: "1", Name: "pepe"}))
+}
@@ -0,0 +1,238 @@
+package tts
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// ReadMoreNamespace is the component namespace of the "Read the rest" buttons offered
+// for long messages. The bot routes clicks on them to ReadMore.HandleButton.
+const ReadMoreNamespace = "read-more"
+
+// ReadMoreWindow is how long listeners can have the rest of a long message read
+const ReadMoreWindow = 2 * time.Minute
+
+// ReadMoreMessenger posts and edits the prompts offering the rest of long messages
+type ReadMoreMessenger interface {
+	ChannelMessageSendComplex(channelID string, data *discordgo.MessageSend, options ...discordgo.RequestOption) (*discordgo.Message, error)
+	ChannelMessageEditComplex(m *discordgo.MessageEdit, options ...discordgo.RequestOption) (*discordgo.Message, error)
+}
+
+// ReadMore offers the rest of messages that guilds using the ask truncation mode only
+// read the first part of. A prompt with a button is posted in reply to the message, and
+// when anyone in the bot's voice channel clicks it within the window the remaining parts
+// are queued. Unclaimed parts are only kept in memory and are dropped when the window
+// ends or the bot restarts.
+type ReadMore struct {
+	messageQueue MessageQueue
+	messenger    ReadMoreMessenger
+	componentIDs ComponentIDFactory
+	localizer    *Localizer
+	logger       *log.Logger
+	window       time.Duration
+
+	// listeners returns the users currently in the bot's voice channel
+	listeners func(guildID string) []string
+
+	// respond answers button clicks; overridable for tests
+	respond func(s *discordgo.Session, i *discordgo.InteractionCreate, response *discordgo.InteractionResponse) error
+
+	mu      sync.Mutex
+	pending map[string]*readMoreOffer // By the ID of the long message
+}
+
+// readMoreOffer is the unread rest of a long message
+type readMoreOffer struct {
+	first     QueuedMessage // The part that was read, copied for the remaining parts
+	parts     []string
+	channelID string
+	promptID  string
+	timer     *time.Timer
+}
+
+// NewReadMore creates the service offering the rest of long messages. Nothing is offered
+// until SetComponentIDs and SetListeners are called.
+func NewReadMore(messageQueue MessageQueue, messenger ReadMoreMessenger, logger *log.Logger) *ReadMore {
+	return &ReadMore{
+		messageQueue: messageQueue,
+		messenger:    messenger,
+		logger:       logger,
+		window:       ReadMoreWindow,
+		respond: func(s *discordgo.Session, i *discordgo.InteractionCreate, response *discordgo.InteractionResponse) error {
+			return s.InteractionRespond(i.Interaction, response)
+		},
+		pending: make(map[string]*readMoreOffer),
+	}
+}
+
+// SetComponentIDs sets the factory for the custom IDs of the buttons
+func (r *ReadMore) SetComponentIDs(componentIDs ComponentIDFactory) {
+	r.componentIDs = componentIDs
+}
+
+// SetLocalizer sets the localizer used to write prompts in each guild's language
+func (r *ReadMore) SetLocalizer(localizer *Localizer) {
+	r.localizer = localizer
+}
+
+// SetListeners sets how the users in the bot's voice channel are looked up, since only
+// they may have the rest read
+func (r *ReadMore) SetListeners(listeners func(guildID string) []string) {
+	r.listeners = listeners
+}
+
+// Offer posts a prompt offering the remaining parts of a long message whose first part
+// was queued as first
+func (r *ReadMore) Offer(first *QueuedMessage, parts []string) error {
+	if r.componentIDs == nil || r.listeners == nil {
+		return errors.New("read more buttons are not set up")
+	}
+
+	customID, err := r.componentIDs.NewCustomID(ReadMoreNamespace, first.ID, "", r.window)
+	if err != nil {
+		return fmt.Errorf("failed to create read more button: %w", err)
+	}
+
+	offer := &readMoreOffer{first: *first, parts: parts, channelID: first.ChannelID}
+	r.mu.Lock()
+	if _, exists := r.pending[first.ID]; exists {
+		r.mu.Unlock()
+		return nil
+	}
+	r.pending[first.ID] = offer
+	r.mu.Unlock()
+
+	guildID := first.GuildID
+	prompt, err := r.messenger.ChannelMessageSendComplex(first.ChannelID, &discordgo.MessageSend{
+		Content:         r.localizer.T(guildID, "read_more.prompt", first.UserID, int(r.window.Minutes())),
+		Reference:       &discordgo.MessageReference{MessageID: first.ID, ChannelID: first.ChannelID, GuildID: guildID},
+		AllowedMentions: &discordgo.MessageAllowedMentions{},
+		Components: []discordgo.MessageComponent{
+			discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Label:    r.localizer.T(guildID, "read_more.button", len(parts)),
+					Style:    discordgo.PrimaryButton,
+					CustomID: customID,
+				},
+			}},
+		},
+	})
+	if err != nil {
+		r.mu.Lock()
+		delete(r.pending, first.ID)
+		r.mu.Unlock()
+		return fmt.Errorf("failed to post read more prompt: %w", err)
+	}
+
+	r.mu.Lock()
+	offer.promptID = prompt.ID
+	offer.timer = time.AfterFunc(r.window, func() { r.expire(first.ID, offer) })
+	r.mu.Unlock()
+	return nil
+}
+
+// expire drops an offer nobody took up and removes the button from its prompt
+func (r *ReadMore) expire(messageID string, offer *readMoreOffer) {
+	r.mu.Lock()
+	if r.pending[messageID] != offer {
+		r.mu.Unlock()
+		return
+	}
+	delete(r.pending, messageID)
+	r.mu.Unlock()
+
+	content := r.localizer.T(offer.first.GuildID, "read_more.expired", offer.first.UserID)
+	if _, err := r.messenger.ChannelMessageEditComplex(&discordgo.MessageEdit{
+		ID:         offer.promptID,
+		Channel:    offer.channelID,
+		Content:    &content,
+		Components: &[]discordgo.MessageComponent{},
+	}); err != nil {
+		r.logger.Printf("Failed to close read more prompt %s in guild %s: %v", offer.promptID, offer.first.GuildID, err)
+	}
+}
+
+// HandleButton handles a click on a "Read the rest" button, whose action is the ID of the
+// long message. The remaining parts are queued when the member is in the bot's voice
+// channel, and the prompt is replaced so the button cannot be clicked again.
+func (r *ReadMore) HandleButton(s *discordgo.Session, i *discordgo.InteractionCreate, messageID string) error {
+	guildID := i.GuildID
+	if guildID == "" {
+		return fmt.Errorf("read more button used outside a guild")
+	}
+
+	userID := interactionUser(i)
+	if r.listeners == nil || !slices.Contains(r.listeners(guildID), userID) {
+		return r.respondError(s, i, r.localizer.T(guildID, "read_more.not_listening"))
+	}
+
+	r.mu.Lock()
+	offer, exists := r.pending[messageID]
+	if exists {
+		delete(r.pending, messageID)
+		if offer.timer != nil {
+			offer.timer.Stop()
+		}
+	}
+	r.mu.Unlock()
+	if !exists {
+		return r.respondError(s, i, r.localizer.T(guildID, "read_more.gone"))
+	}
+
+	queued := 0
+	for index, part := range offer.parts {
+		message := offer.first
+		message.Content = part
+		message.Lead = ""
+		message.Part = index + 2
+		message.Timestamp = time.Now()
+		if err := r.messageQueue.Enqueue(&message); err != nil {
+			r.logger.Printf("Error enqueueing the rest of message %s in guild %s: %v", messageID, guildID, err)
+			break
+		}
+		queued++
+	}
+	if queued == 0 {
+		return r.respondError(s, i, r.localizer.T(guildID, "read_more.queue_failed"))
+	}
+
+	r.logger.Printf("User %s had the rest of message %s read in guild %s (%d parts)", userID, messageID, guildID, queued)
+	return r.respond(s, i, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Content:         r.localizer.T(guildID, "read_more.queued", userID, offer.first.UserID, queued),
+			AllowedMentions: &discordgo.MessageAllowedMentions{},
+			Components:      []discordgo.MessageComponent{},
+		},
+	})
+}
+
+// Stop drops every pending offer
+func (r *ReadMore) Stop() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for messageID, offer := range r.pending {
+		if offer.timer != nil {
+			offer.timer.Stop()
+		}
+		delete(r.pending, messageID)
+	}
+}
+
+// respondError answers a button click with a message only its user can see
+func (r *ReadMore) respondError(s *discordgo.Session, i *discordgo.InteractionCreate, message string) error {
+	return r.respond(s, i, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: "❌ " + message,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
@@ -0,0 +1,175 @@
+package tts
+
+import (
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"darrot/internal/config"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type readMoreTestEnv struct {
+	readMore  *ReadMore
+	queue     MessageQueue
+	messenger *mockQueuePanelMessenger
+	listeners []string
+	responses []*discordgo.InteractionResponse
+}
+
+func setupReadMoreTest(t *testing.T) *readMoreTestEnv {
+	env := &readMoreTestEnv{
+		queue:     NewMessageQueue(),
+		messenger: &mockQueuePanelMessenger{},
+		listeners: []string{"listener1"},
+	}
+
+	env.readMore = NewReadMore(env.queue, env.messenger, log.New(os.Stdout, "[TEST] ", log.LstdFlags))
+	env.readMore.SetComponentIDs(mockComponentIDs{})
+	env.readMore.SetListeners(func(guildID string) []string { return env.listeners })
+	env.readMore.respond = func(s *discordgo.Session, i *discordgo.InteractionCreate, response *discordgo.InteractionResponse) error {
+		env.responses = append(env.responses, response)
+		return nil
+	}
+	t.Cleanup(env.readMore.Stop)
+	return env
+}
+
+// offer offers two more parts of message msg1
+func (env *readMoreTestEnv) offer(t *testing.T) {
+	t.Helper()
+
+	first := &QueuedMessage{ID: "msg1", GuildID: "guild1", ChannelID: "text1", UserID: "author1", Username: "Alice", Content: "Alice says: Part one.", Lead: "Alice says:"}
+	require.NoError(t, env.readMore.Offer(first, []string{"Part two.", "Part three."}))
+}
+
+func (env *readMoreTestEnv) click(userID string) error {
+	return env.readMore.HandleButton(nil, &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+		GuildID: "guild1",
+		Member:  &discordgo.Member{User: &discordgo.User{ID: userID}},
+	}}, "msg1")
+}
+
+func TestReadMore_OfferPostsPrompt(t *testing.T) {
+	env := setupReadMoreTest(t)
+	env.offer(t)
+
+	require.Len(t, env.messenger.sent, 1)
+	prompt := env.messenger.sent[0]
+	assert.Equal(t, "📖 <@author1> wrote more than is read at once. Anyone in the voice channel can have the rest read in the next 2 minutes.", prompt.Content)
+	assert.Equal(t, "msg1", prompt.Reference.MessageID)
+
+	row := prompt.Components[0].(discordgo.ActionsRow)
+	button := row.Components[0].(discordgo.Button)
+	assert.Equal(t, "Read the rest (2 more)", button.Label)
+	assert.Equal(t, ReadMoreNamespace+"//msg1", button.CustomID)
+}
+
+func TestReadMore_ListenerQueuesRest(t *testing.T) {
+	env := setupReadMoreTest(t)
+	env.offer(t)
+
+	require.NoError(t, env.click("listener1"))
+
+	// The rest is queued as the following parts of the message, without the lead
+	assert.Equal(t, 2, env.queue.Size("guild1"))
+	second, err := env.queue.Dequeue("guild1")
+	require.NoError(t, err)
+	assert.Equal(t, "Part two.", second.Content)
+	assert.Equal(t, 2, second.Part)
+	assert.Equal(t, "author1", second.UserID)
+	assert.Empty(t, second.Lead)
+
+	require.Len(t, env.responses, 1)
+	assert.Equal(t, discordgo.InteractionResponseUpdateMessage, env.responses[0].Type)
+	assert.Equal(t, "📖 <@listener1> asked for the rest of <@author1>'s message, 2 more part(s) queued.", env.responses[0].Data.Content)
+	assert.Empty(t, env.responses[0].Data.Components)
+
+	// The rest is only read once
+	require.NoError(t, env.click("listener1"))
+	assert.Equal(t, "❌ The rest of this message is no longer available.", env.responses[1].Data.Content)
+	assert.Equal(t, 1, env.queue.Size("guild1"))
+}
+
+func TestReadMore_OnlyListeners(t *testing.T) {
+	env := setupReadMoreTest(t)
+	env.offer(t)
+
+	require.NoError(t, env.click("outsider"))
+
+	assert.Equal(t, "❌ Only members in the bot's voice channel can have the rest read.", env.responses[0].Data.Content)
+	assert.Equal(t, discordgo.MessageFlagsEphemeral, env.responses[0].Data.Flags)
+	assert.Equal(t, 0, env.queue.Size("guild1"))
+}
+
+func TestReadMore_Expires(t *testing.T) {
+	env := setupReadMoreTest(t)
+	env.readMore.window = 10 * time.Millisecond
+	env.offer(t)
+
+	// The prompt loses its button once the window ends
+	require.Eventually(t, func() bool {
+		env.messenger.mu.Lock()
+		defer env.messenger.mu.Unlock()
+		return len(env.messenger.edits) == 1
+	}, time.Second, 5*time.Millisecond)
+	edit := env.messenger.edits[0]
+	assert.Equal(t, "panel1", edit.ID)
+	assert.Equal(t, "📖 The rest of <@author1>'s message was not read.", *edit.Content)
+	assert.Empty(t, *edit.Components)
+
+	require.NoError(t, env.click("listener1"))
+	assert.Equal(t, "❌ The rest of this message is no longer available.", env.responses[0].Data.Content)
+}
+
+func TestMessageMonitor_AsksBeforeReadingLongMessages(t *testing.T) {
+	logger := log.New(os.Stdout, "[TEST] ", log.LstdFlags)
+
+	storage, err := NewStorageService(t.TempDir())
+	require.NoError(t, err)
+	configService := NewConfigService(storage, config.TTSConfig{DefaultVoice: DefaultVoice, DefaultSpeed: 1.0, DefaultVolume: 1.0, MaxQueueSize: 10})
+	guildConfig := DefaultGuildTTSConfig("guild1")
+	guildConfig.TruncationMode = TruncationModeAsk
+	guildConfig.MaxUtteranceLength = MinMessageLength
+	require.NoError(t, configService.SetGuildConfig("guild1", &guildConfig))
+
+	channelService := newMockChannelService()
+	userService := newMockUserService()
+	messageQueue := newMockMessageQueue()
+	session := &discordgo.Session{}
+
+	monitor := NewMessageMonitor(session, channelService, userService, messageQueue, logger)
+	monitor.SetConfigService(configService)
+	env := setupReadMoreTest(t)
+	monitor.SetReadMore(env.readMore)
+
+	channelService.setPaired("channel1", true)
+	userService.setOptedIn("user1", "guild1", true)
+
+	monitor.handleMessageCreate(session, &discordgo.MessageCreate{
+		Message: &discordgo.Message{
+			ID:        "msg1",
+			Content:   "The first sentence is here. The second sentence follows it. And a third one ends it.",
+			GuildID:   "guild1",
+			ChannelID: "channel1",
+			Author:    &discordgo.User{ID: "user1", Username: "TestUser"},
+		},
+	})
+
+	// Only the first part is read; the rest is offered in the channel
+	messages := messageQueue.getMessages()
+	require.Len(t, messages, 1)
+	assert.Equal(t, "TestUser says: The first sentence is here.", messages[0].Content)
+	require.Len(t, env.messenger.sent, 1)
+	assert.Contains(t, env.messenger.sent[0].Content, "<@user1>")
+
+	require.NoError(t, env.click("listener1"))
+	second, err := env.queue.Dequeue("guild1")
+	require.NoError(t, err)
+	assert.Equal(t, "The second sentence follows it.", second.Content)
+	assert.Equal(t, 2, second.Part)
+}
@@ -0,0 +1,79 @@
+package tts
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// ContentPolicy is the single place that decides whether spoken message content may be
+// written anywhere outside the TTS pipeline. Components that log, dump, cache or record
+// message text must route it through the policy so guilds in metadata-only mode never have
+// content persisted. A nil *ContentPolicy allows everything, matching the default mode.
+type ContentPolicy struct {
+	configService ConfigService
+}
+
+// NewContentPolicy creates a content policy backed by per-guild configuration
+func NewContentPolicy(configService ConfigService) *ContentPolicy {
+	return &ContentPolicy{
+		configService: configService,
+	}
+}
+
+// Mode returns the content retention mode for a guild
+func (p *ContentPolicy) Mode(guildID string) ContentRetention {
+	if p == nil || p.configService == nil || guildID == "" {
+		return ContentRetentionFull
+	}
+
+	config, err := p.configService.GetGuildConfig(guildID)
+	if err != nil || config == nil || config.ContentRetention == "" {
+		return ContentRetentionFull
+	}
+
+	return config.ContentRetention
+}
+
+// ContentFree reports whether only metadata may be retained for a guild
+func (p *ContentPolicy) ContentFree(guildID string) bool {
+	return p.Mode(guildID) == ContentRetentionMetadata
+}
+
+// Loggable returns content when the guild allows it to be logged, or a placeholder
+// describing only its length otherwise
+func (p *ContentPolicy) Loggable(guildID, content string) string {
+	if !p.ContentFree(guildID) {
+		return content
+	}
+	return fmt.Sprintf("[content withheld, %d chars]", utf8.RuneCountInString(content))
+}
+
+// AllowsCaching reports whether content-derived data (such as synthesized audio) may be cached
+func (p *ContentPolicy) AllowsCaching(guildID string) bool {
+	return !p.ContentFree(guildID)
+}
+
+// SetMode changes the content retention mode for a guild
+func (p *ContentPolicy) SetMode(guildID string, mode ContentRetention) error {
+	if p == nil || p.configService == nil {
+		return fmt.Errorf("content policy is not configured")
+	}
+
+	if mode != ContentRetentionFull && mode != ContentRetentionMetadata {
+		return fmt.Errorf("invalid content retention mode: %s", mode)
+	}
+
+	config, err := p.configService.GetGuildConfig(guildID)
+	if err != nil {
+		return fmt.Errorf("failed to get guild config: %w", err)
+	}
+	if config == nil {
+		defaultConfig := DefaultGuildTTSConfig(guildID)
+		config = &defaultConfig
+	}
+
+	updated := *config
+	updated.ContentRetention = mode
+
+	return p.configService.SetGuildConfig(guildID, &updated)
+}
@@ -0,0 +1,86 @@
+package tts
+
+import (
+	"context"
+	"testing"
+
+	"darrot/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createTestContentPolicy(t *testing.T) *ContentPolicy {
+	storage, err := NewStorageService(t.TempDir())
+	require.NoError(t, err)
+
+	configService := NewConfigService(storage, config.TTSConfig{DefaultVoice: DefaultVoice, DefaultSpeed: 1.0, DefaultVolume: 1.0, MaxQueueSize: 10})
+	return NewContentPolicy(configService)
+}
+
+func TestContentPolicy_DefaultsToFull(t *testing.T) {
+	policy := createTestContentPolicy(t)
+
+	assert.Equal(t, ContentRetentionFull, policy.Mode("guild1"))
+	assert.False(t, policy.ContentFree("guild1"))
+	assert.True(t, policy.AllowsCaching("guild1"))
+	assert.Equal(t, "hello there", policy.Loggable("guild1", "hello there"))
+}
+
+func TestContentPolicy_MetadataOnly(t *testing.T) {
+	policy := createTestContentPolicy(t)
+
+	require.NoError(t, policy.SetMode("guild1", ContentRetentionMetadata))
+
+	assert.Equal(t, ContentRetentionMetadata, policy.Mode("guild1"))
+	assert.True(t, policy.ContentFree("guild1"))
+	assert.False(t, policy.AllowsCaching("guild1"))
+	assert.Equal(t, "[content withheld, 11 chars]", policy.Loggable("guild1", "hello there"))
+
+	// Other guilds are unaffected
+	assert.Equal(t, "hello there", policy.Loggable("guild2", "hello there"))
+
+	// Switching back restores content logging
+	require.NoError(t, policy.SetMode("guild1", ContentRetentionFull))
+	assert.Equal(t, "hello there", policy.Loggable("guild1", "hello there"))
+}
+
+func TestContentPolicy_InvalidMode(t *testing.T) {
+	policy := createTestContentPolicy(t)
+
+	assert.Error(t, policy.SetMode("guild1", ContentRetention("everything")))
+}
+
+func TestContentPolicy_NilPolicy(t *testing.T) {
+	var policy *ContentPolicy
+
+	assert.Equal(t, ContentRetentionFull, policy.Mode("guild1"))
+	assert.Equal(t, "hello", policy.Loggable("guild1", "hello"))
+	assert.True(t, policy.AllowsCaching("guild1"))
+	assert.Error(t, policy.SetMode("guild1", ContentRetentionMetadata))
+}
+
+func TestTTSProcessor_ContentFreeGuildSkipsCache(t *testing.T) {
+	ttsManager := &mockTTSManager{}
+	processor := NewTTSProcessor(ttsManager, newMockVoiceManager(), NewMessageQueue(), newMockConfigService(), newMockUserService()).(*ttsProcessor)
+
+	cache := NewAudioCache(1024)
+	policy := createTestContentPolicy(t)
+	require.NoError(t, policy.SetMode("private", ContentRetentionMetadata))
+
+	processor.SetAudioCache(cache)
+	processor.SetContentPolicy(policy)
+
+	config := TTSConfig{Voice: DefaultVoice, Speed: 1.0, Volume: 1.0, Format: AudioFormatDCA}
+	for i := 0; i < 2; i++ {
+		_, err := processor.synthesize(context.Background(), "private", "hello", config)
+		require.NoError(t, err)
+	}
+
+	assert.Len(t, ttsManager.getCallLog(), 2, "content-free guilds should always synthesize")
+	assert.Equal(t, 0, cache.Len())
+
+	_, err := processor.synthesize(context.Background(), "public", "hello", config)
+	require.NoError(t, err)
+	assert.Equal(t, 1, cache.Len())
+}
@@ -0,0 +1,262 @@
+package tts
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"darrot/internal/config"
+	"darrot/internal/events"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// DefaultDataDir is where the TTS system stores guild, user and channel data
+const DefaultDataDir = "./data"
+
+// Services are the shared dependencies the TTS system is built from. Every component
+// and command handler gets its services from here, so each service exists once. Fields
+// left nil are filled in with the production implementation when the system is built,
+// which lets tests assemble the full graph around the mocks they set.
+type Services struct {
+	Events         *events.Bus // Notifications between components, such as utterances for transcripts
+	Storage        *StorageService
+	SessionStorage *StorageService // Channel pairings and voice handoff of this Discord application; Storage unless set
+	Queue          MessageQueue
+	Users          UserService
+	Permissions    PermissionService
+	Config         ConfigService
+	Channels       ChannelService
+	TTS            TTSManager // Google Cloud TTS unless set
+	AudioCache     *AudioCache
+	Voice          VoiceManager
+	Metrics        *Metrics
+	Quota          TTSQuotaService
+	Content        *ContentPolicy
+	Clips          AudioClipService
+	Moderation     ModerationService
+	Stats          StatsService
+	Transcripts    TranscriptService
+	UserData       *UserDataService // Export and deletion of what is stored about a user
+	APITokens      APITokenService
+	Features       *FeatureFlagService
+	Processor      TTSProcessor
+}
+
+// fill builds the production implementation of every service that is not set, in
+// dependency order
+func (s *Services) fill(session *discordgo.Session, cfg *config.Config, logger *log.Logger) error {
+	if s.Events == nil {
+		s.Events = events.New()
+	}
+	if s.Storage == nil {
+		storage, err := NewStorageService(DefaultDataDir)
+		if err != nil {
+			return fmt.Errorf("failed to initialize storage service: %w", err)
+		}
+		// Guild, user and transcript data is encrypted at rest when keys are configured
+		ctx, cancel := context.WithTimeout(context.Background(), storageKeyLoadTimeout)
+		cipher, err := LoadStorageCipher(ctx, cfg.TTS)
+		cancel()
+		if err != nil {
+			return fmt.Errorf("failed to load storage encryption keys: %w", err)
+		}
+		if err := storage.EnableEncryption(cipher); err != nil {
+			return err
+		}
+		s.Storage = storage
+	}
+	if s.SessionStorage == nil {
+		s.SessionStorage = s.Storage
+	}
+
+	sessionWrapper := NewDiscordSessionWrapper(session)
+	if s.Queue == nil {
+		s.Queue = NewMessageQueue()
+	}
+	if s.Users == nil {
+		s.Users = NewUserService(s.Storage)
+	}
+	if s.Permissions == nil {
+		s.Permissions = NewPermissionService(sessionWrapper, s.Storage, logger)
+	}
+	if s.Config == nil {
+		s.Config = NewConfigService(s.Storage, cfg.TTS)
+	}
+	if mq, ok := s.Queue.(*MessageQueueImpl); ok {
+		mq.SetConfigService(s.Config)
+	}
+	if permissions, ok := s.Permissions.(*PermissionServiceImpl); ok {
+		permissions.SetConfigService(s.Config)
+	}
+	if s.Channels == nil {
+		channels := NewChannelService(s.SessionStorage, sessionWrapper, s.Permissions)
+		channels.SetConfigService(s.Config)
+		s.Channels = channels
+	}
+	if s.TTS == nil {
+		s.TTS = newDefaultTTSManager(s.Queue, cfg, logger)
+	}
+	if s.Voice == nil {
+		s.Voice = NewVoiceManager(session)
+	}
+
+	// Usage tracking and budget enforcement
+	if s.Metrics == nil {
+		s.Metrics = NewMetrics()
+	}
+	if vm, ok := s.Voice.(*voiceManager); ok {
+		vm.SetMetrics(s.Metrics)
+	}
+	// Each Discord application has its own queues, so they spill next to its sessions and
+	// are encrypted like them
+	if mq, ok := s.Queue.(*MessageQueueImpl); ok && cfg.TTS.QueueSpilloverMB > 0 {
+		if err := mq.SetSpillover(s.SessionStorage.QueueSpilloverDir(), int64(cfg.TTS.QueueSpilloverMB)<<20, s.SessionStorage.Cipher(), s.Metrics); err != nil {
+			return fmt.Errorf("failed to initialize queue spillover: %w", err)
+		}
+	}
+	if s.Quota == nil {
+		s.Quota = NewTTSQuotaService(s.Storage, s.Config, cfg.TTS.DailyCharacterBudget, s.Metrics)
+	}
+
+	// Content retention is enforced centrally for every component that logs or caches message text
+	if s.Content == nil {
+		s.Content = NewContentPolicy(s.Config)
+	}
+
+	// Audio clips are encoded once on upload with the same pipeline as synthesized speech,
+	// and blocked words are bleeped with the same encoder
+	encoder, _ := s.TTS.(AudioClipEncoder)
+	if s.Clips == nil {
+		s.Clips = NewAudioClipService(s.Storage, encoder, DefaultClipLimits())
+	}
+	if s.Moderation == nil {
+		s.Moderation = NewModerationService(s.Storage, encoder)
+	}
+
+	// Per-guild usage statistics for /darrot-stats
+	if s.Stats == nil {
+		s.Stats = NewStatsService(s.Storage)
+	}
+
+	// Session transcripts for /darrot-transcript, kept only in guilds that turn them on
+	if s.Transcripts == nil {
+		transcripts := NewTranscriptService(s.Storage, s.Config)
+		transcripts.SetContentPolicy(s.Content)
+		transcripts.Subscribe(s.Events)
+		s.Transcripts = transcripts
+	}
+
+	// Data-protection requests reach every service that stores data about a user
+	if s.UserData == nil {
+		s.UserData = NewUserDataService(s.Storage, s.Users, s.Stats, s.Transcripts)
+	}
+
+	// Tokens that let external systems queue messages through the HTTP API
+	if s.APITokens == nil {
+		s.APITokens = NewAPITokenService(s.Storage)
+	}
+
+	// Experimental features, turned on by the operator or per guild
+	if s.Features == nil {
+		defaults, err := ParseFeatureDefaults(cfg.TTS.FeatureList())
+		if err != nil {
+			logger.Printf("Warning: Ignoring tts.features entries: %v", err)
+		}
+		s.Features = NewFeatureFlagService(s.Config, defaults)
+	}
+
+	if s.AudioCache == nil {
+		s.AudioCache = NewAudioCache(DefaultAudioCacheBytes)
+	}
+	if s.Processor == nil {
+		s.Processor = s.newProcessor(cfg, logger)
+	}
+
+	// Services that publish on the event bus
+	for _, service := range []any{s.Queue, s.Config, s.Permissions, s.Voice} {
+		if publisher, ok := service.(eventPublisher); ok {
+			publisher.SetEventBus(s.Events)
+		}
+	}
+	return nil
+}
+
+// ForApplication returns the services another Discord application run from the same
+// process is built from. The TTS backend, the audio cache and everything kept per guild,
+// such as settings, opt-ins, usage and clips, are shared. Everything tied to a Discord
+// session, such as voice connections, channel pairings, queues and the processor, is left
+// unset so it is built for the application, keeping its pairings and handoff in
+// sessionStorage.
+func (s *Services) ForApplication(sessionStorage *StorageService) *Services {
+	return &Services{
+		Events:         s.Events,
+		Storage:        s.Storage,
+		SessionStorage: sessionStorage,
+		Users:          s.Users,
+		Config:         s.Config,
+		TTS:            s.TTS,
+		AudioCache:     s.AudioCache,
+		Metrics:        s.Metrics,
+		Quota:          s.Quota,
+		Content:        s.Content,
+		Clips:          s.Clips,
+		Moderation:     s.Moderation,
+		Stats:          s.Stats,
+		Transcripts:    s.Transcripts,
+		UserData:       s.UserData,
+		APITokens:      s.APITokens,
+		Features:       s.Features,
+	}
+}
+
+// eventPublisher is a service that publishes on the event bus
+type eventPublisher interface {
+	SetEventBus(bus *events.Bus)
+}
+
+// newProcessor creates the TTS processor with every optional service attached
+func (s *Services) newProcessor(cfg *config.Config, logger *log.Logger) TTSProcessor {
+	processor := NewTTSProcessor(s.TTS, s.Voice, s.Queue, s.Config, s.Users)
+	if tp, ok := processor.(*ttsProcessor); ok {
+		tp.SetQuotaService(s.Quota)
+		tp.SetAudioCache(s.AudioCache)
+		tp.SetMetrics(s.Metrics)
+		tp.SetContentPolicy(s.Content)
+		tp.SetClipService(s.Clips)
+		tp.SetModerationService(s.Moderation)
+		tp.SetTextNormalizer(NewTextNormalizer())
+		tp.SetStatsService(s.Stats)
+		tp.SetTranscriptService(s.Transcripts)
+		tp.SetEventBus(s.Events)
+		tp.SetChannelService(s.Channels)
+		tp.SetFeatureFlags(s.Features)
+		tp.SetQueuePressurePolicy(NewQueuePressurePolicy(cfg.TTS.BacklogThreshold, cfg.TTS.BacklogMaxSpeed, s.Metrics, logger))
+		if cfg.TTS.Workers > 0 {
+			tp.SetWorkerCount(cfg.TTS.Workers)
+		}
+	}
+	return processor
+}
+
+// newDefaultTTSManager creates the Google Cloud TTS manager, falling back to degraded mode
+// when the credentials cannot be used
+func newDefaultTTSManager(queue MessageQueue, cfg *config.Config, logger *log.Logger) TTSManager {
+	credentials := CredentialSource{Path: cfg.TTS.GoogleCloudCredentialsPath, Secret: cfg.TTS.GoogleCloudCredentialsSecret}
+	googleManager, err := NewGoogleTTSManagerWithCredentials(queue, credentials, cfg.TTS.GoogleCloudEndpoint)
+	if err != nil {
+		// Commands still work without speech; the health checker reconnects once credentials are valid
+		logger.Printf("Warning: Starting in degraded mode without text-to-speech: %v", err)
+		googleManager = NewDegradedGoogleTTSManager(queue, credentials, cfg.TTS.GoogleCloudEndpoint, err)
+	}
+	if cfg.TTS.SynthesisTimeout > 0 {
+		googleManager.SetSynthesisTimeout(time.Duration(cfg.TTS.SynthesisTimeout) * time.Second)
+	}
+	if cfg.TTS.GoogleCloudEndpoint != "" {
+		logger.Printf("Using Google Cloud TTS Manager at %s", cfg.TTS.GoogleCloudEndpoint)
+	} else {
+		logger.Println("Using Google Cloud TTS Manager")
+	}
+	return googleManager
+}
@@ -0,0 +1,293 @@
+package tts
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"darrot/internal/events"
+)
+
+// MaxSessionRecordingBytes caps a session recording so it can be posted to Discord, which
+// refuses larger attachments in servers without boosts. At 64kbps it holds about 17
+// minutes of speech; later speech is not recorded.
+const MaxSessionRecordingBytes = 8 * 1024 * 1024
+
+// SessionRecorder records the speech the bot plays in guilds that opt in, one Ogg Opus
+// file per voice session. The recording is posted when the session ends with
+// /darrot-leave, and discarded when a session ends any other way.
+type SessionRecorder struct {
+	contentPolicy *ContentPolicy
+	logger        *log.Logger
+
+	mu       sync.Mutex
+	sessions map[string]*recordingSession
+	pending  map[string]events.Utterance // Utterances playing whose audio has not been recorded yet
+}
+
+// recordingSession is the recording of one guild's voice session
+type recordingSession struct {
+	file       *os.File
+	ogg        *oggOpusWriter
+	size       *countingWriter
+	startedAt  time.Time
+	recorded   time.Duration
+	timestamps bool
+	cues       []recordingCue
+	truncated  bool
+}
+
+// recordingCue is the position of one utterance in a recording
+type recordingCue struct {
+	start, end time.Duration
+	speaker    string
+	text       string
+}
+
+// SessionRecording is a finished session recording. Close removes it.
+type SessionRecording struct {
+	Audio      *os.File
+	Timestamps []byte // WebVTT cues of each utterance, or nil unless the guild asked for them
+	StartedAt  time.Time
+	Duration   time.Duration
+	Truncated  bool // Whether speech was left out to keep the recording under its size limit
+}
+
+// Close removes the recording
+func (r *SessionRecording) Close() error {
+	r.Audio.Close()
+	return os.Remove(r.Audio.Name())
+}
+
+// NewSessionRecorder creates a session recorder with no sessions
+func NewSessionRecorder(logger *log.Logger) *SessionRecorder {
+	return &SessionRecorder{
+		logger:   logger,
+		sessions: make(map[string]*recordingSession),
+		pending:  make(map[string]events.Utterance),
+	}
+}
+
+// SetContentPolicy keeps guilds that retain only metadata from being recorded
+func (r *SessionRecorder) SetContentPolicy(policy *ContentPolicy) {
+	r.contentPolicy = policy
+}
+
+// Record appends DCA audio played in a guild to its session recording, starting the
+// recording if the guild opted in. Recordings are local temporary files, so this is fast
+// enough to do as the audio plays.
+func (r *SessionRecorder) Record(guildID string, config *GuildTTSConfig, audioData []byte) {
+	if r == nil || config == nil || !config.RecordSessions || r.contentPolicy.ContentFree(guildID) {
+		return
+	}
+
+	packets, err := parseDCAFrames(audioData)
+	if err != nil || len(packets) == 0 {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	session, ok := r.sessions[guildID]
+	if !ok {
+		session, err = newRecordingSession(config.RecordTimestamps)
+		if err != nil {
+			r.logger.Printf("Warning: failed to start recording guild %s: %v", guildID, err)
+			return
+		}
+		r.sessions[guildID] = session
+	}
+	if session.truncated {
+		return
+	}
+
+	duration := time.Duration(len(packets)) * dcaFrameDuration
+	if session.size.n+opusPacketsSize(packets) > MaxSessionRecordingBytes {
+		session.truncated = true
+		r.logger.Printf("Recording of guild %s reached %d bytes, not recording later speech", guildID, MaxSessionRecordingBytes)
+		return
+	}
+	if err := session.ogg.WritePackets(packets); err != nil {
+		session.truncated = true
+		r.logger.Printf("Warning: failed to record guild %s: %v", guildID, err)
+		return
+	}
+
+	if utterance, ok := r.pending[guildID]; ok && session.timestamps {
+		session.cues = append(session.cues, recordingCue{
+			start:   session.recorded,
+			end:     session.recorded + duration,
+			speaker: utterance.Username,
+			text:    utterance.Text,
+		})
+	}
+	delete(r.pending, guildID)
+	session.recorded += duration
+}
+
+// Finish ends a guild's session recording and returns it, or returns false when the
+// guild has no recording
+func (r *SessionRecorder) Finish(guildID string) (*SessionRecording, bool) {
+	if r == nil {
+		return nil, false
+	}
+
+	r.mu.Lock()
+	session, ok := r.sessions[guildID]
+	delete(r.sessions, guildID)
+	r.mu.Unlock()
+	if !ok {
+		return nil, false
+	}
+
+	recording, err := session.finish()
+	if err != nil {
+		r.logger.Printf("Warning: failed to finish recording of guild %s: %v", guildID, err)
+		session.discard()
+		return nil, false
+	}
+	return recording, true
+}
+
+// Subscribe notes when utterances start so recordings can be timestamped, and discards
+// the recording of a session that ended without /darrot-leave when the bot joins again
+func (r *SessionRecorder) Subscribe(bus *events.Bus) (unsubscribe func()) {
+	unsubscribeStarted := events.Subscribe(bus, func(e events.UtteranceStarted) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.pending[e.GuildID] = e.Utterance
+	})
+	unsubscribeFinished := events.Subscribe(bus, func(e events.UtteranceFinished) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		delete(r.pending, e.GuildID)
+	})
+	unsubscribeJoined := events.Subscribe(bus, func(e events.VoiceJoined) {
+		r.discard(e.GuildID)
+	})
+	return func() {
+		unsubscribeStarted()
+		unsubscribeFinished()
+		unsubscribeJoined()
+	}
+}
+
+// Close discards every recording in progress
+func (r *SessionRecorder) Close() {
+	r.mu.Lock()
+	sessions := r.sessions
+	r.sessions = make(map[string]*recordingSession)
+	r.mu.Unlock()
+
+	for _, session := range sessions {
+		session.discard()
+	}
+}
+
+// discard drops a guild's recording in progress
+func (r *SessionRecorder) discard(guildID string) {
+	r.mu.Lock()
+	session, ok := r.sessions[guildID]
+	delete(r.sessions, guildID)
+	r.mu.Unlock()
+
+	if ok {
+		session.discard()
+	}
+}
+
+// newRecordingSession starts a recording in a temporary file
+func newRecordingSession(timestamps bool) (*recordingSession, error) {
+	file, err := os.CreateTemp("", "darrot-recording-*.ogg")
+	if err != nil {
+		return nil, err
+	}
+	size := &countingWriter{w: file}
+	return &recordingSession{
+		file:       file,
+		ogg:        newOggOpusWriter(size, uint32(time.Now().UnixNano())),
+		size:       size,
+		startedAt:  time.Now(),
+		timestamps: timestamps,
+	}, nil
+}
+
+// finish ends the Ogg stream and rewinds the file for reading
+func (s *recordingSession) finish() (*SessionRecording, error) {
+	if err := s.ogg.Close(); err != nil {
+		return nil, err
+	}
+	if _, err := s.file.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	recording := &SessionRecording{
+		Audio:     s.file,
+		StartedAt: s.startedAt,
+		Duration:  s.recorded,
+		Truncated: s.truncated,
+	}
+	if s.timestamps {
+		recording.Timestamps = webVTT(s.cues)
+	}
+	return recording, nil
+}
+
+// discard removes the recording
+func (s *recordingSession) discard() {
+	s.file.Close()
+	os.Remove(s.file.Name())
+}
+
+// webVTT formats cues as a WebVTT file, which players show as captions next to the
+// recording
+func webVTT(cues []recordingCue) []byte {
+	var b strings.Builder
+	b.WriteString("WEBVTT\n")
+	for _, cue := range cues {
+		fmt.Fprintf(&b, "\n%s --> %s\n", vttTimestamp(cue.start), vttTimestamp(cue.end))
+		if cue.speaker != "" {
+			fmt.Fprintf(&b, "<v %s>", vttSpeakerReplacer.Replace(cue.speaker))
+		}
+		b.WriteString(vttTextReplacer.Replace(cue.text) + "\n")
+	}
+	return []byte(b.String())
+}
+
+// Cue text is escaped like HTML, and may not hold the blank line that ends a cue
+var (
+	vttTextReplacer    = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "\n", " ")
+	vttSpeakerReplacer = strings.NewReplacer("&", "&amp;", "<", "", ">", "", "\n", " ")
+)
+
+// vttTimestamp formats a position in a recording as hh:mm:ss.ttt
+func vttTimestamp(d time.Duration) string {
+	return fmt.Sprintf("%02d:%02d:%02d.%03d", int(d.Hours()), int(d.Minutes())%60, int(d.Seconds())%60, d.Milliseconds()%1000)
+}
+
+// opusPacketsSize returns roughly how many bytes packets take in an Ogg stream
+func opusPacketsSize(packets [][]byte) int64 {
+	pages := int64(len(packets)/oggMaxSegments + 1)
+	size := pages * oggPageHeaderLength
+	for _, packet := range packets {
+		size += int64(len(packet)) + 1 // One lacing value per packet below 255 bytes
+	}
+	return size
+}
+
+// countingWriter counts the bytes written through it
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,124 @@
+package tts
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"darrot/internal/events"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionRecorder_Record(t *testing.T) {
+	bus := events.New()
+	recorder := NewSessionRecorder(log.New(io.Discard, "", 0))
+	defer recorder.Subscribe(bus)()
+	config := &GuildTTSConfig{RecordSessions: true, RecordTimestamps: true}
+
+	greeting := opusPackets(t, goldenTone(100*time.Millisecond))
+	bus.Publish(events.UtteranceStarted{Utterance: events.Utterance{GuildID: "guild123", Username: "Alice", Text: "hello"}})
+	recorder.Record("guild123", config, testDCA(t, greeting...))
+	bus.Publish(events.UtteranceFinished{Utterance: events.Utterance{GuildID: "guild123"}, Completed: true})
+
+	// Clips and other audio without an utterance are recorded without a cue
+	clip := opusPackets(t, goldenTone(60*time.Millisecond))
+	recorder.Record("guild123", config, testDCA(t, clip...))
+
+	recorder.Record("guild456", &GuildTTSConfig{}, testDCA(t, clip...))
+
+	recording, ok := recorder.Finish("guild123")
+	require.True(t, ok)
+	defer recording.Close()
+
+	data, err := io.ReadAll(recording.Audio)
+	require.NoError(t, err)
+	packets, err := demuxOggOpus(data)
+	require.NoError(t, err)
+	assert.Equal(t, append(greeting, clip...), packets)
+	assert.Equal(t, time.Duration(len(packets))*dcaFrameDuration, recording.Duration)
+	assert.False(t, recording.Truncated)
+	assert.Equal(t, "WEBVTT\n\n00:00:00.000 --> 00:00:00.100\n<v Alice>hello\n", string(recording.Timestamps))
+
+	_, ok = recorder.Finish("guild123")
+	assert.False(t, ok, "finishing ends the session")
+	_, ok = recorder.Finish("guild456")
+	assert.False(t, ok, "guilds that did not opt in are not recorded")
+}
+
+func TestSessionRecorder_ContentFree(t *testing.T) {
+	configService := createTestExportConfigService(t)
+	config := DefaultGuildTTSConfig("guild123")
+	config.ContentRetention = ContentRetentionMetadata
+	config.RecordSessions = true
+	require.NoError(t, configService.SetGuildConfig("guild123", &config))
+
+	recorder := NewSessionRecorder(log.New(io.Discard, "", 0))
+	recorder.SetContentPolicy(NewContentPolicy(configService))
+	recorder.Record("guild123", &config, testDCA(t, []byte{0xF8}))
+
+	_, ok := recorder.Finish("guild123")
+	assert.False(t, ok)
+}
+
+func TestSessionRecorder_SizeLimit(t *testing.T) {
+	recorder := NewSessionRecorder(log.New(io.Discard, "", 0))
+	config := &GuildTTSConfig{RecordSessions: true}
+
+	// Three messages of about 3MB each, of which only two fit
+	packets := make([][]byte, 1000)
+	for i := range packets {
+		packets[i] = bytes.Repeat([]byte{0xF8}, 3000)
+	}
+	message := testDCA(t, packets...)
+	for range 3 {
+		recorder.Record("guild123", config, message)
+	}
+
+	recording, ok := recorder.Finish("guild123")
+	require.True(t, ok)
+	defer recording.Close()
+
+	info, err := recording.Audio.Stat()
+	require.NoError(t, err)
+	assert.LessOrEqual(t, info.Size(), int64(MaxSessionRecordingBytes))
+	assert.True(t, recording.Truncated)
+	assert.Equal(t, 2*time.Duration(len(packets))*dcaFrameDuration, recording.Duration)
+	assert.Nil(t, recording.Timestamps, "timestamps are only posted when asked for")
+}
+
+func TestSessionRecorder_DiscardsUnfinishedSessions(t *testing.T) {
+	bus := events.New()
+	recorder := NewSessionRecorder(log.New(io.Discard, "", 0))
+	defer recorder.Subscribe(bus)()
+	config := &GuildTTSConfig{RecordSessions: true}
+
+	recorder.Record("guild123", config, testDCA(t, []byte{0xF8}))
+	recorder.Record("guild456", config, testDCA(t, []byte{0xF8}))
+	name := recorder.sessions["guild123"].file.Name()
+
+	bus.Publish(events.VoiceJoined{GuildID: "guild123", JoinedAt: time.Now()})
+	_, ok := recorder.Finish("guild123")
+	assert.False(t, ok, "joining again starts a new session")
+	_, err := os.Stat(name)
+	assert.True(t, os.IsNotExist(err), "the discarded recording is removed")
+
+	name = recorder.sessions["guild456"].file.Name()
+	recorder.Close()
+	_, err = os.Stat(name)
+	assert.True(t, os.IsNotExist(err), "closing removes recordings in progress")
+}
+
+func TestWebVTT(t *testing.T) {
+	vtt := webVTT([]recordingCue{
+		{start: 90 * time.Minute, end: 90*time.Minute + 1500*time.Millisecond, speaker: "<Bob>", text: "a --> b & c\nd"},
+		{start: 2 * time.Hour, end: 2*time.Hour + time.Second, text: "Alice joined"},
+	})
+	assert.Equal(t, "WEBVTT\n\n"+
+		"01:30:00.000 --> 01:30:01.500\n<v Bob>a --&gt; b &amp; c d\n\n"+
+		"02:00:00.000 --> 02:00:01.000\nAlice joined\n", string(vtt))
+}
@@ -35,3 +35,8 @@ func (w *DiscordSessionWrapper) Channel(channelID string) (*discordgo.Channel, e
 func (w *DiscordSessionWrapper) UserChannelPermissions(userID, channelID string) (int64, error) {
 	return w.session.UserChannelPermissions(userID, channelID)
 }
+
+// Application retrieves the settings of an application, "@me" for the bot's own
+func (w *DiscordSessionWrapper) Application(appID string) (*discordgo.Application, error) {
+	return w.session.Application(appID)
+}
@@ -0,0 +1,156 @@
+package tts
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// DefaultDrainTimeout is how long a shutdown waits for the messages being spoken and the
+// farewell announcements to finish
+const DefaultDrainTimeout = 10 * time.Second
+
+// ShutdownSequence lets the bot leave its voice channels gracefully: it finishes the
+// messages being spoken, optionally says goodbye in each voice channel and posts a notice
+// in each paired text channel. Saving the remaining queues and disconnecting are left to
+// the handoff manager and the voice manager, which stop after it.
+type ShutdownSequence struct {
+	voiceManager   VoiceManager
+	channelService ChannelService
+	drainer        ProcessorDrainer // Nil when the processor cannot drain
+	messenger      HandoffMessenger
+	localizer      *Localizer
+	logger         *log.Logger
+
+	drainTimeout time.Duration
+	farewell     bool
+}
+
+// NewShutdownSequence creates a shutdown sequence with the default drain timeout and the
+// farewell announcement on. The messenger may be nil, in which case no notices are posted.
+func NewShutdownSequence(
+	voiceManager VoiceManager,
+	channelService ChannelService,
+	ttsProcessor TTSProcessor,
+	messenger HandoffMessenger,
+	logger *log.Logger,
+) *ShutdownSequence {
+	drainer, _ := ttsProcessor.(ProcessorDrainer)
+	return &ShutdownSequence{
+		voiceManager:   voiceManager,
+		channelService: channelService,
+		drainer:        drainer,
+		messenger:      messenger,
+		logger:         logger,
+		drainTimeout:   DefaultDrainTimeout,
+		farewell:       true,
+	}
+}
+
+// SetLocalizer sets the localizer used to translate the farewell and the notice
+func (s *ShutdownSequence) SetLocalizer(localizer *Localizer) {
+	s.localizer = localizer
+}
+
+// SetDrainTimeout sets how long the shutdown waits for speech to finish. Zero stops
+// mid-sentence without a farewell.
+func (s *ShutdownSequence) SetDrainTimeout(timeout time.Duration) {
+	s.drainTimeout = timeout
+}
+
+// SetFarewell turns the spoken farewell on or off
+func (s *ShutdownSequence) SetFarewell(enabled bool) {
+	s.farewell = enabled
+}
+
+// Run drains the processor, says goodbye and posts the shutdown notices. It must be
+// called before the processor is stopped and the voice connections are closed.
+func (s *ShutdownSequence) Run() {
+	pairings := s.pairedChannels()
+	if len(pairings) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.drainTimeout)
+	defer cancel()
+
+	// Draining also keeps the processor from taking messages off the queues before they
+	// are saved, even without a timeout
+	if s.drainer != nil {
+		s.logger.Printf("Finishing the messages being spoken in %d guild(s) before shutting down", len(pairings))
+		drained := s.drainer.Drain(ctx)
+		switch {
+		case s.drainTimeout <= 0:
+		case !drained:
+			s.logger.Printf("Warning: Messages were still being spoken after %s, stopping mid-sentence", s.drainTimeout)
+		case s.farewell:
+			s.sayFarewell(ctx, pairings)
+		}
+	}
+
+	s.postNotices(pairings)
+}
+
+// pairedChannels returns the pairing of every voice connection that has one
+func (s *ShutdownSequence) pairedChannels() []*ChannelPairing {
+	var pairings []*ChannelPairing
+	for _, guildID := range s.voiceManager.GetActiveConnections() {
+		connection, exists := s.voiceManager.GetConnection(guildID)
+		if !exists {
+			continue
+		}
+
+		pairing, err := s.channelService.GetPairing(guildID, connection.ChannelID)
+		if err != nil || pairing == nil {
+			continue // Nothing is being read in this channel
+		}
+		pairings = append(pairings, pairing)
+	}
+	return pairings
+}
+
+// sayFarewell speaks the farewell in every voice channel at once, giving up when ctx is
+// done. Paused guilds are skipped, since moderators asked for silence.
+func (s *ShutdownSequence) sayFarewell(ctx context.Context, pairings []*ChannelPairing) {
+	var wg sync.WaitGroup
+	for _, pairing := range pairings {
+		if s.voiceManager.IsPaused(pairing.GuildID) {
+			continue
+		}
+
+		wg.Add(1)
+		go func(guildID string) {
+			defer wg.Done()
+			if err := s.drainer.Announce(ctx, guildID, s.localizer.T(guildID, "shutdown.farewell")); err != nil {
+				s.logger.Printf("Warning: Failed to say farewell in guild %s: %v", guildID, err)
+			}
+		}(pairing.GuildID)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		s.logger.Printf("Warning: Farewell announcements did not finish within %s", s.drainTimeout)
+	}
+}
+
+// postNotices tells each paired text channel that the bot is going offline
+func (s *ShutdownSequence) postNotices(pairings []*ChannelPairing) {
+	if s.messenger == nil {
+		return
+	}
+
+	for _, pairing := range pairings {
+		message := s.localizer.T(pairing.GuildID, "shutdown.notice")
+		if _, err := s.messenger.ChannelMessageSend(pairing.TextChannelID, message); err != nil {
+			s.logger.Printf("Warning: Failed to post shutdown notice in channel %s: %v", pairing.TextChannelID, err)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package tts
+
+import (
+	"context"
+	"log"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDrainingProcessor records drains and announcements
+type fakeDrainingProcessor struct {
+	mockTTSProcessorForRecovery
+	drainResult bool
+
+	mu         sync.Mutex
+	drains     int
+	announced  map[string]string
+	announceFn func(ctx context.Context) error
+}
+
+func (p *fakeDrainingProcessor) Drain(ctx context.Context) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.drains++
+	return p.drainResult
+}
+
+func (p *fakeDrainingProcessor) Announce(ctx context.Context, guildID, text string) error {
+	if p.announceFn != nil {
+		if err := p.announceFn(ctx); err != nil {
+			return err
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.announced[guildID] = text
+	return nil
+}
+
+type shutdownTestEnv struct {
+	*handoffTestEnv
+	processor *fakeDrainingProcessor
+}
+
+func (e *shutdownTestEnv) newSequence() *ShutdownSequence {
+	return NewShutdownSequence(e.voiceManager, e.channelService, e.processor, e.messenger, log.New(os.Stdout, "", 0))
+}
+
+func setupShutdownTest(t *testing.T) *shutdownTestEnv {
+	env := &shutdownTestEnv{
+		handoffTestEnv: setupHandoffTest(t),
+		processor:      &fakeDrainingProcessor{drainResult: true, announced: make(map[string]string)},
+	}
+
+	_, err := env.voiceManager.JoinChannel("guild1", "voice1")
+	require.NoError(t, err)
+	require.NoError(t, env.channelService.CreatePairingWithCreator("guild1", "voice1", "text1", "user1"))
+
+	// A connection without a pairing is left alone
+	_, err = env.voiceManager.JoinChannel("guild2", "voice2")
+	require.NoError(t, err)
+
+	return env
+}
+
+func TestShutdownSequence_FarewellAndNotice(t *testing.T) {
+	env := setupShutdownTest(t)
+
+	env.newSequence().Run()
+
+	assert.Equal(t, 1, env.processor.drains)
+	assert.Equal(t, map[string]string{"guild1": "I'm going offline for maintenance. See you soon!"}, env.processor.announced)
+	assert.Equal(t, map[string]string{"text1": "🔌 I'm going offline for maintenance. Messages posted now won't be read; I'll pick up where I left off when I'm back."}, env.messenger.messages)
+}
+
+func TestShutdownSequence_DrainTimeoutSkipsFarewell(t *testing.T) {
+	env := setupShutdownTest(t)
+	env.processor.drainResult = false
+
+	env.newSequence().Run()
+
+	assert.Empty(t, env.processor.announced, "no farewell while a message is still being spoken")
+	assert.Contains(t, env.messenger.messages, "text1", "the notice is posted either way")
+}
+
+func TestShutdownSequence_FarewellOff(t *testing.T) {
+	env := setupShutdownTest(t)
+
+	sequence := env.newSequence()
+	sequence.SetFarewell(false)
+	sequence.Run()
+
+	assert.Equal(t, 1, env.processor.drains)
+	assert.Empty(t, env.processor.announced)
+	assert.Contains(t, env.messenger.messages, "text1")
+}
+
+func TestShutdownSequence_ZeroTimeoutStopsRightAway(t *testing.T) {
+	env := setupShutdownTest(t)
+
+	sequence := env.newSequence()
+	sequence.SetDrainTimeout(0)
+	sequence.Run()
+
+	assert.Equal(t, 1, env.processor.drains, "the processor still stops taking messages off the queues")
+	assert.Empty(t, env.processor.announced)
+	assert.Contains(t, env.messenger.messages, "text1")
+}
+
+func TestShutdownSequence_SkipsPausedGuilds(t *testing.T) {
+	env := setupShutdownTest(t)
+	require.NoError(t, env.voiceManager.PausePlayback("guild1"))
+
+	env.newSequence().Run()
+
+	assert.Empty(t, env.processor.announced)
+}
+
+func TestShutdownSequence_FarewellBoundedByTimeout(t *testing.T) {
+	env := setupShutdownTest(t)
+	env.processor.announceFn = func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	sequence := env.newSequence()
+	sequence.SetDrainTimeout(50 * time.Millisecond)
+
+	started := time.Now()
+	sequence.Run()
+
+	assert.Less(t, time.Since(started), time.Second)
+	assert.Contains(t, env.messenger.messages, "text1")
+}
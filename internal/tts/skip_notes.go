@@ -0,0 +1,192 @@
+package tts
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Skip note timing
+const (
+	SkipNoteQuietDelay = 5 * time.Second // Skip-free time before a note is spoken, unless a read message comes first
+	SkipNoteInterval   = time.Minute     // Shortest time between two notes in a guild
+)
+
+// SkipReason is why a message in a paired channel was not read
+type SkipReason string
+
+// Reasons a skip note counts
+const (
+	SkipNotOptedIn SkipReason = "not_opted_in" // The author has not opted in
+	SkipMuted      SkipReason = "muted"        // A listener in the voice channel muted the author
+	SkipModerated  SkipReason = "moderated"    // Moderation dropped the message
+)
+
+// skipReasons is the order reasons are spoken in
+var skipReasons = []SkipReason{SkipNotOptedIn, SkipMuted, SkipModerated}
+
+// SkipNotes speaks a low-priority note saying how many messages were skipped and why
+// ("3 messages from muted users were skipped"), so listeners understand gaps in the
+// conversation. Skips are counted until none arrive for SkipNoteQuietDelay or a message
+// is read, and a guild hears at most one note per SkipNoteInterval.
+type SkipNotes struct {
+	messageQueue  MessageQueue
+	configService ConfigService
+	localizer     *Localizer
+	logger        *log.Logger
+
+	quietDelay time.Duration
+	interval   time.Duration
+	now        func() time.Time
+
+	mu       sync.Mutex
+	skipped  map[string]*skippedMessages // Skips waiting to be noted per guild
+	lastNote map[string]time.Time        // When each guild last heard a note
+	stopped  bool
+}
+
+// skippedMessages are the messages skipped in a guild since the last note
+type skippedMessages struct {
+	channelID string
+	counts    map[SkipReason]int
+	timer     *time.Timer
+}
+
+// NewSkipNotes creates the skip notes of the guilds that turn them on
+func NewSkipNotes(messageQueue MessageQueue, configService ConfigService, logger *log.Logger) *SkipNotes {
+	return &SkipNotes{
+		messageQueue:  messageQueue,
+		configService: configService,
+		logger:        logger,
+		quietDelay:    SkipNoteQuietDelay,
+		interval:      SkipNoteInterval,
+		now:           time.Now,
+		skipped:       make(map[string]*skippedMessages),
+		lastNote:      make(map[string]time.Time),
+	}
+}
+
+// SetLocalizer sets the localizer used to translate notes
+func (n *SkipNotes) SetLocalizer(localizer *Localizer) {
+	n.localizer = localizer
+}
+
+// Enabled reports whether skip notes are turned on for a guild
+func (n *SkipNotes) Enabled(guildID string) bool {
+	config, err := n.configService.GetGuildConfig(guildID)
+	if err != nil || config == nil {
+		return false
+	}
+	return config.AnnounceSkipped
+}
+
+// SetEnabled turns skip notes on or off for a guild
+func (n *SkipNotes) SetEnabled(guildID string, enabled bool) error {
+	config, err := n.configService.GetGuildConfig(guildID)
+	if err != nil {
+		return fmt.Errorf("failed to get guild config: %w", err)
+	}
+	if config == nil {
+		defaultConfig := DefaultGuildTTSConfig(guildID)
+		config = &defaultConfig
+	}
+
+	updated := *config
+	updated.AnnounceSkipped = enabled
+
+	return n.configService.SetGuildConfig(guildID, &updated)
+}
+
+// Skipped counts a message skipped in a guild's paired text channel. A nil SkipNotes
+// counts nothing.
+func (n *SkipNotes) Skipped(guildID, channelID string, reason SkipReason) {
+	if n == nil || !n.Enabled(guildID) {
+		return
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.stopped {
+		return
+	}
+	skipped, exists := n.skipped[guildID]
+	if !exists {
+		skipped = &skippedMessages{counts: make(map[SkipReason]int)}
+		skipped.timer = time.AfterFunc(n.quietDelay, func() { n.flush(guildID) })
+		n.skipped[guildID] = skipped
+	} else {
+		skipped.timer.Reset(n.quietDelay)
+	}
+	skipped.channelID = channelID
+	skipped.counts[reason]++
+}
+
+// Read ends a run of skipped messages in a guild, so their note is queued ahead of the
+// message being read
+func (n *SkipNotes) Read(guildID string) {
+	if n == nil {
+		return
+	}
+	n.flush(guildID)
+}
+
+// flush queues the note of the messages skipped in a guild. A guild that heard a note
+// less than interval ago keeps counting until the interval ends.
+func (n *SkipNotes) flush(guildID string) {
+	n.mu.Lock()
+	skipped, exists := n.skipped[guildID]
+	if !exists {
+		n.mu.Unlock()
+		return
+	}
+	if wait := n.interval - n.now().Sub(n.lastNote[guildID]); wait > 0 {
+		skipped.timer.Reset(wait)
+		n.mu.Unlock()
+		return
+	}
+	skipped.timer.Stop()
+	delete(n.skipped, guildID)
+	n.lastNote[guildID] = n.now()
+	n.mu.Unlock()
+
+	// Notes are spoken, where "1 message(s)" would sound odd
+	var sentences []string
+	for _, reason := range skipReasons {
+		switch count := skipped.counts[reason]; count {
+		case 0:
+		case 1:
+			sentences = append(sentences, n.localizer.T(guildID, "skip_notes."+string(reason)+"_one"))
+		default:
+			sentences = append(sentences, n.localizer.T(guildID, "skip_notes."+string(reason), count))
+		}
+	}
+	if len(sentences) == 0 {
+		return
+	}
+
+	message := &QueuedMessage{
+		ID:        fmt.Sprintf("skipped-%s-%d", guildID, n.now().UnixNano()),
+		GuildID:   guildID,
+		ChannelID: skipped.channelID,
+		Username:  "skipped",
+		Content:   strings.Join(sentences, " "),
+		Priority:  PriorityLow,
+		Timestamp: n.now(),
+	}
+	if err := n.messageQueue.Enqueue(message); err != nil {
+		n.logger.Printf("Failed to queue skip note for guild %s: %v", guildID, err)
+	}
+}
+
+// Stop drops the skips that were not noted yet
+func (n *SkipNotes) Stop() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.stopped = true
+	for guildID, skipped := range n.skipped {
+		skipped.timer.Stop()
+		delete(n.skipped, guildID)
+	}
+}
@@ -0,0 +1,118 @@
+package tts
+
+import (
+	"io"
+	"log"
+	"testing"
+	"time"
+
+	"darrot/internal/config"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSkipNotes(t *testing.T) (*SkipNotes, *mockMessageQueue) {
+	t.Helper()
+
+	storage, err := NewStorageService(t.TempDir())
+	require.NoError(t, err)
+	configService := NewConfigService(storage, config.TTSConfig{DefaultVoice: DefaultVoice, DefaultSpeed: 1.0, DefaultVolume: 1.0, MaxQueueSize: 10})
+
+	queue := newMockMessageQueue()
+	notes := NewSkipNotes(queue, configService, log.New(io.Discard, "", 0))
+	notes.quietDelay = time.Hour // Tests flush explicitly unless they test the delay
+	t.Cleanup(notes.Stop)
+	require.NoError(t, notes.SetEnabled("guild1", true))
+	return notes, queue
+}
+
+func TestSkipNotes_NotesSkippedRun(t *testing.T) {
+	notes, queue := newTestSkipNotes(t)
+
+	notes.Skipped("guild1", "text1", SkipMuted)
+	notes.Skipped("guild1", "text1", SkipNotOptedIn)
+	notes.Skipped("guild1", "text1", SkipMuted)
+	notes.Skipped("guild1", "text1", SkipMuted)
+	notes.Skipped("guild2", "text2", SkipMuted) // Not turned on
+	assert.Empty(t, queue.getMessages(), "the note waits for the end of the run")
+
+	notes.Read("guild1")
+	messages := queue.getMessages()
+	require.Len(t, messages, 1)
+	assert.Equal(t, "A message from someone who hasn't opted in was skipped. 3 messages from muted users were skipped.", messages[0].Content)
+	assert.Equal(t, "text1", messages[0].ChannelID)
+	assert.Equal(t, PriorityLow, messages[0].Priority)
+
+	notes.Read("guild1")
+	notes.Read("guild2")
+	assert.Len(t, queue.getMessages(), 1, "the note is spoken once")
+}
+
+func TestSkipNotes_OneNotePerInterval(t *testing.T) {
+	notes, queue := newTestSkipNotes(t)
+	now := time.Now()
+	notes.now = func() time.Time { return now }
+
+	notes.Skipped("guild1", "text1", SkipModerated)
+	notes.Read("guild1")
+	require.Len(t, queue.getMessages(), 1)
+	assert.Equal(t, "A message was skipped by moderation.", queue.getMessages()[0].Content)
+
+	// Skips right after a note wait for the interval to end
+	notes.Skipped("guild1", "text1", SkipModerated)
+	notes.Skipped("guild1", "text1", SkipModerated)
+	notes.Read("guild1")
+	assert.Len(t, queue.getMessages(), 1)
+
+	now = now.Add(SkipNoteInterval)
+	notes.Read("guild1")
+	require.Len(t, queue.getMessages(), 2)
+	assert.Equal(t, "2 messages were skipped by moderation.", queue.getMessages()[1].Content)
+}
+
+func TestSkipNotes_NoteAfterQuietDelay(t *testing.T) {
+	notes, queue := newTestSkipNotes(t)
+	notes.quietDelay = 10 * time.Millisecond
+
+	notes.Skipped("guild1", "text1", SkipNotOptedIn)
+	messages := queue.waitForMessages(t, 1)
+	assert.Equal(t, "A message from someone who hasn't opted in was skipped.", messages[0].Content)
+}
+
+func TestMessageMonitor_NotesSkippedMessages(t *testing.T) {
+	notes, queue := newTestSkipNotes(t)
+	channelService := newMockChannelService()
+	userService := newMockUserService()
+	session := &discordgo.Session{}
+
+	monitor := NewMessageMonitor(session, channelService, userService, queue, log.New(io.Discard, "", 0))
+	monitor.SetSkipNotes(notes)
+	monitor.voiceListeners = func(guildID string) []string { return []string{"listener1"} }
+
+	channelService.setPaired("text1", true)
+	userService.setOptedIn("talker1", "guild1", true)
+	userService.setOptedIn("listener1", "guild1", true)
+	require.NoError(t, userService.MuteUser("listener1", "talker1", "guild1"))
+
+	message := func(id, authorID string) *discordgo.MessageCreate {
+		return &discordgo.MessageCreate{Message: &discordgo.Message{
+			ID:        id,
+			Content:   "Hello world!",
+			GuildID:   "guild1",
+			ChannelID: "text1",
+			Author:    &discordgo.User{ID: authorID, Username: authorID},
+		}}
+	}
+	monitor.handleMessageCreate(session, message("msg1", "talker1"))
+	monitor.handleMessageCreate(session, message("msg2", "stranger"))
+	monitor.handleMessageCreate(session, message("msg3", "talker1"))
+	monitor.handleMessageCreate(session, message("msg4", "listener1"))
+
+	messages := queue.getMessages()
+	require.Len(t, messages, 2)
+	assert.Equal(t, "A message from someone who hasn't opted in was skipped. 2 messages from muted users were skipped.", messages[0].Content,
+		"the note comes before the message ending the run")
+	assert.Equal(t, "msg4", messages[1].ID)
+}
@@ -0,0 +1,112 @@
+package tts
+
+import (
+	"time"
+
+	"darrot/internal/events"
+)
+
+// Speech event types of the now-speaking feed
+const (
+	SpeechEventSpeaking = "speaking" // A message started playing
+	SpeechEventFinished = "finished" // The message stopped playing, or was skipped
+)
+
+// SpeechEvent is the JSON form of an utterance event sent to now-speaking overlays
+type SpeechEvent struct {
+	Type       string    `json:"type"`
+	GuildID    string    `json:"guild_id"`
+	UserID     string    `json:"user_id,omitempty"`
+	Username   string    `json:"username,omitempty"`
+	Text       string    `json:"text"`
+	Tag        string    `json:"tag,omitempty"`
+	DurationMs int64     `json:"duration_ms"` // Expected length when speaking (0 if unknown), time played when finished
+	Timestamp  time.Time `json:"timestamp"`
+}
+
+// speakingEvent converts an utterance that started playing for overlays
+func speakingEvent(e events.UtteranceStarted) SpeechEvent {
+	return newSpeechEvent(SpeechEventSpeaking, e.Utterance, e.Expected, e.StartedAt)
+}
+
+// finishedEvent converts an utterance that stopped playing for overlays
+func finishedEvent(e events.UtteranceFinished) SpeechEvent {
+	return newSpeechEvent(SpeechEventFinished, e.Utterance, e.Played, e.FinishedAt)
+}
+
+func newSpeechEvent(eventType string, utterance events.Utterance, duration time.Duration, at time.Time) SpeechEvent {
+	return SpeechEvent{
+		Type:       eventType,
+		GuildID:    utterance.GuildID,
+		UserID:     utterance.UserID,
+		Username:   utterance.Username,
+		Text:       utterance.Text,
+		Tag:        utterance.Tag,
+		DurationMs: duration.Milliseconds(),
+		Timestamp:  at,
+	}
+}
+
+// speechTracker publishes the started and finished events of one message. A nil
+// tracker publishes nothing.
+type speechTracker struct {
+	bus       *events.Bus
+	utterance events.Utterance
+	startedAt time.Time
+	played    time.Duration
+	completed bool
+}
+
+// trackSpeech returns a tracker for a message about to be read, or nil when no event
+// bus is configured
+func trackSpeech(bus *events.Bus, message *QueuedMessage, text string) *speechTracker {
+	if bus == nil {
+		return nil
+	}
+	return &speechTracker{
+		bus: bus,
+		utterance: events.Utterance{
+			GuildID:      message.GuildID,
+			MessageID:    message.ID,
+			UserID:       message.UserID,
+			Username:     message.Username,
+			Text:         text,
+			Tag:          message.Tag,
+			Part:         message.Part,
+			Announcement: message.Priority != PriorityNormal,
+			Preview:      message.Voice != "" && message.Tag == "",
+		},
+	}
+}
+
+// start publishes that the message started playing. Only the first call publishes.
+func (t *speechTracker) start(expected time.Duration) {
+	if t == nil || !t.startedAt.IsZero() {
+		return
+	}
+	t.startedAt = time.Now()
+	t.bus.Publish(events.UtteranceStarted{Utterance: t.utterance, Expected: expected, StartedAt: t.startedAt})
+}
+
+// complete records that the whole message played
+func (t *speechTracker) complete(played time.Duration) {
+	if t == nil {
+		return
+	}
+	t.played = played
+	t.completed = true
+}
+
+// finish publishes that the message stopped playing, if it started. Messages that did
+// not complete report the time since they started.
+func (t *speechTracker) finish() {
+	if t == nil || t.startedAt.IsZero() {
+		return
+	}
+	now := time.Now()
+	played := t.played
+	if !t.completed {
+		played = now.Sub(t.startedAt)
+	}
+	t.bus.Publish(events.UtteranceFinished{Utterance: t.utterance, Played: played, Completed: t.completed, FinishedAt: now})
+}
@@ -0,0 +1,87 @@
+package tts
+
+import (
+	"testing"
+	"time"
+
+	"darrot/internal/events"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpeechTracker(t *testing.T) {
+	bus := events.New()
+	var started []events.UtteranceStarted
+	var finished []events.UtteranceFinished
+	events.Subscribe(bus, func(e events.UtteranceStarted) { started = append(started, e) })
+	events.Subscribe(bus, func(e events.UtteranceFinished) { finished = append(finished, e) })
+
+	message := &QueuedMessage{ID: "m1", GuildID: "guild1", UserID: "user1", Username: "alice", Tag: "overlay"}
+
+	// Messages that never started playing publish nothing
+	trackSpeech(bus, message, "skipped").finish()
+	assert.Empty(t, finished)
+
+	tracker := trackSpeech(bus, message, "alice says: hi")
+	tracker.start(2 * time.Second)
+	tracker.start(0) // Only the first start is published
+	tracker.complete(1500 * time.Millisecond)
+	tracker.finish()
+
+	require.Len(t, started, 1)
+	assert.Equal(t, events.Utterance{GuildID: "guild1", MessageID: "m1", UserID: "user1", Username: "alice", Text: "alice says: hi", Tag: "overlay"}, started[0].Utterance)
+	assert.Equal(t, 2*time.Second, started[0].Expected)
+
+	require.Len(t, finished, 1)
+	assert.True(t, finished[0].Completed)
+	assert.Equal(t, 1500*time.Millisecond, finished[0].Played)
+
+	// Without a bus nothing is tracked
+	none := trackSpeech(nil, message, "hi")
+	assert.Nil(t, none)
+	none.start(0)
+	none.complete(0)
+	none.finish()
+}
+
+func TestSpeechTracker_ClassifiesMessages(t *testing.T) {
+	bus := events.New()
+
+	announcement := trackSpeech(bus, &QueuedMessage{GuildID: "guild1", Priority: PriorityLow}, "ann joined")
+	assert.True(t, announcement.utterance.Announcement)
+
+	preview := trackSpeech(bus, &QueuedMessage{GuildID: "guild1", Voice: "de-DE-Wavenet-B"}, "preview")
+	assert.True(t, preview.utterance.Preview)
+
+	// Messages queued through the HTTP API can have their own voice without being previews
+	injected := trackSpeech(bus, &QueuedMessage{GuildID: "guild1", Voice: "de-DE-Wavenet-B", Tag: "overlay"}, "hi")
+	assert.False(t, injected.utterance.Preview)
+}
+
+func TestSpeechTracker_InterruptedMessage(t *testing.T) {
+	bus := events.New()
+	var finished []events.UtteranceFinished
+	events.Subscribe(bus, func(e events.UtteranceFinished) { finished = append(finished, e) })
+
+	tracker := trackSpeech(bus, &QueuedMessage{GuildID: "guild1"}, "skipped")
+	tracker.start(0)
+	tracker.finish()
+
+	require.Len(t, finished, 1)
+	assert.False(t, finished[0].Completed)
+	assert.GreaterOrEqual(t, finished[0].Played, time.Duration(0))
+}
+
+func TestSpeechEventConversion(t *testing.T) {
+	at := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	utterance := events.Utterance{GuildID: "guild1", UserID: "user1", Username: "alice", Text: "hi", Tag: "overlay"}
+
+	assert.Equal(t, SpeechEvent{
+		Type: SpeechEventSpeaking, GuildID: "guild1", UserID: "user1", Username: "alice", Text: "hi", Tag: "overlay", DurationMs: 2000, Timestamp: at,
+	}, speakingEvent(events.UtteranceStarted{Utterance: utterance, Expected: 2 * time.Second, StartedAt: at}))
+
+	assert.Equal(t, SpeechEvent{
+		Type: SpeechEventFinished, GuildID: "guild1", UserID: "user1", Username: "alice", Text: "hi", Tag: "overlay", DurationMs: 1500, Timestamp: at,
+	}, finishedEvent(events.UtteranceFinished{Utterance: utterance, Played: 1500 * time.Millisecond, FinishedAt: at}))
+}
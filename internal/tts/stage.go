@@ -0,0 +1,72 @@
+package tts
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// StageVoiceSession is implemented by voice sessions that can speak in stage channels.
+// After joining a stage channel the bot is in the audience; it becomes a speaker by
+// unsuppressing itself, or asks a stage moderator to invite it by requesting to speak.
+type StageVoiceSession interface {
+	DiscordVoiceSession
+	Channel(channelID string) (*discordgo.Channel, error)
+	UpdateOwnVoiceState(guildID string, state *OwnVoiceStateUpdate) error
+}
+
+// OwnVoiceStateUpdate is the body of Discord's "modify current user voice state" request
+type OwnVoiceStateUpdate struct {
+	ChannelID               string     `json:"channel_id"`
+	Suppress                *bool      `json:"suppress,omitempty"`
+	RequestToSpeakTimestamp *time.Time `json:"request_to_speak_timestamp,omitempty"`
+}
+
+// discordVoiceSession adapts a discordgo session to StageVoiceSession
+type discordVoiceSession struct {
+	*discordgo.Session
+}
+
+// UpdateOwnVoiceState modifies the bot's voice state in a stage channel. discordgo has
+// no wrapper for this endpoint, so the request is made directly.
+func (s discordVoiceSession) UpdateOwnVoiceState(guildID string, state *OwnVoiceStateUpdate) error {
+	endpoint := discordgo.EndpointGuild(guildID) + "/voice-states/@me"
+	_, err := s.RequestWithBucketID("PATCH", endpoint, state, endpoint)
+	return err
+}
+
+// joinStageAsSpeaker makes the bot a speaker if channelID is a stage channel. When the
+// bot lacks permission to speak directly it requests to speak instead, and requested
+// reports that a stage moderator has to approve the request before audio is heard.
+func joinStageAsSpeaker(session DiscordVoiceSession, guildID, channelID string) (requested bool, err error) {
+	stage, ok := session.(StageVoiceSession)
+	if !ok {
+		return false, nil
+	}
+
+	channel, err := stage.Channel(channelID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get channel %s: %w", channelID, err)
+	}
+	if channel.Type != discordgo.ChannelTypeGuildStageVoice {
+		return false, nil
+	}
+
+	// Speaking directly needs the Mute Members permission in the stage
+	suppress := false
+	unsuppressErr := stage.UpdateOwnVoiceState(guildID, &OwnVoiceStateUpdate{ChannelID: channelID, Suppress: &suppress})
+	if unsuppressErr == nil {
+		log.Printf("Joined stage channel %s in guild %s as a speaker", channelID, guildID)
+		return false, nil
+	}
+
+	now := time.Now().UTC()
+	if err := stage.UpdateOwnVoiceState(guildID, &OwnVoiceStateUpdate{ChannelID: channelID, RequestToSpeakTimestamp: &now}); err != nil {
+		return false, fmt.Errorf("failed to become a speaker in stage channel %s: %v; failed to request to speak: %w", channelID, unsuppressErr, err)
+	}
+
+	log.Printf("Requested to speak in stage channel %s in guild %s", channelID, guildID)
+	return true, nil
+}
@@ -0,0 +1,106 @@
+package tts
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockStageVoiceSession records voice state updates and fails unsuppressing or
+// requesting to speak on demand
+type mockStageVoiceSession struct {
+	mockDiscordVoiceSession
+	channelType   discordgo.ChannelType
+	unsuppressErr error
+	requestErr    error
+	updates       []*OwnVoiceStateUpdate
+}
+
+func (m *mockStageVoiceSession) Channel(channelID string) (*discordgo.Channel, error) {
+	return &discordgo.Channel{ID: channelID, Type: m.channelType}, nil
+}
+
+func (m *mockStageVoiceSession) UpdateOwnVoiceState(guildID string, state *OwnVoiceStateUpdate) error {
+	m.updates = append(m.updates, state)
+	if state.Suppress != nil {
+		return m.unsuppressErr
+	}
+	return m.requestErr
+}
+
+func TestJoinStageAsSpeaker(t *testing.T) {
+	forbidden := errors.New("HTTP 403 Forbidden")
+
+	t.Run("speaks directly", func(t *testing.T) {
+		session := &mockStageVoiceSession{channelType: discordgo.ChannelTypeGuildStageVoice}
+
+		requested, err := joinStageAsSpeaker(session, "guild1", "stage1")
+		require.NoError(t, err)
+		assert.False(t, requested)
+		require.Len(t, session.updates, 1)
+		assert.Equal(t, "stage1", session.updates[0].ChannelID)
+		assert.False(t, *session.updates[0].Suppress)
+	})
+
+	t.Run("requests to speak without permission", func(t *testing.T) {
+		session := &mockStageVoiceSession{channelType: discordgo.ChannelTypeGuildStageVoice, unsuppressErr: forbidden}
+
+		requested, err := joinStageAsSpeaker(session, "guild1", "stage1")
+		require.NoError(t, err)
+		assert.True(t, requested)
+		require.Len(t, session.updates, 2)
+		assert.NotNil(t, session.updates[1].RequestToSpeakTimestamp)
+		assert.Nil(t, session.updates[1].Suppress)
+	})
+
+	t.Run("both updates fail", func(t *testing.T) {
+		session := &mockStageVoiceSession{channelType: discordgo.ChannelTypeGuildStageVoice, unsuppressErr: forbidden, requestErr: forbidden}
+
+		requested, err := joinStageAsSpeaker(session, "guild1", "stage1")
+		assert.Error(t, err)
+		assert.False(t, requested)
+	})
+
+	t.Run("voice channel", func(t *testing.T) {
+		session := &mockStageVoiceSession{channelType: discordgo.ChannelTypeGuildVoice}
+
+		requested, err := joinStageAsSpeaker(session, "guild1", "voice1")
+		require.NoError(t, err)
+		assert.False(t, requested)
+		assert.Empty(t, session.updates)
+	})
+
+	t.Run("session without stage support", func(t *testing.T) {
+		requested, err := joinStageAsSpeaker(&mockDiscordVoiceSession{}, "guild1", "stage1")
+		require.NoError(t, err)
+		assert.False(t, requested)
+	})
+}
+
+func TestVoiceManager_JoinChannel_Stage(t *testing.T) {
+	vm := NewVoiceManager(&discordgo.Session{}).(*voiceManager)
+	session := &mockStageVoiceSession{
+		mockDiscordVoiceSession: mockDiscordVoiceSession{
+			joinFunc: func(guildID, channelID string, mute, deaf bool) (*discordgo.VoiceConnection, error) {
+				return createMockVoiceConnection(guildID, channelID), nil
+			},
+		},
+		channelType:   discordgo.ChannelTypeGuildStageVoice,
+		unsuppressErr: errors.New("HTTP 403 Forbidden"),
+	}
+	vm.session = session
+
+	conn, err := vm.JoinChannel("guild1", "stage1")
+	require.NoError(t, err)
+	assert.True(t, conn.RequestedToSpeak)
+
+	// Failing to become a speaker leaves the bot connected in the audience
+	session.requestErr = errors.New("HTTP 403 Forbidden")
+	conn, err = vm.JoinChannel("guild1", "stage2")
+	require.NoError(t, err)
+	assert.False(t, conn.RequestedToSpeak)
+	assert.Equal(t, "stage2", conn.ChannelID)
+}
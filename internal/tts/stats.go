@@ -0,0 +1,217 @@
+package tts
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultTopSpeakers is the number of speakers /darrot-stats lists
+const DefaultTopSpeakers = 5
+
+// dcaFrameDuration is the audio length of one Opus frame in a DCA stream
+const dcaFrameDuration = 20 * time.Millisecond
+
+// StatsServiceImpl implements StatsService with statistics persisted through StorageService.
+// Statistics accumulate from the first recorded event and are never reset.
+type StatsServiceImpl struct {
+	storage *StorageService
+	stats   map[string]*GuildStats
+	now     func() time.Time
+	mu      sync.Mutex
+}
+
+// NewStatsService creates a stats service
+func NewStatsService(storage *StorageService) *StatsServiceImpl {
+	return &StatsServiceImpl{
+		storage: storage,
+		stats:   make(map[string]*GuildStats),
+		now:     time.Now,
+	}
+}
+
+// RecordMessage counts a chat message that was read aloud
+func (s *StatsServiceImpl) RecordMessage(guildID, userID, username string) error {
+	return s.update(guildID, func(stats *GuildStats) {
+		stats.MessagesRead++
+
+		if userID == "" {
+			return
+		}
+		if stats.Speakers == nil {
+			stats.Speakers = make(map[string]*SpeakerStats)
+		}
+		speaker, exists := stats.Speakers[userID]
+		if !exists {
+			speaker = &SpeakerStats{UserID: userID}
+			stats.Speakers[userID] = speaker
+		}
+		speaker.Username = username // Keep the latest name
+		speaker.Messages++
+	})
+}
+
+// RecordCharacters adds characters sent to the TTS engine
+func (s *StatsServiceImpl) RecordCharacters(guildID string, characters int) error {
+	if characters <= 0 {
+		return nil
+	}
+	return s.update(guildID, func(stats *GuildStats) {
+		stats.CharactersSynthesized += characters
+	})
+}
+
+// RecordAudio adds audio played through the guild's voice connection
+func (s *StatsServiceImpl) RecordAudio(guildID string, played time.Duration) error {
+	if played <= 0 {
+		return nil
+	}
+	return s.update(guildID, func(stats *GuildStats) {
+		stats.AudioPlayedMs += played.Milliseconds()
+	})
+}
+
+// RecordSkip counts a message skipped with /darrot-control
+func (s *StatsServiceImpl) RecordSkip(guildID string) error {
+	return s.update(guildID, func(stats *GuildStats) {
+		stats.Skips++
+	})
+}
+
+// GetStats returns a copy of the guild's statistics
+func (s *StatsServiceImpl) GetStats(guildID string) (*GuildStats, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats, err := s.currentStats(guildID)
+	if err != nil {
+		return nil, err
+	}
+
+	statsCopy := *stats
+	statsCopy.Speakers = make(map[string]*SpeakerStats, len(stats.Speakers))
+	for userID, speaker := range stats.Speakers {
+		speakerCopy := *speaker
+		statsCopy.Speakers[userID] = &speakerCopy
+	}
+	return &statsCopy, nil
+}
+
+// ExportUserData adds how many of a user's messages were read in a guild to export
+func (s *StatsServiceImpl) ExportUserData(userID, guildID string, export *UserDataExport) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats, err := s.currentStats(guildID)
+	if err != nil {
+		return err
+	}
+
+	if speaker, exists := stats.Speakers[userID]; exists {
+		speakerCopy := *speaker
+		export.Statistics = &speakerCopy
+	}
+	return nil
+}
+
+// DeleteUserData deletes a user's speaker statistics in a guild. The guild's totals keep
+// counting the user's messages.
+func (s *StatsServiceImpl) DeleteUserData(userID, guildID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats, err := s.currentStats(guildID)
+	if err != nil {
+		return err
+	}
+	if _, exists := stats.Speakers[userID]; !exists {
+		return nil
+	}
+
+	delete(stats.Speakers, userID)
+	if err := s.storage.SaveGuildStats(*stats); err != nil {
+		return fmt.Errorf("failed to save guild stats: %w", err)
+	}
+	return nil
+}
+
+// update applies change to the guild's statistics and saves them
+func (s *StatsServiceImpl) update(guildID string, change func(stats *GuildStats)) error {
+	if guildID == "" {
+		return fmt.Errorf("guild ID is required")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats, err := s.currentStats(guildID)
+	if err != nil {
+		return err
+	}
+
+	if stats.StartedAt.IsZero() {
+		stats.StartedAt = s.now()
+	}
+	change(stats)
+
+	if err := s.storage.SaveGuildStats(*stats); err != nil {
+		return fmt.Errorf("failed to save guild stats: %w", err)
+	}
+	return nil
+}
+
+// currentStats returns the guild's statistics, loading them from storage on first use
+// (caller must hold the lock)
+func (s *StatsServiceImpl) currentStats(guildID string) (*GuildStats, error) {
+	if stats, exists := s.stats[guildID]; exists {
+		return stats, nil
+	}
+
+	stats, err := s.storage.LoadGuildStats(guildID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load guild stats: %w", err)
+	}
+	s.stats[guildID] = stats
+	return stats, nil
+}
+
+// AudioPlayed returns the total audio played
+func (g *GuildStats) AudioPlayed() time.Duration {
+	return time.Duration(g.AudioPlayedMs) * time.Millisecond
+}
+
+// TopSpeakers returns up to limit speakers with the most messages read, most first.
+// Ties are broken by user ID so the order is stable.
+func (g *GuildStats) TopSpeakers(limit int) []SpeakerStats {
+	speakers := make([]SpeakerStats, 0, len(g.Speakers))
+	for _, speaker := range g.Speakers {
+		speakers = append(speakers, *speaker)
+	}
+
+	sort.Slice(speakers, func(i, j int) bool {
+		if speakers[i].Messages != speakers[j].Messages {
+			return speakers[i].Messages > speakers[j].Messages
+		}
+		return speakers[i].UserID < speakers[j].UserID
+	})
+
+	if limit > 0 && len(speakers) > limit {
+		speakers = speakers[:limit]
+	}
+	return speakers
+}
+
+// dcaDuration returns the length of the audio in a DCA stream. Malformed trailing data
+// is ignored.
+func dcaDuration(dcaData []byte) time.Duration {
+	frames := 0
+	for offset := 0; offset+2 <= len(dcaData); frames++ {
+		frameLen := int(dcaData[offset]) | int(dcaData[offset+1])<<8
+		offset += 2 + frameLen
+		if frameLen <= 0 || offset > len(dcaData) {
+			break
+		}
+	}
+	return time.Duration(frames) * dcaFrameDuration
+}
@@ -0,0 +1,147 @@
+package tts
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"darrot/internal/commands/textcmd"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// statsEmbedColor is the accent color of the /darrot-stats embed
+const statsEmbedColor = 0x5865F2
+
+// StatsCommandHandler handles the administrator usage statistics command
+type StatsCommandHandler struct {
+	statsService      StatsService
+	permissionService PermissionService
+	localizer         *Localizer
+	logger            *log.Logger
+}
+
+// NewStatsCommandHandler creates a new stats command handler
+func NewStatsCommandHandler(
+	statsService StatsService,
+	permissionService PermissionService,
+	logger *log.Logger,
+) *StatsCommandHandler {
+	return &StatsCommandHandler{
+		statsService:      statsService,
+		permissionService: permissionService,
+		logger:            logger,
+	}
+}
+
+// SetLocalizer sets the localizer used to translate responses
+func (h *StatsCommandHandler) SetLocalizer(localizer *Localizer) {
+	h.localizer = localizer
+}
+
+// Definition returns the Discord slash command definition for the stats command
+func (h *StatsCommandHandler) Definition() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:                     "darrot-stats",
+		Description:              "Show TTS usage statistics for this server (Administrator only)",
+		DefaultMemberPermissions: adminCommandPermissions(),
+	}
+}
+
+// Handle processes the stats command interaction
+func (h *StatsCommandHandler) Handle(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	// Validate guild context
+	if i.GuildID == "" {
+		return h.respondError(s, i, h.localizer.T(i.GuildID, "common.guild_only"))
+	}
+
+	userID := i.Member.User.ID
+	guildID := i.GuildID
+
+	// Validate administrator permissions
+	if err := h.ValidatePermissions(userID, guildID); err != nil {
+		return h.respondError(s, i, h.localizer.T(guildID, "common.permission_denied", err))
+	}
+
+	stats, err := h.statsService.GetStats(guildID)
+	if err != nil {
+		h.logger.Printf("Error getting stats for guild %s: %v", guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "stats.failed"))
+	}
+
+	return textcmd.Respond(s, i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{h.buildEmbed(guildID, stats)},
+			Flags:  discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
+
+// buildEmbed renders the guild's statistics as an embed
+func (h *StatsCommandHandler) buildEmbed(guildID string, stats *GuildStats) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Title: h.localizer.T(guildID, "stats.title"),
+		Color: statsEmbedColor,
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: h.localizer.T(guildID, "stats.messages"), Value: fmt.Sprintf("%d", stats.MessagesRead), Inline: true},
+			{Name: h.localizer.T(guildID, "stats.characters"), Value: fmt.Sprintf("%d", stats.CharactersSynthesized), Inline: true},
+			{Name: h.localizer.T(guildID, "stats.audio"), Value: h.localizer.T(guildID, "stats.audio_minutes", stats.AudioPlayed().Minutes()), Inline: true},
+			{Name: h.localizer.T(guildID, "stats.skips"), Value: fmt.Sprintf("%d", stats.Skips), Inline: true},
+			{Name: h.localizer.T(guildID, "stats.top_speakers"), Value: h.describeTopSpeakers(guildID, stats)},
+		},
+	}
+
+	if stats.StartedAt.IsZero() {
+		embed.Description = h.localizer.T(guildID, "stats.empty")
+	} else {
+		embed.Footer = &discordgo.MessageEmbedFooter{Text: h.localizer.T(guildID, "stats.since", stats.StartedAt.UTC().Format("2006-01-02"))}
+	}
+
+	return embed
+}
+
+// describeTopSpeakers lists the speakers with the most messages read
+func (h *StatsCommandHandler) describeTopSpeakers(guildID string, stats *GuildStats) string {
+	speakers := stats.TopSpeakers(DefaultTopSpeakers)
+	if len(speakers) == 0 {
+		return h.localizer.T(guildID, "stats.no_speakers")
+	}
+
+	lines := make([]string, len(speakers))
+	for rank, speaker := range speakers {
+		lines[rank] = h.localizer.T(guildID, "stats.speaker", rank+1, speaker.UserID, speaker.Messages)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ValidatePermissions validates that the user has administrator permissions
+func (h *StatsCommandHandler) ValidatePermissions(userID, guildID string) error {
+	canControl, err := h.permissionService.CanControlBot(userID, guildID)
+	if err != nil {
+		return fmt.Errorf("failed to check permissions: %w", err)
+	}
+
+	if !canControl {
+		return withKind(ErrPermission, fmt.Errorf("you must have administrator permissions to view TTS statistics"))
+	}
+
+	return nil
+}
+
+// ValidateChannelAccess is not needed for stats commands but required by interface
+func (h *StatsCommandHandler) ValidateChannelAccess(userID, channelID string) error {
+	return nil // Not applicable for stats commands
+}
+
+// Helper methods for response handling
+
+func (h *StatsCommandHandler) respondError(s *discordgo.Session, i *discordgo.InteractionCreate, message string) error {
+	return textcmd.Respond(s, i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: "❌ " + message,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
@@ -0,0 +1,83 @@
+package tts
+
+import (
+	"errors"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createTestStatsHandler(t *testing.T) (*StatsCommandHandler, *StatsServiceImpl, *MockPermissionService) {
+	statsService, _ := createTestStatsService(t)
+	mockPermissionService := &MockPermissionService{}
+	logger := log.New(os.Stdout, "[TEST] ", log.LstdFlags)
+
+	return NewStatsCommandHandler(statsService, mockPermissionService, logger), statsService, mockPermissionService
+}
+
+func TestStatsCommandHandler_Definition(t *testing.T) {
+	handler, _, _ := createTestStatsHandler(t)
+
+	definition := handler.Definition()
+
+	assert.Equal(t, "darrot-stats", definition.Name)
+	assert.Contains(t, definition.Description, "Administrator only")
+	assert.Empty(t, definition.Options)
+}
+
+func TestStatsCommandHandler_ValidatePermissions(t *testing.T) {
+	handler, _, mockPermissionService := createTestStatsHandler(t)
+
+	mockPermissionService.On("CanControlBot", "admin", "guild123").Return(true, nil)
+	mockPermissionService.On("CanControlBot", "user", "guild123").Return(false, nil)
+	mockPermissionService.On("CanControlBot", "broken", "guild123").Return(false, errors.New("permission check failed"))
+
+	assert.NoError(t, handler.ValidatePermissions("admin", "guild123"))
+	assert.ErrorContains(t, handler.ValidatePermissions("user", "guild123"), "administrator permissions")
+	assert.ErrorContains(t, handler.ValidatePermissions("broken", "guild123"), "failed to check permissions")
+
+	mockPermissionService.AssertExpectations(t)
+}
+
+func TestStatsCommandHandler_BuildEmbed(t *testing.T) {
+	handler, statsService, _ := createTestStatsHandler(t)
+	statsService.now = func() time.Time { return time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC) }
+
+	require.NoError(t, statsService.RecordMessage("guild1", "user1", "alice"))
+	require.NoError(t, statsService.RecordMessage("guild1", "user1", "alice"))
+	require.NoError(t, statsService.RecordMessage("guild1", "user2", "bob"))
+	require.NoError(t, statsService.RecordCharacters("guild1", 250))
+	require.NoError(t, statsService.RecordAudio("guild1", 90*time.Second))
+	require.NoError(t, statsService.RecordSkip("guild1"))
+
+	stats, err := statsService.GetStats("guild1")
+	require.NoError(t, err)
+	embed := handler.buildEmbed("guild1", stats)
+
+	assert.Equal(t, "📊 TTS Statistics", embed.Title)
+	require.Len(t, embed.Fields, 5)
+	assert.Equal(t, "3", embed.Fields[0].Value)
+	assert.Equal(t, "250", embed.Fields[1].Value)
+	assert.Equal(t, "1.5 min", embed.Fields[2].Value)
+	assert.Equal(t, "1", embed.Fields[3].Value)
+	assert.Equal(t, "1. <@user1>: 2 message(s)\n2. <@user2>: 1 message(s)", embed.Fields[4].Value)
+	require.NotNil(t, embed.Footer)
+	assert.Equal(t, "Since 2026-10-01 (UTC)", embed.Footer.Text)
+	assert.Empty(t, embed.Description)
+}
+
+func TestStatsCommandHandler_BuildEmbedEmpty(t *testing.T) {
+	handler, statsService, _ := createTestStatsHandler(t)
+
+	stats, err := statsService.GetStats("guild1")
+	require.NoError(t, err)
+	embed := handler.buildEmbed("guild1", stats)
+
+	assert.Equal(t, "Nothing has been read aloud in this server yet.", embed.Description)
+	assert.Equal(t, "None yet", embed.Fields[4].Value)
+	assert.Nil(t, embed.Footer)
+}
@@ -0,0 +1,125 @@
+package tts
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createTestStatsService(t *testing.T) (*StatsServiceImpl, *StorageService) {
+	storage, err := NewStorageService(t.TempDir())
+	require.NoError(t, err)
+
+	return NewStatsService(storage), storage
+}
+
+func TestStatsService_EmptyStats(t *testing.T) {
+	statsService, _ := createTestStatsService(t)
+
+	stats, err := statsService.GetStats("guild1")
+	require.NoError(t, err)
+	assert.Equal(t, "guild1", stats.GuildID)
+	assert.Zero(t, stats.MessagesRead)
+	assert.True(t, stats.StartedAt.IsZero())
+	assert.Empty(t, stats.TopSpeakers(DefaultTopSpeakers))
+}
+
+func TestStatsService_Record(t *testing.T) {
+	statsService, _ := createTestStatsService(t)
+	startedAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	statsService.now = func() time.Time { return startedAt }
+
+	require.NoError(t, statsService.RecordMessage("guild1", "user1", "alice"))
+	require.NoError(t, statsService.RecordMessage("guild1", "user1", "alice2"))
+	require.NoError(t, statsService.RecordMessage("guild1", "user2", "bob"))
+	require.NoError(t, statsService.RecordCharacters("guild1", 120))
+	require.NoError(t, statsService.RecordCharacters("guild1", 0)) // Ignored
+	require.NoError(t, statsService.RecordAudio("guild1", 90*time.Second))
+	require.NoError(t, statsService.RecordAudio("guild1", 1500*time.Millisecond))
+	require.NoError(t, statsService.RecordSkip("guild1"))
+
+	stats, err := statsService.GetStats("guild1")
+	require.NoError(t, err)
+	assert.Equal(t, 3, stats.MessagesRead)
+	assert.Equal(t, 120, stats.CharactersSynthesized)
+	assert.Equal(t, 91500*time.Millisecond, stats.AudioPlayed())
+	assert.Equal(t, 1, stats.Skips)
+	assert.True(t, startedAt.Equal(stats.StartedAt))
+
+	speakers := stats.TopSpeakers(DefaultTopSpeakers)
+	require.Len(t, speakers, 2)
+	assert.Equal(t, SpeakerStats{UserID: "user1", Username: "alice2", Messages: 2}, speakers[0])
+	assert.Equal(t, SpeakerStats{UserID: "user2", Username: "bob", Messages: 1}, speakers[1])
+
+	// Other guilds are unaffected
+	other, err := statsService.GetStats("guild2")
+	require.NoError(t, err)
+	assert.Zero(t, other.MessagesRead)
+
+	assert.Error(t, statsService.RecordSkip(""))
+}
+
+func TestStatsService_Persistence(t *testing.T) {
+	statsService, storage := createTestStatsService(t)
+	require.NoError(t, statsService.RecordMessage("guild1", "user1", "alice"))
+	require.NoError(t, statsService.RecordSkip("guild1"))
+
+	// A new service, as after a restart, continues from the saved statistics
+	restarted := NewStatsService(storage)
+	require.NoError(t, restarted.RecordMessage("guild1", "user1", "alice"))
+
+	stats, err := restarted.GetStats("guild1")
+	require.NoError(t, err)
+	assert.Equal(t, 2, stats.MessagesRead)
+	assert.Equal(t, 1, stats.Skips)
+	assert.Equal(t, 2, stats.Speakers["user1"].Messages)
+}
+
+func TestStatsService_GetStatsReturnsCopy(t *testing.T) {
+	statsService, _ := createTestStatsService(t)
+	require.NoError(t, statsService.RecordMessage("guild1", "user1", "alice"))
+
+	stats, err := statsService.GetStats("guild1")
+	require.NoError(t, err)
+	stats.MessagesRead = 100
+	stats.Speakers["user1"].Messages = 100
+
+	fresh, err := statsService.GetStats("guild1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, fresh.MessagesRead)
+	assert.Equal(t, 1, fresh.Speakers["user1"].Messages)
+}
+
+func TestGuildStats_TopSpeakers(t *testing.T) {
+	stats := &GuildStats{Speakers: map[string]*SpeakerStats{
+		"c": {UserID: "c", Messages: 5},
+		"a": {UserID: "a", Messages: 2},
+		"b": {UserID: "b", Messages: 5},
+		"d": {UserID: "d", Messages: 1},
+	}}
+
+	top := stats.TopSpeakers(3)
+	require.Len(t, top, 3)
+	assert.Equal(t, "b", top[0].UserID) // Ties ordered by user ID
+	assert.Equal(t, "c", top[1].UserID)
+	assert.Equal(t, "a", top[2].UserID)
+
+	assert.Len(t, stats.TopSpeakers(0), 4)
+}
+
+func TestDCADuration(t *testing.T) {
+	var dca bytes.Buffer
+	for i := 0; i < 50; i++ {
+		require.NoError(t, writeDCAFrame(&dca, []byte{0xF8, 0xFF, 0xFE}))
+	}
+	assert.Equal(t, time.Second, dcaDuration(dca.Bytes()))
+
+	// A truncated final frame is not counted
+	truncated := append(dca.Bytes(), 0x10, 0x00, 0x01)
+	assert.Equal(t, time.Second, dcaDuration(truncated))
+
+	assert.Zero(t, dcaDuration(nil))
+}
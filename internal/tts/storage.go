@@ -1,7 +1,6 @@
 package tts
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -9,10 +8,14 @@ import (
 	"time"
 )
 
-// StorageService provides JSON-based storage for TTS configuration data
+// StorageService provides JSON-based storage for TTS configuration data. Each guild's
+// files are locked separately, and every file is written atomically with a checksum and a
+// backup (see storage_files.go), and encrypted when a cipher is set (see storage_crypto.go).
 type StorageService struct {
 	dataDir string
-	mutex   sync.RWMutex
+	cipher  *StorageCipher         // Nil stores files unencrypted
+	locks   map[string]*sync.Mutex // Per guild, created on first use
+	locksMu sync.Mutex
 }
 
 // NewStorageService creates a new storage service with the specified data directory
@@ -23,13 +26,18 @@ func NewStorageService(dataDir string) (*StorageService, error) {
 
 	return &StorageService{
 		dataDir: dataDir,
+		locks:   make(map[string]*sync.Mutex),
 	}, nil
 }
 
+// QueueSpilloverDir returns the directory message queues spill to when they are full
+func (s *StorageService) QueueSpilloverDir() string {
+	return filepath.Join(s.dataDir, "spillover")
+}
+
 // SaveGuildConfig saves guild TTS configuration to JSON file
 func (s *StorageService) SaveGuildConfig(config GuildTTSConfig) error {
-	s.mutex.Lock()
-	defer s.mutex.Unlock()
+	defer s.lockGuild(config.GuildID)()
 
 	if err := ValidateGuildConfig(config); err != nil {
 		return fmt.Errorf("invalid guild config: %w", err)
@@ -38,12 +46,7 @@ func (s *StorageService) SaveGuildConfig(config GuildTTSConfig) error {
 	config.UpdatedAt = time.Now()
 
 	filePath := filepath.Join(s.dataDir, fmt.Sprintf("guild_%s.json", config.GuildID))
-	data, err := json.MarshalIndent(config, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal guild config: %w", err)
-	}
-
-	if err := os.WriteFile(filePath, data, 0600); err != nil {
+	if err := s.writeJSON(filePath, config); err != nil {
 		return fmt.Errorf("failed to write guild config file: %w", err)
 	}
 
@@ -52,25 +55,19 @@ func (s *StorageService) SaveGuildConfig(config GuildTTSConfig) error {
 
 // LoadGuildConfig loads guild TTS configuration from JSON file
 func (s *StorageService) LoadGuildConfig(guildID string) (*GuildTTSConfig, error) {
-	s.mutex.RLock()
-	defer s.mutex.RUnlock()
+	defer s.lockGuild(guildID)()
 
 	filePath := filepath.Join(s.dataDir, fmt.Sprintf("guild_%s.json", guildID))
 
-	if _, err := os.Stat(filePath); os.IsNotExist(err) {
-		// Return default config if file doesn't exist
-		defaultConfig := DefaultGuildTTSConfig(guildID)
-		return &defaultConfig, nil
-	}
-
-	data, err := os.ReadFile(filePath)
+	var config GuildTTSConfig
+	found, err := s.readJSON(filePath, &config)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read guild config file: %w", err)
 	}
-
-	var config GuildTTSConfig
-	if err := json.Unmarshal(data, &config); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal guild config: %w", err)
+	if !found {
+		// Return default config if file doesn't exist
+		defaultConfig := DefaultGuildTTSConfig(guildID)
+		return &defaultConfig, nil
 	}
 
 	return &config, nil
@@ -78,8 +75,7 @@ func (s *StorageService) LoadGuildConfig(guildID string) (*GuildTTSConfig, error
 
 // SaveUserPreferences saves user TTS preferences to JSON file
 func (s *StorageService) SaveUserPreferences(prefs UserTTSPreferences) error {
-	s.mutex.Lock()
-	defer s.mutex.Unlock()
+	defer s.lockGuild(prefs.GuildID)()
 
 	if err := ValidateUserPreferences(prefs); err != nil {
 		return fmt.Errorf("invalid user preferences: %w", err)
@@ -88,12 +84,7 @@ func (s *StorageService) SaveUserPreferences(prefs UserTTSPreferences) error {
 	prefs.UpdatedAt = time.Now()
 
 	filePath := filepath.Join(s.dataDir, fmt.Sprintf("user_%s_%s.json", prefs.UserID, prefs.GuildID))
-	data, err := json.MarshalIndent(prefs, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal user preferences: %w", err)
-	}
-
-	if err := os.WriteFile(filePath, data, 0600); err != nil {
+	if err := s.writeJSON(filePath, prefs); err != nil {
 		return fmt.Errorf("failed to write user preferences file: %w", err)
 	}
 
@@ -102,46 +93,75 @@ func (s *StorageService) SaveUserPreferences(prefs UserTTSPreferences) error {
 
 // LoadUserPreferences loads user TTS preferences from JSON file
 func (s *StorageService) LoadUserPreferences(userID, guildID string) (*UserTTSPreferences, error) {
-	s.mutex.RLock()
-	defer s.mutex.RUnlock()
+	defer s.lockGuild(guildID)()
 
 	filePath := filepath.Join(s.dataDir, fmt.Sprintf("user_%s_%s.json", userID, guildID))
 
-	if _, err := os.Stat(filePath); os.IsNotExist(err) {
+	var prefs UserTTSPreferences
+	found, err := s.readJSON(filePath, &prefs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read user preferences file: %w", err)
+	}
+	if !found {
 		// Return default preferences if file doesn't exist
 		defaultPrefs := DefaultUserPreferences(userID, guildID)
 		return &defaultPrefs, nil
 	}
 
-	data, err := os.ReadFile(filePath)
+	return &prefs, nil
+}
+
+// HasUserPreferences reports whether preferences were ever saved for a user in a guild
+func (s *StorageService) HasUserPreferences(userID, guildID string) bool {
+	defer s.lockGuild(guildID)()
+
+	filePath := filepath.Join(s.dataDir, fmt.Sprintf("user_%s_%s.json", userID, guildID))
+	_, err := os.Stat(filePath)
+	return err == nil
+}
+
+// RemoveUserPreferences deletes a user's preferences in a guild from disk
+func (s *StorageService) RemoveUserPreferences(userID, guildID string) error {
+	defer s.lockGuild(guildID)()
+
+	filePath := filepath.Join(s.dataDir, fmt.Sprintf("user_%s_%s.json", userID, guildID))
+	if err := s.removeFile(filePath); err != nil {
+		return fmt.Errorf("failed to remove user preferences file: %w", err)
+	}
+
+	return nil
+}
+
+// DiscardGuildBackups deletes the backups of a guild's files, so data removed from them
+// is not kept in the previous versions. Each file is backed up again on its next save.
+func (s *StorageService) DiscardGuildBackups(guildID string) error {
+	defer s.lockGuild(guildID)()
+
+	pattern := filepath.Join(s.dataDir, fmt.Sprintf("*_%s.json%s", guildID, backupSuffix))
+	backups, err := filepath.Glob(pattern)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read user preferences file: %w", err)
+		return fmt.Errorf("failed to list backup files: %w", err)
 	}
 
-	var prefs UserTTSPreferences
-	if err := json.Unmarshal(data, &prefs); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal user preferences: %w", err)
+	for _, backup := range backups {
+		if err := os.Remove(backup); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove backup file: %w", err)
+		}
 	}
 
-	return &prefs, nil
+	return nil
 }
 
 // SaveChannelPairing saves channel pairing to JSON file
 func (s *StorageService) SaveChannelPairing(pairing ChannelPairingStorage) error {
-	s.mutex.Lock()
-	defer s.mutex.Unlock()
+	defer s.lockGuild(pairing.GuildID)()
 
 	if err := ValidateChannelPairing(pairing); err != nil {
 		return fmt.Errorf("invalid channel pairing: %w", err)
 	}
 
 	filePath := filepath.Join(s.dataDir, fmt.Sprintf("pairing_%s_%s.json", pairing.GuildID, pairing.VoiceChannelID))
-	data, err := json.MarshalIndent(pairing, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal channel pairing: %w", err)
-	}
-
-	if err := os.WriteFile(filePath, data, 0600); err != nil {
+	if err := s.writeJSON(filePath, pairing); err != nil {
 		return fmt.Errorf("failed to write channel pairing file: %w", err)
 	}
 
@@ -150,23 +170,17 @@ func (s *StorageService) SaveChannelPairing(pairing ChannelPairingStorage) error
 
 // LoadChannelPairing loads channel pairing from JSON file
 func (s *StorageService) LoadChannelPairing(guildID, voiceChannelID string) (*ChannelPairingStorage, error) {
-	s.mutex.RLock()
-	defer s.mutex.RUnlock()
+	defer s.lockGuild(guildID)()
 
 	filePath := filepath.Join(s.dataDir, fmt.Sprintf("pairing_%s_%s.json", guildID, voiceChannelID))
 
-	if _, err := os.Stat(filePath); os.IsNotExist(err) {
-		return nil, fmt.Errorf("channel pairing not found")
-	}
-
-	data, err := os.ReadFile(filePath)
+	var pairing ChannelPairingStorage
+	found, err := s.readJSON(filePath, &pairing)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read channel pairing file: %w", err)
 	}
-
-	var pairing ChannelPairingStorage
-	if err := json.Unmarshal(data, &pairing); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal channel pairing: %w", err)
+	if !found {
+		return nil, fmt.Errorf("channel pairing not found")
 	}
 
 	return &pairing, nil
@@ -174,12 +188,11 @@ func (s *StorageService) LoadChannelPairing(guildID, voiceChannelID string) (*Ch
 
 // RemoveChannelPairing removes channel pairing file
 func (s *StorageService) RemoveChannelPairing(guildID, voiceChannelID string) error {
-	s.mutex.Lock()
-	defer s.mutex.Unlock()
+	defer s.lockGuild(guildID)()
 
 	filePath := filepath.Join(s.dataDir, fmt.Sprintf("pairing_%s_%s.json", guildID, voiceChannelID))
 
-	if err := os.Remove(filePath); err != nil && !os.IsNotExist(err) {
+	if err := s.removeFile(filePath); err != nil {
 		return fmt.Errorf("failed to remove channel pairing file: %w", err)
 	}
 
@@ -188,8 +201,7 @@ func (s *StorageService) RemoveChannelPairing(guildID, voiceChannelID string) er
 
 // ListGuildPairings returns all active channel pairings for a guild
 func (s *StorageService) ListGuildPairings(guildID string) ([]ChannelPairingStorage, error) {
-	s.mutex.RLock()
-	defer s.mutex.RUnlock()
+	defer s.lockGuild(guildID)()
 
 	pattern := filepath.Join(s.dataDir, fmt.Sprintf("pairing_%s_*.json", guildID))
 	files, err := filepath.Glob(pattern)
@@ -199,14 +211,9 @@ func (s *StorageService) ListGuildPairings(guildID string) ([]ChannelPairingStor
 
 	var pairings []ChannelPairingStorage
 	for _, file := range files {
-		data, err := os.ReadFile(file)
-		if err != nil {
-			continue // Skip files that can't be read
-		}
-
 		var pairing ChannelPairingStorage
-		if err := json.Unmarshal(data, &pairing); err != nil {
-			continue // Skip files that can't be parsed
+		if found, err := s.readJSON(file, &pairing); err != nil || !found {
+			continue // Skip files that can't be read or parsed
 		}
 
 		if pairing.IsActive {
@@ -219,8 +226,7 @@ func (s *StorageService) ListGuildPairings(guildID string) ([]ChannelPairingStor
 
 // ListOptedInUsers returns all users who have opted in for a guild
 func (s *StorageService) ListOptedInUsers(guildID string) ([]string, error) {
-	s.mutex.RLock()
-	defer s.mutex.RUnlock()
+	defer s.lockGuild(guildID)()
 
 	pattern := filepath.Join(s.dataDir, fmt.Sprintf("user_*_%s.json", guildID))
 	files, err := filepath.Glob(pattern)
@@ -230,14 +236,9 @@ func (s *StorageService) ListOptedInUsers(guildID string) ([]string, error) {
 
 	var optedInUsers []string
 	for _, file := range files {
-		data, err := os.ReadFile(file)
-		if err != nil {
-			continue // Skip files that can't be read
-		}
-
 		var prefs UserTTSPreferences
-		if err := json.Unmarshal(data, &prefs); err != nil {
-			continue // Skip files that can't be parsed
+		if found, err := s.readJSON(file, &prefs); err != nil || !found {
+			continue // Skip files that can't be read or parsed
 		}
 
 		if prefs.OptedIn {
@@ -247,3 +248,361 @@ func (s *StorageService) ListOptedInUsers(guildID string) ([]string, error) {
 
 	return optedInUsers, nil
 }
+
+// SaveQuotaUsage saves a guild's daily TTS usage to disk
+func (s *StorageService) SaveQuotaUsage(usage QuotaUsage) error {
+	defer s.lockGuild(usage.GuildID)()
+
+	if usage.GuildID == "" {
+		return fmt.Errorf("guild ID is required")
+	}
+
+	usage.UpdatedAt = time.Now()
+
+	filePath := filepath.Join(s.dataDir, fmt.Sprintf("quota_%s.json", usage.GuildID))
+	if err := s.writeJSON(filePath, usage); err != nil {
+		return fmt.Errorf("failed to write quota usage file: %w", err)
+	}
+
+	return nil
+}
+
+// LoadQuotaUsage loads a guild's daily TTS usage from disk
+func (s *StorageService) LoadQuotaUsage(guildID string) (*QuotaUsage, error) {
+	defer s.lockGuild(guildID)()
+
+	filePath := filepath.Join(s.dataDir, fmt.Sprintf("quota_%s.json", guildID))
+
+	var usage QuotaUsage
+	found, err := s.readJSON(filePath, &usage)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read quota usage file: %w", err)
+	}
+	if !found {
+		// No usage recorded yet
+		return &QuotaUsage{GuildID: guildID}, nil
+	}
+
+	return &usage, nil
+}
+
+// SaveGuildStats saves a guild's usage statistics to disk
+func (s *StorageService) SaveGuildStats(stats GuildStats) error {
+	defer s.lockGuild(stats.GuildID)()
+
+	if stats.GuildID == "" {
+		return fmt.Errorf("guild ID is required")
+	}
+
+	stats.UpdatedAt = time.Now()
+
+	filePath := filepath.Join(s.dataDir, fmt.Sprintf("stats_%s.json", stats.GuildID))
+	if err := s.writeJSON(filePath, stats); err != nil {
+		return fmt.Errorf("failed to write guild stats file: %w", err)
+	}
+
+	return nil
+}
+
+// LoadGuildStats loads a guild's usage statistics from disk
+func (s *StorageService) LoadGuildStats(guildID string) (*GuildStats, error) {
+	defer s.lockGuild(guildID)()
+
+	filePath := filepath.Join(s.dataDir, fmt.Sprintf("stats_%s.json", guildID))
+
+	var stats GuildStats
+	found, err := s.readJSON(filePath, &stats)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read guild stats file: %w", err)
+	}
+	if !found {
+		// Nothing recorded yet
+		return &GuildStats{GuildID: guildID}, nil
+	}
+
+	return &stats, nil
+}
+
+// SaveAPITokens saves a guild's HTTP API tokens to disk
+func (s *StorageService) SaveAPITokens(tokens GuildAPITokens) error {
+	defer s.lockGuild(tokens.GuildID)()
+
+	if tokens.GuildID == "" {
+		return fmt.Errorf("guild ID is required")
+	}
+
+	filePath := filepath.Join(s.dataDir, fmt.Sprintf("api_tokens_%s.json", tokens.GuildID))
+	if err := s.writeJSON(filePath, tokens); err != nil {
+		return fmt.Errorf("failed to write API tokens file: %w", err)
+	}
+
+	return nil
+}
+
+// LoadAPITokens loads a guild's HTTP API tokens from disk
+func (s *StorageService) LoadAPITokens(guildID string) (*GuildAPITokens, error) {
+	defer s.lockGuild(guildID)()
+
+	filePath := filepath.Join(s.dataDir, fmt.Sprintf("api_tokens_%s.json", guildID))
+
+	var tokens GuildAPITokens
+	found, err := s.readJSON(filePath, &tokens)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read API tokens file: %w", err)
+	}
+	if !found {
+		// No tokens created yet
+		return &GuildAPITokens{GuildID: guildID}, nil
+	}
+
+	return &tokens, nil
+}
+
+// SaveGuildTranscripts saves a guild's voice session transcripts to disk
+func (s *StorageService) SaveGuildTranscripts(transcripts GuildTranscripts) error {
+	defer s.lockGuild(transcripts.GuildID)()
+
+	if transcripts.GuildID == "" {
+		return fmt.Errorf("guild ID is required")
+	}
+
+	filePath := filepath.Join(s.dataDir, fmt.Sprintf("transcripts_%s.json", transcripts.GuildID))
+	if err := s.writeJSON(filePath, transcripts); err != nil {
+		return fmt.Errorf("failed to write guild transcripts file: %w", err)
+	}
+
+	return nil
+}
+
+// LoadGuildTranscripts loads a guild's voice session transcripts from disk
+func (s *StorageService) LoadGuildTranscripts(guildID string) (*GuildTranscripts, error) {
+	defer s.lockGuild(guildID)()
+
+	filePath := filepath.Join(s.dataDir, fmt.Sprintf("transcripts_%s.json", guildID))
+
+	var transcripts GuildTranscripts
+	found, err := s.readJSON(filePath, &transcripts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read guild transcripts file: %w", err)
+	}
+	if !found {
+		// Nothing recorded yet
+		return &GuildTranscripts{GuildID: guildID}, nil
+	}
+
+	return &transcripts, nil
+}
+
+// RemoveGuildTranscripts deletes a guild's voice session transcripts from disk
+func (s *StorageService) RemoveGuildTranscripts(guildID string) error {
+	defer s.lockGuild(guildID)()
+
+	filePath := filepath.Join(s.dataDir, fmt.Sprintf("transcripts_%s.json", guildID))
+	if err := s.removeFile(filePath); err != nil {
+		return fmt.Errorf("failed to remove guild transcripts file: %w", err)
+	}
+
+	return nil
+}
+
+// SaveModerationSettings saves a guild's moderation settings to disk
+func (s *StorageService) SaveModerationSettings(settings ModerationSettings) error {
+	defer s.lockGuild(settings.GuildID)()
+
+	if settings.GuildID == "" {
+		return fmt.Errorf("guild ID is required")
+	}
+
+	settings.UpdatedAt = time.Now()
+
+	filePath := filepath.Join(s.dataDir, fmt.Sprintf("moderation_%s.json", settings.GuildID))
+	if err := s.writeJSON(filePath, settings); err != nil {
+		return fmt.Errorf("failed to write moderation settings file: %w", err)
+	}
+
+	return nil
+}
+
+// LoadModerationSettings loads a guild's moderation settings from disk
+func (s *StorageService) LoadModerationSettings(guildID string) (*ModerationSettings, error) {
+	defer s.lockGuild(guildID)()
+
+	filePath := filepath.Join(s.dataDir, fmt.Sprintf("moderation_%s.json", guildID))
+
+	var settings ModerationSettings
+	found, err := s.readJSON(filePath, &settings)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read moderation settings file: %w", err)
+	}
+	if !found {
+		// Moderation has not been configured yet
+		return &ModerationSettings{GuildID: guildID, Mode: DefaultModerationMode}, nil
+	}
+
+	return &settings, nil
+}
+
+// SaveGuildProfiles saves a guild's configuration profiles to disk
+func (s *StorageService) SaveGuildProfiles(profiles GuildProfiles) error {
+	defer s.lockGuild(profiles.GuildID)()
+
+	if profiles.GuildID == "" {
+		return fmt.Errorf("guild ID is required")
+	}
+
+	profiles.UpdatedAt = time.Now()
+
+	filePath := filepath.Join(s.dataDir, fmt.Sprintf("profiles_%s.json", profiles.GuildID))
+	if err := s.writeJSON(filePath, profiles); err != nil {
+		return fmt.Errorf("failed to write guild profiles file: %w", err)
+	}
+
+	return nil
+}
+
+// LoadGuildProfiles loads a guild's configuration profiles from disk
+func (s *StorageService) LoadGuildProfiles(guildID string) (*GuildProfiles, error) {
+	defer s.lockGuild(guildID)()
+
+	filePath := filepath.Join(s.dataDir, fmt.Sprintf("profiles_%s.json", guildID))
+
+	var profiles GuildProfiles
+	found, err := s.readJSON(filePath, &profiles)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read guild profiles file: %w", err)
+	}
+	if !found {
+		// No profiles saved yet
+		return &GuildProfiles{GuildID: guildID}, nil
+	}
+
+	return &profiles, nil
+}
+
+// SaveAudioClip writes encoded clip audio and its metadata to disk
+func (s *StorageService) SaveAudioClip(clip AudioClip, audio []byte) error {
+	defer s.lockGuild(clip.GuildID)()
+
+	if clip.GuildID == "" || clip.Name == "" {
+		return fmt.Errorf("guild ID and clip name are required")
+	}
+
+	clipDir := filepath.Join(s.dataDir, "clips", clip.GuildID)
+	if err := os.MkdirAll(clipDir, 0755); err != nil {
+		return fmt.Errorf("failed to create clip directory: %w", err)
+	}
+
+	if err := writeFileAtomic(filepath.Join(clipDir, clip.Name+".dca"), audio); err != nil {
+		return fmt.Errorf("failed to write clip audio file: %w", err)
+	}
+
+	if err := s.writeJSON(filepath.Join(clipDir, clip.Name+".json"), clip); err != nil {
+		return fmt.Errorf("failed to write clip metadata file: %w", err)
+	}
+
+	return nil
+}
+
+// LoadAudioClip reads encoded clip audio from disk
+func (s *StorageService) LoadAudioClip(guildID, name string) ([]byte, error) {
+	defer s.lockGuild(guildID)()
+
+	filePath := filepath.Join(s.dataDir, "clips", guildID, name+".dca")
+	audio, err := os.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		return nil, ErrClipNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read clip audio file: %w", err)
+	}
+
+	return audio, nil
+}
+
+// RemoveAudioClip deletes a clip's audio and metadata from disk
+func (s *StorageService) RemoveAudioClip(guildID, name string) error {
+	defer s.lockGuild(guildID)()
+
+	clipDir := filepath.Join(s.dataDir, "clips", guildID)
+	if err := os.Remove(filepath.Join(clipDir, name+".dca")); err != nil {
+		if os.IsNotExist(err) {
+			return ErrClipNotFound
+		}
+		return fmt.Errorf("failed to remove clip audio file: %w", err)
+	}
+
+	if err := s.removeFile(filepath.Join(clipDir, name+".json")); err != nil {
+		return fmt.Errorf("failed to remove clip metadata file: %w", err)
+	}
+
+	return nil
+}
+
+// ListAudioClips returns metadata for all clips stored for a guild
+func (s *StorageService) ListAudioClips(guildID string) ([]AudioClip, error) {
+	defer s.lockGuild(guildID)()
+
+	pattern := filepath.Join(s.dataDir, "clips", guildID, "*.json")
+	files, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list clip files: %w", err)
+	}
+
+	var clips []AudioClip
+	for _, file := range files {
+		var clip AudioClip
+		if found, err := s.readJSON(file, &clip); err != nil || !found {
+			continue // Skip files that can't be read or parsed
+		}
+
+		clips = append(clips, clip)
+	}
+
+	return clips, nil
+}
+
+// SaveVoiceHandoff saves the voice sessions to resume on the next start
+func (s *StorageService) SaveVoiceHandoff(handoff VoiceHandoff) error {
+	defer s.lockGuild("")()
+
+	handoff.SavedAt = time.Now()
+
+	filePath := filepath.Join(s.dataDir, "handoff.json")
+	if err := s.writeJSON(filePath, handoff); err != nil {
+		return fmt.Errorf("failed to write voice handoff file: %w", err)
+	}
+
+	return nil
+}
+
+// LoadVoiceHandoff loads the voice sessions saved by the last shutdown
+func (s *StorageService) LoadVoiceHandoff() (*VoiceHandoff, error) {
+	defer s.lockGuild("")()
+
+	filePath := filepath.Join(s.dataDir, "handoff.json")
+
+	var handoff VoiceHandoff
+	found, err := s.readJSON(filePath, &handoff)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read voice handoff file: %w", err)
+	}
+	if !found {
+		// Nothing to resume
+		return &VoiceHandoff{}, nil
+	}
+
+	return &handoff, nil
+}
+
+// ClearVoiceHandoff removes the saved voice sessions
+func (s *StorageService) ClearVoiceHandoff() error {
+	defer s.lockGuild("")()
+
+	filePath := filepath.Join(s.dataDir, "handoff.json")
+
+	if err := s.removeFile(filePath); err != nil {
+		return fmt.Errorf("failed to remove voice handoff file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,314 @@
+package tts
+
+import (
+	"bufio"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"darrot/internal/config"
+
+	"google.golang.org/api/cloudkms/v1"
+	"google.golang.org/api/option"
+)
+
+// storageAlgorithm marks a stored file as encrypted and names how. Encrypted files are
+// still JSON, so the checksum and backup of storage_files.go work the same for them.
+const storageAlgorithm = "AES-256-GCM"
+
+// storageKeyLoadTimeout bounds unwrapping the storage keys with Cloud KMS
+const storageKeyLoadTimeout = 30 * time.Second
+
+// ErrStorageKeyUnavailable is returned for files encrypted with a key that is not
+// configured. Such files are not damaged, so they are never replaced by their backup.
+var ErrStorageKeyUnavailable = errors.New("storage encryption key unavailable")
+
+// encryptedFile is how an encrypted file is stored
+type encryptedFile struct {
+	Algorithm  string `json:"encrypted"`
+	KeyID      string `json:"key_id"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// StorageCipher encrypts stored files with AES-256-GCM. The first key encrypts; the
+// others only decrypt, so a key can be rotated by putting the new key first and
+// dropping the old one once every file was re-encrypted.
+type StorageCipher struct {
+	keyIDs []string               // In order, the first one encrypts
+	aeads  map[string]cipher.AEAD // By key ID
+}
+
+// NewStorageCipher creates a cipher from raw 32-byte keys, the key that encrypts first
+func NewStorageCipher(keys [][]byte) (*StorageCipher, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("no storage encryption keys")
+	}
+
+	c := &StorageCipher{aeads: make(map[string]cipher.AEAD, len(keys))}
+	for i, key := range keys {
+		if len(key) != 32 {
+			return nil, fmt.Errorf("storage encryption key %d must be 32 bytes, got %d", i+1, len(key))
+		}
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+
+		id := storageKeyID(key)
+		if _, duplicate := c.aeads[id]; duplicate {
+			return nil, fmt.Errorf("storage encryption key %d is listed twice", i+1)
+		}
+		c.keyIDs = append(c.keyIDs, id)
+		c.aeads[id] = aead
+	}
+	return c, nil
+}
+
+// LoadStorageCipher creates the cipher configured by tts.storage_encryption_keys, first
+// unwrapping the keys with tts.storage_encryption_kms_key when it is set. It returns
+// nil when no keys are configured and data is stored unencrypted.
+func LoadStorageCipher(ctx context.Context, cfg config.TTSConfig, opts ...option.ClientOption) (*StorageCipher, error) {
+	encoded := cfg.StorageKeyList()
+	if len(encoded) == 0 {
+		return nil, nil
+	}
+
+	var service *cloudkms.Service
+	if cfg.StorageEncryptionKMSKey != "" {
+		var err error
+		if service, err = cloudkms.NewService(ctx, opts...); err != nil {
+			return nil, fmt.Errorf("failed to create Cloud KMS client: %w", err)
+		}
+	}
+
+	keys := make([][]byte, len(encoded))
+	for i, key := range encoded {
+		if service != nil {
+			request := &cloudkms.DecryptRequest{Ciphertext: key}
+			response, err := service.Projects.Locations.KeyRings.CryptoKeys.Decrypt(cfg.StorageEncryptionKMSKey, request).Context(ctx).Do()
+			if err != nil {
+				return nil, fmt.Errorf("failed to unwrap storage encryption key %d with %s: %w", i+1, cfg.StorageEncryptionKMSKey, err)
+			}
+			key = response.Plaintext
+		}
+
+		decoded, err := base64.StdEncoding.DecodeString(key)
+		if err != nil {
+			return nil, fmt.Errorf("storage encryption key %d is not valid base64: %w", i+1, err)
+		}
+		keys[i] = decoded
+	}
+	return NewStorageCipher(keys)
+}
+
+// CurrentKeyID returns the ID of the key that encrypts
+func (c *StorageCipher) CurrentKeyID() string {
+	return c.keyIDs[0]
+}
+
+// seal encrypts data with the current key. The file name is authenticated with it, so
+// an encrypted file cannot be passed off as another one, such as a different user's.
+func (c *StorageCipher) seal(path string, data []byte) ([]byte, error) {
+	file, err := c.encrypt(path, data)
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(file, "", "  ")
+}
+
+// encrypt encrypts data with the current key, authenticating the file name with it
+func (c *StorageCipher) encrypt(path string, data []byte) (encryptedFile, error) {
+	aead := c.aeads[c.CurrentKeyID()]
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return encryptedFile{}, fmt.Errorf("failed to create nonce: %w", err)
+	}
+
+	return encryptedFile{
+		Algorithm:  storageAlgorithm,
+		KeyID:      c.CurrentKeyID(),
+		Nonce:      nonce,
+		Ciphertext: aead.Seal(nil, nonce, data, []byte(filepath.Base(path))),
+	}, nil
+}
+
+// open decrypts an encrypted file
+func (c *StorageCipher) open(path string, file encryptedFile) ([]byte, error) {
+	aead, ok := c.aeads[file.KeyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s was encrypted with key %s", ErrStorageKeyUnavailable, filepath.Base(path), file.KeyID)
+	}
+	if len(file.Nonce) != aead.NonceSize() {
+		return nil, errors.New("invalid nonce")
+	}
+
+	data, err := aead.Open(nil, file.Nonce, file.Ciphertext, []byte(filepath.Base(path)))
+	if err != nil {
+		return nil, errors.New("decryption failed")
+	}
+	return data, nil
+}
+
+// storageKeyID identifies a key in the files it encrypted without revealing it
+func storageKeyID(key []byte) string {
+	sum := sha256.Sum256(key)
+	return hex.EncodeToString(sum[:4])
+}
+
+// parseEncryptedFile reports whether data is an encrypted file and returns it
+func parseEncryptedFile(data []byte) (encryptedFile, bool) {
+	var file encryptedFile
+	if json.Unmarshal(data, &file) != nil || file.Algorithm == "" {
+		return encryptedFile{}, false
+	}
+	return file, true
+}
+
+// SetCipher sets the cipher files are encrypted with. Unencrypted files and files
+// encrypted with an older key keep being read and are encrypted with the current key
+// when they are next written. Nil stores files unencrypted.
+func (s *StorageService) SetCipher(c *StorageCipher) {
+	s.cipher = c
+}
+
+// Cipher returns the cipher files are encrypted with, nil when they are not
+func (s *StorageService) Cipher() *StorageCipher {
+	return s.cipher
+}
+
+// EnableEncryption sets the cipher and re-encrypts every file that is unencrypted or
+// encrypted with an older key right away, so an old key can be removed after the next
+// start. It must run before the services using the storage do.
+func (s *StorageService) EnableEncryption(c *StorageCipher) error {
+	s.SetCipher(c)
+	if c == nil {
+		return nil
+	}
+
+	count, err := s.Reencrypt()
+	if count > 0 {
+		log.Printf("Encrypted %d stored files in %s with storage key %s", count, s.dataDir, c.CurrentKeyID())
+	}
+	return err
+}
+
+// Reencrypt rewrites the JSON files of the data directory that are not encrypted with the
+// current key and returns how many it rewrote. Their backups are replaced too, so no copy
+// is left unencrypted or under an old key. Queue spillover segments holding messages not
+// encrypted with the current key are from a previous run, which are never read again, so
+// they are removed instead.
+func (s *StorageService) Reencrypt() (int, error) {
+	if s.cipher == nil {
+		return 0, nil
+	}
+	if err := s.removeStaleSpillover(); err != nil {
+		return 0, fmt.Errorf("failed to remove unencrypted queue spillover: %w", err)
+	}
+
+	paths, err := filepath.Glob(filepath.Join(s.dataDir, "*.json"))
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	var errs []error
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if file, ok := parseEncryptedFile(data); ok && file.KeyID == s.cipher.CurrentKeyID() {
+			continue
+		}
+
+		var value json.RawMessage
+		if _, err := s.readJSON(path, &value); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		// The first write backs up the old version, the second replaces that backup
+		if err := s.writeJSON(path, value); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err := s.writeJSON(path, value); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		count++
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return count, fmt.Errorf("failed to re-encrypt stored files: %w", err)
+	}
+	return count, nil
+}
+
+// removeStaleSpillover removes the queue spillover segments whose first message is not
+// encrypted with the current key
+func (s *StorageService) removeStaleSpillover() error {
+	paths, err := filepath.Glob(filepath.Join(s.QueueSpilloverDir(), "queue_*.jsonl"))
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, path := range paths {
+		file, err := os.Open(path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		line, _ := bufio.NewReader(file).ReadBytes('\n')
+		file.Close()
+
+		if sealed, ok := parseEncryptedFile(line); ok && sealed.KeyID == s.cipher.CurrentKeyID() {
+			continue
+		}
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// encodeStored returns the bytes stored for data, encrypted when a cipher is set
+func (s *StorageService) encodeStored(path string, data []byte) ([]byte, error) {
+	if s.cipher == nil {
+		return data, nil
+	}
+	return s.cipher.seal(path, data)
+}
+
+// decodeStored returns the JSON held by stored bytes, decrypting them when they are
+// encrypted. Unencrypted files are returned as they are.
+func (s *StorageService) decodeStored(path string, stored []byte) ([]byte, error) {
+	file, encrypted := parseEncryptedFile(stored)
+	if !encrypted {
+		return stored, nil
+	}
+	if file.Algorithm != storageAlgorithm {
+		return nil, fmt.Errorf("%s is encrypted with unsupported algorithm %q", filepath.Base(path), file.Algorithm)
+	}
+	if s.cipher == nil {
+		return nil, fmt.Errorf("%w: %s is encrypted but tts.storage_encryption_keys is not set", ErrStorageKeyUnavailable, filepath.Base(path))
+	}
+	return s.cipher.open(path, file)
+}
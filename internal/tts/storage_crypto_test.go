@@ -0,0 +1,268 @@
+package tts
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"darrot/internal/config"
+
+	"google.golang.org/api/option"
+)
+
+// testStorageKey returns a 32-byte key filled with b
+func testStorageKey(b byte) []byte {
+	return bytes.Repeat([]byte{b}, 32)
+}
+
+func newTestCipher(t *testing.T, keys ...[]byte) *StorageCipher {
+	t.Helper()
+
+	cipher, err := NewStorageCipher(keys)
+	if err != nil {
+		t.Fatalf("Failed to create storage cipher: %v", err)
+	}
+	return cipher
+}
+
+// readEncryptedFile returns the encrypted file stored at path, failing when it is stored
+// unencrypted
+func readEncryptedFile(t *testing.T, path string) encryptedFile {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", path, err)
+	}
+	file, ok := parseEncryptedFile(data)
+	if !ok {
+		t.Fatalf("Expected %s to be encrypted, got %s", filepath.Base(path), data)
+	}
+	return file
+}
+
+func TestStorageService_EncryptsFiles(t *testing.T) {
+	service, tempDir := newTestStorage(t)
+	service.SetCipher(newTestCipher(t, testStorageKey(1)))
+
+	prefs := DefaultUserPreferences("user1", "guild1")
+	prefs.OptedIn = true
+	if err := service.SaveUserPreferences(prefs); err != nil {
+		t.Fatalf("Failed to save user preferences: %v", err)
+	}
+
+	path := filepath.Join(tempDir, "user_user1_guild1.json")
+	file := readEncryptedFile(t, path)
+	if file.KeyID != service.Cipher().CurrentKeyID() {
+		t.Errorf("Expected the file to be encrypted with the current key, got %s", file.KeyID)
+	}
+	data, _ := os.ReadFile(path)
+	if bytes.Contains(data, []byte("user1")) {
+		t.Error("Expected no plaintext in the encrypted file")
+	}
+
+	loaded, err := service.LoadUserPreferences("user1", "guild1")
+	if err != nil {
+		t.Fatalf("Failed to load user preferences: %v", err)
+	}
+	if !loaded.OptedIn {
+		t.Error("Expected the decrypted preferences to be opted in")
+	}
+}
+
+func TestStorageService_ReadsUnencryptedFiles(t *testing.T) {
+	service, tempDir := newTestStorage(t)
+	saveQueueSize(t, service, "g1", 15)
+
+	// Files written before encryption was turned on are read and encrypted when written
+	service.SetCipher(newTestCipher(t, testStorageKey(1)))
+	if size := loadQueueSize(t, service, "g1"); size != 15 {
+		t.Errorf("Expected the unencrypted queue size 15, got %d", size)
+	}
+
+	saveQueueSize(t, service, "g1", 20)
+	readEncryptedFile(t, filepath.Join(tempDir, "guild_g1.json"))
+	if size := loadQueueSize(t, service, "g1"); size != 20 {
+		t.Errorf("Expected queue size 20, got %d", size)
+	}
+}
+
+func TestStorageService_KeyRotation(t *testing.T) {
+	service, tempDir := newTestStorage(t)
+	service.SetCipher(newTestCipher(t, testStorageKey(1)))
+	saveQueueSize(t, service, "g1", 15)
+	saveQueueSize(t, service, "g1", 16)
+
+	// The new key encrypts; the old one still decrypts
+	rotated := newTestCipher(t, testStorageKey(2), testStorageKey(1))
+	service.SetCipher(rotated)
+	if size := loadQueueSize(t, service, "g1"); size != 16 {
+		t.Errorf("Expected queue size 16 under the old key, got %d", size)
+	}
+
+	count, err := service.Reencrypt()
+	if err != nil {
+		t.Fatalf("Failed to re-encrypt: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Expected 1 file to be re-encrypted, got %d", count)
+	}
+
+	// Neither the file nor its backup is left under the old key
+	path := filepath.Join(tempDir, "guild_g1.json")
+	for _, file := range []string{path, path + backupSuffix} {
+		if id := readEncryptedFile(t, file).KeyID; id != rotated.CurrentKeyID() {
+			t.Errorf("Expected %s to be encrypted with the new key, got %s", filepath.Base(file), id)
+		}
+	}
+
+	// Once re-encrypted, the old key can be dropped
+	service.SetCipher(newTestCipher(t, testStorageKey(2)))
+	if size := loadQueueSize(t, service, "g1"); size != 16 {
+		t.Errorf("Expected queue size 16 under the new key, got %d", size)
+	}
+	if count, _ := service.Reencrypt(); count != 0 {
+		t.Errorf("Expected nothing left to re-encrypt, got %d files", count)
+	}
+}
+
+func TestStorageService_ReencryptRemovesSpillover(t *testing.T) {
+	service, _ := newTestStorage(t)
+	dir := service.QueueSpilloverDir()
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		t.Fatalf("Failed to create spillover directory: %v", err)
+	}
+	plain := filepath.Join(dir, "queue_g1.jsonl")
+	if err := os.WriteFile(plain, []byte(`{"content":"secret words"}`+"\n"), 0600); err != nil {
+		t.Fatalf("Failed to write segment: %v", err)
+	}
+
+	// A segment sealed with the current key belongs to a running queue and is kept
+	cipher := newTestCipher(t, testStorageKey(1))
+	sealed := filepath.Join(dir, "queue_g2.jsonl")
+	file, err := cipher.encrypt(sealed, []byte(`{"content":"secret words"}`))
+	if err != nil {
+		t.Fatalf("Failed to encrypt: %v", err)
+	}
+	line, _ := json.Marshal(file)
+	if err := os.WriteFile(sealed, append(line, '\n'), 0600); err != nil {
+		t.Fatalf("Failed to write segment: %v", err)
+	}
+
+	service.SetCipher(cipher)
+	if _, err := service.Reencrypt(); err != nil {
+		t.Fatalf("Failed to re-encrypt: %v", err)
+	}
+	if _, err := os.Stat(plain); !os.IsNotExist(err) {
+		t.Errorf("Expected the unencrypted segment to be removed, got %v", err)
+	}
+	if _, err := os.Stat(sealed); err != nil {
+		t.Errorf("Expected the encrypted segment to be kept, got %v", err)
+	}
+}
+
+func TestStorageService_MissingKey(t *testing.T) {
+	service, _ := newTestStorage(t)
+	service.SetCipher(newTestCipher(t, testStorageKey(1)))
+	saveQueueSize(t, service, "g1", 15)
+	saveQueueSize(t, service, "g1", 16)
+
+	// A missing key is a configuration error, not damage the backup could repair
+	for _, cipher := range []*StorageCipher{nil, newTestCipher(t, testStorageKey(2))} {
+		service.SetCipher(cipher)
+		if _, err := service.LoadGuildConfig("g1"); !errors.Is(err, ErrStorageKeyUnavailable) {
+			t.Errorf("Expected ErrStorageKeyUnavailable, got %v", err)
+		}
+	}
+}
+
+func TestStorageService_TamperedFileRestoresBackup(t *testing.T) {
+	service, tempDir := newTestStorage(t)
+	service.SetCipher(newTestCipher(t, testStorageKey(1)))
+	saveQueueSize(t, service, "g1", 15)
+	saveQueueSize(t, service, "g1", 20)
+
+	// An encrypted file moved over another one fails to decrypt, even with a valid checksum
+	path := filepath.Join(tempDir, "guild_g1.json")
+	saveQueueSize(t, service, "g2", 30)
+	other, err := os.ReadFile(filepath.Join(tempDir, "guild_g2.json"))
+	if err != nil {
+		t.Fatalf("Failed to read guild config file: %v", err)
+	}
+	if err := os.WriteFile(path, other, 0600); err != nil {
+		t.Fatalf("Failed to replace guild config file: %v", err)
+	}
+	if err := os.WriteFile(path+checksumSuffix, []byte(checksum(other)+"\n"), 0600); err != nil {
+		t.Fatalf("Failed to replace checksum: %v", err)
+	}
+
+	if size := loadQueueSize(t, service, "g1"); size != 15 {
+		t.Errorf("Expected the backup's queue size 15, got %d", size)
+	}
+}
+
+func TestNewStorageCipher_InvalidKeys(t *testing.T) {
+	if _, err := NewStorageCipher(nil); err == nil {
+		t.Error("Expected an error without keys")
+	}
+	if _, err := NewStorageCipher([][]byte{make([]byte, 16)}); err == nil {
+		t.Error("Expected an error for a 16-byte key")
+	}
+	if _, err := NewStorageCipher([][]byte{testStorageKey(1), testStorageKey(1)}); err == nil {
+		t.Error("Expected an error for a key listed twice")
+	}
+}
+
+func TestLoadStorageCipher(t *testing.T) {
+	cipher, err := LoadStorageCipher(context.Background(), config.TTSConfig{})
+	if err != nil || cipher != nil {
+		t.Fatalf("Expected no cipher without keys, got %v, %v", cipher, err)
+	}
+
+	key := base64.StdEncoding.EncodeToString(testStorageKey(1))
+	cipher, err = LoadStorageCipher(context.Background(), config.TTSConfig{StorageEncryptionKeys: key})
+	if err != nil {
+		t.Fatalf("Failed to load storage cipher: %v", err)
+	}
+	if cipher.CurrentKeyID() != storageKeyID(testStorageKey(1)) {
+		t.Errorf("Expected the configured key, got %s", cipher.CurrentKeyID())
+	}
+
+	// Keys wrapped with Cloud KMS are unwrapped first
+	var decrypted string
+	kmsServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		decrypted = r.URL.Path
+		var request struct {
+			Ciphertext string `json:"ciphertext"`
+		}
+		json.NewDecoder(r.Body).Decode(&request)
+		if request.Ciphertext != "d3JhcHBlZA==" {
+			http.Error(w, "unknown ciphertext", http.StatusBadRequest)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"plaintext": key})
+	}))
+	defer kmsServer.Close()
+
+	cfg := config.TTSConfig{
+		StorageEncryptionKeys:   "d3JhcHBlZA==",
+		StorageEncryptionKMSKey: "projects/p/locations/global/keyRings/r/cryptoKeys/storage",
+	}
+	cipher, err = LoadStorageCipher(context.Background(), cfg, option.WithEndpoint(kmsServer.URL+"/"), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("Failed to load storage cipher with Cloud KMS: %v", err)
+	}
+	if decrypted != "/v1/projects/p/locations/global/keyRings/r/cryptoKeys/storage:decrypt" {
+		t.Errorf("Expected the key to be decrypted with the configured KMS key, got %s", decrypted)
+	}
+	if cipher.CurrentKeyID() != storageKeyID(testStorageKey(1)) {
+		t.Errorf("Expected the unwrapped key, got %s", cipher.CurrentKeyID())
+	}
+}
@@ -0,0 +1,193 @@
+package tts
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+)
+
+// Every JSON file in the data directory is written to a temporary file and renamed into
+// place, so a crash or a full disk never leaves a half-written file behind. Next to it
+// are its SHA-256 checksum, which catches files that were damaged after they were
+// written, and a backup of the last version that passed the checksum. A file that fails
+// either check on load is replaced by its backup.
+const (
+	checksumSuffix = ".sha256"
+	backupSuffix   = ".bak"
+)
+
+// lockGuild locks the files of a guild until the returned function is called, so writes
+// to one guild never wait for another. Files that belong to no guild use the empty ID.
+func (s *StorageService) lockGuild(guildID string) (unlock func()) {
+	s.locksMu.Lock()
+	lock, ok := s.locks[guildID]
+	if !ok {
+		lock = &sync.Mutex{}
+		s.locks[guildID] = lock
+	}
+	s.locksMu.Unlock()
+
+	lock.Lock()
+	return lock.Unlock
+}
+
+// writeJSON replaces path with value as indented JSON, encrypted when a cipher is set
+// (see storage_crypto.go), keeping the version it replaces as the backup when it is intact
+func (s *StorageService) writeJSON(path string, value any) error {
+	plain, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", filepath.Base(path), err)
+	}
+	data, err := s.encodeStored(path, plain)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt %s: %w", filepath.Base(path), err)
+	}
+
+	if current, err := os.ReadFile(path); err == nil && verifyChecksum(path, current) == nil && json.Valid(current) {
+		if err := writeFileAtomic(path+backupSuffix, current); err != nil {
+			log.Printf("Warning: Failed to back up %s: %v", path, err)
+		}
+	}
+
+	// The data goes first: a crash before the checksum is written leaves a mismatch, and
+	// the backup is restored on the next load
+	if err := writeFileAtomic(path, data); err != nil {
+		return err
+	}
+	return writeFileAtomic(path+checksumSuffix, []byte(checksum(data)+"\n"))
+}
+
+// readJSON decodes path into value and reports whether the file exists. A file that
+// fails its checksum, decryption or does not parse is replaced by its backup; it is an
+// error only when the backup is unusable too. A file encrypted with a key that is not
+// configured is an error right away.
+func (s *StorageService) readJSON(path string, value any) (bool, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	damage := verifyChecksum(path, data)
+	if damage == nil {
+		if damage = s.unmarshalStored(path, data, value); damage == nil {
+			return true, nil
+		}
+		if errors.Is(damage, ErrStorageKeyUnavailable) {
+			return false, damage
+		}
+	}
+
+	backup, err := os.ReadFile(path + backupSuffix)
+	if err != nil {
+		return false, fmt.Errorf("%s is damaged and has no backup: %w", filepath.Base(path), damage)
+	}
+	reflect.ValueOf(value).Elem().SetZero() // Drop whatever the damaged file filled in
+	if err := s.unmarshalStored(path, backup, value); err != nil {
+		return false, fmt.Errorf("%s and its backup are damaged: %w", filepath.Base(path), damage)
+	}
+
+	log.Printf("Warning: %s is damaged (%v), restored the last good version", path, damage)
+	if err := writeFileAtomic(path, backup); err != nil {
+		log.Printf("Warning: Failed to restore %s: %v", path, err)
+	} else if err := writeFileAtomic(path+checksumSuffix, []byte(checksum(backup)+"\n")); err != nil {
+		log.Printf("Warning: Failed to restore the checksum of %s: %v", path, err)
+	}
+	return true, nil
+}
+
+// unmarshalStored decodes the stored bytes of path into value, decrypting them first
+// when they are encrypted
+func (s *StorageService) unmarshalStored(path string, stored []byte, value any) error {
+	data, err := s.decodeStored(path, stored)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, value)
+}
+
+// removeFile removes path together with its checksum and backup
+func (s *StorageService) removeFile(path string) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for _, sidecar := range []string{path + checksumSuffix, path + backupSuffix} {
+		if err := os.Remove(sidecar); err != nil && !os.IsNotExist(err) {
+			log.Printf("Warning: Failed to remove %s: %v", sidecar, err)
+		}
+	}
+	return nil
+}
+
+// CheckWritable writes and removes a file in the data directory, the way every save
+// does, to check that saves can succeed
+func (s *StorageService) CheckWritable() error {
+	path := filepath.Join(s.dataDir, ".write-check")
+	if err := writeFileAtomic(path, []byte("ok\n")); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
+
+// writeFileAtomic writes data to a temporary file next to path, syncs it and renames it
+// over path
+func writeFileAtomic(path string, data []byte) error {
+	file, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	tmpPath := file.Name()
+	defer os.Remove(tmpPath) // No-op once renamed
+
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	if err := file.Chmod(0600); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to set permissions of %s: %w", filepath.Base(path), err)
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to sync %s: %w", filepath.Base(path), err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to close %s: %w", filepath.Base(path), err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
+
+// checksum returns the hex SHA-256 of data
+func checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// verifyChecksum checks data against the checksum stored next to path. Files written
+// before checksums were kept have none and pass.
+func verifyChecksum(path string, data []byte) error {
+	stored, err := os.ReadFile(path + checksumSuffix)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read checksum: %w", err)
+	}
+	if string(bytes.TrimSpace(stored)) != checksum(data) {
+		return errors.New("checksum mismatch")
+	}
+	return nil
+}
@@ -0,0 +1,187 @@
+package tts
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func newTestStorage(t *testing.T) (*StorageService, string) {
+	t.Helper()
+
+	tempDir := t.TempDir()
+	service, err := NewStorageService(tempDir)
+	if err != nil {
+		t.Fatalf("Failed to create storage service: %v", err)
+	}
+	return service, tempDir
+}
+
+func saveQueueSize(t *testing.T, service *StorageService, guildID string, size int) {
+	t.Helper()
+
+	config := DefaultGuildTTSConfig(guildID)
+	config.MaxQueueSize = size
+	if err := service.SaveGuildConfig(config); err != nil {
+		t.Fatalf("Failed to save guild config: %v", err)
+	}
+}
+
+func loadQueueSize(t *testing.T, service *StorageService, guildID string) int {
+	t.Helper()
+
+	config, err := service.LoadGuildConfig(guildID)
+	if err != nil {
+		t.Fatalf("Failed to load guild config: %v", err)
+	}
+	return config.MaxQueueSize
+}
+
+func TestStorageService_WritesChecksumAndBackup(t *testing.T) {
+	service, tempDir := newTestStorage(t)
+	filePath := filepath.Join(tempDir, "guild_g1.json")
+
+	saveQueueSize(t, service, "g1", 15)
+	if _, err := os.Stat(filePath + backupSuffix); !os.IsNotExist(err) {
+		t.Error("Expected no backup before the file is replaced")
+	}
+
+	saveQueueSize(t, service, "g1", 20)
+
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatalf("Failed to read guild config file: %v", err)
+	}
+	if err := verifyChecksum(filePath, data); err != nil {
+		t.Errorf("Expected the checksum to match: %v", err)
+	}
+
+	backup, err := os.ReadFile(filePath + backupSuffix)
+	if err != nil {
+		t.Fatalf("Expected a backup of the previous version: %v", err)
+	}
+	if !strings.Contains(string(backup), `"max_queue_size": 15`) {
+		t.Errorf("Expected the backup to hold the previous version, got %s", backup)
+	}
+
+	// No temporary files are left behind
+	entries, _ := os.ReadDir(tempDir)
+	for _, entry := range entries {
+		if strings.Contains(entry.Name(), ".tmp-") {
+			t.Errorf("Temporary file %s was left behind", entry.Name())
+		}
+	}
+}
+
+func TestStorageService_RestoresDamagedFile(t *testing.T) {
+	tests := []struct {
+		name   string
+		damage func(path string) error
+	}{
+		{"unparsable", func(path string) error { return os.WriteFile(path, []byte(`{"guild_id": "g1", "max_qu`), 0600) }},
+		{"checksum mismatch", func(path string) error {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			return os.WriteFile(path, []byte(strings.Replace(string(data), `"max_queue_size": 20`, `"max_queue_size": 99`, 1)), 0600)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, tempDir := newTestStorage(t)
+			filePath := filepath.Join(tempDir, "guild_g1.json")
+
+			saveQueueSize(t, service, "g1", 15)
+			saveQueueSize(t, service, "g1", 20)
+			if err := tt.damage(filePath); err != nil {
+				t.Fatalf("Failed to damage the file: %v", err)
+			}
+
+			// The last good version comes back and is written in place of the damaged one
+			if size := loadQueueSize(t, service, "g1"); size != 15 {
+				t.Errorf("Expected the backup's MaxQueueSize 15, got %d", size)
+			}
+			data, _ := os.ReadFile(filePath)
+			if err := verifyChecksum(filePath, data); err != nil {
+				t.Errorf("Expected the restored file to pass its checksum: %v", err)
+			}
+		})
+	}
+}
+
+func TestStorageService_DamagedFileWithoutBackup(t *testing.T) {
+	service, tempDir := newTestStorage(t)
+
+	saveQueueSize(t, service, "g1", 15)
+	if err := os.WriteFile(filepath.Join(tempDir, "guild_g1.json"), []byte("{"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := service.LoadGuildConfig("g1"); err == nil {
+		t.Error("Expected an error for a damaged file without a backup")
+	}
+}
+
+func TestStorageService_LoadsFilesWithoutChecksum(t *testing.T) {
+	service, tempDir := newTestStorage(t)
+
+	// Files written before checksums were kept
+	if err := os.WriteFile(filepath.Join(tempDir, "guild_g1.json"), []byte(`{"guild_id": "g1", "max_queue_size": 12}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if size := loadQueueSize(t, service, "g1"); size != 12 {
+		t.Errorf("Expected MaxQueueSize 12, got %d", size)
+	}
+}
+
+func TestStorageService_RemoveDeletesChecksumAndBackup(t *testing.T) {
+	service, tempDir := newTestStorage(t)
+
+	transcripts := GuildTranscripts{GuildID: "g1"}
+	for i := 0; i < 2; i++ {
+		if err := service.SaveGuildTranscripts(transcripts); err != nil {
+			t.Fatalf("Failed to save transcripts: %v", err)
+		}
+	}
+	if err := service.RemoveGuildTranscripts("g1"); err != nil {
+		t.Fatalf("Failed to remove transcripts: %v", err)
+	}
+
+	entries, _ := os.ReadDir(tempDir)
+	if len(entries) != 0 {
+		t.Errorf("Expected an empty data directory, found %d files", len(entries))
+	}
+}
+
+func TestStorageService_ConcurrentWrites(t *testing.T) {
+	service, _ := newTestStorage(t)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		for i := 1; i <= 10; i++ {
+			wg.Add(1)
+			go func(guildID string, size int) {
+				defer wg.Done()
+				config := DefaultGuildTTSConfig(guildID)
+				config.MaxQueueSize = size
+				if err := service.SaveGuildConfig(config); err != nil {
+					t.Errorf("Failed to save guild config: %v", err)
+				}
+			}(fmt.Sprintf("g%d", g), i)
+		}
+	}
+	wg.Wait()
+
+	// Every guild ends up with one of the versions written, intact
+	for g := 0; g < 4; g++ {
+		if size := loadQueueSize(t, service, fmt.Sprintf("g%d", g)); size < 1 || size > 10 {
+			t.Errorf("Expected a MaxQueueSize written for guild g%d, got %d", g, size)
+		}
+	}
+}
@@ -3,24 +3,41 @@ package tts
 import (
 	"fmt"
 	"log"
+	"time"
 
+	"darrot/internal/app"
 	"darrot/internal/config"
+	"darrot/internal/i18n"
 
 	"github.com/bwmarrin/discordgo"
 )
 
 // TTSSystem is the main coordinator for all TTS functionality
 type TTSSystem struct {
-	// Core components
-	ttsManager        TTSManager
-	voiceManager      VoiceManager
-	messageQueue      MessageQueue
-	ttsProcessor      TTSProcessor
-	messageMonitor    *MessageMonitor
-	channelService    ChannelService
-	permissionService PermissionService
-	userService       UserService
-	configService     ConfigService
+	// Shared services and the components built from them
+	services           *Services
+	messageMonitor     *MessageMonitor
+	voiceAnnouncer     *VoiceAnnouncer
+	reactionSummarizer *ReactionSummarizer
+	skipNotes          *SkipNotes
+	mutePauser         *MutePauser
+	pauseScheduler     *PauseScheduler
+	listenerGate       *ListenerGate
+	voiceCommands      *VoiceCommandListener
+	privacyService     *PrivacyService
+	queuePanel         *QueuePanel
+	controlGuard       *ControlGuard
+	ownerHandler       *OwnerCommandHandler
+	readMore           *ReadMore
+	handoffManager     *HandoffManager
+	shutdownSequence   *ShutdownSequence
+	apiServer          *APIServer     // Nil unless tts.api_address is set
+	metricsServer      *MetricsServer // Nil unless tts.metrics_address is set
+	inputReader        *InputReader   // Nil unless tts.input_path is set
+	engineWarmer       *EngineWarmer  // Nil when tts.warmup is off
+	audioOutputs       *AudioOutputs
+	sessionRecorder    *SessionRecorder
+	localizer          *Localizer
 
 	// Discord session
 	session *discordgo.Session
@@ -32,12 +49,27 @@ type TTSSystem struct {
 	// Command integration
 	commandIntegration *TTSCommandIntegration
 
+	// Startup and shutdown order of the components
+	lifecycle *app.Container
+
 	// System state
 	isRunning bool
 }
 
 // NewTTSSystem creates a new TTS system with all components initialized
 func NewTTSSystem(session *discordgo.Session, cfg *config.Config, logger *log.Logger) (*TTSSystem, error) {
+	return NewTTSSystemWithManager(session, cfg, logger, nil)
+}
+
+// NewTTSSystemWithManager creates a new TTS system that synthesizes speech with the given
+// TTS manager. A nil manager uses Google Cloud TTS.
+func NewTTSSystemWithManager(session *discordgo.Session, cfg *config.Config, logger *log.Logger, ttsManager TTSManager) (*TTSSystem, error) {
+	return NewTTSSystemWithServices(session, cfg, logger, &Services{TTS: ttsManager})
+}
+
+// NewTTSSystemWithServices creates a new TTS system from the given services, filling in
+// the production implementation of every service that is not set
+func NewTTSSystemWithServices(session *discordgo.Session, cfg *config.Config, logger *log.Logger, services *Services) (*TTSSystem, error) {
 	if session == nil {
 		return nil, fmt.Errorf("discord session cannot be nil")
 	}
@@ -47,65 +79,388 @@ func NewTTSSystem(session *discordgo.Session, cfg *config.Config, logger *log.Lo
 	if logger == nil {
 		return nil, fmt.Errorf("logger cannot be nil")
 	}
+	if services == nil {
+		services = &Services{}
+	}
+	if err := services.fill(session, cfg, logger); err != nil {
+		return nil, err
+	}
 
-	// Initialize storage service
-	storageService, err := NewStorageService("./data")
+	// Initialize message monitor
+	messageMonitor := NewMessageMonitor(session, services.Channels, services.Users, services.Queue, logger)
+	messageMonitor.SetContentPolicy(services.Content)
+	messageMonitor.SetConfigService(services.Config)
+	messageMonitor.SetPermissionService(services.Permissions)
+	messageMonitor.SetFeatureFlags(services.Features)
+
+	// Join/leave announcements share the message queue through its low-priority lane
+	voiceAnnouncer := NewVoiceAnnouncer(services.Voice, services.Queue, services.Config, logger)
+	voiceAnnouncer.Register(session)
+
+	// Reaction summaries use the same lane, collected so single reactions never reach the queue
+	reactionSummarizer := NewReactionSummarizer(services.Voice, services.Channels, services.Users, services.Queue, services.Config, logger)
+	reactionSummarizer.Register(session)
+
+	// So do notes about messages that were skipped for their author or by moderation
+	skipNotes := NewSkipNotes(services.Queue, services.Config, logger)
+	messageMonitor.SetSkipNotes(skipNotes)
+	if tp, ok := services.Processor.(*ttsProcessor); ok {
+		tp.SetSkipNotes(skipNotes)
+	}
+
+	// Messages keep queueing while the bot is server muted and play once it is unmuted
+	mutePauser := NewMutePauser(services.Voice, logger)
+	mutePauser.Register(session)
+
+	// Spoken skip, pause and resume commands are recognized locally in guilds that turn them on
+	voiceCommands := NewVoiceCommandListener(services.Voice, services.Queue, services.Config, services.Permissions, services.TTS, logger)
+	voiceCommands.SetStatsService(services.Stats)
+	voiceCommands.SetMutePauser(mutePauser)
+
+	// Guilds with voice auto-pause hold messages while someone in the voice channel talks,
+	// using the speech the voice command listener receives
+	voiceActivity := NewVoiceActivity()
+	voiceCommands.SetFeatureFlags(services.Features)
+	voiceCommands.SetVoiceActivity(voiceActivity)
+	if tp, ok := services.Processor.(*ttsProcessor); ok {
+		tp.SetVoiceActivity(voiceActivity)
+	}
+	voiceCommands.Register()
+
+	// Create command integration (after TTS processor is created)
+	commandIntegration, err := NewTTSCommandIntegration(services, logger)
 	if err != nil {
-		return nil, fmt.Errorf("failed to initialize storage service: %w", err)
+		return nil, fmt.Errorf("failed to initialize command integration: %w", err)
+	}
+	commandIntegration.GetConfigHandler().SetQuotaService(services.Quota)
+	commandIntegration.GetPreviewHandler().SetQuotaService(services.Quota)
+	commandIntegration.GetConfigHandler().SetContentPolicy(services.Content)
+	commandIntegration.GetConfigHandler().SetVoiceAnnouncer(voiceAnnouncer)
+	commandIntegration.GetConfigHandler().SetReactionSummarizer(reactionSummarizer)
+	commandIntegration.GetConfigHandler().SetSkipNotes(skipNotes)
+	commandIntegration.GetConfigHandler().SetVoiceCommandListener(voiceCommands)
+	commandIntegration.GetConfigHandler().SetFeatureFlags(services.Features)
+	commandIntegration.GetControlHandler().SetMutePauser(mutePauser)
+
+	// Controllers can pause playback for a while, and guilds can set daily quiet hours
+	pauseScheduler := NewPauseScheduler(services.Voice, services.Config, logger)
+	pauseScheduler.SetMutePauser(mutePauser)
+	commandIntegration.GetControlHandler().SetPauseScheduler(pauseScheduler)
+	if tp, ok := services.Processor.(*ttsProcessor); ok {
+		tp.SetPauseScheduler(pauseScheduler)
 	}
 
-	// Initialize core services
-	messageQueue := NewMessageQueue()
-	userService := NewUserService(storageService)
-	sessionWrapper := NewDiscordSessionWrapper(session)
-	permissionService := NewPermissionService(sessionWrapper, storageService, logger)
+	// Guilds can hold messages until enough people are listening
+	listenerGate := NewListenerGate(services.Config, services.Voice, logger)
+	listenerGate.Register(session)
+	if tp, ok := services.Processor.(*ttsProcessor); ok {
+		tp.SetListenerGate(listenerGate)
 
-	configService := NewConfigService(storageService, cfg.TTS)
-	channelService := NewChannelService(storageService, sessionWrapper, permissionService)
+		// Guilds can leave pauses between messages, watching their channels' slow mode
+		tp.SetPacingPolicy(NewPacingPolicy(session, services.Config, logger))
+	}
+	if engineStatus, ok := services.TTS.(TTSEngineStatus); ok {
+		commandIntegration.GetJoinHandler().SetEngineStatus(engineStatus)
+	}
+	if breakers, ok := services.Processor.(TTSBreakerStatus); ok {
+		commandIntegration.GetDiagnoseHandler().SetBreakerStatus(breakers)
+	}
 
-	// Initialize TTS manager - using Google Cloud TTS
-	ttsManager, err := NewGoogleTTSManager(messageQueue, cfg.TTS.GoogleCloudCredentialsPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to initialize TTS manager: %w", err)
+	// Command responses use each guild's configured language
+	localizer := NewLocalizer(i18n.Default(), services.Config)
+	commandIntegration.SetLocalizer(localizer)
+	if tp, ok := services.Processor.(*ttsProcessor); ok {
+		tp.SetLocalizer(localizer) // Idle announcements are spoken in the guild's language
+	}
+	reactionSummarizer.SetLocalizer(localizer)
+	skipNotes.SetLocalizer(localizer)
+	messageMonitor.SetLocalizer(localizer)
+	voiceCommands.SetLocalizer(localizer)
+
+	// Moderators can follow who controls the bot in an optional audit channel
+	auditLog := NewAuditLog(services.Config, session, logger)
+	auditLog.SetLocalizer(localizer)
+	commandIntegration.SetAuditLog(auditLog)
+	if tp, ok := services.Processor.(*ttsProcessor); ok {
+		tp.SetAuditLog(auditLog)
+	}
+	if quotaService, ok := services.Quota.(*TTSQuotaServiceImpl); ok {
+		quotaService.SetAuditLog(auditLog)
 	}
-	logger.Println("Using Google Cloud TTS Manager")
 
-	// Initialize voice manager - this will be shared with the integration
-	voiceManager := NewVoiceManager(session)
-	logger.Printf("Created shared voice manager instance: %p", voiceManager)
+	// Users opted in by /darrot-join or as voice channel members can be told by DM, with a
+	// one-click opt-out
+	privacyService := NewPrivacyService(services.Users, services.Config, session, logger)
+	privacyService.SetLocalizer(localizer)
+	commandIntegration.GetJoinHandler().SetPrivacyService(privacyService)
+	commandIntegration.GetConfigHandler().SetPrivacyService(privacyService)
+	messageMonitor.SetPrivacyService(privacyService)
+
+	// Pairings can greet the voice channel with a welcome text or the latest pinned message
+	joinGreeter := NewJoinGreeter(services.Channels, services.Queue, services.Config, session, logger)
+	joinGreeter.SetLocalizer(localizer)
+	commandIntegration.GetJoinHandler().SetGreeter(joinGreeter)
+
+	// Pairings can mirror everything read aloud to a text channel for deaf members
+	if tp, ok := services.Processor.(*ttsProcessor); ok {
+		tp.SetTextMirror(NewTextMirror(services.Channels, services.Voice, session, logger))
+	}
 
-	// Initialize TTS processor
-	ttsProcessor := NewTTSProcessor(ttsManager, voiceManager, messageQueue, configService, userService)
+	// Controllers can post a live queue panel with playback buttons in the paired text channel
+	queuePanel := NewQueuePanel(services.Voice, services.Queue, services.Channels, services.Permissions, session, logger)
+	queuePanel.SetLocalizer(localizer)
+	queuePanel.SetStatsService(services.Stats)
+	queuePanel.SetMutePauser(mutePauser)
+	if nowPlaying, ok := services.Processor.(NowPlayingReporter); ok {
+		queuePanel.SetNowPlaying(nowPlaying)
+	}
+	commandIntegration.GetControlHandler().SetQueuePanel(queuePanel)
+
+	// Members are limited in how often they can move and steer the bot, and guilds can
+	// keep members outside the voice channel from steering it
+	controlGuard := NewControlGuard(services.Config, services.Voice, logger)
+	controlGuard.SetLocalizer(localizer)
+
+	// Guilds can read the first part of long messages and let listeners ask for the rest
+	readMore := NewReadMore(services.Queue, session, logger)
+	readMore.SetLocalizer(localizer)
+	readMore.SetListeners(messageMonitor.VoiceListeners)
+	messageMonitor.SetReadMore(readMore)
+
+	// Voice sessions active at shutdown are resumed on the next start
+	handoffManager := NewHandoffManager(services.SessionStorage, services.Voice, services.Channels, services.Processor, session, logger)
+	handoffManager.SetLocalizer(localizer)
+	handoffManager.SetMessageQueue(services.Queue)
+	handoffManager.SetContentPolicy(services.Content)
+
+	// On shutdown the bot finishes its sentence, says goodbye and tells the text channels
+	shutdownSequence := NewShutdownSequence(services.Voice, services.Channels, services.Processor, session, logger)
+	shutdownSequence.SetLocalizer(localizer)
+	shutdownSequence.SetDrainTimeout(time.Duration(cfg.TTS.DrainTimeout) * time.Second)
+	shutdownSequence.SetFarewell(cfg.TTS.ShutdownFarewell)
+
+	// Text channels are told when their voice server keeps failing and when the bot rejoins
+	outageNotifier := NewVoiceOutageNotifier(services.Channels, session, logger)
+	outageNotifier.SetLocalizer(localizer)
+	outageNotifier.Subscribe(services.Events)
+
+	// External systems can queue messages and overlays follow what is read over HTTP when
+	// the operator sets an address
+	var apiServer *APIServer
+	if cfg.TTS.APIAddress != "" {
+		apiServer = NewAPIServer(cfg.TTS.APIAddress, services.APITokens, services.Queue, services.Voice, logger)
+		apiServer.SetEventBus(services.Events)
+		commandIntegration.GetAPIHandler().SetAPIEnabled(true)
+	}
 
-	// Initialize message monitor
-	messageMonitor := NewMessageMonitor(session, channelService, userService, messageQueue, logger)
+	// Prometheus scrapes the metrics of every bot sharing the registry when the operator
+	// sets an address
+	var metricsServer *MetricsServer
+	if cfg.TTS.MetricsAddress != "" {
+		metricsServer = NewMetricsServer(cfg.TTS.MetricsAddress, services.Metrics, logger)
+	}
 
-	// Create command integration (after TTS processor is created)
-	commandIntegration, err := NewTTSCommandIntegration(session, storageService, voiceManager, ttsProcessor, logger)
-	if err != nil {
-		return nil, fmt.Errorf("failed to initialize command integration: %w", err)
+	// Scripts can pipe lines into one guild's voice channel through a named pipe or stdin
+	var inputReader *InputReader
+	if cfg.TTS.InputPath != "" {
+		inputReader = NewInputReader(cfg.TTS.InputPath, cfg.TTS.InputGuildID, cfg.TTS.InputChannelID, services.Queue, services.Voice, logger)
+	}
+
+	// Guilds can copy their speech to a recording, an Icecast stream or a local sound card
+	// when the operator sets those outputs up
+	audioOutputs := NewAudioOutputs(services.Config, logger)
+	audioOutputs.SetContentPolicy(services.Content)
+	if cfg.TTS.OutputDir != "" {
+		audioOutputs.Register(AudioOutputFile, NewFileSink(cfg.TTS.OutputDir, logger))
+	}
+	if cfg.TTS.IcecastURL != "" {
+		icecastSink, err := NewIcecastSink(cfg.TTS.IcecastURL, logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize Icecast output: %w", err)
+		}
+		audioOutputs.Register(AudioOutputIcecast, icecastSink)
+	}
+	if cfg.TTS.PulseSink != "" {
+		audioOutputs.Register(AudioOutputPulse, NewPulseAudioSink(cfg.TTS.PulseSink, logger))
+	}
+	if len(audioOutputs.Outputs()) > 1 {
+		audioOutputs.Subscribe(services.Events) // Sessions rotate only when there is something to record
+	}
+	commandIntegration.GetConfigHandler().SetAudioOutputs(audioOutputs)
+	if tp, ok := services.Processor.(*ttsProcessor); ok {
+		tp.SetAudioOutputs(audioOutputs)
+	}
+
+	// Guilds can have each voice session recorded and posted when the bot leaves
+	sessionRecorder := NewSessionRecorder(logger)
+	sessionRecorder.SetContentPolicy(services.Content)
+	sessionRecorder.Subscribe(services.Events)
+	audioOutputs.SetSessionRecorder(sessionRecorder)
+	commandIntegration.GetLeaveHandler().SetSessionRecorder(sessionRecorder)
+
+	// Guilds can mirror their required roles to Discord's command permissions when the
+	// operator gives a server manager's token to edit them with
+	if cfg.TTS.CommandPermissionsToken != "" {
+		commandIntegration.GetConfigHandler().SetCommandPermissionSync(NewCommandPermissionSync(session, cfg.TTS.CommandPermissionsToken, logger))
+	}
+
+	// The operator's owners can look after every guild the bot is in
+	var ownerHandler *OwnerCommandHandler
+	if ownerGate := NewOwnerGate(cfg.OwnerIDs()); ownerGate.Configured() {
+		ownerHandler = NewOwnerCommandHandler(ownerGate, session, services.Voice, services.Channels, services.Processor, services.TTS, services.Events, logger)
+		ownerHandler.SetLocalizer(localizer)
+		ownerHandler.SetAuditLog(auditLog)
+	}
+
+	// The first message after a start or join does not wait for the engine to connect
+	var engineWarmer *EngineWarmer
+	if cfg.TTS.Warmup {
+		defaults := TTSConfig{Voice: cfg.TTS.DefaultVoice, Speed: cfg.TTS.DefaultSpeed, Volume: cfg.TTS.DefaultVolume, Format: AudioFormatDCA}
+		engineWarmer = NewEngineWarmer(services.TTS, services.Config, defaults, services.Metrics, logger)
+		engineWarmer.Subscribe(services.Events)
 	}
 
 	system := &TTSSystem{
-		ttsManager:         ttsManager,
-		voiceManager:       voiceManager,
-		messageQueue:       messageQueue,
-		ttsProcessor:       ttsProcessor,
+		services:           services,
 		messageMonitor:     messageMonitor,
-		channelService:     channelService,
-		permissionService:  permissionService,
-		userService:        userService,
-		configService:      configService,
+		voiceAnnouncer:     voiceAnnouncer,
+		reactionSummarizer: reactionSummarizer,
+		skipNotes:          skipNotes,
+		mutePauser:         mutePauser,
+		pauseScheduler:     pauseScheduler,
+		listenerGate:       listenerGate,
+		voiceCommands:      voiceCommands,
+		privacyService:     privacyService,
+		queuePanel:         queuePanel,
+		controlGuard:       controlGuard,
+		ownerHandler:       ownerHandler,
+		readMore:           readMore,
+		handoffManager:     handoffManager,
+		shutdownSequence:   shutdownSequence,
+		apiServer:          apiServer,
+		metricsServer:      metricsServer,
+		inputReader:        inputReader,
+		engineWarmer:       engineWarmer,
+		audioOutputs:       audioOutputs,
+		sessionRecorder:    sessionRecorder,
+		localizer:          localizer,
 		session:            session,
 		config:             cfg,
 		logger:             logger,
 		commandIntegration: commandIntegration,
+		lifecycle:          app.NewContainer(logger),
 		isRunning:          false,
 	}
 
+	if err := system.registerLifecycle(); err != nil {
+		return nil, fmt.Errorf("failed to register TTS components: %w", err)
+	}
+
 	return system, nil
 }
 
+// registerLifecycle registers the components in startup order. Shutdown runs in reverse:
+// the HTTP API and event listeners stop before the processor, the bot finishes speaking
+// before the remaining queues are saved, and voice connections are closed last.
+func (sys *TTSSystem) registerLifecycle() error {
+	err := sys.lifecycle.Register(
+		&app.Hooks{
+			ComponentName: "voice connections",
+			OnInit: func() error {
+				// Pairings without a voice connection are left over from an unclean shutdown
+				if err := sys.cleanupStalePairings(); err != nil {
+					sys.logger.Printf("Warning: Failed to clean up stale pairings: %v", err)
+				}
+				return nil
+			},
+			OnStop: func() error {
+				sys.disconnectAll()
+				return nil
+			},
+		},
+		&app.Hooks{
+			ComponentName: "TTS credentials",
+			OnStart: func() error {
+				if credentialStatus, ok := sys.services.TTS.(TTSCredentialStatus); ok {
+					return credentialStatus.StartCredentialWatch()
+				}
+				return nil
+			},
+			OnStop: func() error {
+				if credentialStatus, ok := sys.services.TTS.(TTSCredentialStatus); ok {
+					credentialStatus.StopCredentialWatch()
+				}
+				return nil
+			},
+		},
+		// Recordings and streams are finished once the processor has played its last message
+		&app.Hooks{ComponentName: "session recorder", OnStop: app.StopFunc(sys.sessionRecorder.Close)},
+		&app.Hooks{ComponentName: "audio outputs", OnStop: sys.audioOutputs.Close},
+		&app.Hooks{
+			ComponentName: "TTS processor",
+			OnStart:       sys.services.Processor.Start,
+			OnStop: func() error {
+				if err := sys.services.Processor.Stop(); err != nil {
+					sys.logger.Printf("Error stopping TTS processor: %v", err)
+				}
+				return nil
+			},
+		},
+		&app.Hooks{
+			ComponentName: "voice handoff",
+			OnStart: func() error {
+				// Rejoin voice channels from before the last shutdown without blocking startup
+				go func() {
+					if _, err := sys.handoffManager.Resume(); err != nil {
+						sys.logger.Printf("Warning: Failed to resume voice sessions: %v", err)
+					}
+				}()
+				return nil
+			},
+			OnStop: func() error {
+				// Remember active voice sessions so they can be resumed after a restart
+				if err := sys.handoffManager.Save(); err != nil {
+					sys.logger.Printf("Warning: Failed to save voice sessions for handoff: %v", err)
+				}
+				return nil
+			},
+		},
+		&app.Hooks{ComponentName: "shutdown sequence", OnStop: app.StopFunc(sys.shutdownSequence.Run)},
+		&app.Hooks{ComponentName: "queue panel", OnStart: sys.queuePanel.Start, OnStop: app.StopFunc(sys.queuePanel.Stop)},
+		&app.Hooks{ComponentName: "read more", OnStop: app.StopFunc(sys.readMore.Stop)},
+		&app.Hooks{ComponentName: "pause scheduler", OnStart: sys.pauseScheduler.Start, OnStop: app.StopFunc(sys.pauseScheduler.Stop)},
+		&app.Hooks{ComponentName: "mute pauser", OnStop: app.StopFunc(sys.mutePauser.Stop)},
+		&app.Hooks{ComponentName: "listener gate", OnStop: app.StopFunc(sys.listenerGate.Stop)},
+		&app.Hooks{ComponentName: "voice commands", OnStop: app.StopFunc(sys.voiceCommands.Stop)},
+		&app.Hooks{ComponentName: "reaction summarizer", OnStop: app.StopFunc(sys.reactionSummarizer.Stop)},
+		&app.Hooks{ComponentName: "skip notes", OnStop: app.StopFunc(sys.skipNotes.Stop)},
+		&app.Hooks{ComponentName: "voice announcer", OnStop: app.StopFunc(sys.voiceAnnouncer.Stop)},
+		&app.Hooks{ComponentName: "message monitor", OnStop: app.StopFunc(sys.messageMonitor.Stop)},
+	)
+	if err != nil {
+		return err
+	}
+	if sys.apiServer != nil {
+		if err := sys.lifecycle.Register(&app.Hooks{ComponentName: "HTTP API", OnStart: sys.apiServer.Start, OnStop: sys.apiServer.Stop}); err != nil {
+			return err
+		}
+	}
+	if sys.metricsServer != nil {
+		if err := sys.lifecycle.Register(&app.Hooks{ComponentName: "metrics endpoint", OnStart: sys.metricsServer.Start, OnStop: sys.metricsServer.Stop}); err != nil {
+			return err
+		}
+	}
+	if sys.inputReader != nil {
+		if err := sys.lifecycle.Register(&app.Hooks{ComponentName: "input reader", OnStart: sys.inputReader.Start, OnStop: app.StopFunc(sys.inputReader.Stop)}); err != nil {
+			return err
+		}
+	}
+	if sys.engineWarmer != nil {
+		return sys.lifecycle.Register(&app.Hooks{ComponentName: "engine warm-up", OnStart: sys.engineWarmer.Start, OnStop: app.StopFunc(sys.engineWarmer.Stop)})
+	}
+	return nil
+}
+
 // Start initializes and starts all TTS system components
 func (sys *TTSSystem) Start() error {
 	if sys.isRunning {
@@ -114,18 +469,10 @@ func (sys *TTSSystem) Start() error {
 
 	sys.logger.Println("Starting TTS system...")
 
-	// Clean up any stale pairings from previous sessions
-	if err := sys.cleanupStalePairings(); err != nil {
-		sys.logger.Printf("Warning: Failed to clean up stale pairings: %v", err)
-	}
-
-	// Start TTS processor
-	if err := sys.ttsProcessor.Start(); err != nil {
-		return fmt.Errorf("failed to start TTS processor: %w", err)
+	if err := sys.lifecycle.Start(); err != nil {
+		return err
 	}
 
-	// Message monitor starts automatically when created
-
 	sys.isRunning = true
 	sys.logger.Println("TTS system started successfully")
 
@@ -140,20 +487,8 @@ func (sys *TTSSystem) Stop() error {
 
 	sys.logger.Println("Stopping TTS system...")
 
-	// Stop message monitor
-	sys.messageMonitor.Stop()
-
-	// Stop TTS processor
-	if err := sys.ttsProcessor.Stop(); err != nil {
-		sys.logger.Printf("Error stopping TTS processor: %v", err)
-	}
-
-	// Disconnect from all voice channels
-	activeConnections := sys.voiceManager.GetActiveConnections()
-	for _, guildID := range activeConnections {
-		if err := sys.voiceManager.LeaveChannel(guildID); err != nil {
-			sys.logger.Printf("Error disconnecting from guild %s: %v", guildID, err)
-		}
+	if err := sys.lifecycle.Stop(); err != nil {
+		sys.logger.Printf("Error stopping TTS system: %v", err)
 	}
 
 	sys.isRunning = false
@@ -162,34 +497,125 @@ func (sys *TTSSystem) Stop() error {
 	return nil
 }
 
+// disconnectAll leaves every voice channel the bot is connected to
+func (sys *TTSSystem) disconnectAll() {
+	for _, guildID := range sys.services.Voice.GetActiveConnections() {
+		if err := sys.services.Voice.LeaveChannel(guildID); err != nil {
+			sys.logger.Printf("Error disconnecting from guild %s: %v", guildID, err)
+		}
+	}
+}
+
 // GetCommandIntegration returns the command integration for registering slash commands
 func (sys *TTSSystem) GetCommandIntegration() *TTSCommandIntegration {
 	return sys.commandIntegration
 }
 
+// GetServices returns the services the TTS system is built from
+func (sys *TTSSystem) GetServices() *Services {
+	return sys.services
+}
+
 // GetVoiceManager returns the voice manager for direct access
 func (sys *TTSSystem) GetVoiceManager() VoiceManager {
-	return sys.voiceManager
+	return sys.services.Voice
 }
 
 // GetChannelService returns the channel service for direct access
 func (sys *TTSSystem) GetChannelService() ChannelService {
-	return sys.channelService
+	return sys.services.Channels
 }
 
 // GetUserService returns the user service for direct access
 func (sys *TTSSystem) GetUserService() UserService {
-	return sys.userService
+	return sys.services.Users
 }
 
 // GetConfigService returns the config service for direct access
 func (sys *TTSSystem) GetConfigService() ConfigService {
-	return sys.configService
+	return sys.services.Config
 }
 
 // GetTTSProcessor returns the TTS processor for direct access
 func (sys *TTSSystem) GetTTSProcessor() TTSProcessor {
-	return sys.ttsProcessor
+	return sys.services.Processor
+}
+
+// GetQuotaService returns the quota service for direct access
+func (sys *TTSSystem) GetQuotaService() TTSQuotaService {
+	return sys.services.Quota
+}
+
+// GetContentPolicy returns the content retention policy shared by TTS components
+func (sys *TTSSystem) GetContentPolicy() *ContentPolicy {
+	return sys.services.Content
+}
+
+// GetClipService returns the audio clip service for direct access
+func (sys *TTSSystem) GetClipService() AudioClipService {
+	return sys.services.Clips
+}
+
+// GetModerationService returns the moderation service for direct access
+func (sys *TTSSystem) GetModerationService() ModerationService {
+	return sys.services.Moderation
+}
+
+// GetStatsService returns the usage statistics service for direct access
+func (sys *TTSSystem) GetStatsService() StatsService {
+	return sys.services.Stats
+}
+
+// GetPrivacyService returns the service that sends opt-in privacy notices
+func (sys *TTSSystem) GetPrivacyService() *PrivacyService {
+	return sys.privacyService
+}
+
+// GetQueuePanel returns the live queue panel so its buttons can be routed
+func (sys *TTSSystem) GetQueuePanel() *QueuePanel {
+	return sys.queuePanel
+}
+
+// GetControlGuard returns the guard the bot runs join and control commands through
+func (sys *TTSSystem) GetControlGuard() *ControlGuard {
+	return sys.controlGuard
+}
+
+// GetOwnerHandler returns the handler of /darrot-owner, or nil when the operator named no
+// owners
+func (sys *TTSSystem) GetOwnerHandler() *OwnerCommandHandler {
+	return sys.ownerHandler
+}
+
+// GetReadMore returns the service offering the rest of long messages so its buttons can
+// be routed
+func (sys *TTSSystem) GetReadMore() *ReadMore {
+	return sys.readMore
+}
+
+// GetHandoffManager returns the manager that resumes voice sessions across restarts
+func (sys *TTSSystem) GetHandoffManager() *HandoffManager {
+	return sys.handoffManager
+}
+
+// GetLocalizer returns the localizer that translates responses into each guild's language
+func (sys *TTSSystem) GetLocalizer() *Localizer {
+	return sys.localizer
+}
+
+// CommandPrefix returns the prefix of text commands in a guild, or an empty string when
+// they are turned off
+func (sys *TTSSystem) CommandPrefix(guildID string) string {
+	config, err := sys.services.Config.GetGuildConfig(guildID)
+	if err != nil {
+		config = nil // Fall back to the default prefix
+	}
+	return CommandPrefixFor(config)
+}
+
+// GetMetrics returns the metrics registry shared by TTS components
+func (sys *TTSSystem) GetMetrics() *Metrics {
+	return sys.services.Metrics
 }
 
 // IsRunning returns whether the TTS system is currently running
@@ -202,7 +628,7 @@ func (sys *TTSSystem) cleanupStalePairings() error {
 	sys.logger.Println("Cleaning up stale channel pairings from previous sessions...")
 
 	// Get all active voice connections
-	activeConnections := sys.voiceManager.GetActiveConnections()
+	activeConnections := sys.services.Voice.GetActiveConnections()
 	activeGuilds := make(map[string]bool)
 	for _, guildID := range activeConnections {
 		activeGuilds[guildID] = true
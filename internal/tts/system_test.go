@@ -0,0 +1,120 @@
+package tts
+
+import (
+	"io"
+	"log"
+	"testing"
+
+	"darrot/internal/config"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestSystem assembles the full TTS system around mock speech synthesis and voice
+// connections, with storage in a temporary directory
+func newTestSystem(t *testing.T) (*TTSSystem, *Services) {
+	t.Helper()
+
+	storage, err := NewStorageService(t.TempDir())
+	require.NoError(t, err)
+
+	services := &Services{
+		Storage: storage,
+		TTS:     &mockTTSManager{},
+		Voice:   newMockVoiceManager(),
+	}
+	session := &discordgo.Session{State: discordgo.NewState()}
+	cfg := &config.Config{TTS: config.TTSConfig{DefaultVoice: "en-US-Standard-A", DefaultSpeed: 1.0, DefaultVolume: 1.0, MaxQueueSize: 10}}
+
+	system, err := NewTTSSystemWithServices(session, cfg, log.New(io.Discard, "", 0), services)
+	require.NoError(t, err)
+	return system, services
+}
+
+func TestNewTTSSystemWithServices_FillsMissingServices(t *testing.T) {
+	system, services := newTestSystem(t)
+
+	assert.NotNil(t, services.Queue)
+	assert.NotNil(t, services.Users)
+	assert.NotNil(t, services.Permissions)
+	assert.NotNil(t, services.Config)
+	assert.NotNil(t, services.Channels)
+	assert.NotNil(t, services.Metrics)
+	assert.NotNil(t, services.Quota)
+	assert.NotNil(t, services.Content)
+	assert.NotNil(t, services.Clips)
+	assert.NotNil(t, services.Moderation)
+	assert.NotNil(t, services.Stats)
+	assert.NotNil(t, services.Processor)
+
+	// Services that were set are used as given
+	assert.Same(t, services.Voice, system.GetVoiceManager())
+	assert.Same(t, services.TTS, system.services.TTS)
+}
+
+func TestNewTTSSystemWithServices_CommandHandlersShareServices(t *testing.T) {
+	system, services := newTestSystem(t)
+
+	join := system.GetCommandIntegration().GetJoinHandler()
+	assert.Same(t, services.Voice, join.voiceManager)
+	assert.Same(t, services.Channels, join.channelService)
+	assert.Same(t, services.Permissions, join.permissionService)
+	assert.Same(t, services.Users, join.userService)
+	assert.Same(t, services.Processor, join.ttsProcessor)
+}
+
+func TestNewTTSSystemWithServices_RequiresDependencies(t *testing.T) {
+	session := &discordgo.Session{}
+	cfg := &config.Config{}
+	logger := log.New(io.Discard, "", 0)
+
+	_, err := NewTTSSystemWithServices(nil, cfg, logger, &Services{})
+	assert.Error(t, err)
+	_, err = NewTTSSystemWithServices(session, nil, logger, &Services{})
+	assert.Error(t, err)
+	_, err = NewTTSSystemWithServices(session, cfg, nil, &Services{})
+	assert.Error(t, err)
+}
+
+func TestTTSSystem_LifecycleOrder(t *testing.T) {
+	system, _ := newTestSystem(t)
+
+	assert.Equal(t, []string{
+		"voice connections",
+		"TTS credentials",
+		"session recorder",
+		"audio outputs",
+		"TTS processor",
+		"voice handoff",
+		"shutdown sequence",
+		"queue panel",
+		"read more",
+		"pause scheduler",
+		"mute pauser",
+		"listener gate",
+		"voice commands",
+		"reaction summarizer",
+		"skip notes",
+		"voice announcer",
+		"message monitor",
+	}, system.lifecycle.Components())
+}
+
+func TestTTSSystem_StartStop(t *testing.T) {
+	system, services := newTestSystem(t)
+	voice := services.Voice.(*mockVoiceManager)
+
+	require.NoError(t, system.Start())
+	assert.True(t, system.IsRunning())
+	assert.Error(t, system.Start(), "starting twice should fail")
+
+	_, err := voice.JoinChannel("guild1", "voice1")
+	require.NoError(t, err)
+
+	require.NoError(t, system.Stop())
+	assert.False(t, system.IsRunning())
+	assert.Empty(t, voice.GetActiveConnections(), "voice connections should be closed on stop")
+	assert.Error(t, system.Stop(), "stopping twice should fail")
+}
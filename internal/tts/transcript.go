@@ -0,0 +1,326 @@
+package tts
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"darrot/internal/events"
+)
+
+// Transcript retention limits. Sessions past either limit are dropped the next time the
+// guild's transcripts change, and a session stops recording once it is full.
+const (
+	MaxTranscriptSessions = 10                 // Most recent sessions kept per guild
+	TranscriptRetention   = 7 * 24 * time.Hour // How long a finished session is kept
+	MaxTranscriptEntries  = 5000               // Utterances recorded per session
+)
+
+// TranscriptServiceImpl implements TranscriptService with transcripts persisted through
+// StorageService. Nothing is recorded unless the guild turned transcripts on.
+type TranscriptServiceImpl struct {
+	storage       *StorageService
+	configService ConfigService
+	contentPolicy *ContentPolicy
+	transcripts   map[string]*GuildTranscripts
+	now           func() time.Time
+	mu            sync.Mutex
+}
+
+// NewTranscriptService creates a transcript service
+func NewTranscriptService(storage *StorageService, configService ConfigService) *TranscriptServiceImpl {
+	return &TranscriptServiceImpl{
+		storage:       storage,
+		configService: configService,
+		transcripts:   make(map[string]*GuildTranscripts),
+		now:           time.Now,
+	}
+}
+
+// SetContentPolicy sets the policy that keeps spoken text out of transcripts in guilds
+// that retain metadata only
+func (s *TranscriptServiceImpl) SetContentPolicy(policy *ContentPolicy) {
+	s.contentPolicy = policy
+}
+
+// StartSession starts a new session for the guild, ending one left open by a restart
+func (s *TranscriptServiceImpl) StartSession(guildID string) error {
+	if !s.enabled(guildID) {
+		return nil
+	}
+
+	return s.update(guildID, func(transcripts *GuildTranscripts) bool {
+		s.endOpenSession(transcripts)
+		transcripts.Sessions = append(transcripts.Sessions, TranscriptSession{StartedAt: s.now()})
+		return true
+	})
+}
+
+// EndSession ends the guild's ongoing session. Sessions in which nothing was spoken are
+// not kept.
+func (s *TranscriptServiceImpl) EndSession(guildID string) error {
+	if guildID == "" {
+		return fmt.Errorf("guild ID is required")
+	}
+
+	return s.update(guildID, s.endOpenSession)
+}
+
+// Subscribe records every chat message that played to the end, including messages
+// queued through the HTTP API. Every part of a split message is its own utterance;
+// announcements and voice previews are not recorded.
+func (s *TranscriptServiceImpl) Subscribe(bus *events.Bus) (unsubscribe func()) {
+	return events.Subscribe(bus, func(e events.UtteranceFinished) {
+		if !e.Completed || e.Announcement || e.Preview {
+			return
+		}
+
+		entry := TranscriptEntry{
+			UserID:     e.UserID,
+			Username:   e.Username,
+			Text:       e.Text,
+			SpokenAt:   e.FinishedAt,
+			DurationMs: e.Played.Milliseconds(),
+		}
+		if err := s.RecordUtterance(e.GuildID, entry); err != nil {
+			log.Printf("Failed to record transcript for guild %s: %v", e.GuildID, err)
+		}
+	})
+}
+
+// RecordUtterance adds something spoken to the guild's ongoing session, starting one when
+// transcripts were turned on mid-session
+func (s *TranscriptServiceImpl) RecordUtterance(guildID string, entry TranscriptEntry) error {
+	if !s.enabled(guildID) {
+		return nil
+	}
+
+	if s.contentPolicy.ContentFree(guildID) {
+		entry.Text = ""
+	}
+	if entry.SpokenAt.IsZero() {
+		entry.SpokenAt = s.now()
+	}
+
+	return s.update(guildID, func(transcripts *GuildTranscripts) bool {
+		session := openSession(transcripts)
+		if session == nil {
+			transcripts.Sessions = append(transcripts.Sessions, TranscriptSession{StartedAt: entry.SpokenAt})
+			session = &transcripts.Sessions[len(transcripts.Sessions)-1]
+		}
+
+		if len(session.Entries) >= MaxTranscriptEntries {
+			changed := !session.Truncated
+			session.Truncated = true
+			return changed
+		}
+		session.Entries = append(session.Entries, entry)
+		return true
+	})
+}
+
+// LatestSession returns a copy of the guild's most recent session, ongoing or finished,
+// or nil when none is kept
+func (s *TranscriptServiceImpl) LatestSession(guildID string) (*TranscriptSession, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	transcripts, err := s.currentTranscripts(guildID)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := s.retained(transcripts.Sessions)
+	if len(sessions) == 0 {
+		return nil, nil
+	}
+
+	session := sessions[len(sessions)-1]
+	session.Entries = slices.Clone(session.Entries)
+	return &session, nil
+}
+
+// ClearTranscripts deletes every transcript kept for the guild
+func (s *TranscriptServiceImpl) ClearTranscripts(guildID string) error {
+	if guildID == "" {
+		return fmt.Errorf("guild ID is required")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.transcripts, guildID)
+	if err := s.storage.RemoveGuildTranscripts(guildID); err != nil {
+		return fmt.Errorf("failed to remove guild transcripts: %w", err)
+	}
+	return nil
+}
+
+// ExportUserData adds what was read aloud from a user in the guild's kept sessions to
+// export
+func (s *TranscriptServiceImpl) ExportUserData(userID, guildID string, export *UserDataExport) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	transcripts, err := s.currentTranscripts(guildID)
+	if err != nil {
+		return err
+	}
+
+	for _, session := range s.retained(transcripts.Sessions) {
+		for _, entry := range session.Entries {
+			if entry.UserID == userID {
+				export.Transcripts = append(export.Transcripts, entry)
+			}
+		}
+	}
+	return nil
+}
+
+// DeleteUserData removes what was read aloud from a user from the guild's transcripts
+func (s *TranscriptServiceImpl) DeleteUserData(userID, guildID string) error {
+	return s.update(guildID, func(transcripts *GuildTranscripts) bool {
+		changed := false
+		for idx := range transcripts.Sessions {
+			session := &transcripts.Sessions[idx]
+			kept := slices.DeleteFunc(session.Entries, func(entry TranscriptEntry) bool {
+				return entry.UserID == userID
+			})
+			changed = changed || len(kept) != len(session.Entries)
+			session.Entries = kept
+		}
+		return changed
+	})
+}
+
+// enabled reports whether the guild turned transcripts on
+func (s *TranscriptServiceImpl) enabled(guildID string) bool {
+	if guildID == "" || s.configService == nil {
+		return false
+	}
+
+	config, err := s.configService.GetGuildConfig(guildID)
+	return err == nil && config != nil && config.TranscriptsEnabled
+}
+
+// endOpenSession ends the ongoing session, dropping it when nothing was spoken. It
+// reports whether the transcripts changed.
+func (s *TranscriptServiceImpl) endOpenSession(transcripts *GuildTranscripts) bool {
+	session := openSession(transcripts)
+	if session == nil {
+		return false
+	}
+
+	if len(session.Entries) == 0 {
+		transcripts.Sessions = transcripts.Sessions[:len(transcripts.Sessions)-1]
+		return true
+	}
+	session.EndedAt = s.now()
+	return true
+}
+
+// update applies change to the guild's transcripts and saves them, applying the
+// retention limits. change reports whether anything changed.
+func (s *TranscriptServiceImpl) update(guildID string, change func(transcripts *GuildTranscripts) bool) error {
+	if guildID == "" {
+		return fmt.Errorf("guild ID is required")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	transcripts, err := s.currentTranscripts(guildID)
+	if err != nil {
+		return err
+	}
+
+	if !change(transcripts) {
+		return nil
+	}
+	transcripts.Sessions = s.retained(transcripts.Sessions)
+
+	if err := s.storage.SaveGuildTranscripts(*transcripts); err != nil {
+		return fmt.Errorf("failed to save guild transcripts: %w", err)
+	}
+	return nil
+}
+
+// retained returns the sessions within the retention limits
+func (s *TranscriptServiceImpl) retained(sessions []TranscriptSession) []TranscriptSession {
+	cutoff := s.now().Add(-TranscriptRetention)
+	kept := sessions[:0:0]
+	for _, session := range sessions {
+		if session.EndedAt.IsZero() || session.EndedAt.After(cutoff) {
+			kept = append(kept, session)
+		}
+	}
+
+	if len(kept) > MaxTranscriptSessions {
+		kept = kept[len(kept)-MaxTranscriptSessions:]
+	}
+	return kept
+}
+
+// currentTranscripts returns the guild's transcripts, loading them from storage on first
+// use (caller must hold the lock)
+func (s *TranscriptServiceImpl) currentTranscripts(guildID string) (*GuildTranscripts, error) {
+	if transcripts, exists := s.transcripts[guildID]; exists {
+		return transcripts, nil
+	}
+
+	transcripts, err := s.storage.LoadGuildTranscripts(guildID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load guild transcripts: %w", err)
+	}
+	s.transcripts[guildID] = transcripts
+	return transcripts, nil
+}
+
+// openSession returns the guild's ongoing session, or nil when the last one ended
+func openSession(transcripts *GuildTranscripts) *TranscriptSession {
+	if len(transcripts.Sessions) == 0 {
+		return nil
+	}
+
+	session := &transcripts.Sessions[len(transcripts.Sessions)-1]
+	if !session.EndedAt.IsZero() {
+		return nil
+	}
+	return session
+}
+
+// Duration returns how long the utterance played for
+func (e TranscriptEntry) Duration() time.Duration {
+	return time.Duration(e.DurationMs) * time.Millisecond
+}
+
+// Text renders the session as a plain text transcript with one line per utterance.
+// Times are in UTC.
+func (t *TranscriptSession) Text() string {
+	const timeLayout = "2006-01-02 15:04:05 MST"
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Session started %s\n", t.StartedAt.UTC().Format(timeLayout))
+
+	for _, entry := range t.Entries {
+		text := entry.Text
+		if text == "" {
+			text = "[content not retained]"
+		}
+		fmt.Fprintf(&b, "[%s] %s (%.1fs): %s\n",
+			entry.SpokenAt.UTC().Format("15:04:05"), entry.Username, entry.Duration().Seconds(), text)
+	}
+
+	if t.Truncated {
+		fmt.Fprintf(&b, "[transcript stopped after %d utterances]\n", len(t.Entries))
+	}
+	if t.EndedAt.IsZero() {
+		b.WriteString("Session ongoing\n")
+	} else {
+		fmt.Fprintf(&b, "Session ended %s\n", t.EndedAt.UTC().Format(timeLayout))
+	}
+	return b.String()
+}
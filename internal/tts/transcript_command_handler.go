@@ -0,0 +1,261 @@
+package tts
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"darrot/internal/commands/options"
+	"darrot/internal/commands/textcmd"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Transcript export formats
+const (
+	TranscriptFormatText = "text"
+	TranscriptFormatJSON = "json"
+)
+
+// TranscriptCommandHandler handles administrator export of session transcripts and
+// turning transcripts on or off
+type TranscriptCommandHandler struct {
+	transcriptService TranscriptService
+	configService     ConfigService
+	permissionService PermissionService
+	auditLog          *AuditLog
+	localizer         *Localizer
+	logger            *log.Logger
+}
+
+// NewTranscriptCommandHandler creates a new transcript command handler
+func NewTranscriptCommandHandler(
+	transcriptService TranscriptService,
+	configService ConfigService,
+	permissionService PermissionService,
+	logger *log.Logger,
+) *TranscriptCommandHandler {
+	return &TranscriptCommandHandler{
+		transcriptService: transcriptService,
+		configService:     configService,
+		permissionService: permissionService,
+		logger:            logger,
+	}
+}
+
+// SetLocalizer sets the localizer used to translate responses
+func (h *TranscriptCommandHandler) SetLocalizer(localizer *Localizer) {
+	h.localizer = localizer
+}
+
+// SetAuditLog sets the audit log that records transcripts being turned on or off
+func (h *TranscriptCommandHandler) SetAuditLog(auditLog *AuditLog) {
+	h.auditLog = auditLog
+}
+
+// Definition returns the Discord slash command definition for the transcript command
+func (h *TranscriptCommandHandler) Definition() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:                     "darrot-transcript",
+		Description:              "Export what was read aloud in voice sessions (Administrator only)",
+		DefaultMemberPermissions: adminCommandPermissions(),
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "export",
+				Description: "Download the transcript of the latest voice session",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "format",
+						Description: "File format (default: text)",
+						Required:    false,
+						Choices: []*discordgo.ApplicationCommandOptionChoice{
+							{Name: "text", Value: TranscriptFormatText},
+							{Name: "json", Value: TranscriptFormatJSON},
+						},
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "settings",
+				Description: "Turn session transcripts on or off",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionBoolean,
+						Name:        "enabled",
+						Description: "Record what is read aloud; turning it off deletes kept transcripts (omit to show)",
+						Required:    false,
+					},
+				},
+			},
+		},
+	}
+}
+
+// Handle processes the transcript command interaction
+func (h *TranscriptCommandHandler) Handle(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	// Validate guild context
+	if i.GuildID == "" {
+		return h.respondError(s, i, h.localizer.T(i.GuildID, "common.guild_only"))
+	}
+
+	userID := i.Member.User.ID
+	guildID := i.GuildID
+
+	// Validate administrator permissions
+	if err := h.ValidatePermissions(userID, guildID); err != nil {
+		return h.respondError(s, i, h.localizer.T(guildID, "common.permission_denied", err))
+	}
+
+	subcommand, opts, ok := options.FromInteraction(i).Subcommand()
+	if !ok {
+		return h.respondError(s, i, h.localizer.T(guildID, "common.no_subcommand"))
+	}
+
+	switch subcommand {
+	case "export":
+		return h.handleExport(s, i, guildID, opts)
+	case "settings":
+		return h.handleSettings(s, i, guildID, opts)
+	default:
+		return h.respondError(s, i, h.localizer.T(guildID, "common.invalid_subcommand"))
+	}
+}
+
+// handleExport sends the latest session's transcript as an attachment
+func (h *TranscriptCommandHandler) handleExport(s *discordgo.Session, i *discordgo.InteractionCreate, guildID string, opts options.Set) error {
+	format, _ := opts.String("format")
+	if format == "" {
+		format = TranscriptFormatText
+	}
+
+	session, err := h.transcriptService.LatestSession(guildID)
+	if err != nil {
+		h.logger.Printf("Error loading transcript for guild %s: %v", guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "transcript.export_failed"))
+	}
+	if session == nil {
+		return h.respondError(s, i, h.localizer.T(guildID, "transcript.none"))
+	}
+
+	file, err := transcriptFile(guildID, session, format)
+	if err != nil {
+		h.logger.Printf("Error encoding transcript for guild %s: %v", guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "transcript.export_failed"))
+	}
+
+	return textcmd.Respond(s, i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: h.localizer.T(guildID, "transcript.ready", len(session.Entries)),
+			Files:   []*discordgo.File{file},
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
+
+// transcriptFile encodes a session as a text or JSON attachment named after the guild
+// and the session's start
+func transcriptFile(guildID string, session *TranscriptSession, format string) (*discordgo.File, error) {
+	name := fmt.Sprintf("darrot-transcript-%s-%s", guildID, session.StartedAt.UTC().Format("20060102-150405"))
+
+	if format == TranscriptFormatJSON {
+		data, err := json.MarshalIndent(session, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return &discordgo.File{Name: name + ".json", ContentType: "application/json", Reader: bytes.NewReader(data)}, nil
+	}
+
+	return &discordgo.File{Name: name + ".txt", ContentType: "text/plain", Reader: bytes.NewReader([]byte(session.Text()))}, nil
+}
+
+// handleSettings turns transcripts on or off. Turning them off deletes every kept
+// transcript. With no option it shows whether they are on.
+func (h *TranscriptCommandHandler) handleSettings(s *discordgo.Session, i *discordgo.InteractionCreate, guildID string, opts options.Set) error {
+	config, err := h.configService.GetGuildConfig(guildID)
+	if err != nil {
+		h.logger.Printf("Error getting guild config for guild %s: %v", guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "transcript.settings_get_failed"))
+	}
+	if config == nil {
+		defaultConfig := DefaultGuildTTSConfig(guildID)
+		config = &defaultConfig
+	}
+
+	enabled, set := opts.Bool("enabled")
+	if !set {
+		return h.respondSuccess(s, i, h.describeSettings(guildID, config.TranscriptsEnabled))
+	}
+
+	updated := *config
+	updated.TranscriptsEnabled = enabled
+	if err := h.configService.SetGuildConfig(guildID, &updated); err != nil {
+		h.logger.Printf("Error setting transcripts for guild %s: %v", guildID, err)
+		return h.respondError(s, i, h.localizer.T(guildID, "transcript.settings_update_failed", err))
+	}
+
+	if !enabled {
+		if err := h.transcriptService.ClearTranscripts(guildID); err != nil {
+			h.logger.Printf("Error deleting transcripts for guild %s: %v", guildID, err)
+			return h.respondError(s, i, h.localizer.T(guildID, "transcript.clear_failed"))
+		}
+	}
+
+	message := h.localizer.T(guildID, "transcript.settings_updated", h.describeSettings(guildID, enabled))
+	err = h.respondSuccess(s, i, message)
+	h.auditLog.RecordConfigChange(guildID, i.Member.User.ID, commandLine(i), message)
+	return err
+}
+
+// describeSettings returns a user-facing description of whether transcripts are on
+func (h *TranscriptCommandHandler) describeSettings(guildID string, enabled bool) string {
+	if enabled {
+		return h.localizer.T(guildID, "transcript.enabled", MaxTranscriptSessions, int(TranscriptRetention.Hours()/24))
+	}
+	return h.localizer.T(guildID, "transcript.disabled")
+}
+
+// ValidatePermissions validates that the user has administrator permissions
+func (h *TranscriptCommandHandler) ValidatePermissions(userID, guildID string) error {
+	canControl, err := h.permissionService.CanControlBot(userID, guildID)
+	if err != nil {
+		return fmt.Errorf("failed to check permissions: %w", err)
+	}
+
+	if !canControl {
+		return withKind(ErrPermission, fmt.Errorf("you must have administrator permissions to manage transcripts"))
+	}
+
+	return nil
+}
+
+// ValidateChannelAccess is not needed for transcript commands but required by interface
+func (h *TranscriptCommandHandler) ValidateChannelAccess(userID, channelID string) error {
+	return nil // Not applicable for transcript commands
+}
+
+// Helper methods for response handling
+
+func (h *TranscriptCommandHandler) respondSuccess(s *discordgo.Session, i *discordgo.InteractionCreate, message string) error {
+	return textcmd.Respond(s, i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: message,
+			Flags:   discordgo.MessageFlagsEphemeral, // Transcript settings are only shown to administrators
+		},
+	})
+}
+
+func (h *TranscriptCommandHandler) respondError(s *discordgo.Session, i *discordgo.InteractionCreate, message string) error {
+	return textcmd.Respond(s, i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: "❌ " + message,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
@@ -0,0 +1,91 @@
+package tts
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createTestTranscriptHandler(t *testing.T) (*TranscriptCommandHandler, *MockPermissionService) {
+	mockPermissionService := &MockPermissionService{}
+	logger := log.New(os.Stdout, "[TEST] ", log.LstdFlags)
+
+	transcriptService, configService, _ := createTestTranscriptService(t)
+	return NewTranscriptCommandHandler(transcriptService, configService, mockPermissionService, logger), mockPermissionService
+}
+
+func TestTranscriptCommandHandler_Definition(t *testing.T) {
+	handler, _ := createTestTranscriptHandler(t)
+
+	definition := handler.Definition()
+
+	assert.Equal(t, "darrot-transcript", definition.Name)
+	assert.Contains(t, definition.Description, "Administrator only")
+	require.Len(t, definition.Options, 2) // export, settings subcommands
+
+	export := definition.Options[0]
+	assert.Equal(t, "export", export.Name)
+	require.Len(t, export.Options, 1)
+	assert.Len(t, export.Options[0].Choices, 2)
+	assert.False(t, export.Options[0].Required, "the format defaults to text")
+
+	settings := definition.Options[1]
+	assert.Equal(t, "settings", settings.Name)
+	require.Len(t, settings.Options, 1)
+	assert.Equal(t, discordgo.ApplicationCommandOptionBoolean, settings.Options[0].Type)
+}
+
+func TestTranscriptCommandHandler_ValidatePermissions(t *testing.T) {
+	handler, mockPermissionService := createTestTranscriptHandler(t)
+
+	mockPermissionService.On("CanControlBot", "admin", "guild123").Return(true, nil)
+	mockPermissionService.On("CanControlBot", "user", "guild123").Return(false, nil)
+	mockPermissionService.On("CanControlBot", "broken", "guild123").Return(false, errors.New("permission check failed"))
+
+	assert.NoError(t, handler.ValidatePermissions("admin", "guild123"))
+	assert.ErrorContains(t, handler.ValidatePermissions("user", "guild123"), "administrator permissions")
+	assert.ErrorContains(t, handler.ValidatePermissions("broken", "guild123"), "failed to check permissions")
+
+	mockPermissionService.AssertExpectations(t)
+}
+
+func TestTranscriptFile(t *testing.T) {
+	startedAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	session := &TranscriptSession{
+		StartedAt: startedAt,
+		Entries:   []TranscriptEntry{{UserID: "user1", Username: "alice", Text: "hello", SpokenAt: startedAt, DurationMs: 1000}},
+	}
+
+	file, err := transcriptFile("guild1", session, TranscriptFormatText)
+	require.NoError(t, err)
+	assert.Equal(t, "darrot-transcript-guild1-20261001-120000.txt", file.Name)
+	assert.Equal(t, "text/plain", file.ContentType)
+	data, err := io.ReadAll(file.Reader)
+	require.NoError(t, err)
+	assert.Equal(t, session.Text(), string(data))
+
+	file, err = transcriptFile("guild1", session, TranscriptFormatJSON)
+	require.NoError(t, err)
+	assert.Equal(t, "darrot-transcript-guild1-20261001-120000.json", file.Name)
+	assert.Equal(t, "application/json", file.ContentType)
+
+	var decoded TranscriptSession
+	require.NoError(t, json.NewDecoder(file.Reader).Decode(&decoded))
+	require.Len(t, decoded.Entries, 1)
+	assert.Equal(t, "hello", decoded.Entries[0].Text)
+}
+
+func TestTranscriptCommandHandler_DescribeSettings(t *testing.T) {
+	handler, _ := createTestTranscriptHandler(t)
+
+	assert.Contains(t, handler.describeSettings("guild1", true), "The last 10 sessions are kept for up to 7 days")
+	assert.Equal(t, "📝 Transcripts are off and nothing is recorded.", handler.describeSettings("guild1", false))
+}
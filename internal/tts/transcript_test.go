@@ -0,0 +1,233 @@
+package tts
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"darrot/internal/config"
+	"darrot/internal/events"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createTestTranscriptService(t *testing.T) (*TranscriptServiceImpl, ConfigService, *StorageService) {
+	storage, err := NewStorageService(t.TempDir())
+	require.NoError(t, err)
+
+	configService := NewConfigService(storage, config.TTSConfig{DefaultVoice: DefaultVoice, DefaultSpeed: 1.0, DefaultVolume: 1.0, MaxQueueSize: 10})
+	transcriptService := NewTranscriptService(storage, configService)
+	transcriptService.SetContentPolicy(NewContentPolicy(configService))
+	return transcriptService, configService, storage
+}
+
+func enableTranscripts(t *testing.T, configService ConfigService, guildID string) {
+	guildConfig, err := configService.GetGuildConfig(guildID)
+	require.NoError(t, err)
+	guildConfig.TranscriptsEnabled = true
+	require.NoError(t, configService.SetGuildConfig(guildID, guildConfig))
+}
+
+func TestTranscriptService_DisabledByDefault(t *testing.T) {
+	transcriptService, _, storage := createTestTranscriptService(t)
+
+	require.NoError(t, transcriptService.StartSession("guild1"))
+	require.NoError(t, transcriptService.RecordUtterance("guild1", TranscriptEntry{UserID: "user1", Text: "hi"}))
+	require.NoError(t, transcriptService.EndSession("guild1"))
+
+	session, err := transcriptService.LatestSession("guild1")
+	require.NoError(t, err)
+	assert.Nil(t, session)
+
+	stored, err := storage.LoadGuildTranscripts("guild1")
+	require.NoError(t, err)
+	assert.Empty(t, stored.Sessions)
+}
+
+func TestTranscriptService_RecordsSession(t *testing.T) {
+	transcriptService, configService, storage := createTestTranscriptService(t)
+	enableTranscripts(t, configService, "guild1")
+
+	startedAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	transcriptService.now = func() time.Time { return startedAt }
+
+	require.NoError(t, transcriptService.StartSession("guild1"))
+	require.NoError(t, transcriptService.RecordUtterance("guild1", TranscriptEntry{
+		UserID: "user1", Username: "alice", Text: "alice says: hello", SpokenAt: startedAt.Add(5 * time.Second), DurationMs: 1500,
+	}))
+
+	// The ongoing session can be exported before the bot leaves
+	session, err := transcriptService.LatestSession("guild1")
+	require.NoError(t, err)
+	require.NotNil(t, session)
+	assert.True(t, session.EndedAt.IsZero())
+	require.Len(t, session.Entries, 1)
+	assert.Equal(t, 1500*time.Millisecond, session.Entries[0].Duration())
+
+	transcriptService.now = func() time.Time { return startedAt.Add(time.Hour) }
+	require.NoError(t, transcriptService.EndSession("guild1"))
+
+	session, err = transcriptService.LatestSession("guild1")
+	require.NoError(t, err)
+	require.NotNil(t, session)
+	assert.True(t, startedAt.Equal(session.StartedAt))
+	assert.True(t, startedAt.Add(time.Hour).Equal(session.EndedAt))
+
+	// Transcripts survive a restart
+	stored, err := storage.LoadGuildTranscripts("guild1")
+	require.NoError(t, err)
+	require.Len(t, stored.Sessions, 1)
+	assert.Equal(t, "alice says: hello", stored.Sessions[0].Entries[0].Text)
+}
+
+func TestTranscriptService_EmptySessionsAreDropped(t *testing.T) {
+	transcriptService, configService, _ := createTestTranscriptService(t)
+	enableTranscripts(t, configService, "guild1")
+
+	require.NoError(t, transcriptService.StartSession("guild1"))
+	require.NoError(t, transcriptService.EndSession("guild1"))
+
+	session, err := transcriptService.LatestSession("guild1")
+	require.NoError(t, err)
+	assert.Nil(t, session)
+}
+
+func TestTranscriptService_StartsSessionWhenEnabledMidSession(t *testing.T) {
+	transcriptService, configService, _ := createTestTranscriptService(t)
+
+	require.NoError(t, transcriptService.StartSession("guild1")) // Not recorded yet
+	enableTranscripts(t, configService, "guild1")
+	require.NoError(t, transcriptService.RecordUtterance("guild1", TranscriptEntry{UserID: "user1", Text: "hi"}))
+
+	session, err := transcriptService.LatestSession("guild1")
+	require.NoError(t, err)
+	require.NotNil(t, session)
+	assert.Len(t, session.Entries, 1)
+	assert.False(t, session.StartedAt.IsZero())
+}
+
+func TestTranscriptService_MetadataOnlyDropsText(t *testing.T) {
+	transcriptService, configService, storage := createTestTranscriptService(t)
+	enableTranscripts(t, configService, "guild1")
+	require.NoError(t, NewContentPolicy(configService).SetMode("guild1", ContentRetentionMetadata))
+
+	require.NoError(t, transcriptService.RecordUtterance("guild1", TranscriptEntry{UserID: "user1", Username: "alice", Text: "secret"}))
+
+	stored, err := storage.LoadGuildTranscripts("guild1")
+	require.NoError(t, err)
+	require.Len(t, stored.Sessions, 1)
+	require.Len(t, stored.Sessions[0].Entries, 1)
+	assert.Empty(t, stored.Sessions[0].Entries[0].Text)
+	assert.Equal(t, "alice", stored.Sessions[0].Entries[0].Username)
+	assert.Contains(t, stored.Sessions[0].Text(), "[content not retained]")
+}
+
+func TestTranscriptService_RetentionLimits(t *testing.T) {
+	transcriptService, configService, _ := createTestTranscriptService(t)
+	enableTranscripts(t, configService, "guild1")
+
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	transcriptService.now = func() time.Time { return now }
+
+	for session := 0; session < MaxTranscriptSessions+2; session++ {
+		require.NoError(t, transcriptService.StartSession("guild1"))
+		require.NoError(t, transcriptService.RecordUtterance("guild1", TranscriptEntry{UserID: "user1", Text: "hi"}))
+		require.NoError(t, transcriptService.EndSession("guild1"))
+		now = now.Add(time.Hour)
+	}
+
+	transcripts, err := transcriptService.currentTranscripts("guild1")
+	require.NoError(t, err)
+	assert.Len(t, transcripts.Sessions, MaxTranscriptSessions)
+
+	// Finished sessions expire once they are older than the retention period
+	now = now.Add(TranscriptRetention)
+	session, err := transcriptService.LatestSession("guild1")
+	require.NoError(t, err)
+	assert.Nil(t, session)
+}
+
+func TestTranscriptService_SessionEntryLimit(t *testing.T) {
+	transcriptService, configService, _ := createTestTranscriptService(t)
+	enableTranscripts(t, configService, "guild1")
+	require.NoError(t, transcriptService.StartSession("guild1"))
+
+	transcripts, err := transcriptService.currentTranscripts("guild1")
+	require.NoError(t, err)
+	transcripts.Sessions[0].Entries = make([]TranscriptEntry, MaxTranscriptEntries)
+
+	require.NoError(t, transcriptService.RecordUtterance("guild1", TranscriptEntry{UserID: "user1", Text: "one too many"}))
+
+	session, err := transcriptService.LatestSession("guild1")
+	require.NoError(t, err)
+	require.NotNil(t, session)
+	assert.Len(t, session.Entries, MaxTranscriptEntries)
+	assert.True(t, session.Truncated)
+}
+
+func TestTranscriptService_ClearTranscripts(t *testing.T) {
+	transcriptService, configService, storage := createTestTranscriptService(t)
+	enableTranscripts(t, configService, "guild1")
+	enableTranscripts(t, configService, "guild2")
+
+	require.NoError(t, transcriptService.RecordUtterance("guild1", TranscriptEntry{UserID: "user1", Text: "hi"}))
+	require.NoError(t, transcriptService.RecordUtterance("guild2", TranscriptEntry{UserID: "user1", Text: "hi"}))
+	require.NoError(t, transcriptService.ClearTranscripts("guild1"))
+
+	stored, err := storage.LoadGuildTranscripts("guild1")
+	require.NoError(t, err)
+	assert.Empty(t, stored.Sessions)
+
+	// Other guilds are unaffected
+	session, err := transcriptService.LatestSession("guild2")
+	require.NoError(t, err)
+	assert.NotNil(t, session)
+}
+
+func TestTranscriptSession_Text(t *testing.T) {
+	startedAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	session := &TranscriptSession{
+		StartedAt: startedAt,
+		EndedAt:   startedAt.Add(time.Hour),
+		Entries: []TranscriptEntry{
+			{Username: "alice", Text: "alice says: hello", SpokenAt: startedAt.Add(5 * time.Second), DurationMs: 1500},
+			{Username: "bob", SpokenAt: startedAt.Add(10 * time.Second), DurationMs: 2000},
+		},
+	}
+
+	lines := strings.Split(strings.TrimSpace(session.Text()), "\n")
+	assert.Equal(t, []string{
+		"Session started 2026-10-01 12:00:00 UTC",
+		"[12:00:05] alice (1.5s): alice says: hello",
+		"[12:00:10] bob (2.0s): [content not retained]",
+		"Session ended 2026-10-01 13:00:00 UTC",
+	}, lines)
+
+	session.EndedAt = time.Time{}
+	session.Truncated = true
+	assert.Contains(t, session.Text(), "[transcript stopped after 2 utterances]\nSession ongoing\n")
+}
+
+func TestTranscriptService_RecordsFinishedUtterances(t *testing.T) {
+	transcriptService, configService, _ := createTestTranscriptService(t)
+	enableTranscripts(t, configService, "guild1")
+
+	bus := events.New()
+	transcriptService.Subscribe(bus)
+
+	spokenAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	chat := events.Utterance{GuildID: "guild1", UserID: "user1", Username: "alice", Text: "alice says: hi"}
+	bus.Publish(events.UtteranceFinished{Utterance: chat, Played: 1500 * time.Millisecond, Completed: true, FinishedAt: spokenAt})
+
+	// Skipped messages, announcements and previews are not part of the conversation
+	bus.Publish(events.UtteranceFinished{Utterance: chat, Completed: false})
+	bus.Publish(events.UtteranceFinished{Utterance: events.Utterance{GuildID: "guild1", Text: "ann joined", Announcement: true}, Completed: true})
+	bus.Publish(events.UtteranceFinished{Utterance: events.Utterance{GuildID: "guild1", Text: "preview", Preview: true}, Completed: true})
+
+	session, err := transcriptService.LatestSession("guild1")
+	require.NoError(t, err)
+	require.NotNil(t, session)
+	require.Len(t, session.Entries, 1)
+	assert.Equal(t, TranscriptEntry{UserID: "user1", Username: "alice", Text: "alice says: hi", SpokenAt: spokenAt, DurationMs: 1500}, session.Entries[0])
+}
@@ -0,0 +1,128 @@
+package tts
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// ellipsis marks text that was cut short
+const ellipsis = "..."
+
+// LengthPolicy holds how a guild reads messages longer than one utterance
+type LengthPolicy struct {
+	Mode      TruncationMode
+	MaxLength int // Longest utterance in bytes
+}
+
+// LengthPolicyFor returns the length policy of a guild configuration, filling in
+// defaults for unset values
+func LengthPolicyFor(config *GuildTTSConfig) LengthPolicy {
+	policy := LengthPolicy{Mode: TruncationModeSentence, MaxLength: DefaultMaxMessageLength}
+	if config == nil {
+		return policy
+	}
+	if config.TruncationMode != "" {
+		policy.Mode = config.TruncationMode
+	}
+	if config.MaxUtteranceLength > 0 {
+		policy.MaxLength = config.MaxUtteranceLength
+	}
+	return policy
+}
+
+// Apply returns the utterances to read for text. Text that fits is read whole; longer
+// text is cut or split according to the mode. The ask mode splits like split; only its
+// first part is read unless a listener asks for the rest.
+func (p LengthPolicy) Apply(text string) []string {
+	if len(text) <= p.MaxLength {
+		return []string{text}
+	}
+
+	switch p.Mode {
+	case TruncationModeHard:
+		return []string{cutText(text, p.MaxLength)}
+	case TruncationModeSplit, TruncationModeAsk:
+		return splitText(text, p.MaxLength)
+	default:
+		return []string{cutSentence(text, p.MaxLength)}
+	}
+}
+
+// cutText cuts text to limit bytes, ellipsis included, without breaking a character
+func cutText(text string, limit int) string {
+	return text[:runeStart(text, limit-len(ellipsis))] + ellipsis
+}
+
+// cutSentence cuts text after the last whole sentence that fits in limit bytes. When that
+// would drop more than half of the limit it cuts after the last whole word instead, and
+// mid-word when the text has no usable word break either.
+func cutSentence(text string, limit int) string {
+	if end := sentenceEnd(text, limit); end > 0 {
+		return text[:end]
+	}
+	if end := wordEnd(text, limit-len(ellipsis)); end > 0 {
+		return text[:end] + ellipsis
+	}
+	return cutText(text, limit)
+}
+
+// splitText splits text into parts of at most limit bytes, breaking after sentences where
+// possible and between words otherwise
+func splitText(text string, limit int) []string {
+	var parts []string
+	for len(text) > limit {
+		end := sentenceEnd(text, limit)
+		if end == 0 {
+			end = wordEnd(text, limit)
+		}
+		if end == 0 {
+			end = runeStart(text, limit)
+		}
+		if end == 0 {
+			// No character starts within the limit, which only happens with invalid UTF-8
+			end = limit
+		}
+		parts = append(parts, strings.TrimSpace(text[:end]))
+		text = strings.TrimSpace(text[end:])
+	}
+	if text != "" {
+		parts = append(parts, text)
+	}
+	return parts
+}
+
+// sentenceEnd returns where the last sentence ending within the first limit bytes of text
+// ends, or 0 when no sentence ends in the second half of that range. Text must be longer
+// than limit.
+func sentenceEnd(text string, limit int) int {
+	for i := limit; i > limit/2; i-- {
+		if isSpaceByte(text[i]) && strings.ContainsRune(".!?", rune(text[i-1])) {
+			return i
+		}
+	}
+	return 0
+}
+
+// wordEnd returns where the last word ending within the first limit bytes of text ends, or
+// 0 when no word ends in the second half of that range. Text must be longer than limit.
+func wordEnd(text string, limit int) int {
+	for i := limit; i > limit/2; i-- {
+		if isSpaceByte(text[i]) && !isSpaceByte(text[i-1]) {
+			return i
+		}
+	}
+	return 0
+}
+
+// runeStart returns the largest index up to n that starts a character
+func runeStart(text string, n int) int {
+	for n > 0 && !utf8.RuneStart(text[n]) {
+		n--
+	}
+	return n
+}
+
+// isSpaceByte reports whether b is ASCII whitespace
+func isSpaceByte(b byte) bool {
+	return b == ' ' || b == '\n' || b == '\t' || b == '\r'
+}
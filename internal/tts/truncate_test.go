@@ -0,0 +1,106 @@
+package tts
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLengthPolicyFor(t *testing.T) {
+	assert.Equal(t, LengthPolicy{Mode: TruncationModeSentence, MaxLength: DefaultMaxMessageLength}, LengthPolicyFor(nil))
+	assert.Equal(t, LengthPolicy{Mode: TruncationModeSentence, MaxLength: DefaultMaxMessageLength}, LengthPolicyFor(&GuildTTSConfig{}))
+	assert.Equal(t, LengthPolicy{Mode: TruncationModeSplit, MaxLength: 120}, LengthPolicyFor(&GuildTTSConfig{TruncationMode: TruncationModeSplit, MaxUtteranceLength: 120}))
+}
+
+func TestLengthPolicy_ShortTextIsReadWhole(t *testing.T) {
+	for _, mode := range []TruncationMode{TruncationModeHard, TruncationModeSentence, TruncationModeSplit} {
+		policy := LengthPolicy{Mode: mode, MaxLength: 20}
+		assert.Equal(t, []string{"Exactly twenty bytes"}, policy.Apply("Exactly twenty bytes"), mode)
+	}
+}
+
+func TestLengthPolicy_Hard(t *testing.T) {
+	policy := LengthPolicy{Mode: TruncationModeHard, MaxLength: 20}
+
+	assert.Equal(t, []string{"The quick brown f..."}, policy.Apply("The quick brown fox jumps over the lazy dog"))
+
+	// Multi-byte characters are not broken in half
+	parts := policy.Apply(strings.Repeat("ä", 20))
+	assert.True(t, utf8.ValidString(parts[0]))
+	assert.LessOrEqual(t, len(parts[0]), 20)
+}
+
+func TestLengthPolicy_Sentence(t *testing.T) {
+	policy := LengthPolicy{Mode: TruncationModeSentence, MaxLength: 40}
+
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{
+			name: "cuts after the last whole sentence",
+			text: "Hello there. How are you today? I am fine, thanks for asking.",
+			want: "Hello there. How are you today?",
+		},
+		{
+			name: "cuts between words when a sentence would lose too much",
+			text: "Hi. This is one very long sentence that keeps going on and on",
+			want: "Hi. This is one very long sentence...",
+		},
+		{
+			name: "cuts mid-word without word breaks",
+			text: strings.Repeat("a", 60),
+			want: strings.Repeat("a", 37) + "...",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parts := policy.Apply(tt.text)
+			assert.Equal(t, []string{tt.want}, parts)
+			assert.LessOrEqual(t, len(parts[0]), policy.MaxLength)
+		})
+	}
+}
+
+func TestLengthPolicy_Split(t *testing.T) {
+	policy := LengthPolicy{Mode: TruncationModeSplit, MaxLength: 30}
+
+	parts := policy.Apply("First sentence here. Second one is a little longer than that. Done!")
+	assert.Equal(t, []string{
+		"First sentence here.",
+		"Second one is a little longer",
+		"than that. Done!",
+	}, parts)
+
+	// Nothing is lost, and no part is too long
+	text := strings.Repeat("word ", 40) + strings.Repeat("x", 70)
+	parts = policy.Apply(text)
+	assert.Equal(t, strings.Join(strings.Fields(text), ""), strings.Join(strings.Fields(strings.Join(parts, "")), ""))
+	for _, part := range parts {
+		assert.LessOrEqual(t, len(part), policy.MaxLength)
+		assert.NotEmpty(t, part)
+	}
+}
+
+func FuzzLengthPolicy(f *testing.F) {
+	f.Add("First sentence here. Second one is a little longer than that. Done!", uint16(50))
+	f.Add(strings.Repeat("ä", 100), uint16(51))
+	f.Add(strings.Repeat("\x80", 120), uint16(50))
+
+	f.Fuzz(func(t *testing.T, text string, n uint16) {
+		limit := MinMessageLength + int(n)%(MaxMessageLength-MinMessageLength+1)
+		for _, mode := range []TruncationMode{TruncationModeHard, TruncationModeSentence, TruncationModeSplit} {
+			parts := LengthPolicy{Mode: mode, MaxLength: limit}.Apply(text)
+			for _, part := range parts {
+				assert.LessOrEqual(t, len(part), limit)
+				if utf8.ValidString(text) {
+					assert.True(t, utf8.ValidString(part))
+				}
+			}
+		}
+	})
+}
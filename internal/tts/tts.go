@@ -13,11 +13,22 @@ const Version = "0.1.0"
 // Package-level errors
 var (
 	ErrInvalidConfig     = fmt.Errorf("invalid TTS configuration")
-	ErrVoiceNotConnected = fmt.Errorf("not connected to voice channel")
+	ErrVoiceNotConnected = withKind(ErrVoiceGateway, fmt.Errorf("not connected to voice channel"))
 	ErrQueueFull         = fmt.Errorf("message queue is full")
 	ErrUserNotOptedIn    = fmt.Errorf("user has not opted in to TTS")
-	ErrInvalidPermission = fmt.Errorf("insufficient permissions")
+	ErrInvalidPermission = withKind(ErrPermission, fmt.Errorf("insufficient permissions"))
 	ErrChannelNotPaired  = fmt.Errorf("channel is not paired")
+	ErrNSFWChannel       = fmt.Errorf("channel is age-restricted")
+	ErrQuotaExceeded     = withKind(ErrTTSQuota, fmt.Errorf("daily TTS character budget exceeded"))
+	ErrClipNotFound      = fmt.Errorf("audio clip not found")
+	ErrClipLimitExceeded = fmt.Errorf("audio clip storage limit exceeded")
+	ErrProfileNotFound   = fmt.Errorf("configuration profile not found")
+	ErrProfileLimit      = fmt.Errorf("configuration profile limit exceeded")
+	ErrAPITokenNotFound  = fmt.Errorf("API token not found")
+	ErrAPITokenExists    = fmt.Errorf("API token already exists")
+	ErrAPITokenLimit     = fmt.Errorf("API token limit exceeded")
+	ErrAPITokenInvalid   = fmt.Errorf("invalid API token")
+	ErrPlaybackSkipped   = fmt.Errorf("playback skipped")
 )
 
 // Constants for TTS limits and defaults
@@ -35,4 +46,34 @@ const (
 
 	MaxQueueSize     = 100
 	MaxMessageLength = 2000
+	MinMessageLength = 50  // Shortest configurable utterance length
+	MaxMutedUsers    = 100 // Per listener
+	MaxReadChannels  = 25  // Per user and guild
+
+	MaxIgnorePrefixes     = 10 // Per guild
+	MaxIgnorePrefixLength = 10
+
+	MaxSpeakerRoles = 25 // Per guild
+
+	MaxAllowedBots = 25 // Per guild
+
+	MaxGuildProfiles     = 10 // Per guild
+	MaxProfileNameLength = 32
+
+	MaxGreetingLength = 300 // Welcome text spoken when the bot joins a pairing's voice channel
+
+	DefaultCommandPrefix   = "!darrot" // Starts text commands in guilds that did not choose another
+	CommandPrefixOff       = "off"     // Turns text commands off
+	MaxCommandPrefixLength = 16
+
+	DefaultMaxSpokenEmoji = 5 // Per message
+	MaxSpokenEmojiLimit   = 50
+
+	DefaultIdleAnnounceMinutes = 5   // Silence before the "still listening" announcement
+	MaxIdleMinutes             = 720 // Longest configurable idle timeout
+
+	MaxUserMessagesPerMinute = 60  // Highest configurable per-user message limit
+	CooldownReaction         = "⏳" // Added to messages dropped by the per-user limit
+
+	MaxHoldBackSeconds = 10 // Longest configurable wait for more messages from the same author
 )
@@ -0,0 +1,173 @@
+package tts
+
+import (
+	"log"
+	"time"
+
+	"darrot/internal/events"
+)
+
+// Circuit breaker tuning
+const (
+	DefaultTTSBreakerThreshold = 5               // Messages failing in a row before a guild's breaker opens
+	DefaultTTSBreakerCooldown  = 2 * time.Minute // How long an open breaker skips messages before probing
+	ttsBreakerProbeText        = "Test"          // Synthesized to check that the engine works again
+)
+
+// Circuit breaker metric names
+const (
+	MetricTTSBreakerState   = "darrot_tts_breaker_state"
+	MetricTTSBreakerOpened  = "darrot_tts_breaker_opened_total"
+	MetricTTSBreakerSkipped = "darrot_tts_breaker_skipped_total"
+)
+
+// BreakerState is the state of a guild's TTS circuit breaker
+type BreakerState string
+
+// Circuit breaker states
+const (
+	BreakerClosed   BreakerState = "closed"    // Messages are synthesized
+	BreakerOpen     BreakerState = "open"      // Messages are skipped until the cooldown ends
+	BreakerHalfOpen BreakerState = "half_open" // A probe synthesis decides whether to close
+)
+
+// gauge returns the value of the state in MetricTTSBreakerState
+func (s BreakerState) gauge() float64 {
+	switch s {
+	case BreakerOpen:
+		return 1
+	case BreakerHalfOpen:
+		return 2
+	default:
+		return 0
+	}
+}
+
+// BreakerStatus describes a guild's TTS circuit breaker
+type BreakerStatus struct {
+	State     BreakerState
+	Failures  int       // Messages that failed synthesis in a row
+	OpenUntil time.Time // When an open breaker probes the engine
+}
+
+// TTSBreakerStatus is implemented by TTS processors that stop synthesizing for guilds
+// whose messages keep failing
+type TTSBreakerStatus interface {
+	BreakerStatus(guildID string) BreakerStatus
+}
+
+// BreakerStatus returns the state of a guild's TTS circuit breaker
+func (erm *ErrorRecoveryManager) BreakerStatus(guildID string) BreakerStatus {
+	erm.mu.RLock()
+	defer erm.mu.RUnlock()
+
+	stats, exists := erm.errorStats[guildID]
+	if !exists || stats.Breaker == "" {
+		return BreakerStatus{State: BreakerClosed}
+	}
+	return BreakerStatus{State: stats.Breaker, Failures: stats.ConsecutiveTTSFailures, OpenUntil: stats.BreakerOpenUntil}
+}
+
+// allowSynthesis reports whether a guild's next message may be synthesized. An open
+// breaker refuses until its cooldown ends, then synthesizes a probe with config: the
+// breaker closes if it succeeds and stays open for another cooldown if it fails.
+func (erm *ErrorRecoveryManager) allowSynthesis(guildID string, config TTSConfig) bool {
+	erm.mu.Lock()
+	stats, exists := erm.errorStats[guildID]
+	if !exists || stats.Breaker == "" || stats.Breaker == BreakerClosed {
+		erm.mu.Unlock()
+		return true
+	}
+	if stats.Breaker == BreakerHalfOpen || time.Now().Before(stats.BreakerOpenUntil) {
+		erm.mu.Unlock()
+		erm.countBreaker(MetricTTSBreakerSkipped, guildID)
+		return false
+	}
+	erm.setBreakerLocked(stats, BreakerHalfOpen)
+	erm.mu.Unlock()
+
+	_, err := erm.ttsManager.ConvertToSpeech(ttsBreakerProbeText, config.Voice, config)
+
+	erm.mu.Lock()
+	defer erm.mu.Unlock()
+	if err != nil {
+		stats.BreakerOpenUntil = time.Now().Add(erm.breakerCooldown)
+		erm.setBreakerLocked(stats, BreakerOpen)
+		log.Printf("TTS probe failed for guild %s, skipping messages until %s: %v", guildID, stats.BreakerOpenUntil.Format(time.TimeOnly), err)
+		erm.countBreaker(MetricTTSBreakerSkipped, guildID)
+		return false
+	}
+	stats.ConsecutiveTTSFailures = 0
+	erm.setBreakerLocked(stats, BreakerClosed)
+	log.Printf("TTS probe succeeded for guild %s, reading messages again", guildID)
+	return true
+}
+
+// recordTTSSuccess restarts the count of messages failing synthesis in a row
+func (erm *ErrorRecoveryManager) recordTTSSuccess(guildID string) {
+	erm.mu.Lock()
+	defer erm.mu.Unlock()
+
+	if stats, exists := erm.errorStats[guildID]; exists {
+		stats.ConsecutiveTTSFailures = 0
+	}
+}
+
+// recordTTSFailure counts a message whose synthesis failed after every fallback. The
+// breaker opens once breakerThreshold messages failed in a row, and a TTSOutage is
+// published so the guild's text channel is told once.
+func (erm *ErrorRecoveryManager) recordTTSFailure(guildID string, cause error) {
+	erm.mu.Lock()
+	stats, exists := erm.errorStats[guildID]
+	if !exists {
+		stats = &ErrorStats{GuildID: guildID, LastSuccessfulActivity: time.Now()}
+		erm.errorStats[guildID] = stats
+	}
+	stats.ConsecutiveTTSFailures++
+	failures := stats.ConsecutiveTTSFailures
+	open := failures >= erm.breakerThreshold && (stats.Breaker == "" || stats.Breaker == BreakerClosed)
+	if open {
+		stats.BreakerOpenUntil = time.Now().Add(erm.breakerCooldown)
+		erm.setBreakerLocked(stats, BreakerOpen)
+	}
+	retryAt := stats.BreakerOpenUntil
+	erm.mu.Unlock()
+
+	if !open {
+		return
+	}
+
+	log.Printf("TTS failed for %d messages in a row in guild %s, skipping messages until %s", failures, guildID, retryAt.Format(time.TimeOnly))
+	erm.countBreaker(MetricTTSBreakerOpened, guildID)
+
+	var channelID string
+	if connection, connected := erm.voiceManager.GetConnection(guildID); connected && connection != nil {
+		channelID = connection.ChannelID
+	}
+	reason := ""
+	if cause != nil {
+		reason = cause.Error()
+	}
+	erm.eventBus.Publish(events.TTSOutage{
+		GuildID:   guildID,
+		ChannelID: channelID,
+		Reason:    reason,
+		Failures:  failures,
+		RetryAt:   retryAt,
+	})
+}
+
+// setBreakerLocked changes the state of a guild's breaker. erm.mu must be held.
+func (erm *ErrorRecoveryManager) setBreakerLocked(stats *ErrorStats, state BreakerState) {
+	stats.Breaker = state
+	if erm.metrics != nil {
+		erm.metrics.SetGauge(MetricTTSBreakerState, Labels{"guild": stats.GuildID}, state.gauge())
+	}
+}
+
+// countBreaker increases one of a guild's breaker counters
+func (erm *ErrorRecoveryManager) countBreaker(name, guildID string) {
+	if erm.metrics != nil {
+		erm.metrics.IncCounter(name, Labels{"guild": guildID})
+	}
+}
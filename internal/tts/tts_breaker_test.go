@@ -0,0 +1,114 @@
+package tts
+
+import (
+	"errors"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"darrot/internal/events"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newBreakerTestManager(ttsManager TTSManager) *ErrorRecoveryManager {
+	return NewErrorRecoveryManagerWithConfig(newMockVoiceManagerForRecovery(), ttsManager, &mockMessageQueueForRecovery{}, &mockConfigServiceForRecovery{}, ErrorRecoveryConfig{
+		MaxRetries:       1,
+		RetryDelay:       time.Millisecond,
+		BreakerThreshold: 2,
+		BreakerCooldown:  20 * time.Millisecond,
+	})
+}
+
+func TestTTSBreaker_OpensAfterConsecutiveFailures(t *testing.T) {
+	ttsManager := newMockTTSManagerForRecovery()
+	erm := newBreakerTestManager(ttsManager)
+	metrics := NewMetrics()
+	erm.SetMetrics(metrics)
+	bus := events.New()
+	erm.SetEventBus(bus)
+
+	var outages []events.TTSOutage
+	events.Subscribe(bus, func(e events.TTSOutage) { outages = append(outages, e) })
+
+	config := TTSConfig{Voice: DefaultVoice}
+	ttsManager.globalError = errors.New("service unavailable")
+
+	// A success in between restarts the count
+	_, err := erm.HandleTTSFailure("one", "", config, "guild1")
+	require.Error(t, err)
+	erm.recordTTSSuccess("guild1")
+	_, err = erm.HandleTTSFailure("two", "", config, "guild1")
+	require.Error(t, err)
+	assert.Equal(t, BreakerClosed, erm.BreakerStatus("guild1").State)
+	assert.True(t, erm.allowSynthesis("guild1", config))
+
+	_, err = erm.HandleTTSFailure("three", "", config, "guild1")
+	require.Error(t, err)
+	status := erm.BreakerStatus("guild1")
+	assert.Equal(t, BreakerOpen, status.State)
+	assert.Equal(t, 2, status.Failures)
+	assert.Equal(t, 1.0, metrics.Value(MetricTTSBreakerState, Labels{"guild": "guild1"}))
+	assert.Equal(t, 1.0, metrics.Value(MetricTTSBreakerOpened, Labels{"guild": "guild1"}))
+	require.Len(t, outages, 1)
+	assert.Equal(t, "guild1", outages[0].GuildID)
+	assert.Equal(t, 2, outages[0].Failures)
+	assert.Equal(t, status.OpenUntil, outages[0].RetryAt)
+
+	// Messages are skipped without calling the engine until the cooldown ends
+	calls := len(ttsManager.conversionCalls)
+	assert.False(t, erm.allowSynthesis("guild1", config))
+	assert.Len(t, ttsManager.conversionCalls, calls)
+	assert.Equal(t, 1.0, metrics.Value(MetricTTSBreakerSkipped, Labels{"guild": "guild1"}))
+	assert.True(t, erm.allowSynthesis("guild2", config), "other guilds are unaffected")
+
+	// A failed probe keeps the breaker open for another cooldown without a new notice
+	time.Sleep(25 * time.Millisecond)
+	assert.False(t, erm.allowSynthesis("guild1", config))
+	assert.Len(t, ttsManager.conversionCalls, calls+1)
+	assert.Equal(t, ttsBreakerProbeText, ttsManager.conversionCalls[calls].Text)
+	assert.Equal(t, BreakerOpen, erm.BreakerStatus("guild1").State)
+	assert.True(t, erm.BreakerStatus("guild1").OpenUntil.After(status.OpenUntil))
+	assert.Len(t, outages, 1)
+
+	// A successful probe closes it
+	ttsManager.globalError = nil
+	time.Sleep(25 * time.Millisecond)
+	assert.True(t, erm.allowSynthesis("guild1", config))
+	assert.Equal(t, BreakerClosed, erm.BreakerStatus("guild1").State)
+	assert.Zero(t, erm.BreakerStatus("guild1").Failures)
+	assert.Equal(t, 0.0, metrics.Value(MetricTTSBreakerState, Labels{"guild": "guild1"}))
+}
+
+func TestVoiceOutageNotifier_PostsTTSOutage(t *testing.T) {
+	env := setupHandoffTest(t)
+	require.NoError(t, env.channelService.CreatePairingWithCreator("guild1", "voice1", "text1", "user1"))
+	notifier := NewVoiceOutageNotifier(env.channelService, env.messenger, log.New(os.Stdout, "", 0))
+
+	notifier.notifyTTS(events.TTSOutage{GuildID: "guild1", ChannelID: "voice1", Failures: 5, RetryAt: time.Unix(1760000000, 0)})
+	notifier.notifyTTS(events.TTSOutage{GuildID: "guild1", Failures: 5, RetryAt: time.Unix(1760000000, 0)}) // Not in a voice channel
+
+	assert.Equal(t, map[string]string{
+		"text1": "🔇 Text-to-speech failed for the last 5 messages, so I'm skipping messages for now. I'll try again <t:1760000000:R>.",
+	}, env.messenger.messages)
+}
+
+func TestDiagnoseCommandHandler_ReportsBreaker(t *testing.T) {
+	handler, _ := createTestDiagnoseHandler(t, &mockTTSManagerForRecovery{})
+	assert.Len(t, handler.diagnose(&fakeDiagnosticsSession{}, "bot1", 0, "guild1", "", ""), 5, "no breaker row without a processor")
+
+	erm := newBreakerTestManager(newMockTTSManagerForRecovery())
+	handler.SetBreakerStatus(erm)
+	erm.recordTTSFailure("guild1", errors.New("unavailable"))
+	erm.recordTTSFailure("guild1", errors.New("unavailable"))
+
+	checks := handler.diagnose(&fakeDiagnosticsSession{}, "bot1", 0, "guild1", "", "")
+	require.Len(t, checks, 6)
+	assert.Equal(t, diagnosticFailed, checks[5].status)
+	assert.Contains(t, checks[5].detail, "Skipping messages after 2 failed in a row")
+
+	checks = handler.diagnose(&fakeDiagnosticsSession{}, "bot1", 0, "guild2", "", "")
+	assert.Equal(t, diagnosticPassed, checks[5].status)
+}
@@ -1,9 +1,13 @@
 package tts
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // TTS-specific errors
@@ -13,6 +17,7 @@ var (
 	ErrInvalidVoiceConfig    = fmt.Errorf("invalid voice configuration")
 	ErrTextTooLong           = fmt.Errorf("text exceeds maximum length")
 	ErrEmptyText             = fmt.Errorf("text cannot be empty")
+	ErrOpusUnavailable       = fmt.Errorf("no Opus encoder is available")
 )
 
 // TTSError represents a TTS-specific error with context
@@ -70,15 +75,16 @@ func (er *ErrorRecovery) HandleTTSFailure(manager *GoogleTTSManager, text, voice
 	var lastErr error
 
 	// Check if manager has a valid client
-	if manager.client == nil {
+	if manager.ttsClient() == nil {
 		return nil, NewTTSError("conversion", "TTS client not available", guildID, "", ErrTTSEngineUnavailable)
 	}
 
-	// Retry with original configuration
+	// Retry with original configuration, backing off between attempts
+	backoff := retryPolicy{maxAttempts: er.maxRetries, baseDelay: er.retryDelay, maxDelay: 4 * er.retryDelay}
 	for attempt := 0; attempt < er.maxRetries; attempt++ {
 		if attempt > 0 {
 			log.Printf("Retrying TTS conversion for guild %s, attempt %d/%d", guildID, attempt+1, er.maxRetries)
-			time.Sleep(er.retryDelay)
+			time.Sleep(backoff.delay(attempt))
 		}
 
 		audioData, err := manager.ConvertToSpeech(text, voice, config)
@@ -155,11 +161,15 @@ func (er *ErrorRecovery) HandleRateLimit(retryAfter time.Duration, guildID strin
 	return nil
 }
 
-// IsRetryableError determines if an error is retryable
+// IsRetryableError determines if an error is retryable. Rate limits and Google Cloud
+// errors are judged by their kind and gRPC status code, other errors by their message.
 func IsRetryableError(err error) bool {
 	if err == nil {
 		return false
 	}
+	if errors.Is(err, ErrRateLimited) || isRetryableCode(err) {
+		return true
+	}
 
 	// Check for specific retryable error patterns
 	errorStr := err.Error()
@@ -188,6 +198,13 @@ func IsFatalError(err error) bool {
 	if err == nil {
 		return false
 	}
+	if errors.Is(err, ErrPermission) || errors.Is(err, ErrTTSQuota) {
+		return true
+	}
+	switch status.Code(err) {
+	case codes.InvalidArgument, codes.PermissionDenied, codes.Unauthenticated:
+		return true
+	}
 
 	errorStr := err.Error()
 
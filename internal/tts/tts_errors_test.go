@@ -6,6 +6,8 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func TestTTSError(t *testing.T) {
@@ -151,6 +153,16 @@ func TestIsRetryableError(t *testing.T) {
 			err:      errors.New("invalid input"),
 			expected: false,
 		},
+		{
+			name:     "unavailable status",
+			err:      status.Error(codes.Unavailable, "backend unavailable"),
+			expected: true,
+		},
+		{
+			name:     "resource exhausted status",
+			err:      status.Error(codes.ResourceExhausted, "too many requests"),
+			expected: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -202,6 +214,16 @@ func TestIsFatalError(t *testing.T) {
 			err:      errors.New("connection timeout"),
 			expected: false,
 		},
+		{
+			name:     "unauthenticated status",
+			err:      status.Error(codes.Unauthenticated, "request had invalid authentication"),
+			expected: true,
+		},
+		{
+			name:     "unavailable status",
+			err:      status.Error(codes.Unavailable, "backend unavailable"),
+			expected: false,
+		},
 	}
 
 	for _, tt := range tests {
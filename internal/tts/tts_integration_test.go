@@ -242,6 +242,9 @@ func TestTTSAudioFormatConversion(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			if tt.format == AudioFormatDCA || tt.format == AudioFormatOpus {
+				requireOpusEncoding(t)
+			}
 			result, err := manager.convertToDiscordFormat(testData, tt.format)
 
 			if tt.wantErr {
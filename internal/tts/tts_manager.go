@@ -7,58 +7,273 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"slices"
+	"strings"
 	"sync"
+	"time"
 
 	texttospeech "cloud.google.com/go/texttospeech/apiv1"
 	"cloud.google.com/go/texttospeech/apiv1/texttospeechpb"
+	gax "github.com/googleapis/gax-go/v2"
 	"google.golang.org/api/option"
-	"gopkg.in/hraban/opus.v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
+// engineCheckTimeout bounds the API call made to check new credentials
+const engineCheckTimeout = 10 * time.Second
+
 // GoogleTTSManager implements TTSManager using Google Cloud Text-to-Speech
 type GoogleTTSManager struct {
 	client        *texttospeech.Client
+	clientErr     error // Why there is no client, until Reconnect succeeds
+	clientMu      sync.RWMutex
 	messageQueue  MessageQueue
 	voiceConfigs  map[string]TTSConfig
 	errorRecovery *ErrorRecovery
 	healthChecker *TTSHealthChecker
 	mu            sync.RWMutex
+
+	// Opus encoders are pooled per bitrate and reused across messages
+	poolsOnce    sync.Once
+	dcaEncoders  *OpusEncoderPool
+	opusEncoders *OpusEncoderPool
+
+	// How PCM is encoded to Opus, detected on first use
+	backendOnce sync.Once
+	opusBackend opusBackend
+	ffmpegPath  string
+
+	// Voices that cannot be synthesized straight to Ogg Opus
+	pcmOnlyVoices map[string]bool
+
+	// Where Reconnect and ReloadCredentials create the client
+	credentials CredentialSource
+	endpoint    string
+
+	// The credentials the client was built with, and the background check for their
+	// rotation
+	loaded    *loadedCredentials
+	stopWatch chan struct{}
+	watchDone chan struct{}
+
+	// How long each synthesis request may take and how failed requests are retried
+	synthesisTimeout time.Duration
+	retries          retryPolicy
 }
 
 // NewGoogleTTSManager creates a new Google TTS manager instance
 func NewGoogleTTSManager(messageQueue MessageQueue, credentialsPath string) (*GoogleTTSManager, error) {
-	ctx := context.Background()
+	return NewGoogleTTSManagerWithEndpoint(messageQueue, credentialsPath, "")
+}
 
-	var client *texttospeech.Client
-	var err error
+// NewGoogleTTSManagerWithEndpoint creates a Google TTS manager that talks to a custom
+// endpoint. A host:port endpoint is dialed over gRPC with the usual credentials, an
+// https:// URL uses the REST API with the usual credentials and an http:// URL uses the
+// REST API without authentication, which is how local mocks such as tests/mock-tts are
+// reached. An empty endpoint uses Google's public endpoint.
+func NewGoogleTTSManagerWithEndpoint(messageQueue MessageQueue, credentialsPath, endpoint string) (*GoogleTTSManager, error) {
+	return NewGoogleTTSManagerWithCredentials(messageQueue, CredentialSource{Path: credentialsPath}, endpoint)
+}
 
-	if credentialsPath != "" {
-		// Use service account credentials file
-		client, err = texttospeech.NewClient(ctx, option.WithCredentialsFile(credentialsPath))
-	} else {
-		// Use default credentials (Application Default Credentials)
-		client, err = texttospeech.NewClient(ctx)
-	}
+// NewGoogleTTSManagerWithCredentials creates a Google TTS manager that reads its
+// credentials from a file or Secret Manager secret and talks to endpoint as described
+// for NewGoogleTTSManagerWithEndpoint. StartCredentialWatch rebuilds the client when the
+// credentials rotate.
+func NewGoogleTTSManagerWithCredentials(messageQueue MessageQueue, credentials CredentialSource, endpoint string) (*GoogleTTSManager, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), credentialLoadTimeout)
+	defer cancel()
 
+	credentialsJSON, err := credentials.Load(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load TTS credentials: %w", err)
+	}
+	client, err := newTTSClient(ctx, credentialsJSON, endpoint)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create TTS client: %w", err)
 	}
 
+	manager := newGoogleTTSManager(messageQueue, credentials, endpoint)
+	manager.client = client
+	if credentialsJSON != nil {
+		manager.loaded = manager.inspectCredentials(ctx, credentialsJSON)
+	}
+	return manager, nil
+}
+
+// NewDegradedGoogleTTSManager creates a Google TTS manager without a client, for starting
+// the bot when the client cannot be created, such as when no credentials are configured.
+// EngineError reports reason until Reconnect manages to create the client.
+func NewDegradedGoogleTTSManager(messageQueue MessageQueue, credentials CredentialSource, endpoint string, reason error) *GoogleTTSManager {
+	manager := newGoogleTTSManager(messageQueue, credentials, endpoint)
+	manager.clientErr = reason
+	return manager
+}
+
+// newGoogleTTSManager creates a Google TTS manager without a client
+func newGoogleTTSManager(messageQueue MessageQueue, credentials CredentialSource, endpoint string) *GoogleTTSManager {
 	manager := &GoogleTTSManager{
-		client:        client,
-		messageQueue:  messageQueue,
-		voiceConfigs:  make(map[string]TTSConfig),
-		errorRecovery: NewErrorRecovery(),
+		messageQueue:     messageQueue,
+		voiceConfigs:     make(map[string]TTSConfig),
+		errorRecovery:    NewErrorRecovery(),
+		credentials:      credentials,
+		endpoint:         endpoint,
+		synthesisTimeout: DefaultSynthesisTimeout,
+		retries:          defaultRetryPolicy,
 	}
 
 	// Initialize health checker
 	manager.healthChecker = NewTTSHealthChecker(manager)
 
-	return manager, nil
+	return manager
+}
+
+// ttsClient returns the Google Cloud TTS client, or nil when the engine is unavailable
+func (g *GoogleTTSManager) ttsClient() *texttospeech.Client {
+	g.clientMu.RLock()
+	defer g.clientMu.RUnlock()
+	return g.client
+}
+
+// EngineError returns why Google Cloud TTS is unavailable, or nil when it is available
+func (g *GoogleTTSManager) EngineError() error {
+	g.clientMu.RLock()
+	defer g.clientMu.RUnlock()
+
+	if g.client != nil {
+		return nil
+	}
+	if g.clientErr != nil {
+		return g.clientErr
+	}
+	return ErrTTSEngineUnavailable
+}
+
+// ProbeEngine lists the voices with the current client to check that Google Cloud TTS
+// answers and accepts the credentials
+func (g *GoogleTTSManager) ProbeEngine(ctx context.Context) error {
+	client := g.ttsClient()
+	if client == nil {
+		return g.EngineError()
+	}
+	_, err := client.ListVoices(ctx, &texttospeechpb.ListVoicesRequest{})
+	return err
+}
+
+// Reconnect creates the Google Cloud TTS client when the manager has none, reading the
+// credentials again, and checks that the API accepts them before using it
+func (g *GoogleTTSManager) Reconnect() error {
+	if g.EngineError() == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), credentialLoadTimeout)
+	defer cancel()
+
+	credentials, err := g.credentials.Load(ctx)
+	var client *texttospeech.Client
+	var loaded *loadedCredentials
+	if err == nil {
+		client, err = connectTTSClient(credentials, g.endpoint)
+	}
+	if err == nil && credentials != nil {
+		loaded = g.inspectCredentials(ctx, credentials)
+	}
+
+	g.clientMu.Lock()
+	defer g.clientMu.Unlock()
+
+	if err != nil {
+		g.clientErr = fmt.Errorf("failed to create TTS client: %w", err)
+		return g.clientErr
+	}
+	if g.client != nil {
+		// Another reconnect got there first
+		client.Close()
+		return nil
+	}
+	g.client = client
+	g.clientErr = nil
+	g.loaded = loaded
+	return nil
+}
+
+// CheckTTSCredentials checks that Google Cloud TTS accepts the credentials at an
+// endpoint, without keeping a client
+func CheckTTSCredentials(credentials CredentialSource, endpoint string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), credentialLoadTimeout)
+	defer cancel()
+
+	credentialsJSON, err := credentials.Load(ctx)
+	if err != nil {
+		return err
+	}
+	client, err := connectTTSClient(credentialsJSON, endpoint)
+	if err != nil {
+		return err
+	}
+	return client.Close()
+}
+
+// connectTTSClient creates the Google Cloud TTS client for an endpoint and makes one API
+// call with it. Creating a client doesn't contact the API, so invalid credentials only
+// show up in the call.
+func connectTTSClient(credentials []byte, endpoint string) (*texttospeech.Client, error) {
+	client, err := newTTSClient(context.Background(), credentials, endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), engineCheckTimeout)
+	defer cancel()
+	if _, err := client.ListVoices(ctx, &texttospeechpb.ListVoicesRequest{}); err != nil {
+		client.Close()
+		return nil, err
+	}
+	return client, nil
+}
+
+// newTTSClient creates the Google Cloud TTS client for an endpoint, with Application
+// Default Credentials when credentials is nil
+func newTTSClient(ctx context.Context, credentials []byte, endpoint string) (*texttospeech.Client, error) {
+	var opts []option.ClientOption
+
+	switch {
+	case strings.HasPrefix(endpoint, "http://"):
+		// Plain HTTP endpoints are local mocks that don't check credentials
+		return texttospeech.NewRESTClient(ctx, option.WithEndpoint(endpoint), option.WithoutAuthentication())
+	case endpoint != "":
+		opts = append(opts, option.WithEndpoint(endpoint))
+	}
+
+	if credentials != nil {
+		// Use credentials read from a file or secret
+		opts = append(opts, option.WithCredentialsJSON(credentials))
+	}
+	// Otherwise use default credentials (Application Default Credentials)
+
+	if strings.HasPrefix(endpoint, "https://") {
+		return texttospeech.NewRESTClient(ctx, opts...)
+	}
+	return texttospeech.NewClient(ctx, opts...)
+}
+
+// SetSynthesisTimeout sets how long a single synthesis request may take before it is
+// abandoned and, when attempts remain, sent again
+func (g *GoogleTTSManager) SetSynthesisTimeout(timeout time.Duration) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.synthesisTimeout = timeout
 }
 
 // ConvertToSpeech converts text to speech using Google Cloud TTS
 func (g *GoogleTTSManager) ConvertToSpeech(text, voice string, config TTSConfig) ([]byte, error) {
+	return g.ConvertToSpeechContext(context.Background(), text, voice, config)
+}
+
+// ConvertToSpeechContext converts text to speech using Google Cloud TTS, giving up when
+// ctx is cancelled
+func (g *GoogleTTSManager) ConvertToSpeechContext(ctx context.Context, text, voice string, config TTSConfig) ([]byte, error) {
 	if text == "" {
 		return nil, ErrEmptyText
 	}
@@ -68,68 +283,143 @@ func (g *GoogleTTSManager) ConvertToSpeech(text, voice string, config TTSConfig)
 		return nil, ErrTextTooLong
 	}
 
-	// Check if we have a valid client
-	if g.client == nil {
-		return nil, ErrTTSEngineUnavailable
+	// DCA is built straight from Ogg Opus frames when the voice supports it
+	if config.Format == AudioFormatDCA {
+		frames, ok, err := g.synthesizeOpusFrames(ctx, text, voice, config)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			var dcaBuffer bytes.Buffer
+			for _, frame := range frames {
+				if err := writeDCAFrame(&dcaBuffer, frame); err != nil {
+					return nil, err
+				}
+			}
+			return dcaBuffer.Bytes(), nil
+		}
 	}
 
-	// Use provided voice or fall back to config voice or default
-	selectedVoice := voice
-	if selectedVoice == "" {
-		selectedVoice = config.Voice
-	}
-	if selectedVoice == "" {
-		selectedVoice = DefaultVoice
+	// Synthesize and convert to 48kHz stereo PCM
+	processedAudio, err := g.synthesizePCM(ctx, text, voice, config)
+	if err != nil {
+		return nil, err
 	}
 
-	// Validate and set speed
-	speed := config.Speed
-	if speed < MinTTSSpeed || speed > MaxTTSSpeed {
-		speed = DefaultTTSSpeed
+	// Convert audio to Discord-compatible format
+	audioData, err := g.convertToDiscordFormat(processedAudio, config.Format)
+	if err != nil {
+		return nil, fmt.Errorf("audio format conversion failed: %w", err)
 	}
 
-	// Validate and set volume
-	volume := config.Volume
-	if volume < MinTTSVolume || volume > MaxTTSVolume {
-		volume = DefaultTTSVolume
+	log.Printf("[DEBUG] Audio conversion completed: %d bytes PCM -> %d bytes output (format: %s)", len(processedAudio), len(audioData), config.Format)
+	return audioData, nil
+}
+
+// StreamSpeech synthesizes text sentence by sentence and passes each 20ms Opus frame to
+// emit as soon as it is encoded, so playback can start before the whole message has been
+// synthesized. The next sentence is synthesized while the previous one plays. Frames are
+// always Discord-ready Opus, regardless of config.Format. Synthesis stops when ctx is
+// cancelled.
+func (g *GoogleTTSManager) StreamSpeech(ctx context.Context, text, voice string, config TTSConfig, emit func(frame []byte) error) error {
+	if text == "" {
+		return ErrEmptyText
+	}
+	if len(text) > MaxMessageLength {
+		return ErrTextTooLong
+	}
+	if g.ttsClient() == nil {
+		return ErrTTSEngineUnavailable
 	}
 
-	// Parse voice ID to extract language and name
-	languageCode, voiceName := parseVoiceID(selectedVoice)
-
-	// Create the TTS request
-	req := &texttospeechpb.SynthesizeSpeechRequest{
-		Input: &texttospeechpb.SynthesisInput{
-			InputSource: &texttospeechpb.SynthesisInput_Text{
-				Text: text,
-			},
-		},
-		Voice: &texttospeechpb.VoiceSelectionParams{
-			LanguageCode: languageCode,
-			Name:         voiceName,
-		},
-		AudioConfig: &texttospeechpb.AudioConfig{
-			AudioEncoding:   texttospeechpb.AudioEncoding_LINEAR16,
-			SpeakingRate:    float64(speed),
-			VolumeGainDb:    volumeToDB(volume),
-			SampleRateHertz: 24000, // Use 24kHz (widely supported) then resample to 48kHz
-		},
+	// Without libopus, messages are encoded in full through ffmpeg instead
+	if !nativeOpus {
+		return errStreamingUnavailable
 	}
 
-	ctx := context.Background()
-	resp, err := g.client.SynthesizeSpeech(ctx, req)
+	dcaEncoders, _ := g.encoderPools()
+	encoder, err := dcaEncoders.Get()
 	if err != nil {
-		// Check if this is a retryable error
-		if IsRetryableError(err) {
-			return nil, fmt.Errorf("TTS synthesis failed (retryable): %w", err)
+		return err
+	}
+	defer dcaEncoders.Put(encoder)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Synthesize ahead of the encoder, at most one chunk ahead of playback. Each chunk is
+	// either Opus frames from the Ogg Opus path or PCM for the encoder.
+	type synthesizedChunk struct {
+		frames [][]byte
+		pcm    []byte
+		err    error
+	}
+	chunks := splitSpeechChunks(text)
+	results := make(chan synthesizedChunk, 1)
+	go func() {
+		defer close(results)
+		for _, chunk := range chunks {
+			if ctx.Err() != nil {
+				return
+			}
+			var result synthesizedChunk
+			var ok bool
+			result.frames, ok, result.err = g.synthesizeOpusFrames(ctx, chunk, voice, config)
+			if result.err == nil && !ok {
+				result.pcm, result.err = g.synthesizePCM(ctx, chunk, voice, config)
+			}
+			select {
+			case results <- result:
+			case <-ctx.Done():
+				return
+			}
+			if result.err != nil {
+				return
+			}
+		}
+	}()
+
+	writer := newOpusFrameWriter(encoder, emit)
+	directFrames := 0
+	for result := range results {
+		if result.err != nil {
+			return result.err
 		}
-		if IsFatalError(err) {
-			return nil, fmt.Errorf("TTS synthesis failed (fatal): %w", err)
+		if result.frames != nil {
+			// Encoded audio must not overtake PCM still waiting in the encoder
+			if err := writer.Flush(); err != nil {
+				return err
+			}
+			for _, frame := range result.frames {
+				if err := emit(frame); err != nil {
+					return err
+				}
+			}
+			directFrames += len(result.frames)
+			continue
 		}
-		return nil, fmt.Errorf("TTS synthesis failed: %w", err)
+		if err := writer.Write(result.pcm); err != nil {
+			return err
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		return err
 	}
 
-	log.Printf("[DEBUG] Google TTS returned %d bytes of audio data for text: %s", len(resp.AudioContent), text)
+	log.Printf("[DEBUG] Streamed %d Opus frames for %d characters in %d chunk(s)", writer.Frames()+directFrames, len(text), len(chunks))
+	return nil
+}
+
+// synthesizePCM synthesizes text with Google Cloud TTS and returns 48kHz stereo 16-bit PCM
+func (g *GoogleTTSManager) synthesizePCM(ctx context.Context, text, voice string, config TTSConfig) ([]byte, error) {
+	// Use 24kHz (widely supported) then resample to 48kHz
+	req := newSynthesisRequest(text, g.resolveVoice(voice, config), config, texttospeechpb.AudioEncoding_LINEAR16, 24000)
+	resp, err := g.synthesize(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("[DEBUG] Google TTS returned %d bytes of audio data for %d characters of text", len(resp.AudioContent), len(text))
 	log.Printf("[DEBUG] TTS Request config - SampleRate: %d, Channels: %d, Encoding: %s",
 		req.AudioConfig.SampleRateHertz,
 		2, // We set channels to 2 in the config
@@ -170,18 +460,208 @@ func (g *GoogleTTSManager) ConvertToSpeech(text, voice string, config TTSConfig)
 	}
 
 	// Convert mono to stereo if needed, then resample to 48kHz stereo
-	processedAudio := g.processAudioForDiscord(audioContent, actualSampleRate, actualChannels)
+	processedAudio := g.processAudioForDiscord(audioContent, actualSampleRate, actualChannels, config)
 	log.Printf("[DEBUG] Processed audio: %d bytes -> %d bytes (%dHz %dch -> 48kHz 2ch)",
 		len(audioContent), len(processedAudio), actualSampleRate, actualChannels)
 
-	// Convert audio to Discord-compatible format
-	audioData, err := g.convertToDiscordFormat(processedAudio, config.Format)
+	return processedAudio, nil
+}
+
+// synthesizeOpusFrames requests Ogg Opus at 48kHz and returns its packets as Discord-ready
+// 20ms Opus frames, skipping the resample and Opus encode steps of the PCM path. The
+// second return value is false when the voice cannot be used this way; the voice is then
+// remembered and later requests go straight to the PCM path.
+func (g *GoogleTTSManager) synthesizeOpusFrames(ctx context.Context, text, voice string, config TTSConfig) ([][]byte, bool, error) {
+	if g.ttsClient() == nil {
+		return nil, true, ErrTTSEngineUnavailable
+	}
+
+	// Loudness is leveled on PCM, which Ogg Opus frames skip
+	selectedVoice := g.resolveVoice(voice, config)
+	if config.Loudness != 0 || !g.supportsOggOpus(selectedVoice) {
+		return nil, false, nil
+	}
+
+	req := newSynthesisRequest(text, selectedVoice, config, texttospeechpb.AudioEncoding_OGG_OPUS, discordSampleRate)
+	resp, err := g.synthesize(ctx, req)
+	if err != nil {
+		// Voices that reject the encoding fail with InvalidArgument
+		if status.Code(err) == codes.InvalidArgument {
+			g.markPCMOnly(selectedVoice, err)
+			return nil, false, nil
+		}
+		return nil, true, err
+	}
+
+	frames, err := demuxOggOpus(resp.AudioContent)
+	if err != nil {
+		g.markPCMOnly(selectedVoice, err)
+		return nil, false, nil
+	}
+
+	// Discord sends one frame every 20ms, so other packet durations would play at the wrong speed
+	for i, frame := range frames {
+		if samples := opusPacketSamples(frame); samples != opusFrameSamples {
+			g.markPCMOnly(selectedVoice, fmt.Errorf("packet %d is %d samples, want %d", i, samples, opusFrameSamples))
+			return nil, false, nil
+		}
+	}
+
+	log.Printf("[DEBUG] Google TTS returned %d Ogg Opus frames for %d characters of text", len(frames), len(text))
+	return frames, true, nil
+}
+
+// supportsOggOpus reports whether a voice can be synthesized straight to Ogg Opus
+func (g *GoogleTTSManager) supportsOggOpus(voice string) bool {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return !g.pcmOnlyVoices[voice]
+}
+
+// markPCMOnly records that a voice must use the LINEAR16 path
+func (g *GoogleTTSManager) markPCMOnly(voice string, reason error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.pcmOnlyVoices == nil {
+		g.pcmOnlyVoices = make(map[string]bool)
+	}
+	g.pcmOnlyVoices[voice] = true
+	log.Printf("Voice %s does not support direct Ogg Opus synthesis, using PCM: %v", voice, reason)
+}
+
+// resolveVoice returns the voice to synthesize with: the requested voice, the configured
+// voice or the default voice
+func (g *GoogleTTSManager) resolveVoice(voice string, config TTSConfig) string {
+	if voice != "" {
+		return voice
+	}
+	if config.Voice != "" {
+		return config.Voice
+	}
+	return DefaultVoice
+}
+
+// synthesize sends a synthesis request to Google Cloud TTS. Each attempt gets the
+// synthesis timeout, and attempts that fail with a transient status are retried with
+// jittered exponential backoff until ctx is cancelled.
+func (g *GoogleTTSManager) synthesize(ctx context.Context, req *texttospeechpb.SynthesizeSpeechRequest) (*texttospeechpb.SynthesizeSpeechResponse, error) {
+	// Check if we have a valid client
+	client := g.ttsClient()
+	if client == nil {
+		return nil, ErrTTSEngineUnavailable
+	}
+
+	g.mu.RLock()
+	timeout, retries := g.synthesisTimeout, g.retries
+	g.mu.RUnlock()
+	if timeout <= 0 {
+		timeout = DefaultSynthesisTimeout
+	}
+
+	var resp *texttospeechpb.SynthesizeSpeechResponse
+	attempts := 0
+	err := retries.do(ctx, func(ctx context.Context) error {
+		attempts++
+		attemptCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		// Retries are ours, so the client's own retries are turned off
+		var err error
+		resp, err = client.SynthesizeSpeech(attemptCtx, req, gax.WithRetry(nil))
+		if err != nil && isRetryableCode(err) && ctx.Err() == nil {
+			log.Printf("TTS synthesis attempt %d failed: %v", attempts, err)
+		}
+		return err
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("TTS synthesis cancelled: %w", ctx.Err())
+		}
+		return nil, fmt.Errorf("TTS synthesis failed after %d attempt(s): %w", attempts, err)
+	}
+
+	return resp, nil
+}
+
+// newSynthesisRequest builds a Google Cloud TTS request for text in the given encoding
+func newSynthesisRequest(text, voice string, config TTSConfig, encoding texttospeechpb.AudioEncoding, sampleRate int32) *texttospeechpb.SynthesizeSpeechRequest {
+	// Validate and set speed
+	speed := config.Speed
+	if speed < MinTTSSpeed || speed > MaxTTSSpeed {
+		speed = DefaultTTSSpeed
+	}
+
+	// Validate and set volume
+	volume := config.Volume
+	if volume < MinTTSVolume || volume > MaxTTSVolume {
+		volume = DefaultTTSVolume
+	}
+
+	// Pitch and style are dropped for voices that reject them
+	var pitch float32
+	if SupportsPitch(voice) && config.Pitch >= MinTTSPitch && config.Pitch <= MaxTTSPitch {
+		pitch = config.Pitch
+	}
+
+	audioConfig := &texttospeechpb.AudioConfig{
+		AudioEncoding:   encoding,
+		SpeakingRate:    float64(speed),
+		Pitch:           float64(pitch),
+		VolumeGainDb:    volumeToDB(volume),
+		SampleRateHertz: sampleRate,
+	}
+	if slices.Contains(EffectsProfiles, config.EffectsProfile) {
+		audioConfig.EffectsProfileId = []string{config.EffectsProfile}
+	}
+
+	input := &texttospeechpb.SynthesisInput{
+		InputSource: &texttospeechpb.SynthesisInput_Text{Text: text},
+	}
+	if SupportsStyle(voice) && slices.Contains(VoiceStyles, config.Style) {
+		input.InputSource = &texttospeechpb.SynthesisInput_Ssml{Ssml: styledSSML(text, config.Style)}
+	}
+
+	// Parse voice ID to extract language and name
+	languageCode, voiceName := parseVoiceID(voice)
+
+	return &texttospeechpb.SynthesizeSpeechRequest{
+		Input: input,
+		Voice: &texttospeechpb.VoiceSelectionParams{
+			LanguageCode: languageCode,
+			Name:         voiceName,
+		},
+		AudioConfig: audioConfig,
+	}
+}
+
+// encoderPools returns the Opus encoder pools, creating them on first use so managers
+// built without NewGoogleTTSManager still work
+func (g *GoogleTTSManager) encoderPools() (dca, raw *OpusEncoderPool) {
+	g.poolsOnce.Do(func() {
+		g.dcaEncoders = NewOpusEncoderPool(dcaBitrate, DefaultOpusPoolSize)
+		g.opusEncoders = NewOpusEncoderPool(rawOpusBitrate, DefaultOpusPoolSize)
+	})
+	return g.dcaEncoders, g.opusEncoders
+}
+
+// EncodeWAV converts a 16-bit PCM WAV file into DCA audio using the same
+// resampling and Opus encoding path as synthesized speech
+func (g *GoogleTTSManager) EncodeWAV(wavData []byte) ([]byte, error) {
+	audio, err := parseWAV(wavData)
+	if err != nil {
+		return nil, err
+	}
+
+	processedAudio := g.processAudioForDiscord(audio.pcm, audio.sampleRate, audio.channels, TTSConfig{})
+
+	dcaData, err := g.convertToDCA(processedAudio)
 	if err != nil {
 		return nil, fmt.Errorf("audio format conversion failed: %w", err)
 	}
 
-	log.Printf("[DEBUG] Audio conversion completed: %d bytes input -> %d bytes output (format: %s)", len(resp.AudioContent), len(audioData), config.Format)
-	return audioData, nil
+	return dcaData, nil
 }
 
 // ProcessMessageQueue processes queued messages for a guild
@@ -213,7 +693,7 @@ func (g *GoogleTTSManager) ProcessMessageQueue(guildID string) error {
 		}
 
 		// Check if we have a valid client before attempting conversion
-		if g.client == nil {
+		if g.ttsClient() == nil {
 			log.Printf("TTS client not available for guild %s, skipping message", guildID)
 			continue // Skip this message and continue with next
 		}
@@ -260,14 +740,15 @@ func (g *GoogleTTSManager) SetVoiceConfig(guildID string, config TTSConfig) erro
 // GetSupportedVoices returns a list of supported TTS voices
 func (g *GoogleTTSManager) GetSupportedVoices() []Voice {
 	// Return default voices if no client is available
-	if g.client == nil {
+	client := g.ttsClient()
+	if client == nil {
 		return getDefaultVoices()
 	}
 
 	ctx := context.Background()
 	req := &texttospeechpb.ListVoicesRequest{}
 
-	resp, err := g.client.ListVoices(ctx, req)
+	resp, err := client.ListVoices(ctx, req)
 	if err != nil {
 		log.Printf("Failed to list voices: %v", err)
 		return getDefaultVoices()
@@ -295,10 +776,11 @@ func (g *GoogleTTSManager) StartHealthCheck() {
 	}
 }
 
-// Close closes the TTS client
+// Close stops watching the credentials and closes the TTS client
 func (g *GoogleTTSManager) Close() error {
-	if g.client != nil {
-		return g.client.Close()
+	g.StopCredentialWatch()
+	if client := g.ttsClient(); client != nil {
+		return client.Close()
 	}
 	return nil
 }
@@ -337,11 +819,16 @@ func (g *GoogleTTSManager) validateTTSConfig(config TTSConfig) error {
 		return fmt.Errorf("unsupported audio format: %s", config.Format)
 	}
 
-	return nil
+	return validateVoiceOptions(config)
 }
 
-// convertToDiscordFormat converts audio to Discord-compatible format
+// convertToDiscordFormat converts audio to Discord-compatible format. DCA and raw Opus
+// need an Opus backend: libopus, or ffmpeg in builds without cgo.
 func (g *GoogleTTSManager) convertToDiscordFormat(audioData []byte, format AudioFormat) ([]byte, error) {
+	if (format == AudioFormatDCA || format == AudioFormatOpus) && g.opusEncoding() == opusBackendNone {
+		return nil, fmt.Errorf("cannot encode %s audio: %w", format, ErrOpusUnavailable)
+	}
+
 	switch format {
 	case AudioFormatDCA:
 		return g.convertToDCA(audioData)
@@ -354,258 +841,114 @@ func (g *GoogleTTSManager) convertToDiscordFormat(audioData []byte, format Audio
 	}
 }
 
-// convertToDCA converts PCM audio to DCA format using native Opus encoding
+// convertToDCA converts PCM audio to DCA format using the manager's Opus backend
 func (g *GoogleTTSManager) convertToDCA(pcmData []byte) ([]byte, error) {
-	log.Printf("[DEBUG] Converting PCM to DCA format using native Opus: %d bytes", len(pcmData))
-
-	// Discord Opus specifications
-	const (
-		sampleRate      = 48000 // 48kHz
-		channels        = 2     // Stereo
-		bitrate         = 64000 // 64kbps
-		frameDurationMs = 20    // 20ms frames
-		application     = opus.AppAudio
-	)
-
-	// Calculate frame size in samples (per channel)
-	frameSize := (sampleRate * frameDurationMs) / 1000 // 960 samples per channel
+	log.Printf("[DEBUG] Converting PCM to DCA format using %s Opus: %d bytes", g.opusEncoding(), len(pcmData))
 
-	// Create Opus encoder
-	encoder, err := opus.NewEncoder(sampleRate, channels, application)
+	dcaEncoders, _ := g.encoderPools()
+	frames, err := g.encodeOpus(pcmData, dcaEncoders)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create Opus encoder: %w", err)
+		return nil, err
 	}
 
-	// Set encoding parameters for Discord compatibility
-	if err := encoder.SetBitrate(bitrate); err != nil {
-		return nil, fmt.Errorf("failed to set bitrate: %w", err)
-	}
-
-	// Convert byte data to int16 samples
-	if len(pcmData)%2 != 0 {
-		return nil, fmt.Errorf("PCM data length must be even (16-bit samples)")
-	}
-
-	samples := make([]int16, len(pcmData)/2)
-	for i := 0; i < len(samples); i++ {
-		// Convert little-endian bytes to int16
-		samples[i] = int16(pcmData[i*2]) | int16(pcmData[i*2+1])<<8
-	}
-
-	log.Printf("[DEBUG] Converted %d bytes to %d samples for Opus encoding", len(pcmData), len(samples))
-
-	// Process samples in frames and encode to DCA
 	var dcaBuffer bytes.Buffer
-	frameCount := 0
-	samplesPerFrame := frameSize * channels // Total samples per frame (both channels)
-
-	for offset := 0; offset < len(samples); offset += samplesPerFrame {
-		end := offset + samplesPerFrame
-		if end > len(samples) {
-			// Pad the last frame with silence
-			lastFrame := make([]int16, samplesPerFrame)
-			copy(lastFrame, samples[offset:])
-			// The rest is already zero (silence)
-			samples = append(samples[:offset], lastFrame...)
-			end = offset + samplesPerFrame
+	for _, frame := range frames {
+		if err := writeDCAFrame(&dcaBuffer, frame); err != nil {
+			return nil, err
 		}
-
-		frame := samples[offset:end]
-
-		// Encode frame to Opus
-		opusFrame := make([]byte, 4000) // Max Opus frame size
-		n, err := encoder.Encode(frame, opusFrame)
-		if err != nil {
-			return nil, fmt.Errorf("failed to encode Opus frame %d: %w", frameCount, err)
-		}
-
-		opusFrame = opusFrame[:n] // Trim to actual size
-
-		// Write DCA frame header (2 bytes: frame length as int16 little-endian)
-		frameLen := int16(len(opusFrame))
-		if err := binary.Write(&dcaBuffer, binary.LittleEndian, frameLen); err != nil {
-			return nil, fmt.Errorf("failed to write DCA frame header: %w", err)
-		}
-
-		// Write Opus frame data
-		if _, err := dcaBuffer.Write(opusFrame); err != nil {
-			return nil, fmt.Errorf("failed to write DCA frame data: %w", err)
-		}
-
-		frameCount++
 	}
 
 	totalSize := dcaBuffer.Len()
 	avgFrameSize := 0
-	if frameCount > 0 {
-		avgFrameSize = totalSize / frameCount
+	if len(frames) > 0 {
+		avgFrameSize = totalSize / len(frames)
 	}
 
-	log.Printf("[DEBUG] Native Opus encoding completed: %d frames, %d bytes total (avg %d bytes/frame)",
-		frameCount, totalSize, avgFrameSize)
+	log.Printf("[DEBUG] Opus encoding completed: %d frames, %d bytes total (avg %d bytes/frame)",
+		len(frames), totalSize, avgFrameSize)
 
 	return dcaBuffer.Bytes(), nil
 }
 
-// convertToRawOpus converts PCM audio to raw Opus format using native Opus encoding
+// convertToRawOpus converts PCM audio to raw Opus format using the manager's Opus backend
 func (g *GoogleTTSManager) convertToRawOpus(pcmData []byte) ([]byte, error) {
-	log.Printf("[DEBUG] Converting PCM to raw Opus format using native library: %d bytes", len(pcmData))
+	log.Printf("[DEBUG] Converting PCM to raw Opus format using %s Opus: %d bytes", g.opusEncoding(), len(pcmData))
 
-	// Discord Opus specifications
-	const (
-		sampleRate      = 48000  // 48kHz
-		channels        = 2      // Stereo
-		bitrate         = 128000 // 128kbps for higher quality raw Opus
-		frameDurationMs = 20     // 20ms frames
-		application     = opus.AppAudio
-	)
-
-	// Calculate frame size in samples (per channel)
-	frameSize := (sampleRate * frameDurationMs) / 1000 // 960 samples per channel
-
-	// Create Opus encoder
-	encoder, err := opus.NewEncoder(sampleRate, channels, application)
+	_, opusEncoders := g.encoderPools()
+	frames, err := g.encodeOpus(pcmData, opusEncoders)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create Opus encoder: %w", err)
+		return nil, err
 	}
 
-	// Set encoding parameters
-	if err := encoder.SetBitrate(bitrate); err != nil {
-		return nil, fmt.Errorf("failed to set bitrate: %w", err)
-	}
+	// Append raw Opus data (no DCA headers for raw format)
+	opusData := bytes.Join(frames, nil)
+	log.Printf("[DEBUG] Raw Opus encoding completed: %d bytes input -> %d bytes output", len(pcmData), len(opusData))
 
-	// Convert byte data to int16 samples
-	if len(pcmData)%2 != 0 {
-		return nil, fmt.Errorf("PCM data length must be even (16-bit samples)")
-	}
+	return opusData, nil
+}
 
-	samples := make([]int16, len(pcmData)/2)
-	for i := 0; i < len(samples); i++ {
-		// Convert little-endian bytes to int16
-		samples[i] = int16(pcmData[i*2]) | int16(pcmData[i*2+1])<<8
+// writeDCAFrame appends an Opus frame to a DCA stream
+// DCA format: [2 bytes frame length][N bytes Opus data]
+func writeDCAFrame(dcaBuffer *bytes.Buffer, frame []byte) error {
+	if err := binary.Write(dcaBuffer, binary.LittleEndian, int16(len(frame))); err != nil {
+		return fmt.Errorf("failed to write DCA frame header: %w", err)
 	}
 
-	// Encode all samples to raw Opus (not DCA format)
-	var opusBuffer bytes.Buffer
-	samplesPerFrame := frameSize * channels // Total samples per frame (both channels)
-
-	for offset := 0; offset < len(samples); offset += samplesPerFrame {
-		end := offset + samplesPerFrame
-		if end > len(samples) {
-			// Pad the last frame with silence
-			lastFrame := make([]int16, samplesPerFrame)
-			copy(lastFrame, samples[offset:])
-			samples = append(samples[:offset], lastFrame...)
-			end = offset + samplesPerFrame
-		}
-
-		frame := samples[offset:end]
-
-		// Encode frame to Opus
-		opusFrame := make([]byte, 4000) // Max Opus frame size
-		n, err := encoder.Encode(frame, opusFrame)
-		if err != nil {
-			return nil, fmt.Errorf("failed to encode raw Opus frame: %w", err)
-		}
-
-		// Append raw Opus data (no DCA headers for raw format)
-		opusBuffer.Write(opusFrame[:n])
+	if _, err := dcaBuffer.Write(frame); err != nil {
+		return fmt.Errorf("failed to write DCA frame data: %w", err)
 	}
 
-	opusData := opusBuffer.Bytes()
-	log.Printf("[DEBUG] Native raw Opus encoding completed: %d bytes input -> %d bytes output", len(pcmData), len(opusData))
-
-	return opusData, nil
+	return nil
 }
 
-// parseOpusStreamToDCA parses raw Opus stream into proper DCA format
-// This respects Opus frame boundaries for better audio quality
-func (g *GoogleTTSManager) parseOpusStreamToDCA(opusData []byte) ([]byte, error) {
-	var dcaBuffer bytes.Buffer
-	var frameCount int
-
-	// Parse Opus frames from the raw stream
-	// Opus frames start with a TOC (Table of Contents) byte that indicates frame structure
-	offset := 0
-	for offset < len(opusData) {
-		if offset >= len(opusData) {
-			break
+// streamChunkLength is the maximum length of the text chunks synthesized while streaming
+const streamChunkLength = 400
+
+// splitSpeechChunks splits text at sentence boundaries for streaming. The first sentence
+// is synthesized on its own so it can start playing quickly; later sentences are grouped
+// into chunks of up to streamChunkLength characters to limit the number of API calls.
+func splitSpeechChunks(text string) []string {
+	var sentences []string
+	start := 0
+	for i, r := range text {
+		if r != '.' && r != '!' && r != '?' && r != '\n' {
+			continue
 		}
-
-		// Find the next Opus frame by looking for frame patterns
-		// For simplicity, we'll use a heuristic based on the working airhorn
-		frameSize := g.estimateOpusFrameSize(opusData[offset:])
-		if frameSize <= 0 || offset+frameSize > len(opusData) {
-			// If we can't determine frame size, use remaining data
-			frameSize = len(opusData) - offset
+		// Only split where the punctuation is followed by whitespace or the end of text
+		next := i + 1
+		if next < len(text) && text[next] != ' ' && text[next] != '\n' {
+			continue
 		}
-
-		frameData := opusData[offset : offset+frameSize]
-
-		// Write DCA frame header (2 bytes: frame length as int16 little-endian)
-		frameLen := int16(len(frameData))
-		if err := binary.Write(&dcaBuffer, binary.LittleEndian, frameLen); err != nil {
-			return nil, fmt.Errorf("failed to write DCA frame header: %w", err)
-		}
-
-		// Write Opus frame data
-		if _, err := dcaBuffer.Write(frameData); err != nil {
-			return nil, fmt.Errorf("failed to write DCA frame data: %w", err)
-		}
-
-		offset += frameSize
-		frameCount++
-
-		// Safety check to prevent infinite loops
-		if frameCount > 1000 {
-			log.Printf("[DEBUG] Warning: Too many frames, stopping at %d", frameCount)
-			break
+		if sentence := strings.TrimSpace(text[start:next]); sentence != "" {
+			sentences = append(sentences, sentence)
 		}
+		start = next
 	}
-
-	log.Printf("[DEBUG] Parsed %d bytes of Opus data into %d DCA frames", len(opusData), frameCount)
-	return dcaBuffer.Bytes(), nil
-}
-
-// estimateOpusFrameSize estimates the size of an Opus frame based on patterns
-// This is a heuristic approach to avoid complex Opus parsing
-func (g *GoogleTTSManager) estimateOpusFrameSize(data []byte) int {
-	if len(data) == 0 {
-		return 0
+	if rest := strings.TrimSpace(text[start:]); rest != "" {
+		sentences = append(sentences, rest)
 	}
 
-	// For 20ms frames at 64kbps, typical Opus frame sizes are:
-	// - 160-200 bytes for speech
-	// - 120-180 bytes for audio
-	// The airhorn averages ~163 bytes per frame
-
-	// Use a simple heuristic: look for the next potential frame start
-	// or use a reasonable default size
-	const defaultFrameSize = 160
-	const maxFrameSize = 300
-
-	if len(data) <= defaultFrameSize {
-		return len(data)
+	if len(sentences) <= 1 {
+		return []string{text}
 	}
 
-	// Look for patterns that might indicate frame boundaries
-	// This is a simplified approach - in practice, proper Opus parsing would be better
-	for i := defaultFrameSize; i < len(data) && i < maxFrameSize; i++ {
-		// Look for potential TOC byte patterns (simplified heuristic)
-		// All bytes are valid (0x00-0xFF by definition)
-		if true {
-			// This could be a frame boundary, but it's just a heuristic
-			if i > defaultFrameSize/2 { // Ensure reasonable frame size
-				return i
-			}
+	chunks := []string{sentences[0]}
+	current := ""
+	for _, sentence := range sentences[1:] {
+		if current != "" && len(current)+1+len(sentence) > streamChunkLength {
+			chunks = append(chunks, current)
+			current = ""
+		}
+		if current != "" {
+			current += " "
 		}
+		current += sentence
 	}
-
-	// Default to a reasonable frame size
-	if len(data) > defaultFrameSize {
-		return defaultFrameSize
+	if current != "" {
+		chunks = append(chunks, current)
 	}
-	return len(data)
+
+	return chunks
 }
 
 // parseVoiceID parses a voice ID to extract language code and voice name
@@ -615,7 +958,7 @@ func parseVoiceID(voiceID string) (languageCode, voiceName string) {
 	voiceName = voiceID
 
 	// Parse voice ID format: "en-US-Standard-A" or "en-US-Wavenet-A"
-	if len(voiceID) >= 5 {
+	if len(voiceID) > 5 {
 		if voiceID[2] == '-' && voiceID[5] == '-' {
 			languageCode = voiceID[:5] // Extract "en-US"
 		}
@@ -681,8 +1024,8 @@ func (r *bytesReader) Read(p []byte) (n int, err error) {
 }
 
 // processAudioForDiscord converts audio to Discord format (48kHz stereo)
-// Handles mono->stereo conversion and sample rate conversion
-func (g *GoogleTTSManager) processAudioForDiscord(pcmData []byte, fromRate, fromChannels int) []byte {
+// Handles mono->stereo conversion, sample rate conversion and loudness normalization
+func (g *GoogleTTSManager) processAudioForDiscord(pcmData []byte, fromRate, fromChannels int, config TTSConfig) []byte {
 	const (
 		targetRate     = 48000
 		targetChannels = 2
@@ -726,6 +1069,11 @@ func (g *GoogleTTSManager) processAudioForDiscord(pcmData []byte, fromRate, from
 		finalSamples = stereoSamples
 	}
 
+	// Step 3: Level the speech to the guild's loudness target, if it set one
+	if config.Loudness != 0 {
+		normalizeLoudness(finalSamples, config.Loudness, config.Volume)
+	}
+
 	// Convert back to bytes
 	outputData := make([]byte, len(finalSamples)*2)
 	for i, sample := range finalSamples {
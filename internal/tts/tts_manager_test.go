@@ -1,12 +1,26 @@
 package tts
 
 import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"cloud.google.com/go/texttospeech/apiv1/texttospeechpb"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"mock-tts/mocktts"
 )
 
 func TestNewGoogleTTSManager(t *testing.T) {
@@ -604,3 +618,420 @@ func TestGoogleTTSManager_ConvertToSpeech_Integration(t *testing.T) {
 		})
 	}
 }
+
+// The mock TTS server speaks the REST API without credentials, so these run everywhere
+func newMockTTSManager(t *testing.T) (*GoogleTTSManager, *mocktts.Server) {
+	t.Helper()
+
+	server := mocktts.NewServer()
+	httpServer := httptest.NewServer(server.Handler())
+	t.Cleanup(httpServer.Close)
+
+	manager, err := NewGoogleTTSManagerWithEndpoint(&MockMessageQueue{}, "", httpServer.URL)
+	require.NoError(t, err)
+	t.Cleanup(func() { manager.Close() })
+
+	return manager, server
+}
+
+func TestGoogleTTSManager_DegradedReconnect(t *testing.T) {
+	httpServer := httptest.NewServer(mocktts.NewServer().Handler())
+	defer httpServer.Close()
+
+	reason := errors.New("no credentials")
+	manager := NewDegradedGoogleTTSManager(&MockMessageQueue{}, CredentialSource{}, httpServer.URL, reason)
+	t.Cleanup(func() { manager.Close() })
+
+	// Nothing is synthesized until the engine is reachable
+	assert.ErrorIs(t, manager.EngineError(), reason)
+	_, err := manager.ConvertToSpeech("hello", "", TTSConfig{Speed: 1.0, Volume: 1.0, Format: AudioFormatPCM})
+	assert.ErrorIs(t, err, ErrTTSEngineUnavailable)
+
+	require.NoError(t, manager.Reconnect())
+	assert.NoError(t, manager.EngineError())
+	_, err = manager.ConvertToSpeech("hello", "", TTSConfig{Speed: 1.0, Volume: 1.0, Format: AudioFormatPCM})
+	assert.NoError(t, err)
+}
+
+func TestGoogleTTSManager_DegradedReconnectFails(t *testing.T) {
+	httpServer := httptest.NewServer(mocktts.NewServer().Handler())
+	httpServer.Close() // Unreachable endpoint
+
+	manager := NewDegradedGoogleTTSManager(&MockMessageQueue{}, CredentialSource{}, httpServer.URL, errors.New("no credentials"))
+
+	err := manager.Reconnect()
+	assert.Error(t, err)
+	assert.Equal(t, err, manager.EngineError())
+	assert.Nil(t, manager.ttsClient())
+}
+
+func TestCheckTTSCredentials(t *testing.T) {
+	httpServer := httptest.NewServer(mocktts.NewServer().Handler())
+	assert.NoError(t, CheckTTSCredentials(CredentialSource{}, httpServer.URL))
+
+	httpServer.Close() // Unreachable endpoint
+	assert.Error(t, CheckTTSCredentials(CredentialSource{}, httpServer.URL))
+}
+
+func TestGoogleTTSManager_MockEndpoint_ConvertToSpeech(t *testing.T) {
+	manager, server := newMockTTSManager(t)
+
+	config := TTSConfig{Voice: "en-US-Standard-C", Speed: 1.0, Volume: 1.0, Format: AudioFormatPCM}
+	audio, err := manager.ConvertToSpeech("Hello from the mock", "", config)
+	require.NoError(t, err)
+
+	// The mock tone is 24kHz mono and is upsampled to 48kHz stereo
+	pcm := mocktts.GeneratePCM("Hello from the mock", "en-US-Standard-C", 24000)
+	assert.Equal(t, len(pcm)*4, len(audio))
+
+	requests := server.Requests()
+	require.Len(t, requests, 1)
+	assert.Equal(t, "Hello from the mock", requests[0].Text)
+	assert.Equal(t, "en-US-Standard-C", requests[0].Voice)
+	assert.Equal(t, mocktts.EncodingLinear16, requests[0].Encoding)
+}
+
+func TestGoogleTTSManager_MockEndpoint_OggOpusFallback(t *testing.T) {
+	requireOpusEncoding(t)
+	manager, server := newMockTTSManager(t)
+
+	// The mock rejects Ogg Opus, so the voice falls back to LINEAR16 and stays there
+	config := TTSConfig{Voice: "en-US-Standard-A", Speed: 1.0, Volume: 1.0, Format: AudioFormatDCA}
+	audio, err := manager.ConvertToSpeech("Hello", "", config)
+	require.NoError(t, err)
+	assert.NotEmpty(t, audio)
+
+	requests := server.Requests()
+	require.Len(t, requests, 2)
+	assert.Equal(t, mocktts.EncodingOggOpus, requests[0].Encoding)
+	assert.Equal(t, mocktts.EncodingLinear16, requests[1].Encoding)
+	assert.False(t, manager.supportsOggOpus("en-US-Standard-A"))
+
+	server.Reset()
+	_, err = manager.ConvertToSpeech("Again", "", config)
+	require.NoError(t, err)
+	require.Len(t, server.Requests(), 1)
+	assert.Equal(t, mocktts.EncodingLinear16, server.Requests()[0].Encoding)
+}
+
+// newFlakyMockTTSManager serves synthesis requests from the mock TTS server after fail
+// has handled them. fail returns false to pass a request through. The manager retries
+// without waiting.
+func newFlakyMockTTSManager(t *testing.T, fail func(w http.ResponseWriter, request int32) bool) (*GoogleTTSManager, *atomic.Int32) {
+	t.Helper()
+
+	var requests atomic.Int32
+	mock := mocktts.NewServer().Handler()
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, ":synthesize") && fail(w, requests.Add(1)) {
+			return
+		}
+		mock.ServeHTTP(w, r)
+	}))
+	t.Cleanup(httpServer.Close)
+
+	manager, err := NewGoogleTTSManagerWithEndpoint(&MockMessageQueue{}, "", httpServer.URL)
+	require.NoError(t, err)
+	t.Cleanup(func() { manager.Close() })
+	manager.retries = retryPolicy{maxAttempts: 3}
+
+	return manager, &requests
+}
+
+func TestGoogleTTSManager_MockEndpoint_RetriesTransientFailures(t *testing.T) {
+	manager, requests := newFlakyMockTTSManager(t, func(w http.ResponseWriter, request int32) bool {
+		if request > 2 {
+			return false
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(`{"error": {"code": 503, "message": "backend unavailable", "status": "UNAVAILABLE"}}`))
+		return true
+	})
+
+	config := TTSConfig{Voice: "en-US-Standard-C", Speed: 1.0, Volume: 1.0, Format: AudioFormatPCM}
+	audio, err := manager.ConvertToSpeech("Hello", "", config)
+	require.NoError(t, err)
+	assert.NotEmpty(t, audio)
+	assert.Equal(t, int32(3), requests.Load())
+}
+
+func TestGoogleTTSManager_MockEndpoint_DoesNotRetryPermanentFailures(t *testing.T) {
+	manager, requests := newFlakyMockTTSManager(t, func(w http.ResponseWriter, request int32) bool {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"error": {"code": 403, "message": "caller does not have permission", "status": "PERMISSION_DENIED"}}`))
+		return true
+	})
+
+	config := TTSConfig{Voice: "en-US-Standard-C", Speed: 1.0, Volume: 1.0, Format: AudioFormatPCM}
+	_, err := manager.ConvertToSpeech("Hello", "", config)
+	require.Error(t, err)
+	assert.True(t, IsFatalError(err))
+	assert.Equal(t, int32(1), requests.Load())
+}
+
+func TestGoogleTTSManager_MockEndpoint_SynthesisTimeout(t *testing.T) {
+	// The first request hangs until the client gives up on it
+	manager, requests := newFlakyMockTTSManager(t, func(w http.ResponseWriter, request int32) bool {
+		if request > 1 {
+			return false
+		}
+		time.Sleep(500 * time.Millisecond)
+		return true
+	})
+	manager.SetSynthesisTimeout(50 * time.Millisecond)
+
+	config := TTSConfig{Voice: "en-US-Standard-C", Speed: 1.0, Volume: 1.0, Format: AudioFormatPCM}
+	audio, err := manager.ConvertToSpeech("Hello", "", config)
+	require.NoError(t, err)
+	assert.NotEmpty(t, audio)
+	assert.Equal(t, int32(2), requests.Load())
+}
+
+func TestGoogleTTSManager_MockEndpoint_Cancelled(t *testing.T) {
+	manager, requests := newFlakyMockTTSManager(t, func(w http.ResponseWriter, request int32) bool {
+		return false
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	config := TTSConfig{Voice: "en-US-Standard-C", Speed: 1.0, Volume: 1.0, Format: AudioFormatPCM}
+	_, err := manager.ConvertToSpeechContext(ctx, "Hello", "", config)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.False(t, IsRetryableError(err))
+	assert.LessOrEqual(t, requests.Load(), int32(1))
+}
+
+func TestGoogleTTSManager_MockEndpoint_GetSupportedVoices(t *testing.T) {
+	manager, _ := newMockTTSManager(t)
+
+	voices := manager.GetSupportedVoices()
+	require.Len(t, voices, len(mocktts.DefaultVoices))
+	assert.Equal(t, "en-US-Standard-A", voices[0].ID)
+	assert.Equal(t, "en-US", voices[0].Language)
+	assert.Equal(t, "MALE", voices[0].Gender)
+}
+
+func TestSplitSpeechChunks(t *testing.T) {
+	// Short text is synthesized in one request
+	assert.Equal(t, []string{"alice says: hello there"}, splitSpeechChunks("alice says: hello there"))
+
+	// The first sentence is split off so it can start playing early
+	assert.Equal(t,
+		[]string{"alice says: Hi.", "How are you? I'm fine! Version 1.5 works"},
+		splitSpeechChunks("alice says: Hi. How are you? I'm fine!\nVersion 1.5 works"),
+	)
+
+	long := "First. " + strings.Repeat("Another sentence here. ", 40)
+	chunks := splitSpeechChunks(long)
+	assert.Equal(t, "First.", chunks[0])
+	assert.Greater(t, len(chunks), 2)
+	for _, chunk := range chunks {
+		assert.LessOrEqual(t, len(chunk), streamChunkLength)
+	}
+	assert.Equal(t, strings.Join(strings.Fields(long), " "), strings.Join(chunks, " "))
+}
+
+func TestGoogleTTSManager_StreamSpeechValidation(t *testing.T) {
+	manager := &GoogleTTSManager{}
+	emit := func(frame []byte) error { return nil }
+
+	assert.ErrorIs(t, manager.StreamSpeech(context.Background(), "", "", TTSConfig{}, emit), ErrEmptyText)
+	assert.ErrorIs(t, manager.StreamSpeech(context.Background(), strings.Repeat("a", MaxMessageLength+1), "", TTSConfig{}, emit), ErrTextTooLong)
+	assert.ErrorIs(t, manager.StreamSpeech(context.Background(), "hello", "", TTSConfig{}, emit), ErrTTSEngineUnavailable)
+}
+
+func TestGoogleTTSManager_ConvertToDCA(t *testing.T) {
+	requireNativeOpus(t)
+	manager := &GoogleTTSManager{}
+
+	// Two and a half frames of silence
+	dcaData, err := manager.convertToDCA(make([]byte, opusSamplesPerFrame*5))
+	assert.NoError(t, err)
+
+	frames, err := (&voiceManager{}).parseDCAFrames(dcaData)
+	assert.NoError(t, err)
+	assert.Len(t, frames, 3)
+}
+
+// goldenTone returns a 440Hz tone as 48kHz stereo PCM, the reference audio for checking
+// that what the bot plays can be decoded
+func goldenTone(duration time.Duration) []byte {
+	samples := int(duration * discordSampleRate / time.Second)
+	pcm := make([]byte, samples*discordChannels*2)
+	for i := 0; i < samples; i++ {
+		value := int16(16000 * math.Sin(2*math.Pi*440*float64(i)/discordSampleRate))
+		for channel := 0; channel < discordChannels; channel++ {
+			binary.LittleEndian.PutUint16(pcm[(i*discordChannels+channel)*2:], uint16(value))
+		}
+	}
+	return pcm
+}
+
+// requireDecodableDCA decodes every frame of a DCA stream the way Discord clients do and returns
+// the samples per channel it holds. Every frame must be a 20ms Opus packet.
+func requireDecodableDCA(t *testing.T, dcaData []byte) int {
+	t.Helper()
+	requireNativeOpus(t)
+
+	frames, err := parseDCAFrames(dcaData)
+	require.NoError(t, err)
+	require.NotEmpty(t, frames)
+
+	decoder, err := newOpusDecoder(discordSampleRate, discordChannels)
+	require.NoError(t, err)
+
+	pcm := make([]int16, 6*opusSamplesPerFrame) // Room for the longest Opus packet, 120ms
+	total := 0
+	for i, frame := range frames {
+		require.Equal(t, opusFrameSamples, opusPacketSamples(frame), "frame %d", i)
+		n, err := decoder.Decode(frame, pcm)
+		require.NoError(t, err, "frame %d", i)
+		require.Equal(t, opusFrameSamples, n, "frame %d", i)
+		total += n
+	}
+	return total
+}
+
+// opusPackets encodes PCM into Opus packets with a pooled encoder
+func opusPackets(t *testing.T, pcm []byte) [][]byte {
+	t.Helper()
+	requireNativeOpus(t)
+
+	pool := NewOpusEncoderPool(dcaBitrate, 1)
+	encoder, err := pool.Get()
+	require.NoError(t, err)
+	defer pool.Put(encoder)
+
+	var packets [][]byte
+	writer := newOpusFrameWriter(encoder, func(frame []byte) error {
+		packets = append(packets, append([]byte(nil), frame...))
+		return nil
+	})
+	require.NoError(t, writer.Write(pcm))
+	require.NoError(t, writer.Flush())
+	return packets
+}
+
+func TestGoogleTTSManager_ConvertToDCA_Decodes(t *testing.T) {
+	requireNativeOpus(t)
+	manager := &GoogleTTSManager{}
+
+	// A second of tone and a partial frame, which is padded with silence
+	dcaData, err := manager.convertToDCA(goldenTone(time.Second + 5*time.Millisecond))
+	require.NoError(t, err)
+	assert.Equal(t, 51*opusFrameSamples, requireDecodableDCA(t, dcaData))
+}
+
+func TestGoogleTTSManager_MockEndpoint_OggOpusDecodes(t *testing.T) {
+	// Ogg Opus requests get the golden tone; the rest go to the mock
+	stream := oggOpusStream(opusPackets(t, goldenTone(time.Second)))
+	mock := mocktts.NewServer()
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			AudioConfig struct {
+				AudioEncoding json.RawMessage `json:"audioEncoding"`
+			} `json:"audioConfig"`
+		}
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &request)
+		if encoding := string(request.AudioConfig.AudioEncoding); encoding != "3" && encoding != `"OGG_OPUS"` {
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			mock.Handler().ServeHTTP(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string][]byte{"audioContent": stream})
+	}))
+	defer httpServer.Close()
+
+	manager, err := NewGoogleTTSManagerWithEndpoint(&MockMessageQueue{}, "", httpServer.URL)
+	require.NoError(t, err)
+	defer manager.Close()
+
+	config := TTSConfig{Voice: "en-US-Wavenet-A", Speed: 1.0, Volume: 1.0, Format: AudioFormatDCA}
+	dcaData, err := manager.ConvertToSpeech("Hello", "", config)
+	require.NoError(t, err)
+
+	// The packets are played as Google encoded them, without falling back to LINEAR16
+	assert.Equal(t, 50*opusFrameSamples, requireDecodableDCA(t, dcaData))
+	assert.True(t, manager.supportsOggOpus("en-US-Wavenet-A"))
+	assert.Empty(t, mock.Requests())
+}
+
+func TestGoogleTTSManager_OggOpusVoiceSupport(t *testing.T) {
+	manager := &GoogleTTSManager{}
+
+	// Every voice is tried with Ogg Opus until it is known not to work
+	assert.True(t, manager.supportsOggOpus("en-US-Standard-A"))
+
+	manager.markPCMOnly("en-US-Standard-A", errors.New("unsupported encoding"))
+	assert.False(t, manager.supportsOggOpus("en-US-Standard-A"))
+	assert.True(t, manager.supportsOggOpus("en-US-Wavenet-A"))
+
+	// Without a client neither path can synthesize
+	_, _, err := manager.synthesizeOpusFrames(context.Background(), "hello", "en-US-Wavenet-A", TTSConfig{})
+	assert.ErrorIs(t, err, ErrTTSEngineUnavailable)
+}
+
+func TestNewSynthesisRequest(t *testing.T) {
+	manager := &GoogleTTSManager{}
+	assert.Equal(t, "en-GB-Standard-B", manager.resolveVoice("en-GB-Standard-B", TTSConfig{Voice: "en-US-Wavenet-A"}))
+	assert.Equal(t, "en-US-Wavenet-A", manager.resolveVoice("", TTSConfig{Voice: "en-US-Wavenet-A"}))
+	assert.Equal(t, DefaultVoice, manager.resolveVoice("", TTSConfig{}))
+
+	req := newSynthesisRequest("hello", "de-DE-Wavenet-B", TTSConfig{Speed: 10, Volume: 1.0}, texttospeechpb.AudioEncoding_OGG_OPUS, discordSampleRate)
+	assert.Equal(t, "hello", req.Input.GetText())
+	assert.Equal(t, "de-DE", req.Voice.LanguageCode)
+	assert.Equal(t, "de-DE-Wavenet-B", req.Voice.Name)
+	assert.Equal(t, texttospeechpb.AudioEncoding_OGG_OPUS, req.AudioConfig.AudioEncoding)
+	assert.Equal(t, int32(48000), req.AudioConfig.SampleRateHertz)
+	assert.Equal(t, float64(DefaultTTSSpeed), req.AudioConfig.SpeakingRate, "out of range speed falls back to the default")
+
+	assert.Zero(t, req.AudioConfig.Pitch)
+	assert.Empty(t, req.AudioConfig.EffectsProfileId)
+
+	styled := TTSConfig{Speed: 1.0, Volume: 1.0, Pitch: 4.5, EffectsProfile: "headphone-class-device", Style: "lively"}
+	req = newSynthesisRequest("fish & chips", "en-US-Neural2-J", styled, texttospeechpb.AudioEncoding_LINEAR16, discordSampleRate)
+	assert.Equal(t, 4.5, req.AudioConfig.Pitch)
+	assert.Equal(t, []string{"headphone-class-device"}, req.AudioConfig.EffectsProfileId)
+	assert.Equal(t, `<speak><google:style name="lively">fish &amp; chips</google:style></speak>`, req.Input.GetSsml())
+
+	// Voices without style or pitch support get plain text at the default pitch
+	req = newSynthesisRequest("hello", "en-US-Journey-F", styled, texttospeechpb.AudioEncoding_LINEAR16, discordSampleRate)
+	assert.Equal(t, "hello", req.Input.GetText())
+	assert.Zero(t, req.AudioConfig.Pitch)
+	assert.Equal(t, []string{"headphone-class-device"}, req.AudioConfig.EffectsProfileId)
+}
+
+func FuzzParseVoiceID(f *testing.F) {
+	for _, voice := range []string{"en-US-Standard-A", "de-DE-Wavenet-B", "en-US", "en", "", "xx-YY-", "é-é-é"} {
+		f.Add(voice)
+	}
+
+	f.Fuzz(func(t *testing.T, voiceID string) {
+		languageCode, voiceName := parseVoiceID(voiceID)
+		assert.Equal(t, voiceID, voiceName)
+		if languageCode != "en-US" {
+			assert.True(t, strings.HasPrefix(voiceID, languageCode+"-"), "language %q of voice %q", languageCode, voiceID)
+		}
+	})
+}
+
+func FuzzEncodeWAV(f *testing.F) {
+	log.SetOutput(io.Discard)
+	f.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	f.Add(encodeWAV(make([]byte, 480), 24000, 1))
+	f.Add(encodeWAV(make([]byte, 962), 44100, 2))
+	f.Add(encodeWAV([]byte{1}, 8000, 1))
+	f.Add([]byte("RIFF\x00\x00\x00\x00WAVEdata\x04\x00\x00\x00\x00\x00\x00\x00"))
+
+	manager := &GoogleTTSManager{}
+	f.Fuzz(func(t *testing.T, wavData []byte) {
+		// Malformed responses are errors, never panics
+		manager.EncodeWAV(wavData)
+	})
+}
@@ -1,13 +1,50 @@
 package tts
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
+	"unicode/utf8"
+
+	"darrot/internal/events"
+)
+
+// MetricTimeToFirstAudio is the latency before the latest streamed message started playing
+const MetricTimeToFirstAudio = "darrot_tts_time_to_first_audio_seconds"
+
+// MetricTTSErrors counts messages that failed, by stage and error kind
+const MetricTTSErrors = "darrot_tts_errors_total"
+
+// drainPollInterval is how often Drain checks whether the messages being spoken finished
+const drainPollInterval = 50 * time.Millisecond
+
+// Worker pool metric names
+const (
+	MetricGuildMessagesProcessed = "darrot_tts_guild_messages_processed_total"
+	MetricGuildProcessingSeconds = "darrot_tts_guild_processing_seconds_total"
+	MetricGuildWaitSeconds       = "darrot_tts_guild_wait_seconds"
+	MetricWorkersBusy            = "darrot_tts_workers_busy"
+)
+
+// Worker pool sizing
+const (
+	DefaultTTSWorkers = 4  // Guild messages synthesized and played at the same time
+	MaxTTSWorkers     = 64 // Upper bound for tts.workers
 )
 
+// streamFrameBuffer is the number of encoded frames buffered ahead of playback (1 second)
+const streamFrameBuffer = 50
+
+// errStreamingUnavailable means a message cannot be streamed and must be synthesized in full
+var errStreamingUnavailable = errors.New("streaming unavailable")
+
 // ttsProcessor handles the background processing pipeline for TTS conversion and playback
 type ttsProcessor struct {
 	ttsManager    TTSManager
@@ -19,18 +56,52 @@ type ttsProcessor struct {
 	// Error recovery
 	errorRecovery *ErrorRecoveryManager
 
+	// Optional cost controls and instrumentation
+	quotaService   TTSQuotaService
+	audioCache     *AudioCache
+	metrics        *Metrics
+	contentPolicy  *ContentPolicy
+	clipService    AudioClipService
+	moderation     ModerationService
+	normalizer     TextNormalizer
+	statsService   StatsService
+	transcripts    TranscriptService
+	eventBus       *events.Bus
+	textMirror     *TextMirror
+	skipNotes      *SkipNotes
+	audioOutputs   *AudioOutputs
+	features       *FeatureFlagService
+	voiceActivity  *VoiceActivity
+	pauseScheduler *PauseScheduler
+	listenerGate   *ListenerGate
+	queuePressure  *QueuePressurePolicy
+	pacing         *PacingPolicy
+
+	// Idle announcements and disconnects
+	channelService ChannelService
+	localizer      *Localizer
+
 	// Processing control
 	ctx    context.Context
 	cancel context.CancelFunc
 	wg     sync.WaitGroup
 
+	// Worker pool
+	workerCount int
+	jobs        chan guildJob
+	wake        chan struct{}
+	busyWorkers atomic.Int32
+	draining    atomic.Bool // No new messages are started during a graceful shutdown
+
+	// Queued messages synthesized ahead while the current one plays
+	lookaheadCount int
+
 	// Guild-specific processing state
 	guildProcessors map[string]*guildProcessor
 	mu              sync.RWMutex
 
 	// Configuration
 	processingInterval time.Duration
-	inactivityTimeout  time.Duration
 }
 
 // guildProcessor manages TTS processing for a specific guild
@@ -39,9 +110,21 @@ type guildProcessor struct {
 	isProcessing       bool
 	lastActivity       time.Time
 	inactivityNotified bool
+	dispatched         bool                               // A worker owns the guild's next message
+	readySince         time.Time                          // When the guild last started waiting for a worker
+	cancelMessage      context.CancelFunc                 // Stops synthesis of the message being processed
+	current            *QueuedMessage                     // The message being processed
+	heldSince          time.Time                          // When voice auto-pause started holding the next message
+	ahead              map[*QueuedMessage]*lookaheadEntry // Audio synthesized for the next queued messages
 	mu                 sync.RWMutex
 }
 
+// guildJob is a guild's turn on the worker pool
+type guildJob struct {
+	processor  *guildProcessor
+	readySince time.Time
+}
+
 // NewTTSProcessor creates a new TTS processing pipeline
 func NewTTSProcessor(ttsManager TTSManager, voiceManager VoiceManager, messageQueue MessageQueue, configService ConfigService, userService UserService) TTSProcessor {
 	ctx, cancel := context.WithCancel(context.Background())
@@ -54,9 +137,12 @@ func NewTTSProcessor(ttsManager TTSManager, voiceManager VoiceManager, messageQu
 		userService:        userService,
 		ctx:                ctx,
 		cancel:             cancel,
+		workerCount:        DefaultTTSWorkers,
+		lookaheadCount:     LookaheadMessages,
+		jobs:               make(chan guildJob),
+		wake:               make(chan struct{}, 1),
 		guildProcessors:    make(map[string]*guildProcessor),
 		processingInterval: time.Millisecond * 500, // Check for new messages every 500ms
-		inactivityTimeout:  5 * time.Minute,        // Requirement 4.4
 	}
 
 	// Initialize error recovery manager
@@ -74,12 +160,26 @@ func (tp *ttsProcessor) Start() error {
 		return fmt.Errorf("failed to start error recovery manager: %w", err)
 	}
 
+	for i := 0; i < tp.workerCount; i++ {
+		tp.wg.Add(1)
+		go tp.worker()
+	}
+
 	tp.wg.Add(1)
 	go tp.processingLoop()
 
 	return nil
 }
 
+// SetWorkerCount sets how many guild messages are synthesized and played at the same time.
+// It must be called before Start; values outside 1..MaxTTSWorkers are ignored.
+func (tp *ttsProcessor) SetWorkerCount(workers int) {
+	if workers < 1 || workers > MaxTTSWorkers {
+		return
+	}
+	tp.workerCount = workers
+}
+
 // Stop gracefully stops the TTS processing pipeline
 func (tp *ttsProcessor) Stop() error {
 	log.Println("Stopping TTS processing pipeline")
@@ -102,10 +202,10 @@ func (tp *ttsProcessor) StartGuildProcessing(guildID string) error {
 	}
 
 	tp.mu.Lock()
-	defer tp.mu.Unlock()
 
 	// Check if already processing
 	if _, exists := tp.guildProcessors[guildID]; exists {
+		tp.mu.Unlock()
 		return nil // Already processing
 	}
 
@@ -116,6 +216,13 @@ func (tp *ttsProcessor) StartGuildProcessing(guildID string) error {
 		lastActivity:       time.Now(),
 		inactivityNotified: false,
 	}
+	tp.mu.Unlock()
+
+	if tp.transcripts != nil {
+		if err := tp.transcripts.StartSession(guildID); err != nil {
+			log.Printf("Failed to start transcript for guild %s: %v", guildID, err)
+		}
+	}
 
 	log.Printf("Started TTS processing for guild %s", guildID)
 	return nil
@@ -128,15 +235,27 @@ func (tp *ttsProcessor) StopGuildProcessing(guildID string) error {
 	}
 
 	tp.mu.Lock()
-	defer tp.mu.Unlock()
-
+	processor, exists := tp.guildProcessors[guildID]
 	delete(tp.guildProcessors, guildID)
+	tp.mu.Unlock()
+
+	if exists {
+		tp.discardLookahead(processor)
+	}
+	tp.pacing.Forget(guildID)
+
+	if tp.transcripts != nil {
+		if err := tp.transcripts.EndSession(guildID); err != nil {
+			log.Printf("Failed to end transcript for guild %s: %v", guildID, err)
+		}
+	}
 
 	log.Printf("Stopped TTS processing for guild %s", guildID)
 	return nil
 }
 
-// processingLoop is the main processing loop that runs in the background
+// processingLoop dispatches guilds to the worker pool on every tick and whenever a
+// worker becomes idle
 func (tp *ttsProcessor) processingLoop() {
 	defer tp.wg.Done()
 
@@ -149,86 +268,195 @@ func (tp *ttsProcessor) processingLoop() {
 			log.Println("TTS processing loop stopped")
 			return
 		case <-ticker.C:
-			tp.processAllGuilds()
+			tp.dispatchGuilds()
+		case <-tp.wake:
+			tp.dispatchGuilds()
 		}
 	}
 }
 
-// processAllGuilds processes messages for all active guilds
-func (tp *ttsProcessor) processAllGuilds() {
+// dispatchGuilds hands ready guilds to idle workers, longest-waiting first. A guild has at
+// most one message in flight and goes to the back of the line once it is served, so a busy
+// guild cannot starve the others. When every worker is busy the remaining guilds keep their
+// place and nothing more is taken off their queues.
+func (tp *ttsProcessor) dispatchGuilds() {
+	defer Recover(tp.metrics, "tts dispatcher", "")
+
 	tp.mu.RLock()
-	guilds := make([]string, 0, len(tp.guildProcessors))
-	for guildID := range tp.guildProcessors {
-		guilds = append(guilds, guildID)
+	processors := make([]*guildProcessor, 0, len(tp.guildProcessors))
+	for _, processor := range tp.guildProcessors {
+		processors = append(processors, processor)
 	}
 	tp.mu.RUnlock()
 
-	for _, guildID := range guilds {
-		tp.processGuildMessages(guildID)
+	ready := make([]guildJob, 0, len(processors))
+	for _, processor := range processors {
+		if readySince, ok := tp.guildReady(processor); ok {
+			ready = append(ready, guildJob{processor: processor, readySince: readySince})
+		}
 	}
-}
 
-// processGuildMessages processes queued messages for a specific guild
-func (tp *ttsProcessor) processGuildMessages(guildID string) {
-	// Get guild processor
-	tp.mu.RLock()
-	processor, exists := tp.guildProcessors[guildID]
-	tp.mu.RUnlock()
+	sort.Slice(ready, func(i, j int) bool {
+		if !ready[i].readySince.Equal(ready[j].readySince) {
+			return ready[i].readySince.Before(ready[j].readySince)
+		}
+		return ready[i].processor.guildID < ready[j].processor.guildID
+	})
 
-	if !exists {
-		return
+	for _, job := range ready {
+		job.processor.mu.Lock()
+		job.processor.dispatched = true
+		job.processor.mu.Unlock()
+
+		select {
+		case tp.jobs <- job:
+		default:
+			// Every worker is busy
+			job.processor.mu.Lock()
+			job.processor.dispatched = false
+			job.processor.mu.Unlock()
+			return
+		}
+	}
+}
+
+// guildReady reports whether a guild has a message to play and is free to play it,
+// returning when it started waiting for a worker
+func (tp *ttsProcessor) guildReady(processor *guildProcessor) (time.Time, bool) {
+	guildID := processor.guildID
+	if tp.draining.Load() {
+		return time.Time{}, false
 	}
 
 	// Check if voice connection exists
-	isConnected := tp.voiceManager.IsConnected(guildID)
-	if !isConnected {
-		// Debug: Log why we're not processing
+	if !tp.voiceManager.IsConnected(guildID) {
 		queueSize := tp.messageQueue.Size(guildID)
 		if queueSize > 0 {
 			log.Printf("Guild %s has %d queued messages but no voice connection", guildID, queueSize)
 		}
-		return
+		return time.Time{}, false
 	}
 
-	// Check if currently processing or paused
+	// Check if a message is already in flight
 	processor.mu.RLock()
-	isProcessing := processor.isProcessing
+	busy := processor.dispatched || processor.isProcessing
 	processor.mu.RUnlock()
 
-	if isProcessing {
-		return // Already processing a message
-	}
-
-	// Check if paused
-	if tp.voiceManager.IsPaused(guildID) {
-		return
+	if busy || tp.voiceManager.IsPaused(guildID) || tp.pauseScheduler.InQuietHours(guildID) || !tp.listenerGate.Open(guildID) {
+		return time.Time{}, false
 	}
 
 	// Check for messages in queue
-	queueSize := tp.messageQueue.Size(guildID)
-	if queueSize == 0 {
+	if tp.messageQueue.Size(guildID) == 0 {
 		tp.checkInactivity(guildID, processor)
-		return
+		return time.Time{}, false
+	}
+
+	if tp.holdForSpeech(guildID, processor) {
+		return time.Time{}, false
+	}
+
+	processor.mu.Lock()
+	defer processor.mu.Unlock()
+	if processor.readySince.IsZero() {
+		processor.readySince = time.Now()
+	}
+	return processor.readySince, true
+}
+
+// holdForSpeech reports whether a guild's next message waits because someone in the
+// voice channel is talking and the guild turned voice auto-pause on. Once people have
+// talked for maxSpeechHold, messages play until the channel is silent again.
+func (tp *ttsProcessor) holdForSpeech(guildID string, processor *guildProcessor) bool {
+	if tp.voiceActivity == nil || !tp.features.Enabled(guildID, FeatureVoiceAutoPause) {
+		return false
 	}
 
-	log.Printf("Processing %d queued messages for guild %s", queueSize, guildID)
+	processor.mu.Lock()
+	defer processor.mu.Unlock()
 
-	// Process next message
-	tp.processNextMessage(guildID, processor)
+	if !tp.voiceActivity.Speaking(guildID) {
+		processor.heldSince = time.Time{}
+		return false
+	}
+	if processor.heldSince.IsZero() {
+		processor.heldSince = time.Now()
+		log.Printf("Holding messages for guild %s while someone is talking", guildID)
+	}
+	return time.Since(processor.heldSince) < maxSpeechHold
+}
+
+// worker processes guild messages handed out by the dispatcher, one at a time
+func (tp *ttsProcessor) worker() {
+	defer tp.wg.Done()
+
+	for {
+		select {
+		case <-tp.ctx.Done():
+			return
+		case job := <-tp.jobs:
+			tp.runJob(job)
+		}
+	}
+}
+
+// runJob processes a guild's next message and records how long the guild waited for a
+// worker and how long the message took
+func (tp *ttsProcessor) runJob(job guildJob) {
+	processor := job.processor
+	started := time.Now()
+
+	// A panic skips the message and frees the worker and the guild again
+	defer Recover(tp.metrics, "tts worker", processor.guildID)
+	tp.recordBusyWorkers(tp.busyWorkers.Add(1))
+
+	defer func() {
+		processor.mu.Lock()
+		processor.dispatched = false
+		processor.readySince = time.Time{}
+		processor.mu.Unlock()
+
+		tp.recordBusyWorkers(tp.busyWorkers.Add(-1))
+
+		// Let the dispatcher fill the idle worker without waiting for the next tick
+		select {
+		case tp.wake <- struct{}{}:
+		default:
+		}
+	}()
+
+	// The guild may have stopped processing while it waited
+	tp.mu.RLock()
+	current := tp.guildProcessors[processor.guildID]
+	tp.mu.RUnlock()
+	if current != processor || tp.draining.Load() {
+		return
+	}
+
+	log.Printf("Processing %d queued messages for guild %s", tp.messageQueue.Size(processor.guildID), processor.guildID)
+	tp.processNextMessage(processor.guildID, processor)
+	tp.recordGuildJob(processor.guildID, started.Sub(job.readySince), time.Since(started))
 }
 
 // processNextMessage processes the next message in the queue for a guild
 func (tp *ttsProcessor) processNextMessage(guildID string, processor *guildProcessor) {
+	// Skipping the message cancels its synthesis
+	ctx, cancel := context.WithCancel(tp.ctx)
+	defer cancel()
+
 	// Mark as processing
 	processor.mu.Lock()
 	processor.isProcessing = true
 	processor.lastActivity = time.Now()
 	processor.inactivityNotified = false
+	processor.cancelMessage = cancel
 	processor.mu.Unlock()
 
 	defer func() {
 		processor.mu.Lock()
 		processor.isProcessing = false
+		processor.cancelMessage = nil
+		processor.current = nil
 		processor.mu.Unlock()
 	}()
 
@@ -243,29 +471,116 @@ func (tp *ttsProcessor) processNextMessage(guildID string, processor *guildProce
 		return // No message to process
 	}
 
-	// Get TTS configuration for guild
-	config, err := tp.getTTSConfig(guildID)
-	if err != nil {
-		log.Printf("Failed to get TTS config for guild %s: %v", guildID, err)
+	processor.mu.Lock()
+	processor.current = message
+	processor.mu.Unlock()
+
+	// Audio clips are pre-encoded and bypass synthesis entirely
+	if message.ClipName != "" {
+		tp.playClip(ctx, guildID, message.ClipName)
 		return
 	}
 
-	// Message already has author name from message monitor (Requirement 2.3)
-	messageText := message.Content
+	config, moderated, ok := tp.prepareSpeech(guildID, message)
+	if !ok {
+		if moderated != nil && message.Priority == PriorityNormal {
+			tp.skipNotes.Skipped(guildID, message.ChannelID, SkipModerated)
+		}
+		return
+	}
+	messageText := moderated.Text
 
-	// Truncate message if too long (Requirement 4.2)
-	if len(messageText) > MaxMessageLength {
-		messageText = messageText[:MaxMessageLength-3] + "..."
-		log.Printf("Truncated long message for guild %s", guildID)
+	// Deaf members follow what is read in the pairing's mirror channel
+	if tp.textMirror.Post(guildID, moderated) {
+		tp.recordMessage(guildID, message)
+		return
+	}
+
+	// Transcripts and overlays following the guild learn what was read from the event bus
+	speech := trackSpeech(tp.eventBus, message, messageText)
+	defer speech.finish()
+
+	// Only the engine hears numbers and abbreviations spelled out; transcripts and the
+	// mirror keep the message as written
+	spokenText := tp.normalize(messageText, config)
+
+	// Audio synthesized while the previous message played is used as is
+	audioData, ahead := tp.takeLookahead(ctx, processor, message, moderated, config)
+
+	// Messages are skipped without calling the engine while the guild's breaker is open
+	if !ahead && !tp.errorRecovery.allowSynthesis(guildID, config) {
+		log.Printf("Skipping message for guild %s: TTS circuit breaker is open", guildID)
+		return
+	}
+
+	// Pause after the previous message right before playback starts, so synthesis time
+	// counts towards the pause; the next message is paced from the end of this one even
+	// when it is skipped
+	spoke := false
+	pace := func() {
+		tp.pacing.Wait(ctx, guildID, message, tp.messageQueue.Size(guildID))
+		spoke = true
+	}
+	defer func() {
+		if spoke {
+			tp.pacing.Finished(guildID, message)
+		}
+	}()
+
+	// Stream speech when possible so playback starts before synthesis finishes
+	streamErr := errStreamingUnavailable
+	if !ahead && len(moderated.Segments) == 0 {
+		var started bool
+		var played time.Duration
+		started, played, streamErr = tp.streamSpeech(ctx, guildID, spokenText, config, func() {
+			pace()
+			speech.start(0)
+			tp.startLookahead(guildID, processor)
+		})
+		if started {
+			tp.errorRecovery.recordTTSSuccess(guildID)
+			if errors.Is(streamErr, ErrPlaybackSkipped) || ctx.Err() != nil {
+				log.Printf("Message for guild %s was skipped during playback", guildID)
+				return
+			}
+			if streamErr != nil {
+				log.Printf("Streaming playback failed for guild %s: %v", guildID, streamErr)
+				tp.recordError(guildID, "playback", streamErr)
+				return
+			}
+			tp.recordMessage(guildID, message)
+			speech.complete(played)
+			log.Printf("Successfully streamed TTS message for guild %s", guildID)
+			return
+		}
 	}
 
 	// Convert to speech with comprehensive error handling (Requirement 9.2)
-	audioData, err := tp.ttsManager.ConvertToSpeech(messageText, "", config)
+	switch {
+	case ahead:
+	case len(moderated.Segments) > 0, errors.Is(streamErr, errStreamingUnavailable):
+		audioData, err = tp.synthesizeSpeech(ctx, guildID, moderated, config)
+	default:
+		err = streamErr // Synthesis failed before anything played
+	}
+	if err == nil {
+		tp.errorRecovery.recordTTSSuccess(guildID)
+	}
+	if err != nil && ctx.Err() != nil {
+		log.Printf("Message for guild %s was skipped during synthesis", guildID)
+		return
+	}
+	if errors.Is(err, ErrQuotaExceeded) {
+		log.Printf("Skipping message for guild %s: %v", guildID, err)
+		tp.recordError(guildID, "synthesis", err)
+		return
+	}
 	if err != nil {
 		log.Printf("Initial TTS conversion failed for guild %s: %v", guildID, err)
+		tp.recordError(guildID, "synthesis", err)
 
 		// Use comprehensive error recovery
-		audioData, err = tp.errorRecovery.HandleTTSFailure(messageText, "", config, guildID)
+		audioData, err = tp.recoverSpeech(guildID, spokenText, config)
 		if err != nil {
 			log.Printf("TTS conversion failed after comprehensive recovery for guild %s: %v", guildID, err)
 			return // Skip this message and continue
@@ -273,9 +588,17 @@ func (tp *ttsProcessor) processNextMessage(guildID string, processor *guildProce
 	}
 
 	// Play audio through voice connection with error recovery
-	err = tp.voiceManager.PlayAudio(guildID, audioData)
+	pace()
+	speech.start(dcaDuration(audioData))
+	tp.startLookahead(guildID, processor)
+	err = tp.playAudio(ctx, guildID, audioData)
+	if errors.Is(err, ErrPlaybackSkipped) || (err != nil && ctx.Err() != nil) {
+		log.Printf("Message for guild %s was skipped during playback", guildID)
+		return
+	}
 	if err != nil {
 		log.Printf("Audio playback failed for guild %s: %v", guildID, err)
+		tp.recordError(guildID, "playback", err)
 
 		// Use comprehensive audio playback recovery (Requirement 9.1, 9.2)
 		if recoveryErr := tp.errorRecovery.HandleAudioPlaybackFailure(guildID, audioData); recoveryErr != nil {
@@ -288,47 +611,688 @@ func (tp *ttsProcessor) processNextMessage(guildID string, processor *guildProce
 		return
 	}
 
+	played := dcaDuration(audioData)
+	tp.recordAudio(guildID, played)
+	tp.recordMessage(guildID, message)
+	speech.complete(played)
 	log.Printf("Successfully processed TTS message for guild %s: %d bytes audio", guildID, len(audioData))
 }
 
-// checkInactivity checks for inactivity and announces if needed (Requirement 4.4)
+// playClip plays a stored audio clip through the guild's voice connection
+func (tp *ttsProcessor) playClip(ctx context.Context, guildID, name string) {
+	if tp.clipService == nil {
+		log.Printf("Skipping clip %q for guild %s: audio clips are not enabled", name, guildID)
+		return
+	}
+
+	audioData, err := tp.clipService.LoadClip(guildID, name)
+	if err != nil {
+		log.Printf("Failed to load clip %q for guild %s: %v", name, guildID, err)
+		return
+	}
+
+	// Let the next message speak over the clip when the voice manager can mix
+	play := func(guildID string, audioData []byte) error {
+		return tp.playAudio(ctx, guildID, audioData)
+	}
+	if mixer, ok := tp.voiceManager.(ClipMixer); ok {
+		play = mixer.MixClip
+	}
+
+	if err := play(guildID, audioData); err != nil {
+		log.Printf("Clip playback failed for guild %s: %v", guildID, err)
+		return
+	}
+
+	tp.recordAudio(guildID, dcaDuration(audioData))
+
+	log.Printf("Successfully played clip %q for guild %s: %d bytes audio", name, guildID, len(audioData))
+}
+
+// checkInactivity announces that the bot is still listening and leaves the voice channel
+// after the guild's idle timeouts (Requirement 4.4)
 func (tp *ttsProcessor) checkInactivity(guildID string, processor *guildProcessor) {
 	processor.mu.RLock()
-	lastActivity := processor.lastActivity
+	idle := time.Since(processor.lastActivity)
 	inactivityNotified := processor.inactivityNotified
 	processor.mu.RUnlock()
 
-	if time.Since(lastActivity) > tp.inactivityTimeout && !inactivityNotified {
+	config, err := tp.configService.GetGuildConfig(guildID)
+	if err != nil {
+		config = nil // Fall back to the default timeouts
+	}
+	timeouts := IdleTimeoutsFor(config)
+
+	if timeouts.Disconnect > 0 && idle > timeouts.Disconnect {
+		tp.disconnectIdle(guildID, timeouts.Disconnect)
+		return
+	}
+
+	if timeouts.Announce > 0 && idle > timeouts.Announce && !inactivityNotified {
 		// Mark as notified to prevent repeated announcements
 		processor.mu.Lock()
 		processor.inactivityNotified = true
 		processor.mu.Unlock()
 
-		// Create inactivity announcement
-		inactivityMessage := "No new messages for 5 minutes, but I'm still here listening."
+		inactivityMessage := tp.localizer.T(guildID, "idle.still_here", idleMinutes(timeouts.Announce))
+		if err := tp.speakAnnouncement(guildID, inactivityMessage); err != nil {
+			log.Printf("Failed to play inactivity announcement for guild %s: %v", guildID, err)
+		} else {
+			log.Printf("Announced inactivity for guild %s", guildID)
+		}
+	}
+}
+
+// disconnectIdle says goodbye and leaves the voice channel of a guild that has been silent
+// for longer than its idle disconnect timeout. Processing and the channel pairing are
+// cleaned up the same way /darrot-leave does.
+func (tp *ttsProcessor) disconnectIdle(guildID string, timeout time.Duration) {
+	connection, exists := tp.voiceManager.GetConnection(guildID)
+	if !exists {
+		return
+	}
+	voiceChannelID := connection.ChannelID
+
+	leavingMessage := tp.localizer.T(guildID, "idle.leaving", idleMinutes(timeout))
+	if err := tp.speakAnnouncement(guildID, leavingMessage); err != nil {
+		log.Printf("Failed to play idle disconnect announcement for guild %s: %v", guildID, err)
+	}
+
+	if err := tp.voiceManager.LeaveChannel(guildID); err != nil {
+		log.Printf("Failed to leave idle voice channel for guild %s: %v", guildID, err)
+		return
+	}
+
+	if err := tp.StopGuildProcessing(guildID); err != nil {
+		log.Printf("Warning: Failed to stop TTS processing for guild %s: %v", guildID, err)
+	}
+
+	if tp.channelService != nil {
+		if err := tp.channelService.RemovePairing(guildID, voiceChannelID); err != nil {
+			log.Printf("Warning: Failed to remove channel pairing for guild %s: %v", guildID, err)
+		}
+	}
+
+	log.Printf("Left voice channel %s in guild %s after %s without messages", voiceChannelID, guildID, timeout)
+}
+
+// speakAnnouncement synthesizes text with the guild's settings and plays it right away
+func (tp *ttsProcessor) speakAnnouncement(guildID, text string) error {
+	return tp.Announce(tp.ctx, guildID, text)
+}
+
+// Drain stops starting new messages and waits until the messages being spoken finish, or
+// until ctx is done. Queued messages stay in the queue. It reports whether every guild
+// finished in time.
+func (tp *ttsProcessor) Drain(ctx context.Context) bool {
+	tp.draining.Store(true)
+
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+
+	for tp.inFlight() {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-ticker.C:
+		}
+	}
+	return true
+}
+
+// inFlight reports whether any guild has a message dispatched or being spoken
+func (tp *ttsProcessor) inFlight() bool {
+	tp.mu.RLock()
+	defer tp.mu.RUnlock()
+
+	for _, processor := range tp.guildProcessors {
+		processor.mu.RLock()
+		busy := processor.dispatched || processor.isProcessing
+		processor.mu.RUnlock()
+		if busy {
+			return true
+		}
+	}
+	return false
+}
+
+// Announce synthesizes text with the guild's settings and plays it right away, outside
+// the queue
+func (tp *ttsProcessor) Announce(ctx context.Context, guildID, text string) error {
+	config, err := tp.getTTSConfig(guildID)
+	if err != nil {
+		return fmt.Errorf("failed to get TTS config: %w", err)
+	}
+
+	audioData, err := tp.synthesize(ctx, guildID, text, config)
+	if err != nil {
+		return fmt.Errorf("failed to convert announcement: %w", err)
+	}
+
+	return tp.playAudio(ctx, guildID, audioData)
+}
+
+// playAudio plays audio through the guild's voice connection, stopping as soon as ctx is
+// cancelled when the voice manager supports it. Audio that played to the end is copied
+// to the guild's extra audio output.
+func (tp *ttsProcessor) playAudio(ctx context.Context, guildID string, audioData []byte) error {
+	var err error
+	if player, ok := tp.voiceManager.(ContextAudioPlayer); ok {
+		err = player.PlayAudioContext(ctx, guildID, audioData)
+	} else {
+		err = tp.voiceManager.PlayAudio(guildID, audioData)
+	}
+	if err == nil {
+		tp.audioOutputs.Copy(guildID, audioData)
+	}
+	return err
+}
+
+// SetAudioOutputs copies the speech played in each guild to the extra output it chose
+func (tp *ttsProcessor) SetAudioOutputs(outputs *AudioOutputs) {
+	tp.audioOutputs = outputs
+}
+
+// SetTextMirror posts each message read to the mirror channel of its pairing
+func (tp *ttsProcessor) SetTextMirror(mirror *TextMirror) {
+	tp.textMirror = mirror
+}
+
+// SetSkipNotes counts messages moderation skips, for guilds that hear a note about them
+func (tp *ttsProcessor) SetSkipNotes(skipNotes *SkipNotes) {
+	tp.skipNotes = skipNotes
+}
+
+// SetChannelService lets idle disconnects remove the guild's channel pairing
+func (tp *ttsProcessor) SetChannelService(channelService ChannelService) {
+	tp.channelService = channelService
+}
+
+// SetFeatureFlags sets the service deciding which experimental features are on per guild
+func (tp *ttsProcessor) SetFeatureFlags(features *FeatureFlagService) {
+	tp.features = features
+}
+
+// SetVoiceActivity lets guilds with voice auto-pause hold messages while someone talks
+func (tp *ttsProcessor) SetVoiceActivity(activity *VoiceActivity) {
+	tp.voiceActivity = activity
+}
+
+// SetPauseScheduler keeps guilds silent during their quiet hours, even before the
+// scheduler pauses their playback
+func (tp *ttsProcessor) SetPauseScheduler(pauseScheduler *PauseScheduler) {
+	tp.pauseScheduler = pauseScheduler
+}
+
+// SetListenerGate holds messages in guilds with too few people listening
+func (tp *ttsProcessor) SetListenerGate(listenerGate *ListenerGate) {
+	tp.listenerGate = listenerGate
+}
+
+// SetQueuePressurePolicy sets the policy that speeds up reading while a guild's queue is
+// backed up
+func (tp *ttsProcessor) SetQueuePressurePolicy(policy *QueuePressurePolicy) {
+	tp.queuePressure = policy
+}
+
+// SetPacingPolicy sets the policy that leaves pauses between messages
+func (tp *ttsProcessor) SetPacingPolicy(policy *PacingPolicy) {
+	tp.pacing = policy
+}
+
+// SetLocalizer sets the localizer used to translate idle announcements
+func (tp *ttsProcessor) SetLocalizer(localizer *Localizer) {
+	tp.localizer = localizer
+	tp.errorRecovery.SetLocalizer(localizer)
+}
+
+// SetQuotaService enables daily character budget enforcement
+func (tp *ttsProcessor) SetQuotaService(quotaService TTSQuotaService) {
+	tp.quotaService = quotaService
+}
+
+// SetAudioCache enables reuse of previously synthesized audio
+func (tp *ttsProcessor) SetAudioCache(cache *AudioCache) {
+	tp.audioCache = cache
+}
+
+// SetMetrics enables processor instrumentation
+func (tp *ttsProcessor) SetMetrics(metrics *Metrics) {
+	tp.metrics = metrics
+	tp.errorRecovery.SetMetrics(metrics)
+	if metrics != nil {
+		metrics.Describe(MetricAudioCacheHits, MetricTypeCounter, "Messages served from the audio cache")
+		metrics.Describe(MetricTimeToFirstAudio, MetricTypeGauge, "Seconds from the start of synthesis to the first streamed audio frame of the latest message")
+		metrics.Describe(MetricGuildMessagesProcessed, MetricTypeCounter, "Queued messages synthesized and played by the worker pool")
+		metrics.Describe(MetricGuildProcessingSeconds, MetricTypeCounter, "Seconds workers spent synthesizing and playing messages")
+		metrics.Describe(MetricGuildWaitSeconds, MetricTypeGauge, "Seconds the guild's latest message waited for a free worker")
+		metrics.Describe(MetricWorkersBusy, MetricTypeGauge, "Workers currently synthesizing or playing a message")
+		metrics.Describe(MetricLookaheadHits, MetricTypeCounter, "Queued messages played from audio synthesized while the previous message played")
+		metrics.Describe(MetricLookaheadDiscarded, MetricTypeCounter, "Audio synthesized ahead and discarded because its message was skipped, removed or changed")
+		metrics.Describe(MetricTTSErrors, MetricTypeCounter, "Messages whose synthesis or playback failed, by stage and error kind")
+	}
+	describePanicMetrics(metrics)
+}
+
+// SetContentPolicy sets the policy deciding whether synthesized audio may be cached
+func (tp *ttsProcessor) SetContentPolicy(policy *ContentPolicy) {
+	tp.contentPolicy = policy
+}
+
+// SetClipService sets the service used to load audio clips queued by /darrot-play
+func (tp *ttsProcessor) SetClipService(clipService AudioClipService) {
+	tp.clipService = clipService
+}
+
+// SetModerationService sets the service that filters blocked words before synthesis
+func (tp *ttsProcessor) SetModerationService(moderation ModerationService) {
+	tp.moderation = moderation
+}
+
+// SetTextNormalizer spells out numbers, dates and abbreviations before synthesis; nil
+// sends text to the engine as written
+func (tp *ttsProcessor) SetTextNormalizer(normalizer TextNormalizer) {
+	tp.normalizer = normalizer
+}
+
+// SetStatsService enables the per-guild usage statistics shown by /darrot-stats
+func (tp *ttsProcessor) SetStatsService(statsService StatsService) {
+	tp.statsService = statsService
+}
+
+// SetTranscriptService starts and ends the sessions of the transcripts exported by
+// /darrot-transcript. Their utterances are recorded from the event bus.
+func (tp *ttsProcessor) SetTranscriptService(transcripts TranscriptService) {
+	tp.transcripts = transcripts
+}
+
+// SetEventBus publishes when each message starts and stops playing
+func (tp *ttsProcessor) SetEventBus(bus *events.Bus) {
+	tp.eventBus = bus
+	tp.errorRecovery.SetEventBus(bus)
+}
+
+// BreakerStatus returns the state of a guild's TTS circuit breaker
+func (tp *ttsProcessor) BreakerStatus(guildID string) BreakerStatus {
+	return tp.errorRecovery.BreakerStatus(guildID)
+}
 
-		// Get TTS configuration
-		config, err := tp.getTTSConfig(guildID)
+// SetAuditLog records voice connection recoveries in each guild's audit channel
+func (tp *ttsProcessor) SetAuditLog(auditLog *AuditLog) {
+	tp.errorRecovery.SetAuditLog(auditLog)
+}
+
+// synthesize converts text to speech, serving repeated phrases from the audio cache and
+// enforcing the guild's character budget when a quota service is configured. Cached audio
+// is still played after the budget is spent. Synthesis stops when ctx is cancelled if the
+// TTS manager supports it.
+func (tp *ttsProcessor) synthesize(ctx context.Context, guildID, text string, config TTSConfig) ([]byte, error) {
+	config, audioData, err := tp.reserve(guildID, text, config)
+	if err != nil || audioData != nil {
+		return audioData, err
+	}
+
+	if converter, ok := tp.ttsManager.(ContextSpeechConverter); ok {
+		audioData, err = converter.ConvertToSpeechContext(ctx, text, "", config)
+	} else {
+		audioData, err = tp.ttsManager.ConvertToSpeech(text, "", config)
+	}
+	if err != nil {
+		tp.releaseUsage(guildID, text, config.Voice)
+		return nil, err
+	}
+
+	tp.recordUsage(guildID, text, config.Voice)
+	if tp.audioCache != nil && tp.contentPolicy.AllowsCaching(guildID) {
+		tp.audioCache.Put(text, config, audioData)
+	}
+
+	return audioData, nil
+}
+
+// recoverSpeech synthesizes text through the error recovery fallbacks once synthesis
+// failed. The failed attempt handed back its reservation, so the characters are reserved
+// again: the fallbacks use the voice the guild's budget allows and are charged once, or
+// the reservation is released when they fail too.
+func (tp *ttsProcessor) recoverSpeech(guildID, text string, config TTSConfig) ([]byte, error) {
+	if !tp.errorRecovery.allowSynthesis(guildID, config) {
+		return nil, fmt.Errorf("TTS circuit breaker is open")
+	}
+
+	if tp.quotaService != nil {
+		reserved, err := tp.quotaService.Reserve(guildID, text, config)
 		if err != nil {
-			log.Printf("Failed to get TTS config for inactivity announcement in guild %s: %v", guildID, err)
-			return
+			return nil, err
 		}
+		config = reserved
+	}
+
+	audioData, err := tp.errorRecovery.HandleTTSFailure(text, "", config, guildID)
+	if err != nil {
+		tp.releaseUsage(guildID, text, config.Voice)
+		return nil, err
+	}
+	tp.recordUsage(guildID, text, config.Voice)
+	return audioData, nil
+}
 
-		// Convert announcement to speech
-		audioData, err := tp.ttsManager.ConvertToSpeech(inactivityMessage, "", config)
+// reserve checks the audio cache and the guild's character budget before synthesis. It
+// returns cached audio when available, otherwise the config to synthesize with, which may
+// use a cheaper voice when the guild is close to its budget.
+func (tp *ttsProcessor) reserve(guildID, text string, config TTSConfig) (TTSConfig, []byte, error) {
+	if audioData, ok := tp.cachedAudio(guildID, text, config); ok {
+		return config, audioData, nil
+	}
+
+	if tp.quotaService != nil {
+		reserved, err := tp.quotaService.Reserve(guildID, text, config)
 		if err != nil {
-			log.Printf("Failed to convert inactivity announcement for guild %s: %v", guildID, err)
-			return
+			return config, nil, err
 		}
 
-		// Play inactivity announcement
-		err = tp.voiceManager.PlayAudio(guildID, audioData)
+		if reserved.Voice != config.Voice {
+			log.Printf("Guild %s is close to its daily TTS budget, using voice %s instead of %s", guildID, reserved.Voice, config.Voice)
+			if audioData, ok := tp.cachedAudio(guildID, text, reserved); ok {
+				tp.releaseUsage(guildID, text, reserved.Voice)
+				return reserved, audioData, nil
+			}
+			config = reserved
+		}
+	}
+
+	return config, nil, nil
+}
+
+// streamSpeech synthesizes text and plays each Opus frame as soon as it is encoded,
+// calling onStart when playback starts. It reports whether playback started and how
+// much audio played; when it did not start, nothing was played and the caller can fall
+// back to synthesize and PlayAudio. errStreamingUnavailable is returned when the
+// managers cannot stream or the audio is already cached.
+func (tp *ttsProcessor) streamSpeech(ctx context.Context, guildID, text string, config TTSConfig, onStart func()) (bool, time.Duration, error) {
+	streamer, canSynthesize := tp.ttsManager.(SpeechStreamer)
+	player, canPlay := tp.voiceManager.(AudioStreamer)
+	if !canSynthesize || !canPlay || config.Format != AudioFormatDCA {
+		return false, 0, errStreamingUnavailable
+	}
+	streamAudio := func(frames <-chan []byte) error {
+		if contextPlayer, ok := tp.voiceManager.(ContextAudioPlayer); ok {
+			return contextPlayer.StreamAudioContext(ctx, guildID, frames)
+		}
+		return player.StreamAudio(guildID, frames)
+	}
+
+	config, cached, err := tp.reserve(guildID, text, config)
+	if err != nil {
+		return false, 0, err
+	}
+	if cached != nil {
+		return false, 0, errStreamingUnavailable
+	}
+
+	// Playback starts with the first frame, so synthesis failures before it can still fall back
+	startedAt := time.Now()
+	var frames chan []byte
+	var playDone chan error
+	var playErr error
+	var dcaBuffer bytes.Buffer
+	var sentFrames int
+
+	emit := func(frame []byte) error {
+		if frames == nil {
+			tp.recordTimeToFirstAudio(guildID, time.Since(startedAt))
+			if onStart != nil {
+				onStart()
+			}
+			frames = make(chan []byte, streamFrameBuffer)
+			playDone = make(chan error, 1)
+			go func() {
+				playDone <- CatchPanic(tp.metrics, "voice playback", guildID, func() error {
+					return streamAudio(frames)
+				})
+			}()
+		}
+
+		if err := writeDCAFrame(&dcaBuffer, frame); err != nil {
+			return err
+		}
+
+		select {
+		case frames <- frame:
+			sentFrames++
+			return nil
+		case playErr = <-playDone:
+			playDone = nil
+			if playErr == nil {
+				playErr = fmt.Errorf("playback stopped early")
+			}
+			return playErr
+		}
+	}
+
+	synthErr := streamer.StreamSpeech(ctx, text, "", config, emit)
+	if frames == nil {
+		tp.releaseUsage(guildID, text, config.Voice)
+		return false, 0, synthErr
+	}
+
+	close(frames)
+	if playDone != nil {
+		playErr = <-playDone
+	}
+
+	// Playback started, so the text was synthesized and is billed even if it was cut short
+	played := time.Duration(sentFrames) * dcaFrameDuration
+	tp.recordUsage(guildID, text, config.Voice)
+	tp.recordAudio(guildID, played)
+	if playErr != nil {
+		return true, played, playErr
+	}
+	if synthErr != nil {
+		return true, played, synthErr
+	}
+
+	tp.audioOutputs.Copy(guildID, dcaBuffer.Bytes())
+	if tp.audioCache != nil && tp.contentPolicy.AllowsCaching(guildID) {
+		tp.audioCache.Put(text, config, dcaBuffer.Bytes())
+	}
+
+	return true, played, nil
+}
+
+// recordTimeToFirstAudio records how long a streamed message took to start playing
+func (tp *ttsProcessor) recordTimeToFirstAudio(guildID string, latency time.Duration) {
+	if tp.metrics != nil {
+		tp.metrics.SetGauge(MetricTimeToFirstAudio, Labels{"guild": guildID}, latency.Seconds())
+	}
+}
+
+// recordError records a message that failed at stage, synthesis or playback
+func (tp *ttsProcessor) recordError(guildID, stage string, err error) {
+	if tp.metrics != nil {
+		tp.metrics.IncCounter(MetricTTSErrors, Labels{"guild": guildID, "stage": stage, "kind": string(KindOf(err))})
+	}
+}
+
+// recordGuildJob records a guild's throughput and wait time on the worker pool
+func (tp *ttsProcessor) recordGuildJob(guildID string, wait, processing time.Duration) {
+	if tp.metrics == nil {
+		return
+	}
+
+	labels := Labels{"guild": guildID}
+	tp.metrics.IncCounter(MetricGuildMessagesProcessed, labels)
+	tp.metrics.AddCounter(MetricGuildProcessingSeconds, labels, processing.Seconds())
+	tp.metrics.SetGauge(MetricGuildWaitSeconds, labels, wait.Seconds())
+}
+
+// recordBusyWorkers records how many workers are processing a message
+func (tp *ttsProcessor) recordBusyWorkers(busy int32) {
+	if tp.metrics != nil {
+		tp.metrics.SetGauge(MetricWorkersBusy, nil, float64(busy))
+	}
+}
+
+// moderate applies the moderation service to text, passing it through unchanged when
+// moderation is disabled
+func (tp *ttsProcessor) moderate(guildID, text string) *ModerationResult {
+	if tp.moderation == nil {
+		return &ModerationResult{Text: text}
+	}
+
+	result, err := tp.moderation.Moderate(guildID, text)
+	if err != nil {
+		// Fail closed: an unreadable blocklist must not let blocked words through
+		log.Printf("Moderation failed for guild %s: %v", guildID, err)
+		return &ModerationResult{Skip: true}
+	}
+
+	return result
+}
+
+// prepareSpeech returns the voice settings and moderated text a queued message is
+// spoken with. It reports false when the message must not be spoken, along with the
+// moderation result when moderation skipped it.
+func (tp *ttsProcessor) prepareSpeech(guildID string, message *QueuedMessage) (TTSConfig, *ModerationResult, bool) {
+	// Get TTS configuration for guild
+	config, err := tp.getTTSConfig(guildID)
+	if err != nil {
+		log.Printf("Failed to get TTS config for guild %s: %v", guildID, err)
+		return config, nil, false
+	}
+	if message.Voice != "" {
+		config.Voice = message.Voice
+	}
+
+	// Message already has author name from message monitor (Requirement 2.3). A backed
+	// up queue is read faster and with shorter leads to catch up.
+	config, messageText := tp.queuePressure.Apply(guildID, tp.messageQueue.Size(guildID), config, message)
+
+	// Truncate message if too long (Requirement 4.2)
+	if len(messageText) > MaxMessageLength {
+		messageText = messageText[:MaxMessageLength-3] + "..."
+	}
+
+	// Apply the guild's blocklist before anything is synthesized
+	moderated := tp.moderate(guildID, messageText)
+	if moderated.Skip {
+		log.Printf("Skipping message for guild %s: blocked by moderation", guildID)
+		return config, moderated, false
+	}
+
+	return config, moderated, true
+}
+
+// synthesizeSpeech synthesizes moderated text, bleeping blocked words when it has any
+func (tp *ttsProcessor) synthesizeSpeech(ctx context.Context, guildID string, moderated *ModerationResult, config TTSConfig) ([]byte, error) {
+	if len(moderated.Segments) > 0 {
+		return tp.synthesizeBleeped(ctx, guildID, moderated.Segments, config)
+	}
+	return tp.synthesize(ctx, guildID, tp.normalize(moderated.Text, config), config)
+}
+
+// synthesizeBleeped synthesizes each segment separately and joins them with the bleep tone
+func (tp *ttsProcessor) synthesizeBleeped(ctx context.Context, guildID string, segments []string, config TTSConfig) ([]byte, error) {
+	bleep, err := tp.moderation.BleepAudio()
+	if err != nil {
+		return nil, err
+	}
+
+	var audioData []byte
+	for i, segment := range segments {
+		if i > 0 {
+			audioData = append(audioData, bleep...)
+		}
+		if strings.TrimSpace(segment) == "" {
+			continue
+		}
+
+		segmentAudio, err := tp.synthesize(ctx, guildID, tp.normalize(segment, config), config)
 		if err != nil {
-			log.Printf("Failed to play inactivity announcement for guild %s: %v", guildID, err)
-		} else {
-			log.Printf("Announced inactivity for guild %s", guildID)
+			return nil, err
+		}
+		audioData = append(audioData, segmentAudio...)
+	}
+
+	return audioData, nil
+}
+
+// normalize prepares text for the engine in the language of the voice, unless the
+// engine normalizes text itself
+func (tp *ttsProcessor) normalize(text string, config TTSConfig) string {
+	if tp.normalizer == nil {
+		return text
+	}
+	if engine, ok := tp.ttsManager.(NormalizingEngine); ok && engine.NormalizesText() {
+		return text
+	}
+
+	languageCode, _ := parseVoiceID(config.Voice)
+	return tp.normalizer.Normalize(text, languageCode)
+}
+
+// cachedAudio looks up previously synthesized audio for text
+func (tp *ttsProcessor) cachedAudio(guildID, text string, config TTSConfig) ([]byte, bool) {
+	if tp.audioCache == nil || !tp.contentPolicy.AllowsCaching(guildID) {
+		return nil, false
+	}
+
+	audioData, ok := tp.audioCache.Get(text, config)
+	if ok && tp.metrics != nil {
+		tp.metrics.IncCounter(MetricAudioCacheHits, Labels{"guild": guildID})
+	}
+	return audioData, ok
+}
+
+// recordUsage charges text synthesized with a voice against the guild's daily budgets and
+// counts it in the guild's statistics
+func (tp *ttsProcessor) recordUsage(guildID, text, voice string) {
+	characters := utf8.RuneCountInString(text)
+
+	if tp.statsService != nil {
+		if err := tp.statsService.RecordCharacters(guildID, characters); err != nil {
+			log.Printf("Failed to record TTS statistics for guild %s: %v", guildID, err)
 		}
 	}
+
+	if tp.quotaService == nil {
+		return
+	}
+
+	if err := tp.quotaService.RecordUsage(guildID, characters, voice); err != nil {
+		log.Printf("Failed to record TTS usage for guild %s: %v", guildID, err)
+	}
+}
+
+// releaseUsage hands back the characters of text reserved against the guild's daily
+// budgets when it was not synthesized after all
+func (tp *ttsProcessor) releaseUsage(guildID, text, voice string) {
+	if tp.quotaService != nil {
+		tp.quotaService.Release(guildID, utf8.RuneCountInString(text), voice)
+	}
+}
+
+// recordMessage counts a chat message that was read aloud. Announcements, voice
+// previews, messages queued through the HTTP API and the later parts of split messages
+// are not counted.
+func (tp *ttsProcessor) recordMessage(guildID string, message *QueuedMessage) {
+	if tp.statsService == nil || message.Priority != PriorityNormal || message.Voice != "" || message.Tag != "" || message.Part > 0 {
+		return
+	}
+
+	if err := tp.statsService.RecordMessage(guildID, message.UserID, message.Username); err != nil {
+		log.Printf("Failed to record TTS statistics for guild %s: %v", guildID, err)
+	}
+}
+
+// recordAudio adds audio played to the guild's statistics
+func (tp *ttsProcessor) recordAudio(guildID string, played time.Duration) {
+	if tp.statsService == nil {
+		return
+	}
+
+	if err := tp.statsService.RecordAudio(guildID, played); err != nil {
+		log.Printf("Failed to record TTS statistics for guild %s: %v", guildID, err)
+	}
 }
 
 // getTTSConfig gets the TTS configuration for a guild
@@ -378,18 +1342,41 @@ func (tp *ttsProcessor) GetActiveGuilds() []string {
 	return guilds
 }
 
-// SkipCurrentMessage skips the currently processing message for a guild
+// NowPlaying returns the message being synthesized or spoken in a guild, or nil
+func (tp *ttsProcessor) NowPlaying(guildID string) *QueuedMessage {
+	tp.mu.RLock()
+	processor, exists := tp.guildProcessors[guildID]
+	tp.mu.RUnlock()
+	if !exists {
+		return nil
+	}
+
+	processor.mu.RLock()
+	defer processor.mu.RUnlock()
+	return processor.current
+}
+
+// SkipCurrentMessage skips the currently processing message for a guild, stopping its
+// synthesis if it has not finished yet
 func (tp *ttsProcessor) SkipCurrentMessage(guildID string) error {
+	tp.mu.RLock()
+	processor, exists := tp.guildProcessors[guildID]
+	tp.mu.RUnlock()
+
+	if exists {
+		processor.mu.RLock()
+		if processor.cancelMessage != nil {
+			processor.cancelMessage()
+		}
+		processor.mu.RUnlock()
+	}
+
 	// Skip in voice manager (stops current audio)
 	if err := tp.voiceManager.SkipCurrentMessage(guildID); err != nil {
 		return fmt.Errorf("failed to skip current message: %w", err)
 	}
 
 	// Reset processing state
-	tp.mu.RLock()
-	processor, exists := tp.guildProcessors[guildID]
-	tp.mu.RUnlock()
-
 	if exists {
 		processor.mu.Lock()
 		processor.isProcessing = false
@@ -411,9 +1398,20 @@ func (tp *ttsProcessor) ResumeProcessing(guildID string) error {
 	return tp.voiceManager.ResumePlayback(guildID)
 }
 
-// ClearQueue clears the message queue for a guild
+// ClearQueue clears the message queue for a guild and discards the audio synthesized
+// ahead for it
 func (tp *ttsProcessor) ClearQueue(guildID string) error {
-	return tp.messageQueue.Clear(guildID)
+	if err := tp.messageQueue.Clear(guildID); err != nil {
+		return err
+	}
+
+	tp.mu.RLock()
+	processor, exists := tp.guildProcessors[guildID]
+	tp.mu.RUnlock()
+	if exists {
+		tp.discardLookahead(processor)
+	}
+	return nil
 }
 
 // GetQueueSize returns the current queue size for a guild
@@ -1,11 +1,19 @@
 package tts
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io"
+	"log"
+	"slices"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
+
+	"darrot/internal/events"
 )
 
 // Mock implementations for testing
@@ -267,6 +275,38 @@ func (m *mockConfigService) GetMaxQueueSize(guildID string) (int, error) {
 	return 0, errors.New("not implemented")
 }
 
+func (m *mockConfigService) SetIgnorePrefixes(guildID string, prefixes []string) error {
+	return errors.New("not implemented")
+}
+
+func (m *mockConfigService) GetIgnorePrefixes(guildID string) ([]string, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockConfigService) GetProfiles(guildID string) ([]GuildProfile, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockConfigService) SaveProfile(guildID string, profile GuildProfile) error {
+	return errors.New("not implemented")
+}
+
+func (m *mockConfigService) DeleteProfile(guildID, name string) error {
+	return errors.New("not implemented")
+}
+
+func (m *mockConfigService) UseProfile(guildID, name string) (*GuildProfile, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockConfigService) ExportGuildConfig(guildID string) (*GuildConfigExport, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockConfigService) ImportGuildConfig(guildID string, export *GuildConfigExport) error {
+	return errors.New("not implemented")
+}
+
 func (m *mockConfigService) ValidateConfig(config *GuildTTSConfig) error {
 	return nil
 }
@@ -820,3 +860,1041 @@ func TestTTSProcessor_ConcurrentProcessing(t *testing.T) {
 		t.Errorf("Expected %d active guilds, got %d", numGuilds, len(activeGuilds))
 	}
 }
+
+func TestTTSProcessor_SynthesizeWithQuotaAndCache(t *testing.T) {
+	ttsManager := &mockTTSManager{}
+	processor := NewTTSProcessor(ttsManager, newMockVoiceManager(), NewMessageQueue(), newMockConfigService(), newMockUserService()).(*ttsProcessor)
+
+	quotaService, _, metrics := createTestQuotaService(t, 20)
+	processor.SetQuotaService(quotaService)
+	processor.SetAudioCache(NewAudioCache(1024))
+	processor.SetMetrics(metrics)
+
+	config := TTSConfig{Voice: DefaultVoice, Speed: 1.0, Volume: 1.0, Format: AudioFormatDCA}
+
+	// First request is synthesized and charged
+	if _, err := processor.synthesize(context.Background(), "guild1", "hello", config); err != nil {
+		t.Fatalf("Expected synthesis to succeed, got %v", err)
+	}
+	// Repeated text is served from the cache without being charged again
+	if _, err := processor.synthesize(context.Background(), "guild1", "hello", config); err != nil {
+		t.Fatalf("Expected cached synthesis to succeed, got %v", err)
+	}
+
+	if calls := len(ttsManager.getCallLog()); calls != 1 {
+		t.Errorf("Expected 1 TTS call, got %d", calls)
+	}
+	usage, _ := quotaService.GetUsage("guild1")
+	if usage.CharactersUsed != 5 {
+		t.Errorf("Expected 5 characters used, got %d", usage.CharactersUsed)
+	}
+	if hits := metrics.Value(MetricAudioCacheHits, Labels{"guild": "guild1"}); hits != 1 {
+		t.Errorf("Expected 1 cache hit, got %v", hits)
+	}
+
+	// Once the budget is spent only cached audio can be played
+	if err := quotaService.RecordUsage("guild1", 15, DefaultVoice); err != nil {
+		t.Fatalf("Failed to record usage: %v", err)
+	}
+	if _, err := processor.synthesize(context.Background(), "guild1", "something new", config); !errors.Is(err, ErrQuotaExceeded) {
+		t.Errorf("Expected ErrQuotaExceeded, got %v", err)
+	}
+	if _, err := processor.synthesize(context.Background(), "guild1", "hello", config); err != nil {
+		t.Errorf("Expected cached audio after budget exhaustion, got %v", err)
+	}
+}
+
+func TestTTSProcessor_FailedSynthesisReleasesQuota(t *testing.T) {
+	failing := true
+	ttsManager := &mockTTSManager{convertFunc: func(text, voice string, config TTSConfig) ([]byte, error) {
+		if failing {
+			return nil, errors.New("synthesis failed")
+		}
+		return []byte("mock audio data"), nil
+	}}
+	processor := NewTTSProcessor(ttsManager, newMockVoiceManager(), NewMessageQueue(), newMockConfigService(), newMockUserService()).(*ttsProcessor)
+
+	quotaService, _, _ := createTestQuotaService(t, 10)
+	processor.SetQuotaService(quotaService)
+
+	config := TTSConfig{Voice: DefaultVoice, Speed: 1.0, Volume: 1.0, Format: AudioFormatDCA}
+	for range 3 {
+		if _, err := processor.synthesize(context.Background(), "guild1", "hello", config); err == nil {
+			t.Fatal("Expected synthesis to fail")
+		}
+	}
+
+	// Failed attempts hand their reservation back instead of using up the budget
+	failing = false
+	for range 2 {
+		if _, err := processor.synthesize(context.Background(), "guild1", "hello", config); err != nil {
+			t.Fatalf("Expected synthesis to succeed, got %v", err)
+		}
+	}
+	usage, _ := quotaService.GetUsage("guild1")
+	if usage.CharactersUsed != 10 {
+		t.Errorf("Expected 10 characters used, got %d", usage.CharactersUsed)
+	}
+}
+
+func TestTTSProcessor_RecoveryUsesReservedVoice(t *testing.T) {
+	var voices []string
+	failing := 1
+	ttsManager := &mockTTSManager{convertFunc: func(text, voice string, config TTSConfig) ([]byte, error) {
+		voices = append(voices, config.Voice)
+		if len(voices) <= failing {
+			return nil, errors.New("synthesis failed")
+		}
+		return []byte("mock audio data"), nil
+	}}
+	processor := NewTTSProcessor(ttsManager, newMockVoiceManager(), NewMessageQueue(), newMockConfigService(), newMockUserService()).(*ttsProcessor)
+	processor.errorRecovery.retryDelay = time.Millisecond
+
+	quotaService, _, _ := createTestQuotaService(t, 100)
+	processor.SetQuotaService(quotaService)
+	if err := quotaService.RecordUsage("guild1", 80, DefaultVoice); err != nil {
+		t.Fatalf("Failed to record usage: %v", err)
+	}
+
+	// Past the downgrade threshold the fallbacks use the Standard voice the budget allows
+	premium := TTSConfig{Voice: "en-GB-Neural2-B", Speed: 1.0, Volume: 1.0, Format: AudioFormatDCA}
+	if _, err := processor.recoverSpeech("guild1", "hello", premium); err != nil {
+		t.Fatalf("Expected recovery to succeed, got %v", err)
+	}
+	if got := fmt.Sprint(voices); got != "[en-GB-Standard-B en-GB-Standard-B]" {
+		t.Errorf("Expected the fallbacks to use the downgraded voice, got %s", got)
+	}
+	usage, _ := quotaService.GetUsage("guild1")
+	if usage.CharactersUsed != 85 || usage.TierCharacters["Neural2"] != 0 {
+		t.Errorf("Expected 85 Standard characters used, got %d (%v)", usage.CharactersUsed, usage.TierCharacters)
+	}
+
+	// When every fallback fails too, the reservation is handed back
+	failing = 1 << 30
+	if _, err := processor.recoverSpeech("guild1", "hello", premium); err == nil {
+		t.Fatal("Expected recovery to fail")
+	}
+	if _, err := quotaService.Reserve("guild1", strings.Repeat("a", 15), premium); err != nil {
+		t.Errorf("Expected the failed recovery to leave 15 characters of budget, got %v", err)
+	}
+}
+
+// streamingTTSManager adds frame streaming to mockTTSManager
+type streamingTTSManager struct {
+	*mockTTSManager
+	streamFunc func(text string, emit func(frame []byte) error) error
+}
+
+func (m *streamingTTSManager) StreamSpeech(ctx context.Context, text, voice string, config TTSConfig, emit func(frame []byte) error) error {
+	return m.streamFunc(text, emit)
+}
+
+// streamingVoiceManager adds frame streaming to mockVoiceManager
+type streamingVoiceManager struct {
+	*mockVoiceManager
+	streamed [][]byte
+}
+
+func (m *streamingVoiceManager) StreamAudio(guildID string, frames <-chan []byte) error {
+	for frame := range frames {
+		m.streamed = append(m.streamed, frame)
+	}
+	return nil
+}
+
+func TestTTSProcessor_StreamsSpeech(t *testing.T) {
+	ttsManager := &streamingTTSManager{
+		mockTTSManager: &mockTTSManager{},
+		streamFunc: func(text string, emit func(frame []byte) error) error {
+			for _, frame := range []string{"one", "two", "three"} {
+				if err := emit([]byte(frame)); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+	voiceMgr := &streamingVoiceManager{mockVoiceManager: newMockVoiceManager()}
+	processor := NewTTSProcessor(ttsManager, voiceMgr, NewMessageQueue(), newMockConfigService(), newMockUserService()).(*ttsProcessor)
+
+	quotaService, _, metrics := createTestQuotaService(t, 100)
+	processor.SetQuotaService(quotaService)
+	processor.SetAudioCache(NewAudioCache(1024))
+	processor.SetMetrics(metrics)
+
+	config := TTSConfig{Voice: DefaultVoice, Speed: 1.0, Volume: 1.0, Format: AudioFormatDCA}
+	started, played, err := processor.streamSpeech(context.Background(), "guild1", "hello", config, nil)
+	if !started || err != nil {
+		t.Fatalf("Expected streaming to succeed, got started=%v err=%v", started, err)
+	}
+	if played != 3*dcaFrameDuration {
+		t.Errorf("Expected 3 frames of audio played, got %v", played)
+	}
+
+	if len(voiceMgr.streamed) != 3 || string(voiceMgr.streamed[2]) != "three" {
+		t.Errorf("Expected 3 streamed frames, got %q", voiceMgr.streamed)
+	}
+	if calls := len(ttsManager.getCallLog()); calls != 0 {
+		t.Errorf("Expected no full synthesis calls, got %d", calls)
+	}
+	usage, _ := quotaService.GetUsage("guild1")
+	if usage.CharactersUsed != 5 {
+		t.Errorf("Expected 5 characters used, got %d", usage.CharactersUsed)
+	}
+
+	// The streamed frames are cached as DCA so repeats skip synthesis
+	cached, ok := processor.audioCache.Get("hello", config)
+	if !ok {
+		t.Fatal("Expected streamed audio to be cached")
+	}
+	frames, err := (&voiceManager{}).parseDCAFrames(cached)
+	if err != nil || len(frames) != 3 {
+		t.Errorf("Expected 3 cached DCA frames, got %d (%v)", len(frames), err)
+	}
+	if started, _, err := processor.streamSpeech(context.Background(), "guild1", "hello", config, nil); started || !errors.Is(err, errStreamingUnavailable) {
+		t.Errorf("Expected cached audio not to be streamed, got started=%v err=%v", started, err)
+	}
+}
+
+func TestTTSProcessor_CopiesToAudioOutput(t *testing.T) {
+	configService := createTestExportConfigService(t)
+	guildConfig := DefaultGuildTTSConfig("guild1")
+	guildConfig.AudioOutput = AudioOutputIcecast
+	if err := configService.SetGuildConfig("guild1", &guildConfig); err != nil {
+		t.Fatalf("Failed to set guild config: %v", err)
+	}
+
+	sink := &recordingSink{}
+	outputs := NewAudioOutputs(configService, log.New(io.Discard, "", 0))
+	outputs.Register(AudioOutputIcecast, sink)
+
+	voiceMgr := newMockVoiceManager()
+	processor := NewTTSProcessor(&mockTTSManager{}, voiceMgr, NewMessageQueue(), newMockConfigService(), newMockUserService()).(*ttsProcessor)
+	processor.SetAudioOutputs(outputs)
+
+	if err := processor.playAudio(context.Background(), "guild1", []byte{1, 0, 0xF8}); err != nil {
+		t.Fatalf("Expected playback to succeed, got %v", err)
+	}
+	voiceMgr.playAudioFunc = func(guildID string, audioData []byte) error { return errors.New("not connected") }
+	if err := processor.playAudio(context.Background(), "guild1", []byte{1, 0, 0xF8}); err == nil {
+		t.Fatal("Expected playback to fail")
+	}
+
+	if played := sink.count("guild1"); played != 1 {
+		t.Errorf("Expected only the audio played in Discord to be copied, got %d copies", played)
+	}
+}
+
+func TestTTSProcessor_StreamingFailureFallsBack(t *testing.T) {
+	ttsManager := &streamingTTSManager{
+		mockTTSManager: &mockTTSManager{},
+		streamFunc: func(text string, emit func(frame []byte) error) error {
+			return errors.New("synthesis unavailable")
+		},
+	}
+	voiceMgr := &streamingVoiceManager{mockVoiceManager: newMockVoiceManager()}
+	queue := NewMessageQueue()
+	processor := NewTTSProcessor(ttsManager, voiceMgr, queue, newMockConfigService(), newMockUserService()).(*ttsProcessor)
+
+	var played []byte
+	voiceMgr.playAudioFunc = func(guildID string, audioData []byte) error {
+		played = audioData
+		return nil
+	}
+
+	// Nothing has played yet, so the message goes through error recovery and PlayAudio
+	if err := queue.Enqueue(&QueuedMessage{ID: "m1", GuildID: "guild1", Content: "bob says: hi", Timestamp: time.Now()}); err != nil {
+		t.Fatalf("Failed to enqueue message: %v", err)
+	}
+	processor.processNextMessage("guild1", &guildProcessor{guildID: "guild1"})
+
+	if len(voiceMgr.streamed) != 0 {
+		t.Errorf("Expected no streamed frames, got %d", len(voiceMgr.streamed))
+	}
+	if string(played) != "mock audio data" {
+		t.Errorf("Expected recovered audio to be played, got %q", played)
+	}
+}
+
+// cancellableTTSManager holds synthesis until its context is cancelled
+type cancellableTTSManager struct {
+	*mockTTSManager
+	started chan struct{}
+}
+
+func (m *cancellableTTSManager) ConvertToSpeechContext(ctx context.Context, text, voice string, config TTSConfig) ([]byte, error) {
+	close(m.started)
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestTTSProcessor_SkipCancelsSynthesis(t *testing.T) {
+	ttsManager := &cancellableTTSManager{mockTTSManager: &mockTTSManager{}, started: make(chan struct{})}
+	voiceMgr := newMockVoiceManager()
+	queue := NewMessageQueue()
+	processor := NewTTSProcessor(ttsManager, voiceMgr, queue, newMockConfigService(), newMockUserService()).(*ttsProcessor)
+
+	played := false
+	voiceMgr.playAudioFunc = func(guildID string, audioData []byte) error {
+		played = true
+		return nil
+	}
+
+	if err := queue.Enqueue(&QueuedMessage{ID: "m1", GuildID: "guild1", Content: "bob says: hi", Timestamp: time.Now()}); err != nil {
+		t.Fatalf("Failed to enqueue message: %v", err)
+	}
+	guild := &guildProcessor{guildID: "guild1"}
+	processor.guildProcessors["guild1"] = guild
+
+	done := make(chan struct{})
+	go func() {
+		processor.processNextMessage("guild1", guild)
+		close(done)
+	}()
+
+	<-ttsManager.started
+	if err := processor.SkipCurrentMessage("guild1"); err != nil {
+		t.Fatalf("Failed to skip message: %v", err)
+	}
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected skipping to stop synthesis")
+	}
+
+	// A skipped message is neither played nor sent through error recovery
+	if played {
+		t.Error("Expected the skipped message not to be played")
+	}
+	if calls := len(ttsManager.getCallLog()); calls != 0 {
+		t.Errorf("Expected no fallback synthesis, got %d calls", calls)
+	}
+}
+
+func TestTTSProcessor_RecordsStats(t *testing.T) {
+	ttsManager := &streamingTTSManager{
+		mockTTSManager: &mockTTSManager{},
+		streamFunc: func(text string, emit func(frame []byte) error) error {
+			for i := 0; i < 3; i++ {
+				if err := emit([]byte("frame")); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+	voiceMgr := &streamingVoiceManager{mockVoiceManager: newMockVoiceManager()}
+	queue := NewMessageQueue()
+	configService := newMockConfigService()
+	configService.configs["guild1"] = &TTSConfig{Voice: DefaultVoice, Speed: 1.0, Volume: 1.0, Format: AudioFormatDCA}
+	processor := NewTTSProcessor(ttsManager, voiceMgr, queue, configService, newMockUserService()).(*ttsProcessor)
+
+	statsService, _ := createTestStatsService(t)
+	processor.SetStatsService(statsService)
+
+	messages := []*QueuedMessage{
+		{ID: "m1", GuildID: "guild1", UserID: "user1", Username: "bob", Content: "bob says: hi", Timestamp: time.Now()},
+		{ID: "a1", GuildID: "guild1", UserID: "user2", Username: "ann", Content: "ann joined", Priority: PriorityLow, Timestamp: time.Now()},
+	}
+	for _, message := range messages {
+		if err := queue.Enqueue(message); err != nil {
+			t.Fatalf("Failed to enqueue message: %v", err)
+		}
+	}
+	processor.processNextMessage("guild1", &guildProcessor{guildID: "guild1"})
+	processor.processNextMessage("guild1", &guildProcessor{guildID: "guild1"})
+
+	stats, err := statsService.GetStats("guild1")
+	if err != nil {
+		t.Fatalf("Failed to get stats: %v", err)
+	}
+
+	// Announcements are spoken and synthesized but are not chat messages
+	if stats.MessagesRead != 1 || stats.Speakers["user1"] == nil || stats.Speakers["user2"] != nil {
+		t.Errorf("Expected only bob's message to be counted, got %d messages from %v", stats.MessagesRead, stats.Speakers)
+	}
+	if stats.CharactersSynthesized != len("bob says: hi")+len("ann joined") {
+		t.Errorf("Expected both texts to be counted as synthesized, got %d characters", stats.CharactersSynthesized)
+	}
+	if stats.AudioPlayed() != 6*dcaFrameDuration {
+		t.Errorf("Expected 6 frames of audio, got %v", stats.AudioPlayed())
+	}
+}
+
+func TestTTSProcessor_RecordsTranscript(t *testing.T) {
+	ttsManager := &streamingTTSManager{
+		mockTTSManager: &mockTTSManager{},
+		streamFunc: func(text string, emit func(frame []byte) error) error {
+			for i := 0; i < 3; i++ {
+				if err := emit([]byte("frame")); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+	voiceMgr := &streamingVoiceManager{mockVoiceManager: newMockVoiceManager()}
+	queue := NewMessageQueue()
+	configService := newMockConfigService()
+	configService.configs["guild1"] = &TTSConfig{Voice: DefaultVoice, Speed: 1.0, Volume: 1.0, Format: AudioFormatDCA}
+	processor := NewTTSProcessor(ttsManager, voiceMgr, queue, configService, newMockUserService()).(*ttsProcessor)
+
+	transcriptService, guildConfigs, _ := createTestTranscriptService(t)
+	enableTranscripts(t, guildConfigs, "guild1")
+	processor.SetTranscriptService(transcriptService)
+	bus := events.New()
+	processor.SetEventBus(bus)
+	transcriptService.Subscribe(bus)
+
+	if err := processor.StartGuildProcessing("guild1"); err != nil {
+		t.Fatalf("Failed to start processing: %v", err)
+	}
+
+	messages := []*QueuedMessage{
+		{ID: "m1", GuildID: "guild1", UserID: "user1", Username: "bob", Content: "bob says: hi", Timestamp: time.Now()},
+		{ID: "a1", GuildID: "guild1", UserID: "user2", Username: "ann", Content: "ann joined", Priority: PriorityLow, Timestamp: time.Now()},
+	}
+	for _, message := range messages {
+		if err := queue.Enqueue(message); err != nil {
+			t.Fatalf("Failed to enqueue message: %v", err)
+		}
+	}
+	processor.processNextMessage("guild1", &guildProcessor{guildID: "guild1"})
+	processor.processNextMessage("guild1", &guildProcessor{guildID: "guild1"})
+
+	if err := processor.StopGuildProcessing("guild1"); err != nil {
+		t.Fatalf("Failed to stop processing: %v", err)
+	}
+
+	session, err := transcriptService.LatestSession("guild1")
+	if err != nil || session == nil {
+		t.Fatalf("Expected a transcript, got %v (%v)", session, err)
+	}
+	if session.EndedAt.IsZero() {
+		t.Error("Expected the session to end when processing stopped")
+	}
+
+	// Announcements are spoken but are not part of the conversation
+	if len(session.Entries) != 1 {
+		t.Fatalf("Expected only bob's message in the transcript, got %+v", session.Entries)
+	}
+	entry := session.Entries[0]
+	if entry.UserID != "user1" || entry.Text != "bob says: hi" || entry.Duration() != 3*dcaFrameDuration {
+		t.Errorf("Unexpected transcript entry %+v", entry)
+	}
+}
+
+func TestTTSProcessor_PublishesUtteranceEvents(t *testing.T) {
+	ttsManager := &streamingTTSManager{
+		mockTTSManager: &mockTTSManager{},
+		streamFunc: func(text string, emit func(frame []byte) error) error {
+			return emit([]byte("frame"))
+		},
+	}
+	voiceMgr := &streamingVoiceManager{mockVoiceManager: newMockVoiceManager()}
+	queue := NewMessageQueue()
+	configService := newMockConfigService()
+	configService.configs["guild1"] = &TTSConfig{Voice: DefaultVoice, Speed: 1.0, Volume: 1.0, Format: AudioFormatDCA}
+	processor := NewTTSProcessor(ttsManager, voiceMgr, queue, configService, newMockUserService()).(*ttsProcessor)
+
+	bus := events.New()
+	processor.SetEventBus(bus)
+	var started []events.UtteranceStarted
+	var finished []events.UtteranceFinished
+	events.Subscribe(bus, func(e events.UtteranceStarted) { started = append(started, e) })
+	events.Subscribe(bus, func(e events.UtteranceFinished) { finished = append(finished, e) })
+
+	message := &QueuedMessage{ID: "m1", GuildID: "guild1", UserID: "user1", Username: "bob", Content: "bob says: hi", Tag: "overlay", Timestamp: time.Now()}
+	if err := queue.Enqueue(message); err != nil {
+		t.Fatalf("Failed to enqueue message: %v", err)
+	}
+	processor.processNextMessage("guild1", &guildProcessor{guildID: "guild1"})
+
+	if len(started) != 1 || len(finished) != 1 {
+		t.Fatalf("Expected one started and one finished event, got %+v and %+v", started, finished)
+	}
+	if started[0].Username != "bob" || started[0].Text != "bob says: hi" || started[0].Tag != "overlay" {
+		t.Errorf("Unexpected started event %+v", started[0])
+	}
+	if !finished[0].Completed || finished[0].UserID != "user1" || finished[0].Played != dcaFrameDuration {
+		t.Errorf("Unexpected finished event %+v", finished[0])
+	}
+}
+
+func TestTTSProcessor_VoiceOverride(t *testing.T) {
+	var voices []string
+	ttsManager := &mockTTSManager{
+		convertFunc: func(text, voice string, config TTSConfig) ([]byte, error) {
+			voices = append(voices, config.Voice)
+			return []byte("mock audio data"), nil
+		},
+	}
+	queue := NewMessageQueue()
+	configService := newMockConfigService()
+	configService.configs["guild1"] = &TTSConfig{Voice: DefaultVoice, Speed: 1.0, Volume: 1.0, Format: AudioFormatPCM}
+	processor := NewTTSProcessor(ttsManager, newMockVoiceManager(), queue, configService, newMockUserService()).(*ttsProcessor)
+
+	statsService, _ := createTestStatsService(t)
+	processor.SetStatsService(statsService)
+
+	// A preview is read with its own voice and does not change the guild's voice
+	messages := []*QueuedMessage{
+		{ID: "p1", GuildID: "guild1", UserID: "user1", Content: "preview", Voice: "de-DE-Wavenet-B", Timestamp: time.Now()},
+		{ID: "m1", GuildID: "guild1", UserID: "user1", Content: "bob says: hi", Timestamp: time.Now()},
+	}
+	for _, message := range messages {
+		if err := queue.Enqueue(message); err != nil {
+			t.Fatalf("Failed to enqueue message: %v", err)
+		}
+	}
+	processor.processNextMessage("guild1", &guildProcessor{guildID: "guild1"})
+	processor.processNextMessage("guild1", &guildProcessor{guildID: "guild1"})
+
+	if len(voices) != 2 || voices[0] != "de-DE-Wavenet-B" || voices[1] != DefaultVoice {
+		t.Errorf("Expected the preview voice and then the guild voice, got %v", voices)
+	}
+
+	// Previews are not chat messages
+	stats, _ := statsService.GetStats("guild1")
+	if stats.MessagesRead != 1 {
+		t.Errorf("Expected 1 message counted, got %d", stats.MessagesRead)
+	}
+}
+
+// normalizingTTSManager is a TTS manager whose engine normalizes text itself
+type normalizingTTSManager struct {
+	*mockTTSManager
+}
+
+func (m *normalizingTTSManager) NormalizesText() bool {
+	return true
+}
+
+func TestTTSProcessor_NormalizesText(t *testing.T) {
+	var spoken []string
+	ttsManager := &mockTTSManager{
+		convertFunc: func(text, voice string, config TTSConfig) ([]byte, error) {
+			spoken = append(spoken, text)
+			return []byte("mock audio data"), nil
+		},
+	}
+
+	for _, engine := range []TTSManager{ttsManager, &normalizingTTSManager{ttsManager}} {
+		queue := NewMessageQueue()
+		configService := newMockConfigService()
+		configService.configs["guild1"] = &TTSConfig{Voice: DefaultVoice, Speed: 1.0, Volume: 1.0, Format: AudioFormatPCM}
+		processor := NewTTSProcessor(engine, newMockVoiceManager(), queue, configService, newMockUserService()).(*ttsProcessor)
+		processor.SetTextNormalizer(NewTextNormalizer())
+
+		messages := []*QueuedMessage{
+			{ID: "m1", GuildID: "guild1", UserID: "user1", Content: "bob says: I owe you $5", Timestamp: time.Now()},
+			{ID: "m2", GuildID: "guild1", UserID: "user1", Content: "bob says: 1.024 Euro", Voice: "de-DE-Wavenet-B", Timestamp: time.Now()},
+		}
+		for _, message := range messages {
+			if err := queue.Enqueue(message); err != nil {
+				t.Fatalf("Failed to enqueue message: %v", err)
+			}
+			processor.processNextMessage("guild1", &guildProcessor{guildID: "guild1"})
+		}
+	}
+
+	// The rules follow the voice's language, and engines that normalize text get it as written
+	expected := []string{"bob says: I owe you five dollars", "bob says: eintausendvierundzwanzig Euro", "bob says: I owe you $5", "bob says: 1.024 Euro"}
+	if !slices.Equal(spoken, expected) {
+		t.Errorf("Expected %q to be synthesized, got %q", expected, spoken)
+	}
+}
+
+// idleConfigService returns a fixed guild configuration for idle timeout tests
+type idleConfigService struct {
+	*mockConfigService
+	config *GuildTTSConfig
+}
+
+func (m *idleConfigService) GetGuildConfig(guildID string) (*GuildTTSConfig, error) {
+	return m.config, nil
+}
+
+// createIdleTestProcessor returns a processor for a connected guild that has been silent
+// for idle, and records the text it speaks
+func createIdleTestProcessor(t *testing.T, config *GuildTTSConfig, idle time.Duration) (*ttsProcessor, *mockVoiceManager, *[]string) {
+	var spoken []string
+	ttsManager := &mockTTSManager{
+		convertFunc: func(text, voice string, config TTSConfig) ([]byte, error) {
+			spoken = append(spoken, text)
+			return []byte("mock audio data"), nil
+		},
+	}
+	voiceManager := newMockVoiceManager()
+	configService := &idleConfigService{mockConfigService: newMockConfigService(), config: config}
+	processor := NewTTSProcessor(ttsManager, voiceManager, NewMessageQueue(), configService, newMockUserService()).(*ttsProcessor)
+
+	if _, err := voiceManager.JoinChannel("guild1", "voice1"); err != nil {
+		t.Fatalf("Failed to join voice channel: %v", err)
+	}
+	if err := processor.StartGuildProcessing("guild1"); err != nil {
+		t.Fatalf("Failed to start guild processing: %v", err)
+	}
+	processor.guildProcessors["guild1"].lastActivity = time.Now().Add(-idle)
+
+	return processor, voiceManager, &spoken
+}
+
+func TestTTSProcessor_IdleAnnouncement(t *testing.T) {
+	processor, _, spoken := createIdleTestProcessor(t, nil, 6*time.Minute)
+	guildProcessor := processor.guildProcessors["guild1"]
+
+	// The default announcement plays once per silence
+	processor.checkInactivity("guild1", guildProcessor)
+	processor.checkInactivity("guild1", guildProcessor)
+
+	expected := "No new messages for 5 minutes, but I'm still here listening."
+	if len(*spoken) != 1 || (*spoken)[0] != expected {
+		t.Errorf("Expected one default announcement, got %v", *spoken)
+	}
+
+	// Configured timeouts replace the default
+	processor, _, spoken = createIdleTestProcessor(t, &GuildTTSConfig{GuildID: "guild1", IdleAnnounceMinutes: 10}, 6*time.Minute)
+	processor.checkInactivity("guild1", processor.guildProcessors["guild1"])
+	if len(*spoken) != 0 {
+		t.Errorf("Expected no announcement before 10 minutes, got %v", *spoken)
+	}
+
+	// Negative minutes turn the announcement off
+	processor, _, spoken = createIdleTestProcessor(t, &GuildTTSConfig{GuildID: "guild1", IdleAnnounceMinutes: -1}, time.Hour)
+	processor.checkInactivity("guild1", processor.guildProcessors["guild1"])
+	if len(*spoken) != 0 {
+		t.Errorf("Expected announcements to be off, got %v", *spoken)
+	}
+}
+
+func TestTTSProcessor_IdleDisconnect(t *testing.T) {
+	config := &GuildTTSConfig{GuildID: "guild1", IdleDisconnectMinutes: 30}
+	processor, voiceManager, spoken := createIdleTestProcessor(t, config, 31*time.Minute)
+
+	channelService := &MockChannelService{}
+	channelService.On("RemovePairing", "guild1", "voice1").Return(nil)
+	processor.SetChannelService(channelService)
+
+	processor.checkInactivity("guild1", processor.guildProcessors["guild1"])
+
+	expected := "No new messages for 30 minutes, so I'm leaving the voice channel. Use darrot join to bring me back."
+	if len(*spoken) != 1 || (*spoken)[0] != expected {
+		t.Errorf("Expected the leaving announcement, got %v", *spoken)
+	}
+	if voiceManager.IsConnected("guild1") {
+		t.Error("Expected the bot to leave the voice channel")
+	}
+	if _, exists := processor.guildProcessors["guild1"]; exists {
+		t.Error("Expected guild processing to stop")
+	}
+	channelService.AssertExpectations(t)
+
+	// Before the timeout the bot stays
+	processor, voiceManager, _ = createIdleTestProcessor(t, config, 29*time.Minute)
+	processor.checkInactivity("guild1", processor.guildProcessors["guild1"])
+	if !voiceManager.IsConnected("guild1") {
+		t.Error("Expected the bot to stay before the disconnect timeout")
+	}
+}
+
+func TestTTSProcessor_WorkerPoolFairness(t *testing.T) {
+	var mu sync.Mutex
+	var played []string
+
+	voiceManager := newMockVoiceManager()
+	voiceManager.playAudioFunc = func(guildID string, audioData []byte) error {
+		mu.Lock()
+		played = append(played, guildID)
+		mu.Unlock()
+		return nil
+	}
+	messageQueue := NewMessageQueue()
+
+	processor := NewTTSProcessor(&mockTTSManager{}, voiceManager, messageQueue, newMockConfigService(), newMockUserService()).(*ttsProcessor)
+	processor.processingInterval = 10 * time.Millisecond
+	processor.SetWorkerCount(1)
+
+	// A busy guild queues several messages before a quiet guild queues its two
+	for _, guild := range []struct {
+		id       string
+		messages int
+	}{{"busy", 5}, {"quiet", 2}} {
+		if _, err := voiceManager.JoinChannel(guild.id, "voice"); err != nil {
+			t.Fatalf("Failed to join voice channel: %v", err)
+		}
+		if err := processor.StartGuildProcessing(guild.id); err != nil {
+			t.Fatalf("Failed to start guild processing: %v", err)
+		}
+		for i := 0; i < guild.messages; i++ {
+			err := messageQueue.Enqueue(&QueuedMessage{
+				ID:        fmt.Sprintf("%s-%d", guild.id, i),
+				GuildID:   guild.id,
+				UserID:    "user",
+				Username:  "User",
+				Content:   fmt.Sprintf("message %d", i),
+				Timestamp: time.Now(),
+			})
+			if err != nil {
+				t.Fatalf("Failed to enqueue message: %v", err)
+			}
+		}
+	}
+
+	if err := processor.Start(); err != nil {
+		t.Fatalf("Failed to start processor: %v", err)
+	}
+	defer func() { _ = processor.Stop() }()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		mu.Lock()
+		done := len(played) == 7
+		mu.Unlock()
+		if done {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(played) != 7 {
+		t.Fatalf("Expected 7 messages to be played, got %d: %v", len(played), played)
+	}
+
+	// The quiet guild is served in turn instead of after the busy guild's whole queue
+	quiet := 0
+	for _, guildID := range played[:4] {
+		if guildID == "quiet" {
+			quiet++
+		}
+	}
+	if quiet != 2 {
+		t.Errorf("Expected both quiet guild messages within the first 4 plays, got order %v", played)
+	}
+}
+
+func TestTTSProcessor_WorkerPoolBackpressure(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan string, 3)
+
+	voiceManager := newMockVoiceManager()
+	voiceManager.playAudioFunc = func(guildID string, audioData []byte) error {
+		started <- guildID
+		<-release
+		return nil
+	}
+	messageQueue := NewMessageQueue()
+	metrics := NewMetrics()
+
+	processor := NewTTSProcessor(&mockTTSManager{}, voiceManager, messageQueue, newMockConfigService(), newMockUserService()).(*ttsProcessor)
+	processor.processingInterval = 10 * time.Millisecond
+	processor.SetWorkerCount(2)
+	processor.SetMetrics(metrics)
+
+	guilds := []string{"guild-a", "guild-b", "guild-c"}
+	for _, guildID := range guilds {
+		if _, err := voiceManager.JoinChannel(guildID, "voice"); err != nil {
+			t.Fatalf("Failed to join voice channel: %v", err)
+		}
+		if err := processor.StartGuildProcessing(guildID); err != nil {
+			t.Fatalf("Failed to start guild processing: %v", err)
+		}
+		err := messageQueue.Enqueue(&QueuedMessage{
+			ID:        guildID,
+			GuildID:   guildID,
+			UserID:    "user",
+			Username:  "User",
+			Content:   "hello",
+			Timestamp: time.Now(),
+		})
+		if err != nil {
+			t.Fatalf("Failed to enqueue message: %v", err)
+		}
+	}
+
+	if err := processor.Start(); err != nil {
+		t.Fatalf("Failed to start processor: %v", err)
+	}
+	defer func() { _ = processor.Stop() }()
+
+	// Both workers pick up a guild; the third guild's message stays queued
+	for i := 0; i < 2; i++ {
+		select {
+		case <-started:
+		case <-time.After(2 * time.Second):
+			t.Fatal("Timed out waiting for workers to start playback")
+		}
+	}
+
+	select {
+	case guildID := <-started:
+		t.Fatalf("Expected guild %s to wait for a free worker", guildID)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	waiting := 0
+	for _, guildID := range guilds {
+		waiting += messageQueue.Size(guildID)
+	}
+	if waiting != 1 {
+		t.Errorf("Expected 1 message to stay queued while workers are busy, got %d", waiting)
+	}
+	if busy := metrics.Value(MetricWorkersBusy, nil); busy != 2 {
+		t.Errorf("Expected 2 busy workers, got %v", busy)
+	}
+
+	close(release)
+	select {
+	case <-started:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timed out waiting for the waiting guild to be processed")
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) && metrics.Value(MetricWorkersBusy, nil) != 0 {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	for _, guildID := range guilds {
+		if processed := metrics.Value(MetricGuildMessagesProcessed, Labels{"guild": guildID}); processed != 1 {
+			t.Errorf("Expected 1 processed message for %s, got %v", guildID, processed)
+		}
+	}
+
+	// Prometheus scrapes the throughput and wait times from the metrics endpoint
+	output := scrapeMetrics(t, metrics)
+	for _, series := range []string{
+		MetricWorkersBusy + " 0\n",
+		MetricGuildMessagesProcessed + `{guild="` + guilds[0] + `"} 1` + "\n",
+		MetricGuildProcessingSeconds + `{guild="` + guilds[0] + `"} `,
+		MetricGuildWaitSeconds + `{guild="` + guilds[0] + `"} `,
+	} {
+		if !strings.Contains(output, series) {
+			t.Errorf("Expected the metrics endpoint to serve %q, got:\n%s", series, output)
+		}
+	}
+}
+
+func TestTTSProcessor_VoiceAutoPause(t *testing.T) {
+	configService := createTestExportConfigService(t)
+	processor := NewTTSProcessor(&mockTTSManager{}, newMockVoiceManager(), NewMessageQueue(), configService, newMockUserService()).(*ttsProcessor)
+	guild := &guildProcessor{guildID: "guild1"}
+	frame := make([]int16, 960)
+
+	activity := NewVoiceActivity()
+	activity.Observe("guild1", "user1", frame)
+
+	// Nothing is held until the guild turns voice auto-pause on
+	features := NewFeatureFlagService(configService, nil)
+	processor.SetFeatureFlags(features)
+	processor.SetVoiceActivity(activity)
+	if processor.holdForSpeech("guild1", guild) {
+		t.Error("Expected messages not to be held without voice auto-pause")
+	}
+
+	on := true
+	if err := features.Set("guild1", FeatureVoiceAutoPause, &on); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if !processor.holdForSpeech("guild1", guild) {
+		t.Error("Expected messages to be held while someone is talking")
+	}
+
+	// Messages play once the channel is silent
+	activity.Observe("guild1", "user1", nil)
+	if processor.holdForSpeech("guild1", guild) {
+		t.Error("Expected messages not to be held once the channel is silent")
+	}
+
+	// Or once people have talked for too long
+	activity.Observe("guild1", "user1", frame)
+	if !processor.holdForSpeech("guild1", guild) {
+		t.Error("Expected messages to be held when someone starts talking again")
+	}
+	guild.heldSince = time.Now().Add(-maxSpeechHold)
+	if processor.holdForSpeech("guild1", guild) {
+		t.Errorf("Expected messages not to be held for longer than %v", maxSpeechHold)
+	}
+}
+
+func TestTTSProcessor_Drain(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan string, 2)
+
+	voiceManager := newMockVoiceManager()
+	voiceManager.playAudioFunc = func(guildID string, audioData []byte) error {
+		started <- guildID
+		<-release
+		return nil
+	}
+	messageQueue := NewMessageQueue()
+
+	processor := NewTTSProcessor(&mockTTSManager{}, voiceManager, messageQueue, newMockConfigService(), newMockUserService()).(*ttsProcessor)
+	processor.processingInterval = 10 * time.Millisecond
+
+	if _, err := voiceManager.JoinChannel("guild1", "voice"); err != nil {
+		t.Fatalf("Failed to join voice channel: %v", err)
+	}
+	if err := processor.StartGuildProcessing("guild1"); err != nil {
+		t.Fatalf("Failed to start guild processing: %v", err)
+	}
+	for _, id := range []string{"first", "second"} {
+		err := messageQueue.Enqueue(&QueuedMessage{ID: id, GuildID: "guild1", UserID: "user", Username: "User", Content: id, Timestamp: time.Now()})
+		if err != nil {
+			t.Fatalf("Failed to enqueue message: %v", err)
+		}
+	}
+
+	if err := processor.Start(); err != nil {
+		t.Fatalf("Failed to start processor: %v", err)
+	}
+	defer func() { _ = processor.Stop() }()
+
+	select {
+	case <-started:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timed out waiting for playback to start")
+	}
+
+	// The drain times out while the first message is still playing
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if processor.Drain(ctx) {
+		t.Error("Expected Drain to time out while a message is being spoken")
+	}
+
+	// It finishes once the message does, without starting the next one
+	close(release)
+	if !processor.Drain(context.Background()) {
+		t.Error("Expected Drain to finish once the message is spoken")
+	}
+
+	select {
+	case <-started:
+		t.Error("Expected no new message to start while draining")
+	case <-time.After(100 * time.Millisecond):
+	}
+	if size := messageQueue.Size("guild1"); size != 1 {
+		t.Errorf("Expected the second message to stay queued, got %d queued", size)
+	}
+}
+
+func TestTTSProcessor_RecoversFromPanickingMessage(t *testing.T) {
+	played := make(chan string, 2)
+	var calls atomic.Int32
+
+	voiceManager := newMockVoiceManager()
+	voiceManager.playAudioFunc = func(guildID string, audioData []byte) error {
+		if calls.Add(1) == 1 {
+			played <- "panicked"
+			panic("corrupt audio")
+		}
+		played <- "played"
+		return nil
+	}
+	messageQueue := NewMessageQueue()
+	metrics := NewMetrics()
+
+	processor := NewTTSProcessor(&mockTTSManager{}, voiceManager, messageQueue, newMockConfigService(), newMockUserService()).(*ttsProcessor)
+	processor.processingInterval = 10 * time.Millisecond
+	processor.SetWorkerCount(1)
+	processor.SetMetrics(metrics)
+
+	if _, err := voiceManager.JoinChannel("guild1", "voice"); err != nil {
+		t.Fatalf("Failed to join voice channel: %v", err)
+	}
+	if err := processor.StartGuildProcessing("guild1"); err != nil {
+		t.Fatalf("Failed to start guild processing: %v", err)
+	}
+	for _, id := range []string{"first", "second"} {
+		err := messageQueue.Enqueue(&QueuedMessage{ID: id, GuildID: "guild1", UserID: "user", Username: "User", Content: id, Timestamp: time.Now()})
+		if err != nil {
+			t.Fatalf("Failed to enqueue message: %v", err)
+		}
+	}
+
+	if err := processor.Start(); err != nil {
+		t.Fatalf("Failed to start processor: %v", err)
+	}
+	defer func() { _ = processor.Stop() }()
+
+	// The only worker survives the panic and plays the next message
+	for _, want := range []string{"panicked", "played"} {
+		select {
+		case got := <-played:
+			if got != want {
+				t.Errorf("Expected %s, got %s", want, got)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("Timed out waiting for the message to be %s", want)
+		}
+	}
+
+	if got := metrics.Value(MetricPanicsRecovered, Labels{"component": "tts worker"}); got != 1 {
+		t.Errorf("Expected 1 recovered panic, got %v", got)
+	}
+}
+
+// contextVoiceManager adds cancellable playback to mockVoiceManager, playing until the
+// message is skipped
+type contextVoiceManager struct {
+	*mockVoiceManager
+	playing chan struct{}
+}
+
+func (m *contextVoiceManager) PlayAudioContext(ctx context.Context, guildID string, audioData []byte) error {
+	m.playing <- struct{}{}
+	<-ctx.Done()
+	return fmt.Errorf("%w for guild %s", ErrPlaybackSkipped, guildID)
+}
+
+func (m *contextVoiceManager) StreamAudioContext(ctx context.Context, guildID string, frames <-chan []byte) error {
+	return m.PlayAudioContext(ctx, guildID, nil)
+}
+
+func TestTTSProcessor_SkipStopsPlayback(t *testing.T) {
+	voiceManager := &contextVoiceManager{mockVoiceManager: newMockVoiceManager(), playing: make(chan struct{}, 1)}
+	messageQueue := NewMessageQueue()
+
+	processor := NewTTSProcessor(&mockTTSManager{}, voiceManager, messageQueue, newMockConfigService(), newMockUserService()).(*ttsProcessor)
+	processor.processingInterval = 10 * time.Millisecond
+
+	if _, err := voiceManager.JoinChannel("guild1", "voice"); err != nil {
+		t.Fatalf("Failed to join voice channel: %v", err)
+	}
+	if err := processor.StartGuildProcessing("guild1"); err != nil {
+		t.Fatalf("Failed to start guild processing: %v", err)
+	}
+	err := messageQueue.Enqueue(&QueuedMessage{ID: "long", GuildID: "guild1", UserID: "user", Username: "User", Content: "a very long message", Timestamp: time.Now()})
+	if err != nil {
+		t.Fatalf("Failed to enqueue message: %v", err)
+	}
+
+	if err := processor.Start(); err != nil {
+		t.Fatalf("Failed to start processor: %v", err)
+	}
+	defer func() { _ = processor.Stop() }()
+
+	select {
+	case <-voiceManager.playing:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timed out waiting for playback to start")
+	}
+
+	if err := processor.SkipCurrentMessage("guild1"); err != nil {
+		t.Fatalf("Failed to skip message: %v", err)
+	}
+	if !processor.Drain(context.Background()) {
+		t.Fatal("Expected the skipped message to stop playing")
+	}
+
+	// A skipped message is not a playback failure, so it is not played again
+	for _, call := range voiceManager.getCallLog() {
+		if call == "PlayAudio" {
+			t.Error("Expected the skipped message not to be retried")
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package tts
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultSynthesisTimeout bounds a single Google Cloud TTS request
+const DefaultSynthesisTimeout = 15 * time.Second
+
+// retryPolicy retries Google Cloud TTS requests that failed with a transient status,
+// waiting a jittered, exponentially growing delay between attempts
+type retryPolicy struct {
+	maxAttempts int
+	baseDelay   time.Duration
+	maxDelay    time.Duration
+}
+
+// defaultRetryPolicy is how GoogleTTSManager retries synthesis requests
+var defaultRetryPolicy = retryPolicy{
+	maxAttempts: 3,
+	baseDelay:   250 * time.Millisecond,
+	maxDelay:    4 * time.Second,
+}
+
+// retryableCodes are the gRPC codes of requests that may succeed when sent again
+var retryableCodes = map[codes.Code]bool{
+	codes.Unavailable:       true,
+	codes.ResourceExhausted: true,
+	codes.DeadlineExceeded:  true,
+	codes.Aborted:           true,
+	codes.Internal:          true,
+}
+
+// isRetryableCode reports whether err is a transient Google Cloud TTS failure. Requests
+// that ran out of their own timeout count as transient; a cancelled message does not.
+func isRetryableCode(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	return retryableCodes[status.Code(err)]
+}
+
+// delay returns how long to wait before the given retry, counting from 1. The delay is
+// drawn uniformly up to baseDelay doubled for every earlier retry, capped at maxDelay, so
+// guilds that failed together do not retry together.
+func (p retryPolicy) delay(retry int) time.Duration {
+	ceiling := p.baseDelay
+	for i := 1; i < retry && ceiling < p.maxDelay; i++ {
+		ceiling *= 2
+	}
+	if ceiling > p.maxDelay {
+		ceiling = p.maxDelay
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(ceiling) + 1))
+}
+
+// do calls attempt until it succeeds, fails with an error that is not retryable, runs out
+// of attempts or ctx is done. It returns the error of the last attempt.
+func (p retryPolicy) do(ctx context.Context, attempt func(ctx context.Context) error) error {
+	var err error
+	for try := 1; ; try++ {
+		if err = attempt(ctx); err == nil || try >= p.maxAttempts || !isRetryableCode(err) {
+			return err
+		}
+
+		timer := time.NewTimer(p.delay(try))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
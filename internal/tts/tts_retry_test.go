@@ -0,0 +1,107 @@
+package tts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestIsRetryableCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"nil error", nil, false},
+		{"unavailable", status.Error(codes.Unavailable, "backend unavailable"), true},
+		{"resource exhausted", status.Error(codes.ResourceExhausted, "quota"), true},
+		{"deadline exceeded", status.Error(codes.DeadlineExceeded, "too slow"), true},
+		{"aborted", status.Error(codes.Aborted, "aborted"), true},
+		{"internal", status.Error(codes.Internal, "internal"), true},
+		{"invalid argument", status.Error(codes.InvalidArgument, "bad voice"), false},
+		{"permission denied", status.Error(codes.PermissionDenied, "denied"), false},
+		{"request timed out", fmt.Errorf("synthesis: %w", context.DeadlineExceeded), true},
+		{"message cancelled", fmt.Errorf("synthesis: %w", context.Canceled), false},
+		{"plain error", errors.New("something broke"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, isRetryableCode(tt.err))
+		})
+	}
+}
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	policy := retryPolicy{maxAttempts: 5, baseDelay: 100 * time.Millisecond, maxDelay: 300 * time.Millisecond}
+
+	// Each retry waits at most twice as long as the one before, up to maxDelay
+	ceilings := map[int]time.Duration{1: 100 * time.Millisecond, 2: 200 * time.Millisecond, 3: 300 * time.Millisecond, 10: 300 * time.Millisecond}
+	for retry, ceiling := range ceilings {
+		for i := 0; i < 50; i++ {
+			delay := policy.delay(retry)
+			assert.GreaterOrEqual(t, delay, time.Duration(0))
+			assert.LessOrEqual(t, delay, ceiling, "retry %d", retry)
+		}
+	}
+
+	assert.Equal(t, time.Duration(0), retryPolicy{}.delay(1))
+}
+
+func TestRetryPolicy_Do(t *testing.T) {
+	policy := retryPolicy{maxAttempts: 3, baseDelay: time.Millisecond, maxDelay: time.Millisecond}
+	unavailable := status.Error(codes.Unavailable, "backend unavailable")
+
+	t.Run("retries transient failures", func(t *testing.T) {
+		attempts := 0
+		err := policy.do(context.Background(), func(ctx context.Context) error {
+			attempts++
+			if attempts < 3 {
+				return unavailable
+			}
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 3, attempts)
+	})
+
+	t.Run("gives up after max attempts", func(t *testing.T) {
+		attempts := 0
+		err := policy.do(context.Background(), func(ctx context.Context) error {
+			attempts++
+			return unavailable
+		})
+		assert.Equal(t, unavailable, err)
+		assert.Equal(t, 3, attempts)
+	})
+
+	t.Run("does not retry permanent failures", func(t *testing.T) {
+		attempts := 0
+		invalid := status.Error(codes.InvalidArgument, "bad voice")
+		err := policy.do(context.Background(), func(ctx context.Context) error {
+			attempts++
+			return invalid
+		})
+		assert.Equal(t, invalid, err)
+		assert.Equal(t, 1, attempts)
+	})
+
+	t.Run("stops when cancelled", func(t *testing.T) {
+		slow := retryPolicy{maxAttempts: 3, baseDelay: time.Hour, maxDelay: time.Hour}
+		ctx, cancel := context.WithCancel(context.Background())
+		attempts := 0
+		err := slow.do(ctx, func(ctx context.Context) error {
+			attempts++
+			cancel()
+			return unavailable
+		})
+		assert.Equal(t, unavailable, err)
+		assert.Equal(t, 1, attempts)
+	})
+}
@@ -1,6 +1,7 @@
 package tts
 
 import (
+	"context"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
@@ -8,10 +9,14 @@ import (
 
 // TTSConfig holds configuration for text-to-speech conversion
 type TTSConfig struct {
-	Voice  string      `json:"voice"`
-	Speed  float32     `json:"speed"`
-	Volume float32     `json:"volume"`
-	Format AudioFormat `json:"format"`
+	Voice          string      `json:"voice"`
+	Speed          float32     `json:"speed"`
+	Volume         float32     `json:"volume"`
+	Format         AudioFormat `json:"format"`
+	Pitch          float32     `json:"pitch,omitempty"`           // Semitones, MinTTSPitch to MaxTTSPitch
+	EffectsProfile string      `json:"effects_profile,omitempty"` // One of EffectsProfiles
+	Style          string      `json:"style,omitempty"`           // One of VoiceStyles, for styled voices only
+	Loudness       float32     `json:"loudness,omitempty"`        // Speech level in dBFS, MinTTSLoudness to MaxTTSLoudness; 0 is off
 }
 
 // AudioFormat represents the audio format for TTS output
@@ -23,6 +28,76 @@ const (
 	AudioFormatPCM  AudioFormat = "pcm"
 )
 
+// ContentRetention controls whether spoken message content may leave the TTS pipeline
+// (logs, debug output, transcripts, caches) for a guild
+type ContentRetention string
+
+const (
+	ContentRetentionFull     ContentRetention = "full"
+	ContentRetentionMetadata ContentRetention = "metadata"
+)
+
+// LinkMode controls how URLs in messages are read
+type LinkMode string
+
+// Link modes
+const (
+	LinkModeDomain LinkMode = "domain" // Read "a link to github.com" (default)
+	LinkModeFull   LinkMode = "full"   // Read the whole URL
+	LinkModeSkip   LinkMode = "skip"   // Leave links out
+)
+
+// CodeBlockMode controls how fenced code blocks in messages are read
+type CodeBlockMode string
+
+// Code block modes
+const (
+	CodeBlockModeSummary CodeBlockMode = "summary" // Read "a code snippet, 3 lines" (default)
+	CodeBlockModeFull    CodeBlockMode = "full"    // Read the code itself
+	CodeBlockModeSkip    CodeBlockMode = "skip"    // Leave code blocks out
+)
+
+// SpoilerMode controls how spoiler-tagged text (||text||) in messages is read
+type SpoilerMode string
+
+// Spoiler modes
+const (
+	SpoilerModeSummary SpoilerMode = "summary" // Read "spoiler omitted" (default)
+	SpoilerModeFull    SpoilerMode = "full"    // Read the hidden text
+	SpoilerModeSkip    SpoilerMode = "skip"    // Leave spoilers out
+)
+
+// TruncationMode controls how messages longer than a guild's maximum utterance length are read
+type TruncationMode string
+
+// Truncation modes
+const (
+	TruncationModeHard     TruncationMode = "hard"     // Cut at the maximum length
+	TruncationModeSentence TruncationMode = "sentence" // Cut after the last whole sentence that fits (default)
+	TruncationModeSplit    TruncationMode = "split"    // Read the whole message as several queued utterances
+	TruncationModeAsk      TruncationMode = "ask"      // Read the first part and offer the rest with a button
+)
+
+// QueueOrder controls the order in which a guild's queued messages are read
+type QueueOrder string
+
+// Queue orders
+const (
+	QueueOrderFIFO       QueueOrder = "fifo"        // Read messages in the order they were posted (default)
+	QueueOrderRoundRobin QueueOrder = "round-robin" // Take turns between authors, one message each
+)
+
+// AudioOutput names where a guild's speech goes besides its Discord voice channel
+type AudioOutput string
+
+// Audio outputs. Every output but Discord has to be set up by the operator first.
+const (
+	AudioOutputDiscord AudioOutput = "discord" // Only the voice channel (default)
+	AudioOutputFile    AudioOutput = "file"    // Also record each voice session to an Ogg Opus file
+	AudioOutputIcecast AudioOutput = "icecast" // Also stream to an Icecast server
+	AudioOutputPulse   AudioOutput = "pulse"   // Also play on a PulseAudio sink of the host
+)
+
 // Voice represents a TTS voice option
 type Voice struct {
 	ID       string `json:"id"`
@@ -33,12 +108,21 @@ type Voice struct {
 
 // VoiceConnection represents an active Discord voice connection
 type VoiceConnection struct {
-	GuildID    string                     `json:"guild_id"`
-	ChannelID  string                     `json:"channel_id"`
-	Connection *discordgo.VoiceConnection `json:"-"`
-	IsPlaying  bool                       `json:"is_playing"`
-	IsPaused   bool                       `json:"is_paused"`
-	Queue      *AudioQueue                `json:"-"`
+	GuildID          string                     `json:"guild_id"`
+	ChannelID        string                     `json:"channel_id"`
+	Connection       *discordgo.VoiceConnection `json:"-"`
+	IsPlaying        bool                       `json:"is_playing"`
+	IsPaused         bool                       `json:"is_paused"`
+	RequestedToSpeak bool                       `json:"requested_to_speak,omitempty"` // Waiting for a stage moderator to make the bot a speaker
+	Reconnecting     bool                       `json:"reconnecting,omitempty"`       // On standby while the voice socket is re-established
+	Queue            *AudioQueue                `json:"-"`
+
+	standbySince   time.Time
+	stopReceiving  func()             // Stops passing the channel's speech to the voice receiver
+	sendStats      *voiceSendStats    // Quality of the audio sent, created on first playback
+	cancelPlayback context.CancelFunc // Stops the audio being sent, set while IsPlaying
+	failover       voiceFailover      // Recent failures and backoff of rejoins
+	sender         *voiceSender       // Paces and sends the connection's frames, started on first playback
 }
 
 // AudioQueue manages queued audio for playback
@@ -50,40 +134,182 @@ type AudioQueue struct {
 
 // ChannelPairing represents a voice-text channel pairing
 type ChannelPairing struct {
-	GuildID        string    `json:"guild_id"`
-	VoiceChannelID string    `json:"voice_channel_id"`
-	TextChannelID  string    `json:"text_channel_id"`
-	CreatedBy      string    `json:"created_by"`
-	CreatedAt      time.Time `json:"created_at"`
+	GuildID         string    `json:"guild_id"`
+	VoiceChannelID  string    `json:"voice_channel_id"`
+	TextChannelID   string    `json:"text_channel_id"`
+	CreatedBy       string    `json:"created_by"`
+	CreatedAt       time.Time `json:"created_at"`
+	RequirePresence bool      `json:"require_presence,omitempty"`  // Only read authors who are in the voice channel
+	Greeting        string    `json:"greeting,omitempty"`          // Welcome text spoken when the bot joins
+	ReadPinned      bool      `json:"read_pinned,omitempty"`       // Speak the latest pinned message when the bot joins
+	MirrorChannelID string    `json:"mirror_channel_id,omitempty"` // Text channel that shows everything read aloud
+	MirrorOnly      bool      `json:"mirror_only,omitempty"`       // Post to the mirror channel instead of speaking
 }
 
 // QueuedMessage represents a message queued for TTS processing
 type QueuedMessage struct {
-	ID        string    `json:"id"`
-	GuildID   string    `json:"guild_id"`
-	ChannelID string    `json:"channel_id"`
-	UserID    string    `json:"user_id"`
-	Username  string    `json:"username"`
-	Content   string    `json:"content"`
-	Timestamp time.Time `json:"timestamp"`
+	ID        string          `json:"id"`
+	GuildID   string          `json:"guild_id"`
+	ChannelID string          `json:"channel_id"`
+	UserID    string          `json:"user_id"`
+	Username  string          `json:"username"`
+	Content   string          `json:"content"`
+	ClipName  string          `json:"clip_name,omitempty"` // Set when the entry plays a stored clip instead of speech
+	Voice     string          `json:"voice,omitempty"`     // Overrides the guild's voice, used by /darrot-preview
+	Priority  MessagePriority `json:"priority,omitempty"`
+	Part      int             `json:"part,omitempty"` // 2, 3, ... on the later parts of a split message
+	Tag       string          `json:"tag,omitempty"`  // Names the HTTP API source of injected messages
+	Lead      string          `json:"lead,omitempty"` // The "Alice says:" opening Content, left out when merged into the author's previous message
+	Timestamp time.Time       `json:"timestamp"`
+}
+
+// ModerationMode controls what happens to messages containing blocked words
+type ModerationMode string
+
+// Moderation modes
+const (
+	ModerationModeSkip    ModerationMode = "skip"    // Drop the whole message
+	ModerationModeBleep   ModerationMode = "bleep"   // Replace blocked words with a tone
+	ModerationModeReplace ModerationMode = "replace" // Read blocked words as "asterisk"
+)
+
+// ModerationSettings holds a guild's word blocklist and how matches are handled
+type ModerationSettings struct {
+	GuildID   string         `json:"guild_id"`
+	Mode      ModerationMode `json:"mode"`
+	Blocklist []string       `json:"blocklist"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+// ModerationResult describes how a message must be spoken after moderation
+type ModerationResult struct {
+	Text     string   // Text to synthesize
+	Skip     bool     // The message must not be spoken
+	Segments []string // Bleep mode: text to speak with a tone between consecutive segments
+	Matches  int      // Number of blocked words found
+}
+
+// MessagePriority selects the queue lane a message is placed in
+type MessagePriority int
+
+// Message priorities
+const (
+	PriorityNormal MessagePriority = iota // Chat messages and clips
+	PriorityLow                           // Announcements that must never delay chat messages
+)
+
+// AudioClip describes a named audio clip stored for a guild
+type AudioClip struct {
+	GuildID   string        `json:"guild_id"`
+	Name      string        `json:"name"`
+	Size      int           `json:"size"`
+	Duration  time.Duration `json:"duration"`
+	CreatedBy string        `json:"created_by"`
+	CreatedAt time.Time     `json:"created_at"`
 }
 
 // GuildTTSConfig holds TTS configuration for a specific guild
 type GuildTTSConfig struct {
-	GuildID       string    `json:"guild_id"`
-	RequiredRoles []string  `json:"required_roles"`
-	TTSSettings   TTSConfig `json:"tts_settings"`
-	MaxQueueSize  int       `json:"max_queue_size"`
-	UpdatedAt     time.Time `json:"updated_at"`
+	GuildID               string           `json:"guild_id"`
+	RequiredRoles         []string         `json:"required_roles"`
+	SyncRolePermissions   bool             `json:"sync_role_permissions,omitempty"` // Mirror the required roles to Discord's command permissions
+	TTSSettings           TTSConfig        `json:"tts_settings"`
+	MaxQueueSize          int              `json:"max_queue_size"`
+	DailyCharacterBudget  int              `json:"daily_character_budget,omitempty"`
+	PremiumVoiceBudget    int              `json:"premium_voice_budget,omitempty"` // Daily characters of Wavenet, Neural2 and other premium voices; 0 is no separate cap
+	ContentRetention      ContentRetention `json:"content_retention,omitempty"`
+	AnnounceVoiceEvents   bool             `json:"announce_voice_events,omitempty"`
+	ReadReactions         bool             `json:"read_reactions,omitempty"`            // Speak summaries of reactions on recent messages
+	AnnounceSkipped       bool             `json:"announce_skipped,omitempty"`          // Speak a note when messages were skipped for their author or by moderation
+	VoiceCommands         bool             `json:"voice_commands,omitempty"`            // Listen for spoken skip, pause and resume commands
+	OptInNoticeDM         bool             `json:"opt_in_notice_dm,omitempty"`          // DM users who are opted in automatically
+	AutoOptInVoiceMembers bool             `json:"auto_opt_in_voice_members,omitempty"` // Opt in members of the bot's voice channel who never chose
+	TranscriptsEnabled    bool             `json:"transcripts_enabled,omitempty"`       // Record what is spoken for /darrot-transcript
+	Language              string           `json:"language,omitempty"`
+	IgnorePrefixes        []string         `json:"ignore_prefixes,omitempty"` // Messages starting with these are not read aloud
+	SpeakerRoles          []string         `json:"speaker_roles,omitempty"`   // Only members with one of these roles are read; empty reads everyone
+	ReadBots              bool             `json:"read_bots,omitempty"`       // Read messages from every other bot
+	ReadWebhooks          bool             `json:"read_webhooks,omitempty"`   // Read messages sent through webhooks
+	AllowedBots           []string         `json:"allowed_bots,omitempty"`    // Bots read even when ReadBots is off
+	CommandPrefix         string           `json:"command_prefix,omitempty"`  // Starts text commands; empty uses DefaultCommandPrefix, CommandPrefixOff turns them off
+	LinkMode              LinkMode         `json:"link_mode,omitempty"`
+	CodeBlockMode         CodeBlockMode    `json:"code_block_mode,omitempty"`
+	SpoilerMode           SpoilerMode      `json:"spoiler_mode,omitempty"`
+	AllowNSFWChannels     bool             `json:"allow_nsfw_channels,omitempty"` // Allow pairing with age-restricted text channels
+	TruncationMode        TruncationMode   `json:"truncation_mode,omitempty"`
+	QueueOrder            QueueOrder       `json:"queue_order,omitempty"`              // Empty reads in FIFO order
+	AudioOutput           AudioOutput      `json:"audio_output,omitempty"`             // Where speech also goes; empty is only Discord
+	RecordSessions        bool             `json:"record_sessions,omitempty"`          // Record each voice session and post it on /darrot-leave
+	RecordTimestamps      bool             `json:"record_timestamps,omitempty"`        // Post WebVTT timestamps of each utterance with the recording
+	HoldBackSeconds       int              `json:"hold_back_seconds,omitempty"`        // Wait for more messages from the same author before reading; 0 reads right away
+	CatchUpPolicy         CatchUpPolicy    `json:"catch_up_policy,omitempty"`          // What happens to messages older than MessageAgeCutoff; empty reads them
+	MessageAgeCutoff      int              `json:"message_age_cutoff,omitempty"`       // Seconds after which a message is caught up on; 0 uses DefaultMessageAgeCutoff
+	PauseMillis           int              `json:"pause_millis,omitempty"`             // Pause between messages when the queue is empty or the channel has slow mode; 0 is none
+	AuthorPauseMillis     int              `json:"author_pause_millis,omitempty"`      // Longer pause when the next message is from someone else; 0 is none
+	MaxUtteranceLength    int              `json:"max_utterance_length,omitempty"`     // 0 uses DefaultMaxMessageLength
+	MaxSpokenEmoji        int              `json:"max_spoken_emoji,omitempty"`         // 0 uses DefaultMaxSpokenEmoji
+	IdleAnnounceMinutes   int              `json:"idle_announce_minutes,omitempty"`    // 0 uses DefaultIdleAnnounceMinutes, negative turns it off
+	IdleDisconnectMinutes int              `json:"idle_disconnect_minutes,omitempty"`  // 0 never leaves
+	MinListeners          int              `json:"min_listeners,omitempty"`            // Human listeners needed in the voice channel before messages are read; 0 reads to anyone
+	QuietHoursStart       string           `json:"quiet_hours_start,omitempty"`        // HH:MM when the bot stops speaking each day; empty has no quiet hours
+	QuietHoursEnd         string           `json:"quiet_hours_end,omitempty"`          // HH:MM when it speaks again
+	Timezone              string           `json:"timezone,omitempty"`                 // IANA time zone of the quiet hours; empty is UTC
+	AuditChannelID        string           `json:"audit_channel_id,omitempty"`         // Receives audit log entries; empty turns auditing off
+	UserMessagesPerMinute int              `json:"user_messages_per_minute,omitempty"` // Messages read per user per minute; 0 is unlimited
+	ControlsPerMinute     int              `json:"controls_per_minute,omitempty"`      // Join and control actions per member per minute; 0 uses DefaultControlsPerMinute, negative is unlimited
+	ControlInVoiceOnly    bool             `json:"control_in_voice_only,omitempty"`    // Only members in the bot's voice channel may steer it
+	ActiveProfile         string           `json:"active_profile,omitempty"`           // Name of the profile last switched to; empty when none was used
+	Features              map[string]bool  `json:"features,omitempty"`                 // Experimental features the guild turned on or off; unset ones use the operator's defaults
+	UpdatedAt             time.Time        `json:"updated_at"`
+}
+
+// GuildProfile is a named preset of a guild's voice, queue and moderation settings
+// ("movie night", "raid calls") that administrators switch between
+type GuildProfile struct {
+	Name         string              `json:"name"`
+	TTSSettings  TTSConfig           `json:"tts_settings"`
+	MaxQueueSize int                 `json:"max_queue_size"`
+	Moderation   *ModerationSettings `json:"moderation,omitempty"` // Nil leaves moderation unchanged when the profile is used
+	CreatedBy    string              `json:"created_by,omitempty"`
+	UpdatedAt    time.Time           `json:"updated_at"`
+}
+
+// GuildProfiles holds the configuration profiles of a guild
+type GuildProfiles struct {
+	GuildID   string         `json:"guild_id"`
+	Profiles  []GuildProfile `json:"profiles"`
+	UpdatedAt time.Time      `json:"updated_at"`
 }
 
 // UserTTSPreferences holds user-specific TTS preferences
 type UserTTSPreferences struct {
-	UserID    string          `json:"user_id"`
-	GuildID   string          `json:"guild_id"`
-	OptedIn   bool            `json:"opted_in"`
-	Settings  UserTTSSettings `json:"settings"`
-	UpdatedAt time.Time       `json:"updated_at"`
+	UserID       string          `json:"user_id"`
+	GuildID      string          `json:"guild_id"`
+	OptedIn      bool            `json:"opted_in"`
+	Settings     UserTTSSettings `json:"settings"`
+	MutedUsers   []string        `json:"muted_users,omitempty"`   // Users whose messages are not read while this user listens
+	ReadChannels []string        `json:"read_channels,omitempty"` // Paired text channels this user's messages are read from; empty reads all of them
+	Consent      []ConsentRecord `json:"consent,omitempty"`       // Opt-in changes, oldest first
+	UpdatedAt    time.Time       `json:"updated_at"`
+}
+
+// ConsentMethod is how a user's opt-in status was changed
+type ConsentMethod string
+
+// Consent methods
+const (
+	ConsentMethodCommand ConsentMethod = "command" // The user ran an opt-in command
+	ConsentMethodAuto    ConsentMethod = "auto"    // The bot opted the user in automatically
+	ConsentMethodAdmin   ConsentMethod = "admin"   // An administrator changed it
+	ConsentMethodNotice  ConsentMethod = "notice"  // The user opted out from a privacy notice
+)
+
+// ConsentRecord is an entry of a user's consent audit trail
+type ConsentRecord struct {
+	OptedIn   bool          `json:"opted_in"`
+	Method    ConsentMethod `json:"method"`
+	ActorID   string        `json:"actor_id,omitempty"` // Who made the change, empty for the bot
+	Timestamp time.Time     `json:"timestamp"`
 }
 
 // UserTTSSettings holds user-specific TTS settings
@@ -92,14 +318,134 @@ type UserTTSSettings struct {
 	SpeedModifier  float32 `json:"speed_modifier"`
 }
 
+// QuotaUsage records the characters synthesized for a guild on a single UTC day
+type QuotaUsage struct {
+	GuildID           string         `json:"guild_id"`
+	Date              string         `json:"date"`
+	CharactersUsed    int            `json:"characters_used"`
+	TierCharacters    map[string]int `json:"tier_characters,omitempty"`     // Characters per voice tier, such as "Standard" or "Neural2"
+	PremiumCapReached bool           `json:"premium_cap_reached,omitempty"` // The premium voice budget ran out and was audited
+	UpdatedAt         time.Time      `json:"updated_at"`
+}
+
+// PremiumCharacters returns the characters synthesized with voices above the Standard tier
+func (u *QuotaUsage) PremiumCharacters() int {
+	total := 0
+	for tier, characters := range u.TierCharacters {
+		if tier != standardTier {
+			total += characters
+		}
+	}
+	return total
+}
+
+// GuildStats records a guild's TTS usage since StartedAt
+type GuildStats struct {
+	GuildID               string                   `json:"guild_id"`
+	MessagesRead          int                      `json:"messages_read"`
+	CharactersSynthesized int                      `json:"characters_synthesized"`
+	AudioPlayedMs         int64                    `json:"audio_played_ms"`
+	Skips                 int                      `json:"skips"`
+	Speakers              map[string]*SpeakerStats `json:"speakers,omitempty"` // Keyed by user ID
+	StartedAt             time.Time                `json:"started_at"`
+	UpdatedAt             time.Time                `json:"updated_at"`
+}
+
+// SpeakerStats records how many of a user's messages were read aloud in a guild
+type SpeakerStats struct {
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+	Messages int    `json:"messages"`
+}
+
+// GuildAPITokens holds the tokens external systems use to queue messages in a guild
+// through the HTTP API
+type GuildAPITokens struct {
+	GuildID string     `json:"guild_id"`
+	Tokens  []APIToken `json:"tokens"`
+}
+
+// APIToken authorizes an external system, such as a stream overlay or game server, to
+// queue messages in a guild. Only a hash of the secret is stored.
+type APIToken struct {
+	Name      string    `json:"name"`            // Default tag of the messages queued with the token
+	Hash      string    `json:"hash"`            // Hex-encoded SHA-256 of the secret
+	Voice     string    `json:"voice,omitempty"` // Reads the token's messages with this voice; empty uses the guild's
+	CreatedBy string    `json:"created_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// GuildTranscripts holds a guild's recent voice session transcripts, oldest first
+type GuildTranscripts struct {
+	GuildID  string              `json:"guild_id"`
+	Sessions []TranscriptSession `json:"sessions,omitempty"`
+}
+
+// TranscriptSession records what was spoken from the moment the bot started reading in
+// a guild until it left. EndedAt is zero while the session is ongoing.
+type TranscriptSession struct {
+	StartedAt time.Time         `json:"started_at"`
+	EndedAt   time.Time         `json:"ended_at"`
+	Entries   []TranscriptEntry `json:"entries"`
+	Truncated bool              `json:"truncated,omitempty"` // Set once MaxTranscriptEntries was reached
+}
+
+// TranscriptEntry is one utterance spoken in a voice session. Text is empty in guilds
+// that retain metadata only.
+type TranscriptEntry struct {
+	UserID     string    `json:"user_id,omitempty"`
+	Username   string    `json:"username,omitempty"`
+	Text       string    `json:"text,omitempty"`
+	SpokenAt   time.Time `json:"spoken_at"`
+	DurationMs int64     `json:"duration_ms"`
+}
+
+// UserDataExport is everything stored about a user in a guild, as handed to them by
+// /darrot-privacy export
+type UserDataExport struct {
+	UserID      string              `json:"user_id"`
+	GuildID     string              `json:"guild_id"`
+	ExportedAt  time.Time           `json:"exported_at"`
+	Preferences *UserTTSPreferences `json:"preferences,omitempty"` // Opt-in status, settings, muted users and consent history
+	Statistics  *SpeakerStats       `json:"statistics,omitempty"`
+	Transcripts []TranscriptEntry   `json:"transcripts,omitempty"` // What was read aloud from the user
+}
+
+// VoiceHandoff records the voice sessions that were active when the bot last stopped,
+// so they can be resumed on the next start
+type VoiceHandoff struct {
+	Sessions []HandoffSession `json:"sessions"`
+	SavedAt  time.Time        `json:"saved_at"`
+}
+
+// HandoffSession is a single voice session to resume after a restart
+type HandoffSession struct {
+	GuildID         string `json:"guild_id"`
+	VoiceChannelID  string `json:"voice_channel_id"`
+	TextChannelID   string `json:"text_channel_id"`
+	CreatedBy       string `json:"created_by"`
+	RequirePresence bool   `json:"require_presence,omitempty"`
+	Greeting        string `json:"greeting,omitempty"`
+	ReadPinned      bool   `json:"read_pinned,omitempty"`
+	MirrorChannelID string `json:"mirror_channel_id,omitempty"`
+	MirrorOnly      bool   `json:"mirror_only,omitempty"`
+
+	Queue []*QueuedMessage `json:"queue,omitempty"` // Messages still waiting to be read
+}
+
 // ChannelPairingStorage represents stored channel pairing data
 type ChannelPairingStorage struct {
-	GuildID        string    `json:"guild_id"`
-	VoiceChannelID string    `json:"voice_channel_id"`
-	TextChannelID  string    `json:"text_channel_id"`
-	CreatedBy      string    `json:"created_by"`
-	CreatedAt      time.Time `json:"created_at"`
-	IsActive       bool      `json:"is_active"`
+	GuildID         string    `json:"guild_id"`
+	VoiceChannelID  string    `json:"voice_channel_id"`
+	TextChannelID   string    `json:"text_channel_id"`
+	CreatedBy       string    `json:"created_by"`
+	CreatedAt       time.Time `json:"created_at"`
+	IsActive        bool      `json:"is_active"`
+	RequirePresence bool      `json:"require_presence,omitempty"`
+	Greeting        string    `json:"greeting,omitempty"`
+	ReadPinned      bool      `json:"read_pinned,omitempty"`
+	MirrorChannelID string    `json:"mirror_channel_id,omitempty"`
+	MirrorOnly      bool      `json:"mirror_only,omitempty"`
 }
 
 // VoiceSession represents an active voice session with TTS
@@ -2,6 +2,7 @@ package tts
 
 import (
 	"fmt"
+	"slices"
 	"time"
 )
 
@@ -17,8 +18,20 @@ func NewUserService(storage *StorageService) *UserServiceImpl {
 	}
 }
 
-// SetOptInStatus sets the opt-in status for a user in a specific guild
+// MaxConsentHistory is how many opt-in changes are kept per user and guild. Older
+// changes are dropped first.
+const MaxConsentHistory = 50
+
+// SetOptInStatus sets the opt-in status for a user in a specific guild. The change is
+// recorded as made by the user with a command.
 func (u *UserServiceImpl) SetOptInStatus(userID, guildID string, optedIn bool) error {
+	return u.SetOptInStatusBy(userID, guildID, optedIn, ConsentMethodCommand, userID)
+}
+
+// SetOptInStatusBy sets the opt-in status for a user in a specific guild and records in
+// the user's consent history who changed it and how. Setting the status the user already
+// has is not recorded.
+func (u *UserServiceImpl) SetOptInStatusBy(userID, guildID string, optedIn bool, method ConsentMethod, actorID string) error {
 	if userID == "" {
 		return fmt.Errorf("user ID cannot be empty")
 	}
@@ -27,6 +40,7 @@ func (u *UserServiceImpl) SetOptInStatus(userID, guildID string, optedIn bool) e
 	}
 
 	// Load existing preferences or create default ones
+	existed := u.storage.HasUserPreferences(userID, guildID)
 	prefs, err := u.storage.LoadUserPreferences(userID, guildID)
 	if err != nil {
 		// If loading fails, create default preferences
@@ -34,6 +48,19 @@ func (u *UserServiceImpl) SetOptInStatus(userID, guildID string, optedIn bool) e
 		prefs = &defaultPrefs
 	}
 
+	// The audit entry is saved with the change, so neither is stored without the other
+	if !existed || prefs.OptedIn != optedIn {
+		prefs.Consent = append(prefs.Consent, ConsentRecord{
+			OptedIn:   optedIn,
+			Method:    method,
+			ActorID:   actorID,
+			Timestamp: time.Now(),
+		})
+		if excess := len(prefs.Consent) - MaxConsentHistory; excess > 0 {
+			prefs.Consent = slices.Delete(prefs.Consent, 0, excess)
+		}
+	}
+
 	// Update opt-in status
 	prefs.OptedIn = optedIn
 	prefs.UpdatedAt = time.Now()
@@ -46,6 +73,60 @@ func (u *UserServiceImpl) SetOptInStatus(userID, guildID string, optedIn bool) e
 	return nil
 }
 
+// GetConsentHistory returns the recorded opt-in changes of a user in a guild, oldest first
+func (u *UserServiceImpl) GetConsentHistory(userID, guildID string) ([]ConsentRecord, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("user ID cannot be empty")
+	}
+	if guildID == "" {
+		return nil, fmt.Errorf("guild ID cannot be empty")
+	}
+
+	prefs, err := u.storage.LoadUserPreferences(userID, guildID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load user preferences: %w", err)
+	}
+
+	return prefs.Consent, nil
+}
+
+// setOptInStatusBy sets a user's opt-in status through userService, recording who changed
+// it and how when the service keeps a consent history
+func setOptInStatusBy(userService UserService, userID, guildID string, optedIn bool, method ConsentMethod, actorID string) error {
+	if auditor, ok := userService.(ConsentAuditor); ok {
+		return auditor.SetOptInStatusBy(userID, guildID, optedIn, method, actorID)
+	}
+	return userService.SetOptInStatus(userID, guildID, optedIn)
+}
+
+// ExportUserData adds a user's preferences in a guild, including their consent history,
+// to export
+func (u *UserServiceImpl) ExportUserData(userID, guildID string, export *UserDataExport) error {
+	if !u.storage.HasUserPreferences(userID, guildID) {
+		return nil
+	}
+
+	prefs, err := u.storage.LoadUserPreferences(userID, guildID)
+	if err != nil {
+		return fmt.Errorf("failed to load user preferences: %w", err)
+	}
+	export.Preferences = prefs
+	return nil
+}
+
+// DeleteUserData deletes a user's preferences in a guild, including their consent
+// history. The user is left opted out, so automatic opt-in does not pick them up again.
+func (u *UserServiceImpl) DeleteUserData(userID, guildID string) error {
+	if err := u.storage.RemoveUserPreferences(userID, guildID); err != nil {
+		return err
+	}
+
+	if err := u.storage.SaveUserPreferences(DefaultUserPreferences(userID, guildID)); err != nil {
+		return fmt.Errorf("failed to save user preferences: %w", err)
+	}
+	return nil
+}
+
 // IsOptedIn checks if a user has opted in for TTS in a specific guild
 func (u *UserServiceImpl) IsOptedIn(userID, guildID string) (bool, error) {
 	if userID == "" {
@@ -100,13 +181,36 @@ func (u *UserServiceImpl) AutoOptIn(userID, guildID string) error {
 	}
 
 	// Auto opt-in the user
-	if err := u.SetOptInStatus(userID, guildID, true); err != nil {
+	if err := u.SetOptInStatusBy(userID, guildID, true, ConsentMethodAuto, userID); err != nil {
 		return fmt.Errorf("failed to auto opt-in user: %w", err)
 	}
 
 	return nil
 }
 
+// OptInVoiceMember opts in a user who is in the bot's voice channel of a guild that opts
+// voice channel members in automatically. Only users who never chose are opted in: users
+// who opted in or out themselves, or were opted out by an administrator, keep their
+// choice. It reports whether the user was opted in by this call.
+func (u *UserServiceImpl) OptInVoiceMember(userID, guildID string) (bool, error) {
+	if userID == "" {
+		return false, fmt.Errorf("user ID cannot be empty")
+	}
+	if guildID == "" {
+		return false, fmt.Errorf("guild ID cannot be empty")
+	}
+
+	if u.storage.HasUserPreferences(userID, guildID) {
+		return false, nil
+	}
+
+	if err := u.SetOptInStatusBy(userID, guildID, true, ConsentMethodAuto, ""); err != nil {
+		return false, fmt.Errorf("failed to opt in voice channel member: %w", err)
+	}
+
+	return true, nil
+}
+
 // GetUserPreferences returns the full TTS preferences for a user in a specific guild
 func (u *UserServiceImpl) GetUserPreferences(userID, guildID string) (*UserTTSPreferences, error) {
 	if userID == "" {
@@ -157,3 +261,136 @@ func (u *UserServiceImpl) UpdateUserSettings(userID, guildID string, settings Us
 
 	return nil
 }
+
+// MuteUser stops targetID's messages from being read while listenerID is in the voice channel
+func (u *UserServiceImpl) MuteUser(listenerID, targetID, guildID string) error {
+	return u.updateMutedUsers(listenerID, targetID, guildID, func(muted []string) []string {
+		if slices.Contains(muted, targetID) {
+			return muted
+		}
+		return append(muted, targetID)
+	})
+}
+
+// UnmuteUser removes targetID from listenerID's mute list
+func (u *UserServiceImpl) UnmuteUser(listenerID, targetID, guildID string) error {
+	return u.updateMutedUsers(listenerID, targetID, guildID, func(muted []string) []string {
+		return slices.DeleteFunc(muted, func(id string) bool { return id == targetID })
+	})
+}
+
+// GetMutedUsers returns the users listenerID has muted in a specific guild
+func (u *UserServiceImpl) GetMutedUsers(listenerID, guildID string) ([]string, error) {
+	if listenerID == "" {
+		return nil, fmt.Errorf("user ID cannot be empty")
+	}
+	if guildID == "" {
+		return nil, fmt.Errorf("guild ID cannot be empty")
+	}
+
+	prefs, err := u.storage.LoadUserPreferences(listenerID, guildID)
+	if err != nil {
+		// No preferences means nobody has been muted
+		return nil, nil
+	}
+
+	return prefs.MutedUsers, nil
+}
+
+// AddReadChannel adds a text channel to the channels a user's messages are read from
+func (u *UserServiceImpl) AddReadChannel(userID, guildID, channelID string) error {
+	return u.updateReadChannels(userID, guildID, channelID, func(channels []string) []string {
+		if slices.Contains(channels, channelID) {
+			return channels
+		}
+		return append(channels, channelID)
+	})
+}
+
+// RemoveReadChannel removes a text channel from the channels a user's messages are read
+// from. Removing the last one reads every paired channel again.
+func (u *UserServiceImpl) RemoveReadChannel(userID, guildID, channelID string) error {
+	return u.updateReadChannels(userID, guildID, channelID, func(channels []string) []string {
+		return slices.DeleteFunc(channels, func(id string) bool { return id == channelID })
+	})
+}
+
+// GetReadChannels returns the text channels a user's messages are read from in a guild,
+// or nil when they are read from every paired channel
+func (u *UserServiceImpl) GetReadChannels(userID, guildID string) ([]string, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("user ID cannot be empty")
+	}
+	if guildID == "" {
+		return nil, fmt.Errorf("guild ID cannot be empty")
+	}
+
+	prefs, err := u.storage.LoadUserPreferences(userID, guildID)
+	if err != nil {
+		// No preferences means no channels were chosen
+		return nil, nil
+	}
+
+	return prefs.ReadChannels, nil
+}
+
+// updateReadChannels loads a user's preferences, applies a change to their read channels
+// and saves them
+func (u *UserServiceImpl) updateReadChannels(userID, guildID, channelID string, change func(channels []string) []string) error {
+	if userID == "" {
+		return fmt.Errorf("user ID cannot be empty")
+	}
+	if guildID == "" {
+		return fmt.Errorf("guild ID cannot be empty")
+	}
+	if channelID == "" {
+		return fmt.Errorf("channel ID cannot be empty")
+	}
+
+	// Load existing preferences or create default ones
+	prefs, err := u.storage.LoadUserPreferences(userID, guildID)
+	if err != nil {
+		defaultPrefs := DefaultUserPreferences(userID, guildID)
+		prefs = &defaultPrefs
+	}
+
+	prefs.ReadChannels = change(prefs.ReadChannels)
+	prefs.UpdatedAt = time.Now()
+
+	// Save updated preferences
+	if err := u.storage.SaveUserPreferences(*prefs); err != nil {
+		return fmt.Errorf("failed to save user preferences: %w", err)
+	}
+
+	return nil
+}
+
+// updateMutedUsers loads a listener's preferences, applies a change to the mute list and saves them
+func (u *UserServiceImpl) updateMutedUsers(listenerID, targetID, guildID string, change func(muted []string) []string) error {
+	if listenerID == "" || targetID == "" {
+		return fmt.Errorf("user ID cannot be empty")
+	}
+	if guildID == "" {
+		return fmt.Errorf("guild ID cannot be empty")
+	}
+	if listenerID == targetID {
+		return fmt.Errorf("you cannot mute yourself")
+	}
+
+	// Load existing preferences or create default ones
+	prefs, err := u.storage.LoadUserPreferences(listenerID, guildID)
+	if err != nil {
+		defaultPrefs := DefaultUserPreferences(listenerID, guildID)
+		prefs = &defaultPrefs
+	}
+
+	prefs.MutedUsers = change(prefs.MutedUsers)
+	prefs.UpdatedAt = time.Now()
+
+	// Save updated preferences
+	if err := u.storage.SaveUserPreferences(*prefs); err != nil {
+		return fmt.Errorf("failed to save user preferences: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+package tts
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// UserDataHolder is implemented by services that keep data about individual users, so
+// it can be handed to a user or deleted at their request
+type UserDataHolder interface {
+	ExportUserData(userID, guildID string, export *UserDataExport) error
+	DeleteUserData(userID, guildID string) error
+}
+
+// UserDataService answers data-protection requests: it collects everything stored about
+// a user in a guild from every service that keeps such data, and deletes it from all of
+// them
+type UserDataService struct {
+	storage *StorageService
+	holders []UserDataHolder
+	now     func() time.Time
+}
+
+// NewUserDataService creates a user data service over the given services. Services that
+// keep no data about individual users are skipped.
+func NewUserDataService(storage *StorageService, services ...any) *UserDataService {
+	var holders []UserDataHolder
+	for _, service := range services {
+		if holder, ok := service.(UserDataHolder); ok {
+			holders = append(holders, holder)
+		}
+	}
+
+	return &UserDataService{
+		storage: storage,
+		holders: holders,
+		now:     time.Now,
+	}
+}
+
+// Export returns everything stored about a user in a guild
+func (s *UserDataService) Export(userID, guildID string) (*UserDataExport, error) {
+	if userID == "" || guildID == "" {
+		return nil, fmt.Errorf("user ID and guild ID are required")
+	}
+
+	export := &UserDataExport{UserID: userID, GuildID: guildID, ExportedAt: s.now()}
+	for _, holder := range s.holders {
+		if err := holder.ExportUserData(userID, guildID, export); err != nil {
+			return nil, err
+		}
+	}
+	return export, nil
+}
+
+// Delete deletes everything stored about a user in a guild. A service that fails does not
+// stop the others; their errors are returned together. The guild's backups are discarded
+// afterwards so no earlier copy of the data is left.
+func (s *UserDataService) Delete(userID, guildID string) error {
+	if userID == "" || guildID == "" {
+		return fmt.Errorf("user ID and guild ID are required")
+	}
+
+	var errs []error
+	for _, holder := range s.holders {
+		if err := holder.DeleteUserData(userID, guildID); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if s.storage != nil {
+		if err := s.storage.DiscardGuildBackups(guildID); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,127 @@
+package tts
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createTestUserDataService returns a user data service over real user, stats and
+// transcript services that share storage, with data recorded for user1 and user2
+func createTestUserDataService(t *testing.T) (*UserDataService, *UserServiceImpl, *StatsServiceImpl, *TranscriptServiceImpl, *StorageService) {
+	transcriptService, configService, storage := createTestTranscriptService(t)
+	enableTranscripts(t, configService, "guild1")
+	userService := NewUserService(storage)
+	statsService := NewStatsService(storage)
+
+	require.NoError(t, userService.SetOptInStatus("user1", "guild1", true))
+	require.NoError(t, userService.SetOptInStatus("user2", "guild1", true))
+	require.NoError(t, statsService.RecordMessage("guild1", "user1", "alice"))
+	require.NoError(t, statsService.RecordMessage("guild1", "user2", "bob"))
+	require.NoError(t, transcriptService.StartSession("guild1"))
+	require.NoError(t, transcriptService.RecordUtterance("guild1", TranscriptEntry{UserID: "user1", Username: "alice", Text: "alice says: hi"}))
+	require.NoError(t, transcriptService.RecordUtterance("guild1", TranscriptEntry{UserID: "user2", Username: "bob", Text: "bob says: hello"}))
+
+	// The config service keeps no data about users, so it is skipped
+	service := NewUserDataService(storage, userService, statsService, transcriptService, configService)
+	require.Len(t, service.holders, 3)
+	return service, userService, statsService, transcriptService, storage
+}
+
+func TestUserDataService_Export(t *testing.T) {
+	service, _, _, _, _ := createTestUserDataService(t)
+
+	export, err := service.Export("user1", "guild1")
+	require.NoError(t, err)
+
+	assert.Equal(t, "user1", export.UserID)
+	assert.False(t, export.ExportedAt.IsZero())
+	require.NotNil(t, export.Preferences)
+	assert.True(t, export.Preferences.OptedIn)
+	assert.Len(t, export.Preferences.Consent, 1)
+	require.NotNil(t, export.Statistics)
+	assert.Equal(t, 1, export.Statistics.Messages)
+	require.Len(t, export.Transcripts, 1)
+	assert.Equal(t, "alice says: hi", export.Transcripts[0].Text)
+
+	// Users the bot never stored anything about get an empty export
+	export, err = service.Export("stranger", "guild1")
+	require.NoError(t, err)
+	assert.Nil(t, export.Preferences)
+	assert.Nil(t, export.Statistics)
+	assert.Empty(t, export.Transcripts)
+
+	_, err = service.Export("", "guild1")
+	assert.Error(t, err)
+}
+
+func TestUserDataService_Delete(t *testing.T) {
+	service, userService, statsService, transcriptService, storage := createTestUserDataService(t)
+
+	require.NoError(t, service.Delete("user1", "guild1"))
+
+	// Everything about user1 is gone, and they are left opted out
+	export, err := service.Export("user1", "guild1")
+	require.NoError(t, err)
+	require.NotNil(t, export.Preferences)
+	assert.False(t, export.Preferences.OptedIn)
+	assert.Empty(t, export.Preferences.Consent)
+	assert.Nil(t, export.Statistics)
+	assert.Empty(t, export.Transcripts)
+
+	optedIn, err := userService.OptInVoiceMember("user1", "guild1")
+	require.NoError(t, err)
+	assert.False(t, optedIn, "deleted users are not opted in automatically again")
+
+	// Other users and the guild's totals are kept
+	stats, err := statsService.GetStats("guild1")
+	require.NoError(t, err)
+	assert.Equal(t, 2, stats.MessagesRead)
+	assert.Contains(t, stats.Speakers, "user2")
+	session, err := transcriptService.LatestSession("guild1")
+	require.NoError(t, err)
+	require.Len(t, session.Entries, 1)
+	assert.Equal(t, "user2", session.Entries[0].UserID)
+
+	// No backup holds an earlier copy of the deleted data
+	backups, err := filepath.Glob(filepath.Join(storage.dataDir, "*_guild1.json"+backupSuffix))
+	require.NoError(t, err)
+	assert.Empty(t, backups)
+	_, err = os.Stat(filepath.Join(storage.dataDir, "stats_guild1.json"))
+	assert.NoError(t, err)
+}
+
+// failingUserDataHolder fails to delete
+type failingUserDataHolder struct {
+	deleted bool
+}
+
+func (f *failingUserDataHolder) ExportUserData(userID, guildID string, export *UserDataExport) error {
+	return nil
+}
+
+func (f *failingUserDataHolder) DeleteUserData(userID, guildID string) error {
+	f.deleted = true
+	return errors.New("disk full")
+}
+
+func TestUserDataService_DeleteContinuesAfterFailure(t *testing.T) {
+	storage, err := NewStorageService(t.TempDir())
+	require.NoError(t, err)
+	userService := NewUserService(storage)
+	require.NoError(t, userService.SetOptInStatus("user1", "guild1", true))
+	failing := &failingUserDataHolder{}
+
+	err = NewUserDataService(storage, failing, userService).Delete("user1", "guild1")
+
+	// The failure is reported, and the other services still delete
+	assert.ErrorContains(t, err, "disk full")
+	assert.True(t, failing.deleted)
+	history, err := userService.GetConsentHistory("user1", "guild1")
+	require.NoError(t, err)
+	assert.Empty(t, history)
+}
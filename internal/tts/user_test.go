@@ -1,7 +1,9 @@
 package tts
 
 import (
+	"fmt"
 	"os"
+	"reflect"
 	"testing"
 )
 
@@ -971,3 +973,240 @@ func TestUserService_Integration(t *testing.T) {
 		}
 	})
 }
+
+func TestUserService_MuteUsers(t *testing.T) {
+	storage, err := NewStorageService(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create storage service: %v", err)
+	}
+
+	userService := NewUserService(storage)
+
+	if err := userService.MuteUser("listener1", "talker1", "guild1"); err != nil {
+		t.Fatalf("MuteUser() error = %v", err)
+	}
+	// Muting twice is a no-op
+	if err := userService.MuteUser("listener1", "talker1", "guild1"); err != nil {
+		t.Fatalf("MuteUser() error = %v", err)
+	}
+	if err := userService.MuteUser("listener1", "talker2", "guild1"); err != nil {
+		t.Fatalf("MuteUser() error = %v", err)
+	}
+
+	muted, err := userService.GetMutedUsers("listener1", "guild1")
+	if err != nil {
+		t.Fatalf("GetMutedUsers() error = %v", err)
+	}
+	if len(muted) != 2 || muted[0] != "talker1" || muted[1] != "talker2" {
+		t.Errorf("GetMutedUsers() = %v, want [talker1 talker2]", muted)
+	}
+
+	// Mute lists are per listener and per guild
+	if muted, _ := userService.GetMutedUsers("listener2", "guild1"); len(muted) != 0 {
+		t.Errorf("GetMutedUsers() for another listener = %v, want empty", muted)
+	}
+	if muted, _ := userService.GetMutedUsers("listener1", "guild2"); len(muted) != 0 {
+		t.Errorf("GetMutedUsers() for another guild = %v, want empty", muted)
+	}
+
+	if err := userService.UnmuteUser("listener1", "talker1", "guild1"); err != nil {
+		t.Fatalf("UnmuteUser() error = %v", err)
+	}
+	muted, _ = userService.GetMutedUsers("listener1", "guild1")
+	if len(muted) != 1 || muted[0] != "talker2" {
+		t.Errorf("GetMutedUsers() after unmute = %v, want [talker2]", muted)
+	}
+
+	// Muting does not change opt-in status
+	if optedIn, _ := userService.IsOptedIn("listener1", "guild1"); optedIn {
+		t.Error("MuteUser() should not opt the listener in")
+	}
+
+	if err := userService.MuteUser("listener1", "listener1", "guild1"); err == nil {
+		t.Error("MuteUser() should reject muting yourself")
+	}
+	if err := userService.MuteUser("", "talker1", "guild1"); err == nil {
+		t.Error("MuteUser() should reject an empty listener ID")
+	}
+
+	for i := 0; i < MaxMutedUsers; i++ {
+		if err := userService.MuteUser("listener3", fmt.Sprintf("talker%d", i), "guild1"); err != nil {
+			t.Fatalf("MuteUser() error = %v", err)
+		}
+	}
+	if err := userService.MuteUser("listener3", "one-too-many", "guild1"); err == nil {
+		t.Errorf("MuteUser() should reject more than %d muted users", MaxMutedUsers)
+	}
+}
+
+func TestUserService_ReadChannels(t *testing.T) {
+	storage, err := NewStorageService(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create storage service: %v", err)
+	}
+
+	userService := NewUserService(storage)
+
+	if channels, err := userService.GetReadChannels("user1", "guild1"); err != nil || channels != nil {
+		t.Fatalf("GetReadChannels() = %v, %v, want nil, nil for a new user", channels, err)
+	}
+
+	for _, channelID := range []string{"text1", "text2", "text1"} {
+		if err := userService.AddReadChannel("user1", "guild1", channelID); err != nil {
+			t.Fatalf("AddReadChannel() error = %v", err)
+		}
+	}
+	channels, err := userService.GetReadChannels("user1", "guild1")
+	if err != nil || !reflect.DeepEqual(channels, []string{"text1", "text2"}) {
+		t.Errorf("GetReadChannels() = %v, %v, want [text1 text2] without duplicates", channels, err)
+	}
+	if channels, _ := userService.GetReadChannels("user1", "guild2"); len(channels) != 0 {
+		t.Errorf("Expected read channels to be per guild, got %v", channels)
+	}
+	if optedIn, _ := userService.IsOptedIn("user1", "guild1"); optedIn {
+		t.Error("AddReadChannel() should not opt the user in")
+	}
+
+	if err := userService.RemoveReadChannel("user1", "guild1", "text1"); err != nil {
+		t.Fatalf("RemoveReadChannel() error = %v", err)
+	}
+	if channels, _ := userService.GetReadChannels("user1", "guild1"); !reflect.DeepEqual(channels, []string{"text2"}) {
+		t.Errorf("GetReadChannels() = %v after removing text1, want [text2]", channels)
+	}
+
+	if err := userService.AddReadChannel("user1", "guild1", ""); err == nil {
+		t.Error("AddReadChannel() should reject an empty channel ID")
+	}
+	for i := 0; i < MaxReadChannels; i++ {
+		if err := userService.AddReadChannel("user2", "guild1", fmt.Sprintf("text%d", i)); err != nil {
+			t.Fatalf("AddReadChannel() error = %v", err)
+		}
+	}
+	if err := userService.AddReadChannel("user2", "guild1", "one-too-many"); err == nil {
+		t.Errorf("AddReadChannel() should reject more than %d channels", MaxReadChannels)
+	}
+}
+
+func TestUserService_OptInVoiceMember(t *testing.T) {
+	storage, err := NewStorageService(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create storage service: %v", err)
+	}
+
+	userService := NewUserService(storage)
+
+	// Users who never chose are opted in once
+	optedIn, err := userService.OptInVoiceMember("newcomer", "guild1")
+	if err != nil || !optedIn {
+		t.Fatalf("OptInVoiceMember() = %v, %v, want true, nil", optedIn, err)
+	}
+	if isOptedIn, _ := userService.IsOptedIn("newcomer", "guild1"); !isOptedIn {
+		t.Error("Expected voice channel member to be opted in")
+	}
+	if optedIn, _ := userService.OptInVoiceMember("newcomer", "guild1"); optedIn {
+		t.Error("Expected an opted-in user not to be opted in again")
+	}
+
+	// Users who opted out, or were opted out by an administrator, keep their choice
+	if err := userService.SetOptInStatus("quiet", "guild1", false); err != nil {
+		t.Fatalf("SetOptInStatus() error = %v", err)
+	}
+	if optedIn, _ := userService.OptInVoiceMember("quiet", "guild1"); optedIn {
+		t.Error("Expected an opted-out user not to be opted in")
+	}
+	if isOptedIn, _ := userService.IsOptedIn("quiet", "guild1"); isOptedIn {
+		t.Error("Expected opted-out user to stay opted out")
+	}
+
+	if _, err := userService.OptInVoiceMember("", "guild1"); err == nil {
+		t.Error("Expected an error for an empty user ID")
+	}
+}
+
+func TestUserService_ConsentHistory(t *testing.T) {
+	storage, err := NewStorageService(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create storage service: %v", err)
+	}
+
+	userService := NewUserService(storage)
+
+	// Every change is recorded with who made it and how; repeating a status is not
+	if err := userService.AutoOptIn("user1", "guild1"); err != nil {
+		t.Fatalf("AutoOptIn() error = %v", err)
+	}
+	if err := userService.SetOptInStatus("user1", "guild1", true); err != nil {
+		t.Fatalf("SetOptInStatus() error = %v", err)
+	}
+	if err := userService.SetOptInStatusBy("user1", "guild1", false, ConsentMethodAdmin, "admin1"); err != nil {
+		t.Fatalf("SetOptInStatusBy() error = %v", err)
+	}
+	if err := userService.SetOptInStatus("user1", "guild1", true); err != nil {
+		t.Fatalf("SetOptInStatus() error = %v", err)
+	}
+
+	history, err := userService.GetConsentHistory("user1", "guild1")
+	if err != nil {
+		t.Fatalf("GetConsentHistory() error = %v", err)
+	}
+	expected := []ConsentRecord{
+		{OptedIn: true, Method: ConsentMethodAuto, ActorID: "user1"},
+		{OptedIn: false, Method: ConsentMethodAdmin, ActorID: "admin1"},
+		{OptedIn: true, Method: ConsentMethodCommand, ActorID: "user1"},
+	}
+	if len(history) != len(expected) {
+		t.Fatalf("Expected %d consent records, got %+v", len(expected), history)
+	}
+	for idx, record := range history {
+		if record.Timestamp.IsZero() {
+			t.Errorf("Expected record %d to be timestamped", idx)
+		}
+		record.Timestamp = expected[idx].Timestamp
+		if record != expected[idx] {
+			t.Errorf("Record %d = %+v, want %+v", idx, record, expected[idx])
+		}
+	}
+
+	// Other preference changes keep the history
+	if err := userService.MuteUser("user1", "user2", "guild1"); err != nil {
+		t.Fatalf("MuteUser() error = %v", err)
+	}
+	if history, _ := userService.GetConsentHistory("user1", "guild1"); len(history) != len(expected) {
+		t.Errorf("Expected muting to keep the consent history, got %d records", len(history))
+	}
+
+	// An administrator opting out a user who never chose is recorded too
+	if err := userService.SetOptInStatusBy("user3", "guild1", false, ConsentMethodAdmin, "admin1"); err != nil {
+		t.Fatalf("SetOptInStatusBy() error = %v", err)
+	}
+	if history, _ := userService.GetConsentHistory("user3", "guild1"); len(history) != 1 || history[0].OptedIn {
+		t.Errorf("Expected the opt-out to be recorded, got %+v", history)
+	}
+}
+
+func TestUserService_ConsentHistoryBound(t *testing.T) {
+	storage, err := NewStorageService(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create storage service: %v", err)
+	}
+
+	userService := NewUserService(storage)
+
+	for idx := 0; idx < MaxConsentHistory+5; idx++ {
+		if err := userService.SetOptInStatus("user1", "guild1", idx%2 == 0); err != nil {
+			t.Fatalf("SetOptInStatus() error = %v", err)
+		}
+	}
+
+	// The oldest changes are dropped first
+	history, err := userService.GetConsentHistory("user1", "guild1")
+	if err != nil {
+		t.Fatalf("GetConsentHistory() error = %v", err)
+	}
+	if len(history) != MaxConsentHistory {
+		t.Fatalf("Expected %d consent records, got %d", MaxConsentHistory, len(history))
+	}
+	if last := history[len(history)-1]; last.OptedIn != ((MaxConsentHistory+4)%2 == 0) {
+		t.Errorf("Expected the newest change to be kept, got %+v", last)
+	}
+}
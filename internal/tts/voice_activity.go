@@ -0,0 +1,74 @@
+package tts
+
+import (
+	"sync"
+	"time"
+)
+
+// Voice activity timing
+const (
+	// speechTimeout treats a user as silent when their transmission stopped without an end
+	// being reported, such as when they left the channel
+	speechTimeout = 1 * time.Second
+
+	// maxSpeechHold is how long voice auto-pause holds a guild's messages while people keep
+	// talking, so a long conversation cannot stall the queue
+	maxSpeechHold = 10 * time.Second
+)
+
+// VoiceActivity tracks who is talking in the bot's voice channels, from the speech the
+// voice manager receives. A nil *VoiceActivity reports every guild as silent.
+type VoiceActivity struct {
+	now func() time.Time
+
+	mu       sync.Mutex
+	speaking map[string]map[string]time.Time // Last frame received per guild and user
+}
+
+// NewVoiceActivity creates a voice activity tracker
+func NewVoiceActivity() *VoiceActivity {
+	return &VoiceActivity{
+		now:      time.Now,
+		speaking: make(map[string]map[string]time.Time),
+	}
+}
+
+// Observe records a frame of a user's speech. A nil frame means the user stopped
+// transmitting.
+func (a *VoiceActivity) Observe(guildID, userID string, pcm []int16) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	users := a.speaking[guildID]
+	if pcm == nil {
+		delete(users, userID)
+		if len(users) == 0 {
+			delete(a.speaking, guildID)
+		}
+		return
+	}
+
+	if users == nil {
+		users = make(map[string]time.Time)
+		a.speaking[guildID] = users
+	}
+	users[userID] = a.now()
+}
+
+// Speaking reports whether anyone is talking in a guild's voice channel
+func (a *VoiceActivity) Speaking(guildID string) bool {
+	if a == nil {
+		return false
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := a.now()
+	for _, lastFrame := range a.speaking[guildID] {
+		if now.Sub(lastFrame) < speechTimeout {
+			return true
+		}
+	}
+	return false
+}
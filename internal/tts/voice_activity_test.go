@@ -0,0 +1,38 @@
+package tts
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVoiceActivity(t *testing.T) {
+	now := time.Now()
+	activity := NewVoiceActivity()
+	activity.now = func() time.Time { return now }
+	frame := make([]int16, 960)
+
+	assert.False(t, activity.Speaking("guild1"))
+
+	activity.Observe("guild1", "user1", frame)
+	activity.Observe("guild1", "user2", frame)
+	assert.True(t, activity.Speaking("guild1"))
+	assert.False(t, activity.Speaking("guild2"))
+
+	// The guild is silent once everyone stopped transmitting
+	activity.Observe("guild1", "user1", nil)
+	assert.True(t, activity.Speaking("guild1"))
+	activity.Observe("guild1", "user2", nil)
+	assert.False(t, activity.Speaking("guild1"))
+
+	// Transmissions that never reported their end time out
+	activity.Observe("guild1", "user1", frame)
+	now = now.Add(speechTimeout)
+	assert.False(t, activity.Speaking("guild1"))
+}
+
+func TestVoiceActivity_Nil(t *testing.T) {
+	var activity *VoiceActivity
+	assert.False(t, activity.Speaking("guild1"))
+}
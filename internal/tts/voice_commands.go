@@ -0,0 +1,361 @@
+package tts
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// Voice command templates
+const (
+	maxTemplateVoices  = 3               // The guild's voice and voices of other genders in its language
+	templateRetryDelay = 1 * time.Minute // Wait after templates fail to synthesize before trying again
+)
+
+// VoiceCommandListener recognizes spoken commands ("parrot skip") in the voice channels of
+// guilds that turn voice commands on, and skips, pauses or resumes playback like
+// /darrot-control. Speech is matched locally by a KeywordSpotter against templates of
+// each phrase synthesized with the guild's voice; no audio is stored or sent to a speech
+// recognition service.
+type VoiceCommandListener struct {
+	voiceManager      VoiceManager
+	messageQueue      MessageQueue
+	configService     ConfigService
+	permissionService PermissionService
+	ttsManager        TTSManager
+	statsService      StatsService
+	mutePauser        *MutePauser
+	features          *FeatureFlagService
+	activity          *VoiceActivity
+	localizer         *Localizer
+	logger            *log.Logger
+	available         bool
+
+	now func() time.Time
+
+	// decode converts synthesized speech into the PCM templates are made from
+	decode func(audio []byte) ([]int16, error)
+
+	mu         sync.Mutex
+	segmenters map[string]*utteranceSegmenter // Speech being collected per guild and user
+	spotters   map[string]*guildSpotter       // Command templates per guild
+}
+
+// guildSpotter is the keyword spotter of a guild
+type guildSpotter struct {
+	key      string          // Language and voice the templates are made for
+	spotter  *KeywordSpotter // Nil until the templates are synthesized
+	failedAt time.Time       // When synthesizing the templates last failed
+}
+
+// NewVoiceCommandListener creates a voice command listener. Call Register to start
+// receiving speech from the voice manager.
+func NewVoiceCommandListener(
+	voiceManager VoiceManager,
+	messageQueue MessageQueue,
+	configService ConfigService,
+	permissionService PermissionService,
+	ttsManager TTSManager,
+	logger *log.Logger,
+) *VoiceCommandListener {
+	return &VoiceCommandListener{
+		voiceManager:      voiceManager,
+		messageQueue:      messageQueue,
+		configService:     configService,
+		permissionService: permissionService,
+		ttsManager:        ttsManager,
+		logger:            logger,
+		now:               time.Now,
+		decode:            decodeDCA,
+		segmenters:        make(map[string]*utteranceSegmenter),
+		spotters:          make(map[string]*guildSpotter),
+	}
+}
+
+// Register makes the voice manager listen in guilds with voice commands turned on. Voice
+// managers that cannot receive audio leave voice commands unavailable.
+func (l *VoiceCommandListener) Register() {
+	receiver, ok := l.voiceManager.(VoiceReceiver)
+	if !ok {
+		return
+	}
+	receiver.SetVoiceReceiver(l.listen, l.handleSpeech)
+	l.available = true
+}
+
+// Stop stops receiving speech and drops speech that was not recognized yet
+func (l *VoiceCommandListener) Stop() {
+	if receiver, ok := l.voiceManager.(VoiceReceiver); ok && l.available {
+		receiver.SetVoiceReceiver(nil, nil)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.segmenters = make(map[string]*utteranceSegmenter)
+}
+
+// SetLocalizer sets the localizer used to translate the command phrases
+func (l *VoiceCommandListener) SetLocalizer(localizer *Localizer) {
+	l.localizer = localizer
+}
+
+// SetStatsService sets the stats service used to count skipped messages
+func (l *VoiceCommandListener) SetStatsService(statsService StatsService) {
+	l.statsService = statsService
+}
+
+// SetMutePauser sets the mute pauser, so resuming while the bot is muted waits for unmute
+func (l *VoiceCommandListener) SetMutePauser(mutePauser *MutePauser) {
+	l.mutePauser = mutePauser
+}
+
+// SetFeatureFlags sets the service deciding which guilds use voice auto-pause
+func (l *VoiceCommandListener) SetFeatureFlags(features *FeatureFlagService) {
+	l.features = features
+}
+
+// SetVoiceActivity records who is talking for voice auto-pause. The listener also
+// listens in guilds that turn voice auto-pause on without voice commands.
+func (l *VoiceCommandListener) SetVoiceActivity(activity *VoiceActivity) {
+	l.activity = activity
+}
+
+// Available reports whether the voice manager can receive voice commands
+func (l *VoiceCommandListener) Available() bool {
+	return l.available
+}
+
+// Enabled reports whether voice commands are turned on for a guild
+func (l *VoiceCommandListener) Enabled(guildID string) bool {
+	config, err := l.configService.GetGuildConfig(guildID)
+	if err != nil || config == nil {
+		return false
+	}
+	return config.VoiceCommands
+}
+
+// SetEnabled turns voice commands on or off for a guild. The bot starts or stops listening
+// the next time it joins the guild's voice channel.
+func (l *VoiceCommandListener) SetEnabled(guildID string, enabled bool) error {
+	config, err := l.configService.GetGuildConfig(guildID)
+	if err != nil {
+		return fmt.Errorf("failed to get guild config: %w", err)
+	}
+	if config == nil {
+		defaultConfig := DefaultGuildTTSConfig(guildID)
+		config = &defaultConfig
+	}
+
+	updated := *config
+	updated.VoiceCommands = enabled
+
+	return l.configService.SetGuildConfig(guildID, &updated)
+}
+
+// Phrase returns the phrase that triggers a command in a guild's language
+func (l *VoiceCommandListener) Phrase(guildID string, command VoiceCommand) string {
+	return l.localizer.T(guildID, "voice_commands.phrase."+string(command))
+}
+
+// listen reports whether the bot should listen in a guild it joins, and prepares the
+// guild's templates so the first command is recognized
+func (l *VoiceCommandListener) listen(guildID string) bool {
+	if !l.Enabled(guildID) {
+		return l.autoPause(guildID)
+	}
+	l.spotter(guildID)
+	return true
+}
+
+// autoPause reports whether a guild tracks who is talking for voice auto-pause
+func (l *VoiceCommandListener) autoPause(guildID string) bool {
+	return l.activity != nil && l.features.Enabled(guildID, FeatureVoiceAutoPause)
+}
+
+// handleSpeech collects a user's speech into utterances and runs the commands recognized
+// in them. A nil frame means the user stopped transmitting.
+func (l *VoiceCommandListener) handleSpeech(guildID, userID string, pcm []int16) {
+	if userID == "" {
+		return
+	}
+	if l.activity != nil {
+		l.activity.Observe(guildID, userID, pcm)
+	}
+	key := guildID + ":" + userID
+
+	l.mu.Lock()
+	segmenter, exists := l.segmenters[key]
+	if !exists {
+		// Guilds listening only for voice auto-pause have no commands to recognize
+		if pcm == nil || !l.Enabled(guildID) {
+			l.mu.Unlock()
+			return
+		}
+		segmenter = &utteranceSegmenter{}
+		l.segmenters[key] = segmenter
+	}
+
+	var utterance []int16
+	if pcm == nil {
+		utterance = segmenter.Flush()
+		delete(l.segmenters, key)
+	} else {
+		utterance = segmenter.Add(pcm)
+	}
+	l.mu.Unlock()
+
+	if utterance != nil {
+		l.recognize(guildID, userID, utterance)
+	}
+}
+
+// recognize runs the command spoken in an utterance, if the user may control the bot
+func (l *VoiceCommandListener) recognize(guildID, userID string, utterance []int16) {
+	// Voice commands can be turned off while the bot is listening
+	if !l.Enabled(guildID) {
+		return
+	}
+
+	spotter := l.spotter(guildID)
+	if spotter == nil {
+		return
+	}
+	command, ok := spotter.Match(utterance)
+	if !ok {
+		return
+	}
+
+	canControl, err := l.permissionService.CanControlBot(userID, guildID)
+	if err != nil || !canControl {
+		l.logger.Printf("Ignoring voice command %s from user %s in guild %s without permission to control the bot", command, userID, guildID)
+		return
+	}
+
+	l.logger.Printf("Voice command %s from user %s in guild %s", command, userID, guildID)
+	if err := l.run(guildID, command); err != nil {
+		l.logger.Printf("Voice command %s failed in guild %s: %v", command, guildID, err)
+	}
+}
+
+// run skips, pauses or resumes playback in a guild
+func (l *VoiceCommandListener) run(guildID string, command VoiceCommand) error {
+	control := playbackControl{l.voiceManager, l.messageQueue, l.statsService, l.mutePauser, l.logger}
+	switch command {
+	case VoiceCommandPause:
+		_, err := control.pause(guildID)
+		return err
+
+	case VoiceCommandResume:
+		_, _, err := control.resume(guildID)
+		return err
+
+	case VoiceCommandSkip:
+		_, err := control.skip(guildID)
+		return err
+
+	default:
+		return fmt.Errorf("unknown voice command %q", command)
+	}
+}
+
+// spotter returns the keyword spotter for a guild's language and voice, or nil while its
+// templates are synthesized in the background
+func (l *VoiceCommandListener) spotter(guildID string) *KeywordSpotter {
+	settings := l.templateSettings(guildID)
+	key := l.localizer.Locale(guildID) + "|" + settings.Voice
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entry, exists := l.spotters[guildID]
+	if exists && entry.key == key {
+		if entry.spotter != nil || entry.failedAt.IsZero() || l.now().Sub(entry.failedAt) < templateRetryDelay {
+			return entry.spotter
+		}
+	}
+
+	l.spotters[guildID] = &guildSpotter{key: key}
+	go l.buildSpotter(guildID, key, settings)
+	return nil
+}
+
+// buildSpotter synthesizes the templates of every command for a guild
+func (l *VoiceCommandListener) buildSpotter(guildID, key string, settings TTSConfig) {
+	spotter := NewKeywordSpotter(DefaultKeywordThreshold)
+	err := l.addTemplates(guildID, spotter, settings)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entry, exists := l.spotters[guildID]
+	if !exists || entry.key != key {
+		return // The guild's language or voice changed meanwhile
+	}
+	if err != nil {
+		l.logger.Printf("Failed to prepare voice commands for guild %s: %v", guildID, err)
+		entry.failedAt = l.now()
+		return
+	}
+	entry.spotter = spotter
+	l.logger.Printf("Prepared %d voice command templates for guild %s", spotter.Templates(), guildID)
+}
+
+// addTemplates adds a template of each command phrase spoken by each template voice
+func (l *VoiceCommandListener) addTemplates(guildID string, spotter *KeywordSpotter, settings TTSConfig) error {
+	for _, voice := range l.templateVoices(settings.Voice) {
+		config := settings
+		config.Voice = voice
+
+		for _, command := range VoiceCommands {
+			audio, err := l.ttsManager.ConvertToSpeech(l.Phrase(guildID, command), voice, config)
+			if err != nil {
+				return fmt.Errorf("failed to synthesize %s with %s: %w", command, voice, err)
+			}
+			pcm, err := l.decode(audio)
+			if err != nil {
+				return fmt.Errorf("failed to decode %s with %s: %w", command, voice, err)
+			}
+			if err := spotter.AddTemplate(command, trimSilence(pcm)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// templateSettings returns the plain voice settings templates are synthesized with
+func (l *VoiceCommandListener) templateSettings(guildID string) TTSConfig {
+	settings := TTSConfig{Voice: DefaultVoice, Speed: DefaultTTSSpeed, Volume: DefaultTTSVolume, Format: AudioFormatDCA}
+	if guildSettings, err := l.configService.GetTTSSettings(guildID); err == nil && guildSettings != nil && guildSettings.Voice != "" {
+		settings.Voice = guildSettings.Voice
+	}
+	return settings
+}
+
+// templateVoices returns the guild's voice followed by a voice of each other gender in
+// its language, since templates only match speakers with a similar pitch
+func (l *VoiceCommandListener) templateVoices(voice string) []string {
+	voices := l.ttsManager.GetSupportedVoices()
+	language, _ := parseVoiceID(voice)
+
+	genders := make(map[string]bool)
+	for _, v := range voices {
+		if v.ID == voice {
+			genders[v.Gender] = true
+		}
+	}
+
+	selected := []string{voice}
+	for _, v := range voices {
+		if len(selected) == maxTemplateVoices {
+			break
+		}
+		if v.Language != language || v.Gender == "" || v.Gender == "SSML_VOICE_GENDER_UNSPECIFIED" || genders[v.Gender] {
+			continue
+		}
+		genders[v.Gender] = true
+		selected = append(selected, v.ID)
+	}
+	return selected
+}
@@ -0,0 +1,198 @@
+package tts
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// receivingVoiceManager is a mock voice manager that can receive speech
+type receivingVoiceManager struct {
+	*mockVoiceManager
+	listen func(guildID string) bool
+	handle func(guildID, userID string, pcm []int16)
+}
+
+func (m *receivingVoiceManager) SetVoiceReceiver(listen func(guildID string) bool, handle func(guildID, userID string, pcm []int16)) {
+	m.listen = listen
+	m.handle = handle
+}
+
+// syntheticPhrases stand in for the synthesized command phrases
+var syntheticPhrases = map[string][]syllable{
+	"parrot skip":   phraseSkip,
+	"parrot pause":  phrasePause,
+	"parrot resume": phraseResume,
+}
+
+// newTestVoiceCommandListener creates a listener whose templates are the synthetic phrases,
+// with voice commands turned on in guild1 and user1 allowed to control the bot
+func newTestVoiceCommandListener(t *testing.T) (*VoiceCommandListener, *receivingVoiceManager, MessageQueue, *mockTTSManager) {
+	t.Helper()
+
+	voiceManager := &receivingVoiceManager{mockVoiceManager: newMockVoiceManager()}
+	permissionService := &MockPermissionService{}
+	permissionService.On("CanControlBot", "user1", "guild1").Return(true, nil)
+	permissionService.On("CanControlBot", "user2", "guild1").Return(false, nil)
+	messageQueue := NewMessageQueue()
+	ttsManager := &mockTTSManager{
+		convertFunc: func(text, voice string, config TTSConfig) ([]byte, error) {
+			return []byte(text), nil
+		},
+	}
+
+	listener := NewVoiceCommandListener(voiceManager, messageQueue, createTestExportConfigService(t), permissionService, ttsManager, log.New(io.Discard, "", 0))
+	listener.decode = func(audio []byte) ([]int16, error) {
+		phrase, ok := syntheticPhrases[string(audio)]
+		if !ok {
+			return nil, fmt.Errorf("unknown phrase %q", audio)
+		}
+		// Synthesized speech starts and ends with silence
+		silence := make([]int16, keywordSampleRate/10)
+		pcm := append(append(silence, speak(phrase, 1, 1, 0, 1)...), silence...)
+		return pcm, nil
+	}
+	listener.Register()
+	require.NoError(t, listener.SetEnabled("guild1", true))
+
+	return listener, voiceManager, messageQueue, ttsManager
+}
+
+// say passes an utterance to the voice handler in 20ms frames, then ends the transmission
+func say(voiceManager *receivingVoiceManager, userID string, pcm []int16) {
+	const frame = keywordSampleRate / 50
+	for start := 0; start < len(pcm); start += frame {
+		voiceManager.handle("guild1", userID, pcm[start:min(start+frame, len(pcm))])
+	}
+	voiceManager.handle("guild1", userID, nil)
+}
+
+// waitForTemplates waits until the templates of guild1 are synthesized
+func waitForTemplates(t *testing.T, listener *VoiceCommandListener) {
+	t.Helper()
+	require.Eventually(t, func() bool { return listener.spotter("guild1") != nil }, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestVoiceCommandListener_Register(t *testing.T) {
+	listener, voiceManager, _, _ := newTestVoiceCommandListener(t)
+
+	assert.True(t, listener.Available())
+	assert.True(t, voiceManager.listen("guild1"))
+	assert.False(t, voiceManager.listen("guild2"), "voice commands are off by default")
+
+	require.NoError(t, listener.SetEnabled("guild1", false))
+	assert.False(t, voiceManager.listen("guild1"))
+
+	// Voice managers that cannot receive audio leave voice commands unavailable
+	unavailable := NewVoiceCommandListener(newMockVoiceManager(), NewMessageQueue(), createTestExportConfigService(t), &MockPermissionService{}, &mockTTSManager{}, log.New(io.Discard, "", 0))
+	unavailable.Register()
+	assert.False(t, unavailable.Available())
+}
+
+func TestVoiceCommandListener_PauseAndResume(t *testing.T) {
+	listener, voiceManager, _, _ := newTestVoiceCommandListener(t)
+	waitForTemplates(t, listener)
+
+	say(voiceManager, "user1", speak(phrasePause, 1.2, 1.05, 200, 11))
+	assert.True(t, voiceManager.IsPaused("guild1"))
+
+	say(voiceManager, "user1", speak(phraseResume, 0.9, 0.97, 200, 12))
+	assert.False(t, voiceManager.IsPaused("guild1"))
+}
+
+func TestVoiceCommandListener_Skip(t *testing.T) {
+	listener, voiceManager, messageQueue, _ := newTestVoiceCommandListener(t)
+	waitForTemplates(t, listener)
+	for _, content := range []string{"first", "second"} {
+		require.NoError(t, messageQueue.Enqueue(&QueuedMessage{GuildID: "guild1", Content: content, Timestamp: time.Now()}))
+	}
+
+	say(voiceManager, "user1", speak(phraseSkip, 1.1, 1, 200, 13))
+
+	assert.Contains(t, voiceManager.getCallLog(), "SkipCurrentMessage")
+	assert.Equal(t, 1, messageQueue.Size("guild1"))
+}
+
+func TestVoiceCommandListener_IgnoresSpeech(t *testing.T) {
+	listener, voiceManager, _, _ := newTestVoiceCommandListener(t)
+	waitForTemplates(t, listener)
+
+	say(voiceManager, "user1", speak(phraseOther, 1, 1, 200, 14))
+	say(voiceManager, "user2", speak(phrasePause, 1, 1, 200, 15)) // Not allowed to control the bot
+	assert.False(t, voiceManager.IsPaused("guild1"))
+
+	// Turning voice commands off takes effect while the bot is still listening
+	require.NoError(t, listener.SetEnabled("guild1", false))
+	say(voiceManager, "user1", speak(phrasePause, 1, 1, 200, 16))
+	assert.False(t, voiceManager.IsPaused("guild1"))
+}
+
+func TestVoiceCommandListener_RetriesFailedTemplates(t *testing.T) {
+	listener, _, _, ttsManager := newTestVoiceCommandListener(t)
+	now := time.Now()
+	listener.now = func() time.Time { return now }
+
+	ttsManager.mu.Lock()
+	ttsManager.convertFunc = func(text, voice string, config TTSConfig) ([]byte, error) {
+		return nil, errors.New("synthesis unavailable")
+	}
+	ttsManager.mu.Unlock()
+
+	assert.Nil(t, listener.spotter("guild1"))
+	require.Eventually(t, func() bool {
+		listener.mu.Lock()
+		defer listener.mu.Unlock()
+		return !listener.spotters["guild1"].failedAt.IsZero()
+	}, time.Second, 10*time.Millisecond)
+	assert.Nil(t, listener.spotter("guild1"), "no retry before the delay")
+
+	ttsManager.mu.Lock()
+	ttsManager.convertFunc = func(text, voice string, config TTSConfig) ([]byte, error) {
+		return []byte(text), nil
+	}
+	ttsManager.mu.Unlock()
+	now = now.Add(templateRetryDelay)
+
+	waitForTemplates(t, listener)
+	assert.Equal(t, len(VoiceCommands), listener.spotter("guild1").Templates())
+}
+
+func TestVoiceCommandListener_TemplateVoices(t *testing.T) {
+	listener, _, _, ttsManager := newTestVoiceCommandListener(t)
+	ttsManager.getSupportedFunc = getDefaultVoices
+
+	assert.Equal(t, []string{"en-US-Standard-A", "en-US-Standard-B"}, listener.templateVoices("en-US-Standard-A"))
+	assert.Equal(t, []string{"de-DE-Standard-A"}, listener.templateVoices("de-DE-Standard-A"), "no other voices in the language")
+}
+
+func TestVoiceCommandListener_VoiceAutoPause(t *testing.T) {
+	listener, voiceManager, _, _ := newTestVoiceCommandListener(t)
+	configService := createTestExportConfigService(t)
+	listener.configService = configService
+	features := NewFeatureFlagService(configService, nil)
+	activity := NewVoiceActivity()
+	listener.SetFeatureFlags(features)
+	listener.SetVoiceActivity(activity)
+
+	on := true
+	require.NoError(t, features.Set("guild1", FeatureVoiceAutoPause, &on))
+
+	// Guilds listen for voice auto-pause without voice commands
+	assert.True(t, voiceManager.listen("guild1"))
+	assert.False(t, voiceManager.listen("guild2"))
+
+	voiceManager.handle("guild1", "user1", make([]int16, keywordSampleRate/50))
+	assert.True(t, activity.Speaking("guild1"))
+	listener.mu.Lock()
+	assert.Empty(t, listener.segmenters, "speech is not segmented without voice commands")
+	listener.mu.Unlock()
+
+	voiceManager.handle("guild1", "user1", nil)
+	assert.False(t, activity.Speaking("guild1"))
+}
@@ -0,0 +1,294 @@
+package tts
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"darrot/internal/events"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gorilla/websocket"
+)
+
+// Voice server outage tuning
+const (
+	voiceOutageThreshold   = 3                // Failures within the window that make an outage
+	voiceOutageWindow      = 10 * time.Minute // Longer than the longest backoff, so a long outage keeps counting
+	voiceRetryBaseDelay    = 5 * time.Second
+	voiceRetryMaxDelay     = 5 * time.Minute
+	voiceRetryPollInterval = time.Second
+	maxVoiceRejoinAttempts = 12 // About half an hour of backoff before the bot stops rejoining
+)
+
+// Voice gateway close codes that mean the voice server dropped the session. discordgo
+// handles 4014 itself and only logs it at the informational level, without saying which
+// guild it was for; while the voice server is down the rejoin that follows fails and is
+// counted instead.
+var voiceOutageCloseCodes = map[int]bool{
+	4006: true, // Session no longer valid
+	4014: true, // Disconnected
+	4015: true, // Voice server crashed
+}
+
+// voiceFailover tracks a connection's recent failures and when it may rejoin next. A
+// guild whose voice server keeps closing the connection or refusing rejoins is backed
+// off with jitter instead of rejoining in a tight loop.
+type voiceFailover struct {
+	failures []time.Time // Within voiceOutageWindow
+	retryAt  time.Time   // No rejoin before this
+	outage   bool        // Announced; cleared once the window has no failures
+}
+
+// recordVoiceFailure counts a failed rejoin or a voice socket the voice server closed
+// and pushes back the next rejoin. The first time a connection fails voiceOutageThreshold
+// times within voiceOutageWindow, a VoiceOutage is published with the time of the next
+// attempt.
+func (vm *voiceManager) recordVoiceFailure(connection *VoiceConnection, reason string) {
+	now := time.Now()
+
+	vm.mutex.Lock()
+	failover := &connection.failover
+	recent := failover.failures[:0]
+	for _, at := range failover.failures {
+		if now.Sub(at) < voiceOutageWindow {
+			recent = append(recent, at)
+		}
+	}
+	if len(recent) == 0 {
+		failover.outage = false
+	}
+	failover.failures = append(recent, now)
+	failures := len(failover.failures)
+	failover.retryAt = now.Add(voiceRetryDelay(failures))
+	retryAt := failover.retryAt
+	announce := failures >= voiceOutageThreshold && !failover.outage
+	if announce {
+		failover.outage = true
+	}
+	vm.mutex.Unlock()
+
+	log.Printf("Voice connection for guild %s failed (%s), next rejoin in %s", connection.GuildID, reason, time.Until(retryAt).Round(time.Second))
+	if announce {
+		vm.eventBus.Publish(events.VoiceOutage{
+			GuildID:   connection.GuildID,
+			ChannelID: connection.ChannelID,
+			Reason:    reason,
+			Failures:  failures,
+			RetryAt:   retryAt,
+		})
+	}
+}
+
+// voiceRetryDelay returns how long to wait before rejoining after the given number of
+// recent failures: voiceRetryBaseDelay doubled for every earlier failure, capped at
+// voiceRetryMaxDelay, of which the second half is random so guilds on the same voice
+// server do not rejoin together
+func voiceRetryDelay(failures int) time.Duration {
+	ceiling := voiceRetryBaseDelay
+	for i := 1; i < failures && ceiling < voiceRetryMaxDelay; i++ {
+		ceiling *= 2
+	}
+	if ceiling > voiceRetryMaxDelay {
+		ceiling = voiceRetryMaxDelay
+	}
+	half := ceiling / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// retryWait returns how long a connection still has to wait before it may rejoin
+func (vm *voiceManager) retryWait(connection *VoiceConnection) time.Duration {
+	vm.mutex.RLock()
+	defer vm.mutex.RUnlock()
+	return time.Until(connection.failover.retryAt)
+}
+
+// waitForRetry waits until a connection may rejoin. It reports false when the bot left
+// the voice channel in the meantime.
+func (vm *voiceManager) waitForRetry(connection *VoiceConnection) bool {
+	for {
+		vm.mutex.RLock()
+		current := vm.connections[connection.GuildID] == connection
+		vm.mutex.RUnlock()
+		if !current {
+			return false
+		}
+
+		wait := vm.retryWait(connection)
+		if wait <= 0 {
+			return true
+		}
+		time.Sleep(min(wait, voiceRetryPollInterval))
+	}
+}
+
+// rejoin rejoins a connection's voice channel in the background, backing off after each
+// failure, until it succeeds, the bot leaves or maxVoiceRejoinAttempts is reached
+func (vm *voiceManager) rejoin(connection *VoiceConnection) {
+	for attempt := 1; attempt <= maxVoiceRejoinAttempts && vm.waitForRetry(connection); attempt++ {
+		err := CatchPanic(vm.metrics, "voice reconnect", connection.GuildID, func() error {
+			return vm.reconnect(connection)
+		})
+		if err == nil {
+			return
+		}
+		log.Printf("Failed to rejoin voice channel for guild %s: %v", connection.GuildID, err)
+		vm.recordVoiceFailure(connection, err.Error())
+	}
+}
+
+// handleVoiceClose counts a voice socket the voice server at endpoint closed against
+// every guild connected through it
+func (vm *voiceManager) handleVoiceClose(endpoint string, code int) {
+	if !voiceOutageCloseCodes[code] {
+		return
+	}
+
+	vm.mutex.RLock()
+	var affected []*VoiceConnection
+	for guildID, connection := range vm.connections {
+		if vm.endpoints[guildID] == endpoint {
+			affected = append(affected, connection)
+		}
+	}
+	vm.mutex.RUnlock()
+
+	for _, connection := range affected {
+		vm.recordVoiceFailure(connection, fmt.Sprintf("voice server closed the connection with code %d", code))
+	}
+}
+
+// Voice managers told about the voice sockets Discord closes
+var (
+	voiceCloseHook     sync.Once
+	voiceCloseMu       sync.RWMutex
+	voiceCloseWatchers []*voiceManager
+)
+
+// watchVoiceCloses tells vm which voice sockets Discord closes and with which code.
+// discordgo does not report close codes to its callers, only in the error it logs before
+// reconnecting on its own, so its logger is wrapped once to pick them out.
+func watchVoiceCloses(vm *voiceManager) {
+	voiceCloseHook.Do(func() {
+		previous := discordgo.Logger
+		discordgo.Logger = func(msgL, caller int, format string, a ...interface{}) {
+			if previous != nil {
+				previous(msgL, caller+1, format, a...)
+			} else {
+				printDiscordgoLog(msgL, caller+2, format, a...)
+			}
+
+			endpoint, code, ok := voiceCloseFromLog(format, a)
+			if !ok {
+				return
+			}
+			voiceCloseMu.RLock()
+			watchers := voiceCloseWatchers
+			voiceCloseMu.RUnlock()
+			for _, watcher := range watchers {
+				watcher.handleVoiceClose(endpoint, code)
+			}
+		}
+	})
+
+	voiceCloseMu.Lock()
+	voiceCloseWatchers = append(voiceCloseWatchers, vm)
+	voiceCloseMu.Unlock()
+}
+
+// voiceCloseFromLog returns the voice endpoint and close code of discordgo's log line
+// for a voice socket that closed unexpectedly
+func voiceCloseFromLog(format string, args []interface{}) (string, int, bool) {
+	if !strings.HasPrefix(format, "voice endpoint ") || len(args) < 2 {
+		return "", 0, false
+	}
+	endpoint, _ := args[0].(string)
+	err, _ := args[len(args)-1].(error)
+
+	var closeErr *websocket.CloseError
+	if endpoint == "" || !errors.As(err, &closeErr) {
+		return "", 0, false
+	}
+	return endpoint, closeErr.Code, true
+}
+
+// printDiscordgoLog prints a discordgo log line the way discordgo does without a logger
+func printDiscordgoLog(msgL, caller int, format string, a ...interface{}) {
+	pc, file, line, _ := runtime.Caller(caller)
+	file = file[strings.LastIndex(file, "/")+1:]
+	name := runtime.FuncForPC(pc).Name()
+	name = name[strings.LastIndex(name, ".")+1:]
+
+	log.Printf("[DG%d] %s:%d:%s() %s\n", msgL, file, line, name, fmt.Sprintf(format, a...))
+}
+
+// VoiceOutageNotifier tells a guild's paired text channel when its voice server or its
+// speech synthesis keeps failing and when the bot will try again, so members know why it
+// went quiet
+type VoiceOutageNotifier struct {
+	channelService ChannelService
+	messenger      HandoffMessenger
+	localizer      *Localizer
+	logger         *log.Logger
+}
+
+// NewVoiceOutageNotifier creates a notifier that posts with messenger
+func NewVoiceOutageNotifier(channelService ChannelService, messenger HandoffMessenger, logger *log.Logger) *VoiceOutageNotifier {
+	return &VoiceOutageNotifier{
+		channelService: channelService,
+		messenger:      messenger,
+		logger:         logger,
+	}
+}
+
+// SetLocalizer sets the localizer used to translate the notice
+func (n *VoiceOutageNotifier) SetLocalizer(localizer *Localizer) {
+	n.localizer = localizer
+}
+
+// Subscribe posts a notice for every VoiceOutage and TTSOutage published on bus
+func (n *VoiceOutageNotifier) Subscribe(bus *events.Bus) (unsubscribe func()) {
+	unsubscribeVoice := events.Subscribe(bus, func(e events.VoiceOutage) {
+		go n.notify(e)
+	})
+	unsubscribeTTS := events.Subscribe(bus, func(e events.TTSOutage) {
+		go n.notifyTTS(e)
+	})
+	return func() {
+		unsubscribeVoice()
+		unsubscribeTTS()
+	}
+}
+
+// notify posts the notice in the text channel paired with the outage's voice channel.
+// The time of the next attempt is a Discord timestamp, which every member sees relative
+// to now in their own time zone.
+func (n *VoiceOutageNotifier) notify(e events.VoiceOutage) {
+	n.post(e.GuildID, e.ChannelID, n.localizer.T(e.GuildID, "voice.outage", e.RetryAt.Unix()))
+}
+
+// notifyTTS posts the notice of a guild that stopped synthesizing messages in the text
+// channel paired with the bot's voice channel
+func (n *VoiceOutageNotifier) notifyTTS(e events.TTSOutage) {
+	n.post(e.GuildID, e.ChannelID, n.localizer.T(e.GuildID, "tts.outage", e.Failures, e.RetryAt.Unix()))
+}
+
+// post posts a notice in the text channel paired with a voice channel
+func (n *VoiceOutageNotifier) post(guildID, voiceChannelID, message string) {
+	if voiceChannelID == "" {
+		return
+	}
+	pairing, err := n.channelService.GetPairing(guildID, voiceChannelID)
+	if err != nil || pairing == nil {
+		return
+	}
+
+	if _, err := n.messenger.ChannelMessageSend(pairing.TextChannelID, message); err != nil {
+		n.logger.Printf("Warning: Failed to post outage notice in channel %s: %v", pairing.TextChannelID, err)
+	}
+}
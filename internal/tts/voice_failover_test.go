@@ -0,0 +1,138 @@
+package tts
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"darrot/internal/events"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVoiceManager_RepeatedFailuresBackOff(t *testing.T) {
+	vm, connection := newStandbyTestManager(t, createMockVoiceConnection("guild1", "channel1"))
+
+	var joins atomic.Int32
+	vm.session = &mockDiscordVoiceSession{
+		joinFunc: func(guildID, channelID string, mute, deaf bool) (*discordgo.VoiceConnection, error) {
+			joins.Add(1)
+			return nil, errors.New("voice server unavailable")
+		},
+	}
+
+	bus := events.New()
+	vm.SetEventBus(bus)
+	var outages []events.VoiceOutage
+	events.Subscribe(bus, func(e events.VoiceOutage) { outages = append(outages, e) })
+
+	// Failures below the threshold back off without an outage
+	vm.recordVoiceFailure(connection, "voice server closed the connection with code 4006")
+	vm.recordVoiceFailure(connection, "voice server closed the connection with code 4006")
+	assert.Empty(t, outages)
+	assert.Greater(t, vm.retryWait(connection), time.Duration(0))
+
+	// Recovery attempts do not rejoin while backing off
+	assert.Error(t, vm.RecoverConnection("guild1"))
+	assert.Zero(t, joins.Load())
+
+	// The third failure is an outage, announced once with the time of the next rejoin
+	vm.recordVoiceFailure(connection, "voice server closed the connection with code 4006")
+	vm.recordVoiceFailure(connection, "voice server closed the connection with code 4006")
+	require.Len(t, outages, 1)
+	assert.Equal(t, "guild1", outages[0].GuildID)
+	assert.Equal(t, "channel1", outages[0].ChannelID)
+	assert.Equal(t, 3, outages[0].Failures)
+	assert.True(t, outages[0].RetryAt.After(time.Now()))
+}
+
+func TestVoiceManager_RejoinStopsWhenBotLeaves(t *testing.T) {
+	vm, connection := newStandbyTestManager(t, createMockVoiceConnection("guild1", "channel1"))
+
+	var joins atomic.Int32
+	vm.session = &mockDiscordVoiceSession{
+		joinFunc: func(guildID, channelID string, mute, deaf bool) (*discordgo.VoiceConnection, error) {
+			joins.Add(1)
+			return nil, errors.New("voice server unavailable")
+		},
+	}
+
+	done := make(chan struct{})
+	go func() {
+		vm.rejoin(connection)
+		close(done)
+	}()
+
+	// The failed rejoin backs off instead of trying again right away
+	require.Eventually(t, func() bool { return joins.Load() == 1 }, time.Second, 10*time.Millisecond)
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, int32(1), joins.Load())
+
+	vm.mutex.Lock()
+	delete(vm.connections, "guild1")
+	vm.mutex.Unlock()
+
+	select {
+	case <-done:
+	case <-time.After(3 * voiceRetryPollInterval):
+		t.Fatal("rejoin kept waiting after the bot left")
+	}
+	assert.Equal(t, int32(1), joins.Load())
+}
+
+func TestVoiceManager_HandleVoiceClose(t *testing.T) {
+	vm, connection := newStandbyTestManager(t, createMockVoiceConnection("guild1", "channel1"))
+	vm.handleVoiceServerUpdate(nil, &discordgo.VoiceServerUpdate{GuildID: "guild1", Endpoint: "c-ams1.discord.media:443"})
+
+	// Other voice servers and normal closes are not counted
+	vm.handleVoiceClose("c-fra2.discord.media:443", 4006)
+	vm.handleVoiceClose("c-ams1.discord.media:443", websocket.CloseNormalClosure)
+	assert.Empty(t, connection.failover.failures)
+
+	vm.handleVoiceClose("c-ams1.discord.media:443", 4006)
+	assert.Len(t, connection.failover.failures, 1)
+}
+
+func TestVoiceCloseFromLog(t *testing.T) {
+	closeErr := &websocket.CloseError{Code: 4006, Text: "Session is no longer valid."}
+
+	endpoint, code, ok := voiceCloseFromLog("voice endpoint %s websocket closed unexpectantly, %s", []interface{}{"c-ams1.discord.media:443", closeErr})
+	require.True(t, ok)
+	assert.Equal(t, "c-ams1.discord.media:443", endpoint)
+	assert.Equal(t, 4006, code)
+
+	_, _, ok = voiceCloseFromLog("voice endpoint %s websocket closed unexpectantly, %s", []interface{}{"c-ams1.discord.media:443", fmt.Errorf("read: %w", errors.New("EOF"))})
+	assert.False(t, ok)
+	_, _, ok = voiceCloseFromLog("error reading from gateway %s websocket, %s", []interface{}{"wss://gateway.discord.gg", closeErr})
+	assert.False(t, ok)
+}
+
+func TestVoiceRetryDelay(t *testing.T) {
+	for failures := 1; failures <= 10; failures++ {
+		ceiling := min(voiceRetryBaseDelay<<(failures-1), voiceRetryMaxDelay)
+		delay := voiceRetryDelay(failures)
+		assert.GreaterOrEqual(t, delay, ceiling/2, "failures=%d", failures)
+		assert.LessOrEqual(t, delay, ceiling, "failures=%d", failures)
+	}
+}
+
+func TestVoiceOutageNotifier_PostsInPairedChannel(t *testing.T) {
+	env := setupHandoffTest(t)
+	require.NoError(t, env.channelService.CreatePairingWithCreator("guild1", "voice1", "text1", "user1"))
+	notifier := NewVoiceOutageNotifier(env.channelService, env.messenger, log.New(os.Stdout, "", 0))
+
+	retryAt := time.Unix(1760000000, 0)
+	notifier.notify(events.VoiceOutage{GuildID: "guild1", ChannelID: "voice1", Failures: 3, RetryAt: retryAt})
+	notifier.notify(events.VoiceOutage{GuildID: "guild2", ChannelID: "voice2", Failures: 3, RetryAt: retryAt}) // Not paired
+
+	assert.Equal(t, map[string]string{
+		"text1": "📡 Discord's voice server for this channel keeps dropping the connection, so I've stopped reading for now. I'll try to reconnect <t:1760000000:R>.",
+	}, env.messenger.messages)
+}
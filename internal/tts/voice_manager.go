@@ -1,12 +1,15 @@
 package tts
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
 	"sync"
 	"time"
 
+	"darrot/internal/events"
+
 	"github.com/bwmarrin/discordgo"
 )
 
@@ -17,22 +20,60 @@ type DiscordVoiceSession interface {
 
 // voiceManager implements the VoiceManager interface
 type voiceManager struct {
-	session     DiscordVoiceSession
-	connections map[string]*VoiceConnection
-	mutex       sync.RWMutex
+	session       DiscordVoiceSession
+	connections   map[string]*VoiceConnection
+	mutex         sync.RWMutex
+	stateCallback func(guildID string, connected bool)
+	encoders      *OpusEncoderPool      // Encoders for clips mixed with speech
+	mixers        map[string]*clipMixer // Clip currently playing under speech per guild
+
+	listen      func(guildID string) bool                 // Guilds where the bot joins undeafened to hear voice commands
+	handleVoice func(guildID, userID string, pcm []int16) // Receives the speech heard there
+
+	sendTimeout    time.Duration     // How long a frame may wait before the connection goes on standby
+	reconnectGrace time.Duration     // How long playback waits on standby before giving up
+	endpoints      map[string]string // Voice server each guild was last sent to
+
+	metrics          *Metrics
+	eventBus         *events.Bus          // Told when channels are joined and connections go on standby
+	heartbeatLatency func() time.Duration // Round trip of the session's latest heartbeat
 }
 
 // NewVoiceManager creates a new VoiceManager instance
 func NewVoiceManager(session *discordgo.Session) VoiceManager {
-	return &voiceManager{
-		session:     session,
-		connections: make(map[string]*VoiceConnection),
-		mutex:       sync.RWMutex{},
+	vm := &voiceManager{
+		session:        discordVoiceSession{session},
+		connections:    make(map[string]*VoiceConnection),
+		mutex:          sync.RWMutex{},
+		encoders:       NewOpusEncoderPool(dcaBitrate, DefaultOpusPoolSize),
+		mixers:         make(map[string]*clipMixer),
+		endpoints:      make(map[string]string),
+		sendTimeout:    defaultFrameSendTimeout,
+		reconnectGrace: defaultReconnectGracePeriod,
+
+		heartbeatLatency: session.HeartbeatLatency,
 	}
+
+	// Keep connections warm across voice server moves and gateway resumes
+	session.AddHandler(vm.handleVoiceServerUpdate)
+	session.AddHandler(vm.handleResumed)
+
+	// Back off from voice servers that keep closing the connection
+	watchVoiceCloses(vm)
+
+	return vm
 }
 
 // JoinChannel joins a voice channel and creates a voice connection
 func (vm *voiceManager) JoinChannel(guildID, channelID string) (*VoiceConnection, error) {
+	// Published once the lock is released, so subscribers can use the voice manager
+	var joined *VoiceConnection
+	defer func() {
+		if joined != nil {
+			vm.eventBus.Publish(events.VoiceJoined{GuildID: guildID, ChannelID: channelID, JoinedAt: time.Now()})
+		}
+	}()
+
 	vm.mutex.Lock()
 	defer vm.mutex.Unlock()
 
@@ -52,11 +93,14 @@ func (vm *voiceManager) JoinChannel(guildID, channelID string) (*VoiceConnection
 	}
 
 	// Join the voice channel
+	// Discord only sends audio to clients that join undeafened
+	deaf := vm.listen == nil || !vm.listen(guildID)
+
 	log.Printf("[DEBUG] Calling ChannelVoiceJoin for guild %s, channel %s", guildID, channelID)
-	voiceConn, err := vm.session.ChannelVoiceJoin(guildID, channelID, false, true)
+	voiceConn, err := vm.session.ChannelVoiceJoin(guildID, channelID, false, deaf)
 	if err != nil {
 		log.Printf("[DEBUG] ChannelVoiceJoin failed: %v", err)
-		return nil, fmt.Errorf("failed to join voice channel %s: %w", channelID, err)
+		return nil, withKind(ErrVoiceGateway, fmt.Errorf("failed to join voice channel %s: %w", channelID, err))
 	}
 
 	log.Printf("[DEBUG] ChannelVoiceJoin succeeded, voiceConn: %v", voiceConn != nil)
@@ -65,13 +109,20 @@ func (vm *voiceManager) JoinChannel(guildID, channelID string) (*VoiceConnection
 	// In a real implementation, we would wait for the Ready channel
 	time.Sleep(100 * time.Millisecond) // Brief wait for connection setup
 
+	// Stage channels put the bot in the audience until it becomes a speaker
+	requestedToSpeak, err := joinStageAsSpeaker(vm.session, guildID, channelID)
+	if err != nil {
+		log.Printf("Warning: staying in the audience of guild %s: %v", guildID, err)
+	}
+
 	// Create our VoiceConnection wrapper
 	connection := &VoiceConnection{
-		GuildID:    guildID,
-		ChannelID:  channelID,
-		Connection: voiceConn,
-		IsPlaying:  false,
-		IsPaused:   false,
+		GuildID:          guildID,
+		ChannelID:        channelID,
+		Connection:       voiceConn,
+		IsPlaying:        false,
+		IsPaused:         false,
+		RequestedToSpeak: requestedToSpeak,
 		Queue: &AudioQueue{
 			Items:   make([][]byte, 0),
 			MaxSize: 10,
@@ -79,7 +130,12 @@ func (vm *voiceManager) JoinChannel(guildID, channelID string) (*VoiceConnection
 		},
 	}
 
+	if !deaf {
+		connection.stopReceiving = vm.startReceiving(guildID, voiceConn)
+	}
+
 	vm.connections[guildID] = connection
+	joined = connection
 	log.Printf("[DEBUG] Stored voice connection for guild %s, total connections: %d", guildID, len(vm.connections))
 	return connection, nil
 }
@@ -99,6 +155,13 @@ func (vm *voiceManager) leaveChannelInternal(guildID string) error {
 		return fmt.Errorf("no voice connection found for guild %s", guildID)
 	}
 
+	if connection.stopReceiving != nil {
+		connection.stopReceiving()
+	}
+	if connection.sender != nil {
+		connection.sender.cancel()
+	}
+
 	// Disconnect from Discord
 	if connection.Connection != nil {
 		// Note: In a real implementation, we would call connection.Connection.Disconnect()
@@ -125,77 +188,327 @@ func (vm *voiceManager) GetConnection(guildID string) (*VoiceConnection, bool) {
 
 // PlayAudio plays audio data through the voice connection with enhanced error handling
 func (vm *voiceManager) PlayAudio(guildID string, audioData []byte) error {
+	return vm.PlayAudioContext(context.Background(), guildID, audioData)
+}
+
+// PlayAudioContext plays audio data through the voice connection until it ends, it is
+// skipped or ctx is cancelled
+func (vm *voiceManager) PlayAudioContext(ctx context.Context, guildID string, audioData []byte) error {
+	connection, err := vm.readyConnection(guildID)
+	if err != nil {
+		return err
+	}
+
+	// Parse DCA format and send individual Opus frames to Discord
+	log.Printf("[DEBUG] Parsing %d bytes of DCA data", len(audioData))
+
+	frames, err := vm.parseDCAFrames(audioData)
+	if err != nil {
+		return fmt.Errorf("failed to parse DCA frames for guild %s: %w", guildID, err)
+	}
+
+	log.Printf("[DEBUG] Parsed %d DCA frames", len(frames))
+
+	frameChan := make(chan []byte, len(frames))
+	for _, frame := range frames {
+		frameChan <- frame
+	}
+	close(frameChan)
+
+	// Speak over a clip that is still playing instead of waiting for it to end
+	if mixed, err := vm.mixSpeech(ctx, guildID, frameChan); mixed {
+		return err
+	}
+
+	sent, err := vm.sendFrames(ctx, connection, guildID, frameChan)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Successfully sent %d DCA frames (%d total bytes) for guild %s", sent, len(audioData), guildID)
+	return nil
+}
+
+// StreamAudio plays Opus frames as they arrive on frames until the channel is closed
+func (vm *voiceManager) StreamAudio(guildID string, frames <-chan []byte) error {
+	return vm.StreamAudioContext(context.Background(), guildID, frames)
+}
+
+// StreamAudioContext plays Opus frames as they arrive on frames until the channel is
+// closed, the message is skipped or ctx is cancelled
+func (vm *voiceManager) StreamAudioContext(ctx context.Context, guildID string, frames <-chan []byte) error {
+	connection, err := vm.readyConnection(guildID)
+	if err != nil {
+		return err
+	}
+
+	if mixed, err := vm.mixSpeech(ctx, guildID, frames); mixed {
+		return err
+	}
+
+	sent, err := vm.sendFrames(ctx, connection, guildID, frames)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Successfully streamed %d Opus frames for guild %s", sent, guildID)
+	return nil
+}
+
+// MixClip starts playing a DCA clip and returns while it plays. Speech played before the
+// clip ends is mixed over it with the clip ducked; a clip that arrives while another is
+// still playing waits for it to end.
+func (vm *voiceManager) MixClip(guildID string, audioData []byte) error {
+	// Mixing decodes and re-encodes audio, which needs libopus
+	if vm.encoders == nil || !nativeOpus {
+		return vm.PlayAudio(guildID, audioData)
+	}
+
+	connection, err := vm.readyConnection(guildID)
+	if err != nil {
+		return err
+	}
+
+	frames, err := vm.parseDCAFrames(audioData)
+	if err != nil {
+		return fmt.Errorf("failed to parse DCA frames for guild %s: %w", guildID, err)
+	}
+
+	encoder, err := vm.encoders.Get()
+	if err != nil {
+		return fmt.Errorf("failed to mix clip for guild %s: %w", guildID, err)
+	}
+
+	mixer, err := newClipMixer(frames, encoder)
+	if err != nil {
+		vm.encoders.Put(encoder)
+		return fmt.Errorf("failed to mix clip for guild %s: %w", guildID, err)
+	}
+
+	// Clips do not mix with each other
+	for {
+		vm.mutex.Lock()
+		previous := vm.mixers[guildID]
+		if previous == nil {
+			vm.mixers[guildID] = mixer
+			vm.mutex.Unlock()
+			break
+		}
+		vm.mutex.Unlock()
+		<-previous.done
+	}
+
+	go vm.runMixer(connection, guildID, mixer)
+	return nil
+}
+
+// runMixer sends a clip mixer's frames until it finishes and then releases it
+func (vm *voiceManager) runMixer(connection *VoiceConnection, guildID string, mixer *clipMixer) {
+	defer Recover(vm.metrics, "clip mixer", guildID)
+	defer vm.releaseMixer(guildID, mixer) // Runs even after a panic, so the next clip is not kept waiting
+
+	out := make(chan []byte)
+	stop := make(chan struct{})
+	go func() {
+		defer Recover(vm.metrics, "clip mixer", guildID)
+		mixer.run(out, stop)
+	}()
+
+	sent, err := vm.sendFrames(context.Background(), connection, guildID, out)
+	close(stop)
+	if err != nil {
+		log.Printf("Clip playback failed for guild %s: %v", guildID, err)
+		mixer.stop(err)
+	} else {
+		log.Printf("Successfully sent %d mixed clip frames for guild %s", sent, guildID)
+	}
+}
+
+// releaseMixer removes a finished clip mixer from the guild and lets the next clip play
+func (vm *voiceManager) releaseMixer(guildID string, mixer *clipMixer) {
+	vm.mutex.Lock()
+	if vm.mixers[guildID] == mixer {
+		delete(vm.mixers, guildID)
+	}
+	vm.mutex.Unlock()
+
+	vm.encoders.Put(mixer.encoder)
+	close(mixer.done)
+}
+
+// mixSpeech plays speech frames over the guild's playing clip. It reports whether a clip
+// took the speech; when it did not, the caller plays the frames itself.
+func (vm *voiceManager) mixSpeech(ctx context.Context, guildID string, frames <-chan []byte) (bool, error) {
+	vm.mutex.RLock()
+	mixer := vm.mixers[guildID]
+	vm.mutex.RUnlock()
+
+	if mixer == nil {
+		return false, nil
+	}
+
+	speech := make(chan []byte)
+	done, err := mixer.attach(speech)
+	if err != nil {
+		// The clip is ending, play normally once its last frames are sent
+		<-mixer.done
+		return false, nil
+	}
+
+	// The mixer stops taking speech once the message is cancelled
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go forwardFrames(ctx, frames, speech)
+
+	err = <-done
+	if ctx.Err() != nil {
+		return true, fmt.Errorf("%w for guild %s", ErrPlaybackSkipped, guildID)
+	}
+	return true, err
+}
+
+// forwardFrames passes frames on to out until frames is closed or ctx is done, and then
+// closes out
+func forwardFrames(ctx context.Context, frames <-chan []byte, out chan<- []byte) {
+	defer close(out)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case frame, ok := <-frames:
+			if !ok {
+				return
+			}
+			select {
+			case out <- frame:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+// readyConnection returns the guild's voice connection if it can send audio
+func (vm *voiceManager) readyConnection(guildID string) (*VoiceConnection, error) {
 	vm.mutex.RLock()
 	connection, exists := vm.connections[guildID]
 	vm.mutex.RUnlock()
 
 	if !exists {
-		return fmt.Errorf("no voice connection found for guild %s", guildID)
+		return nil, fmt.Errorf("no voice connection found for guild %s", guildID)
 	}
 
 	if connection.Connection == nil {
-		return fmt.Errorf("voice connection is nil for guild %s", guildID)
+		return nil, fmt.Errorf("voice connection is nil for guild %s", guildID)
 	}
 
 	// Check if connection is ready
 	if connection.Connection.OpusSend == nil {
-		return fmt.Errorf("voice connection not ready for guild %s", guildID)
+		return nil, withKind(ErrVoiceGateway, fmt.Errorf("voice connection not ready for guild %s", guildID))
 	}
 
+	return connection, nil
+}
+
+// sendFrames queues Opus frames on the connection's send loop until the channel is closed,
+// waits for them to be sent and returns the number of frames sent. It stops with
+// ErrPlaybackSkipped when ctx is cancelled, the message is skipped or the connection
+// leaves its channel.
+func (vm *voiceManager) sendFrames(ctx context.Context, connection *VoiceConnection, guildID string, frames <-chan []byte) (int, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
 	// Set playing status
 	vm.mutex.Lock()
 	connection.IsPlaying = true
+	connection.cancelPlayback = cancel
+	sender := vm.senderLocked(connection)
 	vm.mutex.Unlock()
 
 	// Ensure playing status is reset regardless of outcome
 	defer func() {
 		vm.mutex.Lock()
 		connection.IsPlaying = false
+		connection.cancelPlayback = nil
 		vm.mutex.Unlock()
 	}()
 
+	// The utterance ends with the send loop
+	stopAfterLeave := context.AfterFunc(sender.ctx, cancel)
+	defer stopAfterLeave()
+
+	stats := vm.sendStatsFor(connection)
+	stats.startUtterance()
+	defer vm.recordUtteranceEnd(guildID, stats)
+
 	// Set speaking state to true before sending audio
-	err := connection.Connection.Speaking(true)
-	if err != nil {
-		log.Printf("[DEBUG] Warning: failed to set speaking state: %v", err)
-	}
+	vm.setSpeaking(connection, true)
 
 	// Ensure speaking state is reset when done
-	defer func() {
-		err := connection.Connection.Speaking(false)
-		if err != nil {
-			log.Printf("[DEBUG] Warning: failed to reset speaking state: %v", err)
-		}
-	}()
+	defer vm.setSpeaking(connection, false)
 
-	// Parse DCA format and send individual Opus frames to Discord
-	log.Printf("[DEBUG] Parsing %d bytes of DCA data", len(audioData))
-
-	// Parse DCA frames and send them individually
-	frames, err := vm.parseDCAFrames(audioData)
-	if err != nil {
-		return fmt.Errorf("failed to parse DCA frames for guild %s: %w", guildID, err)
+	// Frames keep being queued while the connection is on standby, so synthesis only
+	// waits once the send loop holds a standby's worth of audio
+	u := &utterance{ctx: ctx, guildID: guildID, stats: stats, done: make(chan struct{})}
+	finish := func() (int, error) {
+		select {
+		case <-u.done:
+			return int(u.sent.Load()), u.err
+		case <-ctx.Done():
+			sent := int(u.sent.Load())
+			return sent, fmt.Errorf("%w for guild %s after %d frames", ErrPlaybackSkipped, guildID, sent)
+		}
+	}
+	queue := func(frame queuedFrame) bool {
+		select {
+		case sender.frames <- frame:
+			return true
+		case <-u.done:
+		case <-ctx.Done():
+		}
+		return false
 	}
 
-	log.Printf("[DEBUG] Parsed %d DCA frames", len(frames))
-
-	// Send each Opus frame (Discord handles 20ms timing automatically)
-	for i, frame := range frames {
+	if ctx.Err() != nil {
+		return finish()
+	}
+	for {
 		select {
-		case connection.Connection.OpusSend <- frame:
-			// Frame sent successfully - Discord handles timing
-		case <-time.After(5 * time.Second):
-			return fmt.Errorf("timeout sending DCA frame %d for guild %s", i, guildID)
+		case data, ok := <-frames:
+			if !ok {
+				queue(queuedFrame{utterance: u, end: true})
+				return finish()
+			}
+			if !queue(queuedFrame{utterance: u, data: data, readyAt: time.Now()}) {
+				return finish()
+			}
+		case <-u.done:
+			return finish()
+		case <-ctx.Done():
+			return finish()
 		}
 	}
+}
 
-	log.Printf("Successfully sent %d DCA frames (%d total bytes) for guild %s", len(frames), len(audioData), guildID)
-	return nil
+// setSpeaking updates the speaking state of a connection's current voice socket
+func (vm *voiceManager) setSpeaking(connection *VoiceConnection, speaking bool) {
+	vm.mutex.RLock()
+	voiceConn := connection.Connection
+	vm.mutex.RUnlock()
+
+	if err := voiceConn.Speaking(speaking); err != nil {
+		log.Printf("[DEBUG] Warning: failed to set speaking state to %v: %v", speaking, err)
+	}
 }
 
 // parseDCAFrames parses DCA format data into individual Opus frames
-// DCA format: [2 bytes frame length][N bytes Opus data][2 bytes frame length][N bytes Opus data]...
 func (vm *voiceManager) parseDCAFrames(dcaData []byte) ([][]byte, error) {
+	return parseDCAFrames(dcaData)
+}
+
+// parseDCAFrames parses DCA format data into individual Opus frames
+// DCA format: [2 bytes frame length][N bytes Opus data][2 bytes frame length][N bytes Opus data]...
+func parseDCAFrames(dcaData []byte) ([][]byte, error) {
 	var frames [][]byte
 	offset := 0
 
@@ -312,66 +625,44 @@ func (vm *voiceManager) GetActiveConnections() []string {
 	return guildIDs
 }
 
-// RecoverConnection attempts to recover a failed voice connection with enhanced error handling
+// RecoverConnection attempts to recover a failed voice connection with enhanced error handling.
+// The channel is rejoined in place, so playback state and any utterance waiting on
+// standby carry over to the new connection.
 func (vm *voiceManager) RecoverConnection(guildID string) error {
-	vm.mutex.Lock()
-	defer vm.mutex.Unlock()
-
+	vm.mutex.RLock()
 	connection, exists := vm.connections[guildID]
+	vm.mutex.RUnlock()
+
 	if !exists {
 		return fmt.Errorf("no voice connection found for guild %s", guildID)
 	}
 
-	// Attempt to rejoin the same channel
-	channelID := connection.ChannelID
-	log.Printf("Attempting to recover voice connection for guild %s, channel %s", guildID, channelID)
-
-	// Clean up the old connection
-	if err := vm.leaveChannelInternal(guildID); err != nil {
-		log.Printf("Warning: failed to clean up old connection for guild %s: %v", guildID, err)
-		// Continue with recovery attempt despite cleanup failure
+	// Guilds whose voice server keeps failing wait out their backoff
+	if wait := vm.retryWait(connection); wait > 0 {
+		return fmt.Errorf("voice server for guild %s is unavailable, next rejoin in %s", guildID, wait.Round(time.Second))
 	}
 
+	log.Printf("Attempting to recover voice connection for guild %s, channel %s", guildID, connection.ChannelID)
+
 	// Try to rejoin with timeout
 	done := make(chan error, 1)
 	go func() {
-		voiceConn, err := vm.session.ChannelVoiceJoin(guildID, channelID, false, true)
-		if err != nil {
-			done <- fmt.Errorf("failed to rejoin voice channel: %w", err)
-			return
-		}
-
-		// Wait for connection setup
-		time.Sleep(100 * time.Millisecond)
-
-		// Create new connection wrapper
-		newConnection := &VoiceConnection{
-			GuildID:    guildID,
-			ChannelID:  channelID,
-			Connection: voiceConn,
-			IsPlaying:  false,
-			IsPaused:   false,
-			Queue: &AudioQueue{
-				Items:   make([][]byte, 0),
-				MaxSize: 10,
-				Current: 0,
-			},
-		}
-
-		vm.connections[guildID] = newConnection
-		done <- nil
+		done <- CatchPanic(vm.metrics, "voice reconnect", guildID, func() error {
+			return vm.reconnect(connection)
+		})
 	}()
 
 	// Wait for connection with timeout
 	select {
 	case err := <-done:
 		if err != nil {
-			return fmt.Errorf("voice connection recovery failed for guild %s: %w", guildID, err)
+			vm.recordVoiceFailure(connection, err.Error())
+			return withKind(ErrVoiceGateway, fmt.Errorf("voice connection recovery failed for guild %s: %w", guildID, err))
 		}
 		log.Printf("Successfully recovered voice connection for guild %s", guildID)
 		return nil
 	case <-time.After(10 * time.Second):
-		return fmt.Errorf("voice connection recovery timed out for guild %s", guildID)
+		return withKind(ErrVoiceGateway, fmt.Errorf("voice connection recovery timed out for guild %s", guildID))
 	}
 }
 
@@ -388,9 +679,8 @@ func (vm *voiceManager) HealthCheck() map[string]error {
 			continue
 		}
 
-		// In a real implementation, we would check connection health
-		// For now, we'll assume connections are healthy if they exist
-		results[guildID] = nil
+		// Connections that send audio unevenly or lose frames are reported as degraded
+		results[guildID] = vm.connectionStats(connection.sendStats).Degraded(time.Now())
 	}
 
 	return results
@@ -424,7 +714,8 @@ func (vm *voiceManager) ResumePlayback(guildID string) error {
 	return nil
 }
 
-// SkipCurrentMessage stops the current message and allows the next one to play
+// SkipCurrentMessage stops the current message and allows the next one to play. Frames
+// already handed to Discord finish playing; no further frames are sent.
 func (vm *voiceManager) SkipCurrentMessage(guildID string) error {
 	vm.mutex.Lock()
 	defer vm.mutex.Unlock()
@@ -436,8 +727,9 @@ func (vm *voiceManager) SkipCurrentMessage(guildID string) error {
 
 	// If currently playing, stop the current audio
 	if connection.IsPlaying {
-		// In a real implementation, we would interrupt the current audio stream
-		// For now, we'll just mark it as not playing to simulate skipping
+		if connection.cancelPlayback != nil {
+			connection.cancelPlayback()
+		}
 		connection.IsPlaying = false
 	}
 
@@ -457,11 +749,18 @@ func (vm *voiceManager) IsPaused(guildID string) bool {
 	return connection.IsPaused
 }
 
-// SetConnectionStateCallback allows setting a callback for connection state changes
-// This would be used in a real implementation to handle disconnections
+// SetEventBus publishes when a voice channel is joined and when a connection is lost
+func (vm *voiceManager) SetEventBus(bus *events.Bus) {
+	vm.eventBus = bus
+}
+
+// SetConnectionStateCallback sets a callback invoked when a connection goes on standby
+// (connected false) and when it is ready to play again (connected true)
 func (vm *voiceManager) SetConnectionStateCallback(callback func(guildID string, connected bool)) {
-	// In a real implementation, this would register a callback with the Discord session
-	// to handle voice connection state changes and trigger automatic recovery
+	vm.mutex.Lock()
+	defer vm.mutex.Unlock()
+
+	vm.stateCallback = callback
 }
 
 // TestPlayDCAFile plays a known working DCA file for testing
@@ -1,11 +1,14 @@
 package tts
 
 import (
+	"bytes"
+	"context"
 	"testing"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // mockDiscordVoiceSession provides a mock implementation of DiscordVoiceSession for testing
@@ -704,3 +707,63 @@ func TestVoiceManager_ControlMethods_ConcurrentAccess(t *testing.T) {
 	assert.True(t, exists)
 	assert.NotNil(t, conn)
 }
+
+func TestVoiceManager_SkipStopsStreamMidway(t *testing.T) {
+	voiceConn := createMockVoiceConnection("guild1", "channel1")
+	voiceConn.OpusSend = make(chan []byte)
+	vm, _ := newStandbyTestManager(t, voiceConn)
+	vm.sendTimeout = time.Minute // Discord is slow to take the next frame, not gone
+
+	frames := make(chan []byte)
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		for {
+			select {
+			case frames <- []byte{1}:
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- vm.StreamAudio("guild1", frames)
+	}()
+	for i := 0; i < 2; i++ {
+		<-voiceConn.OpusSend
+	}
+
+	require.NoError(t, vm.SkipCurrentMessage("guild1"))
+
+	select {
+	case err := <-done:
+		assert.ErrorIs(t, err, ErrPlaybackSkipped)
+	case <-time.After(time.Second):
+		t.Fatal("playback did not stop when the message was skipped")
+	}
+	assert.Empty(t, drainFrames(voiceConn.OpusSend))
+}
+
+func TestVoiceManager_PlayAudioContextCancelled(t *testing.T) {
+	voiceConn := createMockVoiceConnection("guild1", "channel1")
+	vm, connection := newStandbyTestManager(t, voiceConn)
+
+	var dca bytes.Buffer
+	for i := byte(0); i < 3; i++ {
+		require.NoError(t, writeDCAFrame(&dca, []byte{i}))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := vm.PlayAudioContext(ctx, "guild1", dca.Bytes())
+	assert.ErrorIs(t, err, ErrPlaybackSkipped)
+	assert.Empty(t, drainFrames(voiceConn.OpusSend))
+	assert.False(t, connection.IsPlaying)
+
+	// Without a cancellation the audio plays in full
+	require.NoError(t, vm.PlayAudioContext(context.Background(), "guild1", dca.Bytes()[:6]))
+	assert.Len(t, drainFrames(voiceConn.OpusSend), 2)
+}
@@ -0,0 +1,247 @@
+package tts
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Voice connection quality metrics, labelled by guild
+const (
+	MetricVoiceFramesSent       = "darrot_voice_frames_sent_total"
+	MetricVoiceFramesDropped    = "darrot_voice_frames_dropped_total"
+	MetricVoiceFramesLate       = "darrot_voice_frames_late_total"
+	MetricVoiceFrameJitter      = "darrot_voice_frame_jitter_seconds"
+	MetricVoiceHeartbeatLatency = "darrot_voice_heartbeat_latency_seconds"
+)
+
+// Limits above which HealthCheck reports a voice connection as degraded
+const (
+	voiceJitterLimit           = 10 * time.Millisecond
+	voiceLossLimit             = 0.05
+	voiceHeartbeatLatencyLimit = time.Second
+
+	// voiceStatsWindow is how long after the latest audio its send quality counts
+	// towards the health of the connection
+	voiceStatsWindow = time.Minute
+)
+
+// VoiceConnectionStats describes how smoothly audio is sent on a voice connection
+type VoiceConnectionStats struct {
+	FramesSent       int64         // Frames accepted by the voice connection
+	FramesDropped    int64         // Frames discarded when playback gave up on a connection that did not recover
+	FramesLate       int64         // Frames sent more than a frame late, leaving an audible gap
+	RecentLoss       float64       // Share of the latest utterance's frames that were dropped
+	Jitter           time.Duration // Smoothed deviation of the spacing between frames from the frame duration
+	HeartbeatLatency time.Duration // Round trip of the latest heartbeat to Discord
+	LastAudio        time.Time     // When a frame was last sent or dropped
+}
+
+// Loss returns the share of all frames played on the connection that were dropped
+func (s VoiceConnectionStats) Loss() float64 {
+	total := s.FramesSent + s.FramesDropped
+	if total == 0 {
+		return 0
+	}
+	return float64(s.FramesDropped) / float64(total)
+}
+
+// Degraded returns why audio on the connection is likely to sound choppy, or nil. Send
+// quality only counts while the connection has recently played audio, so an idle
+// connection does not stay degraded because of its last utterance.
+func (s VoiceConnectionStats) Degraded(now time.Time) error {
+	if s.HeartbeatLatency > voiceHeartbeatLatencyLimit {
+		return fmt.Errorf("heartbeat latency %s exceeds %s", s.HeartbeatLatency.Round(time.Millisecond), voiceHeartbeatLatencyLimit)
+	}
+	if s.LastAudio.IsZero() || now.Sub(s.LastAudio) > voiceStatsWindow {
+		return nil
+	}
+	if s.RecentLoss > voiceLossLimit {
+		return fmt.Errorf("%.1f%% of the latest frames were dropped", s.RecentLoss*100)
+	}
+	if s.Jitter > voiceJitterLimit {
+		return fmt.Errorf("frame send jitter %s exceeds %s", s.Jitter.Round(time.Microsecond), voiceJitterLimit)
+	}
+	return nil
+}
+
+// voiceSendStats tracks the frames sent on one voice connection
+type voiceSendStats struct {
+	mu sync.Mutex
+
+	sent, dropped, late int64
+	utteranceSent       int64
+	utteranceDropped    int64
+	jitter              time.Duration
+	lastSent            time.Time
+	lastAudio           time.Time
+}
+
+// startUtterance begins a new run of frames, so the pause before it is not counted as a
+// late frame
+func (s *voiceSendStats) startUtterance() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastSent = time.Time{}
+	s.utteranceSent = 0
+	s.utteranceDropped = 0
+}
+
+// recordSent records a frame that was ready at readyAt and accepted at sentAt, and
+// reports whether it left a gap. Frames that were not ready when the previous frame was
+// sent waited for synthesis rather than the connection and do not count towards jitter.
+func (s *voiceSendStats) recordSent(readyAt, sentAt time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sent++
+	s.utteranceSent++
+	s.lastAudio = sentAt
+
+	late := false
+	if !s.lastSent.IsZero() && !readyAt.After(s.lastSent) {
+		spacing := sentAt.Sub(s.lastSent)
+		deviation := spacing - dcaFrameDuration
+		if deviation < 0 {
+			deviation = -deviation
+		}
+
+		// Smoothed like the interarrival jitter of RFC 3550
+		s.jitter += (deviation - s.jitter) / 16
+
+		if spacing > 2*dcaFrameDuration {
+			s.late++
+			late = true
+		}
+	}
+	s.lastSent = sentAt
+	return late
+}
+
+// recordDropped records frames discarded at now
+func (s *voiceSendStats) recordDropped(frames int, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.dropped += int64(frames)
+	s.utteranceDropped += int64(frames)
+	s.lastAudio = now
+}
+
+// snapshot returns the current statistics
+func (s *voiceSendStats) snapshot() VoiceConnectionStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats := VoiceConnectionStats{
+		FramesSent:    s.sent,
+		FramesDropped: s.dropped,
+		FramesLate:    s.late,
+		Jitter:        s.jitter,
+		LastAudio:     s.lastAudio,
+	}
+	if total := s.utteranceSent + s.utteranceDropped; total > 0 {
+		stats.RecentLoss = float64(s.utteranceDropped) / float64(total)
+	}
+	return stats
+}
+
+// SetMetrics enables voice connection instrumentation
+func (vm *voiceManager) SetMetrics(metrics *Metrics) {
+	vm.metrics = metrics
+	if metrics != nil {
+		metrics.Describe(MetricVoiceFramesSent, MetricTypeCounter, "Opus frames accepted by voice connections")
+		metrics.Describe(MetricVoiceFramesDropped, MetricTypeCounter, "Opus frames discarded because the voice connection did not recover")
+		metrics.Describe(MetricVoiceFramesLate, MetricTypeCounter, "Opus frames sent more than a frame late")
+		metrics.Describe(MetricVoiceFrameJitter, MetricTypeGauge, "Smoothed deviation of the spacing between sent frames from the 20ms frame duration")
+		metrics.Describe(MetricVoiceHeartbeatLatency, MetricTypeGauge, "Round trip of the latest heartbeat to Discord")
+	}
+	describePanicMetrics(metrics)
+}
+
+// VoiceStats returns the send statistics of the guild's voice connection
+func (vm *voiceManager) VoiceStats(guildID string) (*VoiceConnectionStats, bool) {
+	vm.mutex.RLock()
+	connection, exists := vm.connections[guildID]
+	var sendStats *voiceSendStats
+	if exists {
+		sendStats = connection.sendStats
+	}
+	vm.mutex.RUnlock()
+
+	if !exists {
+		return nil, false
+	}
+
+	stats := vm.connectionStats(sendStats)
+	return &stats, true
+}
+
+// connectionStats combines a connection's send statistics with the heartbeat latency
+func (vm *voiceManager) connectionStats(sendStats *voiceSendStats) VoiceConnectionStats {
+	var stats VoiceConnectionStats
+	if sendStats != nil {
+		stats = sendStats.snapshot()
+	}
+	stats.HeartbeatLatency = vm.currentHeartbeatLatency()
+	return stats
+}
+
+// currentHeartbeatLatency returns the round trip of the latest heartbeat. discordgo does
+// not surface the acknowledgements of the voice websocket's heartbeats, so the gateway
+// heartbeat of the same session stands in for it.
+func (vm *voiceManager) currentHeartbeatLatency() time.Duration {
+	if vm.heartbeatLatency == nil {
+		return 0
+	}
+
+	latency := vm.heartbeatLatency()
+	if latency < 0 {
+		latency = 0 // A heartbeat is awaiting its acknowledgement
+	}
+	if vm.metrics != nil {
+		vm.metrics.SetGauge(MetricVoiceHeartbeatLatency, nil, latency.Seconds())
+	}
+	return latency
+}
+
+// sendStatsFor returns the send statistics of a connection, creating them on first use
+func (vm *voiceManager) sendStatsFor(connection *VoiceConnection) *voiceSendStats {
+	vm.mutex.Lock()
+	defer vm.mutex.Unlock()
+
+	if connection.sendStats == nil {
+		connection.sendStats = &voiceSendStats{}
+	}
+	return connection.sendStats
+}
+
+// recordFrameSent records a frame sent to a guild
+func (vm *voiceManager) recordFrameSent(guildID string, stats *voiceSendStats, readyAt time.Time) {
+	late := stats.recordSent(readyAt, time.Now())
+	if vm.metrics == nil {
+		return
+	}
+
+	labels := Labels{"guild": guildID}
+	vm.metrics.IncCounter(MetricVoiceFramesSent, labels)
+	if late {
+		vm.metrics.IncCounter(MetricVoiceFramesLate, labels)
+	}
+}
+
+// recordFramesDropped records frames for a guild that were discarded
+func (vm *voiceManager) recordFramesDropped(guildID string, stats *voiceSendStats, frames int) {
+	stats.recordDropped(frames, time.Now())
+	if vm.metrics != nil {
+		vm.metrics.AddCounter(MetricVoiceFramesDropped, Labels{"guild": guildID}, float64(frames))
+	}
+}
+
+// recordUtteranceEnd publishes the jitter of a guild's connection after it played audio
+func (vm *voiceManager) recordUtteranceEnd(guildID string, stats *voiceSendStats) {
+	if vm.metrics != nil {
+		vm.metrics.SetGauge(MetricVoiceFrameJitter, Labels{"guild": guildID}, stats.snapshot().Jitter.Seconds())
+	}
+}
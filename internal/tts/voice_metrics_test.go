@@ -0,0 +1,145 @@
+package tts
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVoiceSendStats_Jitter(t *testing.T) {
+	stats := &voiceSendStats{}
+	start := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	// Frames sent exactly one frame apart have no jitter
+	stats.startUtterance()
+	for i := 0; i < 10; i++ {
+		sentAt := start.Add(time.Duration(i) * dcaFrameDuration)
+		assert.False(t, stats.recordSent(start, sentAt))
+	}
+	snapshot := stats.snapshot()
+	assert.Equal(t, int64(10), snapshot.FramesSent)
+	assert.Zero(t, snapshot.Jitter)
+	assert.Zero(t, snapshot.FramesLate)
+
+	// A frame sent 60ms after the previous one leaves a gap
+	last := start.Add(9 * dcaFrameDuration)
+	assert.True(t, stats.recordSent(start, last.Add(3*dcaFrameDuration)))
+	snapshot = stats.snapshot()
+	assert.Equal(t, int64(1), snapshot.FramesLate)
+	assert.Equal(t, 2*dcaFrameDuration/16, snapshot.Jitter)
+
+	// Frames that waited for synthesis and the pause between utterances are not counted
+	assert.False(t, stats.recordSent(last.Add(time.Second), last.Add(time.Second)))
+	stats.startUtterance()
+	assert.False(t, stats.recordSent(start, last.Add(time.Minute)))
+	assert.Equal(t, int64(1), stats.snapshot().FramesLate)
+}
+
+func TestVoiceSendStats_Loss(t *testing.T) {
+	stats := &voiceSendStats{}
+	now := time.Now()
+
+	stats.startUtterance()
+	for i := 0; i < 9; i++ {
+		stats.recordSent(now, now)
+	}
+	stats.recordDropped(1, now)
+	snapshot := stats.snapshot()
+	assert.InDelta(t, 0.1, snapshot.Loss(), 0.0001)
+	assert.InDelta(t, 0.1, snapshot.RecentLoss, 0.0001)
+
+	// Recent loss covers the latest utterance only
+	stats.startUtterance()
+	stats.recordSent(now, now)
+	snapshot = stats.snapshot()
+	assert.Zero(t, snapshot.RecentLoss)
+	assert.InDelta(t, 1.0/11, snapshot.Loss(), 0.0001)
+
+	assert.Zero(t, VoiceConnectionStats{}.Loss())
+}
+
+func TestVoiceConnectionStats_Degraded(t *testing.T) {
+	now := time.Now()
+
+	assert.NoError(t, VoiceConnectionStats{}.Degraded(now))
+	assert.NoError(t, VoiceConnectionStats{Jitter: time.Millisecond, LastAudio: now}.Degraded(now))
+
+	err := VoiceConnectionStats{Jitter: 15 * time.Millisecond, LastAudio: now}.Degraded(now)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "jitter")
+
+	err = VoiceConnectionStats{RecentLoss: 0.2, LastAudio: now}.Degraded(now)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "20.0% of the latest frames were dropped")
+
+	err = VoiceConnectionStats{HeartbeatLatency: 2 * time.Second}.Degraded(now)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "heartbeat latency")
+
+	// Idle connections are not judged by their last utterance
+	assert.NoError(t, VoiceConnectionStats{Jitter: 15 * time.Millisecond, RecentLoss: 0.2, LastAudio: now.Add(-2 * voiceStatsWindow)}.Degraded(now))
+}
+
+func TestVoiceManager_RecordsSendMetrics(t *testing.T) {
+	voiceConn := createMockVoiceConnection("guild1", "channel1")
+	voiceConn.OpusSend = make(chan []byte, 10)
+	vm, _ := newStandbyTestManager(t, voiceConn)
+	vm.heartbeatLatency = func() time.Duration { return 42 * time.Millisecond }
+
+	metrics := NewMetrics()
+	vm.SetMetrics(metrics)
+
+	frames := make(chan []byte, 3)
+	frames <- []byte{1}
+	frames <- []byte{2}
+	frames <- []byte{3}
+	close(frames)
+	require.NoError(t, vm.StreamAudio("guild1", frames))
+
+	labels := Labels{"guild": "guild1"}
+	assert.Equal(t, 3.0, metrics.Value(MetricVoiceFramesSent, labels))
+	assert.Zero(t, metrics.Value(MetricVoiceFramesDropped, labels))
+
+	stats, ok := vm.VoiceStats("guild1")
+	require.True(t, ok)
+	assert.Equal(t, int64(3), stats.FramesSent)
+	assert.Equal(t, 42*time.Millisecond, stats.HeartbeatLatency)
+	assert.InDelta(t, 0.042, metrics.Value(MetricVoiceHeartbeatLatency, nil), 0.0001)
+
+	_, ok = vm.VoiceStats("other")
+	assert.False(t, ok)
+
+	// Prometheus scrapes the send quality from the metrics endpoint
+	output := scrapeMetrics(t, metrics)
+	assert.Contains(t, output, "# TYPE "+MetricVoiceFramesSent+" counter")
+	assert.Contains(t, output, MetricVoiceFramesSent+`{guild="guild1"} 3`+"\n")
+	assert.Contains(t, output, "# TYPE "+MetricVoiceFrameJitter+" gauge\n"+MetricVoiceFrameJitter+`{guild="guild1"} `)
+	assert.Contains(t, output, "# TYPE "+MetricVoiceHeartbeatLatency+" gauge\n"+MetricVoiceHeartbeatLatency+" 0.042\n")
+}
+
+func TestVoiceManager_RecordsDroppedFrames(t *testing.T) {
+	voiceConn := createMockVoiceConnection("guild1", "channel1")
+	vm, _ := newStandbyTestManager(t, voiceConn)
+	vm.reconnectGrace = 100 * time.Millisecond
+
+	metrics := NewMetrics()
+	vm.SetMetrics(metrics)
+
+	// The connection never comes back, so the utterance is dropped
+	vm.handleVoiceServerUpdate(nil, &discordgo.VoiceServerUpdate{GuildID: "guild1"})
+
+	frames := make(chan []byte, 1)
+	frames <- []byte{1}
+	close(frames)
+	require.Error(t, vm.StreamAudio("guild1", frames))
+
+	assert.Equal(t, 1.0, metrics.Value(MetricVoiceFramesDropped, Labels{"guild": "guild1"}))
+	assert.Contains(t, scrapeMetrics(t, metrics), MetricVoiceFramesDropped+`{guild="guild1"} 1`+"\n")
+
+	results := vm.HealthCheck()
+	require.Error(t, results["guild1"])
+	assert.Contains(t, results["guild1"].Error(), "were dropped")
+}
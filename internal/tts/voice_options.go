@@ -0,0 +1,68 @@
+package tts
+
+import (
+	"fmt"
+	"html"
+	"slices"
+	"strings"
+)
+
+// Pitch limits in semitones, as accepted by Google Cloud TTS
+const (
+	MinTTSPitch = -20.0
+	MaxTTSPitch = 20.0
+)
+
+// EffectsProfiles are the Google Cloud TTS audio profiles that tune synthesized speech for
+// the device it is played on
+var EffectsProfiles = []string{
+	"wearable-class-device",
+	"handset-class-device",
+	"headphone-class-device",
+	"small-bluetooth-speaker-class-device",
+	"medium-bluetooth-speaker-class-device",
+	"large-home-entertainment-class-device",
+	"large-automotive-class-device",
+	"telephony-class-application",
+}
+
+// VoiceStyles are the speaking styles Google Cloud TTS offers for styled voices
+var VoiceStyles = []string{"apologetic", "calm", "empathetic", "firm", "lively"}
+
+// styledVoices are the voices that can speak in VoiceStyles
+var styledVoices = []string{"en-US-Neural2-F", "en-US-Neural2-J"}
+
+// SupportsStyle reports whether a voice can speak in VoiceStyles
+func SupportsStyle(voice string) bool {
+	return slices.Contains(styledVoices, voice)
+}
+
+// SupportsPitch reports whether a voice can be pitched. Journey and Chirp voices ignore
+// the pitch setting, and Google rejects requests that set it.
+func SupportsPitch(voice string) bool {
+	return !strings.Contains(voice, "-Journey-") && !strings.Contains(voice, "-Chirp")
+}
+
+// validateVoiceOptions checks that pitch, effects profile, style and loudness are known
+// values. Whether the voice supports them is left to the synthesis request, which
+// ignores options a voice cannot use.
+func validateVoiceOptions(config TTSConfig) error {
+	if config.Pitch < MinTTSPitch || config.Pitch > MaxTTSPitch {
+		return fmt.Errorf("pitch must be between %.0f and %.0f semitones", MinTTSPitch, MaxTTSPitch)
+	}
+	if config.EffectsProfile != "" && !slices.Contains(EffectsProfiles, config.EffectsProfile) {
+		return fmt.Errorf("invalid effects profile: %s", config.EffectsProfile)
+	}
+	if config.Style != "" && !slices.Contains(VoiceStyles, config.Style) {
+		return fmt.Errorf("invalid voice style: %s", config.Style)
+	}
+	if config.Loudness != 0 && (config.Loudness < MinTTSLoudness || config.Loudness > MaxTTSLoudness) {
+		return fmt.Errorf("loudness must be between %.0f and %.0f dBFS", MinTTSLoudness, MaxTTSLoudness)
+	}
+	return nil
+}
+
+// styledSSML wraps text in SSML that makes a styled voice speak in style
+func styledSSML(text, style string) string {
+	return fmt.Sprintf(`<speak><google:style name="%s">%s</google:style></speak>`, style, html.EscapeString(text))
+}
@@ -0,0 +1,38 @@
+package tts
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSupportsStyle(t *testing.T) {
+	assert.True(t, SupportsStyle("en-US-Neural2-F"))
+	assert.True(t, SupportsStyle("en-US-Neural2-J"))
+	assert.False(t, SupportsStyle("en-US-Neural2-A"))
+	assert.False(t, SupportsStyle("en-US-Standard-A"))
+}
+
+func TestSupportsPitch(t *testing.T) {
+	assert.True(t, SupportsPitch("en-US-Standard-A"))
+	assert.True(t, SupportsPitch("de-DE-Neural2-B"))
+	assert.False(t, SupportsPitch("en-US-Journey-F"))
+	assert.False(t, SupportsPitch("en-US-Chirp-HD-D"))
+	assert.False(t, SupportsPitch("en-US-Chirp3-HD-Aoede"))
+}
+
+func TestValidateVoiceOptions(t *testing.T) {
+	assert.NoError(t, validateVoiceOptions(TTSConfig{}))
+	assert.NoError(t, validateVoiceOptions(TTSConfig{Pitch: MinTTSPitch, EffectsProfile: "telephony-class-application", Style: "firm"}))
+	assert.NoError(t, validateVoiceOptions(TTSConfig{Pitch: MaxTTSPitch}))
+	assert.NoError(t, validateVoiceOptions(TTSConfig{Loudness: MinTTSLoudness}))
+
+	assert.EqualError(t, validateVoiceOptions(TTSConfig{Pitch: -21}), "pitch must be between -20 and 20 semitones")
+	assert.EqualError(t, validateVoiceOptions(TTSConfig{EffectsProfile: "loud"}), "invalid effects profile: loud")
+	assert.EqualError(t, validateVoiceOptions(TTSConfig{Style: "sarcastic"}), "invalid voice style: sarcastic")
+	assert.EqualError(t, validateVoiceOptions(TTSConfig{Loudness: -5}), "loudness must be between -30 and -10 dBFS")
+}
+
+func TestStyledSSML(t *testing.T) {
+	assert.Equal(t, `<speak><google:style name="calm">a &lt;b&gt; &#34;c&#34;</google:style></speak>`, styledSSML(`a <b> "c"`, "calm"))
+}
@@ -0,0 +1,184 @@
+package tts
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// receiveGap is how long a user sends no audio before their transmission counts as ended
+const receiveGap = 200 * time.Millisecond
+
+// maxDecodedSamples fits the longest Opus frame (120ms) decoded for keyword spotting
+const maxDecodedSamples = keywordSampleRate * 120 / 1000
+
+// newKeywordDecoder creates a decoder for the 16kHz mono audio keyword spotting uses
+func newKeywordDecoder() (opusDecoder, error) {
+	return newOpusDecoder(keywordSampleRate, 1)
+}
+
+// SetVoiceReceiver sets which guilds the bot listens in and the handler for the speech it
+// hears. Guilds start or stop listening the next time the bot joins their voice channel.
+func (vm *voiceManager) SetVoiceReceiver(listen func(guildID string) bool, handle func(guildID, userID string, pcm []int16)) {
+	vm.mutex.Lock()
+	defer vm.mutex.Unlock()
+
+	vm.listen = listen
+	vm.handleVoice = handle
+}
+
+// startReceiving passes the speech received on a voice connection to the voice handler
+// and returns a function that stops it; callers must hold vm.mutex
+func (vm *voiceManager) startReceiving(guildID string, voiceConn *discordgo.VoiceConnection) func() {
+	if voiceConn == nil || vm.handleVoice == nil {
+		return nil
+	}
+
+	voiceConn.RLock()
+	packets := voiceConn.OpusRecv
+	voiceConn.RUnlock()
+	if packets == nil {
+		log.Printf("Warning: no voice receive channel for guild %s, voice commands are unavailable", guildID)
+		return nil
+	}
+
+	receiver := newVoiceReceiver(guildID, vm.handleVoice)
+	voiceConn.AddHandler(receiver.handleSpeakingUpdate)
+	go func() {
+		defer Recover(vm.metrics, "voice receiver", guildID)
+		receiver.run(packets)
+	}()
+	return receiver.stop
+}
+
+// voiceReceiver decodes the audio each user sends in one voice channel
+type voiceReceiver struct {
+	guildID    string
+	handle     func(guildID, userID string, pcm []int16)
+	newDecoder func() (opusDecoder, error)
+
+	mu    sync.Mutex
+	users map[uint32]string // Users by the SSRC of their audio stream
+
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// newVoiceReceiver creates a receiver that passes decoded speech to handle
+func newVoiceReceiver(guildID string, handle func(guildID, userID string, pcm []int16)) *voiceReceiver {
+	return &voiceReceiver{
+		guildID:    guildID,
+		handle:     handle,
+		newDecoder: newKeywordDecoder,
+		users:      make(map[uint32]string),
+		done:       make(chan struct{}),
+	}
+}
+
+// handleSpeakingUpdate learns which user sends each audio stream
+func (r *voiceReceiver) handleSpeakingUpdate(_ *discordgo.VoiceConnection, vs *discordgo.VoiceSpeakingUpdate) {
+	if vs == nil || vs.UserID == "" {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.users[uint32(vs.SSRC)] = vs.UserID
+}
+
+// user returns the user sending an audio stream, or "" if not known yet
+func (r *voiceReceiver) user(ssrc uint32) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.users[ssrc]
+}
+
+// stop stops passing speech to the handler
+func (r *voiceReceiver) stop() {
+	r.stopOnce.Do(func() { close(r.done) })
+}
+
+// run decodes received packets until the receiver is stopped or the channel is closed
+func (r *voiceReceiver) run(packets <-chan *discordgo.Packet) {
+	decoders := make(map[uint32]opusDecoder)
+	lastPacket := make(map[uint32]time.Time) // Streams that are transmitting
+	pcm := make([]int16, maxDecodedSamples)
+
+	ticker := time.NewTicker(receiveGap / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.done:
+			return
+
+		case packet, ok := <-packets:
+			if !ok {
+				return
+			}
+			if packet == nil {
+				continue
+			}
+			userID := r.user(packet.SSRC)
+			if userID == "" {
+				continue
+			}
+
+			// Opus decoders keep state between the packets of a stream
+			decoder, exists := decoders[packet.SSRC]
+			if !exists {
+				var err error
+				if decoder, err = r.newDecoder(); err != nil {
+					log.Printf("Warning: failed to create Opus decoder for guild %s: %v", r.guildID, err)
+					continue
+				}
+				decoders[packet.SSRC] = decoder
+			}
+
+			n, err := decoder.Decode(packet.Opus, pcm)
+			if err != nil || n <= 0 {
+				continue
+			}
+			frame := make([]int16, n)
+			copy(frame, pcm[:n])
+
+			lastPacket[packet.SSRC] = time.Now()
+			r.handle(r.guildID, userID, frame)
+
+		case now := <-ticker.C:
+			for ssrc, last := range lastPacket {
+				if now.Sub(last) >= receiveGap {
+					delete(lastPacket, ssrc)
+					r.handle(r.guildID, r.user(ssrc), nil)
+				}
+			}
+		}
+	}
+}
+
+// decodeDCA decodes DCA audio into the 16kHz mono PCM keyword spotting uses
+func decodeDCA(dcaData []byte) ([]int16, error) {
+	frames, err := parseDCAFrames(dcaData)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder, err := newKeywordDecoder()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Opus decoder: %w", err)
+	}
+
+	pcm := make([]int16, 0, len(frames)*keywordSampleRate/50)
+	buffer := make([]int16, maxDecodedSamples)
+	for i, frame := range frames {
+		n, err := decoder.Decode(frame, buffer)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode Opus frame %d: %w", i, err)
+		}
+		pcm = append(pcm, buffer[:n]...)
+	}
+	return pcm, nil
+}
@@ -0,0 +1,116 @@
+package tts
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeOpusDecoder decodes every packet into a 20ms frame filled with the packet's first byte
+type fakeOpusDecoder struct{}
+
+func (fakeOpusDecoder) Decode(data []byte, pcm []int16) (int, error) {
+	n := keywordSampleRate / 50
+	for i := 0; i < n; i++ {
+		pcm[i] = int16(data[0])
+	}
+	return n, nil
+}
+
+// receivedFrame is a frame passed to a voice handler
+type receivedFrame struct {
+	guildID, userID string
+	pcm             []int16
+}
+
+// frameRecorder collects the frames passed to a voice handler
+type frameRecorder struct {
+	mu     sync.Mutex
+	frames []receivedFrame
+}
+
+func (r *frameRecorder) handle(guildID, userID string, pcm []int16) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.frames = append(r.frames, receivedFrame{guildID, userID, pcm})
+}
+
+func (r *frameRecorder) received() []receivedFrame {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]receivedFrame(nil), r.frames...)
+}
+
+func TestVoiceReceiver_DecodesSpeechOfKnownUsers(t *testing.T) {
+	recorder := &frameRecorder{}
+	receiver := newVoiceReceiver("guild1", recorder.handle)
+	receiver.newDecoder = func() (opusDecoder, error) { return fakeOpusDecoder{}, nil }
+
+	packets := make(chan *discordgo.Packet)
+	finished := make(chan struct{})
+	go func() {
+		receiver.run(packets)
+		close(finished)
+	}()
+
+	// Streams are ignored until a speaking update says whose they are
+	packets <- &discordgo.Packet{SSRC: 7, Opus: []byte{1}}
+	receiver.handleSpeakingUpdate(nil, &discordgo.VoiceSpeakingUpdate{UserID: "user1", SSRC: 7, Speaking: true})
+	packets <- &discordgo.Packet{SSRC: 7, Opus: []byte{2}}
+	packets <- &discordgo.Packet{SSRC: 7, Opus: []byte{3}}
+
+	// The end of the transmission is reported once the user goes quiet
+	require.Eventually(t, func() bool { return len(recorder.received()) == 3 }, time.Second, 10*time.Millisecond)
+	frames := recorder.received()
+	assert.Equal(t, receivedFrame{"guild1", "user1", frames[0].pcm}, frames[0])
+	assert.Len(t, frames[0].pcm, keywordSampleRate/50)
+	assert.Equal(t, int16(2), frames[0].pcm[0])
+	assert.Equal(t, int16(3), frames[1].pcm[0])
+	assert.Equal(t, receivedFrame{"guild1", "user1", nil}, frames[2])
+
+	receiver.stop()
+	receiver.stop() // Stopping twice is harmless
+	select {
+	case <-finished:
+	case <-time.After(time.Second):
+		t.Fatal("receiver did not stop")
+	}
+}
+
+func TestVoiceManager_JoinChannel_ListensWhereEnabled(t *testing.T) {
+	vm := NewVoiceManager(&discordgo.Session{}).(*voiceManager)
+
+	joinedDeaf := make(map[string]bool)
+	vm.session = &mockDiscordVoiceSession{
+		joinFunc: func(guildID, channelID string, mute, deaf bool) (*discordgo.VoiceConnection, error) {
+			joinedDeaf[guildID] = deaf
+			connection := createMockVoiceConnection(guildID, channelID)
+			connection.OpusRecv = make(chan *discordgo.Packet)
+			return connection, nil
+		},
+	}
+
+	recorder := &frameRecorder{}
+	vm.SetVoiceReceiver(func(guildID string) bool { return guildID == "guild1" }, recorder.handle)
+
+	listening, err := vm.JoinChannel("guild1", "voice1")
+	require.NoError(t, err)
+	assert.False(t, joinedDeaf["guild1"], "joins undeafened to hear voice commands")
+	assert.NotNil(t, listening.stopReceiving)
+
+	deafened, err := vm.JoinChannel("guild2", "voice2")
+	require.NoError(t, err)
+	assert.True(t, joinedDeaf["guild2"])
+	assert.Nil(t, deafened.stopReceiving)
+
+	require.NoError(t, vm.LeaveChannel("guild1"))
+}
+
+func TestDecodeDCA_InvalidData(t *testing.T) {
+	_, err := decodeDCA([]byte{0xFF, 0xFF, 0x01})
+	assert.Error(t, err)
+}
@@ -0,0 +1,183 @@
+package tts
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// Voice send loop tuning
+const (
+	sendQueueFrames = 2                // Frames queued ahead of the socket; few, so the clip mixer keeps in step
+	maxPacingLag    = dcaFrameDuration // How far the loop may fall behind before it starts a new schedule
+)
+
+// voiceSender sends a connection's Opus frames from its own goroutine. Playback queues
+// frames on it and waits for the end of the utterance, so the voice manager's lock is
+// not held or contended while audio plays. Frames are sent 20ms apart on the monotonic
+// clock; a connection has one sender from its first playback until it leaves. While the
+// connection is on standby the sender keeps taking frames into pending, so synthesis is
+// not stalled.
+type voiceSender struct {
+	vm         *voiceManager
+	connection *VoiceConnection
+	frames     chan queuedFrame
+	ctx        context.Context // Done once the connection leaves or the loop stops
+	cancel     context.CancelFunc
+	timer      *time.Timer
+	next       time.Time     // When the next frame is due, zero before the first
+	pending    []queuedFrame // Frames taken while on standby, sent before the queue
+}
+
+// utterance is a run of frames queued by one playback
+type utterance struct {
+	ctx     context.Context
+	guildID string
+	stats   *voiceSendStats
+	sent    atomic.Int64  // Frames accepted by the socket
+	err     error         // Why the utterance failed, set before done is closed
+	done    chan struct{} // Closed once the last frame is sent or the utterance fails
+	failed  bool          // Owned by the send loop
+}
+
+// queuedFrame is a frame waiting in a send loop, or the end of its utterance
+type queuedFrame struct {
+	utterance *utterance
+	data      []byte
+	readyAt   time.Time
+	end       bool
+}
+
+// newVoiceSender creates a send loop for a connection; run starts it
+func newVoiceSender(vm *voiceManager, connection *VoiceConnection) *voiceSender {
+	ctx, cancel := context.WithCancel(context.Background())
+	timer := time.NewTimer(time.Hour)
+	timer.Stop()
+	return &voiceSender{
+		vm:         vm,
+		connection: connection,
+		frames:     make(chan queuedFrame, sendQueueFrames),
+		ctx:        ctx,
+		cancel:     cancel,
+		timer:      timer,
+	}
+}
+
+// senderLocked returns a connection's send loop, starting it on first use or after it
+// stopped. vm.mutex must be held.
+func (vm *voiceManager) senderLocked(connection *VoiceConnection) *voiceSender {
+	if connection.sender == nil || connection.sender.ctx.Err() != nil {
+		connection.sender = newVoiceSender(vm, connection)
+		go connection.sender.run()
+	}
+	return connection.sender
+}
+
+// run sends queued frames until the sender is stopped
+func (s *voiceSender) run() {
+	defer s.cancel() // Runs after a panic too, so playback waiting on the loop is released
+	defer Recover(s.vm.metrics, "voice sender", s.connection.GuildID)
+
+	for {
+		if len(s.pending) > 0 {
+			frame := s.pending[0]
+			s.pending = s.pending[1:]
+			s.send(frame)
+			continue
+		}
+		select {
+		case <-s.ctx.Done():
+			return
+		case frame := <-s.frames:
+			s.send(frame)
+		}
+	}
+}
+
+// send sends one queued frame, holding it while the connection is on standby. Frames of
+// skipped utterances are discarded and those of failed utterances counted as dropped.
+func (s *voiceSender) send(frame queuedFrame) {
+	u := frame.utterance
+	if u.failed {
+		if !frame.end {
+			s.vm.recordFramesDropped(u.guildID, u.stats, 1)
+		}
+		return
+	}
+	if frame.end {
+		close(u.done)
+		return
+	}
+	if u.ctx.Err() != nil {
+		return
+	}
+
+	s.pace(u.ctx)
+
+	var stalledAt time.Time
+	for !s.write(u.ctx, frame.data, int(u.sent.Load())) {
+		if u.ctx.Err() != nil {
+			return
+		}
+		if stalledAt.IsZero() {
+			stalledAt = time.Now()
+		}
+		if err := s.awaitReconnect(u.ctx, stalledAt.Add(s.vm.reconnectGrace)); err != nil {
+			if u.ctx.Err() != nil {
+				return
+			}
+			s.vm.recordFramesDropped(u.guildID, u.stats, 1)
+			u.failed = true
+			u.err = fmt.Errorf("timeout sending DCA frame %d for guild %s: %w", u.sent.Load(), u.guildID, err)
+			close(u.done)
+			return
+		}
+		s.vm.setSpeaking(s.connection, true)
+		s.next = time.Time{} // The outage broke the schedule
+	}
+	s.vm.recordFrameSent(u.guildID, u.stats, frame.readyAt)
+	u.sent.Add(1)
+}
+
+// pace waits until the next frame is due. Frames are due 20ms apart from the start of a
+// schedule, so timer overshoot does not add up over an utterance. A loop that falls more
+// than a frame behind, because synthesis or the socket was slow, starts a new schedule
+// instead of bursting to catch up.
+func (s *voiceSender) pace(ctx context.Context) {
+	now := time.Now()
+	if s.next.IsZero() || now.Sub(s.next) > maxPacingLag {
+		s.next = now
+	}
+	if wait := s.next.Sub(now); wait > 0 {
+		s.timer.Reset(wait)
+		select {
+		case <-s.timer.C:
+		case <-ctx.Done():
+			s.timer.Stop()
+		}
+	}
+	s.next = s.next.Add(dcaFrameDuration)
+}
+
+// write hands one frame to the connection's voice socket, putting the connection on
+// standby if it does not accept the frame in time. It reports whether the frame was sent,
+// giving up without a standby when ctx is done or the connection is already down.
+func (s *voiceSender) write(ctx context.Context, data []byte, index int) bool {
+	opusSend, ok := s.vm.opusSendFor(s.connection)
+	if !ok {
+		return false
+	}
+
+	s.timer.Reset(s.vm.sendTimeout)
+	defer s.timer.Stop()
+	select {
+	case opusSend <- data:
+		return true
+	case <-ctx.Done():
+		return false
+	case <-s.timer.C:
+		s.vm.enterStandby(s.connection, fmt.Sprintf("frame %d was not accepted", index), true)
+		return false
+	}
+}
@@ -0,0 +1,119 @@
+package tts
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVoiceSender_PacesFrames(t *testing.T) {
+	voiceConn := createMockVoiceConnection("guild1", "channel1")
+	voiceConn.OpusSend = make(chan []byte)
+	vm, _ := newStandbyTestManager(t, voiceConn)
+
+	const count = 50
+	frames := make(chan []byte, count)
+	for i := 0; i < count; i++ {
+		frames <- []byte{byte(i)}
+	}
+	close(frames)
+
+	// Discord takes frames as soon as they are sent
+	received := make(chan time.Time, count)
+	go func() {
+		for i := 0; i < count; i++ {
+			<-voiceConn.OpusSend
+			received <- time.Now()
+		}
+	}()
+
+	require.NoError(t, vm.StreamAudio("guild1", frames))
+
+	var first, last time.Time
+	for i := 0; i < count; i++ {
+		at := <-received
+		if i == 0 {
+			first = at
+		}
+		last = at
+	}
+
+	// Timer overshoot does not add up over the utterance
+	span := last.Sub(first)
+	assert.GreaterOrEqual(t, span, (count-1)*dcaFrameDuration-5*time.Millisecond)
+	assert.Less(t, span, (count-1)*dcaFrameDuration+10*dcaFrameDuration)
+}
+
+func TestVoiceSender_PaceResyncsAfterFallingBehind(t *testing.T) {
+	sender := newVoiceSender(&voiceManager{}, &VoiceConnection{GuildID: "guild1"})
+
+	// A frame that is due soon is waited for
+	sender.next = time.Now().Add(10 * time.Millisecond)
+	due := sender.next
+	sender.pace(context.Background())
+	assert.False(t, time.Now().Before(due))
+	assert.Equal(t, due.Add(dcaFrameDuration), sender.next)
+
+	// A loop far behind starts a new schedule instead of bursting
+	sender.next = time.Now().Add(-time.Second)
+	started := time.Now()
+	sender.pace(context.Background())
+	assert.Less(t, time.Since(started), dcaFrameDuration)
+	assert.WithinDuration(t, started.Add(dcaFrameDuration), sender.next, 10*time.Millisecond)
+}
+
+func TestVoiceSender_LeavingStopsPlayback(t *testing.T) {
+	voiceConn := createMockVoiceConnection("guild1", "channel1")
+	voiceConn.OpusSend = make(chan []byte)
+	vm, connection := newStandbyTestManager(t, voiceConn)
+	vm.sendTimeout = time.Minute
+
+	frames := make(chan []byte, 1)
+	frames <- []byte{1}
+	done := make(chan error, 1)
+	go func() {
+		done <- vm.StreamAudio("guild1", frames)
+	}()
+	require.Eventually(t, func() bool {
+		vm.mutex.RLock()
+		defer vm.mutex.RUnlock()
+		return connection.sender != nil
+	}, time.Second, time.Millisecond)
+
+	require.NoError(t, vm.LeaveChannel("guild1"))
+
+	select {
+	case err := <-done:
+		assert.ErrorIs(t, err, ErrPlaybackSkipped)
+	case <-time.After(time.Second):
+		t.Fatal("playback did not stop when the bot left")
+	}
+	assert.Empty(t, drainFrames(voiceConn.OpusSend))
+}
+
+func TestVoiceSender_RestartsForNextPlayback(t *testing.T) {
+	voiceConn := createMockVoiceConnection("guild1", "channel1")
+	voiceConn.OpusSend = make(chan []byte, 10)
+	vm, connection := newStandbyTestManager(t, voiceConn)
+
+	frames := make(chan []byte, 1)
+	frames <- []byte{1}
+	close(frames)
+	require.NoError(t, vm.StreamAudio("guild1", frames))
+
+	vm.mutex.Lock()
+	stopped := connection.sender
+	stopped.cancel()
+	vm.mutex.Unlock()
+
+	frames = make(chan []byte, 1)
+	frames <- []byte{2}
+	close(frames)
+	require.NoError(t, vm.StreamAudio("guild1", frames))
+
+	assert.NotSame(t, stopped, connection.sender)
+	assert.Equal(t, [][]byte{{1}, {2}}, drainFrames(voiceConn.OpusSend))
+}
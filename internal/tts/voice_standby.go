@@ -0,0 +1,203 @@
+package tts
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"darrot/internal/events"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Voice connection standby tuning
+const (
+	defaultFrameSendTimeout     = 5 * time.Second
+	defaultReconnectGracePeriod = 15 * time.Second
+	standbySettleTime           = 250 * time.Millisecond // Lets discordgo close the old voice socket before readiness is trusted
+	standbyPollInterval         = 50 * time.Millisecond
+	maxStandbyFrames            = 3000 // One minute of 20ms frames
+)
+
+// A voice connection goes on standby when Discord moves it to another voice server,
+// the gateway resumes with the voice socket down, or frames stop being accepted.
+// Playback holds the in-flight utterance while on standby and continues from the same
+// frame once the connection is ready again, so neither the queue nor guild processing
+// is restarted.
+
+// handleVoiceServerUpdate puts a guild's connection on standby while discordgo reopens
+// the voice socket on the new server
+func (vm *voiceManager) handleVoiceServerUpdate(s *discordgo.Session, vsu *discordgo.VoiceServerUpdate) {
+	vm.mutex.RLock()
+	connection, exists := vm.connections[vsu.GuildID]
+	vm.mutex.RUnlock()
+
+	vm.mutex.Lock()
+	vm.endpoints[vsu.GuildID] = vsu.Endpoint
+	vm.mutex.Unlock()
+
+	if exists {
+		vm.enterStandby(connection, "voice server changed", false)
+	}
+}
+
+// handleResumed rejoins voice channels whose voice socket did not survive a gateway
+// resume
+func (vm *voiceManager) handleResumed(s *discordgo.Session, r *discordgo.Resumed) {
+	vm.mutex.RLock()
+	var dropped []*VoiceConnection
+	for _, connection := range vm.connections {
+		if connection.Connection == nil || !voiceReady(connection.Connection) {
+			dropped = append(dropped, connection)
+		}
+	}
+	vm.mutex.RUnlock()
+
+	for _, connection := range dropped {
+		vm.enterStandby(connection, "gateway resumed without voice", true)
+	}
+}
+
+// enterStandby marks a connection as reconnecting. With rejoin set the voice channel is
+// rejoined in the background, backing off while its voice server keeps failing;
+// otherwise discordgo is expected to reconnect on its own.
+func (vm *voiceManager) enterStandby(connection *VoiceConnection, reason string, rejoin bool) {
+	vm.mutex.Lock()
+	alreadyWaiting := connection.Reconnecting
+	connection.Reconnecting = true
+	connection.standbySince = time.Now()
+	callback := vm.stateCallback
+	vm.mutex.Unlock()
+
+	if alreadyWaiting {
+		return
+	}
+
+	log.Printf("Voice connection for guild %s on standby: %s", connection.GuildID, reason)
+	vm.eventBus.Publish(events.ConnectionLost{
+		GuildID:   connection.GuildID,
+		ChannelID: connection.ChannelID,
+		Reason:    reason,
+		LostAt:    time.Now(),
+	})
+	if callback != nil {
+		callback(connection.GuildID, false)
+	}
+
+	if rejoin {
+		go vm.rejoin(connection)
+	}
+}
+
+// leaveStandby reports whether a connection can send audio, ending its standby once the
+// voice socket is ready again
+func (vm *voiceManager) leaveStandby(connection *VoiceConnection) bool {
+	vm.mutex.Lock()
+	if !connection.Reconnecting {
+		vm.mutex.Unlock()
+		return true
+	}
+	if connection.Connection == nil || time.Since(connection.standbySince) < standbySettleTime || !voiceReady(connection.Connection) {
+		vm.mutex.Unlock()
+		return false
+	}
+	connection.Reconnecting = false
+	outage := time.Since(connection.standbySince)
+	callback := vm.stateCallback
+	vm.mutex.Unlock()
+
+	log.Printf("Voice connection for guild %s is back after %s", connection.GuildID, outage.Round(time.Millisecond))
+	if callback != nil {
+		callback(connection.GuildID, true)
+	}
+	return true
+}
+
+// awaitReconnect waits for the sender's connection to leave standby. Frames that keep
+// being queued are taken into pending so synthesis is not stalled. It gives up once
+// deadline has passed, or with ErrPlaybackSkipped when ctx is done.
+func (s *voiceSender) awaitReconnect(ctx context.Context, deadline time.Time) error {
+	ticker := time.NewTicker(standbyPollInterval)
+	defer ticker.Stop()
+
+	for {
+		if s.vm.leaveStandby(s.connection) {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return withKind(ErrVoiceGateway, fmt.Errorf("voice connection did not recover within %s", s.vm.reconnectGrace))
+		}
+
+		// Stop taking frames when the buffer is full and let the producer block instead
+		drain := s.frames
+		if len(s.pending) >= maxStandbyFrames {
+			drain = nil
+		}
+
+		select {
+		case frame := <-drain:
+			s.pending = append(s.pending, frame)
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ErrPlaybackSkipped
+		}
+	}
+}
+
+// opusSendFor returns the channel a connection's frames are sent on. A connection on
+// standby is taken off it first when its socket is back; while it is still down
+// opusSendFor reports false. Connections that are up only take the read lock.
+func (vm *voiceManager) opusSendFor(connection *VoiceConnection) (chan<- []byte, bool) {
+	vm.mutex.RLock()
+	reconnecting := connection.Reconnecting
+	opusSend := connection.Connection.OpusSend
+	vm.mutex.RUnlock()
+
+	if !reconnecting {
+		return opusSend, true
+	}
+	if !vm.leaveStandby(connection) {
+		return nil, false
+	}
+
+	// A rejoin swaps in a new socket
+	vm.mutex.RLock()
+	defer vm.mutex.RUnlock()
+	return connection.Connection.OpusSend, true
+}
+
+// reconnect rejoins a connection's voice channel and swaps the new discordgo
+// connection into the existing wrapper, keeping its playback state
+func (vm *voiceManager) reconnect(connection *VoiceConnection) error {
+	voiceConn, err := vm.session.ChannelVoiceJoin(connection.GuildID, connection.ChannelID, false, true)
+	if err != nil {
+		return withKind(ErrVoiceGateway, fmt.Errorf("failed to rejoin voice channel: %w", err))
+	}
+	if voiceConn == nil {
+		return withKind(ErrVoiceGateway, fmt.Errorf("failed to rejoin voice channel: no connection returned"))
+	}
+
+	requestedToSpeak, err := joinStageAsSpeaker(vm.session, connection.GuildID, connection.ChannelID)
+	if err != nil {
+		log.Printf("Warning: staying in the audience of guild %s: %v", connection.GuildID, err)
+	}
+
+	vm.mutex.Lock()
+	defer vm.mutex.Unlock()
+
+	// The bot may have left while rejoining
+	if vm.connections[connection.GuildID] != connection {
+		return fmt.Errorf("voice connection for guild %s was closed while reconnecting", connection.GuildID)
+	}
+	connection.Connection = voiceConn
+	connection.RequestedToSpeak = requestedToSpeak
+	return nil
+}
+
+// voiceReady reports whether discordgo has an open voice socket for a connection
+func voiceReady(voiceConn *discordgo.VoiceConnection) bool {
+	voiceConn.RLock()
+	defer voiceConn.RUnlock()
+	return voiceConn.Ready
+}
@@ -0,0 +1,192 @@
+package tts
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"darrot/internal/events"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newStandbyTestManager returns a voice manager connected to guild1 with short standby
+// timeouts
+func newStandbyTestManager(t *testing.T, voiceConn *discordgo.VoiceConnection) (*voiceManager, *VoiceConnection) {
+	t.Helper()
+
+	vm := NewVoiceManager(&discordgo.Session{}).(*voiceManager)
+	vm.session = &mockDiscordVoiceSession{}
+	vm.sendTimeout = 20 * time.Millisecond
+	vm.reconnectGrace = 2 * time.Second
+
+	connection := &VoiceConnection{GuildID: "guild1", ChannelID: "channel1", Connection: voiceConn}
+	vm.connections["guild1"] = connection
+	return vm, connection
+}
+
+func setReady(voiceConn *discordgo.VoiceConnection, ready bool) {
+	voiceConn.Lock()
+	voiceConn.Ready = ready
+	voiceConn.Unlock()
+}
+
+func drainFrames(opusSend chan []byte) [][]byte {
+	var frames [][]byte
+	for {
+		select {
+		case frame := <-opusSend:
+			frames = append(frames, frame)
+		default:
+			return frames
+		}
+	}
+}
+
+func TestVoiceManager_StandbyBuffersUtterance(t *testing.T) {
+	voiceConn := createMockVoiceConnection("guild1", "channel1")
+	voiceConn.OpusSend = make(chan []byte, 10)
+	vm, connection := newStandbyTestManager(t, voiceConn)
+
+	var mu sync.Mutex
+	var states []bool
+	vm.SetConnectionStateCallback(func(guildID string, connected bool) {
+		mu.Lock()
+		states = append(states, connected)
+		mu.Unlock()
+	})
+
+	// Discord moves the connection to another voice server before playback starts
+	setReady(voiceConn, false)
+	vm.handleVoiceServerUpdate(nil, &discordgo.VoiceServerUpdate{GuildID: "guild1"})
+	assert.True(t, connection.Reconnecting)
+
+	frames := make(chan []byte)
+	done := make(chan error, 1)
+	go func() {
+		done <- vm.StreamAudio("guild1", frames)
+	}()
+
+	// Synthesis keeps producing while playback waits
+	for i := byte(0); i < 5; i++ {
+		select {
+		case frames <- []byte{i}:
+		case <-time.After(time.Second):
+			t.Fatalf("frame %d was not buffered during standby", i)
+		}
+	}
+	close(frames)
+	assert.Empty(t, voiceConn.OpusSend)
+
+	setReady(voiceConn, true)
+	require.NoError(t, <-done)
+
+	assert.Equal(t, [][]byte{{0}, {1}, {2}, {3}, {4}}, drainFrames(voiceConn.OpusSend))
+	assert.False(t, connection.Reconnecting)
+	mu.Lock()
+	assert.Equal(t, []bool{false, true}, states)
+	mu.Unlock()
+}
+
+func TestVoiceManager_StandbyPublishesConnectionLost(t *testing.T) {
+	voiceConn := createMockVoiceConnection("guild1", "channel1")
+	vm, connection := newStandbyTestManager(t, voiceConn)
+
+	bus := events.New()
+	vm.SetEventBus(bus)
+	var lost []events.ConnectionLost
+	events.Subscribe(bus, func(e events.ConnectionLost) { lost = append(lost, e) })
+
+	vm.enterStandby(connection, "voice server moved", false)
+	vm.enterStandby(connection, "still waiting", false) // Already on standby
+
+	require.Len(t, lost, 1)
+	assert.Equal(t, "guild1", lost[0].GuildID)
+	assert.Equal(t, "channel1", lost[0].ChannelID)
+	assert.Equal(t, "voice server moved", lost[0].Reason)
+}
+
+func TestVoiceManager_StalledConnectionRejoinsMidUtterance(t *testing.T) {
+	stalled := createMockVoiceConnection("guild1", "channel1")
+	stalled.OpusSend = make(chan []byte, 1)
+	vm, connection := newStandbyTestManager(t, stalled)
+
+	rejoined := createMockVoiceConnection("guild1", "channel1")
+	rejoined.OpusSend = make(chan []byte, 10)
+	setReady(rejoined, true)
+	vm.session = &mockDiscordVoiceSession{
+		joinFunc: func(guildID, channelID string, mute, deaf bool) (*discordgo.VoiceConnection, error) {
+			return rejoined, nil
+		},
+	}
+
+	frames := make(chan []byte, 3)
+	frames <- []byte{1}
+	frames <- []byte{2}
+	frames <- []byte{3}
+	close(frames)
+
+	// The first frame fills the stalled socket; the second is held and sent after rejoining
+	require.NoError(t, vm.StreamAudio("guild1", frames))
+
+	assert.Equal(t, [][]byte{{1}}, drainFrames(stalled.OpusSend))
+	assert.Equal(t, [][]byte{{2}, {3}}, drainFrames(rejoined.OpusSend))
+	assert.Same(t, connection, vm.connections["guild1"])
+	assert.Equal(t, rejoined, connection.Connection)
+}
+
+func TestVoiceManager_StandbyGracePeriod(t *testing.T) {
+	voiceConn := createMockVoiceConnection("guild1", "channel1")
+	vm, _ := newStandbyTestManager(t, voiceConn)
+	vm.reconnectGrace = 100 * time.Millisecond
+
+	vm.handleVoiceServerUpdate(nil, &discordgo.VoiceServerUpdate{GuildID: "guild1"})
+
+	frames := make(chan []byte, 1)
+	frames <- []byte{1}
+	close(frames)
+
+	err := vm.StreamAudio("guild1", frames)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "did not recover")
+}
+
+func TestVoiceManager_ResumedRejoinsDroppedConnections(t *testing.T) {
+	dropped := createMockVoiceConnection("guild1", "channel1")
+	vm, connection := newStandbyTestManager(t, dropped)
+	connection.IsPaused = true
+
+	healthy := createMockVoiceConnection("guild2", "channel2")
+	setReady(healthy, true)
+	vm.connections["guild2"] = &VoiceConnection{GuildID: "guild2", ChannelID: "channel2", Connection: healthy}
+
+	rejoined := createMockVoiceConnection("guild1", "channel1")
+	joins := make(chan string, 2)
+	vm.session = &mockDiscordVoiceSession{
+		joinFunc: func(guildID, channelID string, mute, deaf bool) (*discordgo.VoiceConnection, error) {
+			joins <- guildID
+			return rejoined, nil
+		},
+	}
+
+	vm.handleResumed(nil, &discordgo.Resumed{})
+
+	select {
+	case guildID := <-joins:
+		assert.Equal(t, "guild1", guildID)
+	case <-time.After(time.Second):
+		t.Fatal("dropped connection was not rejoined")
+	}
+	assert.Eventually(t, func() bool {
+		vm.mutex.RLock()
+		defer vm.mutex.RUnlock()
+		return connection.Connection == rejoined
+	}, time.Second, 10*time.Millisecond)
+
+	// Playback state survives the rejoin and healthy connections are left alone
+	assert.True(t, connection.IsPaused)
+	assert.False(t, vm.connections["guild2"].Reconnecting)
+	assert.Empty(t, joins)
+}
@@ -0,0 +1,177 @@
+package tts
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"darrot/internal/events"
+)
+
+// Engine warm-up metrics
+const (
+	MetricTTSWarmups       = "darrot_tts_warmups_total"
+	MetricTTSWarmupSeconds = "darrot_tts_warmup_seconds"
+)
+
+// Why the engine was warmed up
+const (
+	WarmupReasonStartup = "startup"
+	WarmupReasonJoin    = "join"
+)
+
+// warmupText is synthesized to warm up the engine. The audio is discarded.
+const warmupText = "Ready."
+
+// warmupTimeout bounds a single warm-up synthesis
+const warmupTimeout = 30 * time.Second
+
+// WarmupInterval is how long a voice counts as warm after it was synthesized for a warm-up.
+// Joining a voice channel within it does not warm the voice up again.
+const WarmupInterval = 10 * time.Minute
+
+// EngineWarmer synthesizes a short phrase when the bot starts and when it joins a voice
+// channel, so the connection to the TTS engine is established and the guild's voice is
+// ready before the first message. Otherwise the first message after a start pays for
+// connecting and for the engine loading the voice. Warm-ups run in the background, do not
+// count towards daily character budgets and are skipped while the engine is unavailable.
+type EngineWarmer struct {
+	ttsManager    TTSManager
+	configService ConfigService
+	defaults      TTSConfig // Voice settings of the startup warm-up
+	metrics       *Metrics
+	logger        *log.Logger
+	now           func() time.Time // Overridable for tests
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu     sync.Mutex
+	warmed map[string]time.Time // When each voice was last warmed up
+}
+
+// NewEngineWarmer creates an engine warmer. defaults are the voice settings warmed up on
+// startup; joins warm up the guild's own. metrics may be nil.
+func NewEngineWarmer(ttsManager TTSManager, configService ConfigService, defaults TTSConfig, metrics *Metrics, logger *log.Logger) *EngineWarmer {
+	if metrics != nil {
+		metrics.Describe(MetricTTSWarmups, MetricTypeCounter, "Warm-up syntheses made on startup and when joining a voice channel")
+		metrics.Describe(MetricTTSWarmupSeconds, MetricTypeGauge, "Seconds the latest warm-up synthesis took")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &EngineWarmer{
+		ttsManager:    ttsManager,
+		configService: configService,
+		defaults:      defaults,
+		metrics:       metrics,
+		logger:        logger,
+		now:           time.Now,
+		ctx:           ctx,
+		cancel:        cancel,
+		warmed:        make(map[string]time.Time),
+	}
+}
+
+// Subscribe warms up a guild's voice whenever the bot joins one of its voice channels
+func (w *EngineWarmer) Subscribe(bus *events.Bus) (unsubscribe func()) {
+	return events.Subscribe(bus, func(e events.VoiceJoined) {
+		w.run(WarmupReasonJoin, e.GuildID)
+	})
+}
+
+// Start warms up the default voice in the background
+func (w *EngineWarmer) Start() error {
+	w.run(WarmupReasonStartup, "")
+	return nil
+}
+
+// Stop cancels warm-ups in progress and waits for them to end
+func (w *EngineWarmer) Stop() {
+	w.cancel()
+	w.wg.Wait()
+}
+
+// run warms up in the background, with the settings of guildID when it is set
+func (w *EngineWarmer) run(reason, guildID string) {
+	if w.ctx.Err() != nil {
+		return
+	}
+
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		defer Recover(w.metrics, "tts warm-up", guildID)
+
+		config := w.configFor(guildID)
+		if err := w.WarmUp(w.ctx, reason, config); err != nil && w.ctx.Err() == nil {
+			w.logger.Printf("Warning: TTS warm-up on %s failed: %v", reason, err)
+		}
+	}()
+}
+
+// WarmUp synthesizes warmupText with config and discards the audio. A voice warmed up
+// within WarmupInterval is skipped, as is an engine that is unavailable.
+func (w *EngineWarmer) WarmUp(ctx context.Context, reason string, config TTSConfig) error {
+	if engineStatus, ok := w.ttsManager.(TTSEngineStatus); ok && engineStatus.EngineError() != nil {
+		w.logger.Printf("Skipping TTS warm-up on %s: the engine is unavailable", reason)
+		return nil
+	}
+
+	started := w.now()
+	w.mu.Lock()
+	last, warm := w.warmed[config.Voice]
+	if warm && started.Sub(last) < WarmupInterval {
+		w.mu.Unlock()
+		return nil
+	}
+	w.warmed[config.Voice] = started
+	w.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, warmupTimeout)
+	defer cancel()
+
+	var err error
+	if converter, ok := w.ttsManager.(ContextSpeechConverter); ok {
+		_, err = converter.ConvertToSpeechContext(ctx, warmupText, "", config)
+	} else {
+		_, err = w.ttsManager.ConvertToSpeech(warmupText, "", config)
+	}
+	elapsed := w.now().Sub(started)
+
+	if err != nil {
+		// The next join tries again
+		w.mu.Lock()
+		delete(w.warmed, config.Voice)
+		w.mu.Unlock()
+		w.record(reason, "failed", elapsed)
+		return err
+	}
+
+	w.record(reason, "ok", elapsed)
+	w.logger.Printf("Warmed up TTS voice %s on %s in %s", config.Voice, reason, elapsed.Round(time.Millisecond))
+	return nil
+}
+
+// configFor returns the voice settings of a guild, or the defaults when guildID is empty
+// or the guild's settings cannot be read
+func (w *EngineWarmer) configFor(guildID string) TTSConfig {
+	if guildID == "" || w.configService == nil {
+		return w.defaults
+	}
+	settings, err := w.configService.GetTTSSettings(guildID)
+	if err != nil || settings == nil {
+		return w.defaults
+	}
+	return *settings
+}
+
+// record counts a warm-up and how long it took
+func (w *EngineWarmer) record(reason, result string, elapsed time.Duration) {
+	if w.metrics == nil {
+		return
+	}
+	w.metrics.IncCounter(MetricTTSWarmups, Labels{"reason": reason, "result": result})
+	w.metrics.SetGauge(MetricTTSWarmupSeconds, Labels{"reason": reason}, elapsed.Seconds())
+}
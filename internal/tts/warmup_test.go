@@ -0,0 +1,131 @@
+package tts
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"sync"
+	"testing"
+	"time"
+
+	"darrot/internal/config"
+	"darrot/internal/events"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// warmupVoices records the voices a mock TTS manager synthesized
+type warmupVoices struct {
+	mu     sync.Mutex
+	voices []string
+}
+
+func (v *warmupVoices) add(voice string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.voices = append(v.voices, voice)
+}
+
+func (v *warmupVoices) list() []string {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return append([]string(nil), v.voices...)
+}
+
+func setupEngineWarmerTest(t *testing.T, convert func(text, voice string, config TTSConfig) ([]byte, error)) (*EngineWarmer, *mockConfigService, *Metrics) {
+	t.Helper()
+
+	configService := newMockConfigService()
+	metrics := NewMetrics()
+	defaults := TTSConfig{Voice: DefaultVoice, Speed: DefaultTTSSpeed, Volume: DefaultTTSVolume, Format: AudioFormatDCA}
+	warmer := NewEngineWarmer(&mockTTSManager{convertFunc: convert}, configService, defaults, metrics, log.New(io.Discard, "", 0))
+	t.Cleanup(warmer.Stop)
+	return warmer, configService, metrics
+}
+
+func TestEngineWarmer_StartupAndJoin(t *testing.T) {
+	voices := &warmupVoices{}
+	warmer, configService, metrics := setupEngineWarmerTest(t, func(text, voice string, config TTSConfig) ([]byte, error) {
+		assert.Equal(t, warmupText, text)
+		voices.add(config.Voice)
+		return []byte("audio"), nil
+	})
+	configService.configs["guild1"] = &TTSConfig{Voice: "de-DE-Wavenet-B", Speed: 1.0, Volume: 1.0}
+
+	bus := events.New()
+	unsubscribe := warmer.Subscribe(bus)
+	defer unsubscribe()
+
+	require.NoError(t, warmer.Start())
+	require.Eventually(t, func() bool { return len(voices.list()) == 1 }, time.Second, 5*time.Millisecond)
+	assert.Equal(t, []string{DefaultVoice}, voices.list())
+
+	bus.Publish(events.VoiceJoined{GuildID: "guild1", ChannelID: "voice1", JoinedAt: time.Now()})
+	require.Eventually(t, func() bool { return len(voices.list()) == 2 }, time.Second, 5*time.Millisecond)
+	assert.Equal(t, "de-DE-Wavenet-B", voices.list()[1])
+
+	warmer.Stop()
+	assert.Equal(t, 1.0, metrics.Value(MetricTTSWarmups, Labels{"reason": WarmupReasonStartup, "result": "ok"}))
+	assert.Equal(t, 1.0, metrics.Value(MetricTTSWarmups, Labels{"reason": WarmupReasonJoin, "result": "ok"}))
+}
+
+func TestEngineWarmer_SkipsWarmVoices(t *testing.T) {
+	voices := &warmupVoices{}
+	warmer, _, _ := setupEngineWarmerTest(t, func(text, voice string, config TTSConfig) ([]byte, error) {
+		voices.add(config.Voice)
+		return []byte("audio"), nil
+	})
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	warmer.now = func() time.Time { return now }
+	config := TTSConfig{Voice: DefaultVoice, Speed: 1.0, Volume: 1.0}
+
+	require.NoError(t, warmer.WarmUp(context.Background(), WarmupReasonJoin, config))
+	require.NoError(t, warmer.WarmUp(context.Background(), WarmupReasonJoin, config))
+	assert.Len(t, voices.list(), 1)
+
+	now = now.Add(WarmupInterval)
+	require.NoError(t, warmer.WarmUp(context.Background(), WarmupReasonJoin, config))
+	assert.Len(t, voices.list(), 2)
+}
+
+func TestEngineWarmer_RetriesAfterFailure(t *testing.T) {
+	calls := 0
+	warmer, _, metrics := setupEngineWarmerTest(t, func(text, voice string, config TTSConfig) ([]byte, error) {
+		calls++
+		if calls == 1 {
+			return nil, errors.New("deadline exceeded")
+		}
+		return []byte("audio"), nil
+	})
+	config := TTSConfig{Voice: DefaultVoice, Speed: 1.0, Volume: 1.0}
+
+	assert.Error(t, warmer.WarmUp(context.Background(), WarmupReasonStartup, config))
+	assert.NoError(t, warmer.WarmUp(context.Background(), WarmupReasonJoin, config))
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, 1.0, metrics.Value(MetricTTSWarmups, Labels{"reason": WarmupReasonStartup, "result": "failed"}))
+}
+
+func TestEngineWarmer_SkipsUnavailableEngine(t *testing.T) {
+	manager := NewDegradedGoogleTTSManager(NewMessageQueue(), CredentialSource{}, "", errors.New("no credentials"))
+	warmer := NewEngineWarmer(manager, nil, TTSConfig{Voice: DefaultVoice}, nil, log.New(io.Discard, "", 0))
+	defer warmer.Stop()
+
+	assert.NoError(t, warmer.WarmUp(context.Background(), WarmupReasonStartup, TTSConfig{Voice: DefaultVoice}))
+	assert.Empty(t, warmer.warmed)
+}
+
+func TestTTSSystem_EngineWarmupHook(t *testing.T) {
+	storage, err := NewStorageService(t.TempDir())
+	require.NoError(t, err)
+	services := &Services{Storage: storage, TTS: &mockTTSManager{}, Voice: newMockVoiceManager()}
+	cfg := &config.Config{TTS: config.TTSConfig{DefaultVoice: DefaultVoice, DefaultSpeed: 1.0, DefaultVolume: 1.0, MaxQueueSize: 10, Warmup: true}}
+
+	system, err := NewTTSSystemWithServices(&discordgo.Session{State: discordgo.NewState()}, cfg, log.New(io.Discard, "", 0), services)
+	require.NoError(t, err)
+	components := system.lifecycle.Components()
+	assert.Equal(t, "engine warm-up", components[len(components)-1])
+	assert.Equal(t, 2, events.Subscribers[events.VoiceJoined](services.Events)) // The engine warmer and the session recorder
+}
@@ -0,0 +1,119 @@
+package tts
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Sample rates a WAV file may have. Audio is resampled to 48kHz, so lower rates would
+// blow a small file up to a huge buffer.
+const (
+	minWAVSampleRate = 8000
+	maxWAVSampleRate = 192000
+)
+
+// wavAudio holds decoded PCM samples from a WAV file
+type wavAudio struct {
+	pcm        []byte
+	sampleRate int
+	channels   int
+}
+
+// Duration returns the playback length of the audio
+func (w *wavAudio) Duration() time.Duration {
+	bytesPerSecond := w.sampleRate * w.channels * 2
+	if bytesPerSecond == 0 {
+		return 0
+	}
+	return time.Duration(len(w.pcm)) * time.Second / time.Duration(bytesPerSecond)
+}
+
+// parseWAV extracts 16-bit PCM audio from a RIFF/WAVE file, skipping any
+// chunks other than "fmt " and "data"
+func parseWAV(data []byte) (*wavAudio, error) {
+	if len(data) < 12 || string(data[0:4]) != "RIFF" || string(data[8:12]) != "WAVE" {
+		return nil, fmt.Errorf("not a WAV file")
+	}
+
+	audio := &wavAudio{}
+	foundFormat := false
+
+	for offset := 12; offset+8 <= len(data); {
+		chunkID := string(data[offset : offset+4])
+		chunkSize := int(binary.LittleEndian.Uint32(data[offset+4 : offset+8]))
+		body := offset + 8
+		if chunkSize < 0 || body+chunkSize > len(data) {
+			chunkSize = len(data) - body // Tolerate truncated trailing chunks
+		}
+
+		switch chunkID {
+		case "fmt ":
+			if chunkSize < 16 {
+				return nil, fmt.Errorf("invalid WAV format chunk")
+			}
+			audioFormat := binary.LittleEndian.Uint16(data[body : body+2])
+			audio.channels = int(binary.LittleEndian.Uint16(data[body+2 : body+4]))
+			audio.sampleRate = int(binary.LittleEndian.Uint32(data[body+4 : body+8]))
+			bitsPerSample := binary.LittleEndian.Uint16(data[body+14 : body+16])
+
+			if audioFormat != 1 || bitsPerSample != 16 {
+				return nil, fmt.Errorf("unsupported WAV encoding: only 16-bit PCM is supported")
+			}
+			if audio.channels != 1 && audio.channels != 2 {
+				return nil, fmt.Errorf("unsupported WAV channel count: %d", audio.channels)
+			}
+			if audio.sampleRate < minWAVSampleRate || audio.sampleRate > maxWAVSampleRate {
+				return nil, fmt.Errorf("invalid WAV sample rate: %d", audio.sampleRate)
+			}
+			foundFormat = true
+		case "data":
+			if !foundFormat {
+				return nil, fmt.Errorf("WAV data chunk appears before format chunk")
+			}
+			audio.pcm = data[body : body+chunkSize]
+			return audio, nil
+		}
+
+		// Chunks are padded to an even size
+		offset = body + chunkSize + chunkSize%2
+	}
+
+	return nil, fmt.Errorf("WAV file has no audio data")
+}
+
+// encodeWAV wraps 16-bit PCM samples in a RIFF/WAVE container
+func encodeWAV(pcm []byte, sampleRate, channels int) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("RIFF")
+	binary.Write(&buf, binary.LittleEndian, uint32(36+len(pcm)))
+	buf.WriteString("WAVE")
+	buf.WriteString("fmt ")
+	binary.Write(&buf, binary.LittleEndian, uint32(16))
+	binary.Write(&buf, binary.LittleEndian, uint16(1)) // PCM
+	binary.Write(&buf, binary.LittleEndian, uint16(channels))
+	binary.Write(&buf, binary.LittleEndian, uint32(sampleRate))
+	binary.Write(&buf, binary.LittleEndian, uint32(sampleRate*channels*2))
+	binary.Write(&buf, binary.LittleEndian, uint16(channels*2))
+	binary.Write(&buf, binary.LittleEndian, uint16(16))
+	buf.WriteString("data")
+	binary.Write(&buf, binary.LittleEndian, uint32(len(pcm)))
+	buf.Write(pcm)
+	return buf.Bytes()
+}
+
+// toneWAV generates a mono sine tone as a WAV file
+func toneWAV(frequency float64, duration time.Duration, amplitude float64) []byte {
+	const sampleRate = 48000
+
+	samples := int(duration.Seconds() * sampleRate)
+	pcm := make([]byte, samples*2)
+	for i := 0; i < samples; i++ {
+		value := amplitude * math.Sin(2*math.Pi*frequency*float64(i)/sampleRate)
+		binary.LittleEndian.PutUint16(pcm[i*2:], uint16(int16(value*math.MaxInt16)))
+	}
+
+	return encodeWAV(pcm, sampleRate, 1)
+}
@@ -0,0 +1,421 @@
+package devstack
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"mock-discord/mockdiscord"
+)
+
+// Scenario actions
+const (
+	ActionJoin               = "join"                // Run /darrot-join for the test voice channel
+	ActionLeave              = "leave"               // Run /darrot-leave
+	ActionMessage            = "message"             // Post text in the paired text channel
+	ActionFlood              = "flood"               // Post count messages, interval apart
+	ActionDropVoice          = "drop_voice"          // Drop the bot's voice connection on the mock's side
+	ActionWait               = "wait"                // Pause for duration
+	ActionExpectSpoken       = "expect_spoken"       // At least count messages were spoken since the start
+	ActionExpectIdle         = "expect_idle"         // The queue has drained
+	ActionExpectConnected    = "expect_connected"    // The bot is in the voice channel
+	ActionExpectDisconnected = "expect_disconnected" // The bot is in no voice channel
+)
+
+// DefaultTimeout is how long expectations and commands wait when a step sets no timeout
+const DefaultTimeout = 20 * time.Second
+
+// pollInterval is how often expectations are checked
+const pollInterval = 20 * time.Millisecond
+
+//go:embed scenarios/*.json
+var builtinScenarios embed.FS
+
+// Duration is a time.Duration written as a string such as "1.5s" in scenario files
+type Duration time.Duration
+
+// UnmarshalJSON parses a duration string
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("duration must be a string such as \"2s\": %w", err)
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// MarshalJSON writes the duration as a string
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// Scenario is a scripted sequence of actions and expectations. Every step runs in the
+// mock's test guild, as its test user, in its test text and voice channels.
+type Scenario struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Steps       []Step `json:"steps"`
+}
+
+// Step is one action or expectation of a scenario
+type Step struct {
+	Action   string   `json:"action"`
+	Text     string   `json:"text,omitempty"`     // Message text. Flooded messages replace %d with their number.
+	Count    int      `json:"count,omitempty"`    // Messages to flood, or messages that must have been spoken
+	Interval Duration `json:"interval,omitempty"` // Between flooded messages
+	Duration Duration `json:"duration,omitempty"` // How long to wait
+	Timeout  Duration `json:"timeout,omitempty"`  // For expectations and command responses, DefaultTimeout when 0
+}
+
+// String describes the step for reports
+func (s Step) String() string {
+	switch s.Action {
+	case ActionMessage:
+		return fmt.Sprintf("%s %q", s.Action, s.Text)
+	case ActionFlood, ActionExpectSpoken:
+		return fmt.Sprintf("%s %d", s.Action, s.Count)
+	case ActionWait:
+		return fmt.Sprintf("%s %s", s.Action, time.Duration(s.Duration))
+	}
+	return s.Action
+}
+
+// timeout returns how long the step waits for its outcome
+func (s Step) timeout() time.Duration {
+	if s.Timeout > 0 {
+		return time.Duration(s.Timeout)
+	}
+	return DefaultTimeout
+}
+
+// Validate checks that every step has a known action and the fields it needs
+func (sc *Scenario) Validate() error {
+	if sc.Name == "" {
+		return errors.New("scenario name cannot be empty")
+	}
+	if len(sc.Steps) == 0 {
+		return fmt.Errorf("scenario %s has no steps", sc.Name)
+	}
+
+	for i, step := range sc.Steps {
+		var err error
+		switch step.Action {
+		case ActionJoin, ActionLeave, ActionDropVoice, ActionExpectIdle, ActionExpectConnected, ActionExpectDisconnected:
+		case ActionMessage:
+			if step.Text == "" {
+				err = errors.New("text cannot be empty")
+			}
+		case ActionFlood:
+			if step.Count <= 0 {
+				err = errors.New("count must be positive")
+			} else if step.Text == "" {
+				err = errors.New("text cannot be empty")
+			}
+		case ActionExpectSpoken:
+			if step.Count <= 0 {
+				err = errors.New("count must be positive")
+			}
+		case ActionWait:
+			if step.Duration <= 0 {
+				err = errors.New("duration must be positive")
+			}
+		default:
+			err = fmt.Errorf("unknown action %q", step.Action)
+		}
+		if err != nil {
+			return fmt.Errorf("scenario %s, step %d: %w", sc.Name, i+1, err)
+		}
+	}
+	return nil
+}
+
+// ParseScenario reads and validates a scenario in JSON
+func ParseScenario(r io.Reader) (*Scenario, error) {
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+
+	var scenario Scenario
+	if err := decoder.Decode(&scenario); err != nil {
+		return nil, fmt.Errorf("failed to parse scenario: %w", err)
+	}
+	if err := scenario.Validate(); err != nil {
+		return nil, err
+	}
+	return &scenario, nil
+}
+
+// LoadScenario loads a scenario file, or the built-in scenario of that name when no such
+// file exists
+func LoadScenario(nameOrPath string) (*Scenario, error) {
+	var file fs.File
+	file, err := os.Open(nameOrPath)
+	if errors.Is(err, os.ErrNotExist) {
+		file, err = builtinScenarios.Open(path.Join("scenarios", nameOrPath+".json"))
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("no scenario file or built-in scenario named %s", nameOrPath)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return ParseScenario(file)
+}
+
+// BuiltinScenarios returns the scenarios shipped with the dev stack, by name
+func BuiltinScenarios() ([]*Scenario, error) {
+	entries, err := builtinScenarios.ReadDir("scenarios")
+	if err != nil {
+		return nil, err
+	}
+
+	scenarios := make([]*Scenario, 0, len(entries))
+	for _, entry := range entries {
+		scenario, err := LoadScenario(strings.TrimSuffix(entry.Name(), ".json"))
+		if err != nil {
+			return nil, fmt.Errorf("built-in scenario %s: %w", entry.Name(), err)
+		}
+		scenarios = append(scenarios, scenario)
+	}
+	sort.Slice(scenarios, func(i, j int) bool { return scenarios[i].Name < scenarios[j].Name })
+	return scenarios, nil
+}
+
+// StepResult is the outcome of one step
+type StepResult struct {
+	Step    Step
+	Elapsed time.Duration
+	Err     error
+}
+
+// Report is the outcome of a scenario run. Steps after the first failure are not run.
+type Report struct {
+	Scenario     string
+	Steps        []StepResult
+	Elapsed      time.Duration
+	MessagesSent int // Messages posted in the text channel
+	Spoken       int // Messages the bot finished speaking
+	Synthesized  int // Synthesis requests the mock TTS received, including warm-ups
+}
+
+// Err returns the error of the failed step, or nil when every step passed
+func (r *Report) Err() error {
+	for i, result := range r.Steps {
+		if result.Err != nil {
+			return fmt.Errorf("scenario %s, step %d (%s): %w", r.Scenario, i+1, result.Step, result.Err)
+		}
+	}
+	return nil
+}
+
+// Write prints the report
+func (r *Report) Write(w io.Writer) {
+	fmt.Fprintf(w, "Scenario %s\n", r.Scenario)
+	for i, result := range r.Steps {
+		status := "ok"
+		if result.Err != nil {
+			status = "FAILED: " + result.Err.Error()
+		}
+		fmt.Fprintf(w, "  %2d. %-32s %10s  %s\n", i+1, result.Step, result.Elapsed.Round(time.Millisecond), status)
+	}
+	fmt.Fprintf(w, "  %d messages sent, %d spoken, %d syntheses in %s\n",
+		r.MessagesSent, r.Spoken, r.Synthesized, r.Elapsed.Round(time.Millisecond))
+}
+
+// Run runs a scenario against the stack and reports how each step went. It stops at the
+// first failed step, whose error Report.Err returns, or when ctx is done.
+func (s *Stack) Run(ctx context.Context, scenario *Scenario) *Report {
+	report := &Report{Scenario: scenario.Name}
+	started := time.Now()
+	s.logger.Printf("Running scenario %s", scenario.Name)
+
+	for _, step := range scenario.Steps {
+		stepStarted := time.Now()
+		err := s.runStep(ctx, step, report)
+		report.Steps = append(report.Steps, StepResult{Step: step, Elapsed: time.Since(stepStarted), Err: err})
+		if err != nil {
+			break
+		}
+	}
+
+	report.Elapsed = time.Since(started)
+	report.Spoken = s.MessagesSpoken(mockdiscord.TestGuildID)
+	report.Synthesized = len(s.TTS.Requests())
+	return report
+}
+
+// runStep performs one step
+func (s *Stack) runStep(ctx context.Context, step Step, report *Report) error {
+	guildID := mockdiscord.TestGuildID
+
+	switch step.Action {
+	case ActionJoin:
+		return s.Join(ctx, guildID, mockdiscord.TestTextChannelID, mockdiscord.TestVoiceChannelID, step.timeout())
+
+	case ActionLeave:
+		return s.Leave(ctx, guildID, mockdiscord.TestTextChannelID, step.timeout())
+
+	case ActionMessage:
+		s.Discord.SendMessage(mockdiscord.TestTextChannelID, mockdiscord.TestUserID, step.Text)
+		report.MessagesSent++
+		return nil
+
+	case ActionFlood:
+		for i := 1; i <= step.Count; i++ {
+			text := step.Text
+			if strings.Contains(text, "%d") {
+				text = fmt.Sprintf(text, i)
+			}
+			s.Discord.SendMessage(mockdiscord.TestTextChannelID, mockdiscord.TestUserID, text)
+			report.MessagesSent++
+			if i < step.Count {
+				if err := sleep(ctx, time.Duration(step.Interval)); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+
+	case ActionDropVoice:
+		if !s.Discord.DropVoice(guildID) {
+			return errors.New("the bot has no voice connection to drop")
+		}
+		return nil
+
+	case ActionWait:
+		return sleep(ctx, time.Duration(step.Duration))
+
+	case ActionExpectSpoken:
+		return eventually(ctx, step.timeout(), func() error {
+			if spoken := s.MessagesSpoken(guildID); spoken < step.Count {
+				return fmt.Errorf("%d of %d messages spoken", spoken, step.Count)
+			}
+			return nil
+		})
+
+	case ActionExpectIdle:
+		return eventually(ctx, step.timeout(), func() error {
+			if size := s.QueueSize(guildID); size > 0 {
+				return fmt.Errorf("%d messages still queued", size)
+			}
+			return nil
+		})
+
+	case ActionExpectConnected:
+		return eventually(ctx, step.timeout(), func() error {
+			if !s.Connected(guildID) {
+				return errors.New("the bot is not connected to voice")
+			}
+			if channel := s.Discord.BotVoiceChannel(guildID); channel != mockdiscord.TestVoiceChannelID {
+				return fmt.Errorf("the bot is in voice channel %q", channel)
+			}
+			return nil
+		})
+
+	case ActionExpectDisconnected:
+		return eventually(ctx, step.timeout(), func() error {
+			if s.Connected(guildID) {
+				return errors.New("the bot is still connected to voice")
+			}
+			return nil
+		})
+	}
+	return fmt.Errorf("unknown action %q", step.Action)
+}
+
+// AddGuild adds a guild with a text and a voice channel to the mock Discord and waits up
+// to timeout for the bot to see it. It returns the IDs of the channels.
+func (s *Stack) AddGuild(ctx context.Context, guildID, name string, timeout time.Duration) (textChannelID, voiceChannelID string, err error) {
+	textChannelID, voiceChannelID, err = s.Discord.AddGuild(guildID, name)
+	if err != nil {
+		return "", "", err
+	}
+
+	err = eventually(ctx, timeout, func() error {
+		if _, err := s.Bot.GetSession().State.Guild(guildID); err != nil {
+			return fmt.Errorf("the bot has not seen guild %s", guildID)
+		}
+		return nil
+	})
+	return textChannelID, voiceChannelID, err
+}
+
+// Join runs /darrot-join as the test user, pairing a text channel with a voice channel,
+// and waits up to timeout for a successful response
+func (s *Stack) Join(ctx context.Context, guildID, textChannelID, voiceChannelID string, timeout time.Duration) error {
+	id := s.Discord.SendCommand(guildID, textChannelID, mockdiscord.TestUserID,
+		"darrot-join", mockdiscord.ChannelOption("voice-channel", voiceChannelID))
+	return s.expectResponse(ctx, id, timeout)
+}
+
+// Leave runs /darrot-leave as the test user and waits up to timeout for the response
+func (s *Stack) Leave(ctx context.Context, guildID, textChannelID string, timeout time.Duration) error {
+	id := s.Discord.SendCommand(guildID, textChannelID, mockdiscord.TestUserID, "darrot-leave")
+	return s.expectResponse(ctx, id, timeout)
+}
+
+// expectResponse waits for the bot to answer a command without an error
+func (s *Stack) expectResponse(ctx context.Context, interactionID string, timeout time.Duration) error {
+	return eventually(ctx, timeout, func() error {
+		response, ok := s.Discord.InteractionResponse(interactionID)
+		if !ok {
+			return errors.New("no response to the command")
+		}
+		if content := response.Content(); strings.HasPrefix(content, "❌") {
+			return fmt.Errorf("command failed: %s", content)
+		}
+		return nil
+	})
+}
+
+// eventually polls check until it passes, timeout passes or ctx is done, returning the
+// last error of check on timeout
+func eventually(ctx context.Context, timeout time.Duration, check func() error) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		err := check()
+		if err == nil {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w after %s", err, timeout)
+		case <-ticker.C:
+		}
+	}
+}
+
+// sleep waits for d or until ctx is done
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,142 @@
+// Package devstack runs darrot against the mock Discord from tests/mock-discord and the
+// mock Google TTS from tests/mock-tts in a single process, keeping the bot's data in a
+// temporary directory, and drives it through scripted scenarios. Every run starts from
+// the same state, which makes manual QA and load tests reproducible without Discord or
+// Google Cloud credentials.
+package devstack
+
+import (
+	"fmt"
+	"log"
+	"net/http/httptest"
+	"os"
+
+	"darrot/internal/bot"
+	"darrot/internal/config"
+	"darrot/internal/tts"
+
+	"mock-discord/mockdiscord"
+	"mock-tts/mocktts"
+)
+
+// botToken is the token the bot identifies with. The mock accepts any token.
+const botToken = "devstack-bot-token"
+
+// Options configure a dev stack
+type Options struct {
+	Config  *config.Config // Bot configuration, the defaults when nil. The token and TTS endpoint are always set by the stack.
+	DataDir string         // Where the bot keeps its data. A temporary directory removed on Close when empty.
+	Logger  *log.Logger    // Progress of scenarios, standard output when nil
+}
+
+// Stack is a running bot connected to the mock Discord and mock TTS servers
+type Stack struct {
+	Discord *mockdiscord.Fixture
+	TTS     *mocktts.Server
+	Bot     *bot.Bot
+
+	ttsServer     *httptest.Server
+	dataDir       string
+	removeDataDir bool
+	logger        *log.Logger
+}
+
+// Start starts the mock servers and a bot connected to them. The stack must be closed.
+func Start(options Options) (*Stack, error) {
+	stack := &Stack{
+		TTS:    mocktts.NewServer(),
+		logger: options.Logger,
+	}
+	if stack.logger == nil {
+		stack.logger = log.New(os.Stdout, "[DEVSTACK] ", log.LstdFlags)
+	}
+
+	fixture, err := mockdiscord.NewFixture()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start mock Discord: %w", err)
+	}
+	stack.Discord = fixture
+	stack.ttsServer = httptest.NewServer(stack.TTS.Handler())
+
+	stack.dataDir = options.DataDir
+	if stack.dataDir == "" {
+		stack.dataDir, err = os.MkdirTemp("", "darrot-devstack-")
+		if err != nil {
+			stack.Close()
+			return nil, fmt.Errorf("failed to create data directory: %w", err)
+		}
+		stack.removeDataDir = true
+	}
+
+	storage, err := tts.NewStorageService(stack.dataDir)
+	if err != nil {
+		stack.Close()
+		return nil, fmt.Errorf("failed to initialize storage: %w", err)
+	}
+
+	cfg := config.GetDefaultConfig()
+	if options.Config != nil {
+		copied := *options.Config
+		cfg = &copied
+	}
+	cfg.DiscordToken = botToken
+	cfg.TTS.GoogleCloudEndpoint = stack.ttsServer.URL
+
+	darrot, err := bot.NewWithServices(cfg, &tts.Services{Storage: storage})
+	if err != nil {
+		stack.Close()
+		return nil, fmt.Errorf("failed to create bot: %w", err)
+	}
+	session := darrot.GetSession()
+	session.Client = fixture.HTTPClient()
+	session.Dialer = fixture.Dialer()
+
+	if err := darrot.Start(); err != nil {
+		stack.Close()
+		return nil, fmt.Errorf("failed to start bot: %w", err)
+	}
+	stack.Bot = darrot
+
+	stack.logger.Printf("Dev stack running with mock Discord at %s, mock TTS at %s and data in %s",
+		fixture.URL(), stack.ttsServer.URL, stack.dataDir)
+	return stack, nil
+}
+
+// Close stops the bot and the mock servers and removes a temporary data directory
+func (s *Stack) Close() {
+	if s.Bot != nil {
+		if err := s.Bot.Stop(); err != nil {
+			s.logger.Printf("Warning: failed to stop bot: %v", err)
+		}
+	}
+	if s.ttsServer != nil {
+		s.ttsServer.Close()
+	}
+	if s.Discord != nil {
+		s.Discord.Close()
+	}
+	if s.removeDataDir {
+		os.RemoveAll(s.dataDir)
+	}
+}
+
+// DataDir returns the directory the bot keeps its data in
+func (s *Stack) DataDir() string {
+	return s.dataDir
+}
+
+// MessagesSpoken returns how many messages the bot has finished speaking in a guild
+func (s *Stack) MessagesSpoken(guildID string) int {
+	metrics := s.Bot.GetTTSSystem().GetMetrics()
+	return int(metrics.Value(tts.MetricGuildMessagesProcessed, tts.Labels{"guild": guildID}))
+}
+
+// QueueSize returns how many messages wait in a guild's queue
+func (s *Stack) QueueSize(guildID string) int {
+	return s.Bot.GetTTSSystem().GetServices().Queue.Size(guildID)
+}
+
+// Connected reports whether the bot is in a voice channel of a guild
+func (s *Stack) Connected(guildID string) bool {
+	return s.Bot.GetTTSSystem().GetVoiceManager().IsConnected(guildID)
+}
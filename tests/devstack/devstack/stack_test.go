@@ -0,0 +1,78 @@
+package devstack
+
+import (
+	"context"
+	"io"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseScenario(t *testing.T) {
+	scenario, err := ParseScenario(strings.NewReader(`{
+		"name": "custom",
+		"steps": [
+			{"action": "join"},
+			{"action": "flood", "count": 3, "text": "message %d", "interval": "50ms"},
+			{"action": "expect_spoken", "count": 3, "timeout": "1m"}
+		]
+	}`))
+	require.NoError(t, err)
+	assert.Len(t, scenario.Steps, 3)
+	assert.Equal(t, "flood 3", scenario.Steps[1].String())
+	assert.Equal(t, DefaultTimeout, scenario.Steps[0].timeout())
+	assert.Equal(t, "1m0s", scenario.Steps[2].timeout().String())
+
+	tests := map[string]string{
+		"unknown action":   `{"name": "bad", "steps": [{"action": "dance"}]}`,
+		"empty flood":      `{"name": "bad", "steps": [{"action": "flood", "text": "x"}]}`,
+		"no steps":         `{"name": "bad", "steps": []}`,
+		"unknown field":    `{"name": "bad", "steps": [{"action": "join", "user": "alice"}]}`,
+		"invalid duration": `{"name": "bad", "steps": [{"action": "wait", "duration": 5}]}`,
+	}
+	for name, input := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseScenario(strings.NewReader(input))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestBuiltinScenarios(t *testing.T) {
+	scenarios, err := BuiltinScenarios()
+	require.NoError(t, err)
+
+	names := make([]string, len(scenarios))
+	for i, scenario := range scenarios {
+		names[i] = scenario.Name
+	}
+	assert.Equal(t, []string{"drop-voice", "flood", "smoke"}, names)
+
+	_, err = LoadScenario("missing")
+	assert.Error(t, err)
+}
+
+func TestStack_RunBuiltinScenarios(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping dev stack scenarios in short mode")
+	}
+
+	scenarios, err := BuiltinScenarios()
+	require.NoError(t, err)
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			stack, err := Start(Options{DataDir: t.TempDir(), Logger: log.New(io.Discard, "", 0)})
+			require.NoError(t, err)
+			defer stack.Close()
+
+			report := stack.Run(context.Background(), scenario)
+			require.NoError(t, report.Err())
+			assert.Len(t, report.Steps, len(scenario.Steps))
+			assert.Positive(t, report.Spoken)
+		})
+	}
+}
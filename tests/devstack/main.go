@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"darrot/tests/devstack/devstack"
+)
+
+func main() {
+	scenarioFlag := flag.String("scenario", "smoke", "Comma-separated built-in scenario names or scenario files, or \"all\"")
+	list := flag.Bool("list", false, "List the built-in scenarios and exit")
+	dataDir := flag.String("data-dir", "", "Keep bot data in this directory instead of a temporary one")
+	flag.Parse()
+
+	if *list {
+		scenarios, err := devstack.BuiltinScenarios()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		for _, scenario := range scenarios {
+			fmt.Printf("%-12s %s\n", scenario.Name, scenario.Description)
+		}
+		return
+	}
+
+	scenarios, err := loadScenarios(*scenarioFlag)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	// Interrupting stops the running scenario and tears the stack down
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Every scenario gets a fresh stack, so they do not depend on each other
+	var reports []*devstack.Report
+	for _, scenario := range scenarios {
+		stack, err := devstack.Start(devstack.Options{DataDir: *dataDir})
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		reports = append(reports, stack.Run(ctx, scenario))
+		stack.Close()
+
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	failed := false
+	fmt.Println()
+	for _, report := range reports {
+		report.Write(os.Stdout)
+		if report.Err() != nil {
+			failed = true
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// loadScenarios loads the scenarios named by the -scenario flag
+func loadScenarios(names string) ([]*devstack.Scenario, error) {
+	if names == "all" {
+		return devstack.BuiltinScenarios()
+	}
+
+	var scenarios []*devstack.Scenario
+	for _, name := range strings.Split(names, ",") {
+		scenario, err := devstack.LoadScenario(strings.TrimSpace(name))
+		if err != nil {
+			return nil, err
+		}
+		scenarios = append(scenarios, scenario)
+	}
+	return scenarios, nil
+}
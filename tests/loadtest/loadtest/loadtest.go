@@ -0,0 +1,364 @@
+// Package loadtest drives a dev stack with messages from many guilds at a steady rate and
+// measures how the bot keeps up: how long messages wait before they are spoken, how many
+// are synthesized per minute and how much the heap grows. The results are checked against
+// thresholds, so a long run doubles as a soak test in nightly builds.
+package loadtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"darrot/internal/events"
+	"darrot/tests/devstack/devstack"
+
+	"mock-discord/mockdiscord"
+)
+
+// setupTimeout bounds adding a guild and joining its voice channel
+const setupTimeout = 30 * time.Second
+
+// sampleInterval is how often the heap and the queues are sampled
+const sampleInterval = time.Second
+
+// Options describe the load of a run
+type Options struct {
+	Guilds            int           // Guilds the bot reads in, each with its own voice connection
+	MessagesPerMinute int           // Messages posted per guild
+	Duration          time.Duration // How long messages are posted
+	Drain             time.Duration // How long the queues may take to empty afterwards
+	Thresholds        Thresholds
+}
+
+// Validate checks that the options describe a load
+func (o Options) Validate() error {
+	switch {
+	case o.Guilds < 1:
+		return errors.New("guilds must be at least 1")
+	case o.MessagesPerMinute < 1:
+		return errors.New("messages per minute must be at least 1")
+	case o.Duration <= 0:
+		return errors.New("duration must be positive")
+	case o.Drain < 0:
+		return errors.New("drain cannot be negative")
+	case o.Thresholds.MaxUnspoken < 0 || o.Thresholds.MaxUnspoken > 1:
+		return errors.New("the share of unspoken messages must be between 0 and 1")
+	}
+	return nil
+}
+
+// Thresholds a run must stay within to pass. Zero values are not checked, except for
+// MaxUnspoken, where 1 is not checked.
+type Thresholds struct {
+	MaxLatencyP95     time.Duration // 95th percentile of the time from posting a message to speaking it
+	MaxMemoryGrowthMB float64       // Heap growth from before the load to after the queues drained
+	MinThroughput     float64       // Syntheses per minute
+	MaxUnspoken       float64       // Share of posted messages that were never spoken, such as dropped from full queues
+}
+
+// Result is what a run measured
+type Result struct {
+	Options        Options       `json:"-"`
+	MessagesSent   int           `json:"messages_sent"`
+	MessagesSpoken int           `json:"messages_spoken"`
+	Unspoken       float64       `json:"unspoken"` // Share of sent messages
+	Drained        bool          `json:"drained"`  // Whether the queues emptied within the drain time
+	Elapsed        time.Duration `json:"elapsed_ns"`
+
+	LatencyP50 time.Duration `json:"latency_p50_ns"`
+	LatencyP95 time.Duration `json:"latency_p95_ns"`
+	LatencyMax time.Duration `json:"latency_max_ns"`
+	MaxBacklog int           `json:"max_backlog"` // Most messages queued in all guilds at once
+
+	Syntheses  int     `json:"syntheses"`
+	Throughput float64 `json:"throughput_per_minute"`
+
+	HeapStartMB float64 `json:"heap_start_mb"`
+	HeapPeakMB  float64 `json:"heap_peak_mb"`
+	HeapEndMB   float64 `json:"heap_end_mb"`
+
+	Failures []string `json:"failures"` // Thresholds the run did not stay within
+}
+
+// Passed reports whether the run stayed within every threshold
+func (r *Result) Passed() bool {
+	return len(r.Failures) == 0
+}
+
+// MemoryGrowthMB returns how much the heap grew over the run
+func (r *Result) MemoryGrowthMB() float64 {
+	return r.HeapEndMB - r.HeapStartMB
+}
+
+// Write prints the result
+func (r *Result) Write(w io.Writer) {
+	fmt.Fprintf(w, "Load test: %d guilds x %d messages/minute for %s\n",
+		r.Options.Guilds, r.Options.MessagesPerMinute, r.Options.Duration)
+	fmt.Fprintf(w, "  Messages:   %d sent, %d spoken (%.1f%% unspoken), drained: %t\n",
+		r.MessagesSent, r.MessagesSpoken, r.Unspoken*100, r.Drained)
+	fmt.Fprintf(w, "  Latency:    p50 %s, p95 %s, max %s, max backlog %d\n",
+		r.LatencyP50.Round(time.Millisecond), r.LatencyP95.Round(time.Millisecond), r.LatencyMax.Round(time.Millisecond), r.MaxBacklog)
+	fmt.Fprintf(w, "  Throughput: %d syntheses, %.1f per minute\n", r.Syntheses, r.Throughput)
+	fmt.Fprintf(w, "  Heap:       %.1f MB at start, %.1f MB peak, %.1f MB at end (%+.1f MB)\n",
+		r.HeapStartMB, r.HeapPeakMB, r.HeapEndMB, r.MemoryGrowthMB())
+
+	if r.Passed() {
+		fmt.Fprintln(w, "  PASSED")
+		return
+	}
+	for _, failure := range r.Failures {
+		fmt.Fprintf(w, "  FAILED: %s\n", failure)
+	}
+}
+
+// check records the thresholds the result is not within
+func (r *Result) check(thresholds Thresholds) {
+	if thresholds.MaxLatencyP95 > 0 && r.LatencyP95 > thresholds.MaxLatencyP95 {
+		r.Failures = append(r.Failures, fmt.Sprintf("p95 latency %s is above %s",
+			r.LatencyP95.Round(time.Millisecond), thresholds.MaxLatencyP95))
+	}
+	if thresholds.MaxMemoryGrowthMB > 0 && r.MemoryGrowthMB() > thresholds.MaxMemoryGrowthMB {
+		r.Failures = append(r.Failures, fmt.Sprintf("heap grew by %.1f MB, more than %.1f MB",
+			r.MemoryGrowthMB(), thresholds.MaxMemoryGrowthMB))
+	}
+	if thresholds.MinThroughput > 0 && r.Throughput < thresholds.MinThroughput {
+		r.Failures = append(r.Failures, fmt.Sprintf("%.1f syntheses per minute, fewer than %.1f",
+			r.Throughput, thresholds.MinThroughput))
+	}
+	if thresholds.MaxUnspoken < 1 && r.Unspoken > thresholds.MaxUnspoken {
+		r.Failures = append(r.Failures, fmt.Sprintf("%.1f%% of messages were not spoken, more than %.1f%%",
+			r.Unspoken*100, thresholds.MaxUnspoken*100))
+	}
+	if !r.Drained {
+		r.Failures = append(r.Failures, fmt.Sprintf("the queues did not drain within %s", r.Options.Drain))
+	}
+}
+
+// guild is a guild the load is posted in
+type guild struct {
+	id            string
+	textChannelID string
+}
+
+// latencies records when messages were queued and how long they waited to be spoken
+type latencies struct {
+	mu       sync.Mutex
+	queued   map[string]time.Time // By message ID
+	waited   []time.Duration
+	started  int
+	finished int
+}
+
+// Run adds the guilds to the stack, joins their voice channels and posts messages in
+// them at the configured rate. It returns an error when the load could not be set up, and
+// the result measured so far with ctx's error when ctx is done.
+func Run(ctx context.Context, stack *devstack.Stack, options Options) (*Result, error) {
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+
+	guilds := make([]guild, options.Guilds)
+	for i := range guilds {
+		id := fmt.Sprintf("load-guild-%d", i+1)
+		textChannelID, voiceChannelID, err := stack.AddGuild(ctx, id, fmt.Sprintf("Load Guild %d", i+1), setupTimeout)
+		if err != nil {
+			return nil, err
+		}
+		if err := stack.Join(ctx, id, textChannelID, voiceChannelID, setupTimeout); err != nil {
+			return nil, fmt.Errorf("failed to join voice in %s: %w", id, err)
+		}
+		guilds[i] = guild{id: id, textChannelID: textChannelID}
+	}
+
+	// Announcements and the parts after the first of split messages are not posted messages
+	recorded := &latencies{queued: make(map[string]time.Time)}
+	bus := stack.Bot.GetTTSSystem().GetServices().Events
+	unsubscribeQueued := events.Subscribe(bus, func(e events.MessageEnqueued) {
+		if e.Announcement {
+			return
+		}
+		recorded.mu.Lock()
+		defer recorded.mu.Unlock()
+		recorded.queued[e.MessageID] = e.QueuedAt
+	})
+	defer unsubscribeQueued()
+	unsubscribeStarted := events.Subscribe(bus, func(e events.UtteranceStarted) {
+		recorded.mu.Lock()
+		defer recorded.mu.Unlock()
+		recorded.started++
+		if queuedAt, ok := recorded.queued[e.MessageID]; ok && e.Part == 0 && !e.Announcement {
+			recorded.waited = append(recorded.waited, e.StartedAt.Sub(queuedAt))
+			delete(recorded.queued, e.MessageID)
+		}
+	})
+	defer unsubscribeStarted()
+	unsubscribeFinished := events.Subscribe(bus, func(e events.UtteranceFinished) {
+		recorded.mu.Lock()
+		defer recorded.mu.Unlock()
+		recorded.finished++
+	})
+	defer unsubscribeFinished()
+
+	result := &Result{Options: options, HeapStartMB: heapMB()}
+	result.HeapPeakMB = result.HeapStartMB
+	synthesesBefore := len(stack.TTS.Requests())
+	started := time.Now()
+
+	// Sample the heap and the backlog while the load runs and the queues drain
+	sampleCtx, stopSampling := context.WithCancel(ctx)
+	var sampling sync.WaitGroup
+	sampling.Add(1)
+	go func() {
+		defer sampling.Done()
+		sample(sampleCtx, stack, guilds, result)
+	}()
+
+	var sent atomic.Int64
+	post(ctx, stack, guilds, options, &sent)
+
+	result.Drained = drain(ctx, stack, guilds, recorded, options.Drain)
+	stopSampling()
+	sampling.Wait()
+
+	result.Elapsed = time.Since(started)
+	result.HeapEndMB = heapMB()
+	result.MessagesSent = int(sent.Load())
+	result.Syntheses = len(stack.TTS.Requests()) - synthesesBefore
+	if minutes := result.Elapsed.Minutes(); minutes > 0 {
+		result.Throughput = float64(result.Syntheses) / minutes
+	}
+
+	recorded.mu.Lock()
+	result.MessagesSpoken = len(recorded.waited)
+	result.LatencyP50 = percentile(recorded.waited, 50)
+	result.LatencyP95 = percentile(recorded.waited, 95)
+	result.LatencyMax = percentile(recorded.waited, 100)
+	recorded.mu.Unlock()
+	if result.MessagesSent > 0 {
+		result.Unspoken = float64(result.MessagesSent-result.MessagesSpoken) / float64(result.MessagesSent)
+		if result.Unspoken < 0 {
+			result.Unspoken = 0
+		}
+	}
+
+	result.check(options.Thresholds)
+	return result, ctx.Err()
+}
+
+// post posts messages in every guild at the configured rate until the duration passes or
+// ctx is done. The guilds start staggered, so their messages do not arrive in bursts.
+func post(ctx context.Context, stack *devstack.Stack, guilds []guild, options Options, sent *atomic.Int64) {
+	ctx, cancel := context.WithTimeout(ctx, options.Duration)
+	defer cancel()
+
+	interval := time.Minute / time.Duration(options.MessagesPerMinute)
+	var wg sync.WaitGroup
+	for i, g := range guilds {
+		wg.Add(1)
+		go func(offset time.Duration, g guild) {
+			defer wg.Done()
+
+			timer := time.NewTimer(offset)
+			defer timer.Stop()
+			for n := 1; ; n++ {
+				select {
+				case <-ctx.Done():
+					return
+				case <-timer.C:
+				}
+				// Unique texts, so every message is synthesized rather than read from the audio cache
+				stack.Discord.SendMessage(g.textChannelID, mockdiscord.TestUserID, fmt.Sprintf("load test message %d in %s", n, g.id))
+				sent.Add(1)
+				timer.Reset(interval)
+			}
+		}(interval*time.Duration(i)/time.Duration(len(guilds)), g)
+	}
+	wg.Wait()
+}
+
+// drain waits up to timeout for the queues to empty and the last messages to finish
+// playing, and reports whether they did
+func drain(ctx context.Context, stack *devstack.Stack, guilds []guild, recorded *latencies, timeout time.Duration) bool {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		recorded.mu.Lock()
+		playing := recorded.started - recorded.finished
+		recorded.mu.Unlock()
+		if backlog(stack, guilds) == 0 && playing <= 0 {
+			return true
+		}
+
+		select {
+		case <-ctx.Done():
+			return false
+		case <-ticker.C:
+		}
+	}
+}
+
+// sample records the peak heap and backlog until ctx is done
+func sample(ctx context.Context, stack *devstack.Stack, guilds []guild, result *Result) {
+	ticker := time.NewTicker(sampleInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		var stats runtime.MemStats
+		runtime.ReadMemStats(&stats)
+		if heap := float64(stats.HeapAlloc) / (1 << 20); heap > result.HeapPeakMB {
+			result.HeapPeakMB = heap
+		}
+		if queued := backlog(stack, guilds); queued > result.MaxBacklog {
+			result.MaxBacklog = queued
+		}
+	}
+}
+
+// backlog returns how many messages are queued in all guilds
+func backlog(stack *devstack.Stack, guilds []guild) int {
+	total := 0
+	for _, g := range guilds {
+		total += stack.QueueSize(g.id)
+	}
+	return total
+}
+
+// heapMB returns the live heap after a garbage collection, in megabytes
+func heapMB() float64 {
+	runtime.GC()
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return float64(stats.HeapAlloc) / (1 << 20)
+}
+
+// percentile returns the pth percentile of durations, by the nearest-rank method
+func percentile(durations []time.Duration, p int) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+
+	sorted := append([]time.Duration(nil), durations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
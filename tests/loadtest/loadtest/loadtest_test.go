@@ -0,0 +1,88 @@
+package loadtest
+
+import (
+	"context"
+	"io"
+	"log"
+	"testing"
+	"time"
+
+	"darrot/internal/tts"
+	"darrot/tests/devstack/devstack"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPercentile(t *testing.T) {
+	durations := make([]time.Duration, 20)
+	for i := range durations {
+		durations[i] = time.Duration(20-i) * time.Second
+	}
+
+	assert.Equal(t, 10*time.Second, percentile(durations, 50))
+	assert.Equal(t, 19*time.Second, percentile(durations, 95))
+	assert.Equal(t, 20*time.Second, percentile(durations, 100))
+	assert.Equal(t, time.Duration(0), percentile(nil, 95))
+}
+
+func TestResult_Thresholds(t *testing.T) {
+	result := &Result{
+		Options:     Options{Drain: time.Minute},
+		LatencyP95:  40 * time.Second,
+		HeapStartMB: 10,
+		HeapEndMB:   90,
+		Throughput:  12,
+		Unspoken:    0.2,
+		Drained:     true,
+	}
+
+	result.check(Thresholds{MaxUnspoken: 1})
+	assert.True(t, result.Passed(), "zero thresholds are not checked")
+
+	result.check(Thresholds{MaxLatencyP95: 30 * time.Second, MaxMemoryGrowthMB: 64, MinThroughput: 20, MaxUnspoken: 0.05})
+	assert.Len(t, result.Failures, 4)
+	assert.False(t, result.Passed())
+}
+
+func TestOptions_Validate(t *testing.T) {
+	valid := Options{Guilds: 2, MessagesPerMinute: 10, Duration: time.Minute}
+	assert.NoError(t, valid.Validate())
+
+	for name, options := range map[string]Options{
+		"no guilds":        {MessagesPerMinute: 10, Duration: time.Minute},
+		"no messages":      {Guilds: 2, Duration: time.Minute},
+		"no duration":      {Guilds: 2, MessagesPerMinute: 10},
+		"unspoken above 1": {Guilds: 2, MessagesPerMinute: 10, Duration: time.Minute, Thresholds: Thresholds{MaxUnspoken: 2}},
+		"negative drain":   {Guilds: 2, MessagesPerMinute: 10, Duration: time.Minute, Drain: -time.Second},
+	} {
+		assert.Error(t, options.Validate(), name)
+	}
+}
+
+func TestRun(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping load test in short mode")
+	}
+	if !tts.OpusEncodingAvailable() {
+		t.Skip("built without libopus and ffmpeg is not installed")
+	}
+
+	stack, err := devstack.Start(devstack.Options{DataDir: t.TempDir(), Logger: log.New(io.Discard, "", 0)})
+	require.NoError(t, err)
+	defer stack.Close()
+
+	result, err := Run(context.Background(), stack, Options{
+		Guilds:            2,
+		MessagesPerMinute: 30,
+		Duration:          3500 * time.Millisecond,
+		Drain:             time.Minute,
+		Thresholds:        Thresholds{MaxLatencyP95: 30 * time.Second, MaxUnspoken: 0},
+	})
+	require.NoError(t, err)
+	assert.True(t, result.Passed(), "failures: %v", result.Failures)
+	assert.Equal(t, 4, result.MessagesSent)
+	assert.Equal(t, result.MessagesSent, result.MessagesSpoken)
+	assert.Equal(t, result.MessagesSent, result.Syntheses)
+	assert.Positive(t, result.LatencyP95)
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"darrot/internal/config"
+	"darrot/tests/devstack/devstack"
+	"darrot/tests/loadtest/loadtest"
+)
+
+func main() {
+	var options loadtest.Options
+	flag.IntVar(&options.Guilds, "guilds", 4, "Guilds the bot reads in, each with its own voice connection")
+	flag.IntVar(&options.MessagesPerMinute, "rate", 6, "Messages posted per guild per minute")
+	flag.DurationVar(&options.Duration, "duration", time.Minute, "How long messages are posted")
+	flag.DurationVar(&options.Drain, "drain", 2*time.Minute, "How long the queues may take to empty afterwards")
+	flag.DurationVar(&options.Thresholds.MaxLatencyP95, "max-latency", 30*time.Second, "Fail when the 95th percentile of queue latency is above this, 0 to not check")
+	flag.Float64Var(&options.Thresholds.MaxMemoryGrowthMB, "max-memory-growth", 64, "Fail when the heap grows by more megabytes, 0 to not check")
+	flag.Float64Var(&options.Thresholds.MinThroughput, "min-throughput", 0, "Fail with fewer syntheses per minute, 0 to not check")
+	flag.Float64Var(&options.Thresholds.MaxUnspoken, "max-unspoken", 0.05, "Fail when a larger share of messages is never spoken, 1 to not check")
+	workers := flag.Int("workers", 0, "Guild messages synthesized and played at the same time, the bot's default when 0")
+	maxQueueSize := flag.Int("max-queue-size", 0, "Messages queued per guild, the bot's default when 0")
+	jsonOutput := flag.Bool("json", false, "Print the result as JSON")
+	flag.Parse()
+
+	if err := options.Validate(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	cfg := config.GetDefaultConfig()
+	if *workers > 0 {
+		cfg.TTS.Workers = *workers
+	}
+	if *maxQueueSize > 0 {
+		cfg.TTS.MaxQueueSize = *maxQueueSize
+	}
+
+	// Interrupting ends the run early and reports what was measured so far
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	stack, err := devstack.Start(devstack.Options{Config: cfg})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	result, err := loadtest.Run(ctx, stack, options)
+	stack.Close()
+	if result == nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	if *jsonOutput {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(result)
+	} else {
+		fmt.Println()
+		result.Write(os.Stdout)
+	}
+
+	if err != nil || !result.Passed() {
+		os.Exit(1)
+	}
+}
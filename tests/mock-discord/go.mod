@@ -1,10 +1,11 @@
 module mock-discord
 
-go 1.21
+go 1.23.0
 
 require (
 	github.com/gorilla/mux v1.8.1
-	github.com/gorilla/websocket v1.5.1
+	github.com/gorilla/websocket v1.4.2
+	golang.org/x/crypto v0.41.0
 )
 
-require golang.org/x/net v0.17.0 // indirect
+require golang.org/x/sys v0.35.0 // indirect
@@ -2,7 +2,6 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"log"
 	"net/http"
 	"os"
@@ -10,37 +9,23 @@ import (
 	"syscall"
 	"time"
 
-	"github.com/gorilla/mux"
+	"mock-discord/mockdiscord"
 )
 
 func main() {
 	// Create mock Discord API server
-	server := NewMockDiscordServer()
-
-	// Create Gateway server
-	gateway := NewGatewayServer()
+	server := mockdiscord.NewMockDiscordServer()
 
 	// Create Voice server
-	voiceServer := NewVoiceServer()
-
-	// Setup HTTP router
-	router := mux.NewRouter()
-	server.SetupRoutes(router)
+	voiceServer := mockdiscord.NewVoiceServer()
 
-	// Add Gateway WebSocket endpoint
-	router.HandleFunc("/gateway", gateway.HandleWebSocket)
-
-	// Add voice server endpoints
-	router.HandleFunc("/voice/connections", func(w http.ResponseWriter, r *http.Request) {
-		connections := voiceServer.GetActiveConnections()
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(connections)
-	}).Methods("GET")
+	// Create Gateway server
+	gateway := mockdiscord.NewGatewayServer(server, voiceServer)
 
 	// Configure HTTP server
 	httpServer := &http.Server{
 		Addr:         ":8080",
-		Handler:      router,
+		Handler:      mockdiscord.NewRouter(server, gateway, voiceServer),
 		ReadTimeout:  15 * time.Second,
 		WriteTimeout: 15 * time.Second,
 	}
@@ -49,10 +34,8 @@ func main() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	gateway.Start(ctx)
-
-	// Start voice server on port 8081
-	if err := voiceServer.Start(ctx, "8081"); err != nil {
+	// Start voice server on UDP port 8081
+	if err := voiceServer.Start(ctx, ":8081"); err != nil {
 		log.Fatalf("Failed to start voice server: %v", err)
 	}
 	defer voiceServer.Stop()
@@ -61,7 +44,7 @@ func main() {
 	go func() {
 		log.Println("Mock Discord API server starting on :8080")
 		log.Println("Gateway WebSocket available at ws://localhost:8080/gateway")
-		log.Println("Voice server available on :8081")
+		log.Println("Voice WebSocket available at ws://localhost:8080/voice, audio on UDP :8081")
 		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			log.Fatalf("Failed to start server: %v", err)
 		}
@@ -74,7 +57,8 @@ func main() {
 
 	log.Println("Shutting down server...")
 
-	// Cancel Gateway context
+	// Disconnect gateway clients
+	gateway.Close()
 	cancel()
 
 	// Graceful shutdown
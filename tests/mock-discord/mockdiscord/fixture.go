@@ -0,0 +1,166 @@
+// Package mockdiscord implements an in-process mock of the Discord REST API, gateway and
+// voice servers. It backs the standalone mock-discord server and can be embedded in Go
+// tests as a Fixture that a discordgo session connects to instead of Discord.
+package mockdiscord
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+)
+
+// NewRouter routes the REST API, gateway websocket and voice websocket of the mock
+func NewRouter(api *MockDiscordServer, gateway *GatewayServer, voice *VoiceServer) *mux.Router {
+	router := mux.NewRouter()
+	api.SetupRoutes(router)
+
+	// discordgo appends a trailing slash to the gateway URL
+	router.HandleFunc("/gateway", gateway.HandleWebSocket)
+	router.HandleFunc("/gateway/", gateway.HandleWebSocket)
+
+	// Add voice server endpoints
+	router.HandleFunc("/voice", voice.HandleWebSocket)
+	router.HandleFunc("/voice/connections", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, voice.GetActiveConnections())
+	}).Methods("GET")
+
+	return router
+}
+
+// Fixture runs the mock API, gateway and voice servers on loopback for a test. The
+// HTTP side is served over TLS because discordgo always dials voice endpoints with wss.
+type Fixture struct {
+	API     *MockDiscordServer
+	Gateway *GatewayServer
+	Voice   *VoiceServer
+
+	server *httptest.Server
+	cancel context.CancelFunc
+}
+
+// NewFixture starts a fixture with the default test guild, channels and users
+func NewFixture() (*Fixture, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	api := NewMockDiscordServer()
+	voice := NewVoiceServer()
+	if err := voice.Start(ctx, "127.0.0.1:0"); err != nil {
+		cancel()
+		return nil, err
+	}
+	gateway := NewGatewayServer(api, voice)
+
+	return &Fixture{
+		API:     api,
+		Gateway: gateway,
+		Voice:   voice,
+		server:  httptest.NewTLSServer(NewRouter(api, gateway, voice)),
+		cancel:  cancel,
+	}, nil
+}
+
+// Close disconnects all clients and stops the servers
+func (f *Fixture) Close() {
+	f.cancel()
+	f.Gateway.Close()
+	f.Voice.Stop()
+	f.server.Close()
+}
+
+// URL returns the base URL of the fixture
+func (f *Fixture) URL() string {
+	return f.server.URL
+}
+
+// HTTPClient returns a client that sends every request to the fixture regardless of
+// its host, so a discordgo session's hardcoded API endpoints reach the mock
+func (f *Fixture) HTTPClient() *http.Client {
+	target, _ := url.Parse(f.server.URL)
+	return &http.Client{
+		Transport: &redirectTransport{host: target.Host, next: f.server.Client().Transport},
+		Timeout:   10 * time.Second,
+	}
+}
+
+// Dialer returns a websocket dialer that trusts the fixture's certificate
+func (f *Fixture) Dialer() *websocket.Dialer {
+	roots := x509.NewCertPool()
+	roots.AddCert(f.server.Certificate())
+
+	return &websocket.Dialer{
+		TLSClientConfig:  &tls.Config{RootCAs: roots},
+		HandshakeTimeout: 10 * time.Second,
+	}
+}
+
+// SendCommand dispatches a slash command and returns its interaction ID
+func (f *Fixture) SendCommand(guildID, channelID, userID, name string, options ...CommandOption) string {
+	return f.Gateway.SimulateInteractionCreate(CommandInteraction{
+		GuildID:   guildID,
+		ChannelID: channelID,
+		UserID:    userID,
+		Name:      name,
+		Options:   options,
+	})
+}
+
+// SendMessage dispatches a message posted by a user and returns its ID
+func (f *Fixture) SendMessage(channelID, userID, content string) string {
+	return f.Gateway.SimulateMessageCreate(channelID, content, userID)
+}
+
+// AddGuild adds a guild like the test guild, with a text and a voice channel named
+// after it, and sends it to the connected bot. It returns the IDs of the channels.
+func (f *Fixture) AddGuild(guildID, name string) (textChannelID, voiceChannelID string, err error) {
+	textChannelID, voiceChannelID = guildID+"-text", guildID+"-voice"
+	if err := f.API.AddGuild(guildID, name, textChannelID, voiceChannelID); err != nil {
+		return "", "", err
+	}
+	if err := f.Gateway.SimulateGuildCreate(guildID); err != nil {
+		return "", "", err
+	}
+	return textChannelID, voiceChannelID, nil
+}
+
+// InteractionResponse returns the response the bot posted for an interaction
+func (f *Fixture) InteractionResponse(interactionID string) (InteractionResponse, bool) {
+	return f.API.InteractionResponse(interactionID)
+}
+
+// BotVoiceChannel returns the voice channel the bot is in for a guild, or ""
+func (f *Fixture) BotVoiceChannel(guildID string) string {
+	state, _ := f.Gateway.VoiceState(guildID, BotUserID)
+	return state.ChannelID
+}
+
+// DropVoice drops the bot's voice connection in a guild and reports whether it had one
+func (f *Fixture) DropVoice(guildID string) bool {
+	return f.Voice.DropConnection(guildID)
+}
+
+// CapturedFrames returns the decrypted Opus frames the bot sent to a voice channel
+func (f *Fixture) CapturedFrames(channelID string) [][]byte {
+	return f.Voice.CapturedFrames(channelID)
+}
+
+// redirectTransport rewrites request hosts to the fixture
+type redirectTransport struct {
+	host string
+	next http.RoundTripper
+}
+
+// RoundTrip sends the request to the fixture
+func (t *redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = "https"
+	req.URL.Host = t.host
+	req.Host = t.host
+	return t.next.RoundTrip(req)
+}
@@ -0,0 +1,352 @@
+package mockdiscord
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"golang.org/x/crypto/nacl/secretbox"
+)
+
+func newTestFixture(t *testing.T) *Fixture {
+	t.Helper()
+
+	fixture, err := NewFixture()
+	if err != nil {
+		t.Fatalf("Failed to start fixture: %v", err)
+	}
+	t.Cleanup(fixture.Close)
+	return fixture
+}
+
+// readEvent reads gateway events until one with the given opcode and type arrives
+func readEvent(t *testing.T, conn *websocket.Conn, op int, eventType string) GatewayEvent {
+	t.Helper()
+
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for {
+		var event GatewayEvent
+		if err := conn.ReadJSON(&event); err != nil {
+			t.Fatalf("Failed waiting for op %d %s: %v", op, eventType, err)
+		}
+		if event.Op == op && (eventType == "" || (event.T != nil && *event.T == eventType)) {
+			return event
+		}
+	}
+}
+
+func writeEvent(t *testing.T, conn *websocket.Conn, op int, data interface{}) {
+	t.Helper()
+
+	raw, _ := json.Marshal(data)
+	if err := conn.WriteJSON(GatewayEvent{Op: op, D: raw}); err != nil {
+		t.Fatalf("Failed to send op %d: %v", op, err)
+	}
+}
+
+// connectGateway discovers the gateway through the REST API and identifies
+func connectGateway(t *testing.T, fixture *Fixture) *websocket.Conn {
+	t.Helper()
+
+	request, _ := http.NewRequest("GET", "https://discord.com/api/v9/gateway", nil)
+	request.Header.Set("Authorization", "Bot test-token")
+	response, err := fixture.HTTPClient().Do(request)
+	if err != nil {
+		t.Fatalf("Gateway discovery failed: %v", err)
+	}
+	defer response.Body.Close()
+
+	var gateway struct {
+		URL string `json:"url"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&gateway); err != nil {
+		t.Fatalf("Failed to decode gateway response: %v", err)
+	}
+	if !strings.HasPrefix(gateway.URL, "wss://") {
+		t.Fatalf("Expected a wss gateway URL, got %q", gateway.URL)
+	}
+
+	conn, _, err := fixture.Dialer().Dial(gateway.URL+"/?v=9&encoding=json", nil)
+	if err != nil {
+		t.Fatalf("Failed to dial gateway: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	readEvent(t, conn, OpHello, "")
+	writeEvent(t, conn, OpIdentify, map[string]string{"token": "Bot test-token"})
+	return conn
+}
+
+func TestFixture_GatewayIdentifyAndGuildCreate(t *testing.T) {
+	fixture := newTestFixture(t)
+	conn := connectGateway(t, fixture)
+
+	ready := readEvent(t, conn, OpDispatch, "READY")
+	if ready.S == nil || *ready.S != 1 {
+		t.Errorf("Expected READY to be sequence 1, got %v", ready.S)
+	}
+
+	guildCreate := readEvent(t, conn, OpDispatch, "GUILD_CREATE")
+	var guild struct {
+		ID       string `json:"id"`
+		Channels []struct {
+			ID string `json:"id"`
+		} `json:"channels"`
+		Roles []struct {
+			ID          string `json:"id"`
+			Permissions string `json:"permissions"`
+		} `json:"roles"`
+	}
+	if err := json.Unmarshal(guildCreate.D, &guild); err != nil {
+		t.Fatalf("Failed to decode GUILD_CREATE: %v", err)
+	}
+	if guild.ID != TestGuildID || len(guild.Channels) != 2 {
+		t.Errorf("Unexpected guild payload: %+v", guild)
+	}
+	if len(guild.Roles) != 1 || guild.Roles[0].ID != TestGuildID {
+		t.Errorf("Expected an @everyone role, got %+v", guild.Roles)
+	}
+
+	messageID := fixture.SendMessage(TestTextChannelID, TestUserID, "hello")
+	message := readEvent(t, conn, OpDispatch, "MESSAGE_CREATE")
+	var payload struct {
+		ID      string `json:"id"`
+		GuildID string `json:"guild_id"`
+	}
+	json.Unmarshal(message.D, &payload)
+	if payload.ID != messageID || payload.GuildID != TestGuildID {
+		t.Errorf("Unexpected MESSAGE_CREATE payload: %s", message.D)
+	}
+}
+
+func TestFixture_AddGuild(t *testing.T) {
+	fixture := newTestFixture(t)
+	conn := connectGateway(t, fixture)
+	readEvent(t, conn, OpDispatch, "GUILD_CREATE")
+
+	textChannelID, voiceChannelID, err := fixture.AddGuild("load-guild-1", "Load Guild 1")
+	if err != nil {
+		t.Fatalf("Failed to add guild: %v", err)
+	}
+	if _, _, err := fixture.AddGuild("load-guild-1", "Load Guild 1"); err == nil {
+		t.Error("Expected adding a guild twice to fail")
+	}
+
+	var guild struct {
+		ID       string `json:"id"`
+		Channels []struct {
+			ID string `json:"id"`
+		} `json:"channels"`
+		Members []struct {
+			User struct {
+				ID string `json:"id"`
+			} `json:"user"`
+		} `json:"members"`
+	}
+	json.Unmarshal(readEvent(t, conn, OpDispatch, "GUILD_CREATE").D, &guild)
+	if guild.ID != "load-guild-1" || len(guild.Channels) != 2 || len(guild.Members) != 2 {
+		t.Errorf("Unexpected guild payload: %+v", guild)
+	}
+
+	fixture.SendMessage(textChannelID, TestUserID, "hello")
+	var message struct {
+		GuildID string `json:"guild_id"`
+	}
+	json.Unmarshal(readEvent(t, conn, OpDispatch, "MESSAGE_CREATE").D, &message)
+	if message.GuildID != "load-guild-1" {
+		t.Errorf("Expected the message in the new guild, got %q", message.GuildID)
+	}
+	if voiceChannelID != "load-guild-1-voice" {
+		t.Errorf("Unexpected voice channel %q", voiceChannelID)
+	}
+}
+
+func TestFixture_InteractionResponsesAreRecorded(t *testing.T) {
+	fixture := newTestFixture(t)
+	conn := connectGateway(t, fixture)
+	readEvent(t, conn, OpDispatch, "GUILD_CREATE")
+
+	interactionID := fixture.SendCommand(TestGuildID, TestTextChannelID, TestUserID, "darrot-join",
+		ChannelOption("voice-channel", TestVoiceChannelID))
+	event := readEvent(t, conn, OpDispatch, "INTERACTION_CREATE")
+
+	var interaction struct {
+		ID    string `json:"id"`
+		Token string `json:"token"`
+	}
+	json.Unmarshal(event.D, &interaction)
+	if interaction.ID != interactionID {
+		t.Fatalf("Expected interaction %s, got %s", interactionID, interaction.ID)
+	}
+
+	body := strings.NewReader(`{"type":4,"data":{"content":"joined"}}`)
+	url := "https://discord.com/api/v9/interactions/" + interaction.ID + "/" + interaction.Token + "/callback"
+	response, err := fixture.HTTPClient().Post(url, "application/json", body)
+	if err != nil {
+		t.Fatalf("Interaction callback failed: %v", err)
+	}
+	response.Body.Close()
+
+	recorded, ok := fixture.InteractionResponse(interactionID)
+	if !ok || recorded.Content() != "joined" {
+		t.Errorf("Expected the response to be recorded, got %+v (found %v)", recorded, ok)
+	}
+}
+
+func TestFixture_VoiceHandshakeCapturesDecryptedAudio(t *testing.T) {
+	fixture := newTestFixture(t)
+	conn := connectGateway(t, fixture)
+	readEvent(t, conn, OpDispatch, "GUILD_CREATE")
+
+	// Join voice through the gateway
+	writeEvent(t, conn, OpVoiceStateUpdate, map[string]interface{}{
+		"guild_id":   TestGuildID,
+		"channel_id": TestVoiceChannelID,
+		"self_deaf":  true,
+	})
+	readEvent(t, conn, OpDispatch, "VOICE_STATE_UPDATE")
+	serverUpdate := readEvent(t, conn, OpDispatch, "VOICE_SERVER_UPDATE")
+
+	var server struct {
+		Token    string `json:"token"`
+		Endpoint string `json:"endpoint"`
+	}
+	json.Unmarshal(serverUpdate.D, &server)
+	if got := fixture.BotVoiceChannel(TestGuildID); got != TestVoiceChannelID {
+		t.Fatalf("Expected the bot in %s, got %q", TestVoiceChannelID, got)
+	}
+
+	// Voice websocket handshake
+	voice, _, err := fixture.Dialer().Dial("wss://"+server.Endpoint, nil)
+	if err != nil {
+		t.Fatalf("Failed to dial voice endpoint: %v", err)
+	}
+	defer voice.Close()
+
+	writeEvent(t, voice, VoiceOpIdentify, map[string]string{
+		"server_id":  TestGuildID,
+		"user_id":    BotUserID,
+		"session_id": "session",
+		"token":      server.Token,
+	})
+	var ready struct {
+		SSRC uint32 `json:"ssrc"`
+		IP   string `json:"ip"`
+		Port int    `json:"port"`
+	}
+	json.Unmarshal(readEvent(t, voice, VoiceOpReady, "").D, &ready)
+
+	// IP discovery over UDP
+	udp, err := net.DialUDP("udp", nil, &net.UDPAddr{IP: net.ParseIP(ready.IP), Port: ready.Port})
+	if err != nil {
+		t.Fatalf("Failed to dial voice UDP: %v", err)
+	}
+	defer udp.Close()
+
+	discovery := make([]byte, 74)
+	binary.BigEndian.PutUint16(discovery, 1)
+	binary.BigEndian.PutUint16(discovery[2:], 70)
+	binary.BigEndian.PutUint32(discovery[4:], ready.SSRC)
+	udp.Write(discovery)
+
+	reply := make([]byte, 74)
+	udp.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if n, err := udp.Read(reply); err != nil || n != 74 {
+		t.Fatalf("Expected a 74 byte discovery reply, got %d bytes: %v", n, err)
+	}
+	if port := binary.BigEndian.Uint16(reply[72:]); int(port) != udp.LocalAddr().(*net.UDPAddr).Port {
+		t.Errorf("Discovery reported port %d, expected %d", port, udp.LocalAddr().(*net.UDPAddr).Port)
+	}
+
+	// Protocol selection yields the secret key
+	writeEvent(t, voice, VoiceOpSelectProtocol, map[string]interface{}{"protocol": "udp"})
+	var description struct {
+		Mode      string   `json:"mode"`
+		SecretKey [32]byte `json:"secret_key"`
+	}
+	json.Unmarshal(readEvent(t, voice, VoiceOpSessionDescription, "").D, &description)
+	if description.Mode != "xsalsa20_poly1305" {
+		t.Errorf("Unexpected encryption mode %q", description.Mode)
+	}
+
+	// Send one encrypted RTP packet the way discordgo does
+	header := make([]byte, 12)
+	header[0], header[1] = 0x80, 0x78
+	binary.BigEndian.PutUint16(header[2:], 7)
+	binary.BigEndian.PutUint32(header[8:], ready.SSRC)
+	var nonce [24]byte
+	copy(nonce[:], header)
+	frame := []byte{0xF8, 0xFF, 0xFE}
+	udp.Write(secretbox.Seal(header, frame, &nonce, &description.SecretKey))
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if frames := fixture.CapturedFrames(TestVoiceChannelID); len(frames) == 1 {
+			if !bytes.Equal(frames[0], frame) {
+				t.Fatalf("Captured frame %v, expected %v", frames[0], frame)
+			}
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("Timed out waiting for the audio frame to be captured")
+}
+
+func TestFixture_DropVoice(t *testing.T) {
+	fixture := newTestFixture(t)
+	conn := connectGateway(t, fixture)
+	readEvent(t, conn, OpDispatch, "GUILD_CREATE")
+
+	writeEvent(t, conn, OpVoiceStateUpdate, map[string]interface{}{
+		"guild_id":   TestGuildID,
+		"channel_id": TestVoiceChannelID,
+	})
+	var server struct {
+		Token    string `json:"token"`
+		Endpoint string `json:"endpoint"`
+	}
+	json.Unmarshal(readEvent(t, conn, OpDispatch, "VOICE_SERVER_UPDATE").D, &server)
+
+	voice, _, err := fixture.Dialer().Dial("wss://"+server.Endpoint, nil)
+	if err != nil {
+		t.Fatalf("Failed to dial voice endpoint: %v", err)
+	}
+	defer voice.Close()
+
+	writeEvent(t, voice, VoiceOpIdentify, map[string]string{
+		"server_id":  TestGuildID,
+		"user_id":    BotUserID,
+		"session_id": "session",
+		"token":      server.Token,
+	})
+	readEvent(t, voice, VoiceOpReady, "")
+
+	if !fixture.DropVoice(TestGuildID) {
+		t.Fatal("Expected a voice connection to drop")
+	}
+
+	// The client sees the connection go away
+	voice.SetReadDeadline(time.Now().Add(5 * time.Second))
+	var event GatewayEvent
+	if err := voice.ReadJSON(&event); err == nil {
+		t.Fatal("Expected the voice websocket to be closed")
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if _, connected := fixture.Voice.Connection(TestGuildID); !connected {
+			if fixture.DropVoice(TestGuildID) {
+				t.Error("Expected no voice connection left to drop")
+			}
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("Timed out waiting for the voice connection to close")
+}
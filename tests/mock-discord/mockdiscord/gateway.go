@@ -0,0 +1,540 @@
+package mockdiscord
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// GatewayServer handles Discord Gateway WebSocket connections
+type GatewayServer struct {
+	api         *MockDiscordServer
+	voice       *VoiceServer
+	clients     map[*GatewayClient]struct{}
+	voiceStates map[string]map[string]VoiceState // guild ID -> user ID -> state
+	mu          sync.RWMutex
+	upgrader    websocket.Upgrader
+}
+
+// GatewayClient represents a connected Discord bot client
+type GatewayClient struct {
+	conn          *websocket.Conn
+	send          chan GatewayEvent
+	done          chan struct{}
+	closeOnce     sync.Once
+	host          string
+	sessionID     string
+	sequence      atomic.Int64
+	authenticated atomic.Bool
+}
+
+// GatewayEvent represents a Discord Gateway event
+type GatewayEvent struct {
+	Op int             `json:"op"`
+	D  json.RawMessage `json:"d,omitempty"`
+	S  *int64          `json:"s,omitempty"`
+	T  *string         `json:"t,omitempty"`
+}
+
+// VoiceState is a user's presence in a voice channel
+type VoiceState struct {
+	GuildID   string `json:"guild_id"`
+	ChannelID string `json:"channel_id"`
+	UserID    string `json:"user_id"`
+	SessionID string `json:"session_id"`
+	SelfMute  bool   `json:"self_mute"`
+	SelfDeaf  bool   `json:"self_deaf"`
+}
+
+// CommandInteraction describes a slash command invocation to dispatch
+type CommandInteraction struct {
+	GuildID   string
+	ChannelID string
+	UserID    string
+	Name      string
+	Options   []CommandOption
+}
+
+// CommandOption is a single option value of a slash command invocation
+type CommandOption struct {
+	Name  string      `json:"name"`
+	Type  int         `json:"type"`
+	Value interface{} `json:"value"`
+}
+
+// Application command option types
+const (
+	OptionTypeString  = 3
+	OptionTypeInteger = 4
+	OptionTypeBoolean = 5
+	OptionTypeUser    = 6
+	OptionTypeChannel = 7
+	OptionTypeRole    = 8
+)
+
+// StringOption returns a string command option
+func StringOption(name, value string) CommandOption {
+	return CommandOption{Name: name, Type: OptionTypeString, Value: value}
+}
+
+// ChannelOption returns a channel command option
+func ChannelOption(name, channelID string) CommandOption {
+	return CommandOption{Name: name, Type: OptionTypeChannel, Value: channelID}
+}
+
+// Gateway opcodes
+const (
+	OpDispatch            = 0
+	OpHeartbeat           = 1
+	OpIdentify            = 2
+	OpPresenceUpdate      = 3
+	OpVoiceStateUpdate    = 4
+	OpResume              = 6
+	OpReconnect           = 7
+	OpRequestGuildMembers = 8
+	OpInvalidSession      = 9
+	OpHello               = 10
+	OpHeartbeatAck        = 11
+)
+
+// Gateway timing
+const (
+	gatewayHeartbeatInterval = 41250 * time.Millisecond
+	gatewayReadTimeout       = 60 * time.Second
+	gatewayPingInterval      = 54 * time.Second
+	gatewayWriteTimeout      = 10 * time.Second
+)
+
+// NewGatewayServer creates a new Gateway server backed by the API's data and the voice
+// server that VOICE_SERVER_UPDATE events point to
+func NewGatewayServer(api *MockDiscordServer, voice *VoiceServer) *GatewayServer {
+	return &GatewayServer{
+		api:         api,
+		voice:       voice,
+		clients:     make(map[*GatewayClient]struct{}),
+		voiceStates: make(map[string]map[string]VoiceState),
+		upgrader: websocket.Upgrader{
+			CheckOrigin: func(r *http.Request) bool {
+				return true // Allow all origins for testing
+			},
+		},
+	}
+}
+
+// HandleWebSocket handles WebSocket upgrade and client management
+func (gs *GatewayServer) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
+	conn, err := gs.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Printf("WebSocket upgrade failed: %v", err)
+		return
+	}
+
+	client := &GatewayClient{
+		conn:      conn,
+		send:      make(chan GatewayEvent, 256),
+		done:      make(chan struct{}),
+		host:      r.Host,
+		sessionID: gs.api.NextID("mock-session"),
+	}
+
+	gs.mu.Lock()
+	gs.clients[client] = struct{}{}
+	gs.mu.Unlock()
+
+	// Send Hello event
+	gs.send(client, OpHello, map[string]interface{}{
+		"heartbeat_interval": gatewayHeartbeatInterval.Milliseconds(),
+	})
+
+	// Start client goroutines
+	go gs.writePump(client)
+	go gs.readPump(client)
+}
+
+// Close disconnects all clients
+func (gs *GatewayServer) Close() {
+	gs.mu.RLock()
+	clients := make([]*GatewayClient, 0, len(gs.clients))
+	for client := range gs.clients {
+		clients = append(clients, client)
+	}
+	gs.mu.RUnlock()
+
+	for _, client := range clients {
+		gs.closeClient(client)
+	}
+}
+
+// closeClient safely closes a client connection
+func (gs *GatewayServer) closeClient(client *GatewayClient) {
+	gs.mu.Lock()
+	delete(gs.clients, client)
+	gs.mu.Unlock()
+
+	client.closeOnce.Do(func() {
+		close(client.done)
+		client.conn.Close()
+	})
+}
+
+// readPump handles incoming messages from the client
+func (gs *GatewayServer) readPump(client *GatewayClient) {
+	defer gs.closeClient(client)
+
+	client.conn.SetReadLimit(1 << 16)
+	client.conn.SetReadDeadline(time.Now().Add(gatewayReadTimeout))
+	client.conn.SetPongHandler(func(string) error {
+		client.conn.SetReadDeadline(time.Now().Add(gatewayReadTimeout))
+		return nil
+	})
+
+	for {
+		var event GatewayEvent
+		err := client.conn.ReadJSON(&event)
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseNormalClosure, websocket.CloseAbnormalClosure) {
+				log.Printf("WebSocket error: %v", err)
+			}
+			return
+		}
+		client.conn.SetReadDeadline(time.Now().Add(gatewayReadTimeout))
+
+		gs.handleClientEvent(client, event)
+	}
+}
+
+// writePump handles outgoing messages to the client
+func (gs *GatewayServer) writePump(client *GatewayClient) {
+	ticker := time.NewTicker(gatewayPingInterval)
+	defer func() {
+		ticker.Stop()
+		gs.closeClient(client)
+	}()
+
+	for {
+		select {
+		case <-client.done:
+			return
+
+		case event := <-client.send:
+			client.conn.SetWriteDeadline(time.Now().Add(gatewayWriteTimeout))
+			if err := client.conn.WriteJSON(event); err != nil {
+				log.Printf("WebSocket write error: %v", err)
+				return
+			}
+
+		case <-ticker.C:
+			client.conn.SetWriteDeadline(time.Now().Add(gatewayWriteTimeout))
+			if err := client.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// handleClientEvent processes events received from the client
+func (gs *GatewayServer) handleClientEvent(client *GatewayClient, event GatewayEvent) {
+	switch event.Op {
+	case OpIdentify:
+		gs.handleIdentify(client, event)
+	case OpHeartbeat:
+		gs.send(client, OpHeartbeatAck, nil)
+	case OpVoiceStateUpdate:
+		gs.handleVoiceStateUpdate(client, event)
+	case OpPresenceUpdate:
+		// Presence updates need no response
+	default:
+		log.Printf("Unknown opcode received: %d", event.Op)
+	}
+}
+
+// handleIdentify processes client identification
+func (gs *GatewayServer) handleIdentify(client *GatewayClient, event GatewayEvent) {
+	var identify struct {
+		Token string `json:"token"`
+	}
+	if err := json.Unmarshal(event.D, &identify); err != nil || identify.Token == "" {
+		// Not resumable
+		gs.send(client, OpInvalidSession, false)
+		return
+	}
+
+	// Simulate successful authentication
+	client.authenticated.Store(true)
+
+	gs.api.mu.RLock()
+	guilds := make([]map[string]interface{}, 0, len(gs.api.guilds))
+	guildCreates := make([]map[string]interface{}, 0, len(gs.api.guilds))
+	for _, guild := range gs.api.guilds {
+		guilds = append(guilds, map[string]interface{}{"id": guild.ID, "unavailable": true})
+		guildCreates = append(guildCreates, guildPayload(guild))
+	}
+	gs.api.mu.RUnlock()
+
+	gs.dispatch(client, "READY", map[string]interface{}{
+		"v":          9,
+		"user":       gs.api.User(BotUserID),
+		"guilds":     guilds,
+		"session_id": client.sessionID,
+	})
+
+	for _, guild := range guildCreates {
+		guild["voice_states"] = gs.guildVoiceStates(guild["id"].(string))
+		gs.dispatch(client, "GUILD_CREATE", guild)
+	}
+}
+
+// handleVoiceStateUpdate records the bot's voice state and, when joining a channel,
+// points it at the voice server
+func (gs *GatewayServer) handleVoiceStateUpdate(client *GatewayClient, event GatewayEvent) {
+	var update struct {
+		GuildID   string  `json:"guild_id"`
+		ChannelID *string `json:"channel_id"`
+		SelfMute  bool    `json:"self_mute"`
+		SelfDeaf  bool    `json:"self_deaf"`
+	}
+	if err := json.Unmarshal(event.D, &update); err != nil {
+		return
+	}
+
+	state := VoiceState{
+		GuildID:   update.GuildID,
+		UserID:    BotUserID,
+		SessionID: client.sessionID,
+		SelfMute:  update.SelfMute,
+		SelfDeaf:  update.SelfDeaf,
+	}
+	if update.ChannelID != nil {
+		state.ChannelID = *update.ChannelID
+	}
+
+	gs.setVoiceState(state)
+	gs.broadcast("VOICE_STATE_UPDATE", voiceStatePayload(state))
+
+	// If joining a voice channel, send voice server update
+	if state.ChannelID != "" && gs.voice != nil {
+		gs.dispatch(client, "VOICE_SERVER_UPDATE", map[string]interface{}{
+			"token":    gs.voice.IssueToken(state.GuildID, state.ChannelID),
+			"guild_id": state.GuildID,
+			"endpoint": client.host + "/voice",
+		})
+	}
+}
+
+// setVoiceState records a voice state; an empty channel removes it
+func (gs *GatewayServer) setVoiceState(state VoiceState) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+
+	states, exists := gs.voiceStates[state.GuildID]
+	if !exists {
+		states = make(map[string]VoiceState)
+		gs.voiceStates[state.GuildID] = states
+	}
+
+	if state.ChannelID == "" {
+		delete(states, state.UserID)
+		return
+	}
+	states[state.UserID] = state
+}
+
+// guildVoiceStates lists the voice states of a guild
+func (gs *GatewayServer) guildVoiceStates(guildID string) []map[string]interface{} {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+
+	states := make([]map[string]interface{}, 0, len(gs.voiceStates[guildID]))
+	for _, state := range gs.voiceStates[guildID] {
+		states = append(states, voiceStatePayload(state))
+	}
+	return states
+}
+
+// VoiceState returns a user's current voice state in a guild
+func (gs *GatewayServer) VoiceState(guildID, userID string) (VoiceState, bool) {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+
+	state, exists := gs.voiceStates[guildID][userID]
+	return state, exists
+}
+
+// SimulateInteractionCreate dispatches a slash command invocation and returns the
+// interaction ID its response will be recorded under
+func (gs *GatewayServer) SimulateInteractionCreate(command CommandInteraction) string {
+	interactionID := gs.api.NextID("interaction")
+
+	options := command.Options
+	if options == nil {
+		options = []CommandOption{}
+	}
+
+	gs.broadcast("INTERACTION_CREATE", map[string]interface{}{
+		"id":             interactionID,
+		"application_id": BotUserID,
+		"type":           2, // Application command
+		"token":          interactionID + "-token",
+		"version":        1,
+		"guild_id":       command.GuildID,
+		"channel_id":     command.ChannelID,
+		"locale":         "en-US",
+		"member":         gs.memberPayload(command.GuildID, command.UserID),
+		"data": map[string]interface{}{
+			"id":      gs.api.NextID("cmd"),
+			"name":    command.Name,
+			"type":    1, // Chat input
+			"options": options,
+		},
+	})
+
+	return interactionID
+}
+
+// SimulateMessageCreate simulates a MESSAGE_CREATE event in a guild channel and returns
+// the message ID
+func (gs *GatewayServer) SimulateMessageCreate(channelID, content, userID string) string {
+	var guildID string
+	if channel := gs.api.Channel(channelID); channel != nil {
+		guildID = channel.GuildID
+	}
+
+	author := gs.api.User(userID)
+	if author == nil {
+		author = &User{ID: userID, Username: "testuser", Discriminator: "1234"}
+	}
+
+	messageID := gs.api.NextID("msg")
+	gs.broadcast("MESSAGE_CREATE", map[string]interface{}{
+		"id":         messageID,
+		"channel_id": channelID,
+		"guild_id":   guildID,
+		"content":    content,
+		"author":     author,
+		"member":     gs.memberPayload(guildID, userID),
+		"timestamp":  time.Now().Format(time.RFC3339),
+	})
+
+	return messageID
+}
+
+// SimulateGuildCreate sends a guild to connected clients, as when the bot is added to it
+func (gs *GatewayServer) SimulateGuildCreate(guildID string) error {
+	gs.api.mu.RLock()
+	guild, exists := gs.api.guilds[guildID]
+	var payload map[string]interface{}
+	if exists {
+		payload = guildPayload(guild)
+	}
+	gs.api.mu.RUnlock()
+
+	if !exists {
+		return fmt.Errorf("unknown guild %s", guildID)
+	}
+	payload["voice_states"] = gs.guildVoiceStates(guildID)
+	gs.broadcast("GUILD_CREATE", payload)
+	return nil
+}
+
+// SimulateVoiceStateUpdate moves a user into a voice channel, or out of voice when
+// channelID is empty
+func (gs *GatewayServer) SimulateVoiceStateUpdate(guildID, channelID, userID string) {
+	state := VoiceState{
+		GuildID:   guildID,
+		ChannelID: channelID,
+		UserID:    userID,
+		SessionID: gs.api.NextID("voice-session"),
+	}
+
+	gs.setVoiceState(state)
+	gs.broadcast("VOICE_STATE_UPDATE", voiceStatePayload(state))
+}
+
+// memberPayload builds the member JSON for a user, falling back to a bare user
+func (gs *GatewayServer) memberPayload(guildID, userID string) map[string]interface{} {
+	user := gs.api.User(userID)
+	if user == nil {
+		user = &User{ID: userID, Username: "testuser", Discriminator: "1234"}
+	}
+	return memberPayload(guildID, user)
+}
+
+// voiceStatePayload builds the voice state JSON discordgo expects
+func voiceStatePayload(state VoiceState) map[string]interface{} {
+	var channelID interface{}
+	if state.ChannelID != "" {
+		channelID = state.ChannelID
+	}
+
+	return map[string]interface{}{
+		"guild_id":   state.GuildID,
+		"channel_id": channelID,
+		"user_id":    state.UserID,
+		"session_id": state.SessionID,
+		"deaf":       false,
+		"mute":       false,
+		"self_deaf":  state.SelfDeaf,
+		"self_mute":  state.SelfMute,
+	}
+}
+
+// BroadcastEvent sends a dispatch event to all authenticated clients
+func (gs *GatewayServer) BroadcastEvent(eventType string, data interface{}) {
+	gs.broadcast(eventType, data)
+}
+
+// broadcast sends a dispatch event to all authenticated clients
+func (gs *GatewayServer) broadcast(eventType string, data interface{}) {
+	gs.mu.RLock()
+	clients := make([]*GatewayClient, 0, len(gs.clients))
+	for client := range gs.clients {
+		if client.authenticated.Load() {
+			clients = append(clients, client)
+		}
+	}
+	gs.mu.RUnlock()
+
+	for _, client := range clients {
+		gs.dispatch(client, eventType, data)
+	}
+}
+
+// dispatch sends a dispatch event with the client's next sequence number
+func (gs *GatewayServer) dispatch(client *GatewayClient, eventType string, data interface{}) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		log.Printf("Failed to encode %s event: %v", eventType, err)
+		return
+	}
+
+	sequence := client.sequence.Add(1)
+	gs.enqueue(client, GatewayEvent{Op: OpDispatch, D: raw, S: &sequence, T: &eventType})
+}
+
+// send sends a non-dispatch event to a client
+func (gs *GatewayServer) send(client *GatewayClient, op int, data interface{}) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		log.Printf("Failed to encode op %d event: %v", op, err)
+		return
+	}
+
+	gs.enqueue(client, GatewayEvent{Op: op, D: raw})
+}
+
+// enqueue queues an event for the client's write pump, dropping clients that fall behind
+func (gs *GatewayServer) enqueue(client *GatewayClient, event GatewayEvent) {
+	select {
+	case <-client.done:
+	case client.send <- event:
+	default:
+		log.Printf("Client %s is not reading events, disconnecting", client.sessionID)
+		gs.closeClient(client)
+	}
+}
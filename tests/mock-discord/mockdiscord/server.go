@@ -0,0 +1,772 @@
+package mockdiscord
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// Default test data identifiers
+const (
+	TestGuildID        = "test-guild-123"
+	TestTextChannelID  = "test-text-channel-456"
+	TestVoiceChannelID = "test-voice-channel-789"
+	TestUserID         = "test-user-456"
+	BotUserID          = "bot-user-789"
+)
+
+// DefaultPermissions are granted to @everyone in mock guilds: view channel, send messages,
+// read message history, connect, speak and use voice activity
+const DefaultPermissions int64 = 1<<10 | 1<<11 | 1<<16 | 1<<20 | 1<<21 | 1<<25
+
+// MockDiscordServer represents the mock Discord API server
+type MockDiscordServer struct {
+	guilds       map[string]*Guild
+	users        map[string]*User
+	channels     map[string]*Channel
+	interactions []InteractionResponse
+	commands     []map[string]interface{}
+	nextID       atomic.Int64
+
+	commandWrites int // Application commands created, edited or deleted
+	mu            sync.RWMutex
+}
+
+// Guild represents a Discord guild (server)
+type Guild struct {
+	ID       string
+	Name     string
+	OwnerID  string
+	Channels map[string]*Channel
+	Members  map[string]*User
+}
+
+// Channel represents a Discord channel
+type Channel struct {
+	ID       string      `json:"id"`
+	Name     string      `json:"name"`
+	Type     ChannelType `json:"type"`
+	GuildID  string      `json:"guild_id"`
+	Messages []Message   `json:"-"`
+}
+
+// ChannelType represents Discord channel types
+type ChannelType int
+
+const (
+	ChannelTypeText  ChannelType = 0
+	ChannelTypeVoice ChannelType = 2
+	ChannelTypeStage ChannelType = 13
+)
+
+// User represents a Discord user
+type User struct {
+	ID            string `json:"id"`
+	Username      string `json:"username"`
+	Discriminator string `json:"discriminator"`
+	Bot           bool   `json:"bot"`
+}
+
+// Message represents a Discord message
+type Message struct {
+	ID        string    `json:"id"`
+	Content   string    `json:"content"`
+	Author    *User     `json:"author"`
+	ChannelID string    `json:"channel_id"`
+	GuildID   string    `json:"guild_id,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// InteractionResponse is a response the bot posted to an interaction callback
+type InteractionResponse struct {
+	InteractionID string                 `json:"interaction_id"`
+	Token         string                 `json:"token"`
+	Type          int                    `json:"type"`
+	Data          map[string]interface{} `json:"data"`
+}
+
+// Content returns the message content of the response, if any
+func (r InteractionResponse) Content() string {
+	content, _ := r.Data["content"].(string)
+	return content
+}
+
+// NewMockDiscordServer creates a new mock Discord server
+func NewMockDiscordServer() *MockDiscordServer {
+	server := &MockDiscordServer{}
+
+	// Create default test data
+	server.setupTestData()
+
+	return server
+}
+
+// setupTestData creates default guilds, channels, and users for testing
+func (s *MockDiscordServer) setupTestData() {
+	s.guilds = make(map[string]*Guild)
+	s.users = make(map[string]*User)
+	s.channels = make(map[string]*Channel)
+
+	// Create test guild
+	guild := &Guild{
+		ID:       TestGuildID,
+		Name:     "Test Guild",
+		OwnerID:  "test-owner-000",
+		Channels: make(map[string]*Channel),
+		Members:  make(map[string]*User),
+	}
+	s.guilds[guild.ID] = guild
+
+	// Create test channels
+	s.addChannel(&Channel{
+		ID:      TestTextChannelID,
+		Name:    "general",
+		Type:    ChannelTypeText,
+		GuildID: guild.ID,
+	})
+	s.addChannel(&Channel{
+		ID:      TestVoiceChannelID,
+		Name:    "General Voice",
+		Type:    ChannelTypeVoice,
+		GuildID: guild.ID,
+	})
+
+	// Create test and bot users
+	s.addMember(guild.ID, &User{
+		ID:            TestUserID,
+		Username:      "testuser",
+		Discriminator: "1234",
+		Bot:           false,
+	})
+	s.addMember(guild.ID, &User{
+		ID:            BotUserID,
+		Username:      "darrot",
+		Discriminator: "0000",
+		Bot:           true,
+	})
+}
+
+// AddGuild adds a guild with a text and a voice channel that the test user and the bot
+// are members of
+func (s *MockDiscordServer) AddGuild(guildID, name, textChannelID, voiceChannelID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.guilds[guildID]; exists {
+		return fmt.Errorf("guild %s already exists", guildID)
+	}
+	s.guilds[guildID] = &Guild{
+		ID:       guildID,
+		Name:     name,
+		OwnerID:  "test-owner-000",
+		Channels: make(map[string]*Channel),
+		Members:  make(map[string]*User),
+	}
+	s.addChannel(&Channel{ID: textChannelID, Name: "general", Type: ChannelTypeText, GuildID: guildID})
+	s.addChannel(&Channel{ID: voiceChannelID, Name: "General Voice", Type: ChannelTypeVoice, GuildID: guildID})
+	s.addMember(guildID, s.users[TestUserID])
+	s.addMember(guildID, s.users[BotUserID])
+	return nil
+}
+
+// AddChannel adds a channel to its guild
+func (s *MockDiscordServer) AddChannel(channel *Channel) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.guilds[channel.GuildID]; !exists {
+		return fmt.Errorf("unknown guild %s", channel.GuildID)
+	}
+	s.addChannel(channel)
+	return nil
+}
+
+// AddMember adds a user to a guild
+func (s *MockDiscordServer) AddMember(guildID string, user *User) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.guilds[guildID]; !exists {
+		return fmt.Errorf("unknown guild %s", guildID)
+	}
+	s.addMember(guildID, user)
+	return nil
+}
+
+func (s *MockDiscordServer) addChannel(channel *Channel) {
+	s.guilds[channel.GuildID].Channels[channel.ID] = channel
+	s.channels[channel.ID] = channel
+}
+
+func (s *MockDiscordServer) addMember(guildID string, user *User) {
+	s.guilds[guildID].Members[user.ID] = user
+	s.users[user.ID] = user
+}
+
+// SetupRoutes configures the HTTP routes for the mock Discord API
+func (s *MockDiscordServer) SetupRoutes(router *mux.Router) {
+	// Versioned API routes; discordgo uses v9, other clients v10
+	api := router.PathPrefix("/api/v{version:[0-9]+}").Subrouter()
+
+	// Authentication middleware
+	api.Use(s.authMiddleware)
+
+	// Gateway discovery
+	api.HandleFunc("/gateway", s.getGateway).Methods("GET")
+	api.HandleFunc("/gateway/bot", s.getGateway).Methods("GET")
+
+	// Guild endpoints
+	api.HandleFunc("/guilds/{guildId}", s.getGuild).Methods("GET")
+	api.HandleFunc("/guilds/{guildId}/channels", s.getGuildChannels).Methods("GET")
+	api.HandleFunc("/guilds/{guildId}/members/{userId}", s.getGuildMember).Methods("GET")
+
+	// Channel endpoints
+	api.HandleFunc("/channels/{channelId}", s.getChannel).Methods("GET")
+	api.HandleFunc("/channels/{channelId}/messages", s.getChannelMessages).Methods("GET")
+	api.HandleFunc("/channels/{channelId}/messages", s.createMessage).Methods("POST")
+
+	// Voice endpoints
+	api.HandleFunc("/channels/{channelId}/voice-states/@me", s.updateVoiceState).Methods("PATCH")
+	api.HandleFunc("/guilds/{guildId}/voice-states/@me", s.updateGuildVoiceState).Methods("PATCH")
+
+	// User endpoints
+	api.HandleFunc("/users/@me", s.getCurrentUser).Methods("GET")
+	api.HandleFunc("/users/{userId}", s.getUser).Methods("GET")
+
+	// Application command endpoints, global and per guild
+	for _, path := range []string{"/applications/{applicationId}/commands", "/applications/{applicationId}/guilds/{guildId}/commands"} {
+		api.HandleFunc(path, s.listCommands).Methods("GET")
+		api.HandleFunc(path, s.createCommand).Methods("POST")
+		api.HandleFunc(path+"/{commandId}", s.editCommand).Methods("PATCH")
+		api.HandleFunc(path+"/{commandId}", s.deleteCommand).Methods("DELETE")
+	}
+
+	// Interaction endpoints
+	api.HandleFunc("/interactions/{interactionId}/{interactionToken}/callback", s.interactionCallback).Methods("POST")
+
+	// Health check endpoint
+	router.HandleFunc("/health", s.healthCheck).Methods("GET")
+}
+
+// authMiddleware simulates Discord API authentication
+func (s *MockDiscordServer) authMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Interaction callbacks are authenticated by their token, not the bot token
+		if _, ok := mux.Vars(r)["interactionToken"]; ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		auth := r.Header.Get("Authorization")
+		if auth == "" || !strings.HasPrefix(auth, "Bot ") {
+			http.Error(w, `{"message": "401: Unauthorized", "code": 0}`, http.StatusUnauthorized)
+			return
+		}
+
+		// Extract token (in real implementation, validate against known tokens)
+		token := strings.TrimPrefix(auth, "Bot ")
+		if token == "" {
+			http.Error(w, `{"message": "401: Unauthorized", "code": 0}`, http.StatusUnauthorized)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// getGateway handles GET /gateway, pointing clients at the gateway on the same host
+func (s *MockDiscordServer) getGateway(w http.ResponseWriter, r *http.Request) {
+	scheme := "ws"
+	if r.TLS != nil {
+		scheme = "wss"
+	}
+
+	writeJSON(w, map[string]interface{}{
+		"url":    scheme + "://" + r.Host + "/gateway",
+		"shards": 1,
+	})
+}
+
+// getGuild handles GET /guilds/{guildId}
+func (s *MockDiscordServer) getGuild(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	guildID := vars["guildId"]
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	guild, exists := s.guilds[guildID]
+	if !exists {
+		http.Error(w, `{"message": "Unknown Guild", "code": 10004}`, http.StatusNotFound)
+		return
+	}
+
+	writeJSON(w, guildPayload(guild))
+}
+
+// getGuildChannels handles GET /guilds/{guildId}/channels
+func (s *MockDiscordServer) getGuildChannels(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	guildID := vars["guildId"]
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	guild, exists := s.guilds[guildID]
+	if !exists {
+		http.Error(w, `{"message": "Unknown Guild", "code": 10004}`, http.StatusNotFound)
+		return
+	}
+
+	writeJSON(w, channelPayloads(guild))
+}
+
+// getGuildMember handles GET /guilds/{guildId}/members/{userId}
+func (s *MockDiscordServer) getGuildMember(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	guildID := vars["guildId"]
+	userID := vars["userId"]
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	guild, guildExists := s.guilds[guildID]
+	if !guildExists {
+		http.Error(w, `{"message": "Unknown Guild", "code": 10004}`, http.StatusNotFound)
+		return
+	}
+
+	// Check if user is member of guild
+	user, isMember := guild.Members[userID]
+	if !isMember {
+		http.Error(w, `{"message": "Unknown Member", "code": 10007}`, http.StatusNotFound)
+		return
+	}
+
+	writeJSON(w, memberPayload(guildID, user))
+}
+
+// getChannel handles GET /channels/{channelId}
+func (s *MockDiscordServer) getChannel(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	channelID := vars["channelId"]
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	channel, exists := s.channels[channelID]
+	if !exists {
+		http.Error(w, `{"message": "Unknown Channel", "code": 10003}`, http.StatusNotFound)
+		return
+	}
+
+	writeJSON(w, channel)
+}
+
+// getChannelMessages handles GET /channels/{channelId}/messages
+func (s *MockDiscordServer) getChannelMessages(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	channelID := vars["channelId"]
+
+	// Parse query parameters
+	limit := 50
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 && parsedLimit <= 100 {
+			limit = parsedLimit
+		}
+	}
+
+	s.mu.RLock()
+	channel, exists := s.channels[channelID]
+	var messages []Message
+	if exists {
+		// Return recent messages (up to limit)
+		messages = channel.Messages
+		if len(messages) > limit {
+			messages = messages[len(messages)-limit:]
+		}
+		messages = append([]Message(nil), messages...)
+	}
+	s.mu.RUnlock()
+
+	if !exists {
+		http.Error(w, `{"message": "Unknown Channel", "code": 10003}`, http.StatusNotFound)
+		return
+	}
+
+	writeJSON(w, messages)
+}
+
+// createMessage handles POST /channels/{channelId}/messages
+func (s *MockDiscordServer) createMessage(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	channelID := vars["channelId"]
+
+	var messageData struct {
+		Content string `json:"content"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&messageData); err != nil {
+		http.Error(w, `{"message": "Invalid JSON", "code": 50109}`, http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	channel, exists := s.channels[channelID]
+	var message Message
+	if exists {
+		message = Message{
+			ID:        s.NextID("msg"),
+			Content:   messageData.Content,
+			Author:    s.users[BotUserID],
+			ChannelID: channelID,
+			GuildID:   channel.GuildID,
+			Timestamp: time.Now(),
+		}
+		channel.Messages = append(channel.Messages, message)
+	}
+	s.mu.Unlock()
+
+	if !exists {
+		http.Error(w, `{"message": "Unknown Channel", "code": 10003}`, http.StatusNotFound)
+		return
+	}
+
+	writeJSON(w, message)
+}
+
+// updateVoiceState handles PATCH /channels/{channelId}/voice-states/@me
+func (s *MockDiscordServer) updateVoiceState(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	channelID := vars["channelId"]
+
+	s.mu.RLock()
+	channel, exists := s.channels[channelID]
+	s.mu.RUnlock()
+
+	if !exists {
+		http.Error(w, `{"message": "Unknown Channel", "code": 10003}`, http.StatusNotFound)
+		return
+	}
+
+	if channel.Type != ChannelTypeVoice && channel.Type != ChannelTypeStage {
+		http.Error(w, `{"message": "Cannot join non-voice channel", "code": 40032}`, http.StatusBadRequest)
+		return
+	}
+
+	// Simulate successful voice state update
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// updateGuildVoiceState handles PATCH /guilds/{guildId}/voice-states/@me, used to
+// request to speak in stage channels
+func (s *MockDiscordServer) updateGuildVoiceState(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	guildID := vars["guildId"]
+
+	s.mu.RLock()
+	_, exists := s.guilds[guildID]
+	s.mu.RUnlock()
+
+	if !exists {
+		http.Error(w, `{"message": "Unknown Guild", "code": 10004}`, http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// getCurrentUser handles GET /users/@me
+func (s *MockDiscordServer) getCurrentUser(w http.ResponseWriter, r *http.Request) {
+	// Return bot user as current user
+	writeJSON(w, s.User(BotUserID))
+}
+
+// getUser handles GET /users/{userId}
+func (s *MockDiscordServer) getUser(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	userID := vars["userId"]
+
+	user := s.User(userID)
+	if user == nil {
+		http.Error(w, `{"message": "Unknown User", "code": 10013}`, http.StatusNotFound)
+		return
+	}
+
+	writeJSON(w, user)
+}
+
+// listCommands handles GET /applications/{applicationId}[/guilds/{guildId}]/commands
+func (s *MockDiscordServer) listCommands(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	s.mu.RLock()
+	commands := make([]map[string]interface{}, 0, len(s.commands))
+	for _, command := range s.commands {
+		if command["application_id"] == vars["applicationId"] && command["guild_id"] == vars["guildId"] {
+			commands = append(commands, command)
+		}
+	}
+	s.mu.RUnlock()
+
+	writeJSON(w, commands)
+}
+
+// createCommand handles POST /applications/{applicationId}[/guilds/{guildId}]/commands
+func (s *MockDiscordServer) createCommand(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	var command map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&command); err != nil {
+		http.Error(w, `{"message": "Invalid JSON", "code": 50109}`, http.StatusBadRequest)
+		return
+	}
+
+	command["id"] = s.NextID("cmd")
+	command["application_id"] = vars["applicationId"]
+	command["guild_id"] = vars["guildId"]
+
+	s.mu.Lock()
+	s.commands = append(s.commands, command)
+	s.commandWrites++
+	s.mu.Unlock()
+
+	writeJSON(w, command)
+}
+
+// editCommand handles PATCH /applications/{applicationId}[/guilds/{guildId}]/commands/{commandId}
+func (s *MockDiscordServer) editCommand(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	var update map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		http.Error(w, `{"message": "Invalid JSON", "code": 50109}`, http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, command := range s.commands {
+		if command["id"] != vars["commandId"] {
+			continue
+		}
+		for key, value := range update {
+			if key != "id" && key != "application_id" && key != "guild_id" {
+				command[key] = value
+			}
+		}
+		s.commandWrites++
+		writeJSON(w, command)
+		return
+	}
+
+	http.Error(w, `{"message": "Unknown application command", "code": 10063}`, http.StatusNotFound)
+}
+
+// deleteCommand handles DELETE /applications/{applicationId}[/guilds/{guildId}]/commands/{commandId}
+func (s *MockDiscordServer) deleteCommand(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, command := range s.commands {
+		if command["id"] == vars["commandId"] {
+			s.commands = append(s.commands[:i], s.commands[i+1:]...)
+			s.commandWrites++
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+	}
+
+	http.Error(w, `{"message": "Unknown application command", "code": 10063}`, http.StatusNotFound)
+}
+
+// interactionCallback handles POST /interactions/{interactionId}/{interactionToken}/callback
+func (s *MockDiscordServer) interactionCallback(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	var callback struct {
+		Type int                    `json:"type"`
+		Data map[string]interface{} `json:"data"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&callback); err != nil {
+		http.Error(w, `{"message": "Invalid JSON", "code": 50109}`, http.StatusBadRequest)
+		return
+	}
+
+	// Store the response for testing purposes
+	response := InteractionResponse{
+		InteractionID: vars["interactionId"],
+		Token:         vars["interactionToken"],
+		Type:          callback.Type,
+		Data:          callback.Data,
+	}
+
+	s.mu.Lock()
+	s.interactions = append(s.interactions, response)
+	s.mu.Unlock()
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// healthCheck handles GET /health
+func (s *MockDiscordServer) healthCheck(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	response := map[string]interface{}{
+		"status":    "healthy",
+		"timestamp": time.Now().Format(time.RFC3339),
+		"guilds":    len(s.guilds),
+		"users":     len(s.users),
+		"channels":  len(s.channels),
+	}
+	s.mu.RUnlock()
+
+	writeJSON(w, response)
+}
+
+// User returns a copy of a known user, or nil
+func (s *MockDiscordServer) User(userID string) *User {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	user, exists := s.users[userID]
+	if !exists {
+		return nil
+	}
+	userCopy := *user
+	return &userCopy
+}
+
+// Channel returns a copy of a known channel, or nil
+func (s *MockDiscordServer) Channel(channelID string) *Channel {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	channel, exists := s.channels[channelID]
+	if !exists {
+		return nil
+	}
+	channelCopy := *channel
+	channelCopy.Messages = append([]Message(nil), channel.Messages...)
+	return &channelCopy
+}
+
+// GetInteractions returns all recorded interaction responses for testing
+func (s *MockDiscordServer) GetInteractions() []InteractionResponse {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	interactions := make([]InteractionResponse, len(s.interactions))
+	copy(interactions, s.interactions)
+	return interactions
+}
+
+// InteractionResponse returns the response posted for an interaction
+func (s *MockDiscordServer) InteractionResponse(interactionID string) (InteractionResponse, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, response := range s.interactions {
+		if response.InteractionID == interactionID {
+			return response, true
+		}
+	}
+	return InteractionResponse{}, false
+}
+
+// GetCommands returns the names of all registered application commands
+func (s *MockDiscordServer) GetCommands() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	names := make([]string, 0, len(s.commands))
+	for _, command := range s.commands {
+		if name, ok := command["name"].(string); ok {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// CommandWrites returns how many application commands were created, edited or deleted
+func (s *MockDiscordServer) CommandWrites() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.commandWrites
+}
+
+// Reset clears all recorded interactions and resets test data
+func (s *MockDiscordServer) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.interactions = nil
+	s.commands = nil
+	s.commandWrites = 0
+	s.setupTestData()
+}
+
+// NextID returns a unique identifier with the given prefix
+func (s *MockDiscordServer) NextID(prefix string) string {
+	return fmt.Sprintf("%s-%d", prefix, s.nextID.Add(1))
+}
+
+// guildPayload builds the guild JSON discordgo expects. Callers hold s.mu.
+func guildPayload(guild *Guild) map[string]interface{} {
+	members := make([]map[string]interface{}, 0, len(guild.Members))
+	for _, user := range guild.Members {
+		members = append(members, memberPayload(guild.ID, user))
+	}
+
+	return map[string]interface{}{
+		"id":       guild.ID,
+		"name":     guild.Name,
+		"owner_id": guild.OwnerID,
+		"roles": []map[string]interface{}{
+			{
+				"id":          guild.ID,
+				"name":        "@everyone",
+				"permissions": strconv.FormatInt(DefaultPermissions, 10),
+			},
+		},
+		"channels":     channelPayloads(guild),
+		"members":      members,
+		"member_count": len(members),
+	}
+}
+
+// channelPayloads lists a guild's channels. Callers hold s.mu.
+func channelPayloads(guild *Guild) []*Channel {
+	channels := make([]*Channel, 0, len(guild.Channels))
+	for _, channel := range guild.Channels {
+		channels = append(channels, channel)
+	}
+	return channels
+}
+
+// memberPayload builds the member JSON discordgo expects
+func memberPayload(guildID string, user *User) map[string]interface{} {
+	return map[string]interface{}{
+		"guild_id":    guildID,
+		"user":        user,
+		"roles":       []string{},
+		"joined_at":   time.Now().Add(-24 * time.Hour).Format(time.RFC3339),
+		"permissions": strconv.FormatInt(DefaultPermissions, 10),
+	}
+}
+
+// writeJSON writes a JSON response body
+func writeJSON(w http.ResponseWriter, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(value)
+}
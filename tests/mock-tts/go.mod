@@ -0,0 +1,3 @@
+module mock-tts
+
+go 1.23.0
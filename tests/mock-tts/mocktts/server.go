@@ -0,0 +1,309 @@
+// Package mocktts implements an in-process mock of the subset of the Google Cloud
+// Text-to-Speech REST API that darrot uses. Synthesis returns a deterministic tone so
+// tests can assert on the exact audio, and no cloud credentials are needed.
+package mocktts
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Audio encodings from the texttospeech API
+const (
+	EncodingUnspecified = 0
+	EncodingLinear16    = 1
+	EncodingOggOpus     = 3
+	EncodingPCM         = 7
+)
+
+// DefaultSampleRate is used when a request doesn't ask for a sample rate
+const DefaultSampleRate = 24000
+
+// Tone parameters of the generated audio
+const (
+	msPerCharacter = 60
+	minDurationMs  = 200
+	maxDurationMs  = 30000
+	amplitude      = 0.3
+)
+
+// Voice is a voice returned by the voices endpoint
+type Voice struct {
+	Name                   string   `json:"name"`
+	LanguageCodes          []string `json:"languageCodes"`
+	SsmlGender             string   `json:"ssmlGender"`
+	NaturalSampleRateHertz int      `json:"naturalSampleRateHertz"`
+}
+
+// DefaultVoices are the voices the mock lists
+var DefaultVoices = []Voice{
+	{Name: "en-US-Standard-A", LanguageCodes: []string{"en-US"}, SsmlGender: "MALE", NaturalSampleRateHertz: DefaultSampleRate},
+	{Name: "en-US-Standard-C", LanguageCodes: []string{"en-US"}, SsmlGender: "FEMALE", NaturalSampleRateHertz: DefaultSampleRate},
+	{Name: "en-GB-Standard-A", LanguageCodes: []string{"en-GB"}, SsmlGender: "FEMALE", NaturalSampleRateHertz: DefaultSampleRate},
+	{Name: "de-DE-Standard-A", LanguageCodes: []string{"de-DE"}, SsmlGender: "FEMALE", NaturalSampleRateHertz: DefaultSampleRate},
+	{Name: "fr-FR-Standard-A", LanguageCodes: []string{"fr-FR"}, SsmlGender: "FEMALE", NaturalSampleRateHertz: DefaultSampleRate},
+}
+
+// SynthesisRequest is a synthesis request received by the mock
+type SynthesisRequest struct {
+	Text            string
+	SSML            string
+	Voice           string
+	LanguageCode    string
+	Encoding        int
+	SampleRateHertz int
+	SpeakingRate    float64
+	VolumeGainDb    float64
+}
+
+// synthesizeRequest is the JSON body of text:synthesize. Enums may arrive as numbers (the
+// Go client) or names (curl), so the encoding is decoded separately.
+type synthesizeRequest struct {
+	Input struct {
+		Text string `json:"text"`
+		SSML string `json:"ssml"`
+	} `json:"input"`
+	Voice struct {
+		LanguageCode string `json:"languageCode"`
+		Name         string `json:"name"`
+	} `json:"voice"`
+	AudioConfig struct {
+		AudioEncoding   json.RawMessage `json:"audioEncoding"`
+		SpeakingRate    float64         `json:"speakingRate"`
+		VolumeGainDb    float64         `json:"volumeGainDb"`
+		SampleRateHertz int             `json:"sampleRateHertz"`
+	} `json:"audioConfig"`
+}
+
+// encodingNames maps encoding names to their numbers
+var encodingNames = map[string]int{
+	"AUDIO_ENCODING_UNSPECIFIED": EncodingUnspecified,
+	"LINEAR16":                   EncodingLinear16,
+	"MP3":                        2,
+	"OGG_OPUS":                   EncodingOggOpus,
+	"MULAW":                      5,
+	"ALAW":                       6,
+	"PCM":                        EncodingPCM,
+}
+
+// Server is the mock Text-to-Speech API
+type Server struct {
+	voices   []Voice
+	requests []SynthesisRequest
+	mu       sync.RWMutex
+}
+
+// NewServer creates a mock server listing DefaultVoices
+func NewServer() *Server {
+	return &Server{voices: DefaultVoices}
+}
+
+// Handler returns the HTTP handler of the mock API
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/text:synthesize", s.handleSynthesize)
+	mux.HandleFunc("GET /v1/voices", s.handleListVoices)
+	mux.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "healthy", "service": "mock-tts"})
+	})
+	return mux
+}
+
+// handleSynthesize returns the tone for the requested text as LINEAR16 (with a WAV header)
+// or raw PCM. Other encodings are rejected the way Google rejects unsupported voice and
+// encoding combinations, so clients fall back to LINEAR16.
+func (s *Server) handleSynthesize(w http.ResponseWriter, r *http.Request) {
+	var body synthesizeRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_ARGUMENT", "Invalid JSON payload: "+err.Error())
+		return
+	}
+
+	encoding, err := parseEncoding(body.AudioConfig.AudioEncoding)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_ARGUMENT", err.Error())
+		return
+	}
+
+	request := SynthesisRequest{
+		Text:            body.Input.Text,
+		SSML:            body.Input.SSML,
+		Voice:           body.Voice.Name,
+		LanguageCode:    body.Voice.LanguageCode,
+		Encoding:        encoding,
+		SampleRateHertz: body.AudioConfig.SampleRateHertz,
+		SpeakingRate:    body.AudioConfig.SpeakingRate,
+		VolumeGainDb:    body.AudioConfig.VolumeGainDb,
+	}
+	s.mu.Lock()
+	s.requests = append(s.requests, request)
+	s.mu.Unlock()
+
+	text := request.Text
+	if text == "" {
+		text = request.SSML
+	}
+	if text == "" {
+		writeError(w, http.StatusBadRequest, "INVALID_ARGUMENT", "Input text is empty")
+		return
+	}
+
+	sampleRate := request.SampleRateHertz
+	if sampleRate == 0 {
+		sampleRate = DefaultSampleRate
+	}
+
+	var audio []byte
+	switch encoding {
+	case EncodingLinear16, EncodingUnspecified:
+		audio = WAV(GeneratePCM(text, request.Voice, sampleRate), sampleRate)
+	case EncodingPCM:
+		audio = GeneratePCM(text, request.Voice, sampleRate)
+	default:
+		writeError(w, http.StatusBadRequest, "INVALID_ARGUMENT",
+			fmt.Sprintf("mock-tts only synthesizes LINEAR16 and PCM, got encoding %d", encoding))
+		return
+	}
+
+	log.Printf("Synthesized %d characters with voice %q: %d bytes", len(text), request.Voice, len(audio))
+	writeJSON(w, http.StatusOK, map[string][]byte{"audioContent": audio})
+}
+
+// handleListVoices lists the voices, filtered by the languageCode query parameter
+func (s *Server) handleListVoices(w http.ResponseWriter, r *http.Request) {
+	languageCode := r.URL.Query().Get("languageCode")
+
+	s.mu.RLock()
+	voices := make([]Voice, 0, len(s.voices))
+	for _, voice := range s.voices {
+		if languageCode == "" || hasLanguage(voice, languageCode) {
+			voices = append(voices, voice)
+		}
+	}
+	s.mu.RUnlock()
+
+	writeJSON(w, http.StatusOK, map[string][]Voice{"voices": voices})
+}
+
+// SetVoices replaces the voices the mock lists
+func (s *Server) SetVoices(voices []Voice) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.voices = voices
+}
+
+// Requests returns the synthesis requests received so far
+func (s *Server) Requests() []SynthesisRequest {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	requests := make([]SynthesisRequest, len(s.requests))
+	copy(requests, s.requests)
+	return requests
+}
+
+// Reset forgets the received requests
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = nil
+}
+
+// GeneratePCM returns the audio the mock synthesizes for text: 16-bit little-endian mono
+// PCM of a sine tone whose pitch depends on the voice and whose length depends on the text
+func GeneratePCM(text, voice string, sampleRate int) []byte {
+	durationMs := len([]rune(text)) * msPerCharacter
+	durationMs = max(minDurationMs, min(durationMs, maxDurationMs))
+
+	hash := fnv.New32a()
+	hash.Write([]byte(voice))
+	frequency := 220 + float64(hash.Sum32()%440)
+
+	samples := sampleRate * durationMs / 1000
+	pcm := make([]byte, samples*2)
+	for i := 0; i < samples; i++ {
+		value := amplitude * math.Sin(2*math.Pi*frequency*float64(i)/float64(sampleRate))
+		binary.LittleEndian.PutUint16(pcm[i*2:], uint16(int16(value*math.MaxInt16)))
+	}
+	return pcm
+}
+
+// WAV prepends a 44 byte WAV header for 16-bit mono PCM, as Google does for LINEAR16
+func WAV(pcm []byte, sampleRate int) []byte {
+	header := make([]byte, 44)
+	copy(header[0:], "RIFF")
+	binary.LittleEndian.PutUint32(header[4:], uint32(36+len(pcm)))
+	copy(header[8:], "WAVEfmt ")
+	binary.LittleEndian.PutUint32(header[16:], 16)
+	binary.LittleEndian.PutUint16(header[20:], 1) // PCM
+	binary.LittleEndian.PutUint16(header[22:], 1) // mono
+	binary.LittleEndian.PutUint32(header[24:], uint32(sampleRate))
+	binary.LittleEndian.PutUint32(header[28:], uint32(sampleRate*2))
+	binary.LittleEndian.PutUint16(header[32:], 2)
+	binary.LittleEndian.PutUint16(header[34:], 16)
+	copy(header[36:], "data")
+	binary.LittleEndian.PutUint32(header[40:], uint32(len(pcm)))
+	return append(header, pcm...)
+}
+
+// parseEncoding decodes an audio encoding given as a number or a name
+func parseEncoding(raw json.RawMessage) (int, error) {
+	if len(raw) == 0 {
+		return EncodingUnspecified, nil
+	}
+
+	var number int
+	if err := json.Unmarshal(raw, &number); err == nil {
+		return number, nil
+	}
+
+	var name string
+	if err := json.Unmarshal(raw, &name); err != nil {
+		return 0, fmt.Errorf("invalid audioEncoding %s", raw)
+	}
+	if number, err := strconv.Atoi(name); err == nil {
+		return number, nil
+	}
+	encoding, ok := encodingNames[strings.ToUpper(name)]
+	if !ok {
+		return 0, fmt.Errorf("unknown audioEncoding %q", name)
+	}
+	return encoding, nil
+}
+
+// hasLanguage reports whether a voice speaks a language
+func hasLanguage(voice Voice, languageCode string) bool {
+	for _, code := range voice.LanguageCodes {
+		if strings.EqualFold(code, languageCode) || strings.HasPrefix(strings.ToLower(code), strings.ToLower(languageCode)+"-") {
+			return true
+		}
+	}
+	return false
+}
+
+// writeError writes an error in the Google API error format
+func writeError(w http.ResponseWriter, code int, status, message string) {
+	writeJSON(w, code, map[string]interface{}{
+		"error": map[string]interface{}{
+			"code":    code,
+			"message": message,
+			"status":  status,
+		},
+	})
+}
+
+func writeJSON(w http.ResponseWriter, code int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(data)
+}
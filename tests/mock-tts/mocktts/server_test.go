@@ -0,0 +1,144 @@
+package mocktts
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newTestServer(t *testing.T) (*Server, *httptest.Server) {
+	t.Helper()
+
+	server := NewServer()
+	httpServer := httptest.NewServer(server.Handler())
+	t.Cleanup(httpServer.Close)
+	return server, httpServer
+}
+
+func synthesize(t *testing.T, url, body string) (*http.Response, map[string]json.RawMessage) {
+	t.Helper()
+
+	response, err := http.Post(url+"/v1/text:synthesize", "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("Synthesize request failed: %v", err)
+	}
+	defer response.Body.Close()
+
+	var payload map[string]json.RawMessage
+	if err := json.NewDecoder(response.Body).Decode(&payload); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	return response, payload
+}
+
+func TestServer_SynthesizeLinear16IsDeterministic(t *testing.T) {
+	server, httpServer := newTestServer(t)
+
+	body := `{"input":{"text":"hello"},"voice":{"languageCode":"en-US","name":"en-US-Standard-A"},"audioConfig":{"audioEncoding":1,"sampleRateHertz":24000}}`
+	response, payload := synthesize(t, httpServer.URL, body)
+	if response.StatusCode != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", response.StatusCode)
+	}
+
+	var audio []byte
+	if err := json.Unmarshal(payload["audioContent"], &audio); err != nil {
+		t.Fatalf("Failed to decode audioContent: %v", err)
+	}
+
+	expected := WAV(GeneratePCM("hello", "en-US-Standard-A", 24000), 24000)
+	if !bytes.Equal(audio, expected) {
+		t.Fatalf("Expected %d bytes of deterministic audio, got %d bytes", len(expected), len(audio))
+	}
+	if string(audio[:4]) != "RIFF" {
+		t.Errorf("Expected a WAV header, got %q", audio[:4])
+	}
+
+	requests := server.Requests()
+	if len(requests) != 1 || requests[0].Text != "hello" || requests[0].Encoding != EncodingLinear16 {
+		t.Errorf("Unexpected recorded requests: %+v", requests)
+	}
+}
+
+func TestServer_SynthesizeAcceptsEncodingNames(t *testing.T) {
+	_, httpServer := newTestServer(t)
+
+	body := `{"input":{"text":"hi"},"voice":{"name":"en-US-Standard-C"},"audioConfig":{"audioEncoding":"PCM"}}`
+	response, payload := synthesize(t, httpServer.URL, body)
+	if response.StatusCode != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", response.StatusCode)
+	}
+
+	var audio []byte
+	json.Unmarshal(payload["audioContent"], &audio)
+	if !bytes.Equal(audio, GeneratePCM("hi", "en-US-Standard-C", DefaultSampleRate)) {
+		t.Error("Expected raw PCM at the default sample rate")
+	}
+}
+
+func TestServer_SynthesizeRejectsOggOpus(t *testing.T) {
+	_, httpServer := newTestServer(t)
+
+	body := `{"input":{"text":"hello"},"voice":{"name":"en-US-Standard-A"},"audioConfig":{"audioEncoding":3}}`
+	response, payload := synthesize(t, httpServer.URL, body)
+	if response.StatusCode != http.StatusBadRequest {
+		t.Fatalf("Expected 400, got %d", response.StatusCode)
+	}
+
+	var apiError struct {
+		Status string `json:"status"`
+	}
+	json.Unmarshal(payload["error"], &apiError)
+	if apiError.Status != "INVALID_ARGUMENT" {
+		t.Errorf("Expected INVALID_ARGUMENT, got %q", apiError.Status)
+	}
+}
+
+func TestServer_ListVoicesFiltersByLanguage(t *testing.T) {
+	_, httpServer := newTestServer(t)
+
+	tests := []struct {
+		query    string
+		expected int
+	}{
+		{"", len(DefaultVoices)},
+		{"?languageCode=en-US", 2},
+		{"?languageCode=en", 3},
+		{"?languageCode=ja-JP", 0},
+	}
+
+	for _, tt := range tests {
+		response, err := http.Get(httpServer.URL + "/v1/voices" + tt.query)
+		if err != nil {
+			t.Fatalf("List voices failed: %v", err)
+		}
+
+		var payload struct {
+			Voices []Voice `json:"voices"`
+		}
+		json.NewDecoder(response.Body).Decode(&payload)
+		response.Body.Close()
+
+		if len(payload.Voices) != tt.expected {
+			t.Errorf("%q: expected %d voices, got %d", tt.query, tt.expected, len(payload.Voices))
+		}
+	}
+}
+
+func TestGeneratePCM_LengthFollowsText(t *testing.T) {
+	short := GeneratePCM("a", "voice", 24000)
+	long := GeneratePCM(strings.Repeat("a", 100), "voice", 24000)
+
+	// One character is padded to the minimum duration
+	if len(short) != 24000*minDurationMs/1000*2 {
+		t.Errorf("Expected the minimum duration, got %d bytes", len(short))
+	}
+	if len(long) != 24000*100*msPerCharacter/1000*2 {
+		t.Errorf("Expected %d ms of audio, got %d bytes", 100*msPerCharacter, len(long))
+	}
+	if bytes.Equal(GeneratePCM("a", "voice-a", 24000), GeneratePCM("a", "voice-b", 24000)) {
+		t.Error("Expected different voices to produce different tones")
+	}
+}